@@ -2,31 +2,54 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"flag"
 	"fmt"
-	"metadatatool/internal/domain"
+	"metadatatool/internal/graphql/dataloader"
+	"metadatatool/internal/graphql/executor"
+	"metadatatool/internal/graphql/generated"
+	"metadatatool/internal/graphql/resolvers"
 	"metadatatool/internal/handler"
 	"metadatatool/internal/handler/middleware"
 	"metadatatool/internal/pkg/analytics"
+	"metadatatool/internal/pkg/audio"
+	"metadatatool/internal/pkg/background"
 	pkgconfig "metadatatool/internal/pkg/config"
-	"metadatatool/internal/pkg/converter"
+	"metadatatool/internal/pkg/database"
 	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/encryption"
 	"metadatatool/internal/pkg/errortracking"
 	"metadatatool/internal/pkg/logger"
 	"metadatatool/internal/pkg/metrics"
+	pkgmiddleware "metadatatool/internal/pkg/middleware"
+	"metadatatool/internal/pkg/migrations"
+	"metadatatool/internal/pkg/openapi"
+	"metadatatool/internal/pkg/scheduler"
+	"metadatatool/internal/pkg/secrets"
+	"metadatatool/internal/pkg/supervisor"
 	"metadatatool/internal/pkg/validator"
 	"metadatatool/internal/repository/ai"
+	audiorepo "metadatatool/internal/repository/audio"
 	"metadatatool/internal/repository/base"
+	"metadatatool/internal/repository/cached"
+	"metadatatool/internal/repository/jobs"
+	"metadatatool/internal/repository/notify"
 	queuepkg "metadatatool/internal/repository/queue"
 	"metadatatool/internal/repository/redis"
+	"metadatatool/internal/repository/scanner"
 	storagepkg "metadatatool/internal/repository/storage"
 	"metadatatool/internal/usecase"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
@@ -42,18 +65,87 @@ func main() {
 	// Initialize logger
 	log := logger.NewLogger()
 
+	// -dev runs the API as a single process without external services:
+	// SQLite instead of PostgreSQL, an embedded Redis for the change feed,
+	// in-memory sessions and files on the local disk
+	devMode := flag.Bool("dev", false, "run with SQLite, an embedded Redis and local file storage")
+	devDir := flag.String("dev-dir", ".metadatatool-dev", "directory holding the dev mode database and files")
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML config file; environment variables override its values")
+	flag.Parse()
+
 	// Load configuration
-	cfg, err := pkgconfig.Load()
+	cfg, err := pkgconfig.LoadFile(*configPath)
 	if err != nil {
-		log.Warnf("Failed to load config: %v", err)
-		// Continue with defaults
+		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Replace secretref:// values with secrets from GCP, AWS or Vault
+	secretManager := secrets.NewManagerFromConfig(cfg.Secrets)
+	if err := secretManager.Resolve(context.Background(), cfg.SecretFields()); err != nil {
+		log.Fatalf("Failed to resolve secrets: %v", err)
+	}
+	for _, warning := range cfg.Warnings() {
+		log.Warnf("Config: %s", warning)
+	}
+	if cfg.Secrets.RefreshInterval > 0 {
+		// Services copy their secrets at startup, so a rotation is reported
+		// and applied on the next restart
+		secretManager.OnRotate(func(name, _ string) {
+			log.Warnf("Secret %s was rotated, restart to apply it", name)
+		})
+		refreshCtx, stopRefresh := context.WithCancel(context.Background())
+		defer stopRefresh()
+		go secretManager.Run(refreshCtx, cfg.Secrets.RefreshInterval, func(err error) {
+			log.Warnf("Failed to refresh secrets: %v", err)
+		})
+	}
+
+	// "api migrate <command>" manages the schema and exits
+	if args := flag.Args(); len(args) > 0 && args[0] == "migrate" {
+		if err := runMigrate(cfg, args[1:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	if *devMode {
+		if err := os.MkdirAll(*devDir, 0o755); err != nil {
+			log.Fatalf("Failed to create dev directory: %v", err)
+		}
+		cfg.Database.Driver = pkgconfig.DriverSQLite
+		cfg.Database.SQLitePath = filepath.Join(*devDir, "metadatatool.db")
+		if cfg.Analytics.Sink == pkgconfig.SinkBigQuery {
+			cfg.Analytics.Sink = pkgconfig.SinkStdout
+		}
+		log.Infof("Running in dev mode with data in %s", *devDir)
+	}
+	log.Infof("Effective configuration:\n%s", cfg.Redacted())
+
 	// Initialize error tracking
-	errorTracker := errortracking.NewErrorTracker()
+	errorTracker := errortracking.NewErrorTracker(cfg.Sentry)
+	defer errorTracker.Close()
+
+	// Dependencies that are briefly down at boot are retried; optional ones
+	// that stay down are watched and their services re-enabled on recovery
+	startupPolicy := supervisor.Policy{
+		Attempts: uint(cfg.Server.StartupRetries),
+		Delay:    cfg.Server.StartupRetryDelay,
+		MaxDelay: 30 * time.Second,
+	}
+	deps := supervisor.New(cfg.Server.DependencyCheckInterval)
+	depsCtx, stopDeps := context.WithCancel(context.Background())
+	defer stopDeps()
 
 	var redisClient *goredis.Client
-	if os.Getenv("DISABLE_REDIS") != "true" {
+	var redisDep *supervisor.Dependency
+	if *devMode {
+		mr, err := miniredis.Run()
+		if err != nil {
+			log.Fatalf("Failed to start embedded Redis: %v", err)
+		}
+		defer mr.Close()
+		redisClient = goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	} else if os.Getenv("DISABLE_REDIS") != "true" {
 		// Initialize Redis client
 		redisClient = goredis.NewClient(&goredis.Options{
 			Addr:     cfg.Redis.GetAddress(),
@@ -61,11 +153,18 @@ func main() {
 			DB:       cfg.Redis.DB,
 		})
 
-		// Test Redis connection
-		if err := redisClient.Ping(context.Background()).Err(); err != nil {
-			log.Warn("Failed to connect to Redis:", err)
-			redisClient = nil
+		// Test Redis connection. The client is kept when Redis stays down so
+		// sessions and the other Redis-backed services come up once it does.
+		err := supervisor.Connect("Redis", startupPolicy, func() error {
+			return redisClient.Ping(context.Background()).Err()
+		})
+		if err != nil {
+			log.Warnf("Redis is unavailable, sessions are disabled until it recovers: %v", err)
 		}
+		client := redisClient
+		redisDep = deps.Watch("Redis", err == nil, func(ctx context.Context) error {
+			return client.Ping(ctx).Err()
+		})
 	} else {
 		log.Info("Redis is disabled")
 		redisClient = nil
@@ -73,7 +172,13 @@ func main() {
 
 	// Initialize queue service (optional)
 	var queueService *queuepkg.PubSubService
-	if !cfg.Queue.Disabled && os.Getenv("DISABLE_QUEUE") != "true" {
+	var changeFeed pkgdomain.ChangeFeedPublisher
+	// connectQueue is set when the queue could not be reached at startup
+	var connectQueue func(ctx context.Context) (*queuepkg.PubSubService, error)
+	if *devMode {
+		// The change feed goes to the embedded Redis instead of Pub/Sub
+		changeFeed = newRedisQueue(redisClient, cfg.Queue.RedisBackend)
+	} else if !cfg.Queue.Disabled && os.Getenv("DISABLE_QUEUE") != "true" {
 		queueConfig := &queuepkg.PubSubConfig{
 			ProjectID:          cfg.Queue.ProjectID,
 			HighPriorityTopic:  cfg.Queue.HighPriorityTopic,
@@ -83,17 +188,35 @@ func main() {
 			MaxRetries:         cfg.Queue.MaxRetries,
 			AckDeadline:        cfg.Queue.AckDeadline,
 			RetentionDuration:  cfg.Queue.RetentionDuration,
+			MaxExtension:       cfg.Queue.MaxExtension,
+			ShutdownWait:       cfg.Jobs.ShutdownWait,
 		}
 
 		queueMetrics := metrics.NewQueueMetrics()
-		var err error
-		queueService, err = queuepkg.NewPubSubService(context.Background(), queueConfig, queueMetrics)
+		connect := func(ctx context.Context) (*queuepkg.PubSubService, error) {
+			service, err := queuepkg.NewPubSubService(ctx, queueConfig, queueMetrics)
+			if err != nil {
+				return nil, err
+			}
+			if err := service.Ping(ctx); err != nil {
+				service.Close()
+				return nil, err
+			}
+			return service, nil
+		}
+		err := supervisor.Connect("queue", startupPolicy, func() error {
+			var err error
+			queueService, err = connect(context.Background())
+			return err
+		})
 		if err != nil {
-			log.Warnf("Failed to initialize queue service: %v", err)
+			log.Warnf("Queue is unavailable, the change feed starts when it recovers: %v", err)
+			connectQueue = connect
 		} else {
+			changeFeed = queueService
 			defer func() {
-				if queueService != nil {
-					queueService.Close()
+				if err := queueService.Close(); err != nil {
+					log.Warnf("Queue did not drain: %v", err)
 				}
 			}()
 		}
@@ -102,18 +225,16 @@ func main() {
 	}
 
 	var db *gorm.DB
+	var dbRouter *base.DBRouter
+	var dbCheck func(ctx context.Context) error
 	if os.Getenv("DISABLE_DB") != "true" {
-		// Initialize PostgreSQL connection
-		dbConfig := postgres.Config{
-			DSN: fmt.Sprintf(
-				"postgresql://%s:%s@%s:%d/%s?sslmode=%s",
-				cfg.Database.User, cfg.Database.Password, cfg.Database.Host,
-				cfg.Database.Port, cfg.Database.DBName, cfg.Database.SSLMode,
-			),
-			PreferSimpleProtocol: true,
-		}
-		log.Printf("Database DSN: %s", dbConfig.DSN)
-		db, err = gorm.Open(postgres.New(dbConfig), &gorm.Config{})
+		// Initialize database connection
+		log.Printf("Database driver: %s", cfg.Database.Driver)
+		err = supervisor.Connect("database", startupPolicy, func() error {
+			var err error
+			db, err = database.Open(cfg.Database, &gorm.Config{})
+			return err
+		})
 		if err != nil {
 			log.Fatalf("Failed to connect to database: %v", err)
 		}
@@ -124,72 +245,242 @@ func main() {
 			log.Fatalf("Failed to get underlying *sql.DB: %v", err)
 		}
 		defer sqlDB.Close()
+		dbCheck = sqlDB.PingContext
 
 		// Test database connection
-		if err := sqlDB.Ping(); err != nil {
+		if err := supervisor.Connect("database", startupPolicy, sqlDB.Ping); err != nil {
 			log.Fatalf("Failed to ping database: %v", err)
 		}
+		if err := base.ConfigurePool(db, cfg.Database.Pool); err != nil {
+			log.Fatalf("Failed to configure database pool: %v", err)
+		}
+		if err := base.InstrumentDB(db, "primary"); err != nil {
+			log.Warnf("Failed to instrument database: %v", err)
+		}
+
+		// Refuse to serve against a schema this binary was not built for.
+		// The versioned migrations are written for PostgreSQL.
+		if database.IsPostgres(db) {
+			migrator, err := migrations.New(sqlDB, migrations.FS())
+			if err != nil {
+				log.Fatalf("Failed to load migrations: %v", err)
+			}
+			if err := migrator.CheckVersion(context.Background()); err != nil {
+				log.Fatalf("Database schema check failed: %v (run \"api migrate up\")", err)
+			}
+		} else {
+			log.Warnf("Skipping schema version check for %s", cfg.Database.Driver)
+			if err := db.AutoMigrate(&pkgdomain.Track{}, &pkgdomain.User{}, &pkgdomain.OutboxEvent{}, &pkgdomain.UsageRecord{}, &pkgdomain.Delivery{},
+				&pkgdomain.PlayCount{}, &pkgdomain.SalesReport{}, &pkgdomain.ImportMapping{}, &pkgdomain.TrackRedirect{},
+				&pkgdomain.CustomFieldDefinition{}, &pkgdomain.Tag{}, &pkgdomain.TagRule{}, &pkgdomain.PublicAPIKey{}, &pkgdomain.Label{}, &pkgdomain.BootstrapMarker{}); err != nil {
+				log.Fatalf("Failed to create track, user, outbox, usage, delivery, royalty, import, redirect, custom field, tag, public API key, label and bootstrap tables: %v", err)
+			}
+		}
+
+		// Connect read replicas; a replica that cannot be opened is skipped
+		// and reads fall back to the primary
+		var replicas []base.Replica
+		for i, dsn := range cfg.Database.ReplicaDSNs() {
+			if !database.IsPostgres(db) {
+				break
+			}
+			name := cfg.Database.Replicas[i]
+			replicaDB, err := gorm.Open(postgres.New(postgres.Config{DSN: dsn, PreferSimpleProtocol: true}), &gorm.Config{})
+			if err != nil {
+				log.Warnf("Failed to connect to read replica %s: %v", name, err)
+				continue
+			}
+			if err := base.ConfigurePool(replicaDB, cfg.Database.ReplicaPool); err != nil {
+				log.Warnf("Failed to configure read replica %s: %v", name, err)
+				continue
+			}
+			if err := base.InstrumentDB(replicaDB, name); err != nil {
+				log.Warnf("Failed to instrument read replica %s: %v", name, err)
+			}
+			if replicaSQL, err := replicaDB.DB(); err == nil {
+				defer replicaSQL.Close()
+			}
+			replicas = append(replicas, base.Replica{Name: name, DB: replicaDB})
+		}
+		dbRouter = base.NewDBRouter(db, replicas, cfg.Database.ReplicaMaxLag, cfg.Database.ReplicaLagCheckInterval)
+		dbRouter.Start(context.Background())
+		defer dbRouter.Stop()
 	} else {
 		log.Info("Database is disabled")
 	}
 
 	// Initialize analytics service (optional)
-	var analyticsService *analytics.BigQueryService
+	var analyticsService analytics.Service
 	if os.Getenv("DISABLE_ANALYTICS") != "true" {
+		var sqlDB *sql.DB
+		if db != nil {
+			sqlDB, _ = db.DB()
+		}
 		var err error
-		analyticsService, err = analytics.NewBigQueryService(cfg.Queue.ProjectID, cfg.Queue.HighPriorityTopic)
+		analyticsService, err = analytics.NewService(&cfg.Analytics, sqlDB)
 		if err != nil {
 			log.Warnf("Failed to initialize analytics service: %v", err)
+		} else {
+			migrateCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			if err := analyticsService.Migrate(migrateCtx); err != nil {
+				log.Warnf("Failed to migrate analytics tables: %v", err)
+			}
+			cancel()
+			defer analyticsService.Close()
 		}
 	} else {
 		log.Info("Analytics service is disabled")
 	}
 
+	// Meter usage per label for invoicing
+	var usageUseCase *usecase.UsageUseCase
+	if db != nil {
+		usageUseCase = usecase.NewUsageUseCase(base.NewUsageRepository(db))
+		if cfg.Usage.SnapshotInterval > 0 {
+			go usageUseCase.Run(depsCtx, cfg.Usage.SnapshotInterval)
+		}
+	}
+
+	// Export catalog KPIs such as the review queue and enrichment backlog.
+	// The statistics query the metadata JSON with PostgreSQL operators.
+	if db != nil && database.IsPostgres(db) && cfg.KPI.Interval > 0 {
+		go usecase.NewCatalogKPICollector(base.NewCatalogStatsRepository(db)).Run(depsCtx, cfg.KPI.Interval)
+	}
+
+	// The job queues whose finished jobs are archived
+	var finishedJobs []pkgdomain.FinishedJobSource
+
 	// Initialize storage service (optional)
 	var storageService pkgdomain.StorageService
-	if os.Getenv("DISABLE_STORAGE") != "true" {
+	// usageTracker counts the bucket usage in Redis
+	var usageTracker pkgdomain.UsageTracker
+	if *devMode {
+		var err error
+		storageService, err = storagepkg.NewLocalStorage(filepath.Join(*devDir, "files"), "/dev/files", &cfg.Storage)
+		if err != nil {
+			log.Fatalf("Failed to initialize local storage: %v", err)
+		}
+	} else if os.Getenv("DISABLE_STORAGE") != "true" {
 		var err error
 		storageService, err = storagepkg.NewS3Storage(&cfg.Storage)
 		if err != nil {
 			log.Warnf("Failed to initialize storage service: %v", err)
 		}
+
+		// Count the bucket usage in Redis instead of listing the bucket on
+		// every upload, correcting the count now and then
+		if tracker, ok := storageService.(pkgdomain.UsageTracker); ok && redisClient != nil {
+			tracker.TrackUsage(redis.NewUsageCounter(redisClient))
+			usageTracker = tracker
+		}
+
+		// Replicate uploads to a second region and read from it when the
+		// primary fails. Replication jobs use their own queue so the
+		// enrichment jobs in the default queue are left alone.
+		if storageService != nil && cfg.Storage.ReplicaBucket != "" {
+			replicaCfg := cfg.Storage
+			replicaCfg.Bucket, replicaCfg.Region = cfg.Storage.ReplicaBucket, cfg.Storage.ReplicaRegion
+			replicaCfg.KMSKeyID = cfg.Storage.ReplicaKMSKeyID
+			replica, err := storagepkg.NewS3Storage(&replicaCfg)
+			if err != nil {
+				log.Fatalf("Failed to initialize storage replica: %v", err)
+			}
+			var replicationQueue pkgdomain.JobQueue
+			jobConfig := &pkgdomain.JobConfig{
+				NumWorkers:    2,
+				ShutdownWait:  cfg.Jobs.ShutdownWait,
+				DefaultTTL:    24 * time.Hour,
+				QueuePrefix:   "replication:",
+				RetryDelay:    5 * time.Second,
+				MaxRetryDelay: time.Hour,
+				TypeLimits:    configToJobTypeCounts(cfg.Jobs.Limits()),
+				TypeWeights:   configToJobTypeCounts(cfg.Jobs.Weights()),
+			}
+			if redisClient != nil {
+				redisQueue := jobs.NewRedisQueue(redisClient, jobConfig)
+				replicationQueue = redisQueue
+				finishedJobs = append(finishedJobs, redisQueue)
+			}
+			replicated := storagepkg.NewReplicatedStorage(storageService, replica, replicationQueue)
+			if replicationQueue != nil {
+				processor := jobs.NewProcessor(replicationQueue, jobConfig)
+				if err := processor.RegisterHandler(replicated); err != nil {
+					log.Fatalf("Failed to register replication handler: %v", err)
+				}
+				if err := processor.Start(depsCtx); err != nil {
+					log.Fatalf("Failed to start replication workers: %v", err)
+				}
+				defer func() {
+					if err := processor.Stop(); err != nil {
+						log.Warnf("Replication workers did not drain: %v", err)
+					}
+				}()
+			}
+			storageService = replicated
+			log.Infof("Replicating storage to bucket %s in %s", cfg.Storage.ReplicaBucket, cfg.Storage.ReplicaRegion)
+		}
 	} else {
 		log.Info("Storage service is disabled")
 	}
 
+	// Cross-cutting reactions to track events, such as auditing, subscribe
+	// to the event bus instead of being coded where the events happen
+	events := usecase.NewEventBus()
+	defer events.Close()
+	usecase.SubscribeAuditLog(events)
+	if analyticsService != nil {
+		usecase.SubscribeAnalytics(events, analyticsService)
+	}
+	if cfg.Events.WebhookURL != "" {
+		usecase.SubscribeEventWebhook(events, notify.NewWebhookNotifier(cfg.Events.WebhookURL, ""))
+	}
+
 	// Initialize validator
-	validatorService := validator.NewValidator()
+	validatorService := validator.WithEvents(validator.NewValidator(), events)
 
 	// Initialize AI service (optional)
 	var pkgAIService pkgdomain.AIService
+	var compositeAIService *ai.CompositeAIService
+	var provenanceAIService *ai.ProvenanceAIService
 	if os.Getenv("DISABLE_AI") != "true" {
 		// Create AI service config
 		aiConfig := &ai.Config{
-			EnableFallback:        true,
-			TimeoutSeconds:        int(cfg.AI.Timeout.Seconds()),
-			MinConfidence:         cfg.AI.MinConfidence,
-			MaxConcurrentRequests: cfg.AI.BatchSize,
-			RetryAttempts:         3,
-			RetryBackoffSeconds:   2,
+			EnableFallback:           true,
+			TimeoutSeconds:           int(cfg.AI.Timeout.Seconds()),
+			MinConfidence:            cfg.AI.MinConfidence,
+			MaxConcurrentRequests:    cfg.AI.MaxConcurrentRequests,
+			ExperimentTrafficPercent: cfg.AI.Experiment.TrafficPercent,
+			RetryAttempts:            3,
+			RetryBackoffSeconds:      2,
 			OpenAIConfig: &pkgdomain.OpenAIConfig{
 				APIKey:                cfg.AI.APIKey,
 				Endpoint:              cfg.AI.BaseURL,
+				Model:                 cfg.AI.ModelName,
+				ModelVersion:          cfg.AI.ModelVersion,
 				TimeoutSeconds:        int(cfg.AI.Timeout.Seconds()),
 				MinConfidence:         cfg.AI.MinConfidence,
-				MaxConcurrentRequests: cfg.AI.BatchSize,
+				MaxConcurrentRequests: cfg.AI.MaxConcurrentRequests,
 				RetryAttempts:         3,
 				RetryBackoffSeconds:   2,
-				RequestsPerSecond:     10,
+				RequestsPerSecond:     cfg.AI.OpenAIRequestsPerSecond,
+				MaxPromptTokens:       cfg.AI.MaxPromptTokens,
+				AudioFeatures:         cfg.AI.OpenAIAudioFeatures,
 			},
 			Qwen2Config: &pkgdomain.Qwen2Config{
 				APIKey:                cfg.AI.APIKey,
 				Endpoint:              cfg.AI.BaseURL,
+				Model:                 cfg.AI.Qwen2Model,
+				ModelVersion:          cfg.AI.Qwen2ModelVersion,
 				TimeoutSeconds:        int(cfg.AI.Timeout.Seconds()),
 				MinConfidence:         cfg.AI.MinConfidence,
-				MaxConcurrentRequests: cfg.AI.BatchSize,
+				MaxConcurrentRequests: cfg.AI.MaxConcurrentRequests,
 				RetryAttempts:         3,
 				RetryBackoffSeconds:   2,
+				RequestsPerSecond:     cfg.AI.Qwen2RequestsPerSecond,
+				MaxPromptTokens:       cfg.AI.MaxPromptTokens,
+				AudioFeatures:         cfg.AI.Qwen2AudioFeatures,
 			},
+			RoutingPolicy: pkgdomain.AIRoutingPolicy(cfg.AI.RoutingPolicy),
 		}
 
 		// Create composite AI service
@@ -197,7 +488,24 @@ func main() {
 		if err != nil {
 			log.Warnf("Failed to create composite AI service: %v", err)
 		} else {
-			pkgAIService = compositeService
+			compositeAIService, _ = compositeService.(*ai.CompositeAIService)
+			if cfg.AI.HealthCheckInterval > 0 {
+				go compositeAIService.RunHealthChecks(depsCtx, cfg.AI.HealthCheckInterval)
+			}
+			// Identical audio is only enriched once per model version
+			enrichment := compositeService
+			if redisClient != nil {
+				enrichment = cached.NewAIService(redisClient, compositeService,
+					pkgdomain.ModelVersionKey(compositeAIService.ModelVersions()), cfg.AI.CacheTTL)
+			}
+			provenanceAIService = ai.NewProvenanceAIService(enrichment, pkgdomain.MergePolicy{
+				OverwriteManual: cfg.AI.OverwriteManualEdits,
+			})
+			pkgAIService = provenanceAIService
+			if usageUseCase != nil {
+				pkgAIService = ai.NewMeteredAIService(pkgAIService, usageUseCase)
+			}
+			pkgAIService = ai.NewEventAIService(pkgAIService, events)
 		}
 	} else {
 		log.Info("AI service is disabled")
@@ -205,81 +513,800 @@ func main() {
 
 	// Initialize repositories and stores
 	var (
-		baseTrackRepo   domain.TrackRepository
-		pkgTrackRepo    pkgdomain.TrackRepository
-		baseUserRepo    domain.UserRepository
-		pkgUserRepo     pkgdomain.UserRepository
-		sessionStore    domain.SessionStore
-		sessionStorePkg pkgdomain.SessionStore
+		pkgTrackRepo pkgdomain.TrackRepository
+		pkgUserRepo  pkgdomain.UserRepository
+		sessionStore pkgdomain.SessionStore
 	)
 
 	if db != nil {
-		baseTrackRepo = base.NewTrackRepository(db)
-		pkgTrackRepo = base.NewPkgTrackRepository(db)
-		baseUserRepo = base.NewUserRepository(db)
-		pkgUserRepo = base.NewPkgUserRepository(db)
+		pkgTrackRepo = base.NewRoutedPkgTrackRepository(dbRouter)
+		pkgTrackRepo.(*base.PkgTrackRepository).SetEvents(events)
+		pkgUserRepo = base.NewRoutedPkgUserRepository(dbRouter)
 	}
 
-	var sessionStoreWrapper *converter.SessionStoreWrapper
-	if redisClient != nil {
+	if *devMode {
+		sessionStore = base.NewInMemorySessionRepository()
+	} else if redisClient != nil {
 		sessionStore = redis.NewSessionStore(redisClient, configToDomainSession(cfg.Session))
-		sessionStorePkg = redis.NewPkgSessionStore(redisClient, configToPkgSession(cfg.Session))
-		sessionStoreWrapper = converter.NewSessionStoreWrapper(sessionStore, sessionStorePkg)
 	} else {
 		log.Info("Session store is disabled (Redis not available)")
-		sessionStoreWrapper = converter.NewSessionStoreWrapper(nil, nil)
 	}
 
-	trackRepoWrapper := converter.NewTrackRepositoryWrapper(baseTrackRepo, pkgTrackRepo)
-	userRepoWrapper := converter.NewUserRepositoryWrapper(baseUserRepo, pkgUserRepo)
+	if pkgTrackRepo != nil && redisClient != nil {
+		pkgTrackRepo = cached.NewTrackRepository(redisClient, pkgTrackRepo, cfg.Redis.TrackCacheTTL)
+	}
 
 	// Initialize auth service
 	authService := usecase.NewAuthService(&cfg.Auth)
-	authServiceWrapper := converter.NewAuthServiceWrapper(authService, nil)
+	var refreshTokenStore pkgdomain.RefreshTokenStore
+	if redisClient != nil {
+		// Tokens from revoked refresh token families are rejected everywhere
+		// the auth service validates, middleware included
+		refreshTokenStore = redis.NewRefreshTokenStore(redisClient)
+		authService = usecase.NewRevocationAwareAuthService(authService, refreshTokenStore)
+	}
 
 	// Initialize use cases
-	authUseCase := usecase.NewAuthUseCase(userRepoWrapper.Internal(), sessionStoreWrapper.Internal(), authServiceWrapper.Internal())
-	userUseCase := usecase.NewUserUseCase(userRepoWrapper.Pkg())
+	authUseCase := usecase.NewAuthUseCase(pkgUserRepo, sessionStore, authService)
+	if refreshTokenStore != nil {
+		authUseCase.SetRefreshTokenStore(refreshTokenStore, cfg.Auth.RefreshTokenTTL)
+	}
+	userUseCase := usecase.NewUserUseCase(pkgUserRepo)
+	bulkEditUseCase := usecase.NewBulkEditUseCase(pkgTrackRepo, base.NewInMemoryBulkEditJobRepository())
+	// Status changes go through the workflow, which publishes them on the
+	// change feed once it starts
+	trackWorkflow := usecase.NewTrackWorkflowUseCase(pkgTrackRepo)
+	bulkEditUseCase.SetWorkflow(trackWorkflow)
+
+	// Watch queue lag and hold back publishers of topics that fall behind.
+	// Track writes feed the change feed through the outbox.
+	var lagAlerter pkgdomain.QueueAlerter
+	if cfg.Queue.LagAlertWebhookURL != "" {
+		lagAlerter = notify.NewWebhookNotifier(cfg.Queue.LagAlertWebhookURL, "")
+	}
+	queueMonitor := usecase.NewQueueMonitor(usecase.QueueMonitorConfig{
+		LagThreshold: cfg.Queue.LagThreshold,
+		MaxLag:       cfg.Queue.MaxLag,
+	}, lagAlerter)
+	if redisClient != nil {
+		queueMonitor.AddSource(jobs.NewRedisQueue(redisClient, &pkgdomain.JobConfig{QueuePrefix: "jobs:"}))
+	}
+	if db != nil {
+		queueMonitor.AddSource(usecase.OutboxQueueStats(base.NewOutboxRepository(db)))
+	}
+	if cfg.Queue.LagInterval > 0 {
+		go queueMonitor.Run(depsCtx, cfg.Queue.LagInterval)
+	}
+	// Start the change feed publisher when both the outbox and the queue are
+	// available. A queue that was down at startup starts it once it recovers.
+	var outboxPublisher atomic.Pointer[usecase.OutboxPublisher]
+	var recoveredQueue atomic.Pointer[queuepkg.PubSubService]
+	startChangeFeed := func(feed pkgdomain.ChangeFeedPublisher) {
+		feed = queuepkg.NewMonitoredPublisher(feed, queueMonitor)
+		publisher := usecase.NewOutboxPublisher(base.NewOutboxRepository(db), feed, usecase.OutboxPublisherConfig{
+			Topic:        cfg.Queue.ChangeFeedTopic,
+			PollInterval: cfg.Queue.OutboxPollInterval,
+			BatchSize:    cfg.Queue.OutboxBatchSize,
+		})
+		publisher.Start(context.Background())
+		outboxPublisher.Store(publisher)
+		trackWorkflow.OnTransition(usecase.PublishStatusChanges(feed, cfg.Queue.ChangeFeedTopic))
+	}
+	defer func() {
+		if publisher := outboxPublisher.Load(); publisher != nil {
+			publisher.Stop()
+		}
+		if service := recoveredQueue.Load(); service != nil {
+			service.Close()
+		}
+	}()
+	// Handlers registered with the consumer receive their Pub/Sub topics
+	// once the queue is connected
+	queueConsumer := queuepkg.NewConsumer()
+	startConsumer := func(service *queuepkg.PubSubService) {
+		if err := queueConsumer.Start(context.Background(), service); err != nil {
+			log.Warnf("Failed to start the queue consumer: %v", err)
+		}
+	}
+	if queueService != nil {
+		startConsumer(queueService)
+	}
+	switch {
+	case db != nil && changeFeed != nil:
+		startChangeFeed(changeFeed)
+	case connectQueue != nil:
+		deps.Reconnect("queue", func(ctx context.Context) error {
+			service, err := connectQueue(ctx)
+			if err != nil {
+				return err
+			}
+			recoveredQueue.Store(service)
+			return nil
+		}, func() {
+			startConsumer(recoveredQueue.Load())
+			if db != nil {
+				startChangeFeed(recoveredQueue.Load())
+			}
+		})
+	default:
+		log.Info("Change feed publisher is disabled")
+	}
 
 	// Initialize handlers
 	healthHandler := handler.NewHealthHandler(redisClient)
+
+	// Dependencies reported by /health/ready. Only the database is
+	// required to serve; the others degrade the service when they fail.
+	healthHandler.AddCheck(handler.DependencyCheck{Name: "database", Critical: true, Check: dbCheck})
+	redisCheck := handler.DependencyCheck{Name: "redis"}
+	if redisClient != nil {
+		redisCheck.Check = func(ctx context.Context) error { return redisClient.Ping(ctx).Err() }
+	}
+	healthHandler.AddCheck(redisCheck)
+	storageCheck := handler.DependencyCheck{Name: "storage"}
+	if storageService != nil {
+		storageCheck.Check = handler.PingCheck(storageService)
+	}
+	healthHandler.AddCheck(storageCheck)
+	queueCheck := handler.DependencyCheck{Name: "queue"}
+	if changeFeed != nil {
+		queueCheck.Check = handler.PingCheck(changeFeed)
+	} else if connectQueue != nil {
+		queueCheck.Check = func(ctx context.Context) error {
+			if service := recoveredQueue.Load(); service != nil {
+				return service.Ping(ctx)
+			}
+			return fmt.Errorf("queue is not connected")
+		}
+	}
+	healthHandler.AddCheck(queueCheck)
+	aiCheck := handler.DependencyCheck{Name: "ai"}
+	if pkgAIService != nil {
+		aiCheck.Check = handler.HTTPCheck(http.DefaultClient, cfg.AI.BaseURL)
+	}
+	healthHandler.AddCheck(aiCheck)
+	audioCaps := audio.DetectCapabilities()
+	if !audioCaps.FFmpeg {
+		log.Warnf("FFmpeg not found, only %v audio can be analyzed", audioCaps.NativeFormats)
+	}
+	healthHandler.SetAudioCapabilities(audioCaps)
 	var metricsHandler *handler.MetricsHandler
 	if os.Getenv("DISABLE_METRICS") != "true" {
 		metricsHandler = handler.NewMetricsHandler()
 	}
 
-	authHandler := handler.NewAuthHandler(authUseCase, userUseCase, sessionStoreWrapper.Internal())
+	authHandler := handler.NewAuthHandler(authUseCase, userUseCase, sessionStore)
+	if err := authHandler.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("Failed to configure trusted proxies: %v", err)
+	}
+	var passwordResetHandler *handler.PasswordResetHandler
+	if redisClient != nil {
+		passwordResetUseCase := usecase.NewPasswordResetUseCase(
+			pkgUserRepo,
+			sessionStore,
+			authService,
+			redis.NewPasswordResetStore(redisClient),
+			notify.NewWebhookNotifier(cfg.Auth.PasswordResetWebhookURL, cfg.Auth.PasswordResetURL),
+			usecase.PasswordResetConfig{
+				Secret:            cfg.Auth.JWTSecret,
+				TokenTTL:          cfg.Auth.PasswordResetTTL,
+				MaxRequests:       cfg.Auth.PasswordResetMaxRequests,
+				Window:            cfg.Auth.PasswordResetWindow,
+				MinPasswordLength: cfg.Auth.PasswordMinLength,
+			},
+		)
+		passwordResetUseCase.SetRefreshTokenStore(refreshTokenStore, cfg.Auth.RefreshTokenTTL)
+		passwordResetHandler = handler.NewPasswordResetHandler(passwordResetUseCase)
+
+		authHandler.SetDeviceTracker(usecase.NewSessionDeviceTracker(
+			redis.NewDeviceRegistry(redisClient),
+			notify.NewWebhookNotifier(cfg.Auth.LoginAlertWebhookURL, ""),
+		))
+	}
 	trackHandler := handler.NewTrackHandler(
-		trackRepoWrapper.Pkg(),
+		pkgTrackRepo,
 		pkgAIService,
 		storageService,
 		validatorService,
 		errorTracker,
 	)
+	// Tracks are locked in Redis while a worker enriches them, so batch
+	// enrichments do not write the same track at once
+	var trackLocks *usecase.TrackLockUseCase
+	if redisClient != nil {
+		trackLocks = usecase.NewTrackLockUseCase(redis.NewTrackLocker(redisClient), cfg.AI.ProcessingLockTTL)
+		trackHandler.SetTrackLocks(trackLocks)
+	}
+	// Enrichment started by uploads runs on after the response and is
+	// waited for at shutdown
+	backgroundTasks := background.NewRunner(errorTracker, cfg.Server.BackgroundTaskTimeout)
+	trackHandler.SetBackgroundRunner(backgroundTasks)
+	trackHandler.SetWorkflow(trackWorkflow)
+	// Local storage URLs are neither signed nor expire, so dev mode streams
+	// audio through the API
+	if !*devMode {
+		trackHandler.SetStreamURLExpiry(cfg.Storage.StreamURLExpiry)
+	}
+	if analyticsService != nil {
+		trackHandler.SetAnalytics(analyticsService)
+	}
+	if storageService != nil && cfg.Scanner.Provider != pkgconfig.ScannerNone {
+		var fileScanner pkgdomain.FileScanner
+		if cfg.Scanner.Provider == pkgconfig.ScannerClamAV {
+			fileScanner = scanner.NewClamAV(cfg.Scanner.Address, cfg.Scanner.Timeout)
+		} else {
+			fileScanner = scanner.NewHTTPScanner(cfg.Scanner.URL, cfg.Scanner.APIKey, cfg.Scanner.Timeout)
+		}
+		trackHandler.SetUploadScanner(usecase.NewUploadScanner(
+			pkgTrackRepo, storageService, fileScanner, pkgAIService, errorTracker))
+		log.Infof("Uploads are scanned for malware with %s", cfg.Scanner.Provider)
+	}
+	// Per-user quota counters live in Redis so uploads are checked without
+	// listing the bucket
+	if storageService != nil && redisClient != nil && cfg.Storage.UserQuota > 0 {
+		var quotaNotifier pkgdomain.QuotaNotifier
+		if cfg.Storage.QuotaWebhookURL != "" {
+			quotaNotifier = notify.NewWebhookNotifier(cfg.Storage.QuotaWebhookURL, "")
+		}
+		trackHandler.SetStorageQuota(usecase.NewStorageQuotaUseCase(
+			redis.NewQuotaStore(redisClient), cfg.Storage.UserQuota, cfg.Storage.QuotaWarningPct, quotaNotifier))
+	}
+	var usageHandler *handler.UsageHandler
+	if usageUseCase != nil {
+		trackHandler.SetUsage(usageUseCase)
+		usageHandler = handler.NewUsageHandler(usageUseCase)
+	}
+	bulkEditHandler := handler.NewBulkEditHandler(bulkEditUseCase, errorTracker)
+	ddexHandler := handler.NewDDEXHandler(pkgTrackRepo)
+
+	// GraphQL serves the catalog as a graph; nested tracks and users are
+	// loaded in batches per request
+	var graphQLHandler *handler.GraphQLHandler
+	if pkgTrackRepo != nil {
+		graphQLSchema, err := executor.NewSchema(resolvers.NewResolver(generated.NewResolver(
+			pkgTrackRepo, pkgUserRepo, pkgAIService, usecase.NewDDEXService(nil, nil), authService, storageService)))
+		if err != nil {
+			log.Fatalf("Failed to build GraphQL schema: %v", err)
+		}
+		graphQLHandler = handler.NewGraphQLHandler(graphQLSchema)
+	}
+
+	// Track deliveries to DSPs; acknowledged tracks move to delivered
+	var deliveryHandler *handler.DeliveryHandler
+	if db != nil {
+		deliveryUseCase := usecase.NewDeliveryUseCase(base.NewDeliveryRepository(db), pkgTrackRepo)
+		deliveryUseCase.SetWorkflow(trackWorkflow)
+		deliveryHandler = handler.NewDeliveryHandler(deliveryUseCase)
+	}
+
+	// Labels define typed custom fields, which track writes and CSV
+	// imports are checked against
+	var customFieldHandler *handler.CustomFieldHandler
+	var customFields *usecase.CustomFieldUseCase
+	if db != nil {
+		customFields = usecase.NewCustomFieldUseCase(base.NewCustomFieldRepository(db))
+		trackHandler.SetCustomFields(customFields)
+		customFieldHandler = handler.NewCustomFieldHandler(customFields)
+	}
+
+	// Tags are kept in canonical form, and rules add them to tracks as
+	// they are saved
+	var tagHandler *handler.TagHandler
+	var tags *usecase.TagUseCase
+	if db != nil {
+		tags = usecase.NewTagUseCase(base.NewTagRepository(db), base.NewTagRuleRepository(db), pkgTrackRepo)
+		trackHandler.SetTags(tags)
+		tagHandler = handler.NewTagHandler(tags)
+	}
+
+	// Partners read approved tracks through the public catalog API with
+	// keys whose tier sets their quotas, which are counted in Redis
+	var publicHandler *handler.PublicHandler
+	var publicAPI *usecase.PublicAPIUseCase
+	if db != nil && redisClient != nil {
+		publicAPI = usecase.NewPublicAPIUseCase(base.NewPublicAPIKeyRepository(db), pkgTrackRepo,
+			redis.NewPublicAPIQuota(redisClient), configToPublicAPITiers(cfg.PublicAPI))
+		publicHandler = handler.NewPublicHandler(publicAPI)
+	}
+
+	// Import distributor CSV files through per-label mapping templates
+	var importHandler *handler.ImportHandler
+	if db != nil {
+		csvImports := usecase.NewCSVImportUseCase(base.NewImportMappingRepository(db), pkgTrackRepo)
+		csvImports.SetCustomFields(customFields)
+		csvImports.SetTags(tags)
+		importHandler = handler.NewImportHandler(csvImports)
+	}
+
+	// Aggregate the tracks of a label; the statistics query the metadata JSON
+	// with PostgreSQL operators and are cached when Redis is available
+	var labelStatsHandler *handler.LabelStatsHandler
+	if db != nil && database.IsPostgres(db) {
+		labelStats := base.NewLabelStatsRepository(db)
+		if redisClient != nil {
+			labelStats = cached.NewLabelStatsRepository(redisClient, labelStats, cfg.Redis.LabelStatsCacheTTL)
+			usecase.SubscribeLabelStatsInvalidation(events, labelStats.(pkgdomain.LabelStatsInvalidator))
+		}
+		labelStatsHandler = handler.NewLabelStatsHandler(labelStats)
+	}
+
+	// Find tracks that sound alike by the embeddings of their audio
+	// analysis, which pgvector stores and compares. The embeddings table
+	// only exists when vector search was on as the migrations ran.
+	var similarityHandler *handler.SimilarityHandler
+	if db != nil && database.IsPostgres(db) && cfg.Database.VectorSearch {
+		if db.Migrator().HasTable("track_embeddings") {
+			similarity := usecase.NewSimilarityUseCase(base.NewEmbeddingRepository(db), pkgTrackRepo)
+			similarityHandler = handler.NewSimilarityHandler(similarity, errorTracker)
+		} else {
+			log.Warnf("Similar track search is off: database.vector_search is on, but migration 000015 ran without it and created no track_embeddings table")
+		}
+	}
+
+	// Audio analyses are kept per analyzer version. When the version
+	// changes, a job queues the tracks analyzed before for analysis again.
+	var analysisHandler *handler.AnalysisHandler
+	if db != nil && database.IsPostgres(db) {
+		analysisRepo := base.NewAnalysisRepository(db)
+		analyses := usecase.NewAnalysisUseCase(analysisRepo, pkgTrackRepo)
+		if compositeAIService != nil {
+			compositeAIService.SetAudioFeatureSource(analysisRepo)
+		}
+		if redisClient != nil {
+			analyses.SetJobQueue(jobs.NewRedisQueue(redisClient, &pkgdomain.JobConfig{QueuePrefix: "jobs:"}))
+			scheduled, err := analyses.ScheduleReanalysis(context.Background(), audiorepo.AnalyzerVersion)
+			if err != nil {
+				log.Warnf("Failed to schedule reanalysis: %v", err)
+			} else if scheduled {
+				log.Infof("Analyzer version is now %s, queued reanalysis of tracks", audiorepo.AnalyzerVersion)
+			}
+		}
+		analysisHandler = handler.NewAnalysisHandler(analyses, errorTracker)
+	}
+
+	// Score the AI providers against tracks whose values people confirmed
+	var evaluationHandler *handler.EvaluationHandler
+	if db != nil && database.IsPostgres(db) && compositeAIService != nil {
+		evaluations := usecase.NewEvaluationUseCase(base.NewGoldenDatasetRepository(db), pkgTrackRepo,
+			compositeAIService, analyticsService, cfg.AI.EvaluationTargetAccuracy)
+		if cfg.AI.EvaluationInterval > 0 {
+			go evaluations.Run(depsCtx, cfg.AI.EvaluationInterval)
+		}
+		evaluationHandler = handler.NewEvaluationHandler(evaluations)
+	}
+
+	// Report the model versions the catalog was enriched with and enrich
+	// again the tracks made with outdated ones
+	var aiModelHandler *handler.AIModelHandler
+	if db != nil && database.IsPostgres(db) && compositeAIService != nil && pkgAIService != nil {
+		migration := usecase.NewAIModelMigrationUseCase(base.NewAIModelRepository(db),
+			compositeAIService, pkgTrackRepo, pkgAIService)
+		migration.SetTrackLocks(trackLocks)
+		aiModelHandler = handler.NewAIModelHandler(migration)
+	}
+
+	// Enrich large sets of tracks through the providers' batch APIs
+	var aiBatchHandler *handler.AIBatchHandler
+	if db != nil && database.IsPostgres(db) && compositeAIService != nil && provenanceAIService != nil {
+		var usage pkgdomain.UsageRecorder
+		if usageUseCase != nil {
+			usage = usageUseCase
+		}
+		aiBatches := usecase.NewAIBatchUseCase(base.NewAIBatchRepository(db), pkgTrackRepo,
+			compositeAIService, provenanceAIService, usage, cfg.AI.BatchMaxTracks)
+		aiBatches.SetTrackLocks(trackLocks)
+		if cfg.AI.BatchPollInterval > 0 {
+			go aiBatches.Run(depsCtx, cfg.AI.BatchPollInterval)
+		}
+		aiBatchHandler = handler.NewAIBatchHandler(aiBatches)
+	}
+
+	// Count plays from DSP usage reports for royalty reporting
+	var royaltyHandler *handler.RoyaltyHandler
+	if db != nil {
+		royaltyHandler = handler.NewRoyaltyHandler(usecase.NewPlayCountUseCase(base.NewPlayCountRepository(db), pkgTrackRepo))
+	}
+
+	// Provisioning tools create the first admin, default label, buckets and
+	// topics of a new environment with the setup token
+	var bootstrapHandler *handler.BootstrapHandler
+	if db != nil && cfg.Bootstrap.SetupToken != "" {
+		bootstrap := usecase.NewBootstrapUseCase(pkgUserRepo, base.NewBootstrapRepository(db), authService,
+			base.NewLabelRepository(db), usecase.BootstrapConfig{
+				SetupToken:        cfg.Bootstrap.SetupToken,
+				MinPasswordLength: cfg.Auth.PasswordMinLength,
+			})
+		bootstrap.SetUnitOfWork(base.NewUnitOfWork(db))
+		if buckets, ok := storageService.(pkgdomain.BucketProvisioner); ok {
+			bootstrap.SetBuckets(buckets)
+		}
+		if queueService != nil {
+			bootstrap.SetTopics(queueService, cfg.Queue.HighPriorityTopic, cfg.Queue.LowPriorityTopic,
+				cfg.Queue.DeadLetterTopic, cfg.Queue.ChangeFeedTopic)
+		}
+		bootstrapHandler = handler.NewBootstrapHandler(bootstrap)
+	}
+
+	// Merged tracks leave a redirect, and their deliveries and play counts
+	// move to the track they are merged into, all in one transaction
+	if db != nil {
+		duplicates := usecase.NewDuplicateUseCase(pkgTrackRepo)
+		duplicates.AddReferenceRewriter("deliveries", base.NewDeliveryRepository(db).(pkgdomain.TrackReferenceRewriter))
+		duplicates.AddReferenceRewriter("play_counts", base.NewPlayCountRepository(db).(pkgdomain.TrackReferenceRewriter))
+		duplicates.SetRedirects(base.NewTrackRedirectRepository(db))
+		duplicates.SetUnitOfWork(base.NewUnitOfWork(db))
+		trackHandler.SetDuplicates(duplicates)
+	}
+
+	// Tier masters by age and restore archived ones where storage supports it
+	var restoreHandler *handler.StorageRestoreHandler
+	if tierer, ok := storageService.(pkgdomain.StorageTierer); ok {
+		policy := pkgdomain.LifecyclePolicy{
+			Prefix:                    cfg.Storage.LifecyclePrefix,
+			InfrequentAccessAfterDays: cfg.Storage.InfrequentAccessAfterDays,
+			ArchiveAfterDays:          cfg.Storage.ArchiveAfterDays,
+		}
+		go func() {
+			if err := tierer.ApplyLifecycle(depsCtx, policy); err != nil {
+				log.Warnf("Failed to apply storage lifecycle policy: %v", err)
+			}
+		}()
+
+		var restoreNotifier pkgdomain.RestoreNotifier
+		if cfg.Storage.RestoreWebhookURL != "" {
+			restoreNotifier = notify.NewWebhookNotifier(cfg.Storage.RestoreWebhookURL, "")
+		}
+		restoreUseCase := usecase.NewStorageRestoreUseCase(pkgTrackRepo, tierer, restoreNotifier, cfg.Storage.RestoreDays)
+		if cfg.Storage.RestoreCheckInterval > 0 {
+			go restoreUseCase.Run(depsCtx, cfg.Storage.RestoreCheckInterval)
+		}
+		restoreHandler = handler.NewStorageRestoreHandler(restoreUseCase, errorTracker)
+	}
+
+	// Check stored files against the checksums recorded at upload
+	var integrityHandler *handler.StorageIntegrityHandler
+	if storageService != nil {
+		integrityUseCase := usecase.NewIntegrityUseCase(pkgTrackRepo, storageService, errorTracker)
+		if cfg.Storage.IntegrityAuditInterval > 0 {
+			go integrityUseCase.Run(depsCtx, cfg.Storage.IntegrityAuditInterval)
+		}
+		integrityHandler = handler.NewStorageIntegrityHandler(integrityUseCase, errorTracker)
+	}
+
+	// Data export and erasure for data protection requests
+	var complianceHandler *handler.ComplianceHandler
+	if pkgUserRepo != nil {
+		complianceUseCase := usecase.NewComplianceUseCase(pkgUserRepo, sessionStore, pkgTrackRepo)
+		complianceHandler = handler.NewComplianceHandler(complianceUseCase, errorTracker)
+	}
+
+	// System stats for the ops dashboard
+	systemStats := usecase.NewSystemStatsUseCase()
+	systemStats.SetQueueLag(queueMonitor)
+	if redisClient != nil {
+		jobQueue := jobs.NewRedisQueue(redisClient, &pkgdomain.JobConfig{QueuePrefix: "jobs:"})
+		systemStats.AddQueue(jobQueue)
+		systemStats.SetJobs(jobQueue)
+		finishedJobs = append(finishedJobs, jobQueue)
+	}
+	if db != nil {
+		systemStats.AddQueue(usecase.OutboxQueueStats(base.NewOutboxRepository(db)))
+		if sqlDB, err := db.DB(); err == nil {
+			systemStats.SetDatabase(sqlDB)
+		}
+	}
+	if source, ok := changeFeed.(pkgdomain.QueueStatsSource); ok {
+		systemStats.AddQueue(source)
+	}
+	if storageService != nil {
+		systemStats.SetStorage(storageService, cfg.Storage.TotalQuota)
+	}
+	if compositeAIService != nil {
+		systemStats.SetAIProviders(compositeAIService)
+	}
+	systemStatsHandler := handler.NewSystemStatsHandler(systemStats)
+
+	// Move finished jobs out of Redis for long-term reporting. The reports
+	// use PostgreSQL functions.
+	var jobHistoryHandler *handler.JobHistoryHandler
+	if db != nil && database.IsPostgres(db) {
+		jobArchive := usecase.NewJobArchiveUseCase(base.NewJobHistoryRepository(db), cfg.Jobs.ArchiveAfter, finishedJobs...)
+		if cfg.Jobs.ArchiveInterval > 0 && len(finishedJobs) > 0 {
+			go jobArchive.Run(depsCtx, cfg.Jobs.ArchiveInterval)
+		}
+		jobHistoryHandler = handler.NewJobHistoryHandler(jobArchive)
+	}
+
+	writeBackpressure := middleware.QueueBackpressure(queueMonitor, cfg.Queue.LagInterval, "outbox")
+
+	// Dead letters of the Redis queue can be inspected and replayed by admins
+	var deadLetterHandler *handler.DeadLetterHandler
+	var maintenanceHandler *handler.MaintenanceHandler
+	if redisClient != nil {
+		deadLetters, ok := changeFeed.(redisQueue)
+		if !ok {
+			deadLetters = newRedisQueue(redisClient, cfg.Queue.RedisBackend)
+			defer deadLetters.Close()
+		}
+		deadLetterHandler = handler.NewDeadLetterHandler(usecase.NewDeadLetterUseCase(deadLetters))
+
+		// Periodic maintenance runs on the replica holding the lead in
+		// Redis, so each run happens once however many replicas there are
+		host, _ := os.Hostname()
+		instance := fmt.Sprintf("%s-%d", host, os.Getpid())
+		sched := scheduler.New(instance, redis.NewMaintenanceCoordinator(redisClient, instance),
+			redis.NewMaintenanceHistory(redisClient, cfg.Maintenance.HistorySize), cfg.Maintenance.LeaseTTL)
+		addMaintenanceTask := func(name, spec string, task scheduler.Task) {
+			if err := sched.Add(name, spec, task); err != nil {
+				log.Fatalf("Failed to schedule maintenance: %v", err)
+			}
+		}
+		if remover, ok := sessionStore.(pkgdomain.ExpiredSessionRemover); ok && cfg.Session.CleanupInterval > 0 {
+			addMaintenanceTask("session-cleanup", scheduler.Every(cfg.Session.CleanupInterval), remover.DeleteExpired)
+		}
+		if cleaner, ok := storageService.(pkgdomain.TempFileCleaner); ok && cfg.Storage.CleanupInterval > 0 {
+			addMaintenanceTask("storage-cleanup", scheduler.Every(cfg.Storage.CleanupInterval), cleaner.CleanupTempFiles)
+		}
+		if usageTracker != nil && cfg.Storage.UsageReconcileInterval > 0 {
+			addMaintenanceTask("storage-usage-reconcile", scheduler.Every(cfg.Storage.UsageReconcileInterval), func(ctx context.Context) error {
+				_, err := usageTracker.ReconcileUsage(ctx)
+				return err
+			})
+		}
+		if cfg.Maintenance.DeadLetterExpiry != "" {
+			addMaintenanceTask("dead-letter-expiry", cfg.Maintenance.DeadLetterExpiry, func(ctx context.Context) error {
+				n, err := deadLetters.ExpireDeadLetters(ctx)
+				if n > 0 {
+					log.Infof("Removed %d expired dead letters", n)
+				}
+				return err
+			})
+		}
+		go sched.Run(depsCtx)
+		maintenanceHandler = handler.NewMaintenanceHandler(sched)
+	}
+	openAPIHandler := handler.NewOpenAPIHandler(openapi.Spec())
+
+	// Runtime settings are shared through Redis and can be changed with the
+	// admin API without a restart
+	var runtimeConfigHandler *handler.RuntimeConfigHandler
+	var runtimeConfig *usecase.RuntimeConfigUseCase
+	if redisClient != nil {
+		runtimeConfig = usecase.NewRuntimeConfigUseCase(redis.NewRuntimeConfigStore(redisClient), pkgdomain.RuntimeSettings{
+			AIMinConfidence:          cfg.AI.MinConfidence,
+			ExperimentTrafficPercent: cfg.AI.Experiment.TrafficPercent,
+			RateLimitPerMinute:       cfg.Server.RateLimitPerMinute,
+		})
+		if err := runtimeConfig.Load(context.Background()); err != nil {
+			log.Warnf("Failed to load runtime settings, using configured values: %v", err)
+		}
+		if redisDep != nil {
+			redisDep.OnRecover(func() {
+				if err := runtimeConfig.Load(context.Background()); err != nil {
+					log.Warnf("Failed to reload runtime settings: %v", err)
+				}
+			})
+		}
+		if compositeAIService != nil {
+			runtimeConfig.Watch(func(settings pkgdomain.RuntimeSettings) {
+				compositeAIService.SetMinConfidence(settings.AIMinConfidence)
+				compositeAIService.SetExperimentTrafficPercent(settings.ExperimentTrafficPercent)
+				if err := compositeAIService.ForceProvider(settings.AIForcedProvider); err != nil {
+					log.Warnf("Failed to force AI provider: %v", err)
+				}
+			})
+		}
+
+		runtimeCtx, stopRuntimeConfig := context.WithCancel(context.Background())
+		defer stopRuntimeConfig()
+		go func() {
+			// Resubscribe after Redis drops the subscription
+			for {
+				err := runtimeConfig.Run(runtimeCtx)
+				if runtimeCtx.Err() != nil {
+					return
+				}
+				log.Warnf("Runtime settings subscription ended, retrying: %v", err)
+				select {
+				case <-runtimeCtx.Done():
+					return
+				case <-time.After(cfg.Server.DependencyCheckInterval):
+				}
+			}
+		}()
+		runtimeConfigHandler = handler.NewRuntimeConfigHandler(runtimeConfig)
+	}
+
+	// Show the health of the AI providers and force traffic to one of them
+	// during incidents
+	var aiRoutingHandler *handler.AIRoutingHandler
+	if compositeAIService != nil {
+		aiRoutingHandler = handler.NewAIRoutingHandler(compositeAIService, runtimeConfig)
+	}
+
+	// Labels may bring their own AI provider keys, stored encrypted
+	var labelAIConfigHandler *handler.LabelAIConfigHandler
+	if db != nil && cfg.AI.CredentialKey != "" && compositeAIService != nil {
+		credentialCipher, err := encryption.NewCipher(cfg.AI.CredentialKey)
+		if err != nil {
+			log.Fatalf("Invalid AI credential key: %v", err)
+		}
+		labelAIConfigs := base.NewLabelAIConfigRepository(db, credentialCipher)
+		compositeAIService.SetLabelConfigs(labelAIConfigs)
+		labelAIConfigHandler = handler.NewLabelAIConfigHandler(usecase.NewLabelAIConfigUseCase(
+			labelAIConfigs, base.NewLabelRepository(db), compositeAIService.InvalidateLabel))
+	}
+
+	// Configuration is promoted between environments as signed bundles
+	var configPromotionHandler *handler.ConfigPromotionHandler
+	if db != nil && cfg.Promotion.SigningKey != "" {
+		promotion := usecase.NewConfigPromotionUseCase(usecase.ConfigPromotionConfig{
+			SigningKey:  cfg.Promotion.SigningKey,
+			Environment: cfg.Server.Environment,
+		}, base.NewTagRepository(db), base.NewTagRuleRepository(db), base.NewCustomFieldRepository(db),
+			base.NewImportMappingRepository(db), base.NewLabelRepository(db))
+		if runtimeConfig != nil {
+			promotion.SetRuntimeConfig(runtimeConfig)
+		}
+		configPromotionHandler = handler.NewConfigPromotionHandler(promotion)
+	}
 
 	// Initialize router with minimal middleware
 	router := gin.New()
+	// Forwarded client addresses are only believed from the proxies in front
+	// of the API
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("Failed to configure trusted proxies: %v", err)
+	}
 	router.Use(gin.Recovery())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.SecurityHeaders(middleware.SecurityHeadersConfig{
+		HSTSMaxAge:            cfg.Security.HSTSMaxAge,
+		HSTSIncludeSubdomains: cfg.Security.HSTSIncludeSubdomains,
+		FrameOptions:          cfg.Security.FrameOptions,
+	}))
+	// Preflights are answered before authentication, which they do not carry
+	if len(cfg.CORS.AllowedOrigins) > 0 {
+		router.Use(middleware.CORS(middleware.CORSConfig{
+			AllowedOrigins:   cfg.CORS.AllowedOrigins,
+			AllowedMethods:   cfg.CORS.AllowedMethods,
+			AllowedHeaders:   cfg.CORS.AllowedHeaders,
+			ExposedHeaders:   cfg.CORS.ExposedHeaders,
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           cfg.CORS.MaxAge,
+		}))
+	}
+	router.Use(middleware.Sentry(errorTracker))
+	if cfg.Server.CompressionMinSize > 0 {
+		router.Use(middleware.Compression(middleware.CompressionConfig{
+			MinSize:      cfg.Server.CompressionMinSize,
+			ContentTypes: cfg.Server.CompressionTypes,
+		}))
+	}
+	if analyticsService != nil {
+		router.Use(middleware.Analytics(analyticsService))
+	}
+
+	// Routes backed by Redis answer 503 while it is down instead of failing
+	// every request, and serve normally again once it recovers
+	var requireRedis []gin.HandlerFunc
+	whenRedis := func(next gin.HandlerFunc) gin.HandlerFunc { return next }
+	if redisDep != nil {
+		requireRedis = append(requireRedis, middleware.RequireDependency(redisDep))
+		whenRedis = func(next gin.HandlerFunc) gin.HandlerFunc {
+			return middleware.WhenAvailable(redisDep, next)
+		}
+	}
+
+	// Repeats of creating requests that carry an Idempotency-Key header are
+	// answered with the original response instead of creating duplicates
+	idempotent := func(c *gin.Context) { c.Next() }
+	if redisClient != nil && cfg.Server.IdempotencyTTL > 0 {
+		idempotent = whenRedis(middleware.Idempotency(redis.NewIdempotencyStore(redisClient), cfg.Server.IdempotencyTTL))
+	}
+
+	// The public catalog API authenticates partners by API key rather than
+	// a session, so it is registered before the session middleware
+	if publicHandler != nil {
+		var usage pkgdomain.UsageRecorder
+		if usageUseCase != nil {
+			usage = usageUseCase
+		}
+		public := router.Group("/public/v1", requireRedis...)
+		public.Use(middleware.PublicAPIKey(publicAPI, usage))
+		public.GET("/tracks", publicHandler.ListTracks)
+		public.GET("/tracks/:id", publicHandler.GetTrack)
+	}
+
+	// Bootstrap requests carry the setup token instead of a session
+	if bootstrapHandler != nil {
+		router.POST("/api/v1/bootstrap", middleware.APIVersion(middleware.APIVersion1), bootstrapHandler.Bootstrap)
+	}
+
+	// Probes come from the orchestrator, which has no session or token,
+	// and must not fail while Redis is down
+	router.GET("/health", healthHandler.Check)
+	router.GET("/health/live", healthHandler.Live)
+	router.GET("/health/ready", healthHandler.Ready)
 
 	// Only add auth middleware if session store is available
-	if sessionStoreWrapper.Pkg() != nil {
-		router.Use(middleware.Auth(authServiceWrapper.Pkg()))
-		router.Use(middleware.Session(sessionStoreWrapper.Pkg(), configToPkgSession(cfg.Session)))
+	if sessionStore != nil {
+		router.Use(middleware.Auth(authService))
+		router.Use(whenRedis(middleware.Session(sessionStore, configToDomainSession(cfg.Session))))
+		// Browsers send the session cookie with forged requests too
+		if cfg.Security.CSRF {
+			router.Use(middleware.CSRF(middleware.CSRFConfig{
+				Secret:       cfg.Auth.JWTSecret,
+				LoginPaths:   []string{"/api/v1/auth/login", "/api/v1/auth/register"},
+				CookiePath:   cfg.Session.CookiePath,
+				CookieDomain: cfg.Session.CookieDomain,
+				CookieSecure: cfg.Session.CookieSecure,
+			}))
+		}
 	}
 
+	// Destructive operations compute their effect without committing it
+	// when asked with ?dry_run=true; other routes refuse the parameter
+	router.Use(middleware.DryRun(
+		"POST /api/v1/tracks/bulk-edit",
+		"POST /api/v1/tracks/batch-delete",
+		"POST /api/v1/ddex/import",
+		"POST /api/v1/labels/:label_id/imports",
+		"POST /api/v1/deliveries/takedowns",
+		"POST /api/v1/admin/config/import",
+	))
+
 	// Register routes
-	router.GET("/health", healthHandler.Check)
 	if metricsHandler != nil {
 		router.GET("/metrics", metricsHandler.PrometheusHandler())
 	}
+	router.GET("/openapi.json", openAPIHandler.Spec)
+	if *devMode {
+		// Stored audio is served to signed-in users only, as track streams are
+		devFiles := router.Group("/dev/files", middleware.RequireSession(sessionStore),
+			middleware.RequirePermission(pkgdomain.PermissionReadTrack))
+		devFiles.Static("", filepath.Join(*devDir, "files"))
+	}
 
 	// API routes
-	api := router.Group("/api/v1")
+	var rateLimit []gin.HandlerFunc
+	if runtimeConfig != nil {
+		rateLimit = append(rateLimit, whenRedis(pkgmiddleware.RateLimit(pkgmiddleware.RateLimitConfig{
+			RedisClient: redisClient,
+			Limit: func() int {
+				return runtimeConfig.Current().RateLimitPerMinute
+			},
+		})))
+	}
+
+	api := router.Group("/api/v1", middleware.APIVersion(middleware.APIVersion1))
+	if deprecated, sunset := cfg.API.V1Dates(); !deprecated.IsZero() || !sunset.IsZero() {
+		api.Use(middleware.Deprecation(middleware.DeprecationConfig{
+			Deprecated: deprecated,
+			Sunset:     sunset,
+			Link:       cfg.API.V1DeprecationLink,
+			Successor:  "/api/v2",
+		}))
+	}
+	if apiDoc, err := openapi.Load(); err != nil {
+		log.Warnf("Request validation is disabled: %v", err)
+	} else {
+		api.Use(middleware.OpenAPIValidator(apiDoc, "/api/v1"))
+	}
+	api.Use(rateLimit...)
 	{
 		// Auth routes
 		auth := api.Group("/auth")
-		if sessionStoreWrapper.Pkg() != nil {
-			auth.Use(middleware.RequireSession(sessionStoreWrapper.Pkg()))
+		if passwordResetHandler != nil {
+			// Registered before the session requirement: these are used by
+			// signed-out users
+			reset := auth.Group("", requireRedis...)
+			reset.POST("/forgot-password", passwordResetHandler.ForgotPassword)
+			reset.POST("/reset-password", passwordResetHandler.ResetPassword)
+		}
+		if sessionStore != nil {
+			auth.Use(requireRedis...)
+			auth.Use(middleware.RequireSession(sessionStore))
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
 			auth.POST("/refresh", authHandler.RefreshToken)
@@ -288,19 +1315,255 @@ func main() {
 
 		// Track routes
 		tracks := api.Group("/tracks")
-		if sessionStoreWrapper.Pkg() != nil {
-			tracks.Use(middleware.RequireSession(sessionStoreWrapper.Pkg()))
+		if sessionStore != nil {
+			tracks.Use(requireRedis...)
+			tracks.Use(middleware.RequireSession(sessionStore))
 		}
 		{
-			tracks.POST("", trackHandler.CreateTrack)
+			tracks.POST("", idempotent, writeBackpressure, trackHandler.CreateTrack)
+			if storageService != nil {
+				tracks.POST("/upload", idempotent, writeBackpressure, trackHandler.UploadTrack)
+				tracks.POST("/upload-url", idempotent, writeBackpressure, trackHandler.CreateUploadURL)
+				tracks.POST("/:id/confirm-upload", writeBackpressure, trackHandler.ConfirmUpload)
+				tracks.GET("/:id/stream", middleware.RequirePermission(pkgdomain.PermissionReadTrack), trackHandler.StreamTrack)
+			}
+			tracks.POST("/export", idempotent, trackHandler.ExportTracks)
 			tracks.GET("/:id", trackHandler.GetTrack)
-			tracks.PUT("/:id", trackHandler.UpdateTrack)
-			tracks.DELETE("/:id", trackHandler.DeleteTrack)
+			tracks.GET("/:id/provenance", trackHandler.GetTrackProvenance)
+			tracks.GET("/:id/transitions", trackHandler.GetTrackTransitions)
+			tracks.POST("/:id/transitions", writeBackpressure, trackHandler.TransitionTrack)
+			tracks.GET("/:id/duplicates", trackHandler.GetTrackDuplicates)
+			tracks.GET("/:id/harmonic-mixes", trackHandler.GetHarmonicMixes)
+			tracks.POST("/:id/merge-into/:target_id", writeBackpressure, trackHandler.MergeTrack)
+			tracks.PUT("/:id", writeBackpressure, trackHandler.UpdateTrack)
+			tracks.PATCH("/:id", writeBackpressure, trackHandler.PatchTrack)
+			tracks.DELETE("/:id", writeBackpressure, trackHandler.DeleteTrack)
 			tracks.GET("", trackHandler.ListTracks)
 			tracks.POST("/search", trackHandler.SearchTracks)
+			tracks.POST("/bulk-edit", writeBackpressure, bulkEditHandler.BulkEdit)
+			tracks.POST("/batch-delete", writeBackpressure, trackHandler.BatchDeleteTracks)
+			tracks.GET("/bulk-edit/:id", bulkEditHandler.GetBulkEditJob)
+			if restoreHandler != nil {
+				tracks.GET("/:id/restore", restoreHandler.GetRestore)
+				tracks.POST("/:id/restore", restoreHandler.Restore)
+			}
+			if integrityHandler != nil {
+				tracks.GET("/:id/integrity", integrityHandler.GetIntegrity)
+			}
+			if analysisHandler != nil {
+				tracks.GET("/:id/analysis", analysisHandler.GetTrackAnalysis)
+			}
+			if similarityHandler != nil {
+				tracks.GET("/:id/similar", similarityHandler.GetSimilarTracks)
+				tracks.PUT("/:id/embedding", writeBackpressure, similarityHandler.PutTrackEmbedding)
+			}
+		}
+
+		// DDEX ERN messages
+		ddex := api.Group("/ddex")
+		if sessionStore != nil {
+			ddex.Use(requireRedis...)
+			ddex.Use(middleware.RequireSession(sessionStore))
+		}
+		{
+			ddex.POST("/validate", ddexHandler.ValidateERN)
+			ddex.POST("/import", writeBackpressure, ddexHandler.ImportERN)
+			ddex.POST("/export", ddexHandler.ExportERN)
+		}
+
+		// Label custom fields, import mappings and the CSV imports using them
+		if importHandler != nil && sessionStore != nil {
+			labels := api.Group("/labels/:label_id")
+			labels.Use(requireRedis...)
+			labels.Use(middleware.RequireSession(sessionStore))
+			labels.GET("/import-mappings", importHandler.ListImportMappings)
+			labels.POST("/import-mappings", importHandler.CreateImportMapping)
+			labels.GET("/import-mappings/:id", importHandler.GetImportMapping)
+			labels.PUT("/import-mappings/:id", importHandler.UpdateImportMapping)
+			labels.DELETE("/import-mappings/:id", importHandler.DeleteImportMapping)
+			labels.POST("/imports", writeBackpressure, importHandler.ImportCSV)
+			labels.GET("/custom-fields", customFieldHandler.ListCustomFields)
+			labels.POST("/custom-fields", customFieldHandler.CreateCustomField)
+			labels.GET("/custom-fields/:name", customFieldHandler.GetCustomField)
+			labels.PUT("/custom-fields/:name", customFieldHandler.UpdateCustomField)
+			labels.DELETE("/custom-fields/:name", customFieldHandler.DeleteCustomField)
+		}
+
+		// Label statistics
+		if labelStatsHandler != nil {
+			labelStatsGroup := api.Group("/labels/:label_id")
+			if sessionStore != nil {
+				labelStatsGroup.Use(requireRedis...)
+				labelStatsGroup.Use(middleware.RequireSession(sessionStore))
+			}
+			labelStatsGroup.GET("/stats", labelStatsHandler.GetLabelStats)
+		}
+
+		// Tags and tagging rules; renames, merges and deletes rewrite tracks
+		// across the catalog, so changes are left to admins
+		if tagHandler != nil && sessionStore != nil {
+			admin := middleware.RequireRole(pkgdomain.RoleAdmin)
+			tagGroup := api.Group("/tags")
+			tagGroup.Use(requireRedis...)
+			tagGroup.Use(middleware.RequireSession(sessionStore))
+			tagGroup.GET("", tagHandler.ListTags)
+			tagGroup.POST("", tagHandler.CreateTag)
+			tagGroup.GET("/:name", tagHandler.GetTag)
+			tagGroup.PUT("/:name", tagHandler.UpdateTag)
+			tagGroup.POST("/:name/rename", admin, writeBackpressure, tagHandler.RenameTag)
+			tagGroup.POST("/:name/merge-into/:target", admin, writeBackpressure, tagHandler.MergeTag)
+			tagGroup.DELETE("/:name", admin, writeBackpressure, tagHandler.DeleteTag)
+
+			rules := api.Group("/tag-rules")
+			rules.Use(requireRedis...)
+			rules.Use(middleware.RequireSession(sessionStore))
+			rules.GET("", tagHandler.ListTagRules)
+			rules.GET("/:id", tagHandler.GetTagRule)
+			rules.POST("", admin, tagHandler.CreateTagRule)
+			rules.PUT("/:id", admin, tagHandler.UpdateTagRule)
+			rules.DELETE("/:id", admin, tagHandler.DeleteTagRule)
+		}
+
+		// Admin routes
+		if sessionStore != nil {
+			admin := api.Group("/admin")
+			admin.Use(requireRedis...)
+			admin.Use(middleware.RequireSession(sessionStore), middleware.RequireRole(pkgdomain.RoleAdmin))
+			admin.GET("/stats", systemStatsHandler.GetSystemStats)
+			if maintenanceHandler != nil {
+				admin.GET("/maintenance", maintenanceHandler.GetMaintenanceStatus)
+			}
+			if jobHistoryHandler != nil {
+				admin.GET("/jobs/history", jobHistoryHandler.ListJobHistory)
+				admin.GET("/jobs/history/report", jobHistoryHandler.GetJobHistoryReport)
+			}
+			if deadLetterHandler != nil {
+				admin.GET("/dead-letters/:topic", deadLetterHandler.ListDeadLetters)
+				admin.DELETE("/dead-letters/:topic", deadLetterHandler.PurgeDeadLetters)
+				admin.POST("/dead-letters/:topic/replay", deadLetterHandler.ReplayDeadLetters)
+				admin.GET("/dead-letters/:topic/:id", deadLetterHandler.GetDeadLetter)
+				admin.POST("/dead-letters/:topic/:id/replay", deadLetterHandler.ReplayDeadLetter)
+			}
+			if runtimeConfigHandler != nil {
+				admin.GET("/runtime-config", runtimeConfigHandler.GetRuntimeConfig)
+				admin.PATCH("/runtime-config", runtimeConfigHandler.UpdateRuntimeConfig)
+			}
+			if configPromotionHandler != nil {
+				admin.GET("/config/export", configPromotionHandler.ExportConfig)
+				admin.POST("/config/import", configPromotionHandler.ImportConfig)
+			}
+			if evaluationHandler != nil {
+				admin.GET("/ai/golden-tracks", evaluationHandler.ListGoldenTracks)
+				admin.PUT("/ai/golden-tracks/:track_id", evaluationHandler.SaveGoldenTrack)
+				admin.DELETE("/ai/golden-tracks/:track_id", evaluationHandler.DeleteGoldenTrack)
+				admin.POST("/ai/evaluations", evaluationHandler.RunEvaluation)
+				admin.GET("/ai/evaluations/latest", evaluationHandler.GetLatestEvaluation)
+			}
+			if aiModelHandler != nil {
+				admin.GET("/ai/model-coverage", aiModelHandler.GetModelCoverage)
+				admin.POST("/ai/reenrichment", aiModelHandler.StartReenrichment)
+				admin.GET("/ai/reenrichment", aiModelHandler.GetReenrichment)
+				admin.DELETE("/ai/reenrichment", aiModelHandler.CancelReenrichment)
+			}
+			if aiBatchHandler != nil {
+				admin.POST("/ai/batches", aiBatchHandler.SubmitBatch)
+				admin.GET("/ai/batches", aiBatchHandler.ListBatches)
+				admin.GET("/ai/batches/:id", aiBatchHandler.GetBatch)
+				admin.DELETE("/ai/batches/:id", aiBatchHandler.CancelBatch)
+			}
+			if aiRoutingHandler != nil {
+				admin.GET("/ai/routing", aiRoutingHandler.GetRouting)
+				admin.POST("/ai/routing/health-check", aiRoutingHandler.CheckHealth)
+				admin.PUT("/ai/routing/forced", aiRoutingHandler.ForceProvider)
+				admin.DELETE("/ai/routing/forced", aiRoutingHandler.ClearForcedProvider)
+			}
+			if labelAIConfigHandler != nil {
+				admin.GET("/labels/ai-configs", labelAIConfigHandler.ListLabelAIConfigs)
+				admin.GET("/labels/:label_id/ai-config", labelAIConfigHandler.GetLabelAIConfig)
+				admin.PUT("/labels/:label_id/ai-config", labelAIConfigHandler.SetLabelAIConfig)
+				admin.DELETE("/labels/:label_id/ai-config", labelAIConfigHandler.DeleteLabelAIConfig)
+			}
+		}
+
+		// Users export or delete their own data; admins anyone's
+		if complianceHandler != nil && sessionStore != nil {
+			users := api.Group("/users")
+			users.Use(requireRedis...)
+			users.Use(middleware.RequireSession(sessionStore))
+			users.POST("/:id/export", complianceHandler.ExportUserData)
+			users.DELETE("/:id", writeBackpressure, complianceHandler.DeleteUser)
+		}
+
+		// Public API keys are issued and revoked by admins
+		if publicHandler != nil && sessionStore != nil {
+			keys := api.Group("/public-api-keys")
+			keys.Use(requireRedis...)
+			keys.Use(middleware.RequireSession(sessionStore), middleware.RequireRole(pkgdomain.RoleAdmin))
+			keys.GET("", publicHandler.ListKeys)
+			keys.POST("", publicHandler.CreateKey)
+			keys.DELETE("/:id", publicHandler.RevokeKey)
+		}
+
+		// Usage reports are for invoicing and only available to admins
+		if usageHandler != nil && sessionStore != nil {
+			usage := api.Group("/usage")
+			usage.Use(requireRedis...)
+			usage.Use(middleware.RequireSession(sessionStore), middleware.RequireRole(pkgdomain.RoleAdmin))
+			usage.GET("", usageHandler.GetUsage)
+		}
+
+		// Deliveries record what was sent to which DSP; takedowns and
+		// acknowledgements change what DSPs offer, so they are left to admins
+		if deliveryHandler != nil && sessionStore != nil {
+			deliveries := api.Group("/deliveries")
+			deliveries.Use(requireRedis...)
+			deliveries.Use(middleware.RequireSession(sessionStore))
+			deliveries.GET("", deliveryHandler.ListDeliveries)
+			deliveries.POST("", writeBackpressure, deliveryHandler.RecordDelivery)
+			deliveries.POST("/takedowns", middleware.RequireRole(pkgdomain.RoleAdmin), deliveryHandler.RequestTakedown)
+			deliveries.POST("/acknowledgements", middleware.RequireRole(pkgdomain.RoleAdmin), deliveryHandler.AcknowledgeDelivery)
+		}
+
+		// Play counts feed royalty statements and are only available to admins
+		if royaltyHandler != nil && sessionStore != nil {
+			royalties := api.Group("/royalties")
+			royalties.Use(requireRedis...)
+			royalties.Use(middleware.RequireSession(sessionStore), middleware.RequireRole(pkgdomain.RoleAdmin))
+			royalties.POST("/reports", royaltyHandler.IngestSalesReport)
+			royalties.GET("/plays", royaltyHandler.ListPlayCounts)
+		}
+	}
+
+	// Version 2 serves tracks as handler.TrackV2; the handlers are shared
+	// with version 1 and only the request and response bodies differ
+	apiV2 := router.Group("/api/v2", middleware.APIVersion(middleware.APIVersion2))
+	apiV2.Use(rateLimit...)
+	{
+		tracks := apiV2.Group("/tracks")
+		if sessionStore != nil {
+			tracks.Use(requireRedis...)
+			tracks.Use(middleware.RequireSession(sessionStore))
 		}
+		tracks.POST("", idempotent, writeBackpressure, trackHandler.CreateTrack)
+		tracks.GET("/:id", trackHandler.GetTrack)
+		tracks.PUT("/:id", writeBackpressure, trackHandler.UpdateTrack)
+		tracks.DELETE("/:id", writeBackpressure, trackHandler.DeleteTrack)
+		tracks.GET("", trackHandler.ListTracks)
 	}
 
+	if graphQLHandler != nil {
+		graphQL := router.Group("/graphql", rateLimit...)
+		if sessionStore != nil {
+			graphQL.Use(requireRedis...)
+			graphQL.Use(middleware.RequireSession(sessionStore))
+		}
+		graphQL.Use(dataloader.Middleware(pkgTrackRepo, pkgUserRepo))
+		graphQL.POST("", graphQLHandler.Execute)
+	}
+
+	// Watch optional dependencies and reconnect to those that were down
+	go deps.Run(depsCtx)
+
 	// Get port from environment variable for Cloud Run compatibility
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -410,25 +1673,30 @@ func main() {
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Warnf("Server forced to shutdown: %v", err)
 	}
+	if err := backgroundTasks.Wait(shutdownCtx); err != nil {
+		log.Warnf("Background tasks still running at shutdown: %v", err)
+	}
 
 	log.Info("Server exited properly")
 }
 
-func configToDomainSession(cfg pkgconfig.SessionConfig) domain.SessionConfig {
-	return domain.SessionConfig{
-		CookieName:         cfg.CookieName,
-		CookieDomain:       cfg.CookieDomain,
-		CookiePath:         cfg.CookiePath,
-		CookieSecure:       cfg.CookieSecure,
-		CookieHTTPOnly:     cfg.CookieHTTPOnly,
-		CookieSameSite:     cfg.CookieSameSite,
-		SessionDuration:    cfg.SessionDuration,
-		CleanupInterval:    cfg.CleanupInterval,
-		MaxSessionsPerUser: cfg.MaxSessionsPerUser,
+// redisQueue is the queue kept in Redis, on lists or on streams
+type redisQueue interface {
+	pkgdomain.ChangeFeedPublisher
+	pkgdomain.DeadLetterQueue
+	pkgdomain.DeadLetterExpirer
+	Close() error
+}
+
+// newRedisQueue creates the Redis queue of the configured backend
+func newRedisQueue(client *goredis.Client, backend string) redisQueue {
+	if backend == pkgconfig.RedisQueueStreams {
+		return queuepkg.NewStreamQueue(client, pkgdomain.DefaultQueueConfig())
 	}
+	return queuepkg.NewRedisQueue(client, pkgdomain.DefaultQueueConfig())
 }
 
-func configToPkgSession(cfg pkgconfig.SessionConfig) pkgdomain.SessionConfig {
+func configToDomainSession(cfg pkgconfig.SessionConfig) pkgdomain.SessionConfig {
 	return pkgdomain.SessionConfig{
 		CookieName:         cfg.CookieName,
 		CookieDomain:       cfg.CookieDomain,
@@ -441,3 +1709,38 @@ func configToPkgSession(cfg pkgconfig.SessionConfig) pkgdomain.SessionConfig {
 		MaxSessionsPerUser: cfg.MaxSessionsPerUser,
 	}
 }
+
+func configToJobTypeCounts(counts map[string]int) map[pkgdomain.JobType]int {
+	byType := make(map[pkgdomain.JobType]int, len(counts))
+	for jobType, count := range counts {
+		byType[pkgdomain.JobType(jobType)] = count
+	}
+	return byType
+}
+
+func configToPublicAPITiers(cfg pkgconfig.PublicAPIConfig) []pkgdomain.PublicAPITier {
+	return []pkgdomain.PublicAPITier{
+		{Name: pkgdomain.PublicAPITierFree, RequestsPerMinute: cfg.FreePerMinute, RequestsPerDay: cfg.FreePerDay},
+		{Name: pkgdomain.PublicAPITierPartner, RequestsPerMinute: cfg.PartnerPerMinute, RequestsPerDay: cfg.PartnerPerDay},
+		{Name: pkgdomain.PublicAPITierEnterprise, RequestsPerMinute: cfg.EnterprisePerMinute, RequestsPerDay: cfg.EnterprisePerDay},
+	}
+}
+
+// runMigrate connects to the primary database and runs a migrate subcommand
+func runMigrate(cfg *pkgconfig.AppConfig, args []string) error {
+	db, err := database.Open(cfg.Database, &gorm.Config{})
+	if err != nil {
+		return err
+	}
+	if !database.IsPostgres(db) {
+		return fmt.Errorf("migrations are only supported on %s", pkgconfig.DriverPostgres)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get underlying *sql.DB: %w", err)
+	}
+	defer sqlDB.Close()
+
+	settings := map[string]string{migrations.SettingVectorSearch: strconv.FormatBool(cfg.Database.VectorSearch)}
+	return migrations.RunCommand(context.Background(), sqlDB, args, settings, os.Stdout)
+}
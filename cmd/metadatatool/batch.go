@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// readBatchFile reads track IDs from a file holding one ID per line
+func readBatchFile(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch file: %w", err)
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(string(content), "\n") {
+		id := strings.TrimSpace(line)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// batchErrors collects the failures of a batch run by track ID
+type batchErrors map[string]error
+
+func (e batchErrors) Error() string {
+	ids := make([]string, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d tracks failed:", len(e))
+	for _, id := range ids {
+		fmt.Fprintf(&b, "\n  %s: %v", id, e[id])
+	}
+	return b.String()
+}
+
+// checkpoint records the track IDs a batch has completed so an interrupted
+// run can skip them when it is restarted
+type checkpoint struct {
+	path string
+	done map[string]bool
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// openCheckpoint loads the IDs completed by earlier runs and opens the file
+// for appending. An empty path disables checkpointing.
+func openCheckpoint(path string) (*checkpoint, error) {
+	c := &checkpoint{path: path, done: make(map[string]bool)}
+	if path == "" {
+		return c, nil
+	}
+
+	existing, err := os.Open(path)
+	if err == nil {
+		scanner := bufio.NewScanner(existing)
+		for scanner.Scan() {
+			if id := strings.TrimSpace(scanner.Text()); id != "" {
+				c.done[id] = true
+			}
+		}
+		existing.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to open checkpoint: %w", err)
+	}
+
+	c.file, err = os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint: %w", err)
+	}
+	return c, nil
+}
+
+// markDone records a completed track
+func (c *checkpoint) markDone(id string) error {
+	if c.file == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := fmt.Fprintln(c.file, id)
+	return err
+}
+
+// close closes the checkpoint and removes it once the whole batch succeeded
+func (c *checkpoint) close(complete bool) error {
+	if c.file == nil {
+		return nil
+	}
+	if err := c.file.Close(); err != nil {
+		return err
+	}
+	if complete {
+		return os.Remove(c.path)
+	}
+	return nil
+}
+
+// progressBar draws batch progress on a terminal. Messages printed through
+// it are written above the bar so the two never overlap.
+type progressBar struct {
+	out   io.Writer
+	draw  bool
+	total int
+
+	mu     sync.Mutex
+	done   int
+	failed int
+}
+
+func newProgressBar(total int) *progressBar {
+	return &progressBar{out: os.Stderr, draw: isTerminal(os.Stderr), total: total}
+}
+
+// advance counts a finished track and redraws the bar
+func (p *progressBar) advance(failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	if failed {
+		p.failed++
+	}
+	p.render()
+}
+
+// printf writes a message to stdout above the bar
+func (p *progressBar) printf(format string, args ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.draw {
+		fmt.Fprint(p.out, "\r\033[K")
+	}
+	fmt.Printf(format, args...)
+	p.render()
+}
+
+// finish ends the bar line
+func (p *progressBar) finish() {
+	if p.draw {
+		fmt.Fprintln(p.out)
+	}
+}
+
+func (p *progressBar) render() {
+	if !p.draw || p.total == 0 {
+		return
+	}
+	const width = 30
+	filled := p.done * width / p.total
+	fmt.Fprintf(p.out, "\r[%s%s] %d/%d", strings.Repeat("=", filled), strings.Repeat(" ", width-filled), p.done, p.total)
+	if p.failed > 0 {
+		fmt.Fprintf(p.out, " (%d failed)", p.failed)
+	}
+}
+
+// runBatch processes ids with the given number of workers. IDs recorded in
+// the checkpoint are skipped and successful ones are added to it. Failures do
+// not stop the batch; they are returned together as batchErrors. Cancelling
+// ctx stops handing out IDs, leaving the checkpoint in place for a rerun.
+func runBatch(ctx context.Context, ids []string, concurrency int, checkpointPath string, process func(ctx context.Context, id string, progress *progressBar) error) error {
+	cp, err := openCheckpoint(checkpointPath)
+	if err != nil {
+		return err
+	}
+
+	var pending []string
+	for _, id := range ids {
+		if !cp.done[id] {
+			pending = append(pending, id)
+		}
+	}
+	if skipped := len(ids) - len(pending); skipped > 0 {
+		fmt.Fprintf(os.Stderr, "Resuming from %s: %d of %d tracks already done\n", checkpointPath, skipped, len(ids))
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	progress := newProgressBar(len(pending))
+	failures := make(batchErrors)
+	var mu sync.Mutex
+
+	work := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range work {
+				err := process(ctx, id, progress)
+				if err == nil {
+					err = cp.markDone(id)
+				}
+				if err != nil {
+					mu.Lock()
+					failures[id] = err
+					mu.Unlock()
+				}
+				progress.advance(err != nil)
+			}
+		}()
+	}
+
+feed:
+	for _, id := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case work <- id:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+	progress.finish()
+
+	interrupted := ctx.Err() != nil
+	if err := cp.close(!interrupted && len(failures) == 0); err != nil {
+		return fmt.Errorf("failed to close checkpoint: %w", err)
+	}
+	if interrupted {
+		return fmt.Errorf("batch interrupted, rerun to resume: %w", ctx.Err())
+	}
+	if len(failures) > 0 {
+		return failures
+	}
+	return nil
+}
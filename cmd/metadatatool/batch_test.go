@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunBatch_AggregatesErrorsAndResumes(t *testing.T) {
+	checkpointPath := filepath.Join(t.TempDir(), "batch.done")
+	ids := []string{"a", "b", "c", "d"}
+
+	var mu sync.Mutex
+	var processed []string
+	failB := true
+	process := func(ctx context.Context, id string, _ *progressBar) error {
+		mu.Lock()
+		defer mu.Unlock()
+		processed = append(processed, id)
+		if id == "b" && failB {
+			return errors.New("boom")
+		}
+		return nil
+	}
+
+	err := runBatch(context.Background(), ids, 3, checkpointPath, process)
+	var failures batchErrors
+	require.True(t, errors.As(err, &failures))
+	assert.Len(t, failures, 1)
+	assert.Contains(t, failures, "b")
+	assert.ElementsMatch(t, ids, processed)
+	assert.FileExists(t, checkpointPath)
+
+	// The rerun only processes the failed track and removes the checkpoint
+	processed = nil
+	failB = false
+	require.NoError(t, runBatch(context.Background(), ids, 3, checkpointPath, process))
+	assert.Equal(t, []string{"b"}, processed)
+	_, err = os.Stat(checkpointPath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRunBatch_InterruptKeepsCheckpoint(t *testing.T) {
+	checkpointPath := filepath.Join(t.TempDir(), "batch.done")
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := runBatch(ctx, []string{"a", "b", "c"}, 1, checkpointPath, func(ctx context.Context, id string, _ *progressBar) error {
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	cp, err := openCheckpoint(checkpointPath)
+	require.NoError(t, err)
+	defer cp.close(false)
+	assert.True(t, cp.done["a"])
+	assert.False(t, cp.done["c"])
+}
+
+func TestReadBatchFile_SkipsBlankAndDuplicateLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids.txt")
+	require.NoError(t, os.WriteFile(path, []byte("a\n\n b \na\nc\n"), 0o644))
+
+	ids, err := readBatchFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"strconv"
+	"strings"
+)
+
+// catalogPageSize bounds the number of tracks the browser loads at once
+const catalogPageSize = 200
+
+// editableFields lists the track fields that can be edited from the terminal
+var editableFields = []string{
+	"title", "artist", "album", "year", "isrc", "label",
+	"genre", "bpm", "key", "mood", "publisher", "copyright",
+}
+
+// ANSI escape sequences used to color diffs
+const (
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+	ansiReset = "\033[0m"
+)
+
+// catalog holds the operations the interactive browser performs on tracks
+type catalog struct {
+	tracks domain.TrackRepository
+	ai     domain.AIService
+}
+
+// search returns the tracks matching query. A query of the form
+// field=value searches that metadata field; any other query matches tracks
+// whose title, artist or ISRC contains it.
+func (c *catalog) search(ctx context.Context, query string) ([]*domain.Track, error) {
+	query = strings.TrimSpace(query)
+	if field, value, ok := strings.Cut(query, "="); ok {
+		return c.tracks.SearchByMetadata(ctx, map[string]interface{}{
+			strings.TrimSpace(field): strings.TrimSpace(value),
+		})
+	}
+
+	tracks, err := c.tracks.List(ctx, nil, 0, catalogPageSize)
+	if err != nil {
+		return nil, err
+	}
+	if query == "" {
+		return tracks, nil
+	}
+
+	needle := strings.ToLower(query)
+	var matches []*domain.Track
+	for _, track := range tracks {
+		if strings.Contains(strings.ToLower(track.Title()), needle) ||
+			strings.Contains(strings.ToLower(track.Artist()), needle) ||
+			strings.Contains(strings.ToLower(track.ISRC()), needle) {
+			matches = append(matches, track)
+		}
+	}
+	return matches, nil
+}
+
+// propose runs enrichment on a copy of track and returns the enriched copy
+// together with the fields the AI would change. The track itself is left
+// untouched until the proposal is accepted and saved.
+func (c *catalog) propose(ctx context.Context, track *domain.Track) (*domain.Track, []domain.FieldChange, error) {
+	proposed := track.Clone()
+	if err := c.ai.EnrichMetadata(ctx, proposed); err != nil {
+		return nil, nil, fmt.Errorf("failed to enrich track: %w", err)
+	}
+	return proposed, domain.DiffTracks(track, proposed), nil
+}
+
+// validate returns the AI confidence in the track's metadata
+func (c *catalog) validate(ctx context.Context, track *domain.Track) (float64, error) {
+	confidence, err := c.ai.ValidateMetadata(ctx, track)
+	if err != nil {
+		return 0, fmt.Errorf("failed to validate track: %w", err)
+	}
+	return confidence, nil
+}
+
+// save stores track, which must carry the version it was loaded with
+func (c *catalog) save(ctx context.Context, track *domain.Track) error {
+	if err := c.tracks.Update(ctx, track); err != nil {
+		return fmt.Errorf("failed to save track: %w", err)
+	}
+	return nil
+}
+
+// setField parses value and assigns it to the named editable field
+func setField(track *domain.Track, field, value string) error {
+	value = strings.TrimSpace(value)
+	switch field {
+	case "title":
+		track.SetTitle(value)
+	case "artist":
+		track.SetArtist(value)
+	case "album":
+		track.SetAlbum(value)
+	case "year":
+		year, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid year %q", value)
+		}
+		track.SetYear(year)
+	case "isrc":
+		track.SetISRC(strings.ToUpper(value))
+	case "label":
+		if track.Metadata.Additional.CustomFields == nil {
+			track.Metadata.Additional.CustomFields = make(map[string]string)
+		}
+		track.SetLabel(value)
+	case "genre":
+		track.SetGenre(value)
+	case "bpm":
+		bpm, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid BPM %q", value)
+		}
+		track.SetBPM(bpm)
+	case "key":
+		track.SetKey(value)
+	case "mood":
+		track.SetMood(value)
+	case "publisher":
+		track.SetPublisher(value)
+	case "copyright":
+		track.SetCopyright(value)
+	default:
+		return fmt.Errorf("field %q cannot be edited", field)
+	}
+	return nil
+}
+
+// formatValue renders a field value for display
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []string:
+		return strings.Join(v, ", ")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// renderDiff formats field changes as removed and added lines, colored red
+// and green when color is set. Empty values are left out.
+func renderDiff(changes []domain.FieldChange, color bool) string {
+	if len(changes) == 0 {
+		return "no changes proposed\n"
+	}
+
+	var b strings.Builder
+	for _, change := range changes {
+		if old := formatValue(change.OldValue); old != "" {
+			b.WriteString(colorize(fmt.Sprintf("- %s: %s", change.Field, old), ansiRed, color) + "\n")
+		}
+		if added := formatValue(change.NewValue); added != "" {
+			b.WriteString(colorize(fmt.Sprintf("+ %s: %s", change.Field, added), ansiGreen, color) + "\n")
+		}
+	}
+	return b.String()
+}
+
+func colorize(line, code string, color bool) string {
+	if !color {
+		return line
+	}
+	return code + line + ansiReset
+}
@@ -4,15 +4,26 @@
 //   - Enrich track metadata using AI services
 //   - Validate track metadata against quality standards
 //   - Export tracks in various formats (JSON, DDEX)
+//   - Browse, search and edit tracks interactively (built with -tags tui)
+//   - Ingest audio files dropped into a watched directory
+//   - Compare and sync tracks between two deployments
 //
 // Usage:
 //
-//	metadatatool -action=enrich -track=<track_id>
+//	metadatatool -action=enrich -track=<track_id> [-force-refresh]
+//	metadatatool -action=enrich -batch=<file> [-concurrency=<n>] [-checkpoint=<file>] [-force-refresh]
 //	metadatatool -action=validate -track=<track_id>
+//	metadatatool -action=validate -batch=<file> [-concurrency=<n>] [-checkpoint=<file>]
 //	metadatatool -action=export -track=<track_id> -format=[json|ddex]
-//	metadatatool -action=export -batch=<file> -format=[json|ddex]
+//	metadatatool -action=export -batch=<file> -format=[json|ddex] [-concurrency=<n>]
+//	metadatatool -action=tui
+//	metadatatool -action=watch -dir=<directory> [-enrich] [-interval=<duration>]
+//	metadatatool -action=sync -source=<url> -target=<url> [-match=isrc|id] [-apply]
+//	metadatatool migrate [up|down [n]|version|force <v>]
 //
 // Environment Variables:
+//   - DB_DRIVER: postgres (default) or sqlite
+//   - DB_SQLITE_PATH: SQLite database file when DB_DRIVER=sqlite
 //   - DB_HOST: PostgreSQL host
 //   - DB_PORT: PostgreSQL port
 //   - DB_USER: PostgreSQL user
@@ -20,6 +31,11 @@
 //   - DB_NAME: PostgreSQL database name
 //   - AI_API_KEY: API key for AI services
 //   - AI_BASE_URL: Base URL for AI services
+//   - STORAGE_BUCKET, STORAGE_REGION: S3 storage for watch-folder uploads
+//   - REDIS_HOST, REDIS_PORT: Redis job queue for -enrich
+//   - REDIS_ENABLED: cache enrichment results in Redis by audio content hash
+//   - SYNC_SOURCE_TOKEN, SYNC_SOURCE_SESSION: credentials for the sync source
+//   - SYNC_TARGET_TOKEN, SYNC_TARGET_SESSION: credentials for the sync target
 //   - BIGQUERY_PROJECT: Google Cloud project ID
 //   - BIGQUERY_DATASET: BigQuery dataset name
 package main
@@ -33,16 +49,28 @@ import (
 	"log"
 	"metadatatool/internal/pkg/analytics"
 	"metadatatool/internal/pkg/config"
+	"metadatatool/internal/pkg/database"
 	"metadatatool/internal/pkg/ddex"
 	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/encryption"
+	"metadatatool/internal/pkg/migrations"
+	"metadatatool/internal/pkg/secrets"
 	"metadatatool/internal/repository/ai"
 	"metadatatool/internal/repository/base"
+	"metadatatool/internal/repository/cached"
+	"metadatatool/internal/repository/jobs"
+	"metadatatool/internal/repository/notify"
+	"metadatatool/internal/repository/remote"
+	"metadatatool/internal/repository/storage"
 	"metadatatool/internal/usecase"
 	"os"
-	"strings"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	_ "github.com/lib/pq"
-	"gorm.io/driver/postgres"
+	goredis "github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
@@ -52,15 +80,39 @@ type flags struct {
 	action    *string // Action to perform (enrich, validate, export)
 	format    *string // Export format (json, ddex)
 	batchFile *string // File containing list of track IDs to process
+	dir       *string // Directory watched for new audio files
+	enrich    *bool   // Queue AI enrichment for ingested tracks
+	interval  *time.Duration
+	source    *string // API base URL tracks are synced from
+	target    *string // API base URL tracks are synced to
+	match     *string // Key pairing source and target tracks (isrc, id)
+	apply     *bool   // Write the sync changes instead of only printing them
+	config    *string // YAML or TOML config file
+	refresh   *bool   // Ignore cached enrichment results
+
+	concurrency *int    // Number of tracks processed in parallel in batch mode
+	checkpoint  *string // File recording completed tracks of a batch
 }
 
 // parseFlags parses and validates command line flags
 func parseFlags() *flags {
 	f := &flags{
 		trackID:   flag.String("track", "", "Track ID to process"),
-		action:    flag.String("action", "", "Action to perform (enrich, validate, export)"),
+		action:    flag.String("action", "", "Action to perform (enrich, validate, export, tui, watch, sync, config)"),
 		format:    flag.String("format", "json", "Export format (json, ddex)"),
 		batchFile: flag.String("batch", "", "File containing list of track IDs to process"),
+		dir:       flag.String("dir", "", "Directory to watch for new audio files"),
+		enrich:    flag.Bool("enrich", false, "Queue AI enrichment for tracks created by watch"),
+		interval:  flag.Duration("interval", 2*time.Second, "Interval between scans of the watched directory"),
+		source:    flag.String("source", "", "API base URL to sync tracks from"),
+		target:    flag.String("target", "", "API base URL to sync tracks to"),
+		match:     flag.String("match", "isrc", "Key pairing tracks during sync (isrc, id)"),
+		apply:     flag.Bool("apply", false, "Apply sync changes to the target"),
+		config:    flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML config file; environment variables override its values"),
+		refresh:   flag.Bool("force-refresh", false, "Enrich again instead of reusing cached results for identical audio"),
+
+		concurrency: flag.Int("concurrency", 4, "Number of tracks processed in parallel in batch mode"),
+		checkpoint:  flag.String("checkpoint", "", "File recording completed tracks so an interrupted batch can resume (default <batch>.done)"),
 	}
 	flag.Parse()
 	return f
@@ -70,10 +122,37 @@ func main() {
 	flags := parseFlags()
 
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.LoadFile(*flags.config)
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	if err := secrets.NewManagerFromConfig(cfg.Secrets).Resolve(context.Background(), cfg.SecretFields()); err != nil {
+		log.Fatalf("Failed to resolve secrets: %v", err)
+	}
+	for _, warning := range cfg.Warnings() {
+		log.Printf("Config: %s", warning)
+	}
+
+	if args := flag.Args(); len(args) > 0 && args[0] == "migrate" {
+		if err := runMigrate(cfg, args[1:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	// Print the effective configuration with secrets redacted
+	if *flags.action == "config" {
+		fmt.Print(cfg.Redacted())
+		return
+	}
+
+	// Sync only talks to the two APIs and needs no local services
+	if *flags.action == "sync" {
+		if err := syncCatalogs(context.Background(), flags); err != nil {
+			log.Fatalf("Sync failed: %v", err)
+		}
+		return
+	}
 
 	// Initialize services
 	services, err := initializeServices(cfg)
@@ -82,8 +161,12 @@ func main() {
 	}
 	defer services.cleanup()
 
-	// Process command
-	ctx := context.Background()
+	// Process command; an interrupt stops batches and the watcher cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *flags.refresh {
+		ctx = domain.WithForceRefresh(ctx)
+	}
 	if err := processCommand(ctx, flags, services); err != nil {
 		log.Fatalf("Command failed: %v", err)
 	}
@@ -91,11 +174,14 @@ func main() {
 
 // services holds all the service dependencies
 type services struct {
-	analytics *analytics.BigQueryService
+	analytics analytics.Service
 	ai        domain.AIService
+	usage     domain.UsageRecorder
 	tracks    domain.TrackRepository
 	ddex      domain.DDEXService
 	db        *sql.DB
+	redis     *goredis.Client
+	cfg       *config.AppConfig
 }
 
 // cleanup performs cleanup of all services
@@ -106,71 +192,111 @@ func (s *services) cleanup() {
 	if s.db != nil {
 		s.db.Close()
 	}
+	if s.redis != nil {
+		s.redis.Close()
+	}
 }
 
 // initializeServices initializes all required services
 func initializeServices(cfg *config.AppConfig) (*services, error) {
-	// Initialize BigQuery analytics
-	analyticsService, err := analytics.NewBigQueryService(
-		cfg.Queue.ProjectID,
-		cfg.Queue.HighPriorityTopic,
-	)
+	// Initialize database connection
+	db, sqlDB, err := openDatabase(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize BigQuery: %w", err)
+		return nil, err
+	}
+
+	// Refuse to run against a schema this binary was not built for. The
+	// versioned migrations are written for PostgreSQL.
+	if database.IsPostgres(db) {
+		migrator, err := migrations.New(sqlDB, migrations.FS())
+		if err != nil {
+			sqlDB.Close()
+			return nil, err
+		}
+		if err := migrator.CheckVersion(context.Background()); err != nil {
+			sqlDB.Close()
+			return nil, fmt.Errorf("database schema check failed: %w (run \"metadatatool migrate up\")", err)
+		}
+	}
+
+	// Initialize analytics
+	analyticsService, err := analytics.NewService(&cfg.Analytics, sqlDB)
+	if err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to initialize analytics: %w", err)
 	}
 
 	// Initialize AI service
 	aiConfig := &ai.Config{
-		EnableFallback:        cfg.AI.Experiment.EnableFallback,
-		TimeoutSeconds:        int(cfg.AI.Timeout.Seconds()),
-		MinConfidence:         cfg.AI.MinConfidence,
-		MaxConcurrentRequests: cfg.AI.BatchSize,
-		RetryAttempts:         3,
-		RetryBackoffSeconds:   5,
+		EnableFallback:           cfg.AI.Experiment.EnableFallback,
+		TimeoutSeconds:           int(cfg.AI.Timeout.Seconds()),
+		MinConfidence:            cfg.AI.MinConfidence,
+		MaxConcurrentRequests:    cfg.AI.MaxConcurrentRequests,
+		ExperimentTrafficPercent: cfg.AI.Experiment.TrafficPercent,
+		RetryAttempts:            3,
+		RetryBackoffSeconds:      5,
 		OpenAIConfig: &domain.OpenAIConfig{
 			APIKey:                cfg.AI.APIKey,
 			Endpoint:              cfg.AI.BaseURL,
+			Model:                 cfg.AI.ModelName,
+			ModelVersion:          cfg.AI.ModelVersion,
 			TimeoutSeconds:        int(cfg.AI.Timeout.Seconds()),
 			MinConfidence:         cfg.AI.MinConfidence,
-			MaxConcurrentRequests: cfg.AI.BatchSize,
+			MaxConcurrentRequests: cfg.AI.MaxConcurrentRequests,
 			RetryAttempts:         3,
 			RetryBackoffSeconds:   5,
-			RequestsPerSecond:     10,
+			RequestsPerSecond:     cfg.AI.OpenAIRequestsPerSecond,
+			MaxPromptTokens:       cfg.AI.MaxPromptTokens,
 		},
 		Qwen2Config: &domain.Qwen2Config{
 			APIKey:                cfg.AI.APIKey,
 			Endpoint:              cfg.AI.BaseURL,
+			Model:                 cfg.AI.Qwen2Model,
+			ModelVersion:          cfg.AI.Qwen2ModelVersion,
 			TimeoutSeconds:        int(cfg.AI.Timeout.Seconds()),
 			MinConfidence:         cfg.AI.MinConfidence,
-			MaxConcurrentRequests: cfg.AI.BatchSize,
+			MaxConcurrentRequests: cfg.AI.MaxConcurrentRequests,
 			RetryAttempts:         3,
 			RetryBackoffSeconds:   5,
+			RequestsPerSecond:     cfg.AI.Qwen2RequestsPerSecond,
+			MaxPromptTokens:       cfg.AI.MaxPromptTokens,
 		},
 	}
 
 	aiService, err := ai.NewCompositeAIService(aiConfig, analyticsService)
 	if err != nil {
+		analyticsService.Close()
+		sqlDB.Close()
 		return nil, fmt.Errorf("failed to initialize AI service: %w", err)
 	}
 
-	// Initialize database connection
-	dbDSN := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.User,
-		cfg.Database.Password,
-		cfg.Database.DBName,
-	)
-	db, err := gorm.Open(postgres.Open(dbDSN), &gorm.Config{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	// Enrich the tracks of labels with their own AI configuration with it
+	if cfg.AI.CredentialKey != "" {
+		credentialCipher, err := encryption.NewCipher(cfg.AI.CredentialKey)
+		if err != nil {
+			analyticsService.Close()
+			sqlDB.Close()
+			return nil, fmt.Errorf("invalid AI credential key: %w", err)
+		}
+		aiService.(*ai.CompositeAIService).SetLabelConfigs(base.NewLabelAIConfigRepository(db, credentialCipher))
 	}
 
-	// Get underlying *sql.DB for cleanup
-	sqlDB, err := db.DB()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get underlying *sql.DB: %w", err)
+	// Reuse enrichment results for identical audio when Redis is available
+	var redisClient *goredis.Client
+	if cfg.Redis.Enabled {
+		redisClient = goredis.NewClient(&goredis.Options{
+			Addr:     cfg.Redis.GetAddress(),
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+		if err := redisClient.Ping(context.Background()).Err(); err != nil {
+			log.Printf("Enrichment cache is disabled: failed to connect to Redis: %v", err)
+			redisClient.Close()
+			redisClient = nil
+		} else {
+			aiService = cached.NewAIService(redisClient, aiService,
+				domain.ModelVersionKey(aiService.(*ai.CompositeAIService).ModelVersions()), cfg.AI.CacheTTL)
+		}
 	}
 
 	// Initialize repositories and services
@@ -186,35 +312,114 @@ func initializeServices(cfg *config.AppConfig) (*services, error) {
 	}
 	ddexService := usecase.NewDDEXService(schemaValidator, ddexConfig)
 
+	// Meter enrichments and exports per label
+	usage := usecase.NewUsageUseCase(base.NewUsageRepository(db))
+
 	return &services{
 		analytics: analyticsService,
-		ai:        aiService,
-		tracks:    pkgTrackRepo,
-		ddex:      ddexService,
-		db:        sqlDB,
+		ai: ai.NewMeteredAIService(ai.NewProvenanceAIService(aiService, domain.MergePolicy{
+			OverwriteManual: cfg.AI.OverwriteManualEdits,
+		}), usage),
+		usage:  usage,
+		tracks: pkgTrackRepo,
+		ddex:   ddexService,
+		db:     sqlDB,
+		redis:  redisClient,
+		cfg:    cfg,
 	}, nil
 }
 
+// openDatabase connects to the configured database
+func openDatabase(cfg *config.AppConfig) (*gorm.DB, *sql.DB, error) {
+	db, err := database.Open(cfg.Database, &gorm.Config{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get underlying *sql.DB for cleanup
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get underlying *sql.DB: %w", err)
+	}
+	if err := base.ConfigurePool(db, cfg.Database.Pool); err != nil {
+		sqlDB.Close()
+		return nil, nil, err
+	}
+	return db, sqlDB, nil
+}
+
+// runMigrate runs a migrate subcommand against the configured database
+func runMigrate(cfg *config.AppConfig, args []string) error {
+	db, sqlDB, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+	if !database.IsPostgres(db) {
+		return fmt.Errorf("migrations are only supported on %s", config.DriverPostgres)
+	}
+
+	settings := map[string]string{migrations.SettingVectorSearch: strconv.FormatBool(cfg.Database.VectorSearch)}
+	return migrations.RunCommand(context.Background(), sqlDB, args, settings, os.Stdout)
+}
+
 // processCommand processes the CLI command based on the provided flags
 func processCommand(ctx context.Context, f *flags, s *services) error {
 	switch *f.action {
 	case "enrich":
+		if *f.batchFile != "" {
+			return runBatchFile(ctx, f, func(ctx context.Context, id string, progress *progressBar) error {
+				changes, err := enrichTrack(ctx, id, s.tracks, s.ai)
+				if err == nil {
+					progress.printf("%s: %d fields enriched\n", id, len(changes))
+				}
+				return err
+			})
+		}
 		if *f.trackID == "" {
-			return fmt.Errorf("track ID is required for enrich action")
+			return fmt.Errorf("track ID or batch file is required for enrich action")
+		}
+		changes, err := enrichTrack(ctx, *f.trackID, s.tracks, s.ai)
+		if err != nil {
+			return err
 		}
-		return enrichTrack(ctx, *f.trackID, s.tracks, s.ai)
+		fmt.Print(renderDiff(changes, isTerminal(os.Stdout)))
+		return nil
 
 	case "validate":
+		if *f.batchFile != "" {
+			return runBatchFile(ctx, f, func(ctx context.Context, id string, progress *progressBar) error {
+				confidence, err := validateTrack(ctx, id, s.tracks, s.ai)
+				if err == nil {
+					progress.printf("%s: validation confidence %.2f\n", id, confidence)
+				}
+				return err
+			})
+		}
 		if *f.trackID == "" {
-			return fmt.Errorf("track ID is required for validate action")
+			return fmt.Errorf("track ID or batch file is required for validate action")
 		}
-		return validateTrack(ctx, *f.trackID, s.tracks, s.ai)
+		confidence, err := validateTrack(ctx, *f.trackID, s.tracks, s.ai)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Track validation confidence: %.2f\n", confidence)
+		return nil
+
+	case "tui":
+		return runTUI(ctx, s)
+
+	case "watch":
+		if *f.dir == "" {
+			return fmt.Errorf("directory is required for watch action")
+		}
+		return watchDirectory(ctx, *f.dir, *f.interval, *f.enrich, s)
 
 	case "export":
 		if *f.trackID == "" && *f.batchFile == "" {
 			return fmt.Errorf("either track ID or batch file is required for export action")
 		}
-		return exportTracks(ctx, *f.trackID, *f.batchFile, *f.format, s.tracks, s.ddex)
+		return exportTracks(ctx, *f.trackID, *f.batchFile, *f.format, *f.concurrency, s.tracks, s.ddex, s.analytics, s.usage)
 
 	default:
 		printUsage()
@@ -226,66 +431,207 @@ func processCommand(ctx context.Context, f *flags, s *services) error {
 func printUsage() {
 	fmt.Println("Available commands:")
 	fmt.Println("  metadatatool -action=enrich -track=<track_id>")
+	fmt.Println("  metadatatool -action=enrich -batch=<file> [-concurrency=<n>] [-checkpoint=<file>]")
 	fmt.Println("  metadatatool -action=validate -track=<track_id>")
+	fmt.Println("  metadatatool -action=validate -batch=<file> [-concurrency=<n>] [-checkpoint=<file>]")
 	fmt.Println("  metadatatool -action=export -track=<track_id> -format=[json|ddex]")
-	fmt.Println("  metadatatool -action=export -batch=<file> -format=[json|ddex]")
+	fmt.Println("  metadatatool -action=export -batch=<file> -format=[json|ddex] [-concurrency=<n>]")
+	if tuiEnabled {
+		fmt.Println("  metadatatool -action=tui")
+	}
+	fmt.Println("  metadatatool -action=watch -dir=<directory> [-enrich] [-interval=<duration>]")
+	fmt.Println("  metadatatool -action=sync -source=<url> -target=<url> [-match=isrc|id] [-apply]")
 }
 
-// enrichTrack enriches a track's metadata using AI services
-func enrichTrack(ctx context.Context, trackID string, repo domain.TrackRepository, ai domain.AIService) error {
-	track, err := repo.GetByID(ctx, trackID)
+// syncCatalogs prints the differences between the source and target
+// catalogs and, with -apply, writes them to the target
+func syncCatalogs(ctx context.Context, f *flags) error {
+	if *f.source == "" || *f.target == "" {
+		return fmt.Errorf("source and target are required for sync action")
+	}
+
+	source := remote.NewTrackClient(*f.source, os.Getenv("SYNC_SOURCE_TOKEN"), os.Getenv("SYNC_SOURCE_SESSION"))
+	target := remote.NewTrackClient(*f.target, os.Getenv("SYNC_TARGET_TOKEN"), os.Getenv("SYNC_TARGET_SESSION"))
+	sync, err := usecase.NewCatalogSync(source, target, usecase.SyncMatch(*f.match))
+	if err != nil {
+		return err
+	}
+
+	plan, err := sync.Plan(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get track: %w", err)
+		return err
+	}
+
+	color := isTerminal(os.Stdout)
+	for _, change := range plan.Changes {
+		fmt.Printf("%s %s %q\n", change.Action, change.Key, change.Source.Title())
+		fmt.Print(renderDiff(change.Changes, color))
+	}
+	fmt.Printf("%d to create or update, %d unchanged, %d skipped without %s\n",
+		len(plan.Changes), plan.Unchanged, len(plan.Skipped), *f.match)
+
+	if !*f.apply {
+		if len(plan.Changes) > 0 {
+			fmt.Println("Dry run; rerun with -apply to write these changes to", target.BaseURL())
+		}
+		return nil
+	}
+
+	applied, failed := sync.Apply(ctx, plan)
+	for key, err := range failed {
+		fmt.Printf("failed %s: %v\n", key, err)
+	}
+	fmt.Printf("%d changes applied to %s\n", applied, target.BaseURL())
+	if len(failed) > 0 {
+		return fmt.Errorf("%d changes failed", len(failed))
 	}
-	return ai.EnrichMetadata(ctx, track)
+	return nil
 }
 
-// validateTrack validates a track's metadata using AI services
-func validateTrack(ctx context.Context, trackID string, repo domain.TrackRepository, ai domain.AIService) error {
-	track, err := repo.GetByID(ctx, trackID)
+// watchDirectory ingests audio files dropped into dir until interrupted
+func watchDirectory(ctx context.Context, dir string, interval time.Duration, enrich bool, s *services) error {
+	storageService, err := storage.NewS3Storage(&s.cfg.Storage)
 	if err != nil {
-		return fmt.Errorf("failed to get track: %w", err)
+		return fmt.Errorf("failed to initialize storage: %w", err)
 	}
-	confidence, err := ai.ValidateMetadata(ctx, track)
+
+	var jobQueue domain.JobQueue
+	if enrich {
+		client := goredis.NewClient(&goredis.Options{
+			Addr:     s.cfg.Redis.GetAddress(),
+			Password: s.cfg.Redis.Password,
+			DB:       s.cfg.Redis.DB,
+		})
+		defer client.Close()
+		if err := client.Ping(ctx).Err(); err != nil {
+			return fmt.Errorf("failed to connect to Redis: %w", err)
+		}
+		queue := jobs.NewRedisQueue(client, &domain.JobConfig{
+			QueuePrefix: "jobs:",
+			DefaultTTL:  24 * time.Hour,
+		})
+
+		// Hold back enrichment jobs while the job queue lags behind
+		var alerter domain.QueueAlerter
+		if s.cfg.Queue.LagAlertWebhookURL != "" {
+			alerter = notify.NewWebhookNotifier(s.cfg.Queue.LagAlertWebhookURL, "")
+		}
+		monitor := usecase.NewQueueMonitor(usecase.QueueMonitorConfig{
+			LagThreshold: s.cfg.Queue.LagThreshold,
+			MaxLag:       s.cfg.Queue.MaxLag,
+		}, alerter)
+		monitor.AddSource(queue)
+		if s.cfg.Queue.LagInterval > 0 {
+			go monitor.Run(ctx, s.cfg.Queue.LagInterval)
+		}
+		jobQueue = jobs.NewBackpressureQueue(queue, monitor)
+	}
+
+	log.Printf("Watching %s for new audio files", dir)
+	return usecase.NewWatchIngester(s.tracks, storageService, jobQueue, usecase.WatchIngesterConfig{
+		Dir:               dir,
+		PollInterval:      interval,
+		EnqueueEnrichment: enrich,
+	}).Run(ctx)
+}
+
+// runBatchFile runs process for every track ID in the batch file. Progress
+// is checkpointed so that rerunning an interrupted batch resumes it.
+func runBatchFile(ctx context.Context, f *flags, process func(ctx context.Context, id string, progress *progressBar) error) error {
+	ids, err := readBatchFile(*f.batchFile)
 	if err != nil {
 		return err
 	}
-	fmt.Printf("Track validation confidence: %.2f\n", confidence)
-	return nil
+	checkpointPath := *f.checkpoint
+	if checkpointPath == "" {
+		checkpointPath = *f.batchFile + ".done"
+	}
+	return runBatch(ctx, ids, *f.concurrency, checkpointPath, process)
+}
+
+// getTrack loads a track, treating a missing track as an error
+func getTrack(ctx context.Context, trackID string, repo domain.TrackRepository) (*domain.Track, error) {
+	track, err := repo.GetByID(ctx, trackID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get track: %w", err)
+	}
+	if track == nil {
+		return nil, fmt.Errorf("track not found")
+	}
+	return track, nil
+}
+
+// enrichTrack enriches a track's metadata using AI services, saves it and
+// returns the fields that changed
+func enrichTrack(ctx context.Context, trackID string, repo domain.TrackRepository, ai domain.AIService) ([]domain.FieldChange, error) {
+	track, err := getTrack(ctx, trackID, repo)
+	if err != nil {
+		return nil, err
+	}
+	before := track.Clone()
+	if err := ai.EnrichMetadata(ctx, track); err != nil {
+		return nil, err
+	}
+	changes := domain.DiffTracks(before, track)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	if err := repo.Update(ctx, track); err != nil {
+		return nil, fmt.Errorf("failed to save track: %w", err)
+	}
+	return changes, nil
+}
+
+// isTerminal reports whether f is attached to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// validateTrack validates a track's metadata using AI services
+func validateTrack(ctx context.Context, trackID string, repo domain.TrackRepository, ai domain.AIService) (float64, error) {
+	track, err := getTrack(ctx, trackID, repo)
+	if err != nil {
+		return 0, err
+	}
+	return ai.ValidateMetadata(ctx, track)
 }
 
-// exportTracks exports tracks in the specified format
-func exportTracks(ctx context.Context, trackID, batchFile, format string, repo domain.TrackRepository, ddex domain.DDEXService) error {
+// exportTracks exports tracks in the specified format. Batch exports load
+// tracks concurrently and report every track that failed to load.
+func exportTracks(ctx context.Context, trackID, batchFile, format string, concurrency int, repo domain.TrackRepository, ddex domain.DDEXService, recorder analytics.EventRecorder, usage domain.UsageRecorder) error {
 	var tracks []*domain.Track
 
 	if trackID != "" {
-		track, err := repo.GetByID(ctx, trackID)
+		track, err := getTrack(ctx, trackID, repo)
 		if err != nil {
-			return fmt.Errorf("failed to get track: %w", err)
+			return err
 		}
 		tracks = append(tracks, track)
 	} else if batchFile != "" {
-		// Read track IDs from batch file
-		content, err := os.ReadFile(batchFile)
+		ids, err := readBatchFile(batchFile)
 		if err != nil {
-			return fmt.Errorf("failed to read batch file: %w", err)
+			return err
 		}
 
-		// Parse track IDs (assuming one ID per line)
-		trackIDs := strings.Split(strings.TrimSpace(string(content)), "\n")
-		for _, id := range trackIDs {
-			id = strings.TrimSpace(id)
-			if id == "" {
-				continue
-			}
-			track, err := repo.GetByID(ctx, id)
+		// Keep the export in batch file order
+		loaded := make([]*domain.Track, len(ids))
+		index := make(map[string]int, len(ids))
+		for i, id := range ids {
+			index[id] = i
+		}
+		err = runBatch(ctx, ids, concurrency, "", func(ctx context.Context, id string, _ *progressBar) error {
+			track, err := getTrack(ctx, id, repo)
 			if err != nil {
-				return fmt.Errorf("failed to get track %s: %w", id, err)
-			}
-			if track != nil {
-				tracks = append(tracks, track)
+				return err
 			}
+			loaded[index[id]] = track
+			return nil
+		})
+		if err != nil {
+			return err
 		}
+		tracks = loaded
 	} else {
 		return fmt.Errorf("either track ID or batch file is required")
 	}
@@ -294,6 +640,29 @@ func exportTracks(ctx context.Context, trackID, batchFile, format string, repo d
 		return fmt.Errorf("no tracks found to export")
 	}
 
+	start := time.Now()
+	err := writeExport(ctx, format, tracks, ddex)
+	event := analytics.ExportEvent{
+		Timestamp:  start,
+		Format:     format,
+		TrackCount: len(tracks),
+		DurationMs: time.Since(start).Milliseconds(),
+		Success:    err == nil,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	recorder.Record(event)
+	if err == nil {
+		for labelID, n := range domain.TracksPerLabel(tracks) {
+			usage.RecordUsage(ctx, labelID, domain.UsageExports, n)
+		}
+	}
+	return err
+}
+
+// writeExport prints tracks in the given format
+func writeExport(ctx context.Context, format string, tracks []*domain.Track, ddex domain.DDEXService) error {
 	if format == "ddex" {
 		output, err := ddex.ExportTracks(ctx, tracks)
 		if err != nil {
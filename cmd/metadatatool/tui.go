@@ -0,0 +1,341 @@
+//go:build tui
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tuiEnabled reports whether the interactive browser is built in
+const tuiEnabled = true
+
+// tuiMode is the screen the browser currently shows
+type tuiMode int
+
+const (
+	modeList tuiMode = iota
+	modeSearch
+	modeDetail
+	modeEdit
+	modeProposal
+)
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	selectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("12")).Bold(true)
+	statusStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true)
+)
+
+// Messages delivered by the commands the browser starts
+type (
+	tracksLoadedMsg struct{ tracks []*domain.Track }
+	proposalMsg     struct {
+		proposed *domain.Track
+		changes  []domain.FieldChange
+	}
+	validatedMsg struct{ confidence float64 }
+	savedMsg     struct{ status string }
+	errMsg       struct{ err error }
+)
+
+// tuiModel is the bubbletea model of the catalog browser
+type tuiModel struct {
+	ctx     context.Context
+	catalog *catalog
+
+	mode   tuiMode
+	tracks []*domain.Track
+	cursor int
+	field  int
+	input  textinput.Model
+	query  string
+
+	proposed *domain.Track
+	changes  []domain.FieldChange
+	status   string
+	err      error
+}
+
+// runTUI starts the interactive catalog browser
+func runTUI(ctx context.Context, s *services) error {
+	input := textinput.New()
+	m := tuiModel{
+		ctx:     ctx,
+		catalog: &catalog{tracks: s.tracks, ai: s.ai},
+		input:   input,
+	}
+	_, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx)).Run()
+	return err
+}
+
+func (m tuiModel) Init() tea.Cmd {
+	return m.load()
+}
+
+func (m tuiModel) load() tea.Cmd {
+	query := m.query
+	return func() tea.Msg {
+		tracks, err := m.catalog.search(m.ctx, query)
+		if err != nil {
+			return errMsg{err}
+		}
+		return tracksLoadedMsg{tracks}
+	}
+}
+
+func (m tuiModel) selected() *domain.Track {
+	if m.cursor < 0 || m.cursor >= len(m.tracks) {
+		return nil
+	}
+	return m.tracks[m.cursor]
+}
+
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tracksLoadedMsg:
+		m.tracks = msg.tracks
+		m.cursor = 0
+		m.status = fmt.Sprintf("%d tracks", len(msg.tracks))
+		return m, nil
+	case proposalMsg:
+		m.proposed = msg.proposed
+		m.changes = msg.changes
+		m.mode = modeProposal
+		m.status = ""
+		return m, nil
+	case validatedMsg:
+		m.status = fmt.Sprintf("validation confidence: %.2f", msg.confidence)
+		return m, nil
+	case savedMsg:
+		m.status = msg.status
+		return m, nil
+	case errMsg:
+		m.err = msg.err
+		return m, nil
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		m.err = nil
+		switch m.mode {
+		case modeSearch, modeEdit:
+			return m.updateInput(msg)
+		case modeDetail:
+			return m.updateDetail(msg)
+		case modeProposal:
+			return m.updateProposal(msg)
+		default:
+			return m.updateList(msg)
+		}
+	}
+	return m, nil
+}
+
+func (m tuiModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.tracks)-1 {
+			m.cursor++
+		}
+	case "/":
+		m.mode = modeSearch
+		m.input.Prompt = "search: "
+		m.input.Placeholder = "text or field=value"
+		m.input.SetValue(m.query)
+		return m, m.input.Focus()
+	case "r":
+		return m, m.load()
+	case "enter":
+		if m.selected() != nil {
+			m.mode = modeDetail
+			m.field = 0
+		}
+	}
+	return m, nil
+}
+
+func (m tuiModel) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	track := m.selected()
+	switch msg.String() {
+	case "esc", "q":
+		m.mode = modeList
+	case "up", "k":
+		if m.field > 0 {
+			m.field--
+		}
+	case "down", "j":
+		if m.field < len(editableFields)-1 {
+			m.field++
+		}
+	case "enter":
+		value, _ := track.FieldValue(editableFields[m.field])
+		m.mode = modeEdit
+		m.input.Prompt = editableFields[m.field] + ": "
+		m.input.Placeholder = ""
+		m.input.SetValue(formatValue(value))
+		return m, m.input.Focus()
+	case "e":
+		m.status = "enriching..."
+		return m, func() tea.Msg {
+			proposed, changes, err := m.catalog.propose(m.ctx, track)
+			if err != nil {
+				return errMsg{err}
+			}
+			return proposalMsg{proposed, changes}
+		}
+	case "v":
+		m.status = "validating..."
+		return m, func() tea.Msg {
+			confidence, err := m.catalog.validate(m.ctx, track)
+			if err != nil {
+				return errMsg{err}
+			}
+			return validatedMsg{confidence}
+		}
+	}
+	return m, nil
+}
+
+func (m tuiModel) updateProposal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "a", "y":
+		proposed := m.proposed
+		m.tracks[m.cursor] = proposed
+		m.mode = modeDetail
+		m.proposed, m.changes = nil, nil
+		return m, m.saveCmd(proposed, "enrichment accepted")
+	case "esc", "n", "q":
+		m.mode = modeDetail
+		m.proposed, m.changes = nil, nil
+		m.status = "enrichment discarded"
+	}
+	return m, nil
+}
+
+func (m tuiModel) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.input.Blur()
+		if m.mode == modeEdit {
+			m.mode = modeDetail
+		} else {
+			m.mode = modeList
+		}
+		return m, nil
+	case "enter":
+		m.input.Blur()
+		if m.mode == modeSearch {
+			m.mode = modeList
+			m.query = m.input.Value()
+			return m, m.load()
+		}
+
+		m.mode = modeDetail
+		edited := m.selected().Clone()
+		if err := setField(edited, editableFields[m.field], m.input.Value()); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.tracks[m.cursor] = edited
+		return m, m.saveCmd(edited, editableFields[m.field]+" saved")
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// saveCmd stores track and reports status once it is saved
+func (m tuiModel) saveCmd(track *domain.Track, status string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.catalog.save(m.ctx, track); err != nil {
+			return errMsg{err}
+		}
+		return savedMsg{status}
+	}
+}
+
+func (m tuiModel) View() string {
+	var b strings.Builder
+	switch m.mode {
+	case modeDetail, modeEdit:
+		m.viewDetail(&b)
+	case modeProposal:
+		m.viewProposal(&b)
+	default:
+		m.viewList(&b)
+	}
+
+	b.WriteString("\n")
+	if m.err != nil {
+		b.WriteString(errorStyle.Render("error: "+m.err.Error()) + "\n")
+	} else if m.status != "" {
+		b.WriteString(statusStyle.Render(m.status) + "\n")
+	}
+	return b.String()
+}
+
+func (m tuiModel) viewList(b *strings.Builder) {
+	b.WriteString(titleStyle.Render("Tracks") + "\n\n")
+	for i, track := range m.tracks {
+		line := fmt.Sprintf("%-36s  %-30s  %-24s  %s", track.ID, track.Title(), track.Artist(), track.ISRC())
+		if i == m.cursor {
+			line = selectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+	if len(m.tracks) == 0 {
+		b.WriteString("  no tracks found\n")
+	}
+
+	b.WriteString("\n")
+	if m.mode == modeSearch {
+		b.WriteString(m.input.View() + "\n")
+	} else {
+		b.WriteString(statusStyle.Render("↑/↓ move · enter open · / search · r reload · q quit") + "\n")
+	}
+}
+
+func (m tuiModel) viewDetail(b *strings.Builder) {
+	track := m.selected()
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Track %s (version %d)", track.ID, track.Version)) + "\n\n")
+	for i, field := range editableFields {
+		value, _ := track.FieldValue(field)
+		line := fmt.Sprintf("%-10s %s", field, formatValue(value))
+		if i == m.field {
+			line = selectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n")
+	if m.mode == modeEdit {
+		b.WriteString(m.input.View() + "\n")
+	} else {
+		b.WriteString(statusStyle.Render("↑/↓ move · enter edit · e enrich · v validate · esc back") + "\n")
+	}
+}
+
+func (m tuiModel) viewProposal(b *strings.Builder) {
+	b.WriteString(titleStyle.Render("Proposed changes for "+m.proposed.ID) + "\n\n")
+	b.WriteString(renderDiff(m.changes, true))
+	b.WriteString("\n" + statusStyle.Render("a accept · esc discard") + "\n")
+}
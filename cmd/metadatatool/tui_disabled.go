@@ -0,0 +1,16 @@
+//go:build !tui
+
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+// tuiEnabled reports whether the interactive browser is built in
+const tuiEnabled = false
+
+// runTUI reports that the interactive browser was left out of this build
+func runTUI(ctx context.Context, s *services) error {
+	return errors.New("TUI support is not compiled in, rebuild with -tags tui")
+}
@@ -0,0 +1,59 @@
+// Command openapigen builds the OpenAPI 3 document from the swagger
+// annotations on the HTTP handlers and renders the typed Go and TypeScript
+// clients.
+//
+// Usage:
+//
+//	openapigen -root . -out internal/pkg/openapi/openapi.json -client pkg/client/client_gen.go -ts sdk/typescript/src/client.gen.ts
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"metadatatool/internal/pkg/openapi"
+)
+
+func main() {
+	root := flag.String("root", ".", "Repository root")
+	out := flag.String("out", "openapi.json", "Path of the generated OpenAPI document")
+	client := flag.String("client", "", "Path of the generated Go client (optional)")
+	clientPkg := flag.String("client-package", "client", "Package name of the generated Go client")
+	tsClient := flag.String("ts", "", "Path of the generated TypeScript client (optional)")
+	flag.Parse()
+
+	doc, err := openapi.Generate(openapi.DefaultGeneratorConfig(*root))
+	if err != nil {
+		log.Fatalf("Failed to generate OpenAPI document: %v", err)
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to marshal OpenAPI document: %v", err)
+	}
+	if err := os.WriteFile(*out, append(data, '\n'), 0o644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *out, err)
+	}
+
+	if *client != "" {
+		src, err := openapi.GenerateClient(doc, *clientPkg)
+		if err != nil {
+			log.Fatalf("Failed to generate client: %v", err)
+		}
+		if err := os.WriteFile(*client, src, 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", *client, err)
+		}
+	}
+
+	if *tsClient != "" {
+		src, err := openapi.GenerateTypeScriptClient(doc)
+		if err != nil {
+			log.Fatalf("Failed to generate TypeScript client: %v", err)
+		}
+		if err := os.WriteFile(*tsClient, src, 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", *tsClient, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_BatchesConcurrentLoads(t *testing.T) {
+	var calls int32
+	var gotKeys []string
+	loader := NewLoader(func(ctx context.Context, keys []string) (map[string]int, error) {
+		atomic.AddInt32(&calls, 1)
+		gotKeys = keys
+		values := make(map[string]int, len(keys))
+		for _, k := range keys {
+			values[k] = len(k)
+		}
+		return values, nil
+	}, 10*time.Millisecond, 100)
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	results := make([]int, 3)
+	for i, key := range []string{"a", "bb", "ccc"} {
+		wg.Add(1)
+		go func(i int, key string) {
+			defer wg.Done()
+			v, err := loader.Load(ctx, key)
+			require.NoError(t, err)
+			results[i] = v
+		}(i, key)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.ElementsMatch(t, []string{"a", "bb", "ccc"}, gotKeys)
+	assert.Equal(t, []int{1, 2, 3}, results)
+
+	// Cached keys do not trigger another fetch
+	values, err := loader.LoadMany(ctx, []string{"a", "ccc"})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, values)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestLoader_SplitsAtMaxBatch(t *testing.T) {
+	var calls int32
+	loader := NewLoader(func(ctx context.Context, keys []int) (map[int]int, error) {
+		atomic.AddInt32(&calls, 1)
+		assert.LessOrEqual(t, len(keys), 2)
+		values := make(map[int]int, len(keys))
+		for _, k := range keys {
+			values[k] = k * 10
+		}
+		return values, nil
+	}, 10*time.Millisecond, 2)
+
+	values, err := loader.LoadMany(context.Background(), []int{1, 2, 3, 4, 5})
+	require.NoError(t, err)
+	assert.Equal(t, []int{10, 20, 30, 40, 50}, values)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestLoader_DoesNotCacheErrors(t *testing.T) {
+	fail := true
+	loader := NewLoader(func(ctx context.Context, keys []string) (map[string]string, error) {
+		if fail {
+			return nil, errors.New("unavailable")
+		}
+		return map[string]string{"a": "A"}, nil
+	}, time.Millisecond, 10)
+
+	ctx := context.Background()
+	_, err := loader.Load(ctx, "a")
+	require.Error(t, err)
+
+	fail = false
+	v, err := loader.Load(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "A", v)
+
+	// Missing keys resolve to the zero value
+	v, err = loader.Load(ctx, "missing")
+	require.NoError(t, err)
+	assert.Empty(t, v)
+}
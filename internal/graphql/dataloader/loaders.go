@@ -0,0 +1,140 @@
+package dataloader
+
+import (
+	"context"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultWait     = 2 * time.Millisecond
+	defaultMaxBatch = 100
+)
+
+type contextKey string
+
+const loadersContextKey contextKey = "graphql_loaders"
+
+// Loaders holds the per-request loaders used by GraphQL resolvers
+type Loaders struct {
+	TrackByID       *Loader[string, *domain.Track]
+	TracksByRelease *Loader[string, []*domain.Track]
+	UserByID        *Loader[string, *domain.User]
+}
+
+// NewLoaders creates a fresh set of loaders. Repositories implementing
+// domain.TrackBatchReader or domain.UserBatchReader are queried once per
+// batch; others fall back to one lookup per key.
+func NewLoaders(trackRepo domain.TrackRepository, userRepo domain.UserRepository) *Loaders {
+	return &Loaders{
+		TrackByID:       NewLoader(trackBatch(trackRepo), defaultWait, defaultMaxBatch),
+		TracksByRelease: NewLoader(releaseTracksBatch(trackRepo), defaultWait, defaultMaxBatch),
+		UserByID:        NewLoader(userBatch(userRepo), defaultWait, defaultMaxBatch),
+	}
+}
+
+// WithLoaders stores loaders in the context
+func WithLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, loadersContextKey, loaders)
+}
+
+// For returns the loaders attached to the request context
+func For(ctx context.Context) (*Loaders, bool) {
+	loaders, ok := ctx.Value(loadersContextKey).(*Loaders)
+	return loaders, ok
+}
+
+// Middleware attaches a new set of loaders to every request so cached
+// results never leak between requests
+func Middleware(trackRepo domain.TrackRepository, userRepo domain.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := WithLoaders(c.Request.Context(), NewLoaders(trackRepo, userRepo))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+func trackBatch(repo domain.TrackRepository) BatchFunc[string, *domain.Track] {
+	return func(ctx context.Context, ids []string) (map[string]*domain.Track, error) {
+		tracks := make(map[string]*domain.Track, len(ids))
+		if batch, ok := repo.(domain.TrackBatchReader); ok {
+			found, err := batch.GetByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			for _, track := range found {
+				tracks[track.ID] = track
+			}
+			return tracks, nil
+		}
+
+		for _, id := range ids {
+			track, err := repo.GetByID(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if track != nil {
+				tracks[id] = track
+			}
+		}
+		return tracks, nil
+	}
+}
+
+func releaseTracksBatch(repo domain.TrackRepository) BatchFunc[string, []*domain.Track] {
+	return func(ctx context.Context, releaseIDs []string) (map[string][]*domain.Track, error) {
+		byRelease := make(map[string][]*domain.Track, len(releaseIDs))
+		if batch, ok := repo.(domain.TrackBatchReader); ok {
+			found, err := batch.ListByReleaseIDs(ctx, releaseIDs)
+			if err != nil {
+				return nil, err
+			}
+			for _, track := range found {
+				byRelease[track.ReleaseID] = append(byRelease[track.ReleaseID], track)
+			}
+			return byRelease, nil
+		}
+
+		for _, id := range releaseIDs {
+			found, err := repo.List(ctx, map[string]interface{}{"release_id": id}, 0, defaultMaxBatch)
+			if err != nil {
+				return nil, err
+			}
+			byRelease[id] = found
+		}
+		return byRelease, nil
+	}
+}
+
+func userBatch(repo domain.UserRepository) BatchFunc[string, *domain.User] {
+	return func(ctx context.Context, ids []string) (map[string]*domain.User, error) {
+		users := make(map[string]*domain.User, len(ids))
+		if repo == nil {
+			return users, nil
+		}
+		if batch, ok := repo.(domain.UserBatchReader); ok {
+			found, err := batch.GetByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			for _, user := range found {
+				users[user.ID] = user
+			}
+			return users, nil
+		}
+
+		for _, id := range ids {
+			user, err := repo.GetByID(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if user != nil {
+				users[id] = user
+			}
+		}
+		return users, nil
+	}
+}
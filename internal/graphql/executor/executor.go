@@ -0,0 +1,219 @@
+// Package executor binds the GraphQL schema to its resolvers, adapting the
+// domain values the resolvers return to the types of the schema
+package executor
+
+import (
+	"context"
+
+	"metadatatool/internal/graphql/generated"
+	"metadatatool/internal/graphql/schema"
+	"metadatatool/internal/pkg/domain"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// maxDepth bounds how deeply a query can nest, so that one request cannot
+// walk the catalog back and forth through its relations
+const maxDepth = 10
+
+// NewSchema returns the executable schema resolved by root
+func NewSchema(root generated.ResolverRoot) (*graphql.Schema, error) {
+	return graphql.ParseSchema(schema.SDL, &rootResolver{root: root}, graphql.MaxDepth(maxDepth))
+}
+
+// rootResolver resolves the fields of Query, Mutation and Subscription
+type rootResolver struct {
+	root generated.ResolverRoot
+}
+
+type idArgs struct {
+	ID graphql.ID
+}
+
+type idsArgs struct {
+	IDs []graphql.ID
+}
+
+type tracksArgs struct {
+	First   *int32
+	After   *string
+	Filter  *trackFilterInput
+	OrderBy *string
+}
+
+type pageArgs struct {
+	First *int32
+	After *string
+}
+
+func (r *rootResolver) Track(ctx context.Context, args idArgs) (*trackResolver, error) {
+	track, err := r.root.Query().Track(ctx, string(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	return r.track(track), nil
+}
+
+func (r *rootResolver) Tracks(ctx context.Context, args tracksArgs) (*connectionResolver, error) {
+	var filter *domain.TrackFilter
+	if args.Filter != nil {
+		filter = args.Filter.domain()
+	}
+	connection, err := r.root.Query().Tracks(ctx, intPtr(args.First), args.After, filter, args.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+	return &connectionResolver{connection: connection, root: r}, nil
+}
+
+func (r *rootResolver) SearchTracks(ctx context.Context, args struct{ Query string }) ([]*trackResolver, error) {
+	tracks, err := r.root.Query().SearchTracks(ctx, args.Query)
+	if err != nil {
+		return nil, err
+	}
+	return r.tracks(tracks), nil
+}
+
+func (r *rootResolver) TracksNeedingReview(ctx context.Context, args pageArgs) (*connectionResolver, error) {
+	connection, err := r.root.Query().TracksNeedingReview(ctx, intPtr(args.First), args.After)
+	if err != nil {
+		return nil, err
+	}
+	return &connectionResolver{connection: connection, root: r}, nil
+}
+
+func (r *rootResolver) Release(ctx context.Context, args idArgs) (*releaseResolver, error) {
+	release, err := r.root.Query().Release(ctx, string(args.ID))
+	if err != nil || release == nil {
+		return nil, err
+	}
+	return &releaseResolver{release: release, root: r}, nil
+}
+
+func (r *rootResolver) Releases(ctx context.Context, args idsArgs) ([]*releaseResolver, error) {
+	releases, err := r.root.Query().Releases(ctx, ids(args.IDs))
+	if err != nil {
+		return nil, err
+	}
+	resolved := make([]*releaseResolver, len(releases))
+	for i, release := range releases {
+		resolved[i] = &releaseResolver{release: release, root: r}
+	}
+	return resolved, nil
+}
+
+func (r *rootResolver) CreateTrack(ctx context.Context, args struct{ Input createTrackInput }) (*trackResolver, error) {
+	track, err := r.root.Mutation().CreateTrack(ctx, args.Input.domain())
+	if err != nil {
+		return nil, err
+	}
+	return r.track(track), nil
+}
+
+func (r *rootResolver) UpdateTrack(ctx context.Context, args struct{ Input updateTrackInput }) (*trackResolver, error) {
+	track, err := r.root.Mutation().UpdateTrack(ctx, args.Input.domain())
+	if err != nil {
+		return nil, err
+	}
+	return r.track(track), nil
+}
+
+func (r *rootResolver) DeleteTrack(ctx context.Context, args idArgs) (bool, error) {
+	return r.root.Mutation().DeleteTrack(ctx, string(args.ID))
+}
+
+func (r *rootResolver) BatchProcessTracks(ctx context.Context, args idsArgs) (*batchResultResolver, error) {
+	result, err := r.root.Mutation().BatchProcessTracks(ctx, ids(args.IDs))
+	if err != nil {
+		return nil, err
+	}
+	return &batchResultResolver{result: result}, nil
+}
+
+func (r *rootResolver) EnrichTrackMetadata(ctx context.Context, args idArgs) (*trackResolver, error) {
+	track, err := r.root.Mutation().EnrichTrackMetadata(ctx, string(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	return r.track(track), nil
+}
+
+func (r *rootResolver) ValidateTrackMetadata(ctx context.Context, args idArgs) (*batchResultResolver, error) {
+	result, err := r.root.Mutation().ValidateTrackMetadata(ctx, string(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	return &batchResultResolver{result: result}, nil
+}
+
+func (r *rootResolver) ExportToDDEX(ctx context.Context, args idsArgs) (string, error) {
+	return r.root.Mutation().ExportToDDEX(ctx, ids(args.IDs))
+}
+
+func (r *rootResolver) TrackUpdated(ctx context.Context, args idArgs) (<-chan *trackResolver, error) {
+	updates, err := r.root.Subscription().TrackUpdated(ctx, string(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	return relay(ctx, updates, r.track), nil
+}
+
+func (r *rootResolver) BatchProcessingProgress(ctx context.Context, args struct{ BatchID graphql.ID }) (<-chan *batchResultResolver, error) {
+	progress, err := r.root.Subscription().BatchProcessingProgress(ctx, string(args.BatchID))
+	if err != nil {
+		return nil, err
+	}
+	return relay(ctx, progress, func(result *domain.BatchResult) *batchResultResolver {
+		return &batchResultResolver{result: result}
+	}), nil
+}
+
+// track resolves a track, or null when there is none
+func (r *rootResolver) track(track *domain.Track) *trackResolver {
+	if track == nil {
+		return nil
+	}
+	return &trackResolver{track: track, root: r}
+}
+
+func (r *rootResolver) tracks(tracks []*domain.Track) []*trackResolver {
+	resolved := make([]*trackResolver, 0, len(tracks))
+	for _, track := range tracks {
+		if track != nil {
+			resolved = append(resolved, r.track(track))
+		}
+	}
+	return resolved
+}
+
+// relay converts the values of a subscription until in closes or ctx is done
+func relay[T, R any](ctx context.Context, in <-chan T, convert func(T) R) <-chan R {
+	out := make(chan R)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- convert(v):
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func ids(ids []graphql.ID) []string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = string(id)
+	}
+	return s
+}
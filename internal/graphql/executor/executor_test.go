@@ -0,0 +1,153 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"metadatatool/internal/graphql/dataloader"
+	"metadatatool/internal/graphql/generated"
+	"metadatatool/internal/graphql/resolvers"
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchTrackRepository serves tracks in batches and counts the queries
+type batchTrackRepository struct {
+	domain.TrackRepository
+	mu             sync.Mutex
+	tracks         []*domain.Track
+	single         int
+	releaseBatches [][]string
+}
+
+func (r *batchTrackRepository) GetByID(ctx context.Context, id string) (*domain.Track, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.single++
+	for _, track := range r.tracks {
+		if track.ID == id {
+			return track, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *batchTrackRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Track, error) {
+	var found []*domain.Track
+	for _, track := range r.tracks {
+		for _, id := range ids {
+			if track.ID == id {
+				found = append(found, track)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *batchTrackRepository) ListByReleaseIDs(ctx context.Context, releaseIDs []string) ([]*domain.Track, error) {
+	r.mu.Lock()
+	r.releaseBatches = append(r.releaseBatches, releaseIDs)
+	r.mu.Unlock()
+
+	var found []*domain.Track
+	for _, track := range r.tracks {
+		for _, id := range releaseIDs {
+			if track.ReleaseID == id {
+				found = append(found, track)
+			}
+		}
+	}
+	return found, nil
+}
+
+// batchUserRepository serves users in batches and counts the queries
+type batchUserRepository struct {
+	domain.UserRepository
+	mu      sync.Mutex
+	users   map[string]*domain.User
+	single  int
+	batches [][]string
+}
+
+func (r *batchUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.single++
+	return r.users[id], nil
+}
+
+func (r *batchUserRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.User, error) {
+	r.mu.Lock()
+	r.batches = append(r.batches, ids)
+	r.mu.Unlock()
+
+	var found []*domain.User
+	for _, id := range ids {
+		if user, ok := r.users[id]; ok {
+			found = append(found, user)
+		}
+	}
+	return found, nil
+}
+
+func TestSchema_NestedQueryBatchesLookups(t *testing.T) {
+	tracks := &batchTrackRepository{tracks: []*domain.Track{
+		{ID: "t1", ReleaseID: "r1", ArtistIDs: []string{"u1", "u2"}},
+		{ID: "t2", ReleaseID: "r1", ArtistIDs: []string{"u2"}},
+		{ID: "t3", ReleaseID: "r2", ArtistIDs: []string{"u3"}},
+	}}
+	users := &batchUserRepository{users: map[string]*domain.User{
+		"u1": {ID: "u1", Name: "Ada"},
+		"u2": {ID: "u2", Name: "Grace"},
+		"u3": {ID: "u3", Name: "Edsger"},
+	}}
+
+	schema, err := NewSchema(resolvers.NewResolver(generated.NewResolver(tracks, users, nil, nil, nil, nil)))
+	require.NoError(t, err)
+
+	ctx := dataloader.WithLoaders(context.Background(), dataloader.NewLoaders(tracks, users))
+	response := schema.Exec(ctx, `
+		query {
+			releases(ids: ["r1", "r2"]) {
+				id
+				trackCount
+				tracks { id contributors { id name } }
+			}
+		}`, "", nil)
+	require.Empty(t, response.Errors)
+
+	var data struct {
+		Releases []struct {
+			ID         string
+			TrackCount int
+			Tracks     []struct {
+				ID           string
+				Contributors []struct{ ID, Name string }
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(response.Data, &data))
+	require.Len(t, data.Releases, 2)
+	assert.Equal(t, 2, data.Releases[0].TrackCount)
+	assert.Equal(t, "Grace", data.Releases[0].Tracks[1].Contributors[0].Name)
+	assert.Equal(t, "Edsger", data.Releases[1].Tracks[0].Contributors[0].Name)
+
+	assert.Len(t, tracks.releaseBatches, 1, "tracks of all releases are listed in one query")
+	assert.Zero(t, tracks.single)
+	require.Len(t, users.batches, 1, "contributors of all tracks are fetched in one query")
+	assert.ElementsMatch(t, []string{"u1", "u2", "u3"}, users.batches[0])
+	assert.Zero(t, users.single)
+}
+
+func TestSchema_RejectsDeepQueries(t *testing.T) {
+	schema, err := NewSchema(resolvers.NewResolver(generated.NewResolver(&batchTrackRepository{}, &batchUserRepository{}, nil, nil, nil, nil)))
+	require.NoError(t, err)
+
+	query := `query { release(id: "r1") { tracks { release { tracks { release { tracks { release { tracks { release { tracks { release { id } } } } } } } } } } } }`
+	response := schema.Exec(context.Background(), query, "", nil)
+	assert.NotEmpty(t, response.Errors)
+}
@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// DateTime is the DateTime scalar, an RFC 3339 timestamp
+type DateTime struct {
+	time.Time
+}
+
+// ImplementsGraphQLType reports whether DateTime implements the named scalar
+func (DateTime) ImplementsGraphQLType(name string) bool { return name == "DateTime" }
+
+// UnmarshalGraphQL parses an RFC 3339 timestamp
+func (t *DateTime) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok {
+		return fmt.Errorf("DateTime must be an RFC 3339 string, got %T", input)
+	}
+	parsed, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return fmt.Errorf("DateTime must be an RFC 3339 string: %w", err)
+	}
+	t.Time = parsed
+	return nil
+}
+
+// MarshalJSON writes the timestamp in RFC 3339 format
+func (t DateTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Time)
+}
+
+// JSON is the JSON scalar. The schema uses it for custom fields, so it
+// holds an object of strings.
+type JSON map[string]string
+
+// ImplementsGraphQLType reports whether JSON implements the named scalar
+func (JSON) ImplementsGraphQLType(name string) bool { return name == "JSON" }
+
+// UnmarshalGraphQL reads an object whose values are all strings
+func (j *JSON) UnmarshalGraphQL(input interface{}) error {
+	object, ok := input.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("JSON must be an object, got %T", input)
+	}
+	fields := make(JSON, len(object))
+	for key, value := range object {
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("JSON field %q must be a string, got %T", key, value)
+		}
+		fields[key] = s
+	}
+	*j = fields
+	return nil
+}
+
+// Upload is the Upload scalar, a file sent with a multipart request. The
+// handler puts the files of the request into the variables the map of the
+// request names.
+type Upload struct {
+	File *domain.AudioFile
+}
+
+// ImplementsGraphQLType reports whether Upload implements the named scalar
+func (Upload) ImplementsGraphQLType(name string) bool { return name == "Upload" }
+
+// UnmarshalGraphQL takes the file the handler put into the variables
+func (u *Upload) UnmarshalGraphQL(input interface{}) error {
+	file, ok := input.(*domain.AudioFile)
+	if !ok {
+		return fmt.Errorf("Upload must be a file of a multipart request")
+	}
+	u.File = file
+	return nil
+}
@@ -0,0 +1,410 @@
+package executor
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// trackResolver resolves a Track. Blank and zero fields, which the domain
+// uses for unknown values, resolve to null.
+type trackResolver struct {
+	track *domain.Track
+	root  *rootResolver
+}
+
+func (t *trackResolver) ID() graphql.ID     { return graphql.ID(t.track.ID) }
+func (t *trackResolver) Version() int32     { return int32(t.track.Version) }
+func (t *trackResolver) Title() string      { return t.track.Title() }
+func (t *trackResolver) Artist() string     { return t.track.Artist() }
+func (t *trackResolver) Album() *string     { return optional(t.track.Album()) }
+func (t *trackResolver) Genre() *string     { return optional(t.track.Genre()) }
+func (t *trackResolver) Duration() *float64 { return optional(t.track.Duration()) }
+func (t *trackResolver) FilePath() *string  { return optional(t.track.StoragePath) }
+func (t *trackResolver) Year() *int32       { return optionalInt(int64(t.track.Year())) }
+func (t *trackResolver) Label() *string     { return optional(t.track.Label()) }
+func (t *trackResolver) Territory() *string { return optional(t.track.Territory()) }
+func (t *trackResolver) ISRC() *string      { return optional(t.track.ISRC()) }
+func (t *trackResolver) ISWC() *string      { return optional(t.track.ISWC()) }
+func (t *trackResolver) BPM() *float64      { return optional(t.track.BPM()) }
+func (t *trackResolver) Key() *string       { return optional(t.track.Key()) }
+func (t *trackResolver) Mood() *string      { return optional(t.track.Mood()) }
+func (t *trackResolver) Publisher() *string { return optional(t.track.Publisher()) }
+
+func (t *trackResolver) AudioFormat() *string { return optional(t.track.AudioFormat()) }
+func (t *trackResolver) FileSize() *int32     { return optionalInt(t.track.FileSize) }
+
+func (t *trackResolver) AITags() *[]string      { return optionalList(t.track.AITags()) }
+func (t *trackResolver) AIConfidence() *float64 { return optional(t.track.AIConfidence()) }
+func (t *trackResolver) ModelVersion() *string  { return optional(t.track.ModelVersion()) }
+func (t *trackResolver) CreatedAt() DateTime    { return DateTime{t.track.CreatedAt} }
+func (t *trackResolver) UpdatedAt() DateTime    { return DateTime{t.track.UpdatedAt} }
+func (t *trackResolver) DeletedAt() *DateTime   { return optionalTime(t.track.DeletedAt) }
+func (t *trackResolver) NeedsReview() *bool {
+	if t.track.Metadata.AI == nil {
+		return nil
+	}
+	needsReview := t.track.NeedsReview()
+	return &needsReview
+}
+
+func (t *trackResolver) AIMetadata(ctx context.Context) (*aiMetadataResolver, error) {
+	metadata, err := t.root.root.Track().AIMetadata(ctx, t.track)
+	if err != nil || metadata == nil {
+		return nil, err
+	}
+	return &aiMetadataResolver{metadata: metadata}, nil
+}
+
+func (t *trackResolver) Metadata(ctx context.Context) (*metadataResolver, error) {
+	metadata, err := t.root.root.Track().Metadata(ctx, t.track)
+	if err != nil || metadata == nil {
+		return nil, err
+	}
+	return &metadataResolver{metadata: metadata}, nil
+}
+
+func (t *trackResolver) Release(ctx context.Context) (*releaseResolver, error) {
+	release, err := t.root.root.Track().Release(ctx, t.track)
+	if err != nil || release == nil {
+		return nil, err
+	}
+	return &releaseResolver{release: release, root: t.root}, nil
+}
+
+func (t *trackResolver) Contributors(ctx context.Context) (*[]*contributorResolver, error) {
+	users, err := t.root.root.Track().Contributors(ctx, t.track)
+	if err != nil || users == nil {
+		return nil, err
+	}
+	contributors := make([]*contributorResolver, len(users))
+	for i, user := range users {
+		contributors[i] = &contributorResolver{user: user}
+	}
+	return &contributors, nil
+}
+
+// aiMetadataResolver resolves an AIMetadata
+type aiMetadataResolver struct {
+	metadata *domain.AIMetadata
+}
+
+func (m *aiMetadataResolver) Provider() string       { return string(m.metadata.Provider) }
+func (m *aiMetadataResolver) Energy() *float64       { return optional(m.metadata.Energy) }
+func (m *aiMetadataResolver) Danceability() *float64 { return optional(m.metadata.Danceability) }
+func (m *aiMetadataResolver) ProcessedAt() DateTime  { return DateTime{m.metadata.ProcessedAt} }
+func (m *aiMetadataResolver) ProcessingMs() int32 {
+	return int32(min(m.metadata.ProcessingMs, math.MaxInt32))
+}
+func (m *aiMetadataResolver) NeedsReview() bool     { return m.metadata.NeedsReview }
+func (m *aiMetadataResolver) ReviewReason() *string { return optional(m.metadata.ReviewReason) }
+
+// metadataResolver resolves a Metadata
+type metadataResolver struct {
+	metadata *domain.Metadata
+}
+
+func (m *metadataResolver) ISRC() *string         { return optional(m.metadata.ISRC) }
+func (m *metadataResolver) ISWC() *string         { return optional(m.metadata.ISWC) }
+func (m *metadataResolver) BPM() *float64         { return optional(m.metadata.BPM) }
+func (m *metadataResolver) Key() *string          { return optional(m.metadata.Key) }
+func (m *metadataResolver) Mood() *string         { return optional(m.metadata.Mood) }
+func (m *metadataResolver) Labels() *[]string     { return optionalList(m.metadata.Labels) }
+func (m *metadataResolver) AITags() *[]string     { return optionalList(m.metadata.AITags) }
+func (m *metadataResolver) Confidence() *float64  { return optional(m.metadata.Confidence) }
+func (m *metadataResolver) ModelVersion() *string { return optional(m.metadata.ModelVersion) }
+
+func (m *metadataResolver) CustomFields() *JSON {
+	if len(m.metadata.CustomFields) == 0 {
+		return nil
+	}
+	fields := JSON(m.metadata.CustomFields)
+	return &fields
+}
+
+// releaseResolver resolves a Release
+type releaseResolver struct {
+	release *domain.CatalogRelease
+	root    *rootResolver
+}
+
+func (r *releaseResolver) ID() graphql.ID { return graphql.ID(r.release.ID) }
+
+func (r *releaseResolver) TrackCount(ctx context.Context) (int32, error) {
+	count, err := r.root.root.Release().TrackCount(ctx, r.release)
+	return int32(count), err
+}
+
+func (r *releaseResolver) Tracks(ctx context.Context) ([]*trackResolver, error) {
+	tracks, err := r.root.root.Release().Tracks(ctx, r.release)
+	if err != nil {
+		return nil, err
+	}
+	return r.root.tracks(tracks), nil
+}
+
+// contributorResolver resolves a Contributor from the user account of an
+// artist
+type contributorResolver struct {
+	user *domain.User
+}
+
+func (c *contributorResolver) ID() graphql.ID   { return graphql.ID(c.user.ID) }
+func (c *contributorResolver) Name() string     { return c.user.Name }
+func (c *contributorResolver) Company() *string { return optional(c.user.Company) }
+
+// connectionResolver resolves a TrackConnection
+type connectionResolver struct {
+	connection *domain.TrackConnection
+	root       *rootResolver
+}
+
+func (c *connectionResolver) Edges() []*edgeResolver {
+	edges := make([]*edgeResolver, 0, len(c.connection.Edges))
+	for _, edge := range c.connection.Edges {
+		if edge.Node != nil {
+			edges = append(edges, &edgeResolver{edge: edge, root: c.root})
+		}
+	}
+	return edges
+}
+
+func (c *connectionResolver) PageInfo() *pageInfoResolver {
+	info := c.connection.PageInfo
+	if info == nil {
+		info = &domain.PageInfo{}
+	}
+	return &pageInfoResolver{info: info}
+}
+
+func (c *connectionResolver) TotalCount() int32 { return int32(c.connection.TotalCount) }
+
+// edgeResolver resolves a TrackEdge
+type edgeResolver struct {
+	edge *domain.TrackEdge
+	root *rootResolver
+}
+
+func (e *edgeResolver) Node() *trackResolver { return e.root.track(e.edge.Node) }
+func (e *edgeResolver) Cursor() string       { return e.edge.Cursor }
+
+// pageInfoResolver resolves a PageInfo
+type pageInfoResolver struct {
+	info *domain.PageInfo
+}
+
+func (p *pageInfoResolver) HasNextPage() bool     { return p.info.HasNextPage }
+func (p *pageInfoResolver) HasPreviousPage() bool { return p.info.HasPreviousPage }
+func (p *pageInfoResolver) StartCursor() *string  { return optional(p.info.StartCursor) }
+func (p *pageInfoResolver) EndCursor() *string    { return optional(p.info.EndCursor) }
+
+// batchResultResolver resolves a BatchResult
+type batchResultResolver struct {
+	result *domain.BatchResult
+}
+
+func (b *batchResultResolver) SuccessCount() int32 { return int32(b.result.SuccessCount) }
+func (b *batchResultResolver) FailureCount() int32 { return int32(b.result.FailureCount) }
+
+func (b *batchResultResolver) Errors() *[]*batchErrorResolver {
+	if len(b.result.Errors) == 0 {
+		return nil
+	}
+	errs := make([]*batchErrorResolver, len(b.result.Errors))
+	for i, err := range b.result.Errors {
+		errs[i] = &batchErrorResolver{err: err}
+	}
+	return &errs
+}
+
+// batchErrorResolver resolves a BatchError
+type batchErrorResolver struct {
+	err *domain.BatchError
+}
+
+func (b *batchErrorResolver) Message() string { return b.err.Message }
+func (b *batchErrorResolver) Code() string    { return b.err.Code }
+
+func (b *batchErrorResolver) TrackID() *graphql.ID {
+	if b.err.TrackID == "" {
+		return nil
+	}
+	id := graphql.ID(b.err.TrackID)
+	return &id
+}
+
+// createTrackInput is a CreateTrackInput
+type createTrackInput struct {
+	Title     string
+	Artist    string
+	Album     *string
+	Genre     *string
+	Year      *int32
+	Label     *string
+	Territory *string
+	ISRC      *string
+	ISWC      *string
+	AudioFile *Upload
+}
+
+func (in createTrackInput) domain() domain.CreateTrackInput {
+	input := domain.CreateTrackInput{
+		Title:     in.Title,
+		Artist:    in.Artist,
+		Album:     in.Album,
+		Genre:     in.Genre,
+		Year:      intPtr(in.Year),
+		Label:     in.Label,
+		Territory: in.Territory,
+		ISRC:      in.ISRC,
+		ISWC:      in.ISWC,
+	}
+	if in.AudioFile != nil {
+		input.AudioFile = in.AudioFile.File
+	}
+	return input
+}
+
+// updateTrackInput is an UpdateTrackInput
+type updateTrackInput struct {
+	ID        graphql.ID
+	Version   int32
+	Title     *string
+	Artist    *string
+	Album     *string
+	Genre     *string
+	Year      *int32
+	Label     *string
+	Territory *string
+	ISRC      *string
+	ISWC      *string
+	Metadata  *metadataInput
+}
+
+func (in updateTrackInput) domain() domain.UpdateTrackInput {
+	input := domain.UpdateTrackInput{
+		ID:        string(in.ID),
+		Version:   int(in.Version),
+		Title:     in.Title,
+		Artist:    in.Artist,
+		Album:     in.Album,
+		Genre:     in.Genre,
+		Year:      intPtr(in.Year),
+		Label:     in.Label,
+		Territory: in.Territory,
+		ISRC:      in.ISRC,
+		ISWC:      in.ISWC,
+	}
+	if in.Metadata != nil {
+		input.Metadata = in.Metadata.domain()
+	}
+	return input
+}
+
+// metadataInput is a MetadataInput
+type metadataInput struct {
+	ISRC         *string
+	ISWC         *string
+	BPM          *float64
+	Key          *string
+	Mood         *string
+	Labels       *[]string
+	CustomFields *JSON
+}
+
+func (in metadataInput) domain() *domain.MetadataInput {
+	input := &domain.MetadataInput{
+		ISRC: in.ISRC,
+		ISWC: in.ISWC,
+		BPM:  in.BPM,
+		Key:  in.Key,
+		Mood: in.Mood,
+	}
+	if in.Labels != nil {
+		input.Labels = *in.Labels
+	}
+	if in.CustomFields != nil {
+		input.CustomFields = *in.CustomFields
+	}
+	return input
+}
+
+// trackFilterInput is a TrackFilter
+type trackFilterInput struct {
+	Title       *string
+	Artist      *string
+	Album       *string
+	Genre       *string
+	Label       *string
+	ISRC        *string
+	ISWC        *string
+	NeedsReview *bool
+	CreatedFrom *DateTime
+	CreatedTo   *DateTime
+}
+
+func (in trackFilterInput) domain() *domain.TrackFilter {
+	filter := &domain.TrackFilter{
+		Title:       in.Title,
+		Artist:      in.Artist,
+		Album:       in.Album,
+		Genre:       in.Genre,
+		Label:       in.Label,
+		ISRC:        in.ISRC,
+		ISWC:        in.ISWC,
+		NeedsReview: in.NeedsReview,
+	}
+	if in.CreatedFrom != nil {
+		filter.CreatedFrom = &in.CreatedFrom.Time
+	}
+	if in.CreatedTo != nil {
+		filter.CreatedTo = &in.CreatedTo.Time
+	}
+	return filter
+}
+
+// optional returns nil for the zero value, which the domain uses for
+// unknown values
+func optional[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return &v
+}
+
+// optionalInt is optional for Int fields, which hold 32 bits. Values too
+// large for them are unknown too.
+func optionalInt(v int64) *int32 {
+	if v == 0 || v > math.MaxInt32 || v < math.MinInt32 {
+		return nil
+	}
+	i := int32(v)
+	return &i
+}
+
+func optionalList(v []string) *[]string {
+	if len(v) == 0 {
+		return nil
+	}
+	return &v
+}
+
+func optionalTime(t *time.Time) *DateTime {
+	if t == nil {
+		return nil
+	}
+	return &DateTime{*t}
+}
+
+func intPtr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
@@ -11,6 +11,7 @@ import (
 
 type Resolver struct {
 	TrackRepo      domain.TrackRepository
+	UserRepo       domain.UserRepository
 	AIService      domain.AIService
 	DDEXService    domain.DDEXService
 	AuthService    domain.AuthService
@@ -23,6 +24,8 @@ type QueryResolver interface {
 	Tracks(ctx context.Context, first *int, after *string, filter *domain.TrackFilter, orderBy *string) (*domain.TrackConnection, error)
 	SearchTracks(ctx context.Context, query string) ([]*domain.Track, error)
 	TracksNeedingReview(ctx context.Context, first *int, after *string) (*domain.TrackConnection, error)
+	Release(ctx context.Context, id string) (*domain.CatalogRelease, error)
+	Releases(ctx context.Context, ids []string) ([]*domain.CatalogRelease, error)
 }
 
 // MutationResolver defines the mutation resolver interface
@@ -46,10 +49,19 @@ type SubscriptionResolver interface {
 type TrackResolver interface {
 	AIMetadata(ctx context.Context, obj *domain.Track) (*domain.AIMetadata, error)
 	Metadata(ctx context.Context, obj *domain.Track) (*domain.Metadata, error)
+	Release(ctx context.Context, obj *domain.Track) (*domain.CatalogRelease, error)
+	Contributors(ctx context.Context, obj *domain.Track) ([]*domain.User, error)
+}
+
+// ReleaseResolver defines the release resolver interface
+type ReleaseResolver interface {
+	Tracks(ctx context.Context, obj *domain.CatalogRelease) ([]*domain.Track, error)
+	TrackCount(ctx context.Context, obj *domain.CatalogRelease) (int, error)
 }
 
 func NewResolver(
 	trackRepo domain.TrackRepository,
+	userRepo domain.UserRepository,
 	aiService domain.AIService,
 	ddexService domain.DDEXService,
 	authService domain.AuthService,
@@ -57,6 +69,7 @@ func NewResolver(
 ) *Resolver {
 	return &Resolver{
 		TrackRepo:      trackRepo,
+		UserRepo:       userRepo,
 		AIService:      aiService,
 		DDEXService:    ddexService,
 		AuthService:    authService,
@@ -70,4 +83,5 @@ type ResolverRoot interface {
 	Mutation() MutationResolver
 	Subscription() SubscriptionResolver
 	Track() TrackResolver
+	Release() ReleaseResolver
 }
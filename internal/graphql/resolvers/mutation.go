@@ -2,6 +2,7 @@ package resolvers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"metadatatool/internal/pkg/domain"
 	"path/filepath"
@@ -12,34 +13,32 @@ import (
 )
 
 func (r *mutationResolver) CreateTrack(ctx context.Context, input domain.CreateTrackInput) (*domain.Track, error) {
-	// Create track with basic fields
+	metadata, errs := domain.NewMetadataBuilder().
+		Title(input.Title).
+		Artist(input.Artist).
+		Album(stringValue(input.Album)).
+		Year(intValue(input.Year)).
+		ISRC(stringValue(input.ISRC)).
+		ISWC(stringValue(input.ISWC)).
+		Genre(stringValue(input.Genre)).
+		Label(stringValue(input.Label)).
+		Territory(stringValue(input.Territory)).
+		Build()
+	if len(errs) > 0 {
+		return nil, invalidTrackError(errs)
+	}
 	track := &domain.Track{
 		ID:        uuid.New().String(),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-		Metadata: domain.CompleteTrackMetadata{
-			BasicTrackMetadata: domain.BasicTrackMetadata{
-				Title:  input.Title,
-				Artist: input.Artist,
-				Album:  stringValue(input.Album),
-				Year:   intValue(input.Year),
-				ISRC:   stringValue(input.ISRC),
-			},
-			Musical: domain.MusicalMetadata{
-				Genre: stringValue(input.Genre),
-			},
-			Additional: domain.AdditionalMetadata{
-				CustomFields: map[string]string{
-					"label":     stringValue(input.Label),
-					"territory": stringValue(input.Territory),
-					"iswc":      stringValue(input.ISWC),
-				},
-			},
-		},
+		CreatedAt: metadata.CreatedAt,
+		UpdatedAt: metadata.UpdatedAt,
+		Metadata:  metadata,
 	}
 
 	// Handle audio file if provided
 	if input.AudioFile != nil {
+		if r.StorageService == nil {
+			return nil, errStorageDisabled
+		}
 		// Create storage file
 		storageFile := &domain.StorageFile{
 			Key:         fmt.Sprintf("audio/%s/%s", track.ID, input.AudioFile.Filename),
@@ -64,6 +63,34 @@ func (r *mutationResolver) CreateTrack(ctx context.Context, input domain.CreateT
 	return track, nil
 }
 
+// Errors for mutations needing a service the server runs without
+var (
+	errStorageDisabled = errors.New("file storage is not configured")
+	errAIDisabled      = errors.New("the AI service is not configured")
+)
+
+// getTrack returns the track with id, or an error when there is none
+func (r *mutationResolver) getTrack(ctx context.Context, id string) (*domain.Track, error) {
+	track, err := r.TrackRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get track: %w", err)
+	}
+	if track == nil {
+		return nil, fmt.Errorf("track not found: %s", id)
+	}
+	return track, nil
+}
+
+// invalidTrackError describes the fields of a track input that failed
+// validation
+func invalidTrackError(errs []domain.ValidationError) error {
+	fields := make([]string, len(errs))
+	for i, e := range errs {
+		fields[i] = e.Field + ": " + e.Message
+	}
+	return fmt.Errorf("invalid track: %s", strings.Join(fields, "; "))
+}
+
 // Helper functions for handling optional values
 func stringValue(s *string) string {
 	if s == nil {
@@ -85,6 +112,18 @@ func (r *mutationResolver) UpdateTrack(ctx context.Context, input domain.UpdateT
 	if err != nil {
 		return nil, fmt.Errorf("failed to get track: %w", err)
 	}
+	if track == nil {
+		return nil, fmt.Errorf("track not found: %s", input.ID)
+	}
+
+	// Reject edits based on a stale version before applying them
+	if track.Version != input.Version {
+		submitted := *track
+		submitted.Version = input.Version
+		return nil, domain.NewVersionConflictError(track, &submitted)
+	}
+
+	before := track.Clone()
 
 	// Update basic metadata fields if provided
 	if input.Title != nil {
@@ -103,16 +142,16 @@ func (r *mutationResolver) UpdateTrack(ctx context.Context, input domain.UpdateT
 		track.Metadata.Year = *input.Year
 	}
 	if input.Label != nil {
-		track.Metadata.Additional.CustomFields["label"] = *input.Label
+		track.SetLabel(*input.Label)
 	}
 	if input.Territory != nil {
-		track.Metadata.Additional.CustomFields["territory"] = *input.Territory
+		track.SetTerritory(*input.Territory)
 	}
 	if input.ISRC != nil {
 		track.Metadata.ISRC = *input.ISRC
 	}
 	if input.ISWC != nil {
-		track.Metadata.Additional.CustomFields["iswc"] = *input.ISWC
+		track.SetISWC(*input.ISWC)
 	}
 
 	// Update additional metadata if provided
@@ -135,6 +174,9 @@ func (r *mutationResolver) UpdateTrack(ctx context.Context, input domain.UpdateT
 			track.Metadata.Additional.CustomTags = tags
 		}
 		if input.Metadata.CustomFields != nil {
+			if track.Metadata.Additional.CustomFields == nil {
+				track.Metadata.Additional.CustomFields = make(map[string]string)
+			}
 			for k, v := range input.Metadata.CustomFields {
 				track.Metadata.Additional.CustomFields[k] = v
 			}
@@ -143,6 +185,12 @@ func (r *mutationResolver) UpdateTrack(ctx context.Context, input domain.UpdateT
 
 	track.UpdatedAt = time.Now()
 
+	actor := ""
+	if user, ok := domain.UserFromContext(ctx); ok {
+		actor = user.ID
+	}
+	track.RecordProvenance(domain.DiffTracks(before, track), domain.ProvenanceManual, actor)
+
 	// Update track in database
 	if err := r.TrackRepo.Update(ctx, track); err != nil {
 		return nil, fmt.Errorf("failed to update track: %w", err)
@@ -153,13 +201,16 @@ func (r *mutationResolver) UpdateTrack(ctx context.Context, input domain.UpdateT
 
 func (r *mutationResolver) DeleteTrack(ctx context.Context, id string) (bool, error) {
 	// Get track to check if it exists
-	track, err := r.TrackRepo.GetByID(ctx, id)
+	track, err := r.getTrack(ctx, id)
 	if err != nil {
-		return false, fmt.Errorf("failed to get track: %w", err)
+		return false, err
 	}
 
 	// Delete audio file if exists
 	if track.StoragePath != "" {
+		if r.StorageService == nil {
+			return false, errStorageDisabled
+		}
 		if err := r.StorageService.Delete(ctx, track.StoragePath); err != nil {
 			return false, fmt.Errorf("failed to delete audio file: %w", err)
 		}
@@ -174,15 +225,18 @@ func (r *mutationResolver) DeleteTrack(ctx context.Context, id string) (bool, er
 }
 
 func (r *mutationResolver) BatchProcessTracks(ctx context.Context, ids []string) (*domain.BatchResult, error) {
+	if r.AIService == nil {
+		return nil, errAIDisabled
+	}
 	result := &domain.BatchResult{}
 
 	for _, id := range ids {
-		track, err := r.TrackRepo.GetByID(ctx, id)
+		track, err := r.getTrack(ctx, id)
 		if err != nil {
 			result.FailureCount++
 			result.Errors = append(result.Errors, &domain.BatchError{
 				TrackID: id,
-				Message: fmt.Sprintf("failed to get track: %v", err),
+				Message: err.Error(),
 				Code:    "NOT_FOUND",
 			})
 			continue
@@ -205,10 +259,12 @@ func (r *mutationResolver) BatchProcessTracks(ctx context.Context, ids []string)
 }
 
 func (r *mutationResolver) EnrichTrackMetadata(ctx context.Context, id string) (*domain.Track, error) {
-	// Get track
-	track, err := r.TrackRepo.GetByID(ctx, id)
+	if r.AIService == nil {
+		return nil, errAIDisabled
+	}
+	track, err := r.getTrack(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get track: %w", err)
+		return nil, err
 	}
 
 	// Enrich metadata
@@ -225,15 +281,17 @@ func (r *mutationResolver) EnrichTrackMetadata(ctx context.Context, id string) (
 }
 
 func (r *mutationResolver) ValidateTrackMetadata(ctx context.Context, id string) (*domain.BatchResult, error) {
+	if r.AIService == nil {
+		return nil, errAIDisabled
+	}
 	result := &domain.BatchResult{}
 
-	// Get track
-	track, err := r.TrackRepo.GetByID(ctx, id)
+	track, err := r.getTrack(ctx, id)
 	if err != nil {
 		result.FailureCount = 1
 		result.Errors = append(result.Errors, &domain.BatchError{
 			TrackID: id,
-			Message: fmt.Sprintf("failed to get track: %v", err),
+			Message: err.Error(),
 			Code:    "NOT_FOUND",
 		})
 		return result, nil
@@ -268,9 +326,9 @@ func (r *mutationResolver) ValidateTrackMetadata(ctx context.Context, id string)
 func (r *mutationResolver) ExportToDDEX(ctx context.Context, ids []string) (string, error) {
 	var tracks []*domain.Track
 	for _, id := range ids {
-		track, err := r.TrackRepo.GetByID(ctx, id)
+		track, err := r.getTrack(ctx, id)
 		if err != nil {
-			return "", fmt.Errorf("failed to get track %s: %w", id, err)
+			return "", err
 		}
 		tracks = append(tracks, track)
 	}
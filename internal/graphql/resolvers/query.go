@@ -5,8 +5,12 @@ import (
 	"encoding/base64"
 	"fmt"
 	"metadatatool/internal/pkg/domain"
+	"sort"
 )
 
+// defaultPageSize is the number of tracks in a page when first is not given
+const defaultPageSize = 10
+
 func (r *queryResolver) Track(ctx context.Context, id string) (*domain.Track, error) {
 	track, err := r.TrackRepo.GetByID(ctx, id)
 	if err != nil {
@@ -16,68 +20,44 @@ func (r *queryResolver) Track(ctx context.Context, id string) (*domain.Track, er
 }
 
 func (r *queryResolver) Tracks(ctx context.Context, first *int, after *string, filter *domain.TrackFilter, orderBy *string) (*domain.TrackConnection, error) {
-	// Default values
-	limit := 10
-	if first != nil {
-		limit = *first
-	}
-
-	// Convert cursor to offset
-	offset := 0
-	if after != nil {
-		// TODO: Implement cursor-based pagination
-		// For now, using simple offset-based pagination
-		offset = 0
-	}
-
-	// Build query from filter
 	query := make(map[string]interface{})
-	if filter != nil {
-		if filter.Title != nil {
-			query["title"] = *filter.Title
-		}
-		if filter.Artist != nil {
-			query["artist"] = *filter.Artist
-		}
-		// ... add other filter fields
+	if conditions := filterConditions(filter); len(conditions) > 0 {
+		query[domain.SearchFilterKey] = &domain.SearchFilter{And: conditions}
 	}
 
-	// Get tracks
 	tracks, err := r.TrackRepo.SearchByMetadata(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search tracks: %w", err)
 	}
 
-	// Build connection
-	edges := make([]*domain.TrackEdge, 0, len(tracks))
-	for _, track := range tracks {
-		edges = append(edges, &domain.TrackEdge{
-			Node:   track,
-			Cursor: encodeCursor(track.ID),
-		})
+	// Creation times are not searchable, so they are filtered here
+	if filter != nil && (filter.CreatedFrom != nil || filter.CreatedTo != nil) {
+		created := tracks[:0]
+		for _, track := range tracks {
+			if filter.CreatedFrom != nil && track.CreatedAt.Before(*filter.CreatedFrom) {
+				continue
+			}
+			if filter.CreatedTo != nil && track.CreatedAt.After(*filter.CreatedTo) {
+				continue
+			}
+			created = append(created, track)
+		}
+		tracks = created
 	}
 
-	return &domain.TrackConnection{
-		Edges: edges,
-		PageInfo: &domain.PageInfo{
-			HasNextPage:     len(tracks) == limit,
-			HasPreviousPage: offset > 0,
-			StartCursor:     encodeCursor(tracks[0].ID),
-			EndCursor:       encodeCursor(tracks[len(tracks)-1].ID),
-		},
-		TotalCount: len(tracks),
-	}, nil
+	return trackConnection(tracks, first, after)
 }
 
 func (r *queryResolver) SearchTracks(ctx context.Context, query string) ([]*domain.Track, error) {
-	// Implement full-text search
-	searchQuery := map[string]interface{}{
-		"$text": map[string]interface{}{
-			"$search": query,
-		},
+	// Tracks whose title, artist or album contain the query
+	var conditions []*domain.SearchFilter
+	for _, field := range []string{"title", "artist", "album"} {
+		conditions = append(conditions, &domain.SearchFilter{Field: field, Op: domain.SearchContains, Value: query})
 	}
 
-	tracks, err := r.TrackRepo.SearchByMetadata(ctx, searchQuery)
+	tracks, err := r.TrackRepo.SearchByMetadata(ctx, map[string]interface{}{
+		domain.SearchFilterKey: &domain.SearchFilter{Or: conditions},
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to search tracks: %w", err)
 	}
@@ -85,39 +65,112 @@ func (r *queryResolver) SearchTracks(ctx context.Context, query string) ([]*doma
 }
 
 func (r *queryResolver) TracksNeedingReview(ctx context.Context, first *int, after *string) (*domain.TrackConnection, error) {
-	// Default values
-	limit := 10
+	tracks, err := r.TrackRepo.SearchByMetadata(ctx, map[string]interface{}{
+		domain.SearchFilterKey: needsReviewCondition(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tracks needing review: %w", err)
+	}
+
+	return trackConnection(tracks, first, after)
+}
+
+// filterConditions converts a track filter to search conditions
+func filterConditions(filter *domain.TrackFilter) []*domain.SearchFilter {
+	if filter == nil {
+		return nil
+	}
+
+	var conditions []*domain.SearchFilter
+	for _, field := range []struct {
+		name  string
+		value *string
+	}{
+		{"title", filter.Title},
+		{"artist", filter.Artist},
+		{"album", filter.Album},
+		{"genre", filter.Genre},
+		{"label", filter.Label},
+		{"isrc", filter.ISRC},
+		{"iswc", filter.ISWC},
+	} {
+		if field.value != nil {
+			conditions = append(conditions, &domain.SearchFilter{Field: field.name, Op: domain.SearchEq, Value: *field.value})
+		}
+	}
+	if filter.NeedsReview != nil {
+		conditions = append(conditions, needsReviewCondition(*filter.NeedsReview))
+	}
+	return conditions
+}
+
+// needsReviewCondition matches tracks that are, or are not, waiting for
+// review
+func needsReviewCondition(needsReview bool) *domain.SearchFilter {
+	condition := &domain.SearchFilter{Field: "status", Op: domain.SearchEq, Value: string(domain.TrackStatusNeedsReview)}
+	if !needsReview {
+		return &domain.SearchFilter{Not: condition}
+	}
+	return condition
+}
+
+// trackConnection returns the page of tracks following the after cursor,
+// oldest first
+func trackConnection(tracks []*domain.Track, first *int, after *string) (*domain.TrackConnection, error) {
+	limit := defaultPageSize
 	if first != nil {
+		if *first < 0 {
+			return nil, fmt.Errorf("first cannot be negative")
+		}
 		limit = *first
 	}
 
-	// Query tracks needing review
-	query := map[string]interface{}{
-		"needs_review": true,
-	}
+	sort.SliceStable(tracks, func(i, j int) bool {
+		if !tracks[i].CreatedAt.Equal(tracks[j].CreatedAt) {
+			return tracks[i].CreatedAt.Before(tracks[j].CreatedAt)
+		}
+		return tracks[i].ID < tracks[j].ID
+	})
 
-	tracks, err := r.TrackRepo.SearchByMetadata(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get tracks needing review: %w", err)
+	start := 0
+	if after != nil {
+		id, err := decodeCursor(*after)
+		if err != nil {
+			return nil, err
+		}
+		start = -1
+		for i, track := range tracks {
+			if track.ID == id {
+				start = i + 1
+				break
+			}
+		}
+		if start < 0 {
+			return nil, fmt.Errorf("invalid cursor: no track %s", id)
+		}
 	}
+	end := min(start+limit, len(tracks))
 
-	// Build connection
-	edges := make([]*domain.TrackEdge, 0, len(tracks))
-	for _, track := range tracks {
+	edges := make([]*domain.TrackEdge, 0, end-start)
+	for _, track := range tracks[start:end] {
 		edges = append(edges, &domain.TrackEdge{
 			Node:   track,
 			Cursor: encodeCursor(track.ID),
 		})
 	}
 
+	pageInfo := &domain.PageInfo{
+		HasNextPage:     end < len(tracks),
+		HasPreviousPage: start > 0,
+	}
+	if len(edges) > 0 {
+		pageInfo.StartCursor = edges[0].Cursor
+		pageInfo.EndCursor = edges[len(edges)-1].Cursor
+	}
+
 	return &domain.TrackConnection{
-		Edges: edges,
-		PageInfo: &domain.PageInfo{
-			HasNextPage:     len(tracks) == limit,
-			HasPreviousPage: false, // First page
-			StartCursor:     encodeCursor(tracks[0].ID),
-			EndCursor:       encodeCursor(tracks[len(tracks)-1].ID),
-		},
+		Edges:      edges,
+		PageInfo:   pageInfo,
 		TotalCount: len(tracks),
 	}, nil
 }
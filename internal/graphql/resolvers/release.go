@@ -0,0 +1,60 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/graphql/dataloader"
+	"metadatatool/internal/graphql/generated"
+	"metadatatool/internal/pkg/domain"
+)
+
+func (r *queryResolver) Release(ctx context.Context, id string) (*domain.CatalogRelease, error) {
+	tracks, err := loadersFor(ctx, r.Resolver).TracksByRelease.Load(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release: %w", err)
+	}
+	if len(tracks) == 0 {
+		return nil, nil
+	}
+	return &domain.CatalogRelease{ID: id}, nil
+}
+
+func (r *queryResolver) Releases(ctx context.Context, ids []string) ([]*domain.CatalogRelease, error) {
+	trackLists, err := loadersFor(ctx, r.Resolver).TracksByRelease.LoadMany(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get releases: %w", err)
+	}
+
+	releases := make([]*domain.CatalogRelease, 0, len(ids))
+	for i, tracks := range trackLists {
+		if len(tracks) > 0 {
+			releases = append(releases, &domain.CatalogRelease{ID: ids[i]})
+		}
+	}
+	return releases, nil
+}
+
+func (r *releaseResolver) Tracks(ctx context.Context, obj *domain.CatalogRelease) ([]*domain.Track, error) {
+	tracks, err := loadersFor(ctx, r.Resolver).TracksByRelease.Load(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release tracks: %w", err)
+	}
+	return tracks, nil
+}
+
+func (r *releaseResolver) TrackCount(ctx context.Context, obj *domain.CatalogRelease) (int, error) {
+	tracks, err := r.Tracks(ctx, obj)
+	if err != nil {
+		return 0, err
+	}
+	return len(tracks), nil
+}
+
+// loadersFor returns the request's dataloaders, creating an uncached set when
+// the resolver runs outside the dataloader middleware
+func loadersFor(ctx context.Context, r *generated.Resolver) *dataloader.Loaders {
+	if loaders, ok := dataloader.For(ctx); ok {
+		return loaders
+	}
+	return dataloader.NewLoaders(r.TrackRepo, r.UserRepo)
+}
@@ -35,3 +35,8 @@ func (r *Resolver) Subscription() generated.SubscriptionResolver {
 func (r *Resolver) Track() generated.TrackResolver {
 	return &trackResolver{r.Resolver}
 }
+
+// Release returns the release resolver
+func (r *Resolver) Release() generated.ReleaseResolver {
+	return &releaseResolver{r.Resolver}
+}
@@ -2,6 +2,7 @@ package resolvers
 
 import (
 	"context"
+	"fmt"
 	"metadatatool/internal/pkg/domain"
 )
 
@@ -42,3 +43,30 @@ func (r *trackResolver) Metadata(ctx context.Context, obj *domain.Track) (*domai
 		CustomFields: obj.Metadata.Additional.CustomFields,
 	}, nil
 }
+
+func (r *trackResolver) Release(ctx context.Context, obj *domain.Track) (*domain.CatalogRelease, error) {
+	if obj.ReleaseID == "" {
+		return nil, nil
+	}
+	return &domain.CatalogRelease{ID: obj.ReleaseID}, nil
+}
+
+func (r *trackResolver) Contributors(ctx context.Context, obj *domain.Track) ([]*domain.User, error) {
+	if len(obj.ArtistIDs) == 0 {
+		return nil, nil
+	}
+
+	users, err := loadersFor(ctx, r.Resolver).UserByID.LoadMany(ctx, obj.ArtistIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contributors: %w", err)
+	}
+
+	// Contributors that no longer exist are omitted
+	contributors := make([]*domain.User, 0, len(users))
+	for _, user := range users {
+		if user != nil {
+			contributors = append(contributors, user)
+		}
+	}
+	return contributors, nil
+}
@@ -21,3 +21,8 @@ type subscriptionResolver struct {
 type trackResolver struct {
 	*generated.Resolver
 }
+
+// releaseResolver implements the release resolver
+type releaseResolver struct {
+	*generated.Resolver
+}
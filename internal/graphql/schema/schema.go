@@ -0,0 +1,9 @@
+// Package schema holds the definition of the GraphQL API
+package schema
+
+import _ "embed"
+
+// SDL is the GraphQL schema definition the API serves
+//
+//go:embed schema.graphql
+var SDL string
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AIBatchHandler handles HTTP requests for enriching tracks through the AI
+// providers' batch APIs
+type AIBatchHandler struct {
+	batches *usecase.AIBatchUseCase
+}
+
+// NewAIBatchHandler creates a new AI batch handler
+func NewAIBatchHandler(batches *usecase.AIBatchUseCase) *AIBatchHandler {
+	return &AIBatchHandler{batches: batches}
+}
+
+// SubmitAIBatchRequest lists the tracks to enrich in a batch
+type SubmitAIBatchRequest struct {
+	// Provider defaults to openai, the only provider with a batch API
+	Provider domain.AIProvider `json:"provider,omitempty"`
+	TrackIDs []string          `json:"track_ids" binding:"required"`
+}
+
+// AIBatchesResponse lists AI batches
+type AIBatchesResponse struct {
+	Batches []*domain.AIBatch `json:"batches"`
+}
+
+// SubmitBatch submits tracks for batch enrichment
+// @Summary Submit AI batch
+// @Description Enrich tracks through the provider's batch API, at half the cost of enriching them one by one. The provider takes up to 24 hours; the results are then saved to the tracks, keeping the fields a person has edited. Poll the batch to follow it.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body SubmitAIBatchRequest true "Tracks to enrich"
+// @Success 202 {object} domain.AIBatch
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/ai/batches [post]
+func (h *AIBatchHandler) SubmitBatch(c *gin.Context) {
+	var req SubmitAIBatchRequest
+	if err := bindJSON(c, &req); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	batch, err := h.batches.Submit(c.Request.Context(), req.Provider, req.TrackIDs, c.GetString("user_id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to submit AI batch"))
+		return
+	}
+	c.JSON(http.StatusAccepted, batch)
+}
+
+// ListBatches lists the latest AI batches
+// @Summary List AI batches
+// @Description List the latest 100 AI batches, newest first
+// @Tags admin
+// @Produce json
+// @Success 200 {object} AIBatchesResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/ai/batches [get]
+func (h *AIBatchHandler) ListBatches(c *gin.Context) {
+	batches, err := h.batches.List(c.Request.Context())
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to list AI batches"))
+		return
+	}
+	c.JSON(http.StatusOK, AIBatchesResponse{Batches: batches})
+}
+
+// GetBatch returns an AI batch
+// @Summary Get AI batch
+// @Description Get an AI batch. Pending batches are checked with the provider periodically; once done, enriched and failed count the tracks saved and the tracks without a result.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Batch ID"
+// @Success 200 {object} domain.AIBatch
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/ai/batches/{id} [get]
+func (h *AIBatchHandler) GetBatch(c *gin.Context) {
+	batch, err := h.batches.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to get AI batch"))
+		return
+	}
+	c.JSON(http.StatusOK, batch)
+}
+
+// CancelBatch cancels a pending AI batch
+// @Summary Cancel AI batch
+// @Description Ask the provider to stop a pending batch. The batch stays pending until the provider has stopped; the results of the requests that finished are saved then.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Batch ID"
+// @Success 202 {object} domain.AIBatch
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/ai/batches/{id} [delete]
+func (h *AIBatchHandler) CancelBatch(c *gin.Context) {
+	batch, err := h.batches.Cancel(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to cancel AI batch"))
+		return
+	}
+	c.JSON(http.StatusAccepted, batch)
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AIModelHandler handles HTTP requests for the model versions the catalog
+// was enriched with and re-enriching tracks enriched with outdated ones
+type AIModelHandler struct {
+	migration *usecase.AIModelMigrationUseCase
+}
+
+// NewAIModelHandler creates a new AI model handler
+func NewAIModelHandler(migration *usecase.AIModelMigrationUseCase) *AIModelHandler {
+	return &AIModelHandler{migration: migration}
+}
+
+// GetModelCoverage reports the catalog's coverage per model version
+// @Summary Get AI model coverage
+// @Description Count the tracks by the model, model version and prompt version they were last enriched with. Versions no provider enriches with now are not current; their tracks are counted as outdated and are enriched again by a re-enrichment.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.AIModelCoverageReport
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/ai/model-coverage [get]
+func (h *AIModelHandler) GetModelCoverage(c *gin.Context) {
+	report, err := h.migration.Coverage(c.Request.Context())
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to report AI model coverage"))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// StartReenrichment starts re-enriching outdated tracks
+// @Summary Start re-enrichment
+// @Description Enrich again, in the background, every track last enriched with an outdated model version, and save the results. Fields a person has edited are kept as for any enrichment. Poll the run to follow its progress.
+// @Tags admin
+// @Produce json
+// @Success 202 {object} domain.ReenrichmentRun
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/ai/reenrichment [post]
+func (h *AIModelHandler) StartReenrichment(c *gin.Context) {
+	run, err := h.migration.StartReenrichment(c.Request.Context())
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to start re-enrichment"))
+		return
+	}
+	c.JSON(http.StatusAccepted, run)
+}
+
+// GetReenrichment returns the latest re-enrichment
+// @Summary Get re-enrichment
+// @Description Get the progress of the latest re-enrichment
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.ReenrichmentRun
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/ai/reenrichment [get]
+func (h *AIModelHandler) GetReenrichment(c *gin.Context) {
+	run, err := h.migration.Reenrichment()
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to get re-enrichment"))
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+// CancelReenrichment stops the re-enrichment in progress
+// @Summary Cancel re-enrichment
+// @Description Stop the re-enrichment in progress once the track being enriched is saved. Tracks already enriched keep their new metadata.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.ReenrichmentRun
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/ai/reenrichment [delete]
+func (h *AIModelHandler) CancelReenrichment(c *gin.Context) {
+	run, err := h.migration.CancelReenrichment()
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to cancel re-enrichment"))
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
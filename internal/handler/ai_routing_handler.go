@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AIRoutingHandler handles HTTP requests for the health of the AI providers
+// and the routing of traffic between them
+type AIRoutingHandler struct {
+	router        domain.AIRouter
+	runtimeConfig *usecase.RuntimeConfigUseCase
+}
+
+// NewAIRoutingHandler creates a new AI routing handler. With runtimeConfig,
+// a forced provider is saved in the runtime settings and applies to every
+// instance; without it, only to this one.
+func NewAIRoutingHandler(router domain.AIRouter, runtimeConfig *usecase.RuntimeConfigUseCase) *AIRoutingHandler {
+	return &AIRoutingHandler{router: router, runtimeConfig: runtimeConfig}
+}
+
+// ForceAIProviderRequest names the provider to send all AI traffic to
+type ForceAIProviderRequest struct {
+	Provider domain.AIProvider `json:"provider" binding:"required"`
+}
+
+// GetRouting returns the AI routing in effect
+// @Summary Get AI routing
+// @Description Get the routing policy, the forced provider if any, the order enrichments try the providers in and the health probes of each provider
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.AIRouting
+// @Router /admin/ai/routing [get]
+func (h *AIRoutingHandler) GetRouting(c *gin.Context) {
+	c.JSON(http.StatusOK, h.router.Routing())
+}
+
+// CheckHealth probes the AI providers now
+// @Summary Check AI provider health
+// @Description Probe every AI provider now rather than at the next interval, and return the routing that results
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.AIRouting
+// @Router /admin/ai/routing/health-check [post]
+func (h *AIRoutingHandler) CheckHealth(c *gin.Context) {
+	h.router.CheckHealth(c.Request.Context())
+	c.JSON(http.StatusOK, h.router.Routing())
+}
+
+// ForceProvider sends all AI traffic to one provider
+// @Summary Force AI provider
+// @Description Send all AI traffic to one provider during an incident, whatever the health probes say and without falling back to the other provider. Applies to every instance when runtime settings are shared.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body ForceAIProviderRequest true "Provider to force"
+// @Success 200 {object} domain.AIRouting
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/ai/routing/forced [put]
+func (h *AIRoutingHandler) ForceProvider(c *gin.Context) {
+	var req ForceAIProviderRequest
+	if err := bindJSON(c, &req); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	h.force(c, req.Provider)
+}
+
+// ClearForcedProvider routes AI traffic by provider health again
+// @Summary Clear forced AI provider
+// @Description Stop forcing AI traffic to one provider, routing it by the routing policy and the provider health again
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.AIRouting
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/ai/routing/forced [delete]
+func (h *AIRoutingHandler) ClearForcedProvider(c *gin.Context) {
+	h.force(c, "")
+}
+
+// force forces provider through the runtime settings when they are shared,
+// and on the router directly otherwise
+func (h *AIRoutingHandler) force(c *gin.Context, provider domain.AIProvider) {
+	var err error
+	if h.runtimeConfig != nil {
+		_, err = h.runtimeConfig.Update(c.Request.Context(), &domain.RuntimeSettingsPatch{AIForcedProvider: &provider}, c.GetString("user_id"))
+	} else {
+		err = h.router.ForceProvider(provider)
+	}
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to force AI provider"))
+		return
+	}
+	c.JSON(http.StatusOK, h.router.Routing())
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/pkg/errortracking"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnalysisHandler handles HTTP requests for the stored audio analyses of
+// tracks
+type AnalysisHandler struct {
+	analyses     *usecase.AnalysisUseCase
+	errorTracker *errortracking.ErrorTracker
+}
+
+// NewAnalysisHandler creates a new analysis handler
+func NewAnalysisHandler(analyses *usecase.AnalysisUseCase, errorTracker *errortracking.ErrorTracker) *AnalysisHandler {
+	return &AnalysisHandler{
+		analyses:     analyses,
+		errorTracker: errorTracker,
+	}
+}
+
+// GetTrackAnalysis returns the audio analysis of a track
+// @Summary Get track audio analysis
+// @Description Get the stored audio analysis of a track: tempo, beat positions, key, segments, energy, danceability and the other measured features. The latest analysis is returned unless version names an analyzer version. A track without an analysis is answered with 404.
+// @Tags tracks
+// @Produce json
+// @Param id path string true "Track ID"
+// @Param version query string false "Analyzer version, such as basic_analyzer_v1"
+// @Success 200 {object} domain.TrackAnalysis
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/{id}/analysis [get]
+func (h *AnalysisHandler) GetTrackAnalysis(c *gin.Context) {
+	analysis, err := h.analyses.Get(c.Request.Context(), c.Param("id"), c.Query("version"))
+	if err != nil {
+		h.handleError(c, apperrors.FromError(err, "failed to get analysis"))
+		return
+	}
+	c.JSON(http.StatusOK, analysis)
+}
+
+func (h *AnalysisHandler) handleError(c *gin.Context, err *apperrors.AppError) {
+	if h.errorTracker != nil {
+		h.errorTracker.CaptureErrorContext(c.Request.Context(), err, map[string]string{
+			"handler": "analysis",
+			"method":  c.Request.Method,
+			"path":    c.Request.URL.Path,
+		})
+	}
+
+	apperrors.Respond(c, err)
+}
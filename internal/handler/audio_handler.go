@@ -3,6 +3,7 @@ package handler
 import (
 	"fmt"
 	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
 	"path/filepath"
 	"time"
 
@@ -44,7 +45,7 @@ func (h *AudioHandler) UploadAudio(c *gin.Context) {
 	file, err := c.FormFile("file")
 	if err != nil {
 		metrics.AudioOpErrors.WithLabelValues("upload", "form_error").Inc()
-		c.JSON(400, gin.H{"error": "No file provided"})
+		apperrors.Respond(c, apperrors.NewValidationError("no file provided", ""))
 		return
 	}
 
@@ -55,7 +56,7 @@ func (h *AudioHandler) UploadAudio(c *gin.Context) {
 	src, err := file.Open()
 	if err != nil {
 		metrics.AudioOpErrors.WithLabelValues("upload", "open_error").Inc()
-		c.JSON(500, gin.H{"error": "Failed to open file"})
+		apperrors.Respond(c, apperrors.NewInternalError("failed to open file", err))
 		return
 	}
 	defer src.Close()
@@ -72,7 +73,7 @@ func (h *AudioHandler) UploadAudio(c *gin.Context) {
 	// Upload to storage
 	if err := h.storage.Upload(c, storageFile); err != nil {
 		metrics.AudioOpErrors.WithLabelValues("upload", "storage_error").Inc()
-		c.JSON(500, gin.H{"error": "Failed to upload file"})
+		apperrors.Respond(c, apperrors.NewInternalError("failed to upload file", err))
 		return
 	}
 
@@ -97,7 +98,7 @@ func (h *AudioHandler) UploadAudio(c *gin.Context) {
 
 	if err := h.trackRepo.Create(c, track); err != nil {
 		metrics.AudioOpErrors.WithLabelValues("upload", "db_error").Inc()
-		c.JSON(500, gin.H{"error": "Failed to create track record"})
+		apperrors.Respond(c, apperrors.NewInternalError("failed to create track record", err))
 		return
 	}
 
@@ -128,14 +129,14 @@ func (h *AudioHandler) GetAudioURL(c *gin.Context) {
 	track, err := h.trackRepo.GetByID(c, id)
 	if err != nil {
 		metrics.AudioOpErrors.WithLabelValues("get_url", "not_found").Inc()
-		c.JSON(404, gin.H{"error": "Track not found"})
+		apperrors.Respond(c, apperrors.NewNotFoundError("track not found"))
 		return
 	}
 
 	url, err := h.storage.GetURL(c, track.StoragePath)
 	if err != nil {
 		metrics.AudioOpErrors.WithLabelValues("get_url", "storage_error").Inc()
-		c.JSON(500, gin.H{"error": "Failed to generate URL"})
+		apperrors.Respond(c, apperrors.NewInternalError("failed to generate URL", err))
 		return
 	}
 
@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -9,16 +12,19 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
-	"metadatatool/internal/domain"
-	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
 	"metadatatool/internal/usecase"
 )
 
 // AuthHandler handles HTTP requests related to authentication
 type AuthHandler struct {
-	authUseCase  usecase.AuthUseCaseInterface
-	userUseCase  *usecase.UserUseCase
-	sessionStore domain.SessionStore
+	authUseCase   usecase.AuthUseCaseInterface
+	userUseCase   *usecase.UserUseCase
+	sessionStore  domain.SessionStore
+	deviceTracker *usecase.SessionDeviceTracker
+	// trustedProxies may set the country headers of the requests they forward
+	trustedProxies []*net.IPNet
 }
 
 // NewAuthHandler creates a new auth handler
@@ -30,27 +36,91 @@ func NewAuthHandler(authUseCase usecase.AuthUseCaseInterface, userUseCase *useca
 	}
 }
 
+// SetDeviceTracker enables new-device and new-country login alerts
+func (h *AuthHandler) SetDeviceTracker(tracker *usecase.SessionDeviceTracker) {
+	h.deviceTracker = tracker
+}
+
+// SetTrustedProxies sets the load balancers and CDNs, as IP addresses or
+// CIDR ranges, whose country headers are believed. Headers on requests from
+// anyone else are ignored, since clients can send them too.
+func (h *AuthHandler) SetTrustedProxies(proxies []string) error {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		networks = append(networks, network)
+	}
+	h.trustedProxies = networks
+	return nil
+}
+
+// countryHeaders are set by the load balancer or CDN in front of the API
+var countryHeaders = []string{"CF-IPCountry", "X-Country-Code", "X-AppEngine-Country", "CloudFront-Viewer-Country"}
+
+// clientCountry returns the ISO country code of the client, if a trusted
+// proxy forwarded the request and told it
+func (h *AuthHandler) clientCountry(c *gin.Context) string {
+	if !h.fromTrustedProxy(c) {
+		return ""
+	}
+	for _, header := range countryHeaders {
+		if v := c.GetHeader(header); v != "" && v != "XX" {
+			return v
+		}
+	}
+	return ""
+}
+
+// fromTrustedProxy reports whether the request came straight from a trusted
+// proxy
+func (h *AuthHandler) fromTrustedProxy(c *gin.Context) bool {
+	ip := net.ParseIP(c.RemoteIP())
+	if ip == nil {
+		return false
+	}
+	for _, network := range h.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// startSession labels a new session with its device and stores it. Known
+// device tracking runs in the background so alerts never delay the response.
+func (h *AuthHandler) startSession(c *gin.Context, user *domain.User, session *domain.Session) error {
+	usecase.DescribeSessionDevice(session, c.GetHeader("Accept-Language"), h.clientCountry(c))
+	if err := h.sessionStore.Create(c.Request.Context(), session); err != nil {
+		return err
+	}
+	if h.deviceTracker != nil {
+		ctx := context.WithoutCancel(c.Request.Context())
+		go h.deviceTracker.Track(ctx, user, session)
+	}
+	return nil
+}
+
 // Register handles user registration
 func (h *AuthHandler) Register(c *gin.Context) {
 	var input usecase.RegisterInput
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
-		return
-	}
-
-	// Validate input
-	if input.Email == "" || input.Password == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Email and password are required"})
+	if err := bindJSON(c, &input); err != nil {
+		apperrors.Respond(c, err)
 		return
 	}
 
 	user, err := h.authUseCase.Register(c.Request.Context(), input)
 	if err != nil {
-		if strings.Contains(err.Error(), "already registered") {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering user"})
+		apperrors.Respond(c, apperrors.FromError(err, "error registering user"))
 		return
 	}
 
@@ -64,8 +134,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		Password string `json:"password" binding:"required"`
 	}
 
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+	if err := bindJSON(c, &input); err != nil {
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -74,11 +144,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		Password: input.Password,
 	})
 	if err != nil {
-		if errors.Is(err, domain.ErrInvalidCredentials) {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		apperrors.Respond(c, apperrors.FromError(err, "failed to log in"))
 		return
 	}
 
@@ -95,8 +161,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		LastSeenAt:  time.Now(),
 	}
 
-	if err := h.sessionStore.Create(c.Request.Context(), session); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create session"})
+	if err := h.startSession(c, loginOutput.User, session); err != nil {
+		apperrors.Respond(c, apperrors.NewInternalError("failed to create session", err))
 		return
 	}
 
@@ -124,13 +190,13 @@ const (
 func (h *AuthHandler) Logout(c *gin.Context) {
 	sessionID, exists := c.Get("session_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "No active session"})
+		apperrors.Respond(c, apperrors.NewUnauthorizedError("no active session").WithCode(apperrors.CodeSessionRequired))
 		return
 	}
 
 	if err := h.authUseCase.Logout(c.Request.Context(), sessionID.(string)); err != nil {
 		c.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error logging out"})
+		apperrors.Respond(c, apperrors.NewInternalError("error logging out", err))
 		return
 	}
 
@@ -141,17 +207,13 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "No active session"})
+		apperrors.Respond(c, apperrors.NewUnauthorizedError("no active session").WithCode(apperrors.CodeSessionRequired))
 		return
 	}
 
 	user, err := h.userUseCase.GetUser(c.Request.Context(), userID.(string))
 	if err != nil {
-		if errors.Is(err, domain.ErrUserNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error getting user"})
+		apperrors.Respond(c, apperrors.FromError(err, "error getting user"))
 		return
 	}
 
@@ -164,29 +226,18 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		RefreshToken string `json:"refresh_token" binding:"required"`
 	}
 
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+	if err := bindJSON(c, &input); err != nil {
+		apperrors.Respond(c, err)
 		return
 	}
 
 	newAccessToken, newRefreshToken, user, err := h.authUseCase.RefreshToken(c.Request.Context(), input.RefreshToken)
 	if err != nil {
-		var statusCode int
-		var message string
-
-		switch {
-		case errors.Is(err, domain.ErrInvalidToken):
-			statusCode = http.StatusUnauthorized
-			message = "invalid refresh token"
-		case errors.Is(err, domain.ErrUserNotFound):
-			statusCode = http.StatusUnauthorized
-			message = "invalid refresh token"
-		default:
-			statusCode = http.StatusInternalServerError
-			message = "internal error"
+		// A refresh token of a deleted user is as invalid as a forged one
+		if errors.Is(err, domain.ErrUserNotFound) {
+			err = domain.ErrInvalidToken
 		}
-
-		c.JSON(statusCode, gin.H{"error": message})
+		apperrors.Respond(c, apperrors.FromError(err, "failed to refresh token"))
 		return
 	}
 
@@ -203,8 +254,8 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		LastSeenAt:  time.Now(),
 	}
 
-	if err := h.sessionStore.Create(c.Request.Context(), internalSession); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create session"})
+	if err := h.startSession(c, user, internalSession); err != nil {
+		apperrors.Respond(c, apperrors.NewInternalError("failed to create session", err))
 		return
 	}
 
@@ -222,9 +273,9 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 }
 
 // hasPermission checks if a session has a specific permission
-func hasPermission(s *domain.Session, permission pkgdomain.Permission) bool {
+func hasPermission(s *domain.Session, permission domain.Permission) bool {
 	for _, p := range s.Permissions {
-		if pkgdomain.Permission(p) == permission {
+		if domain.Permission(p) == permission {
 			return true
 		}
 	}
@@ -235,33 +286,33 @@ func hasPermission(s *domain.Session, permission pkgdomain.Permission) bool {
 func (h *AuthHandler) GenerateAPIKey(c *gin.Context) {
 	session, exists := c.Get("session")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		apperrors.Respond(c, apperrors.NewUnauthorizedError("unauthorized"))
 		return
 	}
 
 	s, ok := session.(*domain.Session)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid session type"})
+		apperrors.Respond(c, apperrors.NewInternalError("invalid session type", nil))
 		return
 	}
 
 	// Check if user has permission to generate API keys
-	if !hasPermission(s, pkgdomain.PermissionManageAPIKeys) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+	if !hasPermission(s, domain.PermissionManageAPIKeys) {
+		apperrors.Respond(c, apperrors.NewForbiddenError("insufficient permissions"))
 		return
 	}
 
 	// Get user from database
 	user, err := h.userUseCase.GetUser(c.Request.Context(), s.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user"})
+		apperrors.Respond(c, apperrors.NewInternalError("failed to get user", err))
 		return
 	}
 
 	// Generate new API key
 	apiKey, err := h.authUseCase.GenerateAPIKey(c.Request.Context(), user.ID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate API key"})
+		apperrors.Respond(c, apperrors.NewInternalError("failed to generate API key", err))
 		return
 	}
 
@@ -272,64 +323,60 @@ func (h *AuthHandler) GenerateAPIKey(c *gin.Context) {
 func (h *AuthHandler) GetActiveSessions(c *gin.Context) {
 	session, exists := c.Get("session")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "No active session"})
+		apperrors.Respond(c, apperrors.NewUnauthorizedError("no active session").WithCode(apperrors.CodeSessionRequired))
 		return
 	}
 
 	s, ok := session.(*domain.Session)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid session type"})
+		apperrors.Respond(c, apperrors.NewInternalError("invalid session type", nil))
 		return
 	}
 
 	sessions, err := h.sessionStore.GetUserSessions(c.Request.Context(), s.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get sessions"})
+		apperrors.Respond(c, apperrors.NewInternalError("failed to get sessions", err))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions, "current_session_id": s.ID})
 }
 
 // RevokeSession revokes a specific session
 func (h *AuthHandler) RevokeSession(c *gin.Context) {
 	session, exists := c.Get("session")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "No active session"})
+		apperrors.Respond(c, apperrors.NewUnauthorizedError("no active session").WithCode(apperrors.CodeSessionRequired))
 		return
 	}
 
 	s, ok := session.(*domain.Session)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid session type"})
+		apperrors.Respond(c, apperrors.NewInternalError("invalid session type", nil))
 		return
 	}
 
 	sessionID := c.Param("id")
 	if sessionID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Session ID is required"})
+		apperrors.Respond(c, apperrors.NewValidationError("session ID is required", ""))
 		return
 	}
 
 	// Get the session to be revoked
 	targetSession, err := h.sessionStore.Get(c.Request.Context(), sessionID)
 	if err != nil {
-		if errors.Is(err, domain.ErrSessionNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get session"})
+		apperrors.Respond(c, apperrors.FromError(err, "failed to get session"))
 		return
 	}
 
 	// Only allow users to revoke their own sessions
 	if targetSession.UserID != s.UserID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Cannot revoke other users' sessions"})
+		apperrors.Respond(c, apperrors.NewForbiddenError("cannot revoke other users' sessions"))
 		return
 	}
 
 	if err := h.sessionStore.Delete(c.Request.Context(), sessionID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		apperrors.Respond(c, apperrors.NewInternalError("failed to revoke session", err))
 		return
 	}
 
@@ -340,18 +387,18 @@ func (h *AuthHandler) RevokeSession(c *gin.Context) {
 func (h *AuthHandler) RevokeAllSessions(c *gin.Context) {
 	session, exists := c.Get("session")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "No active session"})
+		apperrors.Respond(c, apperrors.NewUnauthorizedError("no active session").WithCode(apperrors.CodeSessionRequired))
 		return
 	}
 
 	s, ok := session.(*domain.Session)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid session type"})
+		apperrors.Respond(c, apperrors.NewInternalError("invalid session type", nil))
 		return
 	}
 
 	if err := h.sessionStore.DeleteUserSessions(c.Request.Context(), s.UserID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		apperrors.Respond(c, apperrors.NewInternalError("failed to revoke sessions", err))
 		return
 	}
 
@@ -381,16 +428,14 @@ func (h *AuthHandler) AuthMiddleware() gin.HandlerFunc {
 
 		// If still no token, return unauthorized
 		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing authorization"})
-			c.Abort()
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("missing authorization"))
 			return
 		}
 
 		// Validate token
 		user, err := h.authUseCase.ValidateToken(c.Request.Context(), token)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("invalid token"))
 			return
 		}
 
@@ -408,14 +453,12 @@ func (h *AuthHandler) RoleMiddleware(role domain.Role) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("user_role")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-			c.Abort()
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("unauthorized"))
 			return
 		}
 
 		if userRole.(domain.Role) != role && userRole.(domain.Role) != domain.RoleAdmin {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
-			c.Abort()
+			apperrors.Respond(c, apperrors.NewForbiddenError("insufficient permissions"))
 			return
 		}
 
@@ -428,13 +471,13 @@ func RequirePermission(permission domain.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		session, exists := c.Get("session")
 		if !exists {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("unauthorized"))
 			return
 		}
 
 		s, ok := session.(*domain.Session)
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "invalid session type"})
+			apperrors.Respond(c, apperrors.NewInternalError("invalid session type", nil))
 			return
 		}
 
@@ -448,7 +491,7 @@ func RequirePermission(permission domain.Permission) gin.HandlerFunc {
 		}
 
 		if !hasPermission {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			apperrors.Respond(c, apperrors.NewForbiddenError("forbidden"))
 			return
 		}
 
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+func init() {
+	validator.UseJSONNames(binding.Validator.Engine())
+}
+
+// bindJSON decodes the request body into obj and checks its binding tags. A
+// body that cannot be bound is returned as a validation error listing the
+// rejected fields.
+func bindJSON(c *gin.Context, obj interface{}) *apperrors.AppError {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		return apperrors.NewFieldValidationError("invalid request body", bindingFieldErrors(err))
+	}
+	return nil
+}
+
+// fieldErrors converts validation errors to the fields of an error response
+func fieldErrors(errs []domain.ValidationError) []apperrors.FieldError {
+	fields := make([]apperrors.FieldError, len(errs))
+	for i, err := range errs {
+		code := err.Code
+		if code == "" {
+			code = apperrors.FieldCodeInvalid
+		}
+		fields[i] = apperrors.FieldError{Field: err.Field, Code: code, Message: err.Message}
+	}
+	return fields
+}
+
+// bindingFieldErrors describes why a request body could not be bound
+// without passing on the decoder's messages
+func bindingFieldErrors(err error) []apperrors.FieldError {
+	if errs, ok := validator.Describe(err); ok {
+		return fieldErrors(errs)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	var timeErr *time.ParseError
+	switch {
+	case errors.As(err, &typeErr):
+		return []apperrors.FieldError{{
+			Field:   typeErr.Field,
+			Code:    apperrors.FieldCodeInvalidType,
+			Message: "must be " + jsonTypeName(typeErr.Type),
+		}}
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return []apperrors.FieldError{{Code: apperrors.FieldCodeInvalidJSON, Message: "request body is not valid JSON"}}
+	case errors.Is(err, io.EOF):
+		return []apperrors.FieldError{{Code: "required", Message: "request body is required"}}
+	case errors.As(err, &timeErr):
+		return []apperrors.FieldError{{Code: apperrors.FieldCodeInvalid, Message: "times must be in RFC 3339 format"}}
+	default:
+		// Custom unmarshalers return messages written for clients
+		return []apperrors.FieldError{{Code: apperrors.FieldCodeInvalid, Message: err.Error()}}
+	}
+}
+
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apperrors "metadatatool/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		body   string
+		fields []apperrors.FieldError
+	}{
+		{"valid body", `{"track_ids":["a"],"format":"csv"}`, nil},
+		{
+			"failed rules",
+			`{"format":"xml"}`,
+			[]apperrors.FieldError{
+				{Field: "track_ids", Code: "required", Message: "is required"},
+				{Field: "format", Code: "oneof", Message: "must be one of json, csv"},
+			},
+		},
+		{
+			"wrong type",
+			`{"track_ids":"a","format":"csv"}`,
+			[]apperrors.FieldError{{Field: "track_ids", Code: apperrors.FieldCodeInvalidType, Message: "must be an array"}},
+		},
+		{
+			"malformed JSON",
+			`{"track_ids":`,
+			[]apperrors.FieldError{{Code: apperrors.FieldCodeInvalidJSON, Message: "request body is not valid JSON"}},
+		},
+		{
+			"empty body",
+			``,
+			[]apperrors.FieldError{{Code: "required", Message: "request body is required"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/tracks/export", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			var req ExportRequest
+			err := bindJSON(c, &req)
+			if tt.fields == nil {
+				assert.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.Equal(t, apperrors.ErrorTypeValidation, err.Type)
+			assert.Equal(t, http.StatusBadRequest, err.StatusCode)
+			assert.Equal(t, tt.fields, err.Fields)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTokenHeader carries the setup token of bootstrap requests
+const SetupTokenHeader = "X-Setup-Token"
+
+// BootstrapHandler provisions new environments
+type BootstrapHandler struct {
+	bootstrap *usecase.BootstrapUseCase
+}
+
+// NewBootstrapHandler creates a new bootstrap handler
+func NewBootstrapHandler(bootstrap *usecase.BootstrapUseCase) *BootstrapHandler {
+	return &BootstrapHandler{bootstrap: bootstrap}
+}
+
+// BootstrapRequest describes the initial state of a new environment
+type BootstrapRequest struct {
+	AdminEmail    string `json:"admin_email" binding:"required,email"`
+	AdminPassword string `json:"admin_password" binding:"required"`
+	AdminName     string `json:"admin_name"`
+	// LabelID and LabelName describe the default label, "default" and
+	// "Default" when unset
+	LabelID   string `json:"label_id"`
+	LabelName string `json:"label_name"`
+}
+
+// Bootstrap provisions a new environment
+// @Summary Bootstrap environment
+// @Description Create the first admin, the default label and the storage buckets and queue topics that are missing. Requests carry the setup token configured with BOOTSTRAP_SETUP_TOKEN instead of a session. Repeating a request for the same admin creates only what is missing, so provisioning tools can apply it on every run; once a different admin exists, requests are refused with 409 and the ALREADY_BOOTSTRAPPED code.
+// @Tags bootstrap
+// @Accept json
+// @Produce json
+// @Param X-Setup-Token header string true "Setup token"
+// @Param request body BootstrapRequest true "Environment"
+// @Success 200 {object} domain.BootstrapResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bootstrap [post]
+func (h *BootstrapHandler) Bootstrap(c *gin.Context) {
+	if !h.bootstrap.Authorize(c.GetHeader(SetupTokenHeader)) {
+		apperrors.Respond(c, apperrors.NewUnauthorizedError("invalid setup token"))
+		return
+	}
+
+	var req BootstrapRequest
+	if err := bindJSON(c, &req); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	result, err := h.bootstrap.Bootstrap(c.Request.Context(), usecase.BootstrapRequest{
+		AdminEmail:    req.AdminEmail,
+		AdminPassword: req.AdminPassword,
+		AdminName:     req.AdminName,
+		LabelID:       req.LabelID,
+		LabelName:     req.LabelName,
+	})
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to bootstrap environment"))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
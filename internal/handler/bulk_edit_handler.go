@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+	"metadatatool/internal/handler/middleware"
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/pkg/errortracking"
+	"metadatatool/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BulkEditHandler handles HTTP requests for bulk track edits
+type BulkEditHandler struct {
+	bulkEditUseCase *usecase.BulkEditUseCase
+	errorTracker    *errortracking.ErrorTracker
+}
+
+// NewBulkEditHandler creates a new bulk edit handler
+func NewBulkEditHandler(bulkEditUseCase *usecase.BulkEditUseCase, errorTracker *errortracking.ErrorTracker) *BulkEditHandler {
+	return &BulkEditHandler{
+		bulkEditUseCase: bulkEditUseCase,
+		errorTracker:    errorTracker,
+	}
+}
+
+// BulkEdit applies a patch to many tracks
+// @Summary Bulk edit tracks
+// @Description Apply a patch (set label, set genre, append tags) to tracks selected by ID list or filter. Dry runs, requested with dry_run in the body or the query, return a per-track preview without writing; other requests run as a background job.
+// @Tags tracks
+// @Accept json
+// @Produce json
+// @Param dry_run query bool false "Preview the changes without writing them"
+// @Param request body domain.BulkEditRequest true "Bulk edit request"
+// @Success 200 {object} domain.BulkEditJob "Dry-run preview"
+// @Success 202 {object} domain.BulkEditJob "Job accepted"
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/bulk-edit [post]
+func (h *BulkEditHandler) BulkEdit(c *gin.Context) {
+	var req domain.BulkEditRequest
+	if err := bindJSON(c, &req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if middleware.IsDryRun(c) {
+		req.DryRun = true
+	}
+
+	userID := c.GetString("user_id")
+	job, err := h.bulkEditUseCase.Submit(c.Request.Context(), &req, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			h.handleError(c, apperrors.NewValidationError("invalid bulk edit request", err.Error()))
+			return
+		}
+		h.handleError(c, apperrors.NewInternalError("failed to submit bulk edit", err))
+		return
+	}
+
+	if req.DryRun {
+		c.JSON(http.StatusOK, job)
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetBulkEditJob returns the status and per-track results of a bulk edit
+// @Summary Get bulk edit job
+// @Description Get the status and per-track results of a bulk edit job
+// @Tags tracks
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} domain.BulkEditJob
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/bulk-edit/{id} [get]
+func (h *BulkEditHandler) GetBulkEditJob(c *gin.Context) {
+	job, err := h.bulkEditUseCase.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, apperrors.NewInternalError("failed to get bulk edit job", err))
+		return
+	}
+	if job == nil {
+		h.handleError(c, apperrors.NewNotFoundError("bulk edit job not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func (h *BulkEditHandler) handleError(c *gin.Context, err *apperrors.AppError) {
+	if h.errorTracker != nil {
+		h.errorTracker.CaptureErrorContext(c.Request.Context(), err, map[string]string{
+			"handler": "bulk_edit",
+			"method":  c.Request.Method,
+			"path":    c.Request.URL.Path,
+		})
+	}
+
+	apperrors.Respond(c, err)
+}
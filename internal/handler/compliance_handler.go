@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/pkg/errortracking"
+	"metadatatool/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ComplianceHandler handles HTTP requests for exporting and erasing the
+// personal data of users
+type ComplianceHandler struct {
+	complianceUseCase *usecase.ComplianceUseCase
+	errorTracker      *errortracking.ErrorTracker
+}
+
+// NewComplianceHandler creates a new compliance handler
+func NewComplianceHandler(complianceUseCase *usecase.ComplianceUseCase, errorTracker *errortracking.ErrorTracker) *ComplianceHandler {
+	return &ComplianceHandler{
+		complianceUseCase: complianceUseCase,
+		errorTracker:      errorTracker,
+	}
+}
+
+// ExportUserData returns the personal data kept about a user
+// @Summary Export user data
+// @Description Bundle the profile, sessions and catalog changes of a user into a zip archive of JSON documents. Users may export their own data; admins may export anyone's.
+// @Tags users
+// @Produce application/zip
+// @Param id path string true "User ID"
+// @Success 200 {file} file "Zip archive"
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/{id}/export [post]
+func (h *ComplianceHandler) ExportUserData(c *gin.Context) {
+	userID := c.Param("id")
+	if !h.mayAccess(c, userID) {
+		h.handleError(c, apperrors.NewForbiddenError("insufficient permissions"))
+		return
+	}
+
+	export, err := h.complianceUseCase.Export(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, apperrors.FromError(err, "failed to export user data"))
+		return
+	}
+	// Built in memory so a failure can still be reported as an error
+	var archive bytes.Buffer
+	if err := export.WriteArchive(&archive); err != nil {
+		h.handleError(c, apperrors.NewInternalError("failed to build export archive", err))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=user-%s-export.zip", userID))
+	c.Data(http.StatusOK, "application/zip", archive.Bytes())
+}
+
+// DeleteUser erases a user
+// @Summary Delete user
+// @Description Delete a user's account and sessions. Tracks they edited are kept, with their changes attributed to "deleted-user" instead. Users may delete their own account; admins may delete anyone's.
+// @Tags users
+// @Param id path string true "User ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/{id} [delete]
+func (h *ComplianceHandler) DeleteUser(c *gin.Context) {
+	userID := c.Param("id")
+	if !h.mayAccess(c, userID) {
+		h.handleError(c, apperrors.NewForbiddenError("insufficient permissions"))
+		return
+	}
+
+	if err := h.complianceUseCase.Delete(c.Request.Context(), userID); err != nil {
+		h.handleError(c, apperrors.FromError(err, "failed to delete user"))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// mayAccess reports whether the signed-in user may act on userID's data
+func (h *ComplianceHandler) mayAccess(c *gin.Context, userID string) bool {
+	if role, _ := c.Get("role"); role == domain.RoleAdmin {
+		return true
+	}
+	return c.GetString("user_id") == userID
+}
+
+func (h *ComplianceHandler) handleError(c *gin.Context, err *apperrors.AppError) {
+	if h.errorTracker != nil {
+		h.errorTracker.CaptureErrorContext(c.Request.Context(), err, map[string]string{
+			"handler": "compliance",
+			"method":  c.Request.Method,
+			"path":    c.Request.URL.Path,
+		})
+	}
+
+	apperrors.Respond(c, err)
+}
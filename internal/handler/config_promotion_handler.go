@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"metadatatool/internal/handler/middleware"
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfigPromotionHandler exports and imports environment configuration
+type ConfigPromotionHandler struct {
+	promotion *usecase.ConfigPromotionUseCase
+}
+
+// NewConfigPromotionHandler creates a new configuration promotion handler
+func NewConfigPromotionHandler(promotion *usecase.ConfigPromotionUseCase) *ConfigPromotionHandler {
+	return &ConfigPromotionHandler{promotion: promotion}
+}
+
+// ExportConfig exports the environment's configuration as a signed bundle
+// @Summary Export configuration
+// @Description Export the runtime settings, tags, tagging rules and the custom fields and import mappings of labels as a bundle signed with the promotion key, to import into another environment
+// @Tags admin
+// @Produce json
+// @Param label query []string false "Labels whose custom fields and import mappings to export; all labels when omitted"
+// @Success 200 {object} domain.ConfigBundle
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/config/export [get]
+func (h *ConfigPromotionHandler) ExportConfig(c *gin.Context) {
+	bundle, err := h.promotion.Export(c.Request.Context(), c.QueryArray("label"), c.GetString("user_id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to export configuration"))
+		return
+	}
+	c.JSON(http.StatusOK, bundle)
+}
+
+// ImportConfig imports a configuration bundle
+// @Summary Import configuration
+// @Description Create and update the entities of a bundle exported from another environment. Bundles must be signed with this environment's promotion key. Entities missing from the bundle are kept. Dry runs report the changes without writing them.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param dry_run query bool false "Report the changes without writing them"
+// @Param request body domain.ConfigBundle true "Signed bundle"
+// @Success 200 {object} domain.ConfigImportResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/config/import [post]
+func (h *ConfigPromotionHandler) ImportConfig(c *gin.Context) {
+	var bundle domain.ConfigBundle
+	if err := bindJSON(c, &bundle); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	result, err := h.promotion.Import(c.Request.Context(), &bundle, c.GetString("user_id"), middleware.IsDryRun(c))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "invalid configuration bundle"))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"net/http"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CustomFieldHandler manages the custom field definitions of labels
+type CustomFieldHandler struct {
+	fields *usecase.CustomFieldUseCase
+}
+
+// NewCustomFieldHandler creates a new custom field handler
+func NewCustomFieldHandler(fields *usecase.CustomFieldUseCase) *CustomFieldHandler {
+	return &CustomFieldHandler{fields: fields}
+}
+
+// CustomFieldsResponse lists a label's custom field definitions
+type CustomFieldsResponse struct {
+	Fields []*domain.CustomFieldDefinition `json:"fields"`
+}
+
+// ListCustomFields lists a label's custom field definitions
+// @Summary List custom fields
+// @Description List the custom fields a label defines for its tracks
+// @Tags custom-fields
+// @Produce json
+// @Param label_id path string true "Label ID"
+// @Success 200 {object} CustomFieldsResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /labels/{label_id}/custom-fields [get]
+func (h *CustomFieldHandler) ListCustomFields(c *gin.Context) {
+	fields, err := h.fields.List(c.Request.Context(), c.Param("label_id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to list custom fields"))
+		return
+	}
+	c.JSON(http.StatusOK, CustomFieldsResponse{Fields: fields})
+}
+
+// GetCustomField returns a custom field definition
+// @Summary Get custom field
+// @Description Get one of a label's custom field definitions
+// @Tags custom-fields
+// @Produce json
+// @Param label_id path string true "Label ID"
+// @Param name path string true "Field name"
+// @Success 200 {object} domain.CustomFieldDefinition
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /labels/{label_id}/custom-fields/{name} [get]
+func (h *CustomFieldHandler) GetCustomField(c *gin.Context) {
+	field, err := h.fields.Get(c.Request.Context(), c.Param("label_id"), c.Param("name"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to get custom field"))
+		return
+	}
+	c.JSON(http.StatusOK, field)
+}
+
+// CreateCustomField defines a custom field
+// @Summary Create custom field
+// @Description Define a custom field for a label's tracks: its type (string, integer, number, boolean, date, url or enum), whether it is required, and how values are checked (pattern, min and max, allowed values). Once a label defines custom fields, track writes are checked against them and custom fields it does not define are refused.
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Param label_id path string true "Label ID"
+// @Param request body domain.CustomFieldDefinition true "Field definition"
+// @Success 201 {object} domain.CustomFieldDefinition
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /labels/{label_id}/custom-fields [post]
+func (h *CustomFieldHandler) CreateCustomField(c *gin.Context) {
+	var field domain.CustomFieldDefinition
+	if err := bindJSON(c, &field); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	if errs := field.Validate(); len(errs) > 0 {
+		apperrors.Respond(c, apperrors.NewFieldValidationError("invalid custom field", fieldErrors(errs)))
+		return
+	}
+
+	if err := h.fields.Create(c.Request.Context(), c.Param("label_id"), &field); err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "invalid custom field"))
+		return
+	}
+	c.JSON(http.StatusCreated, field)
+}
+
+// UpdateCustomField replaces a custom field definition
+// @Summary Replace custom field
+// @Description Replace one of a label's custom field definitions. Values already stored on tracks are checked against it when the tracks are next written.
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Param label_id path string true "Label ID"
+// @Param name path string true "Field name"
+// @Param request body domain.CustomFieldDefinition true "Field definition"
+// @Success 200 {object} domain.CustomFieldDefinition
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /labels/{label_id}/custom-fields/{name} [put]
+func (h *CustomFieldHandler) UpdateCustomField(c *gin.Context) {
+	var field domain.CustomFieldDefinition
+	if err := bindJSON(c, &field); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	field.Name = c.Param("name")
+	if errs := field.Validate(); len(errs) > 0 {
+		apperrors.Respond(c, apperrors.NewFieldValidationError("invalid custom field", fieldErrors(errs)))
+		return
+	}
+
+	if err := h.fields.Update(c.Request.Context(), c.Param("label_id"), &field); err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "invalid custom field"))
+		return
+	}
+	c.JSON(http.StatusOK, field)
+}
+
+// DeleteCustomField deletes a custom field definition
+// @Summary Delete custom field
+// @Description Delete one of a label's custom field definitions. Values stored on tracks are kept, but are refused on later writes while the label defines other custom fields.
+// @Tags custom-fields
+// @Param label_id path string true "Label ID"
+// @Param name path string true "Field name"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /labels/{label_id}/custom-fields/{name} [delete]
+func (h *CustomFieldHandler) DeleteCustomField(c *gin.Context) {
+	if err := h.fields.Delete(c.Request.Context(), c.Param("label_id"), c.Param("name")); err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to delete custom field"))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -2,20 +2,28 @@ package handler
 
 import (
 	"encoding/xml"
+	"log"
+	"metadatatool/internal/handler/middleware"
 	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type DDEXHandler struct {
-	trackRepo domain.TrackRepository
+	trackRepo  domain.TrackRepository
+	duplicates *usecase.DuplicateUseCase
 }
 
 // NewDDEXHandler creates a new DDEX handler
 func NewDDEXHandler(trackRepo domain.TrackRepository) *DDEXHandler {
 	return &DDEXHandler{
-		trackRepo: trackRepo,
+		trackRepo:  trackRepo,
+		duplicates: usecase.NewDuplicateUseCase(trackRepo),
 	}
 }
 
@@ -33,18 +41,14 @@ func NewDDEXHandler(trackRepo domain.TrackRepository) *DDEXHandler {
 func (h *DDEXHandler) ValidateERN(c *gin.Context) {
 	file, err := c.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid file upload",
-		})
+		apperrors.Respond(c, apperrors.NewValidationError("invalid file upload", ""))
 		return
 	}
 
 	// Open and read the file
 	src, err := file.Open()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to read file",
-		})
+		apperrors.Respond(c, apperrors.NewInternalError("failed to read file", err))
 		return
 	}
 	defer src.Close()
@@ -52,9 +56,7 @@ func (h *DDEXHandler) ValidateERN(c *gin.Context) {
 	// Parse XML
 	var ern domain.ERNMessage
 	if err := xml.NewDecoder(src).Decode(&ern); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid ERN XML format",
-		})
+		apperrors.Respond(c, apperrors.NewValidationError("invalid ERN XML format", ""))
 		return
 	}
 
@@ -66,13 +68,25 @@ func (h *DDEXHandler) ValidateERN(c *gin.Context) {
 	})
 }
 
+// ImportPreview describes the tracks a dry-run import would create
+type ImportPreview struct {
+	DryRun bool            `json:"dry_run"`
+	Tracks []*domain.Track `json:"tracks"`
+	// Errors lists the problems validation finds in the message
+	Errors []string `json:"errors,omitempty"`
+	// Duplicates lists the tracks that probably duplicate catalog tracks
+	Duplicates []DuplicatesResponse `json:"duplicates,omitempty"`
+}
+
 // ImportERN imports a DDEX ERN file
 // @Summary Import DDEX ERN
-// @Description Import tracks from a DDEX ERN XML file
+// @Description Import tracks from a DDEX ERN XML file. With dry_run=true nothing is saved; the response lists the tracks that would be created, the problems found in the message and the probable duplicates of catalog tracks. Otherwise the IDs of probably duplicated catalog tracks are listed in the Probable-Duplicates header.
 // @Tags ddex
 // @Accept xml
 // @Produce json
+// @Param dry_run query bool false "Preview the import without saving"
 // @Param file formData file true "DDEX ERN XML file"
+// @Success 200 {object} ImportPreview "Dry-run preview"
 // @Success 201 {array} domain.Track
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -80,18 +94,14 @@ func (h *DDEXHandler) ValidateERN(c *gin.Context) {
 func (h *DDEXHandler) ImportERN(c *gin.Context) {
 	file, err := c.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid file upload",
-		})
+		apperrors.Respond(c, apperrors.NewValidationError("invalid file upload", ""))
 		return
 	}
 
 	// Open and read the file
 	src, err := file.Open()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to read file",
-		})
+		apperrors.Respond(c, apperrors.NewInternalError("failed to read file", err))
 		return
 	}
 	defer src.Close()
@@ -99,22 +109,49 @@ func (h *DDEXHandler) ImportERN(c *gin.Context) {
 	// Parse XML
 	var ern domain.ERNMessage
 	if err := xml.NewDecoder(src).Decode(&ern); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid ERN XML format",
-		})
+		apperrors.Respond(c, apperrors.NewValidationError("invalid ERN XML format", ""))
 		return
 	}
 
 	// Convert ERN to tracks
 	tracks := convertERNToTracks(&ern)
 
-	// Save tracks
+	for _, track := range tracks {
+		track.ID = uuid.New().String()
+		track.RecordProvenance(domain.DiffTracks(&domain.Track{}, track), domain.ProvenanceImport, c.GetString("user_id"))
+	}
+	duplicates := h.findDuplicates(c, tracks)
+
+	if middleware.IsDryRun(c) {
+		_, problems := validateERN(&ern)
+		c.JSON(http.StatusOK, ImportPreview{DryRun: true, Tracks: tracks, Errors: problems, Duplicates: duplicates})
+		return
+	}
+
+	var ids []string
+	for _, d := range duplicates {
+		for _, candidate := range d.Duplicates {
+			ids = append(ids, candidate.TrackID)
+		}
+	}
+	if len(ids) > 0 {
+		c.Header(ProbableDuplicatesHeader, strings.Join(ids, ", "))
+	}
+
+	// Save tracks, in bulk when the repository supports it
+	if writer, ok := h.trackRepo.(domain.TrackBulkWriter); ok {
+		if err := writer.BatchCreate(c, tracks); err != nil {
+			apperrors.Respond(c, apperrors.NewInternalError("failed to save tracks", err))
+			return
+		}
+		c.JSON(http.StatusCreated, tracks)
+		return
+	}
+
 	var savedTracks []*domain.Track
 	for _, track := range tracks {
 		if err := h.trackRepo.Create(c, track); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "failed to save track",
-			})
+			apperrors.Respond(c, apperrors.NewInternalError("failed to save track", err))
 			return
 		}
 		savedTracks = append(savedTracks, track)
@@ -123,6 +160,24 @@ func (h *DDEXHandler) ImportERN(c *gin.Context) {
 	c.JSON(http.StatusCreated, savedTracks)
 }
 
+// findDuplicates compares imported tracks with the catalog and with each
+// other. Detection failures do not stop the import.
+func (h *DDEXHandler) findDuplicates(c *gin.Context, tracks []*domain.Track) []DuplicatesResponse {
+	catalog, err := h.duplicates.Catalog(c.Request.Context())
+	if err != nil {
+		log.Printf("failed to check imported tracks for duplicates: %v", err)
+		return nil
+	}
+	var found []DuplicatesResponse
+	for _, track := range tracks {
+		if duplicates := domain.FindDuplicates(track, catalog); len(duplicates) > 0 {
+			found = append(found, DuplicatesResponse{TrackID: track.ID, Duplicates: duplicates})
+		}
+		catalog = append(catalog, track)
+	}
+	return found
+}
+
 // ExportERN exports tracks as a DDEX ERN file
 // @Summary Export DDEX ERN
 // @Description Export tracks as a DDEX ERN XML file
@@ -135,9 +190,7 @@ func (h *DDEXHandler) ExportERN(c *gin.Context) {
 	// Get all tracks
 	tracks, err := h.trackRepo.List(c, map[string]interface{}{}, 0, 1000) // TODO: Add pagination
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to get tracks",
-		})
+		apperrors.Respond(c, apperrors.NewInternalError("failed to get tracks", err))
 		return
 	}
 
@@ -147,9 +200,7 @@ func (h *DDEXHandler) ExportERN(c *gin.Context) {
 	// Marshal to XML
 	xmlData, err := xml.MarshalIndent(ern, "", "  ")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to generate XML",
-		})
+		apperrors.Respond(c, apperrors.NewInternalError("failed to generate XML", err))
 		return
 	}
 
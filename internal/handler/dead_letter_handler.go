@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"errors"
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeadLetterHandler handles the admin API for dead-lettered queue messages
+type DeadLetterHandler struct {
+	deadLetters *usecase.DeadLetterUseCase
+}
+
+// NewDeadLetterHandler creates a new dead letter handler
+func NewDeadLetterHandler(deadLetters *usecase.DeadLetterUseCase) *DeadLetterHandler {
+	return &DeadLetterHandler{deadLetters: deadLetters}
+}
+
+// ListDeadLetters returns a page of a topic's dead letters
+// @Summary List dead letters
+// @Description List the messages of a topic that exhausted their retries, newest first
+// @Tags admin
+// @Produce json
+// @Param topic path string true "Queue topic"
+// @Param offset query int false "Messages to skip"
+// @Param limit query int false "Page size, at most 500 (default 50)"
+// @Success 200 {object} domain.DeadLetterPage
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/dead-letters/{topic} [get]
+func (h *DeadLetterHandler) ListDeadLetters(c *gin.Context) {
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		h.handleError(c, apperrors.NewValidationError("invalid offset", err.Error()))
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		h.handleError(c, apperrors.NewValidationError("invalid limit", err.Error()))
+		return
+	}
+
+	page, err := h.deadLetters.List(c.Request.Context(), c.Param("topic"), offset, limit)
+	if err != nil {
+		h.handleUseCaseError(c, "failed to list dead letters", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetDeadLetter returns one dead letter with its payload and last error
+// @Summary Get dead letter
+// @Description Get a dead-lettered message with its payload, retry count and last error
+// @Tags admin
+// @Produce json
+// @Param topic path string true "Queue topic"
+// @Param id path string true "Message ID"
+// @Success 200 {object} domain.Message
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/dead-letters/{topic}/{id} [get]
+func (h *DeadLetterHandler) GetDeadLetter(c *gin.Context) {
+	msg, err := h.deadLetters.Get(c.Request.Context(), c.Param("topic"), c.Param("id"))
+	if err != nil {
+		h.handleError(c, apperrors.NewInternalError("failed to get dead letter", err))
+		return
+	}
+	if msg == nil {
+		h.handleError(c, apperrors.NewNotFoundError("dead letter not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, msg)
+}
+
+// ReplayDeadLetter moves one dead letter back to its topic
+// @Summary Replay dead letter
+// @Description Move a dead-lettered message back to its topic with its retries reset. The replay is audit logged.
+// @Tags admin
+// @Param topic path string true "Queue topic"
+// @Param id path string true "Message ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/dead-letters/{topic}/{id}/replay [post]
+func (h *DeadLetterHandler) ReplayDeadLetter(c *gin.Context) {
+	err := h.deadLetters.Replay(c.Request.Context(), c.Param("topic"), c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		h.handleUseCaseError(c, "failed to replay dead letter", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ReplayDeadLetters moves many dead letters back to their topic
+// @Summary Replay dead letters
+// @Description Replay the listed dead letters of a topic, or all of them (up to 1000) when no IDs are given. Every replay is audit logged.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param topic path string true "Queue topic"
+// @Param request body domain.DeadLetterReplayRequest false "Dead letters to replay"
+// @Success 200 {object} domain.DeadLetterReplayReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/dead-letters/{topic}/replay [post]
+func (h *DeadLetterHandler) ReplayDeadLetters(c *gin.Context) {
+	var req domain.DeadLetterReplayRequest
+	if c.Request.ContentLength != 0 {
+		if err := bindJSON(c, &req); err != nil {
+			h.handleError(c, err)
+			return
+		}
+	}
+
+	report, err := h.deadLetters.ReplayMany(c.Request.Context(), c.Param("topic"), &req, c.GetString("user_id"))
+	if err != nil {
+		h.handleUseCaseError(c, "failed to replay dead letters", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// PurgeDeadLetters removes all dead letters of a topic
+// @Summary Purge dead letters
+// @Description Delete every dead letter of a topic. The purge is audit logged.
+// @Tags admin
+// @Param topic path string true "Queue topic"
+// @Success 204 "No Content"
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/dead-letters/{topic} [delete]
+func (h *DeadLetterHandler) PurgeDeadLetters(c *gin.Context) {
+	if err := h.deadLetters.Purge(c.Request.Context(), c.Param("topic"), c.GetString("user_id")); err != nil {
+		h.handleError(c, apperrors.NewInternalError("failed to purge dead letters", err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *DeadLetterHandler) handleUseCaseError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		h.handleError(c, apperrors.NewValidationError(message, err.Error()))
+	case errors.Is(err, domain.ErrDeadLetterNotFound):
+		h.handleError(c, apperrors.NewNotFoundError("dead letter not found"))
+	default:
+		h.handleError(c, apperrors.NewInternalError(message, err))
+	}
+}
+
+func (h *DeadLetterHandler) handleError(c *gin.Context, err *apperrors.AppError) {
+	apperrors.Respond(c, err)
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxAcknowledgementSize caps the size of an acknowledgement body
+const maxAcknowledgementSize = 1 << 20
+
+// DeliveryHandler records deliveries to DSPs and ingests their acknowledgements
+type DeliveryHandler struct {
+	deliveries *usecase.DeliveryUseCase
+}
+
+// NewDeliveryHandler creates a new delivery handler
+func NewDeliveryHandler(deliveries *usecase.DeliveryUseCase) *DeliveryHandler {
+	return &DeliveryHandler{deliveries: deliveries}
+}
+
+// DeliveriesResponse is a page of deliveries
+type DeliveriesResponse struct {
+	Deliveries []*domain.Delivery `json:"deliveries"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+}
+
+// RecordDelivery records a package sent to a DSP
+// @Summary Record a delivery
+// @Description Record that a package of tracks was sent to a DSP. Every track gets a delivery in the sent status until the DSP's acknowledgement arrives.
+// @Tags deliveries
+// @Accept json
+// @Produce json
+// @Param request body usecase.RecordDeliveryRequest true "Delivered package"
+// @Success 201 {array} domain.Delivery
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /deliveries [post]
+func (h *DeliveryHandler) RecordDelivery(c *gin.Context) {
+	var req usecase.RecordDeliveryRequest
+	if err := bindJSON(c, &req); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	deliveries, err := h.deliveries.Record(c.Request.Context(), &req)
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to record delivery"))
+		return
+	}
+	c.JSON(http.StatusCreated, deliveries)
+}
+
+// ListDeliveries lists deliveries, newest first
+// @Summary List deliveries
+// @Description List deliveries to DSPs, newest first. Use status=failed to find the packages DSPs refused.
+// @Tags deliveries
+// @Produce json
+// @Param dsp query string false "Only deliveries to this DSP"
+// @Param track_id query string false "Only deliveries of this track"
+// @Param release_id query string false "Only deliveries of this release"
+// @Param kind query string false "release, takedown or territory_block"
+// @Param status query string false "sent, acknowledged or failed"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} DeliveriesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /deliveries [get]
+func (h *DeliveryHandler) ListDeliveries(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	deliveries, err := h.deliveries.List(c.Request.Context(), domain.DeliveryFilter{
+		DSP:       c.Query("dsp"),
+		TrackID:   c.Query("track_id"),
+		ReleaseID: c.Query("release_id"),
+		Kind:      domain.DeliveryKind(c.Query("kind")),
+		Status:    domain.DeliveryStatus(c.Query("status")),
+		Offset:    (page - 1) * limit,
+		Limit:     limit,
+	})
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "invalid delivery query"))
+		return
+	}
+	c.JSON(http.StatusOK, DeliveriesResponse{Deliveries: deliveries, Page: page, Limit: limit})
+}
+
+// RequestTakedown withdraws delivered tracks from a DSP
+// @Summary Request a takedown or territory block
+// @Description Generate the DDEX update message that takes tracks down from a DSP, or blocks them in the given territories, and record a delivery per track. The tracks must have been delivered to the DSP and acknowledged. Send the returned message to the DSP; its acknowledgement updates the deliveries. With dry_run=true the message and deliveries are returned but not recorded.
+// @Tags deliveries
+// @Accept json
+// @Produce json
+// @Param dry_run query bool false "Generate the message without recording the deliveries"
+// @Param request body usecase.TakedownRequest true "Tracks to withdraw"
+// @Success 200 {object} usecase.TakedownResult "Dry-run preview"
+// @Success 201 {object} usecase.TakedownResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /deliveries/takedowns [post]
+func (h *DeliveryHandler) RequestTakedown(c *gin.Context) {
+	var req usecase.TakedownRequest
+	if err := bindJSON(c, &req); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	result, err := h.deliveries.RequestTakedown(c.Request.Context(), &req)
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "invalid takedown"))
+		return
+	}
+	if result.DryRun {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+// AcknowledgeDelivery ingests a DDEX acknowledgement from a DSP
+// @Summary Ingest a delivery acknowledgement
+// @Description Apply a DDEX acknowledgement message to the deliveries of the package it refers to. FileOK acknowledges them and moves their tracks to delivered; any other status fails them with the DSP's error text.
+// @Tags deliveries
+// @Accept xml
+// @Produce json
+// @Param request body string true "DDEX acknowledgement message"
+// @Success 200 {object} domain.AcknowledgementResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /deliveries/acknowledgements [post]
+func (h *DeliveryHandler) AcknowledgeDelivery(c *gin.Context) {
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxAcknowledgementSize))
+	if err != nil {
+		apperrors.Respond(c, apperrors.NewValidationError("failed to read request body", err.Error()))
+		return
+	}
+
+	result, err := h.deliveries.Acknowledge(c.Request.Context(), data)
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "invalid acknowledgement"))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"net/http"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EvaluationHandler handles HTTP requests for the golden dataset and the
+// evaluations of the AI providers against it
+type EvaluationHandler struct {
+	evaluations *usecase.EvaluationUseCase
+}
+
+// NewEvaluationHandler creates a new evaluation handler
+func NewEvaluationHandler(evaluations *usecase.EvaluationUseCase) *EvaluationHandler {
+	return &EvaluationHandler{evaluations: evaluations}
+}
+
+// GoldenTracksResponse lists the golden dataset
+type GoldenTracksResponse struct {
+	Tracks []*domain.GoldenTrack `json:"tracks"`
+}
+
+// ListGoldenTracks lists the golden dataset
+// @Summary List golden tracks
+// @Description List the tracks whose values a person has confirmed, which the AI providers are scored against
+// @Tags admin
+// @Produce json
+// @Success 200 {object} GoldenTracksResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/ai/golden-tracks [get]
+func (h *EvaluationHandler) ListGoldenTracks(c *gin.Context) {
+	tracks, err := h.evaluations.ListGoldenTracks(c.Request.Context())
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to list golden tracks"))
+		return
+	}
+	c.JSON(http.StatusOK, GoldenTracksResponse{Tracks: tracks})
+}
+
+// SaveGoldenTrack adds a track to the golden dataset
+// @Summary Save golden track
+// @Description Add a track to the golden dataset or replace its confirmed values. Label at least one of genre, mood, bpm and key; fields left empty are not scored. Genres and moods are compared ignoring case, tempos within 2% and keys in any notation, so "Am" and "8A" match.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param track_id path string true "Track ID"
+// @Param request body domain.GoldenTrack true "Confirmed values"
+// @Success 200 {object} domain.GoldenTrack
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/ai/golden-tracks/{track_id} [put]
+func (h *EvaluationHandler) SaveGoldenTrack(c *gin.Context) {
+	var golden domain.GoldenTrack
+	if err := bindJSON(c, &golden); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	golden.TrackID = c.Param("track_id")
+	golden.LabeledBy = c.GetString("user_id")
+	if err := h.evaluations.SaveGoldenTrack(c.Request.Context(), &golden); err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "invalid golden track"))
+		return
+	}
+	c.JSON(http.StatusOK, golden)
+}
+
+// DeleteGoldenTrack removes a track from the golden dataset
+// @Summary Delete golden track
+// @Description Remove a track from the golden dataset. The track itself is kept.
+// @Tags admin
+// @Param track_id path string true "Track ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/ai/golden-tracks/{track_id} [delete]
+func (h *EvaluationHandler) DeleteGoldenTrack(c *gin.Context) {
+	if err := h.evaluations.DeleteGoldenTrack(c.Request.Context(), c.Param("track_id")); err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to delete golden track"))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RunEvaluation scores the AI providers against the golden dataset now
+// @Summary Run evaluation
+// @Description Enrich every golden track with every AI provider and score the results: the accuracy per field, a calibration curve of accuracy by confidence, and the lowest confidence threshold reaching the target accuracy on at least 10 tracks. The enriched tracks are not saved. Evaluations also run on a schedule; this waits for the providers, so it takes a while on a large dataset.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.EvaluationReport
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/ai/evaluations [post]
+func (h *EvaluationHandler) RunEvaluation(c *gin.Context) {
+	report, err := h.evaluations.Evaluate(c.Request.Context())
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to evaluate AI providers"))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// GetLatestEvaluation returns the latest evaluation
+// @Summary Get latest evaluation
+// @Description Get the report of the latest evaluation of the AI providers against the golden dataset. Compare suggested_min_confidence with the runtime AI confidence threshold to tune it.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.EvaluationReport
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/ai/evaluations/latest [get]
+func (h *EvaluationHandler) GetLatestEvaluation(c *gin.Context) {
+	report, err := h.evaluations.Latest()
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to get evaluation"))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+const (
+	// maxGraphQLQuerySize caps the size of a GraphQL request without files
+	maxGraphQLQuerySize = 1 << 20
+	// maxGraphQLUploadSize caps the size of a multipart GraphQL request,
+	// files included
+	maxGraphQLUploadSize = 512 << 20
+)
+
+// GraphQLHandler serves the GraphQL API
+type GraphQLHandler struct {
+	schema *graphql.Schema
+}
+
+// NewGraphQLHandler creates a handler executing operations against schema
+func NewGraphQLHandler(schema *graphql.Schema) *GraphQLHandler {
+	return &GraphQLHandler{schema: schema}
+}
+
+// GraphQLRequest is a GraphQL operation
+type GraphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Execute runs a GraphQL operation. Operations uploading files are sent as
+// multipart requests following the GraphQL multipart request spec: the
+// operation goes in the operations field, and the map field names the
+// variables each file part is put into. The route lives outside /api/v1,
+// so it is not part of the OpenAPI document.
+func (h *GraphQLHandler) Execute(c *gin.Context) {
+	var req GraphQLRequest
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxGraphQLUploadSize)
+		files, err := bindMultipartOperation(c, &req)
+		defer func() {
+			for _, file := range files {
+				file.Close()
+			}
+		}()
+		if err != nil {
+			apperrors.Respond(c, err)
+			return
+		}
+	} else {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxGraphQLQuerySize)
+		if err := bindJSON(c, &req); err != nil {
+			apperrors.Respond(c, err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, h.schema.Exec(c.Request.Context(), req.Query, req.OperationName, req.Variables))
+}
+
+// bindMultipartOperation reads the operation of a multipart request and
+// puts its files into the variables the map names. The files returned are
+// open until the caller closes them.
+func bindMultipartOperation(c *gin.Context, req *GraphQLRequest) ([]multipart.File, *apperrors.AppError) {
+	if err := json.Unmarshal([]byte(c.PostForm("operations")), req); err != nil || req.Query == "" {
+		return nil, apperrors.NewValidationError("the operations field must hold a GraphQL operation", "")
+	}
+	var fileMap map[string][]string
+	if err := json.Unmarshal([]byte(c.PostForm("map")), &fileMap); err != nil {
+		return nil, apperrors.NewValidationError("the map field must map file parts to variables", "")
+	}
+
+	var files []multipart.File
+	for part, paths := range fileMap {
+		header, err := c.FormFile(part)
+		if err != nil {
+			return files, apperrors.NewValidationError(fmt.Sprintf("missing file part %q", part), "")
+		}
+		file, err := header.Open()
+		if err != nil {
+			return files, apperrors.NewInternalError("failed to read uploaded file", err)
+		}
+		files = append(files, file)
+
+		upload := &domain.AudioFile{File: file, Filename: header.Filename, Size: header.Size}
+		for _, path := range paths {
+			if !setVariable(req, path, upload) {
+				return files, apperrors.NewValidationError(fmt.Sprintf("map path %q does not name a variable", path), "")
+			}
+		}
+	}
+	return files, nil
+}
+
+// setVariable replaces the value a path such as variables.input.audioFile
+// or variables.files.0 names
+func setVariable(req *GraphQLRequest, path string, value interface{}) bool {
+	keys := strings.Split(path, ".")
+	if len(keys) < 2 || keys[0] != "variables" || req.Variables == nil {
+		return false
+	}
+
+	var parent interface{} = req.Variables
+	for i, key := range keys[1:] {
+		last := i == len(keys)-2
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := node[key]; !ok {
+				return false
+			}
+			if last {
+				node[key] = value
+				return true
+			}
+			parent = node[key]
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return false
+			}
+			if last {
+				node[index] = value
+				return true
+			}
+			parent = node[index]
+		default:
+			return false
+		}
+	}
+	return false
+}
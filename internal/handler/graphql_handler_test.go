@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"metadatatool/internal/graphql/executor"
+
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const uploadSchema = `
+	scalar Upload
+	schema { query: Query mutation: Mutation }
+	type Query { greeting(name: String!): String! }
+	type Mutation { upload(file: Upload!): String! }
+`
+
+// uploadResolver greets and echoes the name and content of uploaded files
+type uploadResolver struct{}
+
+func (*uploadResolver) Greeting(args struct{ Name string }) string { return "hello " + args.Name }
+
+func (*uploadResolver) Upload(args struct{ File executor.Upload }) (string, error) {
+	content, err := io.ReadAll(args.File.File.File)
+	if err != nil {
+		return "", err
+	}
+	return args.File.File.Filename + ":" + string(content), nil
+}
+
+func TestGraphQLHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	schema := graphql.MustParseSchema(uploadSchema, &uploadResolver{})
+	router := gin.New()
+	router.POST("/graphql", NewGraphQLHandler(schema).Execute)
+
+	serve := func(req *http.Request) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w.Code, body
+	}
+
+	t.Run("executes a JSON request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(
+			`{"query":"query($name: String!) { greeting(name: $name) }","variables":{"name":"Ada"}}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		code, body := serve(req)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, map[string]interface{}{"greeting": "hello Ada"}, body["data"])
+	})
+
+	t.Run("rejects a request without a query", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"variables":{}}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		code, _ := serve(req)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	multipartRequest := func(operations, fileMap string) *http.Request {
+		var buf bytes.Buffer
+		form := multipart.NewWriter(&buf)
+		require.NoError(t, form.WriteField("operations", operations))
+		require.NoError(t, form.WriteField("map", fileMap))
+		part, err := form.CreateFormFile("0", "song.mp3")
+		require.NoError(t, err)
+		_, err = part.Write([]byte("audio"))
+		require.NoError(t, err)
+		require.NoError(t, form.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/graphql", &buf)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		return req
+	}
+
+	t.Run("puts uploaded files into the mapped variables", func(t *testing.T) {
+		code, body := serve(multipartRequest(
+			`{"query":"mutation($file: Upload!) { upload(file: $file) }","variables":{"file":null}}`,
+			`{"0":["variables.file"]}`))
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, map[string]interface{}{"upload": "song.mp3:audio"}, body["data"])
+	})
+
+	t.Run("rejects a map path that names no variable", func(t *testing.T) {
+		code, _ := serve(multipartRequest(
+			`{"query":"mutation($file: Upload!) { upload(file: $file) }","variables":{"file":null}}`,
+			`{"0":["variables.other"]}`))
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
@@ -1,16 +1,71 @@
 package handler
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
+	"metadatatool/internal/pkg/audio"
+
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 )
 
+// checkTimeout bounds each dependency check of the readiness probe
+const checkTimeout = 3 * time.Second
+
+// DependencyCheck probes one dependency for the readiness probe
+type DependencyCheck struct {
+	Name string
+	// Critical dependencies make the service unready when they fail; the
+	// others only mark it degraded
+	Critical bool
+	// Check returns nil when the dependency is reachable. A nil Check
+	// reports the dependency as disabled.
+	Check func(ctx context.Context) error
+}
+
+// Pinger is implemented by dependencies that can report their reachability
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingCheck returns a check that pings dependency, or nil when it cannot be
+// pinged
+func PingCheck(dependency interface{}) func(ctx context.Context) error {
+	if pinger, ok := dependency.(Pinger); ok {
+		return pinger.Ping
+	}
+	return nil
+}
+
+// HTTPCheck returns a check that succeeds when url answers. Any response
+// below 500 shows the service is reachable, including auth errors.
+func HTTPCheck(client *http.Client, url string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 500 {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
 // HealthHandler handles health check requests
 type HealthHandler struct {
-	redis *redis.Client
+	redis  *redis.Client
+	checks []DependencyCheck
+	audio  *audio.Capabilities
 }
 
 // NewHealthHandler creates a new health check handler
@@ -20,18 +75,33 @@ func NewHealthHandler(redis *redis.Client) *HealthHandler {
 	}
 }
 
+// AddCheck registers a dependency checked by the readiness probe
+func (h *HealthHandler) AddCheck(check DependencyCheck) {
+	h.checks = append(h.checks, check)
+}
+
+// SetAudioCapabilities reports how audio can be analyzed on this host in
+// the health check
+func (h *HealthHandler) SetAudioCapabilities(caps audio.Capabilities) {
+	h.audio = &caps
+}
+
 // ServiceStatus represents the status of an individual service
 type ServiceStatus struct {
 	Status    string `json:"status"`
 	Message   string `json:"message,omitempty"`
 	Latency   int64  `json:"latency_ms"`
 	Timestamp string `json:"timestamp"`
+	Critical  bool   `json:"critical,omitempty"`
 }
 
 // HealthStatus represents the overall health check response
 type HealthStatus struct {
 	Status   string                   `json:"status"`
 	Services map[string]ServiceStatus `json:"services"`
+	// Audio lists the audio tools found and the formats analyzed without
+	// them. Missing tools do not make the service unhealthy.
+	Audio *audio.Capabilities `json:"audio,omitempty"`
 }
 
 // Check performs health checks on all services
@@ -39,6 +109,7 @@ func (h *HealthHandler) Check(c *gin.Context) {
 	status := HealthStatus{
 		Status:   "healthy",
 		Services: make(map[string]ServiceStatus),
+		Audio:    h.audio,
 	}
 
 	// Check Redis if enabled
@@ -77,3 +148,74 @@ func (h *HealthHandler) Check(c *gin.Context) {
 	}
 	c.JSON(http.StatusServiceUnavailable, status)
 }
+
+// Live reports that the process is running and able to serve requests. It
+// checks no dependencies, so a failing database does not get the process
+// restarted.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "alive",
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// Ready checks every registered dependency concurrently. The service is
+// "unhealthy" and answers 503 when a critical dependency fails, and
+// "degraded" but still serving when only optional ones fail.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	status := HealthStatus{
+		Status:   "healthy",
+		Services: make(map[string]ServiceStatus, len(h.checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func(check DependencyCheck) {
+			defer wg.Done()
+			result := runCheck(c.Request.Context(), check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			status.Services[check.Name] = result
+			if result.Status != "error" {
+				return
+			}
+			if check.Critical {
+				status.Status = "unhealthy"
+			} else if status.Status == "healthy" {
+				status.Status = "degraded"
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	if status.Status == "unhealthy" {
+		c.JSON(http.StatusServiceUnavailable, status)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+func runCheck(ctx context.Context, check DependencyCheck) ServiceStatus {
+	result := ServiceStatus{Status: "healthy", Critical: check.Critical}
+	if check.Check == nil {
+		result.Status = "disabled"
+		result.Timestamp = time.Now().UTC().Format(time.RFC3339)
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Check(ctx)
+	result.Latency = time.Since(start).Milliseconds()
+	result.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	if err != nil {
+		result.Status = "error"
+		result.Message = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"metadatatool/internal/pkg/audio"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ready(t *testing.T, h *HealthHandler) (int, HealthStatus) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/health/ready", h.Ready)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+	var status HealthStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	return w.Code, status
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name       string
+		checks     []DependencyCheck
+		wantCode   int
+		wantStatus string
+	}{
+		{
+			name:       "all healthy",
+			checks:     []DependencyCheck{{Name: "database", Critical: true, Check: ok}, {Name: "ai", Check: ok}},
+			wantCode:   http.StatusOK,
+			wantStatus: "healthy",
+		},
+		{
+			name:       "optional dependency down",
+			checks:     []DependencyCheck{{Name: "database", Critical: true, Check: ok}, {Name: "ai", Check: failing}},
+			wantCode:   http.StatusOK,
+			wantStatus: "degraded",
+		},
+		{
+			name:       "critical dependency down",
+			checks:     []DependencyCheck{{Name: "database", Critical: true, Check: failing}, {Name: "ai", Check: failing}},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: "unhealthy",
+		},
+		{
+			name:       "disabled dependency",
+			checks:     []DependencyCheck{{Name: "database", Critical: true, Check: ok}, {Name: "queue"}},
+			wantCode:   http.StatusOK,
+			wantStatus: "healthy",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthHandler(nil)
+			for _, check := range tt.checks {
+				h.AddCheck(check)
+			}
+
+			code, status := ready(t, h)
+			assert.Equal(t, tt.wantCode, code)
+			assert.Equal(t, tt.wantStatus, status.Status)
+			assert.Len(t, status.Services, len(tt.checks))
+		})
+	}
+}
+
+func TestHealthHandler_ReadyReportsFailure(t *testing.T) {
+	h := NewHealthHandler(nil)
+	h.AddCheck(DependencyCheck{Name: "storage", Check: func(ctx context.Context) error {
+		return errors.New("bucket not found")
+	}})
+	h.AddCheck(DependencyCheck{Name: "queue"})
+
+	_, status := ready(t, h)
+	assert.Equal(t, "error", status.Services["storage"].Status)
+	assert.Equal(t, "bucket not found", status.Services["storage"].Message)
+	assert.Equal(t, "disabled", status.Services["queue"].Status)
+}
+
+func TestHealthHandler_CheckReportsAudioCapabilities(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewHealthHandler(nil)
+	h.SetAudioCapabilities(audio.Capabilities{FFprobe: true, NativeFormats: []string{"wav"}})
+	router := gin.New()
+	router.GET("/health", h.Check)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	var status HealthStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	// Missing tools are reported without failing the check
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, status.Audio)
+	assert.False(t, status.Audio.FFmpeg)
+	assert.True(t, status.Audio.FFprobe)
+	assert.Equal(t, []string{"wav"}, status.Audio.NativeFormats)
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"net/http"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize caps the size of an imported CSV file
+const maxImportSize = 64 << 20
+
+// ImportHandler manages label import mappings and imports CSV files with them
+type ImportHandler struct {
+	imports *usecase.CSVImportUseCase
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(imports *usecase.CSVImportUseCase) *ImportHandler {
+	return &ImportHandler{imports: imports}
+}
+
+// ImportMappingsResponse lists a label's import mappings
+type ImportMappingsResponse struct {
+	Mappings []*domain.ImportMapping `json:"mappings"`
+}
+
+// ListImportMappings lists a label's import mappings
+// @Summary List import mappings
+// @Description List the CSV import mappings of a label
+// @Tags imports
+// @Produce json
+// @Param label_id path string true "Label ID"
+// @Success 200 {object} ImportMappingsResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /labels/{label_id}/import-mappings [get]
+func (h *ImportHandler) ListImportMappings(c *gin.Context) {
+	mappings, err := h.imports.ListMappings(c.Request.Context(), c.Param("label_id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to list import mappings"))
+		return
+	}
+	c.JSON(http.StatusOK, ImportMappingsResponse{Mappings: mappings})
+}
+
+// GetImportMapping returns an import mapping
+// @Summary Get import mapping
+// @Description Get one of a label's CSV import mappings
+// @Tags imports
+// @Produce json
+// @Param label_id path string true "Label ID"
+// @Param id path string true "Mapping ID"
+// @Success 200 {object} domain.ImportMapping
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /labels/{label_id}/import-mappings/{id} [get]
+func (h *ImportHandler) GetImportMapping(c *gin.Context) {
+	mapping, err := h.imports.GetMapping(c.Request.Context(), c.Param("label_id"), c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to get import mapping"))
+		return
+	}
+	c.JSON(http.StatusOK, mapping)
+}
+
+// CreateImportMapping creates an import mapping
+// @Summary Create import mapping
+// @Description Create a CSV import mapping for a label: which column goes to which track field, and how values are transformed on the way (date formats, lookups, case).
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Param label_id path string true "Label ID"
+// @Param request body domain.ImportMapping true "Mapping"
+// @Success 201 {object} domain.ImportMapping
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /labels/{label_id}/import-mappings [post]
+func (h *ImportHandler) CreateImportMapping(c *gin.Context) {
+	h.saveImportMapping(c, "", http.StatusCreated)
+}
+
+// UpdateImportMapping replaces an import mapping
+// @Summary Replace import mapping
+// @Description Replace one of a label's CSV import mappings
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Param label_id path string true "Label ID"
+// @Param id path string true "Mapping ID"
+// @Param request body domain.ImportMapping true "Mapping"
+// @Success 200 {object} domain.ImportMapping
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /labels/{label_id}/import-mappings/{id} [put]
+func (h *ImportHandler) UpdateImportMapping(c *gin.Context) {
+	h.saveImportMapping(c, c.Param("id"), http.StatusOK)
+}
+
+func (h *ImportHandler) saveImportMapping(c *gin.Context, id string, status int) {
+	var mapping domain.ImportMapping
+	if err := bindJSON(c, &mapping); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	if errs := mapping.Validate(); len(errs) > 0 {
+		apperrors.Respond(c, apperrors.NewFieldValidationError("invalid import mapping", fieldErrors(errs)))
+		return
+	}
+
+	mapping.ID = id
+	if err := h.imports.SaveMapping(c.Request.Context(), c.Param("label_id"), &mapping); err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "invalid import mapping"))
+		return
+	}
+	c.JSON(status, mapping)
+}
+
+// DeleteImportMapping deletes an import mapping
+// @Summary Delete import mapping
+// @Description Delete one of a label's CSV import mappings
+// @Tags imports
+// @Param label_id path string true "Label ID"
+// @Param id path string true "Mapping ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /labels/{label_id}/import-mappings/{id} [delete]
+func (h *ImportHandler) DeleteImportMapping(c *gin.Context) {
+	if err := h.imports.DeleteMapping(c.Request.Context(), c.Param("label_id"), c.Param("id")); err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to delete import mapping"))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ImportCSV imports tracks from a CSV file
+// @Summary Import CSV
+// @Description Create the label's tracks from a distributor CSV file, mapped with one of the label's import mappings. Valid rows are imported; invalid ones are reported with their line number. With dry_run=true nothing is created.
+// @Tags imports
+// @Accept plain
+// @Produce json
+// @Param label_id path string true "Label ID"
+// @Param mapping query string true "Mapping ID or name"
+// @Param dry_run query bool false "Map and validate the rows without creating tracks"
+// @Param request body string true "CSV file"
+// @Success 200 {object} usecase.ImportResult "Dry-run preview"
+// @Success 201 {object} usecase.ImportResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /labels/{label_id}/imports [post]
+func (h *ImportHandler) ImportCSV(c *gin.Context) {
+	mapping := c.Query("mapping")
+	if mapping == "" {
+		apperrors.Respond(c, apperrors.NewValidationError("mapping required", "select an import mapping with ?mapping=<id or name>"))
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	result, err := h.imports.Import(c.Request.Context(), c.Param("label_id"), mapping, body, c.GetString("user_id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "invalid import"))
+		return
+	}
+	if result.DryRun {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"errors"
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobHistoryHandler handles the admin API for the jobs archived from the job
+// queues
+type JobHistoryHandler struct {
+	archive *usecase.JobArchiveUseCase
+}
+
+// NewJobHistoryHandler creates a new job history handler
+func NewJobHistoryHandler(archive *usecase.JobArchiveUseCase) *JobHistoryHandler {
+	return &JobHistoryHandler{archive: archive}
+}
+
+// ListJobHistory returns a page of archived jobs
+// @Summary List job history
+// @Description List the finished background jobs archived from the job queues, newest first
+// @Tags admin
+// @Produce json
+// @Param type query string false "Only jobs of this type"
+// @Param status query string false "Only completed or failed jobs"
+// @Param from query string false "First day the jobs finished, YYYY-MM-DD"
+// @Param to query string false "Last day the jobs finished, YYYY-MM-DD"
+// @Param offset query int false "Jobs to skip"
+// @Param limit query int false "Page size, at most 500 (default 50)"
+// @Success 200 {object} domain.JobHistoryPage
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/jobs/history [get]
+func (h *JobHistoryHandler) ListJobHistory(c *gin.Context) {
+	filter := domain.JobHistoryFilter{
+		Type:   domain.JobType(c.Query("type")),
+		Status: domain.JobStatus(c.Query("status")),
+	}
+	var err error
+	if filter.Offset, err = strconv.Atoi(c.DefaultQuery("offset", "0")); err != nil {
+		h.handleError(c, apperrors.NewValidationError("invalid offset", err.Error()))
+		return
+	}
+	if filter.Limit, err = strconv.Atoi(c.DefaultQuery("limit", "0")); err != nil {
+		h.handleError(c, apperrors.NewValidationError("invalid limit", err.Error()))
+		return
+	}
+	if from := c.Query("from"); from != "" {
+		if filter.From, err = time.Parse(domain.UsageDayFormat, from); err != nil {
+			h.handleError(c, apperrors.NewValidationError("invalid from", "from must be a date like 2006-01-02"))
+			return
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		day, err := time.Parse(domain.UsageDayFormat, to)
+		if err != nil {
+			h.handleError(c, apperrors.NewValidationError("invalid to", "to must be a date like 2006-01-02"))
+			return
+		}
+		filter.To = day.AddDate(0, 0, 1)
+	}
+
+	page, err := h.archive.List(c.Request.Context(), filter)
+	if err != nil {
+		h.handleUseCaseError(c, "failed to list job history", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetJobHistoryReport reports on the archived jobs
+// @Summary Get job history report
+// @Description Get the success rate of each job type per week, Monday to Sunday in UTC, and the job types that ran longest on average
+// @Tags admin
+// @Produce json
+// @Param from query string false "First day, YYYY-MM-DD; defaults to twelve weeks before to"
+// @Param to query string false "Last day, YYYY-MM-DD; defaults to today"
+// @Success 200 {object} domain.JobHistoryReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/jobs/history/report [get]
+func (h *JobHistoryHandler) GetJobHistoryReport(c *gin.Context) {
+	report, err := h.archive.Report(c.Request.Context(), c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.handleUseCaseError(c, "failed to get job history report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *JobHistoryHandler) handleUseCaseError(c *gin.Context, message string, err error) {
+	if errors.Is(err, domain.ErrInvalidInput) {
+		h.handleError(c, apperrors.NewValidationError(message, err.Error()))
+		return
+	}
+	h.handleError(c, apperrors.NewInternalError(message, err))
+}
+
+func (h *JobHistoryHandler) handleError(c *gin.Context, err *apperrors.AppError) {
+	apperrors.Respond(c, err)
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"net/http"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LabelAIConfigHandler manages the AI configurations of labels
+type LabelAIConfigHandler struct {
+	configs *usecase.LabelAIConfigUseCase
+}
+
+// NewLabelAIConfigHandler creates a new label AI config handler
+func NewLabelAIConfigHandler(configs *usecase.LabelAIConfigUseCase) *LabelAIConfigHandler {
+	return &LabelAIConfigHandler{configs: configs}
+}
+
+// LabelAIConfigRequest sets a label's AI configuration
+type LabelAIConfigRequest struct {
+	Provider domain.AIProvider `json:"provider" example:"openai"`
+	// APIKey is stored encrypted; leave it out to keep the stored key
+	APIKey        string   `json:"api_key,omitempty"`
+	Model         string   `json:"model,omitempty" example:"gpt-4o-2024-08-06"`
+	MinConfidence *float64 `json:"min_confidence,omitempty"`
+}
+
+// LabelAIConfigsResponse lists the labels with their own AI configuration
+type LabelAIConfigsResponse struct {
+	Configs []*domain.LabelAIConfig `json:"configs"`
+}
+
+// ListLabelAIConfigs lists the labels' AI configurations
+// @Summary List label AI configurations
+// @Description List the labels that use their own AI configuration rather than the global one. API keys are never returned, only their last characters.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} LabelAIConfigsResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/labels/ai-configs [get]
+func (h *LabelAIConfigHandler) ListLabelAIConfigs(c *gin.Context) {
+	configs, err := h.configs.List(c.Request.Context())
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to list label AI configs"))
+		return
+	}
+	c.JSON(http.StatusOK, LabelAIConfigsResponse{Configs: configs})
+}
+
+// GetLabelAIConfig returns a label's AI configuration
+// @Summary Get label AI configuration
+// @Description Get the AI configuration a label uses instead of the global one. Returns 404 when the label uses the global configuration.
+// @Tags admin
+// @Produce json
+// @Param label_id path string true "Label ID"
+// @Success 200 {object} domain.LabelAIConfig
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/labels/{label_id}/ai-config [get]
+func (h *LabelAIConfigHandler) GetLabelAIConfig(c *gin.Context) {
+	config, err := h.configs.Get(c.Request.Context(), c.Param("label_id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to get label AI config"))
+		return
+	}
+	c.JSON(http.StatusOK, config)
+}
+
+// SetLabelAIConfig sets a label's AI configuration
+// @Summary Set label AI configuration
+// @Description Have a label's tracks enriched with their own provider, API key, model and confidence threshold instead of the global configuration. Fields left out keep the global value, except the API key, which keeps the label's stored key while the provider is unchanged. Label traffic does not fall back to another provider. Changes apply on other instances within a minute.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param label_id path string true "Label ID"
+// @Param request body LabelAIConfigRequest true "AI configuration"
+// @Success 200 {object} domain.LabelAIConfig
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/labels/{label_id}/ai-config [put]
+func (h *LabelAIConfigHandler) SetLabelAIConfig(c *gin.Context) {
+	var req LabelAIConfigRequest
+	if err := bindJSON(c, &req); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	config := &domain.LabelAIConfig{
+		Provider:      req.Provider,
+		APIKey:        req.APIKey,
+		Model:         req.Model,
+		MinConfidence: req.MinConfidence,
+	}
+	if errs := config.Validate(); len(errs) > 0 {
+		apperrors.Respond(c, apperrors.NewFieldValidationError("invalid label AI config", fieldErrors(errs)))
+		return
+	}
+
+	if err := h.configs.Save(c.Request.Context(), c.Param("label_id"), config, c.GetString("user_id")); err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to save label AI config"))
+		return
+	}
+	c.JSON(http.StatusOK, config)
+}
+
+// DeleteLabelAIConfig removes a label's AI configuration
+// @Summary Delete label AI configuration
+// @Description Remove a label's AI configuration and its stored API key, so its tracks use the global configuration again
+// @Tags admin
+// @Param label_id path string true "Label ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/labels/{label_id}/ai-config [delete]
+func (h *LabelAIConfigHandler) DeleteLabelAIConfig(c *gin.Context) {
+	if err := h.configs.Delete(c.Request.Context(), c.Param("label_id")); err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to delete label AI config"))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
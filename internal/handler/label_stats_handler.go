@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LabelStatsHandler serves the track aggregates of labels
+type LabelStatsHandler struct {
+	stats domain.LabelStatsRepository
+}
+
+// NewLabelStatsHandler creates a new label statistics handler
+func NewLabelStatsHandler(stats domain.LabelStatsRepository) *LabelStatsHandler {
+	return &LabelStatsHandler{stats: stats}
+}
+
+// GetLabelStats returns the track aggregates of a label
+// @Summary Get label statistics
+// @Description Count a label's tracks by status, genre and release year, with tracks missing a genre or year under "unknown", and total their duration and stored audio size. enrichment_coverage is the share of tracks with AI metadata and average_confidence the mean AI confidence of those tracks. The aggregates are cached for a few minutes; computed_at tells when they were computed.
+// @Tags labels
+// @Produce json
+// @Param label_id path string true "Label ID"
+// @Success 200 {object} domain.LabelStats
+// @Failure 500 {object} ErrorResponse
+// @Router /labels/{label_id}/stats [get]
+func (h *LabelStatsHandler) GetLabelStats(c *gin.Context) {
+	stats, err := h.stats.LabelStats(c.Request.Context(), c.Param("label_id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.NewDatabaseError("failed to compute label statistics", err))
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
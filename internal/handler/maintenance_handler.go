@@ -0,0 +1,38 @@
+package handler
+
+import (
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/pkg/scheduler"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceHandler handles the admin API for the scheduled maintenance
+// tasks
+type MaintenanceHandler struct {
+	scheduler *scheduler.Scheduler
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(sched *scheduler.Scheduler) *MaintenanceHandler {
+	return &MaintenanceHandler{scheduler: sched}
+}
+
+// GetMaintenanceStatus reports the scheduled maintenance tasks
+// @Summary Get maintenance status
+// @Description Get the scheduled maintenance tasks with their next and latest runs, and whether the replica answering runs them
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.MaintenanceStatus
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/maintenance [get]
+func (h *MaintenanceHandler) GetMaintenanceStatus(c *gin.Context) {
+	status, err := h.scheduler.Status(c.Request.Context())
+	if err != nil {
+		apperrors.Respond(c, apperrors.NewInternalError("failed to get maintenance status", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
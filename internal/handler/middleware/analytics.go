@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"time"
+
+	"metadatatool/internal/pkg/analytics"
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Analytics records an API usage event for every request that matched a route
+func Analytics(recorder analytics.EventRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			return
+		}
+		recorder.Record(analytics.APIUsageEvent{
+			Timestamp:  start,
+			Tenant:     domain.TenantFromContext(c.Request.Context()),
+			Method:     c.Request.Method,
+			Route:      route,
+			Status:     c.Writer.Status(),
+			DurationMs: time.Since(start).Milliseconds(),
+		})
+	}
+}
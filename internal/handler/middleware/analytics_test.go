@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"metadatatool/internal/pkg/analytics"
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedEvents []analytics.Event
+
+func (r *recordedEvents) Record(event analytics.Event) { *r = append(*r, event) }
+
+func TestAnalytics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var events recordedEvents
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(domain.WithTenant(c.Request.Context(), "tenant-1"))
+	}, Analytics(&events))
+	router.GET("/tracks/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tracks/42", nil))
+	// Unmatched routes are not recorded
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	require.Len(t, events, 1)
+	event := events[0].(analytics.APIUsageEvent)
+	assert.Equal(t, "tenant-1", event.Tenant)
+	assert.Equal(t, http.MethodGet, event.Method)
+	assert.Equal(t, "/tracks/:id", event.Route)
+	assert.Equal(t, http.StatusNotFound, event.Status)
+}
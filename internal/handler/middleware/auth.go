@@ -2,7 +2,7 @@ package middleware
 
 import (
 	"metadatatool/internal/pkg/domain"
-	"net/http"
+	apperrors "metadatatool/internal/pkg/errors"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -13,16 +13,18 @@ func Auth(authService domain.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := c.GetHeader("Authorization")
 		if token == "" {
-			c.AbortWithStatus(http.StatusUnauthorized)
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("missing authorization token"))
 			return
 		}
 
 		// Remove "Bearer " prefix if present
 		token = strings.TrimPrefix(token, "Bearer ")
 
-		claims, err := authService.ValidateToken(token)
-		if err != nil {
-			c.AbortWithStatus(http.StatusUnauthorized)
+		// Refresh tokens are only good for getting new tokens, not as
+		// bearer tokens: they live much longer than access tokens
+		claims, err := authService.ValidateToken(c.Request.Context(), token)
+		if err != nil || claims.TokenType == domain.TokenTypeRefresh {
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("invalid or expired token").WithCode(apperrors.CodeInvalidToken))
 			return
 		}
 
@@ -36,13 +38,13 @@ func RequireRole(requiredRole domain.Role) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("role")
 		if !exists {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "No role found"})
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("no role found"))
 			return
 		}
 
 		userRole, ok := role.(domain.Role)
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Invalid role type"})
+			apperrors.Respond(c, apperrors.NewInternalError("invalid role type", nil))
 			return
 		}
 
@@ -54,7 +56,7 @@ func RequireRole(requiredRole domain.Role) gin.HandlerFunc {
 
 		// For other roles, check if they match the required role
 		if userRole != requiredRole {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			apperrors.Respond(c, apperrors.NewForbiddenError("insufficient permissions"))
 			return
 		}
 
@@ -67,7 +69,7 @@ func RequirePermission(permission domain.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		claims, exists := c.Get("claims")
 		if !exists {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "No claims found"})
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("no claims found"))
 			return
 		}
 
@@ -79,7 +81,7 @@ func RequirePermission(permission domain.Permission) gin.HandlerFunc {
 			}
 		}
 
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		apperrors.Respond(c, apperrors.NewForbiddenError("insufficient permissions"))
 	}
 }
 
@@ -88,13 +90,13 @@ func APIKeyAuth(userRepo domain.UserRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		apiKey := c.GetHeader("X-API-Key")
 		if apiKey == "" {
-			c.AbortWithStatus(http.StatusUnauthorized)
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("missing API key"))
 			return
 		}
 
 		user, err := userRepo.GetByAPIKey(c.Request.Context(), apiKey)
 		if err != nil || user == nil {
-			c.AbortWithStatus(http.StatusUnauthorized)
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("invalid API key").WithCode(apperrors.CodeInvalidAPIKey))
 			return
 		}
 
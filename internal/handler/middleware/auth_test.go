@@ -17,6 +17,11 @@ type MockAuthService struct {
 	mock.Mock
 }
 
+func (m *MockAuthService) GenerateToken(ctx context.Context, user *pkgdomain.User) (string, error) {
+	args := m.Called(user)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockAuthService) GenerateTokens(user *pkgdomain.User) (*pkgdomain.TokenPair, error) {
 	args := m.Called(user)
 	if args.Get(0) == nil {
@@ -25,7 +30,7 @@ func (m *MockAuthService) GenerateTokens(user *pkgdomain.User) (*pkgdomain.Token
 	return args.Get(0).(*pkgdomain.TokenPair), args.Error(1)
 }
 
-func (m *MockAuthService) ValidateToken(token string) (*pkgdomain.Claims, error) {
+func (m *MockAuthService) ValidateToken(ctx context.Context, token string) (*pkgdomain.Claims, error) {
 	args := m.Called(token)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -33,14 +38,6 @@ func (m *MockAuthService) ValidateToken(token string) (*pkgdomain.Claims, error)
 	return args.Get(0).(*pkgdomain.Claims), args.Error(1)
 }
 
-func (m *MockAuthService) RefreshToken(refreshToken string) (*pkgdomain.TokenPair, error) {
-	args := m.Called(refreshToken)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*pkgdomain.TokenPair), args.Error(1)
-}
-
 func (m *MockAuthService) HashPassword(password string) (string, error) {
 	args := m.Called(password)
 	return args.String(0), args.Error(1)
@@ -56,19 +53,6 @@ func (m *MockAuthService) GenerateAPIKey() (string, error) {
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockAuthService) HasPermission(role pkgdomain.Role, permission pkgdomain.Permission) bool {
-	args := m.Called(role, permission)
-	return args.Bool(0)
-}
-
-func (m *MockAuthService) GetPermissions(role pkgdomain.Role) []pkgdomain.Permission {
-	args := m.Called(role)
-	if args.Get(0) == nil {
-		return nil
-	}
-	return args.Get(0).([]pkgdomain.Permission)
-}
-
 // MockUserRepository is a mock implementation of pkgdomain.UserRepository
 type MockUserRepository struct {
 	mock.Mock
@@ -167,6 +151,20 @@ func TestAuth_Middleware(t *testing.T) {
 			token:          "invalid-token",
 			expectedStatus: http.StatusUnauthorized,
 		},
+		{
+			name: "refresh token",
+			setupAuth: func() {
+				claims := &pkgdomain.Claims{
+					UserID:    "test-user",
+					Role:      pkgdomain.RoleUser,
+					TokenType: pkgdomain.TokenTypeRefresh,
+					FamilyID:  "family-1",
+				}
+				authService.On("ValidateToken", "refresh-token").Return(claims, nil)
+			},
+			token:          "refresh-token",
+			expectedStatus: http.StatusUnauthorized,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"errors"
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueueBackpressure answers 429 while any of topics rejects new messages, so
+// clients slow down until its consumers catch up. It belongs on the routes
+// whose requests publish to those topics; every successful request is
+// counted as a publish.
+func QueueBackpressure(admitter domain.QueueAdmitter, retryAfter time.Duration, topics ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, topic := range topics {
+			if _, err := admitter.Admit(topic, domain.PriorityLow); errors.Is(err, domain.ErrQueueBackpressure) {
+				c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+				apperrors.Respond(c, apperrors.NewRateLimitError("too many pending changes, retry later").WithCode(apperrors.CodeQueueBackpressure))
+				return
+			}
+		}
+
+		c.Next()
+
+		if c.Writer.Status() < http.StatusBadRequest {
+			for _, topic := range topics {
+				admitter.RecordPublish(topic)
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// CompressionConfig selects the responses Compression compresses
+type CompressionConfig struct {
+	// MinSize is the smallest body that is compressed; smaller bodies are not
+	// worth the overhead
+	MinSize int
+	// ContentTypes lists the media types that may be compressed. A type
+	// ending in "/" matches every subtype, such as "text/". Audio and other
+	// already compressed types should not be listed.
+	ContentTypes []string
+}
+
+// Compression compresses response bodies with brotli or gzip, whichever the
+// Accept-Encoding header of the request prefers. Bodies are held back until
+// MinSize bytes are written, so small responses are sent as they are.
+// Responses that already have a Content-Encoding, or whose type is not in
+// ContentTypes, are never compressed.
+func Compression(cfg CompressionConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encoding := acceptedEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		w := &compressWriter{ResponseWriter: c.Writer, cfg: cfg, encoding: encoding}
+		c.Writer = w
+		defer w.close()
+		c.Next()
+	}
+}
+
+// acceptedEncoding returns "br" or "gzip", whichever the Accept-Encoding
+// header weighs highest, preferring brotli on a tie. It is empty when the
+// client accepts neither.
+func acceptedEncoding(header string) string {
+	weights := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil {
+				weight = v
+			}
+		}
+		weights[strings.ToLower(strings.TrimSpace(name))] = weight
+	}
+
+	best, bestWeight := "", 0.0
+	for _, encoding := range []string{"br", "gzip"} {
+		weight, ok := weights[encoding]
+		if !ok {
+			weight, ok = weights["*"]
+		}
+		if ok && weight > bestWeight {
+			best, bestWeight = encoding, weight
+		}
+	}
+	return best
+}
+
+// compressWriter buffers the start of a response body until it knows
+// whether to compress it
+type compressWriter struct {
+	gin.ResponseWriter
+	cfg      CompressionConfig
+	encoding string
+
+	buf         []byte
+	encoder     io.WriteCloser
+	passthrough bool
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	switch {
+	case w.encoder != nil:
+		return w.encoder.Write(data)
+	case w.passthrough:
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf = append(w.buf, data...)
+	if len(w.buf) >= w.cfg.MinSize {
+		if err := w.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends what has been written so far, compressed if the body may be
+func (w *compressWriter) Flush() {
+	if w.encoder == nil && !w.passthrough {
+		_ = w.start(true)
+	}
+	if flusher, ok := w.encoder.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// start decides whether the body is compressed and writes what is buffered
+func (w *compressWriter) start(large bool) error {
+	buf := w.buf
+	w.buf = nil
+
+	compressible := w.compressible()
+	if compressible {
+		w.Header().Add("Vary", "Accept-Encoding")
+	}
+	if !compressible || !large {
+		w.passthrough = true
+		if len(buf) == 0 {
+			return nil
+		}
+		_, err := w.ResponseWriter.Write(buf)
+		return err
+	}
+
+	w.Header().Set("Content-Encoding", w.encoding)
+	w.Header().Del("Content-Length")
+	if w.encoding == "br" {
+		w.encoder = brotli.NewWriterLevel(w.ResponseWriter, brotli.DefaultCompression)
+	} else {
+		w.encoder, _ = gzip.NewWriterLevel(w.ResponseWriter, gzip.DefaultCompression)
+	}
+	_, err := w.encoder.Write(buf)
+	return err
+}
+
+// compressible reports whether the response may be compressed
+func (w *compressWriter) compressible() bool {
+	status := w.ResponseWriter.Status()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified ||
+		status == http.StatusPartialContent {
+		return false
+	}
+	if w.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	for _, allowed := range w.cfg.ContentTypes {
+		if mediaType == allowed || (strings.HasSuffix(allowed, "/") && strings.HasPrefix(mediaType, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// close finishes the body once the handlers are done
+func (w *compressWriter) close() {
+	switch {
+	case w.encoder != nil:
+		_ = w.encoder.Close()
+	case !w.passthrough && len(w.buf) > 0:
+		_ = w.start(false)
+	}
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptedEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"br", "br"},
+		{"gzip, br", "br"},
+		{"br;q=0.5, gzip", "gzip"},
+		{"br;q=0.8, gzip;q=0.8", "br"},
+		{"GZIP", "gzip"},
+		{"*", "br"},
+		{"gzip;q=0.5, *;q=0.1", "gzip"},
+		{"br;q=0, gzip;q=0", ""},
+		{"deflate, compress", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, acceptedEncoding(tt.header))
+		})
+	}
+}
+
+func TestCompression(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	large := strings.Repeat("metadata ", 200)
+
+	router := gin.New()
+	router.Use(Compression(CompressionConfig{MinSize: 1024, ContentTypes: []string{"application/json", "text/"}}))
+	router.GET("/body", func(c *gin.Context) {
+		size, _ := strconv.Atoi(c.Query("size"))
+		contentType := c.DefaultQuery("type", "application/json; charset=utf-8")
+		c.Header("Content-Length", strconv.Itoa(size))
+		if c.Query("encoded") != "" {
+			c.Header("Content-Encoding", "gzip")
+		}
+		c.Data(http.StatusOK, contentType, []byte(large[:size]))
+	})
+	router.HEAD("/body", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/empty", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	do := func(method, path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) string {
+		var r io.Reader = w.Body
+		switch w.Header().Get("Content-Encoding") {
+		case "gzip":
+			gz, err := gzip.NewReader(w.Body)
+			require.NoError(t, err)
+			r = gz
+		case "br":
+			r = brotli.NewReader(w.Body)
+		}
+		body, err := io.ReadAll(r)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	tests := []struct {
+		name           string
+		size           int
+		query          string
+		acceptEncoding string
+		encoding       string
+		vary           bool
+	}{
+		{"gzip", 1500, "", "gzip", "gzip", true},
+		{"brotli", 1500, "", "br", "br", true},
+		{"brotli preferred", 1500, "", "gzip, deflate, br", "br", true},
+		{"weights win over preference", 1500, "", "br;q=0.2, gzip", "gzip", true},
+		{"nothing accepted", 1500, "", "", "", false},
+		{"unsupported encoding", 1500, "", "deflate", "", false},
+		{"at the minimum size", 1024, "", "gzip", "gzip", true},
+		{"below the minimum size", 1023, "", "gzip", "", true},
+		{"subtype of an allowed type", 1500, "&type=text/csv", "gzip", "gzip", true},
+		{"type not allowed", 1500, "&type=audio/mpeg", "gzip", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(http.MethodGet, fmt.Sprintf("/body?size=%d%s", tt.size, tt.query), tt.acceptEncoding)
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.encoding, w.Header().Get("Content-Encoding"))
+			if tt.vary {
+				assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+			} else {
+				assert.Empty(t, w.Header().Get("Vary"))
+			}
+			assert.Equal(t, large[:tt.size], decode(t, w))
+			if tt.encoding != "" {
+				assert.Empty(t, w.Header().Get("Content-Length"), "the length of the uncompressed body must not be sent")
+				assert.Less(t, w.Body.Len(), tt.size)
+			} else {
+				assert.Equal(t, strconv.Itoa(tt.size), w.Header().Get("Content-Length"))
+			}
+		})
+	}
+
+	t.Run("already encoded", func(t *testing.T) {
+		w := do(http.MethodGet, "/body?size=1500&encoded=1", "gzip")
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Empty(t, w.Header().Get("Vary"))
+		assert.Equal(t, large[:1500], w.Body.String(), "the body is passed on as it is")
+	})
+
+	t.Run("no body", func(t *testing.T) {
+		w := do(http.MethodGet, "/empty", "gzip")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("HEAD is not compressed", func(t *testing.T) {
+		w := do(http.MethodHead, "/body", "gzip")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+	})
+}
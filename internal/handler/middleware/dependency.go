@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	apperrors "metadatatool/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Dependency reports whether a service the handlers rely on is reachable
+type Dependency interface {
+	Name() string
+	Available() bool
+}
+
+// RequireDependency answers 503 while dep is unavailable
+func RequireDependency(dep Dependency) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !dep.Available() {
+			c.Header("Retry-After", "10")
+			apperrors.Respond(c, apperrors.NewUnavailableError(dep.Name()+" is temporarily unavailable").WithCode(apperrors.CodeDependencyUnavailable))
+			return
+		}
+		c.Next()
+	}
+}
+
+// WhenAvailable runs next only while dep is available and skips it otherwise
+func WhenAvailable(dep Dependency, next gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !dep.Available() {
+			c.Next()
+			return
+		}
+		next(c)
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubDependency struct {
+	available bool
+}
+
+func (d *stubDependency) Name() string    { return "redis" }
+func (d *stubDependency) Available() bool { return d.available }
+
+func TestRequireDependency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dep := &stubDependency{}
+
+	router := gin.New()
+	router.GET("/test", RequireDependency(dep), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	dep.available = true
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestWhenAvailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dep := &stubDependency{}
+	ran := false
+
+	router := gin.New()
+	router.Use(WhenAvailable(dep, func(c *gin.Context) { ran = true }))
+	router.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, ran)
+
+	dep.available = true
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+	assert.True(t, ran)
+}
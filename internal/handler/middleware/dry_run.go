@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DryRunParam is the query parameter requesting a dry run
+const DryRunParam = "dry_run"
+
+// DryRunKey is the context key set on dry-run requests
+const DryRunKey = "dry_run"
+
+// DryRun reads ?dry_run=true. Routes, given as "METHOD /full/path" such as
+// "POST /api/v1/tracks/bulk-edit", compute the effect of a dry-run request
+// without committing it; on any other route dry_run is refused rather than
+// ignored, so a dry run can never change anything by mistake.
+func DryRun(routes ...string) gin.HandlerFunc {
+	supported := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		supported[route] = true
+	}
+
+	return func(c *gin.Context) {
+		raw, ok := c.GetQuery(DryRunParam)
+		if !ok {
+			c.Next()
+			return
+		}
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			apperrors.Respond(c, apperrors.NewValidationError("invalid dry_run", fmt.Sprintf("dry_run must be true or false, not %q", raw)))
+			return
+		}
+		if !dryRun {
+			c.Next()
+			return
+		}
+		if !supported[c.Request.Method+" "+c.FullPath()] {
+			apperrors.Respond(c, apperrors.NewValidationError("dry run not supported", "this endpoint does not support dry_run"))
+			return
+		}
+
+		c.Set(DryRunKey, true)
+		c.Request = c.Request.WithContext(domain.WithDryRun(c.Request.Context()))
+		c.Next()
+	}
+}
+
+// IsDryRun reports whether the request is a dry run
+func IsDryRun(c *gin.Context) bool {
+	return c.GetBool(DryRunKey)
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(DryRun("POST /tracks/:id/delete"))
+	handler := func(c *gin.Context) {
+		c.String(http.StatusOK, strconv.FormatBool(IsDryRun(c) && domain.DryRunFromContext(c.Request.Context())))
+	}
+	router.POST("/tracks/:id/delete", handler)
+	router.POST("/tracks", handler)
+
+	tests := []struct {
+		name   string
+		target string
+		status int
+		body   string
+	}{
+		{"supported route", "/tracks/t1/delete?dry_run=true", http.StatusOK, "true"},
+		{"not requested", "/tracks/t1/delete", http.StatusOK, "false"},
+		{"explicitly off", "/tracks?dry_run=false", http.StatusOK, "false"},
+		{"unsupported route", "/tracks?dry_run=true", http.StatusBadRequest, ""},
+		{"invalid value", "/tracks/t1/delete?dry_run=maybe", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.target, nil))
+			assert.Equal(t, tt.status, w.Code)
+			if tt.body != "" {
+				assert.Equal(t, tt.body, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"log"
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client's key for a request
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed from an earlier request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength bounds the keys accepted from clients
+	maxIdempotencyKeyLength = 255
+	// idempotencyLockTTL bounds how long a request in progress holds its key
+	// should the process die before answering it
+	idempotencyLockTTL = 15 * time.Minute
+)
+
+// Idempotency answers repeats of a request carrying an Idempotency-Key
+// header with the response to the first one for ttl, so that clients can
+// retry creating requests without creating duplicates. Keys are scoped to the
+// authenticated user and the path; requests without a user are handled as if
+// they carried no key, so that no one is answered with another user's
+// response. A repeat whose body differs from the first request's is refused
+// with 422. Only successful responses are recorded; a request that failed can
+// be retried with the same key. When the store fails the request is handled
+// as if it carried no key.
+func Idempotency(store domain.IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			apperrors.Respond(c, apperrors.NewValidationError("idempotency key is too long", ""))
+			return
+		}
+
+		userID := idempotencyUser(c)
+		if userID == "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		scoped := userID + ":" + c.Request.Method + ":" + c.Request.URL.Path + ":" + key
+		response, err := store.Reserve(ctx, scoped, idempotencyLockTTL)
+		switch {
+		case errors.Is(err, domain.ErrIdempotencyKeyInUse):
+			apperrors.Respond(c, apperrors.NewConflictError("a request with this idempotency key is in progress", "").WithCode(apperrors.CodeIdempotencyKeyInUse))
+			return
+		case err != nil:
+			log.Printf("Idempotency is unavailable: %v", err)
+			c.Next()
+			return
+		case response != nil:
+			// The body is only read to fingerprint it, as the handler does not run
+			fingerprint := sha256.New()
+			if _, err := io.Copy(fingerprint, c.Request.Body); err != nil {
+				apperrors.Respond(c, apperrors.NewValidationError("failed to read request body", err.Error()))
+				return
+			}
+			if response.RequestHash != "" && response.RequestHash != hex.EncodeToString(fingerprint.Sum(nil)) {
+				apperrors.Respond(c, apperrors.NewUnprocessableError("idempotency key was used for a different request", "").WithCode(apperrors.CodeIdempotencyKeyReused))
+				return
+			}
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(response.StatusCode, response.ContentType, response.Body)
+			c.Abort()
+			return
+		}
+
+		body := &hashingReader{ReadCloser: c.Request.Body, hash: sha256.New()}
+		c.Request.Body = body
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < http.StatusOK || status >= http.StatusMultipleChoices {
+			if err := store.Release(ctx, scoped); err != nil {
+				log.Printf("Failed to release idempotency key: %v", err)
+			}
+			return
+		}
+		requestHash, err := body.sum()
+		if err != nil {
+			log.Printf("Failed to fingerprint idempotent request: %v", err)
+			if err := store.Release(ctx, scoped); err != nil {
+				log.Printf("Failed to release idempotency key: %v", err)
+			}
+			return
+		}
+		err = store.Save(ctx, scoped, &domain.IdempotentResponse{
+			StatusCode:  status,
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+			RequestHash: requestHash,
+		}, ttl)
+		if err != nil {
+			log.Printf("Failed to record idempotent response: %v", err)
+		}
+	}
+}
+
+// idempotencyUser returns the user an idempotency key belongs to: the
+// subject of the bearer token, or else the user of the session
+func idempotencyUser(c *gin.Context) string {
+	if claims, ok := c.Get("claims"); ok {
+		if claims, ok := claims.(*domain.Claims); ok && claims.UserID != "" {
+			return claims.UserID
+		}
+	}
+	return c.GetString("user_id")
+}
+
+// hashingReader hashes the request body as the handler reads it
+type hashingReader struct {
+	io.ReadCloser
+	hash hash.Hash
+}
+
+func (r *hashingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	return n, err
+}
+
+// sum returns the hash of the whole body, reading what the handler left
+func (r *hashingReader) sum() (string, error) {
+	if _, err := io.Copy(r.hash, r.ReadCloser); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(r.hash.Sum(nil)), nil
+}
+
+// responseRecorder keeps a copy of the response body written through it
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
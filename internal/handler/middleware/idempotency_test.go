@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"context"
+	"metadatatool/internal/pkg/domain"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryIdempotencyStore struct {
+	mu        sync.Mutex
+	responses map[string]*domain.IdempotentResponse
+}
+
+func (s *memoryIdempotencyStore) Reserve(ctx context.Context, key string, ttl time.Duration) (*domain.IdempotentResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	response, ok := s.responses[key]
+	if !ok {
+		s.responses[key] = nil
+		return nil, nil
+	}
+	if response == nil {
+		return nil, domain.ErrIdempotencyKeyInUse
+	}
+	return response, nil
+}
+
+func (s *memoryIdempotencyStore) Save(ctx context.Context, key string, response *domain.IdempotentResponse, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[key] = response
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.responses, key)
+	return nil
+}
+
+// withUser authenticates requests as the user named in the X-Test-User
+// header, the way Auth does for bearer tokens
+func withUser(c *gin.Context) {
+	if userID := c.GetHeader("X-Test-User"); userID != "" {
+		c.Set("claims", &domain.Claims{UserID: userID})
+	}
+}
+
+// idempotencyRouter counts the tracks created through POST /tracks
+func idempotencyRouter(store domain.IdempotencyStore, created *int) *gin.Engine {
+	router := gin.New()
+	router.Use(withUser)
+	router.POST("/tracks", Idempotency(store, time.Hour), func(c *gin.Context) {
+		*created++
+		c.JSON(http.StatusCreated, gin.H{"created": *created})
+	})
+	return router
+}
+
+func postIdempotent(router *gin.Engine, userID, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/tracks", strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, key)
+	if userID != "" {
+		req.Header.Set("X-Test-User", userID)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &memoryIdempotencyStore{responses: make(map[string]*domain.IdempotentResponse)}
+	created := 0
+	status := http.StatusCreated
+
+	router := gin.New()
+	router.Use(withUser)
+	router.POST("/tracks", Idempotency(store, time.Hour), func(c *gin.Context) {
+		created++
+		c.JSON(status, gin.H{"created": created})
+	})
+	post := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/tracks", nil)
+		req.Header.Set("X-Test-User", "user-1")
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := post("abc")
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.JSONEq(t, `{"created":1}`, first.Body.String())
+
+	// A repeat is answered with the original response
+	repeat := post("abc")
+	assert.Equal(t, http.StatusCreated, repeat.Code)
+	assert.JSONEq(t, `{"created":1}`, repeat.Body.String())
+	assert.Equal(t, "true", repeat.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 1, created)
+
+	// Requests without a key are always handled
+	assert.JSONEq(t, `{"created":2}`, post("").Body.String())
+
+	// Failed requests are not recorded and can be retried
+	status = http.StatusInternalServerError
+	assert.Equal(t, http.StatusInternalServerError, post("def").Code)
+	status = http.StatusCreated
+	assert.JSONEq(t, `{"created":4}`, post("def").Body.String())
+}
+
+func TestIdempotency_KeyInUse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &memoryIdempotencyStore{responses: map[string]*domain.IdempotentResponse{"user-1:POST:/tracks:abc": nil}}
+
+	router := gin.New()
+	router.Use(withUser)
+	router.POST("/tracks", Idempotency(store, time.Hour), func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	req := httptest.NewRequest(http.MethodPost, "/tracks", nil)
+	req.Header.Set(IdempotencyKeyHeader, "abc")
+	req.Header.Set("X-Test-User", "user-1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestIdempotency_ScopedToUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &memoryIdempotencyStore{responses: make(map[string]*domain.IdempotentResponse)}
+	created := 0
+	router := idempotencyRouter(store, &created)
+
+	assert.JSONEq(t, `{"created":1}`, postIdempotent(router, "user-1", "abc", `{}`).Body.String())
+
+	// Another user with the same key gets their own response
+	other := postIdempotent(router, "user-2", "abc", `{}`)
+	assert.JSONEq(t, `{"created":2}`, other.Body.String())
+	assert.Empty(t, other.Header().Get(IdempotentReplayedHeader))
+
+	// The session user is used when there is no bearer token
+	router.POST("/session/tracks", func(c *gin.Context) { c.Set("user_id", "user-3") }, Idempotency(store, time.Hour), func(c *gin.Context) {
+		created++
+		c.JSON(http.StatusCreated, gin.H{"created": created})
+	})
+	req := httptest.NewRequest(http.MethodPost, "/session/tracks", strings.NewReader(`{}`))
+	req.Header.Set(IdempotencyKeyHeader, "abc")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Contains(t, store.responses, "user-3:POST:/session/tracks:abc")
+
+	// Without a user nothing is recorded or replayed
+	assert.JSONEq(t, `{"created":4}`, postIdempotent(router, "", "abc", `{}`).Body.String())
+	assert.JSONEq(t, `{"created":5}`, postIdempotent(router, "", "abc", `{}`).Body.String())
+	assert.Len(t, store.responses, 3)
+}
+
+func TestIdempotency_DifferentBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &memoryIdempotencyStore{responses: make(map[string]*domain.IdempotentResponse)}
+	created := 0
+	router := idempotencyRouter(store, &created)
+
+	assert.Equal(t, http.StatusCreated, postIdempotent(router, "user-1", "abc", `{"title":"One"}`).Code)
+
+	// The same key with another body is refused rather than replayed
+	w := postIdempotent(router, "user-1", "abc", `{"title":"Two"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "IDEMPOTENCY_KEY_REUSED")
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+
+	// The original body is still replayed
+	repeat := postIdempotent(router, "user-1", "abc", `{"title":"One"}`)
+	assert.Equal(t, http.StatusCreated, repeat.Code)
+	assert.Equal(t, "true", repeat.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 1, created)
+}
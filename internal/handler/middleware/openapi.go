@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+var ginParamRe = regexp.MustCompile(`:(\w+)`)
+
+// OpenAPIValidator rejects JSON request bodies that do not match the schema
+// declared for the route in the OpenAPI document. basePath is the prefix the
+// document's paths are mounted under.
+func OpenAPIValidator(doc *openapi.Document, basePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := ginParamRe.ReplaceAllString(strings.TrimPrefix(c.FullPath(), basePath), "{$1}")
+		op, ok := doc.Operation(c.Request.Method, path)
+		if !ok || op.RequestBody == nil {
+			c.Next()
+			return
+		}
+		media, ok := op.RequestBody.Content["application/json"]
+		if !ok || c.ContentType() != "application/json" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abortValidation(c, "failed to read request body", nil)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if len(bytes.TrimSpace(body)) == 0 {
+			if op.RequestBody.Required {
+				abortValidation(c, "request body is required", []apperrors.FieldError{
+					{Code: "required", Message: "request body is required"},
+				})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			abortValidation(c, "request body is not valid JSON", []apperrors.FieldError{
+				{Code: apperrors.FieldCodeInvalidJSON, Message: "request body is not valid JSON"},
+			})
+			return
+		}
+
+		if errs := doc.Validate(media.Schema, value); len(errs) > 0 {
+			fields := make([]apperrors.FieldError, len(errs))
+			for i, e := range errs {
+				fields[i] = apperrors.FieldError{Field: strings.TrimPrefix(strings.TrimPrefix(e.Path, "$"), "."), Code: e.Code, Message: e.Message}
+			}
+			abortValidation(c, "request body does not match the API schema", fields)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func abortValidation(c *gin.Context, message string, fields []apperrors.FieldError) {
+	apperrors.Respond(c, apperrors.NewFieldValidationError(message, fields))
+}
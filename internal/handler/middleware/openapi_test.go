@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIValidator(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+
+	router := gin.New()
+	api := router.Group("/api/v1")
+	api.Use(OpenAPIValidator(doc, "/api/v1"))
+	api.POST("/tracks/bulk-edit", func(c *gin.Context) {
+		var body map[string]interface{}
+		require.NoError(t, c.ShouldBindJSON(&body))
+		c.Status(http.StatusAccepted)
+	})
+	api.GET("/tracks/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"valid body reaches handler", http.MethodPost, "/api/v1/tracks/bulk-edit", `{"track_ids":["a"],"dry_run":true}`, http.StatusAccepted},
+		{"invalid body is rejected", http.MethodPost, "/api/v1/tracks/bulk-edit", `{"track_ids":"a"}`, http.StatusBadRequest},
+		{"malformed JSON is rejected", http.MethodPost, "/api/v1/tracks/bulk-edit", `{`, http.StatusBadRequest},
+		{"routes without a body are untouched", http.MethodGet, "/api/v1/tracks/123", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusBadRequest {
+				assert.Contains(t, w.Body.String(), "VALIDATION_ERROR")
+			}
+		})
+	}
+
+	t.Run("mismatches are reported by field", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tracks/bulk-edit", strings.NewReader(`{"track_ids":"a"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var body struct {
+			Error struct {
+				Fields []apperrors.FieldError `json:"fields"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, []apperrors.FieldError{
+			{Field: "track_ids", Code: "invalid_type", Message: "must be array, got string"},
+		}, body.Error.Fields)
+	})
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PublicAPIKeyHeader carries the key of public catalog API requests
+const PublicAPIKeyHeader = "X-API-Key"
+
+// publicAPIKeyContext is where PublicAPIKey stores the request's key
+const publicAPIKeyContext = "public_api_key"
+
+// PublicAPIGate authenticates public API keys and counts their requests
+type PublicAPIGate interface {
+	// Authenticate returns the key of a request, or
+	// domain.ErrPublicAPIKeyNotFound
+	Authenticate(ctx context.Context, raw string) (*domain.PublicAPIKey, error)
+	// Take counts a request by key, returning
+	// domain.ErrPublicAPIQuotaExceeded when its tier's quota is used up
+	Take(ctx context.Context, key *domain.PublicAPIKey) (*domain.PublicAPIAllowance, error)
+}
+
+// PublicAPIKey authenticates public catalog API requests by their key and
+// holds them to the quotas of the key's tier. The per-minute quota is
+// reported in the X-RateLimit headers and the daily quota in the X-Quota
+// headers. Requests let through are metered to the key's label when usage
+// is set. Requests are refused while quotas cannot be counted.
+func PublicAPIKey(gate PublicAPIGate, usage domain.UsageRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.GetHeader(PublicAPIKeyHeader)
+		if raw == "" {
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("missing API key"))
+			return
+		}
+
+		ctx := c.Request.Context()
+		key, err := gate.Authenticate(ctx, raw)
+		if err != nil {
+			if errors.Is(err, domain.ErrPublicAPIKeyNotFound) {
+				apperrors.Respond(c, apperrors.NewUnauthorizedError("invalid API key").WithCode(apperrors.CodeInvalidAPIKey))
+				return
+			}
+			apperrors.Respond(c, apperrors.NewInternalError("failed to check API key", err))
+			return
+		}
+
+		allowance, err := gate.Take(ctx, key)
+		if allowance != nil {
+			setAllowanceHeaders(c, allowance)
+		}
+		if errors.Is(err, domain.ErrPublicAPIQuotaExceeded) {
+			reset := allowance.MinuteReset
+			if allowance.DayRemaining == 0 {
+				reset = allowance.DayReset
+			}
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(reset).Seconds())+1))
+			apperrors.Respond(c, apperrors.NewRateLimitError("API quota exceeded").WithCode(apperrors.CodeQuotaExceeded))
+			return
+		}
+		if err != nil {
+			log.Printf("Public API quota is unavailable: %v", err)
+			apperrors.Respond(c, apperrors.NewUnavailableError("public API is temporarily unavailable"))
+			return
+		}
+
+		if usage != nil {
+			usage.RecordUsage(ctx, key.LabelID, domain.UsagePublicAPIRequests, 1)
+		}
+		c.Set(publicAPIKeyContext, key)
+		c.Next()
+	}
+}
+
+// PublicAPIKeyFromContext returns the key PublicAPIKey authenticated
+func PublicAPIKeyFromContext(c *gin.Context) (*domain.PublicAPIKey, bool) {
+	key, ok := c.Get(publicAPIKeyContext)
+	if !ok {
+		return nil, false
+	}
+	apiKey, ok := key.(*domain.PublicAPIKey)
+	return apiKey, ok
+}
+
+// setAllowanceHeaders reports the limited windows of allowance
+func setAllowanceHeaders(c *gin.Context, allowance *domain.PublicAPIAllowance) {
+	if limit := allowance.Tier.RequestsPerMinute; limit > 0 {
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(allowance.MinuteRemaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(allowance.MinuteReset.Unix(), 10))
+	}
+	if limit := allowance.Tier.RequestsPerDay; limit > 0 {
+		c.Header("X-Quota-Limit", strconv.Itoa(limit))
+		c.Header("X-Quota-Remaining", strconv.Itoa(allowance.DayRemaining))
+		c.Header("X-Quota-Reset", strconv.FormatInt(allowance.DayReset.Unix(), 10))
+	}
+}
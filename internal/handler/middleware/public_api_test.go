@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubPublicAPIGate struct {
+	key      *domain.PublicAPIKey
+	exceeded bool
+}
+
+func (g *stubPublicAPIGate) Authenticate(_ context.Context, raw string) (*domain.PublicAPIKey, error) {
+	if raw != "good" {
+		return nil, domain.ErrPublicAPIKeyNotFound
+	}
+	return g.key, nil
+}
+
+func (g *stubPublicAPIGate) Take(_ context.Context, key *domain.PublicAPIKey) (*domain.PublicAPIAllowance, error) {
+	allowance := &domain.PublicAPIAllowance{
+		Tier:            domain.PublicAPITier{Name: key.Tier, RequestsPerMinute: 10, RequestsPerDay: 100},
+		MinuteRemaining: 9,
+		DayRemaining:    99,
+		MinuteReset:     time.Now().Add(30 * time.Second),
+		DayReset:        time.Now().Add(time.Hour),
+	}
+	if g.exceeded {
+		allowance.DayRemaining = 0
+		return allowance, domain.ErrPublicAPIQuotaExceeded
+	}
+	return allowance, nil
+}
+
+type countingUsageRecorder struct {
+	counts map[string]int64
+}
+
+func (r *countingUsageRecorder) RecordUsage(_ context.Context, labelID string, metric domain.UsageMetric, n int64) {
+	r.counts[labelID+":"+string(metric)] += n
+}
+
+func TestPublicAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	gate := &stubPublicAPIGate{key: &domain.PublicAPIKey{ID: "k1", LabelID: "label-a", Tier: domain.PublicAPITierFree}}
+	usage := &countingUsageRecorder{counts: make(map[string]int64)}
+
+	router := gin.New()
+	router.GET("/tracks", PublicAPIKey(gate, usage), func(c *gin.Context) {
+		key, ok := PublicAPIKeyFromContext(c)
+		assert.True(t, ok)
+		c.String(http.StatusOK, key.LabelID)
+	})
+	request := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/tracks", nil)
+		if key != "" {
+			req.Header.Set(PublicAPIKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, request("").Code)
+	assert.Equal(t, http.StatusUnauthorized, request("bad").Code)
+
+	w := request("good")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "label-a", w.Body.String())
+	assert.Equal(t, "10", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "9", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "100", w.Header().Get("X-Quota-Limit"))
+	assert.Equal(t, "99", w.Header().Get("X-Quota-Remaining"))
+
+	// Refused requests wait for the exhausted window and are not metered
+	gate.exceeded = true
+	w = request("good")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-Quota-Remaining"))
+	assert.Contains(t, []string{"3600", "3601"}, w.Header().Get("Retry-After"))
+
+	assert.Equal(t, map[string]int64{"label-a:public_api_requests": 1}, usage.counts)
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apperrors "metadatatool/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SecurityHeadersConfig selects the headers SecurityHeaders sets
+type SecurityHeadersConfig struct {
+	// HSTSMaxAge is how long browsers only use HTTPS for the host; zero
+	// leaves Strict-Transport-Security out
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubdomains bool
+	// FrameOptions is the X-Frame-Options value, DENY or SAMEORIGIN
+	FrameOptions string
+}
+
+// SecurityHeaders sets Strict-Transport-Security, X-Content-Type-Options
+// and X-Frame-Options on every response
+func SecurityHeaders(cfg SecurityHeadersConfig) gin.HandlerFunc {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(int(cfg.HSTSMaxAge.Seconds()))
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		if hsts != "" {
+			h.Set("Strict-Transport-Security", hsts)
+		}
+		h.Set("X-Content-Type-Options", "nosniff")
+		if cfg.FrameOptions != "" {
+			h.Set("X-Frame-Options", cfg.FrameOptions)
+		}
+		c.Next()
+	}
+}
+
+const (
+	// CSRFHeader carries the CSRF token in requests and responses
+	CSRFHeader = "X-CSRF-Token"
+	// CSRFCookie holds the CSRF token where the web app's scripts can read it
+	CSRFCookie = "csrf_token"
+)
+
+// CSRFConfig configures CSRF
+type CSRFConfig struct {
+	// Secret keys the tokens derived from session IDs
+	Secret string
+	// LoginPaths are the paths that start a session, such as login and
+	// registration. They are checked without a session too, against a
+	// pre-session token, so that no other site can sign a browser in.
+	LoginPaths   []string
+	CookiePath   string
+	CookieDomain string
+	CookieSecure bool
+}
+
+// CSRFToken returns the CSRF token of a session. Tokens are derived from
+// the session ID, so they need no storage and change with the session.
+func CSRFToken(secret, sessionID string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("csrf:" + sessionID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// preSessionCSRFToken returns a new token for a browser without a session:
+// a random nonce signed with secret
+func preSessionCSRFToken(secret string) string {
+	nonce := make([]byte, 16)
+	_, _ = rand.Read(nonce)
+	return signPreSessionNonce(secret, hex.EncodeToString(nonce))
+}
+
+func signPreSessionNonce(secret, nonce string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("csrf-login:" + nonce))
+	return nonce + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// validPreSessionCSRFToken reports whether token was issued with secret
+func validPreSessionCSRFToken(secret, token string) bool {
+	nonce, _, ok := strings.Cut(token, ".")
+	return ok && nonce != "" && hmac.Equal([]byte(token), []byte(signPreSessionNonce(secret, nonce)))
+}
+
+// CSRF protects requests authenticated by the session cookie, which
+// browsers send along with requests forged by other sites. It must run after
+// Session. The session's token is returned in the X-CSRF-Token header and
+// a csrf_token cookie; requests that change state must send it back in the
+// X-CSRF-Token header or are refused with 403.
+//
+// Requests without a session get a signed pre-session token the same way,
+// and requests to LoginPaths must send it back, so that a forged form cannot
+// sign the browser in to the attacker's account. Other requests without a
+// session are left alone.
+func CSRF(cfg CSRFConfig) gin.HandlerFunc {
+	loginPaths := make(map[string]bool, len(cfg.LoginPaths))
+	for _, path := range cfg.LoginPaths {
+		loginPaths[path] = true
+	}
+
+	return func(c *gin.Context) {
+		var token string
+		sessionID := c.GetString("session_id")
+		if sessionID != "" {
+			token = CSRFToken(cfg.Secret, sessionID)
+		} else if _, bearer := c.Get("claims"); bearer {
+			// Bearer tokens are not sent by browsers on their own
+			c.Next()
+			return
+		} else if cookie, err := c.Cookie(CSRFCookie); err == nil && validPreSessionCSRFToken(cfg.Secret, cookie) {
+			token = cookie
+		} else {
+			token = preSessionCSRFToken(cfg.Secret)
+		}
+
+		c.Header(CSRFHeader, token)
+		if cookie, err := c.Cookie(CSRFCookie); err != nil || cookie != token {
+			c.SetSameSite(http.SameSiteStrictMode)
+			c.SetCookie(CSRFCookie, token, 0, cfg.CookiePath, cfg.CookieDomain, cfg.CookieSecure, false)
+		}
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			c.Next()
+			return
+		}
+		if sessionID == "" && !loginPaths[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+		// The pre-session token must also be the one in the cookie: another
+		// site can get a validly signed token of its own, but cannot set it
+		// as this site's cookie
+		if sessionID == "" {
+			if cookie, err := c.Cookie(CSRFCookie); err != nil || cookie != token {
+				apperrors.Respond(c, apperrors.NewForbiddenError("missing or invalid CSRF token").WithCode(apperrors.CodeCSRFTokenInvalid))
+				return
+			}
+		}
+		if !hmac.Equal([]byte(c.GetHeader(CSRFHeader)), []byte(token)) {
+			apperrors.Respond(c, apperrors.NewForbiddenError("missing or invalid CSRF token").WithCode(apperrors.CodeCSRFTokenInvalid))
+			return
+		}
+		c.Next()
+	}
+}
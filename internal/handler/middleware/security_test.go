@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(SecurityHeaders(SecurityHeadersConfig{
+		HSTSMaxAge:            time.Hour,
+		HSTSIncludeSubdomains: true,
+		FrameOptions:          "DENY",
+	}))
+	router.GET("/tracks", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tracks", nil))
+	assert.Equal(t, "max-age=3600; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+}
+
+func TestCSRF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const secret = "secret"
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if id := c.GetHeader("X-Test-Session"); id != "" {
+			c.Set("session_id", id)
+		}
+	})
+	router.Use(CSRF(CSRFConfig{Secret: secret, CookiePath: "/"}))
+	router.GET("/tracks", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/tracks", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	do := func(method, sessionID, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/tracks", nil)
+		if sessionID != "" {
+			req.Header.Set("X-Test-Session", sessionID)
+		}
+		if token != "" {
+			req.Header.Set(CSRFHeader, token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("issues token to sessions", func(t *testing.T) {
+		w := do(http.MethodGet, "session-1", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		token := CSRFToken(secret, "session-1")
+		assert.Equal(t, token, w.Header().Get(CSRFHeader))
+		assert.Contains(t, w.Header().Get("Set-Cookie"), CSRFCookie+"="+token)
+		assert.NotContains(t, w.Header().Get("Set-Cookie"), "HttpOnly")
+	})
+
+	t.Run("requires token on state changes", func(t *testing.T) {
+		w := do(http.MethodPost, "session-1", "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "CSRF_TOKEN_INVALID")
+	})
+
+	t.Run("rejects token of another session", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, do(http.MethodPost, "session-1", CSRFToken(secret, "session-2")).Code)
+	})
+
+	t.Run("accepts token of the session", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, do(http.MethodPost, "session-1", CSRFToken(secret, "session-1")).Code)
+	})
+
+	t.Run("does not check requests without session", func(t *testing.T) {
+		w := do(http.MethodPost, "", "")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.True(t, validPreSessionCSRFToken(secret, w.Header().Get(CSRFHeader)))
+	})
+
+	t.Run("ignores bearer requests", func(t *testing.T) {
+		router := gin.New()
+		router.Use(func(c *gin.Context) { c.Set("claims", &domain.Claims{UserID: "user-1"}) })
+		router.Use(CSRF(CSRFConfig{Secret: secret, CookiePath: "/"}))
+		router.POST("/tracks", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/tracks", nil))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Empty(t, w.Header().Get(CSRFHeader))
+		assert.Empty(t, w.Header().Get("Set-Cookie"))
+	})
+}
+
+func TestCSRF_Login(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const secret = "secret"
+	router := gin.New()
+	router.Use(CSRF(CSRFConfig{Secret: secret, LoginPaths: []string{"/auth/login"}, CookiePath: "/"}))
+	router.GET("/tracks", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/auth/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	login := func(cookie, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: CSRFCookie, Value: cookie})
+		}
+		if token != "" {
+			req.Header.Set(CSRFHeader, token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// A browser gets its pre-session token with any request
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tracks", nil))
+	token := w.Header().Get(CSRFHeader)
+	require.NotEmpty(t, token)
+	assert.Contains(t, w.Header().Get("Set-Cookie"), CSRFCookie+"="+token)
+
+	t.Run("accepts the token of the cookie", func(t *testing.T) {
+		w := login(token, token)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, token, w.Header().Get(CSRFHeader))
+		assert.Empty(t, w.Header().Get("Set-Cookie"), "a valid cookie is kept")
+	})
+
+	t.Run("refuses a forged login without token", func(t *testing.T) {
+		w := login("", "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "CSRF_TOKEN_INVALID")
+	})
+
+	t.Run("refuses a missing header", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, login(token, "").Code)
+	})
+
+	t.Run("refuses a token that is not the cookie's", func(t *testing.T) {
+		// Another site can get a signed token of its own, but not put it in
+		// this site's cookie
+		other := preSessionCSRFToken(secret)
+		assert.Equal(t, http.StatusForbidden, login(token, other).Code)
+		assert.Equal(t, http.StatusForbidden, login("", other).Code)
+	})
+
+	t.Run("refuses a token not signed by the server", func(t *testing.T) {
+		forged := "nonce." + strings.Repeat("0", 64)
+		assert.Equal(t, http.StatusForbidden, login(forged, forged).Code)
+	})
+}
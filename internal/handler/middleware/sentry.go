@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"metadatatool/internal/pkg/errortracking"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gin-gonic/gin"
+)
+
+// Sentry traces every request as a Sentry transaction sampled at the
+// configured traces sample rate and captures panics before passing them on
+// to the recovery middleware. Each request gets its own hub on the request
+// context, holding the request and a breadcrumb for it, so errors captured
+// with ErrorTracker.CaptureErrorContext are reported with both.
+func Sentry(tracker *errortracking.ErrorTracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !tracker.Enabled() {
+			c.Next()
+			return
+		}
+
+		hub := sentry.CurrentHub().Clone()
+		hub.Scope().SetRequest(c.Request)
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		hub.AddBreadcrumb(&sentry.Breadcrumb{
+			Type:     "http",
+			Category: "request",
+			Message:  c.Request.Method + " " + route,
+			Data: map[string]interface{}{
+				"method":     c.Request.Method,
+				"url":        c.Request.URL.String(),
+				"request_id": c.GetString("request_id"),
+			},
+		}, nil)
+
+		ctx := sentry.SetHubOnContext(c.Request.Context(), hub)
+		transaction := sentry.StartTransaction(ctx, c.Request.Method+" "+route,
+			sentry.ContinueFromRequest(c.Request),
+			sentry.WithOpName("http.server"),
+			sentry.WithTransactionSource(sentry.SourceRoute),
+		)
+		defer transaction.Finish()
+		c.Request = c.Request.WithContext(transaction.Context())
+
+		defer func() {
+			if r := recover(); r != nil {
+				transaction.Status = sentry.SpanStatusInternalError
+				hub.RecoverWithContext(transaction.Context(), r)
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		transaction.Status = sentry.HTTPtoSpanStatus(status)
+		transaction.SetData("http.response.status_code", status)
+	}
+}
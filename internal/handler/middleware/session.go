@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
 	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
 	"net/http"
 	"time"
 
@@ -19,23 +21,22 @@ func Session(store domain.SessionStore, config domain.SessionConfig) gin.Handler
 				c.Next()
 				return
 			}
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to read session cookie"})
+			apperrors.Respond(c, apperrors.NewInternalError("failed to read session cookie", err))
 			return
 		}
 
 		session, err := store.Get(c.Request.Context(), cookie)
-		if err != nil {
-			// Only try to delete if the session exists but there was an error retrieving it
-			if err != domain.ErrSessionNotFound {
-				if delErr := store.Delete(c.Request.Context(), cookie); delErr != nil {
-					c.Error(fmt.Errorf("failed to delete invalid session: %w", delErr))
-				}
+		if err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+			// The session exists but could not be read
+			if delErr := store.Delete(c.Request.Context(), cookie); delErr != nil {
+				c.Error(fmt.Errorf("failed to delete invalid session: %w", delErr))
 			}
 			clearSessionCookie(c, config)
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve session"})
+			apperrors.Respond(c, apperrors.NewInternalError("failed to retrieve session", err))
 			return
 		}
 
+		// Stores report an unknown session as ErrSessionNotFound or nil
 		if session == nil {
 			clearSessionCookie(c, config)
 			c.Next()
@@ -47,7 +48,7 @@ func Session(store domain.SessionStore, config domain.SessionConfig) gin.Handler
 				c.Error(fmt.Errorf("failed to delete expired session: %w", err))
 			}
 			clearSessionCookie(c, config)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session expired"})
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("session expired").WithCode(apperrors.CodeSessionExpired))
 			return
 		}
 
@@ -60,6 +61,7 @@ func Session(store domain.SessionStore, config domain.SessionConfig) gin.Handler
 		c.Set("user_id", session.UserID)
 		c.Set("role", session.Role)
 		c.Set("permissions", session.Permissions)
+		c.Request = c.Request.WithContext(domain.WithTenant(c.Request.Context(), session.UserID))
 
 		c.Next()
 	}
@@ -86,18 +88,14 @@ func CreateSession(store domain.SessionStore, cfg domain.SessionConfig) gin.Hand
 
 		userClaims, ok := claims.(*domain.Claims)
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"error": "Invalid claims type",
-			})
+			apperrors.Respond(c, apperrors.NewInternalError("invalid claims type", nil))
 			return
 		}
 
 		// Get existing sessions count
 		sessions, err := store.GetUserSessions(c.Request.Context(), userClaims.UserID)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to check existing sessions",
-			})
+			apperrors.Respond(c, apperrors.NewInternalError("failed to check existing sessions", err))
 			return
 		}
 
@@ -128,9 +126,7 @@ func CreateSession(store domain.SessionStore, cfg domain.SessionConfig) gin.Hand
 		}
 
 		if err := store.Create(c.Request.Context(), session); err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to create session",
-			})
+			apperrors.Respond(c, apperrors.NewInternalError("failed to create session", err))
 			return
 		}
 
@@ -167,7 +163,7 @@ func ClearSession(store domain.SessionStore, config domain.SessionConfig) gin.Ha
 				return
 			}
 			clearSessionCookie(c, config)
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to read session cookie"})
+			apperrors.Respond(c, apperrors.NewInternalError("failed to read session cookie", err))
 			return
 		}
 
@@ -182,7 +178,7 @@ func ClearSession(store domain.SessionStore, config domain.SessionConfig) gin.Ha
 		// Delete the session
 		if err := store.Delete(c.Request.Context(), cookie); err != nil {
 			clearSessionCookie(c, config)
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete session"})
+			apperrors.Respond(c, apperrors.NewInternalError("failed to delete session", err))
 			return
 		}
 
@@ -196,13 +192,13 @@ func RequireSession(store domain.SessionStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		session, exists := c.Get("session")
 		if !exists {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "No active session"})
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("no active session").WithCode(apperrors.CodeSessionRequired))
 			return
 		}
 
 		s, ok := session.(*domain.Session)
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Invalid session type"})
+			apperrors.Respond(c, apperrors.NewInternalError("invalid session type", nil))
 			return
 		}
 
@@ -214,7 +210,7 @@ func RequireSession(store domain.SessionStore) gin.HandlerFunc {
 				CookieName: "session_id",
 				CookiePath: "/",
 			})
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session expired"})
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("session expired").WithCode(apperrors.CodeSessionExpired))
 			return
 		}
 
@@ -225,7 +221,7 @@ func RequireSession(store domain.SessionStore) gin.HandlerFunc {
 				CookieName: "session_id",
 				CookiePath: "/",
 			})
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session not found"})
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("session not found"))
 			return
 		}
 
@@ -502,7 +502,7 @@ func TestClearSession_Middleware(t *testing.T) {
 				})
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedError:  "Failed to delete session",
+			expectedError:  "failed to delete session",
 		},
 	}
 
@@ -533,11 +533,13 @@ func TestClearSession_Middleware(t *testing.T) {
 			// For error cases, check error response
 			if tt.expectedStatus >= 400 {
 				var response struct {
-					Error string `json:"error"`
+					Error struct {
+						Message string `json:"message"`
+					} `json:"error"`
 				}
 				err := json.NewDecoder(w.Body).Decode(&response)
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedError, response.Error)
+				assert.Equal(t, tt.expectedError, response.Error.Message)
 			}
 
 			// Verify cookie was cleared
@@ -672,7 +674,7 @@ func TestRequireSession_Middleware(t *testing.T) {
 			setupMocks:     func() {},
 			setupRequest:   func(req *http.Request) {},
 			expectedStatus: http.StatusUnauthorized,
-			expectedError:  "No active session",
+			expectedError:  "no active session",
 			checkContext: func(t *testing.T, c *gin.Context) {
 				_, exists := c.Get("session")
 				assert.False(t, exists)
@@ -701,7 +703,7 @@ func TestRequireSession_Middleware(t *testing.T) {
 				})
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedError:  "Session expired",
+			expectedError:  "session expired",
 			checkContext: func(t *testing.T, c *gin.Context) {
 				_, exists := c.Get("session")
 				assert.False(t, exists)
@@ -729,7 +731,7 @@ func TestRequireSession_Middleware(t *testing.T) {
 				})
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedError:  "Failed to retrieve session",
+			expectedError:  "failed to retrieve session",
 			checkContext: func(t *testing.T, c *gin.Context) {
 				_, exists := c.Get("session")
 				assert.False(t, exists)
@@ -773,11 +775,13 @@ func TestRequireSession_Middleware(t *testing.T) {
 			// For error cases, check error response
 			if tt.expectedStatus >= 400 {
 				var response struct {
-					Error string `json:"error"`
+					Error struct {
+						Message string `json:"message"`
+					} `json:"error"`
 				}
 				err := json.NewDecoder(w.Body).Decode(&response)
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedError, response.Error)
+				assert.Equal(t, tt.expectedError, response.Error.Message)
 			}
 
 			// Check context
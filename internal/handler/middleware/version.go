@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API versions, served under /api/<version>
+const (
+	APIVersion1 = "v1"
+	APIVersion2 = "v2"
+)
+
+// APIVersionKey is the context key holding the API version of a request
+const APIVersionKey = "api_version"
+
+// APIVersion records version as the API version of the requests it sees,
+// which selects the request and response bodies handlers use
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(APIVersionKey, version)
+		c.Next()
+	}
+}
+
+// DeprecationConfig describes the deprecation of an API version
+type DeprecationConfig struct {
+	// Deprecated is when the version was deprecated; zero leaves the
+	// Deprecation header out
+	Deprecated time.Time
+	// Sunset is when the version stops being served; zero leaves the Sunset
+	// header out
+	Sunset time.Time
+	// Link points to documentation of the deprecation, such as a migration
+	// guide
+	Link string
+	// Successor is the path of the version replacing this one
+	Successor string
+}
+
+// Deprecation announces that an API version is going away: responses carry
+// the Deprecation (RFC 9745) and Sunset (RFC 8594) headers, and Link
+// headers to the deprecation notice and the successor version
+func Deprecation(cfg DeprecationConfig) gin.HandlerFunc {
+	var links []string
+	if cfg.Link != "" {
+		links = append(links, "<"+cfg.Link+`>; rel="deprecation"`)
+	}
+	if cfg.Successor != "" {
+		links = append(links, "<"+cfg.Successor+`>; rel="successor-version"`)
+	}
+
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		if !cfg.Deprecated.IsZero() {
+			h.Set("Deprecation", "@"+strconv.FormatInt(cfg.Deprecated.Unix(), 10))
+		}
+		if !cfg.Sunset.IsZero() {
+			h.Set("Sunset", cfg.Sunset.UTC().Format(http.TimeFormat))
+		}
+		for _, link := range links {
+			h.Add("Link", link)
+		}
+		c.Next()
+	}
+}
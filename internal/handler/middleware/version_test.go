@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(APIVersion(APIVersion1), Deprecation(DeprecationConfig{
+		Deprecated: time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC),
+		Sunset:     time.Date(2026, 7, 31, 0, 0, 0, 0, time.UTC),
+		Link:       "https://docs.example.com/api/v2-migration",
+		Successor:  "/api/v2",
+	}))
+	router.GET("/tracks", func(c *gin.Context) { c.String(http.StatusOK, c.GetString(APIVersionKey)) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tracks", nil))
+	assert.Equal(t, "v1", w.Body.String())
+	assert.Equal(t, "@1769817600", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Fri, 31 Jul 2026 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, []string{
+		`<https://docs.example.com/api/v2-migration>; rel="deprecation"`,
+		`</api/v2>; rel="successor-version"`,
+	}, w.Header().Values("Link"))
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+)
+
+// PasswordResetHandler handles password recovery requests
+type PasswordResetHandler struct {
+	resetUseCase *usecase.PasswordResetUseCase
+}
+
+// NewPasswordResetHandler creates a new password reset handler
+func NewPasswordResetHandler(resetUseCase *usecase.PasswordResetUseCase) *PasswordResetHandler {
+	return &PasswordResetHandler{resetUseCase: resetUseCase}
+}
+
+// ForgotPassword sends a reset token to the given email address. The response
+// is the same whether or not an account exists.
+func (h *PasswordResetHandler) ForgotPassword(c *gin.Context) {
+	var input struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := bindJSON(c, &input); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	if err := h.resetUseCase.ForgotPassword(c.Request.Context(), input.Email, c.ClientIP()); err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to request password reset"))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the address is registered, a reset link has been sent"})
+}
+
+// ResetPassword sets a new password using a reset token
+func (h *PasswordResetHandler) ResetPassword(c *gin.Context) {
+	var input struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := bindJSON(c, &input); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	err := h.resetUseCase.ResetPassword(c.Request.Context(), input.Token, input.Password, c.ClientIP())
+	if err != nil {
+		// A bad reset token is part of the request rather than a credential
+		if errors.Is(err, domain.ErrInvalidToken) {
+			apperrors.Respond(c, apperrors.NewValidationError("invalid or expired reset token", "").WithCode(apperrors.CodeInvalidToken))
+			return
+		}
+		apperrors.Respond(c, apperrors.FromError(err, "failed to reset password"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password has been reset"})
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"metadatatool/internal/handler/middleware"
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PublicHandler serves the public catalog API to partners and lets admins
+// manage the keys it accepts
+type PublicHandler struct {
+	api *usecase.PublicAPIUseCase
+}
+
+// NewPublicHandler creates a new public API handler
+func NewPublicHandler(api *usecase.PublicAPIUseCase) *PublicHandler {
+	return &PublicHandler{api: api}
+}
+
+// PublicTracksResponse is a page of a label's approved tracks
+type PublicTracksResponse struct {
+	Tracks []*domain.PublicTrack `json:"tracks"`
+	Page   int                   `json:"page"`
+	Limit  int                   `json:"limit"`
+}
+
+// PublicAPIKeysResponse lists public API keys
+type PublicAPIKeysResponse struct {
+	Keys  []*domain.PublicAPIKey `json:"keys"`
+	Tiers []domain.PublicAPITier `json:"tiers"`
+}
+
+// ListTracks returns a page of the approved tracks of the key's label. It is
+// served under /public/v1 with an API key rather than a session.
+func (h *PublicHandler) ListTracks(c *gin.Context) {
+	key, ok := middleware.PublicAPIKeyFromContext(c)
+	if !ok {
+		apperrors.Respond(c, apperrors.NewUnauthorizedError("missing API key"))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	tracks, err := h.api.ListTracks(c.Request.Context(), key, (page-1)*limit, limit)
+	if err != nil {
+		apperrors.Respond(c, apperrors.NewDatabaseError("failed to list tracks", err))
+		return
+	}
+	c.JSON(http.StatusOK, PublicTracksResponse{Tracks: tracks, Page: page, Limit: limit})
+}
+
+// GetTrack returns an approved track of the key's label. It is served under
+// /public/v1 with an API key rather than a session.
+func (h *PublicHandler) GetTrack(c *gin.Context) {
+	key, ok := middleware.PublicAPIKeyFromContext(c)
+	if !ok {
+		apperrors.Respond(c, apperrors.NewUnauthorizedError("missing API key"))
+		return
+	}
+
+	track, err := h.api.GetTrack(c.Request.Context(), key, c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to get track"))
+		return
+	}
+	c.JSON(http.StatusOK, track)
+}
+
+// ListKeys lists public API keys
+// @Summary List public API keys
+// @Description List the keys partners read approved tracks with through the public catalog API, newest first, and the tiers keys can be created on. Revoked keys are included.
+// @Tags public-api
+// @Produce json
+// @Param label_id query string false "Label whose keys to list"
+// @Success 200 {object} PublicAPIKeysResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /public-api-keys [get]
+func (h *PublicHandler) ListKeys(c *gin.Context) {
+	keys, err := h.api.ListKeys(c.Request.Context(), c.Query("label_id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to list public API keys"))
+		return
+	}
+	c.JSON(http.StatusOK, PublicAPIKeysResponse{Keys: keys, Tiers: h.api.Tiers()})
+}
+
+// CreateKey issues a public API key
+// @Summary Create public API key
+// @Description Issue a key reading a label's approved tracks through the public catalog API under /public/v1. The tier sets the key's requests per minute and per day and defaults to free. The key is only returned in this response.
+// @Tags public-api
+// @Accept json
+// @Produce json
+// @Param request body usecase.PublicAPIKeyRequest true "Key"
+// @Success 201 {object} usecase.CreatedPublicAPIKey
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /public-api-keys [post]
+func (h *PublicHandler) CreateKey(c *gin.Context) {
+	var req usecase.PublicAPIKeyRequest
+	if err := bindJSON(c, &req); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	key, err := h.api.CreateKey(c.Request.Context(), req)
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "invalid public API key"))
+		return
+	}
+	c.JSON(http.StatusCreated, key)
+}
+
+// RevokeKey revokes a public API key
+// @Summary Revoke public API key
+// @Description Stop accepting a public API key. The key stays listed with the time it was revoked.
+// @Tags public-api
+// @Param id path string true "Key ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /public-api-keys/{id} [delete]
+func (h *PublicHandler) RevokeKey(c *gin.Context) {
+	if err := h.api.RevokeKey(c.Request.Context(), c.Param("id")); err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to revoke public API key"))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
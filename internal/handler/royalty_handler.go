@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSalesReportSize caps the size of an uploaded usage report
+const maxSalesReportSize = 512 << 20
+
+// RoyaltyHandler ingests DSP usage reports and serves play counts for
+// royalty reporting
+type RoyaltyHandler struct {
+	plays *usecase.PlayCountUseCase
+}
+
+// NewRoyaltyHandler creates a new royalty handler
+func NewRoyaltyHandler(plays *usecase.PlayCountUseCase) *RoyaltyHandler {
+	return &RoyaltyHandler{plays: plays}
+}
+
+// PlayCountsResponse is a page of play counts
+type PlayCountsResponse struct {
+	PlayCounts []*domain.PlayCount `json:"play_counts"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+}
+
+// IngestSalesReport imports a DSP usage report
+// @Summary Ingest a usage report
+// @Description Import a DSR flat file from a DSP and add its plays to the play counts per track, territory and month. The report's column definition rows are required. A report is ingested once per DSP; usages of unknown ISRCs are counted as unmatched.
+// @Tags royalties
+// @Accept plain
+// @Produce json
+// @Param dsp query string false "DSP that sent the report; defaults to its SenderName"
+// @Param request body string true "DSR flat file"
+// @Success 201 {object} domain.SalesReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /royalties/reports [post]
+func (h *RoyaltyHandler) IngestSalesReport(c *gin.Context) {
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxSalesReportSize)
+	report, err := h.plays.Ingest(c.Request.Context(), c.Query("dsp"), body)
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "invalid usage report"))
+		return
+	}
+	c.JSON(http.StatusCreated, report)
+}
+
+// ListPlayCounts lists play counts for royalty reporting
+// @Summary List play counts
+// @Description List plays per track, DSP, territory and month, ordered by month.
+// @Tags royalties
+// @Produce json
+// @Param track_id query string false "Only this track"
+// @Param dsp query string false "Only this DSP"
+// @Param territory query string false "Only this territory, an ISO 3166-1 alpha-2 code"
+// @Param from query string false "First month, YYYY-MM"
+// @Param to query string false "Last month, YYYY-MM"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page, at most 1000"
+// @Success 200 {object} PlayCountsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /royalties/plays [get]
+func (h *RoyaltyHandler) ListPlayCounts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 1000 {
+		limit = 100
+	}
+
+	counts, err := h.plays.List(c.Request.Context(), domain.PlayCountFilter{
+		TrackID:   c.Query("track_id"),
+		DSP:       c.Query("dsp"),
+		Territory: strings.ToUpper(c.Query("territory")),
+		From:      c.Query("from"),
+		To:        c.Query("to"),
+		Offset:    (page - 1) * limit,
+		Limit:     limit,
+	})
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "invalid play count query"))
+		return
+	}
+	c.JSON(http.StatusOK, PlayCountsResponse{PlayCounts: counts, Page: page, Limit: limit})
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RuntimeConfigHandler handles the admin API for settings that can be
+// changed without a restart
+type RuntimeConfigHandler struct {
+	runtimeConfig *usecase.RuntimeConfigUseCase
+}
+
+// NewRuntimeConfigHandler creates a new runtime config handler
+func NewRuntimeConfigHandler(runtimeConfig *usecase.RuntimeConfigUseCase) *RuntimeConfigHandler {
+	return &RuntimeConfigHandler{runtimeConfig: runtimeConfig}
+}
+
+// GetRuntimeConfig returns the runtime settings in effect
+// @Summary Get runtime settings
+// @Description Get the settings that can be changed while the API runs
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.RuntimeSettings
+// @Router /admin/runtime-config [get]
+func (h *RuntimeConfigHandler) GetRuntimeConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, h.runtimeConfig.Current())
+}
+
+// UpdateRuntimeConfig changes runtime settings on every API instance
+// @Summary Update runtime settings
+// @Description Change AI confidence, experiment traffic or rate limit settings. Omitted fields keep their value; changes apply to all instances without a restart.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body domain.RuntimeSettingsPatch true "Settings to change"
+// @Success 200 {object} domain.RuntimeSettings
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/runtime-config [patch]
+func (h *RuntimeConfigHandler) UpdateRuntimeConfig(c *gin.Context) {
+	var patch domain.RuntimeSettingsPatch
+	if err := bindJSON(c, &patch); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	settings, err := h.runtimeConfig.Update(c.Request.Context(), &patch, c.GetString("user_id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			h.handleError(c, apperrors.NewValidationError("invalid runtime settings", err.Error()))
+			return
+		}
+		h.handleError(c, apperrors.NewInternalError("failed to update runtime settings", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *RuntimeConfigHandler) handleError(c *gin.Context, err *apperrors.AppError) {
+	apperrors.Respond(c, err)
+}
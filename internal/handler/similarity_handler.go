@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/pkg/errortracking"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SimilarityHandler handles HTTP requests for finding tracks by the
+// similarity of their audio embeddings
+type SimilarityHandler struct {
+	similarity   *usecase.SimilarityUseCase
+	errorTracker *errortracking.ErrorTracker
+}
+
+// NewSimilarityHandler creates a new similarity handler
+func NewSimilarityHandler(similarity *usecase.SimilarityUseCase, errorTracker *errortracking.ErrorTracker) *SimilarityHandler {
+	return &SimilarityHandler{
+		similarity:   similarity,
+		errorTracker: errorTracker,
+	}
+}
+
+// SimilarTracksResponse lists the tracks that sound like a track
+type SimilarTracksResponse struct {
+	TrackID string                `json:"track_id"`
+	Tracks  []domain.SimilarTrack `json:"tracks"`
+}
+
+// EmbeddingRequest uploads an embedding computed outside the service
+type EmbeddingRequest struct {
+	// Model names the embedding model; only tracks embedded by the same
+	// model are compared
+	Model  string    `json:"model"`
+	Vector []float32 `json:"vector"`
+}
+
+// GetSimilarTracks lists the tracks that sound like a track
+// @Summary List similar tracks
+// @Description List the tracks whose audio embeddings are nearest to a track's by cosine distance, nearest first, for A&R discovery and duplicate triage. Only embeddings of the same model are compared. Tracks are embedded from their audio analysis when it is processed, or by uploading an embedding. A track without an embedding is answered with 404.
+// @Tags tracks
+// @Produce json
+// @Param id path string true "Track ID"
+// @Param limit query int false "Maximum number of tracks (default 10, at most 100)"
+// @Success 200 {object} SimilarTracksResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/{id}/similar [get]
+func (h *SimilarityHandler) GetSimilarTracks(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		h.handleError(c, apperrors.NewValidationError("invalid limit", err.Error()))
+		return
+	}
+
+	similar, err := h.similarity.Similar(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		h.handleError(c, apperrors.FromError(err, "failed to find similar tracks"))
+		return
+	}
+	c.JSON(http.StatusOK, SimilarTracksResponse{TrackID: c.Param("id"), Tracks: similar})
+}
+
+// PutTrackEmbedding stores an embedding of a track
+// @Summary Upload track embedding
+// @Description Store an audio embedding computed by an external model for a track, replacing the one it had. Vectors have at most 1536 finite values that are not all zero.
+// @Tags tracks
+// @Accept json
+// @Produce json
+// @Param id path string true "Track ID"
+// @Param request body EmbeddingRequest true "Embedding"
+// @Success 200 {object} domain.TrackEmbedding
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/{id}/embedding [put]
+func (h *SimilarityHandler) PutTrackEmbedding(c *gin.Context) {
+	var req EmbeddingRequest
+	if appErr := bindJSON(c, &req); appErr != nil {
+		h.handleError(c, appErr)
+		return
+	}
+
+	embedding := &domain.TrackEmbedding{TrackID: c.Param("id"), Model: req.Model, Vector: req.Vector}
+	if errs := embedding.Validate(); len(errs) > 0 {
+		h.handleError(c, apperrors.NewFieldValidationError("invalid embedding", fieldErrors(errs)))
+		return
+	}
+	if err := h.similarity.Save(c.Request.Context(), embedding); err != nil {
+		h.handleError(c, apperrors.FromError(err, "failed to save embedding"))
+		return
+	}
+	c.JSON(http.StatusOK, embedding)
+}
+
+func (h *SimilarityHandler) handleError(c *gin.Context, err *apperrors.AppError) {
+	if h.errorTracker != nil {
+		h.errorTracker.CaptureErrorContext(c.Request.Context(), err, map[string]string{
+			"handler": "similarity",
+			"method":  c.Request.Method,
+			"path":    c.Request.URL.Path,
+		})
+	}
+
+	apperrors.Respond(c, err)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/pkg/errortracking"
+	"metadatatool/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StorageIntegrityHandler handles HTTP requests for checking stored track
+// files against their checksums
+type StorageIntegrityHandler struct {
+	integrityUseCase *usecase.IntegrityUseCase
+	errorTracker     *errortracking.ErrorTracker
+}
+
+// NewStorageIntegrityHandler creates a new storage integrity handler
+func NewStorageIntegrityHandler(integrityUseCase *usecase.IntegrityUseCase, errorTracker *errortracking.ErrorTracker) *StorageIntegrityHandler {
+	return &StorageIntegrityHandler{
+		integrityUseCase: integrityUseCase,
+		errorTracker:     errorTracker,
+	}
+}
+
+// GetIntegrity checks a track's file against its recorded checksums
+// @Summary Check track file integrity
+// @Description Read a track's audio file and compare its SHA-256 and MD5 with the checksums recorded at upload. The status is ok, mismatch when the file is corrupted, recorded when no checksums were recorded before and the measured ones were stored, or unavailable when the file cannot be read, for example because it is archived.
+// @Tags tracks
+// @Produce json
+// @Param id path string true "Track ID"
+// @Success 200 {object} domain.IntegrityReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/{id}/integrity [get]
+func (h *StorageIntegrityHandler) GetIntegrity(c *gin.Context) {
+	report, err := h.integrityUseCase.Check(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, apperrors.FromError(err, "failed to check file integrity"))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *StorageIntegrityHandler) handleError(c *gin.Context, err *apperrors.AppError) {
+	if h.errorTracker != nil {
+		h.errorTracker.CaptureErrorContext(c.Request.Context(), err, map[string]string{
+			"handler": "storage_integrity",
+			"method":  c.Request.Method,
+			"path":    c.Request.URL.Path,
+		})
+	}
+
+	apperrors.Respond(c, err)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/pkg/errortracking"
+	"metadatatool/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StorageRestoreHandler handles HTTP requests for restoring archived track
+// files
+type StorageRestoreHandler struct {
+	restoreUseCase *usecase.StorageRestoreUseCase
+	errorTracker   *errortracking.ErrorTracker
+}
+
+// NewStorageRestoreHandler creates a new storage restore handler
+func NewStorageRestoreHandler(restoreUseCase *usecase.StorageRestoreUseCase, errorTracker *errortracking.ErrorTracker) *StorageRestoreHandler {
+	return &StorageRestoreHandler{
+		restoreUseCase: restoreUseCase,
+		errorTracker:   errorTracker,
+	}
+}
+
+// GetRestore returns whether a track's file is archived
+// @Summary Get track file archive status
+// @Description Get the storage class of a track's audio file and whether it is available, archived, being restored or restored
+// @Tags tracks
+// @Produce json
+// @Param id path string true "Track ID"
+// @Success 200 {object} domain.ArchiveStatus
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/{id}/restore [get]
+func (h *StorageRestoreHandler) GetRestore(c *gin.Context) {
+	status, err := h.restoreUseCase.Status(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, apperrors.FromError(err, "failed to get archive status"))
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// Restore starts retrieving a track's archived file
+// @Summary Restore archived track file
+// @Description Start retrieving a track's audio file from archive storage. The restore notification webhook is called once the file can be read; until then the status is restoring. Files that are not archived are returned as they are.
+// @Tags tracks
+// @Produce json
+// @Param id path string true "Track ID"
+// @Success 200 {object} domain.ArchiveStatus "File is readable"
+// @Success 202 {object} domain.ArchiveStatus "Restore in progress"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/{id}/restore [post]
+func (h *StorageRestoreHandler) Restore(c *gin.Context) {
+	status, err := h.restoreUseCase.Restore(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		h.handleError(c, apperrors.FromError(err, "failed to restore file"))
+		return
+	}
+
+	if status.State.Readable() {
+		c.JSON(http.StatusOK, status)
+		return
+	}
+	c.JSON(http.StatusAccepted, status)
+}
+
+func (h *StorageRestoreHandler) handleError(c *gin.Context, err *apperrors.AppError) {
+	if h.errorTracker != nil {
+		h.errorTracker.CaptureErrorContext(c.Request.Context(), err, map[string]string{
+			"handler": "storage_restore",
+			"method":  c.Request.Method,
+			"path":    c.Request.URL.Path,
+		})
+	}
+
+	apperrors.Respond(c, err)
+}
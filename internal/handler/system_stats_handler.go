@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"metadatatool/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SystemStatsHandler serves the operational state to the ops dashboard
+type SystemStatsHandler struct {
+	stats *usecase.SystemStatsUseCase
+}
+
+// NewSystemStatsHandler creates a new system stats handler
+func NewSystemStatsHandler(stats *usecase.SystemStatsUseCase) *SystemStatsHandler {
+	return &SystemStatsHandler{stats: stats}
+}
+
+// GetSystemStats returns queue, job, storage, AI provider and database stats
+// @Summary Get system stats
+// @Description Get queue depths, the job failure rate of the last hour, dead-letter counts, storage quota usage, AI provider health and database pool stats in one payload. Sections whose source failed are omitted and listed in errors.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.SystemStats
+// @Router /admin/stats [get]
+func (h *SystemStatsHandler) GetSystemStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.stats.Stats(c.Request.Context()))
+}
@@ -0,0 +1,299 @@
+package handler
+
+import (
+	"net/http"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagHandler manages the catalog's tags and tagging rules
+type TagHandler struct {
+	tags *usecase.TagUseCase
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(tags *usecase.TagUseCase) *TagHandler {
+	return &TagHandler{tags: tags}
+}
+
+// TagsResponse lists the catalog's tags
+type TagsResponse struct {
+	Tags []*domain.Tag `json:"tags"`
+}
+
+// TagUpdateRequest replaces the description of a tag
+type TagUpdateRequest struct {
+	Description string `json:"description"`
+}
+
+// TagRenameRequest names the new name of a tag
+type TagRenameRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// TagRulesResponse lists the tagging rules
+type TagRulesResponse struct {
+	Rules []*domain.TagRule `json:"rules"`
+}
+
+// ListTags lists the catalog's tags
+// @Summary List tags
+// @Description List the catalog's tags
+// @Tags tags
+// @Produce json
+// @Success 200 {object} TagsResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tags [get]
+func (h *TagHandler) ListTags(c *gin.Context) {
+	tags, err := h.tags.ListTags(c.Request.Context())
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to list tags"))
+		return
+	}
+	c.JSON(http.StatusOK, TagsResponse{Tags: tags})
+}
+
+// GetTag returns a tag
+// @Summary Get tag
+// @Description Get a tag by name
+// @Tags tags
+// @Produce json
+// @Param name path string true "Tag name"
+// @Success 200 {object} domain.Tag
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tags/{name} [get]
+func (h *TagHandler) GetTag(c *gin.Context) {
+	tag, err := h.tags.GetTag(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to get tag"))
+		return
+	}
+	c.JSON(http.StatusOK, tag)
+}
+
+// CreateTag adds a tag
+// @Summary Create tag
+// @Description Add a tag to the catalog. Names are stored in canonical form: lower case, with spaces and underscores written as hyphens. Tags saved on tracks are added automatically.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param request body domain.Tag true "Tag"
+// @Success 201 {object} domain.Tag
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var tag domain.Tag
+	if err := bindJSON(c, &tag); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	if err := h.tags.CreateTag(c.Request.Context(), &tag); err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "invalid tag"))
+		return
+	}
+	c.JSON(http.StatusCreated, tag)
+}
+
+// UpdateTag replaces the description of a tag
+// @Summary Update tag
+// @Description Replace the description of a tag. Rename tags with the rename endpoint.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param name path string true "Tag name"
+// @Param request body TagUpdateRequest true "Description"
+// @Success 200 {object} domain.Tag
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tags/{name} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	var req TagUpdateRequest
+	if err := bindJSON(c, &req); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	tag, err := h.tags.UpdateTag(c.Request.Context(), c.Param("name"), req.Description)
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to update tag"))
+		return
+	}
+	c.JSON(http.StatusOK, tag)
+}
+
+// RenameTag renames a tag
+// @Summary Rename tag
+// @Description Rename a tag on every track carrying it and in the rules adding it. The new name must not be taken; merge into existing tags instead.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param name path string true "Tag name"
+// @Param request body TagRenameRequest true "New name"
+// @Success 200 {object} usecase.TagChangeResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tags/{name}/rename [post]
+func (h *TagHandler) RenameTag(c *gin.Context) {
+	var req TagRenameRequest
+	if err := bindJSON(c, &req); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	result, err := h.tags.RenameTag(c.Request.Context(), c.Param("name"), req.Name, c.GetString("user_id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to rename tag"))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// MergeTag merges a tag into another
+// @Summary Merge tags
+// @Description Replace a tag with another on every track carrying it and in the rules adding it, then delete it
+// @Tags tags
+// @Produce json
+// @Param name path string true "Tag name"
+// @Param target path string true "Name of the tag merged into"
+// @Success 200 {object} usecase.TagChangeResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tags/{name}/merge-into/{target} [post]
+func (h *TagHandler) MergeTag(c *gin.Context) {
+	result, err := h.tags.MergeTag(c.Request.Context(), c.Param("name"), c.Param("target"), c.GetString("user_id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to merge tags"))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// DeleteTag deletes a tag
+// @Summary Delete tag
+// @Description Remove a tag from every track carrying it, delete the rules adding it, then delete it
+// @Tags tags
+// @Produce json
+// @Param name path string true "Tag name"
+// @Success 200 {object} usecase.TagChangeResult
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tags/{name} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	result, err := h.tags.DeleteTag(c.Request.Context(), c.Param("name"), c.GetString("user_id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to delete tag"))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ListTagRules lists the tagging rules
+// @Summary List tagging rules
+// @Description List the rules adding tags to tracks when they are saved
+// @Tags tags
+// @Produce json
+// @Success 200 {object} TagRulesResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tag-rules [get]
+func (h *TagHandler) ListTagRules(c *gin.Context) {
+	rules, err := h.tags.ListRules(c.Request.Context())
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to list tag rules"))
+		return
+	}
+	c.JSON(http.StatusOK, TagRulesResponse{Rules: rules})
+}
+
+// GetTagRule returns a tagging rule
+// @Summary Get tagging rule
+// @Description Get a tagging rule
+// @Tags tags
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} domain.TagRule
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tag-rules/{id} [get]
+func (h *TagHandler) GetTagRule(c *gin.Context) {
+	rule, err := h.tags.GetRule(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to get tag rule"))
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// CreateTagRule creates a tagging rule
+// @Summary Create tagging rule
+// @Description Create a rule adding a tag to the tracks matching all of its conditions when they are created, replaced, patched or imported, such as {"tag": "drum-and-bass", "conditions": [{"field": "bpm", "op": "gt", "value": 170}, {"field": "genre", "op": "eq", "value": "dnb"}]}. Ops are eq, ne, gt, gte, lt, lte, contains and in.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param request body domain.TagRule true "Rule"
+// @Success 201 {object} domain.TagRule
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tag-rules [post]
+func (h *TagHandler) CreateTagRule(c *gin.Context) {
+	h.saveTagRule(c, "", http.StatusCreated)
+}
+
+// UpdateTagRule replaces a tagging rule
+// @Summary Replace tagging rule
+// @Description Replace a tagging rule. Tracks are retagged when they are next saved.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param request body domain.TagRule true "Rule"
+// @Success 200 {object} domain.TagRule
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tag-rules/{id} [put]
+func (h *TagHandler) UpdateTagRule(c *gin.Context) {
+	h.saveTagRule(c, c.Param("id"), http.StatusOK)
+}
+
+func (h *TagHandler) saveTagRule(c *gin.Context, id string, status int) {
+	var rule domain.TagRule
+	if err := bindJSON(c, &rule); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	if errs := rule.Validate(); len(errs) > 0 {
+		apperrors.Respond(c, apperrors.NewFieldValidationError("invalid tag rule", fieldErrors(errs)))
+		return
+	}
+
+	rule.ID = id
+	if err := h.tags.SaveRule(c.Request.Context(), &rule); err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "invalid tag rule"))
+		return
+	}
+	c.JSON(status, rule)
+}
+
+// DeleteTagRule deletes a tagging rule
+// @Summary Delete tagging rule
+// @Description Delete a tagging rule. The tags it added stay on the tracks.
+// @Tags tags
+// @Param id path string true "Rule ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tag-rules/{id} [delete]
+func (h *TagHandler) DeleteTagRule(c *gin.Context) {
+	if err := h.tags.DeleteRule(c.Request.Context(), c.Param("id")); err != nil {
+		apperrors.Respond(c, apperrors.FromError(err, "failed to delete tag rule"))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
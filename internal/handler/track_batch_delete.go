@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+
+	"metadatatool/internal/handler/middleware"
+	"metadatatool/internal/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BatchDeleteRequest selects the tracks to delete
+type BatchDeleteRequest struct {
+	TrackIDs []string `json:"track_ids" binding:"required,min=1,max=1000"`
+}
+
+// BatchDeleteStatus is the outcome of a batch delete for one track
+type BatchDeleteStatus string
+
+const (
+	BatchDeleteDeleted  BatchDeleteStatus = "deleted"
+	BatchDeleteNotFound BatchDeleteStatus = "not_found"
+	BatchDeleteFailed   BatchDeleteStatus = "failed"
+)
+
+// BatchDeleteResult reports the outcome of a batch delete for one track
+type BatchDeleteResult struct {
+	TrackID string            `json:"track_id"`
+	Status  BatchDeleteStatus `json:"status"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// BatchDeleteResponse reports the outcome of a batch delete. In a dry run
+// the results describe what would happen and nothing is deleted.
+type BatchDeleteResponse struct {
+	DryRun  bool                `json:"dry_run"`
+	Deleted int                 `json:"deleted"`
+	Results []BatchDeleteResult `json:"results"`
+}
+
+// BatchDeleteTracks deletes many tracks
+// @Summary Delete tracks
+// @Description Delete the listed tracks, reporting the outcome per track. With dry_run=true nothing is deleted and the results show which tracks would be.
+// @Tags tracks
+// @Accept json
+// @Produce json
+// @Param dry_run query bool false "Report what would be deleted without deleting"
+// @Param request body BatchDeleteRequest true "Tracks to delete"
+// @Success 200 {object} BatchDeleteResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /tracks/batch-delete [post]
+func (h *TrackHandler) BatchDeleteTracks(c *gin.Context) {
+	var req BatchDeleteRequest
+	if err := bindJSON(c, &req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	resp := BatchDeleteResponse{DryRun: middleware.IsDryRun(c)}
+	seen := make(map[string]bool, len(req.TrackIDs))
+	for _, id := range req.TrackIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		result := h.deleteTrack(c, id, resp.DryRun)
+		if result.Status == BatchDeleteDeleted {
+			resp.Deleted++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// deleteTrack deletes one track of a batch, or only looks it up in a dry run
+func (h *TrackHandler) deleteTrack(c *gin.Context, id string, dryRun bool) BatchDeleteResult {
+	result := BatchDeleteResult{TrackID: id}
+	track, err := h.trackRepo.GetByID(c, id)
+	if err != nil {
+		result.Status, result.Error = BatchDeleteFailed, err.Error()
+		return result
+	}
+	if track == nil {
+		result.Status = BatchDeleteNotFound
+		return result
+	}
+	if dryRun {
+		result.Status = BatchDeleteDeleted
+		return result
+	}
+
+	metrics.DatabaseOperationsTotal.WithLabelValues("delete", "total").Inc()
+	if err := h.trackRepo.Delete(c, id); err != nil {
+		result.Status, result.Error = BatchDeleteFailed, err.Error()
+		return result
+	}
+	h.releaseQuota(c, id)
+	result.Status = BatchDeleteDeleted
+	return result
+}
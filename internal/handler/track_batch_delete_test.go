@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"metadatatool/internal/handler/middleware"
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchDeleteTracks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func() (*gin.Engine, *stubTrackRepository) {
+		repo := &stubTrackRepository{tracks: map[string]*domain.Track{
+			"t1": {ID: "t1"},
+			"t2": {ID: "t2"},
+		}}
+		router := gin.New()
+		router.Use(middleware.DryRun("POST /tracks/batch-delete"))
+		h := NewTrackHandler(repo, nil, nil, validator.NewValidator(), nil)
+		router.POST("/tracks/batch-delete", h.BatchDeleteTracks)
+		return router, repo
+	}
+	batchDelete := func(router *gin.Engine, query string) (*httptest.ResponseRecorder, BatchDeleteResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/tracks/batch-delete"+query,
+			strings.NewReader(`{"track_ids":["t1","t1","missing","t2"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp BatchDeleteResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp
+	}
+
+	t.Run("deletes", func(t *testing.T) {
+		router, repo := newRouter()
+		w, resp := batchDelete(router, "")
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.False(t, resp.DryRun)
+		assert.Equal(t, 2, resp.Deleted)
+		assert.Equal(t, []BatchDeleteResult{
+			{TrackID: "t1", Status: BatchDeleteDeleted},
+			{TrackID: "missing", Status: BatchDeleteNotFound},
+			{TrackID: "t2", Status: BatchDeleteDeleted},
+		}, resp.Results)
+		assert.Empty(t, repo.tracks)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		router, repo := newRouter()
+		w, resp := batchDelete(router, "?dry_run=true")
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.True(t, resp.DryRun)
+		assert.Equal(t, 2, resp.Deleted)
+		assert.Len(t, repo.tracks, 2, "a dry run deletes nothing")
+	})
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+)
+
+// SetCustomFields enforces the custom field schemas of labels on track
+// writes, and types custom fields in searches and exports, with fields
+func (h *TrackHandler) SetCustomFields(fields *usecase.CustomFieldUseCase) {
+	h.customFields = fields
+}
+
+// enforceCustomFields applies the schema of the track's label to its
+// custom fields. Without schemas every custom field is accepted.
+func (h *TrackHandler) enforceCustomFields(ctx context.Context, track *domain.Track) ([]domain.ValidationError, *apperrors.AppError) {
+	if h.customFields == nil {
+		return nil, nil
+	}
+	errs, err := h.customFields.Enforce(ctx, track)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to load custom fields", err)
+	}
+	return errs, nil
+}
+
+// customFieldSchemas returns the custom field schemas of the labels of
+// tracks, or nil without schemas
+func (h *TrackHandler) customFieldSchemas(ctx context.Context, tracks []*domain.Track) (map[string]domain.CustomFieldSchema, *apperrors.AppError) {
+	if h.customFields == nil {
+		return nil, nil
+	}
+	schemas, err := h.customFields.Schemas(ctx, tracks)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("failed to load custom fields", err)
+	}
+	return schemas, nil
+}
+
+// searchTerms returns the repository query of a search. Custom field
+// values are typed by the schema of the label searched in.
+func (h *TrackHandler) searchTerms(ctx context.Context, query *SearchQuery) (map[string]interface{}, *apperrors.AppError) {
+	terms := query.toMap()
+	if query.Filter != nil {
+		if errs := query.Filter.Validate(); len(errs) > 0 {
+			return nil, apperrors.NewFieldValidationError("invalid search filter", fieldErrors(errs))
+		}
+		terms[domain.SearchFilterKey] = query.Filter
+	}
+	if len(query.CustomFields) == 0 {
+		return terms, nil
+	}
+
+	var schema domain.CustomFieldSchema
+	if h.customFields != nil {
+		var err error
+		if schema, err = h.customFields.Schema(ctx, query.LabelID); err != nil {
+			return nil, apperrors.NewDatabaseError("failed to load custom fields", err)
+		}
+	}
+	custom, errs := schema.SearchTerms(query.CustomFields)
+	if len(errs) > 0 {
+		return nil, apperrors.NewFieldValidationError("invalid search query", fieldErrors(errs))
+	}
+	for key, value := range custom {
+		terms[key] = value
+	}
+	return terms, nil
+}
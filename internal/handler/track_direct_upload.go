@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/pkg/metrics"
+	"metadatatool/internal/pkg/utils"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UploadURLRequest describes an audio file the client is about to upload
+// straight to storage
+type UploadURLRequest struct {
+	Filename string `json:"filename" binding:"required"`
+	Size     int64  `json:"size" binding:"required,gt=0"`
+	Title    string `json:"title" binding:"required"`
+	Artist   string `json:"artist" binding:"required"`
+	Album    string `json:"album,omitempty"`
+}
+
+// UploadURLResponse holds the draft track created for a direct upload and
+// the presigned request that uploads its file
+type UploadURLResponse struct {
+	Track  *domain.Track        `json:"track"`
+	Upload *domain.SignedUpload `json:"upload"`
+}
+
+// CreateUploadURL starts a direct upload
+// @Summary Create direct upload URL
+// @Description Create a draft track and a presigned URL the client uploads the audio file to, bypassing the API. Once the upload is done, POST /tracks/{id}/confirm-upload finalizes the track.
+// @Tags tracks
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Key under which repeats of this request return the original response"
+// @Param request body UploadURLRequest true "File to upload"
+// @Success 201 {object} UploadURLResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Storage quota exceeded"
+// @Failure 500 {object} ErrorResponse
+// @Failure 501 {object} ErrorResponse
+// @Router /tracks/upload-url [post]
+func (h *TrackHandler) CreateUploadURL(c *gin.Context) {
+	start := time.Now()
+	defer func() {
+		metrics.DatabaseOperationsTotal.WithLabelValues("upload_url", "total").Inc()
+		metrics.DatabaseQueryDuration.WithLabelValues("upload_url").Observe(time.Since(start).Seconds())
+	}()
+
+	signer, ok := h.storageService.(domain.UploadSigner)
+	if !ok {
+		h.handleError(c, apperrors.NewNotImplementedError("direct uploads are not supported by the storage backend"))
+		return
+	}
+
+	var req UploadURLRequest
+	if err := bindJSON(c, &req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	if !utils.IsValidAudioFormat(req.Filename) {
+		h.handleError(c, apperrors.NewValidationError("invalid audio format", ""))
+		return
+	}
+
+	trackID := uuid.New().String()
+	audioFormat := utils.GetAudioFormat(req.Filename)
+	storageKey, _ := h.uploadKey(fmt.Sprintf("tracks/%s/audio%s", trackID, filepath.Ext(req.Filename)))
+	metadata, errs := domain.NewMetadataBuilder().
+		Title(req.Title).
+		Artist(req.Artist).
+		Album(req.Album).
+		Format(domain.AudioFormat(audioFormat)).
+		File(req.Size, domain.Checksums{}).
+		Build()
+	if len(errs) > 0 {
+		h.handleError(c, apperrors.NewFieldValidationError("invalid track data", fieldErrors(errs)))
+		return
+	}
+	track := &domain.Track{
+		ID:          trackID,
+		StoragePath: storageKey,
+		FileSize:    req.Size,
+		CreatedAt:   metadata.CreatedAt,
+		UpdatedAt:   metadata.UpdatedAt,
+		Status:      domain.TrackStatusDraft,
+		StatusMsg:   "awaiting upload",
+		Version:     1,
+		Metadata:    metadata,
+	}
+
+	result := h.validator.Validate(track)
+	if !result.IsValid {
+		h.handleError(c, apperrors.NewFieldValidationError("invalid track data", fieldErrors(result.Errors)))
+		return
+	}
+
+	if err := h.reserveQuota(c, trackID, req.Size); err != nil {
+		h.handleError(c, apperrors.FromError(err, "failed to check storage quota"))
+		return
+	}
+
+	upload, err := signer.SignUpload(c.Request.Context(), track.StoragePath, "audio/"+audioFormat, req.Size)
+	if err != nil {
+		h.releaseQuota(c, trackID)
+		var storageErr *domain.StorageError
+		if errors.As(err, &storageErr) {
+			h.handleError(c, apperrors.NewValidationError(storageErr.Message, storageErr.Code))
+			return
+		}
+		h.handleError(c, apperrors.NewStorageError("failed to create upload URL", err))
+		return
+	}
+
+	if err := h.trackRepo.Create(c, track); err != nil {
+		h.releaseQuota(c, trackID)
+		h.handleError(c, apperrors.NewDatabaseError("failed to create track", err))
+		return
+	}
+
+	c.Header("ETag", trackETag(track))
+	c.JSON(http.StatusCreated, UploadURLResponse{Track: track, Upload: upload})
+}
+
+// ConfirmUpload finalizes a track whose file was uploaded directly
+// @Summary Confirm direct upload
+// @Description Finalize a draft track created by POST /tracks/upload-url once its file is in storage. The track becomes pending and is queued for enrichment, after a malware scan when uploads are scanned. Confirming a track again returns it unchanged.
+// @Tags tracks
+// @Produce json
+// @Param id path string true "Track ID"
+// @Success 200 {object} domain.Track
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/{id}/confirm-upload [post]
+func (h *TrackHandler) ConfirmUpload(c *gin.Context) {
+	start := time.Now()
+	defer func() {
+		metrics.DatabaseOperationsTotal.WithLabelValues("confirm_upload", "total").Inc()
+		metrics.DatabaseQueryDuration.WithLabelValues("confirm_upload").Observe(time.Since(start).Seconds())
+	}()
+
+	track, err := h.trackRepo.GetByID(c, c.Param("id"))
+	if err != nil {
+		h.handleError(c, apperrors.NewDatabaseError("failed to get track", err))
+		return
+	}
+	if track == nil {
+		h.handleError(c, apperrors.NewNotFoundError("track not found"))
+		return
+	}
+
+	if track.Status != domain.TrackStatusDraft {
+		// A repeated confirmation finds the track already finalized
+		if track.Status == domain.TrackStatusPending {
+			c.Header("ETag", trackETag(track))
+			c.JSON(http.StatusOK, track)
+			return
+		}
+		h.handleError(c, apperrors.NewConflictError("track is not awaiting an upload", "status is "+track.Status.String()))
+		return
+	}
+
+	file, err := h.storageService.GetMetadata(c.Request.Context(), track.StoragePath)
+	if err != nil {
+		h.handleError(c, apperrors.NewConflictError("file has not been uploaded", err.Error()).WithCode(apperrors.CodeUploadNotFound))
+		return
+	}
+
+	track.FileSize = file.Size
+	track.Metadata.Technical.FileSize = file.Size
+	track.Metadata.Technical.EncryptionKeyID = file.EncryptionKeyID
+	statusMsg := ""
+	if h.uploadScanner != nil && domain.IsQuarantined(track.StoragePath) {
+		statusMsg = scanPendingMsg
+	}
+	if err := track.SetStatus(domain.TrackStatusPending, statusMsg); err != nil {
+		h.handleError(c, apperrors.NewInternalError("failed to finalize track", err))
+		return
+	}
+	if err := h.trackRepo.Update(c, track); err != nil {
+		var conflict *domain.VersionConflictError
+		if errors.As(err, &conflict) {
+			h.handleConflict(c, conflict)
+			return
+		}
+		h.handleError(c, apperrors.NewDatabaseError("failed to update track", err))
+		return
+	}
+
+	// The file reached storage without passing the API, so count it here
+	if tracker, ok := h.storageService.(domain.UsageTracker); ok {
+		tracker.AddUsage(c.Request.Context(), file.Size)
+	}
+
+	// Quarantined files are replicated once the scan moves them
+	if replicator, ok := h.storageService.(domain.StorageReplicator); ok && statusMsg == "" {
+		if err := replicator.Replicate(c.Request.Context(), track.StoragePath); err != nil {
+			h.errorTracker.CaptureErrorContext(c.Request.Context(), err, map[string]string{
+				"operation": "storage_replicate",
+				"track_id":  track.ID,
+			})
+		}
+	}
+
+	if statusMsg != "" {
+		h.uploadScanner.Submit(track.ID)
+	} else if h.aiService != nil {
+		enrich := track.Clone()
+		h.background.Go(c.Request.Context(), "ai_enrich", map[string]string{"track_id": enrich.ID}, func(ctx context.Context) error {
+			return h.aiService.EnrichMetadata(ctx, enrich)
+		})
+	}
+
+	c.Header("ETag", trackETag(track))
+	c.JSON(http.StatusOK, track)
+}
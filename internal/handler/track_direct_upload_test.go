@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signingStorage presigns uploads and reports the files it has been given
+type signingStorage struct {
+	domain.StorageService
+	files map[string]int64
+}
+
+func (s *signingStorage) SignUpload(_ context.Context, key, contentType string, size int64) (*domain.SignedUpload, error) {
+	if size > 1000 {
+		return nil, &domain.StorageError{Code: "FILE_TOO_LARGE", Message: "file too large"}
+	}
+	return &domain.SignedUpload{
+		URL:       "https://bucket.example.com/" + key + "?X-Amz-Signature=sig",
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": contentType},
+		ExpiresAt: time.Now().Add(time.Minute),
+	}, nil
+}
+
+func (s *signingStorage) GetMetadata(_ context.Context, key string) (*domain.FileMetadata, error) {
+	size, ok := s.files[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &domain.FileMetadata{Key: key, Size: size}, nil
+}
+
+// creatingTrackRepository adds Create to stubTrackRepository
+type creatingTrackRepository struct {
+	stubTrackRepository
+}
+
+func (r *creatingTrackRepository) Create(_ context.Context, track *domain.Track) error {
+	r.tracks[track.ID] = track.Clone()
+	return nil
+}
+
+func TestDirectUpload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(storage domain.StorageService) (*gin.Engine, *creatingTrackRepository) {
+		repo := &creatingTrackRepository{stubTrackRepository{tracks: map[string]*domain.Track{}}}
+		h := NewTrackHandler(repo, nil, storage, validator.NewValidator(), nil)
+		router := gin.New()
+		router.POST("/tracks/upload-url", h.CreateUploadURL)
+		router.POST("/tracks/:id/confirm-upload", h.ConfirmUpload)
+		return router, repo
+	}
+	post := func(router *gin.Engine, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("upload and confirm", func(t *testing.T) {
+		storage := &signingStorage{files: map[string]int64{}}
+		router, repo := newRouter(storage)
+
+		w := post(router, "/tracks/upload-url", `{"filename":"song.mp3","size":512,"title":"Song","artist":"Artist"}`)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var resp UploadURLResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, domain.TrackStatusDraft, resp.Track.Status)
+		assert.Equal(t, http.MethodPut, resp.Upload.Method)
+		assert.Equal(t, "audio/mp3", resp.Upload.Headers["Content-Type"])
+		assert.Contains(t, resp.Upload.URL, resp.Track.StoragePath)
+		require.Contains(t, repo.tracks, resp.Track.ID)
+
+		confirm := "/tracks/" + resp.Track.ID + "/confirm-upload"
+		w = post(router, confirm, "")
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "UPLOAD_NOT_FOUND")
+
+		storage.files[resp.Track.StoragePath] = 510
+		w = post(router, confirm, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		stored := repo.tracks[resp.Track.ID]
+		assert.Equal(t, domain.TrackStatusPending, stored.Status)
+		assert.Equal(t, int64(510), stored.FileSize)
+		assert.Equal(t, 2, stored.Version)
+
+		w = post(router, confirm, "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 2, repo.tracks[resp.Track.ID].Version)
+	})
+
+	t.Run("rejects a file that is too large", func(t *testing.T) {
+		router, repo := newRouter(&signingStorage{})
+		w := post(router, "/tracks/upload-url", `{"filename":"song.mp3","size":5000,"title":"Song","artist":"Artist"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, repo.tracks)
+	})
+
+	t.Run("rejects a file that is not audio", func(t *testing.T) {
+		router, _ := newRouter(&signingStorage{})
+		w := post(router, "/tracks/upload-url", `{"filename":"song.exe","size":10,"title":"Song","artist":"Artist"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("normalizes the metadata", func(t *testing.T) {
+		router, repo := newRouter(&signingStorage{})
+		w := post(router, "/tracks/upload-url", `{"filename":"song.flac","size":10,"title":" Song ","artist":"Artist"}`)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var resp UploadURLResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		stored := repo.tracks[resp.Track.ID]
+		assert.Equal(t, "Song", stored.Title())
+		assert.Equal(t, "flac", stored.AudioFormat())
+	})
+
+	t.Run("rejects a blank artist", func(t *testing.T) {
+		router, repo := newRouter(&signingStorage{})
+		w := post(router, "/tracks/upload-url", `{"filename":"song.mp3","size":10,"title":"Song","artist":"  "}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "artist")
+		assert.Empty(t, repo.tracks)
+	})
+
+	t.Run("storage without direct uploads", func(t *testing.T) {
+		router, _ := newRouter(struct{ domain.StorageService }{})
+		w := post(router, "/tracks/upload-url", `{"filename":"song.mp3","size":10,"title":"Song","artist":"Artist"}`)
+		assert.Equal(t, http.StatusNotImplemented, w.Code)
+	})
+
+	t.Run("unknown track", func(t *testing.T) {
+		router, _ := newRouter(&signingStorage{})
+		w := post(router, "/tracks/missing/confirm-upload", "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
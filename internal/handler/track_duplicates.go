@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProbableDuplicatesHeader lists the IDs of the tracks a new track
+// probably duplicates
+const ProbableDuplicatesHeader = "Probable-Duplicates"
+
+// DuplicatesResponse lists the probable duplicates of a track
+type DuplicatesResponse struct {
+	TrackID    string                      `json:"track_id"`
+	Duplicates []domain.DuplicateCandidate `json:"duplicates"`
+}
+
+// GetTrackDuplicates lists the probable duplicates of a track
+// @Summary List probable duplicates
+// @Description List the tracks that probably duplicate a track: those with its ISRC, and those whose normalized title and artist are within a small edit distance or whose artists sound alike (Soundex). Scores run from 0 to 1.
+// @Tags tracks
+// @Produce json
+// @Param id path string true "Track ID"
+// @Success 200 {object} DuplicatesResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/{id}/duplicates [get]
+func (h *TrackHandler) GetTrackDuplicates(c *gin.Context) {
+	duplicates, err := h.duplicates.ForTrack(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, apperrors.FromError(err, "failed to find duplicates"))
+		return
+	}
+	if duplicates == nil {
+		duplicates = []domain.DuplicateCandidate{}
+	}
+	c.JSON(http.StatusOK, DuplicatesResponse{TrackID: c.Param("id"), Duplicates: duplicates})
+}
+
+// TrackMergeRequest picks where the fields of merged tracks come from
+type TrackMergeRequest struct {
+	// Fields maps field names to "fill" (the default: keep the target's
+	// value unless it is empty), "target", "source" or, for tags and
+	// artist_ids, "combine" (their default)
+	Fields domain.MergeRules `json:"fields,omitempty"`
+}
+
+// TrackMergeResponse reports a merge
+type TrackMergeResponse struct {
+	Track interface{} `json:"track"`
+	// RedirectFrom is the ID of the merged track, which now resolves to
+	// the target
+	RedirectFrom string `json:"redirect_from"`
+	// References counts the references moved to the target, such as
+	// deliveries and play counts
+	References map[string]int64 `json:"references"`
+	MovedAudio bool             `json:"moved_audio"`
+}
+
+// MergeTrack merges a track into another
+// @Summary Merge tracks
+// @Description Merge a track into another. The fields of the target are picked by the rules of the request, filling its empty fields from the merged track by default; tags, artists and custom fields are combined. Deliveries and play counts of the merged track move to the target, as does its audio file when the target has none. The merged track is deleted and its ID redirects to the target.
+// @Tags tracks
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the track merged and deleted"
+// @Param target_id path string true "ID of the track kept"
+// @Param request body TrackMergeRequest false "Field pick rules"
+// @Success 200 {object} TrackMergeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/{id}/merge-into/{target_id} [post]
+func (h *TrackHandler) MergeTrack(c *gin.Context) {
+	var req TrackMergeRequest
+	if c.Request.ContentLength != 0 {
+		if err := bindJSON(c, &req); err != nil {
+			h.handleError(c, err)
+			return
+		}
+	}
+	if errs := req.Fields.Validate(); len(errs) > 0 {
+		h.handleError(c, apperrors.NewFieldValidationError("invalid merge rules", fieldErrors(errs)))
+		return
+	}
+
+	result, err := h.duplicates.Merge(c.Request.Context(), c.Param("id"), c.Param("target_id"), req.Fields, c.GetString("user_id"))
+	if err != nil {
+		var conflict *domain.VersionConflictError
+		if errors.As(err, &conflict) {
+			h.handleConflict(c, conflict)
+			return
+		}
+		h.handleError(c, apperrors.FromError(err, "failed to merge tracks"))
+		return
+	}
+
+	c.Header("ETag", trackETag(result.Track))
+	c.JSON(http.StatusOK, TrackMergeResponse{
+		Track:        trackMapperFor(c).track(result.Track),
+		RedirectFrom: result.SourceID,
+		References:   result.References,
+		MovedAudio:   result.MovedAudio,
+	})
+}
+
+// flagDuplicates sets the Probable-Duplicates header when the track being
+// created probably duplicates catalog tracks. Detection failures do not
+// stop the creation.
+func (h *TrackHandler) flagDuplicates(c *gin.Context, track *domain.Track) {
+	duplicates, err := h.duplicates.Check(c.Request.Context(), track)
+	if err != nil {
+		log.Printf("failed to check track %s for duplicates: %v", track.ID, err)
+		return
+	}
+	if len(duplicates) == 0 {
+		return
+	}
+	ids := make([]string, len(duplicates))
+	for i, d := range duplicates {
+		ids[i] = d.TrackID
+	}
+	c.Header(ProbableDuplicatesHeader, strings.Join(ids, ", "))
+}
+
+// redirectMerged answers a request for a missing track: with a permanent
+// redirect to the track it was merged into, or with 404
+func (h *TrackHandler) redirectMerged(c *gin.Context, id string) {
+	targetID, err := h.duplicates.Resolve(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, apperrors.NewDatabaseError("failed to get track", err))
+		return
+	}
+	if targetID == "" {
+		h.handleError(c, apperrors.NewNotFoundError("track not found"))
+		return
+	}
+
+	location := *c.Request.URL
+	location.Path = strings.TrimSuffix(location.Path, id) + targetID
+	c.Redirect(http.StatusMovedPermanently, location.RequestURI())
+}
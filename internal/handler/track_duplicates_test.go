@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/validator"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubTrackRedirectRepository keeps track redirects in memory
+type stubTrackRedirectRepository struct {
+	redirects map[string]*domain.TrackRedirect
+}
+
+func (r *stubTrackRedirectRepository) Save(_ context.Context, redirect *domain.TrackRedirect) error {
+	r.redirects[redirect.SourceID] = redirect
+	return nil
+}
+
+func (r *stubTrackRedirectRepository) Get(_ context.Context, sourceID string) (*domain.TrackRedirect, error) {
+	return r.redirects[sourceID], nil
+}
+
+func (r *stubTrackRedirectRepository) RewriteTrackReferences(_ context.Context, fromID, toID string) (int64, error) {
+	return 0, nil
+}
+
+func TestMergeTrack(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	source := &domain.Track{ID: "t1", Version: 1}
+	source.SetTitle("Song")
+	source.SetArtist("Artist")
+	source.SetGenre("Pop")
+	target := &domain.Track{ID: "t2", Version: 4}
+	target.SetTitle("Song (Remastered)")
+	target.SetArtist("The Artist")
+	repo := &stubTrackRepository{tracks: map[string]*domain.Track{"t1": source, "t2": target}}
+
+	h := NewTrackHandler(repo, nil, nil, validator.NewValidator(), nil)
+	duplicates := usecase.NewDuplicateUseCase(repo)
+	duplicates.SetRedirects(&stubTrackRedirectRepository{redirects: map[string]*domain.TrackRedirect{}})
+	h.SetDuplicates(duplicates)
+	router := gin.New()
+	router.GET("/api/v1/tracks/:id", h.GetTrack)
+	router.POST("/api/v1/tracks/:id/merge-into/:target_id", h.MergeTrack)
+
+	t.Run("rejects unknown picks", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tracks/t1/merge-into/t2", strings.NewReader(`{"fields":{"genre":"both"}}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "fields.genre")
+	})
+
+	t.Run("merges and redirects the merged ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tracks/t1/merge-into/t2", strings.NewReader(`{"fields":{"title":"source"}}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+		assert.Contains(t, w.Body.String(), `"redirect_from":"t1"`)
+		stored := repo.tracks["t2"]
+		assert.Equal(t, "Song", stored.Title())
+		assert.Equal(t, "Pop", stored.Genre())
+		assert.NotContains(t, repo.tracks, "t1")
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/tracks/t1?fields=title", nil))
+		assert.Equal(t, http.StatusMovedPermanently, w.Code)
+		assert.Equal(t, "/api/v1/tracks/t2?fields=title", w.Header().Get("Location"))
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/tracks/t3", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fieldsTrackRepository records the field selection of the list query
+type fieldsTrackRepository struct {
+	domain.TrackRepository
+	tracks []*domain.Track
+	fields []string
+}
+
+func (r *fieldsTrackRepository) List(ctx context.Context, _ map[string]interface{}, _, _ int) ([]*domain.Track, error) {
+	r.fields = domain.TrackFieldsFromContext(ctx)
+	return r.tracks, nil
+}
+
+func TestListTracks_Fields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	track := &domain.Track{ID: "t1", Status: domain.TrackStatusActive, StoragePath: "audio/t1.mp3"}
+	track.SetTitle("Song")
+	track.SetArtist("Artist")
+	repo := &fieldsTrackRepository{tracks: []*domain.Track{track}}
+	router := gin.New()
+	router.GET("/tracks", NewTrackHandler(repo, nil, nil, nil, nil).ListTracks)
+
+	t.Run("sparse tracks", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tracks?fields=id,title,artist,status", nil))
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, []string{"id", "title", "artist", "status"}, repo.fields)
+		assert.Equal(t, []string{"id", "metadata", "status"}, domain.TrackFieldColumns(repo.fields))
+
+		var body struct {
+			Tracks []map[string]interface{} `json:"tracks"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, []map[string]interface{}{
+			{"id": "t1", "title": "Song", "artist": "Artist", "status": "active"},
+		}, body.Tracks)
+	})
+
+	t.Run("full tracks without fields", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tracks", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Nil(t, repo.fields)
+		assert.Contains(t, w.Body.String(), `"storagePath":"audio/t1.mp3"`)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tracks?fields=id,storage_path", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+// sortTrackRepository records the sort of the list query
+type sortTrackRepository struct {
+	domain.TrackRepository
+	sort []domain.TrackSortKey
+}
+
+func (r *sortTrackRepository) List(ctx context.Context, _ map[string]interface{}, _, _ int) ([]*domain.Track, error) {
+	r.sort = domain.TrackSortFromContext(ctx)
+	return []*domain.Track{}, nil
+}
+
+func TestListTracks_Sort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &sortTrackRepository{}
+	router := gin.New()
+	router.GET("/tracks", NewTrackHandler(repo, nil, nil, nil, nil).ListTracks)
+	list := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tracks"+query, nil))
+		return w
+	}
+
+	w := list("?sort=artist,-created_at")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []domain.TrackSortKey{{Field: "artist"}, {Field: "created_at", Desc: true}}, repo.sort)
+
+	w = list("")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, repo.sort)
+
+	for _, sort := range []string{"storage_path", "tags", "artist,-artist", "title,artist,album,year"} {
+		assert.Equal(t, http.StatusBadRequest, list("?sort="+sort).Code, sort)
+	}
+}
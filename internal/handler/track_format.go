@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Media types tracks can be served as, chosen with the Accept header
+const (
+	mediaTypeJSON   = "application/json"
+	mediaTypeXML    = "application/xml"
+	mediaTypeCSV    = "text/csv"
+	mediaTypeNDJSON = "application/x-ndjson"
+)
+
+// trackFormats maps the media types tracks can be served as to the name of
+// the format, which is also the extension of exported files. XML is a DDEX
+// ERN message.
+var trackFormats = map[string]string{
+	mediaTypeJSON:   "json",
+	mediaTypeXML:    "xml",
+	mediaTypeCSV:    "csv",
+	mediaTypeNDJSON: "ndjson",
+}
+
+var trackCSVHeader = []string{"ID", "Title", "Artist", "Album", "ISRC", "Duration", "Created At"}
+
+// negotiateTrackFormat returns the format named by the Accept header of the
+// request, JSON when the client accepts anything
+func negotiateTrackFormat(c *gin.Context) (string, *apperrors.AppError) {
+	if strings.TrimSpace(c.GetHeader("Accept")) == "" {
+		return "json", nil
+	}
+	mediaType := c.NegotiateFormat(mediaTypeJSON, mediaTypeXML, mediaTypeCSV, mediaTypeNDJSON)
+	if mediaType == "" {
+		return "", apperrors.NewNotAcceptableError("unsupported Accept header",
+			fmt.Sprintf("tracks can be served as %s, %s, %s or %s", mediaTypeJSON, mediaTypeXML, mediaTypeCSV, mediaTypeNDJSON))
+	}
+	return trackFormats[mediaType], nil
+}
+
+// ExportedTrack is an exported track with the custom fields its label
+// defines as typed values
+type ExportedTrack struct {
+	*domain.Track
+	Custom map[string]interface{} `json:"custom,omitempty"`
+}
+
+// exportedTracks types the custom fields of tracks with the schemas of
+// their labels
+func exportedTracks(tracks []*domain.Track, schemas map[string]domain.CustomFieldSchema) []ExportedTrack {
+	exported := make([]ExportedTrack, len(tracks))
+	for i, track := range tracks {
+		exported[i] = ExportedTrack{Track: track}
+		if schema := schemas[track.LabelID]; len(schema) > 0 {
+			exported[i].Custom = schema.Typed(track)
+		}
+	}
+	return exported
+}
+
+// customFieldColumns returns the sorted names of the custom fields the
+// schemas define
+func customFieldColumns(schemas map[string]domain.CustomFieldSchema) []string {
+	seen := make(map[string]bool)
+	var columns []string
+	for _, schema := range schemas {
+		for _, def := range schema {
+			if !seen[def.Name] {
+				seen[def.Name] = true
+				columns = append(columns, def.Name)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// writeTracks answers with tracks in format, which must not be JSON. An
+// error is only returned while nothing has been written yet.
+func writeTracks(c *gin.Context, format string, tracks []*domain.Track) error {
+	return writeExportedTracks(c, format, tracks, nil)
+}
+
+// writeExportedTracks answers like writeTracks, adding the custom fields
+// defined by the schemas of the labels of tracks
+func writeExportedTracks(c *gin.Context, format string, tracks []*domain.Track, schemas map[string]domain.CustomFieldSchema) error {
+	c.Header("Vary", "Accept")
+	switch format {
+	case "xml":
+		data, err := xml.MarshalIndent(convertTracksToERN(tracks), "", "  ")
+		if err != nil {
+			return err
+		}
+		c.Data(http.StatusOK, mediaTypeXML, append([]byte(xml.Header), data...))
+	case "csv":
+		c.Header("Content-Type", mediaTypeCSV)
+		c.Status(http.StatusOK)
+		w := csv.NewWriter(c.Writer)
+		columns := customFieldColumns(schemas)
+		_ = w.Write(trackCSVHeaderFor(columns))
+		for _, track := range tracks {
+			_ = w.Write(trackCSVRowFor(track, schemas[track.LabelID], columns))
+		}
+		w.Flush()
+	case "ndjson":
+		c.Header("Content-Type", mediaTypeNDJSON)
+		c.Status(http.StatusOK)
+		enc := json.NewEncoder(c.Writer)
+		for _, track := range exportedTracks(tracks, schemas) {
+			_ = enc.Encode(track)
+		}
+	default:
+		return fmt.Errorf("unsupported track format %q", format)
+	}
+	return nil
+}
+
+// trackCSVHeaderFor returns the CSV header with a column for each of the
+// custom fields named by columns
+func trackCSVHeaderFor(columns []string) []string {
+	header := append([]string{}, trackCSVHeader...)
+	for _, name := range columns {
+		header = append(header, domain.CustomFieldPrefix+name)
+	}
+	return header
+}
+
+// trackCSVRowFor returns the CSV row of a track with the values of the
+// custom fields named by columns that schema, the track's label's, defines
+func trackCSVRowFor(track *domain.Track, schema domain.CustomFieldSchema, columns []string) []string {
+	row := trackCSVRow(track)
+	for _, name := range columns {
+		value := ""
+		if schema.Field(name) != nil {
+			value = track.Metadata.Additional.CustomFields[name]
+		}
+		row = append(row, value)
+	}
+	return row
+}
+
+func trackCSVRow(track *domain.Track) []string {
+	return []string{
+		track.ID,
+		track.Title(),
+		track.Artist(),
+		track.Album(),
+		track.ISRC(),
+		fmt.Sprintf("%d", int(track.Duration())),
+		track.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateTrackFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		accept string
+		format string
+	}{
+		{"", "json"},
+		{"*/*", "json"},
+		{"application/json", "json"},
+		{"application/xml", "xml"},
+		{"text/csv; q=0.9", "csv"},
+		{"application/x-ndjson", "ndjson"},
+		{"image/png, text/*", "csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/tracks/1", nil)
+			c.Request.Header.Set("Accept", tt.accept)
+
+			format, err := negotiateTrackFormat(c)
+			require.Nil(t, err)
+			assert.Equal(t, tt.format, format)
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/tracks/1", nil)
+		c.Request.Header.Set("Accept", "image/png")
+
+		_, err := negotiateTrackFormat(c)
+		require.NotNil(t, err)
+		assert.Equal(t, http.StatusNotAcceptable, err.StatusCode)
+	})
+}
+
+func TestWriteTracks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	track := &domain.Track{ID: "t1", CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	track.SetTitle("Song")
+	track.SetISRC("USRC17607839")
+	tracks := []*domain.Track{track, {ID: "t2", CreatedAt: track.CreatedAt}}
+
+	t.Run("csv", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		require.NoError(t, writeTracks(c, "csv", tracks))
+
+		assert.Equal(t, mediaTypeCSV, w.Header().Get("Content-Type"))
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		require.Len(t, lines, 3)
+		assert.Equal(t, "ID,Title,Artist,Album,ISRC,Duration,Created At", lines[0])
+		assert.Equal(t, "t1,Song,,,USRC17607839,0,2024-01-02T03:04:05Z", lines[1])
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		require.NoError(t, writeTracks(c, "ndjson", tracks))
+
+		assert.Equal(t, mediaTypeNDJSON, w.Header().Get("Content-Type"))
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		require.Len(t, lines, 2)
+		var decoded domain.Track
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &decoded))
+		assert.Equal(t, "t1", decoded.ID)
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		require.NoError(t, writeTracks(c, "xml", tracks))
+
+		assert.Equal(t, mediaTypeXML, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), "<isrc>USRC17607839</isrc>")
+		assert.Equal(t, "Accept", w.Header().Get("Vary"))
+	})
+}
+
+func TestWriteExportedTracks_TypesCustomFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	track := &domain.Track{ID: "t1", LabelID: "label-1", CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	track.Metadata.Additional.CustomFields = map[string]string{"mood_score": "7.5", "explicit_lyrics": "true", "label": "Night Owl"}
+	other := &domain.Track{ID: "t2", LabelID: "label-2", CreatedAt: track.CreatedAt}
+	schemas := map[string]domain.CustomFieldSchema{
+		"label-1": {
+			{Name: "mood_score", Type: domain.CustomFieldNumber},
+			{Name: "explicit_lyrics", Type: domain.CustomFieldBoolean},
+		},
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		require.NoError(t, writeExportedTracks(c, "csv", []*domain.Track{track, other}, schemas))
+
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		require.Len(t, lines, 3)
+		assert.Equal(t, "ID,Title,Artist,Album,ISRC,Duration,Created At,custom.explicit_lyrics,custom.mood_score", lines[0])
+		assert.Equal(t, "t1,,,,,0,2024-01-02T03:04:05Z,true,7.5", lines[1])
+		assert.Equal(t, "t2,,,,,0,2024-01-02T03:04:05Z,,", lines[2])
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		require.NoError(t, writeExportedTracks(c, "ndjson", []*domain.Track{track, other}, schemas))
+
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		require.Len(t, lines, 2)
+		var decoded struct {
+			ID     string                 `json:"id"`
+			Custom map[string]interface{} `json:"custom"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &decoded))
+		assert.Equal(t, "t1", decoded.ID)
+		assert.Equal(t, map[string]interface{}{"mood_score": 7.5, "explicit_lyrics": true}, decoded.Custom)
+		assert.NotContains(t, lines[1], `"custom"`)
+	})
+}
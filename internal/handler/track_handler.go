@@ -1,12 +1,19 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"metadatatool/internal/pkg/analytics"
+	"metadatatool/internal/pkg/background"
 	"metadatatool/internal/pkg/domain"
 	apperrors "metadatatool/internal/pkg/errors"
 	"metadatatool/internal/pkg/errortracking"
 	"metadatatool/internal/pkg/metrics"
 	"metadatatool/internal/pkg/utils"
+	"metadatatool/internal/usecase"
 	"net/http"
 	"path/filepath"
 	"strconv"
@@ -24,6 +31,20 @@ type TrackHandler struct {
 	storageService domain.StorageService
 	validator      domain.Validator
 	errorTracker   *errortracking.ErrorTracker
+	analytics      analytics.EventRecorder
+	usage          domain.UsageRecorder
+	uploadScanner  *usecase.UploadScanner
+	quota          *usecase.StorageQuotaUseCase
+	background     *background.Runner
+	workflow       *usecase.TrackWorkflowUseCase
+	duplicates     *usecase.DuplicateUseCase
+	harmonic       *usecase.HarmonicMixUseCase
+	customFields   *usecase.CustomFieldUseCase
+	tags           *usecase.TagUseCase
+	trackLocks     *usecase.TrackLockUseCase
+	// streamURLExpiry is how long the signed URLs streams are redirected
+	// to stay valid; zero streams through the API
+	streamURLExpiry time.Duration
 }
 
 // NewTrackHandler creates a new track handler
@@ -40,6 +61,10 @@ func NewTrackHandler(
 		storageService: storageService,
 		validator:      validator,
 		errorTracker:   errorTracker,
+		background:     background.NewRunner(errorTracker, 0),
+		workflow:       usecase.NewTrackWorkflowUseCase(trackRepo),
+		duplicates:     usecase.NewDuplicateUseCase(trackRepo),
+		harmonic:       usecase.NewHarmonicMixUseCase(trackRepo),
 	}
 }
 
@@ -52,8 +77,10 @@ func NewTrackHandler(
 // @Param file formData file true "Audio file"
 // @Param title formData string true "Track title"
 // @Param artist formData string true "Artist name"
+// @Param Idempotency-Key header string false "Key under which repeats of this request return the original response"
 // @Success 201 {object} domain.Track
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Storage quota exceeded"
 // @Failure 500 {object} ErrorResponse
 // @Router /tracks/upload [post]
 func (h *TrackHandler) UploadTrack(c *gin.Context) {
@@ -65,92 +92,102 @@ func (h *TrackHandler) UploadTrack(c *gin.Context) {
 
 	file, header, err := c.Request.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "no file uploaded"})
+		apperrors.Respond(c, apperrors.NewValidationError("no file uploaded", ""))
 		return
 	}
 	defer file.Close()
 
 	if !utils.IsValidAudioFormat(header.Filename) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid audio format"})
+		apperrors.Respond(c, apperrors.NewValidationError("invalid audio format", ""))
 		return
 	}
 
 	trackID := uuid.New().String()
 	audioFormat := utils.GetAudioFormat(header.Filename)
-	storageKey := fmt.Sprintf("tracks/%s/audio%s", trackID, filepath.Ext(header.Filename))
+	storageKey, statusMsg := h.uploadKey(fmt.Sprintf("tracks/%s/audio%s", trackID, filepath.Ext(header.Filename)))
 
-	// Upload file to storage
+	// Upload file to storage, recording its checksums on the way for the
+	// enrichment cache and integrity checks
+	content := domain.NewChecksumReader(file)
 	storageFile := &domain.StorageFile{
 		Key:         storageKey,
 		Name:        header.Filename,
 		Size:        header.Size,
 		ContentType: "audio/" + audioFormat,
-		Content:     file,
+		Content:     content,
 		UploadedAt:  time.Now(),
 	}
 
+	if err := h.reserveQuota(c, trackID, header.Size); err != nil {
+		h.handleError(c, apperrors.FromError(err, "failed to check storage quota"))
+		return
+	}
+
 	if err := h.storageService.Upload(c.Request.Context(), storageFile); err != nil {
+		h.releaseQuota(c, trackID)
 		h.handleError(c, apperrors.NewInternalError("failed to upload file", err))
 		return
 	}
 
+	metadata, errs := domain.NewMetadataBuilder().
+		Title(c.PostForm("title")).
+		Artist(c.PostForm("artist")).
+		Album(c.PostForm("album")).
+		Format(domain.AudioFormat(audioFormat)).
+		File(header.Size, content.Sum()).
+		EncryptionKey(storageFile.EncryptionKeyID).
+		Build()
+	if len(errs) > 0 {
+		h.releaseQuota(c, trackID)
+		h.handleError(c, apperrors.NewFieldValidationError("invalid track data", fieldErrors(errs)))
+		return
+	}
 	track := &domain.Track{
 		ID:          trackID,
 		StoragePath: storageKey,
 		FileSize:    header.Size,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		CreatedAt:   metadata.CreatedAt,
+		UpdatedAt:   metadata.UpdatedAt,
 		Status:      domain.TrackStatusPending,
-		Metadata: domain.CompleteTrackMetadata{
-			BasicTrackMetadata: domain.BasicTrackMetadata{
-				Title:     c.PostForm("title"),
-				Artist:    c.PostForm("artist"),
-				Album:     c.PostForm("album"),
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
-			},
-			Technical: domain.AudioTechnicalMetadata{
-				Format:   domain.AudioFormat(audioFormat),
-				FileSize: header.Size,
-			},
-		},
+		StatusMsg:   statusMsg,
+		Metadata:    metadata,
 	}
 
 	// Validate track
 	result := h.validator.Validate(track)
 	if !result.IsValid {
-		details := make([]string, len(result.Errors))
-		for i, err := range result.Errors {
-			details[i] = fmt.Sprintf("%s: %s", err.Field, err.Message)
-		}
-		h.handleError(c, apperrors.NewValidationError("invalid track data", strings.Join(details, "; ")))
+		h.releaseQuota(c, trackID)
+		h.handleError(c, apperrors.NewFieldValidationError("invalid track data", fieldErrors(result.Errors)))
 		return
 	}
 
 	if err := h.trackRepo.Create(c, track); err != nil {
+		h.releaseQuota(c, trackID)
 		h.handleError(c, apperrors.NewDatabaseError("failed to create track", err))
 		return
 	}
 
-	// Trigger async AI processing
-	go func() {
-		if err := h.aiService.EnrichMetadata(c, track); err != nil {
-			h.errorTracker.CaptureError(err, map[string]string{
-				"operation": "ai_enrich",
-				"track_id":  track.ID,
-			})
-		}
-	}()
+	// Trigger async AI processing, after the malware scan when files are
+	// scanned
+	if h.uploadScanner != nil {
+		h.uploadScanner.Submit(track.ID)
+	} else {
+		enrich := track.Clone()
+		h.background.Go(c.Request.Context(), "ai_enrich", map[string]string{"track_id": enrich.ID}, func(ctx context.Context) error {
+			return h.aiService.EnrichMetadata(ctx, enrich)
+		})
+	}
 
 	c.JSON(http.StatusCreated, track)
 }
 
 // CreateTrack handles track creation requests
 // @Summary Create track
-// @Description Create a new track with metadata
+// @Description Create a new track with metadata. When the track probably duplicates catalog tracks, their IDs are listed in the Probable-Duplicates header; the track is created all the same.
 // @Tags tracks
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Key under which repeats of this request return the original response"
 // @Param track body domain.Track true "Track object"
 // @Success 201 {object} domain.Track
 // @Failure 400 {object} ErrorResponse
@@ -163,15 +200,28 @@ func (h *TrackHandler) CreateTrack(c *gin.Context) {
 		metrics.DatabaseQueryDuration.WithLabelValues("create").Observe(time.Since(start).Seconds())
 	}()
 
-	var track domain.Track
-	if err := c.ShouldBindJSON(&track); err != nil {
-		h.handleError(c, apperrors.NewValidationError("invalid request body", err.Error()))
+	mapper := trackMapperFor(c)
+	body, appErr := mapper.bind(c, nil)
+	if appErr != nil {
+		h.handleError(c, appErr)
 		return
 	}
+	track := *body
 
 	// Basic validation
-	if err := validateTrack(&track); err != nil {
-		h.handleError(c, apperrors.NewValidationError("invalid track data", err.Error()))
+	if errs := validateTrack(&track); len(errs) > 0 {
+		h.handleError(c, apperrors.NewFieldValidationError("invalid track data", versionedFieldErrors(c, errs)))
+		return
+	}
+	if errs, appErr := h.enforceCustomFields(c.Request.Context(), &track); appErr != nil || len(errs) > 0 {
+		if appErr == nil {
+			appErr = apperrors.NewFieldValidationError("invalid track data", versionedFieldErrors(c, errs))
+		}
+		h.handleError(c, appErr)
+		return
+	}
+	if appErr := h.tagTrack(c.Request.Context(), &track); appErr != nil {
+		h.handleError(c, appErr)
 		return
 	}
 
@@ -180,34 +230,40 @@ func (h *TrackHandler) CreateTrack(c *gin.Context) {
 	track.CreatedAt = time.Now()
 	track.UpdatedAt = time.Now()
 	track.Status = domain.TrackStatusPending
+	track.Version = 1
+	track.Metadata.Provenance = nil
+	track.RecordProvenance(domain.DiffTracks(&domain.Track{}, &track), domain.ProvenanceManual, c.GetString("user_id"))
 
 	// Additional validation using validator
 	result := h.validator.Validate(&track)
 	if !result.IsValid {
-		details := make([]string, len(result.Errors))
-		for i, err := range result.Errors {
-			details[i] = fmt.Sprintf("%s: %s", err.Field, err.Message)
-		}
-		h.handleError(c, apperrors.NewValidationError("invalid track data", strings.Join(details, "; ")))
+		h.handleError(c, apperrors.NewFieldValidationError("invalid track data", versionedFieldErrors(c, result.Errors)))
 		return
 	}
 
+	h.flagDuplicates(c, &track)
 	if err := h.trackRepo.Create(c, &track); err != nil {
 		h.handleError(c, apperrors.NewDatabaseError("failed to create track", err))
 		return
 	}
 
-	c.JSON(http.StatusCreated, track)
+	c.Header("ETag", trackETag(&track))
+	c.JSON(http.StatusCreated, mapper.track(&track))
 }
 
 // GetTrack retrieves a track by ID
 // @Summary Get track
-// @Description Get a track by ID
+// @Description Get a track by ID. The Accept header selects the representation: application/json (default), application/xml (DDEX ERN), text/csv or application/x-ndjson. The response carries ETag and Last-Modified; a request with a matching If-None-Match, or If-Modified-Since no older than the last change, is answered with 304. IDs of tracks merged into others are redirected to them with 301.
 // @Tags tracks
 // @Produce json
 // @Param id path string true "Track ID"
+// @Param If-None-Match header string false "ETag of the track version the client has"
+// @Param If-Modified-Since header string false "Time of the track version the client has"
 // @Success 200 {object} domain.Track
+// @Success 301 "Track merged into the track in Location"
+// @Success 304 "Not Modified"
 // @Failure 404 {object} ErrorResponse
+// @Failure 406 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /tracks/{id} [get]
 func (h *TrackHandler) GetTrack(c *gin.Context) {
@@ -223,6 +279,12 @@ func (h *TrackHandler) GetTrack(c *gin.Context) {
 		return
 	}
 
+	format, appErr := negotiateTrackFormat(c)
+	if appErr != nil {
+		h.handleError(c, appErr)
+		return
+	}
+
 	track, err := h.trackRepo.GetByID(c, id)
 	if err != nil {
 		h.handleError(c, apperrors.NewDatabaseError("failed to get track", err))
@@ -230,24 +292,43 @@ func (h *TrackHandler) GetTrack(c *gin.Context) {
 	}
 
 	if track == nil {
-		h.handleError(c, apperrors.NewNotFoundError("track not found"))
+		h.redirectMerged(c, id)
 		return
 	}
 
-	c.JSON(http.StatusOK, track)
+	c.Header("ETag", trackETag(track))
+	c.Header("Vary", "Accept")
+	if !track.UpdatedAt.IsZero() {
+		c.Header("Last-Modified", track.UpdatedAt.UTC().Format(http.TimeFormat))
+	}
+	if trackNotModified(c, track) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	if format == "json" {
+		h.annotateLocks(c, track)
+		c.JSON(http.StatusOK, trackMapperFor(c).track(track))
+		return
+	}
+	if err := writeTracks(c, format, []*domain.Track{track}); err != nil {
+		h.handleError(c, apperrors.NewInternalError("failed to encode track", err))
+	}
 }
 
 // UpdateTrack modifies an existing track
 // @Summary Update track
-// @Description Update an existing track
+// @Description Update an existing track. The expected version must be supplied via the If-Match header or the version field of the body.
 // @Tags tracks
 // @Accept json
 // @Produce json
 // @Param id path string true "Track ID"
+// @Param If-Match header string false "ETag of the track version being updated"
 // @Param track body domain.Track true "Track object"
 // @Success 200 {object} domain.Track
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ConflictResponse
+// @Failure 428 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /tracks/{id} [put]
 func (h *TrackHandler) UpdateTrack(c *gin.Context) {
@@ -263,8 +344,8 @@ func (h *TrackHandler) UpdateTrack(c *gin.Context) {
 		return
 	}
 
-	// Get existing track
-	existingTrack, err := h.trackRepo.GetByID(c, id)
+	// Get existing track from the primary: its version is checked on write
+	existingTrack, err := h.trackRepo.GetByID(domain.WithPrimaryReads(c.Request.Context()), id)
 	if err != nil {
 		h.handleError(c, apperrors.NewDatabaseError("failed to get track", err))
 		return
@@ -276,15 +357,46 @@ func (h *TrackHandler) UpdateTrack(c *gin.Context) {
 	}
 
 	// Parse update data
-	var updateData domain.Track
-	if err := c.ShouldBindJSON(&updateData); err != nil {
-		h.handleError(c, apperrors.NewValidationError("invalid request body", err.Error()))
+	mapper := trackMapperFor(c)
+	body, appErr := mapper.bind(c, existingTrack)
+	if appErr != nil {
+		h.handleError(c, appErr)
 		return
 	}
+	updateData := *body
 
 	// Basic validation
-	if err := validateTrack(&updateData); err != nil {
-		h.handleError(c, apperrors.NewValidationError("invalid track data", err.Error()))
+	if errs := validateTrack(&updateData); len(errs) > 0 {
+		h.handleError(c, apperrors.NewFieldValidationError("invalid track data", versionedFieldErrors(c, errs)))
+		return
+	}
+	if errs, appErr := h.enforceCustomFields(c.Request.Context(), &updateData); appErr != nil || len(errs) > 0 {
+		if appErr == nil {
+			appErr = apperrors.NewFieldValidationError("invalid track data", versionedFieldErrors(c, errs))
+		}
+		h.handleError(c, appErr)
+		return
+	}
+	if appErr := h.tagTrack(c.Request.Context(), &updateData); appErr != nil {
+		h.handleError(c, appErr)
+		return
+	}
+
+	expectedVersion, err := expectedTrackVersion(c, &updateData)
+	if err != nil {
+		h.handleError(c, apperrors.NewValidationError("invalid If-Match header", err.Error()))
+		return
+	}
+	if expectedVersion == 0 {
+		h.handleError(c, apperrors.NewPreconditionRequiredError("track version required", "supply the expected version via If-Match or the version field"))
+		return
+	}
+	updateData.Version = expectedVersion
+	if updateData.Status == "" {
+		updateData.Status = existingTrack.Status
+	}
+	if err := domain.CheckStatusTransition(existingTrack.Status, updateData.Status); err != nil {
+		h.handleError(c, apperrors.FromError(err, "invalid status"))
 		return
 	}
 
@@ -298,24 +410,207 @@ func (h *TrackHandler) UpdateTrack(c *gin.Context) {
 	// Additional validation using validator
 	result := h.validator.Validate(&updateData)
 	if !result.IsValid {
-		details := make([]string, len(result.Errors))
-		for i, err := range result.Errors {
-			details[i] = fmt.Sprintf("%s: %s", err.Field, err.Message)
-		}
-		h.handleError(c, apperrors.NewValidationError("invalid track data", strings.Join(details, "; ")))
+		h.handleError(c, apperrors.NewFieldValidationError("invalid track data", versionedFieldErrors(c, result.Errors)))
 		return
 	}
 
-	// Increment version
-	updateData.Version = existingTrack.Version + 1
+	if existingTrack.Version != expectedVersion {
+		h.handleConflict(c, domain.NewVersionConflictError(existingTrack, &updateData))
+		return
+	}
 	updateData.PreviousID = existingTrack.ID
 
+	// Provenance is server-managed: keep the stored record and mark edited fields as manual
+	updateData.Metadata.Provenance = existingTrack.Clone().Metadata.Provenance
+	updateData.RecordProvenance(domain.DiffTracks(existingTrack, &updateData), domain.ProvenanceManual, c.GetString("user_id"))
+
+	// The repository increments the version on success
 	if err := h.trackRepo.Update(c, &updateData); err != nil {
+		var conflict *domain.VersionConflictError
+		if errors.As(err, &conflict) {
+			h.handleConflict(c, conflict)
+			return
+		}
 		h.handleError(c, apperrors.NewDatabaseError("failed to update track", err))
 		return
 	}
 
-	c.JSON(http.StatusOK, updateData)
+	h.workflow.Notify(c.Request.Context(), domain.TrackStatusChange{
+		TrackID:    id,
+		From:       existingTrack.Status,
+		To:         updateData.Status,
+		Message:    updateData.StatusMsg,
+		UserID:     c.GetString("user_id"),
+		Version:    updateData.Version,
+		OccurredAt: updateData.UpdatedAt,
+	})
+
+	c.Header("ETag", trackETag(&updateData))
+	c.JSON(http.StatusOK, mapper.track(&updateData))
+}
+
+// mergePatchContentType is the media type of JSON Merge Patch documents
+const mergePatchContentType = "application/merge-patch+json"
+
+// PatchTrack changes part of an existing track
+// @Summary Patch track
+// @Description Change part of a track with a JSON Merge Patch (RFC 7396): members of the body replace the stored values, null removes them and absent members are left alone. Only the changed fields are validated. The body may be sent as application/merge-patch+json or application/json. The expected version must be supplied via the If-Match header or the version member of the body.
+// @Tags tracks
+// @Accept json
+// @Produce json
+// @Param id path string true "Track ID"
+// @Param If-Match header string false "ETag of the track version being updated"
+// @Param patch body object true "JSON Merge Patch of the track"
+// @Success 200 {object} domain.Track
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ConflictResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 428 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/{id} [patch]
+func (h *TrackHandler) PatchTrack(c *gin.Context) {
+	start := time.Now()
+	defer func() {
+		metrics.DatabaseOperationsTotal.WithLabelValues("patch", "total").Inc()
+		metrics.DatabaseQueryDuration.WithLabelValues("patch").Observe(time.Since(start).Seconds())
+	}()
+
+	if ct := c.ContentType(); ct != mergePatchContentType && ct != "application/json" {
+		h.handleError(c, apperrors.NewUnsupportedMediaTypeError("unsupported content type",
+			fmt.Sprintf("send the patch as %s", mergePatchContentType)))
+		return
+	}
+
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		h.handleError(c, apperrors.NewValidationError("failed to read request body", err.Error()))
+		return
+	}
+
+	// A version member that is not a number is left to the merge to reject
+	var versioned struct {
+		Version int `json:"version"`
+	}
+	_ = json.Unmarshal(patch, &versioned)
+	expectedVersion, err := expectedTrackVersion(c, &domain.Track{Version: versioned.Version})
+	if err != nil {
+		h.handleError(c, apperrors.NewValidationError("invalid If-Match header", err.Error()))
+		return
+	}
+	if expectedVersion == 0 {
+		h.handleError(c, apperrors.NewPreconditionRequiredError("track version required", "supply the expected version via If-Match or the version field"))
+		return
+	}
+
+	var from domain.TrackStatus
+	track, err := domain.PatchTrack(c.Request.Context(), h.trackRepo, c.Param("id"), func(existing *domain.Track) error {
+		patched, paths, err := domain.MergePatchTrack(existing, patch)
+		if err != nil {
+			if errors.Is(err, domain.ErrInvalidInput) {
+				return apperrors.NewValidationError("invalid patch", err.Error())
+			}
+			return err
+		}
+
+		if existing.Version != expectedVersion {
+			patched.Version = expectedVersion
+			return domain.NewVersionConflictError(existing, patched)
+		}
+
+		custom, appErr := h.enforceCustomFields(c.Request.Context(), patched)
+		if appErr != nil {
+			return appErr
+		}
+		if appErr := h.tagTrack(c.Request.Context(), patched); appErr != nil {
+			return appErr
+		}
+		errs := append(validateTrack(patched), h.validator.Validate(patched).Errors...)
+		if errs = changedFieldErrors(append(errs, custom...), paths); len(errs) > 0 {
+			return apperrors.NewFieldValidationError("invalid track data", fieldErrors(errs))
+		}
+		if err := domain.CheckStatusTransition(existing.Status, patched.Status); err != nil {
+			return apperrors.FromError(err, "invalid status")
+		}
+		from = existing.Status
+
+		patched.PreviousID = existing.ID
+		patched.RecordProvenance(domain.DiffTracks(existing, patched), domain.ProvenanceManual, c.GetString("user_id"))
+		*existing = *patched
+		return nil
+	})
+	if err != nil {
+		var appErr *apperrors.AppError
+		var conflict *domain.VersionConflictError
+		switch {
+		case errors.As(err, &appErr):
+			h.handleError(c, appErr)
+		case errors.As(err, &conflict):
+			h.handleConflict(c, conflict)
+		default:
+			h.handleError(c, apperrors.NewDatabaseError("failed to patch track", err))
+		}
+		return
+	}
+	if track == nil {
+		h.handleError(c, apperrors.NewNotFoundError("track not found"))
+		return
+	}
+	h.workflow.Notify(c.Request.Context(), domain.TrackStatusChange{
+		TrackID:    track.ID,
+		From:       from,
+		To:         track.Status,
+		Message:    track.StatusMsg,
+		UserID:     c.GetString("user_id"),
+		Version:    track.Version,
+		OccurredAt: track.UpdatedAt,
+	})
+
+	c.Header("ETag", trackETag(track))
+	c.JSON(http.StatusOK, track)
+}
+
+// changedFieldErrors keeps the validation errors of the fields at paths, or
+// within or around them
+func changedFieldErrors(errs []domain.ValidationError, paths []string) []domain.ValidationError {
+	var changed []domain.ValidationError
+	for _, err := range errs {
+		for _, path := range paths {
+			if err.Field == path || strings.HasPrefix(err.Field, path+".") || strings.HasPrefix(path, err.Field+".") {
+				changed = append(changed, err)
+				break
+			}
+		}
+	}
+	return changed
+}
+
+// GetTrackProvenance reports the source of every user-editable field of a track
+// @Summary Get track field provenance
+// @Description Get each editable field's value and whether it was last set manually, by AI, or by import
+// @Tags tracks
+// @Produce json
+// @Param id path string true "Track ID"
+// @Success 200 {object} ProvenanceResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/{id}/provenance [get]
+func (h *TrackHandler) GetTrackProvenance(c *gin.Context) {
+	track, err := h.trackRepo.GetByID(c, c.Param("id"))
+	if err != nil {
+		h.handleError(c, apperrors.NewDatabaseError("failed to get track", err))
+		return
+	}
+	if track == nil {
+		h.handleError(c, apperrors.NewNotFoundError("track not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, ProvenanceResponse{
+		TrackID: track.ID,
+		Version: track.Version,
+		Fields:  track.ProvenanceReport(),
+	})
 }
 
 // DeleteTrack removes a track
@@ -357,18 +652,22 @@ func (h *TrackHandler) DeleteTrack(c *gin.Context) {
 		h.handleError(c, apperrors.NewDatabaseError("failed to delete track", err))
 		return
 	}
+	h.releaseQuota(c, id)
 
 	c.Status(http.StatusNoContent)
 }
 
 // ListTracks retrieves a paginated list of tracks
 // @Summary List tracks
-// @Description Get a paginated list of tracks
+// @Description Get a paginated list of tracks. With fields, every track is an object holding only the selected fields, keyed by field name, and only the columns holding them are read. With sort, tracks are ordered by up to three of title, artist, album, year, duration, bpm, genre, isrc, label_id, status, created_at and updated_at, each descending when prefixed with "-"; text is ordered ignoring case, blank values come last and ties are ordered by ID, so pages neither repeat nor skip tracks.
 // @Tags tracks
 // @Produce json
 // @Param page query int false "Page number"
 // @Param limit query int false "Items per page"
+// @Param fields query string false "Comma-separated fields to return, such as id,title,artist,status"
+// @Param sort query string false "Comma-separated fields to order by, such as artist,-created_at"
 // @Success 200 {object} ListResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /tracks [get]
 func (h *TrackHandler) ListTracks(c *gin.Context) {
@@ -390,25 +689,46 @@ func (h *TrackHandler) ListTracks(c *gin.Context) {
 
 	offset := (page - 1) * limit
 
-	tracks, err := h.trackRepo.List(c, map[string]interface{}{}, offset, limit)
+	ctx, fields, appErr := trackFieldSelection(c)
+	if appErr != nil {
+		h.handleError(c, appErr)
+		return
+	}
+	sort, err := domain.ParseTrackSort(c.Query("sort"))
+	if err != nil {
+		h.handleError(c, apperrors.NewValidationError("invalid sort", err.Error()))
+		return
+	}
+	if sort != nil {
+		ctx = domain.WithTrackSort(ctx, sort)
+	}
+
+	tracks, err := h.trackRepo.List(ctx, map[string]interface{}{}, offset, limit)
 	if err != nil {
 		h.handleError(c, apperrors.NewDatabaseError("failed to list tracks", err))
 		return
 	}
 
-	c.JSON(http.StatusOK, ListResponse{
-		Tracks: tracks,
-		Page:   page,
-		Limit:  limit,
-	})
+	if fields != nil {
+		c.JSON(http.StatusOK, SparseListResponse{
+			Tracks: projectTracks(tracks, fields),
+			Page:   page,
+			Limit:  limit,
+		})
+		return
+	}
+
+	h.annotateLocks(c, tracks...)
+	c.JSON(http.StatusOK, trackMapperFor(c).list(tracks, page, limit))
 }
 
 // SearchTracks searches tracks by metadata
 // @Summary Search tracks
-// @Description Search tracks by metadata fields
+// @Description Search tracks by metadata fields. Custom fields are matched by value; with label_id their values are checked and typed by the label's custom field schema, so that 7 matches a stored "7.0". filter narrows the search with a boolean filter: and, or and not groups of conditions comparing a field with eq, ne, contains, in or exists, and year, duration and bpm also with gt, gte, lt, lte and between, such as {"and": [{"field": "bpm", "op": "between", "value": [120, 128]}, {"not": {"field": "isrc", "op": "exists"}}]}. Text compares ignoring case. With fields, every track is an object holding only the selected fields, keyed by field name, and only the columns holding them are read.
 // @Tags tracks
 // @Accept json
 // @Produce json
+// @Param fields query string false "Comma-separated fields to return, such as id,title,artist,status"
 // @Param query body SearchQuery true "Search query"
 // @Success 200 {array} domain.Track
 // @Failure 400 {object} ErrorResponse
@@ -422,17 +742,33 @@ func (h *TrackHandler) SearchTracks(c *gin.Context) {
 	}()
 
 	var query SearchQuery
-	if err := c.ShouldBindJSON(&query); err != nil {
-		h.handleError(c, apperrors.NewValidationError("invalid search query", err.Error()))
+	if err := bindJSON(c, &query); err != nil {
+		h.handleError(c, err)
 		return
 	}
 
-	tracks, err := h.trackRepo.SearchByMetadata(c, query.toMap())
+	ctx, fields, appErr := trackFieldSelection(c)
+	if appErr != nil {
+		h.handleError(c, appErr)
+		return
+	}
+
+	terms, appErr := h.searchTerms(ctx, &query)
+	if appErr != nil {
+		h.handleError(c, appErr)
+		return
+	}
+
+	tracks, err := h.trackRepo.SearchByMetadata(ctx, terms)
 	if err != nil {
 		h.handleError(c, apperrors.NewDatabaseError("failed to search tracks", err))
 		return
 	}
 
+	if fields != nil {
+		c.JSON(http.StatusOK, projectTracks(tracks, fields))
+		return
+	}
 	c.JSON(http.StatusOK, tracks)
 }
 
@@ -445,63 +781,101 @@ func (h *TrackHandler) BatchProcess(c *gin.Context) {
 	}()
 
 	var req BatchProcessRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.handleError(c, apperrors.NewValidationError("invalid request body", err.Error()))
+	if err := bindJSON(c, &req); err != nil {
+		h.handleError(c, err)
 		return
 	}
 
+	// The tracks stay locked until they are saved, so other workers do not
+	// enrich them in between
+	var tracks []*domain.Track
+	err := h.trackLocks.Do(c.Request.Context(), "batch_process", req.TrackIDs, func(ctx context.Context) error {
+		var appErr *apperrors.AppError
+		if tracks, appErr = h.batchProcess(ctx, &req); appErr != nil {
+			return appErr
+		}
+		return nil
+	})
+	if err != nil {
+		h.handleError(c, apperrors.FromError(err, "failed to process tracks"))
+		return
+	}
+
+	c.JSON(http.StatusOK, tracks)
+}
+
+// batchProcess enriches the tracks of req and saves them
+func (h *TrackHandler) batchProcess(ctx context.Context, req *BatchProcessRequest) ([]*domain.Track, *apperrors.AppError) {
+	// Read from the primary since the tracks are written back with a version check
+	readCtx := domain.WithPrimaryReads(ctx)
 	var tracks []*domain.Track
 	for _, id := range req.TrackIDs {
-		track, err := h.trackRepo.GetByID(c, id)
+		track, err := h.trackRepo.GetByID(readCtx, id)
 		if err != nil {
-			h.handleError(c, apperrors.NewDatabaseError("failed to get track", err))
-			return
+			return nil, apperrors.NewDatabaseError("failed to get track", err)
 		}
 		if track == nil {
-			h.handleError(c, apperrors.NewNotFoundError(fmt.Sprintf("track not found: %s", id)))
-			return
+			return nil, apperrors.NewNotFoundError(fmt.Sprintf("track not found: %s", id))
 		}
 		tracks = append(tracks, track)
 	}
 
 	// Process tracks in batch
-	if err := h.aiService.BatchProcess(c, tracks); err != nil {
-		h.handleError(c, apperrors.NewAIError("failed to process tracks", err))
-		return
+	if req.OverwriteManual {
+		ctx = domain.WithMergePolicy(ctx, domain.MergePolicy{OverwriteManual: true})
+	}
+	if req.ForceRefresh {
+		ctx = domain.WithForceRefresh(ctx)
+	}
+	if err := h.aiService.BatchProcess(ctx, tracks); err != nil {
+		return nil, apperrors.NewAIError("failed to process tracks", err)
 	}
 
 	// Update tracks in database
-	if err := h.trackRepo.BatchUpdate(c, tracks); err != nil {
-		h.handleError(c, apperrors.NewDatabaseError("failed to update tracks", err))
-		return
+	if err := h.trackRepo.BatchUpdate(ctx, tracks); err != nil {
+		return nil, apperrors.NewDatabaseError("failed to update tracks", err)
 	}
-
-	c.JSON(http.StatusOK, tracks)
+	return tracks, nil
 }
 
 // ExportTracks exports tracks in the specified format
 // @Summary Export tracks
-// @Description Export tracks in the specified format
+// @Description Export tracks. Without a format in the body the Accept header selects the serializer: application/json (default) answers with an ExportResponse, application/xml with a DDEX ERN message, text/csv with a CSV file and application/x-ndjson with one track per line. Custom fields defined by the tracks' labels are added as typed values under custom, or as custom.<name> CSV columns.
 // @Tags tracks
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Key under which repeats of this request return the original response"
 // @Param request body ExportRequest true "Export request"
 // @Success 200 {object} ExportResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 406 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /tracks/export [post]
 func (h *TrackHandler) ExportTracks(c *gin.Context) {
+	start := time.Now()
 	var req ExportRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.handleError(c, apperrors.NewValidationError("invalid request body", err.Error()))
+	if err := bindJSON(c, &req); err != nil {
+		h.handleError(c, err)
 		return
 	}
 
+	// The format in the body wraps the data in an ExportResponse; without
+	// it the Accept header chooses how the tracks are serialized
+	format := req.Format
+	if format == "" {
+		var appErr *apperrors.AppError
+		if format, appErr = negotiateTrackFormat(c); appErr != nil {
+			h.handleError(c, appErr)
+			return
+		}
+	}
+
 	// Get tracks
 	var tracks []*domain.Track
 	for _, id := range req.TrackIDs {
 		track, err := h.trackRepo.GetByID(c, id)
 		if err != nil {
+			metrics.ExportsTotal.WithLabelValues(format, "failure").Inc()
 			h.handleError(c, apperrors.NewDatabaseError("failed to get track", err))
 			return
 		}
@@ -510,44 +884,71 @@ func (h *TrackHandler) ExportTracks(c *gin.Context) {
 		}
 	}
 
-	// Generate export data based on format
-	var exportData interface{}
-	switch req.Format {
-	case "json":
-		exportData = tracks
-	case "csv":
-		csvData := [][]string{
-			{"ID", "Title", "Artist", "Album", "ISRC", "Duration", "Created At"},
+	schemas, appErr := h.customFieldSchemas(c.Request.Context(), tracks)
+	if appErr != nil {
+		metrics.ExportsTotal.WithLabelValues(format, "failure").Inc()
+		h.handleError(c, appErr)
+		return
+	}
+
+	metrics.ExportsTotal.WithLabelValues(format, "success").Inc()
+	if h.usage != nil {
+		for labelID, n := range domain.TracksPerLabel(tracks) {
+			h.usage.RecordUsage(c.Request.Context(), labelID, domain.UsageExports, n)
 		}
+	}
+	if h.analytics != nil {
+		h.analytics.Record(analytics.ExportEvent{
+			Timestamp:  start,
+			Tenant:     domain.TenantFromContext(c.Request.Context()),
+			Format:     format,
+			TrackCount: len(tracks),
+			DurationMs: time.Since(start).Milliseconds(),
+			Success:    true,
+		})
+	}
+
+	switch {
+	case req.Format == "csv":
+		columns := customFieldColumns(schemas)
+		csvData := [][]string{trackCSVHeaderFor(columns)}
 		for _, track := range tracks {
-			row := []string{
-				track.ID,
-				track.Title(),
-				track.Artist(),
-				track.Album(),
-				track.ISRC(),
-				fmt.Sprintf("%d", int(track.Duration())),
-				track.CreatedAt.Format(time.RFC3339),
-			}
-			csvData = append(csvData, row)
+			csvData = append(csvData, trackCSVRowFor(track, schemas[track.LabelID], columns))
 		}
-		exportData = csvData
+		c.JSON(http.StatusOK, ExportResponse{Format: format, Data: csvData})
+	case format == "json":
+		c.JSON(http.StatusOK, ExportResponse{Format: format, Data: exportedTracks(tracks, schemas)})
 	default:
-		h.handleError(c, apperrors.NewValidationError("unsupported export format", fmt.Sprintf("format '%s' is not supported", req.Format)))
-		return
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=export.%s", format))
+		if err := writeExportedTracks(c, format, tracks, schemas); err != nil {
+			h.handleError(c, apperrors.NewInternalError("failed to encode tracks", err))
+		}
 	}
-
-	c.JSON(http.StatusOK, ExportResponse{
-		Format: req.Format,
-		Data:   exportData,
-	})
 }
 
 // Helper functions and types
 
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Details string `json:"details,omitempty"`
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes the error of an ErrorResponse. Code is stable and
+// meant for clients to branch on; RequestID matches the X-Request-ID header.
+type ErrorBody struct {
+	Code      string       `json:"code"`
+	Type      string       `json:"type"`
+	Message   string       `json:"message"`
+	Details   string       `json:"details,omitempty"`
+	Fields    []ErrorField `json:"fields,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	TraceID   string       `json:"trace_id,omitempty"`
+}
+
+// ErrorField reports why one field of a rejected request was invalid
+type ErrorField struct {
+	Field   string `json:"field,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
 type ListResponse struct {
@@ -556,6 +957,35 @@ type ListResponse struct {
 	Limit  int             `json:"limit"`
 }
 
+// SparseListResponse is a ListResponse holding only the selected fields of
+// every track
+type SparseListResponse struct {
+	Tracks []map[string]interface{} `json:"tracks"`
+	Page   int                      `json:"page"`
+	Limit  int                      `json:"limit"`
+}
+
+// trackFieldSelection parses the fields query parameter and returns the
+// request context asking the repository for only the columns holding them
+func trackFieldSelection(c *gin.Context) (context.Context, []string, *apperrors.AppError) {
+	fields, err := domain.ParseTrackFields(c.Query("fields"))
+	if err != nil {
+		return nil, nil, apperrors.NewValidationError("invalid fields", err.Error())
+	}
+	if fields == nil {
+		return c.Request.Context(), nil, nil
+	}
+	return domain.WithTrackFields(c.Request.Context(), fields), fields, nil
+}
+
+func projectTracks(tracks []*domain.Track, fields []string) []map[string]interface{} {
+	projected := make([]map[string]interface{}, len(tracks))
+	for i, track := range tracks {
+		projected[i] = track.Project(fields)
+	}
+	return projected
+}
+
 type SearchQuery struct {
 	Title       string    `json:"title,omitempty"`
 	Artist      string    `json:"artist,omitempty"`
@@ -567,6 +997,15 @@ type SearchQuery struct {
 	CreatedFrom time.Time `json:"created_from,omitempty"`
 	CreatedTo   time.Time `json:"created_to,omitempty"`
 	NeedsReview *bool     `json:"needs_review,omitempty"`
+	// LabelID limits the search to a label, whose schema types the
+	// values of CustomFields
+	LabelID string `json:"label_id,omitempty"`
+	// CustomFields matches custom field values, such as
+	// {"mood_score": 7, "explicit_lyrics": true}
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+	// Filter is a boolean filter the tracks must also meet, combining
+	// conditions with and, or and not
+	Filter *domain.SearchFilter `json:"filter,omitempty"`
 }
 
 func (q *SearchQuery) toMap() map[string]interface{} {
@@ -601,50 +1040,122 @@ func (q *SearchQuery) toMap() map[string]interface{} {
 	if q.NeedsReview != nil {
 		m["needs_review"] = *q.NeedsReview
 	}
+	if q.LabelID != "" {
+		m["label_id"] = q.LabelID
+	}
 	return m
 }
 
 func (h *TrackHandler) handleError(c *gin.Context, err *apperrors.AppError) {
 	if h.errorTracker != nil {
-		h.errorTracker.CaptureError(err, map[string]string{
+		h.errorTracker.CaptureErrorContext(c.Request.Context(), err, map[string]string{
 			"handler": "track",
 			"method":  c.Request.Method,
 			"path":    c.Request.URL.Path,
 		})
 	}
 
-	c.JSON(err.StatusCode, gin.H{
-		"error": gin.H{
-			"type":    err.Type,
-			"message": err.Message,
-			"details": err.Details,
-		},
+	apperrors.Respond(c, err)
+}
+
+// ProvenanceResponse lists the provenance of each editable field of a track
+type ProvenanceResponse struct {
+	TrackID string                       `json:"track_id"`
+	Version int                          `json:"version"`
+	Fields  []domain.FieldProvenanceView `json:"fields"`
+}
+
+// ConflictResponse is returned when an update is based on a stale track version
+type ConflictResponse struct {
+	Error     ErrorBody            `json:"error"`
+	Version   int                  `json:"current_version"`
+	Conflicts []domain.FieldChange `json:"conflicts"`
+}
+
+func (h *TrackHandler) handleConflict(c *gin.Context, conflict *domain.VersionConflictError) {
+	appErr := apperrors.NewConflictError("track has been modified", conflict.Error()).WithCode(apperrors.CodeVersionConflict)
+	c.Header("ETag", fmt.Sprintf(`"%d"`, conflict.CurrentVersion))
+	c.AbortWithStatusJSON(appErr.StatusCode, gin.H{
+		"error":           apperrors.Describe(c, appErr),
+		"current_version": conflict.CurrentVersion,
+		"conflicts":       conflict.Conflicts,
 	})
 }
 
-func validateTrack(track *domain.Track) error {
+// trackETag returns the entity tag for a track, derived from its version
+func trackETag(track *domain.Track) string {
+	return fmt.Sprintf(`"%d"`, track.Version)
+}
+
+// trackNotModified reports whether the client already has the current
+// version of track. If-None-Match takes precedence over If-Modified-Since,
+// as in RFC 7232.
+func trackNotModified(c *gin.Context, track *domain.Track) bool {
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		etag := trackETag(track)
+		for _, tag := range strings.Split(ifNoneMatch, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if track.UpdatedAt.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !track.UpdatedAt.Truncate(time.Second).After(since)
+}
+
+// expectedTrackVersion returns the version the client expects to update,
+// preferring the If-Match header over the version field in the body.
+// Zero means no version was supplied.
+func expectedTrackVersion(c *gin.Context, body *domain.Track) (int, error) {
+	ifMatch := strings.TrimSpace(c.GetHeader("If-Match"))
+	if ifMatch == "" {
+		return body.Version, nil
+	}
+	tag := strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("expected a track version ETag, got %s", ifMatch)
+	}
+	return version, nil
+}
+
+func validateTrack(track *domain.Track) []domain.ValidationError {
+	var errs []domain.ValidationError
 	if track.Title() == "" {
-		return fmt.Errorf("title is required")
+		errs = append(errs, domain.ValidationError{Field: "metadata.basic.title", Code: "required", Message: "is required"})
 	}
 	if track.Artist() == "" {
-		return fmt.Errorf("artist is required")
+		errs = append(errs, domain.ValidationError{Field: "metadata.basic.artist", Code: "required", Message: "is required"})
 	}
 	if track.ISRC() != "" && len(track.ISRC()) != 12 {
-		return fmt.Errorf("ISRC must be 12 characters")
+		errs = append(errs, domain.ValidationError{Field: "metadata.basic.isrc", Code: "len", Message: "must be exactly 12 characters long"})
 	}
 	if track.ISWC() != "" && len(track.ISWC()) != 11 {
-		return fmt.Errorf("ISWC must be 11 characters")
+		errs = append(errs, domain.ValidationError{Field: "metadata.additional.customFields.iswc", Code: "len", Message: "must be exactly 11 characters long"})
 	}
-	return nil
+	return errs
 }
 
 type BatchProcessRequest struct {
-	TrackIDs []string `json:"track_ids" binding:"required"`
+	TrackIDs        []string `json:"track_ids" binding:"required"`
+	OverwriteManual bool     `json:"overwrite_manual"`
+	// ForceRefresh enriches again instead of reusing cached results
+	ForceRefresh bool `json:"force_refresh"`
 }
 
 type ExportRequest struct {
 	TrackIDs []string `json:"track_ids" binding:"required"`
-	Format   string   `json:"format" binding:"required,oneof=json csv"`
+	// Format is optional; without it the Accept header is honored
+	Format string `json:"format,omitempty" binding:"omitempty,oneof=json csv"`
 }
 
 type ExportResponse struct {
@@ -654,27 +1165,96 @@ type ExportResponse struct {
 
 func (h *TrackHandler) UploadAudio(c *gin.Context) {
 	// TODO: Implement audio upload
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "Not implemented"})
+	apperrors.Respond(c, apperrors.NewNotImplementedError("not implemented"))
 }
 
 func (h *TrackHandler) GetAudioURL(c *gin.Context) {
 	// TODO: Implement get audio URL
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "Not implemented"})
+	apperrors.Respond(c, apperrors.NewNotImplementedError("not implemented"))
 }
 
 func (h *TrackHandler) ValidateERN(c *gin.Context) {
 	// TODO: Implement ERN validation
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "Not implemented"})
+	apperrors.Respond(c, apperrors.NewNotImplementedError("not implemented"))
 }
 
 func (h *TrackHandler) ImportERN(c *gin.Context) {
 	// TODO: Implement ERN import
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "Not implemented"})
+	apperrors.Respond(c, apperrors.NewNotImplementedError("not implemented"))
 }
 
 func (h *TrackHandler) ExportERN(c *gin.Context) {
 	// TODO: Implement ERN export
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "Not implemented"})
+	apperrors.Respond(c, apperrors.NewNotImplementedError("not implemented"))
+}
+
+// SetAnalytics records export events with recorder
+func (h *TrackHandler) SetAnalytics(recorder analytics.EventRecorder) {
+	h.analytics = recorder
+}
+
+// SetUsage meters exported tracks per label with usage
+func (h *TrackHandler) SetUsage(usage domain.UsageRecorder) {
+	h.usage = usage
+}
+
+// SetUploadScanner scans uploaded files for malware with scanner. Uploads
+// are then stored in quarantine until they have been scanned.
+func (h *TrackHandler) SetUploadScanner(scanner *usecase.UploadScanner) {
+	h.uploadScanner = scanner
+}
+
+// SetStorageQuota enforces the per-user storage quota on uploads with quota
+func (h *TrackHandler) SetStorageQuota(quota *usecase.StorageQuotaUseCase) {
+	h.quota = quota
+}
+
+// SetBackgroundRunner runs the work started by requests that continues
+// after the response, such as AI enrichment, with runner
+func (h *TrackHandler) SetBackgroundRunner(runner *background.Runner) {
+	h.background = runner
+}
+
+// SetWorkflow makes status changes through workflow, whose hooks are told
+// about them
+func (h *TrackHandler) SetWorkflow(workflow *usecase.TrackWorkflowUseCase) {
+	h.workflow = workflow
+}
+
+// SetDuplicates finds and merges duplicates through duplicates, which
+// knows the stores merges move references in
+func (h *TrackHandler) SetDuplicates(duplicates *usecase.DuplicateUseCase) {
+	h.duplicates = duplicates
+}
+
+// reserveQuota counts an upload of size bytes against the user's quota.
+// Requests without a user are not metered.
+func (h *TrackHandler) reserveQuota(c *gin.Context, trackID string, size int64) error {
+	userID := c.GetString("user_id")
+	if h.quota == nil || userID == "" {
+		return nil
+	}
+	return h.quota.Reserve(c.Request.Context(), userID, trackID, size)
+}
+
+// releaseQuota stops counting a track's file against its owner's quota
+func (h *TrackHandler) releaseQuota(c *gin.Context, trackID string) {
+	if h.quota != nil {
+		h.quota.Release(c.Request.Context(), trackID)
+	}
+}
+
+// scanPendingMsg is the status message of tracks whose file awaits its
+// malware scan
+const scanPendingMsg = "awaiting malware scan"
+
+// uploadKey returns the key an upload is stored under and the status
+// message of its track; with a scanner the file goes to quarantine first
+func (h *TrackHandler) uploadKey(key string) (string, string) {
+	if h.uploadScanner == nil {
+		return key, ""
+	}
+	return domain.QuarantineKey(key), scanPendingMsg
 }
 
 // GetTrackRepo returns the track repository instance
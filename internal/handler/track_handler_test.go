@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTrack_Conditional(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	updatedAt := time.Date(2024, 5, 6, 7, 8, 9, 500, time.UTC)
+	repo := &stubTrackRepository{tracks: map[string]*domain.Track{
+		"t1": {ID: "t1", Version: 4, UpdatedAt: updatedAt},
+	}}
+	router := gin.New()
+	router.GET("/tracks/:id", NewTrackHandler(repo, nil, nil, nil, nil).GetTrack)
+
+	get := func(headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/tracks/t1", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get(nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+	assert.Equal(t, "Mon, 06 May 2024 07:08:09 GMT", w.Header().Get("Last-Modified"))
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		status  int
+	}{
+		{"matching ETag", map[string]string{"If-None-Match": `"4"`}, http.StatusNotModified},
+		{"one of several ETags", map[string]string{"If-None-Match": `"3", W/"4"`}, http.StatusNotModified},
+		{"any ETag", map[string]string{"If-None-Match": "*"}, http.StatusNotModified},
+		{"stale ETag", map[string]string{"If-None-Match": `"3"`}, http.StatusOK},
+		{"ETag wins over date", map[string]string{"If-None-Match": `"3"`, "If-Modified-Since": "Mon, 06 May 2024 07:08:09 GMT"}, http.StatusOK},
+		{"not modified since", map[string]string{"If-Modified-Since": "Mon, 06 May 2024 07:08:09 GMT"}, http.StatusNotModified},
+		{"modified since", map[string]string{"If-Modified-Since": "Mon, 06 May 2024 07:08:08 GMT"}, http.StatusOK},
+		{"invalid date", map[string]string{"If-Modified-Since": "yesterday"}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(tt.headers)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+			if tt.status == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
+
+// racingTrackRepository lets another writer update the track between the
+// handler's read and its write
+type racingTrackRepository struct {
+	*stubTrackRepository
+}
+
+func (r *racingTrackRepository) Update(ctx context.Context, track *domain.Track) error {
+	concurrent := r.tracks[track.ID].Clone()
+	concurrent.SetTitle("Their Song")
+	concurrent.Version++
+	r.tracks[track.ID] = concurrent
+	return r.stubTrackRepository.Update(ctx, track)
+}
+
+func TestUpdateTrack_Versioning(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRepo := func() *stubTrackRepository {
+		track := &domain.Track{ID: "t1", Version: 2, StoragePath: "audio/t1.mp3"}
+		track.SetTitle("Song")
+		track.SetArtist("Artist")
+		return &stubTrackRepository{tracks: map[string]*domain.Track{"t1": track}}
+	}
+	put := func(repo domain.TrackRepository, ifMatch, body string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.PUT("/tracks/:id", NewTrackHandler(repo, nil, nil, validator.NewValidator(), nil).UpdateTrack)
+		req := httptest.NewRequest(http.MethodPut, "/tracks/t1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	const update = `{"metadata":{"basic":{"title":"New Song","artist":"Artist"}}}`
+	const updateV1 = `{"version":1,"metadata":{"basic":{"title":"New Song","artist":"Artist"}}}`
+	const updateV2 = `{"version":2,"metadata":{"basic":{"title":"New Song","artist":"Artist"}}}`
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		body    string
+		status  int
+		etag    string
+	}{
+		{"If-Match with the current version", `"2"`, update, http.StatusOK, `"3"`},
+		{"weak If-Match", `W/"2"`, update, http.StatusOK, `"3"`},
+		{"version in the body", "", updateV2, http.StatusOK, `"3"`},
+		{"If-Match wins over the body", `"2"`, updateV1, http.StatusOK, `"3"`},
+		{"stale If-Match", `"1"`, updateV2, http.StatusConflict, `"2"`},
+		{"stale version in the body", "", updateV1, http.StatusConflict, `"2"`},
+		{"If-Match that is not a version", `"abc"`, update, http.StatusBadRequest, ""},
+		{"If-Match with version zero", `"0"`, update, http.StatusBadRequest, ""},
+		{"no version", "", update, http.StatusPreconditionRequired, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRepo()
+			w := put(repo, tt.ifMatch, tt.body)
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+			assert.Equal(t, tt.etag, w.Header().Get("ETag"))
+			if tt.status != http.StatusOK {
+				assert.Equal(t, "Song", repo.tracks["t1"].Title())
+				assert.Equal(t, 2, repo.tracks["t1"].Version)
+			}
+		})
+	}
+
+	type conflictBody struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+		CurrentVersion int                  `json:"current_version"`
+		Conflicts      []domain.FieldChange `json:"conflicts"`
+	}
+
+	t.Run("conflict lists the fields that differ", func(t *testing.T) {
+		w := put(newRepo(), `"1"`, update)
+		require.Equal(t, http.StatusConflict, w.Code)
+
+		var body conflictBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "VERSION_CONFLICT", body.Error.Code)
+		assert.Equal(t, 2, body.CurrentVersion)
+		assert.Equal(t, []domain.FieldChange{{Field: "title", OldValue: "Song", NewValue: "New Song"}}, body.Conflicts)
+	})
+
+	t.Run("conflict detected on write", func(t *testing.T) {
+		repo := newRepo()
+		w := put(&racingTrackRepository{repo}, `"2"`, update)
+		require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+
+		var body conflictBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, 3, body.CurrentVersion)
+		assert.Equal(t, []domain.FieldChange{{Field: "title", OldValue: "Their Song", NewValue: "New Song"}}, body.Conflicts)
+		assert.Equal(t, "Their Song", repo.tracks["t1"].Title())
+	})
+}
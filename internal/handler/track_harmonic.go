@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HarmonicMixResponse lists the tracks that mix with a track
+type HarmonicMixResponse struct {
+	TrackID string  `json:"track_id"`
+	BPM     float64 `json:"bpm"`
+	// Key is the track's key as stored; Camelot and OpenKey are the same
+	// key on the DJ key wheels
+	Key     string                 `json:"key"`
+	Camelot string                 `json:"camelot"`
+	OpenKey string                 `json:"open_key"`
+	Tracks  []domain.HarmonicMatch `json:"tracks"`
+}
+
+// GetHarmonicMixes lists the tracks that mix harmonically with a track
+// @Summary List harmonically compatible tracks
+// @Description List the tracks a DJ can mix with a track: those within 6% of its BPM whose key is the same, a step round the Camelot wheel (a fifth up or down) or its relative major or minor. Tracks in the same key come first, then adjacent and relative keys, each by closeness of tempo. Keys are read from names such as "Am" or "F# minor" and from Camelot and Open Key codes. A track without a BPM or a known key is answered with 400.
+// @Tags tracks
+// @Produce json
+// @Param id path string true "Track ID"
+// @Param limit query int false "Maximum number of tracks (default 25, at most 200)"
+// @Success 200 {object} HarmonicMixResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/{id}/harmonic-mixes [get]
+func (h *TrackHandler) GetHarmonicMixes(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		h.handleError(c, apperrors.NewValidationError("invalid limit", err.Error()))
+		return
+	}
+
+	track, key, matches, err := h.harmonic.Compatible(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		h.handleError(c, apperrors.FromError(err, "failed to find harmonic mixes"))
+		return
+	}
+	c.JSON(http.StatusOK, HarmonicMixResponse{
+		TrackID: track.ID,
+		BPM:     track.BPM(),
+		Key:     track.Key(),
+		Camelot: key.String(),
+		OpenKey: key.OpenKey(),
+		Tracks:  matches,
+	})
+}
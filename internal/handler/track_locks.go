@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetTrackLocks locks the tracks of a batch while they are processed and
+// shows the locks held on tracks when they are read
+func (h *TrackHandler) SetTrackLocks(locks *usecase.TrackLockUseCase) {
+	h.trackLocks = locks
+}
+
+// annotateLocks sets the locks of the tracks being processed. The tracks
+// are served without them when the locks cannot be read.
+func (h *TrackHandler) annotateLocks(c *gin.Context, tracks ...*domain.Track) {
+	if err := h.trackLocks.Annotate(c.Request.Context(), tracks...); err != nil {
+		h.errorTracker.CaptureErrorContext(c.Request.Context(), err, map[string]string{
+			"operation": "track_locks",
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// heldTrackLocks reports fixed locks and takes none
+type heldTrackLocks map[string]*domain.TrackLock
+
+func (l heldTrackLocks) Lock(context.Context, []string, string, time.Duration) (*domain.TrackLock, error) {
+	return nil, domain.ErrTrackLocked
+}
+
+func (l heldTrackLocks) Unlock(context.Context, []string, *domain.TrackLock) error { return nil }
+
+func (l heldTrackLocks) Locks(_ context.Context, trackIDs []string) (map[string]*domain.TrackLock, error) {
+	locks := make(map[string]*domain.TrackLock)
+	for _, id := range trackIDs {
+		if lock, ok := l[id]; ok {
+			locks[id] = lock
+		}
+	}
+	return locks, nil
+}
+
+func TestGetTrack_ShowsProcessingLock(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &stubTrackRepository{tracks: map[string]*domain.Track{
+		"busy": {ID: "busy", Version: 1},
+		"idle": {ID: "idle", Version: 1},
+	}}
+	acquired := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	h := NewTrackHandler(repo, nil, nil, nil, nil)
+	h.SetTrackLocks(usecase.NewTrackLockUseCase(heldTrackLocks{
+		"busy": {Owner: "ai_batch:b1", AcquiredAt: acquired, ExpiresAt: acquired.Add(30 * time.Minute), Token: "secret"},
+	}, time.Minute))
+	router := gin.New()
+	router.GET("/tracks/:id", h.GetTrack)
+
+	get := func(id string) map[string]interface{} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tracks/"+id, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"owner":      "ai_batch:b1",
+		"acquiredAt": "2024-05-06T07:08:09Z",
+		"expiresAt":  "2024-05-06T07:38:09Z",
+	}, get("busy")["lock"], "the token stays private")
+	assert.NotContains(t, get("idle"), "lock")
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubTrackRepository keeps tracks in memory and checks versions on update
+type stubTrackRepository struct {
+	domain.TrackRepository
+	tracks map[string]*domain.Track
+}
+
+func (r *stubTrackRepository) GetByID(_ context.Context, id string) (*domain.Track, error) {
+	if track, ok := r.tracks[id]; ok {
+		return track.Clone(), nil
+	}
+	return nil, nil
+}
+
+func (r *stubTrackRepository) Update(_ context.Context, track *domain.Track) error {
+	current := r.tracks[track.ID]
+	if current.Version != track.Version {
+		return domain.NewVersionConflictError(current, track)
+	}
+	track.Version++
+	r.tracks[track.ID] = track.Clone()
+	return nil
+}
+
+func (r *stubTrackRepository) Delete(_ context.Context, id string) error {
+	delete(r.tracks, id)
+	return nil
+}
+
+func TestPatchTrack(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func() (*gin.Engine, *stubTrackRepository) {
+		track := &domain.Track{ID: "t1", Version: 3, StoragePath: "audio/t1.mp3"}
+		track.SetTitle("Song")
+		track.SetArtist("Artist")
+		track.SetGenre("Pop")
+		repo := &stubTrackRepository{tracks: map[string]*domain.Track{"t1": track}}
+
+		router := gin.New()
+		h := NewTrackHandler(repo, nil, nil, validator.NewValidator(), nil)
+		router.PATCH("/tracks/:id", h.PatchTrack)
+		return router, repo
+	}
+	patch := func(router *gin.Engine, id, ifMatch, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/tracks/"+id, strings.NewReader(body))
+		req.Header.Set("Content-Type", mergePatchContentType)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("changes only the patched fields", func(t *testing.T) {
+		router, repo := newRouter()
+		w := patch(router, "t1", `"3"`, `{"metadata":{"basic":{"album":"Record"},"musical":{"genre":null}},"storagePath":"elsewhere"}`)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+		stored := repo.tracks["t1"]
+		assert.Equal(t, "Song", stored.Title())
+		assert.Equal(t, "Artist", stored.Artist())
+		assert.Equal(t, "Record", stored.Album())
+		assert.Empty(t, stored.Genre())
+		assert.Equal(t, "audio/t1.mp3", stored.StoragePath)
+		assert.Equal(t, domain.ProvenanceManual, stored.Metadata.Provenance["album"].Source)
+	})
+
+	t.Run("takes the version from the body", func(t *testing.T) {
+		router, repo := newRouter()
+		w := patch(router, "t1", "", `{"version":3,"metadata":{"basic":{"year":1999}}}`)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, 1999, repo.tracks["t1"].Year())
+		assert.Equal(t, 4, repo.tracks["t1"].Version)
+	})
+
+	t.Run("validates only the changed fields", func(t *testing.T) {
+		router, repo := newRouter()
+		repo.tracks["t1"].SetISRC("short")
+
+		w := patch(router, "t1", `"3"`, `{"metadata":{"basic":{"title":""}}}`)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		var body struct {
+			Error struct {
+				Fields []struct {
+					Field string `json:"field"`
+				} `json:"fields"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Len(t, body.Error.Fields, 1)
+		assert.Equal(t, "metadata.basic.title", body.Error.Fields[0].Field)
+
+		w = patch(router, "t1", `"3"`, `{"metadata":{"basic":{"album":"Record"}}}`)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	})
+
+	t.Run("rejects a stale version", func(t *testing.T) {
+		router, _ := newRouter()
+		w := patch(router, "t1", `"2"`, `{"metadata":{"basic":{"album":"Record"}}}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("requires a version", func(t *testing.T) {
+		router, _ := newRouter()
+		w := patch(router, "t1", "", `{"metadata":{"basic":{"album":"Record"}}}`)
+		assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+	})
+
+	t.Run("rejects a patch that is not an object", func(t *testing.T) {
+		router, _ := newRouter()
+		w := patch(router, "t1", `"3"`, `["album"]`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unknown track", func(t *testing.T) {
+		router, _ := newRouter()
+		w := patch(router, "missing", `"3"`, `{}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
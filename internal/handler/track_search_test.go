@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// searchTrackRepository records the query of the last search
+type searchTrackRepository struct {
+	domain.TrackRepository
+	query map[string]interface{}
+}
+
+func (r *searchTrackRepository) SearchByMetadata(_ context.Context, query map[string]interface{}) ([]*domain.Track, error) {
+	r.query = query
+	return []*domain.Track{}, nil
+}
+
+func TestSearchTracks_Filter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &searchTrackRepository{}
+	router := gin.New()
+	router.POST("/tracks/search", NewTrackHandler(repo, nil, nil, nil, nil).SearchTracks)
+	search := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/tracks/search", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("filter reaches the repository", func(t *testing.T) {
+		w := search(`{"genre": "House", "filter": {"or": [
+			{"field": "bpm", "op": "between", "value": [120, 128]},
+			{"not": {"field": "tags", "op": "exists"}}
+		]}}`)
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "House", repo.query["genre"])
+		filter, ok := repo.query[domain.SearchFilterKey].(*domain.SearchFilter)
+		require.True(t, ok)
+		require.Len(t, filter.Or, 2)
+		assert.Equal(t, domain.SearchExists, filter.Or[1].Not.Op)
+	})
+
+	t.Run("invalid conditions are reported by path", func(t *testing.T) {
+		w := search(`{"filter": {"and": [
+			{"field": "title", "op": "gt", "value": 3},
+			{"field": "year", "op": "between", "value": [2020]},
+			{"field": "storage_path", "op": "eq", "value": "x"}
+		]}}`)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"filter.and[0].op"`)
+		assert.Contains(t, w.Body.String(), `"filter.and[1].value"`)
+		assert.Contains(t, w.Body.String(), `"filter.and[2].field"`)
+	})
+
+	t.Run("filters hold one part", func(t *testing.T) {
+		w := search(`{"filter": {"field": "genre", "op": "eq", "value": "house", "not": {"field": "bpm", "op": "exists"}}}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("filters are limited in depth", func(t *testing.T) {
+		filter := `{"field": "genre", "op": "exists"}`
+		for i := 0; i < domain.MaxSearchFilterDepth; i++ {
+			filter = `{"not": ` + filter + `}`
+		}
+		w := search(`{"filter": ` + filter + `}`)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "too_deep")
+	})
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"io"
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/pkg/metrics"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Renditions a track is streamed in, reported in the X-Rendition header
+const (
+	renditionPreview  = "preview"
+	renditionOriginal = "original"
+)
+
+// SetStreamURLExpiry redirects audio streams to signed storage URLs valid
+// for expiry. With zero, the default, audio is streamed through the API.
+func (h *TrackHandler) SetStreamURLExpiry(expiry time.Duration) {
+	h.streamURLExpiry = expiry
+}
+
+// StreamTrack streams a track's audio for auditioning
+// @Summary Stream track audio
+// @Description Stream the audio of a track for auditioning. The preview rendition is served when one has been stored, otherwise the original file; the X-Rendition header says which. Depending on the storage configuration the response either carries the audio, honoring Range requests with 206 Partial Content, or redirects with 307 to a short-lived signed storage URL.
+// @Tags tracks
+// @Produce octet-stream
+// @Param id path string true "Track ID"
+// @Param Range header string false "Byte range of the audio to return, such as bytes=0-1023"
+// @Success 200 {file} file "Audio"
+// @Success 206 {file} file "Requested byte range of the audio"
+// @Success 307 "Audio at the signed URL in Location"
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "File is awaiting a malware scan"
+// @Failure 416 "Requested range not satisfiable"
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/{id}/stream [get]
+func (h *TrackHandler) StreamTrack(c *gin.Context) {
+	ctx := c.Request.Context()
+	track, err := h.trackRepo.GetByID(c, c.Param("id"))
+	if err != nil {
+		h.handleError(c, apperrors.NewDatabaseError("failed to get track", err))
+		return
+	}
+	if track == nil || track.DeletedAt != nil || track.StoragePath == "" {
+		h.handleError(c, apperrors.NewNotFoundError("track audio not found"))
+		return
+	}
+	if domain.IsQuarantined(track.StoragePath) {
+		h.handleError(c, apperrors.NewConflictError("track audio is not available", "file is awaiting a malware scan"))
+		return
+	}
+
+	// Previews are optional, so anything but a stored preview falls back to
+	// the original
+	key, rendition := track.StoragePath, renditionOriginal
+	if _, err := h.storageService.GetMetadata(ctx, domain.PreviewKey(track.ID)); err == nil {
+		key, rendition = domain.PreviewKey(track.ID), renditionPreview
+	}
+	c.Header("X-Rendition", rendition)
+
+	if h.streamURLExpiry > 0 {
+		url, err := h.storageService.GetSignedURL(ctx, key, h.streamURLExpiry)
+		if err != nil {
+			metrics.AudioStreams.WithLabelValues("redirect", rendition, "failure").Inc()
+			h.handleError(c, apperrors.NewStorageError("failed to sign stream URL", err))
+			return
+		}
+		metrics.AudioStreams.WithLabelValues("redirect", rendition, "full").Inc()
+		// The URL grants access to the file, so it must not outlive it
+		c.Header("Cache-Control", "private, no-store")
+		c.Redirect(http.StatusTemporaryRedirect, url)
+		return
+	}
+
+	file, err := h.storageService.Download(ctx, key)
+	if err != nil {
+		metrics.AudioStreams.WithLabelValues("proxy", rendition, "failure").Inc()
+		h.handleError(c, apperrors.NewStorageError("failed to open track audio", err))
+		return
+	}
+	if closer, ok := file.Content.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	c.Header("Cache-Control", "private")
+	if file.ContentType != "" {
+		c.Header("Content-Type", file.ContentType)
+	}
+	if seeker, ok := file.Content.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, filepath.Base(key), file.UploadedAt, seeker)
+	} else {
+		// Ranges need a seekable file, so the whole file is sent instead
+		c.Header("Accept-Ranges", "none")
+		contentType, length := file.ContentType, file.Size
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		if length <= 0 {
+			length = -1
+		}
+		c.DataFromReader(http.StatusOK, length, contentType, file.Content, nil)
+	}
+
+	status := "failure"
+	switch c.Writer.Status() {
+	case http.StatusOK:
+		status = "full"
+	case http.StatusPartialContent:
+		status = "partial"
+	}
+	metrics.AudioStreams.WithLabelValues("proxy", rendition, status).Inc()
+	if size := c.Writer.Size(); size > 0 {
+		metrics.AudioStreamBytes.WithLabelValues(rendition).Add(float64(size))
+	}
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// audioStorage serves files from memory, seekable unless streaming is set
+type audioStorage struct {
+	domain.StorageService
+	files     map[string][]byte
+	streaming bool
+}
+
+func (s *audioStorage) GetMetadata(_ context.Context, key string) (*domain.FileMetadata, error) {
+	data, ok := s.files[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &domain.FileMetadata{Key: key, Size: int64(len(data))}, nil
+}
+
+func (s *audioStorage) Download(_ context.Context, key string) (*domain.StorageFile, error) {
+	data, ok := s.files[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	var content io.Reader = bytes.NewReader(data)
+	if s.streaming {
+		content = io.NopCloser(content)
+	}
+	return &domain.StorageFile{Key: key, Size: int64(len(data)), ContentType: "audio/mpeg", Content: content}, nil
+}
+
+func (s *audioStorage) GetSignedURL(_ context.Context, key string, _ time.Duration) (string, error) {
+	return "https://bucket.example.com/" + key + "?X-Amz-Signature=sig", nil
+}
+
+func TestStreamTrack(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(storage *audioStorage, expiry time.Duration) *gin.Engine {
+		repo := &stubTrackRepository{tracks: map[string]*domain.Track{
+			"t1":      {ID: "t1", StoragePath: "tracks/t1.mp3"},
+			"t2":      {ID: "t2", StoragePath: "tracks/t2.mp3"},
+			"scan":    {ID: "scan", StoragePath: domain.QuarantinePrefix + "tracks/scan.mp3"},
+			"no-file": {ID: "no-file"},
+		}}
+		h := NewTrackHandler(repo, nil, storage, validator.NewValidator(), nil)
+		h.SetStreamURLExpiry(expiry)
+		router := gin.New()
+		router.GET("/tracks/:id/stream", h.StreamTrack)
+		return router
+	}
+	get := func(router *gin.Engine, path, rangeHeader string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if rangeHeader != "" {
+			req.Header.Set("Range", rangeHeader)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	files := func() map[string][]byte {
+		return map[string][]byte{
+			"tracks/t1.mp3":         []byte("original audio"),
+			domain.PreviewKey("t1"): []byte("preview audio"),
+			"tracks/t2.mp3":         []byte("original audio"),
+		}
+	}
+
+	t.Run("serves the preview with ranges", func(t *testing.T) {
+		router := newRouter(&audioStorage{files: files()}, 0)
+
+		w := get(router, "/tracks/t1/stream", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "preview audio", w.Body.String())
+		assert.Equal(t, "preview", w.Header().Get("X-Rendition"))
+		assert.Equal(t, "audio/mpeg", w.Header().Get("Content-Type"))
+		assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+
+		w = get(router, "/tracks/t1/stream", "bytes=0-6")
+		require.Equal(t, http.StatusPartialContent, w.Code)
+		assert.Equal(t, "preview", w.Body.String())
+		assert.Equal(t, "bytes 0-6/13", w.Header().Get("Content-Range"))
+
+		w = get(router, "/tracks/t1/stream", "bytes=100-")
+		assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+	})
+
+	t.Run("falls back to the original", func(t *testing.T) {
+		router := newRouter(&audioStorage{files: files()}, 0)
+
+		w := get(router, "/tracks/t2/stream", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "original audio", w.Body.String())
+		assert.Equal(t, "original", w.Header().Get("X-Rendition"))
+	})
+
+	t.Run("sends whole files that cannot seek", func(t *testing.T) {
+		router := newRouter(&audioStorage{files: files(), streaming: true}, 0)
+
+		w := get(router, "/tracks/t1/stream", "bytes=0-6")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "preview audio", w.Body.String())
+		assert.Equal(t, "none", w.Header().Get("Accept-Ranges"))
+	})
+
+	t.Run("redirects to signed URLs", func(t *testing.T) {
+		router := newRouter(&audioStorage{files: files()}, time.Minute)
+
+		w := get(router, "/tracks/t2/stream", "")
+		require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+		assert.Equal(t, "https://bucket.example.com/tracks/t2.mp3?X-Amz-Signature=sig", w.Header().Get("Location"))
+		assert.Equal(t, "private, no-store", w.Header().Get("Cache-Control"))
+		assert.Equal(t, "original", w.Header().Get("X-Rendition"))
+	})
+
+	t.Run("rejects tracks without streamable audio", func(t *testing.T) {
+		router := newRouter(&audioStorage{files: files()}, 0)
+
+		assert.Equal(t, http.StatusNotFound, get(router, "/tracks/missing/stream", "").Code)
+		assert.Equal(t, http.StatusNotFound, get(router, "/tracks/no-file/stream", "").Code)
+		assert.Equal(t, http.StatusConflict, get(router, "/tracks/scan/stream", "").Code)
+	})
+}
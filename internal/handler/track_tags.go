@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"context"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+)
+
+// SetTags writes the tags of saved tracks in canonical form, registers
+// them and applies the tagging rules with tags
+func (h *TrackHandler) SetTags(tags *usecase.TagUseCase) {
+	h.tags = tags
+}
+
+// tagTrack prepares the tags of a track being saved. Without a tag use
+// case tags are saved as sent.
+func (h *TrackHandler) tagTrack(ctx context.Context, track *domain.Track) *apperrors.AppError {
+	if h.tags == nil {
+		return nil
+	}
+	if _, err := h.tags.Tag(ctx, track); err != nil {
+		return apperrors.NewDatabaseError("failed to apply tag rules", err)
+	}
+	return nil
+}
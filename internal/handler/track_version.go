@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"strings"
+	"time"
+
+	"metadatatool/internal/handler/middleware"
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// trackMapper maps tracks to and from the representation of one API
+// version. Handlers work on domain.Track and leave the wire format to the
+// mapper of the version the request was routed to.
+type trackMapper interface {
+	// bind reads the request body into a track. Fields the version cannot
+	// express are taken from base, which is nil when creating a track.
+	bind(c *gin.Context, base *domain.Track) (*domain.Track, *apperrors.AppError)
+	// track and list are the response bodies of one track and a page of them
+	track(track *domain.Track) interface{}
+	list(tracks []*domain.Track, page, limit int) interface{}
+	// field names a field of domain.Track the way the version does
+	field(path string) string
+}
+
+// trackMappers holds the mapper of every API version
+var trackMappers = map[string]trackMapper{
+	middleware.APIVersion1: trackV1Mapper{},
+	middleware.APIVersion2: trackV2Mapper{},
+}
+
+// trackMapperFor returns the mapper of the API version of the request,
+// version 1 for routes registered without one
+func trackMapperFor(c *gin.Context) trackMapper {
+	if mapper, ok := trackMappers[c.GetString(middleware.APIVersionKey)]; ok {
+		return mapper
+	}
+	return trackV1Mapper{}
+}
+
+// versionedFieldErrors converts validation errors to the fields of an error
+// response, named as the API version of the request does
+func versionedFieldErrors(c *gin.Context, errs []domain.ValidationError) []apperrors.FieldError {
+	mapper := trackMapperFor(c)
+	fields := fieldErrors(errs)
+	for i := range fields {
+		fields[i].Field = mapper.field(fields[i].Field)
+	}
+	return fields
+}
+
+// trackV1Mapper serves domain.Track as it is
+type trackV1Mapper struct{}
+
+func (trackV1Mapper) bind(c *gin.Context, _ *domain.Track) (*domain.Track, *apperrors.AppError) {
+	var track domain.Track
+	if err := bindJSON(c, &track); err != nil {
+		return nil, err
+	}
+	track.Lock = nil
+	return &track, nil
+}
+
+func (trackV1Mapper) track(track *domain.Track) interface{} { return track }
+
+func (trackV1Mapper) list(tracks []*domain.Track, page, limit int) interface{} {
+	return ListResponse{Tracks: tracks, Page: page, Limit: limit}
+}
+
+func (trackV1Mapper) field(path string) string { return path }
+
+// TrackV2 is a track in API version 2. The basic metadata is flattened onto
+// the track, the remaining metadata is grouped by topic and the deprecated
+// filePath is gone.
+type TrackV2 struct {
+	ID        string             `json:"id"`
+	Title     string             `json:"title"`
+	Artist    string             `json:"artist"`
+	Album     string             `json:"album,omitempty"`
+	Year      int                `json:"year,omitempty"`
+	Duration  float64            `json:"durationSeconds,omitempty"`
+	ISRC      string             `json:"isrc,omitempty"`
+	LabelID   string             `json:"labelId,omitempty"`
+	ArtistIDs []string           `json:"artistIds,omitempty"`
+	ReleaseID string             `json:"releaseId,omitempty"`
+	Status    domain.TrackStatus `json:"status,omitempty"`
+	Version   int                `json:"version"`
+	Musical   TrackMusicalV2     `json:"musical"`
+	Rights    TrackRightsV2      `json:"rights"`
+	Tags      []string           `json:"tags,omitempty"`
+	// CustomFields hold label-specific values, such as the ISWC
+	CustomFields map[string]string `json:"customFields,omitempty"`
+	// Audio, AI and Lock are set by the server and ignored in requests
+	Audio     *TrackAudioV2     `json:"audio,omitempty"`
+	AI        *TrackAIV2        `json:"ai,omitempty"`
+	Lock      *domain.TrackLock `json:"lock,omitempty"`
+	CreatedAt time.Time         `json:"createdAt"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+}
+
+// TrackMusicalV2 holds the musical attributes of a track
+type TrackMusicalV2 struct {
+	BPM    float64 `json:"bpm,omitempty"`
+	Key    string  `json:"key,omitempty"`
+	Mode   string  `json:"mode,omitempty"`
+	Mood   string  `json:"mood,omitempty"`
+	Genre  string  `json:"genre,omitempty"`
+	Energy float64 `json:"energy,omitempty"`
+}
+
+// TrackRightsV2 holds the publishing details of a track
+type TrackRightsV2 struct {
+	Publisher string `json:"publisher,omitempty"`
+	Copyright string `json:"copyright,omitempty"`
+}
+
+// TrackAudioV2 describes the stored audio file of a track
+type TrackAudioV2 struct {
+	Format     domain.AudioFormat `json:"format,omitempty"`
+	SampleRate int                `json:"sampleRate,omitempty"`
+	Bitrate    int                `json:"bitrate,omitempty"`
+	Channels   int                `json:"channels,omitempty"`
+	Size       int64              `json:"size"`
+}
+
+// TrackAIV2 summarises the AI enrichment of a track
+type TrackAIV2 struct {
+	Model       string    `json:"model"`
+	Version     string    `json:"version,omitempty"`
+	Confidence  float64   `json:"confidence"`
+	NeedsReview bool      `json:"needsReview"`
+	ProcessedAt time.Time `json:"processedAt"`
+}
+
+// ListResponseV2 is a page of tracks in API version 2
+type ListResponseV2 struct {
+	Tracks []TrackV2 `json:"tracks"`
+	Page   int       `json:"page"`
+	Limit  int       `json:"limit"`
+}
+
+// trackV2Fields names the fields of domain.Track that are named differently
+// in version 2
+var trackV2Fields = map[string]string{
+	"metadata.basic.title":                  "title",
+	"metadata.basic.artist":                 "artist",
+	"metadata.basic.album":                  "album",
+	"metadata.basic.year":                   "year",
+	"metadata.basic.duration":               "durationSeconds",
+	"metadata.basic.isrc":                   "isrc",
+	"metadata.additional.publisher":         "rights.publisher",
+	"metadata.additional.copyright":         "rights.copyright",
+	"metadata.additional.customFields":      "customFields",
+	"metadata.additional.customFields.iswc": "customFields.iswc",
+}
+
+// trackV2Mapper serves tracks as TrackV2
+type trackV2Mapper struct{}
+
+func (trackV2Mapper) bind(c *gin.Context, base *domain.Track) (*domain.Track, *apperrors.AppError) {
+	var body TrackV2
+	if err := bindJSON(c, &body); err != nil {
+		return nil, err
+	}
+	return trackFromV2(&body, base), nil
+}
+
+func (trackV2Mapper) track(track *domain.Track) interface{} { return trackToV2(track) }
+
+func (trackV2Mapper) list(tracks []*domain.Track, page, limit int) interface{} {
+	resp := ListResponseV2{Tracks: make([]TrackV2, len(tracks)), Page: page, Limit: limit}
+	for i, track := range tracks {
+		resp.Tracks[i] = trackToV2(track)
+	}
+	return resp
+}
+
+func (trackV2Mapper) field(path string) string {
+	if name, ok := trackV2Fields[path]; ok {
+		return name
+	}
+	if strings.HasPrefix(path, "metadata.musical.") {
+		return "musical." + strings.TrimPrefix(path, "metadata.musical.")
+	}
+	return path
+}
+
+// trackToV2 returns the version 2 representation of track
+func trackToV2(track *domain.Track) TrackV2 {
+	meta := track.Metadata
+	v2 := TrackV2{
+		ID:        track.ID,
+		Title:     meta.Title,
+		Artist:    meta.Artist,
+		Album:     meta.Album,
+		Year:      meta.Year,
+		Duration:  meta.Duration,
+		ISRC:      meta.ISRC,
+		LabelID:   track.LabelID,
+		ArtistIDs: track.ArtistIDs,
+		ReleaseID: track.ReleaseID,
+		Status:    track.Status,
+		Version:   track.Version,
+		Musical: TrackMusicalV2{
+			BPM:    meta.Musical.BPM,
+			Key:    meta.Musical.Key,
+			Mode:   meta.Musical.Mode,
+			Mood:   meta.Musical.Mood,
+			Genre:  meta.Musical.Genre,
+			Energy: meta.Musical.Energy,
+		},
+		Rights: TrackRightsV2{
+			Publisher: meta.Additional.Publisher,
+			Copyright: meta.Additional.Copyright,
+		},
+		Tags:         meta.Additional.Tags,
+		CustomFields: meta.Additional.CustomFields,
+		Lock:         track.Lock,
+		CreatedAt:    track.CreatedAt,
+		UpdatedAt:    track.UpdatedAt,
+	}
+	if track.StoragePath != "" {
+		v2.Audio = &TrackAudioV2{
+			Format:     meta.Technical.Format,
+			SampleRate: meta.Technical.SampleRate,
+			Bitrate:    meta.Technical.Bitrate,
+			Channels:   meta.Technical.Channels,
+			Size:       track.FileSize,
+		}
+	}
+	if ai := meta.AI; ai != nil {
+		v2.AI = &TrackAIV2{
+			Model:       ai.Model,
+			Version:     ai.Version,
+			Confidence:  ai.Confidence,
+			NeedsReview: ai.NeedsReview,
+			ProcessedAt: ai.ProcessedAt,
+		}
+	}
+	return v2
+}
+
+// trackFromV2 returns the track body describes. Fields version 2 does not
+// express, and those set by the server, are copied from base so replacing a
+// track through version 2 keeps them.
+func trackFromV2(body *TrackV2, base *domain.Track) *domain.Track {
+	track := &domain.Track{}
+	if base != nil {
+		track = base.Clone()
+	}
+	meta := &track.Metadata
+	meta.Title = body.Title
+	meta.Artist = body.Artist
+	meta.Album = body.Album
+	meta.Year = body.Year
+	meta.Duration = body.Duration
+	meta.ISRC = body.ISRC
+	meta.Musical.BPM = body.Musical.BPM
+	meta.Musical.Key = body.Musical.Key
+	meta.Musical.Mode = body.Musical.Mode
+	meta.Musical.Mood = body.Musical.Mood
+	meta.Musical.Genre = body.Musical.Genre
+	meta.Musical.Energy = body.Musical.Energy
+	meta.Additional.Publisher = body.Rights.Publisher
+	meta.Additional.Copyright = body.Rights.Copyright
+	meta.Additional.Tags = body.Tags
+	meta.Additional.CustomFields = body.CustomFields
+	track.LabelID = body.LabelID
+	track.ArtistIDs = body.ArtistIDs
+	track.ReleaseID = body.ReleaseID
+	track.Status = body.Status
+	track.Version = body.Version
+	return track
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"metadatatool/internal/handler/middleware"
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackHandler_V2(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func() (*gin.Engine, *stubTrackRepository) {
+		track := &domain.Track{ID: "t1", Version: 2, StoragePath: "audio/t1.mp3", FileSize: 1024}
+		track.SetTitle("Song")
+		track.SetArtist("Artist")
+		track.SetGenre("Pop")
+		track.Metadata.Additional.Lyrics = "la la la"
+		repo := &stubTrackRepository{tracks: map[string]*domain.Track{"t1": track}}
+		h := NewTrackHandler(repo, nil, nil, validator.NewValidator(), nil)
+		router := gin.New()
+		v1 := router.Group("/api/v1", middleware.APIVersion(middleware.APIVersion1))
+		v1.GET("/tracks/:id", h.GetTrack)
+		v2 := router.Group("/api/v2", middleware.APIVersion(middleware.APIVersion2))
+		v2.GET("/tracks/:id", h.GetTrack)
+		v2.PUT("/tracks/:id", h.UpdateTrack)
+		return router, repo
+	}
+	do := func(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("versions share tracks", func(t *testing.T) {
+		router, _ := newRouter()
+
+		w := do(router, http.MethodGet, "/api/v1/tracks/t1", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"basic":{"title":"Song"`)
+
+		w = do(router, http.MethodGet, "/api/v2/tracks/t1", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var track TrackV2
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &track))
+		assert.Equal(t, "Song", track.Title)
+		assert.Equal(t, "Pop", track.Musical.Genre)
+		require.NotNil(t, track.Audio)
+		assert.Equal(t, int64(1024), track.Audio.Size)
+		assert.NotContains(t, w.Body.String(), "filePath")
+	})
+
+	t.Run("update keeps fields v2 does not express", func(t *testing.T) {
+		router, repo := newRouter()
+
+		w := do(router, http.MethodPut, "/api/v2/tracks/t1", `{"title":"New Song","artist":"Artist","version":2,"musical":{"genre":"Rock"}}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		stored := repo.tracks["t1"]
+		assert.Equal(t, "New Song", stored.Title())
+		assert.Equal(t, "Rock", stored.Genre())
+		assert.Equal(t, "la la la", stored.Metadata.Additional.Lyrics)
+		assert.Equal(t, 3, stored.Version)
+	})
+
+	t.Run("validation errors use v2 field names", func(t *testing.T) {
+		router, _ := newRouter()
+
+		w := do(router, http.MethodPut, "/api/v2/tracks/t1", `{"artist":"Artist","version":2}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"title"`)
+	})
+}
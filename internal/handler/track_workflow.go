@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TransitionsResponse lists the statuses a track may move to
+type TransitionsResponse struct {
+	TrackID string               `json:"track_id"`
+	Status  domain.TrackStatus   `json:"status"`
+	Version int                  `json:"version"`
+	Next    []domain.TrackStatus `json:"next"`
+}
+
+// TransitionRequest moves a track to another status
+type TransitionRequest struct {
+	Status domain.TrackStatus `json:"status" binding:"required"`
+	// Message is the reason for the change, stored as the track's statusMsg
+	Message string `json:"message,omitempty"`
+}
+
+// GetTrackTransitions lists the statuses a track may move to
+// @Summary List allowed status changes
+// @Description Get a track's status and the statuses it may move to next. Tracks move from pending through processing, needs_review, approved and delivered to archived.
+// @Tags tracks
+// @Produce json
+// @Param id path string true "Track ID"
+// @Success 200 {object} TransitionsResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/{id}/transitions [get]
+func (h *TrackHandler) GetTrackTransitions(c *gin.Context) {
+	track, next, err := h.workflow.NextStatuses(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, apperrors.FromError(err, "failed to get track"))
+		return
+	}
+
+	c.Header("ETag", trackETag(track))
+	c.JSON(http.StatusOK, TransitionsResponse{
+		TrackID: track.ID,
+		Status:  track.Status,
+		Version: track.Version,
+		Next:    next,
+	})
+}
+
+// TransitionTrack moves a track to another status
+// @Summary Change track status
+// @Description Move a track to one of the statuses listed by GET /tracks/{id}/transitions. Other changes are refused with 409 and the INVALID_STATUS_TRANSITION code. With If-Match, the track must still be at that version.
+// @Tags tracks
+// @Accept json
+// @Produce json
+// @Param id path string true "Track ID"
+// @Param If-Match header string false "ETag of the track version being changed"
+// @Param request body TransitionRequest true "New status"
+// @Success 200 {object} domain.Track
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tracks/{id}/transitions [post]
+func (h *TrackHandler) TransitionTrack(c *gin.Context) {
+	var req TransitionRequest
+	if err := bindJSON(c, &req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	expectedVersion, err := expectedTrackVersion(c, &domain.Track{})
+	if err != nil {
+		h.handleError(c, apperrors.NewValidationError("invalid If-Match header", err.Error()))
+		return
+	}
+
+	track, err := h.workflow.Transition(c.Request.Context(), c.Param("id"), req.Status, req.Message, c.GetString("user_id"), expectedVersion)
+	if err != nil {
+		var conflict *domain.VersionConflictError
+		if errors.As(err, &conflict) {
+			h.handleConflict(c, conflict)
+			return
+		}
+		h.handleError(c, apperrors.FromError(err, "failed to change track status"))
+		return
+	}
+
+	c.Header("ETag", trackETag(track))
+	c.JSON(http.StatusOK, trackMapperFor(c).track(track))
+}
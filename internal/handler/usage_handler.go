@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
+	"metadatatool/internal/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsageHandler reports metered usage for invoicing
+type UsageHandler struct {
+	usage *usecase.UsageUseCase
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(usage *usecase.UsageUseCase) *UsageHandler {
+	return &UsageHandler{usage: usage}
+}
+
+// usageCSVHeader is the header row of the CSV export
+var usageCSVHeader = []string{"label_id", "day", "tracks_stored", "storage_bytes", "ai_enrichments", "exports", "public_api_requests"}
+
+// GetUsage returns daily usage per label
+// @Summary Get usage
+// @Description Get daily usage per label: tracks and bytes stored, AI enrichments, exported tracks and public API requests. Add format=csv to download it for invoicing.
+// @Tags usage
+// @Produce json
+// @Param label_id query string false "Only this label"
+// @Param from query string false "First day, YYYY-MM-DD; defaults to the start of the month"
+// @Param to query string false "Last day, YYYY-MM-DD; defaults to today"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} domain.UsageReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /usage [get]
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		h.handleError(c, apperrors.NewValidationError("unsupported format", fmt.Sprintf("format '%s' is not supported", format)))
+		return
+	}
+
+	report, err := h.usage.Report(c.Request.Context(), c.Query("label_id"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			h.handleError(c, apperrors.NewValidationError("invalid usage query", err.Error()))
+			return
+		}
+		h.handleError(c, apperrors.NewInternalError("failed to get usage", err))
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=usage-%s-%s.csv", report.From, report.To))
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write(usageCSVHeader)
+	for _, day := range report.Days {
+		_ = w.Write([]string{
+			day.LabelID,
+			day.Day,
+			strconv.FormatInt(day.TracksStored, 10),
+			strconv.FormatInt(day.StorageBytes, 10),
+			strconv.FormatInt(day.AIEnrichments, 10),
+			strconv.FormatInt(day.Exports, 10),
+			strconv.FormatInt(day.PublicAPIRequests, 10),
+		})
+	}
+	w.Flush()
+}
+
+func (h *UsageHandler) handleError(c *gin.Context, err *apperrors.AppError) {
+	apperrors.Respond(c, err)
+}
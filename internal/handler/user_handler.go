@@ -1,8 +1,8 @@
 package handler
 
 import (
-	"fmt"
 	"metadatatool/internal/pkg/domain"
+	apperrors "metadatatool/internal/pkg/errors"
 	"metadatatool/internal/pkg/errortracking"
 	"metadatatool/internal/pkg/metrics"
 	"net/http"
@@ -40,20 +40,20 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	}()
 
 	var user domain.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		h.handleError(c, http.StatusBadRequest, "invalid request body", err)
+	if err := bindJSON(c, &user); err != nil {
+		h.handleError(c, err)
 		return
 	}
 
-	if err := validateUser(&user); err != nil {
-		h.handleError(c, http.StatusBadRequest, "invalid user data", err)
+	if errs := validateUser(&user); len(errs) > 0 {
+		h.handleError(c, apperrors.NewFieldValidationError("invalid user data", fieldErrors(errs)))
 		return
 	}
 
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
-		h.handleError(c, http.StatusInternalServerError, "failed to hash password", err)
+		h.handleError(c, apperrors.NewInternalError("failed to hash password", err))
 		return
 	}
 	user.Password = string(hashedPassword)
@@ -70,7 +70,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	user.LastLoginAt = time.Now()
 
 	if err := h.userRepo.Create(c, &user); err != nil {
-		h.handleError(c, http.StatusInternalServerError, "failed to create user", err)
+		h.handleError(c, apperrors.NewInternalError("failed to create user", err))
 		return
 	}
 
@@ -89,18 +89,18 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 
 	id := c.Param("id")
 	if id == "" {
-		h.handleError(c, http.StatusBadRequest, "missing user ID", nil)
+		h.handleError(c, apperrors.NewValidationError("missing user ID", ""))
 		return
 	}
 
 	user, err := h.userRepo.GetByID(c, id)
 	if err != nil {
-		h.handleError(c, http.StatusInternalServerError, "failed to get user", err)
+		h.handleError(c, apperrors.NewInternalError("failed to get user", err))
 		return
 	}
 
 	if user == nil {
-		h.handleError(c, http.StatusNotFound, "user not found", nil)
+		h.handleError(c, apperrors.NewNotFoundError("user not found"))
 		return
 	}
 
@@ -119,20 +119,20 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 
 	id := c.Param("id")
 	if id == "" {
-		h.handleError(c, http.StatusBadRequest, "missing user ID", nil)
+		h.handleError(c, apperrors.NewValidationError("missing user ID", ""))
 		return
 	}
 
 	var user domain.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		h.handleError(c, http.StatusBadRequest, "invalid request body", err)
+	if err := bindJSON(c, &user); err != nil {
+		h.handleError(c, err)
 		return
 	}
 
 	user.ID = id
 
-	if err := validateUser(&user); err != nil {
-		h.handleError(c, http.StatusBadRequest, "invalid user data", err)
+	if errs := validateUser(&user); len(errs) > 0 {
+		h.handleError(c, apperrors.NewFieldValidationError("invalid user data", fieldErrors(errs)))
 		return
 	}
 
@@ -140,14 +140,14 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	if user.Password != "" {
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 		if err != nil {
-			h.handleError(c, http.StatusInternalServerError, "failed to hash password", err)
+			h.handleError(c, apperrors.NewInternalError("failed to hash password", err))
 			return
 		}
 		user.Password = string(hashedPassword)
 	}
 
 	if err := h.userRepo.Update(c, &user); err != nil {
-		h.handleError(c, http.StatusInternalServerError, "failed to update user", err)
+		h.handleError(c, apperrors.NewInternalError("failed to update user", err))
 		return
 	}
 
@@ -166,12 +166,12 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 
 	id := c.Param("id")
 	if id == "" {
-		h.handleError(c, http.StatusBadRequest, "missing user ID", nil)
+		h.handleError(c, apperrors.NewValidationError("missing user ID", ""))
 		return
 	}
 
 	if err := h.userRepo.Delete(c, id); err != nil {
-		h.handleError(c, http.StatusInternalServerError, "failed to delete user", err)
+		h.handleError(c, apperrors.NewInternalError("failed to delete user", err))
 		return
 	}
 
@@ -200,7 +200,7 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 
 	users, err := h.userRepo.List(c, offset, limit)
 	if err != nil {
-		h.handleError(c, http.StatusInternalServerError, "failed to list users", err)
+		h.handleError(c, apperrors.NewInternalError("failed to list users", err))
 		return
 	}
 
@@ -218,11 +218,11 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 
 // Helper functions and types
 
-func (h *UserHandler) handleError(c *gin.Context, status int, message string, err error) {
-	if err != nil {
-		h.errorTracker.CaptureError(err, map[string]string{
-			"status":    strconv.Itoa(status),
-			"message":   message,
+func (h *UserHandler) handleError(c *gin.Context, err *apperrors.AppError) {
+	if err.Err != nil {
+		h.errorTracker.CaptureErrorContext(c.Request.Context(), err.Err, map[string]string{
+			"status":    strconv.Itoa(err.StatusCode),
+			"message":   err.Message,
 			"path":      c.FullPath(),
 			"method":    c.Request.Method,
 			"client_ip": c.ClientIP(),
@@ -230,28 +230,21 @@ func (h *UserHandler) handleError(c *gin.Context, status int, message string, er
 	}
 
 	metrics.DatabaseOperationsTotal.WithLabelValues(c.Request.Method, "error").Inc()
-
-	response := ErrorResponse{
-		Error: message,
-	}
-	if err != nil && status >= 500 {
-		response.Details = err.Error()
-	}
-
-	c.JSON(status, response)
+	apperrors.Respond(c, err)
 }
 
-func validateUser(user *domain.User) error {
-	if user.Email == "" {
-		return fmt.Errorf("email is required")
-	}
-	if user.Password == "" {
-		return fmt.Errorf("password is required")
-	}
-	if user.Name == "" {
-		return fmt.Errorf("name is required")
+func validateUser(user *domain.User) []domain.ValidationError {
+	var errs []domain.ValidationError
+	for _, f := range []struct{ field, value string }{
+		{"email", user.Email},
+		{"password", user.Password},
+		{"name", user.Name},
+	} {
+		if f.value == "" {
+			errs = append(errs, domain.ValidationError{Field: f.field, Code: "required", Message: "is required"})
+		}
 	}
-	return nil
+	return errs
 }
 
 type ListUsersResponse struct {
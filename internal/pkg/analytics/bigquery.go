@@ -1,43 +1,47 @@
 // Package analytics provides analytics and metrics collection functionality.
 //
 // This package implements analytics services for tracking and analyzing AI
-// experiment results, using Google BigQuery as the backend storage.
+// experiment results and product usage. Events are stored in Google
+// BigQuery, ClickHouse or a PostgreSQL table, or written to stdout.
 //
 // Key features:
-//   - Recording AI experiment results
+//   - Buffered, batched recording of enrichment, validation, export and API
+//     usage events into day-partitioned tables
+//   - Schema migration that creates the tables and adds new columns
 //   - Calculating experiment metrics
 //   - Comparing control and experiment groups
 //
 // Usage example:
 //
-//	service, err := analytics.NewBigQueryService(projectID, dataset)
+//	service, err := analytics.NewService(&cfg.Analytics, db)
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
 //	defer service.Close()
 //
-//	record := &analytics.AIExperimentRecord{
-//	    Timestamp:       time.Now(),
-//	    TrackID:        "track123",
-//	    ModelProvider:  "openai",
-//	    ModelVersion:   "v1",
-//	    ProcessingTime: 1.5,
-//	    Confidence:    0.95,
-//	    Success:       true,
+//	if err := service.Migrate(ctx); err != nil {
+//	    log.Printf("Failed to migrate analytics tables: %v", err)
 //	}
 //
-//	if err := service.RecordAIExperiment(ctx, record); err != nil {
-//	    log.Printf("Failed to record experiment: %v", err)
-//	}
+//	service.Record(analytics.ExportEvent{
+//	    Timestamp:  time.Now(),
+//	    Format:     "ddex",
+//	    TrackCount: 12,
+//	    Success:    true,
+//	})
 package analytics
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"metadatatool/internal/pkg/config"
 	"metadatatool/internal/pkg/domain"
+	"net/http"
 	"time"
 
 	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
@@ -81,36 +85,149 @@ type BigQueryService struct {
 	client          *bigquery.Client
 	projectID       string
 	dataset         string
+	retention       time.Duration
 	experimentTable *bigquery.Table
+	loader          *EventLoader
 }
 
 // NewBigQueryService creates a new BigQuery service instance.
-// It establishes a connection to BigQuery and initializes the required
-// dataset and table references.
+// It establishes a connection to BigQuery, initializes the required
+// dataset and table references and starts loading recorded events.
 //
 // Parameters:
-//   - projectID: Google Cloud project ID
-//   - dataset: BigQuery dataset name
+//   - cfg: Project, dataset and event buffering settings
 //
 // Returns:
 //   - *BigQueryService: Initialized service
 //   - error: Any error that occurred during initialization
-func NewBigQueryService(projectID, dataset string) (*BigQueryService, error) {
+func NewBigQueryService(cfg *config.AnalyticsConfig) (*BigQueryService, error) {
 	ctx := context.Background()
-	client, err := bigquery.NewClient(ctx, projectID, option.WithScopes(bigquery.Scope))
+	client, err := bigquery.NewClient(ctx, cfg.ProjectID, option.WithScopes(bigquery.Scope))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
 	}
 
-	ds := client.Dataset(dataset)
+	ds := client.Dataset(cfg.Dataset)
 	table := ds.Table("ai_experiments")
 
-	return &BigQueryService{
+	service := &BigQueryService{
 		client:          client,
-		projectID:       projectID,
-		dataset:         dataset,
+		projectID:       cfg.ProjectID,
+		dataset:         cfg.Dataset,
+		retention:       cfg.Retention,
 		experimentTable: table,
-	}, nil
+	}
+	service.loader = NewEventLoader(service, LoaderConfig{
+		FlushInterval: cfg.FlushInterval,
+		BatchSize:     cfg.BatchSize,
+		BufferSize:    cfg.BufferSize,
+	})
+	return service, nil
+}
+
+// Record buffers an analytics event. Events are inserted in batches in the
+// background; Record never blocks. Recording on a nil service does nothing.
+func (s *BigQueryService) Record(event Event) {
+	if s == nil {
+		return
+	}
+	s.loader.Record(event)
+}
+
+// Migrate creates the dataset and the event tables if they are missing and
+// adds columns that were added to the event structs since.
+//
+// Parameters:
+//   - ctx: Context for the operation
+//
+// Returns:
+//   - error: Any error that occurred during the operation
+func (s *BigQueryService) Migrate(ctx context.Context) error {
+	ds := s.client.Dataset(s.dataset)
+	if _, err := ds.Metadata(ctx); err != nil {
+		if !isNotFound(err) {
+			return fmt.Errorf("failed to read dataset %s: %w", s.dataset, err)
+		}
+		if err := ds.Create(ctx, &bigquery.DatasetMetadata{}); err != nil {
+			return fmt.Errorf("failed to create dataset %s: %w", s.dataset, err)
+		}
+	}
+
+	for _, event := range eventTables {
+		if err := s.migrateTable(ctx, ds.Table(event.Table()), event); err != nil {
+			return fmt.Errorf("failed to migrate table %s: %w", event.Table(), err)
+		}
+	}
+	return nil
+}
+
+func (s *BigQueryService) migrateTable(ctx context.Context, table *bigquery.Table, event Event) error {
+	schema, err := bigquery.InferSchema(event)
+	if err != nil {
+		return err
+	}
+
+	meta, err := table.Metadata(ctx)
+	if isNotFound(err) {
+		return table.Create(ctx, &bigquery.TableMetadata{
+			Schema: schema,
+			TimePartitioning: &bigquery.TimePartitioning{
+				Type:       bigquery.DayPartitioningType,
+				Field:      "timestamp",
+				Expiration: s.retention,
+			},
+			Clustering: &bigquery.Clustering{Fields: []string{"tenant"}},
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	var update bigquery.TableMetadataToUpdate
+	changed := false
+	if missing := missingFields(meta.Schema, schema); len(missing) > 0 {
+		update.Schema = append(meta.Schema, missing...)
+		changed = true
+	}
+	if meta.TimePartitioning != nil && meta.TimePartitioning.Expiration != s.retention {
+		update.TimePartitioning = &bigquery.TimePartitioning{Expiration: s.retention}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	_, err = table.Update(ctx, update, meta.ETag)
+	return err
+}
+
+// missingFields returns the fields of want that existing lacks. They are
+// returned as nullable, since BigQuery can only add nullable columns.
+func missingFields(existing, want bigquery.Schema) bigquery.Schema {
+	have := make(map[string]bool, len(existing))
+	for _, field := range existing {
+		have[field.Name] = true
+	}
+
+	var missing bigquery.Schema
+	for _, field := range want {
+		if have[field.Name] {
+			continue
+		}
+		added := *field
+		added.Required = false
+		missing = append(missing, &added)
+	}
+	return missing
+}
+
+// insert implements eventSink
+func (s *BigQueryService) insert(ctx context.Context, table string, rows []Event) error {
+	return s.client.Dataset(s.dataset).Table(table).Inserter().Put(ctx, rows)
+}
+
+func isNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
 }
 
 // RecordAIExperiment records an AI experiment result in BigQuery.
@@ -200,8 +317,12 @@ func (s *BigQueryService) GetExperimentMetrics(ctx context.Context, start, end t
 	return metrics, nil
 }
 
-// Close closes the BigQuery client and releases associated resources.
+// Close inserts the buffered events, closes the BigQuery client and
+// releases associated resources.
 // This method should be called when the service is no longer needed.
 func (s *BigQueryService) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	s.loader.Close(ctx)
 	return s.client.Close()
 }
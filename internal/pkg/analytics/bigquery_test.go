@@ -0,0 +1,24 @@
+package analytics
+
+import (
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissingFields(t *testing.T) {
+	want, err := bigquery.InferSchema(ExportEvent{})
+	require.NoError(t, err)
+
+	existing := bigquery.Schema{want[0], want[1], want[2]}
+	missing := missingFields(existing, want)
+
+	require.Len(t, missing, len(want)-3)
+	assert.Equal(t, want[3].Name, missing[0].Name)
+	for _, field := range missing {
+		assert.False(t, field.Required)
+	}
+	assert.Empty(t, missingFields(want, want))
+}
@@ -0,0 +1,163 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"metadatatool/internal/pkg/config"
+)
+
+// clickHouseTimeFormat is the DateTime64 text format ClickHouse parses
+const clickHouseTimeFormat = "2006-01-02 15:04:05.000"
+
+// clickHouseStore writes events to ClickHouse through its HTTP interface.
+// Tables use the MergeTree engine, partitioned by day and ordered by tenant.
+type clickHouseStore struct {
+	client    *http.Client
+	config    config.ClickHouseConfig
+	retention time.Duration
+}
+
+func newClickHouseStore(cfg *config.ClickHouseConfig, retention time.Duration) *clickHouseStore {
+	return &clickHouseStore{
+		client:    &http.Client{Timeout: 30 * time.Second},
+		config:    *cfg,
+		retention: retention,
+	}
+}
+
+// insert implements eventSink
+func (s *clickHouseStore) insert(ctx context.Context, table string, rows []Event) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, event := range rows {
+		row := rowOf(event)
+		for name, value := range row {
+			if t, ok := value.(time.Time); ok {
+				row[name] = t.UTC().Format(clickHouseTimeFormat)
+			}
+		}
+		if err := enc.Encode(row); err != nil {
+			return fmt.Errorf("failed to encode %s row: %w", table, err)
+		}
+	}
+	return s.exec(ctx, fmt.Sprintf("INSERT INTO %s FORMAT JSONEachRow", s.table(table)), &body)
+}
+
+// migrate implements eventStore. Columns added to an event are added to
+// its existing table.
+func (s *clickHouseStore) migrate(ctx context.Context) error {
+	if err := s.exec(ctx, "CREATE DATABASE IF NOT EXISTS "+quoteClickHouse(s.config.Database), nil); err != nil {
+		return err
+	}
+
+	for _, event := range eventTables {
+		columns := columnsOf(event)
+		definitions := make([]string, 0, len(columns))
+		additions := make([]string, 0, len(columns))
+		for _, col := range columns {
+			definition := quoteClickHouse(col.name) + " " + clickHouseType(col.typ)
+			definitions = append(definitions, definition)
+			additions = append(additions, "ADD COLUMN IF NOT EXISTS "+definition)
+		}
+
+		create := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s) ENGINE = MergeTree PARTITION BY toYYYYMMDD(timestamp) ORDER BY (tenant, timestamp)",
+			s.table(event.Table()), strings.Join(definitions, ", "))
+		if s.retention > 0 {
+			create += s.ttl()
+		}
+		if err := s.exec(ctx, create, nil); err != nil {
+			return fmt.Errorf("failed to create table %s: %w", event.Table(), err)
+		}
+		if err := s.exec(ctx, fmt.Sprintf("ALTER TABLE %s %s", s.table(event.Table()), strings.Join(additions, ", ")), nil); err != nil {
+			return fmt.Errorf("failed to add columns to %s: %w", event.Table(), err)
+		}
+		if s.retention > 0 {
+			if err := s.exec(ctx, fmt.Sprintf("ALTER TABLE %s MODIFY%s", s.table(event.Table()), s.ttl()), nil); err != nil {
+				return fmt.Errorf("failed to set retention of %s: %w", event.Table(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// close implements eventStore
+func (s *clickHouseStore) close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// exec runs query, followed by body if given, and fails on any non-200 answer
+func (s *clickHouseStore) exec(ctx context.Context, query string, body io.Reader) error {
+	endpoint, err := url.Parse(s.config.URL)
+	if err != nil {
+		return fmt.Errorf("invalid ClickHouse URL: %w", err)
+	}
+	if body == nil {
+		body = strings.NewReader(query)
+	} else {
+		params := endpoint.Query()
+		params.Set("query", query)
+		endpoint.RawQuery = params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), body)
+	if err != nil {
+		return err
+	}
+	if s.config.User != "" {
+		req.Header.Set("X-ClickHouse-User", s.config.User)
+	}
+	if s.config.Password != "" {
+		req.Header.Set("X-ClickHouse-Key", s.config.Password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ClickHouse request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("ClickHouse returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func (s *clickHouseStore) table(name string) string {
+	return quoteClickHouse(s.config.Database) + "." + quoteClickHouse(name)
+}
+
+func (s *clickHouseStore) ttl() string {
+	return fmt.Sprintf(" TTL toDateTime(timestamp) + INTERVAL %d SECOND", int64(s.retention.Seconds()))
+}
+
+// clickHouseType maps an event field to its column type
+func clickHouseType(t reflect.Type) string {
+	if t == timeType {
+		return "DateTime64(3, 'UTC')"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "Bool"
+	case reflect.Int, reflect.Int64:
+		return "Int64"
+	case reflect.Float64:
+		return "Float64"
+	default:
+		return "String"
+	}
+}
+
+func quoteClickHouse(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "\\`") + "`"
+}
@@ -0,0 +1,91 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clickHouseRequest struct {
+	query string
+	body  string
+	user  string
+}
+
+func newClickHouseServer(t *testing.T, status int) (*httptest.Server, *[]clickHouseRequest) {
+	var mu sync.Mutex
+	var requests []clickHouseRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, clickHouseRequest{
+			query: r.URL.Query().Get("query"),
+			body:  string(body),
+			user:  r.Header.Get("X-ClickHouse-User"),
+		})
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestClickHouseStore_Insert(t *testing.T) {
+	server, requests := newClickHouseServer(t, http.StatusOK)
+	store := newClickHouseStore(&config.ClickHouseConfig{URL: server.URL, Database: "analytics", User: "writer"}, 0)
+
+	at := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	err := store.insert(context.Background(), APIUsageTable, []Event{
+		APIUsageEvent{Timestamp: at, Route: "/tracks/:id", Status: 200},
+		APIUsageEvent{Timestamp: at, Route: "/tracks", Status: 201},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *requests, 1)
+	req := (*requests)[0]
+	assert.Equal(t, "INSERT INTO `analytics`.`api_usage` FORMAT JSONEachRow", req.query)
+	assert.Equal(t, "writer", req.user)
+
+	lines := strings.Split(strings.TrimSpace(req.body), "\n")
+	require.Len(t, lines, 2)
+	var row map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &row))
+	assert.Equal(t, "2024-05-01 12:30:00.000", row["timestamp"])
+	assert.Equal(t, "/tracks/:id", row["route"])
+}
+
+func TestClickHouseStore_Migrate(t *testing.T) {
+	server, requests := newClickHouseServer(t, http.StatusOK)
+	store := newClickHouseStore(&config.ClickHouseConfig{URL: server.URL, Database: "analytics"}, 24*time.Hour)
+
+	require.NoError(t, store.migrate(context.Background()))
+
+	// One database, then create, add columns and retention for each table
+	require.Len(t, *requests, 1+3*len(eventTables))
+	assert.Equal(t, "CREATE DATABASE IF NOT EXISTS `analytics`", (*requests)[0].body)
+	create := (*requests)[1].body
+	assert.Contains(t, create, "CREATE TABLE IF NOT EXISTS `analytics`.`enrichment_runs`")
+	assert.Contains(t, create, "`timestamp` DateTime64(3, 'UTC')")
+	assert.Contains(t, create, "`confidence` Float64")
+	assert.Contains(t, create, "TTL toDateTime(timestamp) + INTERVAL 86400 SECOND")
+	assert.Contains(t, (*requests)[2].body, "ADD COLUMN IF NOT EXISTS `needs_review` Bool")
+}
+
+func TestClickHouseStore_ReportsErrors(t *testing.T) {
+	server, _ := newClickHouseServer(t, http.StatusBadRequest)
+	store := newClickHouseStore(&config.ClickHouseConfig{URL: server.URL, Database: "analytics"}, 0)
+
+	err := store.insert(context.Background(), ExportTable, []Event{ExportEvent{}})
+	assert.ErrorContains(t, err, "400")
+}
@@ -0,0 +1,150 @@
+package analytics
+
+import (
+	"time"
+)
+
+// Event is an analytics record stored as a row of its table. Every table is
+// partitioned by day on the timestamp column.
+type Event interface {
+	// Table returns the name of the table the event is stored in
+	Table() string
+}
+
+// EventRecorder accepts analytics events for asynchronous delivery
+type EventRecorder interface {
+	Record(event Event)
+}
+
+// Table names of the analytics events
+const (
+	EnrichmentTable  = "enrichment_runs"
+	ValidationTable  = "validation_scores"
+	ExportTable      = "exports"
+	APIUsageTable    = "api_usage"
+	AccuracyTable    = "ai_field_accuracy"
+	CalibrationTable = "ai_calibration"
+	CatalogTable     = "catalog_events"
+)
+
+// EnrichmentEvent records one AI enrichment of a track
+type EnrichmentEvent struct {
+	Timestamp       time.Time `bigquery:"timestamp"`
+	TrackID         string    `bigquery:"track_id"`
+	Tenant          string    `bigquery:"tenant"`
+	Provider        string    `bigquery:"provider"`
+	Model           string    `bigquery:"model"`
+	ModelVersion    string    `bigquery:"model_version"`
+	ExperimentGroup string    `bigquery:"experiment_group"`
+	DurationMs      int64     `bigquery:"duration_ms"`
+	Confidence      float64   `bigquery:"confidence"`
+	NeedsReview     bool      `bigquery:"needs_review"`
+	Success         bool      `bigquery:"success"`
+	Error           string    `bigquery:"error"`
+}
+
+// Table implements Event
+func (EnrichmentEvent) Table() string { return EnrichmentTable }
+
+// ValidationEvent records an AI validation score for a track
+type ValidationEvent struct {
+	Timestamp  time.Time `bigquery:"timestamp"`
+	TrackID    string    `bigquery:"track_id"`
+	Tenant     string    `bigquery:"tenant"`
+	Provider   string    `bigquery:"provider"`
+	Score      float64   `bigquery:"score"`
+	DurationMs int64     `bigquery:"duration_ms"`
+	Success    bool      `bigquery:"success"`
+	Error      string    `bigquery:"error"`
+}
+
+// Table implements Event
+func (ValidationEvent) Table() string { return ValidationTable }
+
+// ExportEvent records an export of tracks
+type ExportEvent struct {
+	Timestamp  time.Time `bigquery:"timestamp"`
+	Tenant     string    `bigquery:"tenant"`
+	Format     string    `bigquery:"format"`
+	TrackCount int       `bigquery:"track_count"`
+	DurationMs int64     `bigquery:"duration_ms"`
+	Success    bool      `bigquery:"success"`
+	Error      string    `bigquery:"error"`
+}
+
+// Table implements Event
+func (ExportEvent) Table() string { return ExportTable }
+
+// APIUsageEvent records one API request
+type APIUsageEvent struct {
+	Timestamp  time.Time `bigquery:"timestamp"`
+	Tenant     string    `bigquery:"tenant"`
+	Method     string    `bigquery:"method"`
+	Route      string    `bigquery:"route"`
+	Status     int       `bigquery:"status"`
+	DurationMs int64     `bigquery:"duration_ms"`
+}
+
+// Table implements Event
+func (APIUsageEvent) Table() string { return APIUsageTable }
+
+// FieldAccuracyEvent records how often an AI provider got a field of the
+// golden dataset right in one evaluation. Evaluations cover every tenant,
+// so Tenant is empty.
+type FieldAccuracyEvent struct {
+	Timestamp time.Time `bigquery:"timestamp"`
+	Tenant    string    `bigquery:"tenant"`
+	Provider  string    `bigquery:"provider"`
+	Field     string    `bigquery:"field"`
+	Samples   int       `bigquery:"samples"`
+	Correct   int       `bigquery:"correct"`
+	Accuracy  float64   `bigquery:"accuracy"`
+}
+
+// Table implements Event
+func (FieldAccuracyEvent) Table() string { return AccuracyTable }
+
+// CalibrationEvent records one bin of an AI provider's calibration curve:
+// the accuracy of its enrichments within a confidence range
+type CalibrationEvent struct {
+	Timestamp      time.Time `bigquery:"timestamp"`
+	Tenant         string    `bigquery:"tenant"`
+	Provider       string    `bigquery:"provider"`
+	MinConfidence  float64   `bigquery:"min_confidence"`
+	MaxConfidence  float64   `bigquery:"max_confidence"`
+	Samples        int       `bigquery:"samples"`
+	Correct        int       `bigquery:"correct"`
+	MeanConfidence float64   `bigquery:"mean_confidence"`
+	Accuracy       float64   `bigquery:"accuracy"`
+}
+
+// Table implements Event
+func (CalibrationEvent) Table() string { return CalibrationTable }
+
+// CatalogEvent records a domain event of a track, such as its creation or
+// a failed validation
+type CatalogEvent struct {
+	Timestamp time.Time `bigquery:"timestamp"`
+	Tenant    string    `bigquery:"tenant"`
+	Event     string    `bigquery:"event"`
+	TrackID   string    `bigquery:"track_id"`
+	LabelID   string    `bigquery:"label_id"`
+	// Errors counts the failed rules of a track that failed validation
+	Errors int `bigquery:"errors"`
+}
+
+// Table implements Event
+func (CatalogEvent) Table() string { return CatalogTable }
+
+// eventTables lists every event table with an example row its schema is
+// inferred from. New columns may be added to the structs; Migrate adds them
+// to existing tables. Columns cannot be renamed or removed.
+var eventTables = []Event{
+	EnrichmentEvent{},
+	ValidationEvent{},
+	ExportEvent{},
+	APIUsageEvent{},
+	FieldAccuracyEvent{},
+	CalibrationEvent{},
+	CatalogEvent{},
+}
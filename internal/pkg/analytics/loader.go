@@ -0,0 +1,147 @@
+package analytics
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"metadatatool/internal/pkg/metrics"
+)
+
+// eventSink stores batches of rows in a table
+type eventSink interface {
+	insert(ctx context.Context, table string, rows []Event) error
+}
+
+// LoaderConfig controls how events are buffered
+type LoaderConfig struct {
+	FlushInterval time.Duration
+	BatchSize     int
+	BufferSize    int
+}
+
+// EventLoader buffers events and inserts them in batches. Recording never
+// blocks: events arriving while the buffer is full are dropped and counted.
+type EventLoader struct {
+	sink   eventSink
+	config LoaderConfig
+
+	mu      sync.Mutex
+	pending map[string][]Event
+	count   int
+
+	flush chan struct{}
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// NewEventLoader creates a loader and starts flushing in the background
+func NewEventLoader(sink eventSink, config LoaderConfig) *EventLoader {
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 10 * time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 500
+	}
+	if config.BufferSize < config.BatchSize {
+		config.BufferSize = config.BatchSize
+	}
+
+	l := &EventLoader{
+		sink:    sink,
+		config:  config,
+		pending: make(map[string][]Event),
+		flush:   make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// Record buffers an event for the next batch
+func (l *EventLoader) Record(event Event) {
+	l.mu.Lock()
+	if l.count >= l.config.BufferSize {
+		l.mu.Unlock()
+		metrics.AnalyticsEvents.WithLabelValues(event.Table(), "dropped").Inc()
+		return
+	}
+	l.pending[event.Table()] = append(l.pending[event.Table()], event)
+	l.count++
+	full := l.count >= l.config.BatchSize
+	l.mu.Unlock()
+
+	if full {
+		select {
+		case l.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush inserts every buffered event. Batches that fail are put back into
+// the buffer as far as it has room.
+func (l *EventLoader) Flush(ctx context.Context) {
+	l.mu.Lock()
+	pending := l.pending
+	l.pending = make(map[string][]Event)
+	l.count = 0
+	l.mu.Unlock()
+
+	for table, events := range pending {
+		for start := 0; start < len(events); start += l.config.BatchSize {
+			end := start + l.config.BatchSize
+			if end > len(events) {
+				end = len(events)
+			}
+			batch := events[start:end]
+			if err := l.sink.insert(ctx, table, batch); err != nil {
+				log.Printf("Failed to insert %d analytics events into %s: %v", len(batch), table, err)
+				metrics.AnalyticsEvents.WithLabelValues(table, "failed").Add(float64(len(batch)))
+				l.requeue(table, events[start:])
+				break
+			}
+			metrics.AnalyticsEvents.WithLabelValues(table, "inserted").Add(float64(len(batch)))
+		}
+	}
+}
+
+// Close stops the background flushing and inserts the remaining events
+func (l *EventLoader) Close(ctx context.Context) {
+	close(l.stop)
+	<-l.done
+	l.Flush(ctx)
+}
+
+func (l *EventLoader) run() {
+	defer close(l.done)
+	ticker := time.NewTicker(l.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		case <-l.flush:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), l.config.FlushInterval)
+		l.Flush(ctx)
+		cancel()
+	}
+}
+
+func (l *EventLoader) requeue(table string, events []Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	room := l.config.BufferSize - l.count
+	if room < len(events) {
+		metrics.AnalyticsEvents.WithLabelValues(table, "dropped").Add(float64(len(events) - room))
+		events = events[:room]
+	}
+	l.pending[table] = append(append([]Event(nil), events...), l.pending[table]...)
+	l.count += len(events)
+}
@@ -0,0 +1,109 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSink struct {
+	mu      sync.Mutex
+	batches map[string][][]Event
+	err     error
+}
+
+func (s *fakeSink) insert(_ context.Context, table string, rows []Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if s.batches == nil {
+		s.batches = make(map[string][][]Event)
+	}
+	s.batches[table] = append(s.batches[table], rows)
+	return nil
+}
+
+func (s *fakeSink) rows(table string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, batch := range s.batches[table] {
+		n += len(batch)
+	}
+	return n
+}
+
+func TestEventLoader_FlushesFullBatches(t *testing.T) {
+	sink := &fakeSink{}
+	l := NewEventLoader(sink, LoaderConfig{FlushInterval: time.Hour, BatchSize: 2, BufferSize: 10})
+	defer l.Close(context.Background())
+
+	l.Record(ExportEvent{Format: "json"})
+	l.Record(ExportEvent{Format: "ddex"})
+
+	require.Eventually(t, func() bool { return sink.rows(ExportTable) == 2 }, time.Second, time.Millisecond)
+}
+
+func TestEventLoader_SplitsTablesAndBatches(t *testing.T) {
+	sink := &fakeSink{}
+	l := NewEventLoader(sink, LoaderConfig{FlushInterval: time.Hour, BatchSize: 2, BufferSize: 10})
+
+	for i := 0; i < 3; i++ {
+		l.Record(APIUsageEvent{Status: 200})
+	}
+	l.Record(ValidationEvent{Score: 0.9})
+	l.Close(context.Background())
+
+	assert.Equal(t, 3, sink.rows(APIUsageTable))
+	assert.Equal(t, 1, sink.rows(ValidationTable))
+	for _, batch := range sink.batches[APIUsageTable] {
+		assert.LessOrEqual(t, len(batch), 2)
+	}
+}
+
+func TestEventLoader_DropsWhenBufferIsFull(t *testing.T) {
+	sink := &fakeSink{err: errors.New("unavailable")}
+	l := NewEventLoader(sink, LoaderConfig{FlushInterval: time.Hour, BatchSize: 3, BufferSize: 3})
+	defer l.Close(context.Background())
+
+	// Wait until the full batch has been flushed and requeued after failing
+	for i := 0; i < 3; i++ {
+		l.Record(EnrichmentEvent{TrackID: "t"})
+	}
+	require.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return len(l.flush) == 0 && l.count == 3
+	}, time.Second, time.Millisecond)
+
+	l.Record(EnrichmentEvent{TrackID: "dropped"})
+
+	sink.mu.Lock()
+	sink.err = nil
+	sink.mu.Unlock()
+	l.Flush(context.Background())
+	assert.Equal(t, 3, sink.rows(EnrichmentTable))
+}
+
+func TestEventLoader_RequeuesFailedBatches(t *testing.T) {
+	sink := &fakeSink{err: errors.New("unavailable")}
+	l := NewEventLoader(sink, LoaderConfig{FlushInterval: time.Hour, BatchSize: 5, BufferSize: 10})
+	defer l.Close(context.Background())
+
+	l.Record(ExportEvent{Format: "json"})
+	l.Flush(context.Background())
+	assert.Equal(t, 0, sink.rows(ExportTable))
+
+	sink.mu.Lock()
+	sink.err = nil
+	sink.mu.Unlock()
+	l.Flush(context.Background())
+	assert.Equal(t, 1, sink.rows(ExportTable))
+}
@@ -0,0 +1,41 @@
+package analytics
+
+import (
+	"reflect"
+	"time"
+)
+
+// column is a field of an event struct stored in its table
+type column struct {
+	name  string
+	index int
+	typ   reflect.Type
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// columnsOf lists the columns of an event from the bigquery tags of its
+// fields, which every sink uses as column names
+func columnsOf(event Event) []column {
+	t := reflect.TypeOf(event)
+	var columns []column
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("bigquery")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		columns = append(columns, column{name: name, index: i, typ: field.Type})
+	}
+	return columns
+}
+
+// rowOf returns the column values of an event by column name
+func rowOf(event Event) map[string]interface{} {
+	v := reflect.ValueOf(event)
+	row := make(map[string]interface{})
+	for _, col := range columnsOf(event) {
+		row[col.name] = v.Field(col.index).Interface()
+	}
+	return row
+}
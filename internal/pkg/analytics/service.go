@@ -0,0 +1,136 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"metadatatool/internal/pkg/config"
+)
+
+// closeTimeout bounds the final flush when a service is closed
+const closeTimeout = 30 * time.Second
+
+// Service records analytics events into the configured sink
+type Service interface {
+	EventRecorder
+	// Migrate creates or updates the tables the events are stored in
+	Migrate(ctx context.Context) error
+	// Close stores the buffered events and releases the sink
+	Close() error
+}
+
+// NewService creates the analytics service selected by cfg.Sink. db is the
+// application database and is only used by the postgres sink.
+func NewService(cfg *config.AnalyticsConfig, db *sql.DB) (Service, error) {
+	switch cfg.Sink {
+	case config.SinkBigQuery, "":
+		service, err := NewBigQueryService(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return service, nil
+	case config.SinkClickHouse:
+		return newBufferedService(newClickHouseStore(&cfg.ClickHouse, cfg.Retention), cfg), nil
+	case config.SinkPostgres:
+		if db == nil {
+			return nil, fmt.Errorf("the postgres analytics sink requires the database")
+		}
+		return newBufferedService(newPostgresStore(db), cfg), nil
+	case config.SinkStdout:
+		return newWriterService(os.Stdout), nil
+	case config.SinkNone:
+		return noopService{}, nil
+	default:
+		return nil, fmt.Errorf("unknown analytics sink %q", cfg.Sink)
+	}
+}
+
+// eventStore is a sink whose events are buffered by an EventLoader
+type eventStore interface {
+	eventSink
+	migrate(ctx context.Context) error
+	close() error
+}
+
+// bufferedService batches events into an eventStore
+type bufferedService struct {
+	store  eventStore
+	loader *EventLoader
+}
+
+func newBufferedService(store eventStore, cfg *config.AnalyticsConfig) *bufferedService {
+	return &bufferedService{
+		store: store,
+		loader: NewEventLoader(store, LoaderConfig{
+			FlushInterval: cfg.FlushInterval,
+			BatchSize:     cfg.BatchSize,
+			BufferSize:    cfg.BufferSize,
+		}),
+	}
+}
+
+// Record implements EventRecorder
+func (s *bufferedService) Record(event Event) {
+	s.loader.Record(event)
+}
+
+// Migrate implements Service
+func (s *bufferedService) Migrate(ctx context.Context) error {
+	return s.store.migrate(ctx)
+}
+
+// Close implements Service
+func (s *bufferedService) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	s.loader.Close(ctx)
+	return s.store.close()
+}
+
+// writerService writes every event as a JSON line, for development
+type writerService struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func newWriterService(w io.Writer) *writerService {
+	return &writerService{enc: json.NewEncoder(w)}
+}
+
+// Record implements EventRecorder
+func (s *writerService) Record(event Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	line := struct {
+		Table string                 `json:"table"`
+		Event map[string]interface{} `json:"event"`
+	}{event.Table(), rowOf(event)}
+	if err := s.enc.Encode(line); err != nil {
+		log.Printf("Failed to write analytics event: %v", err)
+	}
+}
+
+// Migrate implements Service
+func (s *writerService) Migrate(context.Context) error { return nil }
+
+// Close implements Service
+func (s *writerService) Close() error { return nil }
+
+// noopService discards every event
+type noopService struct{}
+
+// Record implements EventRecorder
+func (noopService) Record(Event) {}
+
+// Migrate implements Service
+func (noopService) Migrate(context.Context) error { return nil }
+
+// Close implements Service
+func (noopService) Close() error { return nil }
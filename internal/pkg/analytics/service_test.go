@@ -0,0 +1,46 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewService_SelectsSink(t *testing.T) {
+	service, err := NewService(&config.AnalyticsConfig{Sink: config.SinkNone}, nil)
+	require.NoError(t, err)
+	assert.IsType(t, noopService{}, service)
+
+	service, err = NewService(&config.AnalyticsConfig{Sink: config.SinkStdout}, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &writerService{}, service)
+
+	_, err = NewService(&config.AnalyticsConfig{Sink: config.SinkPostgres}, nil)
+	assert.Error(t, err)
+
+	_, err = NewService(&config.AnalyticsConfig{Sink: "kafka"}, nil)
+	assert.Error(t, err)
+}
+
+func TestWriterService_WritesJSONLines(t *testing.T) {
+	var out bytes.Buffer
+	service := newWriterService(&out)
+
+	service.Record(ExportEvent{Timestamp: time.Unix(0, 0).UTC(), Format: "ddex", TrackCount: 3, Success: true})
+
+	var line struct {
+		Table string                 `json:"table"`
+		Event map[string]interface{} `json:"event"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &line))
+	assert.Equal(t, ExportTable, line.Table)
+	assert.Equal(t, "ddex", line.Event["format"])
+	assert.Equal(t, float64(3), line.Event["track_count"])
+	assert.Equal(t, "1970-01-01T00:00:00Z", line.Event["timestamp"])
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"math"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
 )
 
 // MusicalKey represents a musical key
@@ -17,7 +22,7 @@ type MusicalKey struct {
 	Root       string // e.g., "C", "F#"
 	Mode       string // "major" or "minor"
 	Camelot    string // Camelot notation (e.g., "8A")
-	OpenKey    string // Open Key notation (e.g., "8d")
+	OpenKey    string // Open Key notation (e.g., "1m")
 	Confidence float64
 }
 
@@ -30,6 +35,47 @@ type AudioAnalysis struct {
 	Segments      []Segment  // Audio segments analysis
 	Energy        float64    // Overall energy level
 	Danceability  float64    // Danceability score
+	Sections      []Section  // Stretches of steady tempo and key, for audio analyzed in windows
+}
+
+// ToDomain returns the analysis in the form stored with tracks
+func (a *AudioAnalysis) ToDomain() *domain.AudioAnalysis {
+	var segments []domain.AudioSegment
+	for _, segment := range a.Segments {
+		segments = append(segments, domain.AudioSegment{
+			Start:      segment.Start,
+			Duration:   segment.Duration,
+			Loudness:   segment.Loudness,
+			Timbre:     segment.Timbre,
+			Pitches:    segment.Pitches,
+			Confidence: segment.Confidence,
+		})
+	}
+	var sections []domain.AudioSection
+	for _, section := range a.Sections {
+		sections = append(sections, domain.AudioSection{
+			Start:    section.Start,
+			Duration: section.Duration,
+			BPM:      section.BPM,
+			Key:      section.Key.Root,
+			Mode:     section.Key.Mode,
+			Camelot:  section.Key.Camelot,
+			Energy:   section.Energy,
+		})
+	}
+	return &domain.AudioAnalysis{
+		BPM:          a.BPM,
+		Tempo:        a.BPM,
+		Key:          a.Key.Root,
+		Mode:         a.Key.Mode,
+		Beats:        a.Beats,
+		Energy:       a.Energy,
+		Danceability: a.Danceability,
+		Segments:     segments,
+		Sections:     sections,
+		SectionCount: len(sections),
+		AnalyzedAt:   time.Now(),
+	}
 }
 
 // Segment represents an analyzed segment of audio
@@ -44,12 +90,16 @@ type Segment struct {
 
 // AudioAnalyzer handles advanced audio analysis
 type AudioAnalyzer struct {
-	ffmpeg   *FFmpegProcessor
-	essentia string // Path to Essentia extractors
-	aubio    string // Path to Aubio tools
+	ffmpeg   *FFmpegProcessor // nil when FFmpeg is not installed
+	essentia string           // Path to Essentia extractors
+	aubio    string           // Path to Aubio tools
+
+	segmentation SegmentedOptions
 }
 
-// NewAudioAnalyzer creates a new audio analyzer
+// NewAudioAnalyzer creates a new audio analyzer. ffmpeg may be nil when
+// FFmpeg is not installed; tracks are then analyzed natively, which reads
+// WAV files only.
 func NewAudioAnalyzer(ffmpeg *FFmpegProcessor) (*AudioAnalyzer, error) {
 	// Check for Essentia tools
 	essentia, err := exec.LookPath("essentia_streaming_extractor_music")
@@ -70,8 +120,46 @@ func NewAudioAnalyzer(ffmpeg *FFmpegProcessor) (*AudioAnalyzer, error) {
 	}, nil
 }
 
-// AnalyzeTrack performs comprehensive audio analysis
+// AnalyzeTrack performs comprehensive audio analysis. Without FFmpeg, or
+// when the tools fail on a file the native decoder reads, the tempo, beats
+// and energy are estimated in Go instead and the key is left blank. Audio
+// longer than the segmentation's LongAudio, such as DJ mixes, is analyzed
+// in windows by AnalyzeSegmented.
 func (a *AudioAnalyzer) AnalyzeTrack(ctx context.Context, inputPath string) (*AudioAnalysis, error) {
+	duration, err := a.duration(ctx, inputPath)
+	if err != nil {
+		return nil, err
+	}
+	if duration > a.segmentationOptions().LongAudio.Seconds() {
+		return a.AnalyzeSegmented(ctx, inputPath)
+	}
+	return a.analyzeFile(ctx, inputPath)
+}
+
+// analyzeFile analyzes a whole file at once
+func (a *AudioAnalyzer) analyzeFile(ctx context.Context, inputPath string) (*AudioAnalysis, error) {
+	if a.ffmpeg == nil {
+		if !CanDecodeNatively(inputPath) {
+			return nil, fmt.Errorf("%w: FFmpeg is not installed to read %s", ErrNotNativelyDecodable, filepath.Base(inputPath))
+		}
+		return AnalyzeNative(ctx, inputPath)
+	}
+
+	analysis, err := a.analyzeWithTools(ctx, inputPath)
+	if err == nil || !CanDecodeNatively(inputPath) || ctx.Err() != nil {
+		return analysis, err
+	}
+	native, nativeErr := AnalyzeNative(ctx, inputPath)
+	if nativeErr != nil {
+		return nil, fmt.Errorf("%w; native analysis failed too: %v", err, nativeErr)
+	}
+	log.Printf("audio tools failed on %s, analyzed natively: %v", filepath.Base(inputPath), err)
+	return native, nil
+}
+
+// analyzeWithTools analyzes a file with FFmpeg and, when installed, Aubio
+// and Essentia
+func (a *AudioAnalyzer) analyzeWithTools(ctx context.Context, inputPath string) (*AudioAnalysis, error) {
 	analysis := &AudioAnalysis{}
 
 	// Detect BPM using multiple methods for accuracy
@@ -292,15 +380,25 @@ func parseKeyFromChromagram(output string) MusicalKey {
 	matches := re.FindStringSubmatch(output)
 	if len(matches) > 3 {
 		confidence, _ := strconv.ParseFloat(matches[3], 64)
-		return MusicalKey{
+		return withKeyNotation(MusicalKey{
 			Root:       matches[1],
 			Mode:       matches[2],
 			Confidence: confidence,
-		}
+		})
 	}
 	return MusicalKey{}
 }
 
+// withKeyNotation returns the key with its Camelot and Open Key notations,
+// which stay blank when the root and mode are not a known key
+func withKeyNotation(key MusicalKey) MusicalKey {
+	if k, ok := domain.ParseKey(key.Root + " " + key.Mode); ok {
+		key.Camelot = k.String()
+		key.OpenKey = k.OpenKey()
+	}
+	return key
+}
+
 func parseBeatsFromOutput(output string) []float64 {
 	// Parse beat positions from output
 	var beats []float64
@@ -415,9 +513,9 @@ func parseEssentiaKey(jsonPath string) (MusicalKey, error) {
 		return MusicalKey{}, fmt.Errorf("failed to parse Essentia key JSON: %w", err)
 	}
 
-	return MusicalKey{
+	return withKeyNotation(MusicalKey{
 		Root:       result.Key.Key,
 		Mode:       result.Key.Scale,
 		Confidence: result.Key.Confidence,
-	}, nil
+	}), nil
 }
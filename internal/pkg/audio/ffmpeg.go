@@ -293,3 +293,50 @@ func (p *FFmpegProcessor) GenerateWaveform(ctx context.Context, inputPath string
 
 	return waveform, nil
 }
+
+// Duration returns the length of the audio in seconds
+func (p *FFmpegProcessor) Duration(ctx context.Context, inputPath string) (float64, error) {
+	args := []string{
+		"-v", "quiet",
+		"-print_format", "json",
+		"-show_format",
+		inputPath,
+	}
+	cmd := exec.CommandContext(ctx, p.ffprobePath, args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("duration probe failed: %w", err)
+	}
+
+	var probe FFprobeOutput
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return 0, fmt.Errorf("failed to parse audio info: %w", err)
+	}
+	duration, err := strconv.ParseFloat(probe.Format.Duration, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse duration %q: %w", probe.Format.Duration, err)
+	}
+	return duration, nil
+}
+
+// ExtractWindow writes duration seconds of the audio from start on to a
+// mono 16-bit WAV file
+func (p *FFmpegProcessor) ExtractWindow(ctx context.Context, inputPath, outputPath string, start, duration float64) error {
+	args := []string{
+		"-v", "error",
+		"-ss", strconv.FormatFloat(start, 'f', 3, 64),
+		"-t", strconv.FormatFloat(duration, 'f', 3, 64),
+		"-i", inputPath,
+		"-ac", "1",
+		"-c:a", "pcm_s16le",
+		"-y", // overwrite output file
+		outputPath,
+	}
+	cmd := exec.CommandContext(ctx, p.ffmpegPath, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("window extraction failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
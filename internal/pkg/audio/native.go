@@ -0,0 +1,260 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Native analysis settings. Onsets are measured per hop of about 11.6 ms,
+// 512 samples at 44.1 kHz, over frames of two hops.
+const (
+	nativeHopSeconds = 512.0 / 44100
+	nativeMinTempo   = 60.0
+	nativeMaxTempo   = 200.0
+	// nativeTempoPrior is the tempo most tracks sit near; the estimate
+	// prefers multiples of the beat period close to it
+	nativeTempoPrior = 120.0
+	// nativeTempoOctaves is the spread of the prior in octaves
+	nativeTempoOctaves = 1.0
+	// nativeMinBeats is the number of beats at the slowest tempo a track
+	// must span for its tempo to be estimated
+	nativeMinBeats = 4
+	// nativePeriodSlack is how far, as a share of the period, the beat grid
+	// fitted to the whole track may stray from the autocorrelation peak
+	nativePeriodSlack = 0.02
+)
+
+// nativeFormats lists the file extensions decoded without external tools
+var nativeFormats = []string{"wav"}
+
+// Capabilities reports how audio can be analyzed on this host
+type Capabilities struct {
+	FFmpeg   bool `json:"ffmpeg"`
+	FFprobe  bool `json:"ffprobe"`
+	Essentia bool `json:"essentia"`
+	Aubio    bool `json:"aubio"`
+	// NativeFormats are decoded and analyzed in Go when the tools are
+	// missing or fail
+	NativeFormats []string `json:"native_formats"`
+}
+
+// DetectCapabilities looks up the audio tools on the PATH
+func DetectCapabilities() Capabilities {
+	installed := func(name string) bool {
+		_, err := exec.LookPath(name)
+		return err == nil
+	}
+	return Capabilities{
+		FFmpeg:        installed("ffmpeg"),
+		FFprobe:       installed("ffprobe"),
+		Essentia:      installed("essentia_streaming_extractor_music"),
+		Aubio:         installed("aubio"),
+		NativeFormats: append([]string(nil), nativeFormats...),
+	}
+}
+
+// CanDecodeNatively reports whether a file is in a format decoded without
+// external tools, judging by its extension
+func CanDecodeNatively(path string) bool {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	for _, format := range nativeFormats {
+		if ext == format {
+			return true
+		}
+	}
+	return false
+}
+
+// AnalyzeNative estimates the tempo, beats, energy and danceability of a
+// WAV file in Go. The key is not detected.
+func AnalyzeNative(ctx context.Context, inputPath string) (*AudioAnalysis, error) {
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audio: %w", err)
+	}
+	defer file.Close()
+
+	decoder, err := newWAVDecoder(file)
+	if err != nil {
+		return nil, err
+	}
+	return analyzePCM(ctx, decoder)
+}
+
+// analyzePCM measures the onset strength of the samples hop by hop and
+// estimates the tempo and beats from it
+func analyzePCM(ctx context.Context, decoder *wavDecoder) (*AudioAnalysis, error) {
+	hop := int(math.Round(float64(decoder.sampleRate) * nativeHopSeconds))
+	if hop < 1 {
+		hop = 1
+	}
+	rate := float64(decoder.sampleRate) / float64(hop)
+
+	var (
+		onsets        []float64
+		sumSquares    float64
+		samples       int64
+		prevEnergy    float64
+		prevLogEnergy = math.Inf(-1)
+		buf           = make([]float64, hop)
+	)
+	for {
+		if len(onsets)%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		n, err := decoder.Read(buf)
+		if n == 0 {
+			break
+		}
+		var energy float64
+		for _, s := range buf[:n] {
+			energy += s * s
+		}
+		sumSquares += energy
+		samples += int64(n)
+
+		// Frames span two hops; the onset strength is the rise of their
+		// log energy, as a drum hit or a note start raises it
+		logEnergy := math.Log(prevEnergy + energy + 1e-10)
+		onset := 0.0
+		if !math.IsInf(prevLogEnergy, -1) && logEnergy > prevLogEnergy {
+			onset = logEnergy - prevLogEnergy
+		}
+		onsets = append(onsets, onset)
+		prevLogEnergy, prevEnergy = logEnergy, energy
+		if err != nil || n < hop {
+			break
+		}
+	}
+	if samples == 0 {
+		return nil, fmt.Errorf("audio has no samples")
+	}
+
+	period, confidence, err := estimateTempo(onsets, rate)
+	if err != nil {
+		return nil, err
+	}
+	period, phase := fitBeatGrid(onsets, period)
+	bpm := math.Round(rate*60/period*100) / 100
+	var beats []float64
+	for t := phase; int(math.Round(t)) < len(onsets); t += period {
+		beats = append(beats, math.Round(t/rate*1000)/1000)
+	}
+	energy := math.Min(1, math.Sqrt(sumSquares/float64(samples))*math.Sqrt2)
+	return &AudioAnalysis{
+		BPM:           bpm,
+		BPMConfidence: confidence,
+		Beats:         beats,
+		Energy:        energy,
+		Danceability:  calculateDanceability(energy, bpm),
+	}, nil
+}
+
+// estimateTempo finds the beat period by autocorrelation of the onset
+// strength, weighted toward tempos near the prior, and returns it in hops
+// with a confidence from 0 to 1
+func estimateTempo(onsets []float64, rate float64) (float64, float64, error) {
+	minLag := int(math.Floor(rate * 60 / nativeMaxTempo))
+	maxLag := int(math.Ceil(rate * 60 / nativeMinTempo))
+	if minLag < 1 {
+		minLag = 1
+	}
+	if len(onsets) < maxLag*nativeMinBeats {
+		return 0, 0, fmt.Errorf("audio is too short to estimate its tempo")
+	}
+
+	var mean float64
+	for _, o := range onsets {
+		mean += o
+	}
+	mean /= float64(len(onsets))
+	centered := make([]float64, len(onsets))
+	for i, o := range onsets {
+		centered[i] = o - mean
+	}
+
+	autocorrelation := func(lag int) float64 {
+		var sum float64
+		for i := 0; i+lag < len(centered); i++ {
+			sum += centered[i] * centered[i+lag]
+		}
+		return sum / float64(len(centered)-lag)
+	}
+	zero := autocorrelation(0)
+	if zero <= 0 {
+		return 0, 0, fmt.Errorf("audio has no onsets to estimate its tempo from")
+	}
+
+	ac := make([]float64, maxLag+2)
+	for lag := minLag - 1; lag <= maxLag+1 && lag < len(centered); lag++ {
+		if lag >= 0 {
+			ac[lag] = autocorrelation(lag)
+		}
+	}
+	best, bestScore := 0, math.Inf(-1)
+	for lag := minLag; lag <= maxLag; lag++ {
+		tempo := rate * 60 / float64(lag)
+		octaves := math.Log2(tempo/nativeTempoPrior) / nativeTempoOctaves
+		score := ac[lag] * math.Exp(-0.5*octaves*octaves)
+		if score > bestScore {
+			best, bestScore = lag, score
+		}
+	}
+	if ac[best] <= 0 {
+		return 0, 0, fmt.Errorf("audio has no steady beat")
+	}
+
+	// Fit a parabola through the peak and its neighbours for a period
+	// finer than a hop
+	period := float64(best)
+	if best > 0 {
+		left, center, right := ac[best-1], ac[best], ac[best+1]
+		if denom := left - 2*center + right; denom < 0 {
+			period += 0.5 * (left - right) / denom
+		}
+	}
+	confidence := math.Max(0, math.Min(1, ac[best]/zero))
+	return period, confidence, nil
+}
+
+// fitBeatGrid finds the period near period, and the phase, of the beat
+// grid whose beats fall on the strongest onsets on average across the
+// whole track, and returns both in hops. Over hundreds of beats a period a
+// fraction of a hop off drifts off the beat, so the grid pins the period
+// down finer than the autocorrelation can.
+func fitBeatGrid(onsets []float64, period float64) (float64, float64) {
+	// Neighbouring periods tried drift apart by half a hop over the track
+	steps := int(math.Ceil(2 * nativePeriodSlack * float64(len(onsets))))
+	if steps > 500 {
+		steps = 500
+	}
+	bestPeriod, bestPhase, bestScore := period, 0.0, math.Inf(-1)
+	for step := -steps; step <= steps; step++ {
+		p := period
+		if steps > 0 {
+			p *= 1 + nativePeriodSlack*float64(step)/float64(steps)
+		}
+		for phase := 0.0; phase < p; phase++ {
+			var sum float64
+			beats := 0
+			for t := phase; int(math.Round(t)) < len(onsets); t += p {
+				sum += onsets[int(math.Round(t))]
+				beats++
+			}
+			if beats == 0 {
+				continue
+			}
+			if score := sum / float64(beats); score > bestScore {
+				bestPeriod, bestPhase, bestScore = p, phase, score
+			}
+		}
+	}
+	return bestPeriod, bestPhase
+}
@@ -0,0 +1,146 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeWAV writes samples, interleaved by channel, as a WAV file of the
+// given encoding
+func encodeWAV(format uint16, sampleRate, channels, bits int, samples []float64) []byte {
+	var data bytes.Buffer
+	for _, s := range samples {
+		switch {
+		case format == wavFormatFloat:
+			binary.Write(&data, binary.LittleEndian, math.Float32bits(float32(s)))
+		case bits == 16:
+			binary.Write(&data, binary.LittleEndian, int16(s*32767))
+		case bits == 24:
+			v := int32(s * 8388607)
+			data.Write([]byte{byte(v), byte(v >> 8), byte(v >> 16)})
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(36+8+4+data.Len()))
+	b.WriteString("WAVE")
+	b.WriteString("fmt ")
+	binary.Write(&b, binary.LittleEndian, uint32(16))
+	binary.Write(&b, binary.LittleEndian, format)
+	binary.Write(&b, binary.LittleEndian, uint16(channels))
+	binary.Write(&b, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(&b, binary.LittleEndian, uint32(sampleRate*channels*bits/8))
+	binary.Write(&b, binary.LittleEndian, uint16(channels*bits/8))
+	binary.Write(&b, binary.LittleEndian, uint16(bits))
+	// Chunks the decoder does not know are skipped
+	b.WriteString("LIST")
+	binary.Write(&b, binary.LittleEndian, uint32(4))
+	b.WriteString("INFO")
+	b.WriteString("data")
+	binary.Write(&b, binary.LittleEndian, uint32(data.Len()))
+	b.Write(data.Bytes())
+	return b.Bytes()
+}
+
+// clickTrack returns seconds of stereo clicks at bpm over a quiet hum
+func clickTrack(sampleRate int, bpm, seconds float64) []float64 {
+	n := int(float64(sampleRate) * seconds)
+	period := float64(sampleRate) * 60 / bpm
+	samples := make([]float64, 0, 2*n)
+	for i := 0; i < n; i++ {
+		sinceBeat := math.Mod(float64(i), period) / float64(sampleRate)
+		s := 0.02 * math.Sin(2*math.Pi*110*float64(i)/float64(sampleRate))
+		if sinceBeat < 0.05 {
+			s += 0.8 * math.Exp(-sinceBeat*80) * math.Sin(2*math.Pi*1000*float64(i)/float64(sampleRate))
+		}
+		samples = append(samples, s, s)
+	}
+	return samples
+}
+
+func TestWAVDecoder(t *testing.T) {
+	samples := []float64{0.5, -0.5, 0.25, 0.25, -1, 1}
+	for _, tt := range []struct {
+		name   string
+		format uint16
+		bits   int
+	}{
+		{"16-bit", wavFormatPCM, 16},
+		{"24-bit", wavFormatPCM, 24},
+		{"float", wavFormatFloat, 32},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder, err := newWAVDecoder(bytes.NewReader(encodeWAV(tt.format, 8000, 2, tt.bits, samples)))
+			require.NoError(t, err)
+			assert.Equal(t, 8000, decoder.sampleRate)
+
+			buf := make([]float64, 8)
+			n, err := decoder.Read(buf)
+			require.NoError(t, err)
+			// Channels are mixed down to mono
+			require.Equal(t, 3, n)
+			assert.InDelta(t, 0, buf[0], 1e-4)
+			assert.InDelta(t, 0.25, buf[1], 1e-4)
+			assert.InDelta(t, 0, buf[2], 1e-4)
+
+			_, err = decoder.Read(buf)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := newWAVDecoder(bytes.NewReader([]byte("ID3\x04not a wav file")))
+	assert.ErrorIs(t, err, ErrNotNativelyDecodable)
+	_, err = newWAVDecoder(bytes.NewReader(encodeWAV(2, 8000, 1, 16, samples)))
+	assert.ErrorIs(t, err, ErrNotNativelyDecodable)
+}
+
+func TestAnalyzeNative(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+		return path
+	}
+
+	for _, bpm := range []float64{95, 120, 128, 174} {
+		path := write("clicks.wav", encodeWAV(wavFormatPCM, 22050, 2, 16, clickTrack(22050, bpm, 20)))
+		analysis, err := AnalyzeNative(context.Background(), path)
+		require.NoError(t, err)
+		assert.InDelta(t, bpm, analysis.BPM, bpm*0.02, "tempo of %v BPM clicks", bpm)
+		assert.Greater(t, analysis.BPMConfidence, 0.0)
+		assert.Greater(t, analysis.Energy, 0.0)
+		require.Greater(t, len(analysis.Beats), 10)
+		// Beats fall one period apart, on the clicks
+		assert.InDelta(t, 60/bpm, analysis.Beats[5]-analysis.Beats[4], 0.03)
+		offset := math.Remainder(analysis.Beats[len(analysis.Beats)-2], 60/bpm)
+		assert.InDelta(t, 0, offset, 0.03, "beats of %v BPM clicks", bpm)
+	}
+
+	short := write("short.wav", encodeWAV(wavFormatPCM, 22050, 1, 16, make([]float64, 22050)))
+	_, err := AnalyzeNative(context.Background(), short)
+	assert.Error(t, err)
+
+	// Without FFmpeg the analyzer decodes WAV natively and reports other
+	// formats instead of failing silently
+	analyzer, err := NewAudioAnalyzer(nil)
+	require.NoError(t, err)
+	path := write("clicks.wav", encodeWAV(wavFormatPCM, 22050, 2, 16, clickTrack(22050, 120, 20)))
+	analysis, err := analyzer.AnalyzeTrack(context.Background(), path)
+	require.NoError(t, err)
+	assert.InDelta(t, 120, analysis.BPM, 2)
+	stored := analysis.ToDomain()
+	assert.Equal(t, analysis.BPM, stored.BPM)
+	assert.Equal(t, analysis.Beats, stored.Beats)
+	assert.Equal(t, analysis.Danceability, stored.Danceability)
+	_, err = analyzer.AnalyzeTrack(context.Background(), write("song.mp3", []byte("ID3")))
+	assert.ErrorIs(t, err, ErrNotNativelyDecodable)
+}
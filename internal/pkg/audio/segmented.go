@@ -0,0 +1,305 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Segmented analysis defaults
+const (
+	// DefaultWindow is the length of the windows long audio is split into
+	DefaultWindow = time.Minute
+	// DefaultLongAudio is the length above which audio is analyzed in
+	// windows, as the tools time out on whole DJ mixes
+	DefaultLongAudio = 15 * time.Minute
+	// sectionTempoTolerance is how far, as a share, the tempos of
+	// neighbouring windows may differ for them to join one section
+	sectionTempoTolerance = 0.03
+)
+
+// SegmentedOptions configures the analysis of long audio in windows
+type SegmentedOptions struct {
+	// Window is the length of the windows; a last window shorter than half
+	// of it is joined to the one before
+	Window time.Duration
+	// Workers is the number of windows analyzed at once, the number of
+	// CPUs when zero
+	Workers int
+	// LongAudio is the length above which AnalyzeTrack analyzes audio in
+	// windows
+	LongAudio time.Duration
+}
+
+// Section is a stretch of audio with a steady tempo and key, such as one
+// track of a DJ mix
+type Section struct {
+	Start    float64    // Start time in seconds
+	Duration float64    // Duration in seconds
+	BPM      float64    // Beats per minute
+	Key      MusicalKey // Musical key, empty when not detected
+	Energy   float64    // Energy level
+}
+
+// audioWindow is a part of the audio analyzed on its own
+type audioWindow struct {
+	start    float64
+	duration float64
+	analysis *AudioAnalysis
+}
+
+// SetSegmentation sets how long audio is split into windows
+func (a *AudioAnalyzer) SetSegmentation(opts SegmentedOptions) {
+	a.segmentation = opts
+}
+
+// segmentationOptions returns the segmentation settings with the defaults
+// filled in
+func (a *AudioAnalyzer) segmentationOptions() SegmentedOptions {
+	opts := a.segmentation
+	if opts.Window <= 0 {
+		opts.Window = DefaultWindow
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.LongAudio <= 0 {
+		opts.LongAudio = DefaultLongAudio
+	}
+	return opts
+}
+
+// duration returns the length of the audio in seconds, read from the header
+// of WAV files and with FFprobe otherwise
+func (a *AudioAnalyzer) duration(ctx context.Context, inputPath string) (float64, error) {
+	if CanDecodeNatively(inputPath) {
+		file, err := os.Open(inputPath)
+		if err != nil {
+			return 0, fmt.Errorf("failed to open audio: %w", err)
+		}
+		defer file.Close()
+		decoder, err := newWAVDecoder(file)
+		if err == nil {
+			return decoder.duration(), nil
+		}
+	}
+	if a.ffmpeg == nil {
+		return 0, fmt.Errorf("%w: FFmpeg is not installed to read %s", ErrNotNativelyDecodable, filepath.Base(inputPath))
+	}
+	return a.ffmpeg.Duration(ctx, inputPath)
+}
+
+// AnalyzeSegmented splits the audio into windows, analyzes them in
+// parallel and merges them. Windows of a steady tempo and key are joined
+// into sections, so tempo and key changes show on a timeline; the overall
+// tempo and key are those covering most of the audio. Windows that cannot
+// be analyzed, such as silence, are left out of the sections.
+func (a *AudioAnalyzer) AnalyzeSegmented(ctx context.Context, inputPath string) (*AudioAnalysis, error) {
+	total, err := a.duration(ctx, inputPath)
+	if err != nil {
+		return nil, err
+	}
+	opts := a.segmentationOptions()
+	windows := splitWindows(total, opts.Window.Seconds())
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("audio has no samples")
+	}
+
+	var tempDir string
+	if a.ffmpeg != nil {
+		tempDir, err = os.MkdirTemp(a.ffmpeg.tempDir, "windows-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create window directory: %w", err)
+		}
+		defer os.RemoveAll(tempDir)
+	}
+
+	var wg sync.WaitGroup
+	work := make(chan int)
+	errs := make([]error, len(windows))
+	for w := 0; w < opts.Workers && w < len(windows); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				windows[i].analysis, errs[i] = a.analyzeWindow(ctx, inputPath, tempDir, i, windows[i])
+			}
+		}()
+	}
+	for i := range windows {
+		if ctx.Err() != nil {
+			break
+		}
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	analyzed := make([]audioWindow, 0, len(windows))
+	var firstErr error
+	for i, window := range windows {
+		if errs[i] != nil {
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			log.Printf("skipping window at %.0fs of %s: %v", window.start, filepath.Base(inputPath), errs[i])
+			continue
+		}
+		analyzed = append(analyzed, window)
+	}
+	if len(analyzed) == 0 {
+		return nil, fmt.Errorf("no window could be analyzed: %w", firstErr)
+	}
+	return mergeWindows(analyzed), nil
+}
+
+// analyzeWindow analyzes one window of the audio. WAV windows are decoded
+// natively in place when FFmpeg is missing; otherwise FFmpeg cuts the
+// window into a file of its own.
+func (a *AudioAnalyzer) analyzeWindow(ctx context.Context, inputPath, tempDir string, index int, window audioWindow) (*AudioAnalysis, error) {
+	if a.ffmpeg == nil {
+		return analyzeNativeWindow(ctx, inputPath, window.start, window.duration)
+	}
+	windowPath := filepath.Join(tempDir, fmt.Sprintf("window-%05d.wav", index))
+	if err := a.ffmpeg.ExtractWindow(ctx, inputPath, windowPath, window.start, window.duration); err != nil {
+		return nil, err
+	}
+	defer os.Remove(windowPath)
+	return a.analyzeFile(ctx, windowPath)
+}
+
+// analyzeNativeWindow analyzes duration seconds of a WAV file from start on
+func analyzeNativeWindow(ctx context.Context, inputPath string, start, duration float64) (*AudioAnalysis, error) {
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audio: %w", err)
+	}
+	defer file.Close()
+
+	decoder, err := newWAVDecoder(file)
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.window(file, start, duration); err != nil {
+		return nil, err
+	}
+	return analyzePCM(ctx, decoder)
+}
+
+// splitWindows splits total seconds of audio into windows of size seconds,
+// joining a last window shorter than half a window to the one before
+func splitWindows(total, size float64) []audioWindow {
+	var windows []audioWindow
+	for start := 0.0; start < total; start += size {
+		duration := math.Min(size, total-start)
+		if duration < size/2 && len(windows) > 0 {
+			windows[len(windows)-1].duration += duration
+			break
+		}
+		windows = append(windows, audioWindow{start: start, duration: duration})
+	}
+	return windows
+}
+
+// mergeWindows joins the analyses of the windows, in order of time, into
+// one analysis of the whole audio
+func mergeWindows(windows []audioWindow) *AudioAnalysis {
+	merged := &AudioAnalysis{}
+	var total, energy, danceability, confidence float64
+	for _, window := range windows {
+		a := window.analysis
+		for _, beat := range a.Beats {
+			merged.Beats = append(merged.Beats, math.Round((window.start+beat)*1000)/1000)
+		}
+		for _, segment := range a.Segments {
+			segment.Start += window.start
+			merged.Segments = append(merged.Segments, segment)
+		}
+		total += window.duration
+		energy += a.Energy * window.duration
+		danceability += a.Danceability * window.duration
+		confidence += a.BPMConfidence * window.duration
+
+		if n := len(merged.Sections); n > 0 && sameSection(merged.Sections[n-1], window) {
+			section := &merged.Sections[n-1]
+			length := section.Duration + window.duration
+			section.BPM = (section.BPM*section.Duration + a.BPM*window.duration) / length
+			section.Energy = (section.Energy*section.Duration + a.Energy*window.duration) / length
+			section.Duration = length
+			continue
+		}
+		merged.Sections = append(merged.Sections, Section{
+			Start:    window.start,
+			Duration: window.duration,
+			BPM:      a.BPM,
+			Key:      a.Key,
+			Energy:   a.Energy,
+		})
+	}
+	merged.Energy = energy / total
+	merged.Danceability = danceability / total
+	merged.BPMConfidence = confidence / total
+
+	// The tempo and key of the whole audio are those heard the longest
+	bpm := make(map[float64]float64)
+	keys := make(map[string]float64)
+	for i := range merged.Sections {
+		section := &merged.Sections[i]
+		section.BPM = math.Round(section.BPM*100) / 100
+		bpm[section.BPM] += section.Duration
+		if section.Key.Camelot != "" {
+			keys[section.Key.Camelot] += section.Duration
+		}
+	}
+	merged.BPM = longest(bpm)
+	if camelot := longest(keys); camelot != "" {
+		for _, section := range merged.Sections {
+			if section.Key.Camelot == camelot {
+				merged.Key = section.Key
+				break
+			}
+		}
+	}
+	return merged
+}
+
+// sameSection reports whether a window continues a section: it follows on
+// without a gap, its tempo is close and its key the same
+func sameSection(section Section, window audioWindow) bool {
+	a := window.analysis
+	if math.Abs(section.Start+section.Duration-window.start) > 0.001 {
+		return false
+	}
+	if section.BPM <= 0 || math.Abs(a.BPM-section.BPM)/section.BPM > sectionTempoTolerance {
+		return false
+	}
+	return a.Key.Camelot == section.Key.Camelot
+}
+
+// longest returns the value heard for the longest time, the smallest on
+// ties so the result does not depend on map order
+func longest[T float64 | string](durations map[T]float64) T {
+	values := make([]T, 0, len(durations))
+	for value := range durations {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	var best T
+	bestDuration := 0.0
+	for _, value := range values {
+		if durations[value] > bestDuration {
+			best, bestDuration = value, durations[value]
+		}
+	}
+	return best
+}
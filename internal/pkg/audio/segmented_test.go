@@ -0,0 +1,64 @@
+package audio
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeSegmented(t *testing.T) {
+	// A mix of 40 seconds at 120 BPM, 10 of silence and 40 at 128 BPM
+	var mix []float64
+	mix = append(mix, clickTrack(22050, 120, 40)...)
+	mix = append(mix, make([]float64, 2*22050*10)...)
+	mix = append(mix, clickTrack(22050, 128, 40)...)
+	path := filepath.Join(t.TempDir(), "mix.wav")
+	require.NoError(t, os.WriteFile(path, encodeWAV(wavFormatPCM, 22050, 2, 16, mix), 0o600))
+
+	analyzer, err := NewAudioAnalyzer(nil)
+	require.NoError(t, err)
+	analyzer.SetSegmentation(SegmentedOptions{Window: 10 * time.Second, Workers: 3, LongAudio: time.Minute})
+
+	// Longer than LongAudio, so analyzed in windows
+	analysis, err := analyzer.AnalyzeTrack(context.Background(), path)
+	require.NoError(t, err)
+	require.Len(t, analysis.Sections, 2, "sections %+v", analysis.Sections)
+	assert.Equal(t, 0.0, analysis.Sections[0].Start)
+	assert.Equal(t, 40.0, analysis.Sections[0].Duration)
+	assert.InDelta(t, 120, analysis.Sections[0].BPM, 1)
+	// The silent window is left out
+	assert.Equal(t, 50.0, analysis.Sections[1].Start)
+	assert.Equal(t, 40.0, analysis.Sections[1].Duration)
+	assert.InDelta(t, 128, analysis.Sections[1].BPM, 1)
+
+	// Beats are placed on the timeline of the whole mix, in order
+	require.NotEmpty(t, analysis.Beats)
+	assert.True(t, sort.Float64sAreSorted(analysis.Beats))
+	assert.Greater(t, analysis.Beats[len(analysis.Beats)-1], 85.0)
+	assert.Greater(t, analysis.Energy, 0.0)
+
+	stored := analysis.ToDomain()
+	require.Len(t, stored.Sections, 2)
+	assert.Equal(t, 2, stored.SectionCount)
+	assert.Equal(t, analysis.Sections[1].BPM, stored.Sections[1].BPM)
+}
+
+func TestSplitWindows(t *testing.T) {
+	windows := splitWindows(125, 60)
+	require.Len(t, windows, 2)
+	// The last 5 seconds join the window before
+	assert.Equal(t, audioWindow{start: 60, duration: 65}, windows[1])
+
+	windows = splitWindows(150, 60)
+	require.Len(t, windows, 3)
+	assert.Equal(t, audioWindow{start: 120, duration: 30}, windows[2])
+
+	assert.Len(t, splitWindows(20, 60), 1)
+	assert.Empty(t, splitWindows(0, 60))
+}
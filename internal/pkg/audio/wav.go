@@ -0,0 +1,191 @@
+package audio
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ErrNotNativelyDecodable is returned when audio is in a format the native
+// decoder does not read
+var ErrNotNativelyDecodable = errors.New("audio format cannot be decoded natively")
+
+// WAV sample encodings
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatExtensible = 0xFFFE
+)
+
+// wavDecoder reads the samples of a WAV file as mono, mixing the channels
+// down and scaling the samples to the range -1 to 1. It reads one frame at
+// a time, so files of any length decode in constant memory.
+type wavDecoder struct {
+	r              *bufio.Reader
+	format         uint16
+	channels       int
+	sampleRate     int
+	bitsPerSample  int
+	bytesPerSample int
+	// dataOffset is where the sample data starts in the file
+	dataOffset int64
+	// remaining counts the bytes of sample data left to read
+	remaining int64
+	frame     []byte
+}
+
+// newWAVDecoder reads the header of a RIFF WAVE stream up to its samples
+func newWAVDecoder(r io.Reader) (*wavDecoder, error) {
+	br := bufio.NewReaderSize(r, 64*1024)
+	var riff [12]byte
+	if _, err := io.ReadFull(br, riff[:]); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotNativelyDecodable, err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("%w: not a RIFF WAVE file", ErrNotNativelyDecodable)
+	}
+
+	d := &wavDecoder{r: br, dataOffset: int64(len(riff))}
+	haveFormat := false
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(br, header[:]); err != nil {
+			return nil, fmt.Errorf("%w: no data chunk", ErrNotNativelyDecodable)
+		}
+		id := string(header[0:4])
+		size := int64(binary.LittleEndian.Uint32(header[4:8]))
+		d.dataOffset += int64(len(header))
+		switch id {
+		case "fmt ":
+			if err := d.readFormat(br, size); err != nil {
+				return nil, err
+			}
+			d.dataOffset += size + size%2
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return nil, fmt.Errorf("%w: data chunk before fmt chunk", ErrNotNativelyDecodable)
+			}
+			d.remaining = size
+			d.frame = make([]byte, d.channels*d.bytesPerSample)
+			return d, nil
+		default:
+			// Chunks are padded to an even size
+			if _, err := br.Discard(int(size + size%2)); err != nil {
+				return nil, fmt.Errorf("%w: truncated %q chunk", ErrNotNativelyDecodable, id)
+			}
+			d.dataOffset += size + size%2
+		}
+	}
+}
+
+// readFormat reads the fmt chunk describing the samples
+func (d *wavDecoder) readFormat(r io.Reader, size int64) error {
+	if size < 16 {
+		return fmt.Errorf("%w: short fmt chunk", ErrNotNativelyDecodable)
+	}
+	chunk := make([]byte, size+size%2)
+	if _, err := io.ReadFull(r, chunk); err != nil {
+		return fmt.Errorf("%w: truncated fmt chunk", ErrNotNativelyDecodable)
+	}
+	d.format = binary.LittleEndian.Uint16(chunk[0:2])
+	d.channels = int(binary.LittleEndian.Uint16(chunk[2:4]))
+	d.sampleRate = int(binary.LittleEndian.Uint32(chunk[4:8]))
+	d.bitsPerSample = int(binary.LittleEndian.Uint16(chunk[14:16]))
+	if d.format == wavFormatExtensible && size >= 26 {
+		// The sub-format GUID starts with the actual encoding
+		d.format = binary.LittleEndian.Uint16(chunk[24:26])
+	}
+
+	switch {
+	case d.channels < 1 || d.sampleRate < 1:
+		return fmt.Errorf("%w: %d channels at %d Hz", ErrNotNativelyDecodable, d.channels, d.sampleRate)
+	case d.format == wavFormatPCM && (d.bitsPerSample == 8 || d.bitsPerSample == 16 || d.bitsPerSample == 24 || d.bitsPerSample == 32):
+	case d.format == wavFormatFloat && (d.bitsPerSample == 32 || d.bitsPerSample == 64):
+	default:
+		return fmt.Errorf("%w: WAV encoding %d with %d bits", ErrNotNativelyDecodable, d.format, d.bitsPerSample)
+	}
+	d.bytesPerSample = d.bitsPerSample / 8
+	return nil
+}
+
+// duration returns the length of the samples left to read in seconds
+func (d *wavDecoder) duration() float64 {
+	return float64(d.remaining/int64(len(d.frame))) / float64(d.sampleRate)
+}
+
+// window limits the decoder to the samples from start seconds on, for
+// duration seconds, seeking file to them. The decoder must not have read
+// any samples yet.
+func (d *wavDecoder) window(file io.ReadSeeker, start, duration float64) error {
+	frameSize := int64(len(d.frame))
+	skip := int64(start*float64(d.sampleRate)) * frameSize
+	if skip >= d.remaining {
+		return fmt.Errorf("window at %.1fs starts after the audio ends", start)
+	}
+	if _, err := file.Seek(d.dataOffset+skip, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek to %.1fs: %w", start, err)
+	}
+	d.r.Reset(file)
+	d.remaining -= skip
+	if length := int64(duration*float64(d.sampleRate)) * frameSize; length < d.remaining {
+		d.remaining = length
+	}
+	return nil
+}
+
+// Read fills buf with mono samples and returns how many it read. It
+// returns io.EOF once the samples are exhausted.
+func (d *wavDecoder) Read(buf []float64) (int, error) {
+	n := 0
+	for n < len(buf) {
+		if d.remaining < int64(len(d.frame)) {
+			if n == 0 {
+				return 0, io.EOF
+			}
+			return n, nil
+		}
+		if _, err := io.ReadFull(d.r, d.frame); err != nil {
+			// A file cut short ends where its samples end
+			d.remaining = 0
+			if n == 0 {
+				return 0, io.EOF
+			}
+			return n, nil
+		}
+		d.remaining -= int64(len(d.frame))
+
+		var sum float64
+		for c := 0; c < d.channels; c++ {
+			sum += d.sample(d.frame[c*d.bytesPerSample : (c+1)*d.bytesPerSample])
+		}
+		buf[n] = sum / float64(d.channels)
+		n++
+	}
+	return n, nil
+}
+
+// sample decodes one sample of one channel
+func (d *wavDecoder) sample(b []byte) float64 {
+	if d.format == wavFormatFloat {
+		if d.bitsPerSample == 64 {
+			return math.Float64frombits(binary.LittleEndian.Uint64(b))
+		}
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+	}
+	switch d.bitsPerSample {
+	case 8:
+		// 8-bit samples are unsigned
+		return (float64(b[0]) - 128) / 128
+	case 16:
+		return float64(int16(binary.LittleEndian.Uint16(b))) / 32768
+	case 24:
+		v := int32(b[0]) | int32(b[1])<<8 | int32(int8(b[2]))<<16
+		return float64(v) / 8388608
+	default:
+		return float64(int32(binary.LittleEndian.Uint32(b))) / 2147483648
+	}
+}
@@ -0,0 +1,99 @@
+// Package background runs work that outlives the request that started it,
+// such as AI enrichment after an upload, without letting it crash the API or
+// run forever.
+package background
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"metadatatool/internal/pkg/errortracking"
+	"metadatatool/internal/pkg/metrics"
+)
+
+// Task is work run in the background. The context it is given is cancelled
+// when the task times out.
+type Task func(ctx context.Context) error
+
+// Runner runs tasks in their own goroutines. Tasks are detached from the
+// context they are started with, so they keep running after the response
+// is sent, and are cancelled after the runner's timeout. Errors and panics
+// are logged and reported to the error tracker.
+type Runner struct {
+	errorTracker *errortracking.ErrorTracker
+	timeout      time.Duration
+	wg           sync.WaitGroup
+}
+
+// NewRunner creates a runner cancelling tasks after timeout, zero meaning
+// never. errorTracker may be nil.
+func NewRunner(errorTracker *errortracking.ErrorTracker, timeout time.Duration) *Runner {
+	return &Runner{
+		errorTracker: errorTracker,
+		timeout:      timeout,
+	}
+}
+
+// Go runs task in the background. Values of ctx, such as the request's
+// error tracking hub, stay available to the task; its cancellation does
+// not. tags describe the task in error reports.
+func (r *Runner) Go(ctx context.Context, name string, tags map[string]string, task Task) {
+	ctx = context.WithoutCancel(ctx)
+
+	r.wg.Add(1)
+	metrics.BackgroundTasksRunning.WithLabelValues(name).Inc()
+	go func() {
+		defer r.wg.Done()
+		defer metrics.BackgroundTasksRunning.WithLabelValues(name).Dec()
+
+		err := r.run(ctx, name, task)
+		if err == nil {
+			metrics.BackgroundTasks.WithLabelValues(name, "completed").Inc()
+			return
+		}
+		metrics.BackgroundTasks.WithLabelValues(name, "failed").Inc()
+		log.Printf("Background task %s failed: %v", name, err)
+
+		reported := map[string]string{"operation": name}
+		for key, value := range tags {
+			reported[key] = value
+		}
+		r.errorTracker.CaptureErrorContext(ctx, err, reported)
+	}()
+}
+
+// run calls task with the timeout applied, turning a panic into an error
+func (r *Runner) run(ctx context.Context, name string, task Task) (err error) {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("Panic in background task %s: %v\n%s", name, recovered, debug.Stack())
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return task(ctx)
+}
+
+// Wait blocks until every running task has finished or ctx is done, so
+// shutdown does not cut tasks short
+func (r *Runner) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,71 @@
+package background
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunner_DetachesTasksFromTheirRequest(t *testing.T) {
+	runner := NewRunner(nil, time.Second)
+	request, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	result := make(chan error, 1)
+
+	runner.Go(request, "test", nil, func(ctx context.Context) error {
+		close(started)
+		select {
+		case <-ctx.Done():
+			result <- ctx.Err()
+		case <-time.After(50 * time.Millisecond):
+			result <- nil
+		}
+		return nil
+	})
+	<-started
+	cancel()
+
+	require.NoError(t, runner.Wait(context.Background()))
+	assert.NoError(t, <-result)
+}
+
+func TestRunner_CancelsTasksAfterTimeout(t *testing.T) {
+	runner := NewRunner(nil, 10*time.Millisecond)
+	result := make(chan error, 1)
+
+	runner.Go(context.Background(), "test", nil, func(ctx context.Context) error {
+		<-ctx.Done()
+		result <- ctx.Err()
+		return ctx.Err()
+	})
+
+	require.NoError(t, runner.Wait(context.Background()))
+	assert.ErrorIs(t, <-result, context.DeadlineExceeded)
+}
+
+func TestRunner_RecoversPanics(t *testing.T) {
+	runner := NewRunner(nil, 0)
+
+	runner.Go(context.Background(), "test", map[string]string{"track_id": "t1"}, func(ctx context.Context) error {
+		panic("enrichment failed")
+	})
+
+	require.NoError(t, runner.Wait(context.Background()))
+}
+
+func TestRunner_WaitGivesUp(t *testing.T) {
+	runner := NewRunner(nil, 0)
+	release := make(chan struct{})
+	defer close(release)
+	runner.Go(context.Background(), "test", nil, func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, runner.Wait(ctx), context.DeadlineExceeded)
+}
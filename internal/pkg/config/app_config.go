@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -10,17 +12,30 @@ import (
 
 // AppConfig holds all application configuration settings
 type AppConfig struct {
-	Server   ServerConfig   `json:"server"`
-	Database DatabaseConfig `json:"database"`
-	Redis    RedisConfig    `json:"redis"`
-	Auth     AuthConfig     `json:"auth"`
-	AI       AIConfig       `json:"ai"`
-	Storage  StorageConfig  `json:"storage"`
-	Session  SessionConfig  `json:"session"`
-	Tracing  TracingConfig  `json:"tracing"`
-	Jobs     JobsConfig     `json:"jobs"`
-	Sentry   SentryConfig   `json:"sentry"`
-	Queue    QueueConfig    `json:"queue"`
+	Server      ServerConfig      `json:"server"`
+	Database    DatabaseConfig    `json:"database"`
+	Redis       RedisConfig       `json:"redis"`
+	Auth        AuthConfig        `json:"auth"`
+	AI          AIConfig          `json:"ai"`
+	Storage     StorageConfig     `json:"storage"`
+	Session     SessionConfig     `json:"session"`
+	Tracing     TracingConfig     `json:"tracing"`
+	Jobs        JobsConfig        `json:"jobs"`
+	Sentry      SentryConfig      `json:"sentry"`
+	Queue       QueueConfig       `json:"queue"`
+	Secrets     SecretsConfig     `json:"secrets"`
+	Analytics   AnalyticsConfig   `json:"analytics"`
+	Usage       UsageConfig       `json:"usage"`
+	KPI         KPIConfig         `json:"kpi"`
+	PublicAPI   PublicAPIConfig   `json:"public_api"`
+	Scanner     ScannerConfig     `json:"scanner"`
+	CORS        CORSConfig        `json:"cors"`
+	Security    SecurityConfig    `json:"security"`
+	API         APIConfig         `json:"api"`
+	Bootstrap   BootstrapConfig   `json:"bootstrap"`
+	Promotion   PromotionConfig   `json:"promotion"`
+	Maintenance MaintenanceConfig `json:"maintenance"`
+	Events      EventsConfig      `json:"events"`
 }
 
 // ServerConfig holds server-related settings
@@ -29,16 +44,175 @@ type ServerConfig struct {
 	Environment string `json:"environment"`
 	LogLevel    string `json:"log_level"`
 	Address     string `json:"address"`
+	// RateLimitPerMinute caps API requests per client until changed through
+	// the runtime config API; zero disables the limit
+	RateLimitPerMinute int `json:"rate_limit_per_minute"`
+	// StartupRetries is how many times the database, Redis and the queue are
+	// tried at startup before giving up on them
+	StartupRetries    int           `json:"startup_retries"`
+	StartupRetryDelay time.Duration `json:"startup_retry_delay"`
+	// DependencyCheckInterval is how often optional dependencies that are
+	// down are checked so their services can be re-enabled
+	DependencyCheckInterval time.Duration `json:"dependency_check_interval"`
+	// IdempotencyTTL is how long the response to a request with an
+	// Idempotency-Key header is replayed to repeats of that request
+	IdempotencyTTL time.Duration `json:"idempotency_ttl"`
+	// CompressionMinSize is the smallest response body that is compressed
+	// with brotli or gzip; zero disables compression
+	CompressionMinSize int `json:"compression_min_size"`
+	// CompressionTypes lists the media types of the responses that may be
+	// compressed; a type ending in "/" matches every subtype
+	CompressionTypes []string `json:"compression_types"`
+	// TrustedProxies lists the load balancers and CDNs in front of the API,
+	// as IP addresses or CIDR ranges. Only requests they forward may name
+	// the client's address and country in headers; empty trusts none.
+	TrustedProxies []string `json:"trusted_proxies"`
+	// BackgroundTaskTimeout cancels work started by a request that runs on
+	// after the response, such as AI enrichment of an upload; zero never
+	// cancels it
+	BackgroundTaskTimeout time.Duration `json:"background_task_timeout"`
 }
 
+// EnvironmentProduction is the server.environment of production deployments,
+// which get stricter defaults
+const EnvironmentProduction = "production"
+
+// IsProduction reports whether the server runs in production
+func (c *ServerConfig) IsProduction() bool {
+	return c.Environment == EnvironmentProduction
+}
+
+// CORSConfig holds the cross-origin settings for browsers calling the API
+type CORSConfig struct {
+	// AllowedOrigins lists the origins, such as "https://app.example.com",
+	// allowed to call the API. Outside production it defaults to the local
+	// web app; in production it is empty, allowing none, until set.
+	AllowedOrigins   []string `json:"allowed_origins"`
+	AllowedMethods   []string `json:"allowed_methods"`
+	AllowedHeaders   []string `json:"allowed_headers"`
+	ExposedHeaders   []string `json:"exposed_headers"`
+	AllowCredentials bool     `json:"allow_credentials"`
+	// MaxAge is how long browsers cache a preflight response
+	MaxAge time.Duration `json:"max_age"`
+}
+
+// SecurityConfig holds the security headers and CSRF settings
+type SecurityConfig struct {
+	// HSTSMaxAge is how long browsers only use HTTPS for the API; zero
+	// leaves the Strict-Transport-Security header out
+	HSTSMaxAge            time.Duration `json:"hsts_max_age"`
+	HSTSIncludeSubdomains bool          `json:"hsts_include_subdomains"`
+	// FrameOptions is sent as X-Frame-Options, DENY or SAMEORIGIN
+	FrameOptions string `json:"frame_options"`
+	// CSRF requires a CSRF token on state-changing requests authenticated
+	// by the session cookie
+	CSRF bool `json:"csrf"`
+}
+
+// APIConfig holds the API versioning settings
+type APIConfig struct {
+	// V1Deprecated is the date, such as 2026-01-31, /api/v1 was deprecated
+	// on, sent in the Deprecation header of its responses. Empty leaves
+	// version 1 undeprecated.
+	V1Deprecated string `json:"v1_deprecated"`
+	// V1Sunset is the date /api/v1 stops being served, sent in the Sunset
+	// header of its responses
+	V1Sunset string `json:"v1_sunset"`
+	// V1DeprecationLink points clients to the migration guide
+	V1DeprecationLink string `json:"v1_deprecation_link"`
+}
+
+// dateLayout is the layout of date settings
+const dateLayout = "2006-01-02"
+
+// V1Dates returns the deprecation and sunset dates of /api/v1, zero for the
+// dates that are not set or invalid
+func (c *APIConfig) V1Dates() (deprecated, sunset time.Time) {
+	deprecated, _ = time.Parse(dateLayout, c.V1Deprecated)
+	sunset, _ = time.Parse(dateLayout, c.V1Sunset)
+	return deprecated, sunset
+}
+
+// devOrigins are allowed by default outside production: the web app's dev
+// server and the port it used to run on
+var devOrigins = []string{"http://localhost:5173", "http://localhost:3000"}
+
 // DatabaseConfig holds database connection settings
 type DatabaseConfig struct {
+	// Driver selects the database backend, DriverPostgres or DriverSQLite
+	Driver string `json:"driver"`
+	// SQLitePath is the database file used by the SQLite driver
+	SQLitePath string `json:"sqlite_path"`
+
 	Host     string `json:"host"`
 	Port     int    `json:"port"`
 	User     string `json:"user"`
 	Password string `json:"password"`
 	DBName   string `json:"dbname"`
 	SSLMode  string `json:"sslmode"`
+
+	// Pool applies to the primary connection
+	Pool PoolConfig `json:"pool"`
+
+	// Replicas are read replicas as host or host:port; they share the
+	// primary's credentials and database name
+	Replicas    []string   `json:"replicas"`
+	ReplicaPool PoolConfig `json:"replica_pool"`
+	// ReplicaMaxLag is the replication lag above which a replica stops
+	// receiving reads
+	ReplicaMaxLag           time.Duration `json:"replica_max_lag"`
+	ReplicaLagCheckInterval time.Duration `json:"replica_lag_check_interval"`
+
+	// VectorSearch stores track embeddings with the pgvector extension and
+	// serves similar track search. Migration 000015 installs the extension
+	// and creates the embeddings table only when it is on.
+	VectorSearch bool `json:"vector_search"`
+}
+
+// Database drivers
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// PoolConfig holds connection pool settings for one database connection
+type PoolConfig struct {
+	MaxOpenConns    int           `json:"max_open_conns"`
+	MaxIdleConns    int           `json:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `json:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `json:"conn_max_idle_time"`
+}
+
+// DSN returns the connection string for the primary. For SQLite it is the
+// database file path.
+func (c *DatabaseConfig) DSN() string {
+	if c.Driver == DriverSQLite {
+		return c.SQLitePath
+	}
+	return c.dsn(c.Host, c.Port)
+}
+
+// ReplicaDSNs returns the connection strings for the read replicas
+func (c *DatabaseConfig) ReplicaDSNs() []string {
+	dsns := make([]string, 0, len(c.Replicas))
+	for _, replica := range c.Replicas {
+		host, port := replica, c.Port
+		if h, p, err := net.SplitHostPort(replica); err == nil {
+			host = h
+			if n, err := strconv.Atoi(p); err == nil {
+				port = n
+			}
+		}
+		dsns = append(dsns, c.dsn(host, port))
+	}
+	return dsns
+}
+
+func (c *DatabaseConfig) dsn(host string, port int) string {
+	return fmt.Sprintf(
+		"postgresql://%s:%s@%s:%d/%s?sslmode=%s",
+		c.User, c.Password, host, port, c.DBName, c.SSLMode,
+	)
 }
 
 // RedisConfig holds Redis connection settings
@@ -48,6 +222,12 @@ type RedisConfig struct {
 	Port     int    `json:"port"`
 	Password string `json:"password"`
 	DB       int    `json:"db"`
+
+	// TrackCacheTTL is how long tracks stay in the read-through cache
+	TrackCacheTTL time.Duration `json:"track_cache_ttl"`
+	// LabelStatsCacheTTL is how long label statistics are reused before
+	// they are computed again
+	LabelStatsCacheTTL time.Duration `json:"label_stats_cache_ttl"`
 }
 
 // GetAddress returns the formatted Redis address
@@ -68,6 +248,14 @@ type AuthConfig struct {
 	SessionTimeout      time.Duration `json:"session_timeout"`
 	EnableTwoFactor     bool          `json:"enable_two_factor"`
 	RequireStrongPasswd bool          `json:"require_strong_password"`
+
+	PasswordResetTTL         time.Duration `json:"password_reset_ttl"`
+	PasswordResetMaxRequests int           `json:"password_reset_max_requests"`
+	PasswordResetWindow      time.Duration `json:"password_reset_window"`
+	PasswordResetURL         string        `json:"password_reset_url"`
+	PasswordResetWebhookURL  string        `json:"password_reset_webhook_url"`
+
+	LoginAlertWebhookURL string `json:"login_alert_webhook_url"`
 }
 
 // AIConfig holds AI service settings
@@ -83,6 +271,65 @@ type AIConfig struct {
 	BaseURL       string           `json:"base_url"`
 	Timeout       time.Duration    `json:"timeout"`
 	Experiment    ExperimentConfig `json:"experiment"`
+
+	// OverwriteManualEdits lets enrichment replace fields a person has edited
+	OverwriteManualEdits bool `json:"overwrite_manual_edits"`
+
+	// CacheTTL is how long enrichment results are reused for identical audio
+	CacheTTL time.Duration `json:"cache_ttl"`
+
+	// ModelName and ModelVersion pin the OpenAI model; a version other than
+	// "latest" selects a dated snapshot. The Qwen2 settings name the
+	// deployed Qwen2 model. Each enrichment records its model version, so
+	// changing them marks the tracks enriched before as outdated.
+	Qwen2Model        string `json:"qwen2_model"`
+	Qwen2ModelVersion string `json:"qwen2_model_version"`
+
+	// MaxConcurrentRequests caps the requests in flight to each provider.
+	// The RequestsPerSecond settings cap each provider's rate; zero is
+	// unlimited.
+	MaxConcurrentRequests   int `json:"max_concurrent_requests"`
+	Qwen2RequestsPerSecond  int `json:"qwen2_requests_per_second"`
+	OpenAIRequestsPerSecond int `json:"openai_requests_per_second"`
+
+	// The AudioFeatures settings have each provider enrich from the
+	// measured audio analysis of a track as well, for tracks analyzed
+	Qwen2AudioFeatures  bool `json:"qwen2_audio_features"`
+	OpenAIAudioFeatures bool `json:"openai_audio_features"`
+
+	// MaxPromptTokens caps the estimated size of the prompts sent to the
+	// providers. Over it, custom fields are cut first, then the section
+	// timeline, then the audio features; zero is unlimited.
+	MaxPromptTokens int `json:"max_prompt_tokens"`
+
+	// HealthCheckInterval is how often each provider is probed; zero turns
+	// probes off and leaves every provider healthy. RoutingPolicy picks the
+	// provider tried first: "experiment" splits traffic by the experiment
+	// share, "latency" prefers the provider with the fastest probe.
+	HealthCheckInterval time.Duration `json:"health_check_interval"`
+	RoutingPolicy       string        `json:"routing_policy"`
+
+	// CredentialKey encrypts the API keys labels bring for their own AI
+	// configuration: a base64 encoded 32 byte key. Without it, labels
+	// cannot be given their own configuration.
+	CredentialKey string `json:"credential_key"`
+
+	// EvaluationInterval is how often the providers are scored against the
+	// golden dataset; zero turns scheduled evaluation off. The suggested
+	// confidence thresholds aim for EvaluationTargetAccuracy.
+	EvaluationInterval       time.Duration `json:"evaluation_interval"`
+	EvaluationTargetAccuracy float64       `json:"evaluation_target_accuracy"`
+
+	// BatchPollInterval is how often pending batch enrichments are checked
+	// with the provider; zero leaves their results unsaved, for instances
+	// that share the database with one that polls. A batch holds at most
+	// BatchMaxTracks tracks.
+	BatchPollInterval time.Duration `json:"batch_poll_interval"`
+	BatchMaxTracks    int           `json:"batch_max_tracks"`
+
+	// ProcessingLockTTL bounds how long a track stays locked for enrichment
+	// when its worker stops without releasing it
+	ProcessingLockTTL time.Duration `json:"processing_lock_ttl"`
 }
 
 // ExperimentConfig holds A/B testing configuration
@@ -128,6 +375,47 @@ type JobsConfig struct {
 	RetryMultiplier   float64       `json:"retry_multiplier"`
 	CleanupInterval   time.Duration `json:"cleanup_interval"`
 	MaxJobAge         time.Duration `json:"max_job_age"`
+	// TypeLimits caps the running jobs of a type, such as ddex_export=2.
+	// TypeWeights shares the workers between types in proportion to their
+	// weights, such as ai_enrich=3. Types not listed have no limit and
+	// weight 1.
+	TypeLimits  []string `json:"type_limits"`
+	TypeWeights []string `json:"type_weights"`
+	// ArchiveInterval is how often finished jobs are moved from Redis to the
+	// jobs_history table in PostgreSQL; zero disables archiving. A finished
+	// job stays in Redis for ArchiveAfter first, which must be shorter than
+	// DefaultTTL, when its data expires.
+	ArchiveInterval time.Duration `json:"archive_interval"`
+	ArchiveAfter    time.Duration `json:"archive_after"`
+}
+
+// Limits returns TypeLimits by job type, without the invalid entries
+func (c *JobsConfig) Limits() map[string]int {
+	limits, _ := parseTypeCounts(c.TypeLimits)
+	return limits
+}
+
+// Weights returns TypeWeights by job type, without the invalid entries
+func (c *JobsConfig) Weights() map[string]int {
+	weights, _ := parseTypeCounts(c.TypeWeights)
+	return weights
+}
+
+// parseTypeCounts parses entries such as ddex_export=2 into positive counts
+// by job type. It also returns the entries that are not of that form.
+func parseTypeCounts(entries []string) (map[string]int, []string) {
+	counts := make(map[string]int, len(entries))
+	var invalid []string
+	for _, entry := range entries {
+		jobType, value, ok := strings.Cut(entry, "=")
+		count, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || strings.TrimSpace(jobType) == "" || err != nil || count <= 0 {
+			invalid = append(invalid, entry)
+			continue
+		}
+		counts[strings.TrimSpace(jobType)] = count
+	}
+	return counts, invalid
 }
 
 // StorageConfig holds storage service settings
@@ -151,6 +439,41 @@ type StorageConfig struct {
 	UploadBufferSize int64         `json:"upload_buffer_size"`
 	DownloadTimeout  time.Duration `json:"download_timeout"`
 	UploadTimeout    time.Duration `json:"upload_timeout"`
+	// UploadURLExpiry is how long a presigned direct upload URL stays valid
+	UploadURLExpiry time.Duration `json:"upload_url_expiry"`
+	// StreamURLExpiry is how long the signed URLs audio streams are
+	// redirected to stay valid; zero streams audio through the API instead
+	StreamURLExpiry time.Duration `json:"stream_url_expiry"`
+	// Masters below LifecyclePrefix move to infrequent-access storage after
+	// InfrequentAccessAfterDays and to the archive after ArchiveAfterDays;
+	// zero days skips a transition
+	LifecyclePrefix           string `json:"lifecycle_prefix"`
+	InfrequentAccessAfterDays int    `json:"infrequent_access_after_days"`
+	ArchiveAfterDays          int    `json:"archive_after_days"`
+	// RestoreDays is how long a file restored from the archive stays
+	// readable. Restores in progress are checked every RestoreCheckInterval
+	// and reported to RestoreWebhookURL once done.
+	RestoreDays          int           `json:"restore_days"`
+	RestoreCheckInterval time.Duration `json:"restore_check_interval"`
+	RestoreWebhookURL    string        `json:"restore_webhook_url"`
+	// ReplicaBucket in ReplicaRegion receives a copy of every upload and
+	// serves reads while the primary region fails; empty disables it
+	ReplicaBucket string `json:"replica_bucket"`
+	ReplicaRegion string `json:"replica_region"`
+	// KMSKeyID is the KMS key S3 encrypts stored files with; empty leaves
+	// encryption to the bucket default. KMS keys are regional, so the
+	// replica uses ReplicaKMSKeyID.
+	KMSKeyID        string `json:"kms_key_id"`
+	ReplicaKMSKeyID string `json:"replica_kms_key_id"`
+	// QuotaWebhookURL is told when a user's usage reaches QuotaWarningPct
+	// percent of UserQuota
+	QuotaWebhookURL string `json:"quota_webhook_url"`
+	// UsageReconcileInterval is how often the counted bucket usage is
+	// corrected by listing the bucket; zero only lists it to start the count
+	UsageReconcileInterval time.Duration `json:"usage_reconcile_interval"`
+	// IntegrityAuditInterval is how often every track file is read back and
+	// checked against its checksums; zero disables the audit
+	IntegrityAuditInterval time.Duration `json:"integrity_audit_interval"`
 }
 
 // SentryConfig holds Sentry error tracking configuration
@@ -160,6 +483,9 @@ type SentryConfig struct {
 	Debug            bool    `json:"debug" env:"SENTRY_DEBUG" envDefault:"false"`
 	SampleRate       float64 `json:"sample_rate" env:"SENTRY_SAMPLE_RATE" envDefault:"1.0"`
 	TracesSampleRate float64 `json:"traces_sample_rate" env:"SENTRY_TRACES_SAMPLE_RATE" envDefault:"0.2"`
+	// Release tags events with the deployed version; empty uses the VCS
+	// revision the binary was built from
+	Release string `json:"release" env:"SENTRY_RELEASE"`
 }
 
 // QueueConfig holds queue service configuration
@@ -173,187 +499,683 @@ type QueueConfig struct {
 	MaxRetries         int           `json:"max_retries" env:"PUBSUB_MAX_RETRIES" envDefault:"3"`
 	AckDeadline        time.Duration `json:"ack_deadline" env:"PUBSUB_ACK_DEADLINE" envDefault:"30s"`
 	RetentionDuration  time.Duration `json:"retention_duration" env:"PUBSUB_RETENTION" envDefault:"168h"`
+	ChangeFeedTopic    string        `json:"change_feed_topic" env:"PUBSUB_CHANGE_FEED_TOPIC" envDefault:"track-changes"`
+	OutboxPollInterval time.Duration `json:"outbox_poll_interval" env:"OUTBOX_POLL_INTERVAL" envDefault:"1s"`
+	OutboxBatchSize    int           `json:"outbox_batch_size" env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+	// Topics whose lag reaches LagThreshold have new messages downgraded to
+	// low priority; at MaxLag publishing is refused. Zero disables either.
+	LagInterval        time.Duration `json:"lag_interval" env:"QUEUE_LAG_INTERVAL" envDefault:"30s"`
+	LagThreshold       int64         `json:"lag_threshold" env:"QUEUE_LAG_THRESHOLD" envDefault:"1000"`
+	MaxLag             int64         `json:"max_lag" env:"QUEUE_MAX_LAG" envDefault:"10000"`
+	LagAlertWebhookURL string        `json:"lag_alert_webhook_url" env:"QUEUE_LAG_ALERT_WEBHOOK_URL"`
+	// RedisBackend picks how the Redis queue keeps topics: "list" or
+	// "streams", which uses consumer groups and recovers the messages of
+	// consumers that crashed
+	RedisBackend string `json:"redis_backend" env:"QUEUE_REDIS_BACKEND" envDefault:"list"`
+	// MaxExtension is how long Pub/Sub consumers keep extending the ack
+	// deadline of a message whose handler is still running
+	MaxExtension time.Duration `json:"max_extension" env:"PUBSUB_MAX_EXTENSION" envDefault:"10m"`
+}
+
+// Redis queue backends
+const (
+	RedisQueueList    = "list"
+	RedisQueueStreams = "streams"
+)
+
+// SecretsConfig holds the settings used to resolve secretref:// values
+type SecretsConfig struct {
+	// RefreshInterval re-reads referenced secrets periodically; zero
+	// resolves them once at startup
+	RefreshInterval time.Duration `json:"refresh_interval"`
+	VaultAddress    string        `json:"vault_address"`
+	VaultToken      string        `json:"vault_token"`
+	AWSRegion       string        `json:"aws_region"`
+}
+
+// Analytics sinks
+const (
+	SinkBigQuery   = "bigquery"
+	SinkClickHouse = "clickhouse"
+	SinkPostgres   = "postgres"
+	SinkStdout     = "stdout"
+	SinkNone       = "none"
+)
+
+// AnalyticsConfig holds the analytics settings
+type AnalyticsConfig struct {
+	// Sink selects where events are stored
+	Sink string `json:"sink"`
+	// ProjectID and Dataset locate the BigQuery tables. ProjectID defaults
+	// to the Pub/Sub project.
+	ProjectID  string           `json:"project_id"`
+	Dataset    string           `json:"dataset"`
+	ClickHouse ClickHouseConfig `json:"clickhouse"`
+	// Events are buffered and inserted every FlushInterval, or sooner once
+	// BatchSize events are waiting. Events beyond BufferSize are dropped.
+	FlushInterval time.Duration `json:"flush_interval"`
+	BatchSize     int           `json:"batch_size"`
+	BufferSize    int           `json:"buffer_size"`
+	// Retention deletes daily partitions older than this; zero keeps them
+	Retention time.Duration `json:"retention"`
+}
+
+// UsageConfig holds the usage metering settings
+type UsageConfig struct {
+	// SnapshotInterval is how often the tracks and bytes each label stores
+	// are measured; zero disables the snapshots
+	SnapshotInterval time.Duration `json:"snapshot_interval"`
+}
+
+// KPIConfig holds the catalog KPI metrics settings
+type KPIConfig struct {
+	// Interval is how often the catalog KPIs are computed; zero disables them
+	Interval time.Duration `json:"interval"`
+}
+
+// PublicAPIConfig holds the quotas of the public catalog API tiers. Each
+// tier allows its keys a number of requests per minute and per UTC day;
+// zero leaves the window unlimited.
+type PublicAPIConfig struct {
+	FreePerMinute       int `json:"free_per_minute"`
+	FreePerDay          int `json:"free_per_day"`
+	PartnerPerMinute    int `json:"partner_per_minute"`
+	PartnerPerDay       int `json:"partner_per_day"`
+	EnterprisePerMinute int `json:"enterprise_per_minute"`
+	EnterprisePerDay    int `json:"enterprise_per_day"`
 }
 
-// Load loads configuration from environment variables
+// BootstrapConfig holds the settings of environment bootstrapping
+type BootstrapConfig struct {
+	// SetupToken authorizes POST /api/v1/bootstrap, which creates the
+	// first admin, default label, buckets and topics; the endpoint is not
+	// served when it is empty
+	SetupToken string `json:"setup_token"`
+}
+
+// PromotionConfig holds the settings of configuration promotion between
+// environments
+type PromotionConfig struct {
+	// SigningKey signs exported configuration bundles and verifies
+	// imported ones; environments promoting configuration share it. The
+	// export and import endpoints are not served when it is empty.
+	SigningKey string `json:"signing_key"`
+}
+
+// MaintenanceConfig holds the settings of the maintenance scheduler, which
+// runs periodic tasks such as session cleanup on one replica at a time
+type MaintenanceConfig struct {
+	// LeaseTTL is how long a replica leads without renewing its lead; when
+	// the leader stops, another replica takes over within it
+	LeaseTTL time.Duration `json:"lease_ttl"`
+	// HistorySize is how many runs of each task are kept
+	HistorySize int `json:"history_size"`
+	// DeadLetterExpiry is the schedule removing dead letters older than the
+	// queue's dead letter TTL, such as "@hourly" or "30 3 * * *"; empty
+	// disables it
+	DeadLetterExpiry string `json:"dead_letter_expiry"`
+}
+
+// EventsConfig holds the settings of the in-process domain events, such as
+// a track being created
+type EventsConfig struct {
+	// WebhookURL is posted every track event; empty disables the webhook
+	WebhookURL string `json:"webhook_url"`
+}
+
+// Malware scanners
+const (
+	ScannerClamAV = "clamav"
+	ScannerHTTP   = "http"
+	ScannerNone   = "none"
+)
+
+// ScannerConfig holds the malware scanning settings for uploads
+type ScannerConfig struct {
+	// Provider selects the scanner; with none, uploads are not scanned
+	Provider string `json:"provider"`
+	// Address is the host:port of clamd for the clamav provider
+	Address string `json:"address"`
+	// URL and APIKey locate the cloud scanning service of the http provider
+	URL     string        `json:"url"`
+	APIKey  string        `json:"api_key"`
+	Timeout time.Duration `json:"timeout"`
+}
+
+// ClickHouseConfig locates the ClickHouse analytics tables, which are
+// written through the HTTP interface
+type ClickHouseConfig struct {
+	URL      string `json:"url"`
+	Database string `json:"database"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+// Load loads configuration from environment variables on top of the
+// built-in defaults
 func Load() (*AppConfig, error) {
-	cfg := &AppConfig{
+	return LoadFile("")
+}
+
+// LoadFile loads configuration from a YAML or TOML file. Values are layered:
+// built-in defaults, then the file, then environment variables, so any
+// variable that is set overrides the file. An empty path skips the file.
+func LoadFile(path string) (*AppConfig, error) {
+	cfg := defaults()
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	cfg.applyEnv()
+	if cfg.Analytics.ProjectID == "" {
+		cfg.Analytics.ProjectID = cfg.Queue.ProjectID
+	}
+	if cfg.CORS.AllowedOrigins == nil && !cfg.Server.IsProduction() {
+		cfg.CORS.AllowedOrigins = append([]string(nil), devOrigins...)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// defaults returns the configuration used when neither a file nor the
+// environment sets a value
+func defaults() *AppConfig {
+	return &AppConfig{
 		Server: ServerConfig{
-			Port:        getEnvAsInt("SERVER_PORT", 8080),
-			Environment: getEnvOrDefault("ENVIRONMENT", "development"),
-			LogLevel:    getEnvOrDefault("LOG_LEVEL", "info"),
-			Address:     getEnvOrDefault("SERVER_ADDRESS", ""),
+			Port:                    8080,
+			Environment:             "development",
+			LogLevel:                "info",
+			Address:                 "",
+			StartupRetries:          5,
+			StartupRetryDelay:       time.Second,
+			DependencyCheckInterval: 10 * time.Second,
+			IdempotencyTTL:          24 * time.Hour,
+			CompressionMinSize:      1024,
+			CompressionTypes: []string{
+				"application/json", "application/xml", "application/x-ndjson",
+				"text/",
+			},
+
+			BackgroundTaskTimeout: 5 * time.Minute,
 		},
 		Database: DatabaseConfig{
-			Host:     getEnvOrDefault("DB_HOST", "localhost"),
-			Port:     getEnvAsInt("DB_PORT", 5432),
-			User:     getEnvOrDefault("DB_USER", "postgres"),
-			Password: getEnvOrDefault("DB_PASSWORD", ""),
-			DBName:   getEnvOrDefault("DB_NAME", "metadatatool"),
-			SSLMode:  getEnvOrDefault("DB_SSLMODE", "disable"),
+			Driver:     DriverPostgres,
+			SQLitePath: "metadatatool.db",
+			Host:       "localhost",
+			Port:       5432,
+			User:       "postgres",
+			Password:   "",
+			DBName:     "metadatatool",
+			SSLMode:    "disable",
+
+			Pool: PoolConfig{
+				MaxOpenConns:    25,
+				MaxIdleConns:    5,
+				ConnMaxLifetime: 30 * time.Minute,
+				ConnMaxIdleTime: 5 * time.Minute,
+			},
+			ReplicaPool: PoolConfig{
+				MaxOpenConns:    25,
+				MaxIdleConns:    5,
+				ConnMaxLifetime: 30 * time.Minute,
+				ConnMaxIdleTime: 5 * time.Minute,
+			},
+			ReplicaMaxLag:           5 * time.Second,
+			ReplicaLagCheckInterval: 10 * time.Second,
 		},
 		Redis: RedisConfig{
-			Enabled:  getEnvAsBool("REDIS_ENABLED", false),
-			Host:     getEnvOrDefault("REDIS_HOST", "localhost"),
-			Port:     getEnvAsInt("REDIS_PORT", 6379),
-			Password: getEnvOrDefault("REDIS_PASSWORD", ""),
-			DB:       getEnvAsInt("REDIS_DB", 0),
+			Enabled:  false,
+			Host:     "localhost",
+			Port:     6379,
+			Password: "",
+			DB:       0,
+
+			TrackCacheTTL:      time.Hour,
+			LabelStatsCacheTTL: 5 * time.Minute,
 		},
 		Auth: AuthConfig{
-			JWTSecret:           getEnvOrDefault("JWT_SECRET", "your-secret-key"),
-			AccessTokenTTL:      getEnvAsDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
-			RefreshTokenTTL:     getEnvAsDuration("REFRESH_TOKEN_TTL", 7*24*time.Hour),
-			APIKeyLength:        getEnvAsInt("API_KEY_LENGTH", 32),
-			PasswordMinLength:   getEnvAsInt("PASSWORD_MIN_LENGTH", 8),
-			PasswordHashCost:    getEnvAsInt("PASSWORD_HASH_COST", 10),
-			MaxLoginAttempts:    getEnvAsInt("MAX_LOGIN_ATTEMPTS", 5),
-			LockoutDuration:     getEnvAsDuration("LOCKOUT_DURATION", 15*time.Minute),
-			SessionTimeout:      getEnvAsDuration("SESSION_TIMEOUT", 24*time.Hour),
-			EnableTwoFactor:     getEnvAsBool("ENABLE_TWO_FACTOR", false),
-			RequireStrongPasswd: getEnvAsBool("REQUIRE_STRONG_PASSWORD", true),
+			JWTSecret:           "your-secret-key",
+			AccessTokenTTL:      15 * time.Minute,
+			RefreshTokenTTL:     7 * 24 * time.Hour,
+			APIKeyLength:        32,
+			PasswordMinLength:   8,
+			PasswordHashCost:    10,
+			MaxLoginAttempts:    5,
+			LockoutDuration:     15 * time.Minute,
+			SessionTimeout:      24 * time.Hour,
+			EnableTwoFactor:     false,
+			RequireStrongPasswd: true,
+
+			PasswordResetTTL:         30 * time.Minute,
+			PasswordResetMaxRequests: 5,
+			PasswordResetWindow:      time.Hour,
+			PasswordResetURL:         "",
+			PasswordResetWebhookURL:  "",
+
+			LoginAlertWebhookURL: "",
 		},
 		AI: AIConfig{
-			Provider:      getEnvOrDefault("AI_PROVIDER", "openai"),
-			ModelName:     getEnvOrDefault("AI_MODEL_NAME", "gpt-4"),
-			ModelVersion:  getEnvOrDefault("AI_MODEL_VERSION", "latest"),
-			Temperature:   getEnvAsFloat("AI_TEMPERATURE", 0.7),
-			MaxTokens:     getEnvAsInt("AI_MAX_TOKENS", 2048),
-			BatchSize:     getEnvAsInt("AI_BATCH_SIZE", 10),
-			MinConfidence: getEnvAsFloat("AI_MIN_CONFIDENCE", 0.85),
-			APIKey:        getEnvOrDefault("AI_API_KEY", ""),
-			BaseURL:       getEnvOrDefault("AI_BASE_URL", "https://api.openai.com/v1"),
-			Timeout:       getEnvAsDuration("AI_TIMEOUT", 30*time.Second),
+			Provider:      "openai",
+			ModelName:     "gpt-4o-mini",
+			ModelVersion:  "latest",
+			Temperature:   0.7,
+			MaxTokens:     2048,
+			BatchSize:     10,
+			MinConfidence: 0.85,
+			APIKey:        "",
+			BaseURL:       "https://api.openai.com/v1",
+			Timeout:       30 * time.Second,
+			CacheTTL:      30 * 24 * time.Hour,
+			// Bump the Qwen2 version when the deployed model changes
+			Qwen2Model:        "qwen2",
+			Qwen2ModelVersion: "v1",
+			// Qwen2 requests are only limited by concurrency by default
+			MaxConcurrentRequests:   10,
+			OpenAIRequestsPerSecond: 10,
+			// Leaves room for the answer within a 4k context
+			MaxPromptTokens: 3000,
+			// Probes are a model lookup and a ping, so they cost no tokens
+			HealthCheckInterval: 30 * time.Second,
+			RoutingPolicy:       "experiment",
+			// Evaluation only calls the providers for golden tracks
+			EvaluationInterval:       24 * time.Hour,
+			EvaluationTargetAccuracy: 0.9,
+			// OpenAI takes batches of up to 50,000 requests
+			BatchPollInterval: 5 * time.Minute,
+			BatchMaxTracks:    50000,
+			// Long enough to save the results of the largest batch
+			ProcessingLockTTL: 30 * time.Minute,
 			Experiment: ExperimentConfig{
-				TrafficPercent: getEnvAsFloat("AI_EXPERIMENT_TRAFFIC_PERCENT", 0.1),
-				MinConfidence:  getEnvAsFloat("AI_MIN_CONFIDENCE_THRESHOLD", 0.8),
-				EnableFallback: getEnvAsBool("AI_ENABLE_AUTO_FALLBACK", true),
+				TrafficPercent: 0.1,
+				MinConfidence:  0.8,
+				EnableFallback: true,
 			},
+			OverwriteManualEdits: false,
 		},
 		Session: SessionConfig{
-			CookieName:         getEnvOrDefault("SESSION_COOKIE_NAME", "session"),
-			CookieDomain:       getEnvOrDefault("SESSION_COOKIE_DOMAIN", ""),
-			CookiePath:         getEnvOrDefault("SESSION_COOKIE_PATH", "/"),
-			CookieSecure:       getEnvAsBool("SESSION_COOKIE_SECURE", true),
-			CookieHTTPOnly:     getEnvAsBool("SESSION_COOKIE_HTTP_ONLY", true),
-			CookieSameSite:     getEnvOrDefault("SESSION_COOKIE_SAME_SITE", "lax"),
-			SessionDuration:    getEnvAsDuration("SESSION_DURATION", 24*time.Hour),
-			CleanupInterval:    getEnvAsDuration("SESSION_CLEANUP_INTERVAL", time.Hour),
-			MaxSessionsPerUser: getEnvAsInt("SESSION_MAX_PER_USER", 5),
+			CookieName:         "session",
+			CookieDomain:       "",
+			CookiePath:         "/",
+			CookieSecure:       true,
+			CookieHTTPOnly:     true,
+			CookieSameSite:     "lax",
+			SessionDuration:    24 * time.Hour,
+			CleanupInterval:    time.Hour,
+			MaxSessionsPerUser: 5,
 		},
 		Jobs: JobsConfig{
-			NumWorkers:        getEnvAsInt("JOB_NUM_WORKERS", 5),
-			MaxConcurrent:     getEnvAsInt("JOB_MAX_CONCURRENT", 10),
-			PollInterval:      getEnvAsDuration("JOB_POLL_INTERVAL", time.Second),
-			ShutdownWait:      getEnvAsDuration("JOB_SHUTDOWN_WAIT", 30*time.Second),
-			DefaultMaxRetries: getEnvAsInt("JOB_DEFAULT_MAX_RETRIES", 3),
-			DefaultTTL:        getEnvAsDuration("JOB_DEFAULT_TTL", 24*time.Hour),
-			MaxPayloadSize:    getEnvAsInt64("JOB_MAX_PAYLOAD_SIZE", 1024*1024),
-			QueuePrefix:       getEnvOrDefault("JOB_QUEUE_PREFIX", "jobs:"),
-			RetryDelay:        getEnvAsDuration("JOB_RETRY_DELAY", 5*time.Second),
-			MaxRetryDelay:     getEnvAsDuration("JOB_MAX_RETRY_DELAY", time.Hour),
-			RetryMultiplier:   getEnvAsFloat("JOB_RETRY_MULTIPLIER", 2.0),
-			CleanupInterval:   getEnvAsDuration("JOB_CLEANUP_INTERVAL", time.Hour),
-			MaxJobAge:         getEnvAsDuration("JOB_MAX_AGE", 7*24*time.Hour),
+			NumWorkers:        5,
+			MaxConcurrent:     10,
+			PollInterval:      time.Second,
+			ShutdownWait:      30 * time.Second,
+			DefaultMaxRetries: 3,
+			DefaultTTL:        24 * time.Hour,
+			MaxPayloadSize:    1024 * 1024,
+			QueuePrefix:       "jobs:",
+			RetryDelay:        5 * time.Second,
+			MaxRetryDelay:     time.Hour,
+			RetryMultiplier:   2.0,
+			CleanupInterval:   time.Hour,
+			MaxJobAge:         7 * 24 * time.Hour,
+			ArchiveInterval:   10 * time.Minute,
+			ArchiveAfter:      time.Hour,
 		},
 		Storage: StorageConfig{
-			Provider:         getEnvOrDefault("STORAGE_PROVIDER", "s3"),
-			Region:           getEnvOrDefault("STORAGE_REGION", "us-east-1"),
-			Bucket:           getEnvOrDefault("STORAGE_BUCKET", "metadatatool"),
-			AccessKey:        getEnvOrDefault("STORAGE_ACCESS_KEY", ""),
-			SecretKey:        getEnvOrDefault("STORAGE_SECRET_KEY", ""),
-			Endpoint:         getEnvOrDefault("STORAGE_ENDPOINT", ""),
-			UseSSL:           getEnvAsBool("STORAGE_USE_SSL", true),
-			UploadPartSize:   getEnvAsInt64("STORAGE_UPLOAD_PART_SIZE", 5*1024*1024),
-			MaxUploadRetries: getEnvAsInt("STORAGE_MAX_UPLOAD_RETRIES", 3),
-			MaxFileSize:      getEnvAsInt64("STORAGE_MAX_FILE_SIZE", 100*1024*1024),
-			AllowedFileTypes: strings.Split(getEnvOrDefault("STORAGE_ALLOWED_FILE_TYPES", ".mp3,.wav,.flac"), ","),
-			UserQuota:        getEnvAsInt64("STORAGE_USER_QUOTA", 1024*1024*1024),
-			TotalQuota:       getEnvAsInt64("STORAGE_TOTAL_QUOTA", 1024*1024*1024*1024),
-			QuotaWarningPct:  getEnvAsInt("STORAGE_QUOTA_WARNING_PCT", 90),
-			TempFileExpiry:   getEnvAsDuration("STORAGE_TEMP_FILE_EXPIRY", 24*time.Hour),
-			CleanupInterval:  getEnvAsDuration("STORAGE_CLEANUP_INTERVAL", time.Hour),
-			UploadBufferSize: getEnvAsInt64("STORAGE_UPLOAD_BUFFER_SIZE", 5*1024*1024),
-			DownloadTimeout:  getEnvAsDuration("STORAGE_DOWNLOAD_TIMEOUT", 5*time.Minute),
-			UploadTimeout:    getEnvAsDuration("STORAGE_UPLOAD_TIMEOUT", 10*time.Minute),
+			Provider:         "s3",
+			Region:           "us-east-1",
+			Bucket:           "metadatatool",
+			AccessKey:        "",
+			SecretKey:        "",
+			Endpoint:         "",
+			UseSSL:           true,
+			UploadPartSize:   5 * 1024 * 1024,
+			MaxUploadRetries: 3,
+			MaxFileSize:      100 * 1024 * 1024,
+			AllowedFileTypes: []string{".mp3", ".wav", ".flac"},
+			UserQuota:        1024 * 1024 * 1024,
+			TotalQuota:       1024 * 1024 * 1024 * 1024,
+			QuotaWarningPct:  90,
+			TempFileExpiry:   24 * time.Hour,
+			CleanupInterval:  time.Hour,
+			UploadBufferSize: 5 * 1024 * 1024,
+			DownloadTimeout:  5 * time.Minute,
+			UploadTimeout:    10 * time.Minute,
+			UploadURLExpiry:  15 * time.Minute,
+			StreamURLExpiry:  5 * time.Minute,
+
+			LifecyclePrefix:      "tracks/",
+			RestoreDays:          7,
+			RestoreCheckInterval: 5 * time.Minute,
+
+			UsageReconcileInterval: 24 * time.Hour,
 		},
 		Tracing: TracingConfig{
-			Enabled:     getEnvAsBool("TRACING_ENABLED", true),
-			ServiceName: getEnvOrDefault("TRACING_SERVICE_NAME", "metadatatool"),
-			Endpoint:    getEnvOrDefault("TRACING_ENDPOINT", "localhost:4317"),
-			SampleRate:  getEnvAsFloat("TRACING_SAMPLE_RATE", 0.1),
+			Enabled:     true,
+			ServiceName: "metadatatool",
+			Endpoint:    "localhost:4317",
+			SampleRate:  0.1,
 		},
 		Sentry: SentryConfig{
-			DSN:              getEnvOrDefault("SENTRY_DSN", ""),
-			Environment:      getEnvOrDefault("SENTRY_ENVIRONMENT", "development"),
-			Debug:            getEnvAsBool("SENTRY_DEBUG", false),
-			SampleRate:       getEnvAsFloat("SENTRY_SAMPLE_RATE", 1.0),
-			TracesSampleRate: getEnvAsFloat("SENTRY_TRACES_SAMPLE_RATE", 0.2),
+			DSN:              "",
+			Environment:      "development",
+			Debug:            false,
+			SampleRate:       1.0,
+			TracesSampleRate: 0.2,
 		},
 		Queue: QueueConfig{
-			Disabled:           getEnvAsBool("DISABLE_QUEUE", false),
-			ProjectID:          getEnvOrDefault("PUBSUB_PROJECT_ID", ""),
-			HighPriorityTopic:  getEnvOrDefault("PUBSUB_HIGH_PRIORITY_TOPIC", "high-priority"),
-			LowPriorityTopic:   getEnvOrDefault("PUBSUB_LOW_PRIORITY_TOPIC", "low-priority"),
-			DeadLetterTopic:    getEnvOrDefault("PUBSUB_DEAD_LETTER_TOPIC", "dead-letter"),
-			SubscriptionPrefix: getEnvOrDefault("PUBSUB_SUBSCRIPTION_PREFIX", "sub"),
-			MaxRetries:         getEnvAsInt("PUBSUB_MAX_RETRIES", 3),
-			AckDeadline:        getEnvAsDuration("PUBSUB_ACK_DEADLINE", 30*time.Second),
-			RetentionDuration:  getEnvAsDuration("PUBSUB_RETENTION", 168*time.Hour),
+			Disabled:           false,
+			ProjectID:          "",
+			HighPriorityTopic:  "high-priority",
+			LowPriorityTopic:   "low-priority",
+			DeadLetterTopic:    "dead-letter",
+			SubscriptionPrefix: "sub",
+			MaxRetries:         3,
+			AckDeadline:        30 * time.Second,
+			RetentionDuration:  168 * time.Hour,
+			ChangeFeedTopic:    "track-changes",
+			OutboxPollInterval: time.Second,
+			OutboxBatchSize:    100,
+			LagInterval:        30 * time.Second,
+			LagThreshold:       1000,
+			MaxLag:             10000,
+			RedisBackend:       RedisQueueList,
+			MaxExtension:       10 * time.Minute,
+		},
+		Secrets: SecretsConfig{
+			AWSRegion: "us-east-1",
+		},
+		CORS: CORSConfig{
+			AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+			AllowedHeaders: []string{
+				"Authorization", "Content-Type", "Idempotency-Key",
+				"If-Match", "If-None-Match", "X-CSRF-Token", "X-Request-ID",
+			},
+			ExposedHeaders:   []string{"ETag", "Last-Modified", "X-CSRF-Token", "X-Request-ID"},
+			AllowCredentials: true,
+			MaxAge:           10 * time.Minute,
+		},
+		Security: SecurityConfig{
+			HSTSMaxAge:   365 * 24 * time.Hour,
+			FrameOptions: "DENY",
+			CSRF:         true,
+		},
+		Analytics: AnalyticsConfig{
+			Sink:    SinkBigQuery,
+			Dataset: "metadatatool_analytics",
+			ClickHouse: ClickHouseConfig{
+				URL:      "http://localhost:8123",
+				Database: "metadatatool_analytics",
+				User:     "default",
+			},
+			FlushInterval: 10 * time.Second,
+			BatchSize:     500,
+			BufferSize:    10000,
+		},
+		Usage: UsageConfig{
+			SnapshotInterval: time.Hour,
+		},
+		KPI: KPIConfig{
+			Interval: time.Minute,
+		},
+		PublicAPI: PublicAPIConfig{
+			FreePerMinute:       60,
+			FreePerDay:          1000,
+			PartnerPerMinute:    600,
+			PartnerPerDay:       100000,
+			EnterprisePerMinute: 3000,
+		},
+		Scanner: ScannerConfig{
+			Provider: ScannerNone,
+			Address:  "localhost:3310",
+			Timeout:  time.Minute,
+		},
+		Maintenance: MaintenanceConfig{
+			LeaseTTL:         30 * time.Second,
+			HistorySize:      50,
+			DeadLetterExpiry: "@hourly",
 		},
 	}
-
-	return cfg, nil
-}
-
-// Helper functions to get environment variables with defaults
-func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
-func getEnvAsInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
-	}
-	return defaultValue
-}
-
-func getEnvAsInt64(key string, defaultValue int64) int64 {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
-			return intValue
-		}
-	}
-	return defaultValue
-}
-
-func getEnvAsFloat(key string, defaultValue float64) float64 {
-	if value := os.Getenv(key); value != "" {
-		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
-			return floatValue
-		}
-	}
-	return defaultValue
 }
 
-func getEnvAsBool(key string, defaultValue bool) bool {
-	if value := os.Getenv(key); value != "" {
-		if boolValue, err := strconv.ParseBool(value); err == nil {
-			return boolValue
-		}
+// envBindings maps each environment variable to the setting it overrides
+func (c *AppConfig) envBindings() map[string]interface{} {
+	return map[string]interface{}{
+		"SERVER_PORT":                      &c.Server.Port,
+		"ENVIRONMENT":                      &c.Server.Environment,
+		"LOG_LEVEL":                        &c.Server.LogLevel,
+		"SERVER_ADDRESS":                   &c.Server.Address,
+		"RATE_LIMIT_PER_MINUTE":            &c.Server.RateLimitPerMinute,
+		"STARTUP_RETRIES":                  &c.Server.StartupRetries,
+		"STARTUP_RETRY_DELAY":              &c.Server.StartupRetryDelay,
+		"DEPENDENCY_CHECK_INTERVAL":        &c.Server.DependencyCheckInterval,
+		"IDEMPOTENCY_TTL":                  &c.Server.IdempotencyTTL,
+		"COMPRESSION_MIN_SIZE":             &c.Server.CompressionMinSize,
+		"COMPRESSION_TYPES":                &c.Server.CompressionTypes,
+		"TRUSTED_PROXIES":                  &c.Server.TrustedProxies,
+		"BACKGROUND_TASK_TIMEOUT":          &c.Server.BackgroundTaskTimeout,
+		"DB_DRIVER":                        &c.Database.Driver,
+		"DB_SQLITE_PATH":                   &c.Database.SQLitePath,
+		"DB_VECTOR_SEARCH":                 &c.Database.VectorSearch,
+		"DB_HOST":                          &c.Database.Host,
+		"DB_PORT":                          &c.Database.Port,
+		"DB_USER":                          &c.Database.User,
+		"DB_PASSWORD":                      &c.Database.Password,
+		"DB_NAME":                          &c.Database.DBName,
+		"DB_SSLMODE":                       &c.Database.SSLMode,
+		"DB_MAX_OPEN_CONNS":                &c.Database.Pool.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS":                &c.Database.Pool.MaxIdleConns,
+		"DB_CONN_MAX_LIFETIME":             &c.Database.Pool.ConnMaxLifetime,
+		"DB_CONN_MAX_IDLE_TIME":            &c.Database.Pool.ConnMaxIdleTime,
+		"DB_REPLICAS":                      &c.Database.Replicas,
+		"DB_REPLICA_MAX_OPEN_CONNS":        &c.Database.ReplicaPool.MaxOpenConns,
+		"DB_REPLICA_MAX_IDLE_CONNS":        &c.Database.ReplicaPool.MaxIdleConns,
+		"DB_REPLICA_CONN_MAX_LIFETIME":     &c.Database.ReplicaPool.ConnMaxLifetime,
+		"DB_REPLICA_CONN_MAX_IDLE_TIME":    &c.Database.ReplicaPool.ConnMaxIdleTime,
+		"DB_REPLICA_MAX_LAG":               &c.Database.ReplicaMaxLag,
+		"DB_REPLICA_LAG_CHECK_INTERVAL":    &c.Database.ReplicaLagCheckInterval,
+		"REDIS_ENABLED":                    &c.Redis.Enabled,
+		"REDIS_HOST":                       &c.Redis.Host,
+		"REDIS_PORT":                       &c.Redis.Port,
+		"REDIS_PASSWORD":                   &c.Redis.Password,
+		"REDIS_DB":                         &c.Redis.DB,
+		"TRACK_CACHE_TTL":                  &c.Redis.TrackCacheTTL,
+		"LABEL_STATS_CACHE_TTL":            &c.Redis.LabelStatsCacheTTL,
+		"JWT_SECRET":                       &c.Auth.JWTSecret,
+		"ACCESS_TOKEN_TTL":                 &c.Auth.AccessTokenTTL,
+		"REFRESH_TOKEN_TTL":                &c.Auth.RefreshTokenTTL,
+		"API_KEY_LENGTH":                   &c.Auth.APIKeyLength,
+		"PASSWORD_MIN_LENGTH":              &c.Auth.PasswordMinLength,
+		"PASSWORD_HASH_COST":               &c.Auth.PasswordHashCost,
+		"MAX_LOGIN_ATTEMPTS":               &c.Auth.MaxLoginAttempts,
+		"LOCKOUT_DURATION":                 &c.Auth.LockoutDuration,
+		"SESSION_TIMEOUT":                  &c.Auth.SessionTimeout,
+		"ENABLE_TWO_FACTOR":                &c.Auth.EnableTwoFactor,
+		"REQUIRE_STRONG_PASSWORD":          &c.Auth.RequireStrongPasswd,
+		"PASSWORD_RESET_TTL":               &c.Auth.PasswordResetTTL,
+		"PASSWORD_RESET_MAX_REQUESTS":      &c.Auth.PasswordResetMaxRequests,
+		"PASSWORD_RESET_WINDOW":            &c.Auth.PasswordResetWindow,
+		"PASSWORD_RESET_URL":               &c.Auth.PasswordResetURL,
+		"PASSWORD_RESET_WEBHOOK_URL":       &c.Auth.PasswordResetWebhookURL,
+		"LOGIN_ALERT_WEBHOOK_URL":          &c.Auth.LoginAlertWebhookURL,
+		"AI_PROVIDER":                      &c.AI.Provider,
+		"AI_MODEL_NAME":                    &c.AI.ModelName,
+		"AI_MODEL_VERSION":                 &c.AI.ModelVersion,
+		"AI_QWEN2_MODEL":                   &c.AI.Qwen2Model,
+		"AI_QWEN2_MODEL_VERSION":           &c.AI.Qwen2ModelVersion,
+		"AI_TEMPERATURE":                   &c.AI.Temperature,
+		"AI_MAX_TOKENS":                    &c.AI.MaxTokens,
+		"AI_BATCH_SIZE":                    &c.AI.BatchSize,
+		"AI_MIN_CONFIDENCE":                &c.AI.MinConfidence,
+		"AI_API_KEY":                       &c.AI.APIKey,
+		"AI_BASE_URL":                      &c.AI.BaseURL,
+		"AI_TIMEOUT":                       &c.AI.Timeout,
+		"AI_EXPERIMENT_TRAFFIC_PERCENT":    &c.AI.Experiment.TrafficPercent,
+		"AI_MIN_CONFIDENCE_THRESHOLD":      &c.AI.Experiment.MinConfidence,
+		"AI_ENABLE_AUTO_FALLBACK":          &c.AI.Experiment.EnableFallback,
+		"AI_OVERWRITE_MANUAL_EDITS":        &c.AI.OverwriteManualEdits,
+		"AI_CACHE_TTL":                     &c.AI.CacheTTL,
+		"AI_MAX_CONCURRENT_REQUESTS":       &c.AI.MaxConcurrentRequests,
+		"AI_QWEN2_REQUESTS_PER_SECOND":     &c.AI.Qwen2RequestsPerSecond,
+		"AI_OPENAI_REQUESTS_PER_SECOND":    &c.AI.OpenAIRequestsPerSecond,
+		"AI_QWEN2_AUDIO_FEATURES":          &c.AI.Qwen2AudioFeatures,
+		"AI_OPENAI_AUDIO_FEATURES":         &c.AI.OpenAIAudioFeatures,
+		"AI_MAX_PROMPT_TOKENS":             &c.AI.MaxPromptTokens,
+		"AI_HEALTH_CHECK_INTERVAL":         &c.AI.HealthCheckInterval,
+		"AI_ROUTING_POLICY":                &c.AI.RoutingPolicy,
+		"AI_CREDENTIAL_KEY":                &c.AI.CredentialKey,
+		"AI_EVALUATION_INTERVAL":           &c.AI.EvaluationInterval,
+		"AI_EVALUATION_TARGET_ACCURACY":    &c.AI.EvaluationTargetAccuracy,
+		"AI_BATCH_POLL_INTERVAL":           &c.AI.BatchPollInterval,
+		"AI_BATCH_MAX_TRACKS":              &c.AI.BatchMaxTracks,
+		"AI_PROCESSING_LOCK_TTL":           &c.AI.ProcessingLockTTL,
+		"SESSION_COOKIE_NAME":              &c.Session.CookieName,
+		"SESSION_COOKIE_DOMAIN":            &c.Session.CookieDomain,
+		"SESSION_COOKIE_PATH":              &c.Session.CookiePath,
+		"SESSION_COOKIE_SECURE":            &c.Session.CookieSecure,
+		"SESSION_COOKIE_HTTP_ONLY":         &c.Session.CookieHTTPOnly,
+		"SESSION_COOKIE_SAME_SITE":         &c.Session.CookieSameSite,
+		"SESSION_DURATION":                 &c.Session.SessionDuration,
+		"SESSION_CLEANUP_INTERVAL":         &c.Session.CleanupInterval,
+		"SESSION_MAX_PER_USER":             &c.Session.MaxSessionsPerUser,
+		"JOB_NUM_WORKERS":                  &c.Jobs.NumWorkers,
+		"JOB_MAX_CONCURRENT":               &c.Jobs.MaxConcurrent,
+		"JOB_POLL_INTERVAL":                &c.Jobs.PollInterval,
+		"JOB_SHUTDOWN_WAIT":                &c.Jobs.ShutdownWait,
+		"JOB_DEFAULT_MAX_RETRIES":          &c.Jobs.DefaultMaxRetries,
+		"JOB_DEFAULT_TTL":                  &c.Jobs.DefaultTTL,
+		"JOB_MAX_PAYLOAD_SIZE":             &c.Jobs.MaxPayloadSize,
+		"JOB_QUEUE_PREFIX":                 &c.Jobs.QueuePrefix,
+		"JOB_RETRY_DELAY":                  &c.Jobs.RetryDelay,
+		"JOB_MAX_RETRY_DELAY":              &c.Jobs.MaxRetryDelay,
+		"JOB_RETRY_MULTIPLIER":             &c.Jobs.RetryMultiplier,
+		"JOB_CLEANUP_INTERVAL":             &c.Jobs.CleanupInterval,
+		"JOB_MAX_AGE":                      &c.Jobs.MaxJobAge,
+		"JOB_TYPE_LIMITS":                  &c.Jobs.TypeLimits,
+		"JOB_TYPE_WEIGHTS":                 &c.Jobs.TypeWeights,
+		"JOB_ARCHIVE_INTERVAL":             &c.Jobs.ArchiveInterval,
+		"JOB_ARCHIVE_AFTER":                &c.Jobs.ArchiveAfter,
+		"STORAGE_PROVIDER":                 &c.Storage.Provider,
+		"STORAGE_REGION":                   &c.Storage.Region,
+		"STORAGE_BUCKET":                   &c.Storage.Bucket,
+		"STORAGE_ACCESS_KEY":               &c.Storage.AccessKey,
+		"STORAGE_SECRET_KEY":               &c.Storage.SecretKey,
+		"STORAGE_ENDPOINT":                 &c.Storage.Endpoint,
+		"STORAGE_USE_SSL":                  &c.Storage.UseSSL,
+		"STORAGE_UPLOAD_PART_SIZE":         &c.Storage.UploadPartSize,
+		"STORAGE_MAX_UPLOAD_RETRIES":       &c.Storage.MaxUploadRetries,
+		"STORAGE_MAX_FILE_SIZE":            &c.Storage.MaxFileSize,
+		"STORAGE_ALLOWED_FILE_TYPES":       &c.Storage.AllowedFileTypes,
+		"STORAGE_USER_QUOTA":               &c.Storage.UserQuota,
+		"STORAGE_TOTAL_QUOTA":              &c.Storage.TotalQuota,
+		"STORAGE_QUOTA_WARNING_PCT":        &c.Storage.QuotaWarningPct,
+		"STORAGE_TEMP_FILE_EXPIRY":         &c.Storage.TempFileExpiry,
+		"STORAGE_CLEANUP_INTERVAL":         &c.Storage.CleanupInterval,
+		"STORAGE_UPLOAD_BUFFER_SIZE":       &c.Storage.UploadBufferSize,
+		"STORAGE_DOWNLOAD_TIMEOUT":         &c.Storage.DownloadTimeout,
+		"STORAGE_UPLOAD_TIMEOUT":           &c.Storage.UploadTimeout,
+		"STORAGE_UPLOAD_URL_EXPIRY":        &c.Storage.UploadURLExpiry,
+		"STORAGE_STREAM_URL_EXPIRY":        &c.Storage.StreamURLExpiry,
+		"STORAGE_LIFECYCLE_PREFIX":         &c.Storage.LifecyclePrefix,
+		"STORAGE_IA_AFTER_DAYS":            &c.Storage.InfrequentAccessAfterDays,
+		"STORAGE_ARCHIVE_AFTER_DAYS":       &c.Storage.ArchiveAfterDays,
+		"STORAGE_RESTORE_DAYS":             &c.Storage.RestoreDays,
+		"STORAGE_RESTORE_INTERVAL":         &c.Storage.RestoreCheckInterval,
+		"STORAGE_RESTORE_WEBHOOK_URL":      &c.Storage.RestoreWebhookURL,
+		"STORAGE_REPLICA_BUCKET":           &c.Storage.ReplicaBucket,
+		"STORAGE_REPLICA_REGION":           &c.Storage.ReplicaRegion,
+		"STORAGE_QUOTA_WEBHOOK_URL":        &c.Storage.QuotaWebhookURL,
+		"STORAGE_RECONCILE_INTERVAL":       &c.Storage.UsageReconcileInterval,
+		"STORAGE_INTEGRITY_INTERVAL":       &c.Storage.IntegrityAuditInterval,
+		"STORAGE_KMS_KEY_ID":               &c.Storage.KMSKeyID,
+		"STORAGE_REPLICA_KMS_KEY_ID":       &c.Storage.ReplicaKMSKeyID,
+		"TRACING_ENABLED":                  &c.Tracing.Enabled,
+		"TRACING_SERVICE_NAME":             &c.Tracing.ServiceName,
+		"TRACING_ENDPOINT":                 &c.Tracing.Endpoint,
+		"TRACING_SAMPLE_RATE":              &c.Tracing.SampleRate,
+		"SENTRY_DSN":                       &c.Sentry.DSN,
+		"SENTRY_ENVIRONMENT":               &c.Sentry.Environment,
+		"SENTRY_DEBUG":                     &c.Sentry.Debug,
+		"SENTRY_SAMPLE_RATE":               &c.Sentry.SampleRate,
+		"SENTRY_TRACES_SAMPLE_RATE":        &c.Sentry.TracesSampleRate,
+		"SENTRY_RELEASE":                   &c.Sentry.Release,
+		"DISABLE_QUEUE":                    &c.Queue.Disabled,
+		"PUBSUB_PROJECT_ID":                &c.Queue.ProjectID,
+		"PUBSUB_HIGH_PRIORITY_TOPIC":       &c.Queue.HighPriorityTopic,
+		"PUBSUB_LOW_PRIORITY_TOPIC":        &c.Queue.LowPriorityTopic,
+		"PUBSUB_DEAD_LETTER_TOPIC":         &c.Queue.DeadLetterTopic,
+		"PUBSUB_SUBSCRIPTION_PREFIX":       &c.Queue.SubscriptionPrefix,
+		"PUBSUB_MAX_RETRIES":               &c.Queue.MaxRetries,
+		"PUBSUB_ACK_DEADLINE":              &c.Queue.AckDeadline,
+		"PUBSUB_RETENTION":                 &c.Queue.RetentionDuration,
+		"PUBSUB_MAX_EXTENSION":             &c.Queue.MaxExtension,
+		"PUBSUB_CHANGE_FEED_TOPIC":         &c.Queue.ChangeFeedTopic,
+		"OUTBOX_POLL_INTERVAL":             &c.Queue.OutboxPollInterval,
+		"OUTBOX_BATCH_SIZE":                &c.Queue.OutboxBatchSize,
+		"QUEUE_LAG_INTERVAL":               &c.Queue.LagInterval,
+		"QUEUE_LAG_THRESHOLD":              &c.Queue.LagThreshold,
+		"QUEUE_MAX_LAG":                    &c.Queue.MaxLag,
+		"QUEUE_LAG_ALERT_WEBHOOK_URL":      &c.Queue.LagAlertWebhookURL,
+		"QUEUE_REDIS_BACKEND":              &c.Queue.RedisBackend,
+		"CORS_ALLOWED_ORIGINS":             &c.CORS.AllowedOrigins,
+		"CORS_ALLOWED_METHODS":             &c.CORS.AllowedMethods,
+		"CORS_ALLOWED_HEADERS":             &c.CORS.AllowedHeaders,
+		"CORS_EXPOSED_HEADERS":             &c.CORS.ExposedHeaders,
+		"CORS_ALLOW_CREDENTIALS":           &c.CORS.AllowCredentials,
+		"CORS_MAX_AGE":                     &c.CORS.MaxAge,
+		"HSTS_MAX_AGE":                     &c.Security.HSTSMaxAge,
+		"HSTS_INCLUDE_SUBDOMAINS":          &c.Security.HSTSIncludeSubdomains,
+		"FRAME_OPTIONS":                    &c.Security.FrameOptions,
+		"CSRF_ENABLED":                     &c.Security.CSRF,
+		"API_V1_DEPRECATED":                &c.API.V1Deprecated,
+		"API_V1_SUNSET":                    &c.API.V1Sunset,
+		"API_V1_DEPRECATION_LINK":          &c.API.V1DeprecationLink,
+		"SECRETS_REFRESH_INTERVAL":         &c.Secrets.RefreshInterval,
+		"VAULT_ADDR":                       &c.Secrets.VaultAddress,
+		"VAULT_TOKEN":                      &c.Secrets.VaultToken,
+		"SECRETS_AWS_REGION":               &c.Secrets.AWSRegion,
+		"BIGQUERY_PROJECT":                 &c.Analytics.ProjectID,
+		"BIGQUERY_DATASET":                 &c.Analytics.Dataset,
+		"ANALYTICS_FLUSH_INTERVAL":         &c.Analytics.FlushInterval,
+		"ANALYTICS_BATCH_SIZE":             &c.Analytics.BatchSize,
+		"ANALYTICS_BUFFER_SIZE":            &c.Analytics.BufferSize,
+		"ANALYTICS_RETENTION":              &c.Analytics.Retention,
+		"ANALYTICS_SINK":                   &c.Analytics.Sink,
+		"CLICKHOUSE_URL":                   &c.Analytics.ClickHouse.URL,
+		"CLICKHOUSE_DATABASE":              &c.Analytics.ClickHouse.Database,
+		"CLICKHOUSE_USER":                  &c.Analytics.ClickHouse.User,
+		"CLICKHOUSE_PASSWORD":              &c.Analytics.ClickHouse.Password,
+		"USAGE_SNAPSHOT_INTERVAL":          &c.Usage.SnapshotInterval,
+		"KPI_INTERVAL":                     &c.KPI.Interval,
+		"PUBLIC_API_FREE_PER_MINUTE":       &c.PublicAPI.FreePerMinute,
+		"PUBLIC_API_FREE_PER_DAY":          &c.PublicAPI.FreePerDay,
+		"PUBLIC_API_PARTNER_PER_MINUTE":    &c.PublicAPI.PartnerPerMinute,
+		"PUBLIC_API_PARTNER_PER_DAY":       &c.PublicAPI.PartnerPerDay,
+		"PUBLIC_API_ENTERPRISE_PER_MINUTE": &c.PublicAPI.EnterprisePerMinute,
+		"PUBLIC_API_ENTERPRISE_PER_DAY":    &c.PublicAPI.EnterprisePerDay,
+		"SCANNER_PROVIDER":                 &c.Scanner.Provider,
+		"SCANNER_ADDRESS":                  &c.Scanner.Address,
+		"SCANNER_URL":                      &c.Scanner.URL,
+		"SCANNER_API_KEY":                  &c.Scanner.APIKey,
+		"SCANNER_TIMEOUT":                  &c.Scanner.Timeout,
+		"BOOTSTRAP_SETUP_TOKEN":            &c.Bootstrap.SetupToken,
+		"PROMOTION_SIGNING_KEY":            &c.Promotion.SigningKey,
+		"MAINTENANCE_LEASE_TTL":            &c.Maintenance.LeaseTTL,
+		"MAINTENANCE_HISTORY_SIZE":         &c.Maintenance.HistorySize,
+		"MAINTENANCE_DEAD_LETTER_EXPIRY":   &c.Maintenance.DeadLetterExpiry,
+		"EVENTS_WEBHOOK_URL":               &c.Events.WebhookURL,
 	}
-	return defaultValue
 }
 
-func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
-		if duration, err := time.ParseDuration(value); err == nil {
-			return duration
+// applyEnv overrides settings with the environment variables that are set.
+// Values that do not parse are ignored and the current setting is kept.
+func (c *AppConfig) applyEnv() {
+	for key, target := range c.envBindings() {
+		if value := os.Getenv(key); value != "" {
+			_ = setFromString(reflect.ValueOf(target).Elem(), value)
 		}
 	}
-	return defaultValue
 }
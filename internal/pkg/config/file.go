@@ -0,0 +1,530 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"metadatatool/internal/pkg/scheduler"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// redactedValue replaces secrets when the configuration is printed
+const redactedValue = "[REDACTED]"
+
+// secretKeys lists the settings that are never printed
+var secretKeys = map[string]bool{
+	"database.password":             true,
+	"redis.password":                true,
+	"auth.jwt_secret":               true,
+	"ai.api_key":                    true,
+	"ai.credential_key":             true,
+	"storage.access_key":            true,
+	"storage.secret_key":            true,
+	"sentry.dsn":                    true,
+	"secrets.vault_token":           true,
+	"analytics.clickhouse.password": true,
+	"scanner.api_key":               true,
+	"bootstrap.setup_token":         true,
+	"promotion.signing_key":         true,
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// loadFile applies the settings in a YAML or TOML file. Keys follow the JSON
+// names of the configuration fields; unknown keys and values of the wrong
+// type are rejected.
+func (c *AppConfig) loadFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	raw := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &raw)
+	case ".toml":
+		err = toml.Unmarshal(content, &raw)
+	default:
+		return fmt.Errorf("unsupported config file %s: use .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if err := decodeStruct(reflect.ValueOf(c).Elem(), raw, ""); err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// decodeStruct assigns the values in raw to the fields of v by JSON name
+func decodeStruct(v reflect.Value, raw map[string]interface{}, prefix string) error {
+	fields := make(map[string]reflect.Value)
+	for i := 0; i < v.NumField(); i++ {
+		fields[jsonName(v.Type().Field(i))] = v.Field(i)
+	}
+
+	for key, value := range raw {
+		path := prefix + key
+		field, ok := fields[key]
+		if !ok {
+			return fmt.Errorf("unknown key %s", path)
+		}
+
+		if field.Kind() == reflect.Struct {
+			section, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s must be a section", path)
+			}
+			if err := decodeStruct(field, section, path+"."); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := decodeValue(field, value); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// decodeValue assigns a single file value to field
+func decodeValue(field reflect.Value, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	case []interface{}:
+		if field.Kind() != reflect.Slice {
+			return fmt.Errorf("expected %s, got a list", typeName(field.Type()))
+		}
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			list = append(list, fmt.Sprint(item))
+		}
+		field.Set(reflect.ValueOf(list))
+		return nil
+	case map[string]interface{}:
+		return fmt.Errorf("expected %s, got a section", typeName(field.Type()))
+	case time.Time:
+		// YAML reads unquoted dates as timestamps
+		if v.Equal(v.Truncate(24 * time.Hour)) {
+			return setFromString(field, v.Format(dateLayout))
+		}
+		return setFromString(field, v.Format(time.RFC3339))
+	default:
+		return setFromString(field, fmt.Sprint(v))
+	}
+}
+
+// setFromString parses value into field. Lists are comma separated, with
+// empty entries skipped.
+func setFromString(field reflect.Value, value string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", value)
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		var list []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		field.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("unsupported setting type %s", field.Type())
+	}
+	return nil
+}
+
+// defaultJWTSecret is the built-in JWT secret, which is public and so must
+// be replaced in production
+const defaultJWTSecret = "your-secret-key"
+
+// minJWTSecretLength is the shortest JWT secret that is not warned about:
+// HS256 keys should be at least as long as the hash
+const minJWTSecretLength = 32
+
+// ValidationError lists every problem found in the configuration, so they
+// can all be fixed before the next start
+type ValidationError struct {
+	Problems []string
+}
+
+// Error reports the problems one per line
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid configuration, %d problem(s):", len(e.Problems))
+	for _, problem := range e.Problems {
+		b.WriteString("\n  - ")
+		b.WriteString(problem)
+	}
+	return b.String()
+}
+
+// Validate checks that the settings the enabled subsystems need are set
+// and within their allowed ranges. It returns a *ValidationError listing
+// every problem found.
+func (c *AppConfig) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+	// require reports a blank setting, naming the variable that sets it
+	envVars := c.envVarsByPath()
+	require := func(value, path, when string) {
+		if strings.TrimSpace(value) != "" {
+			return
+		}
+		problem := path + " is required" + when
+		if env := envVars[path]; env != "" {
+			problem += "; set it in the config file or with " + env
+		}
+		problems = append(problems, problem)
+	}
+
+	check(c.Server.Port >= 0 && c.Server.Port <= 65535, "server.port %d is out of range", c.Server.Port)
+	check(c.Database.Driver == DriverPostgres || c.Database.Driver == DriverSQLite,
+		"database.driver must be %q or %q, got %q", DriverPostgres, DriverSQLite, c.Database.Driver)
+	switch c.Database.Driver {
+	case DriverPostgres:
+		check(c.Database.Port > 0 && c.Database.Port <= 65535, "database.port %d is out of range", c.Database.Port)
+		require(c.Database.Host, "database.host", " for the postgres driver")
+		require(c.Database.User, "database.user", " for the postgres driver")
+		require(c.Database.DBName, "database.dbname", " for the postgres driver")
+	case DriverSQLite:
+		require(c.Database.SQLitePath, "database.sqlite_path", " for the sqlite driver")
+	}
+	if c.Redis.Enabled {
+		check(c.Redis.Port > 0 && c.Redis.Port <= 65535, "redis.port %d is out of range", c.Redis.Port)
+		require(c.Redis.Host, "redis.host", " with redis.enabled")
+	}
+
+	require(c.Auth.JWTSecret, "auth.jwt_secret", "")
+	if c.Server.IsProduction() {
+		check(c.Auth.JWTSecret != defaultJWTSecret,
+			"auth.jwt_secret must be changed from the built-in default in production; set JWT_SECRET")
+	}
+	check(c.Auth.AccessTokenTTL > 0, "auth.access_token_ttl must be positive, got %v", c.Auth.AccessTokenTTL)
+	check(c.Auth.RefreshTokenTTL > c.Auth.AccessTokenTTL,
+		"auth.refresh_token_ttl %v must be longer than auth.access_token_ttl %v", c.Auth.RefreshTokenTTL, c.Auth.AccessTokenTTL)
+	// The range bcrypt accepts
+	check(c.Auth.PasswordHashCost >= 4 && c.Auth.PasswordHashCost <= 31,
+		"auth.password_hash_cost must be between 4 and 31, got %d", c.Auth.PasswordHashCost)
+	check(c.Auth.APIKeyLength > 0, "auth.api_key_length must be positive, got %d", c.Auth.APIKeyLength)
+
+	require(c.Session.CookieName, "session.cookie_name", "")
+	check(c.Session.SessionDuration > 0, "session.session_duration must be positive, got %v", c.Session.SessionDuration)
+	switch strings.ToLower(c.Session.CookieSameSite) {
+	case "lax", "strict", "none":
+	default:
+		check(false, "session.cookie_same_site must be lax, strict or none, got %q", c.Session.CookieSameSite)
+	}
+
+	switch c.AI.Provider {
+	case "", "openai", "qwen2":
+	default:
+		check(false, "ai.provider must be openai or qwen2, got %q", c.AI.Provider)
+	}
+	switch c.AI.RoutingPolicy {
+	case "", "experiment", "latency":
+	default:
+		check(false, "ai.routing_policy must be experiment or latency, got %q", c.AI.RoutingPolicy)
+	}
+	check(c.AI.ProcessingLockTTL > 0, "ai.processing_lock_ttl must be positive, got %v", c.AI.ProcessingLockTTL)
+
+	require(c.Storage.Bucket, "storage.bucket", "")
+	require(c.Storage.Region, "storage.region", "")
+	check((c.Storage.AccessKey == "") == (c.Storage.SecretKey == ""),
+		"storage.access_key and storage.secret_key must be set together")
+	check(c.Storage.MaxFileSize > 0, "storage.max_file_size must be positive, got %d", c.Storage.MaxFileSize)
+
+	if c.Tracing.Enabled {
+		require(c.Tracing.Endpoint, "tracing.endpoint", " with tracing.enabled")
+		require(c.Tracing.ServiceName, "tracing.service_name", " with tracing.enabled")
+	}
+
+	switch c.Analytics.Sink {
+	case SinkBigQuery, SinkClickHouse, SinkPostgres, SinkStdout, SinkNone:
+	default:
+		check(false, "analytics.sink must be bigquery, clickhouse, postgres, stdout or none, got %q", c.Analytics.Sink)
+	}
+
+	switch c.Scanner.Provider {
+	case ScannerClamAV:
+		check(c.Scanner.Address != "", "scanner.address is required for the clamav scanner")
+	case ScannerHTTP:
+		check(c.Scanner.URL != "", "scanner.url is required for the http scanner")
+	case ScannerNone:
+	default:
+		check(false, "scanner.provider must be clamav, http or none, got %q", c.Scanner.Provider)
+	}
+
+	switch c.Queue.RedisBackend {
+	case RedisQueueList, RedisQueueStreams:
+	default:
+		check(false, "queue.redis_backend must be list or streams, got %q", c.Queue.RedisBackend)
+	}
+	// Pub/Sub only accepts ack deadlines within this range
+	check(c.Queue.AckDeadline >= 10*time.Second && c.Queue.AckDeadline <= 600*time.Second,
+		"queue.ack_deadline must be between 10s and 600s, got %v", c.Queue.AckDeadline)
+	check(c.Queue.MaxExtension >= 0, "queue.max_extension must not be negative, got %v", c.Queue.MaxExtension)
+	if c.Queue.LagThreshold > 0 && c.Queue.MaxLag > 0 {
+		check(c.Queue.MaxLag >= c.Queue.LagThreshold, "queue.max_lag %d must not be below queue.lag_threshold %d", c.Queue.MaxLag, c.Queue.LagThreshold)
+	}
+
+	_, invalidLimits := parseTypeCounts(c.Jobs.TypeLimits)
+	_, invalidWeights := parseTypeCounts(c.Jobs.TypeWeights)
+	check(len(invalidLimits) == 0, "jobs.type_limits entries must look like ddex_export=2, got %q", invalidLimits)
+	check(len(invalidWeights) == 0, "jobs.type_weights entries must look like ai_enrich=3, got %q", invalidWeights)
+	check(c.Jobs.ArchiveAfter >= 0, "jobs.archive_after must not be negative, got %v", c.Jobs.ArchiveAfter)
+	if c.Jobs.ArchiveInterval > 0 {
+		// A job whose data expired before it is archived is lost
+		check(c.Jobs.ArchiveAfter < c.Jobs.DefaultTTL,
+			"jobs.archive_after %v must be shorter than jobs.default_ttl %v", c.Jobs.ArchiveAfter, c.Jobs.DefaultTTL)
+	}
+
+	// The lead is renewed every third of the lease, at most once a second
+	check(c.Maintenance.LeaseTTL >= 3*time.Second, "maintenance.lease_ttl must be at least 3s, got %v", c.Maintenance.LeaseTTL)
+	check(c.Maintenance.HistorySize > 0, "maintenance.history_size must be positive, got %d", c.Maintenance.HistorySize)
+	if c.Maintenance.DeadLetterExpiry != "" {
+		_, err := scheduler.Parse(c.Maintenance.DeadLetterExpiry)
+		check(err == nil, "maintenance.dead_letter_expiry: %v", err)
+	}
+
+	check(c.Storage.QuotaWarningPct <= 100, "storage.quota_warning_pct must be at most 100, got %d", c.Storage.QuotaWarningPct)
+	// S3 keeps files in infrequent-access storage for at least 30 days
+	if c.Storage.InfrequentAccessAfterDays > 0 {
+		check(c.Storage.InfrequentAccessAfterDays >= 30,
+			"storage.infrequent_access_after_days must be at least 30, got %d", c.Storage.InfrequentAccessAfterDays)
+		if c.Storage.ArchiveAfterDays > 0 {
+			check(c.Storage.ArchiveAfterDays >= c.Storage.InfrequentAccessAfterDays+30,
+				"storage.archive_after_days must be at least 30 days after storage.infrequent_access_after_days")
+		}
+	}
+	if c.Storage.ReplicaBucket != "" {
+		check(c.Storage.ReplicaRegion != "", "storage.replica_region is required with storage.replica_bucket")
+		check(c.Storage.ReplicaBucket != c.Storage.Bucket || c.Storage.ReplicaRegion != c.Storage.Region,
+			"storage.replica_bucket must differ from storage.bucket")
+		check(c.Storage.KMSKeyID == "" || c.Storage.ReplicaKMSKeyID != "",
+			"storage.replica_kms_key_id is required with storage.kms_key_id and storage.replica_bucket")
+	}
+
+	switch c.Security.FrameOptions {
+	case "", "DENY", "SAMEORIGIN":
+	default:
+		check(false, "security.frame_options must be DENY or SAMEORIGIN, got %q", c.Security.FrameOptions)
+	}
+
+	deprecated, sunset := c.API.V1Dates()
+	check(c.API.V1Deprecated == "" || !deprecated.IsZero(), "api.v1_deprecated must be a date such as 2026-01-31, got %q", c.API.V1Deprecated)
+	check(c.API.V1Sunset == "" || !sunset.IsZero(), "api.v1_sunset must be a date such as 2026-07-31, got %q", c.API.V1Sunset)
+	if !deprecated.IsZero() && !sunset.IsZero() {
+		check(sunset.After(deprecated), "api.v1_sunset must be after api.v1_deprecated")
+	}
+
+	for _, proxy := range c.Server.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(cidrErr == nil || net.ParseIP(proxy) != nil, "server.trusted_proxies must list IP addresses or CIDR ranges, got %q", proxy)
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		check(origin != "*" || !c.CORS.AllowCredentials, "cors.allowed_origins must list origins instead of \"*\" with cors.allow_credentials")
+		if c.Server.IsProduction() {
+			check(origin != "*", "cors.allowed_origins must not contain \"*\" in production")
+			check(origin == "*" || strings.HasPrefix(origin, "https://"), "cors.allowed_origins must use https in production, got %q", origin)
+		}
+	}
+
+	for path, rate := range map[string]float64{
+		"ai.min_confidence":             c.AI.MinConfidence,
+		"ai.experiment.traffic_percent": c.AI.Experiment.TrafficPercent,
+		"ai.experiment.min_confidence":  c.AI.Experiment.MinConfidence,
+		"tracing.sample_rate":           c.Tracing.SampleRate,
+		"sentry.sample_rate":            c.Sentry.SampleRate,
+		"sentry.traces_sample_rate":     c.Sentry.TracesSampleRate,
+	} {
+		check(rate >= 0 && rate <= 1, "%s must be between 0 and 1, got %v", path, rate)
+	}
+
+	walkSettings(reflect.ValueOf(c).Elem(), "", func(path string, v reflect.Value) {
+		if v.Type() == durationType || v.Kind() == reflect.Int || v.Kind() == reflect.Int64 {
+			check(v.Int() >= 0, "%s must not be negative", path)
+		}
+		// Webhooks and endpoints are called as given
+		if strings.HasSuffix(path, "url") && v.Kind() == reflect.String && v.String() != "" {
+			u, err := url.Parse(v.String())
+			check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+				"%s must be an http or https URL, got %q", path, v.String())
+		}
+	})
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// Warnings reports settings that are allowed but probably wrong, such as a
+// weak JWT secret. Secrets are checked as given, so call it once
+// secretref:// values are resolved.
+func (c *AppConfig) Warnings() []string {
+	var warnings []string
+	warn := func(bad bool, format string, args ...interface{}) {
+		if bad {
+			warnings = append(warnings, fmt.Sprintf(format, args...))
+		}
+	}
+
+	warn(c.Auth.JWTSecret == defaultJWTSecret,
+		"auth.jwt_secret is the built-in default, so anyone can forge tokens; set JWT_SECRET")
+	warn(c.Auth.JWTSecret != defaultJWTSecret && len(c.Auth.JWTSecret) < minJWTSecretLength,
+		"auth.jwt_secret is shorter than %d bytes", minJWTSecretLength)
+	if c.Server.IsProduction() {
+		warn(c.Database.Driver == DriverPostgres && c.Database.SSLMode == "disable",
+			"database.sslmode is disable in production, so database connections are not encrypted")
+		warn(!c.Session.CookieSecure, "session.cookie_secure is off in production, so session cookies are sent over plain http")
+		warn(c.Database.Driver == DriverSQLite, "database.driver is sqlite in production, which does not scale past one replica")
+	}
+	warn(c.AI.Provider == "openai" && c.AI.APIKey == "", "ai.api_key is empty, so OpenAI enrichment will fail; set AI_API_KEY")
+	warn(!c.Queue.Disabled && c.Queue.ProjectID == "",
+		"queue.project_id is empty, so Pub/Sub cannot be reached; set PUBSUB_PROJECT_ID or queue.disabled")
+	warn(c.Database.Pool.MaxOpenConns > 0 && c.Database.Pool.MaxIdleConns > c.Database.Pool.MaxOpenConns,
+		"database.pool.max_idle_conns %d is above max_open_conns %d and is lowered to it",
+		c.Database.Pool.MaxIdleConns, c.Database.Pool.MaxOpenConns)
+	warn(c.Database.VectorSearch && c.Database.Driver != DriverPostgres,
+		"database.vector_search needs the postgres driver, so similar track search is off")
+	warn(c.Tracing.Enabled && c.Tracing.SampleRate == 0, "tracing.enabled is set but tracing.sample_rate is 0, so no traces are recorded")
+	warn(c.Storage.TotalQuota > 0 && c.Storage.UserQuota > c.Storage.TotalQuota,
+		"storage.user_quota is above storage.total_quota, so a single user can fill the storage")
+	return warnings
+}
+
+// envVarsByPath returns the environment variable bound to each setting,
+// keyed by the setting's path
+func (c *AppConfig) envVarsByPath() map[string]string {
+	byAddr := make(map[interface{}]string)
+	for env, ptr := range c.envBindings() {
+		byAddr[ptr] = env
+	}
+	vars := make(map[string]string)
+	walkSettings(reflect.ValueOf(c).Elem(), "", func(path string, v reflect.Value) {
+		if env, ok := byAddr[v.Addr().Interface()]; ok {
+			vars[path] = env
+		}
+	})
+	return vars
+}
+
+// Redacted renders the configuration as one "key: value" line per setting,
+// with secrets replaced so the output can be logged
+func (c *AppConfig) Redacted() string {
+	var b strings.Builder
+	walkSettings(reflect.ValueOf(c).Elem(), "", func(path string, v reflect.Value) {
+		value := formatSetting(v)
+		if secretKeys[path] && value != "" {
+			value = redactedValue
+		}
+		fmt.Fprintf(&b, "%s: %s\n", path, value)
+	})
+	return b.String()
+}
+
+// SecretFields returns the settings that may hold credentials, keyed by their
+// path, so they can be resolved from a secrets manager
+func (c *AppConfig) SecretFields() map[string]*string {
+	secrets := make(map[string]*string, len(secretKeys))
+	walkSettings(reflect.ValueOf(c).Elem(), "", func(path string, v reflect.Value) {
+		if secretKeys[path] && path != "secrets.vault_token" {
+			secrets[path] = v.Addr().Interface().(*string)
+		}
+	})
+	return secrets
+}
+
+// walkSettings calls fn for every leaf setting in declaration order
+func walkSettings(v reflect.Value, prefix string, fn func(path string, v reflect.Value)) {
+	for i := 0; i < v.NumField(); i++ {
+		path := prefix + jsonName(v.Type().Field(i))
+		if field := v.Field(i); field.Kind() == reflect.Struct {
+			walkSettings(field, path+".", fn)
+		} else {
+			fn(path, field)
+		}
+	}
+}
+
+func formatSetting(v reflect.Value) string {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	if v.Kind() == reflect.Slice {
+		return strings.Join(v.Interface().([]string), ",")
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+func typeName(t reflect.Type) string {
+	if t == durationType {
+		return "a duration"
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int64:
+		return "an integer"
+	case reflect.Float64:
+		return "a number"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Slice:
+		return "a list"
+	default:
+		return "a string"
+	}
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadFile_LayersDefaultsFileAndEnv(t *testing.T) {
+	path := writeConfig(t, "config.yaml", `
+server:
+  port: 9090
+database:
+  host: db.internal
+  replicas: [replica-1, replica-2:5433]
+  pool:
+    conn_max_lifetime: 1h
+redis:
+  port: 6380
+`)
+	t.Setenv("DB_HOST", "db.override")
+
+	cfg, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.Equal(t, "db.override", cfg.Database.Host)
+	assert.Equal(t, []string{"replica-1", "replica-2:5433"}, cfg.Database.Replicas)
+	assert.Equal(t, time.Hour, cfg.Database.Pool.ConnMaxLifetime)
+	assert.Equal(t, 6380, cfg.Redis.Port)
+	// Settings the file leaves out keep their defaults
+	assert.Equal(t, "metadatatool", cfg.Database.DBName)
+}
+
+func TestLoadFile_TOML(t *testing.T) {
+	path := writeConfig(t, "config.toml", `
+[server]
+port = 9091
+
+[ai.experiment]
+traffic_percent = 0.5
+`)
+
+	cfg, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 9091, cfg.Server.Port)
+	assert.Equal(t, 0.5, cfg.AI.Experiment.TrafficPercent)
+}
+
+func TestLoadFile_RejectsInvalidFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown key", "server:\n  prot: 80\n", "unknown key server.prot"},
+		{"wrong type", "server:\n  port: eighty\n", `server.port: invalid integer "eighty"`},
+		{"bad duration", "auth:\n  access_token_ttl: 15\n", `auth.access_token_ttl: invalid duration "15"`},
+		{"scalar section", "redis: localhost\n", "redis must be a section"},
+		{"out of range", "database:\n  driver: mysql\n", "database.driver must be"},
+		{"wildcard origin in production", "server:\n  environment: production\ncors:\n  allowed_origins: [\"*\"]\n  allow_credentials: false\n", `must not contain "*" in production`},
+		{"plain http origin in production", "server:\n  environment: production\ncors:\n  allowed_origins: [http://app.example.com]\n", "must use https in production"},
+		{"bad sunset date", "api:\n  v1_sunset: next summer\n", "api.v1_sunset must be a date"},
+		{"sunset before deprecation", "api:\n  v1_deprecated: 2026-06-01\n  v1_sunset: 2026-01-01\n", "api.v1_sunset must be after api.v1_deprecated"},
+		{"bad job type limit", "jobs:\n  type_limits: [ddex_export=0]\n", "jobs.type_limits entries must look like ddex_export=2"},
+		{"archive after data expires", "jobs:\n  default_ttl: 1h\n  archive_after: 2h\n", "jobs.archive_after 2h0m0s must be shorter than jobs.default_ttl 1h0m0s"},
+		{"no processing lock ttl", "ai:\n  processing_lock_ttl: 0s\n", "ai.processing_lock_ttl must be positive"},
+		{"bad maintenance schedule", "maintenance:\n  dead_letter_expiry: \"0 25 * * *\"\n", "maintenance.dead_letter_expiry: invalid schedule"},
+		{"no jwt secret", "auth:\n  jwt_secret: \"\"\n", "auth.jwt_secret is required; set it in the config file or with JWT_SECRET"},
+		{"default jwt secret in production", "server:\n  environment: production\n", "auth.jwt_secret must be changed from the built-in default in production"},
+		{"empty bucket", "storage:\n  bucket: \"\"\n", "storage.bucket is required; set it in the config file or with STORAGE_BUCKET"},
+		{"redis without host", "redis:\n  enabled: true\n  host: \"\"\n", "redis.host is required with redis.enabled"},
+		{"sqlite without path", "database:\n  driver: sqlite\n  sqlite_path: \"\"\n", "database.sqlite_path is required for the sqlite driver"},
+		{"refresh shorter than access", "auth:\n  refresh_token_ttl: 1m\n", "auth.refresh_token_ttl 1m0s must be longer than auth.access_token_ttl 15m0s"},
+		{"bad webhook url", "events:\n  webhook_url: hooks.example.com/track\n", `events.webhook_url must be an http or https URL, got "hooks.example.com/track"`},
+		{"unknown ai provider", "ai:\n  provider: llama\n", `ai.provider must be openai or qwen2, got "llama"`},
+		{"bad trusted proxy", "server:\n  trusted_proxies: [10.0.0.0/8, load-balancer]\n", `server.trusted_proxies must list IP addresses or CIDR ranges, got "load-balancer"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFile(writeConfig(t, "config.yaml", tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadFile_ReportsEveryProblem(t *testing.T) {
+	_, err := LoadFile(writeConfig(t, "config.yaml", "auth:\n  jwt_secret: \"\"\nstorage:\n  bucket: \"\"\n"))
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Len(t, validationErr.Problems, 2)
+	assert.True(t, strings.HasPrefix(err.Error(), "invalid configuration, 2 problem(s):\n  - auth.jwt_secret is required"), err.Error())
+}
+
+func TestAppConfig_Warnings(t *testing.T) {
+	cfg := defaults()
+	cfg.AI.APIKey = "sk-test"
+	cfg.Queue.ProjectID = "project"
+	assert.Equal(t, []string{
+		"auth.jwt_secret is the built-in default, so anyone can forge tokens; set JWT_SECRET",
+	}, cfg.Warnings())
+
+	cfg.Auth.JWTSecret = "short"
+	cfg.Server.Environment = EnvironmentProduction
+	cfg.Database.SSLMode = "require"
+	cfg.Session.CookieSecure = false
+	assert.Equal(t, []string{
+		"auth.jwt_secret is shorter than 32 bytes",
+		"session.cookie_secure is off in production, so session cookies are sent over plain http",
+	}, cfg.Warnings())
+}
+
+func TestLoadFile_CORSOriginsDependOnEnvironment(t *testing.T) {
+	cfg, err := LoadFile("")
+	require.NoError(t, err)
+	assert.Equal(t, devOrigins, cfg.CORS.AllowedOrigins)
+
+	t.Setenv("ENVIRONMENT", EnvironmentProduction)
+	t.Setenv("JWT_SECRET", "a-production-secret-of-at-least-32-bytes")
+	cfg, err = LoadFile("")
+	require.NoError(t, err)
+	assert.Empty(t, cfg.CORS.AllowedOrigins)
+
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com")
+	cfg, err = LoadFile("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://app.example.com"}, cfg.CORS.AllowedOrigins)
+}
+
+func TestAppConfig_RedactedHidesSecrets(t *testing.T) {
+	cfg := defaults()
+	cfg.Database.Password = "hunter2"
+	cfg.Auth.JWTSecret = "jwt-secret"
+	cfg.Database.Host = "db.internal"
+
+	out := cfg.Redacted()
+	assert.NotContains(t, out, "hunter2")
+	assert.NotContains(t, out, "jwt-secret")
+	assert.Contains(t, out, "database.password: [REDACTED]\n")
+	assert.Contains(t, out, "database.host: db.internal\n")
+	// Unset secrets are shown as empty so a missing value is visible
+	assert.Contains(t, out, "ai.api_key: \n")
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"fmt"
+	"sync"
+
+	"metadatatool/internal/pkg/config"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// DialectorFunc creates a GORM dialector for a DSN
+type DialectorFunc func(dsn string) gorm.Dialector
+
+var (
+	dialectorsMu sync.RWMutex
+	dialectors   = map[string]DialectorFunc{
+		config.DriverPostgres: func(dsn string) gorm.Dialector {
+			return postgres.New(postgres.Config{DSN: dsn, PreferSimpleProtocol: true})
+		},
+	}
+)
+
+// RegisterDialector makes a database driver available to Open. Drivers that
+// pull in heavy dependencies register themselves from files behind a build
+// tag.
+func RegisterDialector(driver string, fn DialectorFunc) {
+	dialectorsMu.Lock()
+	defer dialectorsMu.Unlock()
+	dialectors[driver] = fn
+}
+
+// Open connects to the database selected by cfg.Driver
+func Open(cfg config.DatabaseConfig, gormConfig *gorm.Config) (*gorm.DB, error) {
+	driver := cfg.Driver
+	if driver == "" {
+		driver = config.DriverPostgres
+	}
+
+	dialectorsMu.RLock()
+	fn, ok := dialectors[driver]
+	dialectorsMu.RUnlock()
+	if !ok {
+		if driver == config.DriverSQLite {
+			return nil, fmt.Errorf("sqlite support is not compiled in, rebuild with -tags sqlite")
+		}
+		return nil, fmt.Errorf("unknown database driver %q", driver)
+	}
+
+	if gormConfig == nil {
+		gormConfig = &gorm.Config{}
+	}
+	db, err := gorm.Open(fn(cfg.DSN()), gormConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return db, nil
+}
+
+// IsPostgres reports whether db talks to PostgreSQL
+func IsPostgres(db *gorm.DB) bool {
+	return db.Dialector.Name() == "postgres"
+}
@@ -0,0 +1,23 @@
+package database
+
+import (
+	"testing"
+
+	"metadatatool/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestOpen_SelectsDialectorByDriver(t *testing.T) {
+	gormConfig := &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: logger.Discard}
+
+	db, err := Open(config.DatabaseConfig{Host: "localhost", Port: 5432, DBName: "test"}, gormConfig)
+	require.NoError(t, err)
+	assert.True(t, IsPostgres(db))
+
+	_, err = Open(config.DatabaseConfig{Driver: "oracle"}, gormConfig)
+	assert.ErrorContains(t, err, "unknown database driver")
+}
@@ -0,0 +1,18 @@
+//go:build sqlite
+
+package database
+
+import (
+	"metadatatool/internal/pkg/config"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// The SQLite driver is pure Go so the demo binary needs no cgo toolchain.
+// WAL mode lets the API serve reads while a write is in progress.
+func init() {
+	RegisterDialector(config.DriverSQLite, func(dsn string) gorm.Dialector {
+		return sqlite.Open(dsn + "?_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	})
+}
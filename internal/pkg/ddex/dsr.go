@@ -0,0 +1,160 @@
+package ddex
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DSR flat files are tab-separated. Every line is a record whose first
+// field names its type, and lines starting with "#" followed by a record
+// type name the columns of that type, e.g. "#HEAD\tMessageId\t...".
+// ParseDSR needs these definition rows and reads the columns by name, so
+// it does not depend on the profile or version of the report.
+const (
+	dsrHeaderRecord     = "HEAD"
+	dsrMessageID        = "MessageId"
+	dsrSenderName       = "SenderName"
+	dsrUsageStartDate   = "UsageStartDate"
+	dsrUsageEndDate     = "UsageEndDate"
+	dsrTerritory        = "TerritoryOfUse"
+	dsrISRC             = "ISRC"
+	dsrDateLayout       = "2006-01-02"
+	dsrMaxLineSize      = 1 << 20
+	dsrDefinitionPrefix = "#"
+)
+
+// dsrUsageColumns are the columns counting the uses of a resource, in
+// order of preference
+var dsrUsageColumns = []string{"NumberOfStreams", "Usages", "NumberOfUsages"}
+
+// DSRReport is a parsed DSR usage report
+type DSRReport struct {
+	MessageID  string
+	SenderName string
+	UsageStart time.Time
+	UsageEnd   time.Time
+	Usages     []DSRUsage
+}
+
+// DSRUsage counts the uses of one resource in one territory
+type DSRUsage struct {
+	ISRC      string
+	Territory string
+	Plays     int64
+}
+
+// ParseDSR reads a DSR flat file. Usage records are those with an ISRC and
+// a usage count; their territory is their own TerritoryOfUse or that of the
+// summary record before them. ISRCs are returned without hyphens.
+func ParseDSR(r io.Reader) (*DSRReport, error) {
+	report := &DSRReport{}
+	columns := make(map[string]map[string]int)
+	var territory string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), dsrMaxLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+
+		if strings.HasPrefix(fields[0], dsrDefinitionPrefix) {
+			recordType := strings.TrimPrefix(fields[0], dsrDefinitionPrefix)
+			defs := make(map[string]int, len(fields)-1)
+			for i, name := range fields[1:] {
+				defs[strings.TrimSpace(name)] = i + 1
+			}
+			columns[recordType] = defs
+			continue
+		}
+
+		defs, ok := columns[fields[0]]
+		if !ok {
+			return nil, fmt.Errorf("line %d: record type %q has no definition row", line, fields[0])
+		}
+		value := func(name string) string {
+			if i, ok := defs[name]; ok && i < len(fields) {
+				return strings.TrimSpace(fields[i])
+			}
+			return ""
+		}
+
+		if fields[0] == dsrHeaderRecord {
+			if err := parseDSRHeader(report, value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			continue
+		}
+
+		recordTerritory := value(dsrTerritory)
+		isrc := value(dsrISRC)
+		if isrc == "" {
+			// Summary records set the territory of the records after them
+			if recordTerritory != "" {
+				territory = recordTerritory
+			}
+			continue
+		}
+		if recordTerritory == "" {
+			recordTerritory = territory
+		}
+
+		count := ""
+		for _, name := range dsrUsageColumns {
+			if count = value(name); count != "" {
+				break
+			}
+		}
+		if count == "" {
+			continue
+		}
+		plays, err := strconv.ParseInt(count, 10, 64)
+		if err != nil || plays < 0 {
+			return nil, fmt.Errorf("line %d: invalid usage count %q", line, count)
+		}
+		if recordTerritory == "" {
+			return nil, fmt.Errorf("line %d: usage of %s has no territory", line, isrc)
+		}
+		report.Usages = append(report.Usages, DSRUsage{
+			ISRC:      strings.ToUpper(strings.ReplaceAll(isrc, "-", "")),
+			Territory: strings.ToUpper(recordTerritory),
+			Plays:     plays,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	if report.MessageID == "" {
+		return nil, fmt.Errorf("report has no %s record", dsrHeaderRecord)
+	}
+	return report, nil
+}
+
+// parseDSRHeader reads the header record into report
+func parseDSRHeader(report *DSRReport, value func(string) string) error {
+	report.MessageID = value(dsrMessageID)
+	if report.MessageID == "" {
+		return fmt.Errorf("header has no %s", dsrMessageID)
+	}
+	report.SenderName = value(dsrSenderName)
+
+	start, err := time.Parse(dsrDateLayout, value(dsrUsageStartDate))
+	if err != nil {
+		return fmt.Errorf("invalid %s %q", dsrUsageStartDate, value(dsrUsageStartDate))
+	}
+	report.UsageStart = start
+	report.UsageEnd = start
+	if end := value(dsrUsageEndDate); end != "" {
+		if report.UsageEnd, err = time.Parse(dsrDateLayout, end); err != nil {
+			return fmt.Errorf("invalid %s %q", dsrUsageEndDate, end)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package ddex
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDSR = "#HEAD\tMessageVersion\tMessageId\tSenderName\tUsageStartDate\tUsageEndDate\n" +
+	"#SY02\tSummaryRecordId\tCommercialModel\tTerritoryOfUse\n" +
+	"#AS02\tBlockId\tResourceReference\tISRC\tTitle\tNumberOfStreams\n" +
+	"#RU01\tBlockId\tISRC\tTerritoryOfUse\tUsages\n" +
+	"#FOOT\tNumberOfLines\n" +
+	"HEAD\tdsr/3.0\tMSG-42\tSpotify\t2024-03-01\t2024-03-31\n" +
+	"SY02\t1\tSubscriptionModel\tSE\n" +
+	"AS02\tB1\tR1\tse-abc-24-00001\tSong\t1200\n" +
+	"AS02\tB2\tR2\tSEABC2400002\tOther\t0\n" +
+	"RU01\tB3\tSEABC2400001\tno\t30\n" +
+	"FOOT\t9\n"
+
+func TestParseDSR(t *testing.T) {
+	report, err := ParseDSR(strings.NewReader(testDSR))
+	require.NoError(t, err)
+
+	assert.Equal(t, "MSG-42", report.MessageID)
+	assert.Equal(t, "Spotify", report.SenderName)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), report.UsageStart)
+	assert.Equal(t, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), report.UsageEnd)
+	assert.Equal(t, []DSRUsage{
+		{ISRC: "SEABC2400001", Territory: "SE", Plays: 1200},
+		{ISRC: "SEABC2400002", Territory: "SE", Plays: 0},
+		{ISRC: "SEABC2400001", Territory: "NO", Plays: 30},
+	}, report.Usages)
+}
+
+func TestParseDSR_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		report string
+		want   string
+	}{
+		{"undefined record", "HEAD\tMSG-1\n", "has no definition row"},
+		{"no header", "#AS02\tISRC\tTerritoryOfUse\tUsages\nAS02\tX\tSE\t1\n", "no HEAD record"},
+		{"bad date", "#HEAD\tMessageId\tUsageStartDate\nHEAD\tM\t03/2024\n", "invalid UsageStartDate"},
+		{"bad count", "#HEAD\tMessageId\tUsageStartDate\n#AS02\tISRC\tTerritoryOfUse\tUsages\nHEAD\tM\t2024-03-01\nAS02\tX\tSE\tmany\n", "invalid usage count"},
+		{"no territory", "#HEAD\tMessageId\tUsageStartDate\n#AS02\tISRC\tUsages\nHEAD\tM\t2024-03-01\nAS02\tX\t1\n", "has no territory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDSR(strings.NewReader(tt.report))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
@@ -46,23 +46,32 @@ type ExperimentConfig struct {
 type Qwen2Config struct {
 	APIKey                string
 	Endpoint              string
+	Model                 string // Model recorded with each enrichment
+	ModelVersion          string // Version of Model, bumped when the deployed model changes
 	TimeoutSeconds        int
 	MinConfidence         float64
 	MaxConcurrentRequests int
 	RetryAttempts         int
 	RetryBackoffSeconds   int
+	RequestsPerSecond     int  // Rate limit for Qwen2 API requests
+	AudioFeatures         bool // Send the track's audio analysis along with the audio
+	MaxPromptTokens       int  // Prompt size the features are cut down to; zero is unlimited
 }
 
 // OpenAIConfig holds configuration for OpenAI service
 type OpenAIConfig struct {
 	APIKey                string
 	Endpoint              string
+	Model                 string // Model to prompt, such as gpt-4o-mini
+	ModelVersion          string // Version of Model, bumped when the pinned snapshot changes
 	TimeoutSeconds        int
 	MinConfidence         float64
 	MaxConcurrentRequests int
 	RetryAttempts         int
 	RetryBackoffSeconds   int
-	RequestsPerSecond     int // Rate limit for OpenAI API requests
+	RequestsPerSecond     int  // Rate limit for OpenAI API requests
+	AudioFeatures         bool // Prompt with the track's audio analysis, not only its text metadata
+	MaxPromptTokens       int  // Prompt size the metadata and features are cut down to; zero is unlimited
 }
 
 // AIMetadata holds AI-generated metadata for a track
@@ -82,6 +91,7 @@ type AIMetrics struct {
 	SuccessCount   int64
 	FailureCount   int64
 	LastSuccess    time.Time
+	LastFailure    time.Time
 	LastError      error
 	AverageLatency time.Duration
 }
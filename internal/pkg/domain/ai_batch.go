@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrAIBatchNotFound is returned when an AI batch does not exist
+var ErrAIBatchNotFound = errors.New("AI batch not found")
+
+// AIBatchStatus is the state of an AI batch
+type AIBatchStatus string
+
+const (
+	// AIBatchPending batches are queued or running at the provider
+	AIBatchPending AIBatchStatus = "pending"
+	// AIBatchCompleted batches have had their results saved to the tracks
+	AIBatchCompleted AIBatchStatus = "completed"
+	// AIBatchFailed batches were refused or failed as a whole
+	AIBatchFailed AIBatchStatus = "failed"
+	// AIBatchExpired batches did not finish within the provider's window;
+	// the results of the requests that finished are saved
+	AIBatchExpired AIBatchStatus = "expired"
+	// AIBatchCancelled batches were cancelled; the results of the requests
+	// that finished are saved
+	AIBatchCancelled AIBatchStatus = "cancelled"
+)
+
+// AIBatch is a set of tracks enriched through a provider's batch API.
+// Batches cost less than enriching tracks one by one but take up to a day,
+// so their results are saved to the tracks once the provider is done.
+type AIBatch struct {
+	ID       string     `json:"id" gorm:"primaryKey"`
+	Provider AIProvider `json:"provider" gorm:"not null"`
+	// ProviderBatchID is the ID of the batch at the provider
+	ProviderBatchID string        `json:"provider_batch_id" gorm:"not null"`
+	Status          AIBatchStatus `json:"status" gorm:"index;not null"`
+	TrackIDs        []string      `json:"track_ids" gorm:"serializer:json"`
+	// Enriched and Failed count the tracks saved and the tracks without a
+	// result once the batch is done
+	Enriched    int        `json:"enriched"`
+	Failed      int        `json:"failed"`
+	Error       string     `json:"error,omitempty"`
+	RequestedBy string     `json:"requested_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// TableName returns the table name for AI batches
+func (AIBatch) TableName() string {
+	return "ai_batches"
+}
+
+// AIBatchRepository stores AI batches
+type AIBatchRepository interface {
+	Create(ctx context.Context, batch *AIBatch) error
+	Update(ctx context.Context, batch *AIBatch) error
+	// GetByID returns ErrAIBatchNotFound for unknown batches
+	GetByID(ctx context.Context, id string) (*AIBatch, error)
+	// List returns the latest batches, newest first
+	List(ctx context.Context, limit int) ([]*AIBatch, error)
+	// ListPending returns the batches still running at the provider
+	ListPending(ctx context.Context) ([]*AIBatch, error)
+}
+
+// AIBatchProgress is the state of a batch at the provider
+type AIBatchProgress struct {
+	Status AIBatchStatus
+	// Error holds why a failed batch failed
+	Error string
+}
+
+// AIBatchProvider enriches tracks through a provider's batch API
+type AIBatchProvider interface {
+	// SubmitBatch submits an enrichment request for each track and returns
+	// the provider's ID of the batch
+	SubmitBatch(ctx context.Context, tracks []*Track) (string, error)
+	// CheckBatch returns the state of a batch
+	CheckBatch(ctx context.Context, batchID string) (*AIBatchProgress, error)
+	// BatchResults applies the results of a batch that is done to the
+	// tracks it was submitted for, and returns why each track without a
+	// result has none
+	BatchResults(ctx context.Context, batchID string, tracks []*Track) (map[string]error, error)
+	// CancelBatch asks the provider to stop a batch. Requests that finished
+	// keep their results.
+	CancelBatch(ctx context.Context, batchID string) error
+}
+
+// AIBatchProviders returns the batch API of the providers that have one
+type AIBatchProviders interface {
+	BatchProvider(provider AIProvider) (AIBatchProvider, bool)
+}
+
+// EnrichmentMerger merges an enrichment result into a track, keeping the
+// fields a person has edited as enrichment does
+type EnrichmentMerger interface {
+	Merge(ctx context.Context, track, enriched *Track)
+}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrReenrichmentRunning is returned when re-enrichment is started
+	// while a run is in progress
+	ErrReenrichmentRunning = errors.New("re-enrichment is already running")
+	// ErrReenrichmentNotFound is returned when no re-enrichment has run yet
+	ErrReenrichmentNotFound = errors.New("no re-enrichment has run yet")
+)
+
+// AIModelVersion identifies the model and prompt an enrichment was made
+// with
+type AIModelVersion struct {
+	Provider      AIProvider `json:"provider,omitempty"`
+	Model         string     `json:"model"`
+	Version       string     `json:"version,omitempty"`
+	PromptVersion string     `json:"prompt_version,omitempty"`
+}
+
+// String formats the version as model@version/prompt, leaving out the parts
+// that are not set
+func (v AIModelVersion) String() string {
+	s := v.Model
+	if v.Version != "" {
+		s += "@" + v.Version
+	}
+	if v.PromptVersion != "" {
+		s += "/" + v.PromptVersion
+	}
+	return s
+}
+
+// Matches reports whether an enrichment made with other was made with
+// this model version. The provider is not compared, as enrichments do not
+// record it.
+func (v AIModelVersion) Matches(other AIModelVersion) bool {
+	return v.Model == other.Model && v.Version == other.Version && v.PromptVersion == other.PromptVersion
+}
+
+// ModelVersionOf returns the model version a track was last enriched with,
+// and false when it has not been enriched
+func ModelVersionOf(track *Track) (AIModelVersion, bool) {
+	if track.Metadata.AI == nil {
+		return AIModelVersion{}, false
+	}
+	return AIModelVersion{
+		Model:         track.Metadata.AI.Model,
+		Version:       track.Metadata.AI.Version,
+		PromptVersion: track.Metadata.AI.PromptVersion,
+	}, true
+}
+
+// ModelVersionKey joins model versions into one key, so results can be
+// cached per set of models
+func ModelVersionKey(versions []AIModelVersion) string {
+	parts := make([]string, len(versions))
+	for i, v := range versions {
+		parts[i] = v.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// AIModelVersioner reports the model versions the AI providers enrich with
+type AIModelVersioner interface {
+	// ModelVersions returns the current model version of every provider
+	ModelVersions() []AIModelVersion
+}
+
+// AIModelCoverage counts the tracks last enriched with a model version
+type AIModelCoverage struct {
+	AIModelVersion
+	Tracks int64 `json:"tracks"`
+	// Share is the share of the catalog's tracks, enriched or not
+	Share float64 `json:"share"`
+	// Current is whether a provider enriches with this version now
+	Current bool `json:"current"`
+}
+
+// AIModelCoverageReport counts the catalog's tracks by the model version
+// they were last enriched with
+type AIModelCoverageReport struct {
+	Tracks     int64 `json:"tracks"`
+	Unenriched int64 `json:"unenriched"`
+	// Outdated is the number of tracks enriched with a version no provider
+	// enriches with now
+	Outdated int64             `json:"outdated"`
+	Current  []AIModelVersion  `json:"current"`
+	Models   []AIModelCoverage `json:"models"`
+}
+
+// EnrichedTrack is a track with the model version it was last enriched
+// with
+type EnrichedTrack struct {
+	TrackID string
+	AIModelVersion
+}
+
+// AIModelRepository reads the model versions tracks were enriched with
+type AIModelRepository interface {
+	// ModelCoverage counts the tracks that are not deleted by model
+	// version, filling in Tracks, Unenriched and the counts of Models
+	ModelCoverage(ctx context.Context) (*AIModelCoverageReport, error)
+	// EnrichedTracks returns up to limit enriched tracks with IDs after
+	// afterID, ordered by ID
+	EnrichedTracks(ctx context.Context, afterID string, limit int) ([]EnrichedTrack, error)
+}
+
+// ReenrichmentStatus is the state of a re-enrichment run
+type ReenrichmentStatus string
+
+const (
+	ReenrichmentRunning   ReenrichmentStatus = "running"
+	ReenrichmentCompleted ReenrichmentStatus = "completed"
+	ReenrichmentCancelled ReenrichmentStatus = "cancelled"
+	ReenrichmentFailed    ReenrichmentStatus = "failed"
+)
+
+// ReenrichmentRun reports the progress of enriching again the tracks
+// enriched with outdated model versions
+type ReenrichmentRun struct {
+	Status      ReenrichmentStatus `json:"status"`
+	StartedAt   time.Time          `json:"started_at"`
+	CompletedAt *time.Time         `json:"completed_at,omitempty"`
+	// Current lists the model versions tracks are enriched with again
+	Current  []AIModelVersion `json:"current"`
+	Enriched int              `json:"enriched"`
+	Failed   int              `json:"failed"`
+	Error    string           `json:"error,omitempty"`
+}
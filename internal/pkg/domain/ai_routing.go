@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AIRoutingPolicy decides which provider an enrichment goes to first
+type AIRoutingPolicy string
+
+const (
+	// AIRoutingExperiment splits enrichments between the providers by the
+	// experiment traffic share
+	AIRoutingExperiment AIRoutingPolicy = "experiment"
+	// AIRoutingLatency sends enrichments to the provider that answered its
+	// last health probe fastest
+	AIRoutingLatency AIRoutingPolicy = "latency"
+)
+
+// ValidateAIProvider checks that provider is a known AI provider. The empty
+// provider is valid where it means no provider.
+func ValidateAIProvider(provider AIProvider) error {
+	switch provider {
+	case "", AIProviderQwen2, AIProviderOpenAI:
+		return nil
+	}
+	return fmt.Errorf("%w: unknown AI provider %q", ErrInvalidInput, provider)
+}
+
+// AIHealthChecker is implemented by the AI services that can be probed
+// with a cheap request, without enriching a track
+type AIHealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// AIProviderHealth reports the health probes of an AI provider
+type AIProviderHealth struct {
+	Provider AIProvider `json:"provider"`
+	Healthy  bool       `json:"healthy"`
+	// LatencyMs is the duration of the last successful probe
+	LatencyMs           int64      `json:"latency_ms"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastChecked         *time.Time `json:"last_checked,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// AIRouting reports how enrichments are routed between the AI providers
+type AIRouting struct {
+	Policy AIRoutingPolicy `json:"policy"`
+	// Forced is the provider all traffic is sent to, if any
+	Forced AIProvider `json:"forced,omitempty"`
+	// Order lists the providers in the order an enrichment tries them,
+	// before the experiment split
+	Order     []AIProvider       `json:"order"`
+	Providers []AIProviderHealth `json:"providers"`
+}
+
+// AIRouter routes AI requests between the providers by their health
+type AIRouter interface {
+	// Routing reports the routing in effect
+	Routing() *AIRouting
+	// CheckHealth probes every provider now
+	CheckHealth(ctx context.Context)
+	// ForceProvider sends all traffic to provider, skipping health and
+	// fallback; the empty provider routes by health again
+	ForceProvider(provider AIProvider) error
+}
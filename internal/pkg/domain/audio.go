@@ -98,53 +98,68 @@ type AudioProcessResult struct {
 // AudioAnalysis represents the results of audio analysis
 type AudioAnalysis struct {
 	// Temporal features
-	BPM           float64
-	TimeSignature string
-	Key           string
-	Mode          string
-	Tempo         float64
-	BeatsPerBar   int
+	BPM           float64   `json:"bpm"`
+	TimeSignature string    `json:"time_signature,omitempty"`
+	Key           string    `json:"key,omitempty"`
+	Mode          string    `json:"mode,omitempty"`
+	Tempo         float64   `json:"tempo,omitempty"`
+	BeatsPerBar   int       `json:"beats_per_bar,omitempty"`
+	Beats         []float64 `json:"beats,omitempty"` // Beat positions in seconds
 
 	// Spectral features
-	Loudness      float64
-	Energy        float64
-	Brightness    float64
-	Timbre        float64
-	SpectralFlux  float64
-	SpectralRoll  float64
-	SpectralSlope float64
+	Loudness      float64 `json:"loudness"`
+	Energy        float64 `json:"energy"`
+	Brightness    float64 `json:"brightness,omitempty"`
+	Timbre        float64 `json:"timbre,omitempty"`
+	SpectralFlux  float64 `json:"spectral_flux,omitempty"`
+	SpectralRoll  float64 `json:"spectral_roll,omitempty"`
+	SpectralSlope float64 `json:"spectral_slope,omitempty"`
 
 	// Perceptual features
-	Danceability float64
-	Valence      float64
-	Arousal      float64
-	Complexity   float64
-	Intensity    float64
-	Mood         string
+	Danceability float64 `json:"danceability"`
+	Valence      float64 `json:"valence,omitempty"`
+	Arousal      float64 `json:"arousal,omitempty"`
+	Complexity   float64 `json:"complexity,omitempty"`
+	Intensity    float64 `json:"intensity,omitempty"`
+	Mood         string  `json:"mood,omitempty"`
 
 	// Segments and structure
-	Segments     []AudioSegment
-	Transitions  []float64
-	SectionCount int
+	Segments     []AudioSegment `json:"segments,omitempty"`
+	Sections     []AudioSection `json:"sections,omitempty"`
+	Transitions  []float64      `json:"transitions,omitempty"`
+	SectionCount int            `json:"section_count,omitempty"`
 
 	// Analysis metadata
-	AnalyzedAt   time.Time
-	Duration     float64
-	SampleCount  int64
-	WindowSize   int
-	HopSize      int
-	SampleRate   int
-	AnalyzerInfo string
+	AnalyzedAt   time.Time `json:"analyzed_at"`
+	Duration     float64   `json:"duration,omitempty"`
+	SampleCount  int64     `json:"sample_count,omitempty"`
+	WindowSize   int       `json:"window_size,omitempty"`
+	HopSize      int       `json:"hop_size,omitempty"`
+	SampleRate   int       `json:"sample_rate,omitempty"`
+	AnalyzerInfo string    `json:"analyzer_info,omitempty"`
 }
 
 // AudioSegment represents a segment in the audio file
 type AudioSegment struct {
-	Start      float64
-	Duration   float64
-	Loudness   float64
-	Timbre     []float64
-	Pitches    []float64
-	Confidence float64
+	Start      float64   `json:"start"`
+	Duration   float64   `json:"duration"`
+	Loudness   float64   `json:"loudness"`
+	Timbre     []float64 `json:"timbre,omitempty"`
+	Pitches    []float64 `json:"pitches,omitempty"`
+	Confidence float64   `json:"confidence"`
+}
+
+// AudioSection is a stretch of audio with a steady tempo and key, such as
+// one track of a DJ mix. Long audio is analyzed in windows, and windows
+// alike are joined into sections, giving a timeline of the tempo and key.
+type AudioSection struct {
+	Start    float64 `json:"start"`
+	Duration float64 `json:"duration"`
+	BPM      float64 `json:"bpm"`
+	Key      string  `json:"key,omitempty"`
+	Mode     string  `json:"mode,omitempty"`
+	Camelot  string  `json:"camelot,omitempty"`
+	Energy   float64 `json:"energy"`
 }
 
 // AudioService handles audio file operations
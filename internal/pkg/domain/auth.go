@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"context"
+	"time"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -67,25 +70,55 @@ type Claims struct {
 	Email       string       `json:"email"`
 	Role        Role         `json:"role"`
 	Permissions []Permission `json:"permissions"`
+	// TokenType is TokenTypeAccess or TokenTypeRefresh
+	TokenType string `json:"typ,omitempty"`
+	// FamilyID links every token descended from the same login
+	FamilyID string `json:"fid,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// Token types carried in Claims.TokenType
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// NewClaims creates a new Claims instance with standard JWT claims
+func NewClaims(userID string, role Role, permissions []Permission, expiresAt time.Time) *Claims {
+	return &Claims{
+		UserID:      userID,
+		Role:        role,
+		Permissions: permissions,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+}
+
 // TokenPair represents an access and refresh token pair
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+
+	// FamilyID, RefreshTokenID and RefreshExpiresAt describe the refresh
+	// token for rotation tracking
+	FamilyID         string    `json:"-"`
+	RefreshTokenID   string    `json:"-"`
+	RefreshExpiresAt time.Time `json:"-"`
 }
 
-// AuthService handles authentication and authorization
+// AuthService handles authentication
 type AuthService interface {
+	// GenerateToken creates an access token for user
+	GenerateToken(ctx context.Context, user *User) (string, error)
+
 	// GenerateTokens creates a new pair of access and refresh tokens
 	GenerateTokens(user *User) (*TokenPair, error)
 
 	// ValidateToken validates and parses a JWT token
-	ValidateToken(token string) (*Claims, error)
-
-	// RefreshToken validates a refresh token and generates new token pair
-	RefreshToken(refreshToken string) (*TokenPair, error)
+	ValidateToken(ctx context.Context, token string) (*Claims, error)
 
 	// HashPassword creates a bcrypt hash of the password
 	HashPassword(password string) (string, error)
@@ -95,10 +128,10 @@ type AuthService interface {
 
 	// GenerateAPIKey creates a new API key
 	GenerateAPIKey() (string, error)
+}
 
-	// HasPermission checks if a role has a specific permission
-	HasPermission(role Role, permission Permission) bool
-
-	// GetPermissions returns all permissions for a role
-	GetPermissions(role Role) []Permission
+// TokenFamilyIssuer is implemented by auth services that can issue tokens
+// into an existing refresh token family, which refresh token rotation needs
+type TokenFamilyIssuer interface {
+	GenerateTokensInFamily(user *User, familyID string) (*TokenPair, error)
 }
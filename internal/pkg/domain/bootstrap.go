@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrAlreadyBootstrapped is returned when bootstrapping an environment that
+// already has a different admin
+var ErrAlreadyBootstrapped = errors.New("environment already bootstrapped")
+
+// BootstrapMarker is the single row recording that an environment was
+// bootstrapped and by which admin
+type BootstrapMarker struct {
+	ID         int    `gorm:"primaryKey;autoIncrement:false"`
+	AdminEmail string `gorm:"size:255;not null"`
+	CreatedAt  time.Time
+}
+
+// TableName returns the table name for the bootstrap marker
+func (BootstrapMarker) TableName() string {
+	return "bootstrap_marker"
+}
+
+// BootstrapRepository guards the creation of the first admin in the
+// database, so that one bootstrap wins however many replicas serve them
+type BootstrapRepository interface {
+	// AdminExists reports whether any user has the admin role
+	AdminExists(ctx context.Context) (bool, error)
+	// Claim marks the environment bootstrapped by adminEmail, or returns
+	// ErrAlreadyBootstrapped when it already was. In a unit of work, other
+	// claims wait until the unit of work ends.
+	Claim(ctx context.Context, adminEmail string) error
+}
+
+// Statuses of a provisioned resource
+const (
+	ResourceCreated  = "created"
+	ResourceExisting = "existing"
+)
+
+// ProvisionedResource reports one resource a bootstrap ensured
+type ProvisionedResource struct {
+	// Kind is admin, label, bucket or topic
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Status is created, or existing when it was there already
+	Status string `json:"status" enums:"created,existing"`
+}
+
+// BootstrapResult lists the resources a bootstrap ensured
+type BootstrapResult struct {
+	Resources []ProvisionedResource `json:"resources"`
+}
+
+// Add records a resource, created or found existing
+func (r *BootstrapResult) Add(kind, name string, created bool) {
+	status := ResourceExisting
+	if created {
+		status = ResourceCreated
+	}
+	r.Resources = append(r.Resources, ProvisionedResource{Kind: kind, Name: name, Status: status})
+}
+
+// BucketProvisioner is implemented by storage that can create its buckets
+type BucketProvisioner interface {
+	// EnsureBuckets creates the buckets that do not exist. It returns
+	// whether each bucket, by name, was created.
+	EnsureBuckets(ctx context.Context) (map[string]bool, error)
+}
+
+// TopicProvisioner is implemented by queues that can create their topics
+type TopicProvisioner interface {
+	// EnsureTopic creates a topic if it does not exist and reports whether
+	// it was created
+	EnsureTopic(ctx context.Context, name string) (bool, error)
+}
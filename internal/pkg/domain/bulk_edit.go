@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// BulkEditPatch describes the changes applied to every track in a bulk edit
+type BulkEditPatch struct {
+	SetLabel   *string  `json:"set_label,omitempty"`
+	SetGenre   *string  `json:"set_genre,omitempty"`
+	AppendTags []string `json:"append_tags,omitempty"`
+}
+
+// IsEmpty reports whether the patch contains no operations
+func (p *BulkEditPatch) IsEmpty() bool {
+	return p.SetLabel == nil && p.SetGenre == nil && len(p.AppendTags) == 0
+}
+
+// Apply applies the patch to a track and returns the resulting field changes.
+// Fields that already hold the target value are left untouched.
+func (p *BulkEditPatch) Apply(track *Track) []FieldChange {
+	var changes []FieldChange
+
+	if p.SetLabel != nil && track.Label() != *p.SetLabel {
+		if track.Metadata.Additional.CustomFields == nil {
+			track.Metadata.Additional.CustomFields = make(map[string]string)
+		}
+		changes = append(changes, FieldChange{Field: "label", OldValue: track.Label(), NewValue: *p.SetLabel})
+		track.SetLabel(*p.SetLabel)
+	}
+
+	if p.SetGenre != nil && track.Genre() != *p.SetGenre {
+		changes = append(changes, FieldChange{Field: "genre", OldValue: track.Genre(), NewValue: *p.SetGenre})
+		track.SetGenre(*p.SetGenre)
+	}
+
+	for _, tag := range p.AppendTags {
+		if track.HasTag(tag) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: "tags", NewValue: tag})
+		track.Metadata.Additional.Tags = append(track.Metadata.Additional.Tags, tag)
+	}
+
+	return changes
+}
+
+// FieldChange describes a single field modification
+type FieldChange struct {
+	Field    string      `json:"field"`
+	OldValue interface{} `json:"old_value,omitempty"`
+	NewValue interface{} `json:"new_value,omitempty"`
+}
+
+// BulkEditRequest selects tracks either by ID or by metadata filter
+type BulkEditRequest struct {
+	TrackIDs []string               `json:"track_ids,omitempty"`
+	Filter   map[string]interface{} `json:"filter,omitempty"`
+	Patch    BulkEditPatch          `json:"patch"`
+	DryRun   bool                   `json:"dry_run"`
+}
+
+// BulkEditResultStatus represents the outcome of a bulk edit for one track
+type BulkEditResultStatus string
+
+const (
+	BulkEditResultUpdated   BulkEditResultStatus = "updated"
+	BulkEditResultUnchanged BulkEditResultStatus = "unchanged"
+	BulkEditResultNotFound  BulkEditResultStatus = "not_found"
+	BulkEditResultFailed    BulkEditResultStatus = "failed"
+)
+
+// BulkEditResult reports the outcome of a bulk edit for a single track
+type BulkEditResult struct {
+	TrackID string               `json:"track_id"`
+	Status  BulkEditResultStatus `json:"status"`
+	Changes []FieldChange        `json:"changes,omitempty"`
+	Error   string               `json:"error,omitempty"`
+}
+
+// BulkEditJob tracks the progress of a bulk edit running in the background
+type BulkEditJob struct {
+	ID          string           `json:"id"`
+	Status      JobStatus        `json:"status"`
+	Request     BulkEditRequest  `json:"request"`
+	Total       int              `json:"total"`
+	Processed   int              `json:"processed"`
+	Results     []BulkEditResult `json:"results"`
+	Error       string           `json:"error,omitempty"`
+	CreatedBy   string           `json:"created_by,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	StartedAt   *time.Time       `json:"started_at,omitempty"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+}
+
+// BulkEditJobRepository persists bulk edit jobs and their results
+type BulkEditJobRepository interface {
+	// Save creates or replaces a bulk edit job
+	Save(ctx context.Context, job *BulkEditJob) error
+
+	// GetByID retrieves a bulk edit job by ID
+	GetByID(ctx context.Context, id string) (*BulkEditJob, error)
+}
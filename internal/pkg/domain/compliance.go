@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// DeletedUserID takes the place of an erased user's ID wherever the catalog
+// refers to them, so their changes stay attributed to a person without
+// identifying them
+const DeletedUserID = "deleted-user"
+
+// AuditEntry is a change a user made to a track field, as recorded in the
+// track's provenance
+type AuditEntry struct {
+	TrackID   string           `json:"track_id"`
+	Field     string           `json:"field"`
+	Source    ProvenanceSource `json:"source"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// UserDataExport holds the personal data kept about a user
+type UserDataExport struct {
+	User         *User        `json:"user"`
+	Sessions     []*Session   `json:"sessions"`
+	AuditEntries []AuditEntry `json:"audit_entries"`
+	ExportedAt   time.Time    `json:"exported_at"`
+}
+
+// WriteArchive writes the export as a zip archive holding one JSON document
+// per kind of data
+func (e *UserDataExport) WriteArchive(w io.Writer) error {
+	archive := zip.NewWriter(w)
+	documents := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", e.User},
+		{"sessions.json", e.Sessions},
+		{"audit_entries.json", e.AuditEntries},
+	}
+	for _, doc := range documents {
+		file, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     doc.name,
+			Method:   zip.Deflate,
+			Modified: e.ExportedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", doc.name, err)
+		}
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(doc.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", doc.name, err)
+		}
+	}
+	return archive.Close()
+}
+
+// AuditEntries returns the fields of t last changed by userID, oldest
+// change first
+func (t *Track) AuditEntries(userID string) []AuditEntry {
+	var entries []AuditEntry
+	for field, p := range t.Metadata.Provenance {
+		if p.UpdatedBy == userID {
+			entries = append(entries, AuditEntry{
+				TrackID:   t.ID,
+				Field:     field,
+				Source:    p.Source,
+				UpdatedAt: p.UpdatedAt,
+			})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].UpdatedAt.Equal(entries[j].UpdatedAt) {
+			return entries[i].UpdatedAt.Before(entries[j].UpdatedAt)
+		}
+		return entries[i].Field < entries[j].Field
+	})
+	return entries
+}
+
+// AnonymizeUser replaces userID with DeletedUserID in t's provenance and
+// reports whether anything changed. The field values are left alone.
+func (t *Track) AnonymizeUser(userID string) bool {
+	changed := false
+	for field, p := range t.Metadata.Provenance {
+		if p.UpdatedBy == userID {
+			p.UpdatedBy = DeletedUserID
+			t.Metadata.Provenance[field] = p
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ConfigBundleVersion is the format version of the configuration bundles
+// written by this release
+const ConfigBundleVersion = 1
+
+// ErrInvalidSignature is returned when a configuration bundle was not
+// signed with the key of this environment or was changed after signing
+var ErrInvalidSignature = errors.New("invalid bundle signature")
+
+// ConfigBundle holds the configuration of an environment that can be
+// promoted to another, such as from staging to production. It is signed
+// with the promotion key shared by the environments.
+type ConfigBundle struct {
+	Version int `json:"version"`
+	// Environment is the environment the bundle was exported from
+	Environment string    `json:"environment"`
+	ExportedAt  time.Time `json:"exported_at"`
+	ExportedBy  string    `json:"exported_by,omitempty"`
+	// Settings are the runtime settings, absent when the environment has
+	// none
+	Settings *RuntimeSettings `json:"settings,omitempty"`
+	Tags     []*Tag           `json:"tags"`
+	TagRules []*TagRule       `json:"tag_rules"`
+	Labels   []*LabelConfig   `json:"labels"`
+	// Signature is the hex HMAC-SHA256 of the bundle without it
+	Signature string `json:"signature,omitempty"`
+}
+
+// LabelConfig holds the configuration of one label
+type LabelConfig struct {
+	LabelID        string                   `json:"label_id"`
+	CustomFields   []*CustomFieldDefinition `json:"custom_fields"`
+	ImportMappings []*ImportMapping         `json:"import_mappings"`
+}
+
+// Validate checks the bundle's format and every entity in it
+func (b *ConfigBundle) Validate() []ValidationError {
+	var errs []ValidationError
+	if b.Version != ConfigBundleVersion {
+		errs = append(errs, ValidationError{Field: "version", Code: "invalid",
+			Message: fmt.Sprintf("unsupported bundle version %d, expected %d", b.Version, ConfigBundleVersion)})
+	}
+	if b.Settings != nil {
+		if err := b.Settings.Validate(); err != nil {
+			errs = append(errs, ValidationError{Field: "settings", Code: "invalid", Message: err.Error()})
+		}
+	}
+	for i, tag := range b.Tags {
+		if tag == nil || NormalizeTag(tag.Name) == "" {
+			errs = append(errs, ValidationError{Field: fmt.Sprintf("tags[%d].name", i), Code: "required", Message: "name is required"})
+		}
+	}
+	for i, rule := range b.TagRules {
+		if rule == nil || rule.ID == "" {
+			errs = append(errs, ValidationError{Field: fmt.Sprintf("tag_rules[%d].id", i), Code: "required", Message: "id is required"})
+			continue
+		}
+		errs = append(errs, prefixErrors(fmt.Sprintf("tag_rules[%d]", i), rule.Validate())...)
+	}
+	for i, label := range b.Labels {
+		prefix := fmt.Sprintf("labels[%d]", i)
+		if label == nil || label.LabelID == "" {
+			errs = append(errs, ValidationError{Field: prefix + ".label_id", Code: "required", Message: "label_id is required"})
+			continue
+		}
+		for j, field := range label.CustomFields {
+			if field == nil {
+				errs = append(errs, ValidationError{Field: fmt.Sprintf("%s.custom_fields[%d]", prefix, j), Code: "required", Message: "custom field is required"})
+				continue
+			}
+			errs = append(errs, prefixErrors(fmt.Sprintf("%s.custom_fields[%d]", prefix, j), field.Validate())...)
+		}
+		for j, mapping := range label.ImportMappings {
+			if mapping == nil {
+				errs = append(errs, ValidationError{Field: fmt.Sprintf("%s.import_mappings[%d]", prefix, j), Code: "required", Message: "import mapping is required"})
+				continue
+			}
+			if mapping.ID == "" {
+				errs = append(errs, ValidationError{Field: fmt.Sprintf("%s.import_mappings[%d].id", prefix, j), Code: "required", Message: "id is required"})
+			}
+			errs = append(errs, prefixErrors(fmt.Sprintf("%s.import_mappings[%d]", prefix, j), mapping.Validate())...)
+		}
+	}
+	return errs
+}
+
+func prefixErrors(prefix string, errs []ValidationError) []ValidationError {
+	for i := range errs {
+		errs[i].Field = prefix + "." + errs[i].Field
+	}
+	return errs
+}
+
+// Actions of a configuration import on an entity
+const (
+	ConfigCreated   = "created"
+	ConfigUpdated   = "updated"
+	ConfigUnchanged = "unchanged"
+)
+
+// ConfigChange reports what importing a bundle does to one entity
+type ConfigChange struct {
+	// Kind is settings, tag, tag_rule, custom_field or import_mapping
+	Kind    string `json:"kind"`
+	LabelID string `json:"label_id,omitempty"`
+	// Name identifies the entity: a tag, custom field or mapping name, or
+	// a rule ID
+	Name   string `json:"name"`
+	Action string `json:"action" enums:"created,updated,unchanged"`
+}
+
+// ConfigImportResult lists the changes of a configuration import
+type ConfigImportResult struct {
+	// DryRun is set when the changes were computed but not written
+	DryRun bool `json:"dry_run"`
+	// Source is the environment the bundle was exported from
+	Source  string         `json:"source"`
+	Changes []ConfigChange `json:"changes"`
+}
+
+// Add records what the import does to an entity
+func (r *ConfigImportResult) Add(kind, labelID, name, action string) {
+	r.Changes = append(r.Changes, ConfigChange{Kind: kind, LabelID: labelID, Name: name, Action: action})
+}
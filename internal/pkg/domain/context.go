@@ -7,7 +7,14 @@ import (
 type contextKey string
 
 const (
-	userContextKey contextKey = "user"
+	userContextKey        contextKey = "user"
+	mergePolicyContextKey contextKey = "merge_policy"
+	primaryReadsKey       contextKey = "primary_reads"
+	forceRefreshKey       contextKey = "force_refresh"
+	tenantContextKey      contextKey = "tenant"
+	trackFieldsKey        contextKey = "track_fields"
+	trackSortKey          contextKey = "track_sort"
+	dryRunKey             contextKey = "dry_run"
 )
 
 // WithUser adds a user to the context
@@ -20,3 +27,115 @@ func UserFromContext(ctx context.Context) (*User, bool) {
 	user, ok := ctx.Value(userContextKey).(*User)
 	return user, ok
 }
+
+// sessionUserIDKey is the key under which the session middleware keeps the
+// ID of the signed-in user on gin contexts, which handlers pass on as the
+// context of their calls
+const sessionUserIDKey = "user_id"
+
+// ActorFromContext returns the ID of the user ctx acts for: the user of ctx,
+// or the signed-in user of a request. It is empty for work the system does
+// on its own.
+func ActorFromContext(ctx context.Context) string {
+	if user, ok := UserFromContext(ctx); ok && user != nil {
+		return user.ID
+	}
+	id, _ := ctx.Value(sessionUserIDKey).(string)
+	return id
+}
+
+// WithMergePolicy overrides the merge policy for automated updates made with ctx
+func WithMergePolicy(ctx context.Context, policy MergePolicy) context.Context {
+	return context.WithValue(ctx, mergePolicyContextKey, policy)
+}
+
+// MergePolicyFromContext retrieves a merge policy override from the context
+func MergePolicyFromContext(ctx context.Context) (MergePolicy, bool) {
+	policy, ok := ctx.Value(mergePolicyContextKey).(MergePolicy)
+	return policy, ok
+}
+
+// WithPrimaryReads makes repository reads made with ctx go to the primary
+// database instead of a replica. Use it when reading data that is about to be
+// written back, so a lagging replica cannot cause a spurious version conflict.
+func WithPrimaryReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadsKey, true)
+}
+
+// PrimaryReadsFromContext reports whether reads made with ctx must use the
+// primary database
+func PrimaryReadsFromContext(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryReadsKey).(bool)
+	return primary
+}
+
+// WithTrackFields makes track reads with ctx load only the columns holding
+// fields. The other fields of the tracks read are left empty.
+func WithTrackFields(ctx context.Context, fields []string) context.Context {
+	return context.WithValue(ctx, trackFieldsKey, fields)
+}
+
+// TrackFieldsFromContext returns the track fields selected for ctx, nil for
+// all of them
+func TrackFieldsFromContext(ctx context.Context) []string {
+	fields, _ := ctx.Value(trackFieldsKey).([]string)
+	return fields
+}
+
+// WithTrackSort makes track listings with ctx ordered by sort
+func WithTrackSort(ctx context.Context, sort []TrackSortKey) context.Context {
+	return context.WithValue(ctx, trackSortKey, sort)
+}
+
+// TrackSortFromContext returns the order of track listings for ctx, nil
+// for the storage order
+func TrackSortFromContext(ctx context.Context) []TrackSortKey {
+	sort, _ := ctx.Value(trackSortKey).([]TrackSortKey)
+	return sort
+}
+
+// WithForceRefresh makes AI enrichment with ctx skip cached results and call
+// the AI provider again
+func WithForceRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceRefreshKey, true)
+}
+
+// ForceRefreshFromContext reports whether cached enrichment results must be
+// ignored for ctx
+func ForceRefreshFromContext(ctx context.Context) bool {
+	force, _ := ctx.Value(forceRefreshKey).(bool)
+	return force
+}
+
+// WithTenant records the tenant that requests made with ctx are made for.
+// Shared resources such as the AI providers are divided fairly between tenants.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey, tenant)
+}
+
+// TenantFromContext returns the tenant of ctx, falling back to the user's
+// company and then the user's ID. It is empty for requests without either.
+func TenantFromContext(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantContextKey).(string); ok && tenant != "" {
+		return tenant
+	}
+	if user, ok := UserFromContext(ctx); ok && user != nil {
+		if user.Company != "" {
+			return user.Company
+		}
+		return user.ID
+	}
+	return ""
+}
+
+// WithDryRun makes operations done with ctx compute their effect without
+// committing it
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey, true)
+}
+
+// DryRunFromContext reports whether operations done with ctx are dry runs
+func DryRunFromContext(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey).(bool)
+	return dryRun
+}
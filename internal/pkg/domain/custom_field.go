@@ -0,0 +1,332 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrCustomFieldNotFound is returned when a label defines no such field
+var ErrCustomFieldNotFound = errors.New("custom field not found")
+
+// CustomFieldType is the type of the values of a custom field. Values are
+// stored as text in their canonical form, so that equal values compare
+// equal in searches.
+type CustomFieldType string
+
+const (
+	CustomFieldString  CustomFieldType = "string"
+	CustomFieldInteger CustomFieldType = "integer"
+	CustomFieldNumber  CustomFieldType = "number"
+	CustomFieldBoolean CustomFieldType = "boolean"
+	// CustomFieldDate values are dates written as YYYY-MM-DD
+	CustomFieldDate CustomFieldType = "date"
+	CustomFieldURL  CustomFieldType = "url"
+	// CustomFieldEnum values are one of the definition's Values
+	CustomFieldEnum CustomFieldType = "enum"
+)
+
+// customFieldNamePattern matches custom field names, which appear in JSON
+// paths and search queries
+var customFieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// builtinCustomFields are the custom field keys backing track fields
+var builtinCustomFields = map[string]bool{"iswc": true, "label": true, "territory": true}
+
+// CustomFieldDefinition declares a custom field of a label's tracks
+type CustomFieldDefinition struct {
+	LabelID     string          `json:"label_id" gorm:"primaryKey"`
+	Name        string          `json:"name" gorm:"primaryKey"`
+	Type        CustomFieldType `json:"type" gorm:"not null"`
+	Description string          `json:"description,omitempty"`
+	// Required makes tracks without a value invalid
+	Required bool `json:"required,omitempty"`
+	// Pattern is a regular expression string values must match
+	Pattern string `json:"pattern,omitempty"`
+	// Min and Max bound integer and number values, and the length of
+	// string values
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+	// Values lists the allowed values of enum fields
+	Values    []string  `json:"values,omitempty" gorm:"serializer:json"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for custom field definitions
+func (CustomFieldDefinition) TableName() string {
+	return "custom_field_definitions"
+}
+
+// CustomFieldRepository stores the custom field definitions of labels
+type CustomFieldRepository interface {
+	// Save creates or replaces a definition
+	Save(ctx context.Context, def *CustomFieldDefinition) error
+	// Get returns a label's definition, or ErrCustomFieldNotFound
+	Get(ctx context.Context, labelID, name string) (*CustomFieldDefinition, error)
+	// ListByLabel returns a label's definitions ordered by name
+	ListByLabel(ctx context.Context, labelID string) ([]*CustomFieldDefinition, error)
+	// Delete removes a label's definition. Values stored on tracks are kept.
+	Delete(ctx context.Context, labelID, name string) error
+}
+
+// Validate checks the definition itself
+func (d *CustomFieldDefinition) Validate() []ValidationError {
+	var errs []ValidationError
+	switch {
+	case !customFieldNamePattern.MatchString(d.Name):
+		errs = append(errs, ValidationError{Field: "name", Message: "name must start with a lowercase letter and hold only lowercase letters, digits and underscores"})
+	case builtinCustomFields[d.Name]:
+		errs = append(errs, ValidationError{Field: "name", Message: fmt.Sprintf("%s is a track field", d.Name)})
+	}
+	switch d.Type {
+	case CustomFieldString, CustomFieldInteger, CustomFieldNumber, CustomFieldBoolean, CustomFieldDate, CustomFieldURL:
+		if len(d.Values) > 0 {
+			errs = append(errs, ValidationError{Field: "values", Message: "only enum fields have values"})
+		}
+	case CustomFieldEnum:
+		if len(d.Values) == 0 {
+			errs = append(errs, ValidationError{Field: "values", Code: "required", Message: "enum fields need values"})
+		}
+	default:
+		errs = append(errs, ValidationError{Field: "type", Message: fmt.Sprintf("unknown type %q", d.Type)})
+	}
+	if d.Pattern != "" {
+		if d.Type != CustomFieldString {
+			errs = append(errs, ValidationError{Field: "pattern", Message: "only string fields have a pattern"})
+		} else if _, err := regexp.Compile(d.Pattern); err != nil {
+			errs = append(errs, ValidationError{Field: "pattern", Message: "pattern is not a valid regular expression"})
+		}
+	}
+	if (d.Min != nil || d.Max != nil) && d.Type != CustomFieldString && d.Type != CustomFieldInteger && d.Type != CustomFieldNumber {
+		errs = append(errs, ValidationError{Field: "min", Message: "only string, integer and number fields have bounds"})
+	}
+	if d.Min != nil && d.Max != nil && *d.Min > *d.Max {
+		errs = append(errs, ValidationError{Field: "max", Message: "max is below min"})
+	}
+	return errs
+}
+
+// Canonical checks a value of the field and returns its canonical text.
+// value is text as stored on tracks, or a JSON value as sent in searches.
+func (d *CustomFieldDefinition) Canonical(value interface{}) (string, error) {
+	text, ok := value.(string)
+	if !ok {
+		switch v := value.(type) {
+		case bool:
+			text = strconv.FormatBool(v)
+		case float64:
+			text = strconv.FormatFloat(v, 'f', -1, 64)
+		case int:
+			text = strconv.Itoa(v)
+		default:
+			return "", fmt.Errorf("%v is not a %s", value, d.Type)
+		}
+	}
+	text = strings.TrimSpace(text)
+
+	switch d.Type {
+	case CustomFieldString:
+		if d.Pattern != "" && !regexp.MustCompile(d.Pattern).MatchString(text) {
+			return "", fmt.Errorf("%q does not match %s", text, d.Pattern)
+		}
+		if err := d.checkBounds(float64(len([]rune(text))), "characters"); err != nil {
+			return "", err
+		}
+	case CustomFieldInteger:
+		n, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("%q is not an integer", text)
+		}
+		if err := d.checkBounds(float64(n), ""); err != nil {
+			return "", err
+		}
+		text = strconv.FormatInt(n, 10)
+	case CustomFieldNumber:
+		n, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a number", text)
+		}
+		if err := d.checkBounds(n, ""); err != nil {
+			return "", err
+		}
+		text = strconv.FormatFloat(n, 'f', -1, 64)
+	case CustomFieldBoolean:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return "", fmt.Errorf("%q is not true or false", text)
+		}
+		text = strconv.FormatBool(b)
+	case CustomFieldDate:
+		if _, err := time.Parse(ImportDateFormat, text); err != nil {
+			return "", fmt.Errorf("%q is not a date written as YYYY-MM-DD", text)
+		}
+	case CustomFieldURL:
+		if u, err := url.Parse(text); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", fmt.Errorf("%q is not an http or https URL", text)
+		}
+	case CustomFieldEnum:
+		for _, allowed := range d.Values {
+			if text == allowed {
+				return text, nil
+			}
+		}
+		return "", fmt.Errorf("%q is not one of %s", text, strings.Join(d.Values, ", "))
+	}
+	return text, nil
+}
+
+// checkBounds checks n against Min and Max; unit names what is counted
+func (d *CustomFieldDefinition) checkBounds(n float64, unit string) error {
+	if unit != "" {
+		unit = " " + unit
+	}
+	if d.Min != nil && n < *d.Min {
+		return fmt.Errorf("must be at least %s%s", strconv.FormatFloat(*d.Min, 'f', -1, 64), unit)
+	}
+	if d.Max != nil && n > *d.Max {
+		return fmt.Errorf("must be at most %s%s", strconv.FormatFloat(*d.Max, 'f', -1, 64), unit)
+	}
+	return nil
+}
+
+// Typed returns the canonical text of a value as a value of the field's
+// type, such as a float64 for number fields
+func (d *CustomFieldDefinition) Typed(text string) interface{} {
+	switch d.Type {
+	case CustomFieldInteger:
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return n
+		}
+	case CustomFieldNumber:
+		if n, err := strconv.ParseFloat(text, 64); err == nil {
+			return n
+		}
+	case CustomFieldBoolean:
+		if b, err := strconv.ParseBool(text); err == nil {
+			return b
+		}
+	}
+	return text
+}
+
+// CustomFieldSchema is the set of custom fields a label defines
+type CustomFieldSchema []*CustomFieldDefinition
+
+// CustomFieldPath prefixes the JSON paths of the custom fields of a track
+// in validation errors
+const CustomFieldPath = "metadata.additional.customFields."
+
+// Field returns the definition of a field, or nil
+func (s CustomFieldSchema) Field(name string) *CustomFieldDefinition {
+	for _, d := range s {
+		if d.Name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// Apply enforces the schema on the custom fields of a track: required
+// fields must have a value, values must suit their field and are rewritten
+// in canonical form, and fields the schema does not define are refused.
+// Without definitions every custom field is accepted. The errors name the
+// JSON paths of the fields.
+func (s CustomFieldSchema) Apply(track *Track) []ValidationError {
+	if len(s) == 0 {
+		return nil
+	}
+	var errs []ValidationError
+	fields := track.Metadata.Additional.CustomFields
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if builtinCustomFields[name] {
+			continue
+		}
+		def := s.Field(name)
+		if def == nil {
+			errs = append(errs, ValidationError{Field: CustomFieldPath + name, Message: fmt.Sprintf("the label defines no custom field %q", name)})
+			continue
+		}
+		if fields[name] == "" {
+			continue
+		}
+		canonical, err := def.Canonical(fields[name])
+		if err != nil {
+			errs = append(errs, ValidationError{Field: CustomFieldPath + name, Message: err.Error()})
+			continue
+		}
+		fields[name] = canonical
+	}
+	for _, def := range s {
+		if def.Required && fields[def.Name] == "" {
+			errs = append(errs, ValidationError{Field: CustomFieldPath + def.Name, Code: "required", Message: def.Name + " is required"})
+		}
+	}
+	return errs
+}
+
+// Typed returns the custom fields of a track the schema defines, as values
+// of their types
+func (s CustomFieldSchema) Typed(track *Track) map[string]interface{} {
+	typed := make(map[string]interface{})
+	for _, def := range s {
+		if text, ok := track.Metadata.Additional.CustomFields[def.Name]; ok && text != "" {
+			typed[def.Name] = def.Typed(text)
+		}
+	}
+	return typed
+}
+
+// CustomFieldPrefix prefixes custom field names where they sit beside
+// track fields, as in import mappings, search terms and export columns:
+// "custom.mood_score"
+const CustomFieldPrefix = "custom."
+
+// SearchTerms turns custom field values of a search into search query
+// terms, keyed with CustomFieldPrefix. Values of defined fields are
+// written in canonical form so that they match the stored values; with a
+// schema, fields it does not define are refused.
+func (s CustomFieldSchema) SearchTerms(values map[string]interface{}) (map[string]interface{}, []ValidationError) {
+	terms := make(map[string]interface{}, len(values))
+	var errs []ValidationError
+	for name, value := range values {
+		field := "custom_fields." + name
+		if !IsCustomFieldName(name) && !builtinCustomFields[name] {
+			errs = append(errs, ValidationError{Field: field, Message: fmt.Sprintf("%q is not a custom field name", name)})
+			continue
+		}
+		def := s.Field(name)
+		if def == nil {
+			if len(s) > 0 && !builtinCustomFields[name] {
+				errs = append(errs, ValidationError{Field: field, Message: fmt.Sprintf("the label defines no custom field %q", name)})
+				continue
+			}
+			// Untyped fields are matched as written
+			def = &CustomFieldDefinition{Type: CustomFieldString}
+		}
+		canonical, err := def.Canonical(value)
+		if err != nil {
+			errs = append(errs, ValidationError{Field: field, Message: err.Error()})
+			continue
+		}
+		terms[CustomFieldPrefix+name] = canonical
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return terms, errs
+}
+
+// IsCustomFieldName reports whether name is a valid custom field name
+func IsCustomFieldName(name string) bool {
+	return customFieldNamePattern.MatchString(name)
+}
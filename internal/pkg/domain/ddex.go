@@ -10,6 +10,13 @@ const (
 	DDEXERN43 = "4.3"
 )
 
+// DDEXUpdateMessage marks an ERN message that changes an earlier delivery,
+// such as a takedown
+const DDEXUpdateMessage = "UpdateMessage"
+
+// DDEXWorldwide is the territory code of deals that apply everywhere
+const DDEXWorldwide = "Worldwide"
+
 // DDEXService handles DDEX format operations
 type DDEXService interface {
 	// ValidateTrack validates a track's metadata against DDEX schema
@@ -26,9 +33,11 @@ type DDEXService interface {
 type ERNMessage struct {
 	XMLName       xml.Name      `xml:"ernMessage"`
 	MessageHeader MessageHeader `xml:"messageHeader"`
-	ResourceList  ResourceList  `xml:"resourceList"`
-	ReleaseList   ReleaseList   `xml:"releaseList"`
-	DealList      DealList      `xml:"dealList"`
+	// UpdateIndicator is DDEXUpdateMessage for updates and empty otherwise
+	UpdateIndicator string       `xml:"updateIndicator,omitempty"`
+	ResourceList    ResourceList `xml:"resourceList"`
+	ReleaseList     ReleaseList  `xml:"releaseList"`
+	DealList        DealList     `xml:"dealList"`
 }
 
 // MessageHeader represents the DDEX message header
@@ -109,12 +118,16 @@ type Deal struct {
 // Territory represents a DDEX territory
 type Territory struct {
 	TerritoryCode string `xml:"territoryCode"`
+	// ExcludedTerritoryCodes are left out of a worldwide deal
+	ExcludedTerritoryCodes []string `xml:"excludedTerritoryCode,omitempty"`
 }
 
 // DealTerms represents DDEX deal terms
 type DealTerms struct {
 	CommercialModelType string `xml:"commercialModelType"`
 	Usage               Usage  `xml:"usage"`
+	// TakeDown withdraws the release from the deal's territories
+	TakeDown bool `xml:"takeDown,omitempty"`
 }
 
 // Usage represents DDEX usage terms
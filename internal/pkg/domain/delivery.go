@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"time"
+)
+
+// ErrDeliveryNotFound is returned when no delivery was sent in a package
+var ErrDeliveryNotFound = errors.New("delivery not found")
+
+// DeliveryStatus is the state of a track's delivery to a DSP
+type DeliveryStatus string
+
+const (
+	// DeliveryStatusSent means the package was sent and no acknowledgement
+	// has arrived yet
+	DeliveryStatusSent DeliveryStatus = "sent"
+	// DeliveryStatusAcknowledged means the DSP accepted the package
+	DeliveryStatusAcknowledged DeliveryStatus = "acknowledged"
+	// DeliveryStatusFailed means the DSP refused the package
+	DeliveryStatusFailed DeliveryStatus = "failed"
+)
+
+// IsValid reports whether s is a known delivery status
+func (s DeliveryStatus) IsValid() bool {
+	switch s {
+	case DeliveryStatusSent, DeliveryStatusAcknowledged, DeliveryStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// DeliveryKind is what a delivery instructs the DSP to do
+type DeliveryKind string
+
+const (
+	// DeliveryKindRelease makes a track available
+	DeliveryKindRelease DeliveryKind = "release"
+	// DeliveryKindTakedown removes a track from the DSP
+	DeliveryKindTakedown DeliveryKind = "takedown"
+	// DeliveryKindTerritoryBlock removes a track from some territories
+	DeliveryKindTerritoryBlock DeliveryKind = "territory_block"
+)
+
+// IsValid reports whether k is a known delivery kind
+func (k DeliveryKind) IsValid() bool {
+	switch k {
+	case DeliveryKindRelease, DeliveryKindTakedown, DeliveryKindTerritoryBlock:
+		return true
+	default:
+		return false
+	}
+}
+
+// Delivery records that a track was sent to a DSP, such as Spotify or
+// Apple Music, in a delivery package
+type Delivery struct {
+	ID        string `json:"id" gorm:"primaryKey"`
+	DSP       string `json:"dsp" gorm:"index;not null"`
+	TrackID   string `json:"track_id" gorm:"index;not null"`
+	ReleaseID string `json:"release_id,omitempty" gorm:"index"`
+	// PackageID is the message ID of the package, which acknowledgements
+	// refer to
+	PackageID string `json:"package_id" gorm:"index;not null"`
+	// PackageFormat names the package standard, such as "ERN 4.3"
+	PackageFormat string       `json:"package_format,omitempty"`
+	Kind          DeliveryKind `json:"kind" gorm:"not null;default:release"`
+	// Territories are the ISO 3166-1 codes a territory block applies to
+	Territories []string       `json:"territories,omitempty" gorm:"serializer:json"`
+	Status      DeliveryStatus `json:"status" gorm:"index;not null"`
+	// Error holds why the DSP refused the package
+	Error          string     `json:"error,omitempty"`
+	DeliveredAt    time.Time  `json:"delivered_at"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName returns the table name for deliveries
+func (Delivery) TableName() string {
+	return "deliveries"
+}
+
+// DeliveryFilter selects deliveries; empty fields match every delivery
+type DeliveryFilter struct {
+	DSP       string
+	TrackID   string
+	ReleaseID string
+	Kind      DeliveryKind
+	Status    DeliveryStatus
+	Offset    int
+	Limit     int
+}
+
+// DeliveryRepository stores deliveries
+type DeliveryRepository interface {
+	// Create stores new deliveries
+	Create(ctx context.Context, deliveries []*Delivery) error
+	// ListByPackage returns the deliveries sent in a package
+	ListByPackage(ctx context.Context, packageID string) ([]*Delivery, error)
+	// List returns the deliveries matching filter, newest first
+	List(ctx context.Context, filter DeliveryFilter) ([]*Delivery, error)
+	// UpdateStatus sets the status and error of every delivery of a package
+	// and returns how many there were
+	UpdateStatus(ctx context.Context, packageID string, status DeliveryStatus, errText string, at time.Time) (int64, error)
+}
+
+// AcknowledgementFileOK is the message status of an accepted package
+const AcknowledgementFileOK = "FileOK"
+
+// DeliveryAcknowledgement is a DDEX acknowledgement a DSP sends for a
+// received package. Every status other than FileOK, such as
+// ResourceCorrupt or SchemaValidationError, refuses the package.
+type DeliveryAcknowledgement struct {
+	XMLName          xml.Name         `xml:"ftpAcknowledgementMessage"`
+	MessageHeader    MessageHeader    `xml:"messageHeader"`
+	AcknowledgedFile AcknowledgedFile `xml:"acknowledgedFile"`
+	MessageStatus    string           `xml:"messageStatus"`
+	ErrorText        []string         `xml:"errorText"`
+}
+
+// AcknowledgedFile identifies the package an acknowledgement is about
+type AcknowledgedFile struct {
+	MessageID string `xml:"messageId"`
+	FileName  string `xml:"fileName"`
+}
+
+// AcknowledgementResult reports the deliveries an acknowledgement updated
+type AcknowledgementResult struct {
+	PackageID  string         `json:"package_id"`
+	Status     DeliveryStatus `json:"status"`
+	Error      string         `json:"error,omitempty"`
+	Deliveries int64          `json:"deliveries"`
+}
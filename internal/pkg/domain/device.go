@@ -0,0 +1,29 @@
+package domain
+
+import "context"
+
+// LoginNovelty describes what was unfamiliar about a login
+type LoginNovelty struct {
+	// FirstLogin is set when the user had no known devices yet
+	FirstLogin bool `json:"first_login"`
+	NewDevice  bool `json:"new_device"`
+	NewCountry bool `json:"new_country"`
+}
+
+// Suspicious reports whether the login should be brought to the user's
+// attention. A user's very first login is never suspicious.
+func (n LoginNovelty) Suspicious() bool {
+	return !n.FirstLogin && (n.NewDevice || n.NewCountry)
+}
+
+// DeviceRegistry remembers the devices and countries a user has signed in from
+type DeviceRegistry interface {
+	// Remember records a login and reports which parts of it were new
+	Remember(ctx context.Context, userID, deviceID, country string) (LoginNovelty, error)
+}
+
+// LoginAlertNotifier tells a user about a login from an unfamiliar device or
+// country
+type LoginAlertNotifier interface {
+	SendNewLoginAlert(ctx context.Context, user *User, session *Session, novelty LoginNovelty) error
+}
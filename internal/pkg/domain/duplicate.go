@@ -0,0 +1,230 @@
+package domain
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DuplicateThreshold is the score from which two tracks are reported as
+// probable duplicates
+const DuplicateThreshold = 0.85
+
+// Reasons two tracks are taken for duplicates
+const (
+	DuplicateReasonISRC          = "isrc"
+	DuplicateReasonTitle         = "title"
+	DuplicateReasonArtist        = "artist"
+	DuplicateReasonArtistSoundex = "artist_soundex"
+)
+
+// DuplicateCandidate is an existing track that a track probably duplicates
+type DuplicateCandidate struct {
+	TrackID string `json:"track_id"`
+	Title   string `json:"title"`
+	Artist  string `json:"artist"`
+	ISRC    string `json:"isrc,omitempty"`
+	// Score is between 0 and 1, where 1 is a certain duplicate
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+var (
+	// titleVersionPattern matches bracketed remarks such as "(Remastered
+	// 2011)" and "[feat. X]", which do not make a different recording title
+	titleVersionPattern = regexp.MustCompile(`\s*[\(\[][^\)\]]*[\)\]]`)
+	// featuringPattern matches featured artist credits up to the end
+	featuringPattern = regexp.MustCompile(`(?i)\s+(feat\.?|ft\.?|featuring)\s+.*$`)
+)
+
+// stripMarks removes diacritics, so that "Beyoncé" matches "Beyonce"
+var stripMarks = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// NormalizeTitle reduces a title to the words that identify the recording:
+// lower case, without diacritics, bracketed remarks, featured artists or
+// punctuation
+func NormalizeTitle(title string) string {
+	title = titleVersionPattern.ReplaceAllString(title, "")
+	return normalizeWords(featuringPattern.ReplaceAllString(title, ""))
+}
+
+// NormalizeArtist reduces an artist name to its words: lower case, without
+// diacritics, featured artists, a leading "the" or punctuation
+func NormalizeArtist(artist string) string {
+	artist = normalizeWords(featuringPattern.ReplaceAllString(artist, ""))
+	return strings.TrimPrefix(artist, "the ")
+}
+
+// normalizeWords lower-cases s, strips its diacritics and punctuation and
+// separates the remaining words by single spaces
+func normalizeWords(s string) string {
+	if stripped, _, err := transform.String(stripMarks, s); err == nil {
+		s = stripped
+	}
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return unicode.ToLower(r)
+		case r == '&':
+			return r
+		case r == '\'' || r == '’' || r == '.':
+			// "Guns N' Roses" and "R.E.M." keep their words together
+			return -1
+		}
+		return ' '
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Levenshtein returns the number of single-character edits between a and b
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// Similarity returns how alike a and b are, from 0 for nothing in common to
+// 1 for equal strings, based on their Levenshtein distance
+func Similarity(a, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(Levenshtein(a, b))/float64(longest)
+}
+
+// soundexCodes are the American Soundex digits of the consonants
+var soundexCodes = map[rune]byte{
+	'b': '1', 'f': '1', 'p': '1', 'v': '1',
+	'c': '2', 'g': '2', 'j': '2', 'k': '2', 'q': '2', 's': '2', 'x': '2', 'z': '2',
+	'd': '3', 't': '3',
+	'l': '4',
+	'm': '5', 'n': '5',
+	'r': '6',
+}
+
+// Soundex returns the American Soundex code of s, such as "R163" for
+// "Robert" and "Rupert", or "" when s has no letters. Only the letters a
+// to z are coded; normalize s first.
+func Soundex(s string) string {
+	code := make([]byte, 0, 4)
+	var last byte
+	for _, r := range strings.ToLower(s) {
+		if r < 'a' || r > 'z' {
+			continue
+		}
+		digit, coded := soundexCodes[r]
+		if len(code) == 0 {
+			code = append(code, byte(unicode.ToUpper(r)))
+			last = digit
+			continue
+		}
+		switch {
+		case !coded && r != 'h' && r != 'w':
+			// Vowels separate equal digits; h and w do not
+			last = 0
+		case coded && digit != last:
+			code = append(code, digit)
+			last = digit
+		}
+		if len(code) == 4 {
+			break
+		}
+	}
+	if len(code) == 0 {
+		return ""
+	}
+	for len(code) < 4 {
+		code = append(code, '0')
+	}
+	return string(code)
+}
+
+// MatchDuplicate scores how probably track and existing are the same
+// recording. Equal ISRCs make a certain duplicate; otherwise the
+// normalized titles and artists are compared by edit distance, with
+// artists that sound alike counting as close matches.
+func MatchDuplicate(track, existing *Track) DuplicateCandidate {
+	candidate := DuplicateCandidate{
+		TrackID: existing.ID,
+		Title:   existing.Title(),
+		Artist:  existing.Artist(),
+		ISRC:    existing.ISRC(),
+		Reasons: []string{},
+	}
+	if track.ISRC() != "" && strings.EqualFold(track.ISRC(), existing.ISRC()) {
+		candidate.Score = 1
+		candidate.Reasons = append(candidate.Reasons, DuplicateReasonISRC)
+		return candidate
+	}
+
+	title := Similarity(NormalizeTitle(track.Title()), NormalizeTitle(existing.Title()))
+	artistA, artistB := NormalizeArtist(track.Artist()), NormalizeArtist(existing.Artist())
+	artist := Similarity(artistA, artistB)
+	if artist < DuplicateThreshold {
+		if soundex := Soundex(artistA); soundex != "" && soundex == Soundex(artistB) {
+			artist = DuplicateThreshold
+			candidate.Reasons = append(candidate.Reasons, DuplicateReasonArtistSoundex)
+		}
+	} else {
+		candidate.Reasons = append(candidate.Reasons, DuplicateReasonArtist)
+	}
+	if title >= DuplicateThreshold {
+		candidate.Reasons = append(candidate.Reasons, DuplicateReasonTitle)
+	}
+	// The title weighs more: one artist records many songs, but few
+	// artists record the same song
+	candidate.Score = 0.6*title + 0.4*artist
+	return candidate
+}
+
+// FindDuplicates returns the tracks of existing that track probably
+// duplicates, most probable first. track itself is skipped.
+func FindDuplicates(track *Track, existing []*Track) []DuplicateCandidate {
+	var found []DuplicateCandidate
+	for _, other := range existing {
+		if other.ID != "" && other.ID == track.ID {
+			continue
+		}
+		candidate := MatchDuplicate(track, other)
+		// Both the title and the artist must match; a high score from a
+		// perfect title alone is a cover, not a duplicate
+		probable := candidate.Score >= DuplicateThreshold &&
+			hasReason(candidate.Reasons, DuplicateReasonTitle) &&
+			(hasReason(candidate.Reasons, DuplicateReasonArtist) || hasReason(candidate.Reasons, DuplicateReasonArtistSoundex))
+		if probable || hasReason(candidate.Reasons, DuplicateReasonISRC) {
+			found = append(found, candidate)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Score > found[j].Score })
+	return found
+}
+
+func hasReason(reasons []string, reason string) bool {
+	for _, r := range reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
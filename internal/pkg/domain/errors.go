@@ -5,21 +5,35 @@ import "errors"
 var (
 	// Authentication errors
 	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrInvalidToken       = errors.New("invalid token")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrInvalidAPIKey      = errors.New("invalid API key")
 	ErrInvalidPassword    = errors.New("invalid password")
 	ErrUnauthorized       = errors.New("unauthorized")
 	ErrForbidden          = errors.New("forbidden")
 
 	// User errors
 	ErrUserNotFound = errors.New("user not found")
-	ErrEmailExists  = errors.New("email already exists")
+	ErrEmailTaken   = errors.New("email already taken")
+
+	// Track errors
+	ErrTrackNotFound           = errors.New("track not found")
+	ErrInvalidStatusTransition = errors.New("invalid status transition")
+	ErrTrackLocked             = errors.New("track is being processed")
+
+	// Storage errors
+	ErrQuotaExceeded = errors.New("storage quota exceeded")
 
 	// Session errors
-	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrSessionExpired     = errors.New("session expired")
+	ErrMaxSessionsReached = errors.New("maximum number of sessions reached")
 
 	// Validation errors
 	ErrInvalidInput = errors.New("invalid input")
 
+	// Concurrency errors
+	ErrVersionConflict = errors.New("version conflict")
+
 	// System errors
 	ErrInternal = errors.New("internal error")
 )
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrGoldenTrackNotFound is returned when a track is not in the golden
+	// dataset
+	ErrGoldenTrackNotFound = errors.New("golden track not found")
+	// ErrEvaluationNotFound is returned when no evaluation has run yet
+	ErrEvaluationNotFound = errors.New("no evaluation has run yet")
+)
+
+// Fields scored by an evaluation
+const (
+	EvaluationFieldGenre = "genre"
+	EvaluationFieldMood  = "mood"
+	EvaluationFieldBPM   = "bpm"
+	EvaluationFieldKey   = "key"
+)
+
+// GoldenTrack holds the values a person has confirmed for a track. AI
+// providers are scored against them; fields left empty are not scored.
+type GoldenTrack struct {
+	TrackID   string    `json:"track_id" gorm:"primaryKey"`
+	Genre     string    `json:"genre,omitempty"`
+	Mood      string    `json:"mood,omitempty"`
+	BPM       float64   `json:"bpm,omitempty"`
+	Key       string    `json:"key,omitempty"`
+	LabeledBy string    `json:"labeled_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for golden tracks
+func (GoldenTrack) TableName() string {
+	return "golden_tracks"
+}
+
+// GoldenDatasetRepository stores the golden dataset
+type GoldenDatasetRepository interface {
+	// Save adds a track to the dataset or replaces its values
+	Save(ctx context.Context, track *GoldenTrack) error
+	// Delete removes a track from the dataset, or returns
+	// ErrGoldenTrackNotFound
+	Delete(ctx context.Context, trackID string) error
+	// List returns the dataset ordered by track ID
+	List(ctx context.Context) ([]*GoldenTrack, error)
+}
+
+// FieldAccuracy is how often a provider got a field right
+type FieldAccuracy struct {
+	Field    string  `json:"field"`
+	Samples  int     `json:"samples"`
+	Correct  int     `json:"correct"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// CalibrationBin is a point of a calibration curve: how often enrichments
+// with a confidence in [MinConfidence, MaxConfidence) got every labeled
+// field right. A well calibrated provider's accuracy is close to its mean
+// confidence.
+type CalibrationBin struct {
+	MinConfidence  float64 `json:"min_confidence"`
+	MaxConfidence  float64 `json:"max_confidence"`
+	Samples        int     `json:"samples"`
+	Correct        int     `json:"correct"`
+	MeanConfidence float64 `json:"mean_confidence"`
+	Accuracy       float64 `json:"accuracy"`
+}
+
+// ProviderEvaluation scores one AI provider against the golden dataset
+type ProviderEvaluation struct {
+	Provider AIProvider `json:"provider"`
+	// Tracks is the number of golden tracks the provider enriched and
+	// Failures the number it failed to
+	Tracks      int              `json:"tracks"`
+	Failures    int              `json:"failures"`
+	Fields      []FieldAccuracy  `json:"fields"`
+	Calibration []CalibrationBin `json:"calibration"`
+	// SuggestedMinConfidence is the lowest confidence threshold above
+	// which the provider reached the target accuracy, or nil when no
+	// threshold did on enough tracks
+	SuggestedMinConfidence *float64 `json:"suggested_min_confidence,omitempty"`
+}
+
+// EvaluationReport is the result of scoring the AI providers against the
+// golden dataset
+type EvaluationReport struct {
+	EvaluatedAt    time.Time            `json:"evaluated_at"`
+	GoldenTracks   int                  `json:"golden_tracks"`
+	TargetAccuracy float64              `json:"target_accuracy"`
+	Providers      []ProviderEvaluation `json:"providers"`
+}
+
+// ProviderEnricher enriches tracks with a chosen AI provider, without
+// fallback or experiment traffic, so providers can be compared
+type ProviderEnricher interface {
+	// Providers lists the configured providers
+	Providers() []AIProvider
+	// EnrichWithProvider enriches a track with one provider only
+	EnrichWithProvider(ctx context.Context, provider AIProvider, track *Track) error
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Event is a domain event, published in process when something happens to
+// the catalog so that cross-cutting reactions such as auditing and cache
+// invalidation need not be coded where it happens. Events carry copies of
+// their tracks, which subscribers may keep but must not change.
+type Event interface {
+	// EventName names the kind of event, such as "track.created"
+	EventName() string
+}
+
+// Names of the domain events
+const (
+	EventTrackCreated     = "track.created"
+	EventMetadataEnriched = "track.metadata_enriched"
+	EventValidationFailed = "track.validation_failed"
+)
+
+// TrackCreated is published once a track is stored
+type TrackCreated struct {
+	Track *Track
+	// Actor is the ID of the user who created the track, empty for tracks
+	// the system created
+	Actor string
+	At    time.Time
+}
+
+// EventName implements Event
+func (TrackCreated) EventName() string { return EventTrackCreated }
+
+// MetadataEnriched is published once AI enrichment filled in the metadata
+// of a track
+type MetadataEnriched struct {
+	Track *Track
+	At    time.Time
+}
+
+// EventName implements Event
+func (MetadataEnriched) EventName() string { return EventMetadataEnriched }
+
+// ValidationFailed is published when a track fails validation
+type ValidationFailed struct {
+	Track  *Track
+	Errors []ValidationError
+	At     time.Time
+}
+
+// EventName implements Event
+func (ValidationFailed) EventName() string { return EventValidationFailed }
+
+// EventPublisher delivers events to the subscribers of their kind
+type EventPublisher interface {
+	// Publish delivers event. Within a unit of work it is delivered once the
+	// unit of work commits, and not at all if it rolls back. Failing
+	// subscribers do not fail the publisher.
+	Publish(ctx context.Context, event Event)
+}
+
+// EventNotice is an event as reported to a webhook
+type EventNotice struct {
+	Event   string `json:"event"`
+	TrackID string `json:"track_id"`
+	LabelID string `json:"label_id,omitempty"`
+	// Actor is set for events caused by a user
+	Actor string `json:"actor,omitempty"`
+	// Errors lists the failed rules of a track that failed validation
+	Errors []ValidationError `json:"errors,omitempty"`
+	At     time.Time         `json:"at"`
+}
+
+// EventNotifier reports events outside the service
+type EventNotifier interface {
+	SendEventNotice(ctx context.Context, notice *EventNotice) error
+}
+
+// LabelStatsInvalidator drops the cached aggregates of a label, so they are
+// computed again on the next read
+type LabelStatsInvalidator interface {
+	InvalidateLabelStats(ctx context.Context, labelID string) error
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+// QuarantinePrefix is the storage prefix uploads are kept under until they
+// have been scanned for malware
+const QuarantinePrefix = "quarantine/"
+
+// ErrMalwareDetected is returned for files a scanner found infected
+var ErrMalwareDetected = errors.New("malware detected")
+
+// ScanResult is the verdict of a malware scan
+type ScanResult struct {
+	// Clean is true when no threat was found
+	Clean bool
+	// Threat names the signature that matched an infected file
+	Threat string
+}
+
+// FileScanner checks file content for viruses and other malware
+type FileScanner interface {
+	// Scan reads content to the end and returns the verdict. An error means
+	// the file could not be scanned, not that it is infected.
+	Scan(ctx context.Context, content io.Reader) (*ScanResult, error)
+}
+
+// QuarantineKey returns the key a file is uploaded to while it awaits its
+// scan
+func QuarantineKey(key string) string {
+	return QuarantinePrefix + key
+}
+
+// IsQuarantined reports whether key lies in quarantine
+func IsQuarantined(key string) bool {
+	return strings.HasPrefix(key, QuarantinePrefix)
+}
+
+// ReleasedKey returns the key a quarantined file is promoted to once it
+// scans clean
+func ReleasedKey(key string) string {
+	return strings.TrimPrefix(key, QuarantinePrefix)
+}
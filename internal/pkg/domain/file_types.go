@@ -26,6 +26,9 @@ type StorageFile struct {
 	Content     io.Reader // File content
 	Metadata    map[string]string
 	UploadedAt  time.Time
+	// EncryptionKeyID is the KMS key the stored object is encrypted with,
+	// set by Upload and Download when the storage encrypts files
+	EncryptionKeyID string
 }
 
 // FileMetadata represents metadata for a stored file
@@ -39,6 +42,17 @@ type FileMetadata struct {
 	ETag         string
 	StorageClass string
 	Metadata     map[string]string
+	// EncryptionKeyID is the KMS key the object is encrypted with, if any
+	EncryptionKeyID string
+}
+
+// PreviewPrefix is where the preview renditions tracks are auditioned with
+// are stored
+const PreviewPrefix = "previews/"
+
+// PreviewKey returns the storage key of a track's preview rendition
+func PreviewKey(trackID string) string {
+	return PreviewPrefix + trackID
 }
 
 // StorageService defines the interface for storage operations
@@ -61,6 +75,30 @@ type StorageService interface {
 	ValidateUpload(ctx context.Context, fileSize int64, mimeType string) error
 }
 
+// UploadSigner is implemented by storage services that let clients upload a
+// file straight to storage instead of through the API
+type UploadSigner interface {
+	// SignUpload presigns an upload of size bytes of contentType to key
+	SignUpload(ctx context.Context, key, contentType string, size int64) (*SignedUpload, error)
+}
+
+// SignedUpload is a presigned request that stores one file. The client must
+// send Headers with it and finish before ExpiresAt.
+type SignedUpload struct {
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// StorageReplicator is implemented by storage services that copy files to a
+// second region. Files written through the service are replicated on their
+// own; Replicate queues a copy of a file stored by other means, such as a
+// direct upload.
+type StorageReplicator interface {
+	Replicate(ctx context.Context, key string) error
+}
+
 // StorageClient defines the interface for low-level storage operations
 type StorageClient interface {
 	// Upload uploads a file to storage
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // TrackConnection represents a paginated connection of tracks
 type TrackConnection struct {
@@ -50,3 +53,24 @@ type BatchError struct {
 	Message string
 	Code    string
 }
+
+// CatalogRelease groups the tracks that share a release ID
+type CatalogRelease struct {
+	ID string
+}
+
+// TrackBatchReader is implemented by track repositories that can load many
+// tracks in a single query. GraphQL dataloaders use it when available.
+type TrackBatchReader interface {
+	// GetByIDs retrieves the tracks with the given IDs in any order
+	GetByIDs(ctx context.Context, ids []string) ([]*Track, error)
+	// ListByReleaseIDs retrieves all tracks belonging to the given releases
+	ListByReleaseIDs(ctx context.Context, releaseIDs []string) ([]*Track, error)
+}
+
+// UserBatchReader is implemented by user repositories that can load many
+// users in a single query
+type UserBatchReader interface {
+	// GetByIDs retrieves the users with the given IDs in any order
+	GetByIDs(ctx context.Context, ids []string) ([]*User, error)
+}
@@ -19,6 +19,7 @@ type CreateTrackInput struct {
 // UpdateTrackInput represents input for updating a track
 type UpdateTrackInput struct {
 	ID        string
+	Version   int // Version the client last read
 	Title     *string
 	Artist    *string
 	Album     *string
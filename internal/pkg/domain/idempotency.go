@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrIdempotencyKeyInUse is returned while another request with the same
+// idempotency key is still being handled
+var ErrIdempotencyKeyInUse = errors.New("idempotency key is in use")
+
+// IdempotentResponse is the response recorded for an idempotency key.
+// RequestHash fingerprints the body of the request it answered, so that a
+// key reused for a different request is not answered with it.
+type IdempotentResponse struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+	RequestHash string `json:"request_hash,omitempty"`
+}
+
+// IdempotencyStore records the responses to requests carrying an
+// idempotency key so that repeats of a request are answered with the
+// original response
+type IdempotencyStore interface {
+	// Reserve claims key for a request that is about to be handled. It
+	// returns the recorded response if key was used before, and
+	// ErrIdempotencyKeyInUse while the request holding key is in progress.
+	Reserve(ctx context.Context, key string, ttl time.Duration) (*IdempotentResponse, error)
+	// Save records the response to the request that reserved key
+	Save(ctx context.Context, key string, response *IdempotentResponse, ttl time.Duration) error
+	// Release frees a reserved key so that the request can be retried
+	Release(ctx context.Context, key string) error
+}
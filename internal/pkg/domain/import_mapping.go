@@ -0,0 +1,319 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrImportMappingNotFound is returned when a label has no such mapping
+var ErrImportMappingNotFound = errors.New("import mapping not found")
+
+// ImportDateFormat is the layout dates are normalized to
+const ImportDateFormat = "2006-01-02"
+
+// ImportTransformType names a value transform
+type ImportTransformType string
+
+const (
+	// ImportTransformDate parses a date in Format and writes it as YYYY-MM-DD
+	ImportTransformDate ImportTransformType = "date"
+	// ImportTransformLookup replaces values found in Values, ignoring case
+	ImportTransformLookup ImportTransformType = "lookup"
+	// ImportTransformUpper and ImportTransformLower change the case
+	ImportTransformUpper ImportTransformType = "upper"
+	ImportTransformLower ImportTransformType = "lower"
+)
+
+// ImportTransform changes a column value before it is stored
+type ImportTransform struct {
+	Type ImportTransformType `json:"type"`
+	// Format is the layout of date values, written with YYYY, YY, MM, M,
+	// DD and D, such as "DD/MM/YYYY"
+	Format string `json:"format,omitempty"`
+	// Values maps lookup keys to the stored values, such as distributor
+	// genres to catalog genres
+	Values map[string]string `json:"values,omitempty"`
+	// Default replaces values missing from Values; without it they are kept
+	Default string `json:"default,omitempty"`
+}
+
+// ImportColumn maps a CSV column to a track field
+type ImportColumn struct {
+	// Column is the header of the CSV column
+	Column string `json:"column"`
+	// Field is a track field such as "title" or "genre", or
+	// "custom.<name>" for a custom field
+	Field string `json:"field"`
+	// Transforms are applied in order to the trimmed value
+	Transforms []ImportTransform `json:"transforms,omitempty"`
+	// Required makes rows with an empty value invalid
+	Required bool `json:"required,omitempty"`
+}
+
+// ImportMapping is a label's reusable template for importing a
+// distributor's CSV files
+type ImportMapping struct {
+	ID      string `json:"id" gorm:"primaryKey"`
+	LabelID string `json:"label_id" gorm:"uniqueIndex:idx_import_mappings_label_name;not null"`
+	Name    string `json:"name" gorm:"uniqueIndex:idx_import_mappings_label_name;not null"`
+	// Delimiter separates the columns; it defaults to a comma
+	Delimiter string         `json:"delimiter,omitempty" gorm:"size:1"`
+	Columns   []ImportColumn `json:"columns" gorm:"serializer:json"`
+	// Defaults sets fields that no column provides, such as the label
+	Defaults  map[string]string `json:"defaults,omitempty" gorm:"serializer:json"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// TableName returns the table name for import mappings
+func (ImportMapping) TableName() string {
+	return "import_mappings"
+}
+
+// ImportMappingRepository stores import mappings
+type ImportMappingRepository interface {
+	// Save creates or replaces a mapping
+	Save(ctx context.Context, mapping *ImportMapping) error
+	// GetByID returns a label's mapping, or ErrImportMappingNotFound
+	GetByID(ctx context.Context, labelID, id string) (*ImportMapping, error)
+	// GetByName returns a label's mapping by name, or ErrImportMappingNotFound
+	GetByName(ctx context.Context, labelID, name string) (*ImportMapping, error)
+	// ListByLabel returns a label's mappings ordered by name
+	ListByLabel(ctx context.Context, labelID string) ([]*ImportMapping, error)
+	// Delete removes a label's mapping
+	Delete(ctx context.Context, labelID, id string) error
+}
+
+// importFieldSetters set the track fields CSV columns can be mapped to
+var importFieldSetters = map[string]func(t *Track, v string) error{
+	"title":      func(t *Track, v string) error { t.SetTitle(v); return nil },
+	"artist":     func(t *Track, v string) error { t.SetArtist(v); return nil },
+	"album":      func(t *Track, v string) error { t.SetAlbum(v); return nil },
+	"year":       setImportYear,
+	"duration":   setImportDuration,
+	"isrc":       func(t *Track, v string) error { t.SetISRC(strings.ReplaceAll(v, "-", "")); return nil },
+	"iswc":       func(t *Track, v string) error { t.setCustomField("iswc", v); return nil },
+	"label":      func(t *Track, v string) error { t.setCustomField("label", v); return nil },
+	"territory":  func(t *Track, v string) error { t.setCustomField("territory", v); return nil },
+	"genre":      func(t *Track, v string) error { t.SetGenre(v); return nil },
+	"bpm":        setImportBPM,
+	"key":        func(t *Track, v string) error { t.SetKey(v); return nil },
+	"mood":       func(t *Track, v string) error { t.SetMood(v); return nil },
+	"publisher":  func(t *Track, v string) error { t.SetPublisher(v); return nil },
+	"copyright":  func(t *Track, v string) error { t.SetCopyright(v); return nil },
+	"lyrics":     func(t *Track, v string) error { t.SetLyrics(v); return nil },
+	"tags":       setImportTags,
+	"release_id": func(t *Track, v string) error { t.ReleaseID = v; return nil },
+}
+
+// Validate checks that the mapping only names known fields and transforms
+func (m *ImportMapping) Validate() []ValidationError {
+	var errs []ValidationError
+	if strings.TrimSpace(m.Name) == "" {
+		errs = append(errs, ValidationError{Field: "name", Code: "required", Message: "name is required"})
+	}
+	if len([]rune(m.Delimiter)) > 1 {
+		errs = append(errs, ValidationError{Field: "delimiter", Message: "delimiter must be a single character"})
+	}
+	if len(m.Columns) == 0 {
+		errs = append(errs, ValidationError{Field: "columns", Code: "required", Message: "map at least one column"})
+	}
+	for i, col := range m.Columns {
+		path := fmt.Sprintf("columns[%d]", i)
+		if col.Column == "" {
+			errs = append(errs, ValidationError{Field: path + ".column", Code: "required", Message: "column is required"})
+		}
+		if !isImportField(col.Field) {
+			errs = append(errs, ValidationError{Field: path + ".field", Message: fmt.Sprintf("unknown field %q", col.Field)})
+		}
+		for j, tr := range col.Transforms {
+			if err := tr.validate(); err != nil {
+				errs = append(errs, ValidationError{Field: fmt.Sprintf("%s.transforms[%d]", path, j), Message: err.Error()})
+			}
+		}
+	}
+	for field := range m.Defaults {
+		if !isImportField(field) {
+			errs = append(errs, ValidationError{Field: "defaults." + field, Message: fmt.Sprintf("unknown field %q", field)})
+		}
+	}
+	return errs
+}
+
+// Comma returns the column delimiter
+func (m *ImportMapping) Comma() rune {
+	if m.Delimiter == "" {
+		return ','
+	}
+	return []rune(m.Delimiter)[0]
+}
+
+// MapRow turns a CSV row into a track. header holds the column names of
+// the file. The returned errors name the CSV columns of invalid values.
+func (m *ImportMapping) MapRow(header, row []string) (*Track, []ValidationError) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(name)] = i
+	}
+
+	track := &Track{}
+	var errs []ValidationError
+	fields := make([]string, 0, len(m.Defaults))
+	for field := range m.Defaults {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if err := setImportField(track, field, m.Defaults[field]); err != nil {
+			errs = append(errs, ValidationError{Field: field, Message: err.Error()})
+		}
+	}
+	for _, col := range m.Columns {
+		value := ""
+		if i, ok := index[col.Column]; ok && i < len(row) {
+			value = strings.TrimSpace(row[i])
+		}
+		var err error
+		for _, tr := range col.Transforms {
+			if value == "" {
+				break
+			}
+			if value, err = tr.apply(value); err != nil {
+				break
+			}
+		}
+		if err == nil && value == "" {
+			if col.Required {
+				errs = append(errs, ValidationError{Field: col.Column, Code: "required", Message: col.Column + " is required"})
+			}
+			continue
+		}
+		if err == nil {
+			err = setImportField(track, col.Field, value)
+		}
+		if err != nil {
+			errs = append(errs, ValidationError{Field: col.Column, Message: err.Error()})
+		}
+	}
+	return track, errs
+}
+
+// isImportField reports whether columns can be mapped to field
+func isImportField(field string) bool {
+	if _, ok := importFieldSetters[field]; ok {
+		return true
+	}
+	return strings.HasPrefix(field, CustomFieldPrefix) && len(field) > len(CustomFieldPrefix)
+}
+
+// setImportField sets a track field from a column value
+func setImportField(t *Track, field, value string) error {
+	if set, ok := importFieldSetters[field]; ok {
+		return set(t, value)
+	}
+	t.setCustomField(strings.TrimPrefix(field, CustomFieldPrefix), value)
+	return nil
+}
+
+func setImportYear(t *Track, v string) error {
+	// Normalized dates give their year
+	if len(v) == len(ImportDateFormat) {
+		if date, err := time.Parse(ImportDateFormat, v); err == nil {
+			t.SetYear(date.Year())
+			return nil
+		}
+	}
+	year, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("%q is not a year", v)
+	}
+	t.SetYear(year)
+	return nil
+}
+
+// setImportDuration reads seconds, M:SS or H:MM:SS
+func setImportDuration(t *Track, v string) error {
+	var seconds float64
+	for _, part := range strings.Split(v, ":") {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil || n < 0 {
+			return fmt.Errorf("%q is not a duration", v)
+		}
+		seconds = seconds*60 + n
+	}
+	t.SetDuration(seconds)
+	return nil
+}
+
+func setImportBPM(t *Track, v string) error {
+	bpm, err := strconv.ParseFloat(v, 64)
+	if err != nil || bpm < 0 {
+		return fmt.Errorf("%q is not a tempo", v)
+	}
+	t.SetBPM(bpm)
+	return nil
+}
+
+// setImportTags reads tags separated by semicolons or commas
+func setImportTags(t *Track, v string) error {
+	for _, tag := range strings.FieldsFunc(v, func(r rune) bool { return r == ';' || r == ',' }) {
+		if tag = strings.TrimSpace(tag); tag != "" && !t.HasTag(tag) {
+			t.Metadata.Additional.Tags = append(t.Metadata.Additional.Tags, tag)
+		}
+	}
+	return nil
+}
+
+// importDateTokens translate date format tokens to Go layouts, longest first
+var importDateTokens = strings.NewReplacer("YYYY", "2006", "YY", "06", "MM", "01", "DD", "02", "M", "1", "D", "2")
+
+func (tr ImportTransform) validate() error {
+	switch tr.Type {
+	case ImportTransformDate:
+		if tr.Format == "" {
+			return errors.New("date transforms need a format")
+		}
+		if !strings.Contains(tr.Format, "YY") || !strings.Contains(tr.Format, "M") || !strings.Contains(tr.Format, "D") {
+			return fmt.Errorf("date format %q needs a year, month and day", tr.Format)
+		}
+	case ImportTransformLookup:
+		if len(tr.Values) == 0 {
+			return errors.New("lookup transforms need values")
+		}
+	case ImportTransformUpper, ImportTransformLower:
+	default:
+		return fmt.Errorf("unknown transform %q", tr.Type)
+	}
+	return nil
+}
+
+func (tr ImportTransform) apply(value string) (string, error) {
+	switch tr.Type {
+	case ImportTransformDate:
+		date, err := time.Parse(importDateTokens.Replace(tr.Format), value)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a date in the format %s", value, tr.Format)
+		}
+		return date.Format(ImportDateFormat), nil
+	case ImportTransformLookup:
+		for key, mapped := range tr.Values {
+			if strings.EqualFold(key, value) {
+				return mapped, nil
+			}
+		}
+		if tr.Default != "" {
+			return tr.Default, nil
+		}
+		return value, nil
+	case ImportTransformUpper:
+		return strings.ToUpper(value), nil
+	case ImportTransformLower:
+		return strings.ToLower(value), nil
+	}
+	return value, nil
+}
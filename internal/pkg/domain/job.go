@@ -34,6 +34,10 @@ const (
 	JobTypeAIEnrich     JobType = "ai_enrich"
 	JobTypeDDEXExport   JobType = "ddex_export"
 	JobTypeCleanup      JobType = "cleanup"
+	JobTypeBulkEdit     JobType = "bulk_edit"
+	JobTypeFileScan     JobType = "file_scan"
+	JobTypeReplicate    JobType = "storage_replicate"
+	JobTypeReanalyze    JobType = "audio_reanalyze"
 )
 
 // Job represents a background job
@@ -53,6 +57,33 @@ type Job struct {
 	NextRetryAt *time.Time      `json:"next_retry_at,omitempty"`
 }
 
+// AIEnrichPayload is the payload of ai_enrich jobs
+type AIEnrichPayload struct {
+	TrackID string `json:"track_id"`
+}
+
+// AudioProcessPayload is the payload of audio_process jobs
+type AudioProcessPayload struct {
+	TrackID     string `json:"track_id"`
+	StoragePath string `json:"storage_path"`
+	Format      string `json:"format,omitempty"`
+}
+
+// ReanalyzePayload is the payload of audio_reanalyze jobs, which queue the
+// tracks analyzed by other versions of the analyzer for analysis by
+// AnalyzerVersion
+type ReanalyzePayload struct {
+	AnalyzerVersion string `json:"analyzer_version"`
+}
+
+// ReplicatePayload is the payload of storage_replicate jobs. Deleted
+// replicates a deletion instead of copying the file.
+type ReplicatePayload struct {
+	Key      string    `json:"key"`
+	Deleted  bool      `json:"deleted,omitempty"`
+	QueuedAt time.Time `json:"queued_at"`
+}
+
 // JobConfig holds configuration for the job system
 type JobConfig struct {
 	// Worker settings
@@ -61,6 +92,12 @@ type JobConfig struct {
 	PollInterval  time.Duration `env:"JOB_POLL_INTERVAL" envDefault:"1s"`
 	ShutdownWait  time.Duration `env:"JOB_SHUTDOWN_WAIT" envDefault:"30s"`
 
+	// Scheduling settings. TypeLimits caps the running jobs of a type, and
+	// workers share out the other jobs in proportion to TypeWeights, which
+	// default to 1. Types without a limit may use every worker.
+	TypeLimits  map[JobType]int
+	TypeWeights map[JobType]int
+
 	// Job settings
 	DefaultMaxRetries int           `env:"JOB_DEFAULT_MAX_RETRIES" envDefault:"3"`
 	DefaultTTL        time.Duration `env:"JOB_DEFAULT_TTL" envDefault:"24h"`
@@ -85,12 +122,19 @@ type JobQueue interface {
 	// Dequeue gets the next job to process
 	Dequeue(ctx context.Context) (*Job, error)
 
+	// DequeueType gets the next job of a type to process
+	DequeueType(ctx context.Context, jobType JobType) (*Job, error)
+
 	// Complete marks a job as completed
 	Complete(ctx context.Context, jobID string) error
 
 	// Fail marks a job as failed
 	Fail(ctx context.Context, jobID string, err error) error
 
+	// Requeue returns a running job to the pending queue without counting a
+	// retry, for jobs interrupted by shutdown
+	Requeue(ctx context.Context, jobID string) error
+
 	// Cancel cancels a pending or running job
 	Cancel(ctx context.Context, jobID string) error
 
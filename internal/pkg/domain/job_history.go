@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// JobHistory is a finished job archived from the job queue for long-term
+// reporting
+type JobHistory struct {
+	ID string `json:"id" gorm:"primaryKey"`
+	// Queue names the queue the job ran on, such as jobs or replication
+	Queue      string          `json:"queue" gorm:"not null"`
+	Type       JobType         `json:"type" gorm:"index;not null"`
+	Status     JobStatus       `json:"status" gorm:"not null"`
+	Priority   JobPriority     `json:"priority"`
+	Payload    json.RawMessage `json:"payload,omitempty" gorm:"serializer:json"`
+	Error      string          `json:"error,omitempty"`
+	RetryCount int             `json:"retry_count"`
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt time.Time       `json:"finished_at" gorm:"index;not null"`
+	// DurationMs is how long the last attempt ran, unknown for jobs that
+	// never started
+	DurationMs *int64    `json:"duration_ms,omitempty"`
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
+// TableName returns the table name for archived jobs
+func (JobHistory) TableName() string {
+	return "jobs_history"
+}
+
+// JobHistoryFilter selects archived jobs. Zero fields match every job;
+// From and To bound the time the jobs finished.
+type JobHistoryFilter struct {
+	Type   JobType
+	Status JobStatus
+	From   time.Time
+	To     time.Time
+	Offset int
+	Limit  int
+}
+
+// JobHistoryPage is a page of archived jobs, newest first
+type JobHistoryPage struct {
+	Total  int64         `json:"total"`
+	Offset int           `json:"offset"`
+	Limit  int           `json:"limit"`
+	Jobs   []*JobHistory `json:"jobs"`
+}
+
+// JobWeekStats counts how the jobs of a type finished in a week
+type JobWeekStats struct {
+	// Week is the Monday the week starts on, as YYYY-MM-DD in UTC
+	Week        string  `json:"week"`
+	Type        JobType `json:"type"`
+	Completed   int64   `json:"completed"`
+	Failed      int64   `json:"failed"`
+	SuccessRate float64 `json:"success_rate"`
+}
+
+// JobTypeDuration reports how long the jobs of a type ran
+type JobTypeDuration struct {
+	Type  JobType `json:"type"`
+	Jobs  int64   `json:"jobs"`
+	AvgMs float64 `json:"avg_ms"`
+	P95Ms float64 `json:"p95_ms"`
+	MaxMs int64   `json:"max_ms"`
+}
+
+// JobHistoryReport summarizes the jobs that finished between From and To
+// inclusive: the success rate of each type per week, and the types that ran
+// longest on average
+type JobHistoryReport struct {
+	From         string            `json:"from"`
+	To           string            `json:"to"`
+	Weeks        []JobWeekStats    `json:"weeks"`
+	SlowestTypes []JobTypeDuration `json:"slowest_types"`
+}
+
+// JobHistoryRepository stores archived jobs
+type JobHistoryRepository interface {
+	// Save stores archived jobs, skipping those stored before
+	Save(ctx context.Context, jobs []*JobHistory) error
+	// List returns a page of the jobs matching filter, newest first, and the
+	// number of jobs matching it
+	List(ctx context.Context, filter JobHistoryFilter) ([]*JobHistory, int64, error)
+	// WeeklyStats counts the jobs finished in [from, to) per week and type
+	WeeklyStats(ctx context.Context, from, to time.Time) ([]JobWeekStats, error)
+	// TypeDurations returns the limit types whose jobs finished in [from, to)
+	// ran longest on average, slowest first
+	TypeDurations(ctx context.Context, from, to time.Time, limit int) ([]JobTypeDuration, error)
+}
+
+// FinishedJobSource hands over the finished jobs of a job queue for archiving
+type FinishedJobSource interface {
+	// FinishedJobs returns up to limit jobs that completed or finally failed
+	// before, oldest first
+	FinishedJobs(ctx context.Context, before time.Time, limit int) ([]*JobHistory, error)
+	// RemoveFinished removes archived jobs from the queue
+	RemoveFinished(ctx context.Context, jobIDs []string) error
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrLabelNotFound is returned when a label does not exist
+var ErrLabelNotFound = errors.New("label not found")
+
+// Label is a record label. Tracks, custom fields, import mappings and
+// public API keys refer to labels by ID.
+type Label struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for labels
+func (Label) TableName() string {
+	return "labels"
+}
+
+// LabelRepository stores labels
+type LabelRepository interface {
+	// Create adds a label
+	Create(ctx context.Context, label *Label) error
+	// GetByID returns a label, or ErrLabelNotFound
+	GetByID(ctx context.Context, id string) (*Label, error)
+	// List returns the labels ordered by ID
+	List(ctx context.Context) ([]*Label, error)
+}
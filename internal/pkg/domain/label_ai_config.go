@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrLabelAIConfigNotFound is returned when a label uses the global AI
+// configuration
+var ErrLabelAIConfigNotFound = errors.New("label AI config not found")
+
+// LabelAIConfig overrides the global AI configuration for the tracks of a
+// label, such as for labels that bring their own OpenAI key. Fields left
+// empty keep the global value.
+type LabelAIConfig struct {
+	LabelID string `json:"label_id" gorm:"primaryKey"`
+	// Provider enriches the label's tracks, whatever the routing policy
+	Provider AIProvider `json:"provider" gorm:"not null"`
+	// APIKey is only set when saving; it is stored encrypted and never
+	// returned
+	APIKey          string `json:"-" gorm:"-"`
+	EncryptedAPIKey string `json:"-"`
+	// APIKeyHint is the end of the API key, to tell keys apart
+	APIKeyHint string `json:"api_key_hint,omitempty"`
+	// Model replaces the provider's model; OpenAI models may name a dated
+	// snapshot
+	Model         string    `json:"model,omitempty"`
+	MinConfidence *float64  `json:"min_confidence,omitempty"`
+	UpdatedBy     string    `json:"updated_by,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for label AI configurations
+func (LabelAIConfig) TableName() string {
+	return "label_ai_configs"
+}
+
+// Validate checks the configuration
+func (c *LabelAIConfig) Validate() []ValidationError {
+	var errs []ValidationError
+	if c.Provider == "" {
+		errs = append(errs, ValidationError{Field: "provider", Message: "provider is required"})
+	} else if err := ValidateAIProvider(c.Provider); err != nil {
+		errs = append(errs, ValidationError{Field: "provider", Message: "provider must be qwen2 or openai"})
+	}
+	if c.MinConfidence != nil && (*c.MinConfidence < 0 || *c.MinConfidence > 1) {
+		errs = append(errs, ValidationError{Field: "min_confidence", Message: "min_confidence must be between 0 and 1"})
+	}
+	return errs
+}
+
+// LabelAIConfigRepository stores the AI configurations of labels, with
+// their API keys encrypted
+type LabelAIConfigRepository interface {
+	// Save creates or replaces a label's configuration, encrypting its API
+	// key
+	Save(ctx context.Context, config *LabelAIConfig) error
+	// Get returns a label's configuration with its API key decrypted, or
+	// ErrLabelAIConfigNotFound
+	Get(ctx context.Context, labelID string) (*LabelAIConfig, error)
+	// List returns the configurations ordered by label, without API keys
+	List(ctx context.Context) ([]*LabelAIConfig, error)
+	// Delete removes a label's configuration, or returns
+	// ErrLabelAIConfigNotFound
+	Delete(ctx context.Context, labelID string) error
+}
+
+// SecretCipher encrypts secrets before they are stored
+type SecretCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(secret string) (string, error)
+}
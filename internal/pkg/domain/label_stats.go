@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// UnknownStatsKey counts the tracks without a genre or year in LabelStats
+const UnknownStatsKey = "unknown"
+
+// LabelStats aggregates the tracks of a label
+type LabelStats struct {
+	LabelID string `json:"label_id"`
+	// Tracks counts the label's tracks that are not deleted
+	Tracks   int64                 `json:"tracks"`
+	ByStatus map[TrackStatus]int64 `json:"by_status"`
+	// ByGenre and ByYear count tracks by genre and release year, with the
+	// tracks missing one under "unknown"
+	ByGenre map[string]int64 `json:"by_genre"`
+	ByYear  map[string]int64 `json:"by_year"`
+	// DurationSeconds totals the duration of the tracks
+	DurationSeconds float64 `json:"duration_seconds"`
+	// StorageBytes totals the size of the stored audio files
+	StorageBytes int64 `json:"storage_bytes"`
+	// Enriched counts the tracks with AI metadata
+	Enriched int64 `json:"enriched"`
+	// EnrichmentCoverage is the share of tracks with AI metadata, from 0 to 1
+	EnrichmentCoverage float64 `json:"enrichment_coverage"`
+	// AverageConfidence is the mean AI confidence of enriched tracks
+	AverageConfidence float64 `json:"average_confidence"`
+	// ComputedAt is when the aggregates were computed; they are cached for
+	// a few minutes
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+// LabelStatsRepository computes the aggregates of a label's tracks
+type LabelStatsRepository interface {
+	LabelStats(ctx context.Context, labelID string) (*LabelStats, error)
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// MaintenanceRun records one run of a scheduled maintenance task
+type MaintenanceRun struct {
+	Task string `json:"task"`
+	// Instance names the replica that ran the task
+	Instance string `json:"instance"`
+	// DueAt is the time the run was scheduled for
+	DueAt      time.Time `json:"due_at"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// MaintenanceTask describes a scheduled maintenance task and its latest runs,
+// newest first
+type MaintenanceTask struct {
+	Name     string            `json:"name"`
+	Schedule string            `json:"schedule"`
+	NextRun  time.Time         `json:"next_run"`
+	Running  bool              `json:"running"`
+	Runs     []*MaintenanceRun `json:"runs"`
+}
+
+// MaintenanceStatus reports the maintenance tasks as seen by one replica
+type MaintenanceStatus struct {
+	Instance string `json:"instance"`
+	// Leader is set when this replica runs the tasks
+	Leader bool              `json:"leader"`
+	Tasks  []MaintenanceTask `json:"tasks"`
+}
+
+// MaintenanceCoordinator elects the one replica that runs the maintenance
+// tasks
+type MaintenanceCoordinator interface {
+	// Lead takes or keeps the lead for ttl and reports whether this replica
+	// leads
+	Lead(ctx context.Context, ttl time.Duration) (bool, error)
+	// Resign gives up the lead if this replica holds it
+	Resign(ctx context.Context) error
+	// Claim reserves the run of task due at due. It reports false when a run
+	// due at that time or later was claimed before, such as by a leader that
+	// was replaced.
+	Claim(ctx context.Context, task string, due time.Time) (bool, error)
+}
+
+// MaintenanceHistory keeps the latest runs of each maintenance task
+type MaintenanceHistory interface {
+	// Record adds a run to the history of its task
+	Record(ctx context.Context, run *MaintenanceRun) error
+	// Runs returns the latest limit runs of task, newest first
+	Runs(ctx context.Context, task string, limit int) ([]*MaintenanceRun, error)
+}
+
+// DeadLetterExpirer removes the dead letters kept longer than the queue's
+// dead letter TTL
+type DeadLetterExpirer interface {
+	// ExpireDeadLetters removes the expired dead letters of every topic and
+	// returns how many it removed
+	ExpireDeadLetters(ctx context.Context) (int, error)
+}
+
+// TempFileCleaner removes the temporary files left by uploads
+type TempFileCleaner interface {
+	CleanupTempFiles(ctx context.Context) error
+}
+
+// ExpiredSessionRemover removes what is left of the sessions that expired
+type ExpiredSessionRemover interface {
+	DeleteExpired(ctx context.Context) error
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // MetadataProvider defines the interface for any type that can provide metadata
 type MetadataProvider interface {
@@ -40,6 +43,9 @@ type CompleteTrackMetadata struct {
 	Musical            MusicalMetadata        `json:"musical"`
 	AI                 *TrackAIMetadata       `json:"ai,omitempty"`
 	Additional         AdditionalMetadata     `json:"additional"`
+
+	// Provenance records the source of each user-editable field, keyed by field name
+	Provenance map[string]FieldProvenance `json:"provenance,omitempty"`
 }
 
 // AudioTechnicalMetadata contains audio file technical details
@@ -49,6 +55,15 @@ type AudioTechnicalMetadata struct {
 	Bitrate    int         `json:"bitrate"`    // kbps
 	Channels   int         `json:"channels"`
 	FileSize   int64       `json:"fileSize"` // bytes
+	// ContentHash is the hex encoded SHA-256 of the audio file. It keys the
+	// AI enrichment cache, so identical files are only enriched once.
+	ContentHash string `json:"contentHash,omitempty"`
+	// ContentMD5 is the hex encoded MD5 of the audio file, checked along
+	// with ContentHash to detect corrupted files
+	ContentMD5 string `json:"contentMD5,omitempty"`
+	// EncryptionKeyID is the KMS key the stored audio file is encrypted
+	// with, kept so files can be found when a key is rotated or retired
+	EncryptionKeyID string `json:"encryptionKeyId,omitempty"`
 }
 
 // MusicalMetadata contains musical attributes
@@ -60,6 +75,34 @@ type MusicalMetadata struct {
 	Genre  string  `json:"genre"`
 	Energy float64 `json:"energy"`
 	Tempo  float64 `json:"tempo"`
+	// Camelot and OpenKey write the key in the notations of the DJ key
+	// wheels, such as "8A" and "1m" for A minor. They are derived from Key
+	// and Mode whenever the metadata is written, and blank for keys
+	// ParseKey does not read.
+	Camelot string `json:"camelot,omitempty"`
+	OpenKey string `json:"openKey,omitempty"`
+}
+
+// CamelotKey returns the key on the Camelot wheel. The mode is read from
+// Mode when Key does not carry it.
+func (m MusicalMetadata) CamelotKey() (CamelotKey, bool) {
+	if m.Mode != "" {
+		if k, ok := ParseKey(m.Key + " " + m.Mode); ok {
+			return k, true
+		}
+	}
+	return ParseKey(m.Key)
+}
+
+// MarshalJSON writes the metadata with the key notations derived from the
+// key, so stored and returned tracks always carry them
+func (m MusicalMetadata) MarshalJSON() ([]byte, error) {
+	type plain MusicalMetadata
+	m.Camelot, m.OpenKey = "", ""
+	if k, ok := m.CamelotKey(); ok {
+		m.Camelot, m.OpenKey = k.String(), k.OpenKey()
+	}
+	return json.Marshal(plain(m))
 }
 
 // TrackAIMetadata contains AI-generated metadata and processing information
@@ -68,6 +111,7 @@ type TrackAIMetadata struct {
 	Confidence            float64                `json:"confidence"`
 	Model                 string                 `json:"model"`
 	Version               string                 `json:"version"`
+	PromptVersion         string                 `json:"promptVersion,omitempty"`
 	ProcessedAt           time.Time              `json:"processedAt"`
 	NeedsReview           bool                   `json:"needsReview"`
 	ReviewReason          string                 `json:"reviewReason,omitempty"`
@@ -81,6 +125,7 @@ type AdditionalMetadata struct {
 	Publisher    string            `json:"publisher"`
 	Copyright    string            `json:"copyright"`
 	Lyrics       string            `json:"lyrics"`
+	Tags         []string          `json:"tags,omitempty"`
 	CustomTags   map[string]string `json:"customTags"`
 	CustomFields map[string]string `json:"customFields"`
 }
@@ -0,0 +1,207 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CamelotKey is a musical key on the Camelot wheel, which DJs mix by:
+// numbers 1 to 12 run along the circle of fifths, A for minor keys and B
+// for major. Keys a step apart on the wheel, or with the same number, mix
+// without clashing.
+type CamelotKey struct {
+	Number int  `json:"number"`
+	Minor  bool `json:"minor"`
+}
+
+// sharpNames and flatNames name the pitch classes, C at 0
+var (
+	sharpNames = [12]string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+	flatNames  = [12]string{"C", "Db", "D", "Eb", "E", "F", "Gb", "G", "Ab", "A", "Bb", "B"}
+)
+
+// KeyFromPitch returns the key with a root pitch class, C at 0
+func KeyFromPitch(pitch int, minor bool) CamelotKey {
+	pitch = ((pitch % 12) + 12) % 12
+	if minor {
+		// A minor key sits at the number of its relative major
+		pitch = (pitch + 3) % 12
+	}
+	// C major is 8B, and every fifth up is one number on
+	return CamelotKey{Number: (pitch*7%12+7)%12 + 1, Minor: minor}
+}
+
+// ParseKey reads a key written by root and mode, such as "Am", "F# minor",
+// "Db" or "Eb major", or in Camelot ("8A") or Open Key ("1m") notation
+func ParseKey(key string) (CamelotKey, bool) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	key = strings.NewReplacer("♯", "#", "♭", "b").Replace(key)
+	if key == "" {
+		return CamelotKey{}, false
+	}
+	if k, ok := parseWheelKey(key); ok {
+		return k, true
+	}
+
+	root, mode, _ := strings.Cut(key, " ")
+	mode = strings.TrimSpace(mode)
+	minor := false
+	switch mode {
+	case "", "major", "maj":
+	case "minor", "min":
+		minor = true
+	default:
+		return CamelotKey{}, false
+	}
+	if len(root) > 1 && strings.HasSuffix(root, "m") && mode == "" {
+		root = strings.TrimSuffix(root, "m")
+		minor = true
+	}
+	for pitch := range sharpNames {
+		if root == strings.ToLower(sharpNames[pitch]) || root == strings.ToLower(flatNames[pitch]) {
+			return KeyFromPitch(pitch, minor), true
+		}
+	}
+	return CamelotKey{}, false
+}
+
+// parseWheelKey reads a key in Camelot or Open Key notation
+func parseWheelKey(key string) (CamelotKey, bool) {
+	if len(key) < 2 {
+		return CamelotKey{}, false
+	}
+	number, err := strconv.Atoi(key[:len(key)-1])
+	if err != nil || number < 1 || number > 12 {
+		return CamelotKey{}, false
+	}
+	switch key[len(key)-1] {
+	case 'a':
+		return CamelotKey{Number: number, Minor: true}, true
+	case 'b':
+		return CamelotKey{Number: number}, true
+	case 'm':
+		// Open Key starts at C major and A minor, which Camelot numbers 8
+		return CamelotKey{Number: (number+6)%12 + 1, Minor: true}, true
+	case 'd':
+		return CamelotKey{Number: (number+6)%12 + 1}, true
+	}
+	return CamelotKey{}, false
+}
+
+// Valid reports whether the key is on the wheel
+func (k CamelotKey) Valid() bool {
+	return k.Number >= 1 && k.Number <= 12
+}
+
+// String returns the key in Camelot notation, such as "8A"
+func (k CamelotKey) String() string {
+	if k.Minor {
+		return fmt.Sprintf("%dA", k.Number)
+	}
+	return fmt.Sprintf("%dB", k.Number)
+}
+
+// OpenKey returns the key in Open Key notation, such as "1m"
+func (k CamelotKey) OpenKey() string {
+	number := (k.Number+4)%12 + 1
+	if k.Minor {
+		return fmt.Sprintf("%dm", number)
+	}
+	return fmt.Sprintf("%dd", number)
+}
+
+// Pitch returns the pitch class of the key's root, C at 0
+func (k CamelotKey) Pitch() int {
+	// Numbers are fifths from C major at 8, and seven is its own inverse
+	// modulo 12, so the fifths turn back into semitones
+	pitch := (k.Number + 4) % 12 * 7 % 12
+	if k.Minor {
+		pitch = (pitch + 9) % 12
+	}
+	return pitch
+}
+
+// wheelDistance returns the steps between two numbers on the Camelot wheel
+func wheelDistance(a, b int) int {
+	d := (a - b + 12) % 12
+	if d > 6 {
+		d = 12 - d
+	}
+	return d
+}
+
+// HarmonicRelation is how two keys mix
+type HarmonicRelation string
+
+const (
+	// HarmonicSame is the same key
+	HarmonicSame HarmonicRelation = "same"
+	// HarmonicAdjacent is a step round the wheel in the same mode, a fifth
+	// up or down
+	HarmonicAdjacent HarmonicRelation = "adjacent"
+	// HarmonicRelative is the relative major or minor, the same number in
+	// the other mode
+	HarmonicRelative HarmonicRelation = "relative"
+)
+
+// RelationTo returns how the key mixes with other, or false when they
+// clash
+func (k CamelotKey) RelationTo(other CamelotKey) (HarmonicRelation, bool) {
+	switch {
+	case k == other:
+		return HarmonicSame, true
+	case k.Minor == other.Minor && wheelDistance(k.Number, other.Number) == 1:
+		return HarmonicAdjacent, true
+	case k.Minor != other.Minor && k.Number == other.Number:
+		return HarmonicRelative, true
+	}
+	return "", false
+}
+
+// Compatible returns the keys that mix with the key: itself, its
+// neighbours on the wheel and its relative key
+func (k CamelotKey) Compatible() []CamelotKey {
+	return []CamelotKey{
+		k,
+		{Number: (k.Number+10)%12 + 1, Minor: k.Minor},
+		{Number: k.Number%12 + 1, Minor: k.Minor},
+		{Number: k.Number, Minor: !k.Minor},
+	}
+}
+
+// Spellings returns the ways a key is commonly written, in lower case:
+// root names with sharps and flats, with and without the mode spelt out,
+// and the Camelot and Open Key codes
+func (k CamelotKey) Spellings() []string {
+	pitch := k.Pitch()
+	roots := []string{strings.ToLower(sharpNames[pitch])}
+	if flatNames[pitch] != sharpNames[pitch] {
+		roots = append(roots, strings.ToLower(flatNames[pitch]))
+	}
+	spellings := []string{strings.ToLower(k.String()), k.OpenKey()}
+	for _, root := range roots {
+		if k.Minor {
+			spellings = append(spellings, root+"m", root+" minor", root+" min")
+		} else {
+			spellings = append(spellings, root, root+" major", root+" maj")
+		}
+	}
+	return spellings
+}
+
+// HarmonicBPMTolerance is how far, as a share of a track's tempo, the
+// tempo of a track mixing with it may be: about what a DJ's pitch fader
+// covers without the key audibly shifting
+const HarmonicBPMTolerance = 0.06
+
+// HarmonicMatch is a track that mixes with another
+type HarmonicMatch struct {
+	Track *Track `json:"track"`
+	// Camelot is the track's key in Camelot notation
+	Camelot  string           `json:"camelot"`
+	Relation HarmonicRelation `json:"relation"`
+	// BPMDifference is the share by which the track's tempo differs from
+	// the other's, from -0.06 to 0.06
+	BPMDifference float64 `json:"bpm_difference"`
+}
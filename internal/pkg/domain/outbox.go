@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TrackChangeType identifies the kind of change recorded in the change feed
+type TrackChangeType string
+
+const (
+	TrackChangeCreated TrackChangeType = "track.created"
+	TrackChangeUpdated TrackChangeType = "track.updated"
+	TrackChangeDeleted TrackChangeType = "track.deleted"
+	// TrackChangeStatusChanged is published by the status workflow next to
+	// the track.updated event of the same change
+	TrackChangeStatusChanged TrackChangeType = "track.status_changed"
+)
+
+// OutboxEvent is a change event stored in the same transaction as the track
+// change it describes. Sequence gives the feed its global order.
+type OutboxEvent struct {
+	Sequence    int64           `json:"sequence" gorm:"primaryKey;autoIncrement"`
+	AggregateID string          `json:"aggregate_id" gorm:"index;not null"`
+	EventType   TrackChangeType `json:"event_type" gorm:"not null"`
+	Version     int             `json:"version"`
+	Payload     []byte          `json:"payload" gorm:"type:jsonb"`
+	CreatedAt   time.Time       `json:"created_at"`
+	PublishedAt *time.Time      `json:"published_at,omitempty" gorm:"index"`
+	Attempts    int             `json:"attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+}
+
+// TableName returns the table name for outbox events
+func (OutboxEvent) TableName() string {
+	return "track_outbox"
+}
+
+// TrackChangeEvent is the payload published to the change feed
+type TrackChangeEvent struct {
+	Sequence   int64           `json:"sequence"`
+	Type       TrackChangeType `json:"type"`
+	TrackID    string          `json:"track_id"`
+	Version    int             `json:"version"`
+	Track      *Track          `json:"track,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// NewTrackOutboxEvent builds the outbox row for a track change. The track
+// snapshot is omitted for deletions.
+func NewTrackOutboxEvent(changeType TrackChangeType, track *Track) (*OutboxEvent, error) {
+	event := TrackChangeEvent{
+		Type:       changeType,
+		TrackID:    track.ID,
+		Version:    track.Version,
+		OccurredAt: time.Now(),
+	}
+	if changeType != TrackChangeDeleted {
+		event.Track = track
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal track change event: %w", err)
+	}
+
+	return &OutboxEvent{
+		AggregateID: track.ID,
+		EventType:   changeType,
+		Version:     track.Version,
+		Payload:     payload,
+		CreatedAt:   event.OccurredAt,
+	}, nil
+}
+
+// ChangeEvent decodes the stored payload and stamps it with the feed sequence
+func (e *OutboxEvent) ChangeEvent() (*TrackChangeEvent, error) {
+	var event TrackChangeEvent
+	if err := json.Unmarshal(e.Payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal track change event: %w", err)
+	}
+	event.Sequence = e.Sequence
+	return &event, nil
+}
+
+// OutboxRepository reads and acknowledges pending change feed events
+type OutboxRepository interface {
+	// FetchPending returns unpublished events in sequence order
+	FetchPending(ctx context.Context, limit int) ([]*OutboxEvent, error)
+	// MarkPublished records that an event reached the queue
+	MarkPublished(ctx context.Context, sequence int64) error
+	// MarkFailed records a failed publish attempt
+	MarkFailed(ctx context.Context, sequence int64, err error) error
+	// PendingCount returns the number of unpublished events
+	PendingCount(ctx context.Context) (int64, error)
+}
+
+// ChangeFeedPublisher publishes messages that must be delivered in order per key
+type ChangeFeedPublisher interface {
+	PublishOrdered(ctx context.Context, topic, orderingKey string, message *Message) error
+}
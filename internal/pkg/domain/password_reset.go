@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrResetRateLimited is returned when too many password resets are requested
+	ErrResetRateLimited = errors.New("too many password reset requests")
+
+	// ErrWeakPassword is returned when a new password does not meet the policy
+	ErrWeakPassword = errors.New("password does not meet requirements")
+)
+
+// PasswordResetStore keeps outstanding password reset tokens. Only the most
+// recently issued token of a user is valid.
+type PasswordResetStore interface {
+	// Save stores a token ID for the user, replacing any earlier one
+	Save(ctx context.Context, tokenID, userID string, ttl time.Duration) error
+	// Consume returns the user a token ID was issued to and deletes it, so a
+	// token can only be redeemed once
+	Consume(ctx context.Context, tokenID string) (string, error)
+	// Hit counts a reset request against key and returns the number of
+	// requests seen within the window
+	Hit(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+// PasswordResetNotifier delivers a reset token to the user
+type PasswordResetNotifier interface {
+	SendPasswordReset(ctx context.Context, user *User, token string, expiresAt time.Time) error
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrSalesReportIngested is returned when a DSP's usage report was already
+// ingested
+var ErrSalesReportIngested = errors.New("sales report already ingested")
+
+// PlayMonthFormat is the layout of play count months
+const PlayMonthFormat = "2006-01"
+
+// PlayCount is the number of times a track was played on one DSP in one
+// territory in one month, summed over the ingested usage reports
+type PlayCount struct {
+	TrackID   string    `json:"track_id" gorm:"primaryKey"`
+	DSP       string    `json:"dsp" gorm:"primaryKey"`
+	Territory string    `json:"territory" gorm:"primaryKey;size:2"`
+	Month     string    `json:"month" gorm:"primaryKey;size:7"`
+	ISRC      string    `json:"isrc"`
+	Plays     int64     `json:"plays"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for play counts
+func (PlayCount) TableName() string {
+	return "play_counts"
+}
+
+// SalesReport records an ingested DSP usage report so it is not counted twice
+type SalesReport struct {
+	DSP       string `json:"dsp" gorm:"primaryKey"`
+	MessageID string `json:"message_id" gorm:"primaryKey"`
+	// Month is the month the report's plays are counted in
+	Month string `json:"month" gorm:"size:7"`
+	// Records counts the report's usage records; Unmatched those whose ISRC
+	// matches no track, which are not counted
+	Records    int       `json:"records"`
+	Unmatched  int       `json:"unmatched"`
+	Plays      int64     `json:"plays"`
+	IngestedAt time.Time `json:"ingested_at"`
+}
+
+// TableName returns the table name for sales reports
+func (SalesReport) TableName() string {
+	return "sales_reports"
+}
+
+// PlayCountFilter selects play counts; empty fields match every count.
+// From and To are months, inclusive.
+type PlayCountFilter struct {
+	TrackID   string
+	DSP       string
+	Territory string
+	From      string
+	To        string
+	Offset    int
+	Limit     int
+}
+
+// PlayCountRepository stores play counts
+type PlayCountRepository interface {
+	// Ingest records report and adds counts to the stored play counts in one
+	// transaction. It returns ErrSalesReportIngested if the report was
+	// ingested before.
+	Ingest(ctx context.Context, report *SalesReport, counts []*PlayCount) error
+	// List returns the play counts matching filter ordered by month, track,
+	// territory and DSP
+	List(ctx context.Context, filter PlayCountFilter) ([]*PlayCount, error)
+}
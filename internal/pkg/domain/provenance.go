@@ -0,0 +1,101 @@
+package domain
+
+import "time"
+
+// ProvenanceSource identifies where the current value of a field came from
+type ProvenanceSource string
+
+const (
+	ProvenanceManual ProvenanceSource = "manual"
+	ProvenanceAI     ProvenanceSource = "ai"
+	ProvenanceImport ProvenanceSource = "import"
+)
+
+// FieldProvenance records who last set a field and when
+type FieldProvenance struct {
+	Source    ProvenanceSource `json:"source"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+	UpdatedBy string           `json:"updatedBy,omitempty"`
+}
+
+// MergePolicy controls how automated sources merge into existing track data
+type MergePolicy struct {
+	// OverwriteManual allows automated sources to replace manually edited fields
+	OverwriteManual bool `json:"overwrite_manual"`
+}
+
+// FieldProvenanceView describes a field's current value together with its provenance
+type FieldProvenanceView struct {
+	Field     string           `json:"field"`
+	Value     interface{}      `json:"value"`
+	Source    ProvenanceSource `json:"source,omitempty"`
+	UpdatedAt *time.Time       `json:"updatedAt,omitempty"`
+	UpdatedBy string           `json:"updatedBy,omitempty"`
+}
+
+// FieldProvenance returns the recorded provenance of a field, if any
+func (t *Track) FieldProvenance(field string) (FieldProvenance, bool) {
+	p, ok := t.Metadata.Provenance[field]
+	return p, ok
+}
+
+// IsManuallyEdited reports whether a field was last set by a person
+func (t *Track) IsManuallyEdited(field string) bool {
+	p, ok := t.FieldProvenance(field)
+	return ok && p.Source == ProvenanceManual
+}
+
+// RecordProvenance marks the given field changes as coming from source
+func (t *Track) RecordProvenance(changes []FieldChange, source ProvenanceSource, actor string) {
+	if len(changes) == 0 {
+		return
+	}
+	if t.Metadata.Provenance == nil {
+		t.Metadata.Provenance = make(map[string]FieldProvenance)
+	}
+	now := time.Now()
+	for _, change := range changes {
+		t.Metadata.Provenance[change.Field] = FieldProvenance{
+			Source:    source,
+			UpdatedAt: now,
+			UpdatedBy: actor,
+		}
+	}
+}
+
+// ProvenanceReport lists every user-editable field with its value and provenance
+func (t *Track) ProvenanceReport() []FieldProvenanceView {
+	views := make([]FieldProvenanceView, 0, len(trackFields))
+	for _, f := range trackFields {
+		view := FieldProvenanceView{Field: f.name, Value: f.get(t)}
+		if p, ok := t.FieldProvenance(f.name); ok {
+			updatedAt := p.UpdatedAt
+			view.Source = p.Source
+			view.UpdatedAt = &updatedAt
+			view.UpdatedBy = p.UpdatedBy
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+// MergeTrackFields copies fields that differ in src into dst on behalf of an
+// automated source. Manually edited fields in dst are skipped unless the policy
+// allows overwriting them. Applied changes are recorded in dst's provenance.
+func MergeTrackFields(dst, src *Track, source ProvenanceSource, policy MergePolicy, actor string) (applied, skipped []FieldChange) {
+	for _, f := range trackFields {
+		oldValue, newValue := f.get(dst), f.get(src)
+		if valuesEqual(oldValue, newValue) {
+			continue
+		}
+		change := FieldChange{Field: f.name, OldValue: oldValue, NewValue: newValue}
+		if source != ProvenanceManual && dst.IsManuallyEdited(f.name) && !policy.OverwriteManual {
+			skipped = append(skipped, change)
+			continue
+		}
+		f.copy(dst, src)
+		applied = append(applied, change)
+	}
+	dst.RecordProvenance(applied, source, actor)
+	return applied, skipped
+}
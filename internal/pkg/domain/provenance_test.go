@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeTrackFields(t *testing.T) {
+	// stored is a track whose title was last set by source, and whose genre
+	// has no provenance
+	stored := func(source ProvenanceSource) *Track {
+		track := &Track{ID: "t1"}
+		track.SetTitle("Stored Title")
+		track.SetGenre("Pop")
+		if source != "" {
+			track.RecordProvenance([]FieldChange{{Field: "title"}}, source, "earlier")
+		}
+		return track
+	}
+	incoming := func() *Track {
+		track := &Track{ID: "t1"}
+		track.SetTitle("Incoming Title")
+		track.SetGenre("Rock")
+		return track
+	}
+
+	tests := []struct {
+		name      string
+		stored    ProvenanceSource
+		source    ProvenanceSource
+		policy    MergePolicy
+		wantTitle string
+		skipped   []string
+	}{
+		{"AI fills a field without provenance", "", ProvenanceAI, MergePolicy{}, "Incoming Title", nil},
+		{"AI replaces AI", ProvenanceAI, ProvenanceAI, MergePolicy{}, "Incoming Title", nil},
+		{"AI replaces import", ProvenanceImport, ProvenanceAI, MergePolicy{}, "Incoming Title", nil},
+		{"import replaces AI", ProvenanceAI, ProvenanceImport, MergePolicy{}, "Incoming Title", nil},
+		{"AI keeps a manual edit", ProvenanceManual, ProvenanceAI, MergePolicy{}, "Stored Title", []string{"title"}},
+		{"import keeps a manual edit", ProvenanceManual, ProvenanceImport, MergePolicy{}, "Stored Title", []string{"title"}},
+		{"policy lets AI replace a manual edit", ProvenanceManual, ProvenanceAI, MergePolicy{OverwriteManual: true}, "Incoming Title", nil},
+		{"policy lets import replace a manual edit", ProvenanceManual, ProvenanceImport, MergePolicy{OverwriteManual: true}, "Incoming Title", nil},
+		{"manual replaces manual", ProvenanceManual, ProvenanceManual, MergePolicy{}, "Incoming Title", nil},
+		{"manual replaces AI", ProvenanceAI, ProvenanceManual, MergePolicy{}, "Incoming Title", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := stored(tt.stored)
+			applied, skipped := MergeTrackFields(dst, incoming(), tt.source, tt.policy, "merger")
+
+			assert.Equal(t, tt.wantTitle, dst.Title())
+			assert.Equal(t, "Rock", dst.Genre(), "fields without provenance are always merged")
+
+			var skippedFields []string
+			for _, change := range skipped {
+				skippedFields = append(skippedFields, change.Field)
+			}
+			assert.Equal(t, tt.skipped, skippedFields)
+			assert.Len(t, applied, 2-len(tt.skipped))
+
+			// Applied fields now belong to the merging source; skipped ones
+			// keep their provenance
+			for _, change := range applied {
+				p, ok := dst.FieldProvenance(change.Field)
+				require.True(t, ok)
+				assert.Equal(t, tt.source, p.Source)
+				assert.Equal(t, "merger", p.UpdatedBy)
+			}
+			for _, change := range skipped {
+				p, _ := dst.FieldProvenance(change.Field)
+				assert.Equal(t, tt.stored, p.Source)
+				assert.Equal(t, "earlier", p.UpdatedBy)
+				assert.Equal(t, "Stored Title", change.OldValue)
+				assert.Equal(t, "Incoming Title", change.NewValue)
+			}
+		})
+	}
+
+	t.Run("equal fields are left alone", func(t *testing.T) {
+		dst := stored(ProvenanceManual)
+		applied, skipped := MergeTrackFields(dst, dst.Clone(), ProvenanceAI, MergePolicy{}, "merger")
+		assert.Empty(t, applied)
+		assert.Empty(t, skipped)
+		p, _ := dst.FieldProvenance("title")
+		assert.Equal(t, ProvenanceManual, p.Source)
+		_, ok := dst.FieldProvenance("genre")
+		assert.False(t, ok)
+	})
+}
+
+func TestRecordProvenance(t *testing.T) {
+	track := &Track{}
+	track.RecordProvenance(nil, ProvenanceAI, "model")
+	assert.Nil(t, track.Metadata.Provenance, "no changes record nothing")
+
+	track.RecordProvenance([]FieldChange{{Field: "title"}, {Field: "isrc"}}, ProvenanceImport, "csv")
+	track.RecordProvenance([]FieldChange{{Field: "title"}}, ProvenanceManual, "user-1")
+
+	assert.True(t, track.IsManuallyEdited("title"))
+	assert.False(t, track.IsManuallyEdited("isrc"))
+	assert.False(t, track.IsManuallyEdited("genre"))
+
+	p, ok := track.FieldProvenance("isrc")
+	require.True(t, ok)
+	assert.Equal(t, ProvenanceImport, p.Source)
+	assert.Equal(t, "csv", p.UpdatedBy)
+	assert.False(t, p.UpdatedAt.IsZero())
+
+	report := track.ProvenanceReport()
+	require.Len(t, report, len(trackFields))
+	assert.Equal(t, "title", report[0].Field)
+	assert.Equal(t, ProvenanceManual, report[0].Source)
+	assert.Equal(t, "user-1", report[0].UpdatedBy)
+	for _, view := range report {
+		if view.Field == "genre" {
+			assert.Empty(t, view.Source)
+			assert.Nil(t, view.UpdatedAt)
+		}
+	}
+}
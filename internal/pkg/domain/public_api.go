@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrPublicAPIKeyNotFound is returned for unknown and revoked public API keys
+	ErrPublicAPIKeyNotFound = errors.New("public API key not found")
+	// ErrPublicAPIQuotaExceeded is returned when a key has used up a window
+	// of its tier
+	ErrPublicAPIQuotaExceeded = errors.New("public API quota exceeded")
+)
+
+// PublicAPIKeyPrefix starts every public API key so that leaked keys can be
+// recognized
+const PublicAPIKeyPrefix = "mdt_pub_"
+
+// Public API tiers
+const (
+	PublicAPITierFree       = "free"
+	PublicAPITierPartner    = "partner"
+	PublicAPITierEnterprise = "enterprise"
+)
+
+// PublicAPITier sets the request quotas of the keys on it. A limit of zero
+// leaves its window unlimited.
+type PublicAPITier struct {
+	Name              string `json:"name"`
+	RequestsPerMinute int    `json:"requests_per_minute"`
+	RequestsPerDay    int    `json:"requests_per_day"`
+}
+
+// PublicAPIKey lets a partner read a label's approved tracks through the
+// public catalog API. Only a hash of the key is stored.
+type PublicAPIKey struct {
+	ID   string `json:"id" gorm:"primaryKey"`
+	Name string `json:"name" gorm:"not null"`
+	// LabelID is the label whose tracks the key reads and whose usage its
+	// requests are metered to
+	LabelID string `json:"label_id" gorm:"index;not null"`
+	Tier    string `json:"tier" gorm:"not null"`
+	// Prefix is the start of the key, shown to tell keys apart
+	Prefix    string     `json:"prefix" gorm:"not null"`
+	KeyHash   string     `json:"-" gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// TableName returns the table name for public API keys
+func (PublicAPIKey) TableName() string {
+	return "public_api_keys"
+}
+
+// HashPublicAPIKey returns the hash public API keys are stored and looked
+// up by
+func HashPublicAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// PublicAPIKeyRepository stores public API keys
+type PublicAPIKeyRepository interface {
+	// Create stores a new key
+	Create(ctx context.Context, key *PublicAPIKey) error
+	// GetByHash returns the key with hash, or ErrPublicAPIKeyNotFound
+	GetByHash(ctx context.Context, hash string) (*PublicAPIKey, error)
+	// List returns the keys ordered by creation, newest first. An empty
+	// labelID lists every label's keys.
+	List(ctx context.Context, labelID string) ([]*PublicAPIKey, error)
+	// Revoke marks a key revoked at, or returns ErrPublicAPIKeyNotFound
+	Revoke(ctx context.Context, id string, at time.Time) error
+}
+
+// PublicAPIAllowance is what is left of a key's quota after a request
+type PublicAPIAllowance struct {
+	Tier PublicAPITier
+	// MinuteRemaining and DayRemaining are the requests left in the current
+	// windows; they are -1 for unlimited windows
+	MinuteRemaining int
+	DayRemaining    int
+	// MinuteReset and DayReset are when the current windows end
+	MinuteReset time.Time
+	DayReset    time.Time
+}
+
+// PublicAPIQuota counts public API requests against the quotas of their tier
+type PublicAPIQuota interface {
+	// Take counts a request of keyID at now. When a window of tier is used
+	// up the request is not counted and ErrPublicAPIQuotaExceeded is
+	// returned with the allowance.
+	Take(ctx context.Context, keyID string, tier PublicAPITier, now time.Time) (*PublicAPIAllowance, error)
+}
+
+// PublicTrackStatuses are the statuses of tracks the public API serves
+var PublicTrackStatuses = []TrackStatus{TrackStatusApproved, TrackStatusDelivered}
+
+// IsPublic reports whether a track is approved for the public API
+func (t *Track) IsPublic() bool {
+	if t.DeletedAt != nil {
+		return false
+	}
+	for _, status := range PublicTrackStatuses {
+		if t.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicTrack is a track as served to partners: its release metadata
+// without storage, workflow, AI or custom fields
+type PublicTrack struct {
+	ID        string    `json:"id"`
+	ISRC      string    `json:"isrc,omitempty"`
+	ISWC      string    `json:"iswc,omitempty"`
+	Title     string    `json:"title"`
+	Artist    string    `json:"artist"`
+	Album     string    `json:"album,omitempty"`
+	Year      int       `json:"year,omitempty"`
+	Duration  float64   `json:"duration,omitempty"`
+	Genre     string    `json:"genre,omitempty"`
+	BPM       float64   `json:"bpm,omitempty"`
+	Key       string    `json:"key,omitempty"`
+	Mood      string    `json:"mood,omitempty"`
+	Label     string    `json:"label,omitempty"`
+	Publisher string    `json:"publisher,omitempty"`
+	Copyright string    `json:"copyright,omitempty"`
+	ReleaseID string    `json:"release_id,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewPublicTrack returns the public fields of t
+func NewPublicTrack(t *Track) *PublicTrack {
+	return &PublicTrack{
+		ID:        t.ID,
+		ISRC:      t.ISRC(),
+		ISWC:      t.ISWC(),
+		Title:     t.Title(),
+		Artist:    t.Artist(),
+		Album:     t.Album(),
+		Year:      t.Year(),
+		Duration:  t.Duration(),
+		Genre:     t.Genre(),
+		BPM:       t.BPM(),
+		Key:       t.Key(),
+		Mood:      t.Mood(),
+		Label:     t.Label(),
+		Publisher: t.Publisher(),
+		Copyright: t.Copyright(),
+		ReleaseID: t.ReleaseID,
+		Tags:      t.Tags(),
+		UpdatedAt: t.UpdatedAt,
+	}
+}
@@ -2,10 +2,19 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 )
 
+var (
+	// ErrDeadLetterNotFound is returned when a message is not in a dead letter queue
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	// ErrQueueBackpressure is returned when a topic lags too far behind to
+	// accept more messages
+	ErrQueueBackpressure = errors.New("queue is applying backpressure")
+)
+
 // QueuePriority represents the priority level of a queue message
 type QueuePriority int
 
@@ -113,6 +122,95 @@ type QueueService interface {
 	Close() error
 }
 
+// DeadLetterQueue manages the messages that exhausted their retries
+type DeadLetterQueue interface {
+	// ListDeadLetters returns a page of a topic's dead letters, newest first
+	ListDeadLetters(ctx context.Context, topic string, offset, limit int) ([]*Message, error)
+	// CountDeadLetters returns the number of a topic's dead letters
+	CountDeadLetters(ctx context.Context, topic string) (int64, error)
+	// GetDeadLetter returns a dead letter of topic, or nil if there is none with id
+	GetDeadLetter(ctx context.Context, topic, id string) (*Message, error)
+	// ReplayDeadLetter moves a dead letter back to its topic
+	ReplayDeadLetter(ctx context.Context, id string) error
+	// PurgeDeadLetters removes all of a topic's dead letters
+	PurgeDeadLetters(ctx context.Context, topic string) error
+}
+
+// DeadLetterPage is a page of a topic's dead letters
+type DeadLetterPage struct {
+	Topic    string     `json:"topic"`
+	Total    int64      `json:"total"`
+	Offset   int        `json:"offset"`
+	Limit    int        `json:"limit"`
+	Messages []*Message `json:"messages"`
+}
+
+// DeadLetterReplayRequest selects the dead letters of a topic to replay.
+// Without IDs every dead letter of the topic is replayed.
+type DeadLetterReplayRequest struct {
+	IDs []string `json:"ids,omitempty"`
+}
+
+// DeadLetterReplayResult is the outcome of replaying one dead letter
+type DeadLetterReplayResult struct {
+	ID       string `json:"id"`
+	Replayed bool   `json:"replayed"`
+	Error    string `json:"error,omitempty"`
+}
+
+// DeadLetterReplayReport summarizes a bulk replay
+type DeadLetterReplayReport struct {
+	Topic    string                   `json:"topic"`
+	Replayed int                      `json:"replayed"`
+	Failed   int                      `json:"failed"`
+	Results  []DeadLetterReplayResult `json:"results"`
+}
+
+// BackpressureLevel is how strongly publishing to a lagging topic is held back
+type BackpressureLevel string
+
+const (
+	// BackpressureNone accepts messages as published
+	BackpressureNone BackpressureLevel = "none"
+	// BackpressureThrottle downgrades new messages to low priority
+	BackpressureThrottle BackpressureLevel = "throttle"
+	// BackpressureReject refuses new messages
+	BackpressureReject BackpressureLevel = "reject"
+)
+
+// TopicLag is the lag of one topic at its last sample
+type TopicLag struct {
+	Topic string `json:"topic"`
+	// Lag is the number of messages waiting to be consumed
+	Lag int64 `json:"lag"`
+	// PublishRate is the messages published per second since the previous sample
+	PublishRate float64           `json:"publish_rate"`
+	Level       BackpressureLevel `json:"level"`
+	SampledAt   time.Time         `json:"sampled_at"`
+}
+
+// QueueLagAlert reports that a topic's backpressure level changed
+type QueueLagAlert struct {
+	TopicLag
+	Previous  BackpressureLevel `json:"previous"`
+	Threshold int64             `json:"threshold"`
+}
+
+// QueueAlerter delivers queue lag alerts
+type QueueAlerter interface {
+	SendQueueLagAlert(ctx context.Context, alert *QueueLagAlert) error
+}
+
+// QueueAdmitter decides whether a topic accepts a message and tracks its
+// publish rate
+type QueueAdmitter interface {
+	// Admit returns the priority to publish with, or ErrQueueBackpressure
+	// when the topic does not accept messages
+	Admit(topic string, priority QueuePriority) (QueuePriority, error)
+	// RecordPublish counts a message published to topic
+	RecordPublish(topic string)
+}
+
 // QueueConfig holds configuration for the queue service
 type QueueConfig struct {
 	// Connection settings
@@ -129,6 +227,19 @@ type QueueConfig struct {
 	BatchSize         int           `json:"batch_size"`
 	PollInterval      time.Duration `json:"poll_interval"`
 	CleanupInterval   time.Duration `json:"cleanup_interval"`
+	// ShutdownWait is how long Close waits for running handlers before
+	// their messages are requeued
+	ShutdownWait time.Duration `json:"shutdown_wait"`
+
+	// Redis Streams settings. The consumers of ConsumerGroup share the
+	// messages of a topic. ConsumerName defaults to the hostname; it must be
+	// stable across restarts for a consumer to take back the messages it had
+	// not acknowledged. Messages left unacknowledged for ClaimIdle are
+	// claimed by another consumer, checked every ClaimInterval.
+	ConsumerGroup string        `json:"consumer_group"`
+	ConsumerName  string        `json:"consumer_name"`
+	ClaimIdle     time.Duration `json:"claim_idle"`
+	ClaimInterval time.Duration `json:"claim_interval"`
 }
 
 // DefaultQueueConfig returns a default configuration
@@ -141,6 +252,10 @@ func DefaultQueueConfig() QueueConfig {
 		BatchSize:         100,
 		PollInterval:      time.Second,
 		CleanupInterval:   1 * time.Hour,
+		ShutdownWait:      30 * time.Second,
+		ConsumerGroup:     "metadatatool",
+		ClaimIdle:         10 * time.Minute,
+		ClaimInterval:     30 * time.Second,
 	}
 }
 
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RuntimeSettings are the settings that can be changed while the API runs
+type RuntimeSettings struct {
+	// AIMinConfidence is the confidence below which AI results are retried
+	AIMinConfidence float64 `json:"ai_min_confidence"`
+	// ExperimentTrafficPercent is the share of enrichments (0-1) sent to the
+	// experiment provider
+	ExperimentTrafficPercent float64 `json:"experiment_traffic_percent"`
+	// RateLimitPerMinute caps API requests per client; zero disables it
+	RateLimitPerMinute int `json:"rate_limit_per_minute"`
+	// AIForcedProvider sends all AI traffic to one provider during
+	// incidents; empty routes by provider health
+	AIForcedProvider AIProvider `json:"ai_forced_provider,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+}
+
+// Validate checks that the settings are within their allowed ranges
+func (s *RuntimeSettings) Validate() error {
+	if s.AIMinConfidence < 0 || s.AIMinConfidence > 1 {
+		return fmt.Errorf("%w: ai_min_confidence must be between 0 and 1", ErrInvalidInput)
+	}
+	if s.ExperimentTrafficPercent < 0 || s.ExperimentTrafficPercent > 1 {
+		return fmt.Errorf("%w: experiment_traffic_percent must be between 0 and 1", ErrInvalidInput)
+	}
+	if s.RateLimitPerMinute < 0 {
+		return fmt.Errorf("%w: rate_limit_per_minute must not be negative", ErrInvalidInput)
+	}
+	return ValidateAIProvider(s.AIForcedProvider)
+}
+
+// RuntimeSettingsPatch changes the runtime settings that are set
+type RuntimeSettingsPatch struct {
+	AIMinConfidence          *float64 `json:"ai_min_confidence,omitempty"`
+	ExperimentTrafficPercent *float64 `json:"experiment_traffic_percent,omitempty"`
+	RateLimitPerMinute       *int     `json:"rate_limit_per_minute,omitempty"`
+	// AIForcedProvider is cleared by the empty string
+	AIForcedProvider *AIProvider `json:"ai_forced_provider,omitempty"`
+}
+
+// Apply returns a copy of settings with the patch applied
+func (p *RuntimeSettingsPatch) Apply(settings RuntimeSettings) RuntimeSettings {
+	if p.AIMinConfidence != nil {
+		settings.AIMinConfidence = *p.AIMinConfidence
+	}
+	if p.ExperimentTrafficPercent != nil {
+		settings.ExperimentTrafficPercent = *p.ExperimentTrafficPercent
+	}
+	if p.RateLimitPerMinute != nil {
+		settings.RateLimitPerMinute = *p.RateLimitPerMinute
+	}
+	if p.AIForcedProvider != nil {
+		settings.AIForcedProvider = *p.AIForcedProvider
+	}
+	return settings
+}
+
+// RuntimeConfigStore persists the runtime settings shared by all API
+// instances and announces changes to them
+type RuntimeConfigStore interface {
+	// Get returns the stored settings, or nil if none were saved yet
+	Get(ctx context.Context) (*RuntimeSettings, error)
+	// Save stores the settings and notifies subscribers
+	Save(ctx context.Context, settings *RuntimeSettings) error
+	// Subscribe delivers settings saved by any instance until ctx is done
+	Subscribe(ctx context.Context) (<-chan *RuntimeSettings, error)
+}
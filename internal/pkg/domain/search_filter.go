@@ -0,0 +1,302 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SearchFilterKey is the SearchByMetadata query key holding a *SearchFilter
+// that the matching tracks must also meet
+const SearchFilterKey = "filter"
+
+// Limits on the size of a search filter, so that one request cannot build
+// an arbitrarily large query
+const (
+	MaxSearchFilterDepth      = 8
+	MaxSearchFilterConditions = 64
+)
+
+// SearchOp compares a track field in a search filter
+type SearchOp string
+
+const (
+	SearchEq  SearchOp = "eq"
+	SearchNe  SearchOp = "ne"
+	SearchGt  SearchOp = "gt"
+	SearchGte SearchOp = "gte"
+	SearchLt  SearchOp = "lt"
+	SearchLte SearchOp = "lte"
+	// SearchBetween matches numbers within a [min, max] list, inclusive
+	SearchBetween SearchOp = "between"
+	// SearchContains matches text containing the value, ignoring case,
+	// and lists holding it
+	SearchContains SearchOp = "contains"
+	// SearchIn matches fields equal to one of a list of values
+	SearchIn SearchOp = "in"
+	// SearchExists matches fields that are set. It takes no value; wrap it
+	// in not to match blank fields.
+	SearchExists SearchOp = "exists"
+)
+
+// SearchFieldKind is how a searchable field is compared
+type SearchFieldKind string
+
+const (
+	SearchFieldText   SearchFieldKind = "text"
+	SearchFieldNumber SearchFieldKind = "number"
+	SearchFieldList   SearchFieldKind = "list"
+)
+
+// searchFields lists the track fields a search filter can compare. Custom
+// fields, named "custom.<name>", compare as text.
+var searchFields = map[string]SearchFieldKind{
+	"title":      SearchFieldText,
+	"artist":     SearchFieldText,
+	"album":      SearchFieldText,
+	"isrc":       SearchFieldText,
+	"iswc":       SearchFieldText,
+	"label":      SearchFieldText,
+	"territory":  SearchFieldText,
+	"genre":      SearchFieldText,
+	"key":        SearchFieldText,
+	"camelot":    SearchFieldText,
+	"open_key":   SearchFieldText,
+	"mood":       SearchFieldText,
+	"publisher":  SearchFieldText,
+	"copyright":  SearchFieldText,
+	"label_id":   SearchFieldText,
+	"release_id": SearchFieldText,
+	"status":     SearchFieldText,
+	"year":       SearchFieldNumber,
+	"duration":   SearchFieldNumber,
+	"bpm":        SearchFieldNumber,
+	"tags":       SearchFieldList,
+}
+
+// searchOps lists the operators each kind of field supports
+var searchOps = map[SearchFieldKind][]SearchOp{
+	SearchFieldText:   {SearchEq, SearchNe, SearchContains, SearchIn, SearchExists},
+	SearchFieldNumber: {SearchEq, SearchNe, SearchGt, SearchGte, SearchLt, SearchLte, SearchBetween, SearchIn, SearchExists},
+	SearchFieldList:   {SearchContains, SearchExists},
+}
+
+// SearchFieldNames returns the names of the fields a search filter can
+// compare, besides custom fields
+func SearchFieldNames() []string {
+	names := make([]string, 0, len(searchFields))
+	for name := range searchFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SearchFieldKindOf returns how a field is compared, or false when a
+// search filter cannot compare it
+func SearchFieldKindOf(field string) (SearchFieldKind, bool) {
+	if name := strings.TrimPrefix(field, CustomFieldPrefix); name != field {
+		return SearchFieldText, name != ""
+	}
+	kind, ok := searchFields[field]
+	return kind, ok
+}
+
+// SearchFilter is a boolean filter on tracks. Each filter is either a
+// group, combining other filters with and, or or not, or a condition
+// comparing a field with a value, such as
+//
+//	{"and": [
+//	  {"field": "genre", "op": "in", "value": ["house", "techno"]},
+//	  {"field": "bpm", "op": "between", "value": [120, 128]},
+//	  {"not": {"field": "isrc", "op": "exists"}}
+//	]}
+//
+// Blank fields, such as an unknown year, only match ne and not exists.
+type SearchFilter struct {
+	// And matches tracks meeting all of its filters
+	And []*SearchFilter `json:"and,omitempty"`
+	// Or matches tracks meeting any of its filters
+	Or []*SearchFilter `json:"or,omitempty"`
+	// Not matches tracks not meeting its filter
+	Not *SearchFilter `json:"not,omitempty"`
+
+	// Field is a track field such as "bpm" or "genre", or "custom.<name>"
+	// for a custom field
+	Field string   `json:"field,omitempty"`
+	Op    SearchOp `json:"op,omitempty"`
+	// Value is a number or text, a list of them for in, or a [min, max]
+	// list of numbers for between
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Validate checks the filter and its size. Errors name the offending
+// filter by its path, such as "filter.and[1].value".
+func (f *SearchFilter) Validate() []ValidationError {
+	conditions := 0
+	errs := f.validate("filter", 1, &conditions)
+	if conditions > MaxSearchFilterConditions {
+		errs = append(errs, ValidationError{Field: "filter", Code: "too_large",
+			Message: fmt.Sprintf("filter has %d conditions, at most %d are allowed", conditions, MaxSearchFilterConditions)})
+	}
+	return errs
+}
+
+func (f *SearchFilter) validate(path string, depth int, conditions *int) []ValidationError {
+	field := func(name string) string { return path + "." + name }
+	if f == nil {
+		return []ValidationError{{Field: path, Code: "required", Message: "filter is required"}}
+	}
+	if depth > MaxSearchFilterDepth {
+		return []ValidationError{{Field: path, Code: "too_deep",
+			Message: fmt.Sprintf("filters can be nested at most %d deep", MaxSearchFilterDepth)}}
+	}
+
+	parts := 0
+	for _, set := range []bool{f.And != nil, f.Or != nil, f.Not != nil, f.Field != ""} {
+		if set {
+			parts++
+		}
+	}
+	if parts != 1 {
+		return []ValidationError{{Field: path, Code: "invalid",
+			Message: "a filter holds exactly one of and, or, not or field"}}
+	}
+
+	var errs []ValidationError
+	groups := map[string][]*SearchFilter{"and": f.And, "or": f.Or}
+	for _, name := range []string{"and", "or"} {
+		group := groups[name]
+		if group == nil {
+			continue
+		}
+		if len(group) == 0 {
+			errs = append(errs, ValidationError{Field: field(name), Code: "required", Message: "add at least one filter"})
+		}
+		for i, sub := range group {
+			errs = append(errs, sub.validate(fmt.Sprintf("%s[%d]", field(name), i), depth+1, conditions)...)
+		}
+	}
+	if f.Not != nil {
+		errs = append(errs, f.Not.validate(field("not"), depth+1, conditions)...)
+	}
+	if f.Field != "" {
+		*conditions++
+		if name, err := f.validateCondition(); err != nil {
+			errs = append(errs, ValidationError{Field: field(name), Code: "invalid", Message: err.Error()})
+		}
+	}
+	return errs
+}
+
+// validateCondition checks a condition, returning the name of the part in
+// error with the error
+func (f *SearchFilter) validateCondition() (string, error) {
+	kind, ok := SearchFieldKindOf(f.Field)
+	if !ok {
+		return "field", fmt.Errorf("unknown field %q, expected one of %s or %s<name>",
+			f.Field, strings.Join(SearchFieldNames(), ", "), CustomFieldPrefix)
+	}
+	supported := false
+	for _, op := range searchOps[kind] {
+		supported = supported || op == f.Op
+	}
+	if !supported {
+		return "op", fmt.Errorf("%s does not support op %q", f.Field, f.Op)
+	}
+
+	if err := f.validateValue(kind); err != nil {
+		return "value", err
+	}
+	return "", nil
+}
+
+func (f *SearchFilter) validateValue(kind SearchFieldKind) error {
+	switch f.Op {
+	case SearchExists:
+		if f.Value != nil {
+			return fmt.Errorf("exists takes no value")
+		}
+	case SearchGt, SearchGte, SearchLt, SearchLte:
+		if _, ok := ruleNumber(f.Value); !ok {
+			return fmt.Errorf("%s needs a number", f.Op)
+		}
+	case SearchBetween:
+		if _, _, ok := f.Range(); !ok {
+			return fmt.Errorf("between needs a [min, max] list of numbers")
+		}
+	case SearchIn:
+		values, ok := f.Value.([]interface{})
+		if !ok || len(values) == 0 {
+			return fmt.Errorf("in needs a list of values")
+		}
+		for _, v := range values {
+			if err := kind.checkValue(v); err != nil {
+				return fmt.Errorf("in needs %s", err)
+			}
+		}
+	default:
+		if err := kind.checkValue(f.Value); err != nil {
+			return fmt.Errorf("%s needs %s", f.Op, err)
+		}
+	}
+	return nil
+}
+
+// checkValue checks a single value for a field of the kind
+func (k SearchFieldKind) checkValue(v interface{}) error {
+	if k == SearchFieldNumber {
+		if _, ok := ruleNumber(v); !ok {
+			return fmt.Errorf("a number")
+		}
+		return nil
+	}
+	if _, ok := ruleScalar(v); !ok {
+		return fmt.Errorf("a number or text")
+	}
+	return nil
+}
+
+// Range returns the bounds of a between condition
+func (f *SearchFilter) Range() (float64, float64, bool) {
+	bounds, ok := f.Value.([]interface{})
+	if !ok || len(bounds) != 2 {
+		return 0, 0, false
+	}
+	min, minOK := ruleNumber(bounds[0])
+	max, maxOK := ruleNumber(bounds[1])
+	return min, max, minOK && maxOK && min <= max
+}
+
+// Number returns the value of a condition as a number
+func (f *SearchFilter) Number() (float64, bool) {
+	return ruleNumber(f.Value)
+}
+
+// Text returns the value of a condition as text
+func (f *SearchFilter) Text() string {
+	text, _ := ruleScalar(f.Value)
+	return text
+}
+
+// Numbers returns the values of an in condition as numbers
+func (f *SearchFilter) Numbers() []float64 {
+	values, _ := f.Value.([]interface{})
+	numbers := make([]float64, 0, len(values))
+	for _, v := range values {
+		n, _ := ruleNumber(v)
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
+
+// Texts returns the values of an in condition as text
+func (f *SearchFilter) Texts() []string {
+	values, _ := f.Value.([]interface{})
+	texts := make([]string, 0, len(values))
+	for _, v := range values {
+		text, _ := ruleScalar(v)
+		texts = append(texts, text)
+	}
+	return texts
+}
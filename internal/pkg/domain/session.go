@@ -27,11 +27,16 @@ type Session struct {
 	UserID      string       `json:"user_id"`
 	Role        Role         `json:"role"`
 	Permissions []Permission `json:"permissions"`
-	UserAgent   string       `json:"user_agent"`
-	IP          string       `json:"ip"`
-	ExpiresAt   time.Time    `json:"expires_at"`
-	CreatedAt   time.Time    `json:"created_at"`
-	LastSeenAt  time.Time    `json:"last_seen_at"`
+	// TokenFamilyID is the refresh token family issued with the session
+	TokenFamilyID string    `json:"token_family_id,omitempty"`
+	UserAgent     string    `json:"user_agent"`
+	IP            string    `json:"ip"`
+	DeviceID      string    `json:"device_id,omitempty"`
+	DeviceName    string    `json:"device_name,omitempty"`
+	Country       string    `json:"country,omitempty"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	LastSeenAt    time.Time `json:"last_seen_at"`
 }
 
 // HasPermission checks if the session has the given permission
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+)
+
+// ErrChecksumMismatch is returned when a stored file no longer has the
+// checksums recorded at upload
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// IntegrityStatus is the outcome of an integrity check of a stored file
+type IntegrityStatus string
+
+const (
+	// IntegrityOK means the file matches its recorded checksums
+	IntegrityOK IntegrityStatus = "ok"
+	// IntegrityMismatch means the file differs from its recorded checksums
+	IntegrityMismatch IntegrityStatus = "mismatch"
+	// IntegrityRecorded means no checksums were recorded for the file and
+	// the ones just measured were recorded
+	IntegrityRecorded IntegrityStatus = "recorded"
+	// IntegrityUnavailable means the file could not be read, for example
+	// because it is archived
+	IntegrityUnavailable IntegrityStatus = "unavailable"
+)
+
+// IntegrityReport is the result of checking a track's file against the
+// checksums recorded at upload
+type IntegrityReport struct {
+	TrackID        string          `json:"track_id"`
+	Key            string          `json:"key"`
+	Status         IntegrityStatus `json:"status"`
+	ExpectedSHA256 string          `json:"expected_sha256,omitempty"`
+	ActualSHA256   string          `json:"actual_sha256,omitempty"`
+	ExpectedMD5    string          `json:"expected_md5,omitempty"`
+	ActualMD5      string          `json:"actual_md5,omitempty"`
+	Size           int64           `json:"size"`
+	Error          string          `json:"error,omitempty"`
+	CheckedAt      time.Time       `json:"checked_at"`
+}
+
+// Checksums are the hex encoded digests of a file
+type Checksums struct {
+	SHA256 string
+	MD5    string
+}
+
+// Verify compares c with the expected digests, skipping those not recorded
+func (c Checksums) Verify(sha256Hex, md5Hex string) error {
+	if sha256Hex != "" && c.SHA256 != sha256Hex {
+		return fmt.Errorf("%w: SHA-256 is %s, expected %s", ErrChecksumMismatch, c.SHA256, sha256Hex)
+	}
+	if md5Hex != "" && c.MD5 != md5Hex {
+		return fmt.Errorf("%w: MD5 is %s, expected %s", ErrChecksumMismatch, c.MD5, md5Hex)
+	}
+	return nil
+}
+
+// ChecksumReader computes the checksums of the content read through it
+type ChecksumReader struct {
+	r      io.Reader
+	sha256 hash.Hash
+	md5    hash.Hash
+	n      int64
+}
+
+// NewChecksumReader returns a reader that hashes what is read from r
+func NewChecksumReader(r io.Reader) *ChecksumReader {
+	return &ChecksumReader{r: r, sha256: sha256.New(), md5: md5.New()}
+}
+
+// Read implements io.Reader
+func (c *ChecksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.sha256.Write(p[:n])
+	c.md5.Write(p[:n])
+	c.n += int64(n)
+	return n, err
+}
+
+// Sum returns the checksums of the content read so far
+func (c *ChecksumReader) Sum() Checksums {
+	return Checksums{
+		SHA256: hex.EncodeToString(c.sha256.Sum(nil)),
+		MD5:    hex.EncodeToString(c.md5.Sum(nil)),
+	}
+}
+
+// Size returns the number of bytes read so far
+func (c *ChecksumReader) Size() int64 {
+	return c.n
+}
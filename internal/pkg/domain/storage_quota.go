@@ -0,0 +1,59 @@
+package domain
+
+import "context"
+
+// QuotaStore keeps a running count of the bytes every user stores, so the
+// per-user quota is checked without listing the bucket
+type QuotaStore interface {
+	// Reserve adds the size bytes of fileID to userID's usage unless that
+	// takes it above limit, in which case it returns ErrQuotaExceeded. It
+	// returns the usage afterwards; reserving a file again changes nothing.
+	Reserve(ctx context.Context, userID, fileID string, size, limit int64) (int64, error)
+
+	// Release removes fileID from its user's usage and returns that user
+	// and their usage afterwards. Unknown files return an empty user.
+	Release(ctx context.Context, fileID string) (string, int64, error)
+
+	// Usage returns the bytes userID stores
+	Usage(ctx context.Context, userID string) (int64, error)
+}
+
+// QuotaWarning reports that a user's storage usage crossed the warning
+// threshold of their quota
+type QuotaWarning struct {
+	UserID  string `json:"user_id"`
+	Used    int64  `json:"used"`
+	Limit   int64  `json:"limit"`
+	Percent int    `json:"percent"`
+}
+
+// QuotaNotifier sends quota warnings
+type QuotaNotifier interface {
+	SendQuotaWarning(ctx context.Context, warning *QuotaWarning) error
+}
+
+// UsageCounter keeps a running total of the bytes in storage
+type UsageCounter interface {
+	// Add changes the total by delta bytes
+	Add(ctx context.Context, delta int64) error
+
+	// Total returns the total, and false when it was never set
+	Total(ctx context.Context) (int64, bool, error)
+
+	// Set replaces the total with a measured one
+	Set(ctx context.Context, total int64) error
+}
+
+// UsageTracker is implemented by storage services that can keep their
+// quota usage in a UsageCounter instead of measuring it on every upload
+type UsageTracker interface {
+	// TrackUsage keeps the usage in counter from now on
+	TrackUsage(counter UsageCounter)
+
+	// AddUsage counts delta bytes stored without going through the
+	// service, such as a direct upload
+	AddUsage(ctx context.Context, delta int64)
+
+	// ReconcileUsage measures the usage and corrects the counter with it
+	ReconcileUsage(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// StorageClass is the class of storage a file is kept in, trading access
+// speed for cost
+type StorageClass string
+
+const (
+	StorageClassStandard         StorageClass = "STANDARD"
+	StorageClassInfrequentAccess StorageClass = "STANDARD_IA"
+	StorageClassArchive          StorageClass = "GLACIER"
+)
+
+// ArchiveState tells whether an archived file can be read
+type ArchiveState string
+
+const (
+	// ArchiveStateAvailable files are not archived and can be read
+	ArchiveStateAvailable ArchiveState = "available"
+	// ArchiveStateArchived files must be restored before they can be read
+	ArchiveStateArchived ArchiveState = "archived"
+	// ArchiveStateRestoring files are being retrieved from the archive
+	ArchiveStateRestoring ArchiveState = "restoring"
+	// ArchiveStateRestored files are archived but a readable copy exists
+	// until RestoredUntil
+	ArchiveStateRestored ArchiveState = "restored"
+)
+
+// Readable reports whether a file in the state can be downloaded
+func (s ArchiveState) Readable() bool {
+	return s == ArchiveStateAvailable || s == ArchiveStateRestored
+}
+
+// ArchiveStatus describes where a file is stored and whether it can be read
+type ArchiveStatus struct {
+	Key           string       `json:"key"`
+	StorageClass  StorageClass `json:"storage_class"`
+	State         ArchiveState `json:"state"`
+	RestoredUntil *time.Time   `json:"restored_until,omitempty"`
+}
+
+// LifecyclePolicy moves the files below Prefix to cheaper storage classes as
+// they age. A zero number of days skips that transition.
+type LifecyclePolicy struct {
+	Prefix                    string
+	InfrequentAccessAfterDays int
+	ArchiveAfterDays          int
+}
+
+// IsEmpty reports whether the policy has no transitions
+func (p LifecyclePolicy) IsEmpty() bool {
+	return p.InfrequentAccessAfterDays <= 0 && p.ArchiveAfterDays <= 0
+}
+
+// StorageTierer is implemented by storage services that keep files in
+// storage classes and can archive them
+type StorageTierer interface {
+	// ApplyLifecycle installs policy, replacing the policy applied before
+	ApplyLifecycle(ctx context.Context, policy LifecyclePolicy) error
+
+	// ArchiveStatus returns the storage class and archive state of a file
+	ArchiveStatus(ctx context.Context, key string) (*ArchiveStatus, error)
+
+	// Restore starts retrieving an archived file, keeping the readable copy
+	// for days
+	Restore(ctx context.Context, key string, days int) error
+}
+
+// RestoreNotice reports that an archived track file can be read again
+type RestoreNotice struct {
+	TrackID       string     `json:"track_id"`
+	Key           string     `json:"key"`
+	RequestedBy   string     `json:"requested_by,omitempty"`
+	RequestedAt   time.Time  `json:"requested_at"`
+	RestoredUntil *time.Time `json:"restored_until,omitempty"`
+}
+
+// RestoreNotifier delivers restore notices
+type RestoreNotifier interface {
+	SendRestoreCompleted(ctx context.Context, notice *RestoreNotice) error
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// SystemStats is a snapshot of the operational state served to the ops
+// dashboard. A section whose source failed is left empty and its error is
+// reported in Errors.
+type SystemStats struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Queues      []QueueStats      `json:"queues"`
+	QueueLag    []TopicLag        `json:"queue_lag"`
+	Jobs        *JobStats         `json:"jobs,omitempty"`
+	Storage     *StorageStats     `json:"storage,omitempty"`
+	AIProviders []AIProviderStats `json:"ai_providers"`
+	Database    *DatabaseStats    `json:"database,omitempty"`
+	Errors      map[string]string `json:"errors,omitempty"`
+}
+
+// QueueStats reports the depth of one queue
+type QueueStats struct {
+	Name        string `json:"name"`
+	Pending     int64  `json:"pending"`
+	Processing  int64  `json:"processing"`
+	DeadLetters int64  `json:"dead_letters"`
+}
+
+// JobStats reports how background jobs finished within a recent window
+type JobStats struct {
+	WindowSeconds int64   `json:"window_seconds"`
+	Completed     int64   `json:"completed"`
+	Failed        int64   `json:"failed"`
+	FailureRate   float64 `json:"failure_rate"`
+}
+
+// StorageStats reports the storage used against the configured quota. A
+// zero quota means no quota is enforced.
+type StorageStats struct {
+	UsedBytes   int64   `json:"used_bytes"`
+	QuotaBytes  int64   `json:"quota_bytes"`
+	UsedPercent float64 `json:"used_percent"`
+}
+
+// AIProviderStats reports the health of one AI provider since the API started
+type AIProviderStats struct {
+	Provider         AIProvider `json:"provider"`
+	Healthy          bool       `json:"healthy"`
+	Requests         int64      `json:"requests"`
+	Failures         int64      `json:"failures"`
+	FailureRate      float64    `json:"failure_rate"`
+	AverageLatencyMs int64      `json:"average_latency_ms"`
+	QueueDepth       int        `json:"queue_depth"`
+	LastSuccess      *time.Time `json:"last_success,omitempty"`
+	LastError        string     `json:"last_error,omitempty"`
+}
+
+// DatabaseStats reports the primary database connection pool
+type DatabaseStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+}
+
+// QueueStatsSource reports the depth of the queues it manages
+type QueueStatsSource interface {
+	QueueStats(ctx context.Context) ([]QueueStats, error)
+}
+
+// JobStatsSource reports how jobs finished within window
+type JobStatsSource interface {
+	JobStats(ctx context.Context, window time.Duration) (*JobStats, error)
+}
+
+// QueueLagSource reports the lag and backpressure of each monitored topic
+type QueueLagSource interface {
+	Lags() []TopicLag
+}
+
+// AIProviderStatsSource reports the health of the AI providers it calls
+type AIProviderStatsSource interface {
+	ProviderStats() []AIProviderStats
+}
@@ -0,0 +1,339 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	// ErrTagNotFound is returned when a tag is not in the catalog
+	ErrTagNotFound = errors.New("tag not found")
+	// ErrTagRuleNotFound is returned when a tagging rule does not exist
+	ErrTagRuleNotFound = errors.New("tag rule not found")
+)
+
+// Tag is a catalog tag. Tracks carry tags by name.
+type Tag struct {
+	Name        string    `json:"name" gorm:"primaryKey"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for tags
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// TagRepository stores the catalog's tags
+type TagRepository interface {
+	// Save creates or replaces a tag
+	Save(ctx context.Context, tag *Tag) error
+	// Get returns a tag, or ErrTagNotFound
+	Get(ctx context.Context, name string) (*Tag, error)
+	// List returns the tags ordered by name
+	List(ctx context.Context) ([]*Tag, error)
+	// Delete removes a tag
+	Delete(ctx context.Context, name string) error
+}
+
+// NormalizeTag returns the canonical form of a tag name: lower case, with
+// runs of spaces, underscores and hyphens written as one hyphen, such as
+// "drum-and-bass" for "Drum and Bass"
+func NormalizeTag(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsSpace(r) || r == '_' || r == '-' {
+			hyphen = b.Len() > 0
+			continue
+		}
+		if hyphen {
+			b.WriteRune('-')
+			hyphen = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// NormalizeTags writes the tags of a track in canonical form, dropping
+// empty and repeated ones. It reports whether the tags changed.
+func (t *Track) NormalizeTags() bool {
+	tags := t.Metadata.Additional.Tags
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if tag = NormalizeTag(tag); tag != "" && !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) == len(tags) {
+		changed := false
+		for i := range tags {
+			changed = changed || tags[i] != normalized[i]
+		}
+		if !changed {
+			return false
+		}
+	}
+	t.Metadata.Additional.Tags = normalized
+	return true
+}
+
+// ReplaceTag replaces the tag from with the tag to on a track, or removes
+// it when to is empty. Tags are compared in canonical form. It returns the
+// change, or nil when the track does not carry from.
+func (t *Track) ReplaceTag(from, to string) []FieldChange {
+	old := t.Tags()
+	tags := make([]string, 0, len(old))
+	found := false
+	for _, tag := range old {
+		if NormalizeTag(tag) == from {
+			found = true
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	if !found {
+		return nil
+	}
+	t.Metadata.Additional.Tags = tags
+	if to != "" && !t.HasTag(to) {
+		t.Metadata.Additional.Tags = append(t.Metadata.Additional.Tags, to)
+	}
+	return []FieldChange{{Field: "tags", OldValue: old, NewValue: t.Tags()}}
+}
+
+// TagRuleOp compares a track field with the value of a rule condition
+type TagRuleOp string
+
+const (
+	TagRuleEq  TagRuleOp = "eq"
+	TagRuleNe  TagRuleOp = "ne"
+	TagRuleGt  TagRuleOp = "gt"
+	TagRuleGte TagRuleOp = "gte"
+	TagRuleLt  TagRuleOp = "lt"
+	TagRuleLte TagRuleOp = "lte"
+	// TagRuleContains matches text containing the value, ignoring case,
+	// and lists holding it
+	TagRuleContains TagRuleOp = "contains"
+	// TagRuleIn matches fields equal to one of a list of values
+	TagRuleIn TagRuleOp = "in"
+)
+
+// TagCondition compares a track field with a value
+type TagCondition struct {
+	// Field is a track field such as "bpm" or "genre", or "custom.<name>"
+	// for a custom field
+	Field string    `json:"field"`
+	Op    TagRuleOp `json:"op"`
+	// Value is a number or text, or a list of them for in
+	Value interface{} `json:"value"`
+}
+
+// TagRule adds a tag to the tracks matching all of its conditions when
+// they are saved
+type TagRule struct {
+	ID         string         `json:"id" gorm:"primaryKey"`
+	Name       string         `json:"name"`
+	Tag        string         `json:"tag" gorm:"index;not null"`
+	Conditions []TagCondition `json:"conditions" gorm:"serializer:json"`
+	// Disabled rules are kept but not applied
+	Disabled  bool      `json:"disabled,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for tagging rules
+func (TagRule) TableName() string {
+	return "tag_rules"
+}
+
+// TagRuleRepository stores tagging rules
+type TagRuleRepository interface {
+	// Save creates or replaces a rule
+	Save(ctx context.Context, rule *TagRule) error
+	// Get returns a rule, or ErrTagRuleNotFound
+	Get(ctx context.Context, id string) (*TagRule, error)
+	// List returns the rules ordered by name
+	List(ctx context.Context) ([]*TagRule, error)
+	// Delete removes a rule
+	Delete(ctx context.Context, id string) error
+}
+
+// Validate checks the rule and writes its tag in canonical form
+func (r *TagRule) Validate() []ValidationError {
+	var errs []ValidationError
+	if r.Tag = NormalizeTag(r.Tag); r.Tag == "" {
+		errs = append(errs, ValidationError{Field: "tag", Code: "required", Message: "tag is required"})
+	}
+	if len(r.Conditions) == 0 {
+		errs = append(errs, ValidationError{Field: "conditions", Code: "required", Message: "add at least one condition"})
+	}
+	for i, cond := range r.Conditions {
+		if err := cond.validate(); err != nil {
+			errs = append(errs, ValidationError{Field: fmt.Sprintf("conditions[%d]", i), Message: err.Error()})
+		}
+	}
+	return errs
+}
+
+// Matches reports whether a track meets all of the rule's conditions
+func (r *TagRule) Matches(track *Track) bool {
+	for _, cond := range r.Conditions {
+		if !cond.matches(track) {
+			return false
+		}
+	}
+	return len(r.Conditions) > 0
+}
+
+// ApplyTagRules adds the tags of the enabled rules a track matches and
+// returns the tags added
+func ApplyTagRules(track *Track, rules []*TagRule) []string {
+	var added []string
+	for _, rule := range rules {
+		if rule.Disabled || track.HasTag(rule.Tag) || !rule.Matches(track) {
+			continue
+		}
+		track.Metadata.Additional.Tags = append(track.Metadata.Additional.Tags, rule.Tag)
+		added = append(added, rule.Tag)
+	}
+	return added
+}
+
+func (c TagCondition) validate() error {
+	if _, ok := c.fieldValue(&Track{}); !ok {
+		return fmt.Errorf("unknown field %q", c.Field)
+	}
+	switch c.Op {
+	case TagRuleEq, TagRuleNe, TagRuleContains:
+		if _, ok := ruleScalar(c.Value); !ok {
+			return fmt.Errorf("%s needs a number or text", c.Op)
+		}
+	case TagRuleGt, TagRuleGte, TagRuleLt, TagRuleLte:
+		if _, ok := ruleNumber(c.Value); !ok {
+			return fmt.Errorf("%s needs a number", c.Op)
+		}
+	case TagRuleIn:
+		values, ok := c.Value.([]interface{})
+		if !ok || len(values) == 0 {
+			return errors.New("in needs a list of values")
+		}
+		for _, v := range values {
+			if _, ok := ruleScalar(v); !ok {
+				return errors.New("in needs numbers or text")
+			}
+		}
+	default:
+		return fmt.Errorf("unknown op %q", c.Op)
+	}
+	return nil
+}
+
+// fieldValue returns the value of the condition's field on track
+func (c TagCondition) fieldValue(track *Track) (interface{}, bool) {
+	if name := strings.TrimPrefix(c.Field, CustomFieldPrefix); name != c.Field {
+		return track.Metadata.Additional.CustomFields[name], name != ""
+	}
+	return track.FieldValue(c.Field)
+}
+
+// matches compares the condition's field on track with its value. Blank
+// fields, such as an unknown tempo, only match ne.
+func (c TagCondition) matches(track *Track) bool {
+	value, _ := c.fieldValue(track)
+	if isBlankField(value) {
+		return c.Op == TagRuleNe
+	}
+	switch c.Op {
+	case TagRuleEq:
+		return ruleEqual(value, c.Value)
+	case TagRuleNe:
+		return !ruleEqual(value, c.Value)
+	case TagRuleGt, TagRuleGte, TagRuleLt, TagRuleLte:
+		have, ok := ruleNumber(value)
+		want, _ := ruleNumber(c.Value)
+		if !ok {
+			return false
+		}
+		switch c.Op {
+		case TagRuleGt:
+			return have > want
+		case TagRuleGte:
+			return have >= want
+		case TagRuleLt:
+			return have < want
+		}
+		return have <= want
+	case TagRuleContains:
+		want, _ := ruleScalar(c.Value)
+		if list, ok := value.([]string); ok {
+			for _, item := range list {
+				if strings.EqualFold(item, want) {
+					return true
+				}
+			}
+			return false
+		}
+		have, _ := ruleScalar(value)
+		return strings.Contains(strings.ToLower(have), strings.ToLower(want))
+	case TagRuleIn:
+		values, _ := c.Value.([]interface{})
+		for _, v := range values {
+			if ruleEqual(value, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ruleEqual compares a field value with a condition value: as numbers when
+// both are numbers, else as text ignoring case
+func ruleEqual(value, want interface{}) bool {
+	if have, ok := ruleNumber(value); ok {
+		if w, ok := ruleNumber(want); ok {
+			return have == w
+		}
+	}
+	have, _ := ruleScalar(value)
+	w, _ := ruleScalar(want)
+	return strings.EqualFold(strings.TrimSpace(have), strings.TrimSpace(w))
+}
+
+// ruleScalar returns the text of a number or text value
+func ruleScalar(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(v), true
+	case TrackStatus:
+		return string(v), true
+	}
+	return "", false
+}
+
+// ruleNumber returns the number of a number value or of text holding one
+func ruleNumber(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTokenReused is returned when a refresh token that was already rotated
+// is presented again. The whole token family is revoked when this happens.
+var ErrTokenReused = errors.New("refresh token reuse detected")
+
+// RefreshTokenStore tracks the current refresh token of each token family and
+// which families have been revoked.
+//
+// Every login starts a family. Each refresh replaces the family's current
+// token; presenting any earlier token means it was leaked, so the family is
+// revoked and every token in it, access tokens included, stops working.
+// Families are recorded per user so signing a user out everywhere revokes
+// all of them at once.
+type RefreshTokenStore interface {
+	// Issue starts a family of the user with tokenID as its current refresh
+	// token
+	Issue(ctx context.Context, userID, familyID, tokenID string, ttl time.Duration) error
+	// Rotate replaces presentedID with nextID. If presentedID is not the
+	// current token the family is revoked and ErrTokenReused is returned.
+	Rotate(ctx context.Context, familyID, presentedID, nextID string, ttl time.Duration) error
+	// RevokeFamily revokes every token in a family
+	RevokeFamily(ctx context.Context, familyID string, ttl time.Duration) error
+	// RevokeUser revokes every family of the user along with every token
+	// issued to the user until now
+	RevokeUser(ctx context.Context, userID string, ttl time.Duration) error
+	// IsRevoked reports whether a family has been revoked
+	IsRevoked(ctx context.Context, familyID string) (bool, error)
+	// RevokedBefore returns when the user's tokens were last revoked, or the
+	// zero time if they never were
+	RevokedBefore(ctx context.Context, userID string) (time.Time, error)
+}
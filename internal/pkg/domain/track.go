@@ -2,7 +2,6 @@ package domain
 
 import (
 	"context"
-	"fmt"
 	"time"
 )
 
@@ -18,14 +17,14 @@ type Track struct {
 	StoragePath string `json:"storagePath"`
 	FilePath    string `json:"filePath"` // Deprecated: use StoragePath
 	FileSize    int64  `json:"fileSize"`
-	AudioData   []byte `json:"-"` // In-memory audio data for processing
+	AudioData   []byte `json:"-" gorm:"-"` // In-memory audio data for processing
 
-	// Track metadata
-	Metadata CompleteTrackMetadata `json:"metadata"`
+	// Track metadata, stored as a JSON document
+	Metadata CompleteTrackMetadata `json:"metadata" gorm:"serializer:json"`
 
 	// Relationships
 	LabelID   string   `json:"labelId"`
-	ArtistIDs []string `json:"artistIds"`
+	ArtistIDs []string `json:"artistIds" gorm:"serializer:json"`
 	ReleaseID string   `json:"releaseId"`
 
 	// Versioning
@@ -35,30 +34,37 @@ type Track struct {
 	// Status
 	Status    TrackStatus `json:"status"`
 	StatusMsg string      `json:"statusMsg,omitempty"`
+
+	// Lock is set on reads while a worker processes the track. It is not
+	// stored with the track and is ignored in requests.
+	Lock *TrackLock `json:"lock,omitempty" gorm:"-"`
 }
 
 // TrackStatus represents the current status of a track
 type TrackStatus string
 
 const (
-	// Track status constants
-	TrackStatusDraft    TrackStatus = "draft"
-	TrackStatusPending  TrackStatus = "pending"
+	// Track status constants. The status workflow moves tracks from pending
+	// through processing, needs_review, approved and delivered to archived.
+	TrackStatusDraft       TrackStatus = "draft"
+	TrackStatusPending     TrackStatus = "pending"
+	TrackStatusProcessing  TrackStatus = "processing"
+	TrackStatusNeedsReview TrackStatus = "needs_review"
+	TrackStatusApproved    TrackStatus = "approved"
+	TrackStatusDelivered   TrackStatus = "delivered"
+	TrackStatusArchived    TrackStatus = "archived"
+	TrackStatusRejected    TrackStatus = "rejected"
+	// Active, inactive and deleted predate the workflow. Tracks still in
+	// them can move into the workflow but not back.
 	TrackStatusActive   TrackStatus = "active"
 	TrackStatusInactive TrackStatus = "inactive"
-	TrackStatusRejected TrackStatus = "rejected"
 	TrackStatusDeleted  TrackStatus = "deleted"
 )
 
 // IsValid checks if the track status is valid
 func (s TrackStatus) IsValid() bool {
-	switch s {
-	case TrackStatusDraft, TrackStatusPending, TrackStatusActive,
-		TrackStatusInactive, TrackStatusRejected, TrackStatusDeleted:
-		return true
-	default:
-		return false
-	}
+	_, ok := trackStatusTransitions[s]
+	return ok
 }
 
 // String returns the string representation of the track status
@@ -79,6 +85,7 @@ func (t *Track) Territory() string   { return t.Metadata.Additional.CustomFields
 func (t *Track) Genre() string       { return t.Metadata.Musical.Genre }
 func (t *Track) BPM() float64        { return t.Metadata.Musical.BPM }
 func (t *Track) Key() string         { return t.Metadata.Musical.Key }
+func (t *Track) Mode() string        { return t.Metadata.Musical.Mode }
 func (t *Track) Mood() string        { return t.Metadata.Musical.Mood }
 func (t *Track) AudioFormat() string { return string(t.Metadata.Technical.Format) }
 func (t *Track) SampleRate() int     { return t.Metadata.Technical.SampleRate }
@@ -87,12 +94,20 @@ func (t *Track) Channels() int       { return t.Metadata.Technical.Channels }
 func (t *Track) Publisher() string   { return t.Metadata.Additional.Publisher }
 func (t *Track) Copyright() string   { return t.Metadata.Additional.Copyright }
 func (t *Track) Lyrics() string      { return t.Metadata.Additional.Lyrics }
+func (t *Track) Tags() []string      { return t.Metadata.Additional.Tags }
+
+// AI-related fields, which are blank until the track is enriched
+func (t *Track) AITags() []string      { return t.ai().Tags }
+func (t *Track) AIConfidence() float64 { return t.ai().Confidence }
+func (t *Track) ModelVersion() string  { return t.ai().Version }
+func (t *Track) NeedsReview() bool     { return t.ai().NeedsReview }
 
-// AI-related fields
-func (t *Track) AITags() []string      { return t.Metadata.AI.Tags }
-func (t *Track) AIConfidence() float64 { return t.Metadata.AI.Confidence }
-func (t *Track) ModelVersion() string  { return t.Metadata.AI.Version }
-func (t *Track) NeedsReview() bool     { return t.Metadata.AI.NeedsReview }
+func (t *Track) ai() *TrackAIMetadata {
+	if t.Metadata.AI == nil {
+		return &TrackAIMetadata{}
+	}
+	return t.Metadata.AI
+}
 
 // Helper methods to set metadata fields
 func (t *Track) SetTitle(v string)       { t.Metadata.Title = v }
@@ -101,12 +116,13 @@ func (t *Track) SetAlbum(v string)       { t.Metadata.Album = v }
 func (t *Track) SetYear(v int)           { t.Metadata.Year = v }
 func (t *Track) SetDuration(v float64)   { t.Metadata.Duration = v }
 func (t *Track) SetISRC(v string)        { t.Metadata.ISRC = v }
-func (t *Track) SetISWC(v string)        { t.Metadata.Additional.CustomFields["iswc"] = v }
-func (t *Track) SetLabel(v string)       { t.Metadata.Additional.CustomFields["label"] = v }
-func (t *Track) SetTerritory(v string)   { t.Metadata.Additional.CustomFields["territory"] = v }
+func (t *Track) SetISWC(v string)        { t.setCustomField("iswc", v) }
+func (t *Track) SetLabel(v string)       { t.setCustomField("label", v) }
+func (t *Track) SetTerritory(v string)   { t.setCustomField("territory", v) }
 func (t *Track) SetGenre(v string)       { t.Metadata.Musical.Genre = v }
 func (t *Track) SetBPM(v float64)        { t.Metadata.Musical.BPM = v }
 func (t *Track) SetKey(v string)         { t.Metadata.Musical.Key = v }
+func (t *Track) SetMode(v string)        { t.Metadata.Musical.Mode = v }
 func (t *Track) SetMood(v string)        { t.Metadata.Musical.Mood = v }
 func (t *Track) SetAudioFormat(v string) { t.Metadata.Technical.Format = AudioFormat(v) }
 func (t *Track) SetSampleRate(v int)     { t.Metadata.Technical.SampleRate = v }
@@ -116,6 +132,48 @@ func (t *Track) SetPublisher(v string)   { t.Metadata.Additional.Publisher = v }
 func (t *Track) SetCopyright(v string)   { t.Metadata.Additional.Copyright = v }
 func (t *Track) SetLyrics(v string)      { t.Metadata.Additional.Lyrics = v }
 
+// Clone returns a deep copy of the track's mutable state
+func (t *Track) Clone() *Track {
+	cp := *t
+	cp.ArtistIDs = append([]string(nil), t.ArtistIDs...)
+	cp.Metadata.Additional.Tags = append([]string(nil), t.Metadata.Additional.Tags...)
+	cp.Metadata.Additional.CustomTags = copyStringMap(t.Metadata.Additional.CustomTags)
+	cp.Metadata.Additional.CustomFields = copyStringMap(t.Metadata.Additional.CustomFields)
+	if t.Metadata.AI != nil {
+		ai := *t.Metadata.AI
+		ai.Tags = append([]string(nil), t.Metadata.AI.Tags...)
+		cp.Metadata.AI = &ai
+	}
+	if t.Metadata.Provenance != nil {
+		cp.Metadata.Provenance = make(map[string]FieldProvenance, len(t.Metadata.Provenance))
+		for k, v := range t.Metadata.Provenance {
+			cp.Metadata.Provenance[k] = v
+		}
+	}
+	return &cp
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	cp := make(map[string]string, len(m))
+	for k, v := range m {
+		cp[k] = v
+	}
+	return cp
+}
+
+// HasTag reports whether the track carries the given tag
+func (t *Track) HasTag(tag string) bool {
+	for _, existing := range t.Metadata.Additional.Tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
 // Additional helper methods for relationships
 func (t *Track) SetLabelID(id string) {
 	t.LabelID = id
@@ -160,8 +218,8 @@ func (t *Track) SetPreviousVersion(id string) {
 
 // Status management methods
 func (t *Track) SetStatus(status TrackStatus, msg string) error {
-	if !status.IsValid() {
-		return fmt.Errorf("invalid track status: %s", status)
+	if err := CheckStatusTransition(t.Status, status); err != nil {
+		return err
 	}
 	t.Status = status
 	t.StatusMsg = msg
@@ -177,7 +235,9 @@ type TrackRepository interface {
 	// GetByID retrieves a track by ID
 	GetByID(ctx context.Context, id string) (*Track, error)
 
-	// Update updates an existing track
+	// Update updates an existing track. track.Version must hold the version the
+	// caller last read; the update is rejected with a *VersionConflictError if the
+	// stored track has moved on. On success track.Version is incremented.
 	Update(ctx context.Context, track *Track) error
 
 	// Delete soft-deletes a track
@@ -192,6 +252,32 @@ type TrackRepository interface {
 	// GetByISRC retrieves a track by ISRC
 	GetByISRC(ctx context.Context, isrc string) (*Track, error)
 
-	// BatchUpdate updates multiple tracks in a single transaction
+	// BatchUpdate updates multiple tracks in a single transaction, applying the
+	// same version check as Update to every track
 	BatchUpdate(ctx context.Context, tracks []*Track) error
 }
+
+// TrackBulkWriter is implemented by track repositories that can insert many
+// tracks at once. Imports use it when available instead of calling Create
+// for every track.
+type TrackBulkWriter interface {
+	// BatchCreate inserts all tracks in a single transaction
+	BatchCreate(ctx context.Context, tracks []*Track) error
+}
+
+// CatalogStats summarizes the tracks in the catalog
+type CatalogStats struct {
+	// TracksByStatus counts the tracks by status
+	TracksByStatus map[TrackStatus]int64
+	// NeedsReview counts tracks whose AI metadata is flagged for review
+	NeedsReview int64
+	// Unenriched counts tracks without AI metadata
+	Unenriched int64
+	// AverageConfidence is the mean AI confidence of enriched tracks
+	AverageConfidence float64
+}
+
+// CatalogStatsRepository computes catalog statistics
+type CatalogStatsRepository interface {
+	CatalogStats(ctx context.Context) (*CatalogStats, error)
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrAnalysisNotFound is returned when a track has no stored audio analysis
+var ErrAnalysisNotFound = errors.New("track has no audio analysis")
+
+// TrackAnalysis is the audio analysis of a track by one version of the
+// analyzer. The analysis of every version is kept, so tracks a new
+// analyzer has not reached yet still have the previous one.
+type TrackAnalysis struct {
+	TrackID string `json:"track_id"`
+	// AnalyzerVersion names the analyzer that produced the analysis, such
+	// as "basic_analyzer_v1"
+	AnalyzerVersion string         `json:"analyzer_version"`
+	Analysis        *AudioAnalysis `json:"analysis"`
+	AnalyzedAt      time.Time      `json:"analyzed_at"`
+}
+
+// ReanalysisTarget is a track whose audio is to be analyzed again
+type ReanalysisTarget struct {
+	TrackID     string
+	StoragePath string
+}
+
+// AnalysisRepository stores the audio analyses of tracks
+type AnalysisRepository interface {
+	// Save creates or replaces the analysis of a track by its analyzer
+	// version
+	Save(ctx context.Context, analysis *TrackAnalysis) error
+	// Get returns the analysis of a track by version, or the latest when
+	// version is empty. It returns ErrAnalysisNotFound when there is none.
+	Get(ctx context.Context, trackID, version string) (*TrackAnalysis, error)
+	// Versions counts the analyzed tracks by analyzer version
+	Versions(ctx context.Context) (map[string]int64, error)
+	// Outdated returns up to limit tracks with stored audio that were
+	// analyzed by other versions but not by version, in order of ID after
+	// afterID
+	Outdated(ctx context.Context, version, afterID string, limit int) ([]ReanalysisTarget, error)
+}
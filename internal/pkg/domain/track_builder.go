@@ -0,0 +1,255 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// iswcPattern matches an ISWC such as T-345.246.800-1, with or without
+// the separators
+var iswcPattern = regexp.MustCompile(`^T-?\d{3}\.?\d{3}\.?\d{3}-?\d$`)
+
+// territoryPattern matches an ISO 3166-1 alpha-2 country code, or WW for
+// worldwide
+var territoryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// MetadataBuilder builds track metadata through typed setters, so callers
+// need not know where each field lives in CompleteTrackMetadata. Setters
+// normalize their values; Build checks the invariants of the metadata and
+// reports every violation at once.
+type MetadataBuilder struct {
+	metadata CompleteTrackMetadata
+	errs     []ValidationError
+}
+
+// NewMetadataBuilder starts metadata stamped with the current time
+func NewMetadataBuilder() *MetadataBuilder {
+	now := time.Now()
+	b := &MetadataBuilder{}
+	b.metadata.CreatedAt = now
+	b.metadata.UpdatedAt = now
+	return b
+}
+
+// Title sets the title, which is required
+func (b *MetadataBuilder) Title(v string) *MetadataBuilder {
+	b.metadata.Title = strings.TrimSpace(v)
+	return b
+}
+
+// Artist sets the display artist, which is required
+func (b *MetadataBuilder) Artist(v string) *MetadataBuilder {
+	b.metadata.Artist = strings.TrimSpace(v)
+	return b
+}
+
+// Album sets the album title
+func (b *MetadataBuilder) Album(v string) *MetadataBuilder {
+	b.metadata.Album = strings.TrimSpace(v)
+	return b
+}
+
+// Year sets the release year; zero leaves it unknown
+func (b *MetadataBuilder) Year(v int) *MetadataBuilder {
+	b.check("year", v == 0 || (v >= 1900 && v <= time.Now().Year()+1), fmt.Sprintf("%d is not a release year", v))
+	b.metadata.Year = v
+	return b
+}
+
+// Duration sets the playing time
+func (b *MetadataBuilder) Duration(v time.Duration) *MetadataBuilder {
+	b.check("duration", v >= 0, "duration cannot be negative")
+	b.metadata.Duration = v.Seconds()
+	return b
+}
+
+// ISRC sets the recording code, dropping hyphens and upper-casing it
+func (b *MetadataBuilder) ISRC(v string) *MetadataBuilder {
+	v = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(v), "-", ""))
+	b.check("isrc", v == "" || isValidISRC(v), "Invalid ISRC format")
+	b.metadata.ISRC = v
+	return b
+}
+
+// ISWC sets the work code
+func (b *MetadataBuilder) ISWC(v string) *MetadataBuilder {
+	v = strings.ToUpper(strings.TrimSpace(v))
+	b.check("iswc", v == "" || iswcPattern.MatchString(v), "Invalid ISWC format")
+	b.customField("iswc", v)
+	return b
+}
+
+// Label sets the name of the releasing label. The label the track belongs
+// to is Track.LabelID.
+func (b *MetadataBuilder) Label(v string) *MetadataBuilder {
+	b.customField("label", strings.TrimSpace(v))
+	return b
+}
+
+// Territory sets the country code of the release territory
+func (b *MetadataBuilder) Territory(v string) *MetadataBuilder {
+	v = strings.ToUpper(strings.TrimSpace(v))
+	b.check("territory", v == "" || territoryPattern.MatchString(v), fmt.Sprintf("%q is not a country code", v))
+	b.customField("territory", v)
+	return b
+}
+
+// Genre sets the genre
+func (b *MetadataBuilder) Genre(v string) *MetadataBuilder {
+	b.metadata.Musical.Genre = strings.TrimSpace(v)
+	return b
+}
+
+// Mood sets the mood
+func (b *MetadataBuilder) Mood(v string) *MetadataBuilder {
+	b.metadata.Musical.Mood = strings.TrimSpace(v)
+	return b
+}
+
+// BPM sets the tempo; zero leaves it unknown
+func (b *MetadataBuilder) BPM(v float64) *MetadataBuilder {
+	b.check("bpm", v == 0 || isValidBPM(v), fmt.Sprintf("%g is not a tempo", v))
+	b.metadata.Musical.BPM = v
+	return b
+}
+
+// Key sets the musical key in any notation ParseKey reads, and the mode
+// it implies
+func (b *MetadataBuilder) Key(v string) *MetadataBuilder {
+	v = strings.TrimSpace(v)
+	b.metadata.Musical.Key = v
+	b.metadata.Musical.Mode = ""
+	k, ok := ParseKey(v)
+	b.check("key", v == "" || ok, fmt.Sprintf("%q is not a musical key", v))
+	if ok {
+		b.metadata.Musical.Mode = "major"
+		if k.Minor {
+			b.metadata.Musical.Mode = "minor"
+		}
+	}
+	return b
+}
+
+// Format sets the audio format of the file
+func (b *MetadataBuilder) Format(v AudioFormat) *MetadataBuilder {
+	v = AudioFormat(strings.ToLower(string(v)))
+	b.check("format", v == "" || v.IsValid(), fmt.Sprintf("unsupported audio format %q", v))
+	b.metadata.Technical.Format = v
+	return b
+}
+
+// SampleRate sets the sample rate in Hz; zero leaves it unknown
+func (b *MetadataBuilder) SampleRate(v int) *MetadataBuilder {
+	b.check("sample_rate", v == 0 || isValidSampleRate(v), fmt.Sprintf("%d Hz is not a sample rate", v))
+	b.metadata.Technical.SampleRate = v
+	return b
+}
+
+// Bitrate sets the bitrate in kbps; zero leaves it unknown
+func (b *MetadataBuilder) Bitrate(v int) *MetadataBuilder {
+	b.check("bitrate", v == 0 || isValidBitrate(v), fmt.Sprintf("%d kbps is not a bitrate", v))
+	b.metadata.Technical.Bitrate = v
+	return b
+}
+
+// Channels sets the number of audio channels; zero leaves it unknown
+func (b *MetadataBuilder) Channels(v int) *MetadataBuilder {
+	b.check("channels", v >= 0 && v <= 8, fmt.Sprintf("%d is not a channel count", v))
+	b.metadata.Technical.Channels = v
+	return b
+}
+
+// File sets the size and checksums of the stored audio file
+func (b *MetadataBuilder) File(size int64, checksums Checksums) *MetadataBuilder {
+	b.check("file_size", size >= 0, "file size cannot be negative")
+	b.metadata.Technical.FileSize = size
+	b.metadata.Technical.ContentHash = checksums.SHA256
+	b.metadata.Technical.ContentMD5 = checksums.MD5
+	return b
+}
+
+// EncryptionKey sets the KMS key the stored audio file is encrypted with
+func (b *MetadataBuilder) EncryptionKey(keyID string) *MetadataBuilder {
+	b.metadata.Technical.EncryptionKeyID = keyID
+	return b
+}
+
+// Publisher sets the publisher
+func (b *MetadataBuilder) Publisher(v string) *MetadataBuilder {
+	b.metadata.Additional.Publisher = strings.TrimSpace(v)
+	return b
+}
+
+// Copyright sets the copyright line
+func (b *MetadataBuilder) Copyright(v string) *MetadataBuilder {
+	b.metadata.Additional.Copyright = strings.TrimSpace(v)
+	return b
+}
+
+// Lyrics sets the lyrics
+func (b *MetadataBuilder) Lyrics(v string) *MetadataBuilder {
+	b.metadata.Additional.Lyrics = v
+	return b
+}
+
+// Tags adds tags, skipping blank and repeated ones
+func (b *MetadataBuilder) Tags(tags ...string) *MetadataBuilder {
+next:
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag == "" {
+			continue
+		}
+		for _, existing := range b.metadata.Additional.Tags {
+			if existing == tag {
+				continue next
+			}
+		}
+		b.metadata.Additional.Tags = append(b.metadata.Additional.Tags, tag)
+	}
+	return b
+}
+
+// Build returns the metadata, or the invariants it violates: a setter
+// rejected its value, or a required field is blank
+func (b *MetadataBuilder) Build() (CompleteTrackMetadata, []ValidationError) {
+	errs := append([]ValidationError(nil), b.errs...)
+	if b.metadata.Title == "" {
+		errs = append(errs, ValidationError{Field: "title", Code: "required", Message: "Title is required"})
+	}
+	if b.metadata.Artist == "" {
+		errs = append(errs, ValidationError{Field: "artist", Code: "required", Message: "Artist is required"})
+	}
+	if len(errs) > 0 {
+		return CompleteTrackMetadata{}, errs
+	}
+	return b.metadata, nil
+}
+
+// check records that the value last set for field is invalid unless ok,
+// forgetting what was recorded for an earlier value
+func (b *MetadataBuilder) check(field string, ok bool, message string) {
+	errs := b.errs[:0]
+	for _, e := range b.errs {
+		if e.Field != field {
+			errs = append(errs, e)
+		}
+	}
+	b.errs = errs
+	if !ok {
+		b.errs = append(b.errs, ValidationError{Field: field, Code: "invalid", Message: message})
+	}
+}
+
+// customField sets a custom field, leaving blank values out
+func (b *MetadataBuilder) customField(key, value string) {
+	if value == "" {
+		delete(b.metadata.Additional.CustomFields, key)
+		return
+	}
+	if b.metadata.Additional.CustomFields == nil {
+		b.metadata.Additional.CustomFields = make(map[string]string)
+	}
+	b.metadata.Additional.CustomFields[key] = value
+}
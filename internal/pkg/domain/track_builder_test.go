@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validBuilder returns a builder that builds without errors
+func validBuilder() *MetadataBuilder {
+	return NewMetadataBuilder().Title("Song").Artist("Artist")
+}
+
+// fields returns the fields named by errs
+func fields(errs []ValidationError) []string {
+	var names []string
+	for _, e := range errs {
+		names = append(names, e.Field)
+	}
+	return names
+}
+
+func TestMetadataBuilder_Setters(t *testing.T) {
+	nextYear := time.Now().Year() + 1
+
+	tests := []struct {
+		name  string
+		set   func(*MetadataBuilder)
+		field string // field reported invalid, empty when valid
+	}{
+		{"ISRC", func(b *MetadataBuilder) { b.ISRC("USRC17607839") }, ""},
+		{"ISRC with hyphens and lower case", func(b *MetadataBuilder) { b.ISRC("us-rc1-76-07839") }, ""},
+		{"blank ISRC", func(b *MetadataBuilder) { b.ISRC("  ") }, ""},
+		{"ISRC too short", func(b *MetadataBuilder) { b.ISRC("USRC1760783") }, "isrc"},
+		{"ISRC with a bad country code", func(b *MetadataBuilder) { b.ISRC("12RC17607839") }, "isrc"},
+		{"year", func(b *MetadataBuilder) { b.Year(1999) }, ""},
+		{"unknown year", func(b *MetadataBuilder) { b.Year(0) }, ""},
+		{"next year", func(b *MetadataBuilder) { b.Year(nextYear) }, ""},
+		{"year too early", func(b *MetadataBuilder) { b.Year(1899) }, "year"},
+		{"year too late", func(b *MetadataBuilder) { b.Year(nextYear + 1) }, "year"},
+		{"negative year", func(b *MetadataBuilder) { b.Year(-1) }, "year"},
+		{"BPM", func(b *MetadataBuilder) { b.BPM(120) }, ""},
+		{"unknown BPM", func(b *MetadataBuilder) { b.BPM(0) }, ""},
+		{"BPM too slow", func(b *MetadataBuilder) { b.BPM(19.5) }, "bpm"},
+		{"BPM too fast", func(b *MetadataBuilder) { b.BPM(401) }, "bpm"},
+		{"negative BPM", func(b *MetadataBuilder) { b.BPM(-120) }, "bpm"},
+		{"territory", func(b *MetadataBuilder) { b.Territory("se") }, ""},
+		{"territory that is not a country code", func(b *MetadataBuilder) { b.Territory("Sweden") }, "territory"},
+		{"negative duration", func(b *MetadataBuilder) { b.Duration(-time.Second) }, "duration"},
+		{"key", func(b *MetadataBuilder) { b.Key("A minor") }, ""},
+		{"key that is not a key", func(b *MetadataBuilder) { b.Key("H sharp") }, "key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := validBuilder()
+			tt.set(b)
+			metadata, errs := b.Build()
+			if tt.field == "" {
+				assert.Empty(t, errs)
+				assert.Equal(t, "Song", metadata.Title)
+				return
+			}
+			require.Len(t, errs, 1)
+			assert.Equal(t, tt.field, errs[0].Field)
+			assert.Equal(t, "invalid", errs[0].Code)
+			assert.NotEmpty(t, errs[0].Message)
+			assert.Equal(t, CompleteTrackMetadata{}, metadata)
+		})
+	}
+}
+
+func TestMetadataBuilder_Normalizes(t *testing.T) {
+	metadata, errs := NewMetadataBuilder().
+		Title("  Song ").
+		Artist("Artist").
+		ISRC("us-rc1-76-07839").
+		Territory("se").
+		Key("Am").
+		Tags("pop", " ", "pop", "summer").
+		Build()
+
+	require.Empty(t, errs)
+	assert.Equal(t, "Song", metadata.Title)
+	assert.Equal(t, "USRC17607839", metadata.ISRC)
+	assert.Equal(t, "SE", metadata.Additional.CustomFields["territory"])
+	assert.Equal(t, "minor", metadata.Musical.Mode)
+	assert.Equal(t, []string{"pop", "summer"}, metadata.Additional.Tags)
+}
+
+func TestMetadataBuilder_AccumulatesErrors(t *testing.T) {
+	_, errs := NewMetadataBuilder().
+		ISRC("bad").
+		Year(1800).
+		BPM(1000).
+		Build()
+
+	assert.Equal(t, []string{"isrc", "year", "bpm", "title", "artist"}, fields(errs))
+	assert.Equal(t, "required", errs[3].Code)
+	assert.Equal(t, "required", errs[4].Code)
+}
+
+func TestMetadataBuilder_LaterValueReplacesError(t *testing.T) {
+	b := validBuilder().Year(1800).BPM(1000)
+	b.Year(2001)
+
+	_, errs := b.Build()
+	assert.Equal(t, []string{"bpm"}, fields(errs), "only the error of the current year value is kept")
+
+	b.BPM(128)
+	metadata, errs := b.Build()
+	require.Empty(t, errs)
+	assert.Equal(t, 2001, metadata.Year)
+	assert.Equal(t, float64(128), metadata.Musical.BPM)
+}
+
+func TestMetadataBuilder_BuildDoesNotMutate(t *testing.T) {
+	b := NewMetadataBuilder().Artist("Artist").Year(1800)
+
+	_, first := b.Build()
+	_, second := b.Build()
+	assert.Equal(t, first, second, "building twice reports the same errors")
+	assert.Equal(t, []string{"year", "title"}, fields(second))
+
+	// Fixing the builder after a failed build is enough to build: the
+	// failure recorded nothing on the builder
+	b.Title("Song").Year(1999)
+	metadata, errs := b.Build()
+	require.Empty(t, errs)
+	assert.Equal(t, "Song", metadata.Title)
+	assert.Equal(t, 1999, metadata.Year)
+
+	// A successful build hands out a value: changing it leaves the builder
+	// alone
+	metadata.Title = "Changed"
+	again, _ := b.Build()
+	assert.Equal(t, "Song", again.Title)
+}
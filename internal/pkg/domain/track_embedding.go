@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// MaxEmbeddingDimensions is the largest embedding a track can store, the
+// size of the embedding column
+const MaxEmbeddingDimensions = 1536
+
+// ErrEmbeddingNotFound is returned when a track has no audio embedding
+var ErrEmbeddingNotFound = errors.New("track has no embedding")
+
+// TrackEmbedding is a vector describing the sound of a track. Tracks that
+// sound alike have embeddings a small cosine distance apart; only
+// embeddings of the same model are compared.
+type TrackEmbedding struct {
+	TrackID string `json:"track_id"`
+	// Model names what computed the vector, such as "audio-features-v1"
+	// for the analysis features or the name of an embedding model
+	Model     string    `json:"model"`
+	Vector    []float32 `json:"vector"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the model and vector of the embedding
+func (e *TrackEmbedding) Validate() []ValidationError {
+	var errs []ValidationError
+	if e.Model == "" {
+		errs = append(errs, ValidationError{Field: "model", Code: "required", Message: "model is required"})
+	}
+	switch {
+	case len(e.Vector) == 0:
+		errs = append(errs, ValidationError{Field: "vector", Code: "required", Message: "vector is required"})
+	case len(e.Vector) > MaxEmbeddingDimensions:
+		errs = append(errs, ValidationError{Field: "vector", Code: "too_large",
+			Message: fmt.Sprintf("vector has %d dimensions, at most %d are allowed", len(e.Vector), MaxEmbeddingDimensions)})
+	}
+	zero := true
+	for _, v := range e.Vector {
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			errs = append(errs, ValidationError{Field: "vector", Code: "invalid", Message: "vector values must be finite"})
+			return errs
+		}
+		zero = zero && v == 0
+	}
+	if zero && len(e.Vector) > 0 {
+		errs = append(errs, ValidationError{Field: "vector", Code: "invalid", Message: "vector must not be all zeros"})
+	}
+	return errs
+}
+
+// TrackNeighbor is a track near another in embedding space
+type TrackNeighbor struct {
+	TrackID string
+	// Distance is the cosine distance, from 0 for the same direction to 2
+	Distance float64
+}
+
+// SimilarTrack is a track that sounds like another
+type SimilarTrack struct {
+	Track *Track `json:"track"`
+	// Distance is the cosine distance of the embeddings, 0 for tracks that
+	// sound the same
+	Distance float64 `json:"distance"`
+}
+
+// EmbeddingRepository stores track embeddings and searches them
+type EmbeddingRepository interface {
+	// Save creates or replaces the embedding of a track
+	Save(ctx context.Context, embedding *TrackEmbedding) error
+	// Get returns the embedding of a track, or ErrEmbeddingNotFound
+	Get(ctx context.Context, trackID string) (*TrackEmbedding, error)
+	// Nearest returns up to limit tracks whose embeddings of the same
+	// model are nearest to the track's, nearest first. It returns
+	// ErrEmbeddingNotFound when the track has no embedding.
+	Nearest(ctx context.Context, trackID string, limit int) ([]TrackNeighbor, error)
+}
+
+// Embedder computes the embedding of a track from its audio analysis
+type Embedder interface {
+	// Model names the embeddings the embedder computes
+	Model() string
+	Embed(ctx context.Context, analysis *AudioAnalysis) ([]float32, error)
+}
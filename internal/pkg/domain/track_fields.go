@@ -0,0 +1,77 @@
+package domain
+
+// trackField describes a user-editable track field by name with accessors
+// used for diffing, merging and provenance tracking
+type trackField struct {
+	name string
+	get  func(t *Track) interface{}
+	copy func(dst, src *Track)
+}
+
+// trackFields lists the user-editable fields of a track in display order
+var trackFields = []trackField{
+	{"title", func(t *Track) interface{} { return t.Title() }, func(d, s *Track) { d.SetTitle(s.Title()) }},
+	{"artist", func(t *Track) interface{} { return t.Artist() }, func(d, s *Track) { d.SetArtist(s.Artist()) }},
+	{"album", func(t *Track) interface{} { return t.Album() }, func(d, s *Track) { d.SetAlbum(s.Album()) }},
+	{"year", func(t *Track) interface{} { return t.Year() }, func(d, s *Track) { d.SetYear(s.Year()) }},
+	{"isrc", func(t *Track) interface{} { return t.ISRC() }, func(d, s *Track) { d.SetISRC(s.ISRC()) }},
+	{"iswc", func(t *Track) interface{} { return t.ISWC() }, func(d, s *Track) { d.setCustomField("iswc", s.ISWC()) }},
+	{"label", func(t *Track) interface{} { return t.Label() }, func(d, s *Track) { d.setCustomField("label", s.Label()) }},
+	{"territory", func(t *Track) interface{} { return t.Territory() }, func(d, s *Track) { d.setCustomField("territory", s.Territory()) }},
+	{"genre", func(t *Track) interface{} { return t.Genre() }, func(d, s *Track) { d.SetGenre(s.Genre()) }},
+	{"bpm", func(t *Track) interface{} { return t.BPM() }, func(d, s *Track) { d.SetBPM(s.BPM()) }},
+	{"key", func(t *Track) interface{} { return t.Key() }, func(d, s *Track) { d.SetKey(s.Key()) }},
+	{"mood", func(t *Track) interface{} { return t.Mood() }, func(d, s *Track) { d.SetMood(s.Mood()) }},
+	{"publisher", func(t *Track) interface{} { return t.Publisher() }, func(d, s *Track) { d.SetPublisher(s.Publisher()) }},
+	{"copyright", func(t *Track) interface{} { return t.Copyright() }, func(d, s *Track) { d.SetCopyright(s.Copyright()) }},
+	{"lyrics", func(t *Track) interface{} { return t.Lyrics() }, func(d, s *Track) { d.SetLyrics(s.Lyrics()) }},
+	{"tags", func(t *Track) interface{} { return t.Tags() }, func(d, s *Track) {
+		d.Metadata.Additional.Tags = append([]string(nil), s.Tags()...)
+	}},
+	{"label_id", func(t *Track) interface{} { return t.LabelID }, func(d, s *Track) { d.LabelID = s.LabelID }},
+	{"artist_ids", func(t *Track) interface{} { return t.ArtistIDs }, func(d, s *Track) {
+		d.ArtistIDs = append([]string(nil), s.ArtistIDs...)
+	}},
+	{"release_id", func(t *Track) interface{} { return t.ReleaseID }, func(d, s *Track) { d.ReleaseID = s.ReleaseID }},
+	{"status", func(t *Track) interface{} { return t.Status }, func(d, s *Track) { d.Status = s.Status }},
+}
+
+// TrackFieldNames returns the names of all user-editable track fields
+func TrackFieldNames() []string {
+	names := make([]string, len(trackFields))
+	for i, f := range trackFields {
+		names[i] = f.name
+	}
+	return names
+}
+
+// FieldValue returns the current value of a named track field
+func (t *Track) FieldValue(name string) (interface{}, bool) {
+	for _, f := range trackFields {
+		if f.name == name {
+			return f.get(t), true
+		}
+	}
+	return nil, false
+}
+
+// CopyTrackFields copies the named user-editable fields from src to dst.
+// Unknown names are ignored.
+func CopyTrackFields(dst, src *Track, names []string) {
+	for _, name := range names {
+		for _, f := range trackFields {
+			if f.name == name {
+				f.copy(dst, src)
+				break
+			}
+		}
+	}
+}
+
+// setCustomField sets a custom field, initializing the map if needed
+func (t *Track) setCustomField(key, value string) {
+	if t.Metadata.Additional.CustomFields == nil {
+		t.Metadata.Additional.CustomFields = make(map[string]string)
+	}
+	t.Metadata.Additional.CustomFields[key] = value
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TrackLock is the processing lock a worker holds on a track while it
+// enriches it, so two workers never write the same track at once
+type TrackLock struct {
+	// Owner names the work holding the lock, such as an AI batch
+	Owner      string    `json:"owner"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+	// ExpiresAt is when the lock is dropped if its owner stops without
+	// releasing it
+	ExpiresAt time.Time `json:"expiresAt"`
+	// Token identifies the holder, so a lock that expired and was taken
+	// over is not released by its former holder
+	Token string `json:"-"`
+}
+
+// TrackLocker holds the processing locks of tracks
+type TrackLocker interface {
+	// Lock locks every track for owner for at most ttl. It locks none of
+	// them and returns ErrTrackLocked when one is locked already.
+	Lock(ctx context.Context, trackIDs []string, owner string, ttl time.Duration) (*TrackLock, error)
+	// Unlock releases the locks lock holds on the tracks
+	Unlock(ctx context.Context, trackIDs []string, lock *TrackLock) error
+	// Locks returns the locks held on the tracks by track ID
+	Locks(ctx context.Context, trackIDs []string) (map[string]*TrackLock, error)
+}
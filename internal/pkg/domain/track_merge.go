@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MergePick selects which of two merged tracks a field is taken from
+type MergePick string
+
+const (
+	// MergePickFill keeps the target's value, or takes the source's when the
+	// target has none. It is the default for single values.
+	MergePickFill MergePick = "fill"
+	// MergePickTarget keeps the target's value
+	MergePickTarget MergePick = "target"
+	// MergePickSource takes the source's value, even when it is empty
+	MergePickSource MergePick = "source"
+	// MergePickCombine joins the values of both tracks. It is the default
+	// for tags and artist_ids, and only applies to them.
+	MergePickCombine MergePick = "combine"
+)
+
+// MergeRules picks, by field name, where the merged values of a track come
+// from. Fields without a rule use their default pick.
+type MergeRules map[string]MergePick
+
+// combinableMergeFields are the list fields both tracks' values can be
+// combined for
+var combinableMergeFields = map[string]bool{"tags": true, "artist_ids": true}
+
+// Validate checks that the rules name mergeable fields and known picks
+func (r MergeRules) Validate() []ValidationError {
+	var errs []ValidationError
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pick := r[name]
+		if _, ok := mergeField(name); !ok {
+			errs = append(errs, ValidationError{Field: "fields." + name, Message: fmt.Sprintf("unknown field %q", name)})
+			continue
+		}
+		switch pick {
+		case MergePickFill, MergePickTarget, MergePickSource:
+		case MergePickCombine:
+			if !combinableMergeFields[name] {
+				errs = append(errs, ValidationError{Field: "fields." + name, Message: "only tags and artist_ids can be combined"})
+			}
+		default:
+			errs = append(errs, ValidationError{Field: "fields." + name, Message: fmt.Sprintf("unknown pick %q", pick)})
+		}
+	}
+	return errs
+}
+
+// pick returns the rule for a field, or its default
+func (r MergeRules) pick(name string) MergePick {
+	if pick, ok := r[name]; ok {
+		return pick
+	}
+	if combinableMergeFields[name] {
+		return MergePickCombine
+	}
+	return MergePickFill
+}
+
+// mergeField returns the user-editable field of that name. The status is
+// left to the workflow and cannot be merged.
+func mergeField(name string) (trackField, bool) {
+	if name == "status" {
+		return trackField{}, false
+	}
+	for _, f := range trackFields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return trackField{}, false
+}
+
+// MergeTracks merges source into target field by field following rules,
+// and returns the changed fields. Custom fields are combined, with the
+// target's values winning. When target has no audio file, the source's
+// file and its technical metadata move to it, and movedAudio is set.
+func MergeTracks(target, source *Track, rules MergeRules) (changes []FieldChange, movedAudio bool) {
+	before := target.Clone()
+	for _, f := range trackFields {
+		if f.name == "status" {
+			continue
+		}
+		switch rules.pick(f.name) {
+		case MergePickSource:
+			f.copy(target, source)
+		case MergePickFill:
+			if isBlankField(f.get(target)) && !isBlankField(f.get(source)) {
+				f.copy(target, source)
+			}
+		case MergePickCombine:
+			combineMergeField(target, source, f.name)
+		}
+	}
+	for key, value := range source.Metadata.Additional.CustomFields {
+		if _, ok := target.Metadata.Additional.CustomFields[key]; !ok && !isTrackFieldKey(key) {
+			target.setCustomField(key, value)
+		}
+	}
+
+	if target.StoragePath == "" && source.StoragePath != "" {
+		target.StoragePath = source.StoragePath
+		target.FilePath = source.FilePath
+		target.FileSize = source.FileSize
+		target.Metadata.Technical = source.Metadata.Technical
+		movedAudio = true
+	}
+	return DiffTracks(before, target), movedAudio
+}
+
+// combineMergeField appends the source's values of a list field missing
+// from the target's
+func combineMergeField(target, source *Track, name string) {
+	switch name {
+	case "tags":
+		for _, tag := range source.Tags() {
+			if !target.HasTag(tag) {
+				target.Metadata.Additional.Tags = append(target.Metadata.Additional.Tags, tag)
+			}
+		}
+	case "artist_ids":
+		for _, id := range source.ArtistIDs {
+			target.AddArtist(id)
+		}
+	}
+}
+
+// isTrackFieldKey reports whether a custom field key backs a track field,
+// such as "iswc", which the merge rules decide on
+func isTrackFieldKey(key string) bool {
+	_, ok := mergeField(strings.ToLower(key))
+	return ok
+}
+
+// isBlankField reports whether a field value is unset
+func isBlankField(v interface{}) bool {
+	switch v := v.(type) {
+	case string:
+		return v == ""
+	case TrackStatus:
+		return v == ""
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case []string:
+		return len(v) == 0
+	}
+	return v == nil
+}
+
+// TrackRedirect is the tombstone of a track merged into another, so that
+// its ID still resolves
+type TrackRedirect struct {
+	// SourceID is the ID of the merged track
+	SourceID string `json:"source_id" gorm:"primaryKey"`
+	// TargetID is the ID of the track it was merged into
+	TargetID string    `json:"target_id" gorm:"index;not null"`
+	MergedBy string    `json:"merged_by,omitempty"`
+	MergedAt time.Time `json:"merged_at"`
+}
+
+// TableName returns the table name for track redirects
+func (TrackRedirect) TableName() string {
+	return "track_redirects"
+}
+
+// TrackReferenceRewriter is implemented by stores that refer to tracks,
+// such as deliveries and play counts, so that a merge can move the
+// references of the merged track to the track it was merged into
+type TrackReferenceRewriter interface {
+	// RewriteTrackReferences moves the references to fromID to toID and
+	// returns how many there were
+	RewriteTrackReferences(ctx context.Context, fromID, toID string) (int64, error)
+}
+
+// TrackRedirectRepository stores the tombstones of merged tracks. Earlier
+// redirects to a merged track are rewritten to its target, so redirects
+// never chain.
+type TrackRedirectRepository interface {
+	TrackReferenceRewriter
+	// Save stores a redirect
+	Save(ctx context.Context, redirect *TrackRedirect) error
+	// Get returns the redirect of a merged track, or nil if it has none
+	Get(ctx context.Context, sourceID string) (*TrackRedirect, error)
+}
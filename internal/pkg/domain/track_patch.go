@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// TrackPatcher is implemented by track repositories that can change part of
+// a track in a single transaction. PatchTrack uses it when available.
+type TrackPatcher interface {
+	// Patch loads the track with the given ID, lets apply change it and stores
+	// the result with the same version check as Update. It returns nil if the
+	// track does not exist, and the error of apply unchanged.
+	Patch(ctx context.Context, id string, apply func(*Track) error) (*Track, error)
+}
+
+// PatchTrack changes part of a track through repo, atomically when repo is a
+// TrackPatcher and otherwise by reading the track from the primary and
+// updating it. It returns nil if the track does not exist.
+func PatchTrack(ctx context.Context, repo TrackRepository, id string, apply func(*Track) error) (*Track, error) {
+	if patcher, ok := repo.(TrackPatcher); ok {
+		return patcher.Patch(ctx, id, apply)
+	}
+
+	track, err := repo.GetByID(WithPrimaryReads(ctx), id)
+	if err != nil || track == nil {
+		return nil, err
+	}
+	if err := apply(track); err != nil {
+		return nil, err
+	}
+	if err := repo.Update(ctx, track); err != nil {
+		return nil, err
+	}
+	return track, nil
+}
+
+// MergePatchTrack applies a JSON Merge Patch (RFC 7396) to a copy of track:
+// members of the patch replace the stored values, null removes them and
+// absent members are left alone. Server-managed fields keep their stored
+// values. It also returns the JSON paths the patch sets, such as
+// "metadata.basic.title", so that only those need validating.
+func MergePatchTrack(track *Track, patch []byte) (*Track, []string, error) {
+	var changes interface{}
+	if err := decodeJSON(patch, &changes); err != nil {
+		return nil, nil, fmt.Errorf("%w: patch is not valid JSON", ErrInvalidInput)
+	}
+	if _, ok := changes.(map[string]interface{}); !ok {
+		return nil, nil, fmt.Errorf("%w: patch must be a JSON object", ErrInvalidInput)
+	}
+
+	current := track.Clone()
+	data, err := json.Marshal(current)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode track: %w", err)
+	}
+	var doc interface{}
+	if err := decodeJSON(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode track: %w", err)
+	}
+
+	merged, err := json.Marshal(mergePatch(doc, changes))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode patched track: %w", err)
+	}
+	var patched Track
+	if err := json.Unmarshal(merged, &patched); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	patched.ID = current.ID
+	patched.CreatedAt = current.CreatedAt
+	patched.UpdatedAt = current.UpdatedAt
+	patched.DeletedAt = current.DeletedAt
+	patched.StoragePath = current.StoragePath
+	patched.FilePath = current.FilePath
+	patched.FileSize = current.FileSize
+	patched.AudioData = current.AudioData
+	patched.Version = current.Version
+	patched.PreviousID = current.PreviousID
+	patched.Metadata.Provenance = current.Metadata.Provenance
+
+	paths := patchPaths("", changes)
+	sort.Strings(paths)
+	return &patched, paths, nil
+}
+
+// mergePatch implements the MergePatch function of RFC 7396
+func mergePatch(target, patch interface{}) interface{} {
+	changes, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	doc, ok := target.(map[string]interface{})
+	if !ok {
+		doc = make(map[string]interface{})
+	}
+	for name, value := range changes {
+		if value == nil {
+			delete(doc, name)
+			continue
+		}
+		doc[name] = mergePatch(doc[name], value)
+	}
+	return doc
+}
+
+// patchPaths returns the paths of the members a patch sets or removes
+func patchPaths(prefix string, patch interface{}) []string {
+	changes, ok := patch.(map[string]interface{})
+	if !ok || (len(changes) == 0 && prefix != "") {
+		return []string{prefix}
+	}
+	var paths []string
+	for name, value := range changes {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		paths = append(paths, patchPaths(path, value)...)
+	}
+	return paths
+}
+
+// decodeJSON decodes data keeping numbers exact
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
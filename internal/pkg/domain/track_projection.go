@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// trackCoreFields are the server-managed fields a sparse track can hold
+// besides the user-editable ones, with their values
+var trackCoreFields = []trackField{
+	{"id", func(t *Track) interface{} { return t.ID }, nil},
+	{"version", func(t *Track) interface{} { return t.Version }, nil},
+	{"created_at", func(t *Track) interface{} { return t.CreatedAt }, nil},
+	{"updated_at", func(t *Track) interface{} { return t.UpdatedAt }, nil},
+}
+
+// trackFieldColumns maps the selectable fields stored in a column of their
+// own to it. Every other user-editable field lives in the metadata column.
+var trackFieldColumns = map[string]string{
+	"id":         "id",
+	"version":    "version",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"label_id":   "label_id",
+	"artist_ids": "artist_ids",
+	"release_id": "release_id",
+	"status":     "status",
+}
+
+// SelectableTrackFieldNames returns the names of the fields a sparse track
+// can hold
+func SelectableTrackFieldNames() []string {
+	names := make([]string, 0, len(trackCoreFields)+len(trackFields))
+	for _, f := range trackCoreFields {
+		names = append(names, f.name)
+	}
+	return append(names, TrackFieldNames()...)
+}
+
+// ParseTrackFields parses a comma-separated field selection such as
+// "id,title,artist". An empty selection returns nil, meaning all fields.
+func ParseTrackFields(selection string) ([]string, error) {
+	if strings.TrimSpace(selection) == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(selection, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, ok := selectableTrackField(name); !ok {
+			return nil, fmt.Errorf("%w: unknown field %q, expected one of %s",
+				ErrInvalidInput, name, strings.Join(SelectableTrackFieldNames(), ", "))
+		}
+		seen[name] = true
+		fields = append(fields, name)
+	}
+	return fields, nil
+}
+
+// TrackFieldColumns returns the columns of the tracks table that hold
+// fields. The ID is always included.
+func TrackFieldColumns(fields []string) []string {
+	columns := []string{"id"}
+	seen := map[string]bool{"id": true}
+	for _, name := range fields {
+		column, ok := trackFieldColumns[name]
+		if !ok {
+			column = "metadata"
+		}
+		if !seen[column] {
+			seen[column] = true
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// Project returns the selected fields of the track keyed by name
+func (t *Track) Project(fields []string) map[string]interface{} {
+	projected := make(map[string]interface{}, len(fields))
+	for _, name := range fields {
+		if f, ok := selectableTrackField(name); ok {
+			projected[name] = f.get(t)
+		}
+	}
+	return projected
+}
+
+func selectableTrackField(name string) (trackField, bool) {
+	for _, f := range trackCoreFields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	for _, f := range trackFields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return trackField{}, false
+}
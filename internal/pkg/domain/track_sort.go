@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxTrackSortKeys is the number of keys a track listing can be ordered by
+const MaxTrackSortKeys = 3
+
+// sortableTrackFields lists the fields track listings can be ordered by
+var sortableTrackFields = []string{
+	"title", "artist", "album", "year", "duration", "bpm", "genre", "isrc",
+	"label_id", "status", "created_at", "updated_at",
+}
+
+// TrackSortKey orders track listings by one field
+type TrackSortKey struct {
+	Field string
+	Desc  bool
+}
+
+// SortableTrackFieldNames returns the names of the fields track listings
+// can be ordered by
+func SortableTrackFieldNames() []string {
+	return append([]string(nil), sortableTrackFields...)
+}
+
+// ParseTrackSort parses a comma-separated sort such as
+// "artist,-created_at", where a leading "-" orders by a field descending.
+// Later keys order tracks that earlier keys leave tied. An empty sort
+// returns nil, meaning the storage order.
+func ParseTrackSort(sort string) ([]TrackSortKey, error) {
+	if strings.TrimSpace(sort) == "" {
+		return nil, nil
+	}
+
+	var keys []TrackSortKey
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(sort, ",") {
+		part = strings.TrimSpace(part)
+		key := TrackSortKey{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if key.Field == "" {
+			continue
+		}
+		if !isSortableTrackField(key.Field) {
+			return nil, fmt.Errorf("%w: cannot sort by %q, expected one of %s",
+				ErrInvalidInput, key.Field, strings.Join(sortableTrackFields, ", "))
+		}
+		if seen[key.Field] {
+			return nil, fmt.Errorf("%w: %q is sorted by more than once", ErrInvalidInput, key.Field)
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+	if len(keys) > MaxTrackSortKeys {
+		return nil, fmt.Errorf("%w: sort by at most %d fields", ErrInvalidInput, MaxTrackSortKeys)
+	}
+	return keys, nil
+}
+
+func isSortableTrackField(name string) bool {
+	for _, field := range sortableTrackFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
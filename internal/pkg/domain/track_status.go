@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// trackStatusTransitions lists the statuses a track in each status may move
+// to. A track moves through pending, processing, needs_review, approved and
+// delivered to archived; it can go back for another enrichment or review,
+// and rejected tracks can be resubmitted.
+var trackStatusTransitions = map[TrackStatus][]TrackStatus{
+	TrackStatusDraft:       {TrackStatusPending, TrackStatusRejected},
+	TrackStatusPending:     {TrackStatusProcessing, TrackStatusNeedsReview, TrackStatusRejected},
+	TrackStatusProcessing:  {TrackStatusNeedsReview, TrackStatusApproved, TrackStatusPending, TrackStatusRejected},
+	TrackStatusNeedsReview: {TrackStatusApproved, TrackStatusProcessing, TrackStatusRejected},
+	TrackStatusApproved:    {TrackStatusDelivered, TrackStatusNeedsReview, TrackStatusArchived},
+	TrackStatusDelivered:   {TrackStatusArchived},
+	TrackStatusArchived:    {TrackStatusApproved},
+	TrackStatusRejected:    {TrackStatusPending},
+	TrackStatusActive:      {TrackStatusNeedsReview, TrackStatusApproved, TrackStatusDelivered, TrackStatusArchived},
+	TrackStatusInactive:    {TrackStatusApproved, TrackStatusArchived},
+	TrackStatusDeleted:     {},
+}
+
+// NextStatuses returns the statuses a track in status s may move to
+func (s TrackStatus) NextStatuses() []TrackStatus {
+	return append([]TrackStatus{}, trackStatusTransitions[s]...)
+}
+
+// CanTransitionTo reports whether a track in status s may move to next
+func (s TrackStatus) CanTransitionTo(next TrackStatus) bool {
+	for _, status := range trackStatusTransitions[s] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckStatusTransition returns an error wrapping ErrInvalidStatusTransition
+// unless a track may move from status from to status to. Keeping the status,
+// and any status of a track that has none yet, is allowed.
+func CheckStatusTransition(from, to TrackStatus) error {
+	if from == to {
+		return nil
+	}
+	if !to.IsValid() {
+		return fmt.Errorf("%w: unknown track status %q", ErrInvalidStatusTransition, to)
+	}
+	if from == "" || from.CanTransitionTo(to) {
+		return nil
+	}
+	return fmt.Errorf("%w from %s to %s", ErrInvalidStatusTransition, from, to)
+}
+
+// TrackStatusChange describes a track moving from one status to another
+type TrackStatusChange struct {
+	TrackID string      `json:"track_id"`
+	From    TrackStatus `json:"from"`
+	To      TrackStatus `json:"to"`
+	// Message is the reason given for the change, stored as StatusMsg
+	Message string `json:"message,omitempty"`
+	// UserID is who made the change, empty for changes made by the system
+	UserID     string    `json:"user_id,omitempty"`
+	Version    int       `json:"version"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// TrackTransitionHook is called after a status change is stored, on the
+// goroutine that made it. Errors are the hook's to report.
+type TrackTransitionHook func(ctx context.Context, change TrackStatusChange)
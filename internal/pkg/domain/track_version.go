@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// VersionConflictError is returned when a track update is based on a stale version.
+// Conflicts lists the fields whose stored value differs from the submitted one,
+// with OldValue holding the stored value and NewValue the submitted value.
+type VersionConflictError struct {
+	TrackID         string        `json:"track_id"`
+	ExpectedVersion int           `json:"expected_version"`
+	CurrentVersion  int           `json:"current_version"`
+	Conflicts       []FieldChange `json:"conflicts"`
+}
+
+// Error implements the error interface
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict on track %s: expected version %d, current version %d",
+		e.TrackID, e.ExpectedVersion, e.CurrentVersion)
+}
+
+// Is allows errors.Is(err, ErrVersionConflict) to match
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+// NewVersionConflictError builds a conflict error from the stored and submitted tracks
+func NewVersionConflictError(current, submitted *Track) *VersionConflictError {
+	return &VersionConflictError{
+		TrackID:         current.ID,
+		ExpectedVersion: submitted.Version,
+		CurrentVersion:  current.Version,
+		Conflicts:       DiffTracks(current, submitted),
+	}
+}
+
+// DiffTracks returns the user-editable fields that differ between two tracks.
+// OldValue is taken from a and NewValue from b.
+func DiffTracks(a, b *Track) []FieldChange {
+	var changes []FieldChange
+	for _, f := range trackFields {
+		av, bv := f.get(a), f.get(b)
+		if !valuesEqual(av, bv) {
+			changes = append(changes, FieldChange{Field: f.name, OldValue: av, NewValue: bv})
+		}
+	}
+	return changes
+}
+
+// valuesEqual compares two field values, treating nil and empty slices as equal
+func valuesEqual(a, b interface{}) bool {
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	if av.Kind() == reflect.Slice && bv.Kind() == reflect.Slice && av.Len() == 0 && bv.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
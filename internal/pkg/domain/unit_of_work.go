@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"sync"
+)
+
+// UnitOfWork runs the repository calls of a use case operation as one
+// transaction, such as the update of a merged track, the redirect it leaves
+// and the deletion of its source
+type UnitOfWork interface {
+	// Do calls fn with a context carrying the transaction. The repositories
+	// given that context write in the transaction, which commits when fn
+	// returns nil and rolls back when it returns an error or panics. Inside
+	// another unit of work fn runs in a nested transaction that rolls back on
+	// its own, and the outer one still decides the commit.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type commitHooksKey struct{}
+
+// commitHooks are the functions run once a unit of work commits
+type commitHooks struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+// WithCommitHooks returns a context collecting the functions passed to
+// AfterCommit, and a function running them. Units of work call it when they
+// begin a transaction and run the hooks once it commits. Inside a unit of
+// work the hooks go to the outer one and run does nothing.
+func WithCommitHooks(ctx context.Context) (context.Context, func()) {
+	if _, ok := ctx.Value(commitHooksKey{}).(*commitHooks); ok {
+		return ctx, func() {}
+	}
+	hooks := &commitHooks{}
+	return context.WithValue(ctx, commitHooksKey{}, hooks), func() {
+		hooks.mu.Lock()
+		fns := hooks.fns
+		hooks.fns = nil
+		hooks.mu.Unlock()
+		for _, fn := range fns {
+			fn()
+		}
+	}
+}
+
+// AfterCommit runs fn once the unit of work ctx belongs to commits, or right
+// away outside a unit of work. It is for effects outside the database, such
+// as dropping cached copies of the rows written, that must not happen before
+// the rows can be read. fn does not run when the unit of work rolls back.
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(commitHooksKey{}).(*commitHooks)
+	if !ok {
+		fn()
+		return
+	}
+	hooks.mu.Lock()
+	hooks.fns = append(hooks.fns, fn)
+	hooks.mu.Unlock()
+}
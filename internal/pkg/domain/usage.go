@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// UsageMetric names a metered quantity
+type UsageMetric string
+
+const (
+	// UsageTracksStored is the number of tracks a label has stored
+	UsageTracksStored UsageMetric = "tracks_stored"
+	// UsageStorageBytes is the size of a label's stored audio files
+	UsageStorageBytes UsageMetric = "storage_bytes"
+	// UsageAIEnrichments counts AI enrichment requests
+	UsageAIEnrichments UsageMetric = "ai_enrichments"
+	// UsageExports counts exported tracks
+	UsageExports UsageMetric = "exports"
+	// UsagePublicAPIRequests counts requests to the public catalog API
+	UsagePublicAPIRequests UsageMetric = "public_api_requests"
+)
+
+// UsageDayFormat is the layout of usage days
+const UsageDayFormat = "2006-01-02"
+
+// UsageRecord is the usage of one metric by one label on one UTC day.
+// Counted metrics are summed over the day; stored amounts are the latest
+// snapshot taken that day.
+type UsageRecord struct {
+	LabelID   string      `json:"label_id" gorm:"primaryKey"`
+	Day       string      `json:"day" gorm:"primaryKey;size:10"`
+	Metric    UsageMetric `json:"metric" gorm:"primaryKey;size:32"`
+	Value     int64       `json:"value"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// TableName returns the table name for usage records
+func (UsageRecord) TableName() string {
+	return "usage_daily"
+}
+
+// UsageDay is a label's usage on one day, as reported for invoicing
+type UsageDay struct {
+	LabelID           string `json:"label_id"`
+	Day               string `json:"day"`
+	TracksStored      int64  `json:"tracks_stored"`
+	StorageBytes      int64  `json:"storage_bytes"`
+	AIEnrichments     int64  `json:"ai_enrichments"`
+	Exports           int64  `json:"exports"`
+	PublicAPIRequests int64  `json:"public_api_requests"`
+}
+
+// UsageReport lists daily usage between two days, inclusive
+type UsageReport struct {
+	From string     `json:"from"`
+	To   string     `json:"to"`
+	Days []UsageDay `json:"days"`
+}
+
+// UsageFilter selects usage records. An empty LabelID selects every label.
+type UsageFilter struct {
+	LabelID string
+	From    string
+	To      string
+}
+
+// TracksPerLabel counts tracks by label
+func TracksPerLabel(tracks []*Track) map[string]int64 {
+	counts := make(map[string]int64)
+	for _, track := range tracks {
+		counts[track.LabelID]++
+	}
+	return counts
+}
+
+// UsageRecorder counts metered usage
+type UsageRecorder interface {
+	RecordUsage(ctx context.Context, labelID string, metric UsageMetric, n int64)
+}
+
+// UsageRepository stores daily usage
+type UsageRepository interface {
+	// Increment adds n to a label's metric for day
+	Increment(ctx context.Context, labelID, day string, metric UsageMetric, n int64) error
+	// SnapshotStored records every label's stored tracks and bytes for day
+	SnapshotStored(ctx context.Context, day string) error
+	// List returns the records matching filter ordered by label and day
+	List(ctx context.Context, filter UsageFilter) ([]*UsageRecord, error)
+}
@@ -33,7 +33,7 @@ type User struct {
 	Password       string           `json:"-"` // Never expose password in JSON
 	Name           string           `json:"name"`
 	Role           Role             `json:"role"`
-	Permissions    []Permission     `json:"permissions"`
+	Permissions    []Permission     `json:"permissions" gorm:"serializer:json"`
 	Company        string           `json:"company,omitempty"`
 	APIKey         string           `json:"api_key,omitempty"`
 	Plan           SubscriptionPlan `json:"plan"`
@@ -141,4 +141,5 @@ type UserRepository interface {
 	Delete(ctx context.Context, id string) error
 	UpdateAPIKey(ctx context.Context, userID string, apiKey string) error
 	List(ctx context.Context, offset, limit int) ([]*User, error)
+	Count(ctx context.Context) (int64, error)
 }
@@ -10,9 +10,11 @@ type ValidationResult struct {
 	Errors  []ValidationError `json:"errors,omitempty"`
 }
 
-// ValidationError represents a single validation error
+// ValidationError represents a single validation error. Code names the rule
+// the field failed, such as "required".
 type ValidationError struct {
 	Field   string `json:"field"`
+	Code    string `json:"code,omitempty"`
 	Message string `json:"message"`
 }
 
@@ -0,0 +1,53 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c, err := NewCipher(testKey('a'))
+	require.NoError(t, err)
+
+	secret, err := c.Encrypt("sk-label-key")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, "v1:"))
+	assert.NotContains(t, secret, "sk-label-key")
+
+	again, err := c.Encrypt("sk-label-key")
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, again, "every secret gets its own nonce")
+
+	plaintext, err := c.Decrypt(secret)
+	require.NoError(t, err)
+	assert.Equal(t, "sk-label-key", plaintext)
+}
+
+func TestCipher_Rejects(t *testing.T) {
+	_, err := NewCipher("not base64!")
+	assert.Error(t, err)
+	_, err = NewCipher(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Error(t, err)
+
+	c, err := NewCipher(testKey('a'))
+	require.NoError(t, err)
+	other, err := NewCipher(testKey('b'))
+	require.NoError(t, err)
+	secret, err := c.Encrypt("sk-label-key")
+	require.NoError(t, err)
+
+	_, err = other.Decrypt(secret)
+	assert.Error(t, err, "another key")
+	_, err = c.Decrypt("sk-label-key")
+	assert.Error(t, err, "plaintext")
+	_, err = c.Decrypt(secret[:len(secret)-4] + "AAAA")
+	assert.Error(t, err, "tampered")
+}
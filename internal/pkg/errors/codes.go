@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"errors"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// ErrorCode identifies an error in API responses. Codes are stable: clients
+// may branch on them, while messages can change. Every error type is also a
+// code, used for errors that have no more specific one.
+type ErrorCode string
+
+// Error code catalog
+const (
+	CodeInvalidCredentials    ErrorCode = "INVALID_CREDENTIALS"
+	CodeInvalidToken          ErrorCode = "INVALID_TOKEN"
+	CodeTokenReused           ErrorCode = "TOKEN_REUSED"
+	CodeInvalidAPIKey         ErrorCode = "INVALID_API_KEY"
+	CodeSessionRequired       ErrorCode = "SESSION_REQUIRED"
+	CodeSessionExpired        ErrorCode = "SESSION_EXPIRED"
+	CodeMaxSessionsReached    ErrorCode = "MAX_SESSIONS_REACHED"
+	CodeEmailTaken            ErrorCode = "EMAIL_TAKEN"
+	CodeWeakPassword          ErrorCode = "WEAK_PASSWORD"
+	CodeVersionConflict       ErrorCode = "VERSION_CONFLICT"
+	CodeQueueBackpressure     ErrorCode = "QUEUE_BACKPRESSURE"
+	CodeIdempotencyKeyInUse   ErrorCode = "IDEMPOTENCY_KEY_IN_USE"
+	CodeIdempotencyKeyReused  ErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	CodeDependencyUnavailable ErrorCode = "DEPENDENCY_UNAVAILABLE"
+	CodeUploadNotFound        ErrorCode = "UPLOAD_NOT_FOUND"
+	CodeQuotaExceeded         ErrorCode = "QUOTA_EXCEEDED"
+	CodeCSRFTokenInvalid      ErrorCode = "CSRF_TOKEN_INVALID"
+	CodeInvalidTransition     ErrorCode = "INVALID_STATUS_TRANSITION"
+	CodeAlreadyBootstrapped   ErrorCode = "ALREADY_BOOTSTRAPPED"
+	CodeInvalidSignature      ErrorCode = "INVALID_SIGNATURE"
+	CodeTrackLocked           ErrorCode = "TRACK_LOCKED"
+)
+
+// FromError maps err to the API error it stands for. Application errors are
+// returned as they are, domain errors get their status and code, and any
+// other error becomes an internal error with message.
+func FromError(err error, message string) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		return NewUnauthorizedError("invalid credentials").WithCode(CodeInvalidCredentials)
+	case errors.Is(err, domain.ErrTokenReused):
+		return NewUnauthorizedError("refresh token reuse detected, please log in again").WithCode(CodeTokenReused)
+	case errors.Is(err, domain.ErrInvalidToken):
+		return NewUnauthorizedError("invalid or expired token").WithCode(CodeInvalidToken)
+	case errors.Is(err, domain.ErrInvalidAPIKey):
+		return NewUnauthorizedError("invalid API key").WithCode(CodeInvalidAPIKey)
+	case errors.Is(err, domain.ErrSessionExpired):
+		return NewUnauthorizedError("session expired").WithCode(CodeSessionExpired)
+	case errors.Is(err, domain.ErrMaxSessionsReached):
+		return NewConflictError("maximum number of sessions reached", "").WithCode(CodeMaxSessionsReached)
+	case errors.Is(err, domain.ErrUnauthorized):
+		return NewUnauthorizedError("unauthorized")
+	case errors.Is(err, domain.ErrForbidden):
+		return NewForbiddenError("insufficient permissions")
+	case errors.Is(err, domain.ErrSessionNotFound):
+		return NewNotFoundError("session not found")
+	case errors.Is(err, domain.ErrUserNotFound):
+		return NewNotFoundError("user not found")
+	case errors.Is(err, domain.ErrTrackNotFound):
+		return NewNotFoundError("track not found")
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		return NewForbiddenError("storage quota exceeded").WithCode(CodeQuotaExceeded)
+	case errors.Is(err, domain.ErrDeliveryNotFound):
+		return NewNotFoundError("delivery not found")
+	case errors.Is(err, domain.ErrImportMappingNotFound):
+		return NewNotFoundError("import mapping not found")
+	case errors.Is(err, domain.ErrCustomFieldNotFound):
+		return NewNotFoundError("custom field not found")
+	case errors.Is(err, domain.ErrTagNotFound):
+		return NewNotFoundError("tag not found")
+	case errors.Is(err, domain.ErrTagRuleNotFound):
+		return NewNotFoundError("tag rule not found")
+	case errors.Is(err, domain.ErrPublicAPIKeyNotFound):
+		return NewNotFoundError("public API key not found")
+	case errors.Is(err, domain.ErrDeadLetterNotFound):
+		return NewNotFoundError("dead letter not found")
+	case errors.Is(err, domain.ErrLabelNotFound):
+		return NewNotFoundError("label not found")
+	case errors.Is(err, domain.ErrEmbeddingNotFound):
+		return NewNotFoundError("track has no embedding")
+	case errors.Is(err, domain.ErrAnalysisNotFound):
+		return NewNotFoundError("track has no audio analysis")
+	case errors.Is(err, domain.ErrGoldenTrackNotFound):
+		return NewNotFoundError("track is not in the golden dataset")
+	case errors.Is(err, domain.ErrEvaluationNotFound):
+		return NewNotFoundError("no evaluation has run yet")
+	case errors.Is(err, domain.ErrAIBatchNotFound):
+		return NewNotFoundError("AI batch not found")
+	case errors.Is(err, domain.ErrLabelAIConfigNotFound):
+		return NewNotFoundError("label uses the global AI configuration")
+	case errors.Is(err, domain.ErrReenrichmentNotFound):
+		return NewNotFoundError("no re-enrichment has run yet")
+	case errors.Is(err, domain.ErrReenrichmentRunning):
+		return NewConflictError("re-enrichment is already running", "")
+	case errors.Is(err, domain.ErrEmailTaken):
+		return NewConflictError("email already registered", "").WithCode(CodeEmailTaken)
+	case errors.Is(err, domain.ErrSalesReportIngested):
+		return NewConflictError("sales report already ingested", err.Error())
+	case errors.Is(err, domain.ErrInvalidStatusTransition):
+		return NewConflictError("status change not allowed", err.Error()).WithCode(CodeInvalidTransition)
+	case errors.Is(err, domain.ErrTrackLocked):
+		return NewConflictError("track is being processed", err.Error()).WithCode(CodeTrackLocked)
+	case errors.Is(err, domain.ErrVersionConflict):
+		return NewConflictError("version conflict", err.Error()).WithCode(CodeVersionConflict)
+	case errors.Is(err, domain.ErrAlreadyBootstrapped):
+		return NewConflictError("environment already bootstrapped", err.Error()).WithCode(CodeAlreadyBootstrapped)
+	case errors.Is(err, domain.ErrIdempotencyKeyInUse):
+		return NewConflictError("a request with this idempotency key is in progress", "").WithCode(CodeIdempotencyKeyInUse)
+	case errors.Is(err, domain.ErrWeakPassword), errors.Is(err, domain.ErrInvalidPassword):
+		return NewValidationError("password does not meet requirements", err.Error()).WithCode(CodeWeakPassword)
+	case errors.Is(err, domain.ErrInvalidSignature):
+		return NewValidationError("bundle signature does not match", err.Error()).WithCode(CodeInvalidSignature)
+	case errors.Is(err, domain.ErrInvalidInput):
+		return NewValidationError(message, err.Error())
+	case errors.Is(err, domain.ErrResetRateLimited):
+		return NewRateLimitError("too many password reset requests")
+	case errors.Is(err, domain.ErrQueueBackpressure):
+		return NewRateLimitError("too many pending changes, retry later").WithCode(CodeQueueBackpressure)
+	default:
+		return NewInternalError(message, err)
+	}
+}
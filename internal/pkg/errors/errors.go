@@ -3,6 +3,7 @@ package errors
 import (
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // ErrorType represents the type of error
@@ -10,23 +11,51 @@ type ErrorType string
 
 const (
 	// Error types
-	ErrorTypeValidation   ErrorType = "VALIDATION_ERROR"
-	ErrorTypeNotFound     ErrorType = "NOT_FOUND"
-	ErrorTypeDatabase     ErrorType = "DATABASE_ERROR"
-	ErrorTypeStorage      ErrorType = "STORAGE_ERROR"
-	ErrorTypeAI           ErrorType = "AI_ERROR"
-	ErrorTypeUnauthorized ErrorType = "UNAUTHORIZED"
-	ErrorTypeForbidden    ErrorType = "FORBIDDEN"
-	ErrorTypeInternal     ErrorType = "INTERNAL_ERROR"
+	ErrorTypeValidation    ErrorType = "VALIDATION_ERROR"
+	ErrorTypeNotFound      ErrorType = "NOT_FOUND"
+	ErrorTypeDatabase      ErrorType = "DATABASE_ERROR"
+	ErrorTypeStorage       ErrorType = "STORAGE_ERROR"
+	ErrorTypeAI            ErrorType = "AI_ERROR"
+	ErrorTypeUnauthorized  ErrorType = "UNAUTHORIZED"
+	ErrorTypeForbidden     ErrorType = "FORBIDDEN"
+	ErrorTypeConflict      ErrorType = "CONFLICT"
+	ErrorTypePrecondition  ErrorType = "PRECONDITION_REQUIRED"
+	ErrorTypeNotAcceptable ErrorType = "NOT_ACCEPTABLE"
+	ErrorTypeMediaType     ErrorType = "UNSUPPORTED_MEDIA_TYPE"
+	ErrorTypeUnprocessable ErrorType = "UNPROCESSABLE_ENTITY"
+	ErrorTypeRateLimited   ErrorType = "RATE_LIMITED"
+	ErrorTypeUnavailable   ErrorType = "SERVICE_UNAVAILABLE"
+	ErrorTypeUnsupported   ErrorType = "NOT_IMPLEMENTED"
+	ErrorTypeInternal      ErrorType = "INTERNAL_ERROR"
 )
 
-// AppError represents an application error
+// Field error codes that are not the name of a failed validation tag
+const (
+	FieldCodeInvalidJSON = "invalid_json"
+	FieldCodeInvalidType = "invalid_type"
+	FieldCodeInvalid     = "invalid"
+)
+
+// FieldError reports why one field of a request was rejected. Code is
+// FieldCodeInvalidJSON, FieldCodeInvalidType, FieldCodeInvalid or the name
+// of the validation rule the field failed, such as "required" or "oneof".
+// Field is empty when the error concerns the whole body.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// AppError represents an application error. Code is the error's entry in
+// the error code catalog; without one the type is used as the code.
 type AppError struct {
-	Type       ErrorType `json:"type"`
-	Message    string    `json:"message"`
-	Details    string    `json:"details,omitempty"`
-	StatusCode int       `json:"-"`
-	Err        error     `json:"-"`
+	Type       ErrorType    `json:"type"`
+	Code       ErrorCode    `json:"code,omitempty"`
+	Message    string       `json:"message"`
+	Details    string       `json:"details,omitempty"`
+	Fields     []FieldError `json:"fields,omitempty"`
+	StatusCode int          `json:"-"`
+	Err        error        `json:"-"`
 }
 
 // Error implements the error interface
@@ -42,6 +71,20 @@ func (e *AppError) Unwrap() error {
 	return e.Err
 }
 
+// WithCode sets the catalog code of the error
+func (e *AppError) WithCode(code ErrorCode) *AppError {
+	e.Code = code
+	return e
+}
+
+// ErrorCode returns the catalog code of the error
+func (e *AppError) ErrorCode() ErrorCode {
+	if e.Code != "" {
+		return e.Code
+	}
+	return ErrorCode(e.Type)
+}
+
 // NewValidationError creates a new validation error
 func NewValidationError(message string, details string) *AppError {
 	return &AppError{
@@ -52,6 +95,22 @@ func NewValidationError(message string, details string) *AppError {
 	}
 }
 
+// NewFieldValidationError creates a validation error listing the rejected
+// fields of a request
+func NewFieldValidationError(message string, fields []FieldError) *AppError {
+	details := make([]string, len(fields))
+	for i, f := range fields {
+		details[i] = strings.TrimSpace(f.Field + " " + f.Message)
+	}
+	return &AppError{
+		Type:       ErrorTypeValidation,
+		Message:    message,
+		Details:    strings.Join(details, "; "),
+		Fields:     fields,
+		StatusCode: http.StatusBadRequest,
+	}
+}
+
 // NewNotFoundError creates a new not found error
 func NewNotFoundError(message string) *AppError {
 	return &AppError{
@@ -109,6 +168,87 @@ func NewForbiddenError(message string) *AppError {
 	}
 }
 
+// NewConflictError creates a new conflict error
+func NewConflictError(message string, details string) *AppError {
+	return &AppError{
+		Type:       ErrorTypeConflict,
+		Message:    message,
+		Details:    details,
+		StatusCode: http.StatusConflict,
+	}
+}
+
+// NewPreconditionRequiredError creates a new precondition required error
+func NewPreconditionRequiredError(message string, details string) *AppError {
+	return &AppError{
+		Type:       ErrorTypePrecondition,
+		Message:    message,
+		Details:    details,
+		StatusCode: http.StatusPreconditionRequired,
+	}
+}
+
+// NewNotAcceptableError creates a new error for a request whose Accept
+// header names no representation the endpoint can produce
+func NewNotAcceptableError(message string, details string) *AppError {
+	return &AppError{
+		Type:       ErrorTypeNotAcceptable,
+		Message:    message,
+		Details:    details,
+		StatusCode: http.StatusNotAcceptable,
+	}
+}
+
+// NewUnsupportedMediaTypeError creates a new error for a request body of a
+// content type the endpoint does not take
+func NewUnsupportedMediaTypeError(message string, details string) *AppError {
+	return &AppError{
+		Type:       ErrorTypeMediaType,
+		Message:    message,
+		Details:    details,
+		StatusCode: http.StatusUnsupportedMediaType,
+	}
+}
+
+// NewUnprocessableError creates a new error for a well-formed request that
+// cannot be handled as it stands
+func NewUnprocessableError(message string, details string) *AppError {
+	return &AppError{
+		Type:       ErrorTypeUnprocessable,
+		Message:    message,
+		Details:    details,
+		StatusCode: http.StatusUnprocessableEntity,
+	}
+}
+
+// NewRateLimitError creates a new error for a client that has to slow down
+func NewRateLimitError(message string) *AppError {
+	return &AppError{
+		Type:       ErrorTypeRateLimited,
+		Message:    message,
+		StatusCode: http.StatusTooManyRequests,
+	}
+}
+
+// NewUnavailableError creates a new error for a dependency that is down
+func NewUnavailableError(message string) *AppError {
+	return &AppError{
+		Type:       ErrorTypeUnavailable,
+		Message:    message,
+		StatusCode: http.StatusServiceUnavailable,
+	}
+}
+
+// NewNotImplementedError creates a new error for an endpoint that does not
+// work yet
+func NewNotImplementedError(message string) *AppError {
+	return &AppError{
+		Type:       ErrorTypeUnsupported,
+		Message:    message,
+		StatusCode: http.StatusNotImplemented,
+	}
+}
+
 // NewInternalError creates a new internal error
 func NewInternalError(message string, err error) *AppError {
 	return &AppError{
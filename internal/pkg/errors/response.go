@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIDHeader carries the request ID set by the request ID middleware
+const requestIDHeader = "X-Request-ID"
+
+// Response is the body of every error response of the API
+type Response struct {
+	Error ResponseError `json:"error"`
+}
+
+// ResponseError describes the error of a response. RequestID and TraceID
+// identify the request in logs and traces.
+type ResponseError struct {
+	Code      ErrorCode    `json:"code"`
+	Type      ErrorType    `json:"type"`
+	Message   string       `json:"message"`
+	Details   string       `json:"details,omitempty"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	TraceID   string       `json:"trace_id,omitempty"`
+}
+
+// Respond answers the request with err and stops the handler chain
+func Respond(c *gin.Context, err *AppError) {
+	c.AbortWithStatusJSON(err.StatusCode, Response{Error: Describe(c, err)})
+}
+
+// Describe returns the error body for err, for responses that carry more
+// than the error
+func Describe(c *gin.Context, err *AppError) ResponseError {
+	body := ResponseError{
+		Code:      err.ErrorCode(),
+		Type:      err.Type,
+		Message:   err.Message,
+		Details:   err.Details,
+		Fields:    err.Fields,
+		RequestID: c.Writer.Header().Get(requestIDHeader),
+	}
+	if span := trace.SpanContextFromContext(c.Request.Context()); span.HasTraceID() {
+		body.TraceID = span.TraceID().String()
+	}
+	return body
+}
@@ -1,8 +1,12 @@
 package errortracking
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"runtime/debug"
+	"time"
+
+	"metadatatool/internal/pkg/config"
 
 	"github.com/getsentry/sentry-go"
 )
@@ -12,17 +16,22 @@ type ErrorTracker struct {
 	initialized bool
 }
 
-// NewErrorTracker creates a new error tracker
-func NewErrorTracker() *ErrorTracker {
-	dsn := os.Getenv("SENTRY_DSN")
-	if dsn == "" {
+// NewErrorTracker creates a new error tracker reporting to the Sentry DSN
+// in cfg. Without a DSN errors are not reported.
+func NewErrorTracker(cfg config.SentryConfig) *ErrorTracker {
+	if cfg.DSN == "" {
 		return &ErrorTracker{initialized: false}
 	}
 
 	err := sentry.Init(sentry.ClientOptions{
-		Dsn:              dsn,
-		Environment:      os.Getenv("APP_ENV"),
-		TracesSampleRate: 1.0,
+		Dsn:              cfg.DSN,
+		Environment:      cfg.Environment,
+		Release:          release(cfg.Release),
+		Debug:            cfg.Debug,
+		SampleRate:       cfg.SampleRate,
+		EnableTracing:    cfg.TracesSampleRate > 0,
+		TracesSampleRate: cfg.TracesSampleRate,
+		AttachStacktrace: true,
 	})
 
 	if err != nil {
@@ -33,23 +42,57 @@ func NewErrorTracker() *ErrorTracker {
 	return &ErrorTracker{initialized: true}
 }
 
+// release returns the configured release, or the VCS revision the binary
+// was built from
+func release(configured string) string {
+	if configured != "" {
+		return configured
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return "metadatatool@" + setting.Value
+		}
+	}
+	return ""
+}
+
+// Enabled reports whether errors are sent to Sentry
+func (t *ErrorTracker) Enabled() bool {
+	return t != nil && t.initialized
+}
+
 // CaptureError reports an error to Sentry
 func (t *ErrorTracker) CaptureError(err error, tags map[string]string) {
-	if !t.initialized || err == nil {
+	t.CaptureErrorContext(context.Background(), err, tags)
+}
+
+// CaptureErrorContext reports an error to Sentry through the hub of ctx, so
+// errors raised while serving a request carry the request, its breadcrumbs
+// and its trace
+func (t *ErrorTracker) CaptureErrorContext(ctx context.Context, err error, tags map[string]string) {
+	if !t.Enabled() || err == nil {
 		return
 	}
 
-	sentry.WithScope(func(scope *sentry.Scope) {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	hub.WithScope(func(scope *sentry.Scope) {
 		for key, value := range tags {
 			scope.SetTag(key, value)
 		}
-		sentry.CaptureException(err)
+		hub.CaptureException(err)
 	})
 }
 
 // Close flushes any pending events
 func (t *ErrorTracker) Close() {
-	if t.initialized {
-		sentry.Flush(2)
+	if t.Enabled() {
+		sentry.Flush(2 * time.Second)
 	}
 }
@@ -0,0 +1,22 @@
+package errortracking
+
+import (
+	"testing"
+
+	"metadatatool/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorTracker_DisabledWithoutDSN(t *testing.T) {
+	tracker := NewErrorTracker(config.SentryConfig{Environment: "test"})
+	assert.False(t, tracker.Enabled())
+	assert.False(t, (*ErrorTracker)(nil).Enabled())
+
+	// Reporting is a no-op rather than an error
+	tracker.CaptureError(assert.AnError, map[string]string{"operation": "test"})
+}
+
+func TestRelease(t *testing.T) {
+	assert.Equal(t, "metadatatool@1.4.0", release("metadatatool@1.4.0"))
+}
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// CatalogTracks tracks the number of tracks by status
+	CatalogTracks = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "catalog_tracks",
+			Help: "The current number of tracks by status",
+		},
+		[]string{"status"},
+	)
+
+	// TrackStatusTransitions tracks status changes of tracks
+	TrackStatusTransitions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "track_status_transitions_total",
+			Help: "The total number of track status changes by previous and new status",
+		},
+		[]string{"from", "to"},
+	)
+
+	// Deliveries tracks track deliveries to DSPs by outcome
+	Deliveries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "deliveries_total",
+			Help: "The total number of track deliveries by DSP and status (sent, acknowledged, failed)",
+		},
+		[]string{"dsp", "status"},
+	)
+
+	// PlaysIngested counts the plays read from DSP usage reports
+	PlaysIngested = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dsr_plays_ingested_total",
+			Help: "The total number of plays ingested from DSP usage reports by DSP",
+		},
+		[]string{"dsp"},
+	)
+
+	// CatalogTracksNeedingReview tracks AI results flagged for review
+	CatalogTracksNeedingReview = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "catalog_tracks_needing_review",
+			Help: "The current number of tracks whose AI metadata needs review",
+		},
+	)
+
+	// CatalogQualityScore tracks the average AI confidence of enriched tracks
+	CatalogQualityScore = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "catalog_quality_score",
+			Help: "The average AI confidence (0-1) of enriched tracks",
+		},
+	)
+
+	// CatalogEnrichmentBacklog tracks the tracks not enriched yet
+	CatalogEnrichmentBacklog = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "catalog_enrichment_backlog",
+			Help: "The current number of tracks without AI metadata",
+		},
+	)
+
+	// CatalogExportSuccessRatio tracks the share of successful exports
+	CatalogExportSuccessRatio = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "catalog_export_success_ratio",
+			Help: "The share (0-1) of exports that succeeded in the last collection interval with exports",
+		},
+	)
+
+	// CatalogKPICollected tracks when the catalog KPIs were last collected
+	CatalogKPICollected = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "catalog_kpi_last_collected_timestamp_seconds",
+			Help: "Unix time of the last successful catalog KPI collection",
+		},
+	)
+
+	// ExportsTotal tracks exports by format and outcome
+	ExportsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "exports_total",
+			Help: "The total number of exports by format and status (success, failure)",
+		},
+		[]string{"format", "status"},
+	)
+
+	// AudioStreams tracks audio stream requests by how they were served
+	AudioStreams = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "audio_streams_total",
+			Help: "The total number of track audio streams by mode (proxy, redirect), rendition (preview, original) and status (full, partial, failure)",
+		},
+		[]string{"mode", "rendition", "status"},
+	)
+
+	// AudioStreamBytes tracks the audio bytes streamed through the API
+	AudioStreamBytes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "audio_stream_bytes_total",
+			Help: "The total number of audio bytes streamed through the API by rendition",
+		},
+		[]string{"rendition"},
+	)
+)
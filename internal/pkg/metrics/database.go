@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// DatabaseReplicaLag tracks the replication lag of each read replica
+	DatabaseReplicaLag = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "database_replica_lag_seconds",
+			Help: "Replication lag of each read replica in seconds",
+		},
+		[]string{"replica"},
+	)
+)
+
+var (
+	// DatabaseStatementDuration tracks the latency of every SQL statement
+	// issued through GORM
+	DatabaseStatementDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "database_statement_duration_seconds",
+			Help:    "Duration of SQL statements by pool, operation and table",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		},
+		[]string{"pool", "operation", "table"},
+	)
+)
+
+// RegisterDBStats exports the connection pool statistics of db under the
+// given pool name. Registering the same pool twice is a no-op.
+func RegisterDBStats(pool string, db *sql.DB) error {
+	err := prometheus.Register(collectors.NewDBStatsCollector(db, pool))
+	if err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if errors.As(err, &already) {
+			return nil
+		}
+		return fmt.Errorf("failed to register database pool metrics: %w", err)
+	}
+	return nil
+}
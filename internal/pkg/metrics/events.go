@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// DomainEventsPublished counts the domain events published in process
+	DomainEventsPublished = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "domain_events_published_total",
+			Help: "The total number of domain events published in process",
+		},
+		[]string{"event"},
+	)
+
+	// DomainEventDeliveries counts the deliveries of domain events to their
+	// subscribers by outcome
+	DomainEventDeliveries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "domain_event_deliveries_total",
+			Help: "The total number of domain event deliveries to subscribers",
+		},
+		[]string{"event", "subscriber", "status"},
+	)
+)
@@ -24,6 +24,16 @@ var (
 		[]string{"type", "priority"},
 	)
 
+	// JobQueueDepth tracks the pending jobs of each type, as counted by the
+	// job processors
+	JobQueueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "job_queue_depth",
+			Help: "The pending jobs of each type",
+		},
+		[]string{"type"},
+	)
+
 	// JobProcessingDuration tracks job processing duration
 	JobProcessingDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -89,4 +99,50 @@ var (
 		},
 		[]string{"operation", "status"},
 	)
+
+	// BackgroundTasks counts finished background tasks by outcome
+	BackgroundTasks = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "background_tasks_total",
+			Help: "The total number of finished background tasks",
+		},
+		[]string{"task", "status"},
+	)
+
+	// BackgroundTasksRunning tracks the background tasks currently running
+	BackgroundTasksRunning = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "background_tasks_running",
+			Help: "The number of background tasks currently running",
+		},
+		[]string{"task"},
+	)
+
+	// MaintenanceRuns counts finished runs of scheduled maintenance tasks by
+	// outcome
+	MaintenanceRuns = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "maintenance_runs_total",
+			Help: "The total number of finished maintenance task runs",
+		},
+		[]string{"task", "status"},
+	)
+
+	// MaintenanceRunDuration measures how long maintenance task runs take
+	MaintenanceRunDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "maintenance_run_duration_seconds",
+			Help:    "Time spent running maintenance tasks",
+			Buckets: prometheus.ExponentialBuckets(0.1, 4, 8),
+		},
+		[]string{"task"},
+	)
+
+	// MaintenanceLeader is 1 while this replica runs the maintenance tasks
+	MaintenanceLeader = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "maintenance_leader",
+			Help: "Whether this replica runs the maintenance tasks",
+		},
+	)
 )
@@ -162,6 +162,16 @@ var (
 		[]string{"provider"},
 	)
 
+	// AIFieldAccuracy tracks each provider's accuracy per field on the
+	// golden dataset, as of the latest evaluation
+	AIFieldAccuracy = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ai_field_accuracy",
+			Help: "Share of golden dataset tracks whose field the AI provider got right in the latest evaluation",
+		},
+		[]string{"provider", "field"},
+	)
+
 	// AIFallbackTotal tracks the number of times fallback was used
 	AIFallbackTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -180,6 +190,82 @@ var (
 		[]string{"provider", "error_type"},
 	)
 
+	// AIQueueDepth tracks AI requests waiting for a provider slot
+	AIQueueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ai_queue_depth",
+			Help: "Number of AI requests waiting for a provider slot",
+		},
+		[]string{"provider"},
+	)
+
+	// AIQueueWait tracks how long AI requests wait for a slot and the rate limit
+	AIQueueWait = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "ai_queue_wait_seconds",
+			Help:    "Time AI requests wait for a provider slot and rate limit",
+			Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"provider"},
+	)
+
+	// AIRequestsInFlight tracks AI requests currently sent to a provider
+	AIRequestsInFlight = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ai_requests_in_flight",
+			Help: "Number of AI requests currently sent to a provider",
+		},
+		[]string{"provider"},
+	)
+
+	// AIProviderHealthy tracks the health probes of each AI provider
+	AIProviderHealthy = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ai_provider_healthy",
+			Help: "Whether an AI provider passes its health probes (1) or not (0)",
+		},
+		[]string{"provider"},
+	)
+
+	// AIFailovers tracks AI requests moved to another provider
+	AIFailovers = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ai_failovers_total",
+			Help: "AI requests moved from a failing or unhealthy provider to another, by provider",
+		},
+		[]string{"from", "to"},
+	)
+
+	// AIPromptTokens tracks the estimated size of the prompts sent to AI
+	// providers
+	AIPromptTokens = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "ai_prompt_tokens",
+			Help:    "Estimated tokens of the prompts sent to AI providers",
+			Buckets: []float64{128, 256, 512, 1024, 2048, 4096, 8192, 16384},
+		},
+		[]string{"provider"},
+	)
+
+	// AIPromptTruncations tracks the prompt sections cut to fit the token
+	// budget
+	AIPromptTruncations = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ai_prompt_truncations_total",
+			Help: "Prompt sections cut to fit the token budget, by provider and section",
+		},
+		[]string{"provider", "section"},
+	)
+
+	// AnalyticsEvents tracks analytics events by table and outcome
+	AnalyticsEvents = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "analytics_events_total",
+			Help: "Analytics events by table and outcome (inserted, failed, dropped)",
+		},
+		[]string{"table", "status"},
+	)
+
 	// AI Service metrics
 	AIBatchProcessingDuration = promauto.NewHistogram(prometheus.HistogramOpts{
 		Name:    "ai_batch_processing_duration_seconds",
@@ -285,6 +371,10 @@ func init() {
 	DatabaseQueryDuration.WithLabelValues("track_get").Observe(0)
 	CacheHits.WithLabelValues("track").Add(0)
 	CacheMisses.WithLabelValues("track").Add(0)
+	CacheHits.WithLabelValues("track_isrc").Add(0)
+	CacheMisses.WithLabelValues("track_isrc").Add(0)
+	CacheHits.WithLabelValues("ai_enrichment").Add(0)
+	CacheMisses.WithLabelValues("ai_enrichment").Add(0)
 	AIRequestDuration.WithLabelValues("openai").Observe(0)
 	TracksProcessed.WithLabelValues("success").Add(0)
 	DatabaseConnections.WithLabelValues("active").Set(0)
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// OutboxEventsPublished tracks change feed events published from the outbox
+	OutboxEventsPublished = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "outbox_events_published_total",
+			Help: "The total number of change feed events published from the outbox",
+		},
+		[]string{"event_type"},
+	)
+
+	// OutboxPublishErrors tracks failed outbox publish attempts
+	OutboxPublishErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "outbox_publish_errors_total",
+			Help: "The total number of failed outbox publish attempts",
+		},
+		[]string{"event_type"},
+	)
+
+	// OutboxPendingEvents tracks the number of events waiting to be published
+	OutboxPendingEvents = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "outbox_pending_events",
+			Help: "The current number of unpublished change feed events",
+		},
+	)
+
+	// OutboxPublishLag tracks the delay between a change and its publication
+	OutboxPublishLag = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "outbox_publish_lag_seconds",
+			Help:    "Time between a track change being committed and published",
+			Buckets: []float64{0.1, 0.5, 1, 5, 15, 30, 60, 300},
+		},
+	)
+)
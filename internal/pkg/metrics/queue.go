@@ -134,4 +134,26 @@ var (
 		Help:    "Duration of batch message processing",
 		Buckets: []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
 	}, []string{"topic"})
+
+	// Consumer lag metrics
+	QueueLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "queue_lag_messages",
+		Help: "Messages waiting to be consumed at the last lag sample",
+	}, []string{"topic"})
+
+	QueuePublishRate = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "queue_publish_rate",
+		Help: "Messages published per second between the last two lag samples",
+	}, []string{"topic"})
+
+	// Backpressure metrics
+	QueueBackpressureLevel = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "queue_backpressure_level",
+		Help: "Backpressure applied to the topic: 0 none, 1 throttle, 2 reject",
+	}, []string{"topic"})
+
+	QueueBackpressureActions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "queue_backpressure_actions_total",
+		Help: "Messages held back by backpressure",
+	}, []string{"topic", "action"}) // action: downgraded, rejected
 )
@@ -68,3 +68,51 @@ var (
 		},
 	)
 )
+
+// Storage replication metrics
+var (
+	// StorageReplicationLag tracks the time from a write to the primary
+	// region until it reaches the replica
+	StorageReplicationLag = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "storage_replication_lag_seconds",
+			Help:    "Time from a write to the primary region until it is replicated",
+			Buckets: []float64{1, 5, 15, 30, 60, 300, 900, 3600},
+		},
+	)
+
+	// StorageReplicationLastLag tracks the lag of the latest replicated write
+	StorageReplicationLastLag = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "storage_replication_last_lag_seconds",
+			Help: "Replication lag of the most recently replicated write",
+		},
+	)
+
+	// StorageReplicationErrors tracks failed replication attempts
+	StorageReplicationErrors = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "storage_replication_errors_total",
+			Help: "Total number of failed replication attempts",
+		},
+	)
+
+	// StorageReplicaReads tracks reads served by the replica after the
+	// primary region failed
+	StorageReplicaReads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "storage_replica_reads_total",
+			Help: "Total number of reads served by the replica region",
+		},
+		[]string{"operation"},
+	)
+)
+
+// StorageIntegrityChecks tracks integrity checks of stored files by outcome
+var StorageIntegrityChecks = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "storage_integrity_checks_total",
+		Help: "Total number of stored file integrity checks by status",
+	},
+	[]string{"status"},
+)
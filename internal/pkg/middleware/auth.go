@@ -2,7 +2,7 @@ package middleware
 
 import (
 	"metadatatool/internal/pkg/domain"
-	"net/http"
+	apperrors "metadatatool/internal/pkg/errors"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -14,30 +14,21 @@ func AuthMiddleware(authService domain.AuthService) gin.HandlerFunc {
 		// Get the Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "missing authorization header",
-			})
-			c.Abort()
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("missing authorization header"))
 			return
 		}
 
 		// Check if it's a Bearer token
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "invalid authorization header format",
-			})
-			c.Abort()
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("invalid authorization header format"))
 			return
 		}
 
 		// Validate the token
-		claims, err := authService.ValidateToken(parts[1])
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "invalid token",
-			})
-			c.Abort()
+		claims, err := authService.ValidateToken(c.Request.Context(), parts[1])
+		if err != nil || claims.TokenType == domain.TokenTypeRefresh {
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("invalid token").WithCode(apperrors.CodeInvalidToken))
 			return
 		}
 
@@ -52,19 +43,13 @@ func RoleGuard(roles ...domain.Role) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		claims, exists := c.Get("user")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "missing user claims",
-			})
-			c.Abort()
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("missing user claims"))
 			return
 		}
 
 		userClaims, ok := claims.(*domain.Claims)
 		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "invalid user claims",
-			})
-			c.Abort()
+			apperrors.Respond(c, apperrors.NewInternalError("invalid user claims", nil))
 			return
 		}
 
@@ -76,10 +61,7 @@ func RoleGuard(roles ...domain.Role) gin.HandlerFunc {
 			}
 		}
 
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": "insufficient permissions",
-		})
-		c.Abort()
+		apperrors.Respond(c, apperrors.NewForbiddenError("insufficient permissions"))
 	}
 }
 
@@ -88,19 +70,13 @@ func APIKeyAuth(userRepo domain.UserRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		apiKey := c.GetHeader("X-API-Key")
 		if apiKey == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "missing API key",
-			})
-			c.Abort()
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("missing API key"))
 			return
 		}
 
 		user, err := userRepo.GetByAPIKey(c, apiKey)
 		if err != nil || user == nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "invalid API key",
-			})
-			c.Abort()
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("invalid API key").WithCode(apperrors.CodeInvalidAPIKey))
 			return
 		}
 
@@ -8,33 +8,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/prometheus/client_golang/prometheus"
 )
 
-var (
-	httpRequestsTotal = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "http_requests_total",
-			Help: "Total number of HTTP requests",
-		},
-		[]string{"method", "path", "status"},
-	)
-
-	httpRequestDuration = prometheus.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "http_request_duration_seconds",
-			Help:    "HTTP request duration in seconds",
-			Buckets: prometheus.DefBuckets,
-		},
-		[]string{"method", "path", "status"},
-	)
-)
-
-func init() {
-	prometheus.MustRegister(httpRequestsTotal)
-	prometheus.MustRegister(httpRequestDuration)
-}
-
 // MetricsMiddleware returns a middleware that collects HTTP metrics
 func MetricsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -58,10 +33,10 @@ func MetricsMiddleware() gin.HandlerFunc {
 		status := strconv.Itoa(c.Writer.Status())
 
 		// Record request total
-		httpRequestsTotal.WithLabelValues(method, path, status).Inc()
+		metrics.HttpRequestsTotal.WithLabelValues(status, method, path).Inc()
 
 		// Record request duration
-		httpRequestDuration.WithLabelValues(method, path, status).Observe(duration)
+		metrics.HttpRequestDuration.WithLabelValues(method, path).Observe(duration)
 
 		// Record errors if any
 		if len(c.Errors) > 0 {
@@ -177,7 +152,7 @@ func Metrics() gin.HandlerFunc {
 		status := strconv.Itoa(c.Writer.Status())
 		duration := time.Since(start).Seconds()
 
-		httpRequestsTotal.WithLabelValues(c.Request.Method, c.Request.URL.Path, status).Inc()
-		httpRequestDuration.WithLabelValues(c.Request.Method, c.Request.URL.Path, status).Observe(duration)
+		metrics.HttpRequestsTotal.WithLabelValues(status, c.Request.Method, c.Request.URL.Path).Inc()
+		metrics.HttpRequestDuration.WithLabelValues(c.Request.Method, c.Request.URL.Path).Observe(duration)
 	}
 }
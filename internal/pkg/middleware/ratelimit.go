@@ -3,30 +3,40 @@ package middleware
 import (
 	"fmt"
 	"metadatatool/internal/pkg/domain"
-	"net/http"
+	apperrors "metadatatool/internal/pkg/errors"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-redis/redis/v8"
+	"github.com/redis/go-redis/v9"
 )
 
 type RateLimitConfig struct {
 	RequestsPerMinute int
 	BurstSize         int
 	RedisClient       *redis.Client
+	// Limit, when set, is read on every request instead of
+	// RequestsPerMinute so the limit can change at runtime
+	Limit func() int
 }
 
-// RateLimit creates a rate limiting middleware using Redis
+// RateLimit creates a rate limiting middleware using Redis. A limit of zero
+// lets all requests through.
 func RateLimit(cfg RateLimitConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		limit := cfg.RequestsPerMinute
+		if cfg.Limit != nil {
+			limit = cfg.Limit()
+		}
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
 		// Get user identifier (API key or user ID)
 		identifier := getUserIdentifier(c)
 		if identifier == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "missing authentication",
-			})
-			c.Abort()
+			apperrors.Respond(c, apperrors.NewUnauthorizedError("missing authentication"))
 			return
 		}
 
@@ -48,10 +58,7 @@ func RateLimit(cfg RateLimitConfig) gin.HandlerFunc {
 			count = 0
 			lastTimestamp = time.Now()
 		} else if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "rate limit check failed",
-			})
-			c.Abort()
+			apperrors.Respond(c, apperrors.NewInternalError("rate limit check failed", err))
 			return
 		} else {
 			count, _ = strconv.Atoi(countCmd.Val())
@@ -67,16 +74,13 @@ func RateLimit(cfg RateLimitConfig) gin.HandlerFunc {
 		}
 
 		// Check rate limit
-		if count >= cfg.RequestsPerMinute {
-			c.Header("X-RateLimit-Limit", strconv.Itoa(cfg.RequestsPerMinute))
+		if count >= limit {
+			c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
 			c.Header("X-RateLimit-Remaining", "0")
 			c.Header("X-RateLimit-Reset", strconv.FormatInt(lastTimestamp.Add(time.Minute).Unix(), 10))
 
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":       "rate limit exceeded",
-				"retry_after": lastTimestamp.Add(time.Minute).Unix(),
-			})
-			c.Abort()
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(lastTimestamp.Add(time.Minute)).Seconds())+1))
+			apperrors.Respond(c, apperrors.NewRateLimitError("rate limit exceeded"))
 			return
 		}
 
@@ -86,16 +90,13 @@ func RateLimit(cfg RateLimitConfig) gin.HandlerFunc {
 		pipe.Set(c, timestampKey, lastTimestamp.Unix(), time.Minute)
 		_, err = pipe.Exec(c)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "rate limit update failed",
-			})
-			c.Abort()
+			apperrors.Respond(c, apperrors.NewInternalError("rate limit update failed", err))
 			return
 		}
 
 		// Set rate limit headers
-		c.Header("X-RateLimit-Limit", strconv.Itoa(cfg.RequestsPerMinute))
-		c.Header("X-RateLimit-Remaining", strconv.Itoa(cfg.RequestsPerMinute-count-1))
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(limit-count-1))
 		c.Header("X-RateLimit-Reset", strconv.FormatInt(lastTimestamp.Add(time.Minute).Unix(), 10))
 
 		c.Next()
@@ -12,9 +12,7 @@ import (
 )
 
 // SentryMiddleware creates a middleware for Sentry error tracking
-func SentryMiddleware() fiber.Handler {
-	errorTracker := errortracking.NewErrorTracker()
-
+func SentryMiddleware(errorTracker *errortracking.ErrorTracker) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Start a new transaction
 		hub := sentry.CurrentHub().Clone()
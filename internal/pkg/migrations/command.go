@@ -0,0 +1,81 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Usage describes the arguments accepted by RunCommand
+const Usage = `usage: migrate <command>
+
+commands:
+  up          apply all pending migrations
+  down [n]    roll back the last n migrations (default 1)
+  version     print the current schema version
+  force <v>   set the schema version and clear the dirty flag`
+
+// RunCommand runs a migrate subcommand against db with the embedded
+// migrations and the given settings, and writes the outcome to out
+func RunCommand(ctx context.Context, db *sql.DB, args []string, settings map[string]string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate command\n%s", Usage)
+	}
+
+	m, err := New(db, FS())
+	if err != nil {
+		return err
+	}
+	for name, value := range settings {
+		m.Set(name, value)
+	}
+
+	switch args[0] {
+	case "up":
+		version, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "schema at version %d\n", version)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		version, err := m.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "schema at version %d\n", version)
+	case "version":
+		version, dirty, err := m.Version(ctx)
+		if err != nil {
+			return err
+		}
+		state := "clean"
+		if dirty {
+			state = "dirty"
+		}
+		fmt.Fprintf(out, "schema at version %d (%s), latest is %d\n", version, state, m.Latest())
+	case "force":
+		if len(args) < 2 {
+			return fmt.Errorf("force needs a version\n%s", Usage)
+		}
+		version, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		if err := m.Force(ctx, uint(version)); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "schema forced to version %d\n", version)
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], Usage)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000002_add_users.up.sql":    {Data: []byte("CREATE TABLE users ();")},
+		"000002_add_users.down.sql":  {Data: []byte("DROP TABLE users;")},
+		"000001_add_tracks.up.sql":   {Data: []byte("CREATE TABLE tracks ();")},
+		"000001_add_tracks.down.sql": {Data: []byte("DROP TABLE tracks;")},
+		"README.md":                  {Data: []byte("ignored")},
+	}
+
+	migrations, err := Load(fsys)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, Migration{Version: 1, Name: "add_tracks", Up: "CREATE TABLE tracks ();", Down: "DROP TABLE tracks;"}, migrations[0])
+	assert.Equal(t, uint(2), migrations[1].Version)
+
+	_, err = Load(fstest.MapFS{"000001_orphan.down.sql": {Data: []byte("DROP TABLE x;")}})
+	assert.Error(t, err)
+
+	_, err = Load(fstest.MapFS{
+		"000001_a.up.sql": {Data: []byte("SELECT 1;")},
+		"000001_b.up.sql": {Data: []byte("SELECT 2;")},
+	})
+	assert.Error(t, err)
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := Load(FS())
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, migration := range migrations {
+		assert.Equal(t, uint(i+1), migration.Version, "migration versions must be contiguous")
+		assert.NotEmpty(t, migration.Down, "migration %d_%s needs a down file", migration.Version, migration.Name)
+	}
+}
+
+func TestCompareVersion(t *testing.T) {
+	assert.NoError(t, compareVersion(3, false, 3))
+	assert.ErrorIs(t, compareVersion(2, false, 3), ErrVersionMismatch)
+	assert.ErrorIs(t, compareVersion(4, false, 3), ErrVersionMismatch)
+	assert.ErrorIs(t, compareVersion(3, true, 3), ErrDirty)
+}
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS track_outbox (
+    sequence BIGSERIAL PRIMARY KEY,
+    aggregate_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    version INTEGER NOT NULL DEFAULT 0,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMP WITH TIME ZONE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+
+-- Publisher scans unpublished events in sequence order
+CREATE INDEX idx_track_outbox_pending ON track_outbox(sequence) WHERE published_at IS NULL;
+CREATE INDEX idx_track_outbox_aggregate_id ON track_outbox(aggregate_id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS analytics_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_table VARCHAR(64) NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    tenant VARCHAR(255) NOT NULL DEFAULT '',
+    payload JSONB NOT NULL
+);
+
+CREATE INDEX idx_analytics_events_table_occurred_at ON analytics_events(event_table, occurred_at);
+CREATE INDEX idx_analytics_events_tenant ON analytics_events(tenant, occurred_at);
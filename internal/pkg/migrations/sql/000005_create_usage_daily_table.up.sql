@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS usage_daily (
+    label_id VARCHAR(255) NOT NULL DEFAULT '',
+    day VARCHAR(10) NOT NULL,
+    metric VARCHAR(32) NOT NULL,
+    value BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (label_id, day, metric)
+);
+
+-- Reports select a range of days across labels
+CREATE INDEX idx_usage_daily_day ON usage_daily(day);
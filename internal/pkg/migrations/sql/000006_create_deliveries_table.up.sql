@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS deliveries (
+    id VARCHAR(255) PRIMARY KEY,
+    dsp VARCHAR(255) NOT NULL,
+    track_id VARCHAR(255) NOT NULL,
+    release_id VARCHAR(255),
+    package_id VARCHAR(255) NOT NULL,
+    package_format VARCHAR(50),
+    status VARCHAR(20) NOT NULL,
+    error TEXT,
+    delivered_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Acknowledgements update every delivery of a package
+CREATE INDEX idx_deliveries_package_id ON deliveries(package_id);
+CREATE INDEX idx_deliveries_track_id ON deliveries(track_id);
+CREATE INDEX idx_deliveries_release_id ON deliveries(release_id);
+-- Failed deliveries are listed per DSP
+CREATE INDEX idx_deliveries_dsp_status ON deliveries(dsp, status);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_deliveries_track_id_kind;
+ALTER TABLE deliveries DROP COLUMN IF EXISTS territories;
+ALTER TABLE deliveries DROP COLUMN IF EXISTS kind;
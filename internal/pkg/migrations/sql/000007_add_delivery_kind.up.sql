@@ -0,0 +1,6 @@
+-- Takedowns and territory blocks are delivered and acknowledged like
+-- releases and share the table
+ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS kind VARCHAR(20) NOT NULL DEFAULT 'release';
+ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS territories TEXT;
+
+CREATE INDEX idx_deliveries_track_id_kind ON deliveries(track_id, kind);
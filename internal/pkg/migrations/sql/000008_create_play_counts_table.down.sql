@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS sales_reports;
+DROP TABLE IF EXISTS play_counts;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS play_counts (
+    track_id VARCHAR(255) NOT NULL,
+    dsp VARCHAR(255) NOT NULL,
+    territory VARCHAR(2) NOT NULL,
+    month VARCHAR(7) NOT NULL,
+    isrc VARCHAR(12),
+    plays BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (track_id, dsp, territory, month)
+);
+
+-- Royalty reports select a range of months across tracks
+CREATE INDEX idx_play_counts_month ON play_counts(month);
+
+CREATE TABLE IF NOT EXISTS sales_reports (
+    dsp VARCHAR(255) NOT NULL,
+    message_id VARCHAR(255) NOT NULL,
+    month VARCHAR(7) NOT NULL,
+    records INTEGER NOT NULL DEFAULT 0,
+    unmatched INTEGER NOT NULL DEFAULT 0,
+    plays BIGINT NOT NULL DEFAULT 0,
+    ingested_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (dsp, message_id)
+);
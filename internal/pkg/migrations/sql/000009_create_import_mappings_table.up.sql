@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS import_mappings (
+    id VARCHAR(255) PRIMARY KEY,
+    label_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    delimiter VARCHAR(1),
+    columns TEXT NOT NULL,
+    defaults TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Imports select a label's mapping by name
+CREATE UNIQUE INDEX idx_import_mappings_label_name ON import_mappings(label_id, name);
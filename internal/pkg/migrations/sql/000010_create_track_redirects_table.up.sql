@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS track_redirects (
+    source_id VARCHAR(255) PRIMARY KEY,
+    target_id VARCHAR(255) NOT NULL,
+    merged_by VARCHAR(255),
+    merged_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Merges rewrite the redirects to the merged track
+CREATE INDEX idx_track_redirects_target_id ON track_redirects(target_id);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS custom_field_definitions (
+    label_id VARCHAR(255) NOT NULL,
+    name VARCHAR(63) NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    description TEXT,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    pattern TEXT,
+    min DOUBLE PRECISION,
+    max DOUBLE PRECISION,
+    "values" TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (label_id, name)
+);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS tags (
+    name VARCHAR(255) PRIMARY KEY,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS tag_rules (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255),
+    tag VARCHAR(255) NOT NULL,
+    conditions TEXT NOT NULL,
+    disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Renaming, merging and deleting tags rewrites the rules adding them
+CREATE INDEX idx_tag_rules_tag ON tag_rules(tag);
+
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS public_api_keys;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS public_api_keys (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    label_id VARCHAR(255) NOT NULL,
+    tier VARCHAR(32) NOT NULL,
+    prefix VARCHAR(32) NOT NULL,
+    -- SHA-256 of the key; the key itself is only shown when it is created
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_public_api_keys_label_id ON public_api_keys(label_id);
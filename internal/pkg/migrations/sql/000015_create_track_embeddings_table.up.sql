@@ -0,0 +1,26 @@
+-- Embeddings are stored and compared with the pgvector extension, which
+-- only databases with database.vector_search on are expected to have
+DO $$
+BEGIN
+    IF coalesce(current_setting('metadatatool.vector_search', true), '') <> 'true' THEN
+        RETURN;
+    END IF;
+
+    CREATE EXTENSION IF NOT EXISTS vector;
+
+    -- The column holds 1536 dimensions so it can be indexed; shorter
+    -- embeddings are padded with zeros, which leaves cosine distances as
+    -- they are, and dimensions records their own size. Only embeddings of
+    -- the same model and size are compared.
+    CREATE TABLE IF NOT EXISTS track_embeddings (
+        track_id UUID PRIMARY KEY REFERENCES tracks(id) ON DELETE CASCADE,
+        model VARCHAR(255) NOT NULL,
+        dimensions INTEGER NOT NULL,
+        embedding vector(1536) NOT NULL,
+        updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_track_embeddings_model ON track_embeddings(model, dimensions);
+    CREATE INDEX IF NOT EXISTS idx_track_embeddings_embedding ON track_embeddings
+        USING hnsw (embedding vector_cosine_ops);
+END $$;
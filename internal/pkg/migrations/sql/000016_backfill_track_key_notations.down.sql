@@ -0,0 +1,14 @@
+DO $$
+BEGIN
+IF NOT EXISTS (
+    SELECT 1 FROM information_schema.columns
+    WHERE table_schema = current_schema() AND table_name = 'tracks' AND column_name = 'metadata'
+) THEN
+    RETURN;
+END IF;
+
+UPDATE tracks
+SET metadata = jsonb_set(metadata, '{musical}', (metadata->'musical') - 'camelot' - 'openKey')
+WHERE metadata->'musical'->'camelot' IS NOT NULL OR metadata->'musical'->'openKey' IS NOT NULL;
+END
+$$;
@@ -0,0 +1,58 @@
+-- Write the Camelot and Open Key notations of the keys of existing tracks;
+-- tracks written from now on derive them as they are saved. A key is read
+-- with its mode first, as in "A" with "minor", then on its own.
+--
+-- Databases migrated from 000001 only get the metadata column in 000022,
+-- so their tracks hold no keys to backfill yet and the backfill is skipped.
+DO $$
+BEGIN
+IF NOT EXISTS (
+    SELECT 1 FROM information_schema.columns
+    WHERE table_schema = current_schema() AND table_name = 'tracks' AND column_name = 'metadata'
+) THEN
+    RETURN;
+END IF;
+
+WITH key_notations (spelling, camelot, open_key) AS (
+    VALUES
+    ('1a', '1A', '6m'), ('6m', '1A', '6m'), ('g#m', '1A', '6m'), ('g# minor', '1A', '6m'), ('g# min', '1A', '6m'), ('abm', '1A', '6m'), ('ab minor', '1A', '6m'), ('ab min', '1A', '6m'),
+    ('2a', '2A', '7m'), ('7m', '2A', '7m'), ('d#m', '2A', '7m'), ('d# minor', '2A', '7m'), ('d# min', '2A', '7m'), ('ebm', '2A', '7m'), ('eb minor', '2A', '7m'), ('eb min', '2A', '7m'),
+    ('3a', '3A', '8m'), ('8m', '3A', '8m'), ('a#m', '3A', '8m'), ('a# minor', '3A', '8m'), ('a# min', '3A', '8m'), ('bbm', '3A', '8m'), ('bb minor', '3A', '8m'), ('bb min', '3A', '8m'),
+    ('4a', '4A', '9m'), ('9m', '4A', '9m'), ('fm', '4A', '9m'), ('f minor', '4A', '9m'), ('f min', '4A', '9m'),
+    ('5a', '5A', '10m'), ('10m', '5A', '10m'), ('cm', '5A', '10m'), ('c minor', '5A', '10m'), ('c min', '5A', '10m'),
+    ('6a', '6A', '11m'), ('11m', '6A', '11m'), ('gm', '6A', '11m'), ('g minor', '6A', '11m'), ('g min', '6A', '11m'),
+    ('7a', '7A', '12m'), ('12m', '7A', '12m'), ('dm', '7A', '12m'), ('d minor', '7A', '12m'), ('d min', '7A', '12m'),
+    ('8a', '8A', '1m'), ('1m', '8A', '1m'), ('am', '8A', '1m'), ('a minor', '8A', '1m'), ('a min', '8A', '1m'),
+    ('9a', '9A', '2m'), ('2m', '9A', '2m'), ('em', '9A', '2m'), ('e minor', '9A', '2m'), ('e min', '9A', '2m'),
+    ('10a', '10A', '3m'), ('3m', '10A', '3m'), ('bm', '10A', '3m'), ('b minor', '10A', '3m'), ('b min', '10A', '3m'),
+    ('11a', '11A', '4m'), ('4m', '11A', '4m'), ('f#m', '11A', '4m'), ('f# minor', '11A', '4m'), ('f# min', '11A', '4m'), ('gbm', '11A', '4m'), ('gb minor', '11A', '4m'), ('gb min', '11A', '4m'),
+    ('12a', '12A', '5m'), ('5m', '12A', '5m'), ('c#m', '12A', '5m'), ('c# minor', '12A', '5m'), ('c# min', '12A', '5m'), ('dbm', '12A', '5m'), ('db minor', '12A', '5m'), ('db min', '12A', '5m'),
+    ('1b', '1B', '6d'), ('6d', '1B', '6d'), ('b', '1B', '6d'), ('b major', '1B', '6d'), ('b maj', '1B', '6d'),
+    ('2b', '2B', '7d'), ('7d', '2B', '7d'), ('f#', '2B', '7d'), ('f# major', '2B', '7d'), ('f# maj', '2B', '7d'), ('gb', '2B', '7d'), ('gb major', '2B', '7d'), ('gb maj', '2B', '7d'),
+    ('3b', '3B', '8d'), ('8d', '3B', '8d'), ('c#', '3B', '8d'), ('c# major', '3B', '8d'), ('c# maj', '3B', '8d'), ('db', '3B', '8d'), ('db major', '3B', '8d'), ('db maj', '3B', '8d'),
+    ('4b', '4B', '9d'), ('9d', '4B', '9d'), ('g#', '4B', '9d'), ('g# major', '4B', '9d'), ('g# maj', '4B', '9d'), ('ab', '4B', '9d'), ('ab major', '4B', '9d'), ('ab maj', '4B', '9d'),
+    ('5b', '5B', '10d'), ('10d', '5B', '10d'), ('d#', '5B', '10d'), ('d# major', '5B', '10d'), ('d# maj', '5B', '10d'), ('eb', '5B', '10d'), ('eb major', '5B', '10d'), ('eb maj', '5B', '10d'),
+    ('6b', '6B', '11d'), ('11d', '6B', '11d'), ('a#', '6B', '11d'), ('a# major', '6B', '11d'), ('a# maj', '6B', '11d'), ('bb', '6B', '11d'), ('bb major', '6B', '11d'), ('bb maj', '6B', '11d'),
+    ('7b', '7B', '12d'), ('12d', '7B', '12d'), ('f', '7B', '12d'), ('f major', '7B', '12d'), ('f maj', '7B', '12d'),
+    ('8b', '8B', '1d'), ('1d', '8B', '1d'), ('c', '8B', '1d'), ('c major', '8B', '1d'), ('c maj', '8B', '1d'),
+    ('9b', '9B', '2d'), ('2d', '9B', '2d'), ('g', '9B', '2d'), ('g major', '9B', '2d'), ('g maj', '9B', '2d'),
+    ('10b', '10B', '3d'), ('3d', '10B', '3d'), ('d', '10B', '3d'), ('d major', '10B', '3d'), ('d maj', '10B', '3d'),
+    ('11b', '11B', '4d'), ('4d', '11B', '4d'), ('a', '11B', '4d'), ('a major', '11B', '4d'), ('a maj', '11B', '4d'),
+    ('12b', '12B', '5d'), ('5d', '12B', '5d'), ('e', '12B', '5d'), ('e major', '12B', '5d'), ('e maj', '12B', '5d')
+),
+track_keys AS (
+    SELECT DISTINCT ON (t.id) t.id, n.camelot, n.open_key
+    FROM tracks t
+    JOIN key_notations n ON n.spelling IN (
+        LOWER(TRIM(CONCAT(t.metadata->'musical'->>'key', ' ', t.metadata->'musical'->>'mode'))),
+        LOWER(TRIM(t.metadata->'musical'->>'key'))
+    )
+    ORDER BY t.id, n.spelling = LOWER(TRIM(CONCAT(t.metadata->'musical'->>'key', ' ', t.metadata->'musical'->>'mode'))) DESC
+)
+UPDATE tracks
+SET metadata = jsonb_set(jsonb_set(tracks.metadata, '{musical,camelot}', to_jsonb(track_keys.camelot)),
+    '{musical,openKey}', to_jsonb(track_keys.open_key))
+FROM track_keys
+WHERE tracks.id = track_keys.id;
+END
+$$;
@@ -0,0 +1,11 @@
+-- The analysis of each analyzer version is kept, so tracks not yet analyzed
+-- by a new version keep the analysis of the previous one
+CREATE TABLE IF NOT EXISTS track_analyses (
+    track_id VARCHAR(255) NOT NULL,
+    analyzer_version VARCHAR(100) NOT NULL,
+    analysis JSONB NOT NULL,
+    analyzed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (track_id, analyzer_version)
+);
+
+CREATE INDEX idx_track_analyses_version ON track_analyses(analyzer_version);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS golden_tracks;
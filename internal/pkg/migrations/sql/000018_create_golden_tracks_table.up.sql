@@ -0,0 +1,11 @@
+-- Values confirmed by a person, which the AI providers are scored against
+CREATE TABLE IF NOT EXISTS golden_tracks (
+    track_id VARCHAR(255) PRIMARY KEY,
+    genre VARCHAR(255),
+    mood VARCHAR(255),
+    bpm DOUBLE PRECISION,
+    key VARCHAR(50),
+    labeled_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,18 @@
+-- Tracks enriched through a provider's batch API, whose results are saved
+-- once the provider is done
+CREATE TABLE IF NOT EXISTS ai_batches (
+    id VARCHAR(255) PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    provider_batch_id VARCHAR(255) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    track_ids JSONB,
+    enriched INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    requested_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_ai_batches_status ON ai_batches(status);
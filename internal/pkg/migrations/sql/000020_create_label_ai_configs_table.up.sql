@@ -0,0 +1,13 @@
+-- AI configurations overriding the global one for the tracks of a label.
+-- API keys are encrypted by the application before they are stored.
+CREATE TABLE IF NOT EXISTS label_ai_configs (
+    label_id VARCHAR(255) PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    encrypted_api_key TEXT,
+    api_key_hint VARCHAR(16),
+    model VARCHAR(255),
+    min_confidence DOUBLE PRECISION,
+    updated_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
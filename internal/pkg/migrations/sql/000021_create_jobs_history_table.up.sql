@@ -0,0 +1,19 @@
+-- Finished background jobs moved out of Redis for long-term reporting
+CREATE TABLE IF NOT EXISTS jobs_history (
+    id VARCHAR(255) PRIMARY KEY,
+    queue VARCHAR(100) NOT NULL,
+    type VARCHAR(100) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    payload JSONB,
+    error TEXT,
+    retry_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_ms BIGINT,
+    archived_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_jobs_history_finished_at ON jobs_history(finished_at);
+CREATE INDEX idx_jobs_history_type_finished_at ON jobs_history(type, finished_at);
@@ -0,0 +1,57 @@
+DROP INDEX IF EXISTS idx_tracks_status;
+DROP INDEX IF EXISTS idx_tracks_release_id;
+DROP INDEX IF EXISTS idx_tracks_label_id;
+DROP INDEX IF EXISTS idx_tracks_isrc;
+
+ALTER TABLE tracks
+    ADD COLUMN IF NOT EXISTS title VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS artist VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS album VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS isrc VARCHAR(12),
+    ADD COLUMN IF NOT EXISTS iswc VARCHAR(11),
+    ADD COLUMN IF NOT EXISTS year INTEGER,
+    ADD COLUMN IF NOT EXISTS label VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS publisher VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS bpm DECIMAL(5,2),
+    ADD COLUMN IF NOT EXISTS key VARCHAR(10),
+    ADD COLUMN IF NOT EXISTS mood VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS genre VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS ai_confidence DECIMAL(4,3),
+    ADD COLUMN IF NOT EXISTS model_version VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS needs_review BOOLEAN DEFAULT false,
+    ADD COLUMN IF NOT EXISTS territory VARCHAR(2);
+
+UPDATE tracks
+SET title = COALESCE(metadata->'basic'->>'title', ''),
+    artist = COALESCE(metadata->'basic'->>'artist', ''),
+    album = NULLIF(metadata->'basic'->>'album', ''),
+    isrc = LEFT(NULLIF(metadata->'basic'->>'isrc', ''), 12),
+    iswc = LEFT(metadata->'additional'->'customFields'->>'iswc', 11),
+    year = NULLIF((metadata->'basic'->>'year')::INTEGER, 0),
+    label = metadata->'additional'->'customFields'->>'label',
+    publisher = NULLIF(metadata->'additional'->>'publisher', ''),
+    bpm = NULLIF((metadata->'musical'->>'bpm')::DECIMAL, 0),
+    key = LEFT(NULLIF(metadata->'musical'->>'key', ''), 10),
+    mood = LEFT(NULLIF(metadata->'musical'->>'mood', ''), 50),
+    genre = LEFT(NULLIF(metadata->'musical'->>'genre', ''), 50),
+    ai_confidence = (metadata->'ai'->>'confidence')::DECIMAL,
+    model_version = LEFT(metadata->'ai'->>'version', 50),
+    needs_review = status = 'needs_review',
+    territory = LEFT(metadata->'additional'->'customFields'->>'territory', 2);
+
+ALTER TABLE tracks
+    DROP COLUMN IF EXISTS status_msg,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS previous_id,
+    DROP COLUMN IF EXISTS version,
+    DROP COLUMN IF EXISTS release_id,
+    DROP COLUMN IF EXISTS artist_ids,
+    DROP COLUMN IF EXISTS label_id,
+    DROP COLUMN IF EXISTS metadata,
+    DROP COLUMN IF EXISTS file_size,
+    DROP COLUMN IF EXISTS file_path,
+    DROP COLUMN IF EXISTS storage_path;
+
+CREATE INDEX idx_tracks_isrc ON tracks(isrc) WHERE deleted_at IS NULL;
+CREATE INDEX idx_tracks_artist ON tracks(artist) WHERE deleted_at IS NULL;
+CREATE INDEX idx_tracks_label ON tracks(label) WHERE deleted_at IS NULL;
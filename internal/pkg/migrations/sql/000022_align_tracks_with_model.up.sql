@@ -0,0 +1,63 @@
+-- Bring tracks to the shape of domain.Track, which keeps its metadata in a
+-- JSON document instead of the flat columns of 000001. The values of
+-- existing rows move into the document before the flat columns go.
+ALTER TABLE tracks
+    ADD COLUMN IF NOT EXISTS storage_path TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS file_path TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS file_size BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS label_id VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS artist_ids JSONB,
+    ADD COLUMN IF NOT EXISTS release_id VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS previous_id VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    ADD COLUMN IF NOT EXISTS status_msg TEXT NOT NULL DEFAULT '';
+
+UPDATE tracks
+SET metadata = jsonb_build_object(
+        'basic', jsonb_build_object(
+            'title', title,
+            'artist', artist,
+            'album', COALESCE(album, ''),
+            'year', COALESCE(year, 0),
+            'isrc', COALESCE(isrc, '')),
+        'musical', jsonb_build_object(
+            'bpm', COALESCE(bpm, 0),
+            'key', COALESCE(key, ''),
+            'mood', COALESCE(mood, ''),
+            'genre', COALESCE(genre, '')),
+        'additional', jsonb_build_object(
+            'publisher', COALESCE(publisher, ''),
+            'customFields', jsonb_strip_nulls(jsonb_build_object(
+                'iswc', iswc, 'label', label, 'territory', territory))))
+    || CASE WHEN ai_confidence IS NULL AND model_version IS NULL THEN '{}'::jsonb
+       ELSE jsonb_build_object('ai', jsonb_build_object(
+            'confidence', COALESCE(ai_confidence, 0),
+            'version', COALESCE(model_version, ''),
+            'needsReview', COALESCE(needs_review, false)))
+       END,
+    status = CASE WHEN needs_review THEN 'needs_review' ELSE 'pending' END;
+
+ALTER TABLE tracks
+    DROP COLUMN IF EXISTS title,
+    DROP COLUMN IF EXISTS artist,
+    DROP COLUMN IF EXISTS album,
+    DROP COLUMN IF EXISTS isrc,
+    DROP COLUMN IF EXISTS iswc,
+    DROP COLUMN IF EXISTS year,
+    DROP COLUMN IF EXISTS label,
+    DROP COLUMN IF EXISTS publisher,
+    DROP COLUMN IF EXISTS bpm,
+    DROP COLUMN IF EXISTS key,
+    DROP COLUMN IF EXISTS mood,
+    DROP COLUMN IF EXISTS genre,
+    DROP COLUMN IF EXISTS ai_confidence,
+    DROP COLUMN IF EXISTS model_version,
+    DROP COLUMN IF EXISTS needs_review,
+    DROP COLUMN IF EXISTS territory;
+
+CREATE INDEX idx_tracks_isrc ON tracks((metadata->'basic'->>'isrc')) WHERE deleted_at IS NULL;
+CREATE INDEX idx_tracks_label_id ON tracks(label_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_tracks_release_id ON tracks(release_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_tracks_status ON tracks(status) WHERE deleted_at IS NULL;
@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS idx_users_api_key;
+UPDATE users SET api_key = NULL WHERE api_key = '';
+ALTER TABLE users ADD CONSTRAINT users_api_key_key UNIQUE (api_key);
+CREATE INDEX idx_users_api_key ON users(api_key) WHERE deleted_at IS NULL;
+
+ALTER TABLE users DROP COLUMN IF EXISTS permissions;
+
+CREATE TYPE user_role AS ENUM ('admin', 'label_user', 'api_user');
+CREATE TYPE subscription_plan AS ENUM ('free', 'pro', 'enterprise');
+
+ALTER TABLE users ALTER COLUMN role TYPE user_role
+    USING (CASE role WHEN 'admin' THEN 'admin' ELSE 'label_user' END)::user_role;
+
+ALTER TABLE users ALTER COLUMN plan DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN plan TYPE subscription_plan
+    USING (CASE plan WHEN 'business' THEN 'enterprise' WHEN 'basic' THEN 'free' ELSE plan END)::subscription_plan;
+ALTER TABLE users ALTER COLUMN plan SET DEFAULT 'free';
@@ -0,0 +1,19 @@
+-- Bring users to the shape of domain.User. Roles and plans are the strings
+-- of domain.Role and domain.SubscriptionPlan rather than enums, and users
+-- without an API key store it blank, so only set keys must be unique.
+ALTER TABLE users ALTER COLUMN role TYPE VARCHAR(50)
+    USING (CASE role::TEXT WHEN 'admin' THEN 'admin' ELSE 'user' END);
+
+ALTER TABLE users ALTER COLUMN plan DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN plan TYPE VARCHAR(50)
+    USING (CASE plan::TEXT WHEN 'enterprise' THEN 'business' ELSE plan::TEXT END);
+ALTER TABLE users ALTER COLUMN plan SET DEFAULT 'free';
+
+DROP TYPE IF EXISTS user_role;
+DROP TYPE IF EXISTS subscription_plan;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS permissions JSONB;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_api_key_key;
+DROP INDEX IF EXISTS idx_users_api_key;
+CREATE UNIQUE INDEX idx_users_api_key ON users(api_key) WHERE api_key <> '';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_admin;
+DROP TABLE IF EXISTS bootstrap_marker;
@@ -0,0 +1,18 @@
+-- A bootstrap claims the only row of this table in the transaction that
+-- creates the first admin, so concurrent bootstraps cannot both create one
+CREATE TABLE IF NOT EXISTS bootstrap_marker (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    admin_email VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Environments bootstrapped before the marker are claimed by their oldest
+-- admin
+INSERT INTO bootstrap_marker (id, admin_email, created_at)
+SELECT 1, email, created_at FROM users
+WHERE role = 'admin'
+ORDER BY created_at
+LIMIT 1
+ON CONFLICT (id) DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS idx_users_admin ON users(role) WHERE role = 'admin';
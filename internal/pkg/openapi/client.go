@@ -0,0 +1,393 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// GenerateClient renders a typed Go client for the document
+func GenerateClient(doc *Document, pkgName string) ([]byte, error) {
+	c := &clientGen{doc: doc, names: componentGoNames(doc)}
+
+	c.printf("// Code generated by openapigen. DO NOT EDIT.\n\n")
+	c.printf("package %s\n\n", pkgName)
+	c.printf("import (\n\t\"context\"\n\t\"io\"\n\t\"net/http\"\n\t\"net/url\"\n\t\"time\"\n)\n\n")
+	c.printf("var (\n\t_ = time.Time{}\n\t_ io.Reader\n)\n\n")
+
+	components := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		components = append(components, name)
+	}
+	sort.Strings(components)
+	for _, name := range components {
+		c.writeType(c.names[name], doc.Components.Schemas[name])
+	}
+
+	for _, path := range doc.SortedPaths() {
+		methods := make([]string, 0, len(doc.Paths[path]))
+		for m := range doc.Paths[path] {
+			methods = append(methods, m)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			if err := c.writeOperation(path, method, doc.Paths[path][method]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	src, err := format.Source(c.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated client: %w", err)
+	}
+	return src, nil
+}
+
+type clientGen struct {
+	doc   *Document
+	names map[string]string
+	buf   bytes.Buffer
+}
+
+func (c *clientGen) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&c.buf, format, args...)
+}
+
+// componentGoNames maps component names such as "domain.Track" to Go type
+// names, qualifying with the package only when names collide
+func componentGoNames(doc *Document) map[string]string {
+	count := make(map[string]int)
+	for name := range doc.Components.Schemas {
+		count[shortName(name)]++
+	}
+	names := make(map[string]string, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		short := shortName(name)
+		if count[short] > 1 {
+			pkg, _, _ := strings.Cut(name, ".")
+			short = exportName(pkg) + short
+		}
+		names[name] = short
+	}
+	return names
+}
+
+func shortName(component string) string {
+	if i := strings.LastIndex(component, "."); i >= 0 {
+		return component[i+1:]
+	}
+	return component
+}
+
+func (c *clientGen) writeType(name string, schema *Schema) {
+	if schema.Description != "" {
+		for _, line := range strings.Split(schema.Description, "\n") {
+			c.printf("// %s\n", line)
+		}
+	} else {
+		c.printf("// %s is a schema from the API document\n", name)
+	}
+
+	if schema.Type != "object" || schema.Properties == nil {
+		c.printf("type %s %s\n\n", name, c.goType(schema))
+		if len(schema.Enum) > 0 {
+			c.printf("const (\n")
+			for _, v := range schema.Enum {
+				c.printf("\t%s%s %s = %q\n", name, exportName(v), name, v)
+			}
+			c.printf(")\n\n")
+		}
+		return
+	}
+
+	c.printf("type %s struct {\n", name)
+	props := make([]string, 0, len(schema.Properties))
+	for p := range schema.Properties {
+		props = append(props, p)
+	}
+	sort.Strings(props)
+	for _, p := range props {
+		typ := c.goType(schema.Properties[p])
+		tag := p
+		if !contains(schema.Required, p) {
+			tag += ",omitempty"
+		}
+		c.printf("\t%s %s `json:%q`\n", exportName(p), typ, tag)
+	}
+	c.printf("}\n\n")
+}
+
+func (c *clientGen) goType(s *Schema) string {
+	if s == nil {
+		return "interface{}"
+	}
+	if s.Ref != "" {
+		if c.doc.Resolve(s) != nil && c.doc.Resolve(s).Type == "object" {
+			return "*" + c.names[s.RefName()]
+		}
+		return c.names[s.RefName()]
+	}
+	switch s.Type {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return "time.Time"
+		case "byte", "binary":
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		if s.Format == "int64" {
+			return "int64"
+		}
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + c.goType(s.Items)
+	case "object":
+		if s.AdditionalProperties != nil {
+			return "map[string]" + c.goType(s.AdditionalProperties)
+		}
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+var pathParamRe = regexp.MustCompile(`\{(\w+)\}`)
+
+func (c *clientGen) writeOperation(path, method string, op *Operation) error {
+	name := exportName(op.OperationID)
+
+	var query, header []Parameter
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "query":
+			query = append(query, p)
+		case "header":
+			header = append(header, p)
+		}
+	}
+	optional := append(append([]Parameter{}, query...), header...)
+	if len(optional) > 0 {
+		c.printf("// %sParams holds the optional parameters of %s\n", name, name)
+		c.printf("type %sParams struct {\n", name)
+		for _, p := range optional {
+			c.printf("\t%s *%s\n", exportName(p.Name), c.goType(p.Schema))
+		}
+		c.printf("}\n\n")
+	}
+
+	args := []string{"ctx context.Context"}
+	pathExpr := fmt.Sprintf("%q", path)
+	for _, m := range pathParamRe.FindAllStringSubmatch(path, -1) {
+		arg := unexportName(m[1])
+		args = append(args, arg+" string")
+		pathExpr = strings.Replace(pathExpr, "{"+m[1]+"}", `" + url.PathEscape(`+arg+`) + "`, 1)
+	}
+	pathExpr = strings.TrimSuffix(strings.ReplaceAll(pathExpr, ` + ""`, ""), ` + ""`)
+
+	bodyExpr, contentType := "nil", `""`
+	if op.RequestBody != nil {
+		if media, ok := op.RequestBody.Content["application/json"]; ok {
+			args = append(args, "body "+c.goType(media.Schema))
+			bodyExpr, contentType = "body", `"application/json"`
+		} else {
+			args = append(args, "body io.Reader", "contentType string")
+			bodyExpr, contentType = "body", "contentType"
+		}
+	}
+	if len(optional) > 0 {
+		args = append(args, "params *"+name+"Params")
+	}
+
+	result, resultMedia := c.successResult(op)
+
+	c.printf("// %s calls %s %s\n", name, strings.ToUpper(method), path)
+	if op.Summary != "" {
+		c.printf("//\n// %s\n", op.Summary)
+	}
+	c.printf("func (c *Client) %s(%s) ", name, strings.Join(args, ", "))
+	if result != "" {
+		c.printf("(%s, error) {\n", result)
+	} else {
+		c.printf("error {\n")
+	}
+
+	c.printf("\tq := url.Values{}\n\th := http.Header{}\n")
+	if len(optional) > 0 {
+		c.printf("\tif params != nil {\n")
+		for _, p := range query {
+			c.printf("\t\tsetParam(q, %q, params.%s)\n", p.Name, exportName(p.Name))
+		}
+		for _, p := range header {
+			c.printf("\t\tsetHeader(h, %q, params.%s)\n", p.Name, exportName(p.Name))
+		}
+		c.printf("\t}\n")
+	}
+
+	req := fmt.Sprintf("request{method: %q, path: %s, query: q, header: h, body: %s, contentType: %s}",
+		strings.ToUpper(method), pathExpr, bodyExpr, contentType)
+	switch {
+	case result == "":
+		c.printf("\treturn c.do(ctx, %s, nil)\n}\n\n", req)
+	case resultMedia != "application/json":
+		c.printf("\tvar out []byte\n\terr := c.do(ctx, %s, &out)\n\treturn out, err\n}\n\n", req)
+	default:
+		c.printf("\tvar out %s\n\tif err := c.do(ctx, %s, &out); err != nil {\n\t\treturn out, err\n\t}\n\treturn out, nil\n}\n\n", result, req)
+	}
+
+	if items, schema := pageItems(c.doc, op); items != "" && op.RequestBody == nil {
+		c.writePager(name, args[1:], items, schema)
+	}
+	return nil
+}
+
+// writePager renders a method walking the pages of the paginated operation
+// name, whose response lists its items in the property items
+func (c *clientGen) writePager(name string, args []string, items string, schema *Schema) {
+	var callArgs []string
+	for _, arg := range args[:len(args)-1] {
+		callArgs = append(callArgs, strings.Fields(arg)[0])
+	}
+	callArgs = append([]string{"ctx"}, append(callArgs, "&p")...)
+	item := c.goType(schema.Items)
+
+	c.printf("// %sPager walks the pages of %s from params.Page on\n", name, name)
+	c.printf("func (c *Client) %sPager(%s) *Pager[%s] {\n", name, strings.Join(args, ", "), item)
+	c.printf("\tvar p %sParams\n\tif params != nil {\n\t\tp = *params\n\t}\n", name)
+	c.printf("\treturn newPager(p.Page, func(ctx context.Context, page int) ([]%s, int, error) {\n", item)
+	c.printf("\t\tp.Page = &page\n")
+	c.printf("\t\tout, err := c.%s(%s)\n", name, strings.Join(callArgs, ", "))
+	c.printf("\t\tif err != nil || out == nil {\n\t\t\treturn nil, 0, err\n\t\t}\n")
+	c.printf("\t\treturn out.%s, out.Limit, nil\n\t})\n}\n\n", exportName(items))
+}
+
+// pageItems returns the property and schema of the items a paginated
+// operation returns. Operations are paginated when they take page and limit
+// query parameters and answer with a single list beside the page and limit.
+func pageItems(doc *Document, op *Operation) (string, *Schema) {
+	var page, limit bool
+	for _, p := range op.Parameters {
+		page = page || (p.In == "query" && p.Name == "page")
+		limit = limit || (p.In == "query" && p.Name == "limit")
+	}
+	if !page || !limit {
+		return "", nil
+	}
+
+	resp, ok := op.Responses["200"]
+	if !ok {
+		return "", nil
+	}
+	media, ok := resp.Content["application/json"]
+	if !ok {
+		return "", nil
+	}
+	schema := doc.Resolve(media.Schema)
+	if schema == nil || schema.Properties["page"] == nil || schema.Properties["limit"] == nil {
+		return "", nil
+	}
+	var items string
+	for name, prop := range schema.Properties {
+		if prop.Type != "array" {
+			continue
+		}
+		if items != "" {
+			return "", nil
+		}
+		items = name
+	}
+	if items == "" {
+		return "", nil
+	}
+	return items, schema.Properties[items]
+}
+
+// successResult returns the Go type of the first 2xx response
+func (c *clientGen) successResult(op *Operation) (string, string) {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		for mime, media := range op.Responses[code].Content {
+			if mime != "application/json" {
+				return "[]byte", mime
+			}
+			return c.goType(media.Schema), mime
+		}
+		return "", ""
+	}
+	return "", ""
+}
+
+var initialisms = map[string]string{
+	"id": "ID", "ids": "IDs", "url": "URL", "api": "API", "ai": "AI", "isrc": "ISRC",
+	"iswc": "ISWC", "bpm": "BPM", "ddex": "DDEX", "ern": "ERN", "http": "HTTP", "json": "JSON",
+}
+
+// exportName converts a JSON or operation name to an exported Go identifier
+func exportName(s string) string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == '.' || r == ' ' || r == '/':
+			flush()
+		case r >= 'A' && r <= 'Z' && i > 0 && runes[i-1] >= 'a' && runes[i-1] <= 'z':
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+
+	var b strings.Builder
+	for _, w := range words {
+		if v, ok := initialisms[strings.ToLower(w)]; ok {
+			b.WriteString(v)
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	name := b.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "V" + name
+	}
+	return name
+}
+
+// unexportName converts a name to an unexported Go identifier, keeping a
+// leading initialism in one case
+func unexportName(s string) string {
+	name := exportName(s)
+	prefix := ""
+	for _, v := range initialisms {
+		if strings.HasPrefix(name, v) && len(v) > len(prefix) {
+			prefix = v
+		}
+	}
+	if prefix == "" {
+		return lowerFirst(name)
+	}
+	return strings.ToLower(prefix) + name[len(prefix):]
+}
@@ -0,0 +1,509 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GeneratorConfig controls which packages the generator reads
+type GeneratorConfig struct {
+	// Root is the repository root that Dirs are relative to
+	Root string
+	// HandlerDirs contain handlers annotated with swagger comments
+	HandlerDirs []string
+	// ModelDirs contain types referenced by the annotations
+	ModelDirs []string
+	// BasePath is the path prefix the handlers are mounted under
+	BasePath string
+	Title    string
+	Version  string
+}
+
+// DefaultGeneratorConfig returns the configuration for this repository
+func DefaultGeneratorConfig(root string) GeneratorConfig {
+	return GeneratorConfig{
+		Root:        root,
+		HandlerDirs: []string{"internal/handler"},
+		ModelDirs:   []string{"internal/pkg/domain"},
+		BasePath:    "/api/v1",
+		Title:       "Metadata Tool API",
+		Version:     "1.0",
+	}
+}
+
+// Generate builds an OpenAPI document from handler annotations
+func Generate(cfg GeneratorConfig) (*Document, error) {
+	g := &generator{
+		types:   make(map[string]*ast.TypeSpec),
+		enums:   make(map[string][]string),
+		visited: make(map[string]bool),
+		doc: &Document{
+			OpenAPI:    "3.0.3",
+			Info:       Info{Title: cfg.Title, Version: cfg.Version},
+			Servers:    []Server{{URL: cfg.BasePath}},
+			Paths:      make(map[string]PathItem),
+			Components: Components{Schemas: make(map[string]*Schema)},
+		},
+	}
+
+	var handlers []*ast.File
+	var handlerPkg string
+	for _, dir := range append(append([]string{}, cfg.HandlerDirs...), cfg.ModelDirs...) {
+		pkgName, files, err := parsePackage(filepath.Join(cfg.Root, dir))
+		if err != nil {
+			return nil, err
+		}
+		g.collectTypes(pkgName, files)
+		if contains(cfg.HandlerDirs, dir) {
+			handlers = append(handlers, files...)
+			handlerPkg = pkgName
+		}
+	}
+
+	operationIDs := make(map[string]bool)
+	for _, file := range handlers {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Doc == nil {
+				continue
+			}
+			if err := g.addOperation(handlerPkg, fn, operationIDs); err != nil {
+				return nil, fmt.Errorf("%s: %w", fn.Name.Name, err)
+			}
+		}
+	}
+
+	tags := make(map[string]bool)
+	for _, item := range g.doc.Paths {
+		for _, op := range item {
+			for _, tag := range op.Tags {
+				tags[tag] = true
+			}
+		}
+	}
+	for _, tag := range sortedKeys(tags) {
+		g.doc.Tags = append(g.doc.Tags, Tag{Name: tag})
+	}
+
+	return g.doc, nil
+}
+
+type generator struct {
+	doc     *Document
+	types   map[string]*ast.TypeSpec // keyed by "pkg.Type"
+	enums   map[string][]string      // string constants per "pkg.Type"
+	visited map[string]bool
+}
+
+func parsePackage(dir string) (string, []*ast.File, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse %s: %w", dir, err)
+	}
+
+	for name, pkg := range pkgs {
+		names := make([]string, 0, len(pkg.Files))
+		for fileName := range pkg.Files {
+			names = append(names, fileName)
+		}
+		sort.Strings(names)
+		files := make([]*ast.File, 0, len(names))
+		for _, fileName := range names {
+			files = append(files, pkg.Files[fileName])
+		}
+		return name, files, nil
+	}
+	return "", nil, fmt.Errorf("no Go package in %s", dir)
+}
+
+func (g *generator) collectTypes(pkg string, files []*ast.File) {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					g.types[pkg+"."+s.Name.Name] = s
+				case *ast.ValueSpec:
+					if gen.Tok != token.CONST {
+						continue
+					}
+					ident, ok := s.Type.(*ast.Ident)
+					if !ok {
+						continue
+					}
+					for _, value := range s.Values {
+						lit, ok := value.(*ast.BasicLit)
+						if !ok || lit.Kind != token.STRING {
+							continue
+						}
+						if v, err := strconv.Unquote(lit.Value); err == nil {
+							key := pkg + "." + ident.Name
+							g.enums[key] = append(g.enums[key], v)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+var (
+	routerRe = regexp.MustCompile(`^(\S+)\s+\[(\w+)\]`)
+	paramRe  = regexp.MustCompile(`^(\S+)\s+(\w+)\s+(\S+)\s+(true|false)(?:\s+"([^"]*)")?`)
+	resultRe = regexp.MustCompile(`^(\d+)(?:\s+\{(\w+)\}\s+(\S+))?(?:\s+"([^"]*)")?`)
+)
+
+func (g *generator) addOperation(pkg string, fn *ast.FuncDecl, ids map[string]bool) error {
+	op := &Operation{Responses: make(map[string]*Response)}
+	var path, method string
+	consumes := "application/json"
+	produces := "application/json"
+	var formFields []Parameter
+
+	for _, c := range fn.Doc.List {
+		line := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+		if !strings.HasPrefix(line, "@") {
+			continue
+		}
+		key, value, _ := strings.Cut(line, " ")
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "@Summary":
+			op.Summary = value
+		case "@Description":
+			op.Description = value
+		case "@Tags":
+			for _, tag := range strings.Split(value, ",") {
+				op.Tags = append(op.Tags, strings.TrimSpace(tag))
+			}
+		case "@Accept":
+			consumes = mimeType(value)
+		case "@Produce":
+			produces = mimeType(value)
+		case "@Router":
+			m := routerRe.FindStringSubmatch(value)
+			if m == nil {
+				return fmt.Errorf("invalid @Router %q", value)
+			}
+			path, method = m[1], strings.ToLower(m[2])
+		case "@Param":
+			m := paramRe.FindStringSubmatch(value)
+			if m == nil {
+				return fmt.Errorf("invalid @Param %q", value)
+			}
+			name, in, typ, required, desc := m[1], m[2], m[3], m[4] == "true", m[5]
+			switch in {
+			case "body":
+				op.RequestBody = &RequestBody{
+					Description: desc,
+					Required:    required,
+					Content:     map[string]MediaType{"application/json": {Schema: g.schemaForTypeName(pkg, typ)}},
+				}
+			case "formData":
+				schema := &Schema{Type: typ}
+				if typ == "file" {
+					schema = &Schema{Type: "string", Format: "binary"}
+				}
+				formFields = append(formFields, Parameter{Name: name, Required: required, Description: desc, Schema: schema})
+			default:
+				op.Parameters = append(op.Parameters, Parameter{
+					Name:        name,
+					In:          in,
+					Description: desc,
+					Required:    required || in == "path",
+					Schema:      g.schemaForTypeName(pkg, typ),
+				})
+			}
+		case "@Success", "@Failure":
+			m := resultRe.FindStringSubmatch(value)
+			if m == nil {
+				return fmt.Errorf("invalid %s %q", key, value)
+			}
+			code, kind, typ, desc := m[1], m[2], m[3], m[4]
+			if desc == "" {
+				desc = httpStatusText(code)
+			}
+			if existing, ok := op.Responses[code]; ok {
+				// Multiple results for one status share a schema
+				existing.Description += "; " + desc
+				continue
+			}
+			resp := &Response{Description: desc}
+			if typ != "" {
+				schema := g.schemaForTypeName(pkg, typ)
+				if kind == "array" {
+					schema = &Schema{Type: "array", Items: schema}
+				}
+				mime := produces
+				if key == "@Failure" {
+					mime = "application/json"
+				}
+				resp.Content = map[string]MediaType{mime: {Schema: schema}}
+			}
+			op.Responses[code] = resp
+		}
+	}
+
+	if path == "" {
+		return nil
+	}
+	if len(formFields) > 0 {
+		schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		for _, f := range formFields {
+			schema.Properties[f.Name] = f.Schema
+			if f.Required {
+				schema.Required = append(schema.Required, f.Name)
+			}
+		}
+		if consumes == "application/json" {
+			consumes = "multipart/form-data"
+		}
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{consumes: {Schema: schema}}}
+	}
+	if len(op.Responses) == 0 {
+		op.Responses["200"] = &Response{Description: "OK"}
+	}
+
+	op.OperationID = lowerFirst(fn.Name.Name)
+	if ids[op.OperationID] && fn.Recv != nil && len(fn.Recv.List) > 0 {
+		op.OperationID = lowerFirst(strings.TrimSuffix(receiverName(fn.Recv.List[0].Type), "Handler")) + fn.Name.Name
+	}
+	ids[op.OperationID] = true
+
+	if g.doc.Paths[path] == nil {
+		g.doc.Paths[path] = make(PathItem)
+	}
+	g.doc.Paths[path][method] = op
+	return nil
+}
+
+// schemaForTypeName converts an annotation type such as "domain.Track",
+// "string" or "map[string]string" to a schema
+func (g *generator) schemaForTypeName(pkg, name string) *Schema {
+	switch {
+	case strings.HasPrefix(name, "[]"):
+		return &Schema{Type: "array", Items: g.schemaForTypeName(pkg, name[2:])}
+	case strings.HasPrefix(name, "map[string]"):
+		return &Schema{Type: "object", AdditionalProperties: g.schemaForTypeName(pkg, strings.TrimPrefix(name, "map[string]"))}
+	}
+	if s := basicSchema(name); s != nil {
+		return s
+	}
+	if !strings.Contains(name, ".") {
+		name = pkg + "." + name
+	}
+	return g.ref(name)
+}
+
+// ref returns a $ref to the component for a named type, building it first
+func (g *generator) ref(name string) *Schema {
+	spec, ok := g.types[name]
+	if !ok {
+		return &Schema{Type: "object"}
+	}
+	if !g.visited[name] {
+		g.visited[name] = true
+		pkg := strings.SplitN(name, ".", 2)[0]
+		schema := g.schemaForExpr(pkg, spec.Type)
+		if enum := g.enums[name]; len(enum) > 0 && schema.Type == "string" {
+			schema.Enum = enum
+		}
+		if spec.Doc != nil {
+			schema.Description = strings.TrimSpace(spec.Doc.Text())
+		}
+		g.doc.Components.Schemas[name] = schema
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (g *generator) schemaForExpr(pkg string, expr ast.Expr) *Schema {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if s := basicSchema(t.Name); s != nil {
+			return s
+		}
+		return g.ref(pkg + "." + t.Name)
+	case *ast.SelectorExpr:
+		x, _ := t.X.(*ast.Ident)
+		if x == nil {
+			return &Schema{}
+		}
+		switch x.Name + "." + t.Sel.Name {
+		case "time.Time":
+			return &Schema{Type: "string", Format: "date-time"}
+		case "time.Duration":
+			return &Schema{Type: "integer", Format: "int64"}
+		}
+		return g.ref(x.Name + "." + t.Sel.Name)
+	case *ast.StarExpr:
+		return g.schemaForExpr(pkg, t.X)
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaForExpr(pkg, t.Elt)}
+	case *ast.MapType:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaForExpr(pkg, t.Value)}
+	case *ast.StructType:
+		return g.structSchema(pkg, t)
+	default:
+		// interface{} and anything else accepts any JSON value
+		return &Schema{}
+	}
+}
+
+func (g *generator) structSchema(pkg string, st *ast.StructType) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, field := range st.Fields.List {
+		tag := reflect.StructTag("")
+		if field.Tag != nil {
+			if v, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(v)
+			}
+		}
+		jsonName, _, _ := strings.Cut(tag.Get("json"), ",")
+		if jsonName == "-" {
+			continue
+		}
+
+		// Embedded structs without a JSON name are flattened
+		if len(field.Names) == 0 && jsonName == "" {
+			embedded := g.doc.Resolve(g.schemaForExpr(pkg, field.Type))
+			if embedded != nil {
+				for name, prop := range embedded.Properties {
+					schema.Properties[name] = prop
+				}
+				schema.Required = append(schema.Required, embedded.Required...)
+			}
+			continue
+		}
+
+		names := field.Names
+		if len(names) == 0 {
+			// Embedded structs with a JSON name behave like named fields
+			names = []*ast.Ident{ast.NewIdent(jsonName)}
+		}
+		for _, name := range names {
+			if len(field.Names) > 0 && !name.IsExported() {
+				continue
+			}
+			propName := jsonName
+			if propName == "" {
+				propName = name.Name
+			}
+			prop := g.schemaForExpr(pkg, field.Type)
+			if _, ok := field.Type.(*ast.StarExpr); ok && prop.Ref == "" {
+				prop.Nullable = true
+			}
+			schema.Properties[propName] = prop
+			if strings.Contains(tag.Get("binding"), "required") {
+				schema.Required = append(schema.Required, propName)
+			}
+		}
+	}
+	sort.Strings(schema.Required)
+	return schema
+}
+
+func basicSchema(name string) *Schema {
+	switch name {
+	case "string":
+		return &Schema{Type: "string"}
+	case "bool", "boolean":
+		return &Schema{Type: "boolean"}
+	case "int", "int32", "uint", "uint32", "integer":
+		return &Schema{Type: "integer", Format: "int32"}
+	case "int64", "uint64":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "float32", "float64", "number":
+		return &Schema{Type: "number"}
+	case "file":
+		return &Schema{Type: "string", Format: "binary"}
+	case "object", "interface{}", "any":
+		return &Schema{Type: "object"}
+	}
+	return nil
+}
+
+func mimeType(v string) string {
+	switch v {
+	case "json":
+		return "application/json"
+	case "xml":
+		return "application/xml"
+	case "multipart/form-data", "mpfd":
+		return "multipart/form-data"
+	case "plain":
+		return "text/plain"
+	}
+	return v
+}
+
+func httpStatusText(code string) string {
+	switch code {
+	case "200":
+		return "OK"
+	case "201":
+		return "Created"
+	case "202":
+		return "Accepted"
+	case "204":
+		return "No Content"
+	}
+	return "Error"
+}
+
+func receiverName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,106 @@
+package openapi
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedSpecIsUpToDate(t *testing.T) {
+	doc, err := Generate(DefaultGeneratorConfig("../../.."))
+	require.NoError(t, err)
+
+	generated, err := json.MarshalIndent(doc, "", "  ")
+	require.NoError(t, err)
+	assert.JSONEq(t, string(generated), string(Spec()), "run go generate ./internal/pkg/openapi")
+}
+
+func TestGeneratedClientsAreUpToDate(t *testing.T) {
+	doc, err := Load()
+	require.NoError(t, err)
+
+	goClient, err := GenerateClient(doc, "client")
+	require.NoError(t, err)
+	current, err := os.ReadFile("../../../pkg/client/client_gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(current), string(goClient), "run go generate ./internal/pkg/openapi")
+
+	tsClient, err := GenerateTypeScriptClient(doc)
+	require.NoError(t, err)
+	current, err = os.ReadFile("../../../sdk/typescript/src/client.gen.ts")
+	require.NoError(t, err)
+	assert.Equal(t, string(current), string(tsClient), "run go generate ./internal/pkg/openapi")
+}
+
+func TestGenerateTypeScriptClient(t *testing.T) {
+	doc, err := Load()
+	require.NoError(t, err)
+	src, err := GenerateTypeScriptClient(doc)
+	require.NoError(t, err)
+	ts := string(src)
+
+	assert.Contains(t, ts, "export interface CompleteTrackMetadata {\n  additional?: AdditionalMetadata;")
+	assert.Contains(t, ts, "export type TrackStatus = 'draft'")
+	assert.Contains(t, ts, "  updateTrack(id: string, body: Track, params?: UpdateTrackParams): Promise<Track> {")
+	assert.Contains(t, ts, "        'If-Match': params?.ifMatch,")
+	assert.Contains(t, ts, "path: `/tracks/${encodeURIComponent(id)}`,")
+	assert.Contains(t, ts, "  listTracksPager(params?: ListTracksParams): AsyncGenerator<Track> {")
+	assert.Contains(t, ts, "  deleteTrack(id: string): Promise<void> {")
+}
+
+func TestGenerate_TrackOperations(t *testing.T) {
+	doc, err := Load()
+	require.NoError(t, err)
+
+	op, ok := doc.Operation("PUT", "/tracks/{id}")
+	require.True(t, ok)
+	assert.Equal(t, "updateTrack", op.OperationID)
+	assert.Equal(t, "#/components/schemas/domain.Track", op.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Contains(t, op.Responses, "409")
+
+	var params []string
+	for _, p := range op.Parameters {
+		params = append(params, p.In+":"+p.Name)
+	}
+	assert.ElementsMatch(t, []string{"path:id", "header:If-Match"}, params)
+
+	status := doc.Components.Schemas["domain.TrackStatus"]
+	require.NotNil(t, status)
+	assert.Contains(t, status.Enum, "active")
+}
+
+func TestValidate(t *testing.T) {
+	doc, err := Load()
+	require.NoError(t, err)
+	op, ok := doc.Operation("POST", "/tracks/bulk-edit")
+	require.True(t, ok)
+	schema := op.RequestBody.Content["application/json"].Schema
+
+	tests := []struct {
+		name   string
+		body   string
+		errors []string
+	}{
+		{"valid", `{"track_ids":["a"],"patch":{"set_genre":"Jazz"},"dry_run":true}`, nil},
+		{"wrong array item type", `{"track_ids":[1]}`, []string{"$.track_ids[0]"}},
+		{"wrong nested type", `{"patch":{"append_tags":"rock"}}`, []string{"$.patch.append_tags"}},
+		{"wrong boolean", `{"dry_run":"yes"}`, []string{"$.dry_run"}},
+		{"not an object", `[]`, []string{"$"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			require.NoError(t, json.Unmarshal([]byte(tt.body), &value))
+
+			var paths []string
+			for _, e := range doc.Validate(schema, value) {
+				paths = append(paths, e.Path)
+			}
+			assert.Equal(t, tt.errors, paths)
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// GenerateTypeScriptClient renders a typed TypeScript client for the
+// document. The client extends BaseClient from the hand-written runtime.ts
+// next to it.
+func GenerateTypeScriptClient(doc *Document) ([]byte, error) {
+	c := &tsGen{doc: doc, names: componentGoNames(doc)}
+
+	c.printf("// Code generated by openapigen. DO NOT EDIT.\n\n")
+	c.printf("import { BaseClient, paginate } from './runtime.js';\n")
+
+	components := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		components = append(components, name)
+	}
+	sort.Strings(components)
+	for _, name := range components {
+		c.writeType(c.names[name], doc.Components.Schemas[name])
+	}
+
+	var methods bytes.Buffer
+	for _, path := range doc.SortedPaths() {
+		verbs := make([]string, 0, len(doc.Paths[path]))
+		for m := range doc.Paths[path] {
+			verbs = append(verbs, m)
+		}
+		sort.Strings(verbs)
+		for _, method := range verbs {
+			c.writeOperation(&methods, path, method, doc.Paths[path][method])
+		}
+	}
+
+	c.printf("\n/** Client calls the API operations over HTTP */\n")
+	c.printf("export class Client extends BaseClient {\n")
+	c.buf.Write(bytes.TrimSuffix(methods.Bytes(), []byte("\n")))
+	c.printf("}\n")
+	return c.buf.Bytes(), nil
+}
+
+type tsGen struct {
+	doc   *Document
+	names map[string]string
+	buf   bytes.Buffer
+}
+
+func (c *tsGen) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&c.buf, format, args...)
+}
+
+func (c *tsGen) writeType(name string, schema *Schema) {
+	c.printf("\n")
+	if schema.Description != "" {
+		writeTSDoc(&c.buf, "", strings.Split(schema.Description, "\n"))
+	} else {
+		c.printf("/** %s is a schema from the API document */\n", name)
+	}
+
+	if schema.Type != "object" || schema.Properties == nil {
+		if len(schema.Enum) > 0 {
+			values := make([]string, len(schema.Enum))
+			for i, v := range schema.Enum {
+				values[i] = tsString(v)
+			}
+			c.printf("export type %s = %s;\n", name, strings.Join(values, " | "))
+			return
+		}
+		c.printf("export type %s = %s;\n", name, c.tsType(schema))
+		return
+	}
+
+	c.printf("export interface %s {\n", name)
+	props := make([]string, 0, len(schema.Properties))
+	for p := range schema.Properties {
+		props = append(props, p)
+	}
+	sort.Strings(props)
+	for _, p := range props {
+		optional := "?"
+		if contains(schema.Required, p) {
+			optional = ""
+		}
+		c.printf("  %s%s: %s;\n", tsKey(p), optional, c.tsType(schema.Properties[p]))
+	}
+	c.printf("}\n")
+}
+
+func (c *tsGen) tsType(s *Schema) string {
+	if s == nil {
+		return "unknown"
+	}
+	typ := c.baseType(s)
+	if s.Nullable {
+		typ += " | null"
+	}
+	return typ
+}
+
+func (c *tsGen) baseType(s *Schema) string {
+	if s.Ref != "" {
+		return c.names[s.RefName()]
+	}
+	switch s.Type {
+	case "string":
+		if s.Format == "binary" {
+			return "Blob"
+		}
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		item := c.tsType(s.Items)
+		if strings.Contains(item, " ") {
+			return "Array<" + item + ">"
+		}
+		return item + "[]"
+	case "object":
+		if s.AdditionalProperties != nil {
+			return "Record<string, " + c.tsType(s.AdditionalProperties) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+	return "unknown"
+}
+
+func (c *tsGen) writeOperation(w *bytes.Buffer, path, method string, op *Operation) {
+	name := unexportName(op.OperationID)
+	typeName := exportName(op.OperationID)
+
+	var query, header []Parameter
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "query":
+			query = append(query, p)
+		case "header":
+			header = append(header, p)
+		}
+	}
+	optional := append(append([]Parameter{}, query...), header...)
+	if len(optional) > 0 {
+		c.printf("\n/** %sParams holds the optional parameters of %s */\n", typeName, name)
+		c.printf("export interface %sParams {\n", typeName)
+		for _, p := range optional {
+			if p.Description != "" {
+				writeTSDoc(&c.buf, "  ", []string{p.Description})
+			}
+			c.printf("  %s?: %s;\n", unexportName(p.Name), c.tsType(p.Schema))
+		}
+		c.printf("}\n")
+	}
+
+	var args []string
+	pathExpr := path
+	for _, m := range pathParamRe.FindAllStringSubmatch(path, -1) {
+		arg := unexportName(m[1])
+		args = append(args, arg+": string")
+		pathExpr = strings.Replace(pathExpr, "{"+m[1]+"}", "${encodeURIComponent("+arg+")}", 1)
+	}
+	if pathExpr != path {
+		pathExpr = "`" + pathExpr + "`"
+	} else {
+		pathExpr = tsString(pathExpr)
+	}
+
+	body, contentType := "", ""
+	if op.RequestBody != nil {
+		if media, ok := op.RequestBody.Content["application/json"]; ok {
+			args = append(args, "body: "+c.tsType(media.Schema))
+			body, contentType = "body", tsString("application/json")
+		} else {
+			args = append(args, "body: BodyInit", "contentType?: string")
+			body, contentType = "body", "contentType"
+		}
+	}
+	if len(optional) > 0 {
+		args = append(args, "params?: "+typeName+"Params")
+	}
+
+	result, response := c.successResult(op)
+
+	fmt.Fprintf(w, "  /**\n   * %s calls %s %s\n", name, strings.ToUpper(method), path)
+	if op.Summary != "" {
+		fmt.Fprintf(w, "   *\n   * %s\n", op.Summary)
+	}
+	fmt.Fprintf(w, "   */\n")
+	fmt.Fprintf(w, "  %s(%s): Promise<%s> {\n", name, strings.Join(args, ", "), result)
+	fmt.Fprintf(w, "    return this.request<%s>({\n", result)
+	fmt.Fprintf(w, "      method: %s,\n      path: %s,\n", tsString(strings.ToUpper(method)), pathExpr)
+	if len(query) > 0 {
+		fmt.Fprintf(w, "      query: {\n")
+		for _, p := range query {
+			fmt.Fprintf(w, "        %s: params?.%s,\n", tsKey(p.Name), unexportName(p.Name))
+		}
+		fmt.Fprintf(w, "      },\n")
+	}
+	if len(header) > 0 {
+		fmt.Fprintf(w, "      headers: {\n")
+		for _, p := range header {
+			fmt.Fprintf(w, "        %s: params?.%s,\n", tsKey(p.Name), unexportName(p.Name))
+		}
+		fmt.Fprintf(w, "      },\n")
+	}
+	if body != "" {
+		fmt.Fprintf(w, "      body: %s,\n      contentType: %s,\n", body, contentType)
+	}
+	fmt.Fprintf(w, "      response: %s,\n    });\n  }\n\n", tsString(response))
+
+	if items, schema := pageItems(c.doc, op); items != "" && op.RequestBody == nil {
+		callArgs := make([]string, 0, len(args))
+		for _, arg := range args[:len(args)-1] {
+			callArgs = append(callArgs, strings.TrimSuffix(strings.Fields(arg)[0], ":"))
+		}
+		callArgs = append(callArgs, "{ ...params, page }")
+		fmt.Fprintf(w, "  /** %sPager iterates over the items of every page of %s from params.page on */\n", name, name)
+		fmt.Fprintf(w, "  %sPager(%s): AsyncGenerator<%s> {\n", name, strings.Join(args, ", "), c.tsType(schema.Items))
+		fmt.Fprintf(w, "    return paginate(params?.page, async (page) => {\n")
+		fmt.Fprintf(w, "      const out = await this.%s(%s);\n", name, strings.Join(callArgs, ", "))
+		fmt.Fprintf(w, "      return [out.%s ?? [], out.limit ?? 0];\n    });\n  }\n\n", tsKey(items))
+	}
+}
+
+// successResult returns the TypeScript type of the first 2xx response and
+// how the runtime reads it: as JSON, as a Blob or not at all
+func (c *tsGen) successResult(op *Operation) (string, string) {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		for mime, media := range op.Responses[code].Content {
+			if mime != "application/json" {
+				return "Blob", "blob"
+			}
+			return c.tsType(media.Schema), "json"
+		}
+		return "void", "none"
+	}
+	return "void", "none"
+}
+
+var tsIdentRe = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsKey quotes property names that are not identifiers
+func tsKey(name string) string {
+	if tsIdentRe.MatchString(name) {
+		return name
+	}
+	return tsString(name)
+}
+
+func tsString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func writeTSDoc(buf *bytes.Buffer, indent string, lines []string) {
+	if len(lines) == 1 {
+		fmt.Fprintf(buf, "%s/** %s */\n", indent, strings.ReplaceAll(lines[0], "*/", "* /"))
+		return
+	}
+	fmt.Fprintf(buf, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(buf, "%s * %s\n", indent, strings.ReplaceAll(line, "*/", "* /"))
+	}
+	fmt.Fprintf(buf, "%s */\n", indent)
+}
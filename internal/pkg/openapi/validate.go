@@ -0,0 +1,123 @@
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationError describes one way a value does not match its schema. Code
+// is "required", "invalid_type" or "oneof", matching the field error codes
+// of the API.
+type ValidationError struct {
+	Path    string `json:"path"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Validate checks a decoded JSON value against a schema and returns every
+// mismatch found. Properties not described by the schema are allowed.
+func (d *Document) Validate(schema *Schema, value interface{}) []ValidationError {
+	var errs []ValidationError
+	d.validate("$", schema, value, &errs)
+	return errs
+}
+
+func (d *Document) validate(path string, schema *Schema, value interface{}, errs *[]ValidationError) {
+	schema = d.Resolve(schema)
+	if schema == nil {
+		return
+	}
+
+	if value == nil {
+		if !schema.Nullable && schema.Type != "" {
+			*errs = append(*errs, ValidationError{Path: path, Code: "invalid_type", Message: "must not be null"})
+		}
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			*errs = append(*errs, typeError(path, "object", value))
+			return
+		}
+		for _, name := range schema.Required {
+			if _, ok := obj[name]; !ok {
+				*errs = append(*errs, ValidationError{Path: path + "." + name, Code: "required", Message: "is required"})
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if prop, ok := schema.Properties[k]; ok {
+				d.validate(path+"."+k, prop, obj[k], errs)
+			} else if schema.AdditionalProperties != nil {
+				d.validate(path+"."+k, schema.AdditionalProperties, obj[k], errs)
+			}
+		}
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			*errs = append(*errs, typeError(path, "array", value))
+			return
+		}
+		for i, item := range arr {
+			d.validate(fmt.Sprintf("%s[%d]", path, i), schema.Items, item, errs)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			*errs = append(*errs, typeError(path, "string", value))
+			return
+		}
+		if len(schema.Enum) > 0 && !contains(schema.Enum, s) {
+			*errs = append(*errs, ValidationError{
+				Path:    path,
+				Code:    "oneof",
+				Message: fmt.Sprintf("must be one of %s", strings.Join(schema.Enum, ", ")),
+			})
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != float64(int64(n)) {
+			*errs = append(*errs, typeError(path, "integer", value))
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			*errs = append(*errs, typeError(path, "number", value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			*errs = append(*errs, typeError(path, "boolean", value))
+		}
+	}
+}
+
+func typeError(path, want string, value interface{}) ValidationError {
+	return ValidationError{Path: path, Code: "invalid_type", Message: fmt.Sprintf("must be %s, got %s", want, jsonType(value))}
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
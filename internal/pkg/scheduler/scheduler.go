@@ -0,0 +1,290 @@
+// Package scheduler runs periodic maintenance tasks, such as removing
+// expired sessions, on cron-like schedules. The replicas of the API elect a
+// leader and only the leader runs the tasks, so each run happens once
+// however many replicas there are.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+)
+
+const (
+	// tickInterval is how often due tasks are looked for
+	tickInterval = time.Second
+	// statusRuns is how many runs of each task Status reports
+	statusRuns = 10
+	// resignTimeout bounds giving up the lead at shutdown
+	resignTimeout = 5 * time.Second
+)
+
+// Task is a maintenance task. Its context is cancelled when the replica
+// stops leading or shuts down.
+type Task func(ctx context.Context) error
+
+// entry is a task and when it is next due
+type entry struct {
+	name     string
+	spec     string
+	schedule Schedule
+	task     Task
+	next     time.Time
+	running  bool
+}
+
+// Scheduler runs maintenance tasks while its replica leads
+type Scheduler struct {
+	instance    string
+	coordinator domain.MaintenanceCoordinator
+	history     domain.MaintenanceHistory
+	leaseTTL    time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	entries []*entry
+	leader  bool
+	// leaseUntil is when the lead runs out unless it is renewed, and
+	// renewAt when it is renewed next
+	leaseUntil time.Time
+	renewAt    time.Time
+	leadCtx    context.Context
+	stopLead   context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// New creates a scheduler for the replica named instance. The leader renews
+// its lead every third of leaseTTL; once it stops, another replica takes
+// over within leaseTTL.
+func New(instance string, coordinator domain.MaintenanceCoordinator, history domain.MaintenanceHistory, leaseTTL time.Duration) *Scheduler {
+	return &Scheduler{
+		instance:    instance,
+		coordinator: coordinator,
+		history:     history,
+		leaseTTL:    leaseTTL,
+		now:         time.Now,
+	}
+}
+
+// Add schedules task under name with a spec accepted by Parse
+func (s *Scheduler) Add(name, spec string, task Task) error {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return fmt.Errorf("failed to schedule %s: %w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.entries {
+		if e.name == name {
+			return fmt.Errorf("maintenance task %s is already scheduled", name)
+		}
+	}
+	s.entries = append(s.entries, &entry{name: name, spec: spec, schedule: schedule, task: task, next: schedule.Next(s.now())})
+	return nil
+}
+
+// Run runs the tasks that are due while this replica leads, until ctx is
+// done. It then waits for the running tasks and gives up the lead.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+		select {
+		case <-ctx.Done():
+			s.stop()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// due is a run of a task that is due
+type due struct {
+	entry *entry
+	at    time.Time
+}
+
+// tick renews the lead when it is time to and starts the tasks that are due
+func (s *Scheduler) tick(ctx context.Context) {
+	now := s.now()
+	s.mu.Lock()
+	renew := !now.Before(s.renewAt)
+	s.mu.Unlock()
+	if renew {
+		s.lead(ctx, now)
+	}
+
+	s.mu.Lock()
+	var runs []due
+	for _, e := range s.entries {
+		if e.next.IsZero() || now.Before(e.next) {
+			continue
+		}
+		if !s.leader {
+			// A follower holds on to a due run for the lease TTL, in case
+			// it takes over from a leader that stopped before running it
+			if now.Sub(e.next) > s.leaseTTL {
+				e.next = e.schedule.Next(now)
+			}
+			continue
+		}
+		// Runs missed while no replica led are run once
+		at := e.next
+		e.next = e.schedule.Next(now)
+		if e.running {
+			log.Printf("Skipping maintenance task %s due at %s: the previous run has not finished", e.name, at.Format(time.RFC3339))
+			continue
+		}
+		e.running = true
+		runs = append(runs, due{entry: e, at: at})
+	}
+	leadCtx := s.leadCtx
+	s.mu.Unlock()
+
+	for _, run := range runs {
+		claimed, err := s.coordinator.Claim(ctx, run.entry.name, run.at)
+		if err != nil || !claimed {
+			if err != nil {
+				log.Printf("Failed to claim maintenance task %s: %v", run.entry.name, err)
+			}
+			s.finished(run.entry)
+			continue
+		}
+		s.wg.Add(1)
+		go func(run due) {
+			defer s.wg.Done()
+			defer s.finished(run.entry)
+			s.run(leadCtx, run)
+		}(run)
+	}
+}
+
+// lead takes or renews the lead. A replica that cannot reach the
+// coordinator keeps leading until its lease runs out, as no other replica
+// can take over before.
+func (s *Scheduler) lead(ctx context.Context, now time.Time) {
+	leading, err := s.coordinator.Lead(ctx, s.leaseTTL)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Failed to renew the maintenance lead: %v", err)
+		}
+		leading = s.leader && now.Before(s.leaseUntil)
+	} else if leading {
+		s.leaseUntil = now.Add(s.leaseTTL)
+	}
+	s.renewAt = now.Add(s.leaseTTL / 3)
+
+	switch {
+	case leading && !s.leader:
+		s.leadCtx, s.stopLead = context.WithCancel(ctx)
+		metrics.MaintenanceLeader.Set(1)
+		log.Printf("Replica %s now runs the maintenance tasks", s.instance)
+	case !leading && s.leader:
+		s.stopLead()
+		metrics.MaintenanceLeader.Set(0)
+		log.Printf("Replica %s no longer runs the maintenance tasks", s.instance)
+	}
+	s.leader = leading
+}
+
+// run runs a task and records the run
+func (s *Scheduler) run(ctx context.Context, run due) {
+	started := s.now()
+	err := safeRun(ctx, run.entry)
+	finished := s.now()
+
+	record := &domain.MaintenanceRun{
+		Task:       run.entry.name,
+		Instance:   s.instance,
+		DueAt:      run.at,
+		StartedAt:  started,
+		FinishedAt: finished,
+		DurationMs: finished.Sub(started).Milliseconds(),
+	}
+	status := "completed"
+	if err != nil {
+		status = "failed"
+		record.Error = err.Error()
+		log.Printf("Maintenance task %s failed: %v", run.entry.name, err)
+	}
+	metrics.MaintenanceRuns.WithLabelValues(run.entry.name, status).Inc()
+	metrics.MaintenanceRunDuration.WithLabelValues(run.entry.name).Observe(finished.Sub(started).Seconds())
+
+	if err := s.history.Record(context.WithoutCancel(ctx), record); err != nil {
+		log.Printf("Failed to record run of maintenance task %s: %v", run.entry.name, err)
+	}
+}
+
+// safeRun calls the task, turning a panic into an error
+func safeRun(ctx context.Context, e *entry) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("Panic in maintenance task %s: %v\n%s", e.name, recovered, debug.Stack())
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return e.task(ctx)
+}
+
+func (s *Scheduler) finished(e *entry) {
+	s.mu.Lock()
+	e.running = false
+	s.mu.Unlock()
+}
+
+// stop waits for the running tasks, which are cancelled with the context
+// Run was given, and gives up the lead
+func (s *Scheduler) stop() {
+	s.wg.Wait()
+
+	s.mu.Lock()
+	leader := s.leader
+	if leader {
+		s.stopLead()
+		s.leader = false
+		metrics.MaintenanceLeader.Set(0)
+	}
+	s.mu.Unlock()
+
+	if leader {
+		ctx, cancel := context.WithTimeout(context.Background(), resignTimeout)
+		defer cancel()
+		if err := s.coordinator.Resign(ctx); err != nil {
+			log.Printf("Failed to give up the maintenance lead: %v", err)
+		}
+	}
+}
+
+// Status reports the scheduled tasks with their latest runs
+func (s *Scheduler) Status(ctx context.Context) (*domain.MaintenanceStatus, error) {
+	s.mu.Lock()
+	status := &domain.MaintenanceStatus{Instance: s.instance, Leader: s.leader, Tasks: make([]domain.MaintenanceTask, len(s.entries))}
+	for i, e := range s.entries {
+		status.Tasks[i] = domain.MaintenanceTask{Name: e.name, Schedule: e.spec, NextRun: e.next, Running: e.running}
+	}
+	s.mu.Unlock()
+
+	for i := range status.Tasks {
+		runs, err := s.history.Runs(ctx, status.Tasks[i].Name, statusRuns)
+		if err != nil {
+			return nil, err
+		}
+		if runs == nil {
+			runs = []*domain.MaintenanceRun{}
+		}
+		status.Tasks[i].Runs = runs
+	}
+	return status, nil
+}
@@ -0,0 +1,196 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCoordinator is a coordinator shared by the replicas of a test
+type memoryCoordinator struct {
+	mu      sync.Mutex
+	leader  string
+	claimed map[string]time.Time
+	down    bool
+}
+
+// replica is a replica's view of a memoryCoordinator
+type replica struct {
+	*memoryCoordinator
+	instance string
+}
+
+func (c *replica) Lead(ctx context.Context, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.down {
+		return false, errors.New("redis is down")
+	}
+	if c.leader == "" {
+		c.leader = c.instance
+	}
+	return c.leader == c.instance, nil
+}
+
+func (c *replica) Resign(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.leader == c.instance {
+		c.leader = ""
+	}
+	return nil
+}
+
+func (c *replica) Claim(ctx context.Context, task string, due time.Time) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.claimed == nil {
+		c.claimed = make(map[string]time.Time)
+	}
+	if !due.After(c.claimed[task]) {
+		return false, nil
+	}
+	c.claimed[task] = due
+	return true, nil
+}
+
+type memoryHistory struct {
+	mu   sync.Mutex
+	runs []*domain.MaintenanceRun
+}
+
+func (h *memoryHistory) Record(ctx context.Context, run *domain.MaintenanceRun) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.runs = append([]*domain.MaintenanceRun{run}, h.runs...)
+	return nil
+}
+
+func (h *memoryHistory) Runs(ctx context.Context, task string, limit int) ([]*domain.MaintenanceRun, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var runs []*domain.MaintenanceRun
+	for _, run := range h.runs {
+		if run.Task == task && len(runs) < limit {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+// testClock is a settable time shared by the replicas of a test
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+func newTestScheduler(coordinator *memoryCoordinator, history *memoryHistory, clock *testClock, instance string) *Scheduler {
+	s := New(instance, &replica{memoryCoordinator: coordinator, instance: instance}, history, 30*time.Second)
+	s.now = clock.Now
+	return s
+}
+
+func TestScheduler_RunsEachDueRunOnceAcrossReplicas(t *testing.T) {
+	coordinator, history := &memoryCoordinator{}, &memoryHistory{}
+	clock := &testClock{now: time.Date(2024, 3, 1, 2, 59, 0, 0, time.UTC)}
+	a := newTestScheduler(coordinator, history, clock, "api-a")
+	b := newTestScheduler(coordinator, history, clock, "api-b")
+
+	var mu sync.Mutex
+	ran := map[string]int{}
+	for _, s := range []*Scheduler{a, b} {
+		instance := s.instance
+		require.NoError(t, s.Add("session-cleanup", "0 3 * * *", func(ctx context.Context) error {
+			mu.Lock()
+			ran[instance]++
+			mu.Unlock()
+			return nil
+		}))
+	}
+	assert.Error(t, a.Add("session-cleanup", "@hourly", nil), "task names are unique")
+
+	ctx := context.Background()
+	a.tick(ctx)
+	b.tick(ctx)
+	clock.Set(time.Date(2024, 3, 1, 3, 0, 1, 0, time.UTC))
+	a.tick(ctx)
+	b.tick(ctx)
+	a.wg.Wait()
+
+	assert.Equal(t, map[string]int{"api-a": 1}, ran)
+	status, err := a.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, status.Leader)
+	require.Len(t, status.Tasks, 1)
+	assert.Equal(t, time.Date(2024, 3, 2, 3, 0, 0, 0, time.UTC), status.Tasks[0].NextRun)
+	require.Len(t, status.Tasks[0].Runs, 1)
+	assert.Equal(t, "api-a", status.Tasks[0].Runs[0].Instance)
+	assert.Equal(t, time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC), status.Tasks[0].Runs[0].DueAt)
+}
+
+func TestScheduler_FollowerRunsWhatTheStoppedLeaderMissed(t *testing.T) {
+	coordinator, history := &memoryCoordinator{leader: "api-a"}, &memoryHistory{}
+	clock := &testClock{now: time.Date(2024, 3, 1, 2, 59, 0, 0, time.UTC)}
+	b := newTestScheduler(coordinator, history, clock, "api-b")
+
+	ran := make(chan error, 1)
+	require.NoError(t, b.Add("storage-cleanup", "0 3 * * *", func(ctx context.Context) error {
+		ran <- nil
+		return errors.New("bucket is unreachable")
+	}))
+
+	ctx := context.Background()
+	clock.Set(time.Date(2024, 3, 1, 3, 0, 10, 0, time.UTC))
+	b.tick(ctx)
+	assert.Empty(t, ran, "only the leader runs tasks")
+
+	// The leader stops before running the task and its lease runs out
+	coordinator.leader = ""
+	clock.Set(time.Date(2024, 3, 1, 3, 0, 30, 0, time.UTC))
+	b.tick(ctx)
+	b.wg.Wait()
+	require.Len(t, ran, 1)
+	runs, err := history.Runs(ctx, "storage-cleanup", 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, "bucket is unreachable", runs[0].Error)
+}
+
+func TestScheduler_KeepsLeadingUntilTheLeaseRunsOut(t *testing.T) {
+	coordinator, history := &memoryCoordinator{}, &memoryHistory{}
+	clock := &testClock{now: time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)}
+	a := newTestScheduler(coordinator, history, clock, "api-a")
+
+	ctx := context.Background()
+	a.tick(ctx)
+	require.True(t, a.leader)
+	stopped := a.leadCtx
+
+	coordinator.down = true
+	clock.Set(clock.Now().Add(15 * time.Second))
+	a.tick(ctx)
+	assert.True(t, a.leader)
+	clock.Set(clock.Now().Add(20 * time.Second))
+	a.tick(ctx)
+	assert.False(t, a.leader)
+	assert.Error(t, stopped.Err(), "running tasks are cancelled")
+}
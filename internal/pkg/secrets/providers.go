@@ -0,0 +1,241 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"metadatatool/internal/pkg/config"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+)
+
+// requestTimeout bounds a single call to a secrets manager
+const requestTimeout = 10 * time.Second
+
+// NewManagerFromConfig creates a manager with the gcp, aws and vault
+// providers. Providers connect on first use, so credentials are only needed
+// for the secrets managers that are actually referenced.
+func NewManagerFromConfig(cfg config.SecretsConfig) *Manager {
+	m := NewManager()
+	m.Register("gcp", &lazyProvider{init: func(ctx context.Context) (Provider, error) {
+		return NewGCPProvider(ctx)
+	}})
+	m.Register("aws", &lazyProvider{init: func(ctx context.Context) (Provider, error) {
+		return NewAWSProvider(ctx, cfg.AWSRegion)
+	}})
+	m.Register("vault", &lazyProvider{init: func(ctx context.Context) (Provider, error) {
+		return NewVaultProvider(cfg.VaultAddress, cfg.VaultToken)
+	}})
+	return m
+}
+
+// lazyProvider creates its provider on the first fetch
+type lazyProvider struct {
+	init func(ctx context.Context) (Provider, error)
+
+	mu       sync.Mutex
+	provider Provider
+}
+
+func (p *lazyProvider) Fetch(ctx context.Context, path string) (string, error) {
+	p.mu.Lock()
+	if p.provider == nil {
+		provider, err := p.init(ctx)
+		if err != nil {
+			p.mu.Unlock()
+			return "", err
+		}
+		p.provider = provider
+	}
+	provider := p.provider
+	p.mu.Unlock()
+
+	return provider.Fetch(ctx, path)
+}
+
+// GCPProvider reads secrets from Google Cloud Secret Manager. Paths have the
+// form projects/<project>/secrets/<name>[/versions/<version>]; the latest
+// version is used when none is given.
+type GCPProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewGCPProvider creates a provider using the application default
+// credentials
+func NewGCPProvider(ctx context.Context) (*GCPProvider, error) {
+	client, _, err := htransport.NewClient(ctx, option.WithScopes("https://www.googleapis.com/auth/cloud-platform"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Secret Manager client: %w", err)
+	}
+	return &GCPProvider{client: client, baseURL: "https://secretmanager.googleapis.com"}, nil
+}
+
+// Fetch implements Provider
+func (p *GCPProvider) Fetch(ctx context.Context, path string) (string, error) {
+	if !strings.Contains(path, "/versions/") {
+		path += "/versions/latest"
+	}
+	req, err := http.NewRequest(http.MethodGet, p.baseURL+"/v1/"+path+":access", nil)
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := doJSON(ctx, p.client, req, &resp); err != nil {
+		return "", fmt.Errorf("failed to access GCP secret %s: %w", path, err)
+	}
+	data, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode GCP secret %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
+// AWSProvider reads secrets from AWS Secrets Manager. Paths are secret
+// names or ARNs.
+type AWSProvider struct {
+	client      *http.Client
+	endpoint    string
+	region      string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+}
+
+// NewAWSProvider creates a provider using the default AWS credential chain
+func NewAWSProvider(ctx context.Context, region string) (*AWSProvider, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return &AWSProvider{
+		client:      http.DefaultClient,
+		endpoint:    fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", awsCfg.Region),
+		region:      awsCfg.Region,
+		credentials: awsCfg.Credentials,
+		signer:      v4.NewSigner(),
+	}, nil
+}
+
+// Fetch implements Provider
+func (p *AWSProvider) Fetch(ctx context.Context, path string) (string, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": path})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodPost, p.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	creds, err := p.credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	hash := sha256.Sum256(body)
+	if err := p.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "secretsmanager", p.region, time.Now()); err != nil {
+		return "", fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	var resp struct {
+		SecretString string `json:"SecretString"`
+		SecretBinary []byte `json:"SecretBinary"`
+	}
+	if err := doJSON(ctx, p.client, req, &resp); err != nil {
+		return "", fmt.Errorf("failed to get AWS secret %s: %w", path, err)
+	}
+	if resp.SecretString != "" {
+		return resp.SecretString, nil
+	}
+	return string(resp.SecretBinary), nil
+}
+
+// VaultProvider reads secrets from HashiCorp Vault. Paths are API paths
+// below /v1, e.g. secret/data/metadatatool for a KV v2 engine mounted at
+// secret. The secret's fields are returned as a JSON object, so references
+// normally name the field with #key.
+type VaultProvider struct {
+	client  *http.Client
+	address string
+	token   string
+}
+
+// NewVaultProvider creates a provider for the Vault server at address
+func NewVaultProvider(address, token string) (*VaultProvider, error) {
+	if address == "" {
+		return nil, fmt.Errorf("vault address is not configured, set VAULT_ADDR")
+	}
+	return &VaultProvider{
+		client:  http.DefaultClient,
+		address: strings.TrimSuffix(address, "/"),
+		token:   token,
+	}, nil
+}
+
+// Fetch implements Provider
+func (p *VaultProvider) Fetch(ctx context.Context, path string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, p.address+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	var resp struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := doJSON(ctx, p.client, req, &resp); err != nil {
+		return "", fmt.Errorf("failed to read Vault secret %s: %w", path, err)
+	}
+
+	// KV v2 nests the fields below data.data, next to data.metadata
+	data, hasMetadata := resp.Data["data"], resp.Data["metadata"] != nil
+	if data != nil && hasMetadata {
+		return string(data), nil
+	}
+	fields, err := json.Marshal(resp.Data)
+	if err != nil {
+		return "", err
+	}
+	return string(fields), nil
+}
+
+// doJSON sends req and decodes a successful JSON response into out
+func doJSON(ctx context.Context, client *http.Client, req *http.Request, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,222 @@
+// Package secrets resolves configuration values that reference a secrets
+// manager instead of holding the secret itself. A reference has the form
+//
+//	secretref://<provider>/<path>[#<key>]
+//
+// where provider is gcp, aws or vault. When key is set the secret is read as
+// a JSON object and the value of that key is used, e.g.
+//
+//	secretref://gcp/projects/acme/secrets/db-password/versions/latest
+//	secretref://aws/prod/metadatatool#jwt_secret
+//	secretref://vault/secret/data/metadatatool#ai_api_key
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scheme prefixes values that reference a secret
+const Scheme = "secretref://"
+
+// Ref identifies a secret held by a provider
+type Ref struct {
+	Provider string
+	Path     string
+	Key      string
+}
+
+func (r Ref) String() string {
+	s := Scheme + r.Provider + "/" + r.Path
+	if r.Key != "" {
+		s += "#" + r.Key
+	}
+	return s
+}
+
+// IsRef reports whether value references a secret
+func IsRef(value string) bool {
+	return strings.HasPrefix(value, Scheme)
+}
+
+// ParseRef parses a secretref:// value
+func ParseRef(value string) (Ref, error) {
+	if !IsRef(value) {
+		return Ref{}, fmt.Errorf("secret reference must start with %s", Scheme)
+	}
+	rest, key, _ := strings.Cut(strings.TrimPrefix(value, Scheme), "#")
+	provider, path, _ := strings.Cut(rest, "/")
+	if provider == "" || path == "" {
+		return Ref{}, fmt.Errorf("invalid secret reference %q: expected %s<provider>/<path>", value, Scheme)
+	}
+	return Ref{Provider: provider, Path: path, Key: key}, nil
+}
+
+// Provider reads secrets from one secrets manager
+type Provider interface {
+	// Fetch returns the secret stored at path
+	Fetch(ctx context.Context, path string) (string, error)
+}
+
+// Manager resolves secret references with the registered providers and
+// keeps the resolved values current when refreshed
+type Manager struct {
+	providers map[string]Provider
+
+	mu       sync.Mutex
+	refs     map[string]Ref
+	values   map[string]string
+	onRotate []func(name, value string)
+}
+
+// NewManager creates a manager without providers
+func NewManager() *Manager {
+	return &Manager{
+		providers: make(map[string]Provider),
+		refs:      make(map[string]Ref),
+		values:    make(map[string]string),
+	}
+}
+
+// Register makes a provider available under name
+func (m *Manager) Register(name string, provider Provider) {
+	m.providers[name] = provider
+}
+
+// Resolve replaces every value in settings that is a secret reference with
+// the secret it points to. Settings holding plain values are left alone.
+// The references are remembered so Refresh can re-read them.
+func (m *Manager) Resolve(ctx context.Context, settings map[string]*string) error {
+	names := make([]string, 0, len(settings))
+	for name, value := range settings {
+		if IsRef(*value) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		ref, err := ParseRef(*settings[name])
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		value, err := m.fetch(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", name, err)
+		}
+
+		m.mu.Lock()
+		m.refs[name] = ref
+		m.values[name] = value
+		m.mu.Unlock()
+		*settings[name] = value
+	}
+	return nil
+}
+
+// Get returns the current value of a resolved setting
+func (m *Manager) Get(name string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.values[name]
+	return value, ok
+}
+
+// OnRotate registers fn to be called when Refresh finds that a secret has
+// changed
+func (m *Manager) OnRotate(fn func(name, value string)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onRotate = append(m.onRotate, fn)
+}
+
+// Refresh re-reads every resolved secret and notifies the rotation handlers
+// of those whose value changed. It returns the names of the changed settings.
+func (m *Manager) Refresh(ctx context.Context) ([]string, error) {
+	m.mu.Lock()
+	refs := make(map[string]Ref, len(m.refs))
+	for name, ref := range m.refs {
+		refs[name] = ref
+	}
+	m.mu.Unlock()
+
+	var changed []string
+	var errs []string
+	for name, ref := range refs {
+		value, err := m.fetch(ctx, ref)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+
+		m.mu.Lock()
+		rotated := m.values[name] != value
+		m.values[name] = value
+		handlers := m.onRotate
+		m.mu.Unlock()
+
+		if rotated {
+			changed = append(changed, name)
+			for _, fn := range handlers {
+				fn(name, value)
+			}
+		}
+	}
+	sort.Strings(changed)
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return changed, fmt.Errorf("failed to refresh secrets: %s", strings.Join(errs, "; "))
+	}
+	return changed, nil
+}
+
+// Run refreshes the secrets every interval until ctx is done. Errors are
+// passed to onError and do not stop the loop.
+func (m *Manager) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := m.Refresh(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+func (m *Manager) fetch(ctx context.Context, ref Ref) (string, error) {
+	provider, ok := m.providers[ref.Provider]
+	if !ok {
+		return "", fmt.Errorf("unknown secrets provider %q", ref.Provider)
+	}
+	value, err := provider.Fetch(ctx, ref.Path)
+	if err != nil {
+		return "", err
+	}
+	if ref.Key == "" {
+		return value, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", fmt.Errorf("secret %s is not a JSON object: %w", ref.Path, err)
+	}
+	field, ok := fields[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("secret %s has no key %q", ref.Path, ref.Key)
+	}
+	if s, ok := field.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(field), nil
+}
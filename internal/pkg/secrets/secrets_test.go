@@ -0,0 +1,102 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticProvider map[string]string
+
+func (p staticProvider) Fetch(ctx context.Context, path string) (string, error) {
+	return p[path], nil
+}
+
+func TestParseRef(t *testing.T) {
+	ref, err := ParseRef("secretref://aws/prod/metadatatool#jwt_secret")
+	require.NoError(t, err)
+	assert.Equal(t, Ref{Provider: "aws", Path: "prod/metadatatool", Key: "jwt_secret"}, ref)
+	assert.Equal(t, "secretref://aws/prod/metadatatool#jwt_secret", ref.String())
+
+	_, err = ParseRef("secretref://vault")
+	assert.Error(t, err)
+}
+
+func TestManager_ResolveAndRefresh(t *testing.T) {
+	store := staticProvider{
+		"db":  "hunter2",
+		"app": `{"jwt_secret":"jwt-1"}`,
+	}
+	m := NewManager()
+	m.Register("test", store)
+
+	password := "secretref://test/db"
+	jwt := "secretref://test/app#jwt_secret"
+	plain := "not-a-ref"
+	require.NoError(t, m.Resolve(context.Background(), map[string]*string{
+		"database.password": &password,
+		"auth.jwt_secret":   &jwt,
+		"ai.api_key":        &plain,
+	}))
+	assert.Equal(t, "hunter2", password)
+	assert.Equal(t, "jwt-1", jwt)
+	assert.Equal(t, "not-a-ref", plain)
+
+	var rotated []string
+	m.OnRotate(func(name, value string) { rotated = append(rotated, name+"="+value) })
+	store["app"] = `{"jwt_secret":"jwt-2"}`
+
+	changed, err := m.Refresh(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"auth.jwt_secret"}, changed)
+	assert.Equal(t, []string{"auth.jwt_secret=jwt-2"}, rotated)
+	value, _ := m.Get("auth.jwt_secret")
+	assert.Equal(t, "jwt-2", value)
+}
+
+func TestManager_ResolveFailsForUnknownProviderOrKey(t *testing.T) {
+	m := NewManager()
+	m.Register("test", staticProvider{"app": `{"a":"b"}`})
+
+	value := "secretref://nope/x"
+	assert.ErrorContains(t, m.Resolve(context.Background(), map[string]*string{"x": &value}), "unknown secrets provider")
+
+	value = "secretref://test/app#missing"
+	assert.ErrorContains(t, m.Resolve(context.Background(), map[string]*string{"x": &value}), `no key "missing"`)
+}
+
+func TestVaultProvider_ReadsKVv2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/secret/data/metadatatool", r.URL.Path)
+		assert.Equal(t, "token", r.Header.Get("X-Vault-Token"))
+		w.Write([]byte(`{"data":{"data":{"ai_api_key":"sk-1"},"metadata":{"version":3}}}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewVaultProvider(server.URL, "token")
+	require.NoError(t, err)
+	m := NewManager()
+	m.Register("vault", provider)
+
+	key := "secretref://vault/secret/data/metadatatool#ai_api_key"
+	require.NoError(t, m.Resolve(context.Background(), map[string]*string{"ai.api_key": &key}))
+	assert.Equal(t, "sk-1", key)
+}
+
+func TestGCPProvider_DefaultsToLatestVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/projects/acme/secrets/db/versions/latest:access", r.URL.Path)
+		w.Write([]byte(`{"payload":{"data":"` + base64.StdEncoding.EncodeToString([]byte("hunter2")) + `"}}`))
+	}))
+	defer server.Close()
+
+	provider := &GCPProvider{client: server.Client(), baseURL: server.URL}
+	value, err := provider.Fetch(context.Background(), "projects/acme/secrets/db")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", value)
+}
@@ -0,0 +1,170 @@
+// Package supervisor connects to the API's dependencies at startup and keeps
+// watching the optional ones, so services that depend on them can be
+// switched off while they are down and back on once they recover.
+package supervisor
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"metadatatool/internal/pkg/retry"
+)
+
+// Policy controls how often and how fast startup connections are retried
+type Policy struct {
+	Attempts uint
+	Delay    time.Duration
+	MaxDelay time.Duration
+}
+
+// Connect calls connect until it succeeds or the policy's attempts are used
+// up, waiting longer after each failure
+func Connect(name string, policy Policy, connect func() error) error {
+	if policy.Attempts == 0 {
+		policy.Attempts = 1
+	}
+	return retry.Do(connect,
+		retry.Attempts(policy.Attempts),
+		retry.Delay(policy.Delay),
+		retry.MaxDelay(policy.MaxDelay),
+		retry.OnRetry(func(attempt uint, err error) {
+			log.Printf("Connecting to %s failed (attempt %d of %d): %v", name, attempt, policy.Attempts, err)
+		}),
+	)
+}
+
+// Dependency tracks whether an optional dependency is reachable
+type Dependency struct {
+	name  string
+	check func(ctx context.Context) error
+	up    atomic.Bool
+
+	mu        sync.Mutex
+	onRecover []func()
+}
+
+// Name returns the dependency's name
+func (d *Dependency) Name() string {
+	return d.name
+}
+
+// Available reports whether the last check succeeded
+func (d *Dependency) Available() bool {
+	return d.up.Load()
+}
+
+// OnRecover registers fn to run each time the dependency comes back
+func (d *Dependency) OnRecover(fn func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onRecover = append(d.onRecover, fn)
+}
+
+func (d *Dependency) update(ctx context.Context) {
+	err := d.check(ctx)
+	switch {
+	case err == nil && !d.up.Load():
+		d.up.Store(true)
+		log.Printf("%s is available again", d.name)
+		d.mu.Lock()
+		handlers := append([]func(){}, d.onRecover...)
+		d.mu.Unlock()
+		for _, fn := range handlers {
+			fn()
+		}
+	case err != nil && d.up.Load():
+		d.up.Store(false)
+		log.Printf("%s became unavailable: %v", d.name, err)
+	}
+}
+
+// Supervisor periodically checks dependencies and reconnects to those that
+// could not be reached at startup
+type Supervisor struct {
+	interval time.Duration
+
+	mu         sync.Mutex
+	deps       []*Dependency
+	reconnects []*reconnect
+}
+
+type reconnect struct {
+	name      string
+	connect   func(ctx context.Context) error
+	onConnect func()
+	done      bool
+}
+
+// New creates a supervisor that checks every interval
+func New(interval time.Duration) *Supervisor {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	return &Supervisor{interval: interval}
+}
+
+// Watch starts tracking a dependency whose current state is available
+func (s *Supervisor) Watch(name string, available bool, check func(ctx context.Context) error) *Dependency {
+	dep := &Dependency{name: name, check: check}
+	dep.up.Store(available)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deps = append(s.deps, dep)
+	return dep
+}
+
+// Reconnect retries connect on every check until it succeeds once, then
+// calls onConnect. It is meant for services that could not be created at
+// startup.
+func (s *Supervisor) Reconnect(name string, connect func(ctx context.Context) error, onConnect func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reconnects = append(s.reconnects, &reconnect{name: name, connect: connect, onConnect: onConnect})
+}
+
+// Run checks the dependencies until ctx is done
+func (s *Supervisor) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkAll(ctx)
+		}
+	}
+}
+
+func (s *Supervisor) checkAll(ctx context.Context) {
+	s.mu.Lock()
+	deps := append([]*Dependency{}, s.deps...)
+	reconnects := append([]*reconnect{}, s.reconnects...)
+	s.mu.Unlock()
+
+	for _, dep := range deps {
+		checkCtx, cancel := context.WithTimeout(ctx, s.interval)
+		dep.update(checkCtx)
+		cancel()
+	}
+
+	for _, r := range reconnects {
+		if r.done {
+			continue
+		}
+		checkCtx, cancel := context.WithTimeout(ctx, s.interval)
+		err := r.connect(checkCtx)
+		cancel()
+		if err != nil {
+			continue
+		}
+		r.done = true
+		log.Printf("Connected to %s", r.name)
+		r.onConnect()
+	}
+}
@@ -0,0 +1,72 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnect_RetriesUntilSuccess(t *testing.T) {
+	calls := 0
+	err := Connect("db", Policy{Attempts: 3}, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = Connect("db", Policy{Attempts: 2}, func() error {
+		calls++
+		return errors.New("connection refused")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestSupervisor_WatchReportsRecovery(t *testing.T) {
+	s := New(0)
+	var down error = errors.New("connection refused")
+	dep := s.Watch("redis", false, func(ctx context.Context) error { return down })
+
+	recovered := 0
+	dep.OnRecover(func() { recovered++ })
+
+	s.checkAll(context.Background())
+	assert.False(t, dep.Available())
+	assert.Equal(t, 0, recovered)
+
+	down = nil
+	s.checkAll(context.Background())
+	s.checkAll(context.Background())
+	assert.True(t, dep.Available())
+	assert.Equal(t, 1, recovered)
+
+	down = errors.New("connection reset")
+	s.checkAll(context.Background())
+	assert.False(t, dep.Available())
+}
+
+func TestSupervisor_ReconnectCallsOnConnectOnce(t *testing.T) {
+	s := New(0)
+	attempts, connected := 0, 0
+	s.Reconnect("queue", func(ctx context.Context) error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("unavailable")
+		}
+		return nil
+	}, func() { connected++ })
+
+	for i := 0; i < 3; i++ {
+		s.checkAll(context.Background())
+	}
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, 1, connected)
+}
@@ -0,0 +1,30 @@
+package validator
+
+import (
+	"context"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// publishingValidator publishes a domain.ValidationFailed event for every
+// track that fails validation
+type publishingValidator struct {
+	delegate domain.Validator
+	events   domain.EventPublisher
+}
+
+// WithEvents returns a validator that validates with delegate and publishes
+// the failures to events
+func WithEvents(delegate domain.Validator, events domain.EventPublisher) domain.Validator {
+	return &publishingValidator{delegate: delegate, events: events}
+}
+
+// Validate implements domain.Validator
+func (v *publishingValidator) Validate(track *domain.Track) domain.ValidationResult {
+	result := v.delegate.Validate(track)
+	if !result.IsValid {
+		v.events.Publish(context.Background(), domain.ValidationFailed{Track: track.Clone(), Errors: result.Errors, At: time.Now()})
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// UseJSONNames makes engine report fields by the names they have in JSON.
+// Engines other than *validator.Validate, such as gin's binding.Validator
+// engine when replaced, are left alone.
+func UseJSONNames(engine interface{}) {
+	if v, ok := engine.(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonFieldName)
+	}
+}
+
+// Describe turns the errors of a failed struct validation into validation
+// errors a client can act on: the JSON path of the field, the failed rule
+// as code and a sentence explaining it. ok is false when err is not a
+// struct validation error.
+func Describe(err error) (errs []domain.ValidationError, ok bool) {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return nil, false
+	}
+	for _, fe := range fieldErrs {
+		errs = append(errs, domain.ValidationError{
+			Field:   fieldPath(fe.Namespace()),
+			Code:    fe.Tag(),
+			Message: ruleMessage(fe),
+		})
+	}
+	return errs, true
+}
+
+// ruleMessage explains the rule a field failed
+func ruleMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "uuid":
+		return "must be a valid UUID"
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "min", "gte":
+		return "must be at least " + sizeOf(fe)
+	case "max", "lte":
+		return "must be at most " + sizeOf(fe)
+	case "len":
+		return "must be exactly " + sizeOf(fe)
+	default:
+		return "must satisfy " + fe.Tag()
+	}
+}
+
+// sizeOf renders the parameter of a size rule in the unit of the field
+func sizeOf(fe validator.FieldError) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return fe.Param() + " characters long"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fe.Param() + " items"
+	}
+	return fe.Param()
+}
+
+// fieldPath drops the struct name the validator puts in front of a field
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
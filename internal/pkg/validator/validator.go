@@ -11,8 +11,10 @@ type Validator struct {
 }
 
 func NewValidator() *Validator {
+	validate := validator.New()
+	UseJSONNames(validate)
 	return &Validator{
-		validate: validator.New(),
+		validate: validate,
 	}
 }
 
@@ -22,12 +24,9 @@ func (v *Validator) Validate(track *domain.Track) domain.ValidationResult {
 		return domain.ValidationResult{IsValid: true}
 	}
 
-	var errors []domain.ValidationError
-	for _, err := range err.(validator.ValidationErrors) {
-		errors = append(errors, domain.ValidationError{
-			Field:   err.Field(),
-			Message: err.Error(),
-		})
+	errors, ok := Describe(err)
+	if !ok {
+		errors = []domain.ValidationError{{Code: "invalid", Message: err.Error()}}
 	}
 
 	return domain.ValidationResult{
@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+)
+
+// maxPromptSections caps the sections described to a model, so a long DJ
+// mix does not fill the prompt with its timeline
+const maxPromptSections = 12
+
+// AudioFeatureSource gives the stored audio analysis of a track. The
+// latest analysis is asked for, whatever the analyzer version.
+type AudioFeatureSource interface {
+	Get(ctx context.Context, trackID, version string) (*pkgdomain.TrackAnalysis, error)
+}
+
+// audioFeatures returns the latest audio analysis of a track, or nil when
+// there is no source or the track has not been analyzed. Enrichment then
+// goes on from the text metadata alone.
+func audioFeatures(ctx context.Context, source AudioFeatureSource, track *pkgdomain.Track) *pkgdomain.AudioAnalysis {
+	if source == nil || track.ID == "" {
+		return nil
+	}
+	analysis, err := source.Get(ctx, track.ID, "")
+	if err != nil {
+		if !errors.Is(err, pkgdomain.ErrAnalysisNotFound) {
+			log.Printf("failed to get audio analysis of track %s: %v", track.ID, err)
+		}
+		return nil
+	}
+	return analysis.Analysis
+}
+
+// audioFeaturePrompt describes the measured audio features of a track for a
+// model, one feature per line. Features the analyzer did not detect are
+// left out rather than reported as zero.
+func audioFeaturePrompt(analysis *pkgdomain.AudioAnalysis) string {
+	fitted, _ := fitPrompt(audioFeatureParts(analysis), 0)
+	return joinPrompt(fitted)
+}
+
+// audioFeatureParts splits the audio feature prompt of a track into the
+// track-wide features and its sections, which are cut first when the
+// prompt is over budget
+func audioFeatureParts(analysis *pkgdomain.AudioAnalysis) []promptPart {
+	if analysis == nil {
+		return nil
+	}
+	features := promptPart{name: "features", header: "Measured audio features:", priority: promptPriorityFeatures}
+	if analysis.BPM > 0 {
+		features.lines = append(features.lines, fmt.Sprintf("- Tempo: %.1f BPM", analysis.BPM))
+	}
+	if analysis.TimeSignature != "" {
+		features.lines = append(features.lines, fmt.Sprintf("- Time signature: %s", analysis.TimeSignature))
+	}
+	if key := strings.TrimSpace(analysis.Key + " " + analysis.Mode); key != "" {
+		features.lines = append(features.lines, fmt.Sprintf("- Key: %s", key))
+	}
+	if analysis.Duration > 0 {
+		features.lines = append(features.lines, fmt.Sprintf("- Duration: %s", formatSeconds(analysis.Duration)))
+	}
+	features.lines = append(features.lines,
+		fmt.Sprintf("- Energy: %.2f (0-1)", analysis.Energy),
+		fmt.Sprintf("- Danceability: %.2f (0-1)", analysis.Danceability))
+	if analysis.Valence > 0 {
+		features.lines = append(features.lines, fmt.Sprintf("- Valence: %.2f (0-1)", analysis.Valence))
+	}
+	if analysis.Loudness != 0 {
+		features.lines = append(features.lines, fmt.Sprintf("- Loudness: %.1f dB", analysis.Loudness))
+	}
+
+	sections := promptPart{name: "sections", header: "Sections:", priority: promptPrioritySections}
+	if len(analysis.Sections) > 1 {
+		for i, section := range analysis.Sections {
+			if i == maxPromptSections {
+				sections.lines = append(sections.lines, fmt.Sprintf("- and %d more", len(analysis.Sections)-i))
+				break
+			}
+			line := fmt.Sprintf("- %s-%s: %.1f BPM", formatSeconds(section.Start), formatSeconds(section.Start+section.Duration), section.BPM)
+			if section.Camelot != "" {
+				line += fmt.Sprintf(", key %s", section.Camelot)
+			}
+			sections.lines = append(sections.lines, line+fmt.Sprintf(", energy %.2f", section.Energy))
+		}
+	}
+	return []promptPart{features, sections}
+}
+
+// formatSeconds formats a time in seconds as minutes and seconds
+func formatSeconds(seconds float64) string {
+	total := int(seconds + 0.5)
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
@@ -0,0 +1,158 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memFeatureSource serves analyses from memory by track
+type memFeatureSource map[string]*pkgdomain.AudioAnalysis
+
+func (s memFeatureSource) Get(_ context.Context, trackID, _ string) (*pkgdomain.TrackAnalysis, error) {
+	analysis, ok := s[trackID]
+	if !ok {
+		return nil, pkgdomain.ErrAnalysisNotFound
+	}
+	return &pkgdomain.TrackAnalysis{TrackID: trackID, AnalyzerVersion: "v1", Analysis: analysis}, nil
+}
+
+func analyzedMix() *pkgdomain.AudioAnalysis {
+	return &pkgdomain.AudioAnalysis{
+		BPM:          128,
+		Key:          "A",
+		Mode:         "minor",
+		Energy:       0.82,
+		Danceability: 0.9,
+		Duration:     420,
+		Sections: []pkgdomain.AudioSection{
+			{Start: 0, Duration: 240, BPM: 128, Camelot: "8A", Energy: 0.8},
+			{Start: 240, Duration: 180, BPM: 124, Camelot: "5A", Energy: 0.85},
+		},
+	}
+}
+
+func TestAudioFeaturePrompt(t *testing.T) {
+	assert.Empty(t, audioFeaturePrompt(nil))
+
+	prompt := audioFeaturePrompt(analyzedMix())
+	assert.Contains(t, prompt, "- Tempo: 128.0 BPM\n")
+	assert.Contains(t, prompt, "- Key: A minor\n")
+	assert.Contains(t, prompt, "- Duration: 7:00\n")
+	assert.Contains(t, prompt, "- Energy: 0.82 (0-1)\n")
+	assert.Contains(t, prompt, "- 4:00-7:00: 124.0 BPM, key 5A, energy 0.85\n")
+	assert.NotContains(t, prompt, "Time signature", "undetected features are left out")
+
+	// Long timelines are cut short
+	analysis := analyzedMix()
+	analysis.Sections = make([]pkgdomain.AudioSection, maxPromptSections+5)
+	prompt = audioFeaturePrompt(analysis)
+	assert.Equal(t, maxPromptSections, strings.Count(prompt, "BPM, energy"))
+	assert.Contains(t, prompt, "- and 5 more\n")
+}
+
+func TestQwen2Service_EnrichMetadataWithAudioFeatures(t *testing.T) {
+	var response Qwen2Response
+	response.Metadata.Genre = "techno"
+	response.Metadata.Mood = "driving"
+	response.Metadata.Confidence = 0.9
+
+	newService := func(enabled bool) (*Qwen2Service, *mockQwen2Client) {
+		client := &mockQwen2Client{}
+		service, err := NewQwen2ServiceWithClient(&pkgdomain.Qwen2Config{
+			MinConfidence: 0.85,
+			AudioFeatures: enabled,
+		}, client)
+		require.NoError(t, err)
+		qwen2 := service.(*Qwen2Service)
+		qwen2.SetAudioFeatureSource(memFeatureSource{"analyzed": analyzedMix()})
+		return qwen2, client
+	}
+
+	t.Run("analyzed track", func(t *testing.T) {
+		service, client := newService(true)
+		client.On("AnalyzeAudioWithFeatures", mock.Anything, mock.Anything, mock.Anything,
+			mock.MatchedBy(func(features string) bool { return strings.Contains(features, "128.0 BPM") })).
+			Return(&response, nil).Once()
+
+		track := &pkgdomain.Track{ID: "analyzed", AudioData: []byte("audio")}
+		require.NoError(t, service.EnrichMetadata(context.Background(), track))
+		assert.Equal(t, "techno", track.Genre())
+		assert.Equal(t, qwen2FeaturePrompt, track.Metadata.AI.PromptVersion)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("track not analyzed", func(t *testing.T) {
+		service, client := newService(true)
+		client.On("AnalyzeAudio", mock.Anything, mock.Anything, mock.Anything).Return(&response, nil).Once()
+
+		track := &pkgdomain.Track{ID: "new", AudioData: []byte("audio")}
+		require.NoError(t, service.EnrichMetadata(context.Background(), track))
+		assert.Equal(t, qwen2AudioPrompt, track.Metadata.AI.PromptVersion)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("features off", func(t *testing.T) {
+		service, client := newService(false)
+		client.On("AnalyzeAudio", mock.Anything, mock.Anything, mock.Anything).Return(&response, nil).Once()
+
+		require.NoError(t, service.EnrichMetadata(context.Background(), &pkgdomain.Track{ID: "analyzed", AudioData: []byte("audio")}))
+		client.AssertExpectations(t)
+	})
+}
+
+func TestOpenAIService_EnrichMetadataWithAudioFeatures(t *testing.T) {
+	var prompts, models []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openai.ChatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		prompts = append(prompts, req.Messages[len(req.Messages)-1].Content)
+		models = append(models, req.Model)
+
+		answer, _ := json.Marshal(featureEnrichment{Genre: "Techno", Mood: "Driving", Tags: []string{"peak time"}, Confidence: 0.7})
+		_ = json.NewEncoder(w).Encode(openai.ChatCompletionResponse{
+			Model: req.Model,
+			Choices: []openai.ChatCompletionChoice{
+				{Message: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: string(answer)}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	service, err := NewOpenAIService(&pkgdomain.OpenAIConfig{
+		APIKey:        "test-key",
+		Endpoint:      server.URL,
+		ModelVersion:  "2024-07-18",
+		MinConfidence: 0.85,
+		AudioFeatures: true,
+	})
+	require.NoError(t, err)
+	service.(*OpenAIService).SetAudioFeatureSource(memFeatureSource{"analyzed": analyzedMix()})
+
+	track := &pkgdomain.Track{ID: "analyzed"}
+	track.SetTitle("Night Drive")
+	require.NoError(t, service.EnrichMetadata(context.Background(), track))
+
+	require.Len(t, prompts, 1)
+	assert.Contains(t, prompts[0], "- Title: Night Drive\n")
+	assert.Contains(t, prompts[0], "- Tempo: 128.0 BPM\n")
+	assert.Equal(t, "Techno", track.Genre())
+	assert.Equal(t, "Driving", track.Mood())
+	require.NotNil(t, track.Metadata.AI)
+	assert.Equal(t, []string{"peak time"}, track.Metadata.AI.Tags)
+	assert.Equal(t, []string{"gpt-4o-mini-2024-07-18"}, models, "the version pins the snapshot")
+	assert.Equal(t, "gpt-4o-mini", track.Metadata.AI.Model)
+	assert.Equal(t, "2024-07-18", track.Metadata.AI.Version)
+	assert.Equal(t, openAIFeaturePrompt, track.Metadata.AI.PromptVersion)
+	assert.True(t, track.Metadata.AI.NeedsReview, "confidence is below the threshold")
+}
@@ -8,7 +8,7 @@
 //   - Automatic fallback to backup providers
 //   - Retry mechanism with exponential backoff
 //   - Analytics tracking for experiments
-//   - Concurrent request limiting
+//   - Per-provider concurrency and rate limits with fair queuing across tenants
 //
 // Usage example:
 //
@@ -40,6 +40,7 @@ import (
 	"math/rand"
 	"metadatatool/internal/pkg/analytics"
 	pkgdomain "metadatatool/internal/pkg/domain"
+	"sort"
 	"sync"
 	"time"
 )
@@ -50,10 +51,17 @@ type Config struct {
 	TimeoutSeconds        int
 	MinConfidence         float64
 	MaxConcurrentRequests int
-	RetryAttempts         int
-	RetryBackoffSeconds   int
-	Qwen2Config           *pkgdomain.Qwen2Config
-	OpenAIConfig          *pkgdomain.OpenAIConfig
+	// ExperimentTrafficPercent is the share of enrichments (0-1) sent to
+	// the experiment provider
+	ExperimentTrafficPercent float64
+	RetryAttempts            int
+	RetryBackoffSeconds      int
+	Qwen2Config              *pkgdomain.Qwen2Config
+	OpenAIConfig             *pkgdomain.OpenAIConfig
+
+	// RoutingPolicy decides which provider an enrichment tries first;
+	// empty is the experiment split
+	RoutingPolicy pkgdomain.AIRoutingPolicy
 }
 
 // OpenAIConfig contains configuration specific to the OpenAI provider.
@@ -94,10 +102,20 @@ type CompositeAIService struct {
 	primaryProvider  pkgdomain.AIProvider
 	fallbackProvider pkgdomain.AIProvider
 	metrics          map[pkgdomain.AIProvider]*pkgdomain.AIMetrics
-	analytics        *analytics.BigQueryService
+	analytics        analytics.EventRecorder
 	experimentGroup  string
-	semaphore        chan struct{}
+	trafficPercent   float64
+	limiters         map[pkgdomain.AIProvider]*ProviderLimiter
+	policy           pkgdomain.AIRoutingPolicy
+	health           map[pkgdomain.AIProvider]*providerHealth
+	forced           pkgdomain.AIProvider
+	features         AudioFeatureSource
 	mu               sync.RWMutex
+
+	// labels caches the services of labels with their own configuration
+	labelConfigs pkgdomain.LabelAIConfigRepository
+	labels       map[string]*labelService
+	labelMu      sync.Mutex
 }
 
 // Provider defines the interface that all AI providers must implement.
@@ -111,7 +129,7 @@ type Provider interface {
 }
 
 // NewCompositeAIService creates a new composite AI service
-func NewCompositeAIService(config *Config, analytics *analytics.BigQueryService) (pkgdomain.AIService, error) {
+func NewCompositeAIService(config *Config, analytics analytics.EventRecorder) (pkgdomain.AIService, error) {
 	if config == nil {
 		return nil, fmt.Errorf("AI service config is required")
 	}
@@ -131,6 +149,14 @@ func NewCompositeAIService(config *Config, analytics *analytics.BigQueryService)
 		return nil, fmt.Errorf("failed to create openai service: %w", err)
 	}
 
+	policy := config.RoutingPolicy
+	if policy == "" {
+		policy = pkgdomain.AIRoutingExperiment
+	}
+	if policy != pkgdomain.AIRoutingExperiment && policy != pkgdomain.AIRoutingLatency {
+		return nil, fmt.Errorf("unknown AI routing policy %q", policy)
+	}
+
 	service := &CompositeAIService{
 		config:           config,
 		qwen2Service:     qwen2Service,
@@ -139,72 +165,157 @@ func NewCompositeAIService(config *Config, analytics *analytics.BigQueryService)
 		fallbackProvider: pkgdomain.AIProviderOpenAI,
 		metrics:          make(map[pkgdomain.AIProvider]*pkgdomain.AIMetrics),
 		analytics:        analytics,
-		semaphore:        make(chan struct{}, config.MaxConcurrentRequests),
+		trafficPercent:   config.ExperimentTrafficPercent,
+		limiters: map[pkgdomain.AIProvider]*ProviderLimiter{
+			pkgdomain.AIProviderQwen2: NewProviderLimiter(pkgdomain.AIProviderQwen2,
+				providerConcurrency(config.Qwen2Config.MaxConcurrentRequests, config), config.Qwen2Config.RequestsPerSecond),
+			pkgdomain.AIProviderOpenAI: NewProviderLimiter(pkgdomain.AIProviderOpenAI,
+				providerConcurrency(config.OpenAIConfig.MaxConcurrentRequests, config), config.OpenAIConfig.RequestsPerSecond),
+		},
+		policy: policy,
+		health: map[pkgdomain.AIProvider]*providerHealth{
+			pkgdomain.AIProviderQwen2:  {healthy: true},
+			pkgdomain.AIProviderOpenAI: {healthy: true},
+		},
+		labels: make(map[string]*labelService),
 	}
 
 	return service, nil
 }
 
-// EnrichMetadata enriches track metadata using AI
+// EnrichMetadata enriches track metadata using AI. The experiment traffic
+// share decides the provider tried first, unless the routing policy or the
+// provider health says otherwise; with fallback on, the other provider is
+// tried when it fails.
 func (s *CompositeAIService) EnrichMetadata(ctx context.Context, track *pkgdomain.Track) error {
-	start := time.Now()
-
-	// Determine if this request should be part of the experiment (10% of traffic)
-	isExperiment := rand.Float64() < 0.1
+	// Labels with their own configuration are enriched with it alone
+	label, err := s.labelService(ctx, track.LabelID)
+	if err != nil {
+		return fmt.Errorf("failed to enrich metadata: %w", err)
+	}
+	if label.service != nil {
+		start := time.Now()
+		err := s.callLabel(ctx, label, func(service pkgdomain.AIService) error {
+			return service.EnrichMetadata(ctx, track)
+		})
+		duration := time.Since(start)
+		s.recordEnrichment(ctx, track, label.provider, false, duration, err)
+		if err != nil {
+			s.recordFailure(label.provider, err)
+			return fmt.Errorf("failed to enrich metadata: %w", err)
+		}
+		s.recordSuccess(label.provider, duration)
+		return nil
+	}
 
-	var service pkgdomain.AIService
-	var provider pkgdomain.AIProvider
+	// Determine if this request should be part of the experiment
+	s.mu.RLock()
+	trafficPercent := s.trafficPercent
+	s.mu.RUnlock()
+	isExperiment := rand.Float64() < trafficPercent
 
+	preferred := pkgdomain.AIProviderQwen2
 	if isExperiment {
-		service = s.openAIService
-		provider = pkgdomain.AIProviderOpenAI
-	} else {
-		service = s.qwen2Service
-		provider = pkgdomain.AIProviderQwen2
+		preferred = pkgdomain.AIProviderOpenAI
 	}
 
-	// Acquire semaphore
-	select {
-	case s.semaphore <- struct{}{}:
-		defer func() { <-s.semaphore }()
-	case <-ctx.Done():
-		return ctx.Err()
+	err = s.tryProviders(s.route(preferred), func(provider pkgdomain.AIProvider, service pkgdomain.AIService) error {
+		start := time.Now()
+		// Call the service once the provider has capacity
+		err := s.limited(ctx, provider, func() error {
+			return service.EnrichMetadata(ctx, track)
+		})
+		duration := time.Since(start)
+		s.recordEnrichment(ctx, track, provider, isExperiment && provider == preferred, duration, err)
+		if err != nil {
+			s.recordFailure(provider, err)
+			return err
+		}
+		s.recordSuccess(provider, duration)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enrich metadata: %w", err)
 	}
+	return nil
+}
 
-	// Call the service
-	err := service.EnrichMetadata(ctx, track)
-	duration := time.Since(start)
+// Providers lists the configured providers
+func (s *CompositeAIService) Providers() []pkgdomain.AIProvider {
+	return []pkgdomain.AIProvider{pkgdomain.AIProviderQwen2, pkgdomain.AIProviderOpenAI}
+}
 
-	if err != nil {
-		s.recordFailure(provider, err)
-		return fmt.Errorf("failed to enrich metadata: %w", err)
+// ModelVersions returns the model versions the providers enrich with now.
+// Tracks enriched with any other version are outdated.
+func (s *CompositeAIService) ModelVersions() []pkgdomain.AIModelVersion {
+	var versions []pkgdomain.AIModelVersion
+	for _, service := range []pkgdomain.AIService{s.qwen2Service, s.openAIService} {
+		if versioner, ok := service.(pkgdomain.AIModelVersioner); ok {
+			versions = append(versions, versioner.ModelVersions()...)
+		}
 	}
+	return versions
+}
 
-	s.recordSuccess(provider, duration)
+// BatchProvider returns the batch API of a provider, for the providers
+// that have one
+func (s *CompositeAIService) BatchProvider(provider pkgdomain.AIProvider) (pkgdomain.AIBatchProvider, bool) {
+	service, _ := s.service(provider)
+	batches, ok := service.(pkgdomain.AIBatchProvider)
+	return batches, ok
+}
 
-	return nil
+// EnrichWithProvider enriches a track with one provider only, without
+// fallback or experiment traffic. It is used to score providers against
+// each other, so it is not recorded in the enrichment analytics.
+func (s *CompositeAIService) EnrichWithProvider(ctx context.Context, provider pkgdomain.AIProvider, track *pkgdomain.Track) error {
+	service, ok := s.service(provider)
+	if !ok {
+		return fmt.Errorf("unknown AI provider %q", provider)
+	}
+	return s.limited(ctx, provider, func() error {
+		return service.EnrichMetadata(ctx, track)
+	})
 }
 
 // ValidateMetadata validates track metadata using AI
 func (s *CompositeAIService) ValidateMetadata(ctx context.Context, track *pkgdomain.Track) (float64, error) {
-	// Use primary service first
-	confidence, err := s.getPrimaryService().ValidateMetadata(ctx, track)
-	if err == nil {
-		return confidence, nil
-	}
-
-	// If fallback is disabled, return the error
-	if !s.config.EnableFallback {
+	label, err := s.labelService(ctx, track.LabelID)
+	if err != nil {
 		return 0.0, err
 	}
+	var confidence float64
+	if label.service != nil {
+		start := time.Now()
+		err := s.callLabel(ctx, label, func(service pkgdomain.AIService) error {
+			var err error
+			confidence, err = service.ValidateMetadata(ctx, track)
+			return err
+		})
+		s.recordValidation(ctx, track, label.provider, confidence, time.Since(start), err)
+		if err != nil {
+			return 0.0, err
+		}
+		return confidence, nil
+	}
 
-	// Try fallback service
-	confidence, fallbackErr := s.getFallbackService().ValidateMetadata(ctx, track)
-	if fallbackErr != nil {
-		// Return original error if fallback also fails
+	// Use primary service first
+	s.mu.RLock()
+	primary := s.primaryProvider
+	s.mu.RUnlock()
+	err = s.tryProviders(s.route(primary), func(provider pkgdomain.AIProvider, service pkgdomain.AIService) error {
+		start := time.Now()
+		err := s.limited(ctx, provider, func() error {
+			var err error
+			confidence, err = service.ValidateMetadata(ctx, track)
+			return err
+		})
+		s.recordValidation(ctx, track, provider, confidence, time.Since(start), err)
+		return err
+	})
+	if err != nil {
 		return 0.0, err
 	}
-
 	return confidence, nil
 }
 
@@ -226,6 +337,35 @@ func (s *CompositeAIService) SetPrimaryProvider(provider pkgdomain.AIProvider) {
 	s.primaryProvider = provider
 }
 
+// SetExperimentTrafficPercent changes the share of enrichments (0-1) sent to
+// the experiment provider
+func (s *CompositeAIService) SetExperimentTrafficPercent(percent float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trafficPercent = percent
+}
+
+// SetMinConfidence changes the confidence threshold of the providers that
+// support changing it while running
+func (s *CompositeAIService) SetMinConfidence(minConfidence float64) {
+	for _, service := range []pkgdomain.AIService{s.qwen2Service, s.openAIService} {
+		if setter, ok := service.(interface{ SetMinConfidence(float64) }); ok {
+			setter.SetMinConfidence(minConfidence)
+		}
+	}
+}
+
+// SetAudioFeatureSource gives the providers with AudioFeatures on the
+// stored audio analyses to enrich from. It must be called before use.
+func (s *CompositeAIService) SetAudioFeatureSource(source AudioFeatureSource) {
+	s.features = source
+	for _, service := range []pkgdomain.AIService{s.qwen2Service, s.openAIService} {
+		if setter, ok := service.(interface{ SetAudioFeatureSource(AudioFeatureSource) }); ok {
+			setter.SetAudioFeatureSource(source)
+		}
+	}
+}
+
 // SetFallbackProvider sets the fallback AI provider
 func (s *CompositeAIService) SetFallbackProvider(provider pkgdomain.AIProvider) {
 	s.mu.Lock()
@@ -248,26 +388,61 @@ func (s *CompositeAIService) GetProviderMetrics() map[pkgdomain.AIProvider]*pkgd
 	return metrics
 }
 
-// Helper methods
-
-func (s *CompositeAIService) getPrimaryService() pkgdomain.AIService {
+// ProviderStats reports the health of each configured provider. A provider
+// is healthy until a request fails and again once a later request succeeds.
+func (s *CompositeAIService) ProviderStats() []pkgdomain.AIProviderStats {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	if s.primaryProvider == pkgdomain.AIProviderQwen2 {
-		return s.qwen2Service
+	stats := make([]pkgdomain.AIProviderStats, 0, len(s.limiters))
+	for provider, limiter := range s.limiters {
+		entry := pkgdomain.AIProviderStats{Provider: provider, Healthy: true}
+		if m, ok := s.metrics[provider]; ok {
+			entry.Healthy = m.LastFailure.IsZero() || m.LastSuccess.After(m.LastFailure)
+			entry.Requests = m.RequestCount
+			entry.Failures = m.FailureCount
+			entry.AverageLatencyMs = m.AverageLatency.Milliseconds()
+			if m.RequestCount > 0 {
+				entry.FailureRate = float64(m.FailureCount) / float64(m.RequestCount)
+			}
+			if !m.LastSuccess.IsZero() {
+				lastSuccess := m.LastSuccess
+				entry.LastSuccess = &lastSuccess
+			}
+			if m.LastError != nil {
+				entry.LastError = m.LastError.Error()
+			}
+		}
+		entry.QueueDepth = limiter.QueueDepth()
+		stats = append(stats, entry)
 	}
-	return s.openAIService
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Provider < stats[j].Provider })
+	return stats
 }
 
-func (s *CompositeAIService) getFallbackService() pkgdomain.AIService {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// Helper methods
 
-	if s.fallbackProvider == pkgdomain.AIProviderQwen2 {
-		return s.qwen2Service
+// providerConcurrency returns a provider's concurrency limit, defaulting to
+// the service-wide limit
+func providerConcurrency(limit int, config *Config) int {
+	if limit > 0 {
+		return limit
 	}
-	return s.openAIService
+	return config.MaxConcurrentRequests
+}
+
+// limited calls fn once the provider's limiter admits the request
+func (s *CompositeAIService) limited(ctx context.Context, provider pkgdomain.AIProvider, fn func() error) error {
+	limiter, ok := s.limiters[provider]
+	if !ok {
+		return fn()
+	}
+	release, err := limiter.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn()
 }
 
 func (s *CompositeAIService) recordSuccess(provider pkgdomain.AIProvider, duration time.Duration) {
@@ -284,6 +459,55 @@ func (s *CompositeAIService) recordSuccess(provider pkgdomain.AIProvider, durati
 	s.metrics[provider].AverageLatency = (s.metrics[provider].AverageLatency*time.Duration(s.metrics[provider].RequestCount-1) + duration) / time.Duration(s.metrics[provider].RequestCount)
 }
 
+// recordEnrichment sends an enrichment run to analytics
+func (s *CompositeAIService) recordEnrichment(ctx context.Context, track *pkgdomain.Track, provider pkgdomain.AIProvider, isExperiment bool, duration time.Duration, err error) {
+	if s.analytics == nil {
+		return
+	}
+	event := analytics.EnrichmentEvent{
+		Timestamp:       time.Now(),
+		TrackID:         track.ID,
+		Tenant:          pkgdomain.TenantFromContext(ctx),
+		Provider:        string(provider),
+		ExperimentGroup: "control",
+		DurationMs:      duration.Milliseconds(),
+		Success:         err == nil,
+	}
+	if isExperiment {
+		event.ExperimentGroup = "experiment"
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	if ai := track.Metadata.AI; ai != nil {
+		event.Model = ai.Model
+		event.ModelVersion = ai.Version
+		event.Confidence = ai.Confidence
+		event.NeedsReview = ai.NeedsReview
+	}
+	s.analytics.Record(event)
+}
+
+// recordValidation sends a validation score to analytics
+func (s *CompositeAIService) recordValidation(ctx context.Context, track *pkgdomain.Track, provider pkgdomain.AIProvider, score float64, duration time.Duration, err error) {
+	if s.analytics == nil {
+		return
+	}
+	event := analytics.ValidationEvent{
+		Timestamp:  time.Now(),
+		TrackID:    track.ID,
+		Tenant:     pkgdomain.TenantFromContext(ctx),
+		Provider:   string(provider),
+		Score:      score,
+		DurationMs: duration.Milliseconds(),
+		Success:    err == nil,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	s.analytics.Record(event)
+}
+
 func (s *CompositeAIService) recordFailure(provider pkgdomain.AIProvider, err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -294,6 +518,7 @@ func (s *CompositeAIService) recordFailure(provider pkgdomain.AIProvider, err er
 
 	s.metrics[provider].RequestCount++
 	s.metrics[provider].FailureCount++
+	s.metrics[provider].LastFailure = time.Now()
 	s.metrics[provider].LastError = err
 }
 
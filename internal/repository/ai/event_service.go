@@ -0,0 +1,53 @@
+package ai
+
+import (
+	"context"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+)
+
+// EventAIService publishes a domain.MetadataEnriched event for every track
+// it enriches
+type EventAIService struct {
+	delegate pkgdomain.AIService
+	events   pkgdomain.EventPublisher
+}
+
+// NewEventAIService creates an AI service that publishes enrichments to
+// events
+func NewEventAIService(delegate pkgdomain.AIService, events pkgdomain.EventPublisher) *EventAIService {
+	return &EventAIService{
+		delegate: delegate,
+		events:   events,
+	}
+}
+
+// EnrichMetadata enriches the track and publishes the enrichment
+func (s *EventAIService) EnrichMetadata(ctx context.Context, track *pkgdomain.Track) error {
+	if err := s.delegate.EnrichMetadata(ctx, track); err != nil {
+		return err
+	}
+
+	s.events.Publish(ctx, pkgdomain.MetadataEnriched{Track: track.Clone(), At: time.Now()})
+	return nil
+}
+
+// ValidateMetadata delegates validation unchanged
+func (s *EventAIService) ValidateMetadata(ctx context.Context, track *pkgdomain.Track) (float64, error) {
+	return s.delegate.ValidateMetadata(ctx, track)
+}
+
+// BatchProcess enriches the tracks and publishes the enrichment of each. A
+// failed batch publishes nothing.
+func (s *EventAIService) BatchProcess(ctx context.Context, tracks []*pkgdomain.Track) error {
+	if err := s.delegate.BatchProcess(ctx, tracks); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, track := range tracks {
+		s.events.Publish(ctx, pkgdomain.MetadataEnriched{Track: track.Clone(), At: now})
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+)
+
+// labelConfigTTL is how long the AI configuration of a label is used before
+// it is read again, so changes made through other instances apply within it
+const labelConfigTTL = time.Minute
+
+// labelService enriches the tracks of a label with its own configuration.
+// A nil service means the label uses the global configuration.
+type labelService struct {
+	provider pkgdomain.AIProvider
+	service  pkgdomain.AIService
+	// scope tells apart the cached results of the label's configuration
+	scope  string
+	loaded time.Time
+}
+
+// SetLabelConfigs has enrichments of tracks of labels with a configuration
+// in configs use it rather than the global one. It must be called before
+// use.
+func (s *CompositeAIService) SetLabelConfigs(configs pkgdomain.LabelAIConfigRepository) {
+	s.labelConfigs = configs
+}
+
+// InvalidateLabel drops the cached AI configuration of a label, so the next
+// enrichment of its tracks reads it again
+func (s *CompositeAIService) InvalidateLabel(labelID string) {
+	s.labelMu.Lock()
+	defer s.labelMu.Unlock()
+	delete(s.labels, labelID)
+}
+
+// CacheScope returns what tells apart the cached enrichments of a track
+// made with its label's configuration, or "" when the label uses the global
+// one
+func (s *CompositeAIService) CacheScope(ctx context.Context, track *pkgdomain.Track) string {
+	label, err := s.labelService(ctx, track.LabelID)
+	if err != nil || label.service == nil {
+		return ""
+	}
+	return label.scope
+}
+
+// labelService returns the service of a label, building it when its
+// configuration was not read within labelConfigTTL
+func (s *CompositeAIService) labelService(ctx context.Context, labelID string) (*labelService, error) {
+	if s.labelConfigs == nil || labelID == "" {
+		return &labelService{}, nil
+	}
+
+	s.labelMu.Lock()
+	label, ok := s.labels[labelID]
+	s.labelMu.Unlock()
+	if ok && time.Since(label.loaded) < labelConfigTTL {
+		return label, nil
+	}
+
+	label = &labelService{loaded: time.Now()}
+	config, err := s.labelConfigs.Get(ctx, labelID)
+	switch {
+	case errors.Is(err, pkgdomain.ErrLabelAIConfigNotFound):
+	case err != nil:
+		// Enriching with the global key instead would bill the platform
+		// for a label that brings its own
+		return nil, fmt.Errorf("failed to get AI config of label %s: %w", labelID, err)
+	default:
+		label.provider = config.Provider
+		label.scope = "label-" + labelID + "-" + strconv.FormatInt(config.UpdatedAt.Unix(), 10)
+		if label.service, err = s.newLabelService(config); err != nil {
+			return nil, fmt.Errorf("failed to configure AI for label %s: %w", labelID, err)
+		}
+	}
+	s.labelMu.Lock()
+	s.labels[labelID] = label
+	s.labelMu.Unlock()
+	return label, nil
+}
+
+// newLabelService creates a provider service from the global configuration
+// of the provider with the label's overrides applied
+func (s *CompositeAIService) newLabelService(config *pkgdomain.LabelAIConfig) (pkgdomain.AIService, error) {
+	var service pkgdomain.AIService
+	var err error
+	switch config.Provider {
+	case pkgdomain.AIProviderOpenAI:
+		openAIConfig := *s.config.OpenAIConfig
+		if config.APIKey != "" {
+			openAIConfig.APIKey = config.APIKey
+		}
+		if config.Model != "" {
+			// The label's model is sent as is, snapshot date included
+			openAIConfig.Model = config.Model
+			openAIConfig.ModelVersion = "latest"
+		}
+		if config.MinConfidence != nil {
+			openAIConfig.MinConfidence = *config.MinConfidence
+		}
+		service, err = NewOpenAIService(&openAIConfig)
+	case pkgdomain.AIProviderQwen2:
+		qwen2Config := *s.config.Qwen2Config
+		if config.APIKey != "" {
+			qwen2Config.APIKey = config.APIKey
+		}
+		if config.Model != "" {
+			qwen2Config.Model = config.Model
+		}
+		if config.MinConfidence != nil {
+			qwen2Config.MinConfidence = *config.MinConfidence
+		}
+		service, err = NewQwen2Service(&qwen2Config)
+	default:
+		return nil, fmt.Errorf("unknown AI provider %q", config.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if setter, ok := service.(interface{ SetAudioFeatureSource(AudioFeatureSource) }); ok && s.features != nil {
+		setter.SetAudioFeatureSource(s.features)
+	}
+	return service, nil
+}
+
+// callLabel calls fn with the service of a label, within its provider's
+// limits. Failures are not retried with another provider: the label chose
+// its provider.
+func (s *CompositeAIService) callLabel(ctx context.Context, label *labelService, fn func(pkgdomain.AIService) error) error {
+	return s.limited(ctx, label.provider, func() error {
+		return fn(label.service)
+	})
+}
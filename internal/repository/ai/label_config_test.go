@@ -0,0 +1,92 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// labelConfigs is a label AI config repository counting its reads
+type labelConfigs struct {
+	configs map[string]*pkgdomain.LabelAIConfig
+	err     error
+	gets    int
+}
+
+func (r *labelConfigs) Save(context.Context, *pkgdomain.LabelAIConfig) error { return nil }
+
+func (r *labelConfigs) Get(_ context.Context, labelID string) (*pkgdomain.LabelAIConfig, error) {
+	r.gets++
+	if r.err != nil {
+		return nil, r.err
+	}
+	config, ok := r.configs[labelID]
+	if !ok {
+		return nil, pkgdomain.ErrLabelAIConfigNotFound
+	}
+	return config, nil
+}
+
+func (r *labelConfigs) List(context.Context) ([]*pkgdomain.LabelAIConfig, error) { return nil, nil }
+
+func (r *labelConfigs) Delete(context.Context, string) error { return nil }
+
+func TestCompositeAIService_LabelConfigOverridesGlobal(t *testing.T) {
+	service, qwen2, openAI := newRoutedService(pkgdomain.AIRoutingExperiment, true)
+	service.labels = map[string]*labelService{}
+	service.config.OpenAIConfig = &pkgdomain.OpenAIConfig{APIKey: "sk-platform", Model: "gpt-4", ModelVersion: "v1", MinConfidence: 0.8}
+	minConfidence := 0.6
+	updated := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	configs := &labelConfigs{configs: map[string]*pkgdomain.LabelAIConfig{"acme": {
+		LabelID: "acme", Provider: pkgdomain.AIProviderOpenAI, APIKey: "sk-acme",
+		Model: "gpt-4o-2024-08-06", MinConfidence: &minConfidence, UpdatedAt: updated,
+	}}}
+	service.SetLabelConfigs(configs)
+	ctx := context.Background()
+
+	label, err := service.labelService(ctx, "acme")
+	require.NoError(t, err)
+	require.IsType(t, &OpenAIService{}, label.service)
+	config := label.service.(*OpenAIService).config
+	assert.Equal(t, "sk-acme", config.APIKey)
+	assert.Equal(t, "gpt-4o-2024-08-06", config.Model)
+	assert.Equal(t, "latest", config.ModelVersion)
+	assert.Equal(t, 0.6, config.MinConfidence)
+	assert.Equal(t, "sk-platform", service.config.OpenAIConfig.APIKey, "the global config is left alone")
+	assert.Equal(t, "label-acme-1767323045", service.CacheScope(ctx, &pkgdomain.Track{LabelID: "acme"}))
+
+	// The label's service is used without fallback, whatever the routing
+	labelAI := &probedService{err: errors.New("invalid api key")}
+	label.service = labelAI
+	require.NoError(t, service.ForceProvider(pkgdomain.AIProviderQwen2))
+	assert.ErrorContains(t, service.EnrichMetadata(ctx, &pkgdomain.Track{ID: "track-1", LabelID: "acme"}), "invalid api key")
+	assert.Equal(t, 1, labelAI.calls)
+	assert.Equal(t, 0, qwen2.calls+openAI.calls)
+	assert.Equal(t, 1, configs.gets, "read once within the TTL")
+
+	// Other labels use the global configuration
+	require.NoError(t, service.EnrichMetadata(ctx, &pkgdomain.Track{ID: "track-2", LabelID: "other"}))
+	assert.Equal(t, 1, qwen2.calls)
+	assert.Empty(t, service.CacheScope(ctx, &pkgdomain.Track{LabelID: "other"}))
+
+	service.InvalidateLabel("acme")
+	_, err = service.labelService(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, 3, configs.gets)
+}
+
+func TestCompositeAIService_LabelConfigErrorsDoNotFallBackToGlobal(t *testing.T) {
+	service, qwen2, openAI := newRoutedService(pkgdomain.AIRoutingExperiment, true)
+	service.labels = map[string]*labelService{}
+	service.SetLabelConfigs(&labelConfigs{err: errors.New("connection refused")})
+
+	err := service.EnrichMetadata(context.Background(), &pkgdomain.Track{ID: "track-1", LabelID: "acme"})
+	assert.ErrorContains(t, err, "connection refused")
+	assert.Equal(t, 0, qwen2.calls+openAI.calls)
+}
@@ -0,0 +1,162 @@
+package ai
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+
+	"golang.org/x/time/rate"
+)
+
+// ProviderLimiter bounds the requests in flight to one AI provider and the
+// rate they are sent at.
+//
+// Requests that find every slot taken wait in a queue per tenant, and freed
+// slots go to the tenants in turn, so a tenant sending a large batch cannot
+// starve the others. A limit of zero disables that limit.
+type ProviderLimiter struct {
+	provider string
+	max      int
+	limiter  *rate.Limiter
+
+	mu       sync.Mutex
+	inFlight int
+	queues   map[string][]chan struct{}
+	order    []string // tenants with waiting requests, next to be served first
+	depth    int
+}
+
+// NewProviderLimiter creates a limiter allowing maxConcurrent requests in
+// flight and requestsPerSecond requests per second
+func NewProviderLimiter(provider pkgdomain.AIProvider, maxConcurrent, requestsPerSecond int) *ProviderLimiter {
+	l := &ProviderLimiter{
+		provider: string(provider),
+		max:      maxConcurrent,
+		queues:   make(map[string][]chan struct{}),
+	}
+	if requestsPerSecond > 0 {
+		l.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), 1)
+	}
+	metrics.AIQueueDepth.WithLabelValues(l.provider).Set(0)
+	metrics.AIRequestsInFlight.WithLabelValues(l.provider).Set(0)
+	return l
+}
+
+// Acquire waits for a slot and the rate limit. On success the caller must
+// call release once the provider has answered.
+func (l *ProviderLimiter) Acquire(ctx context.Context) (release func(), err error) {
+	start := time.Now()
+	if err := l.acquireSlot(ctx); err != nil {
+		return nil, err
+	}
+	if l.limiter != nil {
+		if err := l.limiter.Wait(ctx); err != nil {
+			l.release()
+			return nil, err
+		}
+	}
+	metrics.AIQueueWait.WithLabelValues(l.provider).Observe(time.Since(start).Seconds())
+
+	var once sync.Once
+	return func() { once.Do(l.release) }, nil
+}
+
+// QueueDepth returns the number of requests waiting for a slot
+func (l *ProviderLimiter) QueueDepth() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.depth
+}
+
+func (l *ProviderLimiter) acquireSlot(ctx context.Context) error {
+	l.mu.Lock()
+	if l.max <= 0 || (l.inFlight < l.max && l.depth == 0) {
+		l.inFlight++
+		l.mu.Unlock()
+		metrics.AIRequestsInFlight.WithLabelValues(l.provider).Inc()
+		return nil
+	}
+
+	tenant := pkgdomain.TenantFromContext(ctx)
+	ready := make(chan struct{})
+	if len(l.queues[tenant]) == 0 {
+		l.order = append(l.order, tenant)
+	}
+	l.queues[tenant] = append(l.queues[tenant], ready)
+	l.setDepth(l.depth + 1)
+	l.mu.Unlock()
+
+	select {
+	case <-ready:
+		// release handed its slot over to this request
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		removed := l.remove(tenant, ready)
+		l.mu.Unlock()
+		if !removed {
+			// The slot was handed over while giving up; pass it on
+			l.release()
+		}
+		return ctx.Err()
+	}
+}
+
+// release frees a slot or hands it to the next tenant in turn
+func (l *ProviderLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.order) == 0 {
+		l.inFlight--
+		metrics.AIRequestsInFlight.WithLabelValues(l.provider).Dec()
+		return
+	}
+
+	tenant := l.order[0]
+	queue := l.queues[tenant]
+	next := queue[0]
+	if len(queue) == 1 {
+		delete(l.queues, tenant)
+		l.order = l.order[1:]
+	} else {
+		l.queues[tenant] = queue[1:]
+		l.order = append(l.order[1:], tenant)
+	}
+	l.setDepth(l.depth - 1)
+	close(next)
+}
+
+// remove drops a waiting request from its tenant's queue and reports whether
+// it was still waiting
+func (l *ProviderLimiter) remove(tenant string, ready chan struct{}) bool {
+	queue := l.queues[tenant]
+	for i, waiting := range queue {
+		if waiting != ready {
+			continue
+		}
+		queue = append(queue[:i:i], queue[i+1:]...)
+		if len(queue) == 0 {
+			delete(l.queues, tenant)
+			for j, t := range l.order {
+				if t == tenant {
+					l.order = append(l.order[:j:j], l.order[j+1:]...)
+					break
+				}
+			}
+		} else {
+			l.queues[tenant] = queue
+		}
+		l.setDepth(l.depth - 1)
+		return true
+	}
+	return false
+}
+
+func (l *ProviderLimiter) setDepth(depth int) {
+	l.depth = depth
+	metrics.AIQueueDepth.WithLabelValues(l.provider).Set(float64(depth))
+}
@@ -0,0 +1,82 @@
+package ai
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderLimiter_ServesTenantsInTurn(t *testing.T) {
+	l := NewProviderLimiter(pkgdomain.AIProviderQwen2, 1, 0)
+	ctx := context.Background()
+
+	release, err := l.Acquire(ctx)
+	require.NoError(t, err)
+
+	// Tenant a queues three requests before tenant b queues one
+	var mu sync.Mutex
+	var served []string
+	var wg sync.WaitGroup
+	enqueue := func(tenant string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := l.Acquire(pkgdomain.WithTenant(ctx, tenant))
+			if !assert.NoError(t, err) {
+				return
+			}
+			mu.Lock()
+			served = append(served, tenant)
+			mu.Unlock()
+			release()
+		}()
+	}
+	for _, tenant := range []string{"a", "a", "a", "b"} {
+		before := l.QueueDepth()
+		enqueue(tenant)
+		require.Eventually(t, func() bool { return l.QueueDepth() == before+1 }, time.Second, time.Millisecond)
+	}
+
+	release()
+	wg.Wait()
+	assert.Equal(t, []string{"a", "b", "a", "a"}, served)
+	assert.Equal(t, 0, l.QueueDepth())
+}
+
+func TestProviderLimiter_CancelledWaitLeavesQueue(t *testing.T) {
+	l := NewProviderLimiter(pkgdomain.AIProviderOpenAI, 1, 0)
+
+	release, err := l.Acquire(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = l.Acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, l.QueueDepth())
+
+	// The slot is still usable once released
+	release()
+	release, err = l.Acquire(context.Background())
+	require.NoError(t, err)
+	release()
+}
+
+func TestProviderLimiter_EnforcesRequestsPerSecond(t *testing.T) {
+	l := NewProviderLimiter(pkgdomain.AIProviderOpenAI, 0, 20)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		release, err := l.Acquire(context.Background())
+		require.NoError(t, err)
+		release()
+	}
+	// The first request passes at once and the next two wait 50ms each
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
@@ -0,0 +1,51 @@
+package ai
+
+import (
+	"context"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+)
+
+// MeteredAIService counts successful enrichments per label for billing.
+// Enrichments answered from the cache are counted as well, since the label
+// requested them.
+type MeteredAIService struct {
+	delegate pkgdomain.AIService
+	usage    pkgdomain.UsageRecorder
+}
+
+// NewMeteredAIService creates an AI service that records usage
+func NewMeteredAIService(delegate pkgdomain.AIService, usage pkgdomain.UsageRecorder) *MeteredAIService {
+	return &MeteredAIService{
+		delegate: delegate,
+		usage:    usage,
+	}
+}
+
+// EnrichMetadata enriches the track and counts the enrichment
+func (s *MeteredAIService) EnrichMetadata(ctx context.Context, track *pkgdomain.Track) error {
+	if err := s.delegate.EnrichMetadata(ctx, track); err != nil {
+		return err
+	}
+
+	s.usage.RecordUsage(ctx, track.LabelID, pkgdomain.UsageAIEnrichments, 1)
+	return nil
+}
+
+// ValidateMetadata delegates validation unchanged
+func (s *MeteredAIService) ValidateMetadata(ctx context.Context, track *pkgdomain.Track) (float64, error) {
+	return s.delegate.ValidateMetadata(ctx, track)
+}
+
+// BatchProcess enriches the tracks and counts them per label. A failed batch
+// is not counted.
+func (s *MeteredAIService) BatchProcess(ctx context.Context, tracks []*pkgdomain.Track) error {
+	if err := s.delegate.BatchProcess(ctx, tracks); err != nil {
+		return err
+	}
+
+	for labelID, n := range pkgdomain.TracksPerLabel(tracks) {
+		s.usage.RecordUsage(ctx, labelID, pkgdomain.UsageAIEnrichments, n)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package ai
+
+import (
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Prompt versions are recorded with each enrichment. Bump one whenever its
+// prompt changes, so tracks enriched with the old prompt are reported as
+// outdated and can be enriched again.
+const (
+	qwen2AudioPrompt    = "audio-v1"
+	qwen2FeaturePrompt  = "audio-features-v1"
+	openAITextPrompt    = "text-v1"
+	openAIFeaturePrompt = "features-v1"
+)
+
+const (
+	defaultQwen2Model        = "qwen2"
+	defaultQwen2ModelVersion = "v1"
+	defaultOpenAIModel       = openai.GPT4oMini
+)
+
+// qwen2ModelVersion returns the model version Qwen2 enrichments with a
+// prompt are recorded with
+func qwen2ModelVersion(config *pkgdomain.Qwen2Config, prompt string) pkgdomain.AIModelVersion {
+	version := pkgdomain.AIModelVersion{
+		Provider:      pkgdomain.AIProviderQwen2,
+		Model:         config.Model,
+		Version:       config.ModelVersion,
+		PromptVersion: prompt,
+	}
+	if version.Model == "" {
+		version.Model = defaultQwen2Model
+	}
+	if version.Version == "" {
+		version.Version = defaultQwen2ModelVersion
+	}
+	return version
+}
+
+// openAIModelVersion returns the model version OpenAI enrichments with a
+// prompt are recorded with
+func openAIModelVersion(config *pkgdomain.OpenAIConfig, prompt string) pkgdomain.AIModelVersion {
+	version := pkgdomain.AIModelVersion{
+		Provider:      pkgdomain.AIProviderOpenAI,
+		Model:         config.Model,
+		Version:       config.ModelVersion,
+		PromptVersion: prompt,
+	}
+	if version.Model == "" {
+		version.Model = defaultOpenAIModel
+	}
+	return version
+}
+
+// openAIRequestModel returns the model name sent to OpenAI. A version other
+// than "latest" pins the dated snapshot of the model, such as
+// gpt-4o-mini-2024-07-18.
+func openAIRequestModel(version pkgdomain.AIModelVersion) string {
+	if version.Version == "" || version.Version == "latest" {
+		return version.Model
+	}
+	return version.Model + "-" + version.Version
+}
@@ -0,0 +1,167 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// batchLineSeparator separates the track ID from the prompt version in the
+// custom ID of a batch request, so each result is recorded with the prompt
+// it was made with
+const batchLineSeparator = "|"
+
+// batchResultLine is a line of a batch output or error file
+type batchResultLine struct {
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int                           `json:"status_code"`
+		Body       openai.ChatCompletionResponse `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// SubmitBatch uploads a chat request per track to the OpenAI batch API.
+// Tracks are classified from their text metadata, and from their audio
+// features as well when AudioFeatures is on and they have been analyzed.
+func (s *OpenAIService) SubmitBatch(ctx context.Context, tracks []*pkgdomain.Track) (string, error) {
+	upload := openai.UploadBatchFileRequest{FileName: "enrichment.jsonl"}
+	for _, track := range tracks {
+		var analysis *pkgdomain.AudioAnalysis
+		if s.config.AudioFeatures {
+			analysis = audioFeatures(ctx, s.features, track)
+		}
+		req, version := s.enrichmentRequest(track, analysis)
+		upload.AddChatCompletion(track.ID+batchLineSeparator+version.PromptVersion, req)
+	}
+
+	file, err := s.client.UploadBatchFile(ctx, upload)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload batch: %w", err)
+	}
+	batch, err := s.client.CreateBatch(ctx, openai.CreateBatchRequest{
+		InputFileID: file.ID,
+		Endpoint:    openai.BatchEndpointChatCompletions,
+		Metadata:    map[string]any{"source": "metadatatool"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create batch: %w", err)
+	}
+	return batch.ID, nil
+}
+
+// CheckBatch returns the state of an OpenAI batch
+func (s *OpenAIService) CheckBatch(ctx context.Context, batchID string) (*pkgdomain.AIBatchProgress, error) {
+	batch, err := s.client.RetrieveBatch(ctx, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch: %w", err)
+	}
+
+	progress := &pkgdomain.AIBatchProgress{Status: pkgdomain.AIBatchPending}
+	switch batch.Status {
+	case "completed":
+		progress.Status = pkgdomain.AIBatchCompleted
+	case "expired":
+		progress.Status = pkgdomain.AIBatchExpired
+	case "cancelled":
+		progress.Status = pkgdomain.AIBatchCancelled
+	case "failed":
+		progress.Status = pkgdomain.AIBatchFailed
+		progress.Error = "batch failed"
+		if batch.Errors != nil && len(batch.Errors.Data) > 0 {
+			progress.Error = batch.Errors.Data[0].Message
+		}
+	}
+	return progress, nil
+}
+
+// BatchResults reads the output and error files of an OpenAI batch and
+// applies each answer to its track
+func (s *OpenAIService) BatchResults(ctx context.Context, batchID string, tracks []*pkgdomain.Track) (map[string]error, error) {
+	batch, err := s.client.RetrieveBatch(ctx, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch: %w", err)
+	}
+
+	byID := make(map[string]*pkgdomain.Track, len(tracks))
+	failures := make(map[string]error, len(tracks))
+	for _, track := range tracks {
+		byID[track.ID] = track
+		failures[track.ID] = fmt.Errorf("no result in batch")
+	}
+
+	for _, fileID := range []*string{batch.OutputFileID, batch.ErrorFileID} {
+		if fileID == nil || *fileID == "" {
+			continue
+		}
+		if err := s.readBatchFile(ctx, *fileID, func(line batchResultLine) {
+			trackID, prompt, _ := strings.Cut(line.CustomID, batchLineSeparator)
+			track, ok := byID[trackID]
+			if !ok {
+				return
+			}
+			switch {
+			case line.Error != nil:
+				failures[trackID] = fmt.Errorf("%s: %s", line.Error.Code, line.Error.Message)
+			case line.Response == nil:
+				failures[trackID] = fmt.Errorf("empty result")
+			case line.Response.StatusCode != http.StatusOK:
+				failures[trackID] = fmt.Errorf("request failed with status %d", line.Response.StatusCode)
+			default:
+				if err := s.applyEnrichment(track, line.Response.Body, openAIModelVersion(s.config, prompt)); err != nil {
+					failures[trackID] = err
+					return
+				}
+				delete(failures, trackID)
+			}
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return failures, nil
+}
+
+// CancelBatch cancels an OpenAI batch
+func (s *OpenAIService) CancelBatch(ctx context.Context, batchID string) error {
+	if _, err := s.client.CancelBatch(ctx, batchID); err != nil {
+		return fmt.Errorf("failed to cancel batch: %w", err)
+	}
+	return nil
+}
+
+// readBatchFile calls fn for every line of a batch file
+func (s *OpenAIService) readBatchFile(ctx context.Context, fileID string, fn func(batchResultLine)) error {
+	content, err := s.client.GetFileContent(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("failed to download batch file %s: %w", fileID, err)
+	}
+	defer content.Close()
+
+	scanner := bufio.NewScanner(content)
+	// Answers are small, but a line holds the whole response
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var line batchResultLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return fmt.Errorf("failed to parse batch file %s: %w", fileID, err)
+		}
+		fn(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read batch file %s: %w", fileID, err)
+	}
+	return nil
+}
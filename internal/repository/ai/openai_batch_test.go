@@ -0,0 +1,133 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBatchAPI serves the files and batches endpoints of the OpenAI API,
+// answering every request of a batch with answer unless it is listed in
+// failed
+type fakeBatchAPI struct {
+	t      *testing.T
+	input  []openai.BatchChatCompletionRequest
+	status string
+	answer featureEnrichment
+	failed map[string]bool
+}
+
+func (f *fakeBatchAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/files":
+		file, _, err := r.FormFile("file")
+		require.NoError(f.t, err)
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var line openai.BatchChatCompletionRequest
+			require.NoError(f.t, json.Unmarshal(scanner.Bytes(), &line))
+			f.input = append(f.input, line)
+		}
+		_ = json.NewEncoder(w).Encode(openai.File{ID: "file-input"})
+	case r.Method == http.MethodPost && r.URL.Path == "/batches":
+		_ = json.NewEncoder(w).Encode(openai.Batch{ID: "batch-1", Status: "validating"})
+	case r.Method == http.MethodGet && r.URL.Path == "/batches/batch-1":
+		output, errors := "file-output", "file-errors"
+		_ = json.NewEncoder(w).Encode(openai.Batch{ID: "batch-1", Status: f.status, OutputFileID: &output, ErrorFileID: &errors})
+	case r.Method == http.MethodGet && r.URL.Path == "/files/file-output/content":
+		for _, line := range f.input {
+			if f.failed[line.CustomID] {
+				continue
+			}
+			content, _ := json.Marshal(f.answer)
+			body := openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{
+				{Message: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: string(content)}},
+			}}
+			out, _ := json.Marshal(map[string]any{
+				"custom_id": line.CustomID,
+				"response":  map[string]any{"status_code": 200, "body": body},
+			})
+			fmt.Fprintf(w, "%s\n", out)
+		}
+	case r.Method == http.MethodGet && r.URL.Path == "/files/file-errors/content":
+		for _, line := range f.input {
+			if f.failed[line.CustomID] {
+				out, _ := json.Marshal(map[string]any{
+					"custom_id": line.CustomID,
+					"error":     map[string]any{"code": "rate_limit_exceeded", "message": "too many tokens"},
+				})
+				fmt.Fprintf(w, "%s\n", out)
+			}
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestOpenAIService_Batch(t *testing.T) {
+	api := &fakeBatchAPI{
+		t:      t,
+		status: "in_progress",
+		answer: featureEnrichment{Genre: "Techno", Mood: "Driving", Tags: []string{"peak time"}, Confidence: 0.9},
+		failed: map[string]bool{"plain|" + openAITextPrompt: true},
+	}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	service, err := NewOpenAIService(&pkgdomain.OpenAIConfig{
+		APIKey:        "test-key",
+		Endpoint:      server.URL,
+		MinConfidence: 0.85,
+		AudioFeatures: true,
+	})
+	require.NoError(t, err)
+	openAI := service.(*OpenAIService)
+	openAI.SetAudioFeatureSource(memFeatureSource{"analyzed": analyzedMix()})
+	ctx := context.Background()
+
+	analyzed := &pkgdomain.Track{ID: "analyzed"}
+	analyzed.SetTitle("Night Drive")
+	plain := &pkgdomain.Track{ID: "plain"}
+	plain.SetTitle("Morning")
+
+	batchID, err := openAI.SubmitBatch(ctx, []*pkgdomain.Track{analyzed, plain})
+	require.NoError(t, err)
+	assert.Equal(t, "batch-1", batchID)
+	require.Len(t, api.input, 2)
+	assert.Equal(t, "analyzed|"+openAIFeaturePrompt, api.input[0].CustomID)
+	assert.Equal(t, openai.BatchEndpointChatCompletions, api.input[0].URL)
+	assert.Contains(t, api.input[0].Body.Messages[1].Content, "- Tempo: 128.0 BPM\n")
+	assert.NotContains(t, api.input[1].Body.Messages[1].Content, "Tempo", "plain has not been analyzed")
+
+	progress, err := openAI.CheckBatch(ctx, batchID)
+	require.NoError(t, err)
+	assert.Equal(t, pkgdomain.AIBatchPending, progress.Status)
+
+	api.status = "completed"
+	progress, err = openAI.CheckBatch(ctx, batchID)
+	require.NoError(t, err)
+	assert.Equal(t, pkgdomain.AIBatchCompleted, progress.Status)
+
+	missing := &pkgdomain.Track{ID: "missing"}
+	failures, err := openAI.BatchResults(ctx, batchID, []*pkgdomain.Track{analyzed, plain, missing})
+	require.NoError(t, err)
+	require.Len(t, failures, 2)
+	assert.ErrorContains(t, failures["plain"], "too many tokens")
+	assert.EqualError(t, failures["missing"], "no result in batch")
+
+	assert.Equal(t, "Techno", analyzed.Genre())
+	require.NotNil(t, analyzed.Metadata.AI)
+	assert.Equal(t, openAIFeaturePrompt, analyzed.Metadata.AI.PromptVersion)
+	assert.Equal(t, defaultOpenAIModel, analyzed.Metadata.AI.Model)
+	assert.Nil(t, plain.Metadata.AI)
+}
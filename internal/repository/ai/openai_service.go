@@ -2,17 +2,34 @@ package ai
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/sashabaranov/go-openai"
 )
 
+// featureSystemPrompt tells the model how to answer when prompted with
+// audio features
+const featureSystemPrompt = `You classify music for a metadata catalogue. Use the measured audio features over the text metadata where they disagree: tempo, key, energy and danceability were measured from the audio itself. Answer with a JSON object with the fields "genre" (string), "mood" (string), "tags" (array of at most 8 lowercase strings) and "confidence" (number from 0 to 1).`
+
+// featureEnrichment is the answer of the model to a feature prompt
+type featureEnrichment struct {
+	Genre      string   `json:"genre"`
+	Mood       string   `json:"mood"`
+	Tags       []string `json:"tags"`
+	Confidence float64  `json:"confidence"`
+}
+
 // OpenAIService implements pkg/domain.AIService interface
 type OpenAIService struct {
-	client *openai.Client
-	config *pkgdomain.OpenAIConfig
+	client   *openai.Client
+	config   *pkgdomain.OpenAIConfig
+	features AudioFeatureSource
 }
 
 // NewOpenAIService creates a new OpenAI service
@@ -21,24 +38,54 @@ func NewOpenAIService(config *pkgdomain.OpenAIConfig) (pkgdomain.AIService, erro
 		return nil, fmt.Errorf("openai config is required")
 	}
 
-	client := openai.NewClient(config.APIKey)
+	clientConfig := openai.DefaultConfig(config.APIKey)
+	if config.Endpoint != "" {
+		clientConfig.BaseURL = config.Endpoint
+	}
 	return &OpenAIService{
-		client: client,
+		client: openai.NewClientWithConfig(clientConfig),
 		config: config,
 	}, nil
 }
 
-// EnrichMetadata enriches track metadata using OpenAI
+// SetAudioFeatureSource sets where the audio analyses put in the prompt
+// are read from when AudioFeatures is on. It must be called before use.
+func (s *OpenAIService) SetAudioFeatureSource(source AudioFeatureSource) {
+	s.features = source
+}
+
+// ModelVersions returns the model versions OpenAI enriches with: from
+// audio features when AudioFeatures is on and the track has been analyzed,
+// and from its text metadata otherwise
+func (s *OpenAIService) ModelVersions() []pkgdomain.AIModelVersion {
+	versions := []pkgdomain.AIModelVersion{openAIModelVersion(s.config, openAITextPrompt)}
+	if s.config.AudioFeatures {
+		versions = append(versions, openAIModelVersion(s.config, openAIFeaturePrompt))
+	}
+	return versions
+}
+
+// EnrichMetadata enriches track metadata using OpenAI. With AudioFeatures
+// on, tracks whose audio has been analyzed are classified from their
+// measured features as well as their text metadata.
 func (s *OpenAIService) EnrichMetadata(ctx context.Context, track *pkgdomain.Track) error {
+	if s.config.AudioFeatures {
+		if analysis := audioFeatures(ctx, s.features, track); analysis != nil {
+			return s.enrichFromFeatures(ctx, track, analysis)
+		}
+	}
+
 	// TODO: Implement OpenAI metadata enrichment
 	// This is a placeholder implementation
 	if track.Metadata.AI == nil {
+		version := openAIModelVersion(s.config, openAITextPrompt)
 		track.Metadata.AI = &pkgdomain.TrackAIMetadata{
-			Model:       "openai",
-			Version:     "1.0",
-			ProcessedAt: time.Now(),
-			Tags:        []string{"upbeat", "summer", "dance"},
-			Confidence:  0.95,
+			Model:         version.Model,
+			Version:       version.Version,
+			PromptVersion: version.PromptVersion,
+			ProcessedAt:   time.Now(),
+			Tags:          []string{"upbeat", "summer", "dance"},
+			Confidence:    0.95,
 		}
 	}
 
@@ -57,6 +104,130 @@ func (s *OpenAIService) EnrichMetadata(ctx context.Context, track *pkgdomain.Tra
 	return nil
 }
 
+// enrichFromFeatures asks the model for the genre, mood and tags of a track
+// given its text metadata and measured audio features
+func (s *OpenAIService) enrichFromFeatures(ctx context.Context, track *pkgdomain.Track, analysis *pkgdomain.AudioAnalysis) error {
+	req, version := s.enrichmentRequest(track, analysis)
+	resp, err := s.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to enrich metadata from audio features: %w", err)
+	}
+	return s.applyEnrichment(track, resp, version)
+}
+
+// enrichmentRequest builds the chat request classifying a track from its
+// text metadata and, when analysis is not nil, its measured audio features
+func (s *OpenAIService) enrichmentRequest(track *pkgdomain.Track, analysis *pkgdomain.AudioAnalysis) (openai.ChatCompletionRequest, pkgdomain.AIModelVersion) {
+	parts := textMetadataParts(track)
+	version := openAIModelVersion(s.config, openAITextPrompt)
+	if analysis != nil {
+		parts = append(parts, audioFeatureParts(analysis)...)
+		version = openAIModelVersion(s.config, openAIFeaturePrompt)
+	}
+	prompt := renderPrompt(pkgdomain.AIProviderOpenAI, parts, promptBudget(s.config.MaxPromptTokens, featureSystemPrompt))
+	return openai.ChatCompletionRequest{
+		Model: openAIRequestModel(version),
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: featureSystemPrompt},
+			{Role: openai.ChatMessageRoleUser, Content: prompt},
+		},
+		ResponseFormat: &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject},
+	}, version
+}
+
+// applyEnrichment sets the answer of the model to an enrichment request on
+// a track
+func (s *OpenAIService) applyEnrichment(track *pkgdomain.Track, resp openai.ChatCompletionResponse, version pkgdomain.AIModelVersion) error {
+	if len(resp.Choices) == 0 {
+		return fmt.Errorf("failed to enrich metadata: empty response")
+	}
+
+	var result featureEnrichment
+	if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &result); err != nil {
+		return fmt.Errorf("failed to parse enrichment: %w", err)
+	}
+
+	ai := &pkgdomain.TrackAIMetadata{
+		Model:         version.Model,
+		Version:       version.Version,
+		PromptVersion: version.PromptVersion,
+		ProcessedAt:   time.Now(),
+		Tags:          result.Tags,
+		Confidence:    result.Confidence,
+	}
+	if result.Confidence < s.config.MinConfidence {
+		ai.NeedsReview = true
+		ai.ReviewReason = fmt.Sprintf("Low confidence score: %.2f", result.Confidence)
+	}
+	track.Metadata.AI = ai
+	if result.Genre != "" {
+		track.SetGenre(result.Genre)
+	}
+	if result.Mood != "" {
+		track.SetMood(result.Mood)
+	}
+	metrics.AIConfidenceScore.WithLabelValues(string(pkgdomain.AIProviderOpenAI)).Observe(result.Confidence)
+	return nil
+}
+
+// textMetadataPrompt describes the text metadata of a track for a model,
+// leaving out the fields that are not set
+func textMetadataPrompt(track *pkgdomain.Track) string {
+	fitted, _ := fitPrompt(textMetadataParts(track), 0)
+	return joinPrompt(fitted)
+}
+
+// textMetadataParts splits the text metadata prompt of a track into its
+// core fields and its custom fields. Custom fields come last in key order,
+// are capped one by one and are the first cut when the prompt is over
+// budget.
+func textMetadataParts(track *pkgdomain.Track) []promptPart {
+	metadata := promptPart{name: "metadata", header: "Track metadata:", priority: promptPriorityMetadata}
+	for _, field := range []struct{ name, value string }{
+		{"Title", track.Title()},
+		{"Artist", track.Artist()},
+		{"Album", track.Album()},
+		{"Genre", track.Genre()},
+		{"Mood", track.Mood()},
+	} {
+		if field.value != "" {
+			metadata.lines = append(metadata.lines, fmt.Sprintf("- %s: %s", field.name, field.value))
+		}
+	}
+	if track.Year() > 0 {
+		metadata.lines = append(metadata.lines, fmt.Sprintf("- Year: %d", track.Year()))
+	}
+
+	custom := promptPart{
+		name:          "custom_fields",
+		header:        "Custom fields:",
+		priority:      promptPriorityCustomFields,
+		maxLineTokens: maxCustomFieldTokens,
+	}
+	fields := track.Metadata.Additional.CustomFields
+	keys := make([]string, 0, len(fields))
+	for key, value := range fields {
+		if strings.TrimSpace(value) != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		custom.lines = append(custom.lines, fmt.Sprintf("- %s: %s", key, strings.Join(strings.Fields(fields[key]), " ")))
+	}
+	return []promptPart{metadata, custom}
+}
+
+// HealthCheck probes OpenAI by looking up the model enrichments are sent
+// to, which costs no tokens
+func (s *OpenAIService) HealthCheck(ctx context.Context) error {
+	model := openAIRequestModel(openAIModelVersion(s.config, openAITextPrompt))
+	if _, err := s.client.GetModel(ctx, model); err != nil {
+		return fmt.Errorf("failed to get model %s: %w", model, err)
+	}
+	return nil
+}
+
 // ValidateMetadata validates track metadata using OpenAI
 func (s *OpenAIService) ValidateMetadata(ctx context.Context, track *pkgdomain.Track) (float64, error) {
 	// TODO: Implement OpenAI metadata validation
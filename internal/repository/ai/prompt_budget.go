@@ -0,0 +1,176 @@
+package ai
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+)
+
+// Priorities of the parts of a prompt. When a prompt is over its token
+// budget, the lines of the lowest priority parts are cut first.
+const (
+	promptPriorityCustomFields = iota + 1
+	promptPrioritySections
+	promptPriorityFeatures
+	promptPriorityMetadata
+)
+
+// maxCustomFieldTokens caps each custom field in a prompt, so one long
+// field does not crowd out the others
+const maxCustomFieldTokens = 100
+
+// truncationMark ends a line cut to fit the budget
+const truncationMark = "…"
+
+// promptPart is a section of a prompt, such as the text metadata of a track
+// or its measured audio features
+type promptPart struct {
+	// name labels the truncation metrics
+	name string
+	// header is written before the lines, and left out with them when
+	// every line is cut
+	header   string
+	lines    []string
+	priority int
+	// maxLineTokens caps each line; zero leaves lines whole
+	maxLineTokens int
+}
+
+// estimateTokens estimates the number of tokens a model counts in s. English
+// text averages four characters a token; other scripts are counted a token
+// a character, which overestimates rather than overflows.
+func estimateTokens(s string) int {
+	ascii, other := 0, 0
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// truncateTokens cuts s to about tokens tokens, marking the cut
+func truncateTokens(s string, tokens int) string {
+	if estimateTokens(s) <= tokens {
+		return s
+	}
+	budget := tokens - estimateTokens(truncationMark)
+	var b strings.Builder
+	for _, r := range s {
+		if estimateTokens(b.String()+string(r)) > budget {
+			break
+		}
+		b.WriteRune(r)
+	}
+	return strings.TrimRight(b.String(), " ") + truncationMark
+}
+
+// renderPrompt fits the parts into budget tokens and joins them, counting
+// the prompt tokens and each cut part in the metrics of provider
+func renderPrompt(provider pkgdomain.AIProvider, parts []promptPart, budget int) string {
+	parts, truncated := fitPrompt(parts, budget)
+	prompt := joinPrompt(parts)
+	metrics.AIPromptTokens.WithLabelValues(string(provider)).Observe(float64(estimateTokens(prompt)))
+	for _, name := range truncated {
+		metrics.AIPromptTruncations.WithLabelValues(string(provider), name).Inc()
+	}
+	return prompt
+}
+
+// fitPrompt caps the lines of the parts and cuts them down to budget
+// tokens, returning the cut parts and the names of the parts it cut. Lines
+// are cut from the end of the lowest priority parts first, and among parts
+// of equal priority from the later part first. A budget of zero or less
+// fits everything.
+func fitPrompt(parts []promptPart, budget int) ([]promptPart, []string) {
+	parts = append([]promptPart(nil), parts...)
+	cut := make([]bool, len(parts))
+
+	for i := range parts {
+		part := &parts[i]
+		part.lines = append([]string(nil), part.lines...)
+		for j, line := range part.lines {
+			if part.maxLineTokens > 0 && estimateTokens(line) > part.maxLineTokens {
+				part.lines[j] = truncateTokens(line, part.maxLineTokens)
+				cut[i] = true
+			}
+		}
+	}
+
+	total := estimateTokens(joinPrompt(parts))
+	if budget > 0 && total > budget {
+		order := make([]int, len(parts))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			if parts[order[a]].priority != parts[order[b]].priority {
+				return parts[order[a]].priority < parts[order[b]].priority
+			}
+			return order[a] > order[b]
+		})
+		for _, i := range order {
+			part := &parts[i]
+			for total > budget && len(part.lines) > 0 {
+				cut[i] = true
+				last := len(part.lines) - 1
+				// The last line left of a part is shortened rather than
+				// dropped when that is enough
+				keep := estimateTokens(part.lines[last]) - (total - budget)
+				if last == 0 && keep > estimateTokens(truncationMark) {
+					part.lines[last] = truncateTokens(part.lines[last], keep)
+				} else {
+					part.lines = part.lines[:last]
+				}
+				total = estimateTokens(joinPrompt(parts))
+			}
+			if total <= budget {
+				break
+			}
+		}
+	}
+
+	var truncated []string
+	for i, part := range parts {
+		if cut[i] {
+			truncated = append(truncated, part.name)
+		}
+	}
+	return parts, truncated
+}
+
+// joinPrompt joins the parts in order, leaving out the parts without lines
+func joinPrompt(parts []promptPart) string {
+	var b strings.Builder
+	for _, part := range parts {
+		if len(part.lines) == 0 {
+			continue
+		}
+		if part.header != "" {
+			b.WriteString(part.header + "\n")
+		}
+		for _, line := range part.lines {
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// promptBudget returns the tokens left for a prompt of at most maxTokens
+// tokens once the fixed instructions sent along are counted. A maxTokens
+// of zero or less leaves the prompt unlimited.
+func promptBudget(maxTokens int, instructions ...string) int {
+	if maxTokens <= 0 {
+		return 0
+	}
+	for _, s := range instructions {
+		maxTokens -= estimateTokens(s)
+	}
+	// Instructions longer than the budget leave room for a line at least
+	return max(maxTokens, 1)
+}
@@ -0,0 +1,112 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, estimateTokens(""))
+	assert.Equal(t, 1, estimateTokens("abc"))
+	assert.Equal(t, 5, estimateTokens("Deep house, 124 BPM"))
+	assert.Equal(t, 3, estimateTokens("東京夜"), "other scripts count a token a character")
+}
+
+func TestTruncateTokens(t *testing.T) {
+	assert.Equal(t, "short", truncateTokens("short", 10))
+
+	cut := truncateTokens(strings.Repeat("word ", 100), 10)
+	assert.True(t, strings.HasSuffix(cut, truncationMark))
+	assert.LessOrEqual(t, estimateTokens(cut), 10)
+}
+
+func TestFitPrompt(t *testing.T) {
+	parts := []promptPart{
+		{name: "metadata", header: "Track metadata:", priority: promptPriorityMetadata, lines: []string{"- Title: Night Drive", "- Artist: Example"}},
+		{name: "custom_fields", header: "Custom fields:", priority: promptPriorityCustomFields, lines: []string{"- credits: " + strings.Repeat("a", 200), "- notes: " + strings.Repeat("b", 200)}},
+		{name: "features", header: "Measured audio features:", priority: promptPriorityFeatures, lines: []string{"- Tempo: 124.0 BPM", "- Energy: 0.80 (0-1)"}},
+	}
+	whole := joinPrompt(parts)
+
+	fitted, truncated := fitPrompt(parts, 0)
+	assert.Equal(t, whole, joinPrompt(fitted), "no budget fits everything")
+	assert.Empty(t, truncated)
+
+	budget := estimateTokens(whole) - 40
+	fitted, truncated = fitPrompt(parts, budget)
+	prompt := joinPrompt(fitted)
+	assert.LessOrEqual(t, estimateTokens(prompt), budget)
+	assert.Equal(t, []string{"custom_fields"}, truncated)
+	assert.Contains(t, prompt, "- credits: aaa", "the last custom field goes first")
+	assert.NotContains(t, prompt, "- notes:")
+	assert.Contains(t, prompt, "- Tempo: 124.0 BPM")
+	assert.Len(t, parts[1].lines, 2, "the parts given are left as they were")
+
+	// Tight budgets drop lower priority parts with their headers, keeping
+	// the order of the rest
+	budget = estimateTokens(joinPrompt([]promptPart{parts[0], parts[2]}))
+	fitted, truncated = fitPrompt(parts, budget)
+	prompt = joinPrompt(fitted)
+	assert.Equal(t, "Track metadata:\n- Title: Night Drive\n- Artist: Example\nMeasured audio features:\n- Tempo: 124.0 BPM\n- Energy: 0.80 (0-1)\n", prompt)
+	assert.Equal(t, []string{"custom_fields"}, truncated)
+
+	budget = estimateTokens(joinPrompt(parts[:1]))
+	fitted, truncated = fitPrompt(parts, budget)
+	assert.LessOrEqual(t, estimateTokens(joinPrompt(fitted)), budget)
+	assert.Contains(t, joinPrompt(fitted), "- Title: Night Drive", "the metadata is cut last")
+	assert.Equal(t, []string{"custom_fields", "features"}, truncated)
+}
+
+func TestFitPrompt_CapsLines(t *testing.T) {
+	parts := []promptPart{{name: "custom_fields", priority: promptPriorityCustomFields, maxLineTokens: 10, lines: []string{"- a: short", "- b: " + strings.Repeat("long ", 50)}}}
+
+	fitted, truncated := fitPrompt(parts, 0)
+	require.Len(t, fitted[0].lines, 2)
+	assert.Equal(t, "- a: short", fitted[0].lines[0])
+	assert.True(t, strings.HasSuffix(fitted[0].lines[1], truncationMark))
+	assert.LessOrEqual(t, estimateTokens(fitted[0].lines[1]), 10)
+	assert.Equal(t, []string{"custom_fields"}, truncated)
+}
+
+func TestTextMetadataParts(t *testing.T) {
+	track := &pkgdomain.Track{}
+	track.SetTitle("Night Drive")
+	track.Metadata.Additional.CustomFields = map[string]string{
+		"notes":   "mixed\n  live",
+		"credits": strings.Repeat("producer ", 200),
+		"empty":   " ",
+	}
+
+	prompt := textMetadataPrompt(track)
+	assert.True(t, strings.HasPrefix(prompt, "Track metadata:\n- Title: Night Drive\nCustom fields:\n- credits: producer"))
+	assert.Contains(t, prompt, "- notes: mixed live\n", "custom fields are kept on one line")
+	assert.NotContains(t, prompt, "empty")
+	assert.Less(t, estimateTokens(prompt), maxCustomFieldTokens+30, "long custom fields are capped")
+}
+
+func TestOpenAIService_EnrichmentRequestFitsBudget(t *testing.T) {
+	track := &pkgdomain.Track{ID: "track-1"}
+	track.SetTitle("Night Drive")
+	track.Metadata.Additional.CustomFields = map[string]string{}
+	for _, key := range []string{"credits", "lyrics", "notes", "publisher", "story"} {
+		track.Metadata.Additional.CustomFields[key] = strings.Repeat(key+" ", 80)
+	}
+	service, err := NewOpenAIService(&pkgdomain.OpenAIConfig{MaxPromptTokens: estimateTokens(featureSystemPrompt) + 150})
+	require.NoError(t, err)
+
+	before := testutil.ToFloat64(metrics.AIPromptTruncations.WithLabelValues(string(pkgdomain.AIProviderOpenAI), "custom_fields"))
+	req, _ := service.(*OpenAIService).enrichmentRequest(track, analyzedMix())
+	prompt := req.Messages[1].Content
+	assert.LessOrEqual(t, estimateTokens(prompt), 150)
+	assert.Contains(t, prompt, "- Title: Night Drive")
+	assert.Contains(t, prompt, "- Tempo: 128.0 BPM", "features are kept over custom fields")
+	assert.NotContains(t, prompt, "story", "the last custom fields are cut")
+	assert.Equal(t, before+1, testutil.ToFloat64(metrics.AIPromptTruncations.WithLabelValues(string(pkgdomain.AIProviderOpenAI), "custom_fields")))
+}
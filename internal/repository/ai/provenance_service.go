@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+)
+
+// ProvenanceAIService wraps an AI service so that enrichment results are merged
+// field by field instead of overwriting the track. Fields last edited by a person
+// are preserved unless the merge policy allows overwriting them; the preserved
+// AI values are surfaced as validation suggestions for review.
+type ProvenanceAIService struct {
+	delegate pkgdomain.AIService
+	policy   pkgdomain.MergePolicy
+}
+
+// NewProvenanceAIService creates a provenance-aware AI service.
+// policy is the default, which callers may override per request with
+// pkgdomain.WithMergePolicy.
+func NewProvenanceAIService(delegate pkgdomain.AIService, policy pkgdomain.MergePolicy) *ProvenanceAIService {
+	return &ProvenanceAIService{
+		delegate: delegate,
+		policy:   policy,
+	}
+}
+
+// EnrichMetadata enriches a copy of the track and merges the result back
+func (s *ProvenanceAIService) EnrichMetadata(ctx context.Context, track *pkgdomain.Track) error {
+	enriched := track.Clone()
+	if err := s.delegate.EnrichMetadata(ctx, enriched); err != nil {
+		return err
+	}
+
+	s.Merge(ctx, track, enriched)
+	return nil
+}
+
+// ValidateMetadata delegates validation unchanged
+func (s *ProvenanceAIService) ValidateMetadata(ctx context.Context, track *pkgdomain.Track) (float64, error) {
+	return s.delegate.ValidateMetadata(ctx, track)
+}
+
+// BatchProcess enriches copies of the tracks in one batch and merges each result back
+func (s *ProvenanceAIService) BatchProcess(ctx context.Context, tracks []*pkgdomain.Track) error {
+	enriched := make([]*pkgdomain.Track, len(tracks))
+	for i, track := range tracks {
+		enriched[i] = track.Clone()
+	}
+
+	if err := s.delegate.BatchProcess(ctx, enriched); err != nil {
+		return err
+	}
+
+	for i, track := range tracks {
+		s.Merge(ctx, track, enriched[i])
+	}
+	return nil
+}
+
+// Merge merges an enrichment result into a track under the merge policy.
+// It is used for enrichments made outside EnrichMetadata, such as batches.
+func (s *ProvenanceAIService) Merge(ctx context.Context, track, enriched *pkgdomain.Track) {
+	policy := s.policy
+	if override, ok := pkgdomain.MergePolicyFromContext(ctx); ok {
+		policy = override
+	}
+
+	actor := ""
+	if enriched.Metadata.AI != nil {
+		actor = fmt.Sprintf("%s@%s", enriched.Metadata.AI.Model, enriched.Metadata.AI.Version)
+	}
+
+	_, skipped := pkgdomain.MergeTrackFields(track, enriched, pkgdomain.ProvenanceAI, policy, actor)
+
+	// AI-only metadata is always taken from the enrichment result
+	track.Metadata.AI = enriched.Metadata.AI
+	if enriched.Duration() > 0 {
+		track.SetDuration(enriched.Duration())
+	}
+
+	if len(skipped) > 0 && track.Metadata.AI != nil {
+		for _, change := range skipped {
+			track.Metadata.AI.ValidationSuggestions = append(track.Metadata.AI.ValidationSuggestions, pkgdomain.ValidationSuggestion{
+				Field:          change.Field,
+				CurrentValue:   fmt.Sprint(change.OldValue),
+				SuggestedValue: fmt.Sprint(change.NewValue),
+				Reason:         "manually edited field preserved",
+			})
+		}
+	}
+}
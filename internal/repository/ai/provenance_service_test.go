@@ -0,0 +1,124 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// enrichingService sets the genre and mood of every track it enriches
+type enrichingService struct {
+	err error
+}
+
+func (s *enrichingService) enrich(track *pkgdomain.Track) {
+	track.SetGenre("Jazz")
+	track.SetMood("Calm")
+	track.SetDuration(180)
+	track.Metadata.AI = &pkgdomain.TrackAIMetadata{Model: "qwen2", Version: "1.5"}
+}
+
+func (s *enrichingService) EnrichMetadata(_ context.Context, track *pkgdomain.Track) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.enrich(track)
+	return nil
+}
+
+func (s *enrichingService) ValidateMetadata(context.Context, *pkgdomain.Track) (float64, error) {
+	return 0.9, s.err
+}
+
+func (s *enrichingService) BatchProcess(_ context.Context, tracks []*pkgdomain.Track) error {
+	if s.err != nil {
+		return s.err
+	}
+	for _, track := range tracks {
+		s.enrich(track)
+	}
+	return nil
+}
+
+// manuallyTagged returns a track whose genre was set by a person
+func manuallyTagged() *pkgdomain.Track {
+	track := &pkgdomain.Track{ID: "t1"}
+	track.SetTitle("Song")
+	track.SetGenre("Pop")
+	track.RecordProvenance([]pkgdomain.FieldChange{{Field: "genre"}}, pkgdomain.ProvenanceManual, "user-1")
+	return track
+}
+
+func TestProvenanceAIService_EnrichMetadata(t *testing.T) {
+	service := NewProvenanceAIService(&enrichingService{}, pkgdomain.MergePolicy{})
+	track := manuallyTagged()
+
+	require.NoError(t, service.EnrichMetadata(context.Background(), track))
+
+	// The AI fills the mood and records itself as its source
+	assert.Equal(t, "Calm", track.Mood())
+	mood, ok := track.FieldProvenance("mood")
+	require.True(t, ok)
+	assert.Equal(t, pkgdomain.ProvenanceAI, mood.Source)
+	assert.Equal(t, "qwen2@1.5", mood.UpdatedBy)
+
+	// The manual genre is kept and the AI's value is offered for review
+	assert.Equal(t, "Pop", track.Genre())
+	genre, _ := track.FieldProvenance("genre")
+	assert.Equal(t, pkgdomain.ProvenanceManual, genre.Source)
+	assert.Equal(t, "user-1", genre.UpdatedBy)
+	require.NotNil(t, track.Metadata.AI)
+	assert.Equal(t, []pkgdomain.ValidationSuggestion{{
+		Field:          "genre",
+		CurrentValue:   "Pop",
+		SuggestedValue: "Jazz",
+		Reason:         "manually edited field preserved",
+	}}, track.Metadata.AI.ValidationSuggestions)
+
+	assert.Equal(t, float64(180), track.Duration())
+}
+
+func TestProvenanceAIService_PolicyOverride(t *testing.T) {
+	service := NewProvenanceAIService(&enrichingService{}, pkgdomain.MergePolicy{})
+	track := manuallyTagged()
+	ctx := pkgdomain.WithMergePolicy(context.Background(), pkgdomain.MergePolicy{OverwriteManual: true})
+
+	require.NoError(t, service.EnrichMetadata(ctx, track))
+
+	assert.Equal(t, "Jazz", track.Genre())
+	genre, _ := track.FieldProvenance("genre")
+	assert.Equal(t, pkgdomain.ProvenanceAI, genre.Source)
+	assert.Empty(t, track.Metadata.AI.ValidationSuggestions)
+}
+
+func TestProvenanceAIService_BatchProcess(t *testing.T) {
+	service := NewProvenanceAIService(&enrichingService{}, pkgdomain.MergePolicy{})
+	manual := manuallyTagged()
+	untouched := &pkgdomain.Track{ID: "t2"}
+	untouched.SetTitle("Other Song")
+
+	require.NoError(t, service.BatchProcess(context.Background(), []*pkgdomain.Track{manual, untouched}))
+
+	assert.Equal(t, "Pop", manual.Genre())
+	assert.Equal(t, "Jazz", untouched.Genre())
+	genre, _ := untouched.FieldProvenance("genre")
+	assert.Equal(t, pkgdomain.ProvenanceAI, genre.Source)
+}
+
+func TestProvenanceAIService_LeavesTrackOnFailure(t *testing.T) {
+	errEnrich := errors.New("model unavailable")
+	service := NewProvenanceAIService(&enrichingService{err: errEnrich}, pkgdomain.MergePolicy{})
+	track := manuallyTagged()
+
+	assert.ErrorIs(t, service.EnrichMetadata(context.Background(), track), errEnrich)
+	assert.Equal(t, "Pop", track.Genre())
+	assert.Empty(t, track.Mood())
+	assert.Nil(t, track.Metadata.AI)
+	_, ok := track.FieldProvenance("mood")
+	assert.False(t, ok)
+}
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"io"
 	"metadatatool/internal/pkg/domain"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"time"
 
 	"github.com/sony/gobreaker"
@@ -96,9 +98,45 @@ func (c *Qwen2Client) AnalyzeAudio(ctx context.Context, audioData io.Reader, for
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-
-	// Set headers
 	req.Header.Set("Content-Type", "audio/"+string(format))
+	return c.analyze(req)
+}
+
+// AnalyzeAudioWithFeatures sends an audio file to Qwen2 for analysis along
+// with a description of its measured features, which the model reads as
+// part of its prompt
+func (c *Qwen2Client) AnalyzeAudioWithFeatures(ctx context.Context, audioData io.Reader, format domain.AudioFormat, features string) (*Qwen2Response, error) {
+	// Prepare multipart body with the audio and the features
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	if err := form.WriteField("features", features); err != nil {
+		return nil, fmt.Errorf("failed to write features: %w", err)
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="audio"; filename="audio.`+string(format)+`"`)
+	header.Set("Content-Type", "audio/"+string(format))
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audio part: %w", err)
+	}
+	if _, err := io.Copy(part, audioData); err != nil {
+		return nil, fmt.Errorf("failed to read audio data: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write request body: %w", err)
+	}
+
+	// Create request
+	req, err := http.NewRequestWithContext(ctx, "POST", c.config.Endpoint+"/analyze", &buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return c.analyze(req)
+}
+
+// analyze sends an analysis request and parses the response
+func (c *Qwen2Client) analyze(req *http.Request) (*Qwen2Response, error) {
 	req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
 
 	// Execute request through circuit breaker
@@ -137,6 +175,30 @@ func (c *Qwen2Client) AnalyzeAudio(ctx context.Context, audioData io.Reader, for
 	return resp.(*Qwen2Response), nil
 }
 
+// Ping checks that the Qwen2 API answers. It fails while the circuit
+// breaker is open, but is not sent through it, so probes do not hold the
+// breaker open.
+func (c *Qwen2Client) Ping(ctx context.Context) error {
+	if c.breaker.State() == gobreaker.StateOpen {
+		return fmt.Errorf("circuit breaker is open")
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.config.Endpoint+"/health", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // ValidateMetadata validates track metadata using Qwen2
 func (c *Qwen2Client) ValidateMetadata(ctx context.Context, track *domain.Track) (float64, error) {
 	// Prepare request body
@@ -14,7 +14,9 @@ import (
 // Qwen2ClientInterface defines the interface for Qwen2 client operations
 type Qwen2ClientInterface interface {
 	AnalyzeAudio(ctx context.Context, audioData io.Reader, format pkgdomain.AudioFormat) (*Qwen2Response, error)
+	AnalyzeAudioWithFeatures(ctx context.Context, audioData io.Reader, format pkgdomain.AudioFormat, features string) (*Qwen2Response, error)
 	ValidateMetadata(ctx context.Context, track *pkgdomain.Track) (float64, error)
+	Ping(ctx context.Context) error
 }
 
 // Qwen2Service implements pkg/domain.AIService interface
@@ -22,7 +24,10 @@ type Qwen2Service struct {
 	config  *pkgdomain.Qwen2Config
 	client  Qwen2ClientInterface
 	metrics *pkgdomain.AIMetrics
-	mu      sync.RWMutex // Protects metrics
+	mu      sync.RWMutex // Protects metrics and minConfidence
+
+	minConfidence float64
+	features      AudioFeatureSource
 }
 
 // NewQwen2Service creates a new Qwen2Service instance
@@ -46,8 +51,9 @@ func NewQwen2Service(config *pkgdomain.Qwen2Config) (pkgdomain.AIService, error)
 	}
 
 	return &Qwen2Service{
-		config: config,
-		client: client,
+		config:        config,
+		client:        client,
+		minConfidence: config.MinConfidence,
 		metrics: &pkgdomain.AIMetrics{
 			RequestCount:   0,
 			SuccessCount:   0,
@@ -68,8 +74,9 @@ func NewQwen2ServiceWithClient(config *pkgdomain.Qwen2Config, client Qwen2Client
 	}
 
 	return &Qwen2Service{
-		config: config,
-		client: client,
+		config:        config,
+		client:        client,
+		minConfidence: config.MinConfidence,
 		metrics: &pkgdomain.AIMetrics{
 			RequestCount:   0,
 			SuccessCount:   0,
@@ -88,6 +95,19 @@ func (s *Qwen2Service) EnrichMetadata(ctx context.Context, track *pkgdomain.Trac
 	startTime := time.Now()
 	var processingErr error
 
+	// The measured features help the model tell apart genres and moods
+	// that sound alike
+	var features string
+	if s.config.AudioFeatures {
+		if analysis := audioFeatures(ctx, s.features, track); analysis != nil {
+			features = renderPrompt(pkgdomain.AIProviderQwen2, audioFeatureParts(analysis), promptBudget(s.config.MaxPromptTokens))
+		}
+	}
+	prompt := qwen2AudioPrompt
+	if features != "" {
+		prompt = qwen2FeaturePrompt
+	}
+
 	// Define retry strategy
 	for attempt := 0; attempt <= s.config.RetryAttempts; attempt++ {
 		if attempt > 0 {
@@ -105,32 +125,31 @@ func (s *Qwen2Service) EnrichMetadata(ctx context.Context, track *pkgdomain.Trac
 		format := pkgdomain.AudioFormat(track.AudioFormat())
 
 		// Call Qwen2 API
-		response, err := s.client.AnalyzeAudio(ctx, audioReader, format)
+		var response *Qwen2Response
+		var err error
+		if features != "" {
+			response, err = s.client.AnalyzeAudioWithFeatures(ctx, audioReader, format, features)
+		} else {
+			response, err = s.client.AnalyzeAudio(ctx, audioReader, format)
+		}
 		if err != nil {
 			processingErr = fmt.Errorf("attempt %d: failed to analyze audio: %w", attempt+1, err)
 			continue
 		}
 
 		// Check confidence threshold
-		if response.Metadata.Confidence < s.config.MinConfidence {
-			track.Metadata.AI = &pkgdomain.TrackAIMetadata{
-				Tags:         response.Metadata.Tags,
-				Confidence:   response.Metadata.Confidence,
-				Model:        "qwen2",
-				Version:      "v1",
-				ProcessedAt:  time.Now(),
-				NeedsReview:  true,
-				ReviewReason: fmt.Sprintf("Low confidence score: %.2f", response.Metadata.Confidence),
-			}
-		} else {
-			track.Metadata.AI = &pkgdomain.TrackAIMetadata{
-				Tags:        response.Metadata.Tags,
-				Confidence:  response.Metadata.Confidence,
-				Model:       "qwen2",
-				Version:     "v1",
-				ProcessedAt: time.Now(),
-				NeedsReview: false,
-			}
+		version := qwen2ModelVersion(s.config, prompt)
+		track.Metadata.AI = &pkgdomain.TrackAIMetadata{
+			Tags:          response.Metadata.Tags,
+			Confidence:    response.Metadata.Confidence,
+			Model:         version.Model,
+			Version:       version.Version,
+			PromptVersion: version.PromptVersion,
+			ProcessedAt:   time.Now(),
+		}
+		if response.Metadata.Confidence < s.getMinConfidence() {
+			track.Metadata.AI.NeedsReview = true
+			track.Metadata.AI.ReviewReason = fmt.Sprintf("Low confidence score: %.2f", response.Metadata.Confidence)
 		}
 
 		// Update track fields
@@ -186,7 +205,7 @@ func (s *Qwen2Service) ValidateMetadata(ctx context.Context, track *pkgdomain.Tr
 		metrics.AIConfidenceScore.WithLabelValues(string(pkgdomain.AIProviderQwen2)).Observe(confidence)
 
 		// Return early if confidence meets threshold
-		if confidence >= s.config.MinConfidence {
+		if confidence >= s.getMinConfidence() {
 			return confidence, nil
 		}
 
@@ -287,3 +306,38 @@ func (s *Qwen2Service) recordFailure(err error) {
 	metrics.AIRequestTotal.WithLabelValues(string(pkgdomain.AIProviderQwen2), "failure").Inc()
 	metrics.AIErrorTotal.WithLabelValues(string(pkgdomain.AIProviderQwen2), err.Error()).Inc()
 }
+
+// HealthCheck probes the Qwen2 API without analyzing audio
+func (s *Qwen2Service) HealthCheck(ctx context.Context) error {
+	return s.client.Ping(ctx)
+}
+
+// SetMinConfidence changes the confidence below which results are retried
+func (s *Qwen2Service) SetMinConfidence(minConfidence float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.minConfidence = minConfidence
+}
+
+// ModelVersions returns the model versions Qwen2 enriches with: with audio
+// features when AudioFeatures is on and the track has been analyzed, and
+// without otherwise
+func (s *Qwen2Service) ModelVersions() []pkgdomain.AIModelVersion {
+	versions := []pkgdomain.AIModelVersion{qwen2ModelVersion(s.config, qwen2AudioPrompt)}
+	if s.config.AudioFeatures {
+		versions = append(versions, qwen2ModelVersion(s.config, qwen2FeaturePrompt))
+	}
+	return versions
+}
+
+// SetAudioFeatureSource sets where the audio analyses sent with the audio
+// are read from when AudioFeatures is on. It must be called before use.
+func (s *Qwen2Service) SetAudioFeatureSource(source AudioFeatureSource) {
+	s.features = source
+}
+
+func (s *Qwen2Service) getMinConfidence() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.minConfidence
+}
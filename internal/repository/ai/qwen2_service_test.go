@@ -25,11 +25,23 @@ func (m *mockQwen2Client) AnalyzeAudio(ctx context.Context, audioData io.Reader,
 	return args.Get(0).(*Qwen2Response), args.Error(1)
 }
 
+func (m *mockQwen2Client) AnalyzeAudioWithFeatures(ctx context.Context, audioData io.Reader, format pkgdomain.AudioFormat, features string) (*Qwen2Response, error) {
+	args := m.Called(ctx, audioData, format, features)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Qwen2Response), args.Error(1)
+}
+
 func (m *mockQwen2Client) ValidateMetadata(ctx context.Context, track *pkgdomain.Track) (float64, error) {
 	args := m.Called(ctx, track)
 	return args.Get(0).(float64), args.Error(1)
 }
 
+func (m *mockQwen2Client) Ping(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
 func TestNewQwen2Service(t *testing.T) {
 	tests := []struct {
 		name    string
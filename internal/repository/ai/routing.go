@@ -0,0 +1,228 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+)
+
+// unhealthyAfter is the number of failed probes in a row that mark a
+// provider unhealthy, so one slow probe does not move all traffic
+const unhealthyAfter = 2
+
+// providerHealth holds the health probes of a provider. Providers are
+// healthy until probed otherwise.
+type providerHealth struct {
+	healthy  bool
+	latency  time.Duration
+	failures int
+	checked  time.Time
+	lastErr  string
+}
+
+// service returns the service of a provider
+func (s *CompositeAIService) service(provider pkgdomain.AIProvider) (pkgdomain.AIService, bool) {
+	switch provider {
+	case pkgdomain.AIProviderQwen2:
+		return s.qwen2Service, true
+	case pkgdomain.AIProviderOpenAI:
+		return s.openAIService, true
+	}
+	return nil, false
+}
+
+// route returns the providers in the order a request tries them, counting
+// a failover when preferred is skipped for being unhealthy
+func (s *CompositeAIService) route(preferred pkgdomain.AIProvider) []pkgdomain.AIProvider {
+	order := s.order(preferred)
+	s.mu.RLock()
+	unhealthy := !s.health[preferred].healthy
+	s.mu.RUnlock()
+	if unhealthy && order[0] != preferred {
+		metrics.AIFailovers.WithLabelValues(string(preferred), string(order[0])).Inc()
+	}
+	return order
+}
+
+// order returns the providers in the order a request tries them, starting
+// from preferred. A forced provider is the only one tried. Otherwise healthy
+// providers go before unhealthy ones, and with the latency policy the
+// fastest healthy provider goes first whatever was preferred.
+func (s *CompositeAIService) order(preferred pkgdomain.AIProvider) []pkgdomain.AIProvider {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.forced != "" {
+		return []pkgdomain.AIProvider{s.forced}
+	}
+	order := []pkgdomain.AIProvider{preferred}
+	for _, provider := range s.Providers() {
+		if provider != preferred {
+			order = append(order, provider)
+		}
+	}
+	if s.policy == pkgdomain.AIRoutingLatency {
+		// Providers without a successful probe go last
+		sort.SliceStable(order, func(i, j int) bool {
+			a, b := s.health[order[i]].latency, s.health[order[j]].latency
+			return a > 0 && (b == 0 || a < b)
+		})
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return s.health[order[i]].healthy && !s.health[order[j]].healthy
+	})
+	return order
+}
+
+// CheckHealth probes every provider that supports it and records the
+// result. Providers without a probe stay healthy.
+func (s *CompositeAIService) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, provider := range s.Providers() {
+		service, _ := s.service(provider)
+		checker, ok := service.(pkgdomain.AIHealthChecker)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(provider pkgdomain.AIProvider) {
+			defer wg.Done()
+			probeCtx := ctx
+			if s.config.TimeoutSeconds > 0 {
+				var cancel context.CancelFunc
+				probeCtx, cancel = context.WithTimeout(ctx, time.Duration(s.config.TimeoutSeconds)*time.Second)
+				defer cancel()
+			}
+			start := time.Now()
+			err := checker.HealthCheck(probeCtx)
+			s.recordProbe(provider, time.Since(start), err)
+		}(provider)
+	}
+	wg.Wait()
+}
+
+// RunHealthChecks probes the providers now and every interval until ctx is
+// cancelled
+func (s *CompositeAIService) RunHealthChecks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.CheckHealth(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// recordProbe records the outcome of a health probe
+func (s *CompositeAIService) recordProbe(provider pkgdomain.AIProvider, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	health := s.health[provider]
+	health.checked = time.Now()
+	if err != nil {
+		health.failures++
+		health.lastErr = err.Error()
+		if health.healthy && health.failures >= unhealthyAfter {
+			log.Printf("AI provider %s is unhealthy: %v", provider, err)
+			health.healthy = false
+		}
+	} else {
+		if !health.healthy {
+			log.Printf("AI provider %s is healthy again", provider)
+		}
+		health.healthy = true
+		health.failures = 0
+		health.latency = latency
+		health.lastErr = ""
+	}
+	if health.healthy {
+		metrics.AIProviderHealthy.WithLabelValues(string(provider)).Set(1)
+	} else {
+		metrics.AIProviderHealthy.WithLabelValues(string(provider)).Set(0)
+	}
+}
+
+// ForceProvider sends all traffic to provider, whatever its health, and
+// without falling back to the other providers. The empty provider routes
+// by health again.
+func (s *CompositeAIService) ForceProvider(provider pkgdomain.AIProvider) error {
+	if err := pkgdomain.ValidateAIProvider(provider); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if provider != s.forced {
+		if provider == "" {
+			log.Printf("AI traffic is routed by provider health again")
+		} else {
+			log.Printf("All AI traffic is forced to %s", provider)
+		}
+	}
+	s.forced = provider
+	return nil
+}
+
+// Routing reports the routing policy, the forced provider and the health
+// of each provider
+func (s *CompositeAIService) Routing() *pkgdomain.AIRouting {
+	s.mu.RLock()
+	preferred := s.primaryProvider
+	routing := &pkgdomain.AIRouting{Policy: s.policy, Forced: s.forced}
+	for _, provider := range s.Providers() {
+		health := s.health[provider]
+		entry := pkgdomain.AIProviderHealth{
+			Provider:            provider,
+			Healthy:             health.healthy,
+			LatencyMs:           health.latency.Milliseconds(),
+			ConsecutiveFailures: health.failures,
+			LastError:           health.lastErr,
+		}
+		if !health.checked.IsZero() {
+			checked := health.checked
+			entry.LastChecked = &checked
+		}
+		routing.Providers = append(routing.Providers, entry)
+	}
+	s.mu.RUnlock()
+
+	routing.Order = s.order(preferred)
+	return routing
+}
+
+// tryProviders calls fn with each provider in order until one succeeds,
+// returning the error of the first. Later providers are only tried with
+// fallback on, each counting as a failover.
+func (s *CompositeAIService) tryProviders(order []pkgdomain.AIProvider, fn func(pkgdomain.AIProvider, pkgdomain.AIService) error) error {
+	var firstErr error
+	for i, provider := range order {
+		if i > 0 {
+			if !s.config.EnableFallback {
+				break
+			}
+			metrics.AIFailovers.WithLabelValues(string(order[i-1]), string(provider)).Inc()
+		}
+		service, ok := s.service(provider)
+		if !ok {
+			return fmt.Errorf("unknown AI provider %q", provider)
+		}
+		err := fn(provider, service)
+		if err == nil {
+			return nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,130 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// probedService is an AI service whose requests and health probes fail
+// while err and probeErr are set
+type probedService struct {
+	err      error
+	probeErr error
+	delay    time.Duration
+	calls    int
+}
+
+func (s *probedService) EnrichMetadata(context.Context, *pkgdomain.Track) error {
+	s.calls++
+	return s.err
+}
+
+func (s *probedService) ValidateMetadata(context.Context, *pkgdomain.Track) (float64, error) {
+	s.calls++
+	return 0.9, s.err
+}
+
+func (s *probedService) BatchProcess(context.Context, []*pkgdomain.Track) error { return nil }
+
+func (s *probedService) HealthCheck(context.Context) error {
+	time.Sleep(s.delay)
+	return s.probeErr
+}
+
+func newRoutedService(policy pkgdomain.AIRoutingPolicy, fallback bool) (*CompositeAIService, *probedService, *probedService) {
+	qwen2, openAI := &probedService{}, &probedService{}
+	return &CompositeAIService{
+		config:           &Config{EnableFallback: fallback},
+		qwen2Service:     qwen2,
+		openAIService:    openAI,
+		primaryProvider:  pkgdomain.AIProviderQwen2,
+		fallbackProvider: pkgdomain.AIProviderOpenAI,
+		metrics:          make(map[pkgdomain.AIProvider]*pkgdomain.AIMetrics),
+		policy:           policy,
+		health: map[pkgdomain.AIProvider]*providerHealth{
+			pkgdomain.AIProviderQwen2:  {healthy: true},
+			pkgdomain.AIProviderOpenAI: {healthy: true},
+		},
+	}, qwen2, openAI
+}
+
+func TestCompositeAIService_RoutesAroundUnhealthyProviders(t *testing.T) {
+	service, qwen2, openAI := newRoutedService(pkgdomain.AIRoutingExperiment, true)
+	ctx := context.Background()
+
+	qwen2.probeErr = errors.New("connection refused")
+	service.CheckHealth(ctx)
+	assert.True(t, service.Routing().Providers[0].Healthy, "one failed probe is not enough")
+	service.CheckHealth(ctx)
+
+	routing := service.Routing()
+	assert.False(t, routing.Providers[0].Healthy)
+	assert.Equal(t, 2, routing.Providers[0].ConsecutiveFailures)
+	assert.Equal(t, "connection refused", routing.Providers[0].LastError)
+	assert.NotNil(t, routing.Providers[0].LastChecked)
+	assert.Equal(t, []pkgdomain.AIProvider{pkgdomain.AIProviderOpenAI, pkgdomain.AIProviderQwen2}, routing.Order)
+
+	require.NoError(t, service.EnrichMetadata(ctx, &pkgdomain.Track{ID: "track-1"}))
+	assert.Equal(t, 0, qwen2.calls)
+	assert.Equal(t, 1, openAI.calls)
+
+	qwen2.probeErr = nil
+	service.CheckHealth(ctx)
+	assert.Equal(t, []pkgdomain.AIProvider{pkgdomain.AIProviderQwen2, pkgdomain.AIProviderOpenAI}, service.Routing().Order, "healthy again")
+}
+
+func TestCompositeAIService_FailsOverOnError(t *testing.T) {
+	service, qwen2, openAI := newRoutedService(pkgdomain.AIRoutingExperiment, true)
+	ctx := context.Background()
+	qwen2.err = errors.New("qwen2 is down")
+
+	require.NoError(t, service.EnrichMetadata(ctx, &pkgdomain.Track{ID: "track-1"}))
+	assert.Equal(t, 1, qwen2.calls)
+	assert.Equal(t, 1, openAI.calls)
+
+	openAI.err = errors.New("openai is down")
+	err := service.EnrichMetadata(ctx, &pkgdomain.Track{ID: "track-1"})
+	assert.ErrorContains(t, err, "qwen2 is down", "the first provider's error is returned")
+
+	service, qwen2, openAI = newRoutedService(pkgdomain.AIRoutingExperiment, false)
+	qwen2.err = errors.New("qwen2 is down")
+	assert.Error(t, service.EnrichMetadata(ctx, &pkgdomain.Track{ID: "track-1"}))
+	assert.Equal(t, 0, openAI.calls, "no fallback")
+}
+
+func TestCompositeAIService_LatencyPolicy(t *testing.T) {
+	service, qwen2, _ := newRoutedService(pkgdomain.AIRoutingLatency, true)
+	assert.Equal(t, []pkgdomain.AIProvider{pkgdomain.AIProviderQwen2, pkgdomain.AIProviderOpenAI}, service.Routing().Order)
+
+	qwen2.delay = 20 * time.Millisecond
+	service.CheckHealth(context.Background())
+	assert.Equal(t, []pkgdomain.AIProvider{pkgdomain.AIProviderOpenAI, pkgdomain.AIProviderQwen2}, service.Routing().Order, "the fastest provider goes first")
+}
+
+func TestCompositeAIService_ForceProvider(t *testing.T) {
+	service, qwen2, openAI := newRoutedService(pkgdomain.AIRoutingExperiment, true)
+	ctx := context.Background()
+	openAI.probeErr = errors.New("timeout")
+	service.CheckHealth(ctx)
+	service.CheckHealth(ctx)
+
+	require.NoError(t, service.ForceProvider(pkgdomain.AIProviderOpenAI))
+	routing := service.Routing()
+	assert.Equal(t, pkgdomain.AIProviderOpenAI, routing.Forced)
+	assert.Equal(t, []pkgdomain.AIProvider{pkgdomain.AIProviderOpenAI}, routing.Order, "forced whatever its health")
+
+	openAI.err = errors.New("openai is down")
+	assert.Error(t, service.EnrichMetadata(ctx, &pkgdomain.Track{ID: "track-1"}))
+	assert.Equal(t, 0, qwen2.calls, "no fallback while forced")
+
+	assert.ErrorIs(t, service.ForceProvider("acme"), pkgdomain.ErrInvalidInput)
+	require.NoError(t, service.ForceProvider(""))
+	assert.Equal(t, []pkgdomain.AIProvider{pkgdomain.AIProviderQwen2, pkgdomain.AIProviderOpenAI}, service.Routing().Order)
+}
@@ -11,6 +11,10 @@ import (
 	"metadatatool/internal/pkg/metrics"
 )
 
+// AnalyzerVersion names the analysis the processor makes. Change it when
+// the analysis changes, so the tracks analyzed before are analyzed again.
+const AnalyzerVersion = "basic_analyzer_v1"
+
 // Processor implements the AudioProcessor interface
 type Processor struct {
 	// Dependencies could be added here
@@ -56,8 +60,9 @@ func (p *Processor) Process(ctx context.Context, file *domain.ProcessingAudioFil
 	}
 
 	// Perform audio analysis if requested
+	var analysis *domain.AudioAnalysis
 	if options.AnalyzeAudio {
-		analysis, err := p.analyzeAudio(ctx, bytes.NewReader(data), file.Format)
+		analysis, err = p.analyzeAudio(ctx, bytes.NewReader(data), file.Format)
 		if err != nil {
 			metrics.AudioOpErrors.WithLabelValues("process", "analysis_error").Inc()
 			return nil, fmt.Errorf("failed to analyze audio: %w", err)
@@ -76,7 +81,8 @@ func (p *Processor) Process(ctx context.Context, file *domain.ProcessingAudioFil
 	metrics.AudioOps.WithLabelValues("process", "completed").Inc()
 	return &domain.AudioProcessResult{
 		Metadata:     metadata,
-		AnalyzerInfo: "audio_processor_v1", // Add version or processor info
+		Analysis:     analysis,
+		AnalyzerInfo: AnalyzerVersion,
 	}, nil
 }
 
@@ -98,7 +104,7 @@ func (p *Processor) analyzeAudio(ctx context.Context, reader io.Reader, format d
 		SampleRate:   44100,
 		WindowSize:   2048,
 		HopSize:      512,
-		AnalyzerInfo: "basic_analyzer_v1",
+		AnalyzerInfo: AnalyzerVersion,
 	}
 
 	metrics.AudioOps.WithLabelValues("analyze", "completed").Inc()
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
@@ -54,6 +55,11 @@ func NewJWTService(secretKey []byte) (*JWTService, error) {
 	}, nil
 }
 
+// GenerateToken creates an access token for user
+func (s *JWTService) GenerateToken(ctx context.Context, user *domain.User) (string, error) {
+	return s.createToken(user, accessTokenDuration)
+}
+
 // GenerateTokens creates a new pair of access and refresh tokens
 func (s *JWTService) GenerateTokens(user *domain.User) (*domain.TokenPair, error) {
 	// Generate access token
@@ -75,7 +81,7 @@ func (s *JWTService) GenerateTokens(user *domain.User) (*domain.TokenPair, error
 }
 
 // ValidateToken validates and parses a JWT token
-func (s *JWTService) ValidateToken(tokenString string) (*domain.Claims, error) {
+func (s *JWTService) ValidateToken(ctx context.Context, tokenString string) (*domain.Claims, error) {
 	if tokenString == "" {
 		return nil, fmt.Errorf("%w: token is empty", domain.ErrInvalidToken)
 	}
@@ -113,7 +119,7 @@ func (s *JWTService) ValidateToken(tokenString string) (*domain.Claims, error) {
 
 // RefreshToken validates a refresh token and generates new token pair
 func (s *JWTService) RefreshToken(refreshToken string) (*domain.TokenPair, error) {
-	claims, err := s.ValidateToken(refreshToken)
+	claims, err := s.ValidateToken(context.Background(), refreshToken)
 	if err != nil {
 		return nil, fmt.Errorf("invalid refresh token: %w", err)
 	}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -50,7 +51,7 @@ func TestJWTService_GenerateTokens(t *testing.T) {
 		assert.NotEmpty(t, tokens.RefreshToken)
 
 		// Validate access token
-		claims, err := service.ValidateToken(tokens.AccessToken)
+		claims, err := service.ValidateToken(context.Background(), tokens.AccessToken)
 		require.NoError(t, err)
 		assert.Equal(t, user.ID, claims.UserID)
 		assert.Equal(t, user.Email, claims.Email)
@@ -58,7 +59,7 @@ func TestJWTService_GenerateTokens(t *testing.T) {
 		assert.Equal(t, user.Permissions, claims.Permissions)
 
 		// Validate refresh token
-		claims, err = service.ValidateToken(tokens.RefreshToken)
+		claims, err = service.ValidateToken(context.Background(), tokens.RefreshToken)
 		require.NoError(t, err)
 		assert.Equal(t, user.ID, claims.UserID)
 	})
@@ -73,7 +74,7 @@ func TestJWTService_ValidateToken(t *testing.T) {
 		tokens, err := service.GenerateTokens(user)
 		require.NoError(t, err)
 
-		claims, err := service.ValidateToken(tokens.AccessToken)
+		claims, err := service.ValidateToken(context.Background(), tokens.AccessToken)
 		require.NoError(t, err)
 		assert.Equal(t, user.ID, claims.UserID)
 		assert.Equal(t, user.Email, claims.Email)
@@ -81,7 +82,7 @@ func TestJWTService_ValidateToken(t *testing.T) {
 	})
 
 	t.Run("invalid token", func(t *testing.T) {
-		_, err := service.ValidateToken("invalid-token")
+		_, err := service.ValidateToken(context.Background(), "invalid-token")
 		assert.Error(t, err)
 	})
 
@@ -98,7 +99,7 @@ func TestJWTService_ValidateToken(t *testing.T) {
 		// Wait for token to expire
 		time.Sleep(time.Millisecond * 2)
 
-		_, err = shortDurationService.ValidateToken(token)
+		_, err = shortDurationService.ValidateToken(context.Background(), token)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "token is expired")
 	})
@@ -0,0 +1,72 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+
+	"gorm.io/gorm"
+)
+
+// AIBatchRepository implements domain.AIBatchRepository using GORM
+type AIBatchRepository struct {
+	db *gorm.DB
+}
+
+// NewAIBatchRepository creates a new AI batch repository
+func NewAIBatchRepository(db *gorm.DB) domain.AIBatchRepository {
+	return &AIBatchRepository{db: db}
+}
+
+// Create stores a new batch
+func (r *AIBatchRepository) Create(ctx context.Context, batch *domain.AIBatch) error {
+	if err := dbFor(ctx, r.db).Create(batch).Error; err != nil {
+		return fmt.Errorf("failed to create AI batch: %w", err)
+	}
+	return nil
+}
+
+// Update saves a batch
+func (r *AIBatchRepository) Update(ctx context.Context, batch *domain.AIBatch) error {
+	if err := dbFor(ctx, r.db).Save(batch).Error; err != nil {
+		return fmt.Errorf("failed to update AI batch: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a batch
+func (r *AIBatchRepository) GetByID(ctx context.Context, id string) (*domain.AIBatch, error) {
+	var batch domain.AIBatch
+	result := dbFor(ctx, r.db).Where("id = ?", id).First(&batch)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrAIBatchNotFound
+		}
+		return nil, fmt.Errorf("failed to get AI batch: %w", result.Error)
+	}
+	return &batch, nil
+}
+
+// List returns the latest batches, newest first
+func (r *AIBatchRepository) List(ctx context.Context, limit int) ([]*domain.AIBatch, error) {
+	var batches []*domain.AIBatch
+	if err := dbFor(ctx, r.db).Order("created_at DESC").Limit(limit).Find(&batches).Error; err != nil {
+		return nil, fmt.Errorf("failed to list AI batches: %w", err)
+	}
+	return batches, nil
+}
+
+// ListPending returns the batches still running at the provider, oldest
+// first
+func (r *AIBatchRepository) ListPending(ctx context.Context) ([]*domain.AIBatch, error) {
+	var batches []*domain.AIBatch
+	result := dbFor(ctx, r.db).
+		Where("status = ?", domain.AIBatchPending).
+		Order("created_at ASC").
+		Find(&batches)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list pending AI batches: %w", result.Error)
+	}
+	return batches, nil
+}
@@ -0,0 +1,102 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+
+	"gorm.io/gorm"
+)
+
+// AIModelRepository implements domain.AIModelRepository using GORM. The
+// model versions are read from the metadata JSON.
+type AIModelRepository struct {
+	db *gorm.DB
+}
+
+// NewAIModelRepository creates a new AI model repository
+func NewAIModelRepository(db *gorm.DB) domain.AIModelRepository {
+	return &AIModelRepository{db: db}
+}
+
+// aiModelCoverageSQL counts the enriched tracks that are not deleted by
+// model version
+const aiModelCoverageSQL = `SELECT
+	COALESCE(metadata->'ai'->>'model', '') AS model,
+	COALESCE(metadata->'ai'->>'version', '') AS version,
+	COALESCE(metadata->'ai'->>'promptVersion', '') AS prompt_version,
+	COUNT(*) AS tracks
+FROM tracks
+WHERE deleted_at IS NULL AND jsonb_typeof(metadata->'ai') = 'object'
+GROUP BY 1, 2, 3
+ORDER BY tracks DESC, model, version, prompt_version`
+
+// enrichedTracksSQL pages through the enriched tracks that are not deleted
+const enrichedTracksSQL = `SELECT
+	id::text AS track_id,
+	COALESCE(metadata->'ai'->>'model', '') AS model,
+	COALESCE(metadata->'ai'->>'version', '') AS version,
+	COALESCE(metadata->'ai'->>'promptVersion', '') AS prompt_version
+FROM tracks
+WHERE deleted_at IS NULL AND jsonb_typeof(metadata->'ai') = 'object' AND id::text > ?
+ORDER BY id::text
+LIMIT ?`
+
+// aiModelRow is a row of the model version queries
+type aiModelRow struct {
+	TrackID       string
+	Model         string
+	Version       string
+	PromptVersion string
+	Tracks        int64
+}
+
+func (row aiModelRow) modelVersion() domain.AIModelVersion {
+	return domain.AIModelVersion{
+		Model:         row.Model,
+		Version:       row.Version,
+		PromptVersion: row.PromptVersion,
+	}
+}
+
+// ModelCoverage counts the tracks by the model version they were last
+// enriched with
+func (r *AIModelRepository) ModelCoverage(ctx context.Context) (*domain.AIModelCoverageReport, error) {
+	var tracks int64
+	if err := dbFor(ctx, r.db).Model(&domain.Track{}).Where("deleted_at IS NULL").Count(&tracks).Error; err != nil {
+		return nil, fmt.Errorf("failed to count tracks: %w", err)
+	}
+
+	var rows []aiModelRow
+	if err := dbFor(ctx, r.db).Raw(aiModelCoverageSQL).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count tracks by model version: %w", err)
+	}
+
+	report := &domain.AIModelCoverageReport{
+		Tracks:     tracks,
+		Unenriched: tracks,
+		Models:     make([]domain.AIModelCoverage, 0, len(rows)),
+	}
+	for _, row := range rows {
+		report.Unenriched -= row.Tracks
+		report.Models = append(report.Models, domain.AIModelCoverage{
+			AIModelVersion: row.modelVersion(),
+			Tracks:         row.Tracks,
+		})
+	}
+	return report, nil
+}
+
+// EnrichedTracks returns a page of enriched tracks with their model versions
+func (r *AIModelRepository) EnrichedTracks(ctx context.Context, afterID string, limit int) ([]domain.EnrichedTrack, error) {
+	var rows []aiModelRow
+	if err := dbFor(ctx, r.db).Raw(enrichedTracksSQL, afterID, limit).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list enriched tracks: %w", err)
+	}
+
+	tracks := make([]domain.EnrichedTrack, len(rows))
+	for i, row := range rows {
+		tracks[i] = domain.EnrichedTrack{TrackID: row.TrackID, AIModelVersion: row.modelVersion()}
+	}
+	return tracks, nil
+}
@@ -0,0 +1,123 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AnalysisRepository implements domain.AnalysisRepository on PostgreSQL,
+// storing each analysis as JSONB
+type AnalysisRepository struct {
+	db *gorm.DB
+}
+
+// NewAnalysisRepository creates a new analysis repository
+func NewAnalysisRepository(db *gorm.DB) domain.AnalysisRepository {
+	return &AnalysisRepository{db: db}
+}
+
+const saveAnalysisSQL = `INSERT INTO track_analyses (track_id, analyzer_version, analysis, analyzed_at)
+VALUES (?, ?, ?::jsonb, ?)
+ON CONFLICT (track_id, analyzer_version) DO UPDATE SET
+	analysis = EXCLUDED.analysis,
+	analyzed_at = EXCLUDED.analyzed_at`
+
+// outdatedAnalysesSQL finds the tracks with audio analyzed by another
+// version but not yet by the wanted one, skipping deleted tracks
+const outdatedAnalysesSQL = `SELECT t.id::text AS track_id, t.storage_path
+FROM tracks t
+WHERE t.deleted_at IS NULL AND COALESCE(t.storage_path, '') <> '' AND t.id::text > ?
+	AND EXISTS (SELECT 1 FROM track_analyses a WHERE a.track_id = t.id::text AND a.analyzer_version <> ?)
+	AND NOT EXISTS (SELECT 1 FROM track_analyses a WHERE a.track_id = t.id::text AND a.analyzer_version = ?)
+ORDER BY t.id::text
+LIMIT ?`
+
+// analysisRow is a row of track_analyses
+type analysisRow struct {
+	TrackID         string
+	AnalyzerVersion string
+	Analysis        string
+	AnalyzedAt      time.Time
+}
+
+// Save creates or replaces the analysis of a track by its analyzer version
+func (r *AnalysisRepository) Save(ctx context.Context, analysis *domain.TrackAnalysis) error {
+	if analysis.AnalyzedAt.IsZero() {
+		analysis.AnalyzedAt = time.Now()
+	}
+	data, err := json.Marshal(analysis.Analysis)
+	if err != nil {
+		return fmt.Errorf("failed to marshal analysis: %w", err)
+	}
+	err = dbFor(ctx, r.db).Exec(saveAnalysisSQL,
+		analysis.TrackID, analysis.AnalyzerVersion, string(data), analysis.AnalyzedAt).Error
+	if err != nil {
+		return fmt.Errorf("failed to save analysis: %w", err)
+	}
+	return nil
+}
+
+// Get returns the analysis of a track by version, or the latest when
+// version is empty
+func (r *AnalysisRepository) Get(ctx context.Context, trackID, version string) (*domain.TrackAnalysis, error) {
+	query := dbFor(ctx, r.db).Table("track_analyses").
+		Select("track_id, analyzer_version, analysis::text AS analysis, analyzed_at").
+		Where("track_id = ?", trackID)
+	if version != "" {
+		query = query.Where("analyzer_version = ?", version)
+	}
+
+	var rows []analysisRow
+	if err := query.Order("analyzed_at DESC").Limit(1).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get analysis: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, domain.ErrAnalysisNotFound
+	}
+
+	var analysis domain.AudioAnalysis
+	if err := json.Unmarshal([]byte(rows[0].Analysis), &analysis); err != nil {
+		return nil, fmt.Errorf("failed to parse analysis of track %s: %w", trackID, err)
+	}
+	return &domain.TrackAnalysis{
+		TrackID:         rows[0].TrackID,
+		AnalyzerVersion: rows[0].AnalyzerVersion,
+		Analysis:        &analysis,
+		AnalyzedAt:      rows[0].AnalyzedAt,
+	}, nil
+}
+
+// Versions counts the analyzed tracks by analyzer version
+func (r *AnalysisRepository) Versions(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		AnalyzerVersion string
+		Tracks          int64
+	}
+	err := dbFor(ctx, r.db).Table("track_analyses").
+		Select("analyzer_version, COUNT(*) AS tracks").
+		Group("analyzer_version").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count analyses: %w", err)
+	}
+	versions := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		versions[row.AnalyzerVersion] = row.Tracks
+	}
+	return versions, nil
+}
+
+// Outdated returns the tracks analyzed by other versions but not by version
+func (r *AnalysisRepository) Outdated(ctx context.Context, version, afterID string, limit int) ([]domain.ReanalysisTarget, error) {
+	var targets []domain.ReanalysisTarget
+	err := dbFor(ctx, r.db).Raw(outdatedAnalysesSQL, afterID, version, version, limit).Scan(&targets).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find outdated analyses: %w", err)
+	}
+	return targets, nil
+}
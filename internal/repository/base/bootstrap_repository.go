@@ -0,0 +1,56 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bootstrapMarkerID is the key of the only bootstrap marker row
+const bootstrapMarkerID = 1
+
+// BootstrapRepository implements domain.BootstrapRepository using GORM
+type BootstrapRepository struct {
+	db *gorm.DB
+}
+
+// NewBootstrapRepository creates a new bootstrap repository
+func NewBootstrapRepository(db *gorm.DB) domain.BootstrapRepository {
+	return &BootstrapRepository{db: db}
+}
+
+// AdminExists reports whether any user has the admin role
+func (r *BootstrapRepository) AdminExists(ctx context.Context) (bool, error) {
+	var ids []string
+	err := dbFor(ctx, r.db).Model(&domain.User{}).
+		Where("role = ?", domain.RoleAdmin).
+		Limit(1).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to look up admins: %w", err)
+	}
+	return len(ids) > 0, nil
+}
+
+// Claim inserts the bootstrap marker. The marker has a fixed key, so of two
+// concurrent claims the second waits for the first to commit and then
+// inserts nothing.
+func (r *BootstrapRepository) Claim(ctx context.Context, adminEmail string) error {
+	result := dbFor(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&domain.BootstrapMarker{
+		ID:         bootstrapMarkerID,
+		AdminEmail: adminEmail,
+		CreatedAt:  time.Now(),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to claim bootstrap: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrAlreadyBootstrapped
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBootstrapRepository(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLiteDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.User{}, &domain.BootstrapMarker{}))
+	repo := NewBootstrapRepository(db)
+	users := NewPkgUserRepository(db)
+	uow := NewUnitOfWork(db)
+
+	exists, err := repo.AdminExists(ctx)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	t.Run("a failed bootstrap leaves the claim open", func(t *testing.T) {
+		failed := errors.New("admin not created")
+		err := uow.Do(ctx, func(ctx context.Context) error {
+			require.NoError(t, repo.Claim(ctx, "first@example.com"))
+			return failed
+		})
+		assert.ErrorIs(t, err, failed)
+	})
+
+	t.Run("only the first claim succeeds", func(t *testing.T) {
+		require.NoError(t, uow.Do(ctx, func(ctx context.Context) error {
+			if err := repo.Claim(ctx, "admin@example.com"); err != nil {
+				return err
+			}
+			return users.Create(ctx, domain.NewUser("admin@example.com", "Admin", domain.RoleAdmin))
+		}))
+		assert.ErrorIs(t, repo.Claim(ctx, "other@example.com"), domain.ErrAlreadyBootstrapped)
+
+		exists, err := repo.AdminExists(ctx)
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+}
@@ -0,0 +1,65 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+
+	"gorm.io/gorm"
+)
+
+// CatalogStatsRepository implements domain.CatalogStatsRepository using
+// GORM. The AI fields are read from the metadata JSON.
+type CatalogStatsRepository struct {
+	db *gorm.DB
+}
+
+// NewCatalogStatsRepository creates a new catalog statistics repository
+func NewCatalogStatsRepository(db *gorm.DB) domain.CatalogStatsRepository {
+	return &CatalogStatsRepository{db: db}
+}
+
+// catalogAIStatsSQL totals the AI fields of the tracks that are not deleted
+const catalogAIStatsSQL = `SELECT
+	COUNT(*) FILTER (WHERE metadata->'ai' IS NULL OR jsonb_typeof(metadata->'ai') = 'null') AS unenriched,
+	COUNT(*) FILTER (WHERE (metadata->'ai'->>'needsReview')::boolean) AS needs_review,
+	COALESCE(AVG((metadata->'ai'->>'confidence')::float8) FILTER (WHERE jsonb_typeof(metadata->'ai') = 'object'), 0) AS average_confidence
+FROM tracks
+WHERE deleted_at IS NULL`
+
+// CatalogStats computes the catalog statistics
+func (r *CatalogStatsRepository) CatalogStats(ctx context.Context) (*domain.CatalogStats, error) {
+	var byStatus []struct {
+		Status domain.TrackStatus
+		Count  int64
+	}
+	result := dbFor(ctx, r.db).
+		Model(&domain.Track{}).
+		Select("status, COUNT(*) AS count").
+		Where("deleted_at IS NULL").
+		Group("status").
+		Scan(&byStatus)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to count tracks by status: %w", result.Error)
+	}
+
+	var ai struct {
+		Unenriched        int64
+		NeedsReview       int64
+		AverageConfidence float64
+	}
+	if err := dbFor(ctx, r.db).Raw(catalogAIStatsSQL).Scan(&ai).Error; err != nil {
+		return nil, fmt.Errorf("failed to total AI metadata: %w", err)
+	}
+
+	stats := &domain.CatalogStats{
+		TracksByStatus:    make(map[domain.TrackStatus]int64, len(byStatus)),
+		NeedsReview:       ai.NeedsReview,
+		Unenriched:        ai.Unenriched,
+		AverageConfidence: ai.AverageConfidence,
+	}
+	for _, row := range byStatus {
+		stats.TracksByStatus[row.Status] = row.Count
+	}
+	return stats, nil
+}
@@ -0,0 +1,67 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+
+	"gorm.io/gorm"
+)
+
+// CustomFieldRepository implements domain.CustomFieldRepository using GORM
+type CustomFieldRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomFieldRepository creates a new custom field repository
+func NewCustomFieldRepository(db *gorm.DB) domain.CustomFieldRepository {
+	return &CustomFieldRepository{db: db}
+}
+
+// Save creates or replaces a definition
+func (r *CustomFieldRepository) Save(ctx context.Context, def *domain.CustomFieldDefinition) error {
+	if err := dbFor(ctx, r.db).Save(def).Error; err != nil {
+		return fmt.Errorf("failed to save custom field: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns a label's definition
+func (r *CustomFieldRepository) Get(ctx context.Context, labelID, name string) (*domain.CustomFieldDefinition, error) {
+	var def domain.CustomFieldDefinition
+	result := dbFor(ctx, r.db).Where("label_id = ? AND name = ?", labelID, name).First(&def)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrCustomFieldNotFound
+		}
+		return nil, fmt.Errorf("failed to get custom field: %w", result.Error)
+	}
+
+	return &def, nil
+}
+
+// ListByLabel returns a label's definitions ordered by name
+func (r *CustomFieldRepository) ListByLabel(ctx context.Context, labelID string) ([]*domain.CustomFieldDefinition, error) {
+	var defs []*domain.CustomFieldDefinition
+	result := dbFor(ctx, r.db).Where("label_id = ?", labelID).Order("name ASC").Find(&defs)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list custom fields: %w", result.Error)
+	}
+
+	return defs, nil
+}
+
+// Delete removes a label's definition
+func (r *CustomFieldRepository) Delete(ctx context.Context, labelID, name string) error {
+	result := dbFor(ctx, r.db).Where("label_id = ? AND name = ?", labelID, name).Delete(&domain.CustomFieldDefinition{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete custom field: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrCustomFieldNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"metadatatool/internal/pkg/config"
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+
+	"gorm.io/gorm"
+)
+
+// replicaLagQuery reports how far a Postgres standby is behind. A standby
+// that has replayed everything it received reports zero, and a server that
+// is not a standby returns NULL.
+const replicaLagQuery = `SELECT COALESCE(
+	CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) END, 0)`
+
+// Replica is a named read replica connection
+type Replica struct {
+	Name string
+	DB   *gorm.DB
+}
+
+type replicaState struct {
+	Replica
+	// usable is false while the replica is unreachable or lagging
+	usable atomic.Bool
+}
+
+// DBRouter sends writes to the primary and spreads reads across replicas
+// that are reachable and within the allowed lag. When no replica qualifies,
+// or the context asks for primary reads, reads go to the primary.
+type DBRouter struct {
+	primary  *gorm.DB
+	replicas []*replicaState
+	next     atomic.Uint64
+
+	maxLag        time.Duration
+	checkInterval time.Duration
+	measureLag    func(ctx context.Context, db *gorm.DB) (time.Duration, error)
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+	once   sync.Once
+}
+
+// NewDBRouter creates a router over a primary and optional replicas.
+// Replicas start out usable until the first lag check says otherwise.
+func NewDBRouter(primary *gorm.DB, replicas []Replica, maxLag, checkInterval time.Duration) *DBRouter {
+	if maxLag <= 0 {
+		maxLag = 5 * time.Second
+	}
+	if checkInterval <= 0 {
+		checkInterval = 10 * time.Second
+	}
+	r := &DBRouter{
+		primary:       primary,
+		maxLag:        maxLag,
+		checkInterval: checkInterval,
+		measureLag:    queryReplicaLag,
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+	}
+	for _, replica := range replicas {
+		state := &replicaState{Replica: replica}
+		state.usable.Store(true)
+		r.replicas = append(r.replicas, state)
+	}
+	return r
+}
+
+// Writer returns the primary database, or the transaction of the unit of
+// work ctx belongs to
+func (r *DBRouter) Writer(ctx context.Context) *gorm.DB {
+	return dbFor(ctx, r.primary)
+}
+
+// Reader returns a usable replica, round robin, or the primary. Inside a
+// unit of work it returns its transaction, so reads see its writes.
+func (r *DBRouter) Reader(ctx context.Context) *gorm.DB {
+	return dbFor(ctx, r.pickReader(ctx))
+}
+
+func (r *DBRouter) pickReader(ctx context.Context) *gorm.DB {
+	if len(r.replicas) == 0 || domain.PrimaryReadsFromContext(ctx) {
+		return r.primary
+	}
+
+	start := r.next.Add(1)
+	for i := range r.replicas {
+		replica := r.replicas[(start+uint64(i))%uint64(len(r.replicas))]
+		if replica.usable.Load() {
+			return replica.DB
+		}
+	}
+	return r.primary
+}
+
+// Start begins checking replica lag in the background
+func (r *DBRouter) Start(ctx context.Context) {
+	if len(r.replicas) == 0 {
+		close(r.doneCh)
+		return
+	}
+
+	r.CheckReplicas(ctx)
+	go func() {
+		defer close(r.doneCh)
+		ticker := time.NewTicker(r.checkInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.CheckReplicas(ctx)
+			case <-r.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the lag checks
+func (r *DBRouter) Stop() {
+	r.once.Do(func() {
+		close(r.stopCh)
+	})
+	<-r.doneCh
+}
+
+// CheckReplicas measures every replica's lag and takes replicas that are
+// unreachable or too far behind out of rotation until they catch up
+func (r *DBRouter) CheckReplicas(ctx context.Context) {
+	for _, replica := range r.replicas {
+		checkCtx, cancel := context.WithTimeout(ctx, r.checkInterval)
+		lag, err := r.measureLag(checkCtx, replica.DB)
+		cancel()
+
+		usable := err == nil && lag <= r.maxLag
+		if was := replica.usable.Swap(usable); was != usable {
+			if usable {
+				log.Printf("read replica %s back in rotation (lag %s)", replica.Name, lag)
+			} else if err != nil {
+				log.Printf("read replica %s out of rotation: %v", replica.Name, err)
+			} else {
+				log.Printf("read replica %s out of rotation: lag %s exceeds %s", replica.Name, lag, r.maxLag)
+			}
+		}
+		if err == nil {
+			metrics.DatabaseReplicaLag.WithLabelValues(replica.Name).Set(lag.Seconds())
+		}
+	}
+}
+
+func queryReplicaLag(ctx context.Context, db *gorm.DB) (time.Duration, error) {
+	var seconds float64
+	if err := db.WithContext(ctx).Raw(replicaLagQuery).Scan(&seconds).Error; err != nil {
+		return 0, fmt.Errorf("failed to measure replica lag: %w", err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// ConfigurePool applies connection pool settings to a database connection
+func ConfigurePool(db *gorm.DB, pool config.PoolConfig) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
+	if pool.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(pool.MaxOpenConns)
+	}
+	if pool.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(pool.MaxIdleConns)
+	}
+	if pool.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	}
+	if pool.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestDBRouter_LagAwareReadRouting(t *testing.T) {
+	primary, replicaA, replicaB := &gorm.DB{}, &gorm.DB{}, &gorm.DB{}
+	router := NewDBRouter(primary, []Replica{{Name: "a", DB: replicaA}, {Name: "b", DB: replicaB}}, time.Second, time.Minute)
+
+	lags := map[*gorm.DB]time.Duration{}
+	failing := map[*gorm.DB]bool{}
+	router.measureLag = func(ctx context.Context, db *gorm.DB) (time.Duration, error) {
+		if failing[db] {
+			return 0, errors.New("connection refused")
+		}
+		return lags[db], nil
+	}
+	ctx := context.Background()
+
+	// Healthy replicas share the reads
+	router.CheckReplicas(ctx)
+	seen := map[*gorm.DB]int{}
+	for i := 0; i < 4; i++ {
+		seen[router.pickReader(ctx)]++
+	}
+	assert.Equal(t, map[*gorm.DB]int{replicaA: 2, replicaB: 2}, seen)
+
+	// Reads that must see the latest writes use the primary
+	assert.Same(t, primary, router.pickReader(domain.WithPrimaryReads(ctx)))
+
+	// A lagging replica leaves rotation
+	lags[replicaA] = 10 * time.Second
+	router.CheckReplicas(ctx)
+	for i := 0; i < 3; i++ {
+		assert.Same(t, replicaB, router.pickReader(ctx))
+	}
+
+	// With every replica lagging or down reads fall back to the primary
+	failing[replicaB] = true
+	router.CheckReplicas(ctx)
+	assert.Same(t, primary, router.pickReader(ctx))
+
+	// Replicas return once they catch up
+	lags[replicaA] = 0
+	router.CheckReplicas(ctx)
+	assert.Same(t, replicaA, router.pickReader(ctx))
+}
+
+func TestDBRouter_WithoutReplicasUsesPrimary(t *testing.T) {
+	primary := &gorm.DB{}
+	router := NewDBRouter(primary, nil, 0, 0)
+
+	assert.Same(t, primary, router.pickReader(context.Background()))
+}
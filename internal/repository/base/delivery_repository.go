@@ -0,0 +1,99 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DeliveryRepository implements domain.DeliveryRepository using GORM
+type DeliveryRepository struct {
+	db *gorm.DB
+}
+
+// NewDeliveryRepository creates a new delivery repository
+func NewDeliveryRepository(db *gorm.DB) domain.DeliveryRepository {
+	return &DeliveryRepository{db: db}
+}
+
+// Create stores new deliveries
+func (r *DeliveryRepository) Create(ctx context.Context, deliveries []*domain.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := dbFor(ctx, r.db).Create(&deliveries).Error; err != nil {
+		return fmt.Errorf("failed to record deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// ListByPackage returns the deliveries sent in a package
+func (r *DeliveryRepository) ListByPackage(ctx context.Context, packageID string) ([]*domain.Delivery, error) {
+	var deliveries []*domain.Delivery
+	result := dbFor(ctx, r.db).Where("package_id = ?", packageID).Order("track_id ASC").Find(&deliveries)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list package deliveries: %w", result.Error)
+	}
+
+	return deliveries, nil
+}
+
+// List returns the deliveries matching filter, newest first
+func (r *DeliveryRepository) List(ctx context.Context, filter domain.DeliveryFilter) ([]*domain.Delivery, error) {
+	db := dbFor(ctx, r.db)
+	if filter.DSP != "" {
+		db = db.Where("dsp = ?", filter.DSP)
+	}
+	if filter.TrackID != "" {
+		db = db.Where("track_id = ?", filter.TrackID)
+	}
+	if filter.ReleaseID != "" {
+		db = db.Where("release_id = ?", filter.ReleaseID)
+	}
+	if filter.Kind != "" {
+		db = db.Where("kind = ?", filter.Kind)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+
+	var deliveries []*domain.Delivery
+	result := db.Order("delivered_at DESC, id ASC").Offset(filter.Offset).Limit(filter.Limit).Find(&deliveries)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", result.Error)
+	}
+
+	return deliveries, nil
+}
+
+// UpdateStatus sets the status and error of every delivery of a package
+func (r *DeliveryRepository) UpdateStatus(ctx context.Context, packageID string, status domain.DeliveryStatus, errText string, at time.Time) (int64, error) {
+	result := dbFor(ctx, r.db).
+		Model(&domain.Delivery{}).
+		Where("package_id = ?", packageID).
+		Updates(map[string]interface{}{
+			"status":          status,
+			"error":           errText,
+			"acknowledged_at": at,
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to update package deliveries: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// RewriteTrackReferences moves the deliveries of fromID to toID
+func (r *DeliveryRepository) RewriteTrackReferences(ctx context.Context, fromID, toID string) (int64, error) {
+	result := dbFor(ctx, r.db).Model(&domain.Delivery{}).Where("track_id = ?", fromID).Update("track_id", toID)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to move deliveries: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
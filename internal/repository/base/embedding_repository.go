@@ -0,0 +1,169 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EmbeddingRepository implements domain.EmbeddingRepository on PostgreSQL
+// with the pgvector extension. The embedding column has a fixed size so it
+// can be indexed; vectors are padded with zeros to fill it, which leaves
+// their cosine distances unchanged, and cut back to size when read.
+type EmbeddingRepository struct {
+	db *gorm.DB
+}
+
+// NewEmbeddingRepository creates a new embedding repository
+func NewEmbeddingRepository(db *gorm.DB) domain.EmbeddingRepository {
+	return &EmbeddingRepository{db: db}
+}
+
+const saveEmbeddingSQL = `INSERT INTO track_embeddings (track_id, model, dimensions, embedding, updated_at)
+VALUES (?, ?, ?, ?::vector, ?)
+ON CONFLICT (track_id) DO UPDATE SET
+	model = EXCLUDED.model,
+	dimensions = EXCLUDED.dimensions,
+	embedding = EXCLUDED.embedding,
+	updated_at = EXCLUDED.updated_at`
+
+// nearestEmbeddingsSQL orders the embeddings of the same model and size by
+// their cosine distance to the track's, skipping deleted tracks. Ordering
+// by the distance to a single vector lets the HNSW index serve the search,
+// which makes it approximate.
+const nearestEmbeddingsSQL = `WITH q AS (
+	SELECT track_id, model, dimensions, embedding FROM track_embeddings WHERE track_id = ?
+)
+SELECT e.track_id, e.embedding <=> (SELECT embedding FROM q) AS distance
+FROM track_embeddings e
+JOIN tracks t ON t.id = e.track_id AND t.deleted_at IS NULL
+WHERE e.model = (SELECT model FROM q)
+	AND e.dimensions = (SELECT dimensions FROM q)
+	AND e.track_id <> (SELECT track_id FROM q)
+ORDER BY e.embedding <=> (SELECT embedding FROM q)
+LIMIT ?`
+
+// Save creates or replaces the embedding of a track
+func (r *EmbeddingRepository) Save(ctx context.Context, embedding *domain.TrackEmbedding) error {
+	if embedding.UpdatedAt.IsZero() {
+		embedding.UpdatedAt = time.Now()
+	}
+	err := dbFor(ctx, r.db).Exec(saveEmbeddingSQL, embedding.TrackID, embedding.Model, len(embedding.Vector),
+		formatVector(padVector(embedding.Vector, domain.MaxEmbeddingDimensions)), embedding.UpdatedAt).Error
+	if err != nil {
+		return fmt.Errorf("failed to save embedding: %w", err)
+	}
+	return nil
+}
+
+// Get returns the embedding of a track
+func (r *EmbeddingRepository) Get(ctx context.Context, trackID string) (*domain.TrackEmbedding, error) {
+	var rows []struct {
+		TrackID    string
+		Model      string
+		Dimensions int
+		Embedding  string
+		UpdatedAt  time.Time
+	}
+	err := dbFor(ctx, r.db).
+		Raw("SELECT track_id, model, dimensions, embedding::text AS embedding, updated_at FROM track_embeddings WHERE track_id = ?", trackID).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get embedding: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, domain.ErrEmbeddingNotFound
+	}
+	vector, err := parseVector(rows[0].Embedding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse embedding of track %s: %w", trackID, err)
+	}
+	if rows[0].Dimensions < len(vector) {
+		vector = vector[:rows[0].Dimensions]
+	}
+	return &domain.TrackEmbedding{
+		TrackID:   rows[0].TrackID,
+		Model:     rows[0].Model,
+		Vector:    vector,
+		UpdatedAt: rows[0].UpdatedAt,
+	}, nil
+}
+
+// Nearest returns the tracks whose embeddings are nearest to the track's
+func (r *EmbeddingRepository) Nearest(ctx context.Context, trackID string, limit int) ([]domain.TrackNeighbor, error) {
+	var rows []struct {
+		TrackID  string
+		Distance float64
+	}
+	if err := dbFor(ctx, r.db).Raw(nearestEmbeddingsSQL, trackID, limit).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to search embeddings: %w", err)
+	}
+	if len(rows) == 0 {
+		// Nothing is near a track without an embedding; tell it apart from
+		// a track that is alone with its model
+		var count int64
+		if err := dbFor(ctx, r.db).Table("track_embeddings").Where("track_id = ?", trackID).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("failed to get embedding: %w", err)
+		}
+		if count == 0 {
+			return nil, domain.ErrEmbeddingNotFound
+		}
+	}
+
+	neighbors := make([]domain.TrackNeighbor, len(rows))
+	for i, row := range rows {
+		neighbors[i] = domain.TrackNeighbor{TrackID: row.TrackID, Distance: row.Distance}
+	}
+	return neighbors, nil
+}
+
+// padVector returns vector extended with zeros to size dimensions
+func padVector(vector []float32, size int) []float32 {
+	if len(vector) >= size {
+		return vector
+	}
+	padded := make([]float32, size)
+	copy(padded, vector)
+	return padded
+}
+
+// formatVector writes a vector in the text form of pgvector, "[1,2,3]"
+func formatVector(vector []float32) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, v := range vector {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// parseVector reads a vector in the text form of pgvector
+func parseVector(text string) ([]float32, error) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "[") || !strings.HasSuffix(text, "]") {
+		return nil, fmt.Errorf("malformed vector %q", text)
+	}
+	text = strings.TrimSpace(text[1 : len(text)-1])
+	if text == "" {
+		return []float32{}, nil
+	}
+	parts := strings.Split(text, ",")
+	vector := make([]float32, len(parts))
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return nil, fmt.Errorf("malformed vector value %q: %w", part, err)
+		}
+		vector[i] = float32(v)
+	}
+	return vector, nil
+}
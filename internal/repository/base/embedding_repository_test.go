@@ -0,0 +1,20 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPadVector(t *testing.T) {
+	padded := padVector([]float32{0.5, -1}, 4)
+	assert.Equal(t, []float32{0.5, -1, 0, 0}, padded)
+
+	vector, err := parseVector(formatVector(padded))
+	require.NoError(t, err)
+	assert.Equal(t, padded, vector)
+
+	full := []float32{1, 2}
+	assert.Equal(t, full, padVector(full, 2))
+}
@@ -0,0 +1,55 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GoldenDatasetRepository implements domain.GoldenDatasetRepository using
+// GORM
+type GoldenDatasetRepository struct {
+	db *gorm.DB
+}
+
+// NewGoldenDatasetRepository creates a new golden dataset repository
+func NewGoldenDatasetRepository(db *gorm.DB) domain.GoldenDatasetRepository {
+	return &GoldenDatasetRepository{db: db}
+}
+
+// Save adds a track to the dataset or replaces its values, keeping the
+// time it was first added
+func (r *GoldenDatasetRepository) Save(ctx context.Context, track *domain.GoldenTrack) error {
+	err := dbFor(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "track_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"genre", "mood", "bpm", "key", "labeled_by", "updated_at"}),
+	}).Create(track).Error
+	if err != nil {
+		return fmt.Errorf("failed to save golden track: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a track from the dataset
+func (r *GoldenDatasetRepository) Delete(ctx context.Context, trackID string) error {
+	result := dbFor(ctx, r.db).Where("track_id = ?", trackID).Delete(&domain.GoldenTrack{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete golden track: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrGoldenTrackNotFound
+	}
+	return nil
+}
+
+// List returns the dataset ordered by track ID
+func (r *GoldenDatasetRepository) List(ctx context.Context) ([]*domain.GoldenTrack, error) {
+	var tracks []*domain.GoldenTrack
+	if err := dbFor(ctx, r.db).Order("track_id ASC").Find(&tracks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list golden tracks: %w", err)
+	}
+	return tracks, nil
+}
@@ -0,0 +1,76 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+
+	"gorm.io/gorm"
+)
+
+// ImportMappingRepository implements domain.ImportMappingRepository using GORM
+type ImportMappingRepository struct {
+	db *gorm.DB
+}
+
+// NewImportMappingRepository creates a new import mapping repository
+func NewImportMappingRepository(db *gorm.DB) domain.ImportMappingRepository {
+	return &ImportMappingRepository{db: db}
+}
+
+// Save creates or replaces a mapping
+func (r *ImportMappingRepository) Save(ctx context.Context, mapping *domain.ImportMapping) error {
+	if err := dbFor(ctx, r.db).Save(mapping).Error; err != nil {
+		return fmt.Errorf("failed to save import mapping: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID returns a label's mapping
+func (r *ImportMappingRepository) GetByID(ctx context.Context, labelID, id string) (*domain.ImportMapping, error) {
+	return r.get(ctx, "label_id = ? AND id = ?", labelID, id)
+}
+
+// GetByName returns a label's mapping by name
+func (r *ImportMappingRepository) GetByName(ctx context.Context, labelID, name string) (*domain.ImportMapping, error) {
+	return r.get(ctx, "label_id = ? AND name = ?", labelID, name)
+}
+
+func (r *ImportMappingRepository) get(ctx context.Context, query string, args ...interface{}) (*domain.ImportMapping, error) {
+	var mapping domain.ImportMapping
+	result := dbFor(ctx, r.db).Where(query, args...).First(&mapping)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrImportMappingNotFound
+		}
+		return nil, fmt.Errorf("failed to get import mapping: %w", result.Error)
+	}
+
+	return &mapping, nil
+}
+
+// ListByLabel returns a label's mappings ordered by name
+func (r *ImportMappingRepository) ListByLabel(ctx context.Context, labelID string) ([]*domain.ImportMapping, error) {
+	var mappings []*domain.ImportMapping
+	result := dbFor(ctx, r.db).Where("label_id = ?", labelID).Order("name ASC").Find(&mappings)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list import mappings: %w", result.Error)
+	}
+
+	return mappings, nil
+}
+
+// Delete removes a label's mapping
+func (r *ImportMappingRepository) Delete(ctx context.Context, labelID, id string) error {
+	result := dbFor(ctx, r.db).Where("label_id = ? AND id = ?", labelID, id).Delete(&domain.ImportMapping{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete import mapping: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrImportMappingNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JobHistoryRepository implements domain.JobHistoryRepository using GORM.
+// The reports use PostgreSQL functions.
+type JobHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewJobHistoryRepository creates a new job history repository
+func NewJobHistoryRepository(db *gorm.DB) domain.JobHistoryRepository {
+	return &JobHistoryRepository{db: db}
+}
+
+// Save stores archived jobs. A job archived again, because it could not be
+// removed from the queue the first time, keeps its first record.
+func (r *JobHistoryRepository) Save(ctx context.Context, jobs []*domain.JobHistory) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	for _, job := range jobs {
+		job.ArchivedAt = now
+	}
+	result := dbFor(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&jobs)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save job history: %w", result.Error)
+	}
+
+	return nil
+}
+
+// List returns a page of the archived jobs matching filter, newest first
+func (r *JobHistoryRepository) List(ctx context.Context, filter domain.JobHistoryFilter) ([]*domain.JobHistory, int64, error) {
+	var total int64
+	if err := r.filtered(ctx, filter).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count job history: %w", err)
+	}
+
+	var jobs []*domain.JobHistory
+	result := r.filtered(ctx, filter).Order("finished_at DESC").Offset(filter.Offset).Limit(filter.Limit).Find(&jobs)
+	if result.Error != nil {
+		return nil, 0, fmt.Errorf("failed to list job history: %w", result.Error)
+	}
+
+	return jobs, total, nil
+}
+
+// filtered selects the archived jobs matching filter
+func (r *JobHistoryRepository) filtered(ctx context.Context, filter domain.JobHistoryFilter) *gorm.DB {
+	db := dbFor(ctx, r.db).Model(&domain.JobHistory{})
+	if filter.Type != "" {
+		db = db.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	if !filter.From.IsZero() {
+		db = db.Where("finished_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		db = db.Where("finished_at < ?", filter.To)
+	}
+	return db
+}
+
+// WeeklyStats counts the jobs finished in [from, to) per UTC week and type
+func (r *JobHistoryRepository) WeeklyStats(ctx context.Context, from, to time.Time) ([]domain.JobWeekStats, error) {
+	var stats []domain.JobWeekStats
+	result := dbFor(ctx, r.db).
+		Model(&domain.JobHistory{}).
+		Select(`to_char(date_trunc('week', finished_at AT TIME ZONE 'UTC'), 'YYYY-MM-DD') AS week, type,
+			COUNT(*) FILTER (WHERE status = ?) AS completed,
+			COUNT(*) FILTER (WHERE status = ?) AS failed`, domain.JobStatusCompleted, domain.JobStatusFailed).
+		Where("finished_at >= ? AND finished_at < ?", from, to).
+		Group("week, type").
+		Order("week ASC, type ASC").
+		Scan(&stats)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to count jobs per week: %w", result.Error)
+	}
+
+	for i := range stats {
+		if total := stats[i].Completed + stats[i].Failed; total > 0 {
+			stats[i].SuccessRate = float64(stats[i].Completed) / float64(total)
+		}
+	}
+	return stats, nil
+}
+
+// TypeDurations returns the limit job types that ran longest on average in
+// [from, to). Jobs that never started are left out.
+func (r *JobHistoryRepository) TypeDurations(ctx context.Context, from, to time.Time, limit int) ([]domain.JobTypeDuration, error) {
+	var durations []domain.JobTypeDuration
+	result := dbFor(ctx, r.db).
+		Model(&domain.JobHistory{}).
+		Select(`type, COUNT(*) AS jobs, AVG(duration_ms) AS avg_ms,
+			percentile_cont(0.95) WITHIN GROUP (ORDER BY duration_ms) AS p95_ms,
+			MAX(duration_ms) AS max_ms`).
+		Where("finished_at >= ? AND finished_at < ? AND duration_ms IS NOT NULL", from, to).
+		Group("type").
+		Order("avg_ms DESC").
+		Limit(limit).
+		Scan(&durations)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to measure job durations: %w", result.Error)
+	}
+
+	return durations, nil
+}
@@ -0,0 +1,32 @@
+package base
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestJobHistoryRepository_SaveKeepsTheFirstRecord(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=test"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	require.NoError(t, err)
+
+	var sql string
+	require.NoError(t, db.Callback().Create().After("gorm:create").Register("test:capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	}))
+
+	repo := NewJobHistoryRepository(db)
+	jobs := []*domain.JobHistory{{ID: "job-1", Queue: "jobs", Type: domain.JobTypeDDEXExport, Status: domain.JobStatusCompleted, FinishedAt: time.Now()}}
+	require.NoError(t, repo.Save(context.Background(), jobs))
+
+	assert.Contains(t, sql, `INSERT INTO "jobs_history"`)
+	assert.Contains(t, sql, `ON CONFLICT DO NOTHING`)
+	assert.False(t, jobs[0].ArchivedAt.IsZero())
+}
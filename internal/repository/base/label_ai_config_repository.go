@@ -0,0 +1,79 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+
+	"gorm.io/gorm"
+)
+
+// LabelAIConfigRepository implements domain.LabelAIConfigRepository using
+// GORM, encrypting API keys with a cipher
+type LabelAIConfigRepository struct {
+	db     *gorm.DB
+	cipher domain.SecretCipher
+}
+
+// NewLabelAIConfigRepository creates a new label AI config repository
+func NewLabelAIConfigRepository(db *gorm.DB, cipher domain.SecretCipher) domain.LabelAIConfigRepository {
+	return &LabelAIConfigRepository{db: db, cipher: cipher}
+}
+
+// Save creates or replaces a label's configuration, encrypting its API key
+func (r *LabelAIConfigRepository) Save(ctx context.Context, config *domain.LabelAIConfig) error {
+	config.EncryptedAPIKey = ""
+	if config.APIKey != "" {
+		encrypted, err := r.cipher.Encrypt(config.APIKey)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt API key: %w", err)
+		}
+		config.EncryptedAPIKey = encrypted
+	}
+	if err := dbFor(ctx, r.db).Save(config).Error; err != nil {
+		return fmt.Errorf("failed to save label AI config: %w", err)
+	}
+	return nil
+}
+
+// Get returns a label's configuration with its API key decrypted
+func (r *LabelAIConfigRepository) Get(ctx context.Context, labelID string) (*domain.LabelAIConfig, error) {
+	var config domain.LabelAIConfig
+	result := dbFor(ctx, r.db).Where("label_id = ?", labelID).First(&config)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrLabelAIConfigNotFound
+		}
+		return nil, fmt.Errorf("failed to get label AI config: %w", result.Error)
+	}
+	if config.EncryptedAPIKey != "" {
+		apiKey, err := r.cipher.Decrypt(config.EncryptedAPIKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt API key of label %s: %w", labelID, err)
+		}
+		config.APIKey = apiKey
+	}
+	return &config, nil
+}
+
+// List returns the configurations ordered by label, without API keys
+func (r *LabelAIConfigRepository) List(ctx context.Context) ([]*domain.LabelAIConfig, error) {
+	var configs []*domain.LabelAIConfig
+	if err := dbFor(ctx, r.db).Order("label_id ASC").Find(&configs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list label AI configs: %w", err)
+	}
+	return configs, nil
+}
+
+// Delete removes a label's configuration
+func (r *LabelAIConfigRepository) Delete(ctx context.Context, labelID string) error {
+	result := dbFor(ctx, r.db).Where("label_id = ?", labelID).Delete(&domain.LabelAIConfig{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete label AI config: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrLabelAIConfigNotFound
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+
+	"gorm.io/gorm"
+)
+
+// LabelRepository implements domain.LabelRepository using GORM
+type LabelRepository struct {
+	db *gorm.DB
+}
+
+// NewLabelRepository creates a new label repository
+func NewLabelRepository(db *gorm.DB) domain.LabelRepository {
+	return &LabelRepository{db: db}
+}
+
+// Create adds a label
+func (r *LabelRepository) Create(ctx context.Context, label *domain.Label) error {
+	if err := dbFor(ctx, r.db).Create(label).Error; err != nil {
+		return fmt.Errorf("failed to create label: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID returns a label
+func (r *LabelRepository) GetByID(ctx context.Context, id string) (*domain.Label, error) {
+	var label domain.Label
+	result := dbFor(ctx, r.db).Where("id = ?", id).First(&label)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrLabelNotFound
+		}
+		return nil, fmt.Errorf("failed to get label: %w", result.Error)
+	}
+
+	return &label, nil
+}
+
+// List returns the labels ordered by ID
+func (r *LabelRepository) List(ctx context.Context) ([]*domain.Label, error) {
+	var labels []*domain.Label
+	if err := dbFor(ctx, r.db).Order("id ASC").Find(&labels).Error; err != nil {
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+
+	return labels, nil
+}
@@ -0,0 +1,98 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LabelStatsRepository implements domain.LabelStatsRepository using GORM.
+// The metadata fields are read from the metadata JSON.
+type LabelStatsRepository struct {
+	db *gorm.DB
+}
+
+// NewLabelStatsRepository creates a new label statistics repository
+func NewLabelStatsRepository(db *gorm.DB) domain.LabelStatsRepository {
+	return &LabelStatsRepository{db: db}
+}
+
+// labelTotalsSQL totals the tracks of a label that are not deleted
+const labelTotalsSQL = `SELECT
+	COUNT(*) AS tracks,
+	COALESCE(SUM((metadata->'basic'->>'duration')::float8), 0) AS duration_seconds,
+	COALESCE(SUM(file_size), 0) AS storage_bytes,
+	COUNT(*) FILTER (WHERE jsonb_typeof(metadata->'ai') = 'object') AS enriched,
+	COALESCE(AVG((metadata->'ai'->>'confidence')::float8) FILTER (WHERE jsonb_typeof(metadata->'ai') = 'object'), 0) AS average_confidence
+FROM tracks
+WHERE deleted_at IS NULL AND label_id = ?`
+
+// labelCountsSQL counts the tracks of a label by the value of an
+// expression, with blank values counted under the bound key
+const labelCountsSQL = `SELECT COALESCE(%s, ?) AS key, COUNT(*) AS count
+FROM tracks
+WHERE deleted_at IS NULL AND label_id = ?
+GROUP BY 1`
+
+// LabelStats computes the aggregates of a label's tracks
+func (r *LabelStatsRepository) LabelStats(ctx context.Context, labelID string) (*domain.LabelStats, error) {
+	var totals struct {
+		Tracks            int64
+		DurationSeconds   float64
+		StorageBytes      int64
+		Enriched          int64
+		AverageConfidence float64
+	}
+	if err := dbFor(ctx, r.db).Raw(labelTotalsSQL, labelID).Scan(&totals).Error; err != nil {
+		return nil, fmt.Errorf("failed to total label tracks: %w", err)
+	}
+
+	stats := &domain.LabelStats{
+		LabelID:           labelID,
+		Tracks:            totals.Tracks,
+		ByStatus:          make(map[domain.TrackStatus]int64),
+		DurationSeconds:   totals.DurationSeconds,
+		StorageBytes:      totals.StorageBytes,
+		Enriched:          totals.Enriched,
+		AverageConfidence: totals.AverageConfidence,
+		ComputedAt:        time.Now().UTC(),
+	}
+	if totals.Tracks > 0 {
+		stats.EnrichmentCoverage = float64(totals.Enriched) / float64(totals.Tracks)
+	}
+
+	byStatus, err := r.countBy(ctx, labelID, "NULLIF(status, '')", "status")
+	if err != nil {
+		return nil, err
+	}
+	for status, count := range byStatus {
+		stats.ByStatus[domain.TrackStatus(status)] = count
+	}
+	if stats.ByGenre, err = r.countBy(ctx, labelID, "NULLIF(metadata->'musical'->>'genre', '')", "genre"); err != nil {
+		return nil, err
+	}
+	if stats.ByYear, err = r.countBy(ctx, labelID, "NULLIF(metadata->'basic'->>'year', '0')", "year"); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// countBy counts the tracks of a label by the value of expr
+func (r *LabelStatsRepository) countBy(ctx context.Context, labelID, expr, name string) (map[string]int64, error) {
+	var rows []struct {
+		Key   string
+		Count int64
+	}
+	query := fmt.Sprintf(labelCountsSQL, expr)
+	if err := dbFor(ctx, r.db).Raw(query, domain.UnknownStatsKey, labelID).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count label tracks by %s: %w", name, err)
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Key] = row.Count
+	}
+	return counts, nil
+}
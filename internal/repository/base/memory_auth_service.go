@@ -5,7 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
-	"metadatatool/internal/domain"
+	"metadatatool/internal/pkg/domain"
 	"sync"
 	"time"
 
@@ -40,7 +40,7 @@ func (s *InMemoryAuthService) GenerateToken(ctx context.Context, user *domain.Us
 }
 
 // ValidateToken validates a token and returns the claims
-func (s *InMemoryAuthService) ValidateToken(ctx context.Context, token string) (*domain.TokenClaims, error) {
+func (s *InMemoryAuthService) ValidateToken(ctx context.Context, token string) (*domain.Claims, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -49,10 +49,8 @@ func (s *InMemoryAuthService) ValidateToken(ctx context.Context, token string) (
 		return nil, fmt.Errorf("invalid token")
 	}
 
-	claims := &domain.TokenClaims{
-		Claims: domain.Claims{
-			UserID: userID,
-		},
+	claims := &domain.Claims{
+		UserID: userID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -63,7 +61,7 @@ func (s *InMemoryAuthService) ValidateToken(ctx context.Context, token string) (
 }
 
 // GenerateTokens creates a new pair of access and refresh tokens
-func (s *InMemoryAuthService) GenerateTokens(user *domain.User) (*domain.Tokens, error) {
+func (s *InMemoryAuthService) GenerateTokens(user *domain.User) (*domain.TokenPair, error) {
 	accessToken, err := s.GenerateToken(context.Background(), user)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
@@ -74,14 +72,14 @@ func (s *InMemoryAuthService) GenerateTokens(user *domain.User) (*domain.Tokens,
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
-	return &domain.Tokens{
+	return &domain.TokenPair{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 	}, nil
 }
 
 // RefreshToken validates a refresh token and generates new token pair
-func (s *InMemoryAuthService) RefreshToken(refreshToken string) (*domain.Tokens, error) {
+func (s *InMemoryAuthService) RefreshToken(refreshToken string) (*domain.TokenPair, error) {
 	claims, err := s.ValidateToken(context.Background(), refreshToken)
 	if err != nil {
 		return nil, fmt.Errorf("invalid refresh token: %w", err)
@@ -118,27 +116,9 @@ func (s *InMemoryAuthService) GenerateAPIKey() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
-// rolePermissions maps roles to their allowed permissions
-var rolePermissions = map[domain.Role][]domain.Permission{
-	domain.RoleAdmin: {
-		domain.PermissionReadTrack,
-		domain.PermissionWriteTrack,
-		domain.PermissionDeleteTrack,
-		domain.PermissionReadLabel,
-		domain.PermissionWriteLabel,
-		domain.PermissionDeleteLabel,
-		domain.PermissionManageAPIKeys,
-	},
-	domain.RoleUser: {
-		domain.PermissionReadTrack,
-		domain.PermissionWriteTrack,
-		domain.PermissionReadLabel,
-	},
-}
-
 // HasPermission checks if a role has a specific permission
 func (s *InMemoryAuthService) HasPermission(role domain.Role, permission domain.Permission) bool {
-	permissions := rolePermissions[role]
+	permissions := domain.RolePermissions[role]
 	for _, p := range permissions {
 		if p == permission {
 			return true
@@ -149,5 +129,5 @@ func (s *InMemoryAuthService) HasPermission(role domain.Role, permission domain.
 
 // GetPermissions returns all permissions for a role
 func (s *InMemoryAuthService) GetPermissions(role domain.Role) []domain.Permission {
-	return rolePermissions[role]
+	return domain.RolePermissions[role]
 }
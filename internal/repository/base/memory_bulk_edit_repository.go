@@ -0,0 +1,47 @@
+package base
+
+import (
+	"context"
+	"metadatatool/internal/pkg/domain"
+	"sync"
+)
+
+// InMemoryBulkEditJobRepository implements domain.BulkEditJobRepository in memory
+type InMemoryBulkEditJobRepository struct {
+	jobs map[string]*domain.BulkEditJob
+	mu   sync.RWMutex
+}
+
+// NewInMemoryBulkEditJobRepository creates a new in-memory bulk edit job repository
+func NewInMemoryBulkEditJobRepository() domain.BulkEditJobRepository {
+	return &InMemoryBulkEditJobRepository{
+		jobs: make(map[string]*domain.BulkEditJob),
+	}
+}
+
+// Save stores a copy of the job
+func (r *InMemoryBulkEditJobRepository) Save(ctx context.Context, job *domain.BulkEditJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.jobs[job.ID] = copyBulkEditJob(job)
+	return nil
+}
+
+// GetByID retrieves a copy of the job, or nil if it does not exist
+func (r *InMemoryBulkEditJobRepository) GetByID(ctx context.Context, id string) (*domain.BulkEditJob, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job, exists := r.jobs[id]
+	if !exists {
+		return nil, nil
+	}
+	return copyBulkEditJob(job), nil
+}
+
+func copyBulkEditJob(job *domain.BulkEditJob) *domain.BulkEditJob {
+	cp := *job
+	cp.Results = append([]domain.BulkEditResult(nil), job.Results...)
+	return &cp
+}
@@ -3,7 +3,7 @@ package base
 import (
 	"context"
 	"fmt"
-	"metadatatool/internal/domain"
+	"metadatatool/internal/pkg/domain"
 	"sync"
 	"time"
 
@@ -0,0 +1,82 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OutboxRepository implements domain.OutboxRepository using GORM
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository creates a new track change outbox repository
+func NewOutboxRepository(db *gorm.DB) domain.OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// FetchPending returns unpublished events in sequence order
+func (r *OutboxRepository) FetchPending(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	var events []*domain.OutboxEvent
+	result := dbFor(ctx, r.db).
+		Where("published_at IS NULL").
+		Order("sequence ASC").
+		Limit(limit).
+		Find(&events)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to fetch pending outbox events: %w", result.Error)
+	}
+
+	return events, nil
+}
+
+// MarkPublished records that an event reached the queue
+func (r *OutboxRepository) MarkPublished(ctx context.Context, sequence int64) error {
+	result := dbFor(ctx, r.db).
+		Model(&domain.OutboxEvent{}).
+		Where("sequence = ?", sequence).
+		Updates(map[string]interface{}{
+			"published_at": time.Now(),
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_error":   "",
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark outbox event published: %w", result.Error)
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed publish attempt
+func (r *OutboxRepository) MarkFailed(ctx context.Context, sequence int64, err error) error {
+	result := dbFor(ctx, r.db).
+		Model(&domain.OutboxEvent{}).
+		Where("sequence = ?", sequence).
+		Updates(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": err.Error(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark outbox event failed: %w", result.Error)
+	}
+
+	return nil
+}
+
+// PendingCount returns the number of unpublished events
+func (r *OutboxRepository) PendingCount(ctx context.Context) (int64, error) {
+	var count int64
+	result := dbFor(ctx, r.db).
+		Model(&domain.OutboxEvent{}).
+		Where("published_at IS NULL").
+		Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to count pending outbox events: %w", result.Error)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,335 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"metadatatool/internal/pkg/database"
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+// batchUpdateChunk bounds the rows per UPDATE statement so the bind
+// parameters stay well below Postgres' limit of 65535
+const batchUpdateChunk = 1000
+
+// trackColumn is a column of the tracks table with the type its bind
+// parameter is cast to in multi-row statements
+type trackColumn struct {
+	name string
+	cast string
+}
+
+// trackColumns lists the persisted track columns in the order trackValues
+// returns them. The in-memory audio data is never stored.
+var trackColumns = []trackColumn{
+	{"id", "uuid"},
+	{"created_at", "timestamptz"},
+	{"updated_at", "timestamptz"},
+	{"deleted_at", "timestamptz"},
+	{"storage_path", "text"},
+	{"file_path", "text"},
+	{"file_size", "bigint"},
+	{"metadata", "jsonb"},
+	{"label_id", "text"},
+	{"artist_ids", "jsonb"},
+	{"release_id", "text"},
+	{"version", "bigint"},
+	{"previous_id", "text"},
+	{"status", "text"},
+	{"status_msg", "text"},
+}
+
+var outboxColumns = []string{"aggregate_id", "event_type", "version", "payload", "created_at"}
+
+var errCopyUnsupported = errors.New("database driver does not support COPY")
+
+// trackValues returns the column values of a track in trackColumns order
+func trackValues(track *domain.Track) ([]interface{}, error) {
+	id, err := uuid.Parse(track.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid track ID %q: %w", track.ID, err)
+	}
+	metadata, err := json.Marshal(track.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata of track %s: %w", track.ID, err)
+	}
+	artistIDs, err := json.Marshal(track.ArtistIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal artist IDs of track %s: %w", track.ID, err)
+	}
+
+	return []interface{}{
+		id,
+		track.CreatedAt,
+		track.UpdatedAt,
+		track.DeletedAt,
+		track.StoragePath,
+		track.FilePath,
+		track.FileSize,
+		metadata,
+		track.LabelID,
+		artistIDs,
+		track.ReleaseID,
+		track.Version,
+		track.PreviousID,
+		string(track.Status),
+		track.StatusMsg,
+	}, nil
+}
+
+// BatchCreate inserts all tracks and their outbox events in one transaction
+// using COPY. Inside a unit of work, and on connections that are not served
+// by pgx, they are written with batched INSERT statements instead.
+func (r *PkgTrackRepository) BatchCreate(ctx context.Context, tracks []*domain.Track) error {
+	if len(tracks) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	rows := make([][]interface{}, len(tracks))
+	events := make([]*domain.OutboxEvent, len(tracks))
+	eventRows := make([][]interface{}, len(tracks))
+	for i, track := range tracks {
+		track.CreatedAt = now
+		track.UpdatedAt = now
+		if track.ID == "" {
+			track.ID = uuid.New().String()
+		}
+		if track.Version == 0 {
+			track.Version = 1
+		}
+
+		values, err := trackValues(track)
+		if err != nil {
+			return err
+		}
+		rows[i] = values
+
+		event, err := domain.NewTrackOutboxEvent(domain.TrackChangeCreated, track)
+		if err != nil {
+			return err
+		}
+		events[i] = event
+		eventRows[i] = []interface{}{event.AggregateID, string(event.EventType), event.Version, event.Payload, event.CreatedAt}
+	}
+
+	// COPY runs on a connection of its own, so inside a unit of work the
+	// tracks are inserted in its transaction instead
+	err := errCopyUnsupported
+	if _, ok := transaction(ctx); !ok {
+		err = r.copyTracks(ctx, rows, eventRows)
+	}
+	if errors.Is(err, errCopyUnsupported) {
+		err = dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+			if err := tx.CreateInBatches(tracks, batchUpdateChunk).Error; err != nil {
+				return fmt.Errorf("failed to create tracks: %w", err)
+			}
+			if err := tx.CreateInBatches(events, batchUpdateChunk).Error; err != nil {
+				return fmt.Errorf("failed to write outbox events: %w", err)
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	r.publishCreated(ctx, tracks...)
+	return nil
+}
+
+// copyTracks streams the rows into tracks and track_outbox with COPY inside
+// a single pgx transaction
+func (r *PkgTrackRepository) copyTracks(ctx context.Context, rows, eventRows [][]interface{}) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer conn.Close()
+
+	columns := make([]string, len(trackColumns))
+	for i, column := range trackColumns {
+		columns[i] = column.name
+	}
+
+	return conn.Raw(func(driverConn interface{}) error {
+		pgxConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errCopyUnsupported
+		}
+		return pgx.BeginFunc(ctx, pgxConn.Conn(), func(tx pgx.Tx) error {
+			if _, err := tx.CopyFrom(ctx, pgx.Identifier{"tracks"}, columns, pgx.CopyFromRows(rows)); err != nil {
+				return fmt.Errorf("failed to copy tracks: %w", err)
+			}
+			if _, err := tx.CopyFrom(ctx, pgx.Identifier{"track_outbox"}, outboxColumns, pgx.CopyFromRows(eventRows)); err != nil {
+				return fmt.Errorf("failed to write outbox events: %w", err)
+			}
+			return nil
+		})
+	})
+}
+
+// BatchUpdate updates multiple tracks in a single transaction, rolling back if
+// any track fails its version check. On PostgreSQL tracks are written with
+// one multi-row UPDATE ... FROM (VALUES ...) per chunk rather than a
+// statement per track.
+func (r *PkgTrackRepository) BatchUpdate(ctx context.Context, tracks []*domain.Track) error {
+	if len(tracks) == 0 {
+		return nil
+	}
+	if !database.IsPostgres(r.db) {
+		return r.batchUpdateEach(ctx, tracks)
+	}
+
+	versions := make([]int, len(tracks))
+	updatedAts := make([]time.Time, len(tracks))
+	for i, track := range tracks {
+		versions[i] = track.Version
+		updatedAts[i] = track.UpdatedAt
+	}
+
+	err := dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		for start := 0; start < len(tracks); start += batchUpdateChunk {
+			chunk := tracks[start:min(start+batchUpdateChunk, len(tracks))]
+
+			args := make([]interface{}, 0, len(chunk)*(len(trackColumns)+1))
+			for _, track := range chunk {
+				expected := track.Version
+				track.Version = expected + 1
+				track.UpdatedAt = now
+
+				values, err := trackValues(track)
+				if err != nil {
+					return err
+				}
+				args = append(args, values[0], expected)
+				args = append(args, values[1:]...)
+			}
+
+			var updated []string
+			if err := tx.Raw(batchUpdateSQL(len(chunk)), args...).Scan(&updated).Error; err != nil {
+				return fmt.Errorf("failed to update tracks: %w", err)
+			}
+			if len(updated) != len(chunk) {
+				return batchConflict(tx, chunk, updated, versions[start:start+len(chunk)])
+			}
+
+			events := make([]*domain.OutboxEvent, len(chunk))
+			for i, track := range chunk {
+				event, err := domain.NewTrackOutboxEvent(domain.TrackChangeUpdated, track)
+				if err != nil {
+					return err
+				}
+				events[i] = event
+			}
+			if err := tx.CreateInBatches(events, batchUpdateChunk).Error; err != nil {
+				return fmt.Errorf("failed to write outbox events: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		for i, track := range tracks {
+			track.Version = versions[i]
+			track.UpdatedAt = updatedAts[i]
+		}
+	}
+	return err
+}
+
+// batchUpdateEach updates the tracks one statement at a time for databases
+// without multi-row UPDATE ... FROM support
+func (r *PkgTrackRepository) batchUpdateEach(ctx context.Context, tracks []*domain.Track) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, track := range tracks {
+			if err := updateVersioned(tx, track); err != nil {
+				return fmt.Errorf("failed to update track %s: %w", track.ID, err)
+			}
+			if err := writeOutbox(tx, domain.TrackChangeUpdated, track); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// batchConflict reports the first track of a chunk that was not updated,
+// either because it no longer exists or because its version moved on
+func batchConflict(tx *gorm.DB, chunk []*domain.Track, updated []string, versions []int) error {
+	done := make(map[string]bool, len(updated))
+	for _, id := range updated {
+		done[id] = true
+	}
+
+	for i, track := range chunk {
+		if done[track.ID] {
+			continue
+		}
+
+		var current domain.Track
+		if err := tx.First(&current, "id = ?", track.ID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("failed to update track %s: track not found: %s", track.ID, track.ID)
+			}
+			return fmt.Errorf("failed to load current track: %w", err)
+		}
+
+		stale := *track
+		stale.Version = versions[i]
+		return fmt.Errorf("failed to update track %s: %w", track.ID, domain.NewVersionConflictError(&current, &stale))
+	}
+	return fmt.Errorf("failed to update tracks: %d of %d rows matched", len(updated), len(chunk))
+}
+
+// batchUpdateSQL builds a multi-row compare-and-swap update for n tracks.
+// Each VALUES row holds the track ID, the version the caller read and then
+// the remaining columns; only rows whose stored version still matches are
+// updated and their IDs returned.
+func batchUpdateSQL(n int) string {
+	var b strings.Builder
+
+	assignments := make([]string, 0, len(trackColumns))
+	for _, column := range trackColumns[1:] {
+		// created_at never changes after insert
+		if column.name != "created_at" {
+			assignments = append(assignments, fmt.Sprintf("%s = v.%s", column.name, column.name))
+		}
+	}
+	b.WriteString("UPDATE tracks AS t SET ")
+	b.WriteString(strings.Join(assignments, ", "))
+
+	b.WriteString(" FROM (VALUES ")
+	for row := 0; row < n; row++ {
+		if row > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "(?::%s, ?::bigint", trackColumns[0].cast)
+		for _, column := range trackColumns[1:] {
+			fmt.Fprintf(&b, ", ?::%s", column.cast)
+		}
+		b.WriteString(")")
+	}
+
+	b.WriteString(") AS v(id, expected_version")
+	for _, column := range trackColumns[1:] {
+		b.WriteString(", ")
+		b.WriteString(column.name)
+	}
+	b.WriteString(") WHERE t.id = v.id AND t.version = v.expected_version RETURNING t.id")
+
+	return b.String()
+}
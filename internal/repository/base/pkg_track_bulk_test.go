@@ -0,0 +1,41 @@
+package base
+
+import (
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestBatchUpdateSQL_BindsEveryTrack(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=test"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var args []interface{}
+	for i := 0; i < 3; i++ {
+		track := &domain.Track{ID: uuid.New().String(), Version: 2, ArtistIDs: []string{"artist"}}
+		values, err := trackValues(track)
+		require.NoError(t, err)
+		args = append(args, values[0], track.Version-1)
+		args = append(args, values[1:]...)
+	}
+
+	stmt := db.Raw(batchUpdateSQL(3), args...).Statement
+	perRow := len(trackColumns) + 1
+
+	assert.Len(t, stmt.Vars, 3*perRow)
+	assert.NotContains(t, stmt.SQL.String(), "?")
+	assert.Contains(t, stmt.SQL.String(), "$48::text")
+	assert.Contains(t, stmt.SQL.String(), "WHERE t.id = v.id AND t.version = v.expected_version RETURNING t.id")
+	assert.NotContains(t, stmt.SQL.String(), "created_at = v.created_at")
+}
+
+func TestTrackValues_RejectsInvalidID(t *testing.T) {
+	_, err := trackValues(&domain.Track{ID: "not-a-uuid"})
+	assert.Error(t, err)
+}
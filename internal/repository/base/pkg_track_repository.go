@@ -2,22 +2,43 @@ package base
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"metadatatool/internal/pkg/database"
 	"metadatatool/internal/pkg/domain"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// PkgTrackRepository implements pkg/domain.TrackRepository using GORM
+// PkgTrackRepository implements pkg/domain.TrackRepository using GORM.
+// Writes go to the primary and reads are routed through a DBRouter.
 type PkgTrackRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	router *DBRouter
+	events domain.EventPublisher
 }
 
 // NewPkgTrackRepository creates a new pkg/domain track repository
 func NewPkgTrackRepository(db *gorm.DB) domain.TrackRepository {
-	return &PkgTrackRepository{db: db}
+	return NewRoutedPkgTrackRepository(NewDBRouter(db, nil, 0, 0))
+}
+
+// NewRoutedPkgTrackRepository creates a track repository that reads from the
+// router's replicas
+func NewRoutedPkgTrackRepository(router *DBRouter) domain.TrackRepository {
+	return &PkgTrackRepository{db: router.primary, router: router}
+}
+
+// SetEvents publishes a domain.TrackCreated event to events for every track
+// created
+func (r *PkgTrackRepository) SetEvents(events domain.EventPublisher) {
+	r.events = events
 }
 
 // Create creates a new track
@@ -27,19 +48,28 @@ func (r *PkgTrackRepository) Create(ctx context.Context, track *domain.Track) er
 	if track.ID == "" {
 		track.ID = uuid.New().String()
 	}
+	if track.Version == 0 {
+		track.Version = 1
+	}
 
-	result := r.db.WithContext(ctx).Create(track)
-	if result.Error != nil {
-		return fmt.Errorf("failed to create track: %w", result.Error)
+	err := dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(track).Error; err != nil {
+			return fmt.Errorf("failed to create track: %w", err)
+		}
+		return writeOutbox(tx, domain.TrackChangeCreated, track)
+	})
+	if err != nil {
+		return err
 	}
 
+	r.publishCreated(ctx, track)
 	return nil
 }
 
 // GetByID retrieves a track by ID
 func (r *PkgTrackRepository) GetByID(ctx context.Context, id string) (*domain.Track, error) {
 	var track domain.Track
-	result := r.db.WithContext(ctx).First(&track, "id = ?", id)
+	result := r.router.Reader(ctx).First(&track, "id = ?", id)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -50,34 +80,99 @@ func (r *PkgTrackRepository) GetByID(ctx context.Context, id string) (*domain.Tr
 	return &track, nil
 }
 
-// Update updates an existing track
-func (r *PkgTrackRepository) Update(ctx context.Context, track *domain.Track) error {
-	track.UpdatedAt = time.Now()
-	result := r.db.WithContext(ctx).Save(track)
+// GetByIDs retrieves the tracks with the given IDs in a single query
+func (r *PkgTrackRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Track, error) {
+	var tracks []*domain.Track
+	result := r.router.Reader(ctx).Where("id IN ?", ids).Find(&tracks)
 	if result.Error != nil {
-		return fmt.Errorf("failed to update track: %w", result.Error)
+		return nil, fmt.Errorf("failed to get tracks: %w", result.Error)
 	}
 
-	return nil
+	return tracks, nil
 }
 
-// Delete deletes a track
-func (r *PkgTrackRepository) Delete(ctx context.Context, id string) error {
-	result := r.db.WithContext(ctx).Delete(&domain.Track{}, "id = ?", id)
+// ListByReleaseIDs retrieves all tracks belonging to the given releases
+func (r *PkgTrackRepository) ListByReleaseIDs(ctx context.Context, releaseIDs []string) ([]*domain.Track, error) {
+	var tracks []*domain.Track
+	result := r.router.Reader(ctx).Where("release_id IN ?", releaseIDs).Order("created_at ASC").Find(&tracks)
 	if result.Error != nil {
-		return fmt.Errorf("failed to delete track: %w", result.Error)
+		return nil, fmt.Errorf("failed to list tracks by release: %w", result.Error)
 	}
 
-	return nil
+	return tracks, nil
+}
+
+// Update updates an existing track if its stored version matches track.Version
+func (r *PkgTrackRepository) Update(ctx context.Context, track *domain.Track) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := updateVersioned(tx, track); err != nil {
+			return err
+		}
+		return writeOutbox(tx, domain.TrackChangeUpdated, track)
+	})
+}
+
+// Patch changes part of a track. On PostgreSQL the row is locked while apply
+// runs so that concurrent patches are applied one after the other. SQLite
+// has no row locks and runs one writer at a time; a patch whose read went
+// stale meanwhile fails the version check.
+func (r *PkgTrackRepository) Patch(ctx context.Context, id string, apply func(*domain.Track) error) (*domain.Track, error) {
+	var patched *domain.Track
+	err := dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var track domain.Track
+		query := tx
+		if database.IsPostgres(tx) {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		if err := query.First(&track, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return fmt.Errorf("failed to get track: %w", err)
+		}
+		if err := apply(&track); err != nil {
+			return err
+		}
+		if err := updateVersioned(tx, &track); err != nil {
+			return err
+		}
+		patched = &track
+		return writeOutbox(tx, domain.TrackChangeUpdated, &track)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+// Delete deletes a track
+func (r *PkgTrackRepository) Delete(ctx context.Context, id string) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&domain.Track{}, "id = ?", id)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete track: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return writeOutbox(tx, domain.TrackChangeDeleted, &domain.Track{ID: id})
+	})
 }
 
 // List retrieves tracks with pagination and filtering
 func (r *PkgTrackRepository) List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*domain.Track, error) {
 	var tracks []*domain.Track
-	db := r.db.WithContext(ctx)
+	db, err := sortTracks(ctx, selectTrackFields(ctx, r.router.Reader(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tracks: %w", err)
+	}
 
-	// Apply filters if any
+	// Apply filters if any; lists match any of their values
 	for field, value := range filter {
+		if reflect.ValueOf(value).Kind() == reflect.Slice {
+			db = db.Where(fmt.Sprintf("%s IN ?", field), value)
+			continue
+		}
 		db = db.Where(fmt.Sprintf("%s = ?", field), value)
 	}
 
@@ -92,11 +187,42 @@ func (r *PkgTrackRepository) List(ctx context.Context, filter map[string]interfa
 // SearchByMetadata searches tracks by metadata fields
 func (r *PkgTrackRepository) SearchByMetadata(ctx context.Context, query map[string]interface{}) ([]*domain.Track, error) {
 	var tracks []*domain.Track
-	db := r.db.WithContext(ctx)
+	db := selectTrackFields(ctx, r.router.Reader(ctx))
 
 	// Build query dynamically based on metadata fields
 	for field, value := range query {
-		db = db.Where(fmt.Sprintf("metadata->>'%s' = ?", field), value)
+		switch {
+		case field == "label_id":
+			db = db.Where("label_id = ?", value)
+		case field == "tag":
+			// Tracks carrying a tag, in canonical form
+			if !database.IsPostgres(db) {
+				db = db.Where("EXISTS (SELECT 1 FROM json_each(metadata, '$.additional.tags') WHERE value = ?)", value)
+				continue
+			}
+			tag, err := json.Marshal([]interface{}{value})
+			if err != nil {
+				return nil, fmt.Errorf("failed to search tracks: %w", err)
+			}
+			db = db.Where("metadata->'additional'->'tags' @> ?::jsonb", string(tag))
+		case field == domain.SearchFilterKey:
+			filter, ok := value.(*domain.SearchFilter)
+			if !ok {
+				return nil, fmt.Errorf("failed to search tracks: %s is a %T, not a search filter", field, value)
+			}
+			sql, args, err := searchFilterSQL(filter)
+			if err != nil {
+				return nil, fmt.Errorf("failed to search tracks: %w", err)
+			}
+			db = db.Where(sql, args...)
+		case strings.HasPrefix(field, domain.CustomFieldPrefix):
+			// Custom field names come from clients, so they are bound
+			// rather than written into the path
+			name := strings.TrimPrefix(field, domain.CustomFieldPrefix)
+			db = db.Where("metadata->'additional'->'customFields'->>? = ?", name, value)
+		default:
+			db = db.Where(fmt.Sprintf("metadata->>'%s' = ?", field), value)
+		}
 	}
 
 	result := db.Find(&tracks)
@@ -110,7 +236,7 @@ func (r *PkgTrackRepository) SearchByMetadata(ctx context.Context, query map[str
 // GetByISRC retrieves a track by ISRC
 func (r *PkgTrackRepository) GetByISRC(ctx context.Context, isrc string) (*domain.Track, error) {
 	var track domain.Track
-	result := r.db.WithContext(ctx).First(&track, "metadata->>'isrc' = ?", isrc)
+	result := r.router.Reader(ctx).First(&track, "metadata->>'isrc' = ?", isrc)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -121,15 +247,97 @@ func (r *PkgTrackRepository) GetByISRC(ctx context.Context, isrc string) (*domai
 	return &track, nil
 }
 
-// BatchUpdate updates multiple tracks in a single transaction
-func (r *PkgTrackRepository) BatchUpdate(ctx context.Context, tracks []*domain.Track) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		for _, track := range tracks {
-			track.UpdatedAt = time.Now()
-			if err := tx.Save(track).Error; err != nil {
-				return fmt.Errorf("failed to update track %s: %w", track.ID, err)
-			}
+// selectTrackFields limits db to the columns holding the track fields
+// selected for ctx
+func selectTrackFields(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if fields := domain.TrackFieldsFromContext(ctx); len(fields) > 0 {
+		return db.Select(domain.TrackFieldColumns(fields))
+	}
+	return db
+}
+
+// sortTracks orders db by the track sort of ctx. Ties are ordered by ID, so
+// that pages of a sorted listing neither repeat nor skip tracks. Tracks
+// without a value come last; outside PostgreSQL that is spelled as a
+// leading IS NULL key.
+func sortTracks(ctx context.Context, db *gorm.DB) (*gorm.DB, error) {
+	keys := domain.TrackSortFromContext(ctx)
+	if len(keys) == 0 {
+		return db, nil
+	}
+	for _, key := range keys {
+		expr, err := trackSortExpr(key.Field)
+		if err != nil {
+			return nil, err
+		}
+		direction := "ASC"
+		if key.Desc {
+			direction = "DESC"
 		}
+		if database.IsPostgres(db) {
+			db = db.Order(fmt.Sprintf("%s %s NULLS LAST", expr, direction))
+			continue
+		}
+		db = db.Order(fmt.Sprintf("%s IS NULL, %s %s", expr, expr, direction))
+	}
+	return db.Order("id ASC"), nil
+}
+
+// updateVersioned performs a compare-and-swap update on the track version.
+// When no row matches, the stored track is loaded to distinguish a missing
+// track from a stale one and to report the conflicting fields.
+func updateVersioned(db *gorm.DB, track *domain.Track) error {
+	expected := track.Version
+	updatedAt := track.UpdatedAt
+
+	track.Version = expected + 1
+	track.UpdatedAt = time.Now()
+
+	result := db.Model(&domain.Track{}).
+		Where("id = ? AND version = ?", track.ID, expected).
+		Select("*").
+		Updates(track)
+	if result.Error == nil && result.RowsAffected == 1 {
 		return nil
-	})
+	}
+
+	track.Version = expected
+	track.UpdatedAt = updatedAt
+	if result.Error != nil {
+		return fmt.Errorf("failed to update track: %w", result.Error)
+	}
+
+	var current domain.Track
+	if err := db.First(&current, "id = ?", track.ID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("track not found: %s", track.ID)
+		}
+		return fmt.Errorf("failed to load current track: %w", err)
+	}
+
+	return domain.NewVersionConflictError(&current, track)
+}
+
+// publishCreated publishes the creation of tracks by the actor of ctx
+func (r *PkgTrackRepository) publishCreated(ctx context.Context, tracks ...*domain.Track) {
+	if r.events == nil {
+		return
+	}
+	actor := domain.ActorFromContext(ctx)
+	for _, track := range tracks {
+		r.events.Publish(ctx, domain.TrackCreated{Track: track.Clone(), Actor: actor, At: track.CreatedAt})
+	}
+}
+
+// writeOutbox records a change feed event inside the caller's transaction so
+// the event is stored if and only if the track change commits
+func writeOutbox(tx *gorm.DB, changeType domain.TrackChangeType, track *domain.Track) error {
+	event, err := domain.NewTrackOutboxEvent(changeType, track)
+	if err != nil {
+		return err
+	}
+	if err := tx.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to write outbox event: %w", err)
+	}
+	return nil
 }
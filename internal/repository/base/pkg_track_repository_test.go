@@ -0,0 +1,83 @@
+package base
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateVersioned(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	newTrack := func() *domain.Track {
+		track := &domain.Track{ID: "t1", Version: 3, UpdatedAt: updatedAt}
+		track.SetTitle("Mine")
+		return track
+	}
+
+	t.Run("swaps a matching version", func(t *testing.T) {
+		db, d := openRecordingDB(t)
+		track := newTrack()
+
+		require.NoError(t, updateVersioned(db, track))
+		assert.Equal(t, 4, track.Version)
+		assert.True(t, track.UpdatedAt.After(updatedAt))
+
+		require.Equal(t, []string{"BEGIN", "UPDATE", "COMMIT"}, d.verbs())
+		assert.Contains(t, d.statements[1], "WHERE id = $16 AND version = $17")
+		args := d.args[1]
+		assert.Equal(t, int64(4), args[11], "the new version is written")
+		assert.Equal(t, []driver.Value{"t1", int64(3)}, args[len(args)-2:], "the old version is matched")
+	})
+
+	t.Run("reports the fields of a newer version", func(t *testing.T) {
+		db, d := openRecordingDB(t)
+		d.unmatched = "UPDATE"
+		d.row = map[string]driver.Value{
+			"id":       "t1",
+			"version":  int64(5),
+			"metadata": []byte(`{"basic":{"title":"Theirs"}}`),
+		}
+		track := newTrack()
+
+		err := updateVersioned(db, track)
+		require.ErrorIs(t, err, domain.ErrVersionConflict)
+		var conflict *domain.VersionConflictError
+		require.True(t, errors.As(err, &conflict))
+		assert.Equal(t, 3, conflict.ExpectedVersion)
+		assert.Equal(t, 5, conflict.CurrentVersion)
+		assert.Equal(t, []domain.FieldChange{{Field: "title", OldValue: "Theirs", NewValue: "Mine"}}, conflict.Conflicts)
+
+		assert.Equal(t, 3, track.Version, "a failed swap leaves the track as it was")
+		assert.Equal(t, updatedAt, track.UpdatedAt)
+	})
+
+	t.Run("missing track", func(t *testing.T) {
+		db, d := openRecordingDB(t)
+		d.unmatched = "UPDATE"
+		track := newTrack()
+
+		err := updateVersioned(db, track)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrVersionConflict)
+		assert.Contains(t, err.Error(), "track not found")
+		assert.Equal(t, 3, track.Version)
+	})
+
+	t.Run("failed update", func(t *testing.T) {
+		db, d := openRecordingDB(t)
+		d.failOn = "UPDATE"
+		track := newTrack()
+
+		err := updateVersioned(db, track)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrVersionConflict)
+		assert.Equal(t, 3, track.Version)
+		assert.Equal(t, updatedAt, track.UpdatedAt)
+	})
+}
@@ -0,0 +1,158 @@
+package base
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// searchFieldExprs maps the fields a search filter compares to the SQL
+// reading them from a track row. Blank values read as NULL, so that only ne
+// and not exists match them.
+var searchFieldExprs = map[string]string{
+	"title":      "NULLIF(metadata->'basic'->>'title', '')",
+	"artist":     "NULLIF(metadata->'basic'->>'artist', '')",
+	"album":      "NULLIF(metadata->'basic'->>'album', '')",
+	"isrc":       "NULLIF(metadata->'basic'->>'isrc', '')",
+	"iswc":       "NULLIF(metadata->'additional'->'customFields'->>'iswc', '')",
+	"label":      "NULLIF(metadata->'additional'->'customFields'->>'label', '')",
+	"territory":  "NULLIF(metadata->'additional'->'customFields'->>'territory', '')",
+	"genre":      "NULLIF(metadata->'musical'->>'genre', '')",
+	"key":        "NULLIF(metadata->'musical'->>'key', '')",
+	"camelot":    "NULLIF(metadata->'musical'->>'camelot', '')",
+	"open_key":   "NULLIF(metadata->'musical'->>'openKey', '')",
+	"mood":       "NULLIF(metadata->'musical'->>'mood', '')",
+	"publisher":  "NULLIF(metadata->'additional'->>'publisher', '')",
+	"copyright":  "NULLIF(metadata->'additional'->>'copyright', '')",
+	"label_id":   "NULLIF(label_id, '')",
+	"release_id": "NULLIF(release_id, '')",
+	"status":     "NULLIF(status, '')",
+	"year":       "NULLIF(CAST(metadata->'basic'->>'year' AS NUMERIC), 0)",
+	"duration":   "NULLIF(CAST(metadata->'basic'->>'duration' AS NUMERIC), 0)",
+	"bpm":        "NULLIF(CAST(metadata->'musical'->>'bpm' AS NUMERIC), 0)",
+	"tags":       "metadata->'additional'->'tags'",
+}
+
+// trackSortExpr returns the SQL ordering tracks by a field. Text is ordered
+// ignoring case and blank values come last.
+func trackSortExpr(field string) (string, error) {
+	switch field {
+	case "created_at", "updated_at":
+		return field, nil
+	}
+	expr, ok := searchFieldExprs[field]
+	kind, _ := domain.SearchFieldKindOf(field)
+	if !ok || kind == domain.SearchFieldList {
+		return "", fmt.Errorf("cannot sort by %q", field)
+	}
+	if kind == domain.SearchFieldText {
+		return "LOWER(" + expr + ")", nil
+	}
+	return expr, nil
+}
+
+// searchFilterSQL renders a validated search filter as a condition with
+// its bound values. Every condition yields true or false, never NULL, so
+// that not inverts it.
+func searchFilterSQL(f *domain.SearchFilter) (string, []interface{}, error) {
+	switch {
+	case f.And != nil:
+		return joinSearchFilters(f.And, " AND ")
+	case f.Or != nil:
+		return joinSearchFilters(f.Or, " OR ")
+	case f.Not != nil:
+		sql, args, err := searchFilterSQL(f.Not)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT " + sql, args, nil
+	}
+	return searchConditionSQL(f)
+}
+
+func joinSearchFilters(filters []*domain.SearchFilter, op string) (string, []interface{}, error) {
+	parts := make([]string, len(filters))
+	var args []interface{}
+	for i, f := range filters {
+		sql, fArgs, err := searchFilterSQL(f)
+		if err != nil {
+			return "", nil, err
+		}
+		parts[i] = sql
+		args = append(args, fArgs...)
+	}
+	return "(" + strings.Join(parts, op) + ")", args, nil
+}
+
+func searchConditionSQL(f *domain.SearchFilter) (string, []interface{}, error) {
+	kind, ok := domain.SearchFieldKindOf(f.Field)
+	expr, known := searchFieldExprs[f.Field]
+	var args []interface{}
+	if name := strings.TrimPrefix(f.Field, domain.CustomFieldPrefix); name != f.Field {
+		// Custom field names come from clients, so they are bound rather
+		// than written into the path
+		expr, known = "NULLIF(metadata->'additional'->'customFields'->>?, '')", true
+		args = append(args, name)
+	}
+	if !ok || !known {
+		return "", nil, fmt.Errorf("unknown search field %q", f.Field)
+	}
+
+	if f.Op == domain.SearchExists {
+		if kind == domain.SearchFieldList {
+			return fmt.Sprintf("COALESCE(jsonb_typeof(%[1]s) = 'array' AND %[1]s <> '[]'::jsonb, false)", expr), args, nil
+		}
+		return fmt.Sprintf("(%s IS NOT NULL)", expr), args, nil
+	}
+
+	switch kind {
+	case domain.SearchFieldList:
+		// Tags are stored in canonical form
+		tag, err := json.Marshal([]string{domain.NormalizeTag(f.Text())})
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("COALESCE(%s @> ?::jsonb, false)", expr), append(args, string(tag)), nil
+
+	case domain.SearchFieldNumber:
+		switch f.Op {
+		case domain.SearchNe:
+			n, _ := f.Number()
+			return fmt.Sprintf("(%s IS DISTINCT FROM ?)", expr), append(args, n), nil
+		case domain.SearchBetween:
+			min, max, _ := f.Range()
+			return fmt.Sprintf("COALESCE(%s BETWEEN ? AND ?, false)", expr), append(args, min, max), nil
+		case domain.SearchIn:
+			return fmt.Sprintf("COALESCE(%s IN ?, false)", expr), append(args, f.Numbers()), nil
+		}
+		ops := map[domain.SearchOp]string{
+			domain.SearchEq: "=", domain.SearchGt: ">", domain.SearchGte: ">=",
+			domain.SearchLt: "<", domain.SearchLte: "<=",
+		}
+		n, _ := f.Number()
+		return fmt.Sprintf("COALESCE(%s %s ?, false)", expr, ops[f.Op]), append(args, n), nil
+	}
+
+	// Text compares ignoring case
+	switch f.Op {
+	case domain.SearchNe:
+		return fmt.Sprintf("(LOWER(%s) IS DISTINCT FROM LOWER(?))", expr), append(args, f.Text()), nil
+	case domain.SearchContains:
+		pattern := "%" + escapeLike(f.Text()) + "%"
+		return fmt.Sprintf("COALESCE(%s ILIKE ?, false)", expr), append(args, pattern), nil
+	case domain.SearchIn:
+		values := f.Texts()
+		for i := range values {
+			values[i] = strings.ToLower(values[i])
+		}
+		return fmt.Sprintf("COALESCE(LOWER(%s) IN ?, false)", expr), append(args, values), nil
+	}
+	return fmt.Sprintf("COALESCE(LOWER(%s) = LOWER(?), false)", expr), append(args, f.Text()), nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,85 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func parseSearchFilter(t *testing.T, body string) *domain.SearchFilter {
+	var filter domain.SearchFilter
+	require.NoError(t, json.Unmarshal([]byte(body), &filter))
+	require.Empty(t, filter.Validate())
+	return &filter
+}
+
+func TestSearchFilterSQL_BindsEveryValue(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=test"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	filter := parseSearchFilter(t, `{"and": [
+		{"field": "genre", "op": "in", "value": ["House", "techno"]},
+		{"field": "bpm", "op": "between", "value": [120, 128]},
+		{"or": [
+			{"field": "year", "op": "gte", "value": 2020},
+			{"field": "custom.catalog_code", "op": "contains", "value": "50%"}
+		]},
+		{"not": {"field": "isrc", "op": "exists"}},
+		{"field": "tags", "op": "contains", "value": "Deep House"}
+	]}`)
+
+	sql, args, err := searchFilterSQL(filter)
+	require.NoError(t, err)
+	assert.Equal(t, "(COALESCE(LOWER(NULLIF(metadata->'musical'->>'genre', '')) IN ?, false) AND "+
+		"COALESCE(NULLIF(CAST(metadata->'musical'->>'bpm' AS NUMERIC), 0) BETWEEN ? AND ?, false) AND "+
+		"(COALESCE(NULLIF(CAST(metadata->'basic'->>'year' AS NUMERIC), 0) >= ?, false) OR "+
+		"COALESCE(NULLIF(metadata->'additional'->'customFields'->>?, '') ILIKE ?, false)) AND "+
+		"NOT (NULLIF(metadata->'basic'->>'isrc', '') IS NOT NULL) AND "+
+		"COALESCE(metadata->'additional'->'tags' @> ?::jsonb, false))", sql)
+	assert.Equal(t, []interface{}{
+		[]string{"house", "techno"}, 120.0, 128.0, 2020.0, "catalog_code", `%50\%%`, `["deep-house"]`,
+	}, args)
+
+	stmt := db.Table("tracks").Where(sql, args...).Find(&[]map[string]interface{}{}).Statement
+	assert.NotContains(t, stmt.SQL.String(), "?")
+	assert.Len(t, stmt.Vars, 8)
+}
+
+func TestSearchFilterSQL_EveryFieldIsReadable(t *testing.T) {
+	for _, field := range domain.SearchFieldNames() {
+		_, _, err := searchFilterSQL(&domain.SearchFilter{Field: field, Op: domain.SearchExists})
+		assert.NoError(t, err, field)
+	}
+}
+
+func TestSortTracks_OrdersTiesByID(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=test"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	keys, err := domain.ParseTrackSort("artist,-bpm,created_at")
+	require.NoError(t, err)
+	sorted, err := sortTracks(domain.WithTrackSort(context.Background(), keys), db.Table("tracks"))
+	require.NoError(t, err)
+
+	stmt := sorted.Find(&[]map[string]interface{}{}).Statement
+	assert.Contains(t, stmt.SQL.String(), "ORDER BY LOWER(NULLIF(metadata->'basic'->>'artist', '')) ASC NULLS LAST,"+
+		"NULLIF(CAST(metadata->'musical'->>'bpm' AS NUMERIC), 0) DESC NULLS LAST,created_at ASC NULLS LAST,id ASC")
+
+	unsorted, err := sortTracks(context.Background(), db.Table("tracks"))
+	require.NoError(t, err)
+	assert.NotContains(t, unsorted.Find(&[]map[string]interface{}{}).Statement.SQL.String(), "ORDER BY")
+}
+
+func TestTrackSortExpr_EverySortableField(t *testing.T) {
+	for _, field := range domain.SortableTrackFieldNames() {
+		_, err := trackSortExpr(field)
+		assert.NoError(t, err, field)
+	}
+}
@@ -0,0 +1,68 @@
+package base
+
+import (
+	"context"
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openSQLiteDB opens an in-memory SQLite database holding the track tables
+func openSQLiteDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// Every connection to :memory: opens a database of its own
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&domain.Track{}, &domain.OutboxEvent{}))
+	return db
+}
+
+func TestPkgTrackRepository_SQLite(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPkgTrackRepository(openSQLiteDB(t))
+
+	tracks := []*domain.Track{
+		{Metadata: domain.CompleteTrackMetadata{Musical: domain.MusicalMetadata{BPM: 128}, Additional: domain.AdditionalMetadata{Tags: []string{"house"}}}},
+		{Metadata: domain.CompleteTrackMetadata{Additional: domain.AdditionalMetadata{Tags: []string{"house", "deep"}}}},
+		{Metadata: domain.CompleteTrackMetadata{Musical: domain.MusicalMetadata{BPM: 90}}},
+	}
+	for _, track := range tracks {
+		require.NoError(t, repo.Create(ctx, track))
+	}
+
+	t.Run("searches by tag", func(t *testing.T) {
+		found, err := repo.SearchByMetadata(ctx, map[string]interface{}{"tag": "deep"})
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, tracks[1].ID, found[0].ID)
+	})
+
+	t.Run("sorts tracks without a value last", func(t *testing.T) {
+		keys, err := domain.ParseTrackSort("bpm")
+		require.NoError(t, err)
+		listed, err := repo.List(domain.WithTrackSort(ctx, keys), nil, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, listed, 3)
+		assert.Equal(t, []string{tracks[2].ID, tracks[0].ID, tracks[1].ID},
+			[]string{listed[0].ID, listed[1].ID, listed[2].ID})
+	})
+
+	t.Run("patches a track", func(t *testing.T) {
+		patched, err := repo.(domain.TrackPatcher).Patch(ctx, tracks[0].ID, func(track *domain.Track) error {
+			track.SetMood("dark")
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "dark", patched.Metadata.Musical.Mood)
+		assert.Equal(t, 2, patched.Version)
+	})
+}
@@ -10,14 +10,23 @@ import (
 	"gorm.io/gorm"
 )
 
-// PkgUserRepository implements pkg/domain.UserRepository using GORM
+// PkgUserRepository implements pkg/domain.UserRepository using GORM.
+// Listing reads are routed through a DBRouter; single-user lookups stay on
+// the primary because they back authentication and read-modify-write updates.
 type PkgUserRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	router *DBRouter
 }
 
 // NewPkgUserRepository creates a new pkg/domain user repository
 func NewPkgUserRepository(db *gorm.DB) domain.UserRepository {
-	return &PkgUserRepository{db: db}
+	return NewRoutedPkgUserRepository(NewDBRouter(db, nil, 0, 0))
+}
+
+// NewRoutedPkgUserRepository creates a user repository that reads from the
+// router's replicas
+func NewRoutedPkgUserRepository(router *DBRouter) domain.UserRepository {
+	return &PkgUserRepository{db: router.primary, router: router}
 }
 
 // Create creates a new user
@@ -28,7 +37,7 @@ func (r *PkgUserRepository) Create(ctx context.Context, user *domain.User) error
 		user.ID = uuid.New().String()
 	}
 
-	result := r.db.WithContext(ctx).Create(user)
+	result := dbFor(ctx, r.db).Create(user)
 	if result.Error != nil {
 		return fmt.Errorf("failed to create user: %w", result.Error)
 	}
@@ -39,7 +48,7 @@ func (r *PkgUserRepository) Create(ctx context.Context, user *domain.User) error
 // GetByID retrieves a user by ID
 func (r *PkgUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
 	var user domain.User
-	result := r.db.WithContext(ctx).First(&user, "id = ?", id)
+	result := dbFor(ctx, r.db).First(&user, "id = ?", id)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -50,10 +59,21 @@ func (r *PkgUserRepository) GetByID(ctx context.Context, id string) (*domain.Use
 	return &user, nil
 }
 
+// GetByIDs retrieves the users with the given IDs in a single query
+func (r *PkgUserRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.User, error) {
+	var users []*domain.User
+	result := r.router.Reader(ctx).Where("id IN ?", ids).Find(&users)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get users: %w", result.Error)
+	}
+
+	return users, nil
+}
+
 // GetByEmail retrieves a user by email
 func (r *PkgUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
-	result := r.db.WithContext(ctx).First(&user, "email = ?", email)
+	result := dbFor(ctx, r.db).First(&user, "email = ?", email)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -67,7 +87,7 @@ func (r *PkgUserRepository) GetByEmail(ctx context.Context, email string) (*doma
 // GetByAPIKey retrieves a user by API key
 func (r *PkgUserRepository) GetByAPIKey(ctx context.Context, apiKey string) (*domain.User, error) {
 	var user domain.User
-	result := r.db.WithContext(ctx).First(&user, "api_key = ?", apiKey)
+	result := dbFor(ctx, r.db).First(&user, "api_key = ?", apiKey)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -81,7 +101,7 @@ func (r *PkgUserRepository) GetByAPIKey(ctx context.Context, apiKey string) (*do
 // Update updates an existing user
 func (r *PkgUserRepository) Update(ctx context.Context, user *domain.User) error {
 	user.UpdatedAt = time.Now()
-	result := r.db.WithContext(ctx).Save(user)
+	result := dbFor(ctx, r.db).Save(user)
 	if result.Error != nil {
 		return fmt.Errorf("failed to update user: %w", result.Error)
 	}
@@ -91,7 +111,7 @@ func (r *PkgUserRepository) Update(ctx context.Context, user *domain.User) error
 
 // Delete deletes a user
 func (r *PkgUserRepository) Delete(ctx context.Context, id string) error {
-	result := r.db.WithContext(ctx).Delete(&domain.User{}, "id = ?", id)
+	result := dbFor(ctx, r.db).Delete(&domain.User{}, "id = ?", id)
 	if result.Error != nil {
 		return fmt.Errorf("failed to delete user: %w", result.Error)
 	}
@@ -102,7 +122,7 @@ func (r *PkgUserRepository) Delete(ctx context.Context, id string) error {
 // List retrieves users with pagination
 func (r *PkgUserRepository) List(ctx context.Context, offset, limit int) ([]*domain.User, error) {
 	var users []*domain.User
-	result := r.db.WithContext(ctx).Offset(offset).Limit(limit).Find(&users)
+	result := r.router.Reader(ctx).Offset(offset).Limit(limit).Find(&users)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to list users: %w", result.Error)
 	}
@@ -112,7 +132,7 @@ func (r *PkgUserRepository) List(ctx context.Context, offset, limit int) ([]*dom
 
 // UpdateAPIKey updates a user's API key
 func (r *PkgUserRepository) UpdateAPIKey(ctx context.Context, userID string, apiKey string) error {
-	result := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", userID).Update("api_key", apiKey)
+	result := dbFor(ctx, r.db).Model(&domain.User{}).Where("id = ?", userID).Update("api_key", apiKey)
 	if result.Error != nil {
 		return fmt.Errorf("failed to update API key: %w", result.Error)
 	}
@@ -123,7 +143,7 @@ func (r *PkgUserRepository) UpdateAPIKey(ctx context.Context, userID string, api
 // Count returns the total number of users
 func (r *PkgUserRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
-	result := r.db.WithContext(ctx).Model(&domain.User{}).Count(&count)
+	result := r.router.Reader(ctx).Model(&domain.User{}).Count(&count)
 	if result.Error != nil {
 		return 0, fmt.Errorf("failed to count users: %w", result.Error)
 	}
@@ -0,0 +1,118 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PlayCountRepository implements domain.PlayCountRepository using GORM
+type PlayCountRepository struct {
+	db *gorm.DB
+}
+
+// NewPlayCountRepository creates a new play count repository
+func NewPlayCountRepository(db *gorm.DB) domain.PlayCountRepository {
+	return &PlayCountRepository{db: db}
+}
+
+var playCountKey = []clause.Column{{Name: "track_id"}, {Name: "dsp"}, {Name: "territory"}, {Name: "month"}}
+
+// Ingest records report and adds counts to the stored play counts
+func (r *PlayCountRepository) Ingest(ctx context.Context, report *domain.SalesReport, counts []*domain.PlayCount) error {
+	return dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var existing domain.SalesReport
+		err := tx.Where("dsp = ? AND message_id = ?", report.DSP, report.MessageID).First(&existing).Error
+		if err == nil {
+			return fmt.Errorf("%w: %s report %s", domain.ErrSalesReportIngested, report.DSP, report.MessageID)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to look up sales report: %w", err)
+		}
+		if err := tx.Create(report).Error; err != nil {
+			return fmt.Errorf("failed to record sales report: %w", err)
+		}
+		if len(counts) == 0 {
+			return nil
+		}
+
+		result := tx.Clauses(clause.OnConflict{
+			Columns: playCountKey,
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"plays":      gorm.Expr("play_counts.plays + excluded.plays"),
+				"updated_at": gorm.Expr("excluded.updated_at"),
+			}),
+		}).CreateInBatches(&counts, 500)
+		if result.Error != nil {
+			return fmt.Errorf("failed to record play counts: %w", result.Error)
+		}
+		return nil
+	})
+}
+
+// List returns the play counts matching filter
+func (r *PlayCountRepository) List(ctx context.Context, filter domain.PlayCountFilter) ([]*domain.PlayCount, error) {
+	db := dbFor(ctx, r.db)
+	if filter.TrackID != "" {
+		db = db.Where("track_id = ?", filter.TrackID)
+	}
+	if filter.DSP != "" {
+		db = db.Where("dsp = ?", filter.DSP)
+	}
+	if filter.Territory != "" {
+		db = db.Where("territory = ?", filter.Territory)
+	}
+	if filter.From != "" {
+		db = db.Where("month >= ?", filter.From)
+	}
+	if filter.To != "" {
+		db = db.Where("month <= ?", filter.To)
+	}
+
+	var counts []*domain.PlayCount
+	result := db.Order("month ASC, track_id ASC, territory ASC, dsp ASC").Offset(filter.Offset).Limit(filter.Limit).Find(&counts)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list play counts: %w", result.Error)
+	}
+
+	return counts, nil
+}
+
+// RewriteTrackReferences moves the play counts of fromID to toID, adding
+// them to the counts toID has for the same DSP, territory and month
+func (r *PlayCountRepository) RewriteTrackReferences(ctx context.Context, fromID, toID string) (int64, error) {
+	var moved int64
+	err := dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var counts []*domain.PlayCount
+		if err := tx.Where("track_id = ?", fromID).Find(&counts).Error; err != nil {
+			return fmt.Errorf("failed to list play counts: %w", err)
+		}
+		if len(counts) == 0 {
+			return nil
+		}
+		for _, count := range counts {
+			count.TrackID = toID
+		}
+
+		result := tx.Clauses(clause.OnConflict{
+			Columns: playCountKey,
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"plays":      gorm.Expr("play_counts.plays + excluded.plays"),
+				"updated_at": gorm.Expr("excluded.updated_at"),
+			}),
+		}).CreateInBatches(&counts, 500)
+		if result.Error != nil {
+			return fmt.Errorf("failed to move play counts: %w", result.Error)
+		}
+		if err := tx.Where("track_id = ?", fromID).Delete(&domain.PlayCount{}).Error; err != nil {
+			return fmt.Errorf("failed to move play counts: %w", err)
+		}
+		moved = int64(len(counts))
+		return nil
+	})
+	return moved, err
+}
@@ -0,0 +1,81 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PublicAPIKeyRepository implements domain.PublicAPIKeyRepository using GORM
+type PublicAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewPublicAPIKeyRepository creates a new public API key repository
+func NewPublicAPIKeyRepository(db *gorm.DB) domain.PublicAPIKeyRepository {
+	return &PublicAPIKeyRepository{db: db}
+}
+
+// Create stores a new key
+func (r *PublicAPIKeyRepository) Create(ctx context.Context, key *domain.PublicAPIKey) error {
+	if err := dbFor(ctx, r.db).Create(key).Error; err != nil {
+		return fmt.Errorf("failed to create public API key: %w", err)
+	}
+
+	return nil
+}
+
+// GetByHash returns the key with hash
+func (r *PublicAPIKeyRepository) GetByHash(ctx context.Context, hash string) (*domain.PublicAPIKey, error) {
+	var key domain.PublicAPIKey
+	result := dbFor(ctx, r.db).Where("key_hash = ?", hash).First(&key)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrPublicAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get public API key: %w", result.Error)
+	}
+
+	return &key, nil
+}
+
+// List returns the keys of a label, or of every label, newest first
+func (r *PublicAPIKeyRepository) List(ctx context.Context, labelID string) ([]*domain.PublicAPIKey, error) {
+	db := dbFor(ctx, r.db)
+	if labelID != "" {
+		db = db.Where("label_id = ?", labelID)
+	}
+
+	var keys []*domain.PublicAPIKey
+	if err := db.Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to list public API keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Revoke marks a key revoked. Revoking a revoked key keeps its first
+// revocation time.
+func (r *PublicAPIKeyRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	var key domain.PublicAPIKey
+	err := dbFor(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", id).First(&key).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrPublicAPIKeyNotFound
+			}
+			return fmt.Errorf("failed to get public API key: %w", err)
+		}
+		if key.RevokedAt != nil {
+			return nil
+		}
+		if err := tx.Model(&key).Update("revoked_at", at).Error; err != nil {
+			return fmt.Errorf("failed to revoke public API key: %w", err)
+		}
+		return nil
+	})
+	return err
+}
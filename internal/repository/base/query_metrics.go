@@ -0,0 +1,103 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"metadatatool/internal/pkg/metrics"
+
+	"gorm.io/gorm"
+)
+
+const queryStartKey = "metrics:query_start"
+
+// QueryMetrics is a GORM plugin that records the latency of every statement
+// and counts failed ones, labelled with the pool the connection belongs to
+type QueryMetrics struct {
+	pool string
+}
+
+// NewQueryMetrics creates a query metrics plugin for the named pool
+func NewQueryMetrics(pool string) *QueryMetrics {
+	return &QueryMetrics{pool: pool}
+}
+
+// Name implements gorm.Plugin
+func (p *QueryMetrics) Name() string {
+	return "query_metrics:" + p.pool
+}
+
+// Initialize implements gorm.Plugin
+func (p *QueryMetrics) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	hooks := []struct {
+		operation     string
+		before, after callbackRegisterer
+	}{
+		{"create", callbacks.Create().Before("gorm:create"), callbacks.Create().After("gorm:create")},
+		{"query", callbacks.Query().Before("gorm:query"), callbacks.Query().After("gorm:query")},
+		{"update", callbacks.Update().Before("gorm:update"), callbacks.Update().After("gorm:update")},
+		{"delete", callbacks.Delete().Before("gorm:delete"), callbacks.Delete().After("gorm:delete")},
+		{"row", callbacks.Row().Before("gorm:row"), callbacks.Row().After("gorm:row")},
+		{"raw", callbacks.Raw().Before("gorm:raw"), callbacks.Raw().After("gorm:raw")},
+	}
+
+	for _, hook := range hooks {
+		if err := hook.before.Register(p.Name()+":before_"+hook.operation, p.before); err != nil {
+			return fmt.Errorf("failed to register query metrics: %w", err)
+		}
+		if err := hook.after.Register(p.Name()+":after_"+hook.operation, p.after(hook.operation)); err != nil {
+			return fmt.Errorf("failed to register query metrics: %w", err)
+		}
+	}
+	return nil
+}
+
+func (p *QueryMetrics) before(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func (p *QueryMetrics) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		metrics.DatabaseStatementDuration.WithLabelValues(p.pool, operation, table).Observe(time.Since(start).Seconds())
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			metrics.DatabaseErrors.WithLabelValues(table+"_"+operation, "error").Inc()
+		}
+	}
+}
+
+// callbackRegisterer is the part of GORM's callback API the plugin uses;
+// GORM does not export the concrete type
+type callbackRegisterer interface {
+	Register(name string, fn func(*gorm.DB)) error
+}
+
+// InstrumentDB exports pool statistics and statement latencies for db under
+// the given pool name
+func InstrumentDB(db *gorm.DB, pool string) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
+	if err := metrics.RegisterDBStats(pool, sqlDB); err != nil {
+		return err
+	}
+	if err := db.Use(NewQueryMetrics(pool)); err != nil && !errors.Is(err, gorm.ErrRegistered) {
+		return fmt.Errorf("failed to install query metrics: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package base
+
+import (
+	"testing"
+
+	"metadatatool/internal/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type metricsProbe struct {
+	ID string
+}
+
+func statementCount(t *testing.T, pool, operation, table string) uint64 {
+	var m dto.Metric
+	histogram := metrics.DatabaseStatementDuration.WithLabelValues(pool, operation, table).(prometheus.Histogram)
+	require.NoError(t, histogram.Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestInstrumentDB_RecordsStatementLatency(t *testing.T) {
+	// DryRun builds statements and runs the callbacks without a server
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=test"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	require.NoError(t, InstrumentDB(db, "test"))
+	// Instrumenting twice must neither fail nor double count
+	require.NoError(t, InstrumentDB(db, "test"))
+
+	var probes []metricsProbe
+	db.Find(&probes)
+	db.Where("id = ?", "1").Delete(&metricsProbe{})
+
+	assert.Equal(t, uint64(1), statementCount(t, "test", "query", "metrics_probes"))
+	assert.Equal(t, uint64(1), statementCount(t, "test", "delete", "metrics_probes"))
+	assert.Equal(t, uint64(0), statementCount(t, "test", "create", "metrics_probes"))
+}
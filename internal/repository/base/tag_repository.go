@@ -0,0 +1,122 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+
+	"gorm.io/gorm"
+)
+
+// TagRepository implements domain.TagRepository using GORM
+type TagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *gorm.DB) domain.TagRepository {
+	return &TagRepository{db: db}
+}
+
+// Save creates or replaces a tag
+func (r *TagRepository) Save(ctx context.Context, tag *domain.Tag) error {
+	if err := dbFor(ctx, r.db).Save(tag).Error; err != nil {
+		return fmt.Errorf("failed to save tag: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns a tag
+func (r *TagRepository) Get(ctx context.Context, name string) (*domain.Tag, error) {
+	var tag domain.Tag
+	result := dbFor(ctx, r.db).Where("name = ?", name).First(&tag)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTagNotFound
+		}
+		return nil, fmt.Errorf("failed to get tag: %w", result.Error)
+	}
+
+	return &tag, nil
+}
+
+// List returns the tags ordered by name
+func (r *TagRepository) List(ctx context.Context) ([]*domain.Tag, error) {
+	var tags []*domain.Tag
+	if err := dbFor(ctx, r.db).Order("name ASC").Find(&tags).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// Delete removes a tag
+func (r *TagRepository) Delete(ctx context.Context, name string) error {
+	result := dbFor(ctx, r.db).Where("name = ?", name).Delete(&domain.Tag{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete tag: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTagNotFound
+	}
+
+	return nil
+}
+
+// TagRuleRepository implements domain.TagRuleRepository using GORM
+type TagRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRuleRepository creates a new tagging rule repository
+func NewTagRuleRepository(db *gorm.DB) domain.TagRuleRepository {
+	return &TagRuleRepository{db: db}
+}
+
+// Save creates or replaces a rule
+func (r *TagRuleRepository) Save(ctx context.Context, rule *domain.TagRule) error {
+	if err := dbFor(ctx, r.db).Save(rule).Error; err != nil {
+		return fmt.Errorf("failed to save tag rule: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns a rule
+func (r *TagRuleRepository) Get(ctx context.Context, id string) (*domain.TagRule, error) {
+	var rule domain.TagRule
+	result := dbFor(ctx, r.db).Where("id = ?", id).First(&rule)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTagRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get tag rule: %w", result.Error)
+	}
+
+	return &rule, nil
+}
+
+// List returns the rules ordered by name
+func (r *TagRuleRepository) List(ctx context.Context) ([]*domain.TagRule, error) {
+	var rules []*domain.TagRule
+	if err := dbFor(ctx, r.db).Order("name ASC, id ASC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tag rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// Delete removes a rule
+func (r *TagRuleRepository) Delete(ctx context.Context, id string) error {
+	result := dbFor(ctx, r.db).Where("id = ?", id).Delete(&domain.TagRule{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete tag rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTagRuleNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+
+	"gorm.io/gorm"
+)
+
+// TrackRedirectRepository implements domain.TrackRedirectRepository using
+// GORM
+type TrackRedirectRepository struct {
+	db *gorm.DB
+}
+
+// NewTrackRedirectRepository creates a new track redirect repository
+func NewTrackRedirectRepository(db *gorm.DB) domain.TrackRedirectRepository {
+	return &TrackRedirectRepository{db: db}
+}
+
+// Save stores a redirect
+func (r *TrackRedirectRepository) Save(ctx context.Context, redirect *domain.TrackRedirect) error {
+	if err := dbFor(ctx, r.db).Save(redirect).Error; err != nil {
+		return fmt.Errorf("failed to save track redirect: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the redirect of a merged track, or nil if it has none
+func (r *TrackRedirectRepository) Get(ctx context.Context, sourceID string) (*domain.TrackRedirect, error) {
+	var redirect domain.TrackRedirect
+	result := dbFor(ctx, r.db).First(&redirect, "source_id = ?", sourceID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get track redirect: %w", result.Error)
+	}
+
+	return &redirect, nil
+}
+
+// RewriteTrackReferences points the redirects to fromID at toID
+func (r *TrackRedirectRepository) RewriteTrackReferences(ctx context.Context, fromID, toID string) (int64, error) {
+	result := dbFor(ctx, r.db).Model(&domain.TrackRedirect{}).Where("target_id = ?", fromID).Update("target_id", toID)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to rewrite track redirects: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,60 @@
+package base
+
+import (
+	"context"
+
+	"metadatatool/internal/pkg/domain"
+
+	"gorm.io/gorm"
+)
+
+// txKey is the context key of the transaction of a unit of work
+type txKey struct{}
+
+// UnitOfWork implements domain.UnitOfWork with GORM transactions. The
+// repositories of this package write in the transaction of the context they
+// are given, so they must share the unit of work's database.
+type UnitOfWork struct {
+	db *gorm.DB
+}
+
+// NewUnitOfWork creates a unit of work running transactions on db
+func NewUnitOfWork(db *gorm.DB) domain.UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// Do runs fn in a transaction. Reads made in it go to the transaction, and
+// so bypass the replicas and the caches that only serve replica reads. A
+// nested unit of work runs in a savepoint.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx, ok := transaction(ctx); ok {
+		return tx.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(context.WithValue(ctx, txKey{}, tx))
+		})
+	}
+
+	ctx, runHooks := domain.WithCommitHooks(ctx)
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(domain.WithPrimaryReads(ctx), txKey{}, tx))
+	})
+	if err != nil {
+		return err
+	}
+	runHooks()
+	return nil
+}
+
+// transaction returns the transaction of the unit of work ctx belongs to
+func transaction(ctx context.Context) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return tx, ok
+}
+
+// dbFor returns the transaction of the unit of work ctx belongs to, or db
+// outside a unit of work
+func dbFor(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := transaction(ctx); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,325 @@
+package base
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordingDriver is a database/sql driver that records the statements it is
+// given, so transactions can be checked without a server. Statements
+// containing failOn fail, statements containing unmatched affect no rows,
+// and queries return row when it is set.
+type recordingDriver struct {
+	mu         sync.Mutex
+	statements []string
+	args       [][]driver.Value // arguments of each statement
+	failOn     string
+	unmatched  string
+	row        map[string]driver.Value
+}
+
+var recordingDrivers atomic.Int64
+
+// openRecordingDB opens a Postgres GORM database on a new recording driver
+func openRecordingDB(t *testing.T) (*gorm.DB, *recordingDriver) {
+	d := &recordingDriver{}
+	name := fmt.Sprintf("recording-%d", recordingDrivers.Add(1))
+	sql.Register(name, d)
+	db, err := gorm.Open(postgres.New(postgres.Config{DriverName: name, DSN: name}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db, d
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) {
+	return &recordingConn{d: d}, nil
+}
+
+func (d *recordingDriver) record(statement string, args ...driver.NamedValue) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	d.args = append(d.args, values)
+	// Savepoint names vary from run to run
+	if name, ok := strings.CutPrefix(statement, "SAVEPOINT "); ok && name != "" {
+		statement = "SAVEPOINT"
+	} else if strings.HasPrefix(statement, "ROLLBACK TO SAVEPOINT ") {
+		statement = "ROLLBACK TO SAVEPOINT"
+	}
+	d.statements = append(d.statements, statement)
+	if d.failOn != "" && strings.Contains(statement, d.failOn) {
+		return errors.New("statement failed")
+	}
+	return nil
+}
+
+// verbs returns the first word of each statement, or the whole statement
+// for transaction control
+func (d *recordingDriver) verbs() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	verbs := make([]string, len(d.statements))
+	for i, statement := range d.statements {
+		switch statement {
+		case "BEGIN", "COMMIT", "ROLLBACK", "SAVEPOINT", "ROLLBACK TO SAVEPOINT":
+			verbs[i] = statement
+		default:
+			verbs[i], _, _ = strings.Cut(statement, " ")
+		}
+	}
+	return verbs
+}
+
+type recordingConn struct {
+	d *recordingDriver
+}
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *recordingConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return &recordingTx{d: c.d}, c.d.record("BEGIN")
+}
+
+func (c *recordingConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.d.record(query, args...); err != nil {
+		return nil, err
+	}
+	if c.d.unmatched != "" && strings.Contains(query, c.d.unmatched) {
+		return driver.RowsAffected(0), nil
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *recordingConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.d.record(query, args...); err != nil {
+		return nil, err
+	}
+	if c.d.row != nil {
+		return newRowRows(c.d.row), nil
+	}
+	return emptyRows{}, nil
+}
+
+type recordingTx struct {
+	d *recordingDriver
+}
+
+func (tx *recordingTx) Commit() error   { return tx.d.record("COMMIT") }
+func (tx *recordingTx) Rollback() error { return tx.d.record("ROLLBACK") }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return nil }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+// rowRows returns a single row
+type rowRows struct {
+	columns []string
+	values  []driver.Value
+	done    bool
+}
+
+func newRowRows(row map[string]driver.Value) *rowRows {
+	r := &rowRows{}
+	for column, value := range row {
+		r.columns = append(r.columns, column)
+		r.values = append(r.values, value)
+	}
+	return r
+}
+
+func (r *rowRows) Columns() []string { return r.columns }
+func (r *rowRows) Close() error      { return nil }
+
+func (r *rowRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.values)
+	return nil
+}
+
+func TestUnitOfWork_CommitsRepositoryWrites(t *testing.T) {
+	db, d := openRecordingDB(t)
+	uow := NewUnitOfWork(db)
+	redirects := NewTrackRedirectRepository(db)
+
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		if err := redirects.Save(ctx, &domain.TrackRedirect{SourceID: "a", TargetID: "b", MergedAt: time.Now()}); err != nil {
+			return err
+		}
+		_, err := redirects.RewriteTrackReferences(ctx, "a", "b")
+		return err
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BEGIN", "UPDATE", "UPDATE", "COMMIT"}, d.verbs())
+}
+
+func TestUnitOfWork_RollsBackWhenFnFails(t *testing.T) {
+	db, d := openRecordingDB(t)
+	uow := NewUnitOfWork(db)
+	redirects := NewTrackRedirectRepository(db)
+	errMerge := errors.New("merge failed")
+
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		if err := redirects.Save(ctx, &domain.TrackRedirect{SourceID: "a", TargetID: "b", MergedAt: time.Now()}); err != nil {
+			return err
+		}
+		return errMerge
+	})
+
+	assert.ErrorIs(t, err, errMerge)
+	assert.Equal(t, []string{"BEGIN", "UPDATE", "ROLLBACK"}, d.verbs())
+}
+
+func TestUnitOfWork_RollsBackWhenAWriteFails(t *testing.T) {
+	db, d := openRecordingDB(t)
+	uow := NewUnitOfWork(db)
+	redirects := NewTrackRedirectRepository(db)
+	d.failOn = "WHERE target_id"
+
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		if err := redirects.Save(ctx, &domain.TrackRedirect{SourceID: "a", TargetID: "b", MergedAt: time.Now()}); err != nil {
+			return err
+		}
+		_, err := redirects.RewriteTrackReferences(ctx, "a", "b")
+		return err
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, []string{"BEGIN", "UPDATE", "UPDATE", "ROLLBACK"}, d.verbs())
+}
+
+func TestUnitOfWork_RollsBackOnPanic(t *testing.T) {
+	db, d := openRecordingDB(t)
+	uow := NewUnitOfWork(db)
+	redirects := NewTrackRedirectRepository(db)
+
+	assert.Panics(t, func() {
+		_ = uow.Do(context.Background(), func(ctx context.Context) error {
+			if err := redirects.Save(ctx, &domain.TrackRedirect{SourceID: "a", TargetID: "b", MergedAt: time.Now()}); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	})
+
+	assert.Equal(t, []string{"BEGIN", "UPDATE", "ROLLBACK"}, d.verbs())
+}
+
+func TestUnitOfWork_NestedRollsBackToItsSavepoint(t *testing.T) {
+	db, d := openRecordingDB(t)
+	uow := NewUnitOfWork(db)
+	redirects := NewTrackRedirectRepository(db)
+	save := func(ctx context.Context, source string) error {
+		return redirects.Save(ctx, &domain.TrackRedirect{SourceID: source, TargetID: "b", MergedAt: time.Now()})
+	}
+
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		if err := save(ctx, "a"); err != nil {
+			return err
+		}
+		nested := uow.Do(ctx, func(ctx context.Context) error {
+			if err := save(ctx, "c"); err != nil {
+				return err
+			}
+			return errors.New("skipped")
+		})
+		assert.Error(t, nested)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BEGIN", "UPDATE", "SAVEPOINT", "UPDATE", "ROLLBACK TO SAVEPOINT", "COMMIT"}, d.verbs())
+}
+
+func TestUnitOfWork_RunsCommitHooksOnlyOnCommit(t *testing.T) {
+	db, _ := openRecordingDB(t)
+	uow := NewUnitOfWork(db)
+
+	var ran []string
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		domain.AfterCommit(ctx, func() { ran = append(ran, "outer") })
+		return uow.Do(ctx, func(ctx context.Context) error {
+			domain.AfterCommit(ctx, func() { ran = append(ran, "nested") })
+			assert.Empty(t, ran, "hooks must wait for the outer commit")
+			return nil
+		})
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"outer", "nested"}, ran)
+
+	ran = nil
+	err = uow.Do(context.Background(), func(ctx context.Context) error {
+		domain.AfterCommit(ctx, func() { ran = append(ran, "rolled back") })
+		return errors.New("failed")
+	})
+	require.Error(t, err)
+	assert.Empty(t, ran)
+}
+
+func TestUnitOfWork_ReadsGoToTheTransaction(t *testing.T) {
+	primary, primaryDriver := openRecordingDB(t)
+	replica, replicaDriver := openRecordingDB(t)
+	router := NewDBRouter(primary, []Replica{{Name: "replica", DB: replica}}, time.Second, time.Minute)
+
+	err := NewUnitOfWork(primary).Do(context.Background(), func(ctx context.Context) error {
+		var n int
+		return router.Reader(ctx).Raw("SELECT 1").Scan(&n).Error
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BEGIN", "SELECT", "COMMIT"}, primaryDriver.verbs())
+	assert.Empty(t, replicaDriver.verbs())
+}
+
+func TestUnitOfWork_BatchCreateWritesInTheTransaction(t *testing.T) {
+	db, d := openRecordingDB(t)
+	uow := NewUnitOfWork(db)
+	tracks := NewPkgTrackRepository(db).(*PkgTrackRepository)
+	errImport := errors.New("import failed")
+
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		if err := tracks.BatchCreate(ctx, []*domain.Track{{}, {}}); err != nil {
+			return err
+		}
+		return errImport
+	})
+
+	assert.ErrorIs(t, err, errImport)
+	assert.Equal(t, []string{"BEGIN", "SAVEPOINT", "INSERT", "INSERT", "ROLLBACK"}, d.verbs(),
+		"the tracks and their outbox events are rolled back with the unit of work")
+}
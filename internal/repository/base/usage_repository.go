@@ -0,0 +1,96 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UsageRepository implements domain.UsageRepository using GORM
+type UsageRepository struct {
+	db *gorm.DB
+}
+
+// NewUsageRepository creates a new usage repository
+func NewUsageRepository(db *gorm.DB) domain.UsageRepository {
+	return &UsageRepository{db: db}
+}
+
+var usageKey = []clause.Column{{Name: "label_id"}, {Name: "day"}, {Name: "metric"}}
+
+// Increment adds n to a label's metric for day
+func (r *UsageRepository) Increment(ctx context.Context, labelID, day string, metric domain.UsageMetric, n int64) error {
+	record := &domain.UsageRecord{LabelID: labelID, Day: day, Metric: metric, Value: n, UpdatedAt: time.Now()}
+	result := dbFor(ctx, r.db).Clauses(clause.OnConflict{
+		Columns: usageKey,
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"value":      gorm.Expr("usage_daily.value + excluded.value"),
+			"updated_at": gorm.Expr("excluded.updated_at"),
+		}),
+	}).Create(record)
+	if result.Error != nil {
+		return fmt.Errorf("failed to record usage: %w", result.Error)
+	}
+
+	return nil
+}
+
+// SnapshotStored records every label's stored tracks and bytes for day,
+// replacing an earlier snapshot of the same day
+func (r *UsageRepository) SnapshotStored(ctx context.Context, day string) error {
+	var totals []struct {
+		LabelID string
+		Tracks  int64
+		Bytes   int64
+	}
+	result := dbFor(ctx, r.db).
+		Model(&domain.Track{}).
+		Select("label_id, COUNT(*) AS tracks, COALESCE(SUM(file_size), 0) AS bytes").
+		Where("deleted_at IS NULL").
+		Group("label_id").
+		Scan(&totals)
+	if result.Error != nil {
+		return fmt.Errorf("failed to total stored tracks: %w", result.Error)
+	}
+	if len(totals) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	records := make([]*domain.UsageRecord, 0, 2*len(totals))
+	for _, total := range totals {
+		records = append(records,
+			&domain.UsageRecord{LabelID: total.LabelID, Day: day, Metric: domain.UsageTracksStored, Value: total.Tracks, UpdatedAt: now},
+			&domain.UsageRecord{LabelID: total.LabelID, Day: day, Metric: domain.UsageStorageBytes, Value: total.Bytes, UpdatedAt: now},
+		)
+	}
+	result = dbFor(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   usageKey,
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&records)
+	if result.Error != nil {
+		return fmt.Errorf("failed to record stored usage: %w", result.Error)
+	}
+
+	return nil
+}
+
+// List returns the records matching filter ordered by label and day
+func (r *UsageRepository) List(ctx context.Context, filter domain.UsageFilter) ([]*domain.UsageRecord, error) {
+	db := dbFor(ctx, r.db).Where("day BETWEEN ? AND ?", filter.From, filter.To)
+	if filter.LabelID != "" {
+		db = db.Where("label_id = ?", filter.LabelID)
+	}
+
+	var records []*domain.UsageRecord
+	result := db.Order("label_id ASC, day ASC").Find(&records)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list usage: %w", result.Error)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,30 @@
+package base
+
+import (
+	"context"
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestUsageRepository_IncrementAddsToTheDay(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=test"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	require.NoError(t, err)
+
+	var sql string
+	require.NoError(t, db.Callback().Create().After("gorm:create").Register("test:capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	}))
+
+	repo := NewUsageRepository(db)
+	require.NoError(t, repo.Increment(context.Background(), "label-a", "2024-03-01", domain.UsageExports, 3))
+
+	assert.Contains(t, sql, `INSERT INTO "usage_daily"`)
+	assert.Contains(t, sql, `ON CONFLICT ("label_id","day","metric") DO UPDATE SET`)
+	assert.Contains(t, sql, `"value"=usage_daily.value + excluded.value`)
+}
@@ -1,130 +0,0 @@
-package base
-
-import (
-	"context"
-	"fmt"
-	"metadatatool/internal/domain"
-	"time"
-
-	"github.com/google/uuid"
-	"gorm.io/gorm"
-)
-
-// UserRepository implements domain.UserRepository using GORM
-type UserRepository struct {
-	db *gorm.DB
-}
-
-// NewUserRepository creates a new internal domain user repository
-func NewUserRepository(db *gorm.DB) domain.UserRepository {
-	return &UserRepository{db: db}
-}
-
-// Create creates a new user
-func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
-	user.CreatedAt = time.Now()
-	if user.ID == "" {
-		user.ID = uuid.New().String()
-	}
-
-	result := r.db.WithContext(ctx).Create(user)
-	if result.Error != nil {
-		return fmt.Errorf("failed to create user: %w", result.Error)
-	}
-
-	return nil
-}
-
-// GetByID retrieves a user by ID
-func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
-	var user domain.User
-	result := r.db.WithContext(ctx).First(&user, "id = ?", id)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to get user: %w", result.Error)
-	}
-
-	return &user, nil
-}
-
-// GetByEmail retrieves a user by email
-func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
-	var user domain.User
-	result := r.db.WithContext(ctx).First(&user, "email = ?", email)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to get user by email: %w", result.Error)
-	}
-
-	return &user, nil
-}
-
-// GetByAPIKey retrieves a user by API key
-func (r *UserRepository) GetByAPIKey(ctx context.Context, apiKey string) (*domain.User, error) {
-	var user domain.User
-	result := r.db.WithContext(ctx).First(&user, "api_key = ?", apiKey)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to get user by API key: %w", result.Error)
-	}
-
-	return &user, nil
-}
-
-// Update updates an existing user
-func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
-	result := r.db.WithContext(ctx).Save(user)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update user: %w", result.Error)
-	}
-
-	return nil
-}
-
-// Delete deletes a user
-func (r *UserRepository) Delete(ctx context.Context, id string) error {
-	result := r.db.WithContext(ctx).Delete(&domain.User{}, "id = ?", id)
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete user: %w", result.Error)
-	}
-
-	return nil
-}
-
-// List retrieves users with pagination
-func (r *UserRepository) List(ctx context.Context, offset, limit int) ([]*domain.User, error) {
-	var users []*domain.User
-	result := r.db.WithContext(ctx).Offset(offset).Limit(limit).Find(&users)
-	if result.Error != nil {
-		return nil, fmt.Errorf("failed to list users: %w", result.Error)
-	}
-
-	return users, nil
-}
-
-// Count returns the total number of users
-func (r *UserRepository) Count(ctx context.Context) (int64, error) {
-	var count int64
-	result := r.db.WithContext(ctx).Model(&domain.User{}).Count(&count)
-	if result.Error != nil {
-		return 0, fmt.Errorf("failed to count users: %w", result.Error)
-	}
-
-	return count, nil
-}
-
-// UpdateAPIKey updates a user's API key
-func (r *UserRepository) UpdateAPIKey(ctx context.Context, userID string, apiKey string) error {
-	result := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", userID).Update("api_key", apiKey)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update API key: %w", result.Error)
-	}
-
-	return nil
-}
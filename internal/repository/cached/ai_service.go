@@ -0,0 +1,211 @@
+package cached
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	enrichmentKeyPrefix = "ai_enrichment:"
+	enrichmentTTL       = 30 * 24 * time.Hour
+)
+
+// CachedAIService implements domain.AIService with a Redis cache of
+// enrichment results in front of another AI service.
+//
+// Results are keyed by the audio content hash and the model version, so the
+// same file is only sent to the AI provider once per model. Tracks without a
+// content hash, results flagged for review and requests made with
+// domain.WithForceRefresh always go to the delegate. Cache failures are
+// logged and fall back to the delegate. Delegates that enrich some tracks
+// with another configuration, such as a label's own, keep those results apart
+// by implementing cacheScoper.
+type CachedAIService struct {
+	client       *redis.Client
+	delegate     domain.AIService
+	modelVersion string
+	ttl          time.Duration
+}
+
+// enrichmentEntry is a cached enrichment result: the enriched track and the
+// fields the enrichment changed
+type enrichmentEntry struct {
+	Fields []string      `json:"fields"`
+	Track  *domain.Track `json:"track"`
+}
+
+// cacheScoper is implemented by delegates whose results for a track depend
+// on more than the model version. CacheScope returns "" for the default.
+type cacheScoper interface {
+	CacheScope(ctx context.Context, track *domain.Track) string
+}
+
+// NewAIService creates a new cached AI service. A ttl of zero uses the
+// default of 30 days.
+func NewAIService(client *redis.Client, delegate domain.AIService, modelVersion string, ttl time.Duration) domain.AIService {
+	if ttl <= 0 {
+		ttl = enrichmentTTL
+	}
+	return &CachedAIService{
+		client:       client,
+		delegate:     delegate,
+		modelVersion: modelVersion,
+		ttl:          ttl,
+	}
+}
+
+// EnrichMetadata enriches a track, reusing the cached result for the same
+// audio content when there is one
+func (s *CachedAIService) EnrichMetadata(ctx context.Context, track *domain.Track) error {
+	key := s.key(ctx, track)
+	if key == "" {
+		return s.delegate.EnrichMetadata(ctx, track)
+	}
+
+	if s.applyCached(ctx, key, track) {
+		return nil
+	}
+
+	before := track.Clone()
+	if err := s.delegate.EnrichMetadata(ctx, track); err != nil {
+		return err
+	}
+	s.store(ctx, key, before, track)
+	return nil
+}
+
+// ValidateMetadata delegates validation unchanged
+func (s *CachedAIService) ValidateMetadata(ctx context.Context, track *domain.Track) (float64, error) {
+	return s.delegate.ValidateMetadata(ctx, track)
+}
+
+// BatchProcess answers cached tracks from the cache and sends the rest to
+// the delegate in one batch
+func (s *CachedAIService) BatchProcess(ctx context.Context, tracks []*domain.Track) error {
+	var (
+		misses []*domain.Track
+		keys   []string
+		before []*domain.Track
+	)
+	for _, track := range tracks {
+		key := s.key(ctx, track)
+		if key != "" && s.applyCached(ctx, key, track) {
+			continue
+		}
+		misses = append(misses, track)
+		keys = append(keys, key)
+		before = append(before, track.Clone())
+	}
+	if len(misses) == 0 {
+		return nil
+	}
+
+	if err := s.delegate.BatchProcess(ctx, misses); err != nil {
+		return err
+	}
+	for i, track := range misses {
+		if keys[i] != "" {
+			s.store(ctx, keys[i], before[i], track)
+		}
+	}
+	return nil
+}
+
+// key returns the cache key for a track, or "" when it cannot be cached
+func (s *CachedAIService) key(ctx context.Context, track *domain.Track) string {
+	hash := track.Metadata.Technical.ContentHash
+	if hash == "" && len(track.AudioData) > 0 {
+		sum := sha256.Sum256(track.AudioData)
+		hash = hex.EncodeToString(sum[:])
+	}
+	if hash == "" {
+		return ""
+	}
+	if scoper, ok := s.delegate.(cacheScoper); ok {
+		if scope := scoper.CacheScope(ctx, track); scope != "" {
+			return enrichmentKeyPrefix + s.modelVersion + ":" + scope + ":" + hash
+		}
+	}
+	return enrichmentKeyPrefix + s.modelVersion + ":" + hash
+}
+
+// applyCached copies a cached result into track and reports whether there
+// was one
+func (s *CachedAIService) applyCached(ctx context.Context, key string, track *domain.Track) bool {
+	if domain.ForceRefreshFromContext(ctx) {
+		return false
+	}
+
+	entry, err := s.getFromCache(ctx, key)
+	if err != nil {
+		log.Printf("failed to read enrichment for track %s from cache: %v", track.ID, err)
+	}
+	if entry == nil {
+		metrics.CacheMisses.WithLabelValues("ai_enrichment").Inc()
+		return false
+	}
+
+	metrics.CacheHits.WithLabelValues("ai_enrichment").Inc()
+	domain.CopyTrackFields(track, entry.Track, entry.Fields)
+	track.Metadata.AI = entry.Track.Metadata.AI
+	if entry.Track.Duration() > 0 {
+		track.SetDuration(entry.Track.Duration())
+	}
+	return true
+}
+
+// store caches the result of enriching before into after
+func (s *CachedAIService) store(ctx context.Context, key string, before, after *domain.Track) {
+	// Results flagged for review are not reused so the next run can do better
+	if after.Metadata.AI == nil || after.Metadata.AI.NeedsReview {
+		return
+	}
+
+	changes := domain.DiffTracks(before, after)
+	entry := enrichmentEntry{Fields: make([]string, len(changes)), Track: after}
+	for i, change := range changes {
+		entry.Fields[i] = change.Field
+	}
+	if err := s.setCache(ctx, key, &entry); err != nil {
+		log.Printf("failed to cache enrichment for track %s: %v", after.ID, err)
+	}
+}
+
+func (s *CachedAIService) getFromCache(ctx context.Context, key string) (*enrichmentEntry, error) {
+	data, err := s.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get from cache: %w", err)
+	}
+
+	var entry enrichmentEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal enrichment: %w", err)
+	}
+	if entry.Track == nil {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+func (s *CachedAIService) setCache(ctx context.Context, key string, entry *enrichmentEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal enrichment: %w", err)
+	}
+	if err := s.client.Set(ctx, key, data, s.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package cached
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingAIService is a delegate that sets a genre and counts calls
+type countingAIService struct {
+	domain.AIService
+	calls int
+}
+
+func (s *countingAIService) EnrichMetadata(ctx context.Context, track *domain.Track) error {
+	s.calls++
+	track.SetGenre("House")
+	track.Metadata.AI = &domain.TrackAIMetadata{Model: "qwen2", Version: "v1", Confidence: 0.9}
+	return nil
+}
+
+func (s *countingAIService) BatchProcess(ctx context.Context, tracks []*domain.Track) error {
+	for _, track := range tracks {
+		if err := s.EnrichMetadata(ctx, track); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setupCachedAIService(t *testing.T) (domain.AIService, *countingAIService) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		client.Close()
+		mr.Close()
+	})
+
+	delegate := &countingAIService{}
+	return NewAIService(client, delegate, "qwen2@v1", time.Minute), delegate
+}
+
+func newHashedTrack(id, hash string) *domain.Track {
+	track := &domain.Track{ID: id}
+	track.SetTitle("Title " + id)
+	track.Metadata.Technical.ContentHash = hash
+	return track
+}
+
+func TestCachedAIService_ReusesResultForSameContent(t *testing.T) {
+	service, delegate := setupCachedAIService(t)
+	ctx := context.Background()
+
+	require.NoError(t, service.EnrichMetadata(ctx, newHashedTrack("a", "hash-1")))
+
+	track := newHashedTrack("b", "hash-1")
+	require.NoError(t, service.EnrichMetadata(ctx, track))
+	assert.Equal(t, 1, delegate.calls)
+	assert.Equal(t, "House", track.Genre())
+	assert.Equal(t, "Title b", track.Title())
+	require.NotNil(t, track.Metadata.AI)
+	assert.Equal(t, "qwen2", track.Metadata.AI.Model)
+
+	// Different content and forced refreshes go to the delegate
+	require.NoError(t, service.EnrichMetadata(ctx, newHashedTrack("c", "hash-2")))
+	require.NoError(t, service.EnrichMetadata(domain.WithForceRefresh(ctx), newHashedTrack("d", "hash-1")))
+	assert.Equal(t, 3, delegate.calls)
+}
+
+func TestCachedAIService_BatchProcessOnlySendsMisses(t *testing.T) {
+	service, delegate := setupCachedAIService(t)
+	ctx := context.Background()
+
+	require.NoError(t, service.EnrichMetadata(ctx, newHashedTrack("a", "hash-1")))
+
+	tracks := []*domain.Track{newHashedTrack("b", "hash-1"), newHashedTrack("c", "hash-2"), {ID: "d"}}
+	require.NoError(t, service.BatchProcess(ctx, tracks))
+	assert.Equal(t, 3, delegate.calls)
+	for _, track := range tracks {
+		assert.Equal(t, "House", track.Genre())
+	}
+}
+
+// scopedAIService enriches the tracks of label-1 with another configuration
+type scopedAIService struct {
+	countingAIService
+}
+
+func (s *scopedAIService) CacheScope(ctx context.Context, track *domain.Track) string {
+	if track.LabelID == "label-1" {
+		return "label-1-v1"
+	}
+	return ""
+}
+
+func TestCachedAIService_KeepsScopesApart(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer mr.Close()
+	defer client.Close()
+
+	delegate := &scopedAIService{}
+	service := NewAIService(client, delegate, "qwen2@v1", time.Minute)
+	ctx := context.Background()
+
+	require.NoError(t, service.EnrichMetadata(ctx, newHashedTrack("a", "hash-1")))
+	labelTrack := newHashedTrack("b", "hash-1")
+	labelTrack.LabelID = "label-1"
+	require.NoError(t, service.EnrichMetadata(ctx, labelTrack))
+	assert.Equal(t, 2, delegate.calls, "the label's configuration is not answered from the global cache")
+
+	again := newHashedTrack("c", "hash-1")
+	again.LabelID = "label-1"
+	require.NoError(t, service.EnrichMetadata(ctx, again))
+	assert.Equal(t, 2, delegate.calls)
+}
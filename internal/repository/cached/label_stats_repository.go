@@ -0,0 +1,78 @@
+package cached
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	labelStatsKeyPrefix = "label_stats:"
+	labelStatsTTL       = 5 * time.Minute
+)
+
+// CachedLabelStatsRepository implements domain.LabelStatsRepository as a
+// Redis cache in front of another repository. The aggregates scan all of a
+// label's tracks, so they are computed at most once per TTL and are up to
+// one TTL old. Cache failures are logged and fall back to the delegate.
+type CachedLabelStatsRepository struct {
+	client   *redis.Client
+	delegate domain.LabelStatsRepository
+	ttl      time.Duration
+}
+
+// NewLabelStatsRepository creates a new cached label statistics
+// repository. A ttl of zero uses the default of 5 minutes.
+func NewLabelStatsRepository(client *redis.Client, delegate domain.LabelStatsRepository, ttl time.Duration) domain.LabelStatsRepository {
+	if ttl <= 0 {
+		ttl = labelStatsTTL
+	}
+	return &CachedLabelStatsRepository{
+		client:   client,
+		delegate: delegate,
+		ttl:      ttl,
+	}
+}
+
+// LabelStats returns the cached aggregates of a label, computing them when
+// they are missing or expired
+func (r *CachedLabelStatsRepository) LabelStats(ctx context.Context, labelID string) (*domain.LabelStats, error) {
+	key := labelStatsKeyPrefix + labelID
+	data, err := r.client.Get(ctx, key).Bytes()
+	if err != nil && err != redis.Nil {
+		log.Printf("failed to read stats of label %s from cache: %v", labelID, err)
+	}
+	if len(data) > 0 {
+		var stats domain.LabelStats
+		if err := json.Unmarshal(data, &stats); err == nil {
+			metrics.CacheHits.WithLabelValues("label_stats").Inc()
+			return &stats, nil
+		}
+	}
+
+	metrics.CacheMisses.WithLabelValues("label_stats").Inc()
+	stats, err := r.delegate.LabelStats(ctx, labelID)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(stats); err == nil {
+		if err := r.client.Set(ctx, key, data, r.ttl).Err(); err != nil {
+			log.Printf("failed to cache stats of label %s: %v", labelID, err)
+		}
+	}
+	return stats, nil
+}
+
+// InvalidateLabelStats drops the cached aggregates of a label
+func (r *CachedLabelStatsRepository) InvalidateLabelStats(ctx context.Context, labelID string) error {
+	if err := r.client.Del(ctx, labelStatsKeyPrefix+labelID).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate stats of label %s: %w", labelID, err)
+	}
+	return nil
+}
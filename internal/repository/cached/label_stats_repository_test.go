@@ -0,0 +1,68 @@
+package cached
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingLabelStatsRepository counts how often the aggregates are computed
+type countingLabelStatsRepository struct {
+	computed int
+}
+
+func (r *countingLabelStatsRepository) LabelStats(_ context.Context, labelID string) (*domain.LabelStats, error) {
+	r.computed++
+	return &domain.LabelStats{
+		LabelID:  labelID,
+		Tracks:   int64(r.computed),
+		ByStatus: map[domain.TrackStatus]int64{domain.TrackStatusActive: 1},
+		ByGenre:  map[string]int64{"house": 1},
+	}, nil
+}
+
+func TestCachedLabelStatsRepository(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		client.Close()
+		mr.Close()
+	})
+
+	delegate := &countingLabelStatsRepository{}
+	repo := NewLabelStatsRepository(client, delegate, time.Minute)
+	ctx := context.Background()
+
+	stats, err := repo.LabelStats(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Tracks)
+
+	stats, err = repo.LabelStats(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Tracks)
+	assert.Equal(t, int64(1), stats.ByStatus[domain.TrackStatusActive])
+	assert.Equal(t, 1, delegate.computed)
+
+	// Labels are cached apart and recomputed once expired
+	_, err = repo.LabelStats(ctx, "other")
+	require.NoError(t, err)
+	assert.Equal(t, 2, delegate.computed)
+
+	mr.FastForward(2 * time.Minute)
+	stats, err = repo.LabelStats(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.Tracks)
+	// An invalidated label is recomputed on the next read
+	require.NoError(t, repo.(domain.LabelStatsInvalidator).InvalidateLabelStats(ctx, "acme"))
+	stats, err = repo.LabelStats(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), stats.Tracks)
+}
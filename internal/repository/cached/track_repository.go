@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"metadatatool/internal/pkg/domain"
 	"metadatatool/internal/pkg/metrics"
 	"time"
@@ -14,58 +15,82 @@ import (
 const (
 	trackKeyPrefix = "track:"
 	trackTTL       = 24 * time.Hour
+	// maxReleaseTracks bounds the tracks listed per release when the
+	// delegate cannot list many releases at once
+	maxReleaseTracks = 100
 )
 
-// CachedTrackRepository implements domain.TrackRepository with Redis caching
+// CachedTrackRepository implements domain.TrackRepository as a read-through
+// Redis cache in front of another repository.
+//
+// Tracks are cached by ID. Lookups by ISRC cache only the ISRC to ID mapping
+// and then go through the ID cache, so every write only has to invalidate a
+// single key. Cache failures are logged and fall back to the delegate.
 type CachedTrackRepository struct {
 	client   *redis.Client
 	delegate domain.TrackRepository
+	ttl      time.Duration
 }
 
-// NewTrackRepository creates a new cached track repository
-func NewTrackRepository(client *redis.Client, delegate domain.TrackRepository) domain.TrackRepository {
+// NewTrackRepository creates a new cached track repository. A ttl of zero
+// uses the default of 24 hours.
+func NewTrackRepository(client *redis.Client, delegate domain.TrackRepository, ttl time.Duration) domain.TrackRepository {
+	if ttl <= 0 {
+		ttl = trackTTL
+	}
 	return &CachedTrackRepository{
 		client:   client,
 		delegate: delegate,
+		ttl:      ttl,
 	}
 }
 
-// Create inserts a new track and invalidates cache
+// Create inserts a new track
 func (r *CachedTrackRepository) Create(ctx context.Context, track *domain.Track) error {
-	err := r.delegate.Create(ctx, track)
-	if err != nil {
-		return fmt.Errorf("failed to create track: %w", err)
-	}
+	return r.delegate.Create(ctx, track)
+}
 
-	// Invalidate any existing cache entries
-	r.invalidateCache(ctx, track)
+// BatchCreate inserts many tracks, in bulk when the delegate supports it
+func (r *CachedTrackRepository) BatchCreate(ctx context.Context, tracks []*domain.Track) error {
+	if writer, ok := r.delegate.(domain.TrackBulkWriter); ok {
+		return writer.BatchCreate(ctx, tracks)
+	}
+	for _, track := range tracks {
+		if err := r.delegate.Create(ctx, track); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// GetByID retrieves a track by ID, using cache if available
+// GetByID retrieves a track by ID, using cache if available. Reads that must
+// see the primary bypass the cache.
 func (r *CachedTrackRepository) GetByID(ctx context.Context, id string) (*domain.Track, error) {
-	// Try cache first
-	key := fmt.Sprintf("%s:id:%s", trackKeyPrefix, id)
+	if domain.PrimaryReadsFromContext(ctx) {
+		return r.delegate.GetByID(ctx, id)
+	}
+
+	key := trackIDKey(id)
 	track, err := r.getFromCache(ctx, key)
-	if err == nil && track != nil {
+	if err != nil {
+		log.Printf("failed to read track %s from cache: %v", id, err)
+	}
+	if track != nil {
 		metrics.CacheHits.WithLabelValues("track").Inc()
 		return track, nil
 	}
 
-	// Cache miss, get from delegate
 	metrics.CacheMisses.WithLabelValues("track").Inc()
 	track, err = r.delegate.GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get track: %w", err)
+		return nil, err
 	}
 
 	if track != nil {
 		if err := r.setCache(ctx, key, track); err != nil {
-			// Log error but don't fail the request
-			fmt.Printf("failed to cache track: %v\n", err)
+			log.Printf("failed to cache track %s: %v", id, err)
 		}
 	}
-
 	return track, nil
 }
 
@@ -75,31 +100,34 @@ func (r *CachedTrackRepository) SearchByMetadata(ctx context.Context, query map[
 	return r.delegate.SearchByMetadata(ctx, query)
 }
 
-// Update modifies an existing track and invalidates cache
+// Update modifies an existing track and invalidates its cache entry
 func (r *CachedTrackRepository) Update(ctx context.Context, track *domain.Track) error {
-	err := r.delegate.Update(ctx, track)
-	if err != nil {
-		return fmt.Errorf("failed to update track: %w", err)
+	if err := r.delegate.Update(ctx, track); err != nil {
+		return err
 	}
-
-	// Invalidate cache entries
-	r.invalidateCache(ctx, track)
+	r.invalidate(ctx, track.ID)
 	return nil
 }
 
-// Delete removes a track and invalidates cache
-func (r *CachedTrackRepository) Delete(ctx context.Context, id string) error {
-	// Get track first to invalidate cache
-	track, err := r.delegate.GetByID(ctx, id)
+// Patch changes part of a track and invalidates its cache entry
+func (r *CachedTrackRepository) Patch(ctx context.Context, id string, apply func(*domain.Track) error) (*domain.Track, error) {
+	track, err := domain.PatchTrack(ctx, r.delegate, id, apply)
 	if err != nil {
-		return fmt.Errorf("failed to get track: %w", err)
+		return nil, err
 	}
-
 	if track != nil {
-		r.invalidateCache(ctx, track)
+		r.invalidate(ctx, id)
 	}
+	return track, nil
+}
 
-	return r.delegate.Delete(ctx, id)
+// Delete removes a track and invalidates its cache entry
+func (r *CachedTrackRepository) Delete(ctx context.Context, id string) error {
+	if err := r.delegate.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
 }
 
 // List retrieves tracks based on filters with pagination
@@ -108,50 +136,100 @@ func (r *CachedTrackRepository) List(ctx context.Context, filters map[string]int
 	return r.delegate.List(ctx, filters, offset, limit)
 }
 
-// GetByISRC retrieves a track by ISRC
-func (r *CachedTrackRepository) GetByISRC(ctx context.Context, isrc string) (*domain.Track, error) {
-	key := fmt.Sprintf("%sisrc:%s", trackKeyPrefix, isrc)
+// GetByIDs retrieves many tracks in one query when the delegate supports
+// it. Batches are not cached; they serve the GraphQL loaders, which cache
+// per request.
+func (r *CachedTrackRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Track, error) {
+	if batch, ok := r.delegate.(domain.TrackBatchReader); ok {
+		return batch.GetByIDs(ctx, ids)
+	}
+	tracks := make([]*domain.Track, 0, len(ids))
+	for _, id := range ids {
+		track, err := r.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if track != nil {
+			tracks = append(tracks, track)
+		}
+	}
+	return tracks, nil
+}
 
-	// Try to get from cache first
-	track, err := r.getFromCache(ctx, key)
-	if err == nil {
-		metrics.CacheHits.WithLabelValues("track", "isrc").Inc()
-		return track, nil
+// ListByReleaseIDs retrieves the tracks of many releases
+func (r *CachedTrackRepository) ListByReleaseIDs(ctx context.Context, releaseIDs []string) ([]*domain.Track, error) {
+	if batch, ok := r.delegate.(domain.TrackBatchReader); ok {
+		return batch.ListByReleaseIDs(ctx, releaseIDs)
+	}
+	var tracks []*domain.Track
+	for _, id := range releaseIDs {
+		found, err := r.delegate.List(ctx, map[string]interface{}{"release_id": id}, 0, maxReleaseTracks)
+		if err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, found...)
+	}
+	return tracks, nil
+}
+
+// GetByISRC retrieves a track by ISRC, using cache if available
+func (r *CachedTrackRepository) GetByISRC(ctx context.Context, isrc string) (*domain.Track, error) {
+	key := trackISRCKey(isrc)
+	id, err := r.client.Get(ctx, key).Result()
+	if err != nil && err != redis.Nil {
+		log.Printf("failed to read ISRC %s from cache: %v", isrc, err)
+	}
+	if id != "" {
+		track, err := r.GetByID(ctx, id)
+		// The mapping is only trusted while the track still carries the ISRC
+		if err == nil && track != nil && track.ISRC() == isrc {
+			metrics.CacheHits.WithLabelValues("track_isrc").Inc()
+			return track, nil
+		}
+		r.client.Del(ctx, key)
 	}
 
-	// Get from delegate
-	track, err = r.delegate.GetByISRC(ctx, isrc)
+	metrics.CacheMisses.WithLabelValues("track_isrc").Inc()
+	track, err := r.delegate.GetByISRC(ctx, isrc)
 	if err != nil {
 		return nil, err
 	}
 
-	// Store in cache
-	if err := r.setCache(ctx, key, track); err != nil {
-		// Log error but don't fail the request
-		fmt.Printf("failed to cache track by ISRC: %v\n", err)
+	if track != nil {
+		if err := r.client.Set(ctx, key, track.ID, r.ttl).Err(); err != nil {
+			log.Printf("failed to cache ISRC %s: %v", isrc, err)
+		}
+		if err := r.setCache(ctx, trackIDKey(track.ID), track); err != nil {
+			log.Printf("failed to cache track %s: %v", track.ID, err)
+		}
 	}
-
-	metrics.CacheMisses.WithLabelValues("track", "isrc").Inc()
 	return track, nil
 }
 
 // BatchUpdate updates multiple tracks in a single transaction
 func (r *CachedTrackRepository) BatchUpdate(ctx context.Context, tracks []*domain.Track) error {
-	err := r.delegate.BatchUpdate(ctx, tracks)
-	if err != nil {
+	if err := r.delegate.BatchUpdate(ctx, tracks); err != nil {
 		return err
 	}
 
-	// Invalidate cache for all updated tracks
-	for _, track := range tracks {
-		r.invalidateCache(ctx, track)
+	ids := make([]string, len(tracks))
+	for i, track := range tracks {
+		ids[i] = track.ID
 	}
-
+	r.invalidate(ctx, ids...)
 	return nil
 }
 
 // Helper functions for cache operations
 
+func trackIDKey(id string) string {
+	return trackKeyPrefix + "id:" + id
+}
+
+func trackISRCKey(isrc string) string {
+	return trackKeyPrefix + "isrc:" + isrc
+}
+
 func (r *CachedTrackRepository) getFromCache(ctx context.Context, key string) (*domain.Track, error) {
 	data, err := r.client.Get(ctx, key).Bytes()
 	if err != nil {
@@ -175,20 +253,26 @@ func (r *CachedTrackRepository) setCache(ctx context.Context, key string, track
 		return fmt.Errorf("failed to marshal track: %w", err)
 	}
 
-	if err := r.client.Set(ctx, key, data, trackTTL).Err(); err != nil {
+	if err := r.client.Set(ctx, key, data, r.ttl).Err(); err != nil {
 		return fmt.Errorf("failed to set cache: %w", err)
 	}
 
 	return nil
 }
 
-func (r *CachedTrackRepository) invalidateCache(ctx context.Context, track *domain.Track) {
-	keys := []string{
-		fmt.Sprintf("%s:id:%s", trackKeyPrefix, track.ID),
-		// Add other cache keys that need invalidation
+// invalidate drops the cached tracks, once the unit of work ctx belongs to
+// commits. A failure is logged; the entries then expire with their TTL.
+func (r *CachedTrackRepository) invalidate(ctx context.Context, ids ...string) {
+	if len(ids) == 0 {
+		return
 	}
-
-	if len(keys) > 0 {
-		r.client.Del(ctx, keys...)
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = trackIDKey(id)
 	}
+	domain.AfterCommit(ctx, func() {
+		if err := r.client.Del(ctx, keys...).Err(); err != nil {
+			log.Printf("failed to invalidate cached tracks %v: %v", ids, err)
+		}
+	})
 }
@@ -0,0 +1,118 @@
+package cached
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingTrackRepository is an in-memory delegate that counts reads
+type countingTrackRepository struct {
+	domain.TrackRepository
+	tracks map[string]*domain.Track
+	reads  int
+}
+
+func (r *countingTrackRepository) GetByID(ctx context.Context, id string) (*domain.Track, error) {
+	r.reads++
+	track, ok := r.tracks[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *track
+	return &copied, nil
+}
+
+func (r *countingTrackRepository) GetByISRC(ctx context.Context, isrc string) (*domain.Track, error) {
+	r.reads++
+	for _, track := range r.tracks {
+		if track.ISRC() == isrc {
+			copied := *track
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *countingTrackRepository) Update(ctx context.Context, track *domain.Track) error {
+	copied := *track
+	r.tracks[track.ID] = &copied
+	return nil
+}
+
+func (r *countingTrackRepository) Delete(ctx context.Context, id string) error {
+	delete(r.tracks, id)
+	return nil
+}
+
+func setupCachedTrackRepository(t *testing.T) (domain.TrackRepository, *countingTrackRepository) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		client.Close()
+		mr.Close()
+	})
+
+	track := &domain.Track{ID: "track-1", Version: 1}
+	track.SetTitle("Original")
+	track.SetISRC("USABC2400001")
+	delegate := &countingTrackRepository{tracks: map[string]*domain.Track{track.ID: track}}
+
+	return NewTrackRepository(client, delegate, time.Minute), delegate
+}
+
+func TestCachedTrackRepository_ReadThroughAndInvalidate(t *testing.T) {
+	repo, delegate := setupCachedTrackRepository(t)
+	ctx := context.Background()
+
+	track, err := repo.GetByID(ctx, "track-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Original", track.Title())
+
+	track, err = repo.GetByID(ctx, "track-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Original", track.Title())
+	assert.Equal(t, 1, delegate.reads)
+
+	track.SetTitle("Updated")
+	require.NoError(t, repo.Update(ctx, track))
+
+	track, err = repo.GetByID(ctx, "track-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", track.Title())
+	assert.Equal(t, 2, delegate.reads)
+
+	require.NoError(t, repo.Delete(ctx, "track-1"))
+	track, err = repo.GetByID(ctx, "track-1")
+	require.NoError(t, err)
+	assert.Nil(t, track)
+}
+
+func TestCachedTrackRepository_GetByISRCFollowsChanges(t *testing.T) {
+	repo, delegate := setupCachedTrackRepository(t)
+	ctx := context.Background()
+
+	track, err := repo.GetByISRC(ctx, "USABC2400001")
+	require.NoError(t, err)
+	require.NotNil(t, track)
+
+	_, err = repo.GetByISRC(ctx, "USABC2400001")
+	require.NoError(t, err)
+	assert.Equal(t, 1, delegate.reads)
+
+	// Once the ISRC moves off the track the cached mapping must not be used
+	track.SetISRC("USABC2400002")
+	require.NoError(t, repo.Update(ctx, track))
+
+	track, err = repo.GetByISRC(ctx, "USABC2400001")
+	require.NoError(t, err)
+	assert.Nil(t, track)
+}
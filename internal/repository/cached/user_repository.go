@@ -129,6 +129,11 @@ func (r *CachedUserRepository) List(ctx context.Context, offset, limit int) ([]*
 	return r.delegate.List(ctx, offset, limit)
 }
 
+// Count returns the total number of users (not cached)
+func (r *CachedUserRepository) Count(ctx context.Context) (int64, error) {
+	return r.delegate.Count(ctx)
+}
+
 // UpdateAPIKey updates the API key for a user
 func (r *CachedUserRepository) UpdateAPIKey(ctx context.Context, userID string, apiKey string) error {
 	// Update in database
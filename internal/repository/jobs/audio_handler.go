@@ -4,17 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"time"
 
 	"metadatatool/internal/pkg/domain"
 	"metadatatool/internal/pkg/metrics"
 )
 
-// AudioProcessPayload represents the payload for audio processing jobs
-type AudioProcessPayload struct {
-	TrackID     string `json:"track_id"`
-	StoragePath string `json:"storage_path"`
-	Format      string `json:"format,omitempty"`
+// EmbeddingIndexer stores the embedding of a track's audio analysis
+type EmbeddingIndexer interface {
+	Index(ctx context.Context, trackID string, analysis *domain.AudioAnalysis) error
+}
+
+// AnalysisStore keeps the audio analysis of a track by analyzer version
+type AnalysisStore interface {
+	Save(ctx context.Context, trackID, version string, analysis *domain.AudioAnalysis) error
 }
 
 // AudioProcessHandler handles audio processing jobs
@@ -22,6 +26,8 @@ type AudioProcessHandler struct {
 	audioProcessor domain.AudioProcessor
 	trackRepo      domain.TrackRepository
 	storageClient  domain.StorageClient
+	indexer        EmbeddingIndexer
+	analyses       AnalysisStore
 }
 
 // NewAudioProcessHandler creates a new audio process handler
@@ -37,6 +43,17 @@ func NewAudioProcessHandler(
 	}
 }
 
+// SetIndexer stores the embeddings of the analyses with indexer, so
+// processed tracks can be found by similarity
+func (h *AudioProcessHandler) SetIndexer(indexer EmbeddingIndexer) {
+	h.indexer = indexer
+}
+
+// SetAnalysisStore keeps the analyses of processed tracks in analyses
+func (h *AudioProcessHandler) SetAnalysisStore(analyses AnalysisStore) {
+	h.analyses = analyses
+}
+
 // JobType returns the type of job this handler processes
 func (h *AudioProcessHandler) JobType() domain.JobType {
 	return domain.JobTypeAudioProcess
@@ -50,7 +67,7 @@ func (h *AudioProcessHandler) HandleJob(ctx context.Context, job *domain.Job) er
 	}()
 
 	// Parse payload
-	var payload AudioProcessPayload
+	var payload domain.AudioProcessPayload
 	if err := json.Unmarshal(job.Payload, &payload); err != nil {
 		return fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
@@ -107,6 +124,7 @@ func (h *AudioProcessHandler) HandleJob(ctx context.Context, job *domain.Job) er
 	track.SetDuration(result.Metadata.Duration)
 	track.SetBPM(result.Metadata.Musical.BPM)
 	track.SetKey(result.Metadata.Musical.Key)
+	track.SetMode(result.Metadata.Musical.Mode)
 	track.SetISRC(result.Metadata.ISRC)
 	track.SetAudioFormat(string(result.Metadata.Technical.Format))
 	track.SetSampleRate(result.Metadata.Technical.SampleRate)
@@ -118,5 +136,23 @@ func (h *AudioProcessHandler) HandleJob(ctx context.Context, job *domain.Job) er
 		return fmt.Errorf("failed to update track: %w", err)
 	}
 
+	if h.analyses != nil && result.Analysis != nil {
+		version := result.AnalyzerInfo
+		if version == "" {
+			version = result.Analysis.AnalyzerInfo
+		}
+		if err := h.analyses.Save(ctx, track.ID, version, result.Analysis); err != nil {
+			return fmt.Errorf("failed to save analysis: %w", err)
+		}
+	}
+
+	// A missing embedding only keeps the track out of similarity search,
+	// so it does not fail the job
+	if h.indexer != nil && result.Analysis != nil {
+		if err := h.indexer.Index(ctx, track.ID, result.Analysis); err != nil {
+			log.Printf("failed to index embedding of track %s: %v", track.ID, err)
+		}
+	}
+
 	return nil
 }
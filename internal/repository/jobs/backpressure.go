@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"metadatatool/internal/pkg/domain"
+)
+
+// BackpressureQueue applies the backpressure of the job queue to new jobs:
+// while the queue is throttled jobs are enqueued with low priority, and while
+// it rejects messages Enqueue returns domain.ErrQueueBackpressure.
+type BackpressureQueue struct {
+	domain.JobQueue
+	admitter domain.QueueAdmitter
+}
+
+// NewBackpressureQueue wraps queue with the backpressure decided by admitter
+func NewBackpressureQueue(queue domain.JobQueue, admitter domain.QueueAdmitter) *BackpressureQueue {
+	return &BackpressureQueue{JobQueue: queue, admitter: admitter}
+}
+
+// Enqueue adds a job unless the job queue rejects new messages
+func (q *BackpressureQueue) Enqueue(ctx context.Context, job *domain.Job) error {
+	// Job and queue priorities share their low, normal and high values
+	priority, err := q.admitter.Admit(queueName, domain.QueuePriority(job.Priority))
+	if err != nil {
+		return err
+	}
+	job.Priority = domain.JobPriority(priority)
+
+	if err := q.JobQueue.Enqueue(ctx, job); err != nil {
+		return err
+	}
+	q.admitter.RecordPublish(queueName)
+	return nil
+}
@@ -16,29 +16,85 @@ type Processor struct {
 	config   *domain.JobConfig
 	handlers map[domain.JobType]domain.JobHandler
 	workers  []*worker
+	running  *runningJobs
 	wg       sync.WaitGroup
 	mu       sync.RWMutex
+	// ctx stops workers from taking new jobs; jobCtx is given to handlers
+	// and only cancelled when they outlast the shutdown wait
 	ctx      context.Context
 	cancel   context.CancelFunc
+	jobCtx   context.Context
+	abortJob context.CancelFunc
 }
 
 // worker represents a job processing worker
 type worker struct {
-	id       int
-	queue    domain.JobQueue
-	handlers map[domain.JobType]domain.JobHandler
-	wg       *sync.WaitGroup
+	id        int
+	queue     domain.JobQueue
+	handlers  map[domain.JobType]domain.JobHandler
+	scheduler *scheduler
+	running   *runningJobs
+	wg        *sync.WaitGroup
+}
+
+// depthInterval is how often the pending jobs of each type are counted
+const depthInterval = 15 * time.Second
+
+// pendingCounter counts the pending jobs of each type
+type pendingCounter interface {
+	PendingByType(ctx context.Context) (map[domain.JobType]int64, error)
+}
+
+// runningJobs tracks the jobs being handled so shutdown can requeue the
+// ones that do not finish in time
+type runningJobs struct {
+	mu  sync.Mutex
+	ids map[string]struct{}
+}
+
+func (r *runningJobs) add(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ids[id] = struct{}{}
+}
+
+// done stops tracking a job and reports whether its worker still owns it,
+// which it does not once shutdown has requeued it
+func (r *runningJobs) done(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.ids[id]; !ok {
+		return false
+	}
+	delete(r.ids, id)
+	return true
+}
+
+// takeAll stops tracking every running job and returns their IDs
+func (r *runningJobs) takeAll() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]string, 0, len(r.ids))
+	for id := range r.ids {
+		ids = append(ids, id)
+	}
+	r.ids = make(map[string]struct{})
+	return ids
 }
 
 // NewProcessor creates a new job processor
 func NewProcessor(queue domain.JobQueue, config *domain.JobConfig) *Processor {
 	ctx, cancel := context.WithCancel(context.Background())
+	jobCtx, abortJob := context.WithCancel(context.Background())
 	return &Processor{
 		queue:    queue,
 		config:   config,
 		handlers: make(map[domain.JobType]domain.JobHandler),
+		running:  &runningJobs{ids: make(map[string]struct{})},
 		ctx:      ctx,
 		cancel:   cancel,
+		jobCtx:   jobCtx,
+		abortJob: abortJob,
 	}
 }
 
@@ -65,24 +121,60 @@ func (p *Processor) Start(ctx context.Context) error {
 		return fmt.Errorf("no job handlers registered")
 	}
 
+	types := make([]domain.JobType, 0, len(p.handlers))
+	for jobType := range p.handlers {
+		types = append(types, jobType)
+	}
+	scheduler := newScheduler(types, p.config.TypeLimits, p.config.TypeWeights)
+
 	// Create worker pool
 	p.workers = make([]*worker, p.config.NumWorkers)
 	for i := 0; i < p.config.NumWorkers; i++ {
 		w := &worker{
-			id:       i,
-			queue:    p.queue,
-			handlers: p.handlers,
-			wg:       &p.wg,
+			id:        i,
+			queue:     p.queue,
+			handlers:  p.handlers,
+			scheduler: scheduler,
+			running:   p.running,
+			wg:        &p.wg,
 		}
 		p.workers[i] = w
 		p.wg.Add(1)
-		go w.start(p.ctx)
+		go w.start(p.ctx, p.jobCtx)
+	}
+
+	if counter, ok := p.queue.(pendingCounter); ok {
+		p.wg.Add(1)
+		go p.reportDepth(counter)
 	}
 
 	return nil
 }
 
-// Stop stops the job processor
+// reportDepth records the pending jobs of each type until the processor
+// stops
+func (p *Processor) reportDepth(counter pendingCounter) {
+	defer p.wg.Done()
+	ticker := time.NewTicker(depthInterval)
+	defer ticker.Stop()
+
+	for {
+		if pending, err := counter.PendingByType(p.ctx); err == nil {
+			for jobType, count := range pending {
+				metrics.JobQueueDepth.WithLabelValues(string(jobType)).Set(float64(count))
+			}
+		}
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop stops taking new jobs and waits up to ShutdownWait for the running
+// ones. Jobs still running then are cancelled and returned to the queue
+// without counting a retry.
 func (p *Processor) Stop() error {
 	p.cancel()
 	done := make(chan struct{})
@@ -93,14 +185,26 @@ func (p *Processor) Stop() error {
 
 	select {
 	case <-done:
+		p.abortJob()
 		return nil
 	case <-time.After(p.config.ShutdownWait):
-		return fmt.Errorf("shutdown timed out after %v", p.config.ShutdownWait)
 	}
+
+	// Take the jobs before cancelling them so their workers neither
+	// complete nor fail them
+	ids := p.running.takeAll()
+	p.abortJob()
+	for _, id := range ids {
+		if err := p.queue.Requeue(context.Background(), id); err != nil {
+			metrics.JobErrors.WithLabelValues("worker", "requeue_error").Inc()
+		}
+	}
+	return fmt.Errorf("shutdown timed out after %v, requeued %d running jobs", p.config.ShutdownWait, len(ids))
 }
 
-// start starts the worker's processing loop
-func (w *worker) start(ctx context.Context) {
+// start starts the worker's processing loop. New jobs are only taken while
+// ctx is open; jobCtx is handed to the job handlers.
+func (w *worker) start(ctx, jobCtx context.Context) {
 	defer w.wg.Done()
 
 	for {
@@ -108,7 +212,7 @@ func (w *worker) start(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		default:
-			if err := w.processNextJob(ctx); err != nil {
+			if err := w.processNextJob(ctx, jobCtx); err != nil {
 				// Log error but continue processing
 				metrics.JobErrors.WithLabelValues("worker", "process_error").Inc()
 			}
@@ -117,11 +221,11 @@ func (w *worker) start(ctx context.Context) {
 }
 
 // processNextJob processes the next available job
-func (w *worker) processNextJob(ctx context.Context) error {
+func (w *worker) processNextJob(ctx, jobCtx context.Context) error {
 	// Dequeue job
-	job, err := w.queue.Dequeue(ctx)
+	job, err := w.nextJob(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to dequeue job: %w", err)
+		return err
 	}
 
 	// No job available
@@ -129,29 +233,53 @@ func (w *worker) processNextJob(ctx context.Context) error {
 		time.Sleep(100 * time.Millisecond) // Prevent tight loop
 		return nil
 	}
+	defer w.scheduler.release(job.Type)
 
-	// Get handler for job type
-	handler, ok := w.handlers[job.Type]
-	if !ok {
-		err := fmt.Errorf("no handler registered for job type: %s", job.Type)
-		if err := w.queue.Fail(ctx, job.ID, err); err != nil {
-			return fmt.Errorf("failed to mark job as failed: %w", err)
-		}
-		return err
-	}
+	// The job is settled even when intake stopped while it ran
+	settleCtx := context.WithoutCancel(ctx)
 
 	// Process job
-	if err := handler.HandleJob(ctx, job); err != nil {
-		if err := w.queue.Fail(ctx, job.ID, err); err != nil {
+	handler := w.handlers[job.Type]
+	w.running.add(job.ID)
+	err = handler.HandleJob(jobCtx, job)
+	if !w.running.done(job.ID) {
+		// Requeued by shutdown
+		return nil
+	}
+	if err != nil {
+		if err := w.queue.Fail(settleCtx, job.ID, err); err != nil {
 			return fmt.Errorf("failed to mark job as failed: %w", err)
 		}
 		return err
 	}
 
 	// Mark job as completed
-	if err := w.queue.Complete(ctx, job.ID); err != nil {
+	if err := w.queue.Complete(settleCtx, job.ID); err != nil {
 		return fmt.Errorf("failed to mark job as completed: %w", err)
 	}
 
 	return nil
 }
+
+// nextJob takes a job of the type the scheduler picks, trying the other
+// types in turn while the picked one has no pending jobs. The job holds a
+// slot of its type until released.
+func (w *worker) nextJob(ctx context.Context) (*domain.Job, error) {
+	for _, jobType := range w.scheduler.candidates() {
+		if !w.scheduler.acquire(jobType) {
+			continue
+		}
+		job, err := w.queue.DequeueType(ctx, jobType)
+		if err != nil {
+			w.scheduler.release(jobType)
+			return nil, fmt.Errorf("failed to dequeue job: %w", err)
+		}
+		if job == nil {
+			w.scheduler.release(jobType)
+			continue
+		}
+		w.scheduler.served(jobType)
+		return job, nil
+	}
+	return nil, nil
+}
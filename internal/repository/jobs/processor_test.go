@@ -0,0 +1,169 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingHandler signals when a job starts and finishes it when released
+// or when its context is cancelled
+type blockingHandler struct {
+	started chan string
+	release chan struct{}
+}
+
+func (h *blockingHandler) JobType() domain.JobType { return domain.JobTypeAudioProcess }
+
+func (h *blockingHandler) HandleJob(ctx context.Context, job *domain.Job) error {
+	h.started <- job.ID
+	select {
+	case <-h.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func setupProcessor(t *testing.T, shutdownWait time.Duration) (*RedisQueue, *Processor, *blockingHandler) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		client.Close()
+		mr.Close()
+	})
+
+	config := &domain.JobConfig{
+		NumWorkers:    1,
+		ShutdownWait:  shutdownWait,
+		DefaultTTL:    time.Hour,
+		QueuePrefix:   "jobs:",
+		RetryDelay:    time.Second,
+		MaxRetryDelay: time.Minute,
+	}
+	queue := NewRedisQueue(client, config)
+	handler := &blockingHandler{started: make(chan string, 1), release: make(chan struct{})}
+	processor := NewProcessor(queue, config)
+	require.NoError(t, processor.RegisterHandler(handler))
+	return queue, processor, handler
+}
+
+func enqueueJob(t *testing.T, queue *RedisQueue) string {
+	job := &domain.Job{
+		ID:         "job-1",
+		Type:       domain.JobTypeAudioProcess,
+		Status:     domain.JobStatusPending,
+		MaxRetries: 3,
+		CreatedAt:  time.Now(),
+	}
+	require.NoError(t, queue.Enqueue(context.Background(), job))
+	return job.ID
+}
+
+func TestProcessor_StopWaitsForRunningJob(t *testing.T) {
+	queue, processor, handler := setupProcessor(t, 5*time.Second)
+	id := enqueueJob(t, queue)
+	require.NoError(t, processor.Start(context.Background()))
+	<-handler.started
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- processor.Stop() }()
+	time.Sleep(50 * time.Millisecond)
+	close(handler.release)
+
+	require.NoError(t, <-stopped)
+	job, err := queue.GetStatus(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusCompleted, job.Status)
+}
+
+func TestProcessor_StopRequeuesJobThatOutlastsWait(t *testing.T) {
+	queue, processor, handler := setupProcessor(t, 50*time.Millisecond)
+	id := enqueueJob(t, queue)
+	require.NoError(t, processor.Start(context.Background()))
+	<-handler.started
+
+	assert.Error(t, processor.Stop())
+
+	job, err := queue.GetStatus(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusPending, job.Status)
+	assert.Zero(t, job.RetryCount)
+
+	next, err := queue.Dequeue(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.Equal(t, id, next.ID)
+}
+
+// typedHandler signals when a job of its type starts and finishes it when
+// released
+type typedHandler struct {
+	jobType domain.JobType
+	started chan string
+	release chan struct{}
+}
+
+func (h *typedHandler) JobType() domain.JobType { return h.jobType }
+
+func (h *typedHandler) HandleJob(ctx context.Context, job *domain.Job) error {
+	h.started <- job.ID
+	select {
+	case <-h.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestProcessor_LimitedTypeDoesNotStarveOthers(t *testing.T) {
+	queue, _, _ := setupProcessor(t, time.Second)
+	config := &domain.JobConfig{
+		NumWorkers:   3,
+		ShutdownWait: time.Second,
+		TypeLimits:   map[domain.JobType]int{domain.JobTypeDDEXExport: 1},
+	}
+	exports := &typedHandler{jobType: domain.JobTypeDDEXExport, started: make(chan string, 10), release: make(chan struct{})}
+	enrichments := &typedHandler{jobType: domain.JobTypeAIEnrich, started: make(chan string, 10), release: make(chan struct{})}
+	processor := NewProcessor(queue, config)
+	require.NoError(t, processor.RegisterHandler(exports))
+	require.NoError(t, processor.RegisterHandler(enrichments))
+	t.Cleanup(func() {
+		close(exports.release)
+		close(enrichments.release)
+		processor.Stop()
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, queue.Enqueue(ctx, &domain.Job{
+			ID: fmt.Sprintf("export-%d", i), Type: domain.JobTypeDDEXExport, Status: domain.JobStatusPending, CreatedAt: time.Now(),
+		}))
+	}
+	require.NoError(t, queue.Enqueue(ctx, &domain.Job{
+		ID: "enrich-1", Type: domain.JobTypeAIEnrich, Status: domain.JobStatusPending, CreatedAt: time.Now(),
+	}))
+	require.NoError(t, processor.Start(ctx))
+
+	select {
+	case id := <-enrichments.started:
+		assert.Equal(t, "enrich-1", id)
+	case <-time.After(time.Second):
+		t.Fatal("enrichment waited behind the exports")
+	}
+	time.Sleep(250 * time.Millisecond)
+	assert.Len(t, exports.started, 1, "one export at a time")
+
+	pending, err := queue.PendingByType(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[domain.JobType]int64{domain.JobTypeDDEXExport: 4, domain.JobTypeAIEnrich: 0}, pending)
+}
@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+)
+
+// Reanalyzer queues the tracks analyzed by other analyzer versions for
+// analysis by version
+type Reanalyzer interface {
+	Reanalyze(ctx context.Context, version string) (int, error)
+}
+
+// ReanalyzeHandler handles audio_reanalyze jobs, which are queued when the
+// analyzer version changes
+type ReanalyzeHandler struct {
+	reanalyzer Reanalyzer
+}
+
+// NewReanalyzeHandler creates a new reanalyze handler
+func NewReanalyzeHandler(reanalyzer Reanalyzer) *ReanalyzeHandler {
+	return &ReanalyzeHandler{reanalyzer: reanalyzer}
+}
+
+// JobType returns the type of job this handler processes
+func (h *ReanalyzeHandler) JobType() domain.JobType {
+	return domain.JobTypeReanalyze
+}
+
+// HandleJob queues an audio processing job for every track not yet
+// analyzed by the payload's analyzer version
+func (h *ReanalyzeHandler) HandleJob(ctx context.Context, job *domain.Job) error {
+	start := time.Now()
+	defer func() {
+		metrics.JobProcessingDuration.WithLabelValues(string(job.Type)).Observe(time.Since(start).Seconds())
+	}()
+
+	var payload domain.ReanalyzePayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	if payload.AnalyzerVersion == "" {
+		return fmt.Errorf("invalid payload: missing analyzer version")
+	}
+
+	queued, err := h.reanalyzer.Reanalyze(ctx, payload.AnalyzerVersion)
+	if err != nil {
+		// A retry queues again the tracks queued before the failure
+		// unless they have been analyzed by then
+		return fmt.Errorf("failed to queue reanalysis after %d tracks: %w", queued, err)
+	}
+	log.Printf("queued %d tracks for analysis by %s", queued, payload.AnalyzerVersion)
+	return nil
+}
@@ -6,6 +6,10 @@ import (
 	"fmt"
 	"metadatatool/internal/pkg/domain"
 	"metadatatool/internal/pkg/metrics"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -18,6 +22,11 @@ const (
 	queueKeyProcessing = "processing"
 	queueKeyCompleted  = "completed"
 	queueKeyFailed     = "failed"
+	// queueKeyPendingTypes holds the types that have had pending jobs
+	queueKeyPendingTypes = "pending_types"
+
+	// queueName names the job queue in queue stats
+	queueName = "jobs"
 
 	// Hash fields
 	hashFieldJob        = "job"
@@ -28,10 +37,14 @@ const (
 	hashFieldRetryCount = "retry_count"
 )
 
-// RedisQueue implements domain.JobQueue using Redis
+// RedisQueue implements domain.JobQueue using Redis. Each job type has its
+// own pending queue, so workers can choose which type to take next.
 type RedisQueue struct {
 	client *redis.Client
 	config *domain.JobConfig
+	// legacyMoved is set once the shared pending queue of earlier versions
+	// is found empty
+	legacyMoved atomic.Bool
 }
 
 // NewRedisQueue creates a new Redis-backed job queue
@@ -47,11 +60,28 @@ func (q *RedisQueue) queueKey(queueType string) string {
 	return fmt.Sprintf("%s%s", q.config.QueuePrefix, queueType)
 }
 
+// pendingKey returns the Redis key of the pending queue of a job type
+func (q *RedisQueue) pendingKey(jobType domain.JobType) string {
+	return q.queueKey(queueKeyPending + ":" + string(jobType))
+}
+
 // jobKey returns the Redis key for a specific job
 func (q *RedisQueue) jobKey(jobID string) string {
 	return fmt.Sprintf("%s:job:%s", q.config.QueuePrefix, jobID)
 }
 
+// priorityLabel names a job priority in metrics
+func priorityLabel(priority domain.JobPriority) string {
+	switch priority {
+	case domain.JobPriorityLow:
+		return "low"
+	case domain.JobPriorityHigh:
+		return "high"
+	default:
+		return "normal"
+	}
+}
+
 // Enqueue adds a new job to the queue
 func (q *RedisQueue) Enqueue(ctx context.Context, job *domain.Job) error {
 	// Start pipeline
@@ -73,10 +103,11 @@ func (q *RedisQueue) Enqueue(ctx context.Context, job *domain.Job) error {
 
 	// Add to pending queue with priority score
 	score := float64(time.Now().UnixNano()) - float64(job.Priority)*1e12 // Lower score = higher priority
-	pipe.ZAdd(ctx, q.queueKey(queueKeyPending), redis.Z{
+	pipe.ZAdd(ctx, q.pendingKey(job.Type), redis.Z{
 		Score:  score,
 		Member: job.ID,
 	})
+	pipe.SAdd(ctx, q.queueKey(queueKeyPendingTypes), string(job.Type))
 
 	// Execute pipeline
 	if _, err := pipe.Exec(ctx); err != nil {
@@ -84,34 +115,72 @@ func (q *RedisQueue) Enqueue(ctx context.Context, job *domain.Job) error {
 	}
 
 	// Record metrics
-	metrics.JobsInQueue.WithLabelValues(string(job.Type)).Inc()
+	metrics.JobsInQueue.WithLabelValues(string(job.Type), priorityLabel(job.Priority)).Inc()
 	metrics.JobStatusTransitions.WithLabelValues(string(job.Type), "", string(domain.JobStatusPending)).Inc()
 
 	return nil
 }
 
-// Dequeue gets the next job to process
+// Dequeue gets the next job of any type to process, by priority and then
+// age
 func (q *RedisQueue) Dequeue(ctx context.Context) (*domain.Job, error) {
-	// Start pipeline
-	pipe := q.client.Pipeline()
+	if err := q.moveLegacyPending(ctx); err != nil {
+		return nil, err
+	}
 
-	// Get highest priority job from pending queue
-	pendingKey := q.queueKey(queueKeyPending)
-	processingKey := q.queueKey(queueKeyProcessing)
-	result := pipe.ZPopMin(ctx, pendingKey, 1)
+	types, err := q.pendingTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		// Take the lowest score among the heads of the type queues. Another
+		// worker may take it first, in which case the next one is tried.
+		var next *redis.Z
+		var nextKey string
+		for _, jobType := range types {
+			key := q.pendingKey(jobType)
+			head, err := q.client.ZRangeWithScores(ctx, key, 0, 0).Result()
+			if err != nil {
+				return nil, fmt.Errorf("failed to dequeue job: %w", err)
+			}
+			if len(head) > 0 && (next == nil || head[0].Score < next.Score) {
+				next, nextKey = &head[0], key
+			}
+		}
+		if next == nil {
+			return nil, nil
+		}
 
-	// Execute pipeline to get job ID
-	if _, err := pipe.Exec(ctx); err != nil {
-		return nil, fmt.Errorf("failed to dequeue job: %w", err)
+		jobID := next.Member.(string)
+		removed, err := q.client.ZRem(ctx, nextKey, jobID).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to dequeue job: %w", err)
+		}
+		if removed == 1 {
+			return q.take(ctx, jobID)
+		}
+	}
+}
+
+// DequeueType gets the next job of a type to process
+func (q *RedisQueue) DequeueType(ctx context.Context, jobType domain.JobType) (*domain.Job, error) {
+	if err := q.moveLegacyPending(ctx); err != nil {
+		return nil, err
 	}
 
-	// Check if queue is empty
-	popResult := result.Val()
-	if len(popResult) == 0 {
+	popped, err := q.client.ZPopMin(ctx, q.pendingKey(jobType), 1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to dequeue job: %w", err)
+	}
+	if len(popped) == 0 {
 		return nil, nil
 	}
+	return q.take(ctx, popped[0].Member.(string))
+}
 
-	jobID := popResult[0].Member.(string)
+// take marks a job removed from its pending queue as processing
+func (q *RedisQueue) take(ctx context.Context, jobID string) (*domain.Job, error) {
+	processingKey := q.queueKey(queueKeyProcessing)
 	jobKey := q.jobKey(jobID)
 
 	// Get job data
@@ -132,7 +201,7 @@ func (q *RedisQueue) Dequeue(ctx context.Context) (*domain.Job, error) {
 	job.StartedAt = &now
 
 	// Update job in Redis
-	pipe = q.client.Pipeline()
+	pipe := q.client.Pipeline()
 	updatedJobData, _ := json.Marshal(job)
 	pipe.HSet(ctx, jobKey, map[string]interface{}{
 		hashFieldJob:       string(updatedJobData),
@@ -150,9 +219,9 @@ func (q *RedisQueue) Dequeue(ctx context.Context) (*domain.Job, error) {
 	}
 
 	// Record metrics
-	metrics.JobsInQueue.WithLabelValues(string(job.Type)).Dec()
+	metrics.JobsInQueue.WithLabelValues(string(job.Type), priorityLabel(job.Priority)).Dec()
 	metrics.JobStatusTransitions.WithLabelValues(string(job.Type), string(domain.JobStatusPending), string(domain.JobStatusProcessing)).Inc()
-	metrics.JobQueueLatency.WithLabelValues(string(job.Type)).Observe(time.Since(job.CreatedAt).Seconds())
+	metrics.JobQueueLatency.WithLabelValues(string(job.Type), priorityLabel(job.Priority)).Observe(time.Since(job.CreatedAt).Seconds())
 
 	return &job, nil
 }
@@ -269,7 +338,7 @@ func (q *RedisQueue) Fail(ctx context.Context, jobID string, jobErr error) error
 	// Add to appropriate queue based on retry status
 	if job.Status == domain.JobStatusPending {
 		// Add back to pending queue with retry time as score
-		pipe.ZAdd(ctx, q.queueKey(queueKeyPending), redis.Z{
+		pipe.ZAdd(ctx, q.pendingKey(job.Type), redis.Z{
 			Score:  float64(nextRetryAt.UnixNano()),
 			Member: jobID,
 		})
@@ -295,6 +364,59 @@ func (q *RedisQueue) Fail(ctx context.Context, jobID string, jobErr error) error
 	return nil
 }
 
+// Requeue returns a running job to the pending queue at the position it was
+// first enqueued at, without counting a retry
+func (q *RedisQueue) Requeue(ctx context.Context, jobID string) error {
+	jobKey := q.jobKey(jobID)
+
+	// Get current job data
+	jobData, err := q.client.HGet(ctx, jobKey, hashFieldJob).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get job data: %w", err)
+	}
+
+	// Deserialize job
+	var job domain.Job
+	if err := json.Unmarshal([]byte(jobData), &job); err != nil {
+		return fmt.Errorf("failed to unmarshal job: %w", err)
+	}
+
+	if job.Status != domain.JobStatusProcessing {
+		return fmt.Errorf("cannot requeue job with status %s", job.Status)
+	}
+	job.Status = domain.JobStatusPending
+	job.StartedAt = nil
+
+	// Start pipeline
+	pipe := q.client.Pipeline()
+
+	// Update job data
+	updatedJobData, _ := json.Marshal(job)
+	pipe.HSet(ctx, jobKey, map[string]interface{}{
+		hashFieldJob:    string(updatedJobData),
+		hashFieldStatus: string(job.Status),
+	})
+	pipe.HDel(ctx, jobKey, hashFieldStartedAt)
+
+	// Move from processing back to pending
+	pipe.ZRem(ctx, q.queueKey(queueKeyProcessing), jobID)
+	pipe.ZAdd(ctx, q.pendingKey(job.Type), redis.Z{
+		Score:  float64(job.CreatedAt.UnixNano()) - float64(job.Priority)*1e12,
+		Member: jobID,
+	})
+
+	// Execute pipeline
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+
+	// Record metrics
+	metrics.JobsInQueue.WithLabelValues(string(job.Type), priorityLabel(job.Priority)).Inc()
+	metrics.JobStatusTransitions.WithLabelValues(string(job.Type), string(domain.JobStatusProcessing), string(domain.JobStatusPending)).Inc()
+
+	return nil
+}
+
 // Cancel cancels a pending or running job
 func (q *RedisQueue) Cancel(ctx context.Context, jobID string) error {
 	jobKey := q.jobKey(jobID)
@@ -331,6 +453,7 @@ func (q *RedisQueue) Cancel(ctx context.Context, jobID string) error {
 
 	// Remove from current queue
 	if prevStatus == domain.JobStatusPending {
+		pipe.ZRem(ctx, q.pendingKey(job.Type), jobID)
 		pipe.ZRem(ctx, q.queueKey(queueKeyPending), jobID)
 	} else {
 		pipe.ZRem(ctx, q.queueKey(queueKeyProcessing), jobID)
@@ -344,7 +467,7 @@ func (q *RedisQueue) Cancel(ctx context.Context, jobID string) error {
 	// Record metrics
 	metrics.JobStatusTransitions.WithLabelValues(string(job.Type), string(prevStatus), string(domain.JobStatusCanceled)).Inc()
 	if prevStatus == domain.JobStatusPending {
-		metrics.JobsInQueue.WithLabelValues(string(job.Type)).Dec()
+		metrics.JobsInQueue.WithLabelValues(string(job.Type), priorityLabel(job.Priority)).Dec()
 	}
 
 	return nil
@@ -404,3 +527,225 @@ func (q *RedisQueue) UpdateProgress(ctx context.Context, jobID string, progress
 
 	return nil
 }
+
+// QueueStats reports the pending and running jobs. Jobs that failed after
+// their last retry are counted as dead letters.
+func (q *RedisQueue) QueueStats(ctx context.Context) ([]domain.QueueStats, error) {
+	pending, err := q.PendingByType(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := q.client.Pipeline()
+	legacy := pipe.ZCard(ctx, q.queueKey(queueKeyPending))
+	processing := pipe.ZCard(ctx, q.queueKey(queueKeyProcessing))
+	failed := pipe.ZCard(ctx, q.queueKey(queueKeyFailed))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get job queue stats: %w", err)
+	}
+
+	total := legacy.Val()
+	for _, count := range pending {
+		total += count
+	}
+	return []domain.QueueStats{{
+		Name:        queueName,
+		Pending:     total,
+		Processing:  processing.Val(),
+		DeadLetters: failed.Val(),
+	}}, nil
+}
+
+// JobStats counts the jobs that completed or finally failed within window
+func (q *RedisQueue) JobStats(ctx context.Context, window time.Duration) (*domain.JobStats, error) {
+	since := strconv.FormatInt(time.Now().Add(-window).UnixNano(), 10)
+
+	pipe := q.client.Pipeline()
+	completed := pipe.ZCount(ctx, q.queueKey(queueKeyCompleted), since, "+inf")
+	failed := pipe.ZCount(ctx, q.queueKey(queueKeyFailed), since, "+inf")
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get job stats: %w", err)
+	}
+
+	stats := &domain.JobStats{
+		WindowSeconds: int64(window.Seconds()),
+		Completed:     completed.Val(),
+		Failed:        failed.Val(),
+	}
+	if total := stats.Completed + stats.Failed; total > 0 {
+		stats.FailureRate = float64(stats.Failed) / float64(total)
+	}
+	return stats, nil
+}
+
+// PendingByType counts the pending jobs of each type that has had any
+func (q *RedisQueue) PendingByType(ctx context.Context) (map[domain.JobType]int64, error) {
+	types, err := q.pendingTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := q.client.Pipeline()
+	counts := make(map[domain.JobType]*redis.IntCmd, len(types))
+	for _, jobType := range types {
+		counts[jobType] = pipe.ZCard(ctx, q.pendingKey(jobType))
+	}
+	if len(types) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("failed to count pending jobs: %w", err)
+		}
+	}
+
+	pending := make(map[domain.JobType]int64, len(types))
+	for jobType, count := range counts {
+		pending[jobType] = count.Val()
+	}
+	return pending, nil
+}
+
+// pendingTypes lists the job types that have had pending jobs
+func (q *RedisQueue) pendingTypes(ctx context.Context) ([]domain.JobType, error) {
+	members, err := q.client.SMembers(ctx, q.queueKey(queueKeyPendingTypes)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job types: %w", err)
+	}
+	types := make([]domain.JobType, len(members))
+	for i, member := range members {
+		types[i] = domain.JobType(member)
+	}
+	return types, nil
+}
+
+// moveLegacyPending moves the jobs of the pending queue shared by all types
+// in earlier versions to the queues of their types, keeping their scores
+func (q *RedisQueue) moveLegacyPending(ctx context.Context) error {
+	if q.legacyMoved.Load() {
+		return nil
+	}
+
+	legacyKey := q.queueKey(queueKeyPending)
+	for {
+		entries, err := q.client.ZRangeWithScores(ctx, legacyKey, 0, 99).Result()
+		if err != nil {
+			return fmt.Errorf("failed to read pending jobs: %w", err)
+		}
+		if len(entries) == 0 {
+			q.legacyMoved.Store(true)
+			return nil
+		}
+
+		for _, entry := range entries {
+			jobID := entry.Member.(string)
+			jobData, err := q.client.HGet(ctx, q.jobKey(jobID), hashFieldJob).Result()
+			if err != nil && err != redis.Nil {
+				return fmt.Errorf("failed to get job data: %w", err)
+			}
+			// Jobs whose data expired cannot be run and are dropped
+			var job domain.Job
+			expired := err == redis.Nil || json.Unmarshal([]byte(jobData), &job) != nil
+			_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.ZRem(ctx, legacyKey, jobID)
+				if !expired {
+					pipe.ZAdd(ctx, q.pendingKey(job.Type), entry)
+					pipe.SAdd(ctx, q.queueKey(queueKeyPendingTypes), string(job.Type))
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("failed to move pending job %s: %w", jobID, err)
+			}
+		}
+	}
+}
+
+// FinishedJobs returns up to limit jobs that completed or finally failed
+// before, oldest first. Jobs whose data already expired cannot be archived
+// and are dropped from the queue.
+func (q *RedisQueue) FinishedJobs(ctx context.Context, before time.Time, limit int) ([]*domain.JobHistory, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	rng := &redis.ZRangeBy{Min: "-inf", Max: "(" + strconv.FormatInt(before.UnixNano(), 10), Count: int64(limit)}
+	var entries []redis.Z
+	for _, key := range []string{queueKeyCompleted, queueKeyFailed} {
+		finished, err := q.client.ZRangeByScoreWithScores(ctx, q.queueKey(key), rng).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list finished jobs: %w", err)
+		}
+		entries = append(entries, finished...)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Score < entries[j].Score })
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	pipe := q.client.Pipeline()
+	data := make([]*redis.StringCmd, len(entries))
+	for i, entry := range entries {
+		data[i] = pipe.HGet(ctx, q.jobKey(entry.Member.(string)), hashFieldJob)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get finished jobs: %w", err)
+	}
+
+	queue := strings.TrimSuffix(q.config.QueuePrefix, ":")
+	history := make([]*domain.JobHistory, 0, len(entries))
+	var expired []string
+	for i, entry := range entries {
+		var job domain.Job
+		if data[i].Err() != nil || json.Unmarshal([]byte(data[i].Val()), &job) != nil {
+			expired = append(expired, entry.Member.(string))
+			continue
+		}
+		finishedAt := time.Unix(0, int64(entry.Score))
+		record := &domain.JobHistory{
+			ID:         job.ID,
+			Queue:      queue,
+			Type:       job.Type,
+			Status:     job.Status,
+			Priority:   job.Priority,
+			Payload:    job.Payload,
+			Error:      job.Error,
+			RetryCount: job.RetryCount,
+			CreatedAt:  job.CreatedAt,
+			StartedAt:  job.StartedAt,
+			FinishedAt: finishedAt,
+		}
+		if job.StartedAt != nil {
+			duration := finishedAt.Sub(*job.StartedAt).Milliseconds()
+			record.DurationMs = &duration
+		}
+		history = append(history, record)
+	}
+	if len(expired) > 0 {
+		if err := q.RemoveFinished(ctx, expired); err != nil {
+			return nil, err
+		}
+	}
+	return history, nil
+}
+
+// RemoveFinished removes archived jobs and their data from the queue
+func (q *RedisQueue) RemoveFinished(ctx context.Context, jobIDs []string) error {
+	if len(jobIDs) == 0 {
+		return nil
+	}
+	members := make([]interface{}, len(jobIDs))
+	keys := make([]string, len(jobIDs))
+	for i, jobID := range jobIDs {
+		members[i] = jobID
+		keys[i] = q.jobKey(jobID)
+	}
+
+	pipe := q.client.Pipeline()
+	pipe.ZRem(ctx, q.queueKey(queueKeyCompleted), members...)
+	pipe.ZRem(ctx, q.queueKey(queueKeyFailed), members...)
+	pipe.Del(ctx, keys...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to remove finished jobs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupJobQueue(t *testing.T) (*RedisQueue, *redis.Client) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		client.Close()
+		mr.Close()
+	})
+	return NewRedisQueue(client, &domain.JobConfig{QueuePrefix: "jobs:", DefaultTTL: time.Hour}), client
+}
+
+func TestRedisQueue_DequeueTakesHighestPriorityOfAnyType(t *testing.T) {
+	queue, _ := setupJobQueue(t)
+	ctx := context.Background()
+	require.NoError(t, queue.Enqueue(ctx, &domain.Job{ID: "export", Type: domain.JobTypeDDEXExport, CreatedAt: time.Now()}))
+	require.NoError(t, queue.Enqueue(ctx, &domain.Job{ID: "enrich", Type: domain.JobTypeAIEnrich, Priority: domain.JobPriorityHigh, CreatedAt: time.Now()}))
+
+	job, err := queue.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "enrich", job.ID)
+	job, err = queue.DequeueType(ctx, domain.JobTypeAIEnrich)
+	require.NoError(t, err)
+	assert.Nil(t, job)
+	job, err = queue.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "export", job.ID)
+}
+
+func TestRedisQueue_MovesJobsOfTheSharedPendingQueue(t *testing.T) {
+	queue, client := setupJobQueue(t)
+	ctx := context.Background()
+
+	// Queued by an earlier version, along with a job whose data expired
+	data, err := json.Marshal(&domain.Job{ID: "export", Type: domain.JobTypeDDEXExport})
+	require.NoError(t, err)
+	client.HSet(ctx, queue.jobKey("export"), hashFieldJob, string(data))
+	client.ZAdd(ctx, "jobs:pending", redis.Z{Score: 1, Member: "export"}, redis.Z{Score: 2, Member: "expired"})
+
+	job, err := queue.DequeueType(ctx, domain.JobTypeDDEXExport)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, "export", job.ID)
+	assert.Zero(t, client.Exists(ctx, "jobs:pending").Val())
+}
+
+func TestRedisQueue_HandsOverFinishedJobsForArchiving(t *testing.T) {
+	queue, client := setupJobQueue(t)
+	ctx := context.Background()
+	for _, id := range []string{"done", "broken"} {
+		require.NoError(t, queue.Enqueue(ctx, &domain.Job{ID: id, Type: domain.JobTypeDDEXExport, CreatedAt: time.Now()}))
+		job, err := queue.DequeueType(ctx, domain.JobTypeDDEXExport)
+		require.NoError(t, err)
+		require.Equal(t, id, job.ID)
+	}
+	require.NoError(t, queue.Complete(ctx, "done"))
+	require.NoError(t, queue.Fail(ctx, "broken", errors.New("disk full")))
+	client.ZAdd(ctx, "jobs:completed", redis.Z{Score: 1, Member: "expired"})
+
+	finished, err := queue.FinishedJobs(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, finished, 2)
+	assert.Equal(t, "done", finished[0].ID)
+	assert.Equal(t, domain.JobStatusCompleted, finished[0].Status)
+	assert.Equal(t, "jobs", finished[0].Queue)
+	assert.NotNil(t, finished[0].DurationMs)
+	assert.Equal(t, "broken", finished[1].ID)
+	assert.Equal(t, domain.JobStatusFailed, finished[1].Status)
+	assert.Equal(t, "disk full", finished[1].Error)
+	assert.Zero(t, client.ZScore(ctx, "jobs:completed", "expired").Val(), "expired jobs are dropped")
+
+	finished, err = queue.FinishedJobs(ctx, time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, finished, "jobs finished since are kept")
+
+	require.NoError(t, queue.RemoveFinished(ctx, []string{"done", "broken"}))
+	assert.Zero(t, client.ZCard(ctx, "jobs:completed").Val())
+	assert.Zero(t, client.ZCard(ctx, "jobs:failed").Val())
+	assert.Zero(t, client.Exists(ctx, queue.jobKey("done")).Val())
+}
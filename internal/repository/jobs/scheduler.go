@@ -0,0 +1,112 @@
+package jobs
+
+import (
+	"sort"
+	"sync"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// scheduler decides which job type a worker takes next. Types share the
+// workers in proportion to their weights, using start-time fair queuing:
+// each type has a virtual finish time that grows by 1/weight per job
+// started, and the type furthest behind goes first. A type that was idle
+// rejoins at the current virtual time, so it cannot claim the share it
+// missed. Ties go to the type served least recently. Types at their limit
+// are skipped until one of their jobs ends.
+type scheduler struct {
+	mu      sync.Mutex
+	types   []domain.JobType
+	limits  map[domain.JobType]int
+	weights map[domain.JobType]int
+	running map[domain.JobType]int
+	finish  map[domain.JobType]float64
+	vtime   float64
+	// last is when each type was served, counted in jobs started
+	last    map[domain.JobType]int
+	started int
+}
+
+// newScheduler creates a scheduler for types. Types without a limit may
+// use every worker; types without a weight have weight 1.
+func newScheduler(types []domain.JobType, limits, weights map[domain.JobType]int) *scheduler {
+	sorted := append([]domain.JobType(nil), types...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return &scheduler{
+		types:   sorted,
+		limits:  limits,
+		weights: weights,
+		running: make(map[domain.JobType]int),
+		finish:  make(map[domain.JobType]float64),
+		last:    make(map[domain.JobType]int),
+	}
+}
+
+// candidates returns the types below their limit, the one furthest behind
+// its share first
+func (s *scheduler) candidates() []domain.JobType {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	candidates := make([]domain.JobType, 0, len(s.types))
+	for _, jobType := range s.types {
+		if s.atLimit(jobType) {
+			continue
+		}
+		candidates = append(candidates, jobType)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if s.start(a) != s.start(b) {
+			return s.start(a) < s.start(b)
+		}
+		return s.last[a] < s.last[b]
+	})
+	return candidates
+}
+
+// acquire reserves a running slot for a job of a type, reporting false when
+// the type is at its limit
+func (s *scheduler) acquire(jobType domain.JobType) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.atLimit(jobType) {
+		return false
+	}
+	s.running[jobType]++
+	return true
+}
+
+// release frees a slot reserved by acquire
+func (s *scheduler) release(jobType domain.JobType) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running[jobType]--
+}
+
+// served records that a job of a type was started
+func (s *scheduler) served(jobType domain.JobType) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	start := s.start(jobType)
+	s.vtime = start
+	s.finish[jobType] = start + 1/float64(s.weight(jobType))
+	s.started++
+	s.last[jobType] = s.started
+}
+
+func (s *scheduler) atLimit(jobType domain.JobType) bool {
+	limit := s.limits[jobType]
+	return limit > 0 && s.running[jobType] >= limit
+}
+
+func (s *scheduler) start(jobType domain.JobType) float64 {
+	return max(s.finish[jobType], s.vtime)
+}
+
+func (s *scheduler) weight(jobType domain.JobType) int {
+	if weight := s.weights[jobType]; weight > 0 {
+		return weight
+	}
+	return 1
+}
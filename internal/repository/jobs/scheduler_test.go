@@ -0,0 +1,71 @@
+package jobs
+
+import (
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// take picks the next job type as a worker would when every type has
+// pending jobs
+func take(s *scheduler) domain.JobType {
+	for _, jobType := range s.candidates() {
+		if s.acquire(jobType) {
+			s.served(jobType)
+			return jobType
+		}
+	}
+	return ""
+}
+
+func TestScheduler_SharesByWeight(t *testing.T) {
+	s := newScheduler([]domain.JobType{domain.JobTypeDDEXExport, domain.JobTypeAIEnrich}, nil,
+		map[domain.JobType]int{domain.JobTypeAIEnrich: 3})
+
+	counts := map[domain.JobType]int{}
+	for i := 0; i < 40; i++ {
+		jobType := take(s)
+		counts[jobType]++
+		s.release(jobType)
+	}
+	assert.Equal(t, 30, counts[domain.JobTypeAIEnrich])
+	assert.Equal(t, 10, counts[domain.JobTypeDDEXExport])
+}
+
+func TestScheduler_SkipsTypesAtTheirLimit(t *testing.T) {
+	s := newScheduler([]domain.JobType{domain.JobTypeDDEXExport, domain.JobTypeAIEnrich},
+		map[domain.JobType]int{domain.JobTypeDDEXExport: 1}, nil)
+
+	assert.True(t, s.acquire(domain.JobTypeDDEXExport))
+	s.served(domain.JobTypeDDEXExport)
+	assert.False(t, s.acquire(domain.JobTypeDDEXExport), "at its limit")
+	assert.Equal(t, []domain.JobType{domain.JobTypeAIEnrich}, s.candidates())
+
+	s.release(domain.JobTypeDDEXExport)
+	assert.Contains(t, s.candidates(), domain.JobTypeDDEXExport)
+}
+
+func TestScheduler_IdleTypeDoesNotCatchUp(t *testing.T) {
+	s := newScheduler([]domain.JobType{domain.JobTypeDDEXExport, domain.JobTypeAIEnrich}, nil, nil)
+
+	// Only exports were pending for a while
+	for i := 0; i < 10; i++ {
+		assert.True(t, s.acquire(domain.JobTypeDDEXExport))
+		s.served(domain.JobTypeDDEXExport)
+		s.release(domain.JobTypeDDEXExport)
+	}
+
+	// Enrichment jobs arrive and alternate with exports rather than
+	// taking the next ten turns
+	var order []domain.JobType
+	for i := 0; i < 4; i++ {
+		jobType := take(s)
+		order = append(order, jobType)
+		s.release(jobType)
+	}
+	assert.Equal(t, []domain.JobType{
+		domain.JobTypeAIEnrich, domain.JobTypeDDEXExport, domain.JobTypeAIEnrich, domain.JobTypeDDEXExport,
+	}, order)
+}
@@ -0,0 +1,181 @@
+// Package notify delivers account notifications to users, and operational
+// alerts, through an external webhook, typically a mail relay.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// WebhookNotifier posts notifications as JSON to a webhook
+type WebhookNotifier struct {
+	webhookURL string
+	resetURL   string
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier creates a notifier posting to webhookURL. resetURL is the
+// front-end page that accepts a reset token; the token is appended as the
+// "token" query parameter and resetURL may be empty when the notifier is not
+// used for password resets. With an empty webhookURL notifications are only
+// logged.
+func NewWebhookNotifier(webhookURL, resetURL string) *WebhookNotifier {
+	return &WebhookNotifier{
+		webhookURL: webhookURL,
+		resetURL:   resetURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type webhookPayload struct {
+	Event     string                 `json:"event"`
+	UserID    string                 `json:"user_id"`
+	Email     string                 `json:"email"`
+	Data      map[string]interface{} `json:"data"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// SendPasswordReset delivers a password reset token
+func (n *WebhookNotifier) SendPasswordReset(ctx context.Context, user *domain.User, token string, expiresAt time.Time) error {
+	data := map[string]interface{}{
+		"token":      token,
+		"expires_at": expiresAt,
+	}
+	if n.resetURL != "" {
+		link, err := url.Parse(n.resetURL)
+		if err != nil {
+			return fmt.Errorf("invalid password reset url: %w", err)
+		}
+		q := link.Query()
+		q.Set("token", token)
+		link.RawQuery = q.Encode()
+		data["reset_url"] = link.String()
+	}
+	return n.send(ctx, webhookPayload{
+		Event:     "password_reset",
+		UserID:    user.ID,
+		Email:     user.Email,
+		Data:      data,
+		CreatedAt: time.Now(),
+	})
+}
+
+// SendNewLoginAlert reports a login from an unfamiliar device or country
+func (n *WebhookNotifier) SendNewLoginAlert(ctx context.Context, user *domain.User, session *domain.Session, novelty domain.LoginNovelty) error {
+	return n.send(ctx, webhookPayload{
+		Event:  "new_login",
+		UserID: user.ID,
+		Email:  user.Email,
+		Data: map[string]interface{}{
+			"session_id":  session.ID,
+			"device_id":   session.DeviceID,
+			"device_name": session.DeviceName,
+			"country":     session.Country,
+			"ip":          session.IP,
+			"new_device":  novelty.NewDevice,
+			"new_country": novelty.NewCountry,
+			"login_at":    session.CreatedAt,
+		},
+		CreatedAt: time.Now(),
+	})
+}
+
+// SendQueueLagAlert reports that a queue topic's backpressure level changed
+func (n *WebhookNotifier) SendQueueLagAlert(ctx context.Context, alert *domain.QueueLagAlert) error {
+	return n.send(ctx, webhookPayload{
+		Event: "queue_lag",
+		Data: map[string]interface{}{
+			"topic":        alert.Topic,
+			"lag":          alert.Lag,
+			"publish_rate": alert.PublishRate,
+			"level":        alert.Level,
+			"previous":     alert.Previous,
+			"threshold":    alert.Threshold,
+		},
+		CreatedAt: alert.SampledAt,
+	})
+}
+
+// SendRestoreCompleted reports that an archived track file can be read again
+func (n *WebhookNotifier) SendRestoreCompleted(ctx context.Context, notice *domain.RestoreNotice) error {
+	return n.send(ctx, webhookPayload{
+		Event:  "restore_completed",
+		UserID: notice.RequestedBy,
+		Data: map[string]interface{}{
+			"track_id":       notice.TrackID,
+			"key":            notice.Key,
+			"requested_at":   notice.RequestedAt,
+			"restored_until": notice.RestoredUntil,
+		},
+		CreatedAt: time.Now(),
+	})
+}
+
+// SendQuotaWarning reports that a user's storage usage crossed the warning
+// threshold of their quota
+func (n *WebhookNotifier) SendQuotaWarning(ctx context.Context, warning *domain.QuotaWarning) error {
+	return n.send(ctx, webhookPayload{
+		Event:  "quota_warning",
+		UserID: warning.UserID,
+		Data: map[string]interface{}{
+			"used":    warning.Used,
+			"limit":   warning.Limit,
+			"percent": warning.Percent,
+		},
+		CreatedAt: time.Now(),
+	})
+}
+
+// SendEventNotice reports a domain event, such as a track being created
+func (n *WebhookNotifier) SendEventNotice(ctx context.Context, notice *domain.EventNotice) error {
+	data := map[string]interface{}{
+		"track_id": notice.TrackID,
+		"label_id": notice.LabelID,
+	}
+	if len(notice.Errors) > 0 {
+		data["errors"] = notice.Errors
+	}
+	return n.send(ctx, webhookPayload{
+		Event:     notice.Event,
+		UserID:    notice.Actor,
+		Data:      data,
+		CreatedAt: notice.At,
+	})
+}
+
+func (n *WebhookNotifier) send(ctx context.Context, payload webhookPayload) error {
+	if n.webhookURL == "" {
+		log.Printf("notification webhook not configured, dropping %s notification for user %s", payload.Event, payload.UserID)
+		return nil
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
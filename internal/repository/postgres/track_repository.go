@@ -21,6 +21,7 @@ type TrackModel struct {
 	UpdatedAt   time.Time
 	DeletedAt   *time.Time      `gorm:"index"`
 	Metadata    json.RawMessage `gorm:"type:jsonb"`
+	Version     int             `gorm:"not null;default:1"`
 }
 
 // PostgresTrackRepository implements domain.TrackRepository
@@ -43,6 +44,9 @@ func (r *PostgresTrackRepository) Create(ctx context.Context, track *domain.Trac
 	if track.ID == "" {
 		track.ID = uuid.New().String()
 	}
+	if track.Version == 0 {
+		track.Version = 1
+	}
 
 	model, err := toModel(track)
 	if err != nil {
@@ -78,6 +82,7 @@ func (r *PostgresTrackRepository) GetByID(ctx context.Context, id string) (*doma
 		CreatedAt:   model.CreatedAt,
 		UpdatedAt:   model.UpdatedAt,
 		DeletedAt:   model.DeletedAt,
+		Version:     model.Version,
 	}
 
 	if err := json.Unmarshal(model.Metadata, &track.Metadata); err != nil {
@@ -98,16 +103,28 @@ func (r *PostgresTrackRepository) Update(ctx context.Context, track *domain.Trac
 	if err != nil {
 		return fmt.Errorf("failed to convert track to model: %w", err)
 	}
+	expected := track.Version
+	model.Version = expected + 1
 
-	result := r.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", track.ID).Updates(model)
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND deleted_at IS NULL AND version = ?", track.ID, expected).
+		Updates(model)
 	if result.Error != nil {
 		return fmt.Errorf("failed to update track: %w", result.Error)
 	}
 
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("track not found: %s", track.ID)
+		current, err := r.GetByID(ctx, track.ID)
+		if err != nil {
+			return err
+		}
+		if current == nil || current.DeletedAt != nil {
+			return fmt.Errorf("track not found: %s", track.ID)
+		}
+		return domain.NewVersionConflictError(current, track)
 	}
 
+	track.Version = model.Version
 	return nil
 }
 
@@ -228,6 +245,7 @@ func toModel(track *domain.Track) (*TrackModel, error) {
 		UpdatedAt:   track.UpdatedAt,
 		DeletedAt:   track.DeletedAt,
 		Metadata:    metadata,
+		Version:     track.Version,
 	}, nil
 }
 
@@ -247,6 +265,7 @@ func (m *TrackModel) toDomain() (*domain.Track, error) {
 		UpdatedAt:   m.UpdatedAt,
 		DeletedAt:   m.DeletedAt,
 		Metadata:    metadata,
+		Version:     m.Version,
 	}
 	return track, nil
 }
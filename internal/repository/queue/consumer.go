@@ -0,0 +1,84 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"sort"
+	"sync"
+)
+
+// subscriber delivers the messages of a topic to a handler, such as
+// PubSubService or RedisQueue
+type subscriber interface {
+	Subscribe(ctx context.Context, topic string, handler domain.MessageHandler) error
+}
+
+// Consumer collects the message handlers of the application by topic, so
+// they can be registered before the queue is connected and subscribed once
+// it is
+type Consumer struct {
+	mu       sync.Mutex
+	handlers map[string]domain.MessageHandler
+	started  bool
+}
+
+// NewConsumer creates a consumer without handlers
+func NewConsumer() *Consumer {
+	return &Consumer{handlers: make(map[string]domain.MessageHandler)}
+}
+
+// Register sets the handler of a topic. Each topic has one handler, and
+// handlers must be registered before Start.
+func (c *Consumer) Register(topic string, handler domain.MessageHandler) error {
+	if handler == nil {
+		return fmt.Errorf("message handler is required")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.started {
+		return fmt.Errorf("consumer already started")
+	}
+	if _, exists := c.handlers[topic]; exists {
+		return fmt.Errorf("handler already registered for topic: %s", topic)
+	}
+	c.handlers[topic] = handler
+	return nil
+}
+
+// Topics lists the topics with a handler in name order
+func (c *Consumer) Topics() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	topics := make([]string, 0, len(c.handlers))
+	for topic := range c.handlers {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// Start subscribes every registered handler to its topic on sub. It stops
+// at the first topic that cannot be subscribed; the topics subscribed
+// before it keep receiving.
+func (c *Consumer) Start(ctx context.Context, sub subscriber) error {
+	c.mu.Lock()
+	if c.started {
+		c.mu.Unlock()
+		return fmt.Errorf("consumer already started")
+	}
+	c.started = true
+	c.mu.Unlock()
+
+	for _, topic := range c.Topics() {
+		c.mu.Lock()
+		handler := c.handlers[topic]
+		c.mu.Unlock()
+		if err := sub.Subscribe(ctx, topic, handler); err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"metadatatool/internal/pkg/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingSubscriber struct {
+	topics []string
+	fail   string
+}
+
+func (s *recordingSubscriber) Subscribe(ctx context.Context, topic string, handler domain.MessageHandler) error {
+	if topic == s.fail {
+		return errors.New("permission denied")
+	}
+	s.topics = append(s.topics, topic)
+	return nil
+}
+
+func TestConsumer_SubscribesRegisteredHandlers(t *testing.T) {
+	handler := func(ctx context.Context, msg *domain.Message) error { return nil }
+	consumer := NewConsumer()
+	require.NoError(t, consumer.Register("track-changes", handler))
+	require.NoError(t, consumer.Register("enrich", handler))
+	assert.Error(t, consumer.Register("enrich", handler), "one handler per topic")
+	assert.Error(t, consumer.Register("validate", nil))
+
+	sub := &recordingSubscriber{}
+	require.NoError(t, consumer.Start(context.Background(), sub))
+	assert.Equal(t, []string{"enrich", "track-changes"}, sub.topics)
+
+	assert.Error(t, consumer.Register("validate", handler), "registered after start")
+	assert.Error(t, consumer.Start(context.Background(), sub), "started twice")
+}
+
+func TestConsumer_ReportsTopicThatCannotBeSubscribed(t *testing.T) {
+	handler := func(ctx context.Context, msg *domain.Message) error { return nil }
+	consumer := NewConsumer()
+	require.NoError(t, consumer.Register("enrich", handler))
+
+	err := consumer.Start(context.Background(), &recordingSubscriber{fail: "enrich"})
+	assert.ErrorContains(t, err, "failed to subscribe to enrich: permission denied")
+}
@@ -0,0 +1,28 @@
+package queue
+
+import (
+	"context"
+	"metadatatool/internal/pkg/domain"
+)
+
+// MonitoredPublisher counts the messages a change feed publisher publishes
+// so the queue monitor can report each topic's publish rate. Ordered
+// messages are never held back.
+type MonitoredPublisher struct {
+	domain.ChangeFeedPublisher
+	admitter domain.QueueAdmitter
+}
+
+// NewMonitoredPublisher wraps publisher, reporting publishes to admitter
+func NewMonitoredPublisher(publisher domain.ChangeFeedPublisher, admitter domain.QueueAdmitter) *MonitoredPublisher {
+	return &MonitoredPublisher{ChangeFeedPublisher: publisher, admitter: admitter}
+}
+
+// PublishOrdered publishes message and counts it for topic
+func (p *MonitoredPublisher) PublishOrdered(ctx context.Context, topic, orderingKey string, message *domain.Message) error {
+	if err := p.ChangeFeedPublisher.PublishOrdered(ctx, topic, orderingKey, message); err != nil {
+		return err
+	}
+	p.admitter.RecordPublish(topic)
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"cloud.google.com/go/pubsub"
+	"google.golang.org/api/option"
 )
 
 // PubSubConfig holds configuration for Google Pub/Sub
@@ -22,6 +23,13 @@ type PubSubConfig struct {
 	MaxRetries         int           `env:"PUBSUB_MAX_RETRIES" envDefault:"3"`
 	AckDeadline        time.Duration `env:"PUBSUB_ACK_DEADLINE" envDefault:"30s"`
 	RetentionDuration  time.Duration `env:"PUBSUB_RETENTION" envDefault:"168h"` // 7 days
+	// MaxExtension is how long the ack deadline of a message is extended
+	// while its handler runs. Handlers are cancelled once it passes, since
+	// Pub/Sub then delivers the message again.
+	MaxExtension time.Duration `env:"PUBSUB_MAX_EXTENSION" envDefault:"10m"`
+	// ShutdownWait is how long Close waits for running handlers before
+	// their messages are nacked for redelivery
+	ShutdownWait time.Duration `env:"JOB_SHUTDOWN_WAIT" envDefault:"30s"`
 }
 
 // PubSubService implements domain.QueueService using Google Pub/Sub
@@ -32,28 +40,42 @@ type PubSubService struct {
 	subs     map[string]*pubsub.Subscription
 	metrics  *metrics.QueueMetrics
 	handlers map[string]domain.MessageHandler
-	mu       sync.RWMutex // protects handlers and topics
+	mu       sync.RWMutex // protects handlers, topics and subs
+
+	// Close cancels receiving to stop intake and handlerCtx once handlers
+	// outlast ShutdownWait; receivers counts the running Receive calls
+	receiving     context.Context
+	stopReceiving context.CancelFunc
+	handlerCtx    context.Context
+	abortHandlers context.CancelFunc
+	receivers     sync.WaitGroup
 }
 
 // NewPubSubService creates a new Google Pub/Sub service
-func NewPubSubService(ctx context.Context, config *PubSubConfig, metrics *metrics.QueueMetrics) (*PubSubService, error) {
+func NewPubSubService(ctx context.Context, config *PubSubConfig, metrics *metrics.QueueMetrics, opts ...option.ClientOption) (*PubSubService, error) {
 	if config == nil {
 		return nil, fmt.Errorf("pubsub config is required")
 	}
 
 	// Create Pub/Sub client
-	client, err := pubsub.NewClient(ctx, config.ProjectID)
+	client, err := pubsub.NewClient(ctx, config.ProjectID, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create pubsub client: %w", err)
 	}
 
+	receiving, stopReceiving := context.WithCancel(context.Background())
+	handlerCtx, abortHandlers := context.WithCancel(context.Background())
 	return &PubSubService{
-		client:   client,
-		config:   config,
-		topics:   make(map[string]*pubsub.Topic),
-		subs:     make(map[string]*pubsub.Subscription),
-		metrics:  metrics,
-		handlers: make(map[string]domain.MessageHandler),
+		client:        client,
+		config:        config,
+		topics:        make(map[string]*pubsub.Topic),
+		subs:          make(map[string]*pubsub.Subscription),
+		metrics:       metrics,
+		handlers:      make(map[string]domain.MessageHandler),
+		receiving:     receiving,
+		stopReceiving: stopReceiving,
+		handlerCtx:    handlerCtx,
+		abortHandlers: abortHandlers,
 	}, nil
 }
 
@@ -116,7 +138,49 @@ func (s *PubSubService) Publish(ctx context.Context, topic string, message *doma
 	return nil
 }
 
-// Subscribe subscribes to a topic and processes messages
+// PublishOrdered publishes a message to the named topic. Messages sharing an
+// ordering key are delivered to subscribers in publish order.
+func (s *PubSubService) PublishOrdered(ctx context.Context, topic, orderingKey string, message *domain.Message) error {
+	start := time.Now()
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		s.metrics.PublishErrors.WithLabelValues(topic).Inc()
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	t, err := s.ensureTopic(ctx, topic)
+	if err != nil {
+		return err
+	}
+	t.EnableMessageOrdering = true
+
+	result := t.Publish(ctx, &pubsub.Message{
+		Data:        data,
+		OrderingKey: orderingKey,
+		Attributes: map[string]string{
+			"message_id": message.ID,
+			"type":       message.Type,
+		},
+	})
+
+	if _, err := result.Get(ctx); err != nil {
+		// A failed ordered publish pauses the key until it is resumed
+		t.ResumePublish(orderingKey)
+		s.metrics.PublishErrors.WithLabelValues(topic).Inc()
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	s.metrics.PublishLatency.WithLabelValues(topic).Observe(time.Since(start).Seconds())
+	s.metrics.MessagesPublished.WithLabelValues(topic).Inc()
+
+	return nil
+}
+
+// Subscribe creates the topic's subscription if needed and delivers its
+// messages to handler. A message whose handler fails is delivered again,
+// and is published to the dead letter topic once it has failed MaxRetries
+// more times.
 func (s *PubSubService) Subscribe(ctx context.Context, topic string, handler domain.MessageHandler) error {
 	if handler == nil {
 		return fmt.Errorf("message handler is required")
@@ -124,50 +188,42 @@ func (s *PubSubService) Subscribe(ctx context.Context, topic string, handler dom
 
 	// Register handler
 	s.mu.Lock()
+	if _, exists := s.handlers[topic]; exists {
+		s.mu.Unlock()
+		return fmt.Errorf("handler already registered for topic: %s", topic)
+	}
 	s.handlers[topic] = handler
 	s.mu.Unlock()
 
 	// Get or create subscription
 	sub, err := s.ensureSubscription(ctx, topic)
 	if err != nil {
+		s.mu.Lock()
+		delete(s.handlers, topic)
+		s.mu.Unlock()
 		return err
 	}
 
-	// Configure subscription
+	// Configure subscription. The client extends the ack deadline of each
+	// message by AckDeadline until its handler returns or MaxExtension
+	// passes.
 	sub.ReceiveSettings.MaxOutstandingMessages = 100
 	sub.ReceiveSettings.NumGoroutines = 10
+	sub.ReceiveSettings.MaxExtension = s.config.MaxExtension
+	if s.config.AckDeadline >= 10*time.Second && s.config.AckDeadline <= 600*time.Second {
+		sub.ReceiveSettings.MaxExtensionPeriod = s.config.AckDeadline
+	}
 
-	// Start receiving messages
+	// Start receiving messages until ctx is done or the service is closed
+	receiveCtx, stopReceive := context.WithCancel(ctx)
+	stopOnClose := context.AfterFunc(s.receiving, stopReceive)
+	s.receivers.Add(1)
 	go func() {
-		err := sub.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
-			// Record start time for latency tracking
-			start := time.Now()
-
-			// Unmarshal message
-			var message domain.Message
-			if err := json.Unmarshal(msg.Data, &message); err != nil {
-				s.metrics.ProcessingErrors.WithLabelValues(topic).Inc()
-				msg.Nack()
-				return
-			}
-
-			// Get handler
-			s.mu.RLock()
-			handler := s.handlers[topic]
-			s.mu.RUnlock()
-
-			// Process message
-			if err := handler(ctx, &message); err != nil {
-				s.metrics.ProcessingErrors.WithLabelValues(topic).Inc()
-				msg.Nack()
-				return
-			}
-
-			// Record metrics
-			s.metrics.ProcessingLatency.WithLabelValues(topic).Observe(time.Since(start).Seconds())
-			s.metrics.MessagesProcessed.WithLabelValues(topic).Inc()
-
-			msg.Ack()
+		defer s.receivers.Done()
+		defer stopOnClose()
+		defer stopReceive()
+		err := sub.Receive(receiveCtx, func(ctx context.Context, msg *pubsub.Message) {
+			s.receive(ctx, topic, msg)
 		})
 		if err != nil {
 			// Log error and increment metric
@@ -178,6 +234,95 @@ func (s *PubSubService) Subscribe(ctx context.Context, topic string, handler dom
 	return nil
 }
 
+// receive hands a message to the topic's handler and acks it once handled
+func (s *PubSubService) receive(ctx context.Context, topic string, msg *pubsub.Message) {
+	// Record start time for latency tracking
+	start := time.Now()
+
+	// A message that cannot be read never will be
+	var message domain.Message
+	if err := json.Unmarshal(msg.Data, &message); err != nil {
+		s.metrics.ProcessingErrors.WithLabelValues(topic).Inc()
+		s.deadLetter(ctx, topic, msg, msg.Data, err)
+		return
+	}
+
+	// Get handler
+	s.mu.RLock()
+	handler := s.handlers[topic]
+	s.mu.RUnlock()
+
+	// Handlers keep running while intake stops and are only cancelled when
+	// they outlast the shutdown wait, or the ack deadline can no longer be
+	// extended
+	handlerCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	if s.config.MaxExtension > 0 {
+		handlerCtx, cancel = context.WithTimeout(context.WithoutCancel(ctx), s.config.MaxExtension)
+	}
+	defer cancel()
+	defer context.AfterFunc(s.handlerCtx, cancel)()
+
+	// Process message
+	attempt := 0
+	if msg.DeliveryAttempt != nil {
+		attempt = *msg.DeliveryAttempt
+	}
+	message.RetryCount = max(attempt-1, 0)
+	if err := handler(handlerCtx, &message); err != nil {
+		s.metrics.ProcessingErrors.WithLabelValues(topic).Inc()
+		// Delivery attempts are only counted for subscriptions with a
+		// dead letter policy; without one Pub/Sub retries indefinitely
+		if attempt == 0 || attempt <= s.config.MaxRetries {
+			msg.Nack()
+			return
+		}
+		now := time.Now()
+		message.Status = domain.MessageStatusDeadLetter
+		message.ErrorMessage = err.Error()
+		message.UpdatedAt = now
+		message.DeadLetterAt = &now
+		data, marshalErr := json.Marshal(&message)
+		if marshalErr != nil {
+			msg.Nack()
+			return
+		}
+		s.deadLetter(ctx, topic, msg, data, err)
+		return
+	}
+
+	// Record metrics
+	s.metrics.ProcessingLatency.WithLabelValues(topic).Observe(time.Since(start).Seconds())
+	s.metrics.MessagesProcessed.WithLabelValues(topic).Inc()
+
+	msg.Ack()
+}
+
+// deadLetter publishes data for a message of topic that could not be
+// handled to the dead letter topic and acks the message. The message is
+// nacked instead when publishing fails.
+func (s *PubSubService) deadLetter(ctx context.Context, topic string, msg *pubsub.Message, data []byte, cause error) {
+	t, err := s.ensureTopic(ctx, s.config.DeadLetterTopic)
+	if err != nil {
+		msg.Nack()
+		return
+	}
+
+	attrs := map[string]string{"topic": topic, "error": cause.Error()}
+	for key, value := range msg.Attributes {
+		if _, set := attrs[key]; !set {
+			attrs[key] = value
+		}
+	}
+	if _, err := t.Publish(ctx, &pubsub.Message{Data: data, Attributes: attrs}).Get(ctx); err != nil {
+		s.metrics.PublishErrors.WithLabelValues(s.config.DeadLetterTopic).Inc()
+		msg.Nack()
+		return
+	}
+
+	s.metrics.DeadLetters.WithLabelValues(topic).Inc()
+	msg.Ack()
+}
+
 // HandleDeadLetter processes messages from the dead letter queue
 func (s *PubSubService) HandleDeadLetter(ctx context.Context, topic string, message *domain.Message) error {
 	// Record dead letter handling
@@ -193,41 +338,72 @@ func (s *PubSubService) HandleDeadLetter(ctx context.Context, topic string, mess
 	return nil
 }
 
-// Close closes the Pub/Sub client
+// Close stops receiving messages and waits up to ShutdownWait for the
+// running handlers before closing the client. Handlers still running then
+// are cancelled and their messages nacked, so Pub/Sub redelivers them.
 func (s *PubSubService) Close() error {
+	s.stopReceiving()
+	finished := make(chan struct{})
+	go func() {
+		s.receivers.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(s.config.ShutdownWait):
+	}
+	s.abortHandlers()
 	return s.client.Close()
 }
 
+// EnsureTopic creates a topic if it does not exist and reports whether it
+// was created
+func (s *PubSubService) EnsureTopic(ctx context.Context, name string) (bool, error) {
+	_, created, err := s.topic(ctx, name)
+	return created, err
+}
+
 func (s *PubSubService) ensureTopic(ctx context.Context, name string) (*pubsub.Topic, error) {
+	t, _, err := s.topic(ctx, name)
+	return t, err
+}
+
+func (s *PubSubService) topic(ctx context.Context, name string) (*pubsub.Topic, bool, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	if t, ok := s.topics[name]; ok {
-		return t, nil
+		return t, false, nil
 	}
 
 	t := s.client.Topic(name)
 	exists, err := t.Exists(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check topic existence: %w", err)
+		return nil, false, fmt.Errorf("failed to check topic existence: %w", err)
 	}
 
 	if !exists {
 		t, err = s.client.CreateTopic(ctx, name)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create topic: %w", err)
+			return nil, false, fmt.Errorf("failed to create topic: %w", err)
 		}
 	}
 
 	s.topics[name] = t
-	return t, nil
+	return t, !exists, nil
 }
 
-func (s *PubSubService) ensureSubscription(ctx context.Context, topic string) (*pubsub.Subscription, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// SubscriptionName returns the name of the subscription Subscribe uses for
+// topic
+func (s *PubSubService) SubscriptionName(topic string) string {
+	return fmt.Sprintf("%s-%s", s.config.SubscriptionPrefix, topic)
+}
 
-	if sub, ok := s.subs[topic]; ok {
+func (s *PubSubService) ensureSubscription(ctx context.Context, topic string) (*pubsub.Subscription, error) {
+	s.mu.RLock()
+	sub, ok := s.subs[topic]
+	s.mu.RUnlock()
+	if ok {
 		return sub, nil
 	}
 
@@ -235,16 +411,23 @@ func (s *PubSubService) ensureSubscription(ctx context.Context, topic string) (*
 	if err != nil {
 		return nil, err
 	}
+	// The dead letter policy needs its topic to exist
+	deadLetters, err := s.ensureTopic(ctx, s.config.DeadLetterTopic)
+	if err != nil {
+		return nil, err
+	}
 
-	subName := fmt.Sprintf("%s-sub", topic)
-	sub := s.client.Subscription(subName)
+	subName := s.SubscriptionName(topic)
+	sub = s.client.Subscription(subName)
 	exists, err := sub.Exists(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check subscription existence: %w", err)
 	}
 
 	if !exists {
-		// Create subscription with configuration
+		// Create subscription with configuration. Subscribe dead-letters
+		// messages after MaxRetries; the policy also catches those that
+		// keep crashing their consumer, within the bounds Pub/Sub allows.
 		sub, err = s.client.CreateSubscription(ctx, subName, pubsub.SubscriptionConfig{
 			Topic:             t,
 			AckDeadline:       s.config.AckDeadline,
@@ -254,8 +437,8 @@ func (s *PubSubService) ensureSubscription(ctx context.Context, topic string) (*
 				MinimumBackoff: time.Second,
 			},
 			DeadLetterPolicy: &pubsub.DeadLetterPolicy{
-				DeadLetterTopic:     s.client.Topic(s.config.DeadLetterTopic).String(),
-				MaxDeliveryAttempts: s.config.MaxRetries,
+				DeadLetterTopic:     deadLetters.String(),
+				MaxDeliveryAttempts: min(max(s.config.MaxRetries+1, 5), 100),
 			},
 		})
 		if err != nil {
@@ -263,6 +446,11 @@ func (s *PubSubService) ensureSubscription(ctx context.Context, topic string) (*
 		}
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.subs[topic]; ok {
+		return existing, nil
+	}
 	s.subs[topic] = sub
 	return sub, nil
 }
@@ -301,3 +489,11 @@ func (s *PubSubService) PurgeDeadLetters(ctx context.Context, topic string) erro
 	// Not implemented for PubSub as it doesn't support purging dead letters
 	return fmt.Errorf("purge dead letters not supported for PubSub")
 }
+
+// Ping checks that Pub/Sub is reachable by looking up the high priority topic
+func (s *PubSubService) Ping(ctx context.Context) error {
+	if _, err := s.client.Topic(s.config.HighPriorityTopic).Exists(ctx); err != nil {
+		return fmt.Errorf("failed to reach pubsub: %w", err)
+	}
+	return nil
+}
@@ -2,17 +2,103 @@ package queue
 
 import (
 	"context"
+	"errors"
 	"metadatatool/internal/pkg/domain"
 	"metadatatool/internal/pkg/metrics"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
+// The queue metrics register with the default registry, so they are
+// created once per test binary
+var testQueueMetrics = sync.OnceValue(metrics.NewQueueMetrics)
+
+// setupFakePubSub returns a service connected to an in-memory Pub/Sub
+func setupFakePubSub(t *testing.T, maxRetries int) (*PubSubService, *pstest.Server) {
+	srv := pstest.NewServer()
+	conn, err := grpc.NewClient(srv.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	config := &PubSubConfig{
+		ProjectID:          "test-project",
+		DeadLetterTopic:    "dead-letter",
+		SubscriptionPrefix: "sub",
+		MaxRetries:         maxRetries,
+		AckDeadline:        10 * time.Second,
+		MaxExtension:       time.Minute,
+		ShutdownWait:       time.Second,
+	}
+	service, err := NewPubSubService(context.Background(), config, testQueueMetrics(), option.WithGRPCConn(conn))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		service.Close()
+		srv.Close()
+	})
+	return service, srv
+}
+
+func TestPubSubService_DeliversToHandler(t *testing.T) {
+	service, _ := setupFakePubSub(t, 3)
+	ctx := context.Background()
+
+	received := make(chan *domain.Message, 1)
+	require.NoError(t, service.Subscribe(ctx, "enrich", func(ctx context.Context, msg *domain.Message) error {
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline, "handlers stop when the ack deadline can no longer be extended")
+		received <- msg
+		return nil
+	}))
+	assert.Error(t, service.Subscribe(ctx, "enrich", func(ctx context.Context, msg *domain.Message) error { return nil }),
+		"one handler per topic")
+
+	require.NoError(t, service.PublishOrdered(ctx, "enrich", "", &domain.Message{ID: "msg-1", Type: "enrich"}))
+	select {
+	case msg := <-received:
+		assert.Equal(t, "msg-1", msg.ID)
+		assert.Equal(t, 0, msg.RetryCount)
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not handled")
+	}
+}
+
+func TestPubSubService_DeadLettersAfterMaxRetries(t *testing.T) {
+	service, srv := setupFakePubSub(t, 1)
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	require.NoError(t, service.Subscribe(ctx, "enrich", func(ctx context.Context, msg *domain.Message) error {
+		calls.Add(1)
+		return errors.New("provider is down")
+	}))
+	require.NoError(t, service.PublishOrdered(ctx, "enrich", "", &domain.Message{ID: "msg-1", Type: "enrich"}))
+
+	var deadLetter *pstest.Message
+	require.Eventually(t, func() bool {
+		for _, msg := range srv.Messages() {
+			if msg.Attributes["topic"] == "enrich" {
+				deadLetter = msg
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(2), calls.Load(), "the first attempt and one retry")
+	assert.Equal(t, "provider is down", deadLetter.Attributes["error"])
+	assert.Equal(t, "msg-1", deadLetter.Attributes["message_id"])
+	assert.Contains(t, string(deadLetter.Data), `"status":"dead_letter"`)
+	assert.Contains(t, string(deadLetter.Data), `"retry_count":1`)
+}
+
 func TestPubSubService(t *testing.T) {
 	ctx := context.Background()
 
@@ -29,7 +115,7 @@ func TestPubSubService(t *testing.T) {
 	}
 
 	// Create metrics
-	metrics := metrics.NewQueueMetrics()
+	metrics := testQueueMetrics()
 
 	// Create test client with emulator
 	client, err := pubsub.NewClient(ctx, "test-project", option.WithEndpoint("localhost:8085"))
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"metadatatool/internal/pkg/domain"
 	"metadatatool/internal/pkg/metrics"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,12 +21,17 @@ const (
 	pendingPrefix    = "pending:"
 	processingPrefix = "processing:"
 	deadLetterPrefix = "dead_letter:"
+	// processingSincePrefix keys a sorted set of the messages of a topic
+	// being processed, scored by when they were taken
+	processingSincePrefix = "processing_since:"
 
 	// Lock duration for message processing
 	processingLockDuration = 5 * time.Minute
 )
 
-// RedisQueue implements domain.QueueService using Redis
+// RedisQueue implements domain.QueueService using Redis. Every move of a
+// message between lists is a Lua script, and messages left processing by a
+// process that died are returned to their topic by a reconciliation sweep.
 type RedisQueue struct {
 	client    *redis.Client
 	config    domain.QueueConfig
@@ -32,15 +39,30 @@ type RedisQueue struct {
 	mu        sync.RWMutex
 	done      chan struct{}
 	closeOnce sync.Once
+	closeErr  error
+
+	// polled is closed when polling has stopped after Close. Handlers run
+	// on handlerCtx, which is cancelled when they outlast ShutdownWait.
+	polled        chan struct{}
+	inFlight      sync.WaitGroup
+	running       map[string]string // message ID to topic, guarded by runningMu
+	runningMu     sync.Mutex
+	handlerCtx    context.Context
+	abortHandlers context.CancelFunc
 }
 
 // NewRedisQueue creates a new Redis-based queue service
 func NewRedisQueue(client *redis.Client, config domain.QueueConfig) *RedisQueue {
+	handlerCtx, abortHandlers := context.WithCancel(context.Background())
 	q := &RedisQueue{
-		client:   client,
-		config:   config,
-		handlers: make(map[string]domain.MessageHandler),
-		done:     make(chan struct{}),
+		client:        client,
+		config:        config,
+		handlers:      make(map[string]domain.MessageHandler),
+		done:          make(chan struct{}),
+		polled:        make(chan struct{}),
+		running:       make(map[string]string),
+		handlerCtx:    handlerCtx,
+		abortHandlers: abortHandlers,
 	}
 
 	// Start background workers
@@ -71,20 +93,39 @@ func (q *RedisQueue) Publish(ctx context.Context, topic string, data []byte) err
 		UpdatedAt: time.Now(),
 	}
 
-	// Marshal message data
+	return q.enqueue(ctx, topic, msg)
+}
+
+// PublishOrdered publishes a prepared message to a topic. A topic is a single
+// Redis list, so messages are picked up in publish order whatever their
+// ordering key; handlers for one topic may still run concurrently.
+func (q *RedisQueue) PublishOrdered(ctx context.Context, topic, orderingKey string, message *domain.Message) error {
+	timer := prometheus.NewTimer(metrics.MessageProcessingDuration.WithLabelValues(topic))
+	defer timer.ObserveDuration()
+
+	msg := *message
+	if msg.ID == "" {
+		msg.ID = uuid.NewString()
+	}
+	msg.Status = domain.MessageStatusPending
+	msg.UpdatedAt = time.Now()
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = msg.UpdatedAt
+	}
+
+	return q.enqueue(ctx, topic, &msg)
+}
+
+// enqueue stores a message and appends it to the topic's pending list
+func (q *RedisQueue) enqueue(ctx context.Context, topic string, msg *domain.Message) error {
 	msgBytes, err := json.Marshal(msg)
 	if err != nil {
 		metrics.QueueOperations.WithLabelValues("publish", topic, "failure").Inc()
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	// Store in Redis
-	pipe := q.client.Pipeline()
-	pipe.Set(ctx, processingPrefix+msg.ID, msgBytes, 0)
-	pipe.LPush(ctx, keyPrefix+msg.Type, msg.ID)
-	pipe.IncrBy(ctx, keyPrefix+msg.Type+":size", 1)
-
-	if _, err := pipe.Exec(ctx); err != nil {
+	keys := []string{processingPrefix + msg.ID, keyPrefix + topic, keyPrefix + topic + ":size"}
+	if err := enqueueScript.Run(ctx, q.client, keys, msg.ID, msgBytes).Err(); err != nil {
 		metrics.QueueOperations.WithLabelValues("publish", topic, "failure").Inc()
 		return fmt.Errorf("failed to store message: %w", err)
 	}
@@ -149,9 +190,14 @@ func (q *RedisQueue) RetryMessage(ctx context.Context, id string) error {
 	if msg == nil {
 		return fmt.Errorf("message not found: %s", id)
 	}
+	return q.retry(ctx, msg, msg.Type)
+}
 
+// retry returns a message to the tail of topic, or moves it to the dead
+// letter queue when it has no retries left
+func (q *RedisQueue) retry(ctx context.Context, msg *domain.Message, topic string) error {
 	if msg.RetryCount >= msg.MaxRetries {
-		return q.moveToDeadLetter(ctx, msg)
+		return q.moveToDeadLetter(ctx, msg, topic)
 	}
 
 	msg.RetryCount++
@@ -159,18 +205,11 @@ func (q *RedisQueue) RetryMessage(ctx context.Context, id string) error {
 	msg.UpdatedAt = time.Now()
 	msg.NextRetryAt = q.calculateNextRetry(msg.RetryCount)
 
-	// Store updated message
-	msgBytes, _ := json.Marshal(msg)
-	pipe := q.client.Pipeline()
-	pipe.Set(ctx, processingPrefix+msg.ID, msgBytes, 0)
-	pipe.LPush(ctx, keyPrefix+msg.Type, msg.ID)
-	pipe.IncrBy(ctx, keyPrefix+msg.Type+":size", 1)
-
-	if _, err := pipe.Exec(ctx); err != nil {
+	if err := q.moveToPending(ctx, msg, topic, "tail"); err != nil {
 		return fmt.Errorf("failed to retry message: %w", err)
 	}
 
-	metrics.MessageRetries.WithLabelValues(msg.Type).Inc()
+	metrics.MessageRetries.WithLabelValues(topic).Inc()
 	return nil
 }
 
@@ -183,25 +222,25 @@ func (q *RedisQueue) AckMessage(ctx context.Context, id string) error {
 	if msg == nil {
 		return fmt.Errorf("message not found: %s", id)
 	}
+	return q.ack(ctx, msg, msg.Type)
+}
 
-	now := time.Now()
-	msg.Status = domain.MessageStatusCompleted
-	msg.ProcessedAt = &now
-	msg.UpdatedAt = now
-
-	// Store updated message and clean up
-	msgBytes, _ := json.Marshal(msg)
-	pipe := q.client.Pipeline()
-	pipe.Set(ctx, processingPrefix+msg.ID, msgBytes, 0)
-	pipe.Del(ctx, processingPrefix+msg.ID)
-	pipe.DecrBy(ctx, keyPrefix+msg.Type+":size", 1)
-
-	if _, err := pipe.Exec(ctx); err != nil {
+// ack removes a processed message taken from topic
+func (q *RedisQueue) ack(ctx context.Context, msg *domain.Message, topic string) error {
+	keys := []string{
+		processingPrefix + msg.ID, processingPrefix + topic,
+		processingSincePrefix + topic, keyPrefix + topic + ":size",
+	}
+	acked, err := ackScript.Run(ctx, q.client, keys, msg.ID).Int()
+	if err != nil {
 		return fmt.Errorf("failed to acknowledge message: %w", err)
 	}
+	if acked == 0 {
+		return fmt.Errorf("message not found: %s", msg.ID)
+	}
 
-	metrics.QueueOperations.WithLabelValues("ack", msg.Type, "success").Inc()
-	metrics.QueueSize.WithLabelValues(msg.Type).Dec()
+	metrics.QueueOperations.WithLabelValues("ack", topic, "success").Inc()
+	metrics.QueueSize.WithLabelValues(topic).Dec()
 	return nil
 }
 
@@ -214,16 +253,17 @@ func (q *RedisQueue) NackMessage(ctx context.Context, id string, err error) erro
 	if msg == nil {
 		return fmt.Errorf("message not found: %s", id)
 	}
+	return q.nack(ctx, msg, msg.Type, err)
+}
 
+// nack records why a message taken from topic failed and retries it
+func (q *RedisQueue) nack(ctx context.Context, msg *domain.Message, topic string, err error) error {
 	msg.Status = domain.MessageStatusFailed
 	msg.UpdatedAt = time.Now()
 	msg.ErrorMessage = err.Error()
 
-	if msg.RetryCount < msg.MaxRetries {
-		return q.RetryMessage(ctx, id)
-	}
-
-	return q.moveToDeadLetter(ctx, msg)
+	// The error is kept with the message
+	return q.retry(ctx, msg, topic)
 }
 
 // ListDeadLetters retrieves messages in the dead letter queue
@@ -243,14 +283,36 @@ func (q *RedisQueue) ListDeadLetters(ctx context.Context, topic string, offset,
 	return messages, nil
 }
 
+// CountDeadLetters returns the number of messages in a topic's dead letter queue
+func (q *RedisQueue) CountDeadLetters(ctx context.Context, topic string) (int64, error) {
+	count, err := q.client.LLen(ctx, deadLetterPrefix+topic).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count dead letters: %w", err)
+	}
+	return count, nil
+}
+
+// GetDeadLetter returns a message of a topic's dead letter queue, or nil
+// when the topic has no dead letter with that ID
+func (q *RedisQueue) GetDeadLetter(ctx context.Context, topic, id string) (*domain.Message, error) {
+	msg, err := q.GetMessage(ctx, id)
+	if err != nil || msg == nil {
+		return nil, err
+	}
+	if msg.Type != topic || msg.Status != domain.MessageStatusDeadLetter {
+		return nil, nil
+	}
+	return msg, nil
+}
+
 // ReplayDeadLetter moves a message from dead letter queue back to main queue
 func (q *RedisQueue) ReplayDeadLetter(ctx context.Context, id string) error {
 	msg, err := q.GetMessage(ctx, id)
 	if err != nil {
 		return err
 	}
-	if msg == nil {
-		return fmt.Errorf("message not found: %s", id)
+	if msg == nil || msg.Status != domain.MessageStatusDeadLetter {
+		return fmt.Errorf("%w: %s", domain.ErrDeadLetterNotFound, id)
 	}
 
 	msg.Status = domain.MessageStatusPending
@@ -260,17 +322,18 @@ func (q *RedisQueue) ReplayDeadLetter(ctx context.Context, id string) error {
 	msg.ProcessedAt = nil
 	msg.DeadLetterAt = nil
 
-	// Move message back to main queue
 	msgBytes, _ := json.Marshal(msg)
-	pipe := q.client.Pipeline()
-	pipe.Set(ctx, processingPrefix+msg.ID, msgBytes, 0)
-	pipe.LRem(ctx, deadLetterPrefix+msg.Type, 0, msg.ID)
-	pipe.LPush(ctx, keyPrefix+msg.Type, msg.ID)
-	pipe.IncrBy(ctx, keyPrefix+msg.Type+":size", 1)
-
-	if _, err := pipe.Exec(ctx); err != nil {
+	keys := []string{
+		processingPrefix + msg.ID, deadLetterPrefix + msg.Type, keyPrefix + msg.Type,
+		keyPrefix + msg.Type + ":size", deadLetterPrefix + msg.Type + ":size",
+	}
+	replayed, err := replayScript.Run(ctx, q.client, keys, msg.ID, msgBytes).Int()
+	if err != nil {
 		return fmt.Errorf("failed to replay dead letter: %w", err)
 	}
+	if replayed == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrDeadLetterNotFound, id)
+	}
 
 	metrics.DeadLetterMessages.WithLabelValues(msg.Type).Dec()
 	metrics.QueueSize.WithLabelValues(msg.Type).Inc()
@@ -279,7 +342,15 @@ func (q *RedisQueue) ReplayDeadLetter(ctx context.Context, id string) error {
 
 // PurgeDeadLetters removes all messages from dead letter queue
 func (q *RedisQueue) PurgeDeadLetters(ctx context.Context, topic string) error {
+	ids, err := q.client.LRange(ctx, deadLetterPrefix+topic, 0, -1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
 	pipe := q.client.Pipeline()
+	for _, id := range ids {
+		pipe.Del(ctx, processingPrefix+id)
+	}
 	pipe.Del(ctx, deadLetterPrefix+topic)
 	pipe.Set(ctx, deadLetterPrefix+topic+":size", 0, 0)
 
@@ -291,17 +362,48 @@ func (q *RedisQueue) PurgeDeadLetters(ctx context.Context, topic string) error {
 	return nil
 }
 
-// Close closes the queue service
+// Close stops taking new messages and waits up to ShutdownWait for the
+// running handlers. Messages whose handlers are still running then are
+// returned to the head of their topic without counting a retry.
 func (q *RedisQueue) Close() error {
 	q.closeOnce.Do(func() {
 		close(q.done)
+		<-q.polled
+
+		finished := make(chan struct{})
+		go func() {
+			q.inFlight.Wait()
+			close(finished)
+		}()
+		select {
+		case <-finished:
+			q.abortHandlers()
+			return
+		case <-time.After(q.config.ShutdownWait):
+		}
+
+		// Take the messages before cancelling their handlers so they are
+		// neither acknowledged nor retried
+		q.runningMu.Lock()
+		running := q.running
+		q.running = make(map[string]string)
+		q.runningMu.Unlock()
+		q.abortHandlers()
+
+		for id, topic := range running {
+			if err := q.requeue(context.Background(), id, topic); err != nil {
+				metrics.ProcessingErrors.WithLabelValues(topic, "requeue_error").Inc()
+			}
+		}
+		q.closeErr = fmt.Errorf("shutdown timed out after %v, requeued %d running messages", q.config.ShutdownWait, len(running))
 	})
-	return nil
+	return q.closeErr
 }
 
 // Helper methods
 
 func (q *RedisQueue) processMessages() {
+	defer close(q.polled)
 	ticker := time.NewTicker(q.config.PollInterval)
 	defer ticker.Stop()
 
@@ -326,7 +428,8 @@ func (q *RedisQueue) processBatch() {
 
 	for _, topic := range topics {
 		for i := 0; i < q.config.BatchSize; i++ {
-			id, err := q.client.RPopLPush(ctx, keyPrefix+topic, processingPrefix+topic).Result()
+			keys := []string{keyPrefix + topic, processingPrefix + topic, processingSincePrefix + topic}
+			id, err := takeScript.Run(ctx, q.client, keys, time.Now().UnixMilli()).Text()
 			if err == redis.Nil {
 				break
 			}
@@ -335,7 +438,11 @@ func (q *RedisQueue) processBatch() {
 				continue
 			}
 
-			go q.processMessage(ctx, id, topic)
+			q.inFlight.Add(1)
+			go func() {
+				defer q.inFlight.Done()
+				q.processMessage(ctx, id, topic)
+			}()
 		}
 	}
 }
@@ -347,6 +454,12 @@ func (q *RedisQueue) processMessage(ctx context.Context, id, topic string) {
 		metrics.ProcessingErrors.WithLabelValues(topic, "get_message_error").Inc()
 		return
 	}
+	if msg == nil {
+		// The message is gone, such as after a purge
+		keys := []string{processingPrefix + id, processingPrefix + topic, processingSincePrefix + topic}
+		dropOrphanScript.Run(ctx, q.client, keys, id)
+		return
+	}
 
 	q.mu.RLock()
 	handler := q.handlers[topic]
@@ -364,42 +477,98 @@ func (q *RedisQueue) processMessage(ctx context.Context, id, topic string) {
 	q.client.Set(ctx, processingPrefix+id, msgBytes, 0)
 
 	// Create processing context with timeout
-	ctx, cancel := context.WithTimeout(ctx, q.config.ProcessingTimeout)
+	handlerCtx, cancel := context.WithTimeout(q.handlerCtx, q.config.ProcessingTimeout)
 	defer cancel()
 
 	// Process message
-	err = handler(ctx, msg)
+	q.runningMu.Lock()
+	q.running[id] = topic
+	q.runningMu.Unlock()
+	err = handler(handlerCtx, msg)
 	duration := time.Since(start)
 	metrics.MessageProcessingDuration.WithLabelValues(topic).Observe(duration.Seconds())
 
+	q.runningMu.Lock()
+	_, owned := q.running[id]
+	delete(q.running, id)
+	q.runningMu.Unlock()
+	if !owned {
+		// Requeued by Close
+		return
+	}
+
 	if err != nil {
 		metrics.ProcessingErrors.WithLabelValues(topic, "handler_error").Inc()
-		q.NackMessage(ctx, id, err)
+		q.nack(ctx, msg, topic, err)
 	} else {
-		q.AckMessage(ctx, id)
+		q.ack(ctx, msg, topic)
 	}
 }
 
-func (q *RedisQueue) moveToDeadLetter(ctx context.Context, msg *domain.Message) error {
+// requeue puts a message whose handler was interrupted by shutdown back at
+// the head of its topic without counting a retry
+func (q *RedisQueue) requeue(ctx context.Context, id, topic string) error {
+	msg, err := q.GetMessage(ctx, id)
+	if err != nil {
+		return err
+	}
+	if msg == nil {
+		return fmt.Errorf("message not found: %s", id)
+	}
+
+	msg.Status = domain.MessageStatusPending
+	msg.UpdatedAt = time.Now()
+
+	if err := q.moveToPending(ctx, msg, topic, "head"); err != nil {
+		return fmt.Errorf("failed to requeue message: %w", err)
+	}
+
+	metrics.QueueOperations.WithLabelValues("requeue", topic, "success").Inc()
+	return nil
+}
+
+// moveToPending stores msg and moves it from the processing list of topic
+// back to the head or the tail of topic. Messages are taken from the head.
+func (q *RedisQueue) moveToPending(ctx context.Context, msg *domain.Message, topic, end string) error {
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	keys := []string{
+		processingPrefix + msg.ID, processingPrefix + topic,
+		processingSincePrefix + topic, keyPrefix + topic,
+	}
+	moved, err := retryScript.Run(ctx, q.client, keys, msg.ID, msgBytes, end).Int()
+	if err != nil {
+		return err
+	}
+	if moved == 0 {
+		return fmt.Errorf("message not found: %s", msg.ID)
+	}
+	return nil
+}
+
+func (q *RedisQueue) moveToDeadLetter(ctx context.Context, msg *domain.Message, topic string) error {
 	now := time.Now()
 	msg.Status = domain.MessageStatusDeadLetter
 	msg.UpdatedAt = now
 	msg.DeadLetterAt = &now
 
-	// Move message to dead letter queue
 	msgBytes, _ := json.Marshal(msg)
-	pipe := q.client.Pipeline()
-	pipe.Set(ctx, processingPrefix+msg.ID, msgBytes, 0)
-	pipe.LPush(ctx, deadLetterPrefix+msg.Type, msg.ID)
-	pipe.DecrBy(ctx, keyPrefix+msg.Type+":size", 1)
-	pipe.IncrBy(ctx, deadLetterPrefix+msg.Type+":size", 1)
-
-	if _, err := pipe.Exec(ctx); err != nil {
+	keys := []string{
+		processingPrefix + msg.ID, processingPrefix + topic, processingSincePrefix + topic,
+		deadLetterPrefix + topic, keyPrefix + topic + ":size", deadLetterPrefix + topic + ":size",
+	}
+	moved, err := deadLetterScript.Run(ctx, q.client, keys, msg.ID, msgBytes).Int()
+	if err != nil {
 		return fmt.Errorf("failed to move to dead letter: %w", err)
 	}
+	if moved == 0 {
+		return fmt.Errorf("message not found: %s", msg.ID)
+	}
 
-	metrics.DeadLetterMessages.WithLabelValues(msg.Type).Inc()
-	metrics.QueueSize.WithLabelValues(msg.Type).Dec()
+	metrics.DeadLetterMessages.WithLabelValues(topic).Inc()
+	metrics.QueueSize.WithLabelValues(topic).Dec()
 	return nil
 }
 
@@ -413,62 +582,194 @@ func (q *RedisQueue) calculateNextRetry(retryCount int) *time.Time {
 	return &next
 }
 
+// cleanupExpired reconciles the messages left processing until the queue is
+// closed. Expired dead letters are removed by the maintenance scheduler
+// through ExpireDeadLetters.
 func (q *RedisQueue) cleanupExpired() {
-	ticker := time.NewTicker(time.Hour)
-	defer ticker.Stop()
+	reconcileTicker := time.NewTicker(processingLockDuration)
+	defer reconcileTicker.Stop()
 
 	for {
 		select {
 		case <-q.done:
 			return
-		case <-ticker.C:
-			ctx := context.Background()
-			q.mu.RLock()
-			topics := make([]string, 0, len(q.handlers))
-			for topic := range q.handlers {
-				topics = append(topics, topic)
+		case <-reconcileTicker.C:
+			if _, err := q.Reconcile(context.Background()); err != nil {
+				metrics.ProcessingErrors.WithLabelValues("all", "reconcile_error").Inc()
 			}
-			q.mu.RUnlock()
+		}
+	}
+}
 
-			for _, topic := range topics {
-				q.cleanupExpiredForTopic(ctx, topic)
-			}
+// Reconcile returns the messages of every topic left processing by a
+// process that died to their topic, counting a retry, and drops the IDs of
+// messages that no longer exist from the processing lists. A message is
+// left processing once it was taken processingLockDuration ago, or twice
+// ProcessingTimeout when that is longer, and its handler does not run in
+// this process. It returns the number of entries reconciled.
+func (q *RedisQueue) Reconcile(ctx context.Context) (int, error) {
+	sizeKeys, err := q.client.Keys(ctx, keyPrefix+"*:size").Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list queue topics: %w", err)
+	}
+
+	orphanAfter := processingLockDuration
+	if 2*q.config.ProcessingTimeout > orphanAfter {
+		orphanAfter = 2 * q.config.ProcessingTimeout
+	}
+	reconciled := 0
+	for _, sizeKey := range sizeKeys {
+		topic := strings.TrimSuffix(strings.TrimPrefix(sizeKey, keyPrefix), ":size")
+		n, err := q.reconcileTopic(ctx, topic, orphanAfter)
+		reconciled += n
+		if err != nil {
+			return reconciled, err
 		}
 	}
+	return reconciled, nil
 }
 
-func (q *RedisQueue) cleanupExpiredForTopic(ctx context.Context, topic string) {
-	// Cleanup expired processing messages
-	ids, _ := q.client.LRange(ctx, processingPrefix+topic, 0, -1).Result()
+func (q *RedisQueue) reconcileTopic(ctx context.Context, topic string, orphanAfter time.Duration) (int, error) {
+	ids, err := q.client.LRange(ctx, processingPrefix+topic, 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list processing messages of %s: %w", topic, err)
+	}
+	taken, err := q.client.ZRangeWithScores(ctx, processingSincePrefix+topic, 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list processing messages of %s: %w", topic, err)
+	}
+	since := make(map[string]time.Time, len(taken))
+	for _, z := range taken {
+		since[z.Member.(string)] = time.UnixMilli(int64(z.Score))
+	}
+
+	reconciled := 0
 	for _, id := range ids {
+		q.runningMu.Lock()
+		_, running := q.running[id]
+		q.runningMu.Unlock()
+		if running {
+			continue
+		}
+
+		takenAt, ok := since[id]
+		if !ok {
+			// Taken before processing times were recorded
+			q.client.ZAddNX(ctx, processingSincePrefix+topic, redis.Z{Score: float64(time.Now().UnixMilli()), Member: id})
+			continue
+		}
+		if time.Since(takenAt) < orphanAfter {
+			continue
+		}
+
 		msg, err := q.GetMessage(ctx, id)
-		if err != nil || msg == nil {
+		if err != nil {
+			return reconciled, err
+		}
+		keys := []string{processingPrefix + id, processingPrefix + topic, processingSincePrefix + topic}
+		if msg == nil {
+			if err := dropOrphanScript.Run(ctx, q.client, keys, id).Err(); err != nil {
+				return reconciled, fmt.Errorf("failed to drop orphaned message %s: %w", id, err)
+			}
+			reconciled++
 			continue
 		}
+		msg.ErrorMessage = fmt.Sprintf("processing was abandoned after %v", orphanAfter)
+		if err := q.retry(ctx, msg, topic); err != nil {
+			continue
+		}
+		metrics.QueueOperations.WithLabelValues("reconcile", topic, "success").Inc()
+		reconciled++
+	}
+	return reconciled, nil
+}
+
+// ExpireDeadLetters removes the dead letters of every topic older than
+// DeadLetterTTL
+func (q *RedisQueue) ExpireDeadLetters(ctx context.Context) (int, error) {
+	if q.config.DeadLetterTTL <= 0 {
+		return 0, nil
+	}
+	keys, err := q.client.Keys(ctx, deadLetterPrefix+"*").Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list dead letter topics: %w", err)
+	}
 
-		if time.Since(msg.UpdatedAt) > processingLockDuration {
-			q.RetryMessage(ctx, id)
+	expired := 0
+	for _, key := range keys {
+		if strings.HasSuffix(key, ":size") {
+			continue
+		}
+		n, err := q.expireDeadLetters(ctx, strings.TrimPrefix(key, deadLetterPrefix))
+		expired += n
+		if err != nil {
+			return expired, err
 		}
 	}
+	return expired, nil
+}
 
-	// Cleanup expired dead letter messages
-	if q.config.DeadLetterTTL > 0 {
-		ids, _ = q.client.LRange(ctx, deadLetterPrefix+topic, 0, -1).Result()
-		for _, id := range ids {
-			msg, err := q.GetMessage(ctx, id)
-			if err != nil || msg == nil {
-				continue
-			}
+// expireDeadLetters removes the dead letters of a topic older than
+// DeadLetterTTL
+func (q *RedisQueue) expireDeadLetters(ctx context.Context, topic string) (int, error) {
+	ids, err := q.client.LRange(ctx, deadLetterPrefix+topic, 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list dead letters of %s: %w", topic, err)
+	}
 
-			if msg.DeadLetterAt != nil && time.Since(*msg.DeadLetterAt) > q.config.DeadLetterTTL {
-				pipe := q.client.Pipeline()
-				pipe.Del(ctx, processingPrefix+id)
-				pipe.LRem(ctx, deadLetterPrefix+topic, 0, id)
-				pipe.DecrBy(ctx, deadLetterPrefix+topic+":size", 1)
-				pipe.Exec(ctx)
+	expired := 0
+	for _, id := range ids {
+		msg, err := q.GetMessage(ctx, id)
+		if err != nil || msg == nil {
+			continue
+		}
 
-				metrics.DeadLetterMessages.WithLabelValues(topic).Dec()
+		if msg.DeadLetterAt != nil && time.Since(*msg.DeadLetterAt) > q.config.DeadLetterTTL {
+			pipe := q.client.Pipeline()
+			pipe.Del(ctx, processingPrefix+id)
+			pipe.LRem(ctx, deadLetterPrefix+topic, 0, id)
+			pipe.DecrBy(ctx, deadLetterPrefix+topic+":size", 1)
+			if _, err := pipe.Exec(ctx); err != nil {
+				return expired, fmt.Errorf("failed to expire dead letter %s: %w", id, err)
 			}
+
+			metrics.DeadLetterMessages.WithLabelValues(topic).Dec()
+			expired++
 		}
 	}
+	return expired, nil
+}
+
+// Ping checks that Redis is reachable
+func (q *RedisQueue) Ping(ctx context.Context) error {
+	return q.client.Ping(ctx).Err()
+}
+
+// QueueStats reports the depth of every topic that has received messages
+func (q *RedisQueue) QueueStats(ctx context.Context) ([]domain.QueueStats, error) {
+	sizeKeys, err := q.client.Keys(ctx, keyPrefix+"*:size").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queue topics: %w", err)
+	}
+	sort.Strings(sizeKeys)
+
+	stats := make([]domain.QueueStats, 0, len(sizeKeys))
+	for _, sizeKey := range sizeKeys {
+		topic := strings.TrimSuffix(strings.TrimPrefix(sizeKey, keyPrefix), ":size")
+
+		pipe := q.client.Pipeline()
+		pending := pipe.LLen(ctx, keyPrefix+topic)
+		processing := pipe.LLen(ctx, processingPrefix+topic)
+		deadLetters := pipe.LLen(ctx, deadLetterPrefix+topic)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get stats of queue %s: %w", topic, err)
+		}
+		stats = append(stats, domain.QueueStats{
+			Name:        topic,
+			Pending:     pending.Val(),
+			Processing:  processing.Val(),
+			DeadLetters: deadLetters.Val(),
+		})
+	}
+	return stats, nil
 }
@@ -0,0 +1,126 @@
+package queue
+
+import "github.com/redis/go-redis/v9"
+
+// The scripts below make each transition of a RedisQueue message atomic, so
+// a process dying halfway cannot leave a message in no list, or in two.
+//
+// A topic keeps message IDs in three places: its pending list
+// (queue:<topic>), its processing list (processing:<topic>) with the time
+// each message was taken in processingSincePrefix+<topic>, and its dead
+// letter list (dead_letter:<topic>). Messages themselves are stored at
+// processing:<id>. <topic>:size counts the messages pending or processing.
+
+// enqueueScript stores a message and adds it to the topic's pending list.
+//
+// KEYS: message, pending list, size
+// ARGV: message ID, message JSON
+var enqueueScript = redis.NewScript(`
+redis.call('SET', KEYS[1], ARGV[2])
+redis.call('LPUSH', KEYS[2], ARGV[1])
+redis.call('INCRBY', KEYS[3], 1)
+return 1
+`)
+
+// takeScript moves the oldest pending message of a topic to its processing
+// list and records when it was taken. It returns the message ID, or nil
+// when the topic has no pending message.
+//
+// KEYS: pending list, processing list, processing since
+// ARGV: unix time in milliseconds
+var takeScript = redis.NewScript(`
+local id = redis.call('RPOPLPUSH', KEYS[1], KEYS[2])
+if not id then
+	return false
+end
+redis.call('ZADD', KEYS[3], ARGV[1], id)
+return id
+`)
+
+// ackScript removes a processed message. It returns 0 when the message
+// was already gone.
+//
+// KEYS: message, processing list, processing since, size
+// ARGV: message ID
+var ackScript = redis.NewScript(`
+redis.call('LREM', KEYS[2], 0, ARGV[1])
+redis.call('ZREM', KEYS[3], ARGV[1])
+if redis.call('DEL', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('DECRBY', KEYS[4], 1)
+return 1
+`)
+
+// retryScript stores a message and moves it from the processing list back
+// to the topic's pending list, at its head when ARGV[3] is "head" so it is
+// taken next, and at its tail otherwise. It returns 0 when the message was
+// acknowledged meanwhile.
+//
+// KEYS: message, processing list, processing since, pending list
+// ARGV: message ID, message JSON, "head" or "tail"
+var retryScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2])
+redis.call('LREM', KEYS[2], 0, ARGV[1])
+redis.call('ZREM', KEYS[3], ARGV[1])
+if ARGV[3] == 'head' then
+	redis.call('RPUSH', KEYS[4], ARGV[1])
+else
+	redis.call('LPUSH', KEYS[4], ARGV[1])
+end
+return 1
+`)
+
+// deadLetterScript stores a message and moves it from the processing list
+// to the topic's dead letter list. It returns 0 when the message was
+// acknowledged meanwhile.
+//
+// KEYS: message, processing list, processing since, dead letter list, size,
+// dead letter size
+// ARGV: message ID, message JSON
+var deadLetterScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2])
+redis.call('LREM', KEYS[2], 0, ARGV[1])
+redis.call('ZREM', KEYS[3], ARGV[1])
+redis.call('LPUSH', KEYS[4], ARGV[1])
+redis.call('DECRBY', KEYS[5], 1)
+redis.call('INCRBY', KEYS[6], 1)
+return 1
+`)
+
+// replayScript stores a dead letter and moves it back to the topic's
+// pending list. It returns 0 when the message is not a dead letter of the
+// topic.
+//
+// KEYS: message, dead letter list, pending list, size, dead letter size
+// ARGV: message ID, message JSON
+var replayScript = redis.NewScript(`
+if redis.call('LREM', KEYS[2], 0, ARGV[1]) == 0 then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2])
+redis.call('LPUSH', KEYS[3], ARGV[1])
+redis.call('INCRBY', KEYS[4], 1)
+redis.call('DECRBY', KEYS[5], 1)
+return 1
+`)
+
+// dropOrphanScript removes an ID from a topic's processing list whose
+// message is gone.
+//
+// KEYS: message, processing list, processing since
+// ARGV: message ID
+var dropOrphanScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+redis.call('LREM', KEYS[2], 0, ARGV[1])
+redis.call('ZREM', KEYS[3], ARGV[1])
+return 1
+`)
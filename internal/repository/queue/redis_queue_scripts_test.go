@@ -0,0 +1,133 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupScriptedQueue(t *testing.T, pollInterval time.Duration) (*RedisQueue, *redis.Client) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	config := domain.DefaultQueueConfig()
+	config.PollInterval = pollInterval
+	config.ProcessingTimeout = time.Second
+	config.ShutdownWait = time.Second
+	q := NewRedisQueue(client, config)
+	t.Cleanup(func() {
+		q.Close()
+		client.Close()
+		mr.Close()
+	})
+	return q, client
+}
+
+func publishRetried(t *testing.T, q *RedisQueue, topic string, maxRetries int) string {
+	msg := &domain.Message{ID: "msg-1", Type: topic, Data: map[string]interface{}{"track_id": "track-1"}, MaxRetries: maxRetries}
+	require.NoError(t, q.PublishOrdered(context.Background(), topic, "", msg))
+	return msg.ID
+}
+
+// processingState returns the IDs in a topic's processing list and sorted set
+func processingState(t *testing.T, client *redis.Client, topic string) ([]string, []string) {
+	ctx := context.Background()
+	list, err := client.LRange(ctx, processingPrefix+topic, 0, -1).Result()
+	require.NoError(t, err)
+	since, err := client.ZRange(ctx, processingSincePrefix+topic, 0, -1).Result()
+	require.NoError(t, err)
+	return list, since
+}
+
+func TestRedisQueueScripts_AckLeavesNothingProcessing(t *testing.T) {
+	q, client := setupScriptedQueue(t, 10*time.Millisecond)
+	ctx := context.Background()
+
+	done := make(chan struct{})
+	require.NoError(t, q.Subscribe(ctx, "enrich", func(ctx context.Context, msg *domain.Message) error {
+		close(done)
+		return nil
+	}))
+	id := publishRetried(t, q, "enrich", 0)
+	<-done
+
+	require.Eventually(t, func() bool {
+		msg, err := q.GetMessage(ctx, id)
+		return err == nil && msg == nil
+	}, time.Second, 10*time.Millisecond)
+	list, since := processingState(t, client, "enrich")
+	assert.Empty(t, list)
+	assert.Empty(t, since)
+	assert.Equal(t, "0", client.Get(ctx, keyPrefix+"enrich:size").Val())
+	assert.Error(t, q.AckMessage(ctx, id), "already acknowledged")
+}
+
+func TestRedisQueueScripts_RetriesThenDeadLetters(t *testing.T) {
+	q, client := setupScriptedQueue(t, 10*time.Millisecond)
+	ctx := context.Background()
+
+	calls := make(chan struct{}, 10)
+	require.NoError(t, q.Subscribe(ctx, "enrich", func(ctx context.Context, msg *domain.Message) error {
+		calls <- struct{}{}
+		return errors.New("provider is down")
+	}))
+	id := publishRetried(t, q, "enrich", 1)
+
+	require.Eventually(t, func() bool {
+		msg, err := q.GetDeadLetter(ctx, "enrich", id)
+		return err == nil && msg != nil
+	}, time.Second, 10*time.Millisecond)
+	assert.Len(t, calls, 2, "the first attempt and one retry")
+
+	msg, err := q.GetMessage(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "provider is down", msg.ErrorMessage, "the error is kept")
+	list, since := processingState(t, client, "enrich")
+	assert.Empty(t, list)
+	assert.Empty(t, since)
+	assert.Equal(t, "0", client.Get(ctx, keyPrefix+"enrich:size").Val())
+	assert.Equal(t, "1", client.Get(ctx, deadLetterPrefix+"enrich:size").Val())
+
+	require.NoError(t, q.ReplayDeadLetter(ctx, id))
+	assert.ErrorIs(t, q.ReplayDeadLetter(ctx, id), domain.ErrDeadLetterNotFound)
+}
+
+func TestRedisQueue_ReconcilesAbandonedMessages(t *testing.T) {
+	q, client := setupScriptedQueue(t, time.Hour)
+	ctx := context.Background()
+	id := publishRetried(t, q, "enrich", 3)
+
+	// A process took the message ten minutes ago and died
+	keys := []string{keyPrefix + "enrich", processingPrefix + "enrich", processingSincePrefix + "enrich"}
+	taken := time.Now().Add(-10 * time.Minute).UnixMilli()
+	require.NoError(t, takeScript.Run(ctx, client, keys, taken).Err())
+	// and another left the ID of a message that no longer exists
+	client.LPush(ctx, processingPrefix+"enrich", "gone")
+	client.ZAdd(ctx, processingSincePrefix+"enrich", redis.Z{Score: float64(taken), Member: "gone"})
+	// while a message taken a moment ago is still being handled elsewhere
+	client.LPush(ctx, processingPrefix+"enrich", "busy")
+	client.Set(ctx, processingPrefix+"busy", `{"id":"busy","type":"enrich"}`, 0)
+	client.ZAdd(ctx, processingSincePrefix+"enrich", redis.Z{Score: float64(time.Now().UnixMilli()), Member: "busy"})
+
+	reconciled, err := q.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, reconciled)
+
+	list, since := processingState(t, client, "enrich")
+	assert.Equal(t, []string{"busy"}, list)
+	assert.Equal(t, []string{"busy"}, since)
+	assert.Equal(t, []string{id}, client.LRange(ctx, keyPrefix+"enrich", 0, -1).Val())
+	msg, err := q.GetMessage(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, 1, msg.RetryCount)
+	assert.Equal(t, domain.MessageStatusRetrying, msg.Status)
+}
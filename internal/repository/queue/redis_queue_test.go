@@ -167,7 +167,10 @@ func TestRedisQueue_DeadLetterOperations(t *testing.T) {
 			UpdatedAt: now,
 		}
 		// Add messages to dead letter queue
-		err := queue.moveToDeadLetter(ctx, msg)
+		msgBytes, err := json.Marshal(msg)
+		require.NoError(t, err)
+		require.NoError(t, queue.client.Set(ctx, processingPrefix+msg.ID, msgBytes, 0).Err())
+		err = queue.moveToDeadLetter(ctx, msg, topic)
 		require.NoError(t, err)
 	}
 
@@ -0,0 +1,794 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	streamPrefix           = "stream:"
+	streamMessagePrefix    = "stream_message:"
+	streamDeadLetterPrefix = "stream_dead_letter:"
+
+	// Fields of a stream entry and of a stored message
+	entryMessageField = "id"
+	messageDataField  = "message"
+	messageEntryField = "entry"
+)
+
+// StreamQueue implements the same queue as RedisQueue on Redis Streams.
+//
+// Each topic is a stream read by a consumer group, so the messages a
+// consumer has taken but not acknowledged stay visible in the group's
+// pending entries. A consumer restarted under the same name takes its own
+// pending messages back when it subscribes, and messages left pending for
+// ClaimIdle, such as those of a consumer that crashed for good, are claimed
+// with XAUTOCLAIM. Both count as a retry, so a message that keeps crashing
+// its consumer ends up in the dead letter queue.
+//
+// Messages are stored apart from the stream entries that point at them, so
+// they can be looked up by ID. Acknowledged entries are deleted from the
+// stream.
+type StreamQueue struct {
+	client    *redis.Client
+	config    domain.QueueConfig
+	group     string
+	consumer  string
+	handlers  map[string]domain.MessageHandler
+	mu        sync.RWMutex
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+
+	// polled is closed when polling has stopped after Close. Handlers run
+	// on handlerCtx, which is cancelled when they outlast ShutdownWait.
+	polled        chan struct{}
+	inFlight      sync.WaitGroup
+	running       map[string]string // message ID to topic, guarded by runningMu
+	runningMu     sync.Mutex
+	handlerCtx    context.Context
+	abortHandlers context.CancelFunc
+}
+
+// NewStreamQueue creates a new queue service on Redis Streams
+func NewStreamQueue(client *redis.Client, config domain.QueueConfig) *StreamQueue {
+	if config.ConsumerGroup == "" {
+		config.ConsumerGroup = domain.DefaultQueueConfig().ConsumerGroup
+	}
+	consumer := config.ConsumerName
+	if consumer == "" {
+		consumer, _ = os.Hostname()
+	}
+	if consumer == "" {
+		consumer = uuid.NewString()
+	}
+	// Messages still being handled must not be claimed
+	if config.ClaimIdle <= config.ProcessingTimeout {
+		config.ClaimIdle = 2 * config.ProcessingTimeout
+	}
+
+	handlerCtx, abortHandlers := context.WithCancel(context.Background())
+	q := &StreamQueue{
+		client:        client,
+		config:        config,
+		group:         config.ConsumerGroup,
+		consumer:      consumer,
+		handlers:      make(map[string]domain.MessageHandler),
+		done:          make(chan struct{}),
+		polled:        make(chan struct{}),
+		running:       make(map[string]string),
+		handlerCtx:    handlerCtx,
+		abortHandlers: abortHandlers,
+	}
+
+	// Start background workers
+	go q.processMessages()
+
+	return q
+}
+
+// Publish publishes a message to a topic
+func (q *StreamQueue) Publish(ctx context.Context, topic string, data []byte) error {
+	timer := prometheus.NewTimer(metrics.MessageProcessingDuration.WithLabelValues(topic))
+	defer timer.ObserveDuration()
+
+	var dataMap map[string]interface{}
+	if err := json.Unmarshal(data, &dataMap); err != nil {
+		metrics.QueueOperations.WithLabelValues("publish", topic, "failure").Inc()
+		return fmt.Errorf("failed to unmarshal message data: %w", err)
+	}
+
+	now := time.Now()
+	return q.enqueue(ctx, topic, &domain.Message{
+		ID:         uuid.NewString(),
+		Type:       topic,
+		Data:       dataMap,
+		Status:     domain.MessageStatusPending,
+		MaxRetries: q.config.DefaultMaxRetries,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+}
+
+// PublishOrdered publishes a prepared message to a topic. A topic is a single
+// stream, so messages are handed out in publish order whatever their
+// ordering key; handlers for one topic may still run concurrently.
+func (q *StreamQueue) PublishOrdered(ctx context.Context, topic, orderingKey string, message *domain.Message) error {
+	timer := prometheus.NewTimer(metrics.MessageProcessingDuration.WithLabelValues(topic))
+	defer timer.ObserveDuration()
+
+	msg := *message
+	if msg.ID == "" {
+		msg.ID = uuid.NewString()
+	}
+	msg.Type = topic
+	msg.Status = domain.MessageStatusPending
+	msg.UpdatedAt = time.Now()
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = msg.UpdatedAt
+	}
+
+	return q.enqueue(ctx, topic, &msg)
+}
+
+// enqueue stores a message and adds an entry for it to the topic's stream
+func (q *StreamQueue) enqueue(ctx context.Context, topic string, msg *domain.Message) error {
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		metrics.QueueOperations.WithLabelValues("publish", topic, "failure").Inc()
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	pipe := q.client.TxPipeline()
+	pipe.HSet(ctx, streamMessagePrefix+msg.ID, messageDataField, msgBytes)
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: streamPrefix + topic,
+		Values: map[string]interface{}{entryMessageField: msg.ID},
+	})
+	if _, err := pipe.Exec(ctx); err != nil {
+		metrics.QueueOperations.WithLabelValues("publish", topic, "failure").Inc()
+		return fmt.Errorf("failed to store message: %w", err)
+	}
+
+	metrics.QueueOperations.WithLabelValues("publish", topic, "success").Inc()
+	metrics.QueueSize.WithLabelValues(topic).Inc()
+	return nil
+}
+
+// Subscribe subscribes to a topic with a message handler, creating the
+// topic's consumer group if needed. The messages this consumer had taken
+// from the topic before a restart are handled again first.
+func (q *StreamQueue) Subscribe(ctx context.Context, topic string, handler domain.MessageHandler) error {
+	err := q.client.XGroupCreateMkStream(ctx, streamPrefix+topic, q.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		metrics.QueueOperations.WithLabelValues("subscribe", topic, "failure").Inc()
+		return fmt.Errorf("failed to create consumer group for topic %s: %w", topic, err)
+	}
+
+	q.mu.Lock()
+	if _, exists := q.handlers[topic]; exists {
+		q.mu.Unlock()
+		return fmt.Errorf("handler already registered for topic: %s", topic)
+	}
+	q.handlers[topic] = handler
+	q.mu.Unlock()
+
+	// Reading from ID 0 returns the consumer's own pending entries
+	if err := q.read(ctx, topic, "0", true); err != nil {
+		metrics.ProcessingErrors.WithLabelValues(topic, "redis_error").Inc()
+	}
+	metrics.QueueOperations.WithLabelValues("subscribe", topic, "success").Inc()
+	return nil
+}
+
+// Unsubscribe removes a subscription from a topic. The consumer group is
+// kept, so messages published meanwhile are handled once subscribed again.
+func (q *StreamQueue) Unsubscribe(ctx context.Context, topic string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, exists := q.handlers[topic]; !exists {
+		return fmt.Errorf("no handler registered for topic: %s", topic)
+	}
+
+	delete(q.handlers, topic)
+	metrics.QueueOperations.WithLabelValues("unsubscribe", topic, "success").Inc()
+	return nil
+}
+
+// GetMessage retrieves a message by ID
+func (q *StreamQueue) GetMessage(ctx context.Context, id string) (*domain.Message, error) {
+	msg, _, err := q.getMessage(ctx, id)
+	return msg, err
+}
+
+// getMessage returns a message and the ID of the stream entry it was last
+// delivered with, which is empty while it is not delivered
+func (q *StreamQueue) getMessage(ctx context.Context, id string) (*domain.Message, string, error) {
+	fields, err := q.client.HMGet(ctx, streamMessagePrefix+id, messageDataField, messageEntryField).Result()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get message: %w", err)
+	}
+	data, ok := fields[0].(string)
+	if !ok {
+		return nil, "", nil
+	}
+	entry, _ := fields[1].(string)
+
+	var msg domain.Message
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal message: %w", err)
+	}
+	return &msg, entry, nil
+}
+
+// RetryMessage marks a message for retry, adding it back to the end of its
+// topic
+func (q *StreamQueue) RetryMessage(ctx context.Context, id string) error {
+	msg, entry, err := q.getMessage(ctx, id)
+	if err != nil {
+		return err
+	}
+	if msg == nil {
+		return fmt.Errorf("message not found: %s", id)
+	}
+
+	return q.retry(ctx, msg, entry)
+}
+
+// retry adds a message back to the end of its topic, or moves it to the
+// dead letter queue when it has no retries left
+func (q *StreamQueue) retry(ctx context.Context, msg *domain.Message, entry string) error {
+	if msg.RetryCount >= msg.MaxRetries {
+		return q.moveToDeadLetter(ctx, msg, entry)
+	}
+
+	msg.RetryCount++
+	msg.Status = domain.MessageStatusRetrying
+	msg.UpdatedAt = time.Now()
+	msg.NextRetryAt = q.calculateNextRetry(msg.RetryCount)
+
+	if err := q.redeliver(ctx, msg, entry); err != nil {
+		return fmt.Errorf("failed to retry message: %w", err)
+	}
+
+	metrics.MessageRetries.WithLabelValues(msg.Type).Inc()
+	return nil
+}
+
+// redeliver stores msg and replaces the stream entry it was delivered with
+// by a new one at the end of its topic
+func (q *StreamQueue) redeliver(ctx context.Context, msg *domain.Message, entry string) error {
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	stream := streamPrefix + msg.Type
+	pipe := q.client.TxPipeline()
+	pipe.HSet(ctx, streamMessagePrefix+msg.ID, messageDataField, msgBytes)
+	pipe.HDel(ctx, streamMessagePrefix+msg.ID, messageEntryField)
+	if entry != "" {
+		pipe.XAck(ctx, stream, q.group, entry)
+		pipe.XDel(ctx, stream, entry)
+	}
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		Values: map[string]interface{}{entryMessageField: msg.ID},
+	})
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// AckMessage acknowledges a message as processed, removing it and its
+// stream entry
+func (q *StreamQueue) AckMessage(ctx context.Context, id string) error {
+	msg, entry, err := q.getMessage(ctx, id)
+	if err != nil {
+		return err
+	}
+	if msg == nil {
+		return fmt.Errorf("message not found: %s", id)
+	}
+
+	stream := streamPrefix + msg.Type
+	pipe := q.client.TxPipeline()
+	if entry != "" {
+		pipe.XAck(ctx, stream, q.group, entry)
+		pipe.XDel(ctx, stream, entry)
+	}
+	pipe.Del(ctx, streamMessagePrefix+msg.ID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to acknowledge message: %w", err)
+	}
+
+	metrics.QueueOperations.WithLabelValues("ack", msg.Type, "success").Inc()
+	metrics.QueueSize.WithLabelValues(msg.Type).Dec()
+	return nil
+}
+
+// NackMessage marks a message as failed, retrying it until it runs out of
+// retries
+func (q *StreamQueue) NackMessage(ctx context.Context, id string, err error) error {
+	msg, entry, getErr := q.getMessage(ctx, id)
+	if getErr != nil {
+		return getErr
+	}
+	if msg == nil {
+		return fmt.Errorf("message not found: %s", id)
+	}
+
+	msg.Status = domain.MessageStatusFailed
+	msg.UpdatedAt = time.Now()
+	msg.ErrorMessage = err.Error()
+
+	return q.retry(ctx, msg, entry)
+}
+
+// ListDeadLetters retrieves messages in the dead letter queue
+func (q *StreamQueue) ListDeadLetters(ctx context.Context, topic string, offset, limit int) ([]*domain.Message, error) {
+	ids, err := q.client.LRange(ctx, streamDeadLetterPrefix+topic, int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	messages := make([]*domain.Message, 0, len(ids))
+	for _, id := range ids {
+		if msg, err := q.GetMessage(ctx, id); err == nil && msg != nil {
+			messages = append(messages, msg)
+		}
+	}
+
+	return messages, nil
+}
+
+// CountDeadLetters returns the number of messages in a topic's dead letter queue
+func (q *StreamQueue) CountDeadLetters(ctx context.Context, topic string) (int64, error) {
+	count, err := q.client.LLen(ctx, streamDeadLetterPrefix+topic).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count dead letters: %w", err)
+	}
+	return count, nil
+}
+
+// GetDeadLetter returns a message of a topic's dead letter queue, or nil
+// when the topic has no dead letter with that ID
+func (q *StreamQueue) GetDeadLetter(ctx context.Context, topic, id string) (*domain.Message, error) {
+	msg, err := q.GetMessage(ctx, id)
+	if err != nil || msg == nil {
+		return nil, err
+	}
+	if msg.Type != topic || msg.Status != domain.MessageStatusDeadLetter {
+		return nil, nil
+	}
+	return msg, nil
+}
+
+// ReplayDeadLetter moves a message from dead letter queue back to its topic
+func (q *StreamQueue) ReplayDeadLetter(ctx context.Context, id string) error {
+	msg, err := q.GetMessage(ctx, id)
+	if err != nil {
+		return err
+	}
+	if msg == nil || msg.Status != domain.MessageStatusDeadLetter {
+		return fmt.Errorf("%w: %s", domain.ErrDeadLetterNotFound, id)
+	}
+
+	msg.Status = domain.MessageStatusPending
+	msg.RetryCount = 0
+	msg.UpdatedAt = time.Now()
+	msg.NextRetryAt = nil
+	msg.ProcessedAt = nil
+	msg.DeadLetterAt = nil
+
+	msgBytes, _ := json.Marshal(msg)
+	pipe := q.client.TxPipeline()
+	pipe.HSet(ctx, streamMessagePrefix+msg.ID, messageDataField, msgBytes)
+	pipe.LRem(ctx, streamDeadLetterPrefix+msg.Type, 0, msg.ID)
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: streamPrefix + msg.Type,
+		Values: map[string]interface{}{entryMessageField: msg.ID},
+	})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to replay dead letter: %w", err)
+	}
+
+	metrics.DeadLetterMessages.WithLabelValues(msg.Type).Dec()
+	metrics.QueueSize.WithLabelValues(msg.Type).Inc()
+	return nil
+}
+
+// PurgeDeadLetters removes all messages from dead letter queue
+func (q *StreamQueue) PurgeDeadLetters(ctx context.Context, topic string) error {
+	ids, err := q.client.LRange(ctx, streamDeadLetterPrefix+topic, 0, -1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	pipe := q.client.TxPipeline()
+	for _, id := range ids {
+		pipe.Del(ctx, streamMessagePrefix+id)
+	}
+	pipe.Del(ctx, streamDeadLetterPrefix+topic)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to purge dead letters: %w", err)
+	}
+
+	metrics.DeadLetterMessages.WithLabelValues(topic).Set(0)
+	return nil
+}
+
+// Close stops taking new messages and waits up to ShutdownWait for the
+// running handlers. Messages whose handlers are still running then are
+// added back to their topic without counting a retry.
+func (q *StreamQueue) Close() error {
+	q.closeOnce.Do(func() {
+		close(q.done)
+		<-q.polled
+
+		finished := make(chan struct{})
+		go func() {
+			q.inFlight.Wait()
+			close(finished)
+		}()
+		select {
+		case <-finished:
+			q.abortHandlers()
+			return
+		case <-time.After(q.config.ShutdownWait):
+		}
+
+		// Take the messages before cancelling their handlers so they are
+		// neither acknowledged nor retried
+		q.runningMu.Lock()
+		running := q.running
+		q.running = make(map[string]string)
+		q.runningMu.Unlock()
+		q.abortHandlers()
+
+		for id, topic := range running {
+			if err := q.requeue(context.Background(), id); err != nil {
+				metrics.ProcessingErrors.WithLabelValues(topic, "requeue_error").Inc()
+			}
+		}
+		q.closeErr = fmt.Errorf("shutdown timed out after %v, requeued %d running messages", q.config.ShutdownWait, len(running))
+	})
+	return q.closeErr
+}
+
+// Helper methods
+
+// topics returns the topics with a handler
+func (q *StreamQueue) topics() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	topics := make([]string, 0, len(q.handlers))
+	for topic := range q.handlers {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+func (q *StreamQueue) processMessages() {
+	defer close(q.polled)
+	ticker := time.NewTicker(q.config.PollInterval)
+	defer ticker.Stop()
+
+	claimInterval := q.config.ClaimInterval
+	if claimInterval <= 0 {
+		claimInterval = domain.DefaultQueueConfig().ClaimInterval
+	}
+	claimTicker := time.NewTicker(claimInterval)
+	defer claimTicker.Stop()
+
+	for {
+		select {
+		case <-q.done:
+			return
+		case <-ticker.C:
+			ctx := context.Background()
+			for _, topic := range q.topics() {
+				if err := q.read(ctx, topic, ">", false); err != nil {
+					metrics.ProcessingErrors.WithLabelValues(topic, "redis_error").Inc()
+				}
+			}
+		case <-claimTicker.C:
+			ctx := context.Background()
+			for _, topic := range q.topics() {
+				if err := q.claimStuck(ctx, topic); err != nil {
+					metrics.ProcessingErrors.WithLabelValues(topic, "claim_error").Inc()
+				}
+			}
+		}
+	}
+}
+
+// read takes up to BatchSize entries of a topic for this consumer, new ones
+// with ">" or its own pending ones with "0", and handles them
+func (q *StreamQueue) read(ctx context.Context, topic, start string, redelivered bool) error {
+	streams, err := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    q.group,
+		Consumer: q.consumer,
+		Streams:  []string{streamPrefix + topic, start},
+		Count:    int64(q.config.BatchSize),
+		Block:    -1,
+	}).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, stream := range streams {
+		for _, entry := range stream.Messages {
+			q.dispatch(topic, entry, redelivered)
+		}
+	}
+	return nil
+}
+
+// claimStuck takes over the entries of a topic left unacknowledged for
+// ClaimIdle by any consumer of the group, and handles them again
+func (q *StreamQueue) claimStuck(ctx context.Context, topic string) error {
+	start := "0-0"
+	for {
+		entries, next, err := q.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   streamPrefix + topic,
+			Group:    q.group,
+			Consumer: q.consumer,
+			MinIdle:  q.config.ClaimIdle,
+			Start:    start,
+			Count:    int64(q.config.BatchSize),
+		}).Result()
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			metrics.QueueOperations.WithLabelValues("claim", topic, "success").Inc()
+			q.dispatch(topic, entry, true)
+		}
+		if next == "0-0" || len(entries) == 0 {
+			return nil
+		}
+		start = next
+	}
+}
+
+// dispatch handles a stream entry in the background
+func (q *StreamQueue) dispatch(topic string, entry redis.XMessage, redelivered bool) {
+	id, _ := entry.Values[entryMessageField].(string)
+	q.inFlight.Add(1)
+	go func() {
+		defer q.inFlight.Done()
+		q.processMessage(context.Background(), topic, entry.ID, id, redelivered)
+	}()
+}
+
+func (q *StreamQueue) processMessage(ctx context.Context, topic, entry, id string, redelivered bool) {
+	start := time.Now()
+	q.runningMu.Lock()
+	_, running := q.running[id]
+	q.runningMu.Unlock()
+	if running {
+		// Claimed while its handler still runs here
+		return
+	}
+
+	msg, _, err := q.getMessage(ctx, id)
+	if err != nil {
+		metrics.ProcessingErrors.WithLabelValues(topic, "get_message_error").Inc()
+		return
+	}
+	if msg == nil {
+		// The message is gone, such as after a purge
+		q.client.XAck(ctx, streamPrefix+topic, q.group, entry)
+		q.client.XDel(ctx, streamPrefix+topic, entry)
+		return
+	}
+
+	q.mu.RLock()
+	handler := q.handlers[topic]
+	q.mu.RUnlock()
+
+	if handler == nil {
+		metrics.ProcessingErrors.WithLabelValues(topic, "no_handler").Inc()
+		return
+	}
+
+	// Messages taken back from a consumer that stopped may be what stopped
+	// it, so they count as a retry
+	if redelivered {
+		if msg.RetryCount >= msg.MaxRetries {
+			if err := q.moveToDeadLetter(ctx, msg, entry); err != nil {
+				metrics.ProcessingErrors.WithLabelValues(topic, "dead_letter_error").Inc()
+			}
+			return
+		}
+		msg.RetryCount++
+		metrics.MessageRetries.WithLabelValues(topic).Inc()
+	}
+
+	// Set processing status and remember the entry to acknowledge
+	msg.Status = domain.MessageStatusProcessing
+	msg.UpdatedAt = time.Now()
+	msgBytes, _ := json.Marshal(msg)
+	q.client.HSet(ctx, streamMessagePrefix+id, messageDataField, msgBytes, messageEntryField, entry)
+
+	handlerCtx, cancel := context.WithTimeout(q.handlerCtx, q.config.ProcessingTimeout)
+	defer cancel()
+
+	q.runningMu.Lock()
+	q.running[id] = topic
+	q.runningMu.Unlock()
+	err = handler(handlerCtx, msg)
+	duration := time.Since(start)
+	metrics.MessageProcessingDuration.WithLabelValues(topic).Observe(duration.Seconds())
+
+	q.runningMu.Lock()
+	_, owned := q.running[id]
+	delete(q.running, id)
+	q.runningMu.Unlock()
+	if !owned {
+		// Requeued by Close
+		return
+	}
+
+	if err != nil {
+		metrics.ProcessingErrors.WithLabelValues(topic, "handler_error").Inc()
+		q.NackMessage(ctx, id, err)
+	} else {
+		q.AckMessage(ctx, id)
+	}
+}
+
+// requeue adds a message whose handler was interrupted by shutdown back to
+// its topic without counting a retry
+func (q *StreamQueue) requeue(ctx context.Context, id string) error {
+	msg, entry, err := q.getMessage(ctx, id)
+	if err != nil {
+		return err
+	}
+	if msg == nil {
+		return fmt.Errorf("message not found: %s", id)
+	}
+
+	msg.Status = domain.MessageStatusPending
+	msg.UpdatedAt = time.Now()
+	if err := q.redeliver(ctx, msg, entry); err != nil {
+		return fmt.Errorf("failed to requeue message: %w", err)
+	}
+
+	metrics.QueueOperations.WithLabelValues("requeue", msg.Type, "success").Inc()
+	return nil
+}
+
+func (q *StreamQueue) moveToDeadLetter(ctx context.Context, msg *domain.Message, entry string) error {
+	now := time.Now()
+	msg.Status = domain.MessageStatusDeadLetter
+	msg.UpdatedAt = now
+	msg.DeadLetterAt = &now
+
+	msgBytes, _ := json.Marshal(msg)
+	stream := streamPrefix + msg.Type
+	pipe := q.client.TxPipeline()
+	pipe.HSet(ctx, streamMessagePrefix+msg.ID, messageDataField, msgBytes)
+	pipe.HDel(ctx, streamMessagePrefix+msg.ID, messageEntryField)
+	if entry != "" {
+		pipe.XAck(ctx, stream, q.group, entry)
+		pipe.XDel(ctx, stream, entry)
+	}
+	pipe.LPush(ctx, streamDeadLetterPrefix+msg.Type, msg.ID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to move to dead letter: %w", err)
+	}
+
+	metrics.DeadLetterMessages.WithLabelValues(msg.Type).Inc()
+	metrics.QueueSize.WithLabelValues(msg.Type).Dec()
+	return nil
+}
+
+func (q *StreamQueue) calculateNextRetry(retryCount int) *time.Time {
+	if retryCount <= 0 || retryCount > len(q.config.RetryDelays) {
+		return nil
+	}
+
+	delay := time.Duration(q.config.RetryDelays[retryCount-1]) * time.Second
+	next := time.Now().Add(delay)
+	return &next
+}
+
+// ExpireDeadLetters removes the dead letters of every topic older than
+// DeadLetterTTL
+func (q *StreamQueue) ExpireDeadLetters(ctx context.Context) (int, error) {
+	if q.config.DeadLetterTTL <= 0 {
+		return 0, nil
+	}
+	keys, err := q.client.Keys(ctx, streamDeadLetterPrefix+"*").Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list dead letter topics: %w", err)
+	}
+
+	expired := 0
+	for _, key := range keys {
+		topic := strings.TrimPrefix(key, streamDeadLetterPrefix)
+		ids, err := q.client.LRange(ctx, key, 0, -1).Result()
+		if err != nil {
+			return expired, fmt.Errorf("failed to list dead letters of %s: %w", topic, err)
+		}
+		for _, id := range ids {
+			msg, err := q.GetMessage(ctx, id)
+			if err != nil || msg == nil {
+				continue
+			}
+			if msg.DeadLetterAt != nil && time.Since(*msg.DeadLetterAt) > q.config.DeadLetterTTL {
+				pipe := q.client.TxPipeline()
+				pipe.Del(ctx, streamMessagePrefix+id)
+				pipe.LRem(ctx, key, 0, id)
+				if _, err := pipe.Exec(ctx); err != nil {
+					return expired, fmt.Errorf("failed to expire dead letter %s: %w", id, err)
+				}
+
+				metrics.DeadLetterMessages.WithLabelValues(topic).Dec()
+				expired++
+			}
+		}
+	}
+	return expired, nil
+}
+
+// Ping checks that Redis is reachable
+func (q *StreamQueue) Ping(ctx context.Context) error {
+	return q.client.Ping(ctx).Err()
+}
+
+// QueueStats reports the depth of every topic that has received messages.
+// Processing counts the entries delivered to a consumer and not yet
+// acknowledged.
+func (q *StreamQueue) QueueStats(ctx context.Context) ([]domain.QueueStats, error) {
+	streams, err := q.client.Keys(ctx, streamPrefix+"*").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queue topics: %w", err)
+	}
+	sort.Strings(streams)
+
+	stats := make([]domain.QueueStats, 0, len(streams))
+	for _, stream := range streams {
+		topic := strings.TrimPrefix(stream, streamPrefix)
+
+		length, err := q.client.XLen(ctx, stream).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get stats of queue %s: %w", topic, err)
+		}
+		var processing int64
+		pending, err := q.client.XPending(ctx, stream, q.group).Result()
+		switch {
+		case err == nil:
+			processing = pending.Count
+		case !strings.HasPrefix(err.Error(), "NOGROUP"):
+			return nil, fmt.Errorf("failed to get stats of queue %s: %w", topic, err)
+		}
+		deadLetters, err := q.client.LLen(ctx, streamDeadLetterPrefix+topic).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get stats of queue %s: %w", topic, err)
+		}
+		stats = append(stats, domain.QueueStats{
+			Name:        topic,
+			Pending:     length - processing,
+			Processing:  processing,
+			DeadLetters: deadLetters,
+		})
+	}
+	return stats, nil
+}
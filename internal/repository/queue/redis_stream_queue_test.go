@@ -0,0 +1,210 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupStreamQueue(t *testing.T, consumer string) (*StreamQueue, *redis.Client, *miniredis.Miniredis) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		client.Close()
+		mr.Close()
+	})
+	return newStreamQueue(t, client, consumer), client, mr
+}
+
+func newStreamQueue(t *testing.T, client *redis.Client, consumer string) *StreamQueue {
+	config := domain.DefaultQueueConfig()
+	config.DefaultMaxRetries = 2
+	config.PollInterval = 10 * time.Millisecond
+	config.ProcessingTimeout = time.Second
+	config.ClaimIdle = time.Minute
+	config.ClaimInterval = 10 * time.Millisecond
+	config.ShutdownWait = time.Second
+	config.ConsumerName = consumer
+	q := NewStreamQueue(client, config)
+	t.Cleanup(func() { q.Close() })
+	return q
+}
+
+func publishTestMessage(t *testing.T, q *StreamQueue, topic string) {
+	data, err := json.Marshal(map[string]interface{}{"track_id": "track-1"})
+	require.NoError(t, err)
+	require.NoError(t, q.Publish(context.Background(), topic, data))
+}
+
+func TestStreamQueue_PublishAndSubscribe(t *testing.T) {
+	q, client, _ := setupStreamQueue(t, "worker-1")
+	ctx := context.Background()
+
+	received := make(chan *domain.Message, 1)
+	require.NoError(t, q.Subscribe(ctx, "enrich", func(ctx context.Context, msg *domain.Message) error {
+		received <- msg
+		return nil
+	}))
+	assert.Error(t, q.Subscribe(ctx, "enrich", nil), "one handler per topic")
+	publishTestMessage(t, q, "enrich")
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, "track-1", msg.Data["track_id"])
+		assert.Equal(t, domain.MessageStatusProcessing, msg.Status)
+	case <-time.After(time.Second):
+		t.Fatal("message was not handled")
+	}
+
+	require.Eventually(t, func() bool {
+		return client.XLen(ctx, streamPrefix+"enrich").Val() == 0
+	}, time.Second, 10*time.Millisecond, "acknowledged entries are deleted")
+	stats, err := q.QueueStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.QueueStats{{Name: "enrich"}}, stats)
+}
+
+func TestStreamQueue_RetriesThenDeadLetters(t *testing.T) {
+	q, _, _ := setupStreamQueue(t, "worker-1")
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	require.NoError(t, q.Subscribe(ctx, "enrich", func(ctx context.Context, msg *domain.Message) error {
+		calls.Add(1)
+		return errors.New("provider is down")
+	}))
+	publishTestMessage(t, q, "enrich")
+
+	require.Eventually(t, func() bool {
+		count, err := q.CountDeadLetters(ctx, "enrich")
+		return err == nil && count == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(3), calls.Load(), "the first attempt and two retries")
+
+	deadLetters, err := q.ListDeadLetters(ctx, "enrich", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, domain.MessageStatusDeadLetter, deadLetters[0].Status)
+	assert.Equal(t, "provider is down", deadLetters[0].ErrorMessage)
+
+	require.NoError(t, q.ReplayDeadLetter(ctx, deadLetters[0].ID))
+	require.Eventually(t, func() bool {
+		return calls.Load() > 3
+	}, time.Second, 10*time.Millisecond, "replayed")
+}
+
+func TestStreamQueue_TakesBackOwnPendingOnRestart(t *testing.T) {
+	q, client, _ := setupStreamQueue(t, "worker-1")
+	ctx := context.Background()
+
+	// The consumer takes the message and stops before acknowledging it
+	require.NoError(t, q.Subscribe(ctx, "enrich", func(ctx context.Context, msg *domain.Message) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	publishTestMessage(t, q, "enrich")
+	require.Eventually(t, func() bool {
+		pending, err := client.XPending(ctx, streamPrefix+"enrich", q.group).Result()
+		return err == nil && pending.Count == 1
+	}, time.Second, 10*time.Millisecond)
+	// As if the process died
+	q.runningMu.Lock()
+	q.running = map[string]string{}
+	q.runningMu.Unlock()
+	q.abortHandlers()
+
+	restarted := newStreamQueue(t, client, "worker-1")
+	received := make(chan *domain.Message, 1)
+	require.NoError(t, restarted.Subscribe(ctx, "enrich", func(ctx context.Context, msg *domain.Message) error {
+		received <- msg
+		return nil
+	}))
+	select {
+	case msg := <-received:
+		assert.Equal(t, 1, msg.RetryCount)
+	case <-time.After(time.Second):
+		t.Fatal("pending message was not taken back")
+	}
+}
+
+func TestStreamQueue_ClaimsStuckMessages(t *testing.T) {
+	q, client, mr := setupStreamQueue(t, "worker-1")
+	ctx := context.Background()
+	publishTestMessage(t, q, "enrich")
+
+	// Another consumer takes the message and crashes
+	require.NoError(t, client.XGroupCreateMkStream(ctx, streamPrefix+"enrich", q.group, "0").Err())
+	_, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group: q.group, Consumer: "crashed", Streams: []string{streamPrefix + "enrich", ">"}, Block: -1,
+	}).Result()
+	require.NoError(t, err)
+
+	received := make(chan *domain.Message, 1)
+	require.NoError(t, q.Subscribe(ctx, "enrich", func(ctx context.Context, msg *domain.Message) error {
+		received <- msg
+		return nil
+	}))
+	select {
+	case <-received:
+		t.Fatal("claimed before ClaimIdle")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	mr.SetTime(time.Now().Add(2 * time.Minute))
+	select {
+	case msg := <-received:
+		assert.Equal(t, 1, msg.RetryCount)
+	case <-time.After(time.Second):
+		t.Fatal("stuck message was not claimed")
+	}
+	require.Eventually(t, func() bool {
+		return client.XLen(ctx, streamPrefix+"enrich").Val() == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestStreamQueue_ExpiresOldDeadLetters(t *testing.T) {
+	q, client, _ := setupStreamQueue(t, "worker-1")
+	ctx := context.Background()
+
+	require.NoError(t, q.Subscribe(ctx, "enrich", func(ctx context.Context, msg *domain.Message) error {
+		return errors.New("provider is down")
+	}))
+	publishTestMessage(t, q, "enrich")
+	publishTestMessage(t, q, "enrich")
+	require.Eventually(t, func() bool {
+		count, err := q.CountDeadLetters(ctx, "enrich")
+		return err == nil && count == 2
+	}, time.Second, 10*time.Millisecond)
+
+	expired, err := q.ExpireDeadLetters(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, expired, "dead letters are kept for the TTL")
+
+	deadLetters, err := q.ListDeadLetters(ctx, "enrich", 0, 10)
+	require.NoError(t, err)
+	old := deadLetters[0]
+	deadLetterAt := time.Now().Add(-q.config.DeadLetterTTL - time.Hour)
+	old.DeadLetterAt = &deadLetterAt
+	data, err := json.Marshal(old)
+	require.NoError(t, err)
+	require.NoError(t, client.HSet(ctx, streamMessagePrefix+old.ID, messageDataField, data).Err())
+
+	expired, err = q.ExpireDeadLetters(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, expired)
+	remaining, err := q.ListDeadLetters(ctx, "enrich", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, deadLetters[1].ID, remaining[0].ID)
+}
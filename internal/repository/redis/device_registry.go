@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	userDevicesPrefix   = "user_devices:"
+	userCountriesPrefix = "user_countries:"
+)
+
+// DeviceRegistry implements domain.DeviceRegistry using Redis sets
+type DeviceRegistry struct {
+	client *redis.Client
+}
+
+// NewDeviceRegistry creates a new Redis device registry
+func NewDeviceRegistry(client *redis.Client) domain.DeviceRegistry {
+	return &DeviceRegistry{client: client}
+}
+
+// Remember adds the device and country to the user's known sets
+func (r *DeviceRegistry) Remember(ctx context.Context, userID, deviceID, country string) (domain.LoginNovelty, error) {
+	devicesKey := userDevicesPrefix + userID
+	countriesKey := userCountriesPrefix + userID
+
+	pipe := r.client.TxPipeline()
+	knownDevices := pipe.SCard(ctx, devicesKey)
+	knownCountries := pipe.SCard(ctx, countriesKey)
+	addedDevice := pipe.SAdd(ctx, devicesKey, deviceID)
+	var addedCountry *redis.IntCmd
+	if country != "" {
+		addedCountry = pipe.SAdd(ctx, countriesKey, country)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return domain.LoginNovelty{}, fmt.Errorf("failed to record login device: %w", err)
+	}
+
+	novelty := domain.LoginNovelty{
+		FirstLogin: knownDevices.Val() == 0,
+		NewDevice:  addedDevice.Val() == 1,
+	}
+	// Without a previous country there is nothing to compare against
+	if addedCountry != nil && knownCountries.Val() > 0 {
+		novelty.NewCountry = addedCountry.Val() == 1
+	}
+	return novelty, nil
+}
@@ -0,0 +1,38 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceRegistry_Remember(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	registry := NewDeviceRegistry(client)
+	ctx := context.Background()
+
+	novelty, err := registry.Remember(ctx, "user-1", "laptop", "SE")
+	require.NoError(t, err)
+	assert.True(t, novelty.FirstLogin)
+	assert.False(t, novelty.Suspicious())
+
+	novelty, err = registry.Remember(ctx, "user-1", "laptop", "SE")
+	require.NoError(t, err)
+	assert.False(t, novelty.NewDevice)
+	assert.False(t, novelty.NewCountry)
+
+	novelty, err = registry.Remember(ctx, "user-1", "laptop", "BR")
+	require.NoError(t, err)
+	assert.False(t, novelty.NewDevice)
+	assert.True(t, novelty.NewCountry)
+	assert.True(t, novelty.Suspicious())
+
+	novelty, err = registry.Remember(ctx, "user-1", "phone", "")
+	require.NoError(t, err)
+	assert.True(t, novelty.NewDevice)
+	assert.False(t, novelty.NewCountry)
+}
@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const idempotencyPrefix = "idempotency:"
+
+// inProgress marks a reserved key whose response is not recorded yet
+const inProgress = ""
+
+// IdempotencyStore implements domain.IdempotencyStore using Redis
+type IdempotencyStore struct {
+	client *redis.Client
+}
+
+// NewIdempotencyStore creates a new Redis idempotency store
+func NewIdempotencyStore(client *redis.Client) domain.IdempotencyStore {
+	return &IdempotencyStore{client: client}
+}
+
+// Reserve claims key, or returns the response recorded for it
+func (s *IdempotencyStore) Reserve(ctx context.Context, key string, ttl time.Duration) (*domain.IdempotentResponse, error) {
+	reserved, err := s.client.SetNX(ctx, idempotencyPrefix+key, inProgress, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	data, err := s.client.Get(ctx, idempotencyPrefix+key).Result()
+	// A key that disappeared since SetNX was just released by its request
+	if err == redis.Nil || (err == nil && data == inProgress) {
+		return nil, domain.ErrIdempotencyKeyInUse
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotent response: %w", err)
+	}
+
+	var response domain.IdempotentResponse
+	if err := json.Unmarshal([]byte(data), &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal idempotent response: %w", err)
+	}
+	return &response, nil
+}
+
+// Save records the response to the request that reserved key
+func (s *IdempotencyStore) Save(ctx context.Context, key string, response *domain.IdempotentResponse, ttl time.Duration) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotent response: %w", err)
+	}
+	if err := s.client.Set(ctx, idempotencyPrefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+// Release frees a reserved key
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, idempotencyPrefix+key).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
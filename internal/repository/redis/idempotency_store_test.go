@@ -0,0 +1,41 @@
+package redis
+
+import (
+	"context"
+	"metadatatool/internal/pkg/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyStore(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewIdempotencyStore(client)
+	ctx := context.Background()
+
+	response, err := store.Reserve(ctx, "user-1:key", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, response)
+
+	// The key stays reserved until its response is recorded
+	_, err = store.Reserve(ctx, "user-1:key", time.Minute)
+	assert.ErrorIs(t, err, domain.ErrIdempotencyKeyInUse)
+
+	saved := &domain.IdempotentResponse{StatusCode: 201, ContentType: "application/json", Body: []byte(`{"id":"1"}`)}
+	require.NoError(t, store.Save(ctx, "user-1:key", saved, time.Hour))
+	response, err = store.Reserve(ctx, "user-1:key", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, saved, response)
+
+	// A released key can be reserved again
+	_, err = store.Reserve(ctx, "user-1:other", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, store.Release(ctx, "user-1:other"))
+	response, err = store.Reserve(ctx, "user-1:other", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, response)
+}
@@ -0,0 +1,142 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	maintenanceLeaderKey   = "maintenance:leader"
+	maintenanceClaimPrefix = "maintenance:claimed:"
+	maintenanceRunsPrefix  = "maintenance:runs:"
+	defaultMaintenanceRuns = 50
+)
+
+// leadScript takes the lead when nobody holds it and renews it when
+// ARGV[1] holds it
+var leadScript = redis.NewScript(`
+local holder = redis.call('GET', KEYS[1])
+if holder == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+if not holder then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// resignScript gives up the lead if ARGV[1] holds it
+var resignScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// claimScript records ARGV[1] as the latest claimed run unless a run as
+// late was claimed before
+var claimScript = redis.NewScript(`
+local last = tonumber(redis.call('GET', KEYS[1]) or '0')
+if tonumber(ARGV[1]) <= last then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1])
+return 1
+`)
+
+// MaintenanceCoordinator implements domain.MaintenanceCoordinator with a
+// lease in Redis
+type MaintenanceCoordinator struct {
+	client   *redis.Client
+	instance string
+}
+
+// NewMaintenanceCoordinator creates a coordinator for the replica named
+// instance, which must be unique among the replicas
+func NewMaintenanceCoordinator(client *redis.Client, instance string) domain.MaintenanceCoordinator {
+	return &MaintenanceCoordinator{client: client, instance: instance}
+}
+
+// Lead takes or renews the lead for ttl
+func (c *MaintenanceCoordinator) Lead(ctx context.Context, ttl time.Duration) (bool, error) {
+	leading, err := leadScript.Run(ctx, c.client, []string{maintenanceLeaderKey}, c.instance, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to take the maintenance lead: %w", err)
+	}
+	return leading == 1, nil
+}
+
+// Resign gives up the lead if this replica holds it
+func (c *MaintenanceCoordinator) Resign(ctx context.Context) error {
+	if err := resignScript.Run(ctx, c.client, []string{maintenanceLeaderKey}, c.instance).Err(); err != nil {
+		return fmt.Errorf("failed to give up the maintenance lead: %w", err)
+	}
+	return nil
+}
+
+// Claim reserves the run of task due at due
+func (c *MaintenanceCoordinator) Claim(ctx context.Context, task string, due time.Time) (bool, error) {
+	claimed, err := claimScript.Run(ctx, c.client, []string{maintenanceClaimPrefix + task}, due.UnixMilli()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim maintenance task %s: %w", task, err)
+	}
+	return claimed == 1, nil
+}
+
+// MaintenanceHistory implements domain.MaintenanceHistory with a capped
+// list per task in Redis
+type MaintenanceHistory struct {
+	client *redis.Client
+	size   int64
+}
+
+// NewMaintenanceHistory creates a history keeping the latest size runs of
+// each task, 50 when size is not positive
+func NewMaintenanceHistory(client *redis.Client, size int) domain.MaintenanceHistory {
+	if size <= 0 {
+		size = defaultMaintenanceRuns
+	}
+	return &MaintenanceHistory{client: client, size: int64(size)}
+}
+
+// Record adds a run to the history of its task
+func (h *MaintenanceHistory) Record(ctx context.Context, run *domain.MaintenanceRun) error {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("failed to marshal maintenance run: %w", err)
+	}
+
+	key := maintenanceRunsPrefix + run.Task
+	pipe := h.client.TxPipeline()
+	pipe.LPush(ctx, key, data)
+	pipe.LTrim(ctx, key, 0, h.size-1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record maintenance run: %w", err)
+	}
+	return nil
+}
+
+// Runs returns the latest limit runs of task, newest first
+func (h *MaintenanceHistory) Runs(ctx context.Context, task string, limit int) ([]*domain.MaintenanceRun, error) {
+	entries, err := h.client.LRange(ctx, maintenanceRunsPrefix+task, 0, int64(limit)-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance runs: %w", err)
+	}
+
+	runs := make([]*domain.MaintenanceRun, 0, len(entries))
+	for _, entry := range entries {
+		var run domain.MaintenanceRun
+		if err := json.Unmarshal([]byte(entry), &run); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal maintenance run: %w", err)
+		}
+		runs = append(runs, &run)
+	}
+	return runs, nil
+}
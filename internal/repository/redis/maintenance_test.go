@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceCoordinator_OneReplicaLeads(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	a := NewMaintenanceCoordinator(client, "api-a")
+	b := NewMaintenanceCoordinator(client, "api-b")
+
+	leading, err := a.Lead(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, leading)
+	leading, err = b.Lead(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, leading)
+	leading, err = a.Lead(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, leading, "the leader renews its lead")
+
+	require.NoError(t, b.Resign(ctx))
+	leading, err = b.Lead(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, leading, "only the leader can resign")
+
+	require.NoError(t, a.Resign(ctx))
+	leading, err = b.Lead(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, leading)
+}
+
+func TestMaintenanceCoordinator_ClaimsEachRunOnce(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	a := NewMaintenanceCoordinator(client, "api-a")
+	b := NewMaintenanceCoordinator(client, "api-b")
+	due := time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)
+
+	claimed, err := a.Claim(ctx, "session-cleanup", due)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = b.Claim(ctx, "session-cleanup", due)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+	claimed, err = b.Claim(ctx, "session-cleanup", due.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.False(t, claimed, "earlier runs are not run after later ones")
+	claimed, err = b.Claim(ctx, "storage-cleanup", due)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+}
+
+func TestMaintenanceHistory_KeepsTheLatestRuns(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	history := NewMaintenanceHistory(client, 2)
+	for _, run := range []string{"first", "second", "third"} {
+		require.NoError(t, history.Record(ctx, &domain.MaintenanceRun{Task: "session-cleanup", Error: run}))
+	}
+
+	runs, err := history.Runs(ctx, "session-cleanup", 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "third", runs[0].Error)
+	assert.Equal(t, "second", runs[1].Error)
+}
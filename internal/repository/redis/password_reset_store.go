@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	passwordResetPrefix     = "password_reset:"
+	passwordResetUserPrefix = "password_reset_user:"
+	passwordResetRatePrefix = "password_reset_rate:"
+)
+
+// PasswordResetStore implements domain.PasswordResetStore using Redis
+type PasswordResetStore struct {
+	client *redis.Client
+}
+
+// NewPasswordResetStore creates a new Redis password reset store
+func NewPasswordResetStore(client *redis.Client) domain.PasswordResetStore {
+	return &PasswordResetStore{client: client}
+}
+
+// Save stores a token ID for the user and invalidates the previous one
+func (s *PasswordResetStore) Save(ctx context.Context, tokenID, userID string, ttl time.Duration) error {
+	previous, err := s.client.Get(ctx, passwordResetUserPrefix+userID).Result()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to get previous reset token: %w", err)
+	}
+
+	pipe := s.client.TxPipeline()
+	if previous != "" {
+		pipe.Del(ctx, passwordResetPrefix+previous)
+	}
+	pipe.Set(ctx, passwordResetPrefix+tokenID, userID, ttl)
+	pipe.Set(ctx, passwordResetUserPrefix+userID, tokenID, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to store reset token: %w", err)
+	}
+	return nil
+}
+
+// Consume redeems a token ID and returns the user it belongs to
+func (s *PasswordResetStore) Consume(ctx context.Context, tokenID string) (string, error) {
+	userID, err := s.client.GetDel(ctx, passwordResetPrefix+tokenID).Result()
+	if err == redis.Nil {
+		return "", domain.ErrInvalidToken
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to consume reset token: %w", err)
+	}
+
+	if err := s.client.Del(ctx, passwordResetUserPrefix+userID).Err(); err != nil {
+		return "", fmt.Errorf("failed to clear reset token: %w", err)
+	}
+	return userID, nil
+}
+
+// Hit increments the request counter for key, starting the window on the
+// first request
+func (s *PasswordResetStore) Hit(ctx context.Context, key string, window time.Duration) (int64, error) {
+	rateKey := passwordResetRatePrefix + key
+	pipe := s.client.TxPipeline()
+	incr := pipe.Incr(ctx, rateKey)
+	pipe.ExpireNX(ctx, rateKey, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to count reset request: %w", err)
+	}
+	return incr.Val(), nil
+}
@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"metadatatool/internal/pkg/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordResetStore_SaveAndConsume(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewPasswordResetStore(client)
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, "first", "user-1", time.Minute))
+	require.NoError(t, store.Save(ctx, "second", "user-1", time.Minute))
+
+	// Issuing a new token invalidates the previous one
+	_, err := store.Consume(ctx, "first")
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+
+	userID, err := store.Consume(ctx, "second")
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", userID)
+
+	_, err = store.Consume(ctx, "second")
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+}
+
+func TestPasswordResetStore_Hit(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewPasswordResetStore(client)
+	ctx := context.Background()
+
+	for i := int64(1); i <= 3; i++ {
+		count, err := store.Hit(ctx, "forgot:ip:10.0.0.1", time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, i, count)
+	}
+
+	ttl, err := client.TTL(ctx, passwordResetRatePrefix+"forgot:ip:10.0.0.1").Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0))
+}
@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	pkgdomain "metadatatool/internal/pkg/domain"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const publicAPIQuotaPrefix = "public_api:quota:"
+
+// takeScript counts a request in a minute and a day counter unless either
+// is at its limit, in which case nothing is counted. Returns {1, minute,
+// day} for counted requests and {0, minute, day} for refused ones; a limit
+// of 0 is unlimited.
+var takeScript = redis.NewScript(`
+local minute = tonumber(redis.call('GET', KEYS[1]) or '0')
+local day = tonumber(redis.call('GET', KEYS[2]) or '0')
+local perMinute, perDay = tonumber(ARGV[1]), tonumber(ARGV[2])
+if (perMinute > 0 and minute >= perMinute) or (perDay > 0 and day >= perDay) then
+	return {0, minute, day}
+end
+minute = redis.call('INCR', KEYS[1])
+if minute == 1 then
+	redis.call('EXPIRE', KEYS[1], ARGV[3])
+end
+day = redis.call('INCR', KEYS[2])
+if day == 1 then
+	redis.call('EXPIRE', KEYS[2], ARGV[4])
+end
+return {1, minute, day}
+`)
+
+// PublicAPIQuota implements pkg/domain.PublicAPIQuota with a counter per key
+// for every minute and every UTC day
+type PublicAPIQuota struct {
+	client *redis.Client
+}
+
+// NewPublicAPIQuota creates a new Redis public API quota
+func NewPublicAPIQuota(client *redis.Client) *PublicAPIQuota {
+	return &PublicAPIQuota{client: client}
+}
+
+// Take implements pkg/domain.PublicAPIQuota
+func (q *PublicAPIQuota) Take(ctx context.Context, keyID string, tier pkgdomain.PublicAPITier, now time.Time) (*pkgdomain.PublicAPIAllowance, error) {
+	now = now.UTC()
+	minute := now.Truncate(time.Minute)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	keys := []string{
+		fmt.Sprintf("%s%s:m:%d", publicAPIQuotaPrefix, keyID, minute.Unix()),
+		fmt.Sprintf("%s%s:d:%s", publicAPIQuotaPrefix, keyID, day.Format(pkgdomain.UsageDayFormat)),
+	}
+	// Counters outlive their window a little so clock skew between
+	// instances does not reset them early
+	args := []interface{}{tier.RequestsPerMinute, tier.RequestsPerDay, 2 * 60, 25 * 60 * 60}
+
+	result, err := takeScript.Run(ctx, q.client, keys, args...).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count public API request: %w", err)
+	}
+
+	allowance := &pkgdomain.PublicAPIAllowance{
+		Tier:            tier,
+		MinuteRemaining: remainingRequests(tier.RequestsPerMinute, result[1]),
+		DayRemaining:    remainingRequests(tier.RequestsPerDay, result[2]),
+		MinuteReset:     minute.Add(time.Minute),
+		DayReset:        day.AddDate(0, 0, 1),
+	}
+	if result[0] == 0 {
+		return allowance, pkgdomain.ErrPublicAPIQuotaExceeded
+	}
+	return allowance, nil
+}
+
+// remainingRequests returns what is left of limit after used requests, or
+// -1 when the limit is unlimited
+func remainingRequests(limit int, used int64) int {
+	if limit <= 0 {
+		return -1
+	}
+	if left := limit - int(used); left > 0 {
+		return left
+	}
+	return 0
+}
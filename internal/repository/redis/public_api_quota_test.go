@@ -0,0 +1,57 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicAPIQuota_Take(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	quota := NewPublicAPIQuota(client)
+	ctx := context.Background()
+	tier := pkgdomain.PublicAPITier{Name: pkgdomain.PublicAPITierFree, RequestsPerMinute: 2, RequestsPerDay: 3}
+	now := time.Date(2024, 3, 1, 23, 58, 30, 0, time.UTC)
+
+	allowance, err := quota.Take(ctx, "k1", tier, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, allowance.MinuteRemaining)
+	assert.Equal(t, 2, allowance.DayRemaining)
+	assert.Equal(t, time.Date(2024, 3, 1, 23, 59, 0, 0, time.UTC), allowance.MinuteReset)
+	assert.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), allowance.DayReset)
+
+	_, err = quota.Take(ctx, "k1", tier, now)
+	require.NoError(t, err)
+
+	// The minute is used up; refused requests are not counted
+	allowance, err = quota.Take(ctx, "k1", tier, now.Add(10*time.Second))
+	assert.ErrorIs(t, err, pkgdomain.ErrPublicAPIQuotaExceeded)
+	assert.Zero(t, allowance.MinuteRemaining)
+	assert.Equal(t, 1, allowance.DayRemaining)
+
+	// The next minute has room but the day is used up after one request
+	allowance, err = quota.Take(ctx, "k1", tier, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Zero(t, allowance.DayRemaining)
+	_, err = quota.Take(ctx, "k1", tier, now.Add(time.Minute))
+	assert.ErrorIs(t, err, pkgdomain.ErrPublicAPIQuotaExceeded)
+
+	// Other keys and the next day have their own counters
+	_, err = quota.Take(ctx, "k2", tier, now)
+	require.NoError(t, err)
+	_, err = quota.Take(ctx, "k1", tier, now.Add(2*time.Minute))
+	require.NoError(t, err)
+
+	// Unlimited windows report -1
+	allowance, err = quota.Take(ctx, "k3", pkgdomain.PublicAPITier{RequestsPerMinute: 10}, now)
+	require.NoError(t, err)
+	assert.Equal(t, 9, allowance.MinuteRemaining)
+	assert.Equal(t, -1, allowance.DayRemaining)
+}
@@ -0,0 +1,96 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	quotaUsagePrefix = "quota:usage:"
+	quotaFileOwners  = "quota:file_owners"
+	quotaFileSizes   = "quota:file_sizes"
+)
+
+// reserveScript adds a file to its owner's usage if it fits the limit.
+// Returns {1, usage} when the file is counted and {0, usage} when it would
+// exceed the limit; a limit of 0 is unlimited.
+var reserveScript = redis.NewScript(`
+local used = tonumber(redis.call('GET', KEYS[1]) or '0')
+if redis.call('HEXISTS', KEYS[2], ARGV[1]) == 1 then
+	return {1, used}
+end
+local size, limit = tonumber(ARGV[2]), tonumber(ARGV[3])
+if limit > 0 and used + size > limit then
+	return {0, used}
+end
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[4])
+redis.call('HSET', KEYS[3], ARGV[1], size)
+return {1, redis.call('INCRBY', KEYS[1], size)}
+`)
+
+// releaseScript removes a file from its owner's usage and returns the owner
+// and their usage, or nothing for unknown files
+var releaseScript = redis.NewScript(`
+local owner = redis.call('HGET', KEYS[1], ARGV[1])
+if not owner then
+	return {}
+end
+local size = redis.call('HGET', KEYS[2], ARGV[1]) or '0'
+redis.call('HDEL', KEYS[1], ARGV[1])
+redis.call('HDEL', KEYS[2], ARGV[1])
+return {owner, redis.call('DECRBY', ARGV[2] .. owner, size)}
+`)
+
+// QuotaStore implements pkg/domain.QuotaStore with a usage counter per user
+// and hashes recording the owner and size of every counted file
+type QuotaStore struct {
+	client *redis.Client
+}
+
+// NewQuotaStore creates a new Redis quota store
+func NewQuotaStore(client *redis.Client) *QuotaStore {
+	return &QuotaStore{client: client}
+}
+
+// Reserve implements pkg/domain.QuotaStore
+func (s *QuotaStore) Reserve(ctx context.Context, userID, fileID string, size, limit int64) (int64, error) {
+	keys := []string{quotaUsagePrefix + userID, quotaFileOwners, quotaFileSizes}
+	result, err := reserveScript.Run(ctx, s.client, keys, fileID, size, limit, userID).Int64Slice()
+	if err != nil {
+		return 0, fmt.Errorf("failed to reserve storage quota: %w", err)
+	}
+	if result[0] == 0 {
+		return result[1], pkgdomain.ErrQuotaExceeded
+	}
+	return result[1], nil
+}
+
+// Release implements pkg/domain.QuotaStore
+func (s *QuotaStore) Release(ctx context.Context, fileID string) (string, int64, error) {
+	keys := []string{quotaFileOwners, quotaFileSizes}
+	result, err := releaseScript.Run(ctx, s.client, keys, fileID, quotaUsagePrefix).Slice()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to release storage quota: %w", err)
+	}
+	if len(result) < 2 {
+		return "", 0, nil
+	}
+	owner, _ := result[0].(string)
+	used, _ := result[1].(int64)
+	return owner, used, nil
+}
+
+// Usage implements pkg/domain.QuotaStore
+func (s *QuotaStore) Usage(ctx context.Context, userID string) (int64, error) {
+	used, err := s.client.Get(ctx, quotaUsagePrefix+userID).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get storage usage: %w", err)
+	}
+	return used, nil
+}
@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaStore_ReserveAndRelease(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewQuotaStore(client)
+	ctx := context.Background()
+
+	used, err := store.Reserve(ctx, "u1", "t1", 60, 100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(60), used)
+
+	// Reserving the same file again is not counted twice
+	used, err = store.Reserve(ctx, "u1", "t1", 60, 100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(60), used)
+
+	used, err = store.Reserve(ctx, "u1", "t2", 50, 100)
+	assert.ErrorIs(t, err, pkgdomain.ErrQuotaExceeded)
+	assert.Equal(t, int64(60), used)
+
+	// Other users have their own usage
+	_, err = store.Reserve(ctx, "u2", "t3", 50, 100)
+	require.NoError(t, err)
+
+	owner, used, err := store.Release(ctx, "t1")
+	require.NoError(t, err)
+	assert.Equal(t, "u1", owner)
+	assert.Zero(t, used)
+
+	owner, _, err = store.Release(ctx, "t1")
+	require.NoError(t, err)
+	assert.Empty(t, owner)
+
+	used, err = store.Usage(ctx, "u2")
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), used)
+
+	// A zero limit is unlimited
+	used, err = store.Reserve(ctx, "u1", "t4", 1000, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), used)
+}
@@ -0,0 +1,153 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	refreshFamilyPrefix       = "refresh_family:"
+	refreshRevokedPrefix      = "refresh_revoked:"
+	refreshUserFamiliesPrefix = "refresh_user_families:"
+	refreshUserRevokedPrefix  = "refresh_user_revoked:"
+)
+
+// rotateScript swaps a family's current refresh token in one step so two
+// concurrent refreshes with the same token cannot both succeed.
+// Returns 1 on success, 0 when the token was reused and -1 when the family
+// was already revoked.
+var rotateScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[2]) == 1 then
+	return -1
+end
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	redis.call('SET', KEYS[2], '1', 'PX', ARGV[3])
+	redis.call('DEL', KEYS[1])
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+// RefreshTokenStore implements domain.RefreshTokenStore using Redis
+type RefreshTokenStore struct {
+	client *redis.Client
+}
+
+// NewRefreshTokenStore creates a new Redis refresh token store
+func NewRefreshTokenStore(client *redis.Client) domain.RefreshTokenStore {
+	return &RefreshTokenStore{client: client}
+}
+
+// Issue starts a new token family and records it under the user. The user's
+// families are kept in a sorted set scored by expiry, so expired families
+// are dropped as new ones come in.
+func (s *RefreshTokenStore) Issue(ctx context.Context, userID, familyID, tokenID string, ttl time.Duration) error {
+	now := time.Now()
+	userKey := refreshUserFamiliesPrefix + userID
+
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, refreshFamilyPrefix+familyID, tokenID, ttl)
+	pipe.ZRemRangeByScore(ctx, userKey, "-inf", strconv.FormatInt(now.UnixMilli(), 10))
+	pipe.ZAdd(ctx, userKey, redis.Z{Score: float64(now.Add(ttl).UnixMilli()), Member: familyID})
+	pipe.PExpire(ctx, userKey, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return nil
+}
+
+// Rotate replaces the family's current refresh token
+func (s *RefreshTokenStore) Rotate(ctx context.Context, familyID, presentedID, nextID string, ttl time.Duration) error {
+	keys := []string{refreshFamilyPrefix + familyID, refreshRevokedPrefix + familyID}
+	result, err := rotateScript.Run(ctx, s.client, keys, presentedID, nextID, ttl.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	switch result {
+	case 1:
+		return nil
+	case 0:
+		return domain.ErrTokenReused
+	default:
+		return domain.ErrInvalidToken
+	}
+}
+
+// RevokeFamily marks a family as revoked. ttl should cover the lifetime of
+// the longest-lived token in the family.
+func (s *RefreshTokenStore) RevokeFamily(ctx context.Context, familyID string, ttl time.Duration) error {
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, refreshRevokedPrefix+familyID, "1", ttl)
+	pipe.Del(ctx, refreshFamilyPrefix+familyID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to revoke token family: %w", err)
+	}
+	return nil
+}
+
+// RevokeUser revokes every live family of the user and records the time of
+// revocation, which rejects the user's tokens issued before it, including
+// those outside any family. ttl should cover the lifetime of the
+// longest-lived token.
+func (s *RefreshTokenStore) RevokeUser(ctx context.Context, userID string, ttl time.Duration) error {
+	now := time.Now()
+	userKey := refreshUserFamiliesPrefix + userID
+
+	families, err := s.client.ZRangeByScore(ctx, userKey, &redis.ZRangeBy{
+		Min: strconv.FormatInt(now.UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list token families: %w", err)
+	}
+
+	pipe := s.client.TxPipeline()
+	for _, familyID := range families {
+		pipe.Set(ctx, refreshRevokedPrefix+familyID, "1", ttl)
+		pipe.Del(ctx, refreshFamilyPrefix+familyID)
+	}
+	if len(families) > 0 {
+		pipe.ZRem(ctx, userKey, toMembers(families)...)
+	}
+	pipe.Set(ctx, refreshUserRevokedPrefix+userID, now.UnixMilli(), ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	return nil
+}
+
+// IsRevoked reports whether the family has been revoked
+func (s *RefreshTokenStore) IsRevoked(ctx context.Context, familyID string) (bool, error) {
+	n, err := s.client.Exists(ctx, refreshRevokedPrefix+familyID).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	return n > 0, nil
+}
+
+// RevokedBefore returns when the user's tokens were last revoked
+func (s *RefreshTokenStore) RevokedBefore(ctx context.Context, userID string) (time.Time, error) {
+	millis, err := s.client.Get(ctx, refreshUserRevokedPrefix+userID).Int64()
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to check user token revocation: %w", err)
+	}
+	return time.UnixMilli(millis), nil
+}
+
+func toMembers(values []string) []interface{} {
+	members := make([]interface{}, len(values))
+	for i, value := range values {
+		members[i] = value
+	}
+	return members
+}
@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"context"
+	"metadatatool/internal/pkg/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshTokenStore_Rotate(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRefreshTokenStore(client)
+	ctx := context.Background()
+
+	require.NoError(t, store.Issue(ctx, "user", "family", "t1", time.Hour))
+	require.NoError(t, store.Rotate(ctx, "family", "t1", "t2", time.Hour))
+
+	revoked, err := store.IsRevoked(ctx, "family")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	// Presenting the rotated token again revokes the family
+	err = store.Rotate(ctx, "family", "t1", "t3", time.Hour)
+	assert.ErrorIs(t, err, domain.ErrTokenReused)
+
+	revoked, err = store.IsRevoked(ctx, "family")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	// The legitimate current token no longer works either
+	err = store.Rotate(ctx, "family", "t2", "t4", time.Hour)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+}
+
+func TestRefreshTokenStore_RevokeFamily(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRefreshTokenStore(client)
+	ctx := context.Background()
+
+	require.NoError(t, store.Issue(ctx, "user", "family", "t1", time.Hour))
+	require.NoError(t, store.RevokeFamily(ctx, "family", time.Hour))
+
+	revoked, err := store.IsRevoked(ctx, "family")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestRefreshTokenStore_RevokeUser(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRefreshTokenStore(client)
+	ctx := context.Background()
+
+	revokedAt, err := store.RevokedBefore(ctx, "user")
+	require.NoError(t, err)
+	assert.True(t, revokedAt.IsZero())
+
+	require.NoError(t, store.Issue(ctx, "user", "f1", "t1", time.Hour))
+	require.NoError(t, store.Issue(ctx, "user", "f2", "t2", time.Hour))
+	require.NoError(t, store.Issue(ctx, "other", "f3", "t3", time.Hour))
+
+	before := time.Now().Truncate(time.Millisecond)
+	require.NoError(t, store.RevokeUser(ctx, "user", time.Hour))
+
+	for _, familyID := range []string{"f1", "f2"} {
+		revoked, err := store.IsRevoked(ctx, familyID)
+		require.NoError(t, err)
+		assert.True(t, revoked, familyID)
+	}
+	revoked, err := store.IsRevoked(ctx, "f3")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	revokedAt, err = store.RevokedBefore(ctx, "user")
+	require.NoError(t, err)
+	assert.False(t, revokedAt.Before(before))
+
+	// Revoking a user without live families still records the revocation
+	require.NoError(t, store.RevokeUser(ctx, "user", time.Hour))
+}
@@ -0,0 +1,97 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	runtimeConfigKey     = "runtime_config"
+	runtimeConfigChannel = "runtime_config:changed"
+)
+
+// RuntimeConfigStore implements pkg/domain.RuntimeConfigStore using a Redis
+// key for the current settings and a pub/sub channel for changes
+type RuntimeConfigStore struct {
+	client *redis.Client
+}
+
+// NewRuntimeConfigStore creates a new Redis runtime config store
+func NewRuntimeConfigStore(client *redis.Client) *RuntimeConfigStore {
+	return &RuntimeConfigStore{client: client}
+}
+
+// Get implements pkg/domain.RuntimeConfigStore
+func (s *RuntimeConfigStore) Get(ctx context.Context) (*pkgdomain.RuntimeSettings, error) {
+	data, err := s.client.Get(ctx, runtimeConfigKey).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get runtime config: %w", err)
+	}
+
+	var settings pkgdomain.RuntimeSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to decode runtime config: %w", err)
+	}
+	return &settings, nil
+}
+
+// Save implements pkg/domain.RuntimeConfigStore
+func (s *RuntimeConfigStore) Save(ctx context.Context, settings *pkgdomain.RuntimeSettings) error {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to encode runtime config: %w", err)
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, runtimeConfigKey, data, 0)
+	pipe.Publish(ctx, runtimeConfigChannel, data)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save runtime config: %w", err)
+	}
+	return nil
+}
+
+// Subscribe implements pkg/domain.RuntimeConfigStore. Messages that cannot
+// be decoded are skipped.
+func (s *RuntimeConfigStore) Subscribe(ctx context.Context) (<-chan *pkgdomain.RuntimeSettings, error) {
+	sub := s.client.Subscribe(ctx, runtimeConfigChannel)
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, fmt.Errorf("failed to subscribe to runtime config: %w", err)
+	}
+
+	updates := make(chan *pkgdomain.RuntimeSettings)
+	go func() {
+		defer close(updates)
+		defer sub.Close()
+
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var settings pkgdomain.RuntimeSettings
+				if err := json.Unmarshal([]byte(msg.Payload), &settings); err != nil {
+					continue
+				}
+				select {
+				case updates <- &settings:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return updates, nil
+}
@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeConfigStore_SaveNotifiesSubscribers(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	store := NewRuntimeConfigStore(client)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	settings, err := store.Get(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, settings)
+
+	updates, err := store.Subscribe(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Save(ctx, &pkgdomain.RuntimeSettings{AIMinConfidence: 0.7, RateLimitPerMinute: 60}))
+
+	select {
+	case got := <-updates:
+		assert.Equal(t, 0.7, got.AIMinConfidence)
+		assert.Equal(t, 60, got.RateLimitPerMinute)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no update received")
+	}
+
+	settings, err = store.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 60, settings.RateLimitPerMinute)
+
+	cancel()
+	_, open := <-updates
+	assert.False(t, open)
+}
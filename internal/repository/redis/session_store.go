@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"metadatatool/internal/domain"
+	"metadatatool/internal/pkg/domain"
 	"sync"
 	"time"
 
@@ -29,18 +29,11 @@ type RedisSessionStore struct {
 
 // NewSessionStore creates a new Redis session store
 func NewSessionStore(client *redis.Client, config domain.SessionConfig) domain.SessionStore {
-	store := &RedisSessionStore{
+	return &RedisSessionStore{
 		client: client,
 		config: config,
 		done:   make(chan struct{}),
 	}
-
-	// Start cleanup goroutine if cleanup interval is set
-	if config.CleanupInterval > 0 {
-		go store.cleanupLoop()
-	}
-
-	return store
 }
 
 // Create stores a new session in Redis
@@ -198,10 +191,10 @@ func (s *RedisSessionStore) DeleteUserSessions(ctx context.Context, userID strin
 	return nil
 }
 
-// DeleteExpired removes all expired sessions
+// DeleteExpired removes the expired sessions from the session sets of their
+// users. Redis drops the sessions themselves when they expire.
 func (s *RedisSessionStore) DeleteExpired(ctx context.Context) error {
-	// This is handled automatically by Redis TTL
-	return nil
+	return deleteExpiredSessions(ctx, s.client)
 }
 
 // Touch updates the last seen time of a session
@@ -215,7 +208,7 @@ func (s *RedisSessionStore) Touch(ctx context.Context, sessionID string) error {
 	return s.Update(ctx, session)
 }
 
-// Close stops the cleanup goroutine
+// Close closes the store
 func (s *RedisSessionStore) Close() error {
 	s.closeOnce.Do(func() {
 		close(s.done)
@@ -223,22 +216,60 @@ func (s *RedisSessionStore) Close() error {
 	return nil
 }
 
-// cleanupLoop periodically runs cleanup of expired sessions
-func (s *RedisSessionStore) cleanupLoop() {
-	ticker := time.NewTicker(s.config.CleanupInterval)
-	defer ticker.Stop()
-
+// deleteExpiredSessions removes the ids of the sessions that expired from
+// the session sets of their users, scanning the sets in batches
+func deleteExpiredSessions(ctx context.Context, client *redis.Client) error {
+	var cursor uint64
 	for {
-		select {
-		case <-ticker.C:
-			if err := s.DeleteExpired(context.Background()); err != nil {
-				// Log error but continue
-				fmt.Printf("Failed to cleanup expired sessions: %v\n", err)
+		keys, next, err := client.Scan(ctx, cursor, userSessionsPrefix+"*", defaultCleanupBatch).Result()
+		if err != nil {
+			return fmt.Errorf("failed to scan user sessions: %w", err)
+		}
+		for _, key := range keys {
+			if err := deleteExpiredUserSessions(ctx, client, key); err != nil {
+				return err
 			}
-		case <-s.done:
-			return
 		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// deleteExpiredUserSessions removes the ids of the sessions that expired from
+// the session set at key
+func deleteExpiredUserSessions(ctx context.Context, client *redis.Client, key string) error {
+	ids, err := client.SMembers(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get user session IDs: %w", err)
 	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	pipe := client.Pipeline()
+	exists := make([]*redis.IntCmd, len(ids))
+	for i, id := range ids {
+		exists[i] = pipe.Exists(ctx, sessionKey(id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to check user sessions: %w", err)
+	}
+
+	var expired []interface{}
+	for i, cmd := range exists {
+		if cmd.Val() == 0 {
+			expired = append(expired, ids[i])
+		}
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+	if err := client.SRem(ctx, key, expired...).Err(); err != nil {
+		return fmt.Errorf("failed to remove expired sessions: %w", err)
+	}
+	return nil
 }
 
 // Helper functions for Redis keys
@@ -2,8 +2,8 @@ package redis
 
 import (
 	"context"
-	"metadatatool/internal/domain"
 	"metadatatool/internal/pkg/config"
+	"metadatatool/internal/pkg/domain"
 	"testing"
 	"time"
 
@@ -326,3 +326,33 @@ func TestRedisSessionStore_Touch(t *testing.T) {
 		assert.Contains(t, err.Error(), "session not found")
 	})
 }
+
+func TestRedisSessionStore_DeleteExpired(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	cfg := config.SessionConfig{
+		CookieName:         "session",
+		CookiePath:         "/",
+		SessionDuration:    24 * time.Hour,
+		MaxSessionsPerUser: 5,
+	}
+
+	store := NewSessionStore(client, configToDomainConfig(cfg))
+	ctx := context.Background()
+
+	expired := createTestSession()
+	live := createTestSession()
+	live.UserID = expired.UserID
+	require.NoError(t, store.Create(ctx, expired))
+	require.NoError(t, store.Create(ctx, live))
+
+	// Redis drops the session when it expires but leaves its id in the set
+	require.NoError(t, client.Del(ctx, sessionKey(expired.ID)).Err())
+
+	require.NoError(t, store.(*RedisSessionStore).DeleteExpired(ctx))
+
+	ids, err := client.SMembers(ctx, userSessionsKey(expired.UserID)).Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{live.ID}, ids)
+}
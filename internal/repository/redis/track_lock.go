@@ -0,0 +1,128 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const trackLockPrefix = "track_lock:"
+
+// trackLockScript locks every key unless one of them is locked. ARGV holds
+// the owner, the token, the times the lock is taken and expires in unix
+// milliseconds, and the TTL in milliseconds.
+var trackLockScript = redis.NewScript(`
+for _, key in ipairs(KEYS) do
+	if redis.call('EXISTS', key) == 1 then
+		return 0
+	end
+end
+for _, key in ipairs(KEYS) do
+	redis.call('HSET', key, 'owner', ARGV[1], 'token', ARGV[2], 'acquired_at', ARGV[3], 'expires_at', ARGV[4])
+	redis.call('PEXPIRE', key, ARGV[5])
+end
+return 1
+`)
+
+// trackUnlockScript releases the keys locked with the token ARGV[1]
+var trackUnlockScript = redis.NewScript(`
+local released = 0
+for _, key in ipairs(KEYS) do
+	if redis.call('HGET', key, 'token') == ARGV[1] then
+		released = released + redis.call('DEL', key)
+	end
+end
+return released
+`)
+
+// TrackLocker implements domain.TrackLocker with a hash per locked track
+// that expires with the lock
+type TrackLocker struct {
+	client *redis.Client
+}
+
+// NewTrackLocker creates a new Redis track locker
+func NewTrackLocker(client *redis.Client) domain.TrackLocker {
+	return &TrackLocker{client: client}
+}
+
+// Lock locks every track for owner for at most ttl
+func (l *TrackLocker) Lock(ctx context.Context, trackIDs []string, owner string, ttl time.Duration) (*domain.TrackLock, error) {
+	now := time.Now()
+	lock := &domain.TrackLock{
+		Owner:      owner,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(ttl),
+		Token:      uuid.New().String(),
+	}
+	if len(trackIDs) == 0 {
+		return lock, nil
+	}
+
+	locked, err := trackLockScript.Run(ctx, l.client, trackLockKeys(trackIDs),
+		lock.Owner, lock.Token, lock.AcquiredAt.UnixMilli(), lock.ExpiresAt.UnixMilli(), ttl.Milliseconds()).Int()
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock tracks: %w", err)
+	}
+	if locked == 0 {
+		return nil, domain.ErrTrackLocked
+	}
+	return lock, nil
+}
+
+// Unlock releases the locks lock holds on the tracks. Locks that expired
+// and were taken by another owner are left alone.
+func (l *TrackLocker) Unlock(ctx context.Context, trackIDs []string, lock *domain.TrackLock) error {
+	if len(trackIDs) == 0 {
+		return nil
+	}
+	if err := trackUnlockScript.Run(ctx, l.client, trackLockKeys(trackIDs), lock.Token).Err(); err != nil {
+		return fmt.Errorf("failed to unlock tracks: %w", err)
+	}
+	return nil
+}
+
+// Locks returns the locks held on the tracks by track ID
+func (l *TrackLocker) Locks(ctx context.Context, trackIDs []string) (map[string]*domain.TrackLock, error) {
+	locks := make(map[string]*domain.TrackLock)
+	if len(trackIDs) == 0 {
+		return locks, nil
+	}
+
+	pipe := l.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(trackIDs))
+	for i, id := range trackIDs {
+		cmds[i] = pipe.HGetAll(ctx, trackLockPrefix+id)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get track locks: %w", err)
+	}
+
+	for i, cmd := range cmds {
+		fields := cmd.Val()
+		if len(fields) == 0 {
+			continue
+		}
+		acquired, _ := strconv.ParseInt(fields["acquired_at"], 10, 64)
+		expires, _ := strconv.ParseInt(fields["expires_at"], 10, 64)
+		locks[trackIDs[i]] = &domain.TrackLock{
+			Owner:      fields["owner"],
+			AcquiredAt: time.UnixMilli(acquired).UTC(),
+			ExpiresAt:  time.UnixMilli(expires).UTC(),
+		}
+	}
+	return locks, nil
+}
+
+func trackLockKeys(trackIDs []string) []string {
+	keys := make([]string, len(trackIDs))
+	for i, id := range trackIDs {
+		keys[i] = trackLockPrefix + id
+	}
+	return keys
+}
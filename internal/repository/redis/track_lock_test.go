@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackLocker_LocksTracksOnce(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+	ctx := context.Background()
+	locker := NewTrackLocker(client)
+
+	lock, err := locker.Lock(ctx, []string{"t1", "t2"}, "ai_batch:b1", time.Minute)
+	require.NoError(t, err)
+
+	_, err = locker.Lock(ctx, []string{"t2", "t3"}, "reenrichment", time.Minute)
+	assert.ErrorIs(t, err, domain.ErrTrackLocked)
+	locks, err := locker.Locks(ctx, []string{"t1", "t2", "t3"})
+	require.NoError(t, err)
+	require.Len(t, locks, 2, "a failed lock takes none of the tracks")
+	assert.Equal(t, "ai_batch:b1", locks["t1"].Owner)
+	assert.WithinDuration(t, lock.ExpiresAt, locks["t1"].ExpiresAt, time.Millisecond)
+
+	require.NoError(t, locker.Unlock(ctx, []string{"t1", "t2"}, lock))
+	locks, err = locker.Locks(ctx, []string{"t1", "t2"})
+	require.NoError(t, err)
+	assert.Empty(t, locks)
+
+	_, err = locker.Lock(ctx, []string{"t2", "t3"}, "reenrichment", time.Minute)
+	assert.NoError(t, err)
+}
+
+func TestTrackLocker_LeavesLocksTakenOver(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+	ctx := context.Background()
+	locker := NewTrackLocker(client)
+
+	stale, err := locker.Lock(ctx, []string{"t1"}, "worker-a", time.Minute)
+	require.NoError(t, err)
+	// The lock expires and another worker takes it
+	require.NoError(t, client.Del(ctx, trackLockPrefix+"t1").Err())
+	_, err = locker.Lock(ctx, []string{"t1"}, "worker-b", time.Minute)
+	require.NoError(t, err)
+
+	require.NoError(t, locker.Unlock(ctx, []string{"t1"}, stale))
+	locks, err := locker.Locks(ctx, []string{"t1"})
+	require.NoError(t, err)
+	require.Contains(t, locks, "t1")
+	assert.Equal(t, "worker-b", locks["t1"].Owner)
+}
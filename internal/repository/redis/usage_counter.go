@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const storageUsageKey = "quota:total"
+
+// addIfSetScript increments a counter that exists and leaves a missing one
+// missing
+var addIfSetScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return nil
+end
+return redis.call('INCRBY', KEYS[1], ARGV[1])
+`)
+
+// UsageCounter implements pkg/domain.UsageCounter with a Redis counter
+type UsageCounter struct {
+	client *redis.Client
+}
+
+// NewUsageCounter creates a new Redis storage usage counter
+func NewUsageCounter(client *redis.Client) *UsageCounter {
+	return &UsageCounter{client: client}
+}
+
+// Add implements pkg/domain.UsageCounter. Changes before the total was
+// first set are dropped, as the measurement that sets it includes them.
+func (u *UsageCounter) Add(ctx context.Context, delta int64) error {
+	if err := addIfSetScript.Run(ctx, u.client, []string{storageUsageKey}, delta).Err(); err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to update storage usage: %w", err)
+	}
+	return nil
+}
+
+// Total implements pkg/domain.UsageCounter
+func (u *UsageCounter) Total(ctx context.Context) (int64, bool, error) {
+	total, err := u.client.Get(ctx, storageUsageKey).Int64()
+	if err == redis.Nil {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get storage usage: %w", err)
+	}
+	return total, true, nil
+}
+
+// Set implements pkg/domain.UsageCounter
+func (u *UsageCounter) Set(ctx context.Context, total int64) error {
+	if err := u.client.Set(ctx, storageUsageKey, total, 0).Err(); err != nil {
+		return fmt.Errorf("failed to set storage usage: %w", err)
+	}
+	return nil
+}
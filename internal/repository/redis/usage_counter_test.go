@@ -0,0 +1,31 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageCounter(t *testing.T) {
+	client, cleanup := setupTestRedis(t)
+	defer cleanup()
+
+	counter := NewUsageCounter(client)
+	ctx := context.Background()
+
+	// Changes before the first measurement are left to it
+	require.NoError(t, counter.Add(ctx, 10))
+	_, ok, err := counter.Total(ctx)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, counter.Set(ctx, 100))
+	require.NoError(t, counter.Add(ctx, 25))
+	require.NoError(t, counter.Add(ctx, -5))
+	total, ok, err := counter.Total(ctx)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(120), total)
+}
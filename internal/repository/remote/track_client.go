@@ -0,0 +1,168 @@
+// Package remote accesses the catalog of another deployment through its REST
+// API, for example to compare staging with production.
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// pageSize is the largest page the tracks endpoint serves
+const pageSize = 100
+
+// APIError is returned when the remote API answers with an error status
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("remote API returned %d: %s", e.StatusCode, e.Message)
+}
+
+// TrackClient reads and writes tracks through the /api/v1/tracks endpoints
+type TrackClient struct {
+	baseURL    string
+	token      string
+	sessionID  string
+	httpClient *http.Client
+}
+
+// NewTrackClient creates a client for the deployment at baseURL. token is
+// sent as a bearer token and sessionID as the session cookie; either may be
+// empty when the remote API does not require it.
+func NewTrackClient(baseURL, token, sessionID string) *TrackClient {
+	return &TrackClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		sessionID:  sessionID,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// BaseURL returns the address of the remote deployment
+func (c *TrackClient) BaseURL() string {
+	return c.baseURL
+}
+
+// ListAll returns every track of the remote catalog
+func (c *TrackClient) ListAll(ctx context.Context) ([]*domain.Track, error) {
+	var all []*domain.Track
+	for page := 1; ; page++ {
+		var resp struct {
+			Tracks []*domain.Track `json:"tracks"`
+		}
+		path := "/api/v1/tracks?page=" + strconv.Itoa(page) + "&limit=" + strconv.Itoa(pageSize)
+		if err := c.do(ctx, http.MethodGet, path, nil, nil, &resp); err != nil {
+			return nil, fmt.Errorf("failed to list tracks: %w", err)
+		}
+		all = append(all, resp.Tracks...)
+		if len(resp.Tracks) < pageSize {
+			return all, nil
+		}
+	}
+}
+
+// Create creates a track; the remote API assigns its ID
+func (c *TrackClient) Create(ctx context.Context, track *domain.Track) (*domain.Track, error) {
+	var created domain.Track
+	if err := c.do(ctx, http.MethodPost, "/api/v1/tracks", track, nil, &created); err != nil {
+		return nil, fmt.Errorf("failed to create track: %w", err)
+	}
+	return &created, nil
+}
+
+// Update updates a track. track.Version must hold the remote version the
+// change is based on; it is sent as If-Match.
+func (c *TrackClient) Update(ctx context.Context, track *domain.Track) (*domain.Track, error) {
+	headers := map[string]string{"If-Match": strconv.Quote(strconv.Itoa(track.Version))}
+	var updated domain.Track
+	if err := c.do(ctx, http.MethodPut, "/api/v1/tracks/"+track.ID, track, headers, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update track %s: %w", track.ID, err)
+	}
+	return &updated, nil
+}
+
+func (c *TrackClient) do(ctx context.Context, method, path string, body interface{}, headers map[string]string, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.sessionID != "" {
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: c.sessionID})
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return &APIError{StatusCode: resp.StatusCode, Message: errorMessage(data)}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// errorMessage extracts the message from an error response, which is either
+// {"error": "..."} or {"error": {"message": "..."}}
+func errorMessage(body []byte) string {
+	var resp struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err == nil && len(resp.Error) > 0 {
+		var message string
+		if json.Unmarshal(resp.Error, &message) == nil {
+			return message
+		}
+		var detailed struct {
+			Message string `json:"message"`
+			Details string `json:"details"`
+		}
+		if json.Unmarshal(resp.Error, &detailed) == nil && detailed.Message != "" {
+			if detailed.Details != "" {
+				return detailed.Message + ": " + detailed.Details
+			}
+			return detailed.Message
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,57 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackClient_ListAllFollowsPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		cookie, err := r.Cookie("session_id")
+		require.NoError(t, err)
+		assert.Equal(t, "sess", cookie.Value)
+
+		count := pageSize
+		if r.URL.Query().Get("page") == "2" {
+			count = 3
+		}
+		tracks := make([]*domain.Track, count)
+		for i := range tracks {
+			tracks[i] = &domain.Track{ID: fmt.Sprintf("%s-%d", r.URL.Query().Get("page"), i)}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"tracks": tracks})
+	}))
+	defer server.Close()
+
+	tracks, err := NewTrackClient(server.URL+"/", "secret", "sess").ListAll(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, tracks, pageSize+3)
+}
+
+func TestTrackClient_UpdateSendsVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/v1/tracks/t1", r.URL.Path)
+		assert.Equal(t, `"4"`, r.Header.Get("If-Match"))
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"error":{"type":"CONFLICT","message":"track was modified"}}`))
+	}))
+	defer server.Close()
+
+	_, err := NewTrackClient(server.URL, "", "").Update(context.Background(), &domain.Track{ID: "t1", Version: 4})
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	assert.Equal(t, "track was modified", apiErr.Message)
+}
@@ -0,0 +1,66 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// HTTPScanner sends files to a cloud scanning service. The file is posted as
+// the request body and the service answers with {"clean": bool, "threat":
+// "name"}.
+type HTTPScanner struct {
+	url        string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewHTTPScanner creates a scanner posting files to url. A non-empty apiKey
+// is sent as a bearer token.
+func NewHTTPScanner(url, apiKey string, timeout time.Duration) *HTTPScanner {
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	return &HTTPScanner{
+		url:        url,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+type httpScanVerdict struct {
+	Clean  bool   `json:"clean"`
+	Threat string `json:"threat"`
+}
+
+// Scan posts content to the service and returns its verdict
+func (s *HTTPScanner) Scan(ctx context.Context, content io.Reader) (*domain.ScanResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scan request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach scanner: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("scanner returned status %d", resp.StatusCode)
+	}
+
+	var verdict httpScanVerdict
+	if err := json.NewDecoder(resp.Body).Decode(&verdict); err != nil {
+		return nil, fmt.Errorf("failed to decode scan verdict: %w", err)
+	}
+	return &domain.ScanResult{Clean: verdict.Clean, Threat: verdict.Threat}, nil
+}
@@ -0,0 +1,350 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"metadatatool/internal/pkg/config"
+	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// metaSuffix marks the sidecar file holding a stored file's metadata
+const metaSuffix = ".meta.json"
+
+// localMeta is the sidecar content stored next to every file
+type localMeta struct {
+	Name        string            `json:"name"`
+	ContentType string            `json:"content_type"`
+	UploadedAt  time.Time         `json:"uploaded_at"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// localStorage implements StorageService on the local filesystem. It is
+// meant for development where no object store is available; URLs point at
+// baseURL, which the API serves from the same directory.
+type localStorage struct {
+	root    string
+	baseURL string
+	cfg     *config.StorageConfig
+}
+
+// NewLocalStorage creates a storage service that keeps files below root
+func NewLocalStorage(root, baseURL string, cfg *config.StorageConfig) (pkgdomain.StorageService, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &localStorage{
+		root:    root,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		cfg:     cfg,
+	}, nil
+}
+
+// path resolves a key below the root and rejects keys that escape it
+func (s *localStorage) path(key string) (string, error) {
+	clean := filepath.Clean("/" + filepath.FromSlash(key))
+	if clean == string(filepath.Separator) || strings.HasSuffix(clean, metaSuffix) {
+		return "", &pkgdomain.StorageError{Code: "InvalidKey", Message: fmt.Sprintf("invalid storage key %q", key)}
+	}
+	return filepath.Join(s.root, clean), nil
+}
+
+func (s *localStorage) write(key string, content io.Reader, meta localMeta) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see partial content
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create file: %w", err)
+	}
+	size, err := io.Copy(tmp, content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return 0, fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return 0, fmt.Errorf("failed to store file: %w", err)
+	}
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal file metadata: %w", err)
+	}
+	if err := os.WriteFile(path+metaSuffix, data, 0o644); err != nil {
+		return 0, fmt.Errorf("failed to write file metadata: %w", err)
+	}
+	return size, nil
+}
+
+func (s *localStorage) readMeta(path string) localMeta {
+	var meta localMeta
+	if data, err := os.ReadFile(path + metaSuffix); err == nil {
+		_ = json.Unmarshal(data, &meta)
+	}
+	return meta
+}
+
+func (s *localStorage) remove(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	if err := os.Remove(path + metaSuffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete file metadata: %w", err)
+	}
+	return nil
+}
+
+// Upload stores a file
+func (s *localStorage) Upload(ctx context.Context, file *pkgdomain.StorageFile) error {
+	timer := metrics.NewTimer(metrics.StorageOperationDuration.WithLabelValues("upload"))
+	defer timer.ObserveDuration()
+
+	if file.Key == "" {
+		file.Key = generateKey(pkgdomain.StoragePathPerm, file.Name)
+	}
+	if err := s.ValidateUpload(ctx, file.Size, file.ContentType); err != nil {
+		metrics.StorageOperationErrors.WithLabelValues("upload").Inc()
+		return err
+	}
+
+	now := time.Now()
+	if _, err := s.write(file.Key, file.Content, localMeta{
+		Name:        file.Name,
+		ContentType: file.ContentType,
+		UploadedAt:  now,
+		Metadata:    file.Metadata,
+	}); err != nil {
+		metrics.StorageOperationErrors.WithLabelValues("upload").Inc()
+		return err
+	}
+	file.UploadedAt = now
+
+	metrics.StorageOperationSuccess.WithLabelValues("upload").Inc()
+	return nil
+}
+
+// Download opens a stored file. The caller closes the content when it is an
+// io.Closer.
+func (s *localStorage) Download(ctx context.Context, key string) (*pkgdomain.StorageFile, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		metrics.StorageOperationErrors.WithLabelValues("download").Inc()
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	meta := s.readMeta(path)
+	name := meta.Name
+	if name == "" {
+		name = filepath.Base(key)
+	}
+
+	metrics.StorageOperationSuccess.WithLabelValues("download").Inc()
+	return &pkgdomain.StorageFile{
+		Key:         key,
+		Name:        name,
+		Size:        info.Size(),
+		ContentType: meta.ContentType,
+		Content:     f,
+		Metadata:    meta.Metadata,
+		UploadedAt:  meta.UploadedAt,
+	}, nil
+}
+
+// Delete removes a file
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	if err := s.remove(key); err != nil {
+		metrics.StorageOperationErrors.WithLabelValues("delete").Inc()
+		return err
+	}
+	metrics.StorageOperationSuccess.WithLabelValues("delete").Inc()
+	return nil
+}
+
+// GetURL returns the URL the file is served from
+func (s *localStorage) GetURL(ctx context.Context, key string) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	return s.baseURL + "/" + (&url.URL{Path: strings.TrimLeft(key, "/")}).EscapedPath(), nil
+}
+
+// GetMetadata returns metadata for a file
+func (s *localStorage) GetMetadata(ctx context.Context, key string) (*pkgdomain.FileMetadata, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	return s.fileMetadata(key, path, info), nil
+}
+
+func (s *localStorage) fileMetadata(key, path string, info fs.FileInfo) *pkgdomain.FileMetadata {
+	meta := s.readMeta(path)
+	name := meta.Name
+	if name == "" {
+		name = info.Name()
+	}
+	return &pkgdomain.FileMetadata{
+		Key:          key,
+		Name:         name,
+		Size:         info.Size(),
+		ContentType:  meta.ContentType,
+		UploadedAt:   meta.UploadedAt,
+		LastModified: info.ModTime(),
+		StorageClass: "LOCAL",
+		Metadata:     meta.Metadata,
+	}
+}
+
+// ListFiles lists the files whose key starts with prefix
+func (s *localStorage) ListFiles(ctx context.Context, prefix string) ([]*pkgdomain.FileMetadata, error) {
+	var files []*pkgdomain.FileMetadata
+	err := filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, metaSuffix) || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, s.fileMetadata(key, path, info))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	return files, nil
+}
+
+// UploadAudio stores an audio file at path
+func (s *localStorage) UploadAudio(ctx context.Context, file io.Reader, path string) error {
+	if _, err := s.write(path, file, localMeta{Name: filepath.Base(path), UploadedAt: time.Now()}); err != nil {
+		metrics.StorageOperationErrors.WithLabelValues("upload_audio").Inc()
+		return err
+	}
+	metrics.StorageOperationSuccess.WithLabelValues("upload_audio").Inc()
+	return nil
+}
+
+// DeleteAudio removes an audio file
+func (s *localStorage) DeleteAudio(ctx context.Context, path string) error {
+	if err := s.remove(path); err != nil {
+		metrics.StorageOperationErrors.WithLabelValues("delete_audio").Inc()
+		return err
+	}
+	metrics.StorageOperationSuccess.WithLabelValues("delete_audio").Inc()
+	return nil
+}
+
+// GetSignedURL returns the file URL. Local files are served without
+// signatures, so the expiry is ignored.
+func (s *localStorage) GetSignedURL(ctx context.Context, path string, expiry time.Duration) (string, error) {
+	return s.GetURL(ctx, path)
+}
+
+// GetQuotaUsage returns the total size of all stored files
+func (s *localStorage) GetQuotaUsage(ctx context.Context) (int64, error) {
+	files, err := s.ListFiles(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, file := range files {
+		total += file.Size
+	}
+	return total, nil
+}
+
+// ValidateUpload applies the configured size, type and quota limits. Limits
+// that are not configured are not enforced.
+func (s *localStorage) ValidateUpload(ctx context.Context, fileSize int64, mimeType string) error {
+	if s.cfg == nil {
+		return nil
+	}
+	if s.cfg.MaxFileSize > 0 && fileSize > s.cfg.MaxFileSize {
+		return &pkgdomain.StorageError{
+			Code:    "FILE_TOO_LARGE",
+			Message: fmt.Sprintf("file size %d exceeds maximum allowed size %d", fileSize, s.cfg.MaxFileSize),
+		}
+	}
+	if len(s.cfg.AllowedFileTypes) > 0 {
+		allowed := false
+		for _, allowedType := range s.cfg.AllowedFileTypes {
+			if mimeType == allowedType {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return &pkgdomain.StorageError{
+				Code:    "INVALID_FILE_TYPE",
+				Message: fmt.Sprintf("file type %s is not allowed", mimeType),
+			}
+		}
+	}
+	if s.cfg.TotalQuota > 0 {
+		usage, err := s.GetQuotaUsage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check quota: %w", err)
+		}
+		if usage+fileSize > s.cfg.TotalQuota {
+			return &pkgdomain.StorageError{
+				Code:    "QUOTA_EXCEEDED",
+				Message: "total storage quota exceeded",
+			}
+		}
+	}
+	return nil
+}
+
+// Ping checks that the storage directory is available
+func (s *localStorage) Ping(ctx context.Context) error {
+	_, err := os.Stat(s.root)
+	return err
+}
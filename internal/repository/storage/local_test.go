@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"metadatatool/internal/pkg/config"
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := NewLocalStorage(root, "http://localhost:8080/files/", &config.StorageConfig{})
+	require.NoError(t, err)
+
+	file := &pkgdomain.StorageFile{
+		Key:         "audio/track.mp3",
+		Name:        "track.mp3",
+		Size:        5,
+		ContentType: "audio/mpeg",
+		Content:     strings.NewReader("audio"),
+		Metadata:    map[string]string{"track_id": "1"},
+	}
+	require.NoError(t, store.Upload(ctx, file))
+
+	downloaded, err := store.Download(ctx, "audio/track.mp3")
+	require.NoError(t, err)
+	content, err := io.ReadAll(downloaded.Content)
+	require.NoError(t, err)
+	downloaded.Content.(io.Closer).Close()
+	assert.Equal(t, "audio", string(content))
+	assert.Equal(t, "audio/mpeg", downloaded.ContentType)
+	assert.Equal(t, "1", downloaded.Metadata["track_id"])
+
+	files, err := store.ListFiles(ctx, "audio/")
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "audio/track.mp3", files[0].Key)
+
+	url, err := store.GetURL(ctx, "audio/track.mp3")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/files/audio/track.mp3", url)
+
+	usage, err := store.GetQuotaUsage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), usage)
+
+	require.NoError(t, store.Delete(ctx, "audio/track.mp3"))
+	_, err = os.Stat(filepath.Join(root, "audio", "track.mp3"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLocalStorage_KeysStayInsideRoot(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := NewLocalStorage(filepath.Join(root, "store"), "", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, store.UploadAudio(ctx, strings.NewReader("x"), "../../escape.mp3"))
+	_, err = os.Stat(filepath.Join(root, "store", "escape.mp3"))
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(root, "escape.mp3"))
+	assert.True(t, os.IsNotExist(err))
+
+	assert.Error(t, store.UploadAudio(ctx, strings.NewReader("x"), "track.mp3.meta.json"))
+}
@@ -0,0 +1,363 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/google/uuid"
+)
+
+// failoverPeriod is how long URLs point at the replica after a read from the
+// primary region failed
+const failoverPeriod = 30 * time.Second
+
+// ReplicatedStorage keeps a copy of every file in a second region. Writes go
+// to the primary and are copied to the replica by storage_replicate jobs;
+// reads fall back to the replica when the primary fails. Without a job queue
+// the copies are made in the background of the writing process and are lost
+// on restart.
+type ReplicatedStorage struct {
+	primary pkgdomain.StorageService
+	replica pkgdomain.StorageService
+	jobs    pkgdomain.JobQueue
+
+	mu       sync.Mutex
+	failedAt time.Time
+}
+
+// NewReplicatedStorage creates a storage service that replicates primary to
+// replica through jobs, which may be nil
+func NewReplicatedStorage(primary, replica pkgdomain.StorageService, jobs pkgdomain.JobQueue) *ReplicatedStorage {
+	return &ReplicatedStorage{
+		primary: primary,
+		replica: replica,
+		jobs:    jobs,
+	}
+}
+
+// Upload stores a file in the primary region and queues its replication
+func (r *ReplicatedStorage) Upload(ctx context.Context, file *pkgdomain.StorageFile) error {
+	if err := r.primary.Upload(ctx, file); err != nil {
+		return err
+	}
+	r.enqueue(ctx, pkgdomain.ReplicatePayload{Key: file.Key, QueuedAt: time.Now()})
+	return nil
+}
+
+// UploadAudio stores an audio file in the primary region and queues its
+// replication
+func (r *ReplicatedStorage) UploadAudio(ctx context.Context, file io.Reader, path string) error {
+	if err := r.primary.UploadAudio(ctx, file, path); err != nil {
+		return err
+	}
+	r.enqueue(ctx, pkgdomain.ReplicatePayload{Key: path, QueuedAt: time.Now()})
+	return nil
+}
+
+// Delete removes a file from the primary region and queues its removal from
+// the replica
+func (r *ReplicatedStorage) Delete(ctx context.Context, key string) error {
+	if err := r.primary.Delete(ctx, key); err != nil {
+		return err
+	}
+	r.enqueue(ctx, pkgdomain.ReplicatePayload{Key: key, Deleted: true, QueuedAt: time.Now()})
+	return nil
+}
+
+// DeleteAudio removes an audio file from the primary region and queues its
+// removal from the replica
+func (r *ReplicatedStorage) DeleteAudio(ctx context.Context, path string) error {
+	if err := r.primary.DeleteAudio(ctx, path); err != nil {
+		return err
+	}
+	r.enqueue(ctx, pkgdomain.ReplicatePayload{Key: path, Deleted: true, QueuedAt: time.Now()})
+	return nil
+}
+
+// Replicate queues a copy of a file that was stored in the primary region
+// without going through this service
+func (r *ReplicatedStorage) Replicate(ctx context.Context, key string) error {
+	r.enqueue(ctx, pkgdomain.ReplicatePayload{Key: key, QueuedAt: time.Now()})
+	return nil
+}
+
+// Download reads a file from the primary region, or from the replica when
+// the primary fails
+func (r *ReplicatedStorage) Download(ctx context.Context, key string) (*pkgdomain.StorageFile, error) {
+	file, err := r.primary.Download(ctx, key)
+	if !r.failover(err) {
+		return file, err
+	}
+	metrics.StorageReplicaReads.WithLabelValues("download").Inc()
+	return r.replica.Download(ctx, key)
+}
+
+// GetMetadata reads a file's metadata from the primary region, or from the
+// replica when the primary fails
+func (r *ReplicatedStorage) GetMetadata(ctx context.Context, key string) (*pkgdomain.FileMetadata, error) {
+	meta, err := r.primary.GetMetadata(ctx, key)
+	if !r.failover(err) {
+		return meta, err
+	}
+	metrics.StorageReplicaReads.WithLabelValues("get_metadata").Inc()
+	return r.replica.GetMetadata(ctx, key)
+}
+
+// ListFiles lists files in the primary region, or in the replica when the
+// primary fails
+func (r *ReplicatedStorage) ListFiles(ctx context.Context, prefix string) ([]*pkgdomain.FileMetadata, error) {
+	files, err := r.primary.ListFiles(ctx, prefix)
+	if !r.failover(err) {
+		return files, err
+	}
+	metrics.StorageReplicaReads.WithLabelValues("list_files").Inc()
+	return r.replica.ListFiles(ctx, prefix)
+}
+
+// GetURL returns a URL for a file. Signing a URL does not reach the primary
+// region, so URLs point at the replica for a while after a read from the
+// primary failed.
+func (r *ReplicatedStorage) GetURL(ctx context.Context, key string) (string, error) {
+	if r.primaryFailing() {
+		metrics.StorageReplicaReads.WithLabelValues("get_url").Inc()
+		return r.replica.GetURL(ctx, key)
+	}
+	return r.primary.GetURL(ctx, key)
+}
+
+// GetSignedURL returns a signed URL for a file, pointing at the replica for
+// a while after a read from the primary failed
+func (r *ReplicatedStorage) GetSignedURL(ctx context.Context, path string, expiry time.Duration) (string, error) {
+	if r.primaryFailing() {
+		metrics.StorageReplicaReads.WithLabelValues("get_signed_url").Inc()
+		return r.replica.GetSignedURL(ctx, path, expiry)
+	}
+	return r.primary.GetSignedURL(ctx, path, expiry)
+}
+
+// GetQuotaUsage returns the usage of the primary region
+func (r *ReplicatedStorage) GetQuotaUsage(ctx context.Context) (int64, error) {
+	return r.primary.GetQuotaUsage(ctx)
+}
+
+// ValidateUpload validates an upload against the primary region
+func (r *ReplicatedStorage) ValidateUpload(ctx context.Context, fileSize int64, mimeType string) error {
+	return r.primary.ValidateUpload(ctx, fileSize, mimeType)
+}
+
+// SignUpload presigns a direct upload to the primary region. The upload is
+// replicated once it is confirmed through Replicate.
+func (r *ReplicatedStorage) SignUpload(ctx context.Context, key, contentType string, size int64) (*pkgdomain.SignedUpload, error) {
+	signer, ok := r.primary.(pkgdomain.UploadSigner)
+	if !ok {
+		return nil, errNotSupported("SignUpload")
+	}
+	return signer.SignUpload(ctx, key, contentType, size)
+}
+
+// ApplyLifecycle applies the tiering policy to the primary region
+func (r *ReplicatedStorage) ApplyLifecycle(ctx context.Context, policy pkgdomain.LifecyclePolicy) error {
+	tierer, ok := r.primary.(pkgdomain.StorageTierer)
+	if !ok {
+		return errNotSupported("ApplyLifecycle")
+	}
+	return tierer.ApplyLifecycle(ctx, policy)
+}
+
+// ArchiveStatus returns the archive status of a file in the primary region
+func (r *ReplicatedStorage) ArchiveStatus(ctx context.Context, key string) (*pkgdomain.ArchiveStatus, error) {
+	tierer, ok := r.primary.(pkgdomain.StorageTierer)
+	if !ok {
+		return nil, errNotSupported("ArchiveStatus")
+	}
+	return tierer.ArchiveStatus(ctx, key)
+}
+
+// Restore restores an archived file in the primary region
+func (r *ReplicatedStorage) Restore(ctx context.Context, key string, days int) error {
+	tierer, ok := r.primary.(pkgdomain.StorageTierer)
+	if !ok {
+		return errNotSupported("Restore")
+	}
+	return tierer.Restore(ctx, key, days)
+}
+
+// EnsureBuckets creates the missing buckets of both regions
+func (r *ReplicatedStorage) EnsureBuckets(ctx context.Context) (map[string]bool, error) {
+	created := make(map[string]bool)
+	for _, store := range []pkgdomain.StorageService{r.primary, r.replica} {
+		provisioner, ok := store.(pkgdomain.BucketProvisioner)
+		if !ok {
+			continue
+		}
+		buckets, err := provisioner.EnsureBuckets(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for name, ok := range buckets {
+			created[name] = ok
+		}
+	}
+	return created, nil
+}
+
+// TrackUsage counts the usage of the primary region in counter
+func (r *ReplicatedStorage) TrackUsage(counter pkgdomain.UsageCounter) {
+	if tracker, ok := r.primary.(pkgdomain.UsageTracker); ok {
+		tracker.TrackUsage(counter)
+	}
+}
+
+// AddUsage counts bytes stored in the primary region without going through
+// the service
+func (r *ReplicatedStorage) AddUsage(ctx context.Context, delta int64) {
+	if tracker, ok := r.primary.(pkgdomain.UsageTracker); ok {
+		tracker.AddUsage(ctx, delta)
+	}
+}
+
+// ReconcileUsage measures the usage of the primary region
+func (r *ReplicatedStorage) ReconcileUsage(ctx context.Context) (int64, error) {
+	tracker, ok := r.primary.(pkgdomain.UsageTracker)
+	if !ok {
+		return 0, errNotSupported("ReconcileUsage")
+	}
+	return tracker.ReconcileUsage(ctx)
+}
+
+// CleanupTempFiles removes the expired temporary files of the primary
+// region, where uploads are staged
+func (r *ReplicatedStorage) CleanupTempFiles(ctx context.Context) error {
+	cleaner, ok := r.primary.(pkgdomain.TempFileCleaner)
+	if !ok {
+		return errNotSupported("CleanupTempFiles")
+	}
+	return cleaner.CleanupTempFiles(ctx)
+}
+
+// Ping checks that the primary region is reachable
+func (r *ReplicatedStorage) Ping(ctx context.Context) error {
+	if pinger, ok := r.primary.(interface{ Ping(context.Context) error }); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// JobType returns the type of job this handler processes
+func (r *ReplicatedStorage) JobType() pkgdomain.JobType {
+	return pkgdomain.JobTypeReplicate
+}
+
+// HandleJob copies a file from the primary region to the replica, or removes
+// it from the replica
+func (r *ReplicatedStorage) HandleJob(ctx context.Context, job *pkgdomain.Job) error {
+	var payload pkgdomain.ReplicatePayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid replicate payload: %w", err)
+	}
+	return r.replicate(ctx, payload)
+}
+
+// replicate applies one write of the primary region to the replica and
+// records the replication lag
+func (r *ReplicatedStorage) replicate(ctx context.Context, payload pkgdomain.ReplicatePayload) error {
+	if payload.Deleted {
+		if err := r.replica.Delete(ctx, payload.Key); err != nil && !isNotFound(err) {
+			metrics.StorageReplicationErrors.Inc()
+			return fmt.Errorf("failed to delete replica of %s: %w", payload.Key, err)
+		}
+	} else {
+		file, err := r.primary.Download(ctx, payload.Key)
+		if isNotFound(err) {
+			// Deleted since; the deletion is replicated by its own job
+			return nil
+		}
+		if err != nil {
+			metrics.StorageReplicationErrors.Inc()
+			return fmt.Errorf("failed to read %s for replication: %w", payload.Key, err)
+		}
+		if closer, ok := file.Content.(io.Closer); ok {
+			defer closer.Close()
+		}
+		if err := r.replica.Upload(ctx, file); err != nil {
+			metrics.StorageReplicationErrors.Inc()
+			return fmt.Errorf("failed to replicate %s: %w", payload.Key, err)
+		}
+	}
+
+	lag := time.Since(payload.QueuedAt).Seconds()
+	metrics.StorageReplicationLag.Observe(lag)
+	metrics.StorageReplicationLastLag.Set(lag)
+	return nil
+}
+
+// enqueue queues a replication job, or replicates in the background when
+// there is no queue or it cannot be reached
+func (r *ReplicatedStorage) enqueue(ctx context.Context, payload pkgdomain.ReplicatePayload) {
+	if r.jobs != nil {
+		data, err := json.Marshal(payload)
+		if err == nil {
+			err = r.jobs.Enqueue(ctx, &pkgdomain.Job{
+				ID:         uuid.New().String(),
+				Type:       pkgdomain.JobTypeReplicate,
+				Priority:   pkgdomain.JobPriorityNormal,
+				Status:     pkgdomain.JobStatusPending,
+				Payload:    data,
+				MaxRetries: 5,
+				CreatedAt:  time.Now(),
+			})
+		}
+		if err == nil {
+			return
+		}
+		log.Printf("Failed to queue replication of %s, replicating in the background: %v", payload.Key, err)
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if err := r.replicate(ctx, payload); err != nil {
+			log.Printf("Error replicating %s: %v", payload.Key, err)
+		}
+	}()
+}
+
+// failover reports whether a read that returned err should be retried on
+// the replica. Missing files are not: the replica may still hold a file the
+// primary has already deleted.
+func (r *ReplicatedStorage) failover(err error) bool {
+	if err == nil || isNotFound(err) {
+		return false
+	}
+	r.mu.Lock()
+	r.failedAt = time.Now()
+	r.mu.Unlock()
+	return true
+}
+
+// primaryFailing reports whether a read from the primary failed recently
+func (r *ReplicatedStorage) primaryFailing() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return time.Since(r.failedAt) < failoverPeriod
+}
+
+// isNotFound reports whether err says a file does not exist
+func isNotFound(err error) bool {
+	var noSuchKey *types.NoSuchKey
+	var notFound *types.NotFound
+	return errors.As(err, &noSuchKey) || errors.As(err, &notFound) || errors.Is(err, fs.ErrNotExist)
+}
+
+func errNotSupported(op string) error {
+	return &pkgdomain.StorageError{Code: "NotSupported", Message: "operation is not supported by the primary storage", Op: op}
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"metadatatool/internal/pkg/config"
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingJobQueue keeps the jobs enqueued to it
+type recordingJobQueue struct {
+	pkgdomain.JobQueue
+	jobs []*pkgdomain.Job
+}
+
+func (q *recordingJobQueue) Enqueue(_ context.Context, job *pkgdomain.Job) error {
+	q.jobs = append(q.jobs, job)
+	return nil
+}
+
+// unreachableStorage fails every read, like a storage in a region that is
+// down
+type unreachableStorage struct {
+	pkgdomain.StorageService
+}
+
+func (unreachableStorage) Download(context.Context, string) (*pkgdomain.StorageFile, error) {
+	return nil, errors.New("connection refused")
+}
+
+func newTestLocalStorage(t *testing.T, baseURL string) pkgdomain.StorageService {
+	store, err := NewLocalStorage(t.TempDir(), baseURL, &config.StorageConfig{})
+	require.NoError(t, err)
+	return store
+}
+
+func readContent(t *testing.T, file *pkgdomain.StorageFile) string {
+	defer file.Content.(io.Closer).Close()
+	content, err := io.ReadAll(file.Content)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestReplicatedStorage_ReplicatesThroughJobs(t *testing.T) {
+	ctx := context.Background()
+	primary, replica := newTestLocalStorage(t, "http://primary"), newTestLocalStorage(t, "http://replica")
+	queue := &recordingJobQueue{}
+	store := NewReplicatedStorage(primary, replica, queue)
+
+	require.NoError(t, store.Upload(ctx, &pkgdomain.StorageFile{
+		Key:         "tracks/t1/audio.mp3",
+		Name:        "audio.mp3",
+		ContentType: "audio/mpeg",
+		Content:     strings.NewReader("audio"),
+	}))
+	require.Len(t, queue.jobs, 1)
+	assert.Equal(t, pkgdomain.JobTypeReplicate, queue.jobs[0].Type)
+
+	_, err := replica.GetMetadata(ctx, "tracks/t1/audio.mp3")
+	require.Error(t, err)
+	require.NoError(t, store.HandleJob(ctx, queue.jobs[0]))
+	file, err := replica.Download(ctx, "tracks/t1/audio.mp3")
+	require.NoError(t, err)
+	assert.Equal(t, "audio", readContent(t, file))
+	assert.Equal(t, "audio/mpeg", file.ContentType)
+
+	require.NoError(t, store.Delete(ctx, "tracks/t1/audio.mp3"))
+	require.Len(t, queue.jobs, 2)
+	require.NoError(t, store.HandleJob(ctx, queue.jobs[1]))
+	_, err = replica.GetMetadata(ctx, "tracks/t1/audio.mp3")
+	assert.Error(t, err)
+
+	// Copying a file deleted in the meantime is not an error
+	assert.NoError(t, store.HandleJob(ctx, queue.jobs[0]))
+}
+
+func TestReplicatedStorage_ReadsFromReplicaWhenPrimaryFails(t *testing.T) {
+	ctx := context.Background()
+	replica := newTestLocalStorage(t, "http://replica")
+	require.NoError(t, replica.Upload(ctx, &pkgdomain.StorageFile{
+		Key:     "tracks/t1/audio.mp3",
+		Content: strings.NewReader("replica"),
+	}))
+	store := NewReplicatedStorage(unreachableStorage{newTestLocalStorage(t, "http://primary")}, replica, &recordingJobQueue{})
+
+	url, err := store.GetURL(ctx, "tracks/t1/audio.mp3")
+	require.NoError(t, err)
+	assert.Equal(t, "http://primary/tracks/t1/audio.mp3", url)
+
+	file, err := store.Download(ctx, "tracks/t1/audio.mp3")
+	require.NoError(t, err)
+	assert.Equal(t, "replica", readContent(t, file))
+
+	// URLs point at the replica once a read from the primary failed
+	url, err = store.GetURL(ctx, "tracks/t1/audio.mp3")
+	require.NoError(t, err)
+	assert.Equal(t, "http://replica/tracks/t1/audio.mp3", url)
+}
+
+func TestReplicatedStorage_MissingFilesDoNotFailOver(t *testing.T) {
+	ctx := context.Background()
+	replica := newTestLocalStorage(t, "http://replica")
+	require.NoError(t, replica.Upload(ctx, &pkgdomain.StorageFile{
+		Key:     "tracks/t1/audio.mp3",
+		Content: strings.NewReader("stale"),
+	}))
+	store := NewReplicatedStorage(newTestLocalStorage(t, "http://primary"), replica, &recordingJobQueue{})
+
+	_, err := store.Download(ctx, "tracks/t1/audio.mp3")
+	assert.Error(t, err)
+	assert.False(t, store.primaryFailing())
+}
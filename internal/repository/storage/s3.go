@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"metadatatool/internal/pkg/config"
 	"metadatatool/internal/pkg/domain"
 	pkgdomain "metadatatool/internal/pkg/domain"
@@ -24,6 +25,8 @@ type s3Storage struct {
 	bucket  string
 	cfg     *config.StorageConfig
 	quotaMu sync.RWMutex
+	// usage counts the bytes in the bucket when set; see TrackUsage
+	usage domain.UsageCounter
 }
 
 // NewS3Storage creates a new S3 storage service
@@ -90,19 +93,21 @@ func (s *s3Storage) Upload(ctx context.Context, file *domain.StorageFile) error
 	}
 
 	// Upload file
-	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+	result, err := s.client.PutObject(ctx, s.encryptPut(&s3.PutObjectInput{
 		Bucket:      aws.String(s.bucket),
 		Key:         aws.String(file.Key),
 		Body:        file.Content,
 		ContentType: aws.String(file.ContentType),
 		Metadata:    awsMetadata,
-	})
+	}))
 
 	if err != nil {
 		metrics.AudioOpErrors.WithLabelValues("s3_upload", "s3_error").Inc()
 		return fmt.Errorf("failed to upload to S3: %w", err)
 	}
+	file.EncryptionKeyID = aws.ToString(result.SSEKMSKeyId)
 
+	s.AddUsage(ctx, file.Size)
 	metrics.AudioOps.WithLabelValues("s3_upload", "completed").Inc()
 	return nil
 }
@@ -137,12 +142,13 @@ func (s *s3Storage) Download(ctx context.Context, key string) (*domain.StorageFi
 	}
 
 	file := &domain.StorageFile{
-		Key:         key,
-		Name:        filepath.Base(key),
-		Size:        size,
-		ContentType: aws.ToString(result.ContentType),
-		Content:     result.Body,
-		Metadata:    metadata,
+		Key:             key,
+		Name:            filepath.Base(key),
+		Size:            size,
+		ContentType:     aws.ToString(result.ContentType),
+		Content:         result.Body,
+		Metadata:        metadata,
+		EncryptionKeyID: aws.ToString(result.SSEKMSKeyId),
 	}
 
 	metrics.AudioOps.WithLabelValues("s3_download", "completed").Inc()
@@ -155,6 +161,7 @@ func (s *s3Storage) Delete(ctx context.Context, key string) error {
 	defer timer.ObserveDuration()
 
 	// Delete file
+	size := s.objectSize(ctx, key)
 	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
@@ -164,6 +171,7 @@ func (s *s3Storage) Delete(ctx context.Context, key string) error {
 		metrics.StorageOperationErrors.WithLabelValues("delete").Inc()
 		return fmt.Errorf("failed to delete file: %w", err)
 	}
+	s.AddUsage(ctx, -size)
 
 	metrics.StorageOperationSuccess.WithLabelValues("delete").Inc()
 	return nil
@@ -213,11 +221,12 @@ func (s *s3Storage) GetMetadata(ctx context.Context, key string) (*domain.FileMe
 	}
 
 	metadata := &domain.FileMetadata{
-		Key:          key,
-		Size:         aws.ToInt64(result.ContentLength),
-		ContentType:  aws.ToString(result.ContentType),
-		LastModified: *result.LastModified,
-		ETag:         aws.ToString(result.ETag),
+		Key:             key,
+		Size:            aws.ToInt64(result.ContentLength),
+		ContentType:     aws.ToString(result.ContentType),
+		LastModified:    *result.LastModified,
+		ETag:            aws.ToString(result.ETag),
+		EncryptionKeyID: aws.ToString(result.SSEKMSKeyId),
 	}
 
 	metrics.StorageOperationSuccess.WithLabelValues("get_metadata").Inc()
@@ -258,31 +267,22 @@ func (s *s3Storage) ListFiles(ctx context.Context, prefix string) ([]*domain.Fil
 	return files, nil
 }
 
-// GetQuotaUsage gets the total storage usage
+// GetQuotaUsage gets the total storage usage. With a usage counter the
+// counted total is returned; the bucket is only listed to start the count.
 func (s *s3Storage) GetQuotaUsage(ctx context.Context) (int64, error) {
-	timer := metrics.NewTimer(metrics.StorageOperationDuration.WithLabelValues("get_quota"))
-	defer timer.ObserveDuration()
-
-	var totalSize int64
-
-	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
-		Bucket: aws.String(s.bucket),
-	})
-
-	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(ctx)
-		if err != nil {
-			metrics.StorageOperationErrors.WithLabelValues("get_quota").Inc()
-			return 0, fmt.Errorf("failed to get quota usage: %w", err)
-		}
-
-		for _, obj := range page.Contents {
-			totalSize += aws.ToInt64(obj.Size)
-		}
+	if s.usage == nil {
+		return s.scanUsage(ctx)
 	}
 
-	metrics.StorageOperationSuccess.WithLabelValues("get_quota").Inc()
-	return totalSize, nil
+	total, ok, err := s.usage.Total(ctx)
+	if err != nil {
+		log.Printf("Error reading storage usage, listing the bucket: %v", err)
+		return s.scanUsage(ctx)
+	}
+	if !ok {
+		return s.ReconcileUsage(ctx)
+	}
+	return total, nil
 }
 
 // GetUserQuotaUsage gets the total storage usage for a specific user
@@ -409,6 +409,7 @@ func (s *s3Storage) CleanupTempFiles(ctx context.Context) error {
 		}
 	}
 
+	s.AddUsage(ctx, -totalSize)
 	metrics.StorageCleanupFilesDeleted.Add(float64(deletedCount))
 	metrics.StorageCleanupBytesReclaimed.Add(float64(totalSize))
 	metrics.StorageOperationSuccess.WithLabelValues("cleanup").Inc()
@@ -438,6 +439,7 @@ func (s *s3Storage) DeleteAudio(ctx context.Context, path string) error {
 	defer timer.ObserveDuration()
 
 	// Delete file
+	size := s.objectSize(ctx, path)
 	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(path),
@@ -447,6 +449,7 @@ func (s *s3Storage) DeleteAudio(ctx context.Context, path string) error {
 		metrics.StorageOperationErrors.WithLabelValues("delete_audio").Inc()
 		return fmt.Errorf("failed to delete audio file: %w", err)
 	}
+	s.AddUsage(ctx, -size)
 
 	metrics.StorageOperationSuccess.WithLabelValues("delete_audio").Inc()
 	return nil
@@ -477,23 +480,80 @@ func (s *s3Storage) GetSignedURL(ctx context.Context, path string, expiry time.D
 	return request.URL, nil
 }
 
+// SignUpload presigns a PUT of the file to key. The content type and length
+// are part of the signature, so the client cannot store anything else.
+func (s *s3Storage) SignUpload(ctx context.Context, key, contentType string, size int64) (*domain.SignedUpload, error) {
+	timer := metrics.NewTimer(metrics.StorageOperationDuration.WithLabelValues("sign_upload"))
+	defer timer.ObserveDuration()
+
+	if size <= 0 {
+		return nil, &domain.StorageError{Code: "INVALID_FILE_SIZE", Message: "file size must be positive"}
+	}
+	if size > s.cfg.MaxFileSize {
+		return nil, &domain.StorageError{
+			Code:    "FILE_TOO_LARGE",
+			Message: fmt.Sprintf("file size %d exceeds maximum allowed size %d", size, s.cfg.MaxFileSize),
+		}
+	}
+
+	expiry := s.cfg.UploadURLExpiry
+	if expiry <= 0 {
+		expiry = 15 * time.Minute
+	}
+	presigner := s3.NewPresignClient(s.client)
+	request, err := presigner.PresignPutObject(ctx, s.encryptPut(&s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	}), func(opts *s3.PresignOptions) {
+		opts.Expires = expiry
+	})
+	if err != nil {
+		metrics.StorageOperationErrors.WithLabelValues("sign_upload").Inc()
+		return nil, fmt.Errorf("failed to generate upload URL: %w", err)
+	}
+
+	headers := map[string]string{"Content-Type": contentType}
+	for name, value := range s.encryptionHeaders() {
+		headers[name] = value
+	}
+
+	metrics.StorageOperationSuccess.WithLabelValues("sign_upload").Inc()
+	return &domain.SignedUpload{
+		URL:       request.URL,
+		Method:    request.Method,
+		Headers:   headers,
+		ExpiresAt: time.Now().Add(expiry),
+	}, nil
+}
+
 // UploadAudio uploads an audio file to storage
 func (s *s3Storage) UploadAudio(ctx context.Context, file io.Reader, path string) error {
 	timer := metrics.NewTimer(metrics.StorageOperationDuration.WithLabelValues("upload_audio"))
 	defer timer.ObserveDuration()
 
 	// Upload file
-	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+	_, err := s.client.PutObject(ctx, s.encryptPut(&s3.PutObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(path),
 		Body:   file,
-	})
+	}))
 
 	if err != nil {
 		metrics.StorageOperationErrors.WithLabelValues("upload_audio").Inc()
 		return fmt.Errorf("failed to upload audio file: %w", err)
 	}
+	s.AddUsage(ctx, s.objectSize(ctx, path))
 
 	metrics.StorageOperationSuccess.WithLabelValues("upload_audio").Inc()
 	return nil
 }
+
+// Ping checks that the bucket is reachable with the configured credentials
+func (s *s3Storage) Ping(ctx context.Context) error {
+	if _, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(s.bucket)}); err != nil {
+		return fmt.Errorf("failed to reach bucket %s: %w", s.bucket, err)
+	}
+	return nil
+}
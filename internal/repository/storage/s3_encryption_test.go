@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"metadatatool/internal/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3Storage_SignsEncryptedUploads(t *testing.T) {
+	store, err := NewS3Storage(&config.StorageConfig{
+		Region:      "eu-west-1",
+		Bucket:      "masters",
+		AccessKey:   "key",
+		SecretKey:   "secret",
+		MaxFileSize: 1 << 20,
+		KMSKeyID:    "arn:aws:kms:eu-west-1:123456789012:key/masters",
+	})
+	require.NoError(t, err)
+
+	upload, err := store.(*s3Storage).SignUpload(context.Background(), "tracks/t1/audio.mp3", "audio/mpeg", 1024)
+	require.NoError(t, err)
+	assert.Equal(t, "aws:kms", upload.Headers["x-amz-server-side-encryption"])
+	assert.Equal(t, "arn:aws:kms:eu-west-1:123456789012:key/masters", upload.Headers["x-amz-server-side-encryption-aws-kms-key-id"])
+
+	// The key is part of the signature, so the client cannot choose another
+	signed, err := url.Parse(upload.URL)
+	require.NoError(t, err)
+	assert.Contains(t, signed.Query().Get("X-Amz-SignedHeaders"), "x-amz-server-side-encryption-aws-kms-key-id")
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// EnsureBuckets creates the bucket in the configured region if it does not
+// exist
+func (s *s3Storage) EnsureBuckets(ctx context.Context) (map[string]bool, error) {
+	_, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(s.bucket)})
+	if err == nil {
+		return map[string]bool{s.bucket: false}, nil
+	}
+	if !isMissingBucket(err) {
+		return nil, fmt.Errorf("failed to check bucket %s: %w", s.bucket, err)
+	}
+
+	input := &s3.CreateBucketInput{Bucket: aws.String(s.bucket)}
+	// us-east-1 is the default location and refuses to be named as one
+	if s.cfg.Region != "" && s.cfg.Region != "us-east-1" {
+		input.CreateBucketConfiguration = &types.CreateBucketConfiguration{
+			LocationConstraint: types.BucketLocationConstraint(s.cfg.Region),
+		}
+	}
+	var owned *types.BucketAlreadyOwnedByYou
+	if _, err := s.client.CreateBucket(ctx, input); err != nil && !errors.As(err, &owned) {
+		return nil, fmt.Errorf("failed to create bucket %s: %w", s.bucket, err)
+	}
+	return map[string]bool{s.bucket: true}, nil
+}
+
+// isMissingBucket reports whether a HeadBucket error means the bucket does
+// not exist
+func isMissingBucket(err error) bool {
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		return true
+	}
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && (apiErr.ErrorCode() == "NotFound" || apiErr.ErrorCode() == "NoSuchBucket")
+}
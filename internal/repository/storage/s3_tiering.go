@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// lifecycleRuleID identifies the bucket lifecycle rule managed by the
+// service; rules with other IDs are left as they are
+const lifecycleRuleID = "metadatatool-tiering"
+
+// ApplyLifecycle installs the tiering policy as a bucket lifecycle rule, so
+// S3 moves files between storage classes by itself. An empty policy removes
+// the rule.
+func (s *s3Storage) ApplyLifecycle(ctx context.Context, policy domain.LifecyclePolicy) error {
+	timer := metrics.NewTimer(metrics.StorageOperationDuration.WithLabelValues("apply_lifecycle"))
+	defer timer.ObserveDuration()
+
+	var rules []types.LifecycleRule
+	current, err := s.client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(s.bucket),
+	})
+	var apiErr smithy.APIError
+	switch {
+	case err == nil:
+		for _, rule := range current.Rules {
+			if aws.ToString(rule.ID) != lifecycleRuleID {
+				rules = append(rules, rule)
+			}
+		}
+	case errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchLifecycleConfiguration":
+	default:
+		metrics.StorageOperationErrors.WithLabelValues("apply_lifecycle").Inc()
+		return fmt.Errorf("failed to get bucket lifecycle: %w", err)
+	}
+
+	if !policy.IsEmpty() {
+		rule := types.LifecycleRule{
+			ID:     aws.String(lifecycleRuleID),
+			Status: types.ExpirationStatusEnabled,
+			Filter: &types.LifecycleRuleFilter{Prefix: aws.String(policy.Prefix)},
+		}
+		if policy.InfrequentAccessAfterDays > 0 {
+			rule.Transitions = append(rule.Transitions, types.Transition{
+				Days:         aws.Int32(int32(policy.InfrequentAccessAfterDays)),
+				StorageClass: types.TransitionStorageClassStandardIa,
+			})
+		}
+		if policy.ArchiveAfterDays > 0 {
+			rule.Transitions = append(rule.Transitions, types.Transition{
+				Days:         aws.Int32(int32(policy.ArchiveAfterDays)),
+				StorageClass: types.TransitionStorageClassGlacier,
+			})
+		}
+		rules = append(rules, rule)
+	}
+
+	if len(rules) == 0 {
+		_, err = s.client.DeleteBucketLifecycle(ctx, &s3.DeleteBucketLifecycleInput{Bucket: aws.String(s.bucket)})
+	} else {
+		_, err = s.client.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+			Bucket:                 aws.String(s.bucket),
+			LifecycleConfiguration: &types.BucketLifecycleConfiguration{Rules: rules},
+		})
+	}
+	if err != nil {
+		metrics.StorageOperationErrors.WithLabelValues("apply_lifecycle").Inc()
+		return fmt.Errorf("failed to apply bucket lifecycle: %w", err)
+	}
+
+	metrics.StorageOperationSuccess.WithLabelValues("apply_lifecycle").Inc()
+	return nil
+}
+
+// ArchiveStatus reads the storage class and restore state of a file
+func (s *s3Storage) ArchiveStatus(ctx context.Context, key string) (*domain.ArchiveStatus, error) {
+	timer := metrics.NewTimer(metrics.StorageOperationDuration.WithLabelValues("archive_status"))
+	defer timer.ObserveDuration()
+
+	result, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		metrics.StorageOperationErrors.WithLabelValues("archive_status").Inc()
+		return nil, fmt.Errorf("failed to get file status: %w", err)
+	}
+
+	metrics.StorageOperationSuccess.WithLabelValues("archive_status").Inc()
+	return archiveStatus(key, string(result.StorageClass), aws.ToString(result.Restore)), nil
+}
+
+// Restore starts retrieving an archived file with the standard retrieval
+// tier. A restore that is already in progress is not an error.
+func (s *s3Storage) Restore(ctx context.Context, key string, days int) error {
+	timer := metrics.NewTimer(metrics.StorageOperationDuration.WithLabelValues("restore"))
+	defer timer.ObserveDuration()
+
+	_, err := s.client.RestoreObject(ctx, &s3.RestoreObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		RestoreRequest: &types.RestoreRequest{
+			Days:                 aws.Int32(int32(days)),
+			GlacierJobParameters: &types.GlacierJobParameters{Tier: types.TierStandard},
+		},
+	})
+	var apiErr smithy.APIError
+	if err != nil && !(errors.As(err, &apiErr) && apiErr.ErrorCode() == "RestoreAlreadyInProgress") {
+		metrics.StorageOperationErrors.WithLabelValues("restore").Inc()
+		return fmt.Errorf("failed to restore file: %w", err)
+	}
+
+	metrics.StorageOperationSuccess.WithLabelValues("restore").Inc()
+	return nil
+}
+
+// restoreExpiry matches the expiry date of the x-amz-restore header
+var restoreExpiry = regexp.MustCompile(`expiry-date="([^"]+)"`)
+
+// archiveStatus derives the status of a file from its storage class and its
+// x-amz-restore header, such as
+// `ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT"`
+func archiveStatus(key, storageClass, restore string) *domain.ArchiveStatus {
+	status := &domain.ArchiveStatus{Key: key, StorageClass: domain.StorageClass(storageClass)}
+	if storageClass == "" {
+		status.StorageClass = domain.StorageClassStandard
+	}
+
+	switch {
+	case storageClass != string(types.StorageClassGlacier) && storageClass != string(types.StorageClassDeepArchive):
+		status.State = domain.ArchiveStateAvailable
+	case strings.Contains(restore, `ongoing-request="true"`):
+		status.State = domain.ArchiveStateRestoring
+	case strings.Contains(restore, `ongoing-request="false"`):
+		status.State = domain.ArchiveStateRestored
+		if m := restoreExpiry.FindStringSubmatch(restore); m != nil {
+			if until, err := time.Parse(http.TimeFormat, m[1]); err == nil {
+				status.RestoredUntil = &until
+			}
+		}
+	default:
+		status.State = domain.ArchiveStateArchived
+	}
+	return status
+}
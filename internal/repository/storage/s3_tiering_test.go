@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		storageClass string
+		restore      string
+		class        pkgdomain.StorageClass
+		state        pkgdomain.ArchiveState
+	}{
+		{"standard", "", "", pkgdomain.StorageClassStandard, pkgdomain.ArchiveStateAvailable},
+		{"infrequent access", "STANDARD_IA", "", pkgdomain.StorageClassInfrequentAccess, pkgdomain.ArchiveStateAvailable},
+		{"archived", "GLACIER", "", pkgdomain.StorageClassArchive, pkgdomain.ArchiveStateArchived},
+		{"restoring", "GLACIER", `ongoing-request="true"`, pkgdomain.StorageClassArchive, pkgdomain.ArchiveStateRestoring},
+		{"deep archive", "DEEP_ARCHIVE", "", "DEEP_ARCHIVE", pkgdomain.ArchiveStateArchived},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := archiveStatus("tracks/t1/audio.mp3", tt.storageClass, tt.restore)
+			assert.Equal(t, tt.class, status.StorageClass)
+			assert.Equal(t, tt.state, status.State)
+			assert.Nil(t, status.RestoredUntil)
+		})
+	}
+
+	status := archiveStatus("k", "GLACIER", `ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT"`)
+	assert.Equal(t, pkgdomain.ArchiveStateRestored, status.State)
+	require.NotNil(t, status.RestoredUntil)
+	assert.Equal(t, time.Date(2012, 12, 21, 0, 0, 0, 0, time.UTC), *status.RestoredUntil)
+}
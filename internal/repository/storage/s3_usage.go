@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// TrackUsage keeps the bucket usage in counter, so uploads check the total
+// quota without listing the bucket
+func (s *s3Storage) TrackUsage(counter pkgdomain.UsageCounter) {
+	s.usage = counter
+}
+
+// AddUsage counts delta bytes stored or removed without going through the
+// service. Errors are only logged; the next reconciliation corrects them.
+func (s *s3Storage) AddUsage(ctx context.Context, delta int64) {
+	if s.usage == nil || delta == 0 {
+		return
+	}
+	if err := s.usage.Add(ctx, delta); err != nil {
+		log.Printf("Error updating storage usage: %v", err)
+	}
+}
+
+// ReconcileUsage lists the bucket and replaces the counted usage with the
+// measured one
+func (s *s3Storage) ReconcileUsage(ctx context.Context) (int64, error) {
+	total, err := s.scanUsage(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if s.usage != nil {
+		if err := s.usage.Set(ctx, total); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// scanUsage adds up the size of every object in the bucket
+func (s *s3Storage) scanUsage(ctx context.Context) (int64, error) {
+	timer := metrics.NewTimer(metrics.StorageOperationDuration.WithLabelValues("scan_usage"))
+	defer timer.ObserveDuration()
+
+	var totalSize int64
+
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			metrics.StorageOperationErrors.WithLabelValues("scan_usage").Inc()
+			return 0, fmt.Errorf("failed to get quota usage: %w", err)
+		}
+
+		for _, obj := range page.Contents {
+			totalSize += aws.ToInt64(obj.Size)
+		}
+	}
+
+	metrics.StorageOperationSuccess.WithLabelValues("scan_usage").Inc()
+	return totalSize, nil
+}
+
+// objectSize returns the size of an object, or 0 when it cannot be read.
+// It is only looked up when usage is counted.
+func (s *s3Storage) objectSize(ctx context.Context, key string) int64 {
+	if s.usage == nil {
+		return 0
+	}
+	result, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return 0
+	}
+	return aws.ToInt64(result.ContentLength)
+}
@@ -4,11 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"metadatatool/internal/domain"
 	"metadatatool/internal/handler"
 	"metadatatool/internal/handler/middleware"
-	"metadatatool/internal/pkg/converter"
-	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/domain"
 	"metadatatool/internal/repository/base"
 	"metadatatool/internal/test/testutil"
 	"metadatatool/internal/usecase"
@@ -25,31 +23,21 @@ import (
 type TestServer struct {
 	Router       *gin.Engine
 	AuthHandler  *handler.AuthHandler
-	SessionStore pkgdomain.SessionStore
-	AuthService  pkgdomain.AuthService
-	UserRepo     pkgdomain.UserRepository
+	SessionStore domain.SessionStore
+	AuthService  domain.AuthService
+	UserRepo     domain.UserRepository
 	cleanup      func()
 }
 
 // NewTestServer creates a new test server with all necessary dependencies
 func NewTestServer(t *testing.T) *TestServer {
-	// Setup internal repositories
-	internalUserRepo := base.NewInMemoryUserRepository()
-	internalSessionStore := base.NewInMemorySessionRepository()
-	internalAuthService := base.NewInMemoryAuthService()
-
-	// Setup pkg repositories
-	pkgUserRepo := testutil.NewInMemoryPkgUserRepository()
-	pkgSessionStore := testutil.NewInMemoryPkgSessionStore()
-	pkgAuthService := testutil.NewInMemoryPkgAuthService()
-
-	// Create wrappers
-	userRepoWrapper := converter.NewUserRepositoryWrapper(internalUserRepo, pkgUserRepo)
-	sessionStoreWrapper := converter.NewSessionStoreWrapper(internalSessionStore, pkgSessionStore)
-	authServiceWrapper := converter.NewAuthServiceWrapper(internalAuthService, pkgAuthService)
+	// Setup repositories
+	userRepo := base.NewInMemoryUserRepository()
+	sessionStore := base.NewInMemorySessionRepository()
+	authService := base.NewInMemoryAuthService()
 
 	// Setup session config
-	sessionConfig := pkgdomain.SessionConfig{
+	sessionConfig := domain.SessionConfig{
 		SessionDuration:    24 * time.Hour,
 		CleanupInterval:    time.Hour,
 		MaxSessionsPerUser: 5,
@@ -61,11 +49,11 @@ func NewTestServer(t *testing.T) *TestServer {
 	}
 
 	// Initialize usecases
-	authUseCase := usecase.NewAuthUseCase(userRepoWrapper.Internal(), sessionStoreWrapper.Internal(), authServiceWrapper.Internal())
-	userUseCase := usecase.NewUserUseCase(userRepoWrapper.Pkg())
+	authUseCase := usecase.NewAuthUseCase(userRepo, sessionStore, authService)
+	userUseCase := usecase.NewUserUseCase(userRepo)
 
 	// Initialize handlers
-	authHandler := handler.NewAuthHandler(authUseCase, userUseCase, sessionStoreWrapper.Internal())
+	authHandler := handler.NewAuthHandler(authUseCase, userUseCase, sessionStore)
 
 	// Setup router
 	gin.SetMode(gin.TestMode)
@@ -76,15 +64,15 @@ func NewTestServer(t *testing.T) *TestServer {
 	auth := router.Group("/api/v1/auth")
 	{
 		auth.POST("/register", authHandler.Register)
-		auth.POST("/login", middleware.CreateSession(sessionStoreWrapper.Pkg(), sessionConfig), authHandler.Login)
+		auth.POST("/login", middleware.CreateSession(sessionStore, sessionConfig), authHandler.Login)
 		auth.POST("/refresh", authHandler.RefreshToken)
 
 		// Protected auth routes
 		protected := auth.Group("")
-		protected.Use(middleware.Session(sessionStoreWrapper.Pkg(), sessionConfig))
-		protected.Use(middleware.RequireSession(sessionStoreWrapper.Pkg()))
+		protected.Use(middleware.Session(sessionStore, sessionConfig))
+		protected.Use(middleware.RequireSession(sessionStore))
 		{
-			protected.POST("/logout", middleware.ClearSession(sessionStoreWrapper.Pkg(), sessionConfig), authHandler.Logout)
+			protected.POST("/logout", middleware.ClearSession(sessionStore, sessionConfig), authHandler.Logout)
 			protected.POST("/apikey", authHandler.GenerateAPIKey)
 			protected.GET("/sessions", authHandler.GetActiveSessions)
 			protected.DELETE("/sessions/:id", authHandler.RevokeSession)
@@ -100,15 +88,15 @@ func NewTestServer(t *testing.T) *TestServer {
 			tracks.GET("", func(c *gin.Context) { c.Status(http.StatusOK) })
 
 			authenticated := tracks.Group("")
-			authenticated.Use(middleware.Session(sessionStoreWrapper.Pkg(), sessionConfig))
-			authenticated.Use(middleware.RequireSession(sessionStoreWrapper.Pkg()))
+			authenticated.Use(middleware.Session(sessionStore, sessionConfig))
+			authenticated.Use(middleware.RequireSession(sessionStore))
 			{
 				authenticated.POST("", func(c *gin.Context) { c.Status(http.StatusOK) })
 				authenticated.PUT("/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
 				authenticated.DELETE("/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
 
 				admin := authenticated.Group("")
-				admin.Use(middleware.RequireRole(pkgdomain.RoleAdmin))
+				admin.Use(middleware.RequireRole(domain.RoleAdmin))
 				{
 					admin.POST("/batch", func(c *gin.Context) { c.Status(http.StatusOK) })
 					admin.POST("/export", func(c *gin.Context) { c.Status(http.StatusOK) })
@@ -121,9 +109,9 @@ func NewTestServer(t *testing.T) *TestServer {
 	ts := &TestServer{
 		Router:       router,
 		AuthHandler:  authHandler,
-		SessionStore: sessionStoreWrapper.Pkg(),
-		UserRepo:     userRepoWrapper.Pkg(),
-		AuthService:  authServiceWrapper.Pkg(),
+		SessionStore: sessionStore,
+		UserRepo:     userRepo,
+		AuthService:  authService,
 		cleanup:      func() {},
 	}
 
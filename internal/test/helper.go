@@ -2,7 +2,7 @@ package test
 
 import (
 	"fmt"
-	"metadatatool/internal/domain"
+	"metadatatool/internal/pkg/domain"
 	"metadatatool/internal/test/testutil"
 	"net/http/httptest"
 	"testing"
@@ -2,7 +2,7 @@ package integration
 
 import (
 	"encoding/json"
-	"metadatatool/internal/domain"
+	"metadatatool/internal/pkg/domain"
 	"metadatatool/internal/test/testutil"
 	"net/http"
 	"testing"
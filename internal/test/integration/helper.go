@@ -2,8 +2,8 @@ package integration
 
 import (
 	"context"
-	"metadatatool/internal/domain"
 	"metadatatool/internal/handler"
+	"metadatatool/internal/pkg/domain"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
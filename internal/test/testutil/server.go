@@ -5,9 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"metadatatool/internal/domain"
 	"metadatatool/internal/handler"
-	"metadatatool/internal/pkg/converter"
+	"metadatatool/internal/pkg/domain"
 	"metadatatool/internal/repository/base"
 	"metadatatool/internal/usecase"
 	"net/http"
@@ -34,18 +33,15 @@ func NewTestServer(t *testing.T) *TestServer {
 	router := gin.New()
 	router.Use(gin.Recovery())
 
-	// Initialize repositories and wrappers
-	pkgUserRepo := NewInMemoryPkgUserRepository()
-	pkgSessionStore := NewInMemoryPkgSessionStore()
-
-	userRepoWrapper := converter.NewUserRepositoryWrapper(base.NewInMemoryUserRepository(), pkgUserRepo)
-	sessionStoreWrapper := converter.NewSessionStoreWrapper(base.NewInMemorySessionRepository(), pkgSessionStore)
+	// Initialize repositories
+	userRepo := base.NewInMemoryUserRepository()
+	sessionStore := base.NewInMemorySessionRepository()
 
 	// Initialize services and handlers
 	authService := base.NewInMemoryAuthService()
-	userUseCase := usecase.NewUserUseCase(userRepoWrapper)
-	authUseCase := usecase.NewAuthUseCase(userRepoWrapper.Internal(), sessionStoreWrapper.Internal(), authService)
-	authHandler := handler.NewAuthHandler(authUseCase, userUseCase, sessionStoreWrapper.Internal())
+	userUseCase := usecase.NewUserUseCase(userRepo)
+	authUseCase := usecase.NewAuthUseCase(userRepo, sessionStore, authService)
+	authHandler := handler.NewAuthHandler(authUseCase, userUseCase, sessionStore)
 
 	// Setup routes
 	router.POST("/auth/register", authHandler.Register)
@@ -57,9 +53,9 @@ func NewTestServer(t *testing.T) *TestServer {
 	return &TestServer{
 		Router:       router,
 		AuthHandler:  authHandler,
-		SessionStore: sessionStoreWrapper.Internal(),
+		SessionStore: sessionStore,
 		AuthService:  authService,
-		UserRepo:     userRepoWrapper.Internal(),
+		UserRepo:     userRepo,
 		cleanup:      func() {},
 	}
 }
@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/google/uuid"
+)
+
+// aiBatchListLimit is the number of batches listed
+const aiBatchListLimit = 100
+
+// AIBatchUseCase enriches large sets of tracks through the providers'
+// batch APIs, which cost less than enriching tracks one by one. Batches
+// are polled until the provider is done, and their results are then merged
+// into the tracks like any other enrichment.
+type AIBatchUseCase struct {
+	batches   domain.AIBatchRepository
+	tracks    domain.TrackRepository
+	providers domain.AIBatchProviders
+	merger    domain.EnrichmentMerger
+	usage     domain.UsageRecorder
+	maxTracks int
+	locks     *TrackLockUseCase
+}
+
+// NewAIBatchUseCase creates a new AI batch use case. usage may be nil to
+// leave batch enrichments unmetered. A batch holds at most maxTracks
+// tracks.
+func NewAIBatchUseCase(batches domain.AIBatchRepository, tracks domain.TrackRepository, providers domain.AIBatchProviders, merger domain.EnrichmentMerger, usage domain.UsageRecorder, maxTracks int) *AIBatchUseCase {
+	return &AIBatchUseCase{
+		batches:   batches,
+		tracks:    tracks,
+		providers: providers,
+		merger:    merger,
+		usage:     usage,
+		maxTracks: maxTracks,
+	}
+}
+
+// SetTrackLocks locks the tracks of a batch while its results are saved.
+// A batch whose tracks another worker is enriching is saved at a later
+// poll.
+func (uc *AIBatchUseCase) SetTrackLocks(locks *TrackLockUseCase) {
+	uc.locks = locks
+}
+
+// Submit submits a batch enriching the tracks with a provider
+func (uc *AIBatchUseCase) Submit(ctx context.Context, provider domain.AIProvider, trackIDs []string, requestedBy string) (*domain.AIBatch, error) {
+	trackIDs = uniqueIDs(trackIDs)
+	if len(trackIDs) == 0 {
+		return nil, fmt.Errorf("%w: no tracks to enrich", domain.ErrInvalidInput)
+	}
+	if len(trackIDs) > uc.maxTracks {
+		return nil, fmt.Errorf("%w: a batch holds at most %d tracks", domain.ErrInvalidInput, uc.maxTracks)
+	}
+	if provider == "" {
+		provider = domain.AIProviderOpenAI
+	}
+	batches, ok := uc.providers.BatchProvider(provider)
+	if !ok {
+		return nil, fmt.Errorf("%w: AI provider %q has no batch API", domain.ErrInvalidInput, provider)
+	}
+
+	tracks := make([]*domain.Track, 0, len(trackIDs))
+	for _, id := range trackIDs {
+		track, err := uc.tracks.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get track %s: %w", id, err)
+		}
+		if track == nil {
+			return nil, fmt.Errorf("%w: %s", domain.ErrTrackNotFound, id)
+		}
+		tracks = append(tracks, track)
+	}
+
+	providerBatchID, err := batches.SubmitBatch(ctx, tracks)
+	if err != nil {
+		return nil, err
+	}
+	batch := &domain.AIBatch{
+		ID:              uuid.NewString(),
+		Provider:        provider,
+		ProviderBatchID: providerBatchID,
+		Status:          domain.AIBatchPending,
+		TrackIDs:        make([]string, len(tracks)),
+		RequestedBy:     requestedBy,
+	}
+	for i, track := range tracks {
+		batch.TrackIDs[i] = track.ID
+	}
+	if err := uc.batches.Create(ctx, batch); err != nil {
+		// The provider runs the batch anyway, so stop it rather than pay
+		// for results nobody saves
+		if cancelErr := batches.CancelBatch(ctx, providerBatchID); cancelErr != nil {
+			log.Printf("Failed to cancel unsaved AI batch %s: %v", providerBatchID, cancelErr)
+		}
+		return nil, err
+	}
+	return batch, nil
+}
+
+// Get returns a batch
+func (uc *AIBatchUseCase) Get(ctx context.Context, id string) (*domain.AIBatch, error) {
+	return uc.batches.GetByID(ctx, id)
+}
+
+// List returns the latest batches, newest first
+func (uc *AIBatchUseCase) List(ctx context.Context) ([]*domain.AIBatch, error) {
+	return uc.batches.List(ctx, aiBatchListLimit)
+}
+
+// Cancel asks the provider to stop a pending batch. The batch stays
+// pending until the provider has stopped it; the results of the requests
+// that finished are saved then.
+func (uc *AIBatchUseCase) Cancel(ctx context.Context, id string) (*domain.AIBatch, error) {
+	batch, err := uc.batches.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status != domain.AIBatchPending {
+		return nil, fmt.Errorf("%w: batch is %s", domain.ErrInvalidInput, batch.Status)
+	}
+	batches, ok := uc.providers.BatchProvider(batch.Provider)
+	if !ok {
+		return nil, fmt.Errorf("AI provider %q has no batch API", batch.Provider)
+	}
+	if err := batches.CancelBatch(ctx, batch.ProviderBatchID); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// Poll checks every pending batch and saves the results of the ones the
+// provider is done with
+func (uc *AIBatchUseCase) Poll(ctx context.Context) error {
+	pending, err := uc.batches.ListPending(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, batch := range pending {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := uc.poll(ctx, batch); err != nil {
+			errs = append(errs, fmt.Errorf("batch %s: %w", batch.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run polls the pending batches every interval until ctx is cancelled
+func (uc *AIBatchUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := uc.Poll(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Error polling AI batches: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// poll checks a batch and, once the provider is done with it, merges its
+// results into the tracks
+func (uc *AIBatchUseCase) poll(ctx context.Context, batch *domain.AIBatch) error {
+	batches, ok := uc.providers.BatchProvider(batch.Provider)
+	if !ok {
+		return fmt.Errorf("AI provider %q has no batch API", batch.Provider)
+	}
+	progress, err := batches.CheckBatch(ctx, batch.ProviderBatchID)
+	if err != nil {
+		return err
+	}
+	if progress.Status == domain.AIBatchPending {
+		return nil
+	}
+
+	if progress.Status == domain.AIBatchFailed {
+		batch.Failed = len(batch.TrackIDs)
+		batch.Error = progress.Error
+	} else if err := uc.reconcile(ctx, batches, batch); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	batch.Status = progress.Status
+	batch.CompletedAt = &now
+	return uc.batches.Update(ctx, batch)
+}
+
+// reconcile applies the results of a batch to fresh copies of its tracks,
+// merges them and saves the tracks, holding their locks throughout
+func (uc *AIBatchUseCase) reconcile(ctx context.Context, batches domain.AIBatchProvider, batch *domain.AIBatch) error {
+	return uc.locks.Do(ctx, "ai_batch:"+batch.ID, batch.TrackIDs, func(ctx context.Context) error {
+		return uc.saveResults(ctx, batches, batch)
+	})
+}
+
+// saveResults merges the results of a batch into its tracks and saves them
+func (uc *AIBatchUseCase) saveResults(ctx context.Context, batches domain.AIBatchProvider, batch *domain.AIBatch) error {
+	tracks := make([]*domain.Track, 0, len(batch.TrackIDs))
+	enriched := make([]*domain.Track, 0, len(batch.TrackIDs))
+	for _, id := range batch.TrackIDs {
+		track, err := uc.tracks.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get track %s: %w", id, err)
+		}
+		// Tracks deleted since the batch was submitted are skipped
+		if track == nil {
+			continue
+		}
+		tracks = append(tracks, track)
+		enriched = append(enriched, track.Clone())
+	}
+
+	failures, err := batches.BatchResults(ctx, batch.ProviderBatchID, enriched)
+	if err != nil {
+		return err
+	}
+
+	batch.Enriched, batch.Failed = 0, len(batch.TrackIDs)-len(tracks)
+	for i, track := range tracks {
+		if failure, failed := failures[track.ID]; failed {
+			log.Printf("AI batch %s has no result for track %s: %v", batch.ID, track.ID, failure)
+			batch.Failed++
+			continue
+		}
+		uc.merger.Merge(ctx, track, enriched[i])
+		if err := uc.tracks.Update(ctx, track); err != nil {
+			log.Printf("Failed to save track %s enriched by AI batch %s: %v", track.ID, batch.ID, err)
+			batch.Failed++
+			continue
+		}
+		batch.Enriched++
+		if uc.usage != nil {
+			uc.usage.RecordUsage(ctx, track.LabelID, domain.UsageAIEnrichments, 1)
+		}
+	}
+	return nil
+}
+
+// uniqueIDs returns ids without duplicates, in their first order
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,265 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memAIBatchRepository keeps AI batches in memory
+type memAIBatchRepository map[string]*pkgdomain.AIBatch
+
+func (r memAIBatchRepository) Create(_ context.Context, batch *pkgdomain.AIBatch) error {
+	r[batch.ID] = batch
+	return nil
+}
+
+func (r memAIBatchRepository) Update(_ context.Context, batch *pkgdomain.AIBatch) error {
+	r[batch.ID] = batch
+	return nil
+}
+
+func (r memAIBatchRepository) GetByID(_ context.Context, id string) (*pkgdomain.AIBatch, error) {
+	batch, ok := r[id]
+	if !ok {
+		return nil, pkgdomain.ErrAIBatchNotFound
+	}
+	return batch, nil
+}
+
+func (r memAIBatchRepository) List(_ context.Context, _ int) ([]*pkgdomain.AIBatch, error) {
+	batches := make([]*pkgdomain.AIBatch, 0, len(r))
+	for _, batch := range r {
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
+func (r memAIBatchRepository) ListPending(_ context.Context) ([]*pkgdomain.AIBatch, error) {
+	var batches []*pkgdomain.AIBatch
+	for _, batch := range r {
+		if batch.Status == pkgdomain.AIBatchPending {
+			batches = append(batches, batch)
+		}
+	}
+	return batches, nil
+}
+
+// fakeBatchProvider runs batches in memory, answering every track with
+// genre except the ones in failed
+type fakeBatchProvider struct {
+	submitted [][]string
+	cancelled []string
+	progress  pkgdomain.AIBatchProgress
+	genre     string
+	failed    map[string]bool
+}
+
+func (p *fakeBatchProvider) BatchProvider(provider pkgdomain.AIProvider) (pkgdomain.AIBatchProvider, bool) {
+	return p, provider == pkgdomain.AIProviderOpenAI
+}
+
+func (p *fakeBatchProvider) SubmitBatch(_ context.Context, tracks []*pkgdomain.Track) (string, error) {
+	ids := make([]string, len(tracks))
+	for i, track := range tracks {
+		ids[i] = track.ID
+	}
+	p.submitted = append(p.submitted, ids)
+	return fmt.Sprintf("batch-%d", len(p.submitted)), nil
+}
+
+func (p *fakeBatchProvider) CheckBatch(context.Context, string) (*pkgdomain.AIBatchProgress, error) {
+	progress := p.progress
+	return &progress, nil
+}
+
+func (p *fakeBatchProvider) BatchResults(_ context.Context, _ string, tracks []*pkgdomain.Track) (map[string]error, error) {
+	failures := make(map[string]error)
+	for _, track := range tracks {
+		if p.failed[track.ID] {
+			failures[track.ID] = fmt.Errorf("no result in batch")
+			continue
+		}
+		track.SetGenre(p.genre)
+		track.Metadata.AI = &pkgdomain.TrackAIMetadata{Model: "gpt-4o-mini", Confidence: 0.9}
+	}
+	return failures, nil
+}
+
+func (p *fakeBatchProvider) CancelBatch(_ context.Context, batchID string) error {
+	p.cancelled = append(p.cancelled, batchID)
+	return nil
+}
+
+// manualMerger keeps the genre of tracks a person has edited, as the
+// provenance merge does
+type manualMerger struct{ manual map[string]bool }
+
+func (m manualMerger) Merge(_ context.Context, track, enriched *pkgdomain.Track) {
+	if !m.manual[track.ID] {
+		track.SetGenre(enriched.Genre())
+	}
+	track.Metadata.AI = enriched.Metadata.AI
+}
+
+// memUsage counts usage per label
+type memUsage map[string]int64
+
+func (u memUsage) RecordUsage(_ context.Context, labelID string, _ pkgdomain.UsageMetric, n int64) {
+	u[labelID] += n
+}
+
+func TestAIBatchUseCase_Submit(t *testing.T) {
+	tracks := new(MockTrackRepository)
+	tracks.On("GetByID", mock.Anything, "track-1").Return(&pkgdomain.Track{ID: "track-1"}, nil)
+	tracks.On("GetByID", mock.Anything, "track-2").Return(&pkgdomain.Track{ID: "track-2"}, nil)
+	tracks.On("GetByID", mock.Anything, "missing").Return(nil, nil)
+	provider := &fakeBatchProvider{}
+	batches := memAIBatchRepository{}
+	uc := NewAIBatchUseCase(batches, tracks, provider, manualMerger{}, nil, 2)
+	ctx := context.Background()
+
+	batch, err := uc.Submit(ctx, "", []string{"track-1", "track-2", "track-1"}, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, pkgdomain.AIProviderOpenAI, batch.Provider)
+	assert.Equal(t, "batch-1", batch.ProviderBatchID)
+	assert.Equal(t, pkgdomain.AIBatchPending, batch.Status)
+	assert.Equal(t, []string{"track-1", "track-2"}, batch.TrackIDs, "duplicates are submitted once")
+	assert.Same(t, batch, batches[batch.ID])
+
+	_, err = uc.Submit(ctx, "", nil, "admin-1")
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput)
+	_, err = uc.Submit(ctx, "", []string{"track-1", "track-2", "track-3"}, "admin-1")
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput, "too many tracks")
+	_, err = uc.Submit(ctx, pkgdomain.AIProviderQwen2, []string{"track-1"}, "admin-1")
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput, "qwen2 has no batch API")
+	_, err = uc.Submit(ctx, "", []string{"missing"}, "admin-1")
+	assert.ErrorIs(t, err, pkgdomain.ErrTrackNotFound)
+	assert.Len(t, provider.submitted, 1)
+}
+
+func TestAIBatchUseCase_Poll(t *testing.T) {
+	tracks := new(MockTrackRepository)
+	edited := &pkgdomain.Track{ID: "edited", LabelID: "label-a"}
+	edited.SetGenre("Ambient")
+	tracks.On("GetByID", mock.Anything, "plain").Return(&pkgdomain.Track{ID: "plain", LabelID: "label-a"}, nil)
+	tracks.On("GetByID", mock.Anything, "edited").Return(edited, nil)
+	tracks.On("GetByID", mock.Anything, "failed").Return(&pkgdomain.Track{ID: "failed", LabelID: "label-b"}, nil)
+	tracks.On("GetByID", mock.Anything, "deleted").Return(nil, nil)
+	saved := make(map[string]*pkgdomain.Track)
+	tracks.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		track := args.Get(1).(*pkgdomain.Track)
+		saved[track.ID] = track
+	}).Return(nil)
+
+	provider := &fakeBatchProvider{
+		progress: pkgdomain.AIBatchProgress{Status: pkgdomain.AIBatchPending},
+		genre:    "Techno",
+		failed:   map[string]bool{"failed": true},
+	}
+	batches := memAIBatchRepository{"batch": {
+		ID:              "batch",
+		Provider:        pkgdomain.AIProviderOpenAI,
+		ProviderBatchID: "batch-1",
+		Status:          pkgdomain.AIBatchPending,
+		TrackIDs:        []string{"plain", "edited", "failed", "deleted"},
+	}}
+	usage := memUsage{}
+	uc := NewAIBatchUseCase(batches, tracks, provider, manualMerger{manual: map[string]bool{"edited": true}}, usage, 100)
+	ctx := context.Background()
+
+	require.NoError(t, uc.Poll(ctx))
+	assert.Equal(t, pkgdomain.AIBatchPending, batches["batch"].Status, "the provider is not done")
+	assert.Empty(t, saved)
+
+	provider.progress.Status = pkgdomain.AIBatchCompleted
+	require.NoError(t, uc.Poll(ctx))
+	batch := batches["batch"]
+	assert.Equal(t, pkgdomain.AIBatchCompleted, batch.Status)
+	assert.NotNil(t, batch.CompletedAt)
+	assert.Equal(t, 2, batch.Enriched)
+	assert.Equal(t, 2, batch.Failed, "a track without a result and a deleted track")
+
+	require.Len(t, saved, 2)
+	assert.Equal(t, "Techno", saved["plain"].Genre())
+	assert.Equal(t, "Ambient", saved["edited"].Genre(), "edited fields are kept")
+	assert.NotNil(t, saved["edited"].Metadata.AI)
+	assert.Equal(t, memUsage{"label-a": 2}, usage)
+
+	require.NoError(t, uc.Poll(ctx))
+	assert.Len(t, saved, 2, "completed batches are not polled again")
+}
+
+func TestAIBatchUseCase_PollLockedTracks(t *testing.T) {
+	tracks := new(MockTrackRepository)
+	tracks.On("GetByID", mock.Anything, "track-1").Return(&pkgdomain.Track{ID: "track-1"}, nil)
+	tracks.On("Update", mock.Anything, mock.Anything).Return(nil)
+	provider := &fakeBatchProvider{progress: pkgdomain.AIBatchProgress{Status: pkgdomain.AIBatchCompleted}, genre: "Techno"}
+	batches := memAIBatchRepository{"batch": {
+		ID:       "batch",
+		Provider: pkgdomain.AIProviderOpenAI,
+		Status:   pkgdomain.AIBatchPending,
+		TrackIDs: []string{"track-1"},
+	}}
+	locker := memTrackLocker{}
+	uc := NewAIBatchUseCase(batches, tracks, provider, manualMerger{}, nil, 100)
+	uc.SetTrackLocks(NewTrackLockUseCase(locker, time.Minute))
+	ctx := context.Background()
+
+	lock, err := locker.Lock(ctx, []string{"track-1"}, "reenrichment", time.Minute)
+	require.NoError(t, err)
+	assert.ErrorIs(t, uc.Poll(ctx), pkgdomain.ErrTrackLocked)
+	assert.Equal(t, pkgdomain.AIBatchPending, batches["batch"].Status, "the results are saved at a later poll")
+	tracks.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+	require.NoError(t, locker.Unlock(ctx, []string{"track-1"}, lock))
+	require.NoError(t, uc.Poll(ctx))
+	assert.Equal(t, pkgdomain.AIBatchCompleted, batches["batch"].Status)
+	assert.Equal(t, 1, batches["batch"].Enriched)
+	assert.Empty(t, locker)
+}
+
+func TestAIBatchUseCase_PollFailedBatch(t *testing.T) {
+	provider := &fakeBatchProvider{progress: pkgdomain.AIBatchProgress{Status: pkgdomain.AIBatchFailed, Error: "invalid input file"}}
+	batches := memAIBatchRepository{"batch": {
+		ID:       "batch",
+		Provider: pkgdomain.AIProviderOpenAI,
+		Status:   pkgdomain.AIBatchPending,
+		TrackIDs: []string{"track-1", "track-2"},
+	}}
+	tracks := new(MockTrackRepository)
+	uc := NewAIBatchUseCase(batches, tracks, provider, manualMerger{}, nil, 100)
+
+	require.NoError(t, uc.Poll(context.Background()))
+	batch := batches["batch"]
+	assert.Equal(t, pkgdomain.AIBatchFailed, batch.Status)
+	assert.Equal(t, "invalid input file", batch.Error)
+	assert.Equal(t, 2, batch.Failed)
+	tracks.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestAIBatchUseCase_Cancel(t *testing.T) {
+	provider := &fakeBatchProvider{}
+	batches := memAIBatchRepository{
+		"pending": {ID: "pending", Provider: pkgdomain.AIProviderOpenAI, ProviderBatchID: "batch-1", Status: pkgdomain.AIBatchPending},
+		"done":    {ID: "done", Provider: pkgdomain.AIProviderOpenAI, ProviderBatchID: "batch-2", Status: pkgdomain.AIBatchCompleted},
+	}
+	uc := NewAIBatchUseCase(batches, new(MockTrackRepository), provider, manualMerger{}, nil, 100)
+	ctx := context.Background()
+
+	_, err := uc.Cancel(ctx, "pending")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"batch-1"}, provider.cancelled)
+
+	_, err = uc.Cancel(ctx, "done")
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput)
+	_, err = uc.Cancel(ctx, "unknown")
+	assert.ErrorIs(t, err, pkgdomain.ErrAIBatchNotFound)
+}
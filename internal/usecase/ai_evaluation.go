@@ -0,0 +1,365 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"metadatatool/internal/pkg/analytics"
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+)
+
+const (
+	// calibrationBins is the number of equal confidence ranges the
+	// calibration curves are split into
+	calibrationBins = 10
+	// minSuggestionSamples is the number of tracks a confidence threshold
+	// must keep for it to be suggested
+	minSuggestionSamples = 10
+	// bpmTolerance is how far, as a share, an AI tempo may be from the
+	// golden one and still count as right
+	bpmTolerance = 0.02
+)
+
+// evaluationFields lists the scored fields in report order
+var evaluationFields = []string{
+	domain.EvaluationFieldGenre,
+	domain.EvaluationFieldMood,
+	domain.EvaluationFieldBPM,
+	domain.EvaluationFieldKey,
+}
+
+// evaluationSample is one enrichment of a golden track
+type evaluationSample struct {
+	confidence float64
+	// correct is whether every labeled field was right
+	correct bool
+}
+
+// EvaluationUseCase scores the AI providers against a golden dataset of
+// tracks whose values a person has confirmed. Each evaluation measures the
+// accuracy per field, records calibration curves in analytics and suggests
+// the confidence threshold reaching the target accuracy.
+type EvaluationUseCase struct {
+	golden         domain.GoldenDatasetRepository
+	tracks         domain.TrackRepository
+	providers      domain.ProviderEnricher
+	recorder       analytics.EventRecorder
+	targetAccuracy float64
+
+	mu     sync.RWMutex
+	latest *domain.EvaluationReport
+}
+
+// NewEvaluationUseCase creates a new evaluation use case. recorder may be
+// nil to keep the results out of analytics.
+func NewEvaluationUseCase(golden domain.GoldenDatasetRepository, tracks domain.TrackRepository, providers domain.ProviderEnricher, recorder analytics.EventRecorder, targetAccuracy float64) *EvaluationUseCase {
+	return &EvaluationUseCase{
+		golden:         golden,
+		tracks:         tracks,
+		providers:      providers,
+		recorder:       recorder,
+		targetAccuracy: targetAccuracy,
+	}
+}
+
+// SaveGoldenTrack adds a track to the golden dataset or replaces its
+// values. At least one field must be labeled.
+func (uc *EvaluationUseCase) SaveGoldenTrack(ctx context.Context, golden *domain.GoldenTrack) error {
+	golden.Genre = strings.TrimSpace(golden.Genre)
+	golden.Mood = strings.TrimSpace(golden.Mood)
+	golden.Key = strings.TrimSpace(golden.Key)
+	if golden.Genre == "" && golden.Mood == "" && golden.BPM == 0 && golden.Key == "" {
+		return fmt.Errorf("%w: label at least one of genre, mood, bpm and key", domain.ErrInvalidInput)
+	}
+	if golden.BPM < 0 {
+		return fmt.Errorf("%w: bpm must not be negative", domain.ErrInvalidInput)
+	}
+	if golden.Key != "" {
+		if _, ok := domain.ParseKey(golden.Key); !ok {
+			return fmt.Errorf("%w: unknown key %q", domain.ErrInvalidInput, golden.Key)
+		}
+	}
+
+	track, err := uc.tracks.GetByID(ctx, golden.TrackID)
+	if err != nil {
+		return fmt.Errorf("failed to get track: %w", err)
+	}
+	if track == nil {
+		return domain.ErrTrackNotFound
+	}
+	golden.UpdatedAt = time.Now()
+	if golden.CreatedAt.IsZero() {
+		golden.CreatedAt = golden.UpdatedAt
+	}
+	return uc.golden.Save(ctx, golden)
+}
+
+// DeleteGoldenTrack removes a track from the golden dataset
+func (uc *EvaluationUseCase) DeleteGoldenTrack(ctx context.Context, trackID string) error {
+	return uc.golden.Delete(ctx, trackID)
+}
+
+// ListGoldenTracks returns the golden dataset
+func (uc *EvaluationUseCase) ListGoldenTracks(ctx context.Context) ([]*domain.GoldenTrack, error) {
+	return uc.golden.List(ctx)
+}
+
+// Latest returns the report of the latest evaluation
+func (uc *EvaluationUseCase) Latest() (*domain.EvaluationReport, error) {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	if uc.latest == nil {
+		return nil, domain.ErrEvaluationNotFound
+	}
+	return uc.latest, nil
+}
+
+// Evaluate enriches every golden track with every provider and scores the
+// results. The labeled fields are cleared before enrichment so providers
+// cannot copy them, and the enriched copies are not saved.
+func (uc *EvaluationUseCase) Evaluate(ctx context.Context) (*domain.EvaluationReport, error) {
+	golden, err := uc.golden.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Golden tracks deleted from the catalog are skipped
+	type goldenPair struct {
+		golden *domain.GoldenTrack
+		track  *domain.Track
+	}
+	pairs := make([]goldenPair, 0, len(golden))
+	for _, g := range golden {
+		track, err := uc.tracks.GetByID(ctx, g.TrackID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get track %s: %w", g.TrackID, err)
+		}
+		if track != nil {
+			pairs = append(pairs, goldenPair{golden: g, track: track})
+		}
+	}
+
+	report := &domain.EvaluationReport{
+		EvaluatedAt:    time.Now(),
+		GoldenTracks:   len(pairs),
+		TargetAccuracy: uc.targetAccuracy,
+	}
+	for _, provider := range uc.providers.Providers() {
+		evaluation := domain.ProviderEvaluation{Provider: provider}
+		samples := make([]evaluationSample, 0, len(pairs))
+		correct := make(map[string]int)
+		labeled := make(map[string]int)
+		for _, pair := range pairs {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			enriched := pair.track.Clone()
+			clearGoldenFields(enriched, pair.golden)
+			enriched.Metadata.AI = nil
+			if err := uc.providers.EnrichWithProvider(ctx, provider, enriched); err != nil {
+				evaluation.Failures++
+				continue
+			}
+			evaluation.Tracks++
+
+			sample := evaluationSample{correct: true}
+			if enriched.Metadata.AI != nil {
+				sample.confidence = enriched.Metadata.AI.Confidence
+			}
+			for field, right := range scoreGoldenFields(enriched, pair.golden) {
+				labeled[field]++
+				if right {
+					correct[field]++
+				} else {
+					sample.correct = false
+				}
+			}
+			samples = append(samples, sample)
+		}
+
+		for _, field := range evaluationFields {
+			if labeled[field] == 0 {
+				continue
+			}
+			evaluation.Fields = append(evaluation.Fields, domain.FieldAccuracy{
+				Field:    field,
+				Samples:  labeled[field],
+				Correct:  correct[field],
+				Accuracy: float64(correct[field]) / float64(labeled[field]),
+			})
+		}
+		evaluation.Calibration = calibrationCurve(samples)
+		evaluation.SuggestedMinConfidence = suggestMinConfidence(samples, uc.targetAccuracy)
+		report.Providers = append(report.Providers, evaluation)
+	}
+
+	uc.publish(report)
+	return report, nil
+}
+
+// Run evaluates the providers now and every interval until ctx is done,
+// logging the suggested confidence thresholds
+func (uc *EvaluationUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := uc.Evaluate(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Failed to evaluate AI providers: %v", err)
+		}
+		if report != nil {
+			for _, provider := range report.Providers {
+				if provider.SuggestedMinConfidence != nil {
+					log.Printf("AI provider %s reaches %.0f%% accuracy above a confidence of %.2f",
+						provider.Provider, report.TargetAccuracy*100, *provider.SuggestedMinConfidence)
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish keeps a report as the latest and exports it to the metrics and
+// analytics
+func (uc *EvaluationUseCase) publish(report *domain.EvaluationReport) {
+	uc.mu.Lock()
+	uc.latest = report
+	uc.mu.Unlock()
+
+	for _, provider := range report.Providers {
+		for _, field := range provider.Fields {
+			metrics.AIFieldAccuracy.WithLabelValues(string(provider.Provider), field.Field).Set(field.Accuracy)
+			if uc.recorder != nil {
+				uc.recorder.Record(analytics.FieldAccuracyEvent{
+					Timestamp: report.EvaluatedAt,
+					Provider:  string(provider.Provider),
+					Field:     field.Field,
+					Samples:   field.Samples,
+					Correct:   field.Correct,
+					Accuracy:  field.Accuracy,
+				})
+			}
+		}
+		if uc.recorder == nil {
+			continue
+		}
+		for _, bin := range provider.Calibration {
+			uc.recorder.Record(analytics.CalibrationEvent{
+				Timestamp:      report.EvaluatedAt,
+				Provider:       string(provider.Provider),
+				MinConfidence:  bin.MinConfidence,
+				MaxConfidence:  bin.MaxConfidence,
+				Samples:        bin.Samples,
+				Correct:        bin.Correct,
+				MeanConfidence: bin.MeanConfidence,
+				Accuracy:       bin.Accuracy,
+			})
+		}
+	}
+}
+
+// clearGoldenFields clears the fields of a track that are labeled in the
+// golden dataset
+func clearGoldenFields(track *domain.Track, golden *domain.GoldenTrack) {
+	if golden.Genre != "" {
+		track.SetGenre("")
+	}
+	if golden.Mood != "" {
+		track.SetMood("")
+	}
+	if golden.BPM > 0 {
+		track.SetBPM(0)
+	}
+	if golden.Key != "" {
+		track.SetKey("")
+	}
+}
+
+// scoreGoldenFields reports for every labeled field whether the enriched
+// track got it right. Text is compared ignoring case, tempos within
+// bpmTolerance and keys by their place on the Camelot wheel, so "Am" and
+// "8A" match.
+func scoreGoldenFields(track *domain.Track, golden *domain.GoldenTrack) map[string]bool {
+	scores := make(map[string]bool)
+	if golden.Genre != "" {
+		scores[domain.EvaluationFieldGenre] = strings.EqualFold(strings.TrimSpace(track.Genre()), golden.Genre)
+	}
+	if golden.Mood != "" {
+		scores[domain.EvaluationFieldMood] = strings.EqualFold(strings.TrimSpace(track.Mood()), golden.Mood)
+	}
+	if golden.BPM > 0 {
+		scores[domain.EvaluationFieldBPM] = math.Abs(track.BPM()-golden.BPM) <= golden.BPM*bpmTolerance
+	}
+	if golden.Key != "" {
+		want, _ := domain.ParseKey(golden.Key)
+		got, ok := domain.ParseKey(track.Key())
+		scores[domain.EvaluationFieldKey] = ok && got == want
+	}
+	return scores
+}
+
+// calibrationCurve bins the samples by confidence and measures the
+// accuracy of each bin. Empty bins are left out.
+func calibrationCurve(samples []evaluationSample) []domain.CalibrationBin {
+	bins := make([]domain.CalibrationBin, calibrationBins)
+	for i := range bins {
+		bins[i].MinConfidence = float64(i) / calibrationBins
+		bins[i].MaxConfidence = float64(i+1) / calibrationBins
+	}
+	for _, sample := range samples {
+		i := int(sample.confidence * calibrationBins)
+		i = max(0, min(i, calibrationBins-1))
+		bins[i].Samples++
+		bins[i].MeanConfidence += sample.confidence
+		if sample.correct {
+			bins[i].Correct++
+		}
+	}
+
+	curve := make([]domain.CalibrationBin, 0, calibrationBins)
+	for _, bin := range bins {
+		if bin.Samples == 0 {
+			continue
+		}
+		bin.MeanConfidence /= float64(bin.Samples)
+		bin.Accuracy = float64(bin.Correct) / float64(bin.Samples)
+		curve = append(curve, bin)
+	}
+	return curve
+}
+
+// suggestMinConfidence returns the lowest bin edge above which the samples
+// reach the target accuracy, keeping at least minSuggestionSamples, or nil
+// when none does
+func suggestMinConfidence(samples []evaluationSample, target float64) *float64 {
+	for i := 0; i < calibrationBins; i++ {
+		threshold := float64(i) / calibrationBins
+		kept, correct := 0, 0
+		for _, sample := range samples {
+			if sample.confidence >= threshold {
+				kept++
+				if sample.correct {
+					correct++
+				}
+			}
+		}
+		if kept < minSuggestionSamples {
+			return nil
+		}
+		if float64(correct)/float64(kept) >= target {
+			return &threshold
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"metadatatool/internal/pkg/analytics"
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memGoldenRepository keeps the golden dataset in memory
+type memGoldenRepository map[string]*pkgdomain.GoldenTrack
+
+func (r memGoldenRepository) Save(_ context.Context, track *pkgdomain.GoldenTrack) error {
+	r[track.TrackID] = track
+	return nil
+}
+
+func (r memGoldenRepository) Delete(_ context.Context, trackID string) error {
+	if _, ok := r[trackID]; !ok {
+		return pkgdomain.ErrGoldenTrackNotFound
+	}
+	delete(r, trackID)
+	return nil
+}
+
+func (r memGoldenRepository) List(_ context.Context) ([]*pkgdomain.GoldenTrack, error) {
+	tracks := make([]*pkgdomain.GoldenTrack, 0, len(r))
+	for _, track := range r {
+		tracks = append(tracks, track)
+	}
+	sort.Slice(tracks, func(i, j int) bool { return tracks[i].TrackID < tracks[j].TrackID })
+	return tracks, nil
+}
+
+// fakeEnricher enriches tracks with a function per provider
+type fakeEnricher map[pkgdomain.AIProvider]func(track *pkgdomain.Track) error
+
+func (e fakeEnricher) Providers() []pkgdomain.AIProvider {
+	return []pkgdomain.AIProvider{pkgdomain.AIProviderQwen2, pkgdomain.AIProviderOpenAI}
+}
+
+func (e fakeEnricher) EnrichWithProvider(_ context.Context, provider pkgdomain.AIProvider, track *pkgdomain.Track) error {
+	return e[provider](track)
+}
+
+// memRecorder keeps the analytics events recorded
+type memRecorder struct {
+	mu     sync.Mutex
+	events []analytics.Event
+}
+
+func (r *memRecorder) Record(event analytics.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func TestEvaluationUseCase_SaveGoldenTrack(t *testing.T) {
+	tracks := new(MockTrackRepository)
+	tracks.On("GetByID", mock.Anything, "track-1").Return(&pkgdomain.Track{ID: "track-1"}, nil)
+	tracks.On("GetByID", mock.Anything, "missing").Return(nil, nil)
+	golden := memGoldenRepository{}
+	uc := NewEvaluationUseCase(golden, tracks, fakeEnricher{}, nil, 0.9)
+	ctx := context.Background()
+
+	require.NoError(t, uc.SaveGoldenTrack(ctx, &pkgdomain.GoldenTrack{TrackID: "track-1", Genre: " House ", Key: "8A"}))
+	assert.Equal(t, "House", golden["track-1"].Genre)
+	assert.False(t, golden["track-1"].CreatedAt.IsZero())
+
+	assert.ErrorIs(t, uc.SaveGoldenTrack(ctx, &pkgdomain.GoldenTrack{TrackID: "track-1"}), pkgdomain.ErrInvalidInput)
+	assert.ErrorIs(t, uc.SaveGoldenTrack(ctx, &pkgdomain.GoldenTrack{TrackID: "track-1", Key: "H minor"}), pkgdomain.ErrInvalidInput)
+	assert.ErrorIs(t, uc.SaveGoldenTrack(ctx, &pkgdomain.GoldenTrack{TrackID: "missing", Genre: "House"}), pkgdomain.ErrTrackNotFound)
+
+	require.NoError(t, uc.DeleteGoldenTrack(ctx, "track-1"))
+	assert.ErrorIs(t, uc.DeleteGoldenTrack(ctx, "track-1"), pkgdomain.ErrGoldenTrackNotFound)
+}
+
+func TestEvaluationUseCase_Evaluate(t *testing.T) {
+	tracks := new(MockTrackRepository)
+	golden := memGoldenRepository{}
+	for i := 0; i < 20; i++ {
+		id := fmt.Sprintf("track-%02d", i)
+		track := &pkgdomain.Track{ID: id}
+		// The answers are on the tracks, so providers must not see them
+		track.SetGenre("Techno")
+		track.SetKey("Am")
+		tracks.On("GetByID", mock.Anything, id).Return(track, nil)
+		golden[id] = &pkgdomain.GoldenTrack{TrackID: id, Genre: "techno", BPM: 128, Key: "Am"}
+	}
+	golden["deleted"] = &pkgdomain.GoldenTrack{TrackID: "deleted", Genre: "house"}
+	tracks.On("GetByID", mock.Anything, "deleted").Return(nil, nil)
+
+	var mu sync.Mutex
+	calls := 0
+	enricher := fakeEnricher{
+		// Qwen2 is right whenever it is confident
+		pkgdomain.AIProviderQwen2: func(track *pkgdomain.Track) error {
+			assert.Empty(t, track.Genre())
+			assert.Empty(t, track.Key())
+			mu.Lock()
+			calls++
+			n := calls
+			mu.Unlock()
+			confidence := 0.95
+			track.SetGenre("Techno")
+			track.SetBPM(129)
+			track.SetKey("8A")
+			if n%4 == 0 {
+				confidence = 0.55
+				track.SetGenre("House")
+			}
+			track.Metadata.AI = &pkgdomain.TrackAIMetadata{Confidence: confidence}
+			return nil
+		},
+		// OpenAI fails on every track
+		pkgdomain.AIProviderOpenAI: func(*pkgdomain.Track) error {
+			return fmt.Errorf("unavailable")
+		},
+	}
+	recorder := &memRecorder{}
+	uc := NewEvaluationUseCase(golden, tracks, enricher, recorder, 0.9)
+
+	_, err := uc.Latest()
+	assert.ErrorIs(t, err, pkgdomain.ErrEvaluationNotFound)
+
+	report, err := uc.Evaluate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 20, report.GoldenTracks)
+	require.Len(t, report.Providers, 2)
+
+	qwen2 := report.Providers[0]
+	assert.Equal(t, pkgdomain.AIProviderQwen2, qwen2.Provider)
+	assert.Equal(t, 20, qwen2.Tracks)
+	assert.Equal(t, []pkgdomain.FieldAccuracy{
+		{Field: pkgdomain.EvaluationFieldGenre, Samples: 20, Correct: 15, Accuracy: 0.75},
+		{Field: pkgdomain.EvaluationFieldBPM, Samples: 20, Correct: 20, Accuracy: 1},
+		{Field: pkgdomain.EvaluationFieldKey, Samples: 20, Correct: 20, Accuracy: 1},
+	}, qwen2.Fields)
+
+	require.Len(t, qwen2.Calibration, 2)
+	assert.Equal(t, 5, qwen2.Calibration[0].Samples)
+	assert.Equal(t, 0.0, qwen2.Calibration[0].Accuracy)
+	assert.Equal(t, 0.5, qwen2.Calibration[0].MinConfidence)
+	assert.Equal(t, 15, qwen2.Calibration[1].Samples)
+	assert.Equal(t, 1.0, qwen2.Calibration[1].Accuracy)
+	assert.InDelta(t, 0.95, qwen2.Calibration[1].MeanConfidence, 1e-9)
+
+	require.NotNil(t, qwen2.SuggestedMinConfidence)
+	assert.InDelta(t, 0.6, *qwen2.SuggestedMinConfidence, 1e-9)
+
+	openAI := report.Providers[1]
+	assert.Equal(t, 20, openAI.Failures)
+	assert.Zero(t, openAI.Tracks)
+	assert.Nil(t, openAI.SuggestedMinConfidence, "no track to base a threshold on")
+
+	latest, err := uc.Latest()
+	require.NoError(t, err)
+	assert.Same(t, report, latest)
+
+	var accuracy, calibration int
+	for _, event := range recorder.events {
+		switch event.(type) {
+		case analytics.FieldAccuracyEvent:
+			accuracy++
+		case analytics.CalibrationEvent:
+			calibration++
+		}
+	}
+	assert.Equal(t, 3, accuracy)
+	assert.Equal(t, 2, calibration)
+}
+
+func TestSuggestMinConfidence(t *testing.T) {
+	samples := make([]evaluationSample, 0, 12)
+	for i := 0; i < 12; i++ {
+		samples = append(samples, evaluationSample{confidence: 0.9, correct: i < 10})
+	}
+	assert.Nil(t, suggestMinConfidence(samples, 0.9), "83% is below the target at every threshold")
+
+	threshold := suggestMinConfidence(samples, 0.8)
+	require.NotNil(t, threshold)
+	assert.Equal(t, 0.0, *threshold)
+
+	assert.Nil(t, suggestMinConfidence(samples[:5], 0.5), "too few tracks")
+}
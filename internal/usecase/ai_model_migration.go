@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// reenrichmentPageSize is the number of enriched tracks a re-enrichment
+// reads at a time
+const reenrichmentPageSize = 100
+
+// AIModelMigrationUseCase reports which model versions the catalog was
+// enriched with and enriches again, in the background, the tracks enriched
+// with a version no provider uses any more
+type AIModelMigrationUseCase struct {
+	models   domain.AIModelRepository
+	versions domain.AIModelVersioner
+	tracks   domain.TrackRepository
+	ai       domain.AIService
+	locks    *TrackLockUseCase
+
+	mu     sync.Mutex
+	run    *domain.ReenrichmentRun
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewAIModelMigrationUseCase creates a new AI model migration use case.
+// Tracks are enriched again with ai, which should merge the results like
+// any other enrichment.
+func NewAIModelMigrationUseCase(models domain.AIModelRepository, versions domain.AIModelVersioner, tracks domain.TrackRepository, ai domain.AIService) *AIModelMigrationUseCase {
+	return &AIModelMigrationUseCase{
+		models:   models,
+		versions: versions,
+		tracks:   tracks,
+		ai:       ai,
+	}
+}
+
+// SetTrackLocks locks each track while it is enriched again
+func (uc *AIModelMigrationUseCase) SetTrackLocks(locks *TrackLockUseCase) {
+	uc.locks = locks
+}
+
+// Coverage counts the catalog's tracks by the model version they were last
+// enriched with, and how many of them are outdated
+func (uc *AIModelMigrationUseCase) Coverage(ctx context.Context) (*domain.AIModelCoverageReport, error) {
+	report, err := uc.models.ModelCoverage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Current = uc.versions.ModelVersions()
+	for i := range report.Models {
+		model := &report.Models[i]
+		if report.Tracks > 0 {
+			model.Share = float64(model.Tracks) / float64(report.Tracks)
+		}
+		if current, ok := currentModelVersion(report.Current, model.AIModelVersion); ok {
+			model.Current = true
+			model.Provider = current.Provider
+		} else {
+			report.Outdated += model.Tracks
+		}
+	}
+	return report, nil
+}
+
+// StartReenrichment starts enriching again every track enriched with an
+// outdated model version and returns the new run. Only one run is in
+// progress at a time. The run keeps the values of ctx, such as the tenant,
+// but not its cancellation; use CancelReenrichment to stop it.
+func (uc *AIModelMigrationUseCase) StartReenrichment(ctx context.Context) (*domain.ReenrichmentRun, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if uc.run != nil && uc.run.Status == domain.ReenrichmentRunning {
+		return nil, domain.ErrReenrichmentRunning
+	}
+
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	uc.run = &domain.ReenrichmentRun{
+		Status:    domain.ReenrichmentRunning,
+		StartedAt: time.Now(),
+		Current:   uc.versions.ModelVersions(),
+	}
+	uc.cancel = cancel
+	uc.done = make(chan struct{})
+	go uc.reenrich(runCtx, uc.run.Current, uc.done)
+
+	run := *uc.run
+	return &run, nil
+}
+
+// Reenrichment returns the progress of the latest re-enrichment
+func (uc *AIModelMigrationUseCase) Reenrichment() (*domain.ReenrichmentRun, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if uc.run == nil {
+		return nil, domain.ErrReenrichmentNotFound
+	}
+	run := *uc.run
+	return &run, nil
+}
+
+// CancelReenrichment stops the re-enrichment in progress, waits for the
+// track being enriched and returns the run. Tracks already enriched keep
+// their new metadata.
+func (uc *AIModelMigrationUseCase) CancelReenrichment() (*domain.ReenrichmentRun, error) {
+	uc.mu.Lock()
+	if uc.run == nil {
+		uc.mu.Unlock()
+		return nil, domain.ErrReenrichmentNotFound
+	}
+	cancel, done := uc.cancel, uc.done
+	uc.mu.Unlock()
+
+	cancel()
+	<-done
+	return uc.Reenrichment()
+}
+
+// reenrich pages through the enriched tracks and enriches again the ones
+// made with a model version not in current
+func (uc *AIModelMigrationUseCase) reenrich(ctx context.Context, current []domain.AIModelVersion, done chan struct{}) {
+	defer close(done)
+
+	err := func() error {
+		afterID := ""
+		for {
+			page, err := uc.models.EnrichedTracks(ctx, afterID, reenrichmentPageSize)
+			if err != nil {
+				return err
+			}
+			for _, enriched := range page {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				afterID = enriched.TrackID
+				if _, ok := currentModelVersion(current, enriched.AIModelVersion); ok {
+					continue
+				}
+				if err := uc.reenrichTrack(ctx, enriched.TrackID); err != nil {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					log.Printf("Failed to re-enrich track %s: %v", enriched.TrackID, err)
+					uc.progress(func(run *domain.ReenrichmentRun) { run.Failed++ })
+					continue
+				}
+				uc.progress(func(run *domain.ReenrichmentRun) { run.Enriched++ })
+			}
+			if len(page) < reenrichmentPageSize {
+				return nil
+			}
+		}
+	}()
+
+	uc.progress(func(run *domain.ReenrichmentRun) {
+		now := time.Now()
+		run.CompletedAt = &now
+		switch {
+		case err == nil:
+			run.Status = domain.ReenrichmentCompleted
+		case errors.Is(err, context.Canceled):
+			run.Status = domain.ReenrichmentCancelled
+		default:
+			run.Status = domain.ReenrichmentFailed
+			run.Error = err.Error()
+		}
+		log.Printf("Re-enrichment %s: %d tracks enriched, %d failed", run.Status, run.Enriched, run.Failed)
+	})
+}
+
+// reenrichTrack enriches a track again and saves it, holding its lock. A
+// track another worker is enriching counts as failed.
+func (uc *AIModelMigrationUseCase) reenrichTrack(ctx context.Context, trackID string) error {
+	return uc.locks.Do(ctx, "reenrichment", []string{trackID}, func(ctx context.Context) error {
+		return uc.enrichAgain(ctx, trackID)
+	})
+}
+
+// enrichAgain enriches a track again and saves it. Its AI metadata is
+// cleared first, so every provider records the model version used now.
+func (uc *AIModelMigrationUseCase) enrichAgain(ctx context.Context, trackID string) error {
+	track, err := uc.tracks.GetByID(ctx, trackID)
+	if err != nil {
+		return fmt.Errorf("failed to get track: %w", err)
+	}
+	if track == nil {
+		return domain.ErrTrackNotFound
+	}
+
+	track.Metadata.AI = nil
+	if err := uc.ai.EnrichMetadata(ctx, track); err != nil {
+		return fmt.Errorf("failed to enrich track: %w", err)
+	}
+	if err := uc.tracks.Update(ctx, track); err != nil {
+		return fmt.Errorf("failed to save track: %w", err)
+	}
+	return nil
+}
+
+// progress updates the latest run
+func (uc *AIModelMigrationUseCase) progress(update func(run *domain.ReenrichmentRun)) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	update(uc.run)
+}
+
+// currentModelVersion returns the current model version an enrichment was
+// made with, if any
+func currentModelVersion(current []domain.AIModelVersion, version domain.AIModelVersion) (domain.AIModelVersion, bool) {
+	for _, c := range current {
+		if c.Matches(version) {
+			return c, true
+		}
+	}
+	return domain.AIModelVersion{}, false
+}
@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memAIModelRepository reads the model versions of tracks kept in memory
+type memAIModelRepository struct {
+	tracks map[string]*pkgdomain.Track
+}
+
+func (r *memAIModelRepository) ModelCoverage(_ context.Context) (*pkgdomain.AIModelCoverageReport, error) {
+	report := &pkgdomain.AIModelCoverageReport{Tracks: int64(len(r.tracks))}
+	counts := make(map[pkgdomain.AIModelVersion]int64)
+	for _, track := range r.tracks {
+		if version, ok := pkgdomain.ModelVersionOf(track); ok {
+			counts[version]++
+		} else {
+			report.Unenriched++
+		}
+	}
+	for version, count := range counts {
+		report.Models = append(report.Models, pkgdomain.AIModelCoverage{AIModelVersion: version, Tracks: count})
+	}
+	sort.Slice(report.Models, func(i, j int) bool { return report.Models[i].Tracks > report.Models[j].Tracks })
+	return report, nil
+}
+
+func (r *memAIModelRepository) EnrichedTracks(_ context.Context, afterID string, limit int) ([]pkgdomain.EnrichedTrack, error) {
+	var page []pkgdomain.EnrichedTrack
+	for _, track := range r.tracks {
+		if version, ok := pkgdomain.ModelVersionOf(track); ok && track.ID > afterID {
+			page = append(page, pkgdomain.EnrichedTrack{TrackID: track.ID, AIModelVersion: version})
+		}
+	}
+	sort.Slice(page, func(i, j int) bool { return page[i].TrackID < page[j].TrackID })
+	if len(page) > limit {
+		page = page[:limit]
+	}
+	return page, nil
+}
+
+// fakeVersioner reports fixed model versions
+type fakeVersioner []pkgdomain.AIModelVersion
+
+func (v fakeVersioner) ModelVersions() []pkgdomain.AIModelVersion { return v }
+
+// fakeAIService enriches tracks with a function
+type fakeAIService func(ctx context.Context, track *pkgdomain.Track) error
+
+func (f fakeAIService) EnrichMetadata(ctx context.Context, track *pkgdomain.Track) error {
+	return f(ctx, track)
+}
+
+func (f fakeAIService) ValidateMetadata(context.Context, *pkgdomain.Track) (float64, error) {
+	return 0, nil
+}
+
+func (f fakeAIService) BatchProcess(ctx context.Context, tracks []*pkgdomain.Track) error {
+	for _, track := range tracks {
+		if err := f(ctx, track); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	currentQwen2 = pkgdomain.AIModelVersion{Provider: pkgdomain.AIProviderQwen2, Model: "qwen2", Version: "v2", PromptVersion: "audio-v1"}
+	outdatedQwen = pkgdomain.AIModelVersion{Model: "qwen2", Version: "v1", PromptVersion: "audio-v1"}
+	legacyOpenAI = pkgdomain.AIModelVersion{Model: "openai", Version: "1.0"}
+)
+
+// enrichedTrack returns a track last enriched with a model version
+func enrichedTrack(id string, version *pkgdomain.AIModelVersion) *pkgdomain.Track {
+	track := &pkgdomain.Track{ID: id}
+	if version != nil {
+		track.Metadata.AI = &pkgdomain.TrackAIMetadata{
+			Model:         version.Model,
+			Version:       version.Version,
+			PromptVersion: version.PromptVersion,
+		}
+	}
+	return track
+}
+
+func newMigrationFixture(ai fakeAIService) (*AIModelMigrationUseCase, *MockTrackRepository) {
+	repo := &memAIModelRepository{tracks: map[string]*pkgdomain.Track{
+		"track-1": enrichedTrack("track-1", &currentQwen2),
+		"track-2": enrichedTrack("track-2", &outdatedQwen),
+		"track-3": enrichedTrack("track-3", &outdatedQwen),
+		"track-4": enrichedTrack("track-4", &legacyOpenAI),
+		"track-5": enrichedTrack("track-5", nil),
+	}}
+	tracks := new(MockTrackRepository)
+	for id, track := range repo.tracks {
+		tracks.On("GetByID", mock.Anything, id).Return(track.Clone(), nil).Maybe()
+	}
+	return NewAIModelMigrationUseCase(repo, fakeVersioner{currentQwen2}, tracks, ai), tracks
+}
+
+// waitForReenrichment waits until the latest run has finished
+func waitForReenrichment(t *testing.T, uc *AIModelMigrationUseCase) *pkgdomain.ReenrichmentRun {
+	t.Helper()
+	var run *pkgdomain.ReenrichmentRun
+	require.Eventually(t, func() bool {
+		var err error
+		run, err = uc.Reenrichment()
+		return err == nil && run.Status != pkgdomain.ReenrichmentRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	return run
+}
+
+func TestAIModelMigrationUseCase_Coverage(t *testing.T) {
+	uc, _ := newMigrationFixture(nil)
+
+	report, err := uc.Coverage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), report.Tracks)
+	assert.Equal(t, int64(1), report.Unenriched)
+	assert.Equal(t, int64(3), report.Outdated)
+	assert.Equal(t, []pkgdomain.AIModelVersion{currentQwen2}, report.Current)
+
+	require.Len(t, report.Models, 3)
+	assert.Equal(t, outdatedQwen, report.Models[0].AIModelVersion)
+	assert.Equal(t, 0.4, report.Models[0].Share)
+	assert.False(t, report.Models[0].Current)
+	current := 0
+	for _, model := range report.Models {
+		if model.Current {
+			current++
+			assert.Equal(t, currentQwen2, model.AIModelVersion, "the provider of the current version is filled in")
+		}
+	}
+	assert.Equal(t, 1, current)
+}
+
+func TestAIModelMigrationUseCase_Reenrichment(t *testing.T) {
+	uc, tracks := newMigrationFixture(func(_ context.Context, track *pkgdomain.Track) error {
+		assert.Nil(t, track.Metadata.AI, "outdated AI metadata is cleared before enrichment")
+		if track.ID == "track-4" {
+			return fmt.Errorf("unavailable")
+		}
+		track.Metadata.AI = &pkgdomain.TrackAIMetadata{Model: "qwen2", Version: "v2", PromptVersion: "audio-v1"}
+		return nil
+	})
+	var saved []string
+	tracks.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		track := args.Get(1).(*pkgdomain.Track)
+		assert.Equal(t, "v2", track.Metadata.AI.Version)
+		saved = append(saved, track.ID)
+	}).Return(nil)
+
+	_, err := uc.Reenrichment()
+	assert.ErrorIs(t, err, pkgdomain.ErrReenrichmentNotFound)
+
+	started, err := uc.StartReenrichment(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, pkgdomain.ReenrichmentRunning, started.Status)
+
+	run := waitForReenrichment(t, uc)
+	assert.Equal(t, pkgdomain.ReenrichmentCompleted, run.Status)
+	assert.Equal(t, 2, run.Enriched)
+	assert.Equal(t, 1, run.Failed)
+	assert.NotNil(t, run.CompletedAt)
+	assert.Equal(t, []string{"track-2", "track-3"}, saved, "current and unenriched tracks are left alone")
+}
+
+func TestAIModelMigrationUseCase_CancelReenrichment(t *testing.T) {
+	started := make(chan struct{})
+	uc, tracks := newMigrationFixture(func(ctx context.Context, _ *pkgdomain.Track) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	tracks.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	_, err := uc.CancelReenrichment()
+	assert.ErrorIs(t, err, pkgdomain.ErrReenrichmentNotFound)
+
+	_, err = uc.StartReenrichment(context.Background())
+	require.NoError(t, err)
+	<-started
+	_, err = uc.StartReenrichment(context.Background())
+	assert.ErrorIs(t, err, pkgdomain.ErrReenrichmentRunning)
+
+	run, err := uc.CancelReenrichment()
+	require.NoError(t, err)
+	assert.Equal(t, pkgdomain.ReenrichmentCancelled, run.Status)
+	assert.Zero(t, run.Enriched)
+	assert.Zero(t, run.Failed, "the interrupted track is not counted as failed")
+	tracks.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -4,8 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"metadatatool/internal/domain"
 	"metadatatool/internal/pkg/config"
+	"metadatatool/internal/pkg/domain"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -36,8 +36,14 @@ func (s *AuthService) GenerateToken(ctx context.Context, user *domain.User) (str
 	return token.SignedString([]byte(s.config.JWTSecret))
 }
 
-// GenerateTokens generates access and refresh tokens
-func (s *AuthService) GenerateTokens(user *domain.User) (*domain.Tokens, error) {
+// GenerateTokens generates access and refresh tokens for a new login
+func (s *AuthService) GenerateTokens(user *domain.User) (*domain.TokenPair, error) {
+	return s.GenerateTokensInFamily(user, uuid.NewString())
+}
+
+// GenerateTokensInFamily generates access and refresh tokens belonging to an
+// existing refresh token family
+func (s *AuthService) GenerateTokensInFamily(user *domain.User, familyID string) (*domain.TokenPair, error) {
 	// Generate access token
 	accessClaims := domain.NewClaims(
 		user.ID,
@@ -45,6 +51,9 @@ func (s *AuthService) GenerateTokens(user *domain.User) (*domain.Tokens, error)
 		user.Permissions,
 		time.Now().Add(s.config.AccessTokenTTL),
 	)
+	accessClaims.TokenType = domain.TokenTypeAccess
+	accessClaims.FamilyID = familyID
+	accessClaims.ID = uuid.NewString()
 	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
 	accessTokenString, err := accessToken.SignedString([]byte(s.config.JWTSecret))
 	if err != nil {
@@ -52,27 +61,34 @@ func (s *AuthService) GenerateTokens(user *domain.User) (*domain.Tokens, error)
 	}
 
 	// Generate refresh token
+	refreshExpiresAt := time.Now().Add(s.config.RefreshTokenTTL)
 	refreshClaims := domain.NewClaims(
 		user.ID,
 		user.Role,
 		user.Permissions,
-		time.Now().Add(s.config.RefreshTokenTTL),
+		refreshExpiresAt,
 	)
+	refreshClaims.TokenType = domain.TokenTypeRefresh
+	refreshClaims.FamilyID = familyID
+	refreshClaims.ID = uuid.NewString()
 	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
 	refreshTokenString, err := refreshToken.SignedString([]byte(s.config.JWTSecret))
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
 
-	return &domain.Tokens{
-		AccessToken:  accessTokenString,
-		RefreshToken: refreshTokenString,
+	return &domain.TokenPair{
+		AccessToken:      accessTokenString,
+		RefreshToken:     refreshTokenString,
+		FamilyID:         familyID,
+		RefreshTokenID:   refreshClaims.ID,
+		RefreshExpiresAt: refreshExpiresAt,
 	}, nil
 }
 
 // ValidateToken validates a JWT token and returns the claims
-func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*domain.TokenClaims, error) {
-	claims := &domain.TokenClaims{}
+func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*domain.Claims, error) {
+	claims := &domain.Claims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, domain.ErrInvalidToken
@@ -91,7 +107,7 @@ func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*d
 		return nil, domain.ErrInvalidToken
 	}
 
-	if claims, ok := token.Claims.(*domain.TokenClaims); ok {
+	if claims, ok := token.Claims.(*domain.Claims); ok {
 		return claims, nil
 	}
 
@@ -3,8 +3,7 @@ package usecase
 import (
 	"context"
 	"fmt"
-	"metadatatool/internal/domain"
-	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/domain"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -122,12 +121,12 @@ func (m *MockAuthService) GenerateToken(ctx context.Context, user *domain.User)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockAuthService) ValidateToken(ctx context.Context, token string) (*domain.TokenClaims, error) {
+func (m *MockAuthService) ValidateToken(ctx context.Context, token string) (*domain.Claims, error) {
 	args := m.Called(ctx, token)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*domain.TokenClaims), args.Error(1)
+	return args.Get(0).(*domain.Claims), args.Error(1)
 }
 
 func (m *MockAuthService) HashPassword(password string) (string, error) {
@@ -140,12 +139,12 @@ func (m *MockAuthService) VerifyPassword(hashedPassword, password string) error
 	return args.Error(0)
 }
 
-func (m *MockAuthService) GenerateTokens(user *domain.User) (*domain.Tokens, error) {
+func (m *MockAuthService) GenerateTokens(user *domain.User) (*domain.TokenPair, error) {
 	args := m.Called(user)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*domain.Tokens), args.Error(1)
+	return args.Get(0).(*domain.TokenPair), args.Error(1)
 }
 
 func (m *MockAuthService) GenerateAPIKey() (string, error) {
@@ -192,11 +191,9 @@ func TestAuthUseCase_ValidateToken(t *testing.T) {
 	user := createTestUser()
 
 	t.Run("valid token", func(t *testing.T) {
-		claims := &domain.TokenClaims{
-			Claims: domain.Claims{
-				UserID: user.ID,
-				Role:   user.Role,
-			},
+		claims := &domain.Claims{
+			UserID: user.ID,
+			Role:   user.Role,
 		}
 
 		useCase.authService.(*MockAuthService).On("ValidateToken", mock.Anything, "test-token").Return(claims, nil)
@@ -267,26 +264,26 @@ func TestAuthUseCase_HasPermission(t *testing.T) {
 
 	testCases := []struct {
 		name       string
-		role       pkgdomain.Role
-		permission pkgdomain.Permission
+		role       domain.Role
+		permission domain.Permission
 		hasAccess  bool
 	}{
 		{
 			name:       "admin has all permissions",
-			role:       pkgdomain.RoleAdmin,
-			permission: pkgdomain.PermissionCreateTrack,
+			role:       domain.RoleAdmin,
+			permission: domain.PermissionCreateTrack,
 			hasAccess:  true,
 		},
 		{
 			name:       "user has basic permissions",
-			role:       pkgdomain.RoleUser,
-			permission: pkgdomain.PermissionReadTrack,
+			role:       domain.RoleUser,
+			permission: domain.PermissionReadTrack,
 			hasAccess:  true,
 		},
 		{
 			name:       "guest has limited permissions",
-			role:       pkgdomain.RoleGuest,
-			permission: pkgdomain.PermissionCreateTrack,
+			role:       domain.RoleGuest,
+			permission: domain.PermissionCreateTrack,
 			hasAccess:  false,
 		},
 	}
@@ -4,8 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"metadatatool/internal/domain"
-	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/domain"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,15 +22,17 @@ type AuthUseCaseInterface interface {
 	RevokeAllSessions(ctx context.Context, userID string) error
 	GetSession(ctx context.Context, sessionID string) (*domain.Session, error)
 	GenerateAPIKey(ctx context.Context, userID string) (string, error)
-	HasPermission(user *domain.User, permission pkgdomain.Permission) bool
+	HasPermission(user *domain.User, permission domain.Permission) bool
 	CreateSession(ctx context.Context, session *domain.Session) error
 }
 
 // AuthUseCase handles authentication operations
 type AuthUseCase struct {
-	userRepo    domain.UserRepository
-	sessionRepo domain.SessionStore
-	authService domain.AuthService
+	userRepo      domain.UserRepository
+	sessionRepo   domain.SessionStore
+	authService   domain.AuthService
+	refreshTokens domain.RefreshTokenStore
+	refreshTTL    time.Duration
 }
 
 // NewAuthUseCase creates a new auth use case
@@ -43,10 +44,19 @@ func NewAuthUseCase(userRepo domain.UserRepository, sessionRepo domain.SessionSt
 	}
 }
 
+// SetRefreshTokenStore enables refresh token rotation with reuse detection
+// and revokes token families when their sessions end. refreshTTL is the
+// lifetime of refresh tokens, which revocations must outlive. The auth
+// service must implement domain.TokenFamilyIssuer.
+func (uc *AuthUseCase) SetRefreshTokenStore(store domain.RefreshTokenStore, refreshTTL time.Duration) {
+	uc.refreshTokens = store
+	uc.refreshTTL = refreshTTL
+}
+
 // RegisterInput represents registration request data
 type RegisterInput struct {
-	Email    string      `json:"email"`
-	Password string      `json:"password"`
+	Email    string      `json:"email" binding:"required,email"`
+	Password string      `json:"password" binding:"required"`
 	Role     domain.Role `json:"role"`
 	Name     string      `json:"name"`
 }
@@ -121,18 +131,24 @@ func (uc *AuthUseCase) Login(ctx context.Context, input LoginInput) (*LoginOutpu
 	if err != nil {
 		return nil, fmt.Errorf("error generating tokens: %w", err)
 	}
+	if uc.refreshTokens != nil && tokens.FamilyID != "" {
+		if err := uc.refreshTokens.Issue(ctx, user.ID, tokens.FamilyID, tokens.RefreshTokenID, time.Until(tokens.RefreshExpiresAt)); err != nil {
+			return nil, fmt.Errorf("error storing refresh token: %w", err)
+		}
+	}
 
 	// Create session
 	session := &domain.Session{
-		ID:          uuid.New().String(),
-		UserID:      user.ID,
-		Role:        user.Role,
-		Permissions: user.Permissions,
-		UserAgent:   "", // This should be set by the handler
-		IP:          "", // This should be set by the handler
-		ExpiresAt:   time.Now().Add(24 * time.Hour),
-		CreatedAt:   time.Now(),
-		LastSeenAt:  time.Now(),
+		ID:            uuid.New().String(),
+		UserID:        user.ID,
+		Role:          user.Role,
+		Permissions:   user.Permissions,
+		TokenFamilyID: tokens.FamilyID,
+		UserAgent:     "", // This should be set by the handler
+		IP:            "", // This should be set by the handler
+		ExpiresAt:     time.Now().Add(24 * time.Hour),
+		CreatedAt:     time.Now(),
+		LastSeenAt:    time.Now(),
 	}
 
 	if err := uc.sessionRepo.Create(ctx, session); err != nil {
@@ -152,9 +168,9 @@ func (uc *AuthUseCase) CreateSession(ctx context.Context, session *domain.Sessio
 	return uc.sessionRepo.Create(ctx, session)
 }
 
-// Logout ends a user session
+// Logout ends a user session and revokes the token family issued with it
 func (uc *AuthUseCase) Logout(ctx context.Context, sessionID string) error {
-	return uc.sessionRepo.Delete(ctx, sessionID)
+	return uc.RevokeSession(ctx, sessionID)
 }
 
 // ValidateToken validates a JWT token and returns the associated user
@@ -163,6 +179,9 @@ func (uc *AuthUseCase) ValidateToken(ctx context.Context, tokenString string) (*
 	if err != nil {
 		return nil, fmt.Errorf("error validating token: %w", err)
 	}
+	if claims.TokenType == domain.TokenTypeRefresh {
+		return nil, domain.ErrInvalidToken
+	}
 
 	// Get user
 	user, err := uc.userRepo.GetByID(ctx, claims.UserID)
@@ -181,13 +200,30 @@ func (uc *AuthUseCase) GetUserSessions(ctx context.Context, userID string) ([]*d
 	return uc.sessionRepo.GetUserSessions(ctx, userID)
 }
 
-// RevokeSession revokes a specific session
+// RevokeSession revokes a specific session along with its token family
 func (uc *AuthUseCase) RevokeSession(ctx context.Context, sessionID string) error {
+	if uc.refreshTokens != nil {
+		session, err := uc.sessionRepo.Get(ctx, sessionID)
+		if err != nil {
+			return fmt.Errorf("error getting session: %w", err)
+		}
+		if session != nil && session.TokenFamilyID != "" {
+			if err := uc.refreshTokens.RevokeFamily(ctx, session.TokenFamilyID, uc.refreshTTL); err != nil {
+				return fmt.Errorf("error revoking refresh tokens: %w", err)
+			}
+		}
+	}
 	return uc.sessionRepo.Delete(ctx, sessionID)
 }
 
-// RevokeAllSessions revokes all sessions for a user
+// RevokeAllSessions revokes all sessions for a user along with every token
+// issued to the user
 func (uc *AuthUseCase) RevokeAllSessions(ctx context.Context, userID string) error {
+	if uc.refreshTokens != nil {
+		if err := uc.refreshTokens.RevokeUser(ctx, userID, uc.refreshTTL); err != nil {
+			return fmt.Errorf("error revoking refresh tokens: %w", err)
+		}
+	}
 	return uc.sessionRepo.DeleteUserSessions(ctx, userID)
 }
 
@@ -201,6 +237,9 @@ func (uc *AuthUseCase) RefreshToken(ctx context.Context, refreshToken string) (s
 		}
 		return "", "", nil, fmt.Errorf("error validating token: %w", err)
 	}
+	if uc.refreshTokens != nil && (claims.TokenType != domain.TokenTypeRefresh || claims.FamilyID == "") {
+		return "", "", nil, domain.ErrInvalidToken
+	}
 
 	// Get user
 	user, err := uc.userRepo.GetByID(ctx, claims.UserID)
@@ -214,11 +253,30 @@ func (uc *AuthUseCase) RefreshToken(ctx context.Context, refreshToken string) (s
 		return "", "", nil, domain.ErrUserNotFound
 	}
 
-	// Generate new tokens
-	tokens, err := uc.authService.GenerateTokens(user)
+	if uc.refreshTokens == nil {
+		tokens, err := uc.authService.GenerateTokens(user)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("error generating tokens: %w", err)
+		}
+		return tokens.AccessToken, tokens.RefreshToken, user, nil
+	}
+
+	// Rotate within the family; presenting an already rotated token revokes
+	// the family
+	issuer, ok := uc.authService.(domain.TokenFamilyIssuer)
+	if !ok {
+		return "", "", nil, errors.New("auth service does not support token families")
+	}
+	tokens, err := issuer.GenerateTokensInFamily(user, claims.FamilyID)
 	if err != nil {
 		return "", "", nil, fmt.Errorf("error generating tokens: %w", err)
 	}
+	if err := uc.refreshTokens.Rotate(ctx, claims.FamilyID, claims.ID, tokens.RefreshTokenID, time.Until(tokens.RefreshExpiresAt)); err != nil {
+		if errors.Is(err, domain.ErrTokenReused) || errors.Is(err, domain.ErrInvalidToken) {
+			return "", "", nil, err
+		}
+		return "", "", nil, fmt.Errorf("error rotating refresh token: %w", err)
+	}
 
 	return tokens.AccessToken, tokens.RefreshToken, user, nil
 }
@@ -252,17 +310,13 @@ func (uc *AuthUseCase) GenerateAPIKey(ctx context.Context, userID string) (strin
 }
 
 // HasPermission checks if a user has a specific permission
-func (uc *AuthUseCase) HasPermission(user *domain.User, permission pkgdomain.Permission) bool {
+func (uc *AuthUseCase) HasPermission(user *domain.User, permission domain.Permission) bool {
 	// Admin role has all permissions
 	if user.Role == domain.RoleAdmin {
 		return true
 	}
 
-	// Convert domain role to pkg domain role
-	pkgRole := pkgdomain.Role(user.Role)
-
-	// Check role permissions from pkg domain
-	permissions := pkgdomain.RolePermissions[pkgRole]
+	permissions := domain.RolePermissions[user.Role]
 	for _, p := range permissions {
 		if p == permission {
 			return true
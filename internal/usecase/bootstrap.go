@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// Default label created when a bootstrap request names none
+const (
+	DefaultLabelID   = "default"
+	DefaultLabelName = "Default"
+)
+
+// BootstrapConfig configures the bootstrap use case
+type BootstrapConfig struct {
+	// SetupToken authorizes bootstrap requests; bootstrapping is disabled
+	// when it is empty
+	SetupToken string
+	// MinPasswordLength is the minimum length of the admin password
+	MinPasswordLength int
+}
+
+// BootstrapRequest describes the initial state of a new environment
+type BootstrapRequest struct {
+	AdminEmail    string
+	AdminPassword string
+	AdminName     string
+	// LabelID and LabelName describe the default label, DefaultLabelID and
+	// DefaultLabelName when unset
+	LabelID   string
+	LabelName string
+}
+
+// BootstrapUseCase provisions a new environment: its first admin, its
+// default label and the buckets and topics it needs. It runs once; after
+// the first admin exists, only requests for that same admin are answered,
+// so infrastructure code can apply it again without changes.
+//
+// The first admin is created in the unit of work that claims the bootstrap
+// in the database, so concurrent requests, to one replica or several,
+// create a single admin.
+type BootstrapUseCase struct {
+	userRepo    domain.UserRepository
+	bootstraps  domain.BootstrapRepository
+	authService domain.AuthService
+	labels      domain.LabelRepository
+	config      BootstrapConfig
+	unitOfWork  domain.UnitOfWork
+
+	buckets domain.BucketProvisioner
+	topics  domain.TopicProvisioner
+	names   []string
+
+	now func() time.Time
+}
+
+// NewBootstrapUseCase creates a new bootstrap use case
+func NewBootstrapUseCase(userRepo domain.UserRepository, bootstraps domain.BootstrapRepository, authService domain.AuthService, labels domain.LabelRepository, config BootstrapConfig) *BootstrapUseCase {
+	return &BootstrapUseCase{
+		userRepo:    userRepo,
+		bootstraps:  bootstraps,
+		authService: authService,
+		labels:      labels,
+		config:      config,
+		now:         time.Now,
+	}
+}
+
+// SetUnitOfWork claims the bootstrap and creates the first admin in one
+// transaction, so an admin that fails to be created leaves the environment
+// unclaimed
+func (uc *BootstrapUseCase) SetUnitOfWork(uow domain.UnitOfWork) {
+	uc.unitOfWork = uow
+}
+
+// SetBuckets creates the storage buckets while bootstrapping
+func (uc *BootstrapUseCase) SetBuckets(buckets domain.BucketProvisioner) {
+	uc.buckets = buckets
+}
+
+// SetTopics creates the named queue topics while bootstrapping
+func (uc *BootstrapUseCase) SetTopics(topics domain.TopicProvisioner, names ...string) {
+	uc.topics = topics
+	uc.names = names
+}
+
+// Authorize reports whether token is the setup token
+func (uc *BootstrapUseCase) Authorize(token string) bool {
+	return uc.config.SetupToken != "" &&
+		subtle.ConstantTimeCompare([]byte(token), []byte(uc.config.SetupToken)) == 1
+}
+
+// Bootstrap creates what is missing of the environment described by req.
+// It returns domain.ErrAlreadyBootstrapped when another admin exists.
+func (uc *BootstrapUseCase) Bootstrap(ctx context.Context, req BootstrapRequest) (*domain.BootstrapResult, error) {
+	result := &domain.BootstrapResult{}
+	created, err := uc.ensureAdmin(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result.Add("admin", req.AdminEmail, created)
+
+	label := &domain.Label{ID: strings.TrimSpace(req.LabelID), Name: strings.TrimSpace(req.LabelName)}
+	if label.ID == "" {
+		label.ID = DefaultLabelID
+	}
+	if label.Name == "" {
+		label.Name = DefaultLabelName
+	}
+	created, err = uc.ensureLabel(ctx, label)
+	if err != nil {
+		return nil, err
+	}
+	result.Add("label", label.ID, created)
+
+	if uc.buckets != nil {
+		buckets, err := uc.buckets.EnsureBuckets(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(buckets))
+		for name := range buckets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			result.Add("bucket", name, buckets[name])
+		}
+	}
+
+	for _, name := range uc.names {
+		if name == "" {
+			continue
+		}
+		created, err := uc.topics.EnsureTopic(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("error creating topic %s: %w", name, err)
+		}
+		result.Add("topic", name, created)
+	}
+
+	return result, nil
+}
+
+// ensureAdmin creates the admin unless it exists, and refuses when a
+// different admin does
+func (uc *BootstrapUseCase) ensureAdmin(ctx context.Context, req BootstrapRequest) (bool, error) {
+	user, err := uc.userRepo.GetByEmail(ctx, req.AdminEmail)
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		return false, fmt.Errorf("error checking existing user: %w", err)
+	}
+	if user != nil {
+		if user.Role != domain.RoleAdmin {
+			return false, domain.ErrEmailTaken
+		}
+		return false, nil
+	}
+
+	exists, err := uc.bootstraps.AdminExists(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error checking for an admin: %w", err)
+	}
+	if exists {
+		return false, domain.ErrAlreadyBootstrapped
+	}
+
+	if len(req.AdminPassword) < uc.config.MinPasswordLength {
+		return false, domain.ErrWeakPassword
+	}
+	hashedPassword, err := uc.authService.HashPassword(req.AdminPassword)
+	if err != nil {
+		return false, fmt.Errorf("error hashing password: %w", err)
+	}
+	now := uc.now()
+	admin := &domain.User{
+		Email:     req.AdminEmail,
+		Password:  hashedPassword,
+		Name:      req.AdminName,
+		Role:      domain.RoleAdmin,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	err = inUnitOfWork(ctx, uc.unitOfWork, func(ctx context.Context) error {
+		if err := uc.bootstraps.Claim(ctx, req.AdminEmail); err != nil {
+			return err
+		}
+		if err := uc.userRepo.Create(ctx, admin); err != nil {
+			return fmt.Errorf("error creating admin: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, domain.ErrAlreadyBootstrapped) {
+		// A concurrent bootstrap claimed the environment first; it was the
+		// same request when it created this admin
+		user, getErr := uc.userRepo.GetByEmail(domain.WithPrimaryReads(ctx), req.AdminEmail)
+		if getErr == nil && user != nil && user.Role == domain.RoleAdmin {
+			return false, nil
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ensureLabel creates the label unless it exists, and reports whether it
+// was created. A label created concurrently by the same request counts as
+// existing.
+func (uc *BootstrapUseCase) ensureLabel(ctx context.Context, label *domain.Label) (bool, error) {
+	_, err := uc.labels.GetByID(ctx, label.ID)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, domain.ErrLabelNotFound) {
+		return false, err
+	}
+
+	label.CreatedAt = uc.now()
+	if err := uc.labels.Create(ctx, label); err != nil {
+		if _, getErr := uc.labels.GetByID(domain.WithPrimaryReads(ctx), label.ID); getErr == nil {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
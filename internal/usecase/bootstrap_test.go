@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryLabelRepository keeps labels in memory
+type memoryLabelRepository struct {
+	labels map[string]*domain.Label
+}
+
+func (r *memoryLabelRepository) Create(_ context.Context, label *domain.Label) error {
+	r.labels[label.ID] = label
+	return nil
+}
+
+func (r *memoryLabelRepository) GetByID(_ context.Context, id string) (*domain.Label, error) {
+	if label, ok := r.labels[id]; ok {
+		return label, nil
+	}
+	return nil, domain.ErrLabelNotFound
+}
+
+func (r *memoryLabelRepository) List(_ context.Context) ([]*domain.Label, error) {
+	var out []*domain.Label
+	for _, label := range r.labels {
+		out = append(out, label)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+// memoryBootstrapRepository claims the bootstrap in memory
+type memoryBootstrapRepository struct {
+	admin     bool
+	claimedBy string
+}
+
+func (r *memoryBootstrapRepository) AdminExists(_ context.Context) (bool, error) {
+	return r.admin, nil
+}
+
+func (r *memoryBootstrapRepository) Claim(_ context.Context, adminEmail string) error {
+	if r.claimedBy != "" {
+		return domain.ErrAlreadyBootstrapped
+	}
+	r.claimedBy = adminEmail
+	return nil
+}
+
+// fakeProvisioner records the buckets and topics it was asked to create
+type fakeProvisioner struct {
+	existing map[string]bool
+}
+
+func (p *fakeProvisioner) EnsureBuckets(_ context.Context) (map[string]bool, error) {
+	created := !p.existing["media"]
+	p.existing["media"] = true
+	return map[string]bool{"media": created}, nil
+}
+
+func (p *fakeProvisioner) EnsureTopic(_ context.Context, name string) (bool, error) {
+	created := !p.existing[name]
+	p.existing[name] = true
+	return created, nil
+}
+
+func setupBootstrap() (*BootstrapUseCase, *MockUserRepository, *memoryBootstrapRepository, *memoryLabelRepository) {
+	userRepo := new(MockUserRepository)
+	authService := new(MockAuthService)
+	authService.On("HashPassword", "correct horse").Return("admin-hash", nil)
+	labels := &memoryLabelRepository{labels: map[string]*domain.Label{}}
+	bootstraps := &memoryBootstrapRepository{}
+
+	uc := NewBootstrapUseCase(userRepo, bootstraps, authService, labels, BootstrapConfig{SetupToken: "setup", MinPasswordLength: 8})
+	provisioner := &fakeProvisioner{existing: map[string]bool{"jobs-low": true}}
+	uc.SetBuckets(provisioner)
+	uc.SetTopics(provisioner, "jobs-high", "jobs-low", "")
+	return uc, userRepo, bootstraps, labels
+}
+
+func TestBootstrapUseCase_Authorize(t *testing.T) {
+	uc, _, _, _ := setupBootstrap()
+	assert.True(t, uc.Authorize("setup"))
+	assert.False(t, uc.Authorize("wrong"))
+	assert.False(t, uc.Authorize(""))
+
+	disabled := NewBootstrapUseCase(nil, nil, nil, nil, BootstrapConfig{})
+	assert.False(t, disabled.Authorize(""))
+}
+
+func TestBootstrapUseCase_Bootstrap(t *testing.T) {
+	uc, userRepo, bootstraps, labels := setupBootstrap()
+	ctx := context.Background()
+	req := BootstrapRequest{AdminEmail: "admin@example.com", AdminPassword: "correct horse", AdminName: "Admin"}
+
+	var admin *domain.User
+	userRepo.On("GetByEmail", mock.Anything, req.AdminEmail).Return(nil, nil).Once()
+	userRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		admin = args.Get(1).(*domain.User)
+	}).Return(nil).Once()
+
+	result, err := uc.Bootstrap(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ProvisionedResource{
+		{Kind: "admin", Name: "admin@example.com", Status: domain.ResourceCreated},
+		{Kind: "label", Name: DefaultLabelID, Status: domain.ResourceCreated},
+		{Kind: "bucket", Name: "media", Status: domain.ResourceCreated},
+		{Kind: "topic", Name: "jobs-high", Status: domain.ResourceCreated},
+		{Kind: "topic", Name: "jobs-low", Status: domain.ResourceExisting},
+	}, result.Resources)
+	require.NotNil(t, admin)
+	assert.Equal(t, domain.RoleAdmin, admin.Role)
+	assert.Equal(t, "admin-hash", admin.Password)
+	assert.Equal(t, DefaultLabelName, labels.labels[DefaultLabelID].Name)
+	assert.Equal(t, req.AdminEmail, bootstraps.claimedBy)
+
+	// Applying the same request again changes nothing
+	userRepo.On("GetByEmail", mock.Anything, req.AdminEmail).Return(admin, nil).Once()
+	result, err = uc.Bootstrap(ctx, req)
+	require.NoError(t, err)
+	for _, resource := range result.Resources {
+		assert.Equal(t, domain.ResourceExisting, resource.Status, resource.Kind)
+	}
+	userRepo.AssertExpectations(t)
+}
+
+func TestBootstrapUseCase_RefusesSecondAdmin(t *testing.T) {
+	uc, userRepo, bootstraps, labels := setupBootstrap()
+	bootstraps.admin = true
+	userRepo.On("GetByEmail", mock.Anything, "other@example.com").Return(nil, nil)
+
+	_, err := uc.Bootstrap(context.Background(), BootstrapRequest{AdminEmail: "other@example.com", AdminPassword: "correct horse"})
+	assert.ErrorIs(t, err, domain.ErrAlreadyBootstrapped)
+	assert.Empty(t, labels.labels)
+	userRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestBootstrapUseCase_Validation(t *testing.T) {
+	uc, userRepo, _, _ := setupBootstrap()
+	user := createTestUser()
+	userRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	userRepo.On("GetByEmail", mock.Anything, "admin@example.com").Return(nil, nil)
+
+	_, err := uc.Bootstrap(context.Background(), BootstrapRequest{AdminEmail: user.Email, AdminPassword: "correct horse"})
+	assert.ErrorIs(t, err, domain.ErrEmailTaken)
+
+	_, err = uc.Bootstrap(context.Background(), BootstrapRequest{AdminEmail: "admin@example.com", AdminPassword: "short"})
+	assert.ErrorIs(t, err, domain.ErrWeakPassword)
+}
+
+func TestBootstrapUseCase_LosesClaimToConcurrentBootstrap(t *testing.T) {
+	uc, userRepo, bootstraps, _ := setupBootstrap()
+	ctx := context.Background()
+	admin := createTestUser()
+	admin.Email = "admin@example.com"
+	admin.Role = domain.RoleAdmin
+
+	// Another replica claimed the bootstrap for the same admin after this
+	// request looked the admin up
+	bootstraps.claimedBy = admin.Email
+	userRepo.On("GetByEmail", mock.Anything, admin.Email).Return(nil, nil).Once()
+	userRepo.On("GetByEmail", mock.Anything, admin.Email).Return(admin, nil).Once()
+
+	result, err := uc.Bootstrap(ctx, BootstrapRequest{AdminEmail: admin.Email, AdminPassword: "correct horse"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.ProvisionedResource{Kind: "admin", Name: admin.Email, Status: domain.ResourceExisting}, result.Resources[0])
+
+	// A different admin is refused
+	userRepo.On("GetByEmail", mock.Anything, "other@example.com").Return(nil, nil)
+	_, err = uc.Bootstrap(ctx, BootstrapRequest{AdminEmail: "other@example.com", AdminPassword: "correct horse"})
+	assert.ErrorIs(t, err, domain.ErrAlreadyBootstrapped)
+	userRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
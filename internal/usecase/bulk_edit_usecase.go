@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+
+	"github.com/google/uuid"
+)
+
+// MaxBulkEditTracks caps the number of tracks a single bulk edit may touch
+const MaxBulkEditTracks = 1000
+
+// BulkEditUseCase applies metadata patches to many tracks at once
+type BulkEditUseCase struct {
+	trackRepo domain.TrackRepository
+	jobRepo   domain.BulkEditJobRepository
+	workflow  *TrackWorkflowUseCase
+}
+
+// NewBulkEditUseCase creates a new bulk edit use case
+func NewBulkEditUseCase(trackRepo domain.TrackRepository, jobRepo domain.BulkEditJobRepository) *BulkEditUseCase {
+	return &BulkEditUseCase{
+		trackRepo: trackRepo,
+		jobRepo:   jobRepo,
+	}
+}
+
+// SetWorkflow tells the hooks of workflow about the status changes bulk
+// edits make
+func (uc *BulkEditUseCase) SetWorkflow(workflow *TrackWorkflowUseCase) {
+	uc.workflow = workflow
+}
+
+// Submit validates a bulk edit request and resolves its target tracks.
+// Dry runs are evaluated synchronously and never persisted; all other
+// requests are saved as a pending job and executed in the background.
+func (uc *BulkEditUseCase) Submit(ctx context.Context, req *domain.BulkEditRequest, userID string) (*domain.BulkEditJob, error) {
+	if req.Patch.IsEmpty() {
+		return nil, fmt.Errorf("%w: patch must contain at least one operation", domain.ErrInvalidInput)
+	}
+	if len(req.TrackIDs) == 0 && len(req.Filter) == 0 {
+		return nil, fmt.Errorf("%w: either track_ids or filter is required", domain.ErrInvalidInput)
+	}
+
+	trackIDs, err := uc.resolveTrackIDs(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(trackIDs) > MaxBulkEditTracks {
+		return nil, fmt.Errorf("%w: bulk edit matches %d tracks, maximum is %d", domain.ErrInvalidInput, len(trackIDs), MaxBulkEditTracks)
+	}
+
+	job := &domain.BulkEditJob{
+		ID:        uuid.New().String(),
+		Status:    domain.JobStatusPending,
+		Request:   *req,
+		Total:     len(trackIDs),
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+	}
+	job.Request.TrackIDs = trackIDs
+
+	if req.DryRun {
+		uc.execute(ctx, job)
+		return job, nil
+	}
+
+	if err := uc.jobRepo.Save(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to save bulk edit job: %w", err)
+	}
+	metrics.JobsInQueue.WithLabelValues(string(domain.JobTypeBulkEdit), "normal").Inc()
+
+	snapshot := *job
+	go uc.run(job)
+
+	return &snapshot, nil
+}
+
+// GetJob retrieves a bulk edit job by ID
+func (uc *BulkEditUseCase) GetJob(ctx context.Context, id string) (*domain.BulkEditJob, error) {
+	job, err := uc.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bulk edit job: %w", err)
+	}
+	return job, nil
+}
+
+// run executes a persisted job detached from the originating request
+func (uc *BulkEditUseCase) run(job *domain.BulkEditJob) {
+	ctx := context.Background()
+	start := time.Now()
+	defer func() {
+		metrics.JobsInQueue.WithLabelValues(string(domain.JobTypeBulkEdit), "normal").Dec()
+		metrics.JobProcessingDuration.WithLabelValues(string(domain.JobTypeBulkEdit)).Observe(time.Since(start).Seconds())
+		metrics.JobsProcessed.WithLabelValues(string(domain.JobTypeBulkEdit), string(job.Status)).Inc()
+	}()
+
+	uc.execute(ctx, job)
+}
+
+// execute applies the patch to every target track, recording one result per
+// track. Progress is saved after each track so clients can poll the job.
+func (uc *BulkEditUseCase) execute(ctx context.Context, job *domain.BulkEditJob) {
+	dryRun := job.Request.DryRun
+
+	now := time.Now()
+	job.Status = domain.JobStatusProcessing
+	job.StartedAt = &now
+	uc.save(ctx, job)
+
+	failed := 0
+	for _, id := range job.Request.TrackIDs {
+		result := uc.applyToTrack(ctx, id, &job.Request.Patch, job.CreatedBy, dryRun)
+		if result.Status == domain.BulkEditResultFailed {
+			failed++
+		}
+		job.Results = append(job.Results, result)
+		job.Processed++
+		uc.save(ctx, job)
+	}
+
+	completed := time.Now()
+	job.CompletedAt = &completed
+	job.Status = domain.JobStatusCompleted
+	if failed > 0 && failed == job.Total {
+		job.Status = domain.JobStatusFailed
+		job.Error = "all track updates failed"
+	}
+	uc.save(ctx, job)
+}
+
+func (uc *BulkEditUseCase) applyToTrack(ctx context.Context, id string, patch *domain.BulkEditPatch, actor string, dryRun bool) domain.BulkEditResult {
+	result := domain.BulkEditResult{TrackID: id}
+
+	if !dryRun {
+		// The track is written back with a version check, so read the latest
+		ctx = domain.WithPrimaryReads(ctx)
+	}
+	track, err := uc.trackRepo.GetByID(ctx, id)
+	if err != nil {
+		result.Status = domain.BulkEditResultFailed
+		result.Error = err.Error()
+		return result
+	}
+	if track == nil {
+		result.Status = domain.BulkEditResultNotFound
+		return result
+	}
+
+	from := track.Status
+	result.Changes = patch.Apply(track)
+	if len(result.Changes) == 0 {
+		result.Status = domain.BulkEditResultUnchanged
+		return result
+	}
+	if err := domain.CheckStatusTransition(from, track.Status); err != nil {
+		result.Status = domain.BulkEditResultFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	if !dryRun {
+		track.RecordProvenance(result.Changes, domain.ProvenanceManual, actor)
+		if err := uc.trackRepo.Update(ctx, track); err != nil {
+			result.Status = domain.BulkEditResultFailed
+			result.Error = err.Error()
+			return result
+		}
+	}
+
+	if !dryRun && uc.workflow != nil {
+		uc.workflow.Notify(ctx, domain.TrackStatusChange{
+			TrackID:    track.ID,
+			From:       from,
+			To:         track.Status,
+			Message:    track.StatusMsg,
+			UserID:     actor,
+			Version:    track.Version,
+			OccurredAt: track.UpdatedAt,
+		})
+	}
+
+	result.Status = domain.BulkEditResultUpdated
+	return result
+}
+
+func (uc *BulkEditUseCase) resolveTrackIDs(ctx context.Context, req *domain.BulkEditRequest) ([]string, error) {
+	if len(req.TrackIDs) > 0 {
+		seen := make(map[string]bool, len(req.TrackIDs))
+		ids := make([]string, 0, len(req.TrackIDs))
+		for _, id := range req.TrackIDs {
+			if id == "" || seen[id] {
+				continue
+			}
+			seen[id] = true
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+
+	tracks, err := uc.trackRepo.SearchByMetadata(ctx, req.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve bulk edit filter: %w", err)
+	}
+	ids := make([]string, len(tracks))
+	for i, track := range tracks {
+		ids[i] = track.ID
+	}
+	return ids, nil
+}
+
+// save persists job progress for non-dry-run jobs; failures are counted but
+// do not abort the edit since track updates have already been applied
+func (uc *BulkEditUseCase) save(ctx context.Context, job *domain.BulkEditJob) {
+	if job.Request.DryRun {
+		return
+	}
+	if err := uc.jobRepo.Save(ctx, job); err != nil {
+		metrics.JobErrors.WithLabelValues(string(domain.JobTypeBulkEdit), "save_error").Inc()
+	}
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/repository/base"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTrackRepository is a mock implementation of pkg/domain.TrackRepository
+type MockTrackRepository struct {
+	mock.Mock
+}
+
+func (m *MockTrackRepository) Create(ctx context.Context, track *pkgdomain.Track) error {
+	args := m.Called(ctx, track)
+	return args.Error(0)
+}
+
+func (m *MockTrackRepository) GetByID(ctx context.Context, id string) (*pkgdomain.Track, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pkgdomain.Track), args.Error(1)
+}
+
+func (m *MockTrackRepository) Update(ctx context.Context, track *pkgdomain.Track) error {
+	args := m.Called(ctx, track)
+	return args.Error(0)
+}
+
+func (m *MockTrackRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTrackRepository) List(ctx context.Context, filter map[string]interface{}, offset, limit int) ([]*pkgdomain.Track, error) {
+	args := m.Called(ctx, filter, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*pkgdomain.Track), args.Error(1)
+}
+
+func (m *MockTrackRepository) SearchByMetadata(ctx context.Context, query map[string]interface{}) ([]*pkgdomain.Track, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*pkgdomain.Track), args.Error(1)
+}
+
+func (m *MockTrackRepository) GetByISRC(ctx context.Context, isrc string) (*pkgdomain.Track, error) {
+	args := m.Called(ctx, isrc)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pkgdomain.Track), args.Error(1)
+}
+
+func (m *MockTrackRepository) BatchUpdate(ctx context.Context, tracks []*pkgdomain.Track) error {
+	args := m.Called(ctx, tracks)
+	return args.Error(0)
+}
+
+func newBulkEditTrack(id, genre string) *pkgdomain.Track {
+	track := &pkgdomain.Track{ID: id}
+	track.SetGenre(genre)
+	return track
+}
+
+func TestBulkEditUseCase_DryRun(t *testing.T) {
+	trackRepo := new(MockTrackRepository)
+	uc := NewBulkEditUseCase(trackRepo, base.NewInMemoryBulkEditJobRepository())
+
+	trackRepo.On("GetByID", mock.Anything, "t1").Return(newBulkEditTrack("t1", "Rock"), nil)
+	trackRepo.On("GetByID", mock.Anything, "t2").Return(newBulkEditTrack("t2", "Pop"), nil)
+	trackRepo.On("GetByID", mock.Anything, "t3").Return(nil, nil)
+
+	genre := "Pop"
+	job, err := uc.Submit(context.Background(), &pkgdomain.BulkEditRequest{
+		TrackIDs: []string{"t1", "t2", "t3", "t1"},
+		Patch:    pkgdomain.BulkEditPatch{SetGenre: &genre, AppendTags: []string{"summer"}},
+		DryRun:   true,
+	}, "user-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, pkgdomain.JobStatusCompleted, job.Status)
+	assert.Equal(t, 3, job.Total)
+	require.Len(t, job.Results, 3)
+	assert.Equal(t, pkgdomain.BulkEditResultUpdated, job.Results[0].Status)
+	assert.Len(t, job.Results[0].Changes, 2)
+	assert.Equal(t, pkgdomain.BulkEditResultUpdated, job.Results[1].Status)
+	assert.Len(t, job.Results[1].Changes, 1)
+	assert.Equal(t, pkgdomain.BulkEditResultNotFound, job.Results[2].Status)
+
+	trackRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestBulkEditUseCase_BackgroundJob(t *testing.T) {
+	trackRepo := new(MockTrackRepository)
+	jobRepo := base.NewInMemoryBulkEditJobRepository()
+	uc := NewBulkEditUseCase(trackRepo, jobRepo)
+
+	filter := map[string]interface{}{"genre": "Rock"}
+	trackRepo.On("SearchByMetadata", mock.Anything, filter).Return([]*pkgdomain.Track{newBulkEditTrack("t1", "Rock")}, nil)
+	trackRepo.On("GetByID", mock.Anything, "t1").Return(newBulkEditTrack("t1", "Rock"), nil)
+	trackRepo.On("Update", mock.Anything, mock.MatchedBy(func(track *pkgdomain.Track) bool {
+		return track.Label() == "Acme" && track.Genre() == "Rock"
+	})).Return(nil)
+
+	label := "Acme"
+	job, err := uc.Submit(context.Background(), &pkgdomain.BulkEditRequest{
+		Filter: filter,
+		Patch:  pkgdomain.BulkEditPatch{SetLabel: &label},
+	}, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, pkgdomain.JobStatusPending, job.Status)
+
+	require.Eventually(t, func() bool {
+		stored, err := uc.GetJob(context.Background(), job.ID)
+		return err == nil && stored != nil && stored.Status == pkgdomain.JobStatusCompleted
+	}, time.Second, 10*time.Millisecond)
+
+	stored, err := uc.GetJob(context.Background(), job.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.Results, 1)
+	assert.Equal(t, pkgdomain.BulkEditResultUpdated, stored.Results[0].Status)
+	trackRepo.AssertExpectations(t)
+}
+
+func TestBulkEditUseCase_RejectsEmptyPatch(t *testing.T) {
+	uc := NewBulkEditUseCase(new(MockTrackRepository), base.NewInMemoryBulkEditJobRepository())
+
+	_, err := uc.Submit(context.Background(), &pkgdomain.BulkEditRequest{TrackIDs: []string{"t1"}}, "")
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput)
+}
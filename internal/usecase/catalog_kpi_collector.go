@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// trackStatuses are always reported, so a status dropping to zero tracks
+// shows up as zero instead of disappearing
+var trackStatuses = []domain.TrackStatus{
+	domain.TrackStatusDraft,
+	domain.TrackStatusPending,
+	domain.TrackStatusProcessing,
+	domain.TrackStatusNeedsReview,
+	domain.TrackStatusApproved,
+	domain.TrackStatusDelivered,
+	domain.TrackStatusArchived,
+	domain.TrackStatusActive,
+	domain.TrackStatusInactive,
+	domain.TrackStatusRejected,
+	domain.TrackStatusDeleted,
+}
+
+// CatalogKPICollector periodically computes catalog KPIs and exports them as
+// Prometheus gauges for dashboards and alerts
+type CatalogKPICollector struct {
+	stats domain.CatalogStatsRepository
+
+	mu          sync.Mutex
+	lastExports map[string]float64
+}
+
+// NewCatalogKPICollector creates a new KPI collector
+func NewCatalogKPICollector(stats domain.CatalogStatsRepository) *CatalogKPICollector {
+	return &CatalogKPICollector{stats: stats, lastExports: make(map[string]float64)}
+}
+
+// Collect computes the KPIs once and updates the gauges
+func (c *CatalogKPICollector) Collect(ctx context.Context) error {
+	stats, err := c.stats.CatalogStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range trackStatuses {
+		metrics.CatalogTracks.WithLabelValues(string(status)).Set(float64(stats.TracksByStatus[status]))
+	}
+	for status, count := range stats.TracksByStatus {
+		if !status.IsValid() {
+			metrics.CatalogTracks.WithLabelValues(string(status)).Set(float64(count))
+		}
+	}
+	metrics.CatalogTracksNeedingReview.Set(float64(stats.NeedsReview))
+	metrics.CatalogEnrichmentBacklog.Set(float64(stats.Unenriched))
+	metrics.CatalogQualityScore.Set(stats.AverageConfidence)
+
+	c.collectExportRatio()
+	metrics.CatalogKPICollected.SetToCurrentTime()
+	return nil
+}
+
+// Run collects the KPIs now and every interval until ctx is done
+func (c *CatalogKPICollector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Collect(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to collect catalog KPIs: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collectExportRatio sets the export success ratio from the exports counted
+// since the previous collection. Intervals without exports keep the last
+// ratio.
+func (c *CatalogKPICollector) collectExportRatio() {
+	totals := counterTotals(metrics.ExportsTotal, "status")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	succeeded := totals["success"] - c.lastExports["success"]
+	failed := totals["failure"] - c.lastExports["failure"]
+	c.lastExports = totals
+	if succeeded+failed > 0 {
+		metrics.CatalogExportSuccessRatio.Set(succeeded / (succeeded + failed))
+	}
+}
+
+// counterTotals sums the counters of vec by the value of label
+func counterTotals(vec *prometheus.CounterVec, label string) map[string]float64 {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		vec.Collect(ch)
+		close(ch)
+	}()
+
+	totals := make(map[string]float64)
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			continue
+		}
+		for _, pair := range m.GetLabel() {
+			if pair.GetName() == label {
+				totals[pair.GetValue()] += m.GetCounter().GetValue()
+			}
+		}
+	}
+	return totals
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticCatalogStats struct {
+	stats *domain.CatalogStats
+}
+
+func (s *staticCatalogStats) CatalogStats(ctx context.Context) (*domain.CatalogStats, error) {
+	return s.stats, nil
+}
+
+func TestCatalogKPICollector_Collect(t *testing.T) {
+	collector := NewCatalogKPICollector(&staticCatalogStats{stats: &domain.CatalogStats{
+		TracksByStatus:    map[domain.TrackStatus]int64{domain.TrackStatusActive: 7, domain.TrackStatusDraft: 2},
+		NeedsReview:       3,
+		Unenriched:        4,
+		AverageConfidence: 0.82,
+	}})
+
+	metrics.ExportsTotal.WithLabelValues("json", "success").Add(3)
+	metrics.ExportsTotal.WithLabelValues("csv", "failure").Add(1)
+	require.NoError(t, collector.Collect(context.Background()))
+
+	assert.Equal(t, 7.0, testutil.ToFloat64(metrics.CatalogTracks.WithLabelValues("active")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.CatalogTracks.WithLabelValues("rejected")))
+	assert.Equal(t, 3.0, testutil.ToFloat64(metrics.CatalogTracksNeedingReview))
+	assert.Equal(t, 4.0, testutil.ToFloat64(metrics.CatalogEnrichmentBacklog))
+	assert.Equal(t, 0.82, testutil.ToFloat64(metrics.CatalogQualityScore))
+	assert.Equal(t, 0.75, testutil.ToFloat64(metrics.CatalogExportSuccessRatio))
+
+	// Only exports since the previous collection count
+	metrics.ExportsTotal.WithLabelValues("json", "failure").Add(1)
+	require.NoError(t, collector.Collect(context.Background()))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.CatalogExportSuccessRatio))
+
+	// Without exports the ratio is kept
+	require.NoError(t, collector.Collect(context.Background()))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.CatalogExportSuccessRatio))
+}
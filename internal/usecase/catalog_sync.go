@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// SyncMatch selects the key that pairs source tracks with target tracks
+type SyncMatch string
+
+const (
+	SyncMatchISRC SyncMatch = "isrc"
+	SyncMatchID   SyncMatch = "id"
+)
+
+// SyncAction is what a sync does with a source track
+type SyncAction string
+
+const (
+	SyncActionCreate SyncAction = "create"
+	SyncActionUpdate SyncAction = "update"
+)
+
+// SyncCatalog is a catalog tracks can be read from and written to
+type SyncCatalog interface {
+	ListAll(ctx context.Context) ([]*domain.Track, error)
+	Create(ctx context.Context, track *domain.Track) (*domain.Track, error)
+	Update(ctx context.Context, track *domain.Track) (*domain.Track, error)
+}
+
+// SyncChange describes how one target track differs from its source track
+type SyncChange struct {
+	Key     string
+	Action  SyncAction
+	Source  *domain.Track
+	Target  *domain.Track // nil when the track is created
+	Changes []domain.FieldChange
+}
+
+// SyncPlan lists the changes needed to bring the target in line with the source
+type SyncPlan struct {
+	Changes []SyncChange
+	// Unchanged counts matched tracks that are already identical
+	Unchanged int
+	// Skipped lists source tracks without a match key, e.g. without ISRC
+	Skipped []string
+}
+
+// CatalogSync compares the tracks of two catalogs and copies differences from
+// the source to the target
+type CatalogSync struct {
+	source SyncCatalog
+	target SyncCatalog
+	match  SyncMatch
+}
+
+// NewCatalogSync creates a sync from source to target pairing tracks by match
+func NewCatalogSync(source, target SyncCatalog, match SyncMatch) (*CatalogSync, error) {
+	if match != SyncMatchISRC && match != SyncMatchID {
+		return nil, fmt.Errorf("invalid match key %q, expected %s or %s", match, SyncMatchISRC, SyncMatchID)
+	}
+	return &CatalogSync{source: source, target: target, match: match}, nil
+}
+
+func (s *CatalogSync) key(track *domain.Track) string {
+	if s.match == SyncMatchID {
+		return track.ID
+	}
+	return strings.ToUpper(strings.TrimSpace(track.ISRC()))
+}
+
+// Plan loads both catalogs and returns the field-level differences
+func (s *CatalogSync) Plan(ctx context.Context) (*SyncPlan, error) {
+	sourceTracks, err := s.source.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load source tracks: %w", err)
+	}
+	targetTracks, err := s.target.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load target tracks: %w", err)
+	}
+
+	targets := make(map[string]*domain.Track, len(targetTracks))
+	for _, track := range targetTracks {
+		if key := s.key(track); key != "" {
+			targets[key] = track
+		}
+	}
+
+	plan := &SyncPlan{}
+	for _, source := range sourceTracks {
+		key := s.key(source)
+		if key == "" {
+			plan.Skipped = append(plan.Skipped, source.ID)
+			continue
+		}
+
+		target, ok := targets[key]
+		if !ok {
+			plan.Changes = append(plan.Changes, SyncChange{
+				Key:     key,
+				Action:  SyncActionCreate,
+				Source:  source,
+				Changes: domain.DiffTracks(&domain.Track{}, source),
+			})
+			continue
+		}
+
+		changes := domain.DiffTracks(target, source)
+		if len(changes) == 0 {
+			plan.Unchanged++
+			continue
+		}
+		plan.Changes = append(plan.Changes, SyncChange{
+			Key:     key,
+			Action:  SyncActionUpdate,
+			Source:  source,
+			Target:  target,
+			Changes: changes,
+		})
+	}
+
+	sort.Slice(plan.Changes, func(i, j int) bool { return plan.Changes[i].Key < plan.Changes[j].Key })
+	return plan, nil
+}
+
+// Apply writes the planned changes to the target. It continues past failed
+// tracks and returns the number applied together with the failures.
+func (s *CatalogSync) Apply(ctx context.Context, plan *SyncPlan) (int, map[string]error) {
+	applied := 0
+	failed := make(map[string]error)
+	for _, change := range plan.Changes {
+		var err error
+		switch change.Action {
+		case SyncActionCreate:
+			_, err = s.target.Create(ctx, change.Source.Clone())
+		case SyncActionUpdate:
+			updated := change.Target.Clone()
+			domain.MergeTrackFields(updated, change.Source, domain.ProvenanceImport, domain.MergePolicy{OverwriteManual: true}, "sync")
+			_, err = s.target.Update(ctx, updated)
+		}
+		if err != nil {
+			failed[change.Key] = err
+			continue
+		}
+		applied++
+	}
+	return applied, failed
+}
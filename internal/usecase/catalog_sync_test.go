@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSyncCatalog is an in-memory SyncCatalog recording writes
+type fakeSyncCatalog struct {
+	tracks  []*pkgdomain.Track
+	created []*pkgdomain.Track
+	updated []*pkgdomain.Track
+	failOn  string
+}
+
+func (c *fakeSyncCatalog) ListAll(ctx context.Context) ([]*pkgdomain.Track, error) {
+	return c.tracks, nil
+}
+
+func (c *fakeSyncCatalog) Create(ctx context.Context, track *pkgdomain.Track) (*pkgdomain.Track, error) {
+	c.created = append(c.created, track)
+	return track, nil
+}
+
+func (c *fakeSyncCatalog) Update(ctx context.Context, track *pkgdomain.Track) (*pkgdomain.Track, error) {
+	if track.ID == c.failOn {
+		return nil, errors.New("version conflict")
+	}
+	c.updated = append(c.updated, track)
+	return track, nil
+}
+
+func newSyncTrack(id, isrc, title string) *pkgdomain.Track {
+	track := &pkgdomain.Track{ID: id, Version: 1}
+	track.SetISRC(isrc)
+	track.SetTitle(title)
+	track.SetArtist("Artist")
+	return track
+}
+
+func TestCatalogSync_PlanByISRC(t *testing.T) {
+	source := &fakeSyncCatalog{tracks: []*pkgdomain.Track{
+		newSyncTrack("s1", "USAAA2400001", "Renamed"),
+		newSyncTrack("s2", "USAAA2400002", "Same"),
+		newSyncTrack("s3", "USAAA2400003", "New"),
+		newSyncTrack("s4", "", "No ISRC"),
+	}}
+	target := &fakeSyncCatalog{tracks: []*pkgdomain.Track{
+		newSyncTrack("t1", "usaaa2400001", "Original"),
+		newSyncTrack("t2", "USAAA2400002", "Same"),
+	}}
+
+	sync, err := NewCatalogSync(source, target, SyncMatchISRC)
+	require.NoError(t, err)
+	plan, err := sync.Plan(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, plan.Changes, 2)
+	assert.Equal(t, 1, plan.Unchanged)
+	assert.Equal(t, []string{"s4"}, plan.Skipped)
+
+	update := plan.Changes[0]
+	assert.Equal(t, SyncActionUpdate, update.Action)
+	assert.Equal(t, "t1", update.Target.ID)
+	assert.Contains(t, update.Changes, pkgdomain.FieldChange{Field: "title", OldValue: "Original", NewValue: "Renamed"})
+
+	assert.Equal(t, SyncActionCreate, plan.Changes[1].Action)
+	assert.Equal(t, "s3", plan.Changes[1].Source.ID)
+}
+
+func TestCatalogSync_ApplyContinuesPastFailures(t *testing.T) {
+	source := &fakeSyncCatalog{tracks: []*pkgdomain.Track{
+		newSyncTrack("a", "", "Changed A"),
+		newSyncTrack("b", "", "Changed B"),
+		newSyncTrack("c", "", "New C"),
+	}}
+	target := &fakeSyncCatalog{
+		tracks: []*pkgdomain.Track{newSyncTrack("a", "", "A"), newSyncTrack("b", "", "B")},
+		failOn: "a",
+	}
+
+	sync, err := NewCatalogSync(source, target, SyncMatchID)
+	require.NoError(t, err)
+	plan, err := sync.Plan(context.Background())
+	require.NoError(t, err)
+
+	applied, failed := sync.Apply(context.Background(), plan)
+	assert.Equal(t, 2, applied)
+	assert.Contains(t, failed, "a")
+
+	require.Len(t, target.updated, 1)
+	assert.Equal(t, "Changed B", target.updated[0].Title())
+	assert.Equal(t, 1, target.updated[0].Version, "the update is based on the target version")
+	require.Len(t, target.created, 1)
+	assert.Equal(t, "New C", target.created[0].Title())
+}
+
+func TestNewCatalogSync_RejectsUnknownMatch(t *testing.T) {
+	_, err := NewCatalogSync(&fakeSyncCatalog{}, &fakeSyncCatalog{}, "title")
+	assert.Error(t, err)
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// compliancePageSize is the number of tracks read at a time when looking
+// for a user's changes
+const compliancePageSize = 100
+
+// ComplianceUseCase exports the personal data kept about a user and erases
+// users on request. Erasing a user keeps the catalog intact: tracks they
+// edited stay, attributed to domain.DeletedUserID.
+type ComplianceUseCase struct {
+	users    domain.UserRepository
+	sessions domain.SessionStore
+	tracks   domain.TrackRepository
+}
+
+// NewComplianceUseCase creates a new compliance use case. sessions may be
+// nil when sessions are not stored.
+func NewComplianceUseCase(users domain.UserRepository, sessions domain.SessionStore, tracks domain.TrackRepository) *ComplianceUseCase {
+	return &ComplianceUseCase{
+		users:    users,
+		sessions: sessions,
+		tracks:   tracks,
+	}
+}
+
+// Export collects the profile, sessions and catalog changes of a user
+func (uc *ComplianceUseCase) Export(ctx context.Context, userID string) (*domain.UserDataExport, error) {
+	user, err := uc.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &domain.UserDataExport{
+		User:         user,
+		Sessions:     []*domain.Session{},
+		AuditEntries: []domain.AuditEntry{},
+		ExportedAt:   time.Now().UTC(),
+	}
+	if uc.sessions != nil {
+		sessions, err := uc.sessions.GetUserSessions(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sessions: %w", err)
+		}
+		export.Sessions = append(export.Sessions, sessions...)
+	}
+	err = uc.eachTrack(ctx, func(track *domain.Track) error {
+		export.AuditEntries = append(export.AuditEntries, track.AuditEntries(userID)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// Delete erases a user. Their changes to tracks are attributed to
+// domain.DeletedUserID first, so a failed deletion can be retried, then
+// their sessions and account are deleted.
+func (uc *ComplianceUseCase) Delete(ctx context.Context, userID string) error {
+	if _, err := uc.getUser(ctx, userID); err != nil {
+		return err
+	}
+
+	anonymized := 0
+	err := uc.eachTrack(ctx, func(track *domain.Track) error {
+		if len(track.AuditEntries(userID)) == 0 {
+			return nil
+		}
+		_, err := domain.PatchTrack(ctx, uc.tracks, track.ID, func(t *domain.Track) error {
+			t.AnonymizeUser(userID)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to anonymize track %s: %w", track.ID, err)
+		}
+		anonymized++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if uc.sessions != nil {
+		if err := uc.sessions.DeleteUserSessions(ctx, userID); err != nil {
+			return fmt.Errorf("failed to delete sessions: %w", err)
+		}
+	}
+	if err := uc.users.Delete(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	log.Printf("Deleted user %s and anonymized %d tracks", userID, anonymized)
+	return nil
+}
+
+func (uc *ComplianceUseCase) getUser(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := uc.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// eachTrack calls fn for every track in the catalog
+func (uc *ComplianceUseCase) eachTrack(ctx context.Context, fn func(*domain.Track) error) error {
+	for offset := 0; ; offset += compliancePageSize {
+		tracks, err := uc.tracks.List(ctx, nil, offset, compliancePageSize)
+		if err != nil {
+			return fmt.Errorf("failed to list tracks: %w", err)
+		}
+		for _, track := range tracks {
+			if err := fn(track); err != nil {
+				return err
+			}
+		}
+		if len(tracks) < compliancePageSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryUserRepository keeps users in a map
+type memoryUserRepository struct {
+	pkgdomain.UserRepository
+	users map[string]*pkgdomain.User
+}
+
+func (r *memoryUserRepository) GetByID(_ context.Context, id string) (*pkgdomain.User, error) {
+	return r.users[id], nil
+}
+
+func (r *memoryUserRepository) Delete(_ context.Context, id string) error {
+	delete(r.users, id)
+	return nil
+}
+
+// memorySessionStore keeps sessions by user
+type memorySessionStore struct {
+	pkgdomain.SessionStore
+	sessions map[string][]*pkgdomain.Session
+}
+
+func (s *memorySessionStore) GetUserSessions(_ context.Context, userID string) ([]*pkgdomain.Session, error) {
+	return s.sessions[userID], nil
+}
+
+func (s *memorySessionStore) DeleteUserSessions(_ context.Context, userID string) error {
+	delete(s.sessions, userID)
+	return nil
+}
+
+func newComplianceFixture() (*ComplianceUseCase, *memoryUserRepository, *memorySessionStore, *pkgdomain.Track) {
+	users := &memoryUserRepository{users: map[string]*pkgdomain.User{
+		"u1": {ID: "u1", Email: "ada@example.com", Name: "Ada"},
+	}}
+	sessions := &memorySessionStore{sessions: map[string][]*pkgdomain.Session{
+		"u1": {{ID: "s1", UserID: "u1", IP: "192.0.2.1"}},
+	}}
+	edited := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	track := &pkgdomain.Track{ID: "t1", Version: 1}
+	track.Metadata.Provenance = map[string]pkgdomain.FieldProvenance{
+		"title":  {Source: pkgdomain.ProvenanceManual, UpdatedAt: edited, UpdatedBy: "u1"},
+		"artist": {Source: pkgdomain.ProvenanceManual, UpdatedAt: edited, UpdatedBy: "u2"},
+	}
+
+	repo := new(MockTrackRepository)
+	repo.On("List", mock.Anything, mock.Anything, 0, compliancePageSize).Return([]*pkgdomain.Track{track}, nil)
+	repo.On("GetByID", mock.Anything, "t1").Return(track, nil)
+	repo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Track")).Return(nil)
+	return NewComplianceUseCase(users, sessions, repo), users, sessions, track
+}
+
+func TestCompliance_ExportsUserData(t *testing.T) {
+	uc, _, _, _ := newComplianceFixture()
+
+	export, err := uc.Export(context.Background(), "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "ada@example.com", export.User.Email)
+	require.Len(t, export.Sessions, 1)
+	require.Len(t, export.AuditEntries, 1)
+	assert.Equal(t, pkgdomain.AuditEntry{
+		TrackID:   "t1",
+		Field:     "title",
+		Source:    pkgdomain.ProvenanceManual,
+		UpdatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}, export.AuditEntries[0])
+
+	var archive bytes.Buffer
+	require.NoError(t, export.WriteArchive(&archive))
+	reader, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	require.NoError(t, err)
+	var names []string
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+	}
+	assert.Equal(t, []string{"profile.json", "sessions.json", "audit_entries.json"}, names)
+
+	_, err = uc.Export(context.Background(), "missing")
+	assert.ErrorIs(t, err, pkgdomain.ErrUserNotFound)
+}
+
+func TestCompliance_DeleteAnonymizesCatalogReferences(t *testing.T) {
+	uc, users, sessions, track := newComplianceFixture()
+
+	require.NoError(t, uc.Delete(context.Background(), "u1"))
+
+	assert.Empty(t, users.users)
+	assert.Empty(t, sessions.sessions)
+	assert.Equal(t, pkgdomain.DeletedUserID, track.Metadata.Provenance["title"].UpdatedBy)
+	assert.Equal(t, "u2", track.Metadata.Provenance["artist"].UpdatedBy)
+
+	assert.ErrorIs(t, uc.Delete(context.Background(), "u1"), pkgdomain.ErrUserNotFound)
+}
@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// ConfigPromotionConfig configures configuration promotion
+type ConfigPromotionConfig struct {
+	// SigningKey signs exported bundles and verifies imported ones; it is
+	// shared by the environments configuration is promoted between
+	SigningKey string
+	// Environment names this environment in exported bundles
+	Environment string
+}
+
+// ConfigPromotionUseCase exports the configuration of an environment as a
+// signed bundle and imports such bundles, so configuration tried on staging
+// can be promoted to production. Imports create and update entities but
+// never delete them.
+type ConfigPromotionUseCase struct {
+	config   ConfigPromotionConfig
+	tags     domain.TagRepository
+	rules    domain.TagRuleRepository
+	fields   domain.CustomFieldRepository
+	mappings domain.ImportMappingRepository
+	labels   domain.LabelRepository
+	runtime  *RuntimeConfigUseCase
+	now      func() time.Time
+}
+
+// NewConfigPromotionUseCase creates a new configuration promotion use case
+func NewConfigPromotionUseCase(config ConfigPromotionConfig, tags domain.TagRepository, rules domain.TagRuleRepository,
+	fields domain.CustomFieldRepository, mappings domain.ImportMappingRepository, labels domain.LabelRepository) *ConfigPromotionUseCase {
+	return &ConfigPromotionUseCase{
+		config:   config,
+		tags:     tags,
+		rules:    rules,
+		fields:   fields,
+		mappings: mappings,
+		labels:   labels,
+		now:      time.Now,
+	}
+}
+
+// SetRuntimeConfig includes the runtime settings in bundles
+func (uc *ConfigPromotionUseCase) SetRuntimeConfig(runtime *RuntimeConfigUseCase) {
+	uc.runtime = runtime
+}
+
+// Export returns the signed configuration of the environment. Custom
+// fields and import mappings are exported for labelIDs, or for every
+// label when none are given.
+func (uc *ConfigPromotionUseCase) Export(ctx context.Context, labelIDs []string, actor string) (*domain.ConfigBundle, error) {
+	bundle := &domain.ConfigBundle{
+		Version:     domain.ConfigBundleVersion,
+		Environment: uc.config.Environment,
+		ExportedAt:  uc.now().UTC(),
+		ExportedBy:  actor,
+	}
+	if uc.runtime != nil {
+		settings := uc.runtime.Current()
+		bundle.Settings = &settings
+	}
+
+	var err error
+	if bundle.Tags, err = uc.tags.List(ctx); err != nil {
+		return nil, err
+	}
+	if bundle.TagRules, err = uc.rules.List(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(labelIDs) == 0 {
+		labels, err := uc.labels.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, label := range labels {
+			labelIDs = append(labelIDs, label.ID)
+		}
+	}
+	for _, labelID := range labelIDs {
+		label := &domain.LabelConfig{LabelID: labelID}
+		if label.CustomFields, err = uc.fields.ListByLabel(ctx, labelID); err != nil {
+			return nil, err
+		}
+		if label.ImportMappings, err = uc.mappings.ListByLabel(ctx, labelID); err != nil {
+			return nil, err
+		}
+		bundle.Labels = append(bundle.Labels, label)
+	}
+
+	if bundle.Signature, err = uc.sign(bundle); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// Import verifies a bundle and creates or updates the entities it holds.
+// Tags and custom fields are matched by name, import mappings by name
+// within their label and tagging rules by ID. With dryRun the changes are
+// reported without being written.
+func (uc *ConfigPromotionUseCase) Import(ctx context.Context, bundle *domain.ConfigBundle, actor string, dryRun bool) (*domain.ConfigImportResult, error) {
+	signature, err := uc.sign(bundle)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(signature), []byte(bundle.Signature)) {
+		return nil, domain.ErrInvalidSignature
+	}
+	if errs := bundle.Validate(); len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %s: %s", domain.ErrInvalidInput, errs[0].Field, errs[0].Message)
+	}
+
+	result := &domain.ConfigImportResult{DryRun: dryRun, Source: bundle.Environment}
+	now := uc.now()
+
+	if bundle.Settings != nil && uc.runtime != nil {
+		current := uc.runtime.Current()
+		action := domain.ConfigUnchanged
+		if current.AIMinConfidence != bundle.Settings.AIMinConfidence ||
+			current.ExperimentTrafficPercent != bundle.Settings.ExperimentTrafficPercent ||
+			current.RateLimitPerMinute != bundle.Settings.RateLimitPerMinute {
+			action = domain.ConfigUpdated
+		}
+		if action == domain.ConfigUpdated && !dryRun {
+			patch := &domain.RuntimeSettingsPatch{
+				AIMinConfidence:          &bundle.Settings.AIMinConfidence,
+				ExperimentTrafficPercent: &bundle.Settings.ExperimentTrafficPercent,
+				RateLimitPerMinute:       &bundle.Settings.RateLimitPerMinute,
+			}
+			if _, err := uc.runtime.Update(ctx, patch, actor); err != nil {
+				return nil, err
+			}
+		}
+		result.Add("settings", "", "runtime", action)
+	}
+
+	for _, tag := range bundle.Tags {
+		tag.Name = domain.NormalizeTag(tag.Name)
+		existing, err := uc.tags.Get(ctx, tag.Name)
+		action := domain.ConfigCreated
+		switch {
+		case err == nil:
+			tag.CreatedAt, tag.UpdatedAt = existing.CreatedAt, existing.UpdatedAt
+			action = changeAction(existing, tag)
+		case !errors.Is(err, domain.ErrTagNotFound):
+			return nil, err
+		default:
+			tag.CreatedAt = now
+		}
+		if action != domain.ConfigUnchanged && !dryRun {
+			tag.UpdatedAt = now
+			if err := uc.tags.Save(ctx, tag); err != nil {
+				return nil, err
+			}
+		}
+		result.Add("tag", "", tag.Name, action)
+	}
+
+	for _, rule := range bundle.TagRules {
+		existing, err := uc.rules.Get(ctx, rule.ID)
+		action := domain.ConfigCreated
+		switch {
+		case err == nil:
+			rule.CreatedAt, rule.UpdatedAt = existing.CreatedAt, existing.UpdatedAt
+			action = changeAction(existing, rule)
+		case !errors.Is(err, domain.ErrTagRuleNotFound):
+			return nil, err
+		default:
+			rule.CreatedAt = now
+		}
+		if action != domain.ConfigUnchanged && !dryRun {
+			rule.UpdatedAt = now
+			if err := uc.rules.Save(ctx, rule); err != nil {
+				return nil, err
+			}
+		}
+		result.Add("tag_rule", "", rule.ID, action)
+	}
+
+	for _, label := range bundle.Labels {
+		for _, field := range label.CustomFields {
+			field.LabelID = label.LabelID
+			existing, err := uc.fields.Get(ctx, label.LabelID, field.Name)
+			action := domain.ConfigCreated
+			switch {
+			case err == nil:
+				field.CreatedAt, field.UpdatedAt = existing.CreatedAt, existing.UpdatedAt
+				action = changeAction(existing, field)
+			case !errors.Is(err, domain.ErrCustomFieldNotFound):
+				return nil, err
+			default:
+				field.CreatedAt = now
+			}
+			if action != domain.ConfigUnchanged && !dryRun {
+				field.UpdatedAt = now
+				if err := uc.fields.Save(ctx, field); err != nil {
+					return nil, err
+				}
+			}
+			result.Add("custom_field", label.LabelID, field.Name, action)
+		}
+
+		for _, mapping := range label.ImportMappings {
+			mapping.LabelID = label.LabelID
+			existing, err := uc.mappings.GetByName(ctx, label.LabelID, mapping.Name)
+			action := domain.ConfigCreated
+			switch {
+			case err == nil:
+				// The mapping keeps its ID in this environment
+				mapping.ID = existing.ID
+				mapping.CreatedAt, mapping.UpdatedAt = existing.CreatedAt, existing.UpdatedAt
+				action = changeAction(existing, mapping)
+			case !errors.Is(err, domain.ErrImportMappingNotFound):
+				return nil, err
+			default:
+				mapping.CreatedAt = now
+			}
+			if action != domain.ConfigUnchanged && !dryRun {
+				mapping.UpdatedAt = now
+				if err := uc.mappings.Save(ctx, mapping); err != nil {
+					return nil, err
+				}
+			}
+			result.Add("import_mapping", label.LabelID, mapping.Name, action)
+		}
+	}
+
+	return result, nil
+}
+
+// changeAction compares an entity with the existing one it replaces
+func changeAction(existing, entity interface{}) string {
+	before, _ := json.Marshal(existing)
+	after, _ := json.Marshal(entity)
+	if bytes.Equal(before, after) {
+		return domain.ConfigUnchanged
+	}
+	return domain.ConfigUpdated
+}
+
+// sign returns the signature of the bundle without its signature
+func (uc *ConfigPromotionUseCase) sign(bundle *domain.ConfigBundle) (string, error) {
+	unsigned := *bundle
+	unsigned.Signature = ""
+	payload, err := json.Marshal(&unsigned)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode bundle: %w", err)
+	}
+	mac := hmac.New(sha256.New, []byte(uc.config.SigningKey))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type promotionEnv struct {
+	uc       *ConfigPromotionUseCase
+	runtime  *RuntimeConfigUseCase
+	tags     *memoryTagRepository
+	rules    *memoryTagRuleRepository
+	fields   *memoryCustomFieldRepository
+	mappings *memoryImportMappingRepository
+}
+
+func newPromotionEnv(name string) *promotionEnv {
+	env := &promotionEnv{
+		runtime:  NewRuntimeConfigUseCase(&memoryRuntimeConfigStore{}, pkgdomain.RuntimeSettings{AIMinConfidence: 0.85}),
+		tags:     &memoryTagRepository{tags: map[string]*pkgdomain.Tag{}},
+		rules:    &memoryTagRuleRepository{rules: map[string]*pkgdomain.TagRule{}},
+		fields:   &memoryCustomFieldRepository{fields: map[string]*pkgdomain.CustomFieldDefinition{}},
+		mappings: &memoryImportMappingRepository{mappings: map[string]*pkgdomain.ImportMapping{}},
+	}
+	labels := &memoryLabelRepository{labels: map[string]*pkgdomain.Label{"acme": {ID: "acme", Name: "Acme"}}}
+	env.uc = NewConfigPromotionUseCase(ConfigPromotionConfig{SigningKey: "shared-key", Environment: name},
+		env.tags, env.rules, env.fields, env.mappings, labels)
+	env.uc.SetRuntimeConfig(env.runtime)
+	return env
+}
+
+// transfer sends the bundle through JSON like an export downloaded and
+// uploaded again
+func transfer(t *testing.T, bundle *pkgdomain.ConfigBundle) *pkgdomain.ConfigBundle {
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+	var out pkgdomain.ConfigBundle
+	require.NoError(t, json.Unmarshal(data, &out))
+	return &out
+}
+
+func TestConfigPromotionUseCase_PromotesConfiguration(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	staging := newPromotionEnv("staging")
+	confidence := 0.9
+	_, err := staging.runtime.Update(ctx, &pkgdomain.RuntimeSettingsPatch{AIMinConfidence: &confidence}, "admin")
+	require.NoError(t, err)
+	staging.tags.tags["techno"] = &pkgdomain.Tag{Name: "techno", Description: "Four to the floor", CreatedAt: created}
+	staging.rules.rules["r1"] = &pkgdomain.TagRule{ID: "r1", Tag: "fast", CreatedAt: created,
+		Conditions: []pkgdomain.TagCondition{{Field: "bpm", Op: pkgdomain.TagRuleGte, Value: 140}}}
+	staging.fields.fields["acme/catalog_code"] = &pkgdomain.CustomFieldDefinition{LabelID: "acme", Name: "catalog_code",
+		Type: pkgdomain.CustomFieldString, CreatedAt: created}
+	staging.mappings.mappings["m-staging"] = &pkgdomain.ImportMapping{ID: "m-staging", LabelID: "acme", Name: "distrokid",
+		Columns: []pkgdomain.ImportColumn{{Column: "Title", Field: "title"}}, CreatedAt: created}
+
+	bundle, err := staging.uc.Export(ctx, nil, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, "staging", bundle.Environment)
+	require.Len(t, bundle.Labels, 1)
+	assert.NotEmpty(t, bundle.Signature)
+
+	// Production already has the mapping under another ID
+	prod := newPromotionEnv("production")
+	prod.mappings.mappings["m-prod"] = &pkgdomain.ImportMapping{ID: "m-prod", LabelID: "acme", Name: "distrokid", CreatedAt: created}
+
+	preview, err := prod.uc.Import(ctx, transfer(t, bundle), "admin", true)
+	require.NoError(t, err)
+	assert.True(t, preview.DryRun)
+	assert.Equal(t, "staging", preview.Source)
+	assert.Empty(t, prod.tags.tags)
+	assert.Equal(t, 0.85, prod.runtime.Current().AIMinConfidence)
+
+	result, err := prod.uc.Import(ctx, transfer(t, bundle), "admin", false)
+	require.NoError(t, err)
+	assert.Equal(t, preview.Changes, result.Changes)
+	assert.Equal(t, []pkgdomain.ConfigChange{
+		{Kind: "settings", Name: "runtime", Action: pkgdomain.ConfigUpdated},
+		{Kind: "tag", Name: "techno", Action: pkgdomain.ConfigCreated},
+		{Kind: "tag_rule", Name: "r1", Action: pkgdomain.ConfigCreated},
+		{Kind: "custom_field", LabelID: "acme", Name: "catalog_code", Action: pkgdomain.ConfigCreated},
+		{Kind: "import_mapping", LabelID: "acme", Name: "distrokid", Action: pkgdomain.ConfigUpdated},
+	}, result.Changes)
+	assert.Equal(t, 0.9, prod.runtime.Current().AIMinConfidence)
+	assert.Equal(t, "Four to the floor", prod.tags.tags["techno"].Description)
+	assert.Len(t, prod.mappings.mappings["m-prod"].Columns, 1)
+	assert.NotContains(t, prod.mappings.mappings, "m-staging")
+
+	// Importing the same bundle again changes nothing
+	again, err := prod.uc.Import(ctx, transfer(t, bundle), "admin", false)
+	require.NoError(t, err)
+	for _, change := range again.Changes {
+		assert.Equal(t, pkgdomain.ConfigUnchanged, change.Action, change.Kind)
+	}
+}
+
+func TestConfigPromotionUseCase_RejectsTamperedBundles(t *testing.T) {
+	ctx := context.Background()
+	staging := newPromotionEnv("staging")
+	staging.tags.tags["techno"] = &pkgdomain.Tag{Name: "techno"}
+	bundle, err := staging.uc.Export(ctx, nil, "admin")
+	require.NoError(t, err)
+
+	tampered := transfer(t, bundle)
+	tampered.Tags[0].Description = "changed"
+	_, err = newPromotionEnv("production").uc.Import(ctx, tampered, "admin", false)
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidSignature)
+
+	other := newPromotionEnv("production")
+	other.uc.config.SigningKey = "other-key"
+	_, err = other.uc.Import(ctx, transfer(t, bundle), "admin", false)
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidSignature)
+
+	invalid := transfer(t, bundle)
+	invalid.Version = 99
+	invalid.Signature, err = staging.uc.sign(invalid)
+	require.NoError(t, err)
+	_, err = newPromotionEnv("production").uc.Import(ctx, invalid, "admin", false)
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput)
+}
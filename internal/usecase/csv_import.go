@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/google/uuid"
+)
+
+// MaxImportRows caps the number of rows of one CSV import
+const MaxImportRows = 10000
+
+// ImportRowError lists why a CSV row was not imported. Row is the line
+// number of the row in the file, counting the header as line 1.
+type ImportRowError struct {
+	Row    int                      `json:"row"`
+	Errors []domain.ValidationError `json:"errors"`
+}
+
+// ImportDuplicate warns that the track of a CSV row probably duplicates
+// catalog tracks or the tracks of earlier rows. The track is imported all
+// the same.
+type ImportDuplicate struct {
+	Row        int                         `json:"row"`
+	TrackID    string                      `json:"track_id"`
+	Candidates []domain.DuplicateCandidate `json:"candidates"`
+}
+
+// ImportResult reports the outcome of a CSV import
+type ImportResult struct {
+	MappingID string `json:"mapping_id"`
+	DryRun    bool   `json:"dry_run,omitempty"`
+	Rows      int    `json:"rows"`
+	// Created counts the tracks created, or that would be in a dry run
+	Created int              `json:"created"`
+	Tracks  []*domain.Track  `json:"tracks"`
+	Errors  []ImportRowError `json:"errors,omitempty"`
+	// Duplicates warns about imported tracks that are probable duplicates
+	Duplicates []ImportDuplicate `json:"duplicates,omitempty"`
+}
+
+// CSVImportUseCase imports distributor CSV files through the mapping
+// templates labels keep for them
+type CSVImportUseCase struct {
+	mappings   domain.ImportMappingRepository
+	tracks     domain.TrackRepository
+	duplicates *DuplicateUseCase
+	fields     *CustomFieldUseCase
+	tags       *TagUseCase
+	validator  domain.Validator
+	now        func() time.Time
+}
+
+// NewCSVImportUseCase creates a new CSV import use case
+func NewCSVImportUseCase(mappings domain.ImportMappingRepository, tracks domain.TrackRepository) *CSVImportUseCase {
+	return &CSVImportUseCase{
+		mappings:   mappings,
+		tracks:     tracks,
+		duplicates: NewDuplicateUseCase(tracks),
+		validator:  domain.NewTrackValidator(),
+		now:        time.Now,
+	}
+}
+
+// SetCustomFields checks the custom fields of imported rows against the
+// schema of the label with fields
+func (uc *CSVImportUseCase) SetCustomFields(fields *CustomFieldUseCase) {
+	uc.fields = fields
+}
+
+// SetTags applies the tagging rules to imported rows and registers their
+// tags with tags
+func (uc *CSVImportUseCase) SetTags(tags *TagUseCase) {
+	uc.tags = tags
+}
+
+// ListMappings returns a label's mappings
+func (uc *CSVImportUseCase) ListMappings(ctx context.Context, labelID string) ([]*domain.ImportMapping, error) {
+	return uc.mappings.ListByLabel(ctx, labelID)
+}
+
+// GetMapping returns a label's mapping by ID
+func (uc *CSVImportUseCase) GetMapping(ctx context.Context, labelID, id string) (*domain.ImportMapping, error) {
+	return uc.mappings.GetByID(ctx, labelID, id)
+}
+
+// SaveMapping stores a validated mapping for a label. Without an ID a new
+// mapping is created; with one the label's mapping is replaced. Names are
+// unique per label.
+func (uc *CSVImportUseCase) SaveMapping(ctx context.Context, labelID string, mapping *domain.ImportMapping) error {
+	now := uc.now()
+	mapping.LabelID = labelID
+	mapping.UpdatedAt = now
+	if mapping.ID == "" {
+		mapping.ID = uuid.New().String()
+		mapping.CreatedAt = now
+	} else {
+		existing, err := uc.mappings.GetByID(ctx, labelID, mapping.ID)
+		if err != nil {
+			return err
+		}
+		mapping.CreatedAt = existing.CreatedAt
+	}
+
+	named, err := uc.mappings.GetByName(ctx, labelID, mapping.Name)
+	switch {
+	case err == nil && named.ID != mapping.ID:
+		return fmt.Errorf("%w: the label already has a mapping named %q", domain.ErrInvalidInput, mapping.Name)
+	case err != nil && !errors.Is(err, domain.ErrImportMappingNotFound):
+		return err
+	}
+	return uc.mappings.Save(ctx, mapping)
+}
+
+// DeleteMapping removes a label's mapping
+func (uc *CSVImportUseCase) DeleteMapping(ctx context.Context, labelID, id string) error {
+	return uc.mappings.Delete(ctx, labelID, id)
+}
+
+// Import creates a track for every valid row of a CSV file, mapped with the
+// label's mapping named by mapping, its ID or name. Invalid rows are
+// reported and skipped. Tracks that probably duplicate catalog tracks or
+// earlier rows are imported with a warning. Dry runs map and validate every
+// row without creating anything.
+func (uc *CSVImportUseCase) Import(ctx context.Context, labelID, mapping string, r io.Reader, actor string) (*ImportResult, error) {
+	m, err := uc.mappings.GetByID(ctx, labelID, mapping)
+	if errors.Is(err, domain.ErrImportMappingNotFound) {
+		m, err = uc.mappings.GetByName(ctx, labelID, mapping)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = m.Comma()
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read the CSV header: %v", domain.ErrInvalidInput, err)
+	}
+
+	catalog, err := uc.duplicates.Catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var schema domain.CustomFieldSchema
+	if uc.fields != nil {
+		if schema, err = uc.fields.Schema(ctx, labelID); err != nil {
+			return nil, err
+		}
+	}
+	var rules []*domain.TagRule
+	if uc.tags != nil {
+		if rules, err = uc.tags.Rules(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ImportResult{MappingID: m.ID, DryRun: domain.DryRunFromContext(ctx), Tracks: []*domain.Track{}}
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+		}
+		result.Rows++
+		if result.Rows > MaxImportRows {
+			return nil, fmt.Errorf("%w: an import holds at most %d rows", domain.ErrInvalidInput, MaxImportRows)
+		}
+
+		track, errs := m.MapRow(header, row)
+		if len(errs) == 0 {
+			errs = uc.validator.Validate(track).Errors
+		}
+		if len(errs) == 0 {
+			errs = customFieldColumnErrors(m, schema.Apply(track))
+		}
+		if len(errs) > 0 {
+			result.Errors = append(result.Errors, ImportRowError{Row: line, Errors: errs})
+			continue
+		}
+
+		if uc.tags != nil {
+			track.NormalizeTags()
+			domain.ApplyTagRules(track, rules)
+		}
+		track.ID = uuid.New().String()
+		track.LabelID = labelID
+		track.Status = domain.TrackStatusPending
+		track.RecordProvenance(domain.DiffTracks(&domain.Track{}, track), domain.ProvenanceImport, actor)
+		if duplicates := domain.FindDuplicates(track, catalog); len(duplicates) > 0 {
+			result.Duplicates = append(result.Duplicates, ImportDuplicate{Row: line, TrackID: track.ID, Candidates: duplicates})
+		}
+		catalog = append(catalog, track)
+		result.Tracks = append(result.Tracks, track)
+	}
+	result.Created = len(result.Tracks)
+
+	if result.DryRun || len(result.Tracks) == 0 {
+		return result, nil
+	}
+	if uc.tags != nil {
+		seen := make(map[string]bool)
+		var tags []string
+		for _, track := range result.Tracks {
+			for _, tag := range track.Tags() {
+				if !seen[tag] {
+					seen[tag] = true
+					tags = append(tags, tag)
+				}
+			}
+		}
+		if err := uc.tags.Register(ctx, tags...); err != nil {
+			return nil, err
+		}
+	}
+	if writer, ok := uc.tracks.(domain.TrackBulkWriter); ok {
+		if err := writer.BatchCreate(ctx, result.Tracks); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	for _, track := range result.Tracks {
+		if err := uc.tracks.Create(ctx, track); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// customFieldColumnErrors names the CSV columns of custom field errors, as
+// mapped by m. Errors of fields no column provides keep their track path.
+func customFieldColumnErrors(m *domain.ImportMapping, errs []domain.ValidationError) []domain.ValidationError {
+	for i, err := range errs {
+		name := strings.TrimPrefix(err.Field, domain.CustomFieldPath)
+		for _, col := range m.Columns {
+			if col.Field == domain.CustomFieldPrefix+name {
+				errs[i].Field = col.Column
+				break
+			}
+		}
+	}
+	return errs
+}
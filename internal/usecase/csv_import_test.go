@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryImportMappingRepository keeps import mappings in memory
+type memoryImportMappingRepository struct {
+	mappings map[string]*pkgdomain.ImportMapping
+}
+
+func (r *memoryImportMappingRepository) Save(_ context.Context, mapping *pkgdomain.ImportMapping) error {
+	r.mappings[mapping.ID] = mapping
+	return nil
+}
+
+func (r *memoryImportMappingRepository) GetByID(_ context.Context, labelID, id string) (*pkgdomain.ImportMapping, error) {
+	if m, ok := r.mappings[id]; ok && m.LabelID == labelID {
+		return m, nil
+	}
+	return nil, pkgdomain.ErrImportMappingNotFound
+}
+
+func (r *memoryImportMappingRepository) GetByName(_ context.Context, labelID, name string) (*pkgdomain.ImportMapping, error) {
+	for _, m := range r.mappings {
+		if m.LabelID == labelID && m.Name == name {
+			return m, nil
+		}
+	}
+	return nil, pkgdomain.ErrImportMappingNotFound
+}
+
+func (r *memoryImportMappingRepository) ListByLabel(_ context.Context, labelID string) ([]*pkgdomain.ImportMapping, error) {
+	var out []*pkgdomain.ImportMapping
+	for _, m := range r.mappings {
+		if m.LabelID == labelID {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryImportMappingRepository) Delete(_ context.Context, labelID, id string) error {
+	if _, err := r.GetByID(context.Background(), labelID, id); err != nil {
+		return err
+	}
+	delete(r.mappings, id)
+	return nil
+}
+
+// distributorMapping maps a distributor's semicolon-separated export
+func distributorMapping() *pkgdomain.ImportMapping {
+	return &pkgdomain.ImportMapping{
+		Name:      "distributor",
+		Delimiter: ";",
+		Columns: []pkgdomain.ImportColumn{
+			{Column: "Track Title", Field: "title", Required: true},
+			{Column: "Main Artist", Field: "artist", Required: true},
+			{Column: "ISRC Code", Field: "isrc", Transforms: []pkgdomain.ImportTransform{{Type: pkgdomain.ImportTransformUpper}}},
+			{Column: "Release Date", Field: "year", Transforms: []pkgdomain.ImportTransform{{Type: pkgdomain.ImportTransformDate, Format: "DD/MM/YYYY"}}},
+			{Column: "Release Date", Field: "custom.release_date", Transforms: []pkgdomain.ImportTransform{{Type: pkgdomain.ImportTransformDate, Format: "DD/MM/YYYY"}}},
+			{Column: "Genre", Field: "genre", Transforms: []pkgdomain.ImportTransform{{
+				Type:    pkgdomain.ImportTransformLookup,
+				Values:  map[string]string{"hip hop/rap": "Hip-Hop", "dance": "Electronic"},
+				Default: "Other",
+			}}},
+			{Column: "Length", Field: "duration"},
+		},
+		Defaults: map[string]string{"label": "Night Owl Records"},
+	}
+}
+
+const distributorCSV = `Track Title;Main Artist;ISRC Code;Release Date;Genre;Length
+Midnight;The Owls;usabc2400001;31/01/2024;DANCE;3:25
+;The Owls;USABC2400002;01/02/2024;Dance;2:10
+Dawn;The Owls;USABC2400003;2024-02-01;Hip Hop/Rap;3:00
+Noon;The Owls;US-ABC-24-00004;15/03/2024;Polka;4:05
+`
+
+func newCSVImportUseCase(t *testing.T, catalog ...*pkgdomain.Track) (*CSVImportUseCase, *MockTrackRepository) {
+	tracks := new(MockTrackRepository)
+	tracks.On("List", mock.Anything, mock.Anything, 0, mock.Anything).Return(catalog, nil)
+	uc := NewCSVImportUseCase(&memoryImportMappingRepository{mappings: map[string]*pkgdomain.ImportMapping{}}, tracks)
+	require.NoError(t, uc.SaveMapping(context.Background(), "label-1", distributorMapping()))
+	return uc, tracks
+}
+
+func TestCSVImport_MapsAndTransformsRows(t *testing.T) {
+	uc, tracks := newCSVImportUseCase(t)
+	tracks.On("Create", mock.Anything, mock.AnythingOfType("*domain.Track")).Return(nil)
+
+	result, err := uc.Import(context.Background(), "label-1", "distributor", strings.NewReader(distributorCSV), "user-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, result.Rows)
+	assert.Equal(t, 2, result.Created)
+	tracks.AssertNumberOfCalls(t, "Create", 2)
+
+	midnight := result.Tracks[0]
+	assert.Equal(t, "Midnight", midnight.Title())
+	assert.Equal(t, "USABC2400001", midnight.ISRC())
+	assert.Equal(t, 2024, midnight.Year())
+	assert.Equal(t, "2024-01-31", midnight.Metadata.Additional.CustomFields["release_date"])
+	assert.Equal(t, "Electronic", midnight.Genre())
+	assert.Equal(t, 205.0, midnight.Duration())
+	assert.Equal(t, "Night Owl Records", midnight.Label())
+	assert.Equal(t, "label-1", midnight.LabelID)
+	assert.Equal(t, pkgdomain.TrackStatusPending, midnight.Status)
+
+	noon := result.Tracks[1]
+	assert.Equal(t, "USABC2400004", noon.ISRC())
+	assert.Equal(t, "Other", noon.Genre())
+
+	require.Len(t, result.Errors, 2)
+	assert.Equal(t, 3, result.Errors[0].Row)
+	assert.Equal(t, "Track Title", result.Errors[0].Errors[0].Field)
+	assert.Equal(t, 4, result.Errors[1].Row)
+	assert.Equal(t, "Release Date", result.Errors[1].Errors[0].Field)
+}
+
+func TestCSVImport_DryRunCreatesNothing(t *testing.T) {
+	uc, tracks := newCSVImportUseCase(t)
+
+	ctx := pkgdomain.WithDryRun(context.Background())
+	result, err := uc.Import(ctx, "label-1", "distributor", strings.NewReader(distributorCSV), "user-1")
+	require.NoError(t, err)
+
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 2, result.Created)
+	tracks.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCSVImport_SelectsMappingByID(t *testing.T) {
+	uc, tracks := newCSVImportUseCase(t)
+	tracks.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	mappings, err := uc.ListMappings(context.Background(), "label-1")
+	require.NoError(t, err)
+	require.Len(t, mappings, 1)
+
+	result, err := uc.Import(context.Background(), "label-1", mappings[0].ID, strings.NewReader(distributorCSV), "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, mappings[0].ID, result.MappingID)
+
+	// Mappings belong to their label
+	_, err = uc.Import(context.Background(), "label-2", "distributor", strings.NewReader(distributorCSV), "user-1")
+	assert.ErrorIs(t, err, pkgdomain.ErrImportMappingNotFound)
+}
+
+func TestCSVImport_RejectsDuplicateMappingNames(t *testing.T) {
+	uc, _ := newCSVImportUseCase(t)
+
+	err := uc.SaveMapping(context.Background(), "label-1", distributorMapping())
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput)
+
+	// Another label can reuse the name
+	assert.NoError(t, uc.SaveMapping(context.Background(), "label-2", distributorMapping()))
+}
+
+func TestCSVImport_WarnsAboutDuplicates(t *testing.T) {
+	existing := &pkgdomain.Track{ID: "existing"}
+	existing.SetTitle("Midnight (Remastered 2020)")
+	existing.SetArtist("Owls")
+	uc, tracks := newCSVImportUseCase(t, existing)
+	tracks.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	csv := distributorCSV + "Noon (Radio Edit);The Owls feat. Lark;USABC2400005;16/03/2024;Dance;3:10\n"
+	result, err := uc.Import(context.Background(), "label-1", "distributor", strings.NewReader(csv), "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Created)
+
+	// Both the catalog and earlier rows of the file are compared
+	require.Len(t, result.Duplicates, 2)
+	assert.Equal(t, 2, result.Duplicates[0].Row)
+	assert.Equal(t, "existing", result.Duplicates[0].Candidates[0].TrackID)
+	assert.Equal(t, 6, result.Duplicates[1].Row)
+	assert.Equal(t, result.Tracks[1].ID, result.Duplicates[1].Candidates[0].TrackID)
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// CustomFieldUseCase manages the custom field schemas of labels and
+// enforces them on the label's tracks
+type CustomFieldUseCase struct {
+	fields domain.CustomFieldRepository
+	now    func() time.Time
+}
+
+// NewCustomFieldUseCase creates a new custom field use case
+func NewCustomFieldUseCase(fields domain.CustomFieldRepository) *CustomFieldUseCase {
+	return &CustomFieldUseCase{fields: fields, now: time.Now}
+}
+
+// List returns a label's field definitions
+func (uc *CustomFieldUseCase) List(ctx context.Context, labelID string) ([]*domain.CustomFieldDefinition, error) {
+	return uc.fields.ListByLabel(ctx, labelID)
+}
+
+// Get returns a label's field definition by name
+func (uc *CustomFieldUseCase) Get(ctx context.Context, labelID, name string) (*domain.CustomFieldDefinition, error) {
+	return uc.fields.Get(ctx, labelID, name)
+}
+
+// Create adds a validated field definition to a label. Names are unique
+// per label.
+func (uc *CustomFieldUseCase) Create(ctx context.Context, labelID string, def *domain.CustomFieldDefinition) error {
+	_, err := uc.fields.Get(ctx, labelID, def.Name)
+	switch {
+	case err == nil:
+		return fmt.Errorf("%w: the label already has a custom field named %q", domain.ErrInvalidInput, def.Name)
+	case !errors.Is(err, domain.ErrCustomFieldNotFound):
+		return err
+	}
+	now := uc.now()
+	def.LabelID = labelID
+	def.CreatedAt = now
+	def.UpdatedAt = now
+	return uc.fields.Save(ctx, def)
+}
+
+// Update replaces a label's validated field definition. Values already
+// stored on tracks are checked against it when the tracks are next written.
+func (uc *CustomFieldUseCase) Update(ctx context.Context, labelID string, def *domain.CustomFieldDefinition) error {
+	existing, err := uc.fields.Get(ctx, labelID, def.Name)
+	if err != nil {
+		return err
+	}
+	def.LabelID = labelID
+	def.CreatedAt = existing.CreatedAt
+	def.UpdatedAt = uc.now()
+	return uc.fields.Save(ctx, def)
+}
+
+// Delete removes a label's field definition
+func (uc *CustomFieldUseCase) Delete(ctx context.Context, labelID, name string) error {
+	return uc.fields.Delete(ctx, labelID, name)
+}
+
+// Schema returns the fields a label defines. Tracks without a label have
+// no schema.
+func (uc *CustomFieldUseCase) Schema(ctx context.Context, labelID string) (domain.CustomFieldSchema, error) {
+	if labelID == "" {
+		return nil, nil
+	}
+	defs, err := uc.fields.ListByLabel(ctx, labelID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.CustomFieldSchema(defs), nil
+}
+
+// Enforce applies the schema of the track's label to its custom fields,
+// rewriting values in canonical form. The returned errors are the
+// violations; err is only set when the schema cannot be loaded.
+func (uc *CustomFieldUseCase) Enforce(ctx context.Context, track *domain.Track) ([]domain.ValidationError, error) {
+	schema, err := uc.Schema(ctx, track.LabelID)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Apply(track), nil
+}
+
+// Schemas returns the schemas of the labels of tracks, keyed by label ID
+func (uc *CustomFieldUseCase) Schemas(ctx context.Context, tracks []*domain.Track) (map[string]domain.CustomFieldSchema, error) {
+	schemas := make(map[string]domain.CustomFieldSchema)
+	for _, track := range tracks {
+		if _, ok := schemas[track.LabelID]; ok || track.LabelID == "" {
+			continue
+		}
+		schema, err := uc.Schema(ctx, track.LabelID)
+		if err != nil {
+			return nil, err
+		}
+		schemas[track.LabelID] = schema
+	}
+	return schemas, nil
+}
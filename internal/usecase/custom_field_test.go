@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCustomFieldRepository keeps custom field definitions in memory
+type memoryCustomFieldRepository struct {
+	fields map[string]*pkgdomain.CustomFieldDefinition
+}
+
+func (r *memoryCustomFieldRepository) Save(_ context.Context, def *pkgdomain.CustomFieldDefinition) error {
+	r.fields[def.LabelID+"/"+def.Name] = def
+	return nil
+}
+
+func (r *memoryCustomFieldRepository) Get(_ context.Context, labelID, name string) (*pkgdomain.CustomFieldDefinition, error) {
+	if def, ok := r.fields[labelID+"/"+name]; ok {
+		return def, nil
+	}
+	return nil, pkgdomain.ErrCustomFieldNotFound
+}
+
+func (r *memoryCustomFieldRepository) ListByLabel(_ context.Context, labelID string) ([]*pkgdomain.CustomFieldDefinition, error) {
+	var out []*pkgdomain.CustomFieldDefinition
+	for _, def := range r.fields {
+		if def.LabelID == labelID {
+			out = append(out, def)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryCustomFieldRepository) Delete(_ context.Context, labelID, name string) error {
+	if _, err := r.Get(context.Background(), labelID, name); err != nil {
+		return err
+	}
+	delete(r.fields, labelID+"/"+name)
+	return nil
+}
+
+func newCustomFieldUseCase(t *testing.T, defs ...*pkgdomain.CustomFieldDefinition) *CustomFieldUseCase {
+	uc := NewCustomFieldUseCase(&memoryCustomFieldRepository{fields: map[string]*pkgdomain.CustomFieldDefinition{}})
+	for _, def := range defs {
+		require.Empty(t, def.Validate())
+		require.NoError(t, uc.Create(context.Background(), "label-1", def))
+	}
+	return uc
+}
+
+func floatPtr(f float64) *float64 { return &f }
+
+// labelSchema defines one field of every kind of check
+func labelSchema() []*pkgdomain.CustomFieldDefinition {
+	return []*pkgdomain.CustomFieldDefinition{
+		{Name: "mood_score", Type: pkgdomain.CustomFieldNumber, Min: floatPtr(0), Max: floatPtr(10)},
+		{Name: "explicit_lyrics", Type: pkgdomain.CustomFieldBoolean, Required: true},
+		{Name: "recorded_on", Type: pkgdomain.CustomFieldDate},
+		{Name: "sync_tier", Type: pkgdomain.CustomFieldEnum, Values: []string{"gold", "silver"}},
+		{Name: "catalog_code", Type: pkgdomain.CustomFieldString, Pattern: `^NO-\d{4}$`},
+	}
+}
+
+func TestCustomFields_EnforceCanonicalizesValues(t *testing.T) {
+	uc := newCustomFieldUseCase(t, labelSchema()...)
+
+	track := &pkgdomain.Track{LabelID: "label-1"}
+	track.Metadata.Additional.CustomFields = map[string]string{
+		"mood_score":      "7.50",
+		"explicit_lyrics": "TRUE",
+		"recorded_on":     "2024-03-01",
+		"sync_tier":       "gold",
+		"catalog_code":    "NO-0042",
+		"label":           "Night Owl Records",
+	}
+	errs, err := uc.Enforce(context.Background(), track)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+	assert.Equal(t, "7.5", track.Metadata.Additional.CustomFields["mood_score"])
+	assert.Equal(t, "true", track.Metadata.Additional.CustomFields["explicit_lyrics"])
+
+	schema, err := uc.Schema(context.Background(), "label-1")
+	require.NoError(t, err)
+	typed := schema.Typed(track)
+	assert.Equal(t, 7.5, typed["mood_score"])
+	assert.Equal(t, true, typed["explicit_lyrics"])
+	assert.NotContains(t, typed, "label")
+}
+
+func TestCustomFields_EnforceRejectsInvalidValues(t *testing.T) {
+	uc := newCustomFieldUseCase(t, labelSchema()...)
+
+	track := &pkgdomain.Track{LabelID: "label-1"}
+	track.Metadata.Additional.CustomFields = map[string]string{
+		"mood_score":   "11",
+		"recorded_on":  "01/03/2024",
+		"sync_tier":    "bronze",
+		"catalog_code": "0042",
+		"undeclared":   "x",
+	}
+	errs, err := uc.Enforce(context.Background(), track)
+	require.NoError(t, err)
+
+	fields := make(map[string]string)
+	for _, e := range errs {
+		fields[strings.TrimPrefix(e.Field, pkgdomain.CustomFieldPath)] = e.Code
+	}
+	assert.Len(t, fields, 6)
+	assert.Equal(t, "required", fields["explicit_lyrics"])
+	for _, name := range []string{"mood_score", "recorded_on", "sync_tier", "catalog_code", "undeclared"} {
+		assert.Contains(t, fields, name)
+	}
+}
+
+func TestCustomFields_LabelsWithoutSchemaAcceptAnything(t *testing.T) {
+	uc := newCustomFieldUseCase(t, labelSchema()...)
+
+	track := &pkgdomain.Track{LabelID: "label-2"}
+	track.Metadata.Additional.CustomFields = map[string]string{"anything": "goes"}
+	errs, err := uc.Enforce(context.Background(), track)
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestCustomFields_CreateAndUpdate(t *testing.T) {
+	uc := newCustomFieldUseCase(t, labelSchema()...)
+	ctx := context.Background()
+
+	err := uc.Create(ctx, "label-1", &pkgdomain.CustomFieldDefinition{Name: "mood_score", Type: pkgdomain.CustomFieldInteger})
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput)
+
+	err = uc.Update(ctx, "label-1", &pkgdomain.CustomFieldDefinition{Name: "missing", Type: pkgdomain.CustomFieldInteger})
+	assert.ErrorIs(t, err, pkgdomain.ErrCustomFieldNotFound)
+
+	require.NoError(t, uc.Update(ctx, "label-1", &pkgdomain.CustomFieldDefinition{Name: "mood_score", Type: pkgdomain.CustomFieldInteger}))
+	def, err := uc.Get(ctx, "label-1", "mood_score")
+	require.NoError(t, err)
+	assert.Equal(t, pkgdomain.CustomFieldInteger, def.Type)
+	assert.False(t, def.CreatedAt.IsZero())
+
+	assert.NotEmpty(t, (&pkgdomain.CustomFieldDefinition{Name: "label", Type: pkgdomain.CustomFieldString}).Validate())
+	assert.NotEmpty(t, (&pkgdomain.CustomFieldDefinition{Name: "Bad Name", Type: pkgdomain.CustomFieldString}).Validate())
+	assert.NotEmpty(t, (&pkgdomain.CustomFieldDefinition{Name: "tier", Type: pkgdomain.CustomFieldEnum}).Validate())
+}
+
+func TestCustomFields_SearchTermsAreTyped(t *testing.T) {
+	uc := newCustomFieldUseCase(t, labelSchema()...)
+	schema, err := uc.Schema(context.Background(), "label-1")
+	require.NoError(t, err)
+
+	terms, errs := schema.SearchTerms(map[string]interface{}{"mood_score": 7.0, "explicit_lyrics": true})
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"custom.mood_score": "7", "custom.explicit_lyrics": "true"}, terms)
+
+	_, errs = schema.SearchTerms(map[string]interface{}{"mood_score": "loud", "x') OR 1=1 --": "y"})
+	assert.Len(t, errs, 2)
+
+	// Without a label every well-formed name is matched as written
+	terms, errs = pkgdomain.CustomFieldSchema(nil).SearchTerms(map[string]interface{}{"anything": "goes"})
+	assert.Empty(t, errs)
+	assert.Equal(t, "goes", terms["custom.anything"])
+}
+
+func TestCSVImport_EnforcesCustomFields(t *testing.T) {
+	uc, tracks := newCSVImportUseCase(t)
+	uc.SetCustomFields(newCustomFieldUseCase(t,
+		&pkgdomain.CustomFieldDefinition{Name: "release_date", Type: pkgdomain.CustomFieldDate, Required: true}))
+	tracks.On("Create", mock.Anything, mock.AnythingOfType("*domain.Track")).Return(nil)
+
+	csv := "Track Title;Main Artist;Release Date\nMidnight;The Owls;31/01/2024\nDawn;The Owls;\n"
+	result, err := uc.Import(context.Background(), "label-1", "distributor", strings.NewReader(csv), "user-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Created)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, 3, result.Errors[0].Row)
+	assert.Equal(t, "Release Date", result.Errors[0].Errors[0].Field)
+	assert.Equal(t, "required", result.Errors[0].Errors[0].Code)
+}
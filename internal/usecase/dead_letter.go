@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+const (
+	// defaultDeadLetterPageSize is the page size when none is requested
+	defaultDeadLetterPageSize = 50
+	// maxDeadLetterPageSize bounds a page of dead letters
+	maxDeadLetterPageSize = 500
+	// maxDeadLetterReplay bounds the dead letters replayed by one request
+	maxDeadLetterReplay = 1000
+)
+
+// DeadLetterUseCase lets admins inspect, replay and purge dead letters.
+// Replays and purges are written to the audit log with the acting user.
+type DeadLetterUseCase struct {
+	queue domain.DeadLetterQueue
+	audit *log.Logger
+}
+
+// NewDeadLetterUseCase creates a new dead letter use case
+func NewDeadLetterUseCase(queue domain.DeadLetterQueue) *DeadLetterUseCase {
+	return &DeadLetterUseCase{queue: queue, audit: log.Default()}
+}
+
+// List returns a page of a topic's dead letters, newest first
+func (uc *DeadLetterUseCase) List(ctx context.Context, topic string, offset, limit int) (*domain.DeadLetterPage, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", domain.ErrInvalidInput)
+	}
+	if limit <= 0 {
+		limit = defaultDeadLetterPageSize
+	}
+	if limit > maxDeadLetterPageSize {
+		return nil, fmt.Errorf("%w: limit must be at most %d", domain.ErrInvalidInput, maxDeadLetterPageSize)
+	}
+
+	total, err := uc.queue.CountDeadLetters(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := uc.queue.ListDeadLetters(ctx, topic, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.DeadLetterPage{
+		Topic:    topic,
+		Total:    total,
+		Offset:   offset,
+		Limit:    limit,
+		Messages: messages,
+	}, nil
+}
+
+// Get returns a dead letter of topic, or nil if there is none with id
+func (uc *DeadLetterUseCase) Get(ctx context.Context, topic, id string) (*domain.Message, error) {
+	return uc.queue.GetDeadLetter(ctx, topic, id)
+}
+
+// Replay moves one dead letter of topic back to the topic
+func (uc *DeadLetterUseCase) Replay(ctx context.Context, topic, id, actor string) error {
+	msg, err := uc.queue.GetDeadLetter(ctx, topic, id)
+	if err != nil {
+		return err
+	}
+	if msg == nil {
+		return fmt.Errorf("%w: %s", domain.ErrDeadLetterNotFound, id)
+	}
+	if err := uc.queue.ReplayDeadLetter(ctx, id); err != nil {
+		return err
+	}
+	uc.audit.Printf("audit: user %q replayed dead letter %s of topic %s", actor, id, topic)
+	return nil
+}
+
+// ReplayMany replays the requested dead letters of topic, or all of them
+// when no IDs are given. A dead letter that cannot be replayed does not stop
+// the others; its error is reported in its result.
+func (uc *DeadLetterUseCase) ReplayMany(ctx context.Context, topic string, req *domain.DeadLetterReplayRequest, actor string) (*domain.DeadLetterReplayReport, error) {
+	ids := req.IDs
+	if len(ids) > maxDeadLetterReplay {
+		return nil, fmt.Errorf("%w: at most %d dead letters can be replayed at once", domain.ErrInvalidInput, maxDeadLetterReplay)
+	}
+	if len(ids) == 0 {
+		messages, err := uc.queue.ListDeadLetters(ctx, topic, 0, maxDeadLetterReplay)
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range messages {
+			ids = append(ids, msg.ID)
+		}
+	}
+
+	report := &domain.DeadLetterReplayReport{Topic: topic, Results: make([]domain.DeadLetterReplayResult, 0, len(ids))}
+	for _, id := range ids {
+		result := domain.DeadLetterReplayResult{ID: id, Replayed: true}
+		if err := uc.Replay(ctx, topic, id, actor); err != nil {
+			result.Replayed = false
+			result.Error = err.Error()
+			report.Failed++
+		} else {
+			report.Replayed++
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// Purge removes all dead letters of topic
+func (uc *DeadLetterUseCase) Purge(ctx context.Context, topic, actor string) error {
+	count, err := uc.queue.CountDeadLetters(ctx, topic)
+	if err != nil {
+		return err
+	}
+	if err := uc.queue.PurgeDeadLetters(ctx, topic); err != nil {
+		return err
+	}
+	uc.audit.Printf("audit: user %q purged %d dead letters of topic %s", actor, count, topic)
+	return nil
+}
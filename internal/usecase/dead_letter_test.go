@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryDeadLetterQueue struct {
+	topic    string
+	messages []*domain.Message
+	replayed []string
+}
+
+func (q *memoryDeadLetterQueue) ListDeadLetters(ctx context.Context, topic string, offset, limit int) ([]*domain.Message, error) {
+	if topic != q.topic || offset >= len(q.messages) {
+		return nil, nil
+	}
+	end := offset + limit
+	if end > len(q.messages) {
+		end = len(q.messages)
+	}
+	return q.messages[offset:end], nil
+}
+
+func (q *memoryDeadLetterQueue) CountDeadLetters(ctx context.Context, topic string) (int64, error) {
+	if topic != q.topic {
+		return 0, nil
+	}
+	return int64(len(q.messages)), nil
+}
+
+func (q *memoryDeadLetterQueue) GetDeadLetter(ctx context.Context, topic, id string) (*domain.Message, error) {
+	for _, msg := range q.messages {
+		if topic == q.topic && msg.ID == id {
+			return msg, nil
+		}
+	}
+	return nil, nil
+}
+
+func (q *memoryDeadLetterQueue) ReplayDeadLetter(ctx context.Context, id string) error {
+	for i, msg := range q.messages {
+		if msg.ID == id {
+			q.messages = append(q.messages[:i], q.messages[i+1:]...)
+			q.replayed = append(q.replayed, id)
+			return nil
+		}
+	}
+	return domain.ErrDeadLetterNotFound
+}
+
+func (q *memoryDeadLetterQueue) PurgeDeadLetters(ctx context.Context, topic string) error {
+	q.messages = nil
+	return nil
+}
+
+func newTestDeadLetterUseCase(ids ...string) (*DeadLetterUseCase, *memoryDeadLetterQueue, *bytes.Buffer) {
+	queue := &memoryDeadLetterQueue{topic: "ai_process"}
+	for _, id := range ids {
+		queue.messages = append(queue.messages, &domain.Message{ID: id, Type: "ai_process", Status: domain.MessageStatusDeadLetter})
+	}
+	var audit bytes.Buffer
+	uc := NewDeadLetterUseCase(queue)
+	uc.audit = log.New(&audit, "", 0)
+	return uc, queue, &audit
+}
+
+func TestDeadLetterUseCase_List(t *testing.T) {
+	uc, _, _ := newTestDeadLetterUseCase("m1", "m2", "m3")
+
+	page, err := uc.List(context.Background(), "ai_process", 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), page.Total)
+	assert.Equal(t, defaultDeadLetterPageSize, page.Limit)
+	assert.Len(t, page.Messages, 2)
+
+	_, err = uc.List(context.Background(), "ai_process", 0, maxDeadLetterPageSize+1)
+	assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+}
+
+func TestDeadLetterUseCase_ReplayIsAudited(t *testing.T) {
+	uc, queue, audit := newTestDeadLetterUseCase("m1")
+
+	require.NoError(t, uc.Replay(context.Background(), "ai_process", "m1", "admin-1"))
+	assert.Equal(t, []string{"m1"}, queue.replayed)
+	assert.Equal(t, "audit: user \"admin-1\" replayed dead letter m1 of topic ai_process\n", audit.String())
+
+	// A message of another topic is not replayed
+	err := uc.Replay(context.Background(), "ddex", "m1", "admin-1")
+	assert.True(t, errors.Is(err, domain.ErrDeadLetterNotFound))
+}
+
+func TestDeadLetterUseCase_ReplayMany(t *testing.T) {
+	uc, queue, _ := newTestDeadLetterUseCase("m1", "m2", "m3")
+
+	report, err := uc.ReplayMany(context.Background(), "ai_process", &domain.DeadLetterReplayRequest{IDs: []string{"m1", "missing"}}, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Replayed)
+	assert.Equal(t, 1, report.Failed)
+	assert.False(t, report.Results[1].Replayed)
+
+	// Without IDs the rest of the topic is replayed
+	report, err = uc.ReplayMany(context.Background(), "ai_process", &domain.DeadLetterReplayRequest{}, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Replayed)
+	assert.Equal(t, []string{"m1", "m2", "m3"}, queue.replayed)
+}
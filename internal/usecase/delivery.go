@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+
+	"github.com/google/uuid"
+)
+
+// MaxDeliveryTracks caps the number of tracks recorded for one package
+const MaxDeliveryTracks = 1000
+
+// RecordDeliveryRequest describes a package sent to a DSP
+type RecordDeliveryRequest struct {
+	DSP           string    `json:"dsp" binding:"required"`
+	PackageID     string    `json:"package_id" binding:"required"`
+	PackageFormat string    `json:"package_format,omitempty"`
+	TrackIDs      []string  `json:"track_ids" binding:"required,min=1"`
+	DeliveredAt   time.Time `json:"delivered_at,omitempty"`
+}
+
+// DeliveryUseCase records which tracks were delivered to which DSP and
+// updates the deliveries from the acknowledgements DSPs send back
+type DeliveryUseCase struct {
+	deliveries domain.DeliveryRepository
+	tracks     domain.TrackRepository
+	workflow   *TrackWorkflowUseCase
+	now        func() time.Time
+}
+
+// NewDeliveryUseCase creates a new delivery use case
+func NewDeliveryUseCase(deliveries domain.DeliveryRepository, tracks domain.TrackRepository) *DeliveryUseCase {
+	return &DeliveryUseCase{deliveries: deliveries, tracks: tracks, now: time.Now}
+}
+
+// SetWorkflow moves acknowledged tracks to delivered through workflow
+func (uc *DeliveryUseCase) SetWorkflow(workflow *TrackWorkflowUseCase) {
+	uc.workflow = workflow
+}
+
+// Record stores one delivery per track of a package sent to a DSP. Every
+// track must exist; its release is taken from the track.
+func (uc *DeliveryUseCase) Record(ctx context.Context, req *RecordDeliveryRequest) ([]*domain.Delivery, error) {
+	if len(req.TrackIDs) > MaxDeliveryTracks {
+		return nil, fmt.Errorf("%w: a package holds at most %d tracks", domain.ErrInvalidInput, MaxDeliveryTracks)
+	}
+	existing, err := uc.deliveries.ListByPackage(ctx, req.PackageID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("%w: package %s is already recorded", domain.ErrInvalidInput, req.PackageID)
+	}
+
+	now := uc.now()
+	deliveredAt := req.DeliveredAt
+	if deliveredAt.IsZero() {
+		deliveredAt = now
+	}
+
+	seen := make(map[string]bool, len(req.TrackIDs))
+	deliveries := make([]*domain.Delivery, 0, len(req.TrackIDs))
+	for _, id := range req.TrackIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		track, err := uc.tracks.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if track == nil {
+			return nil, fmt.Errorf("%w: track %s", domain.ErrTrackNotFound, id)
+		}
+		deliveries = append(deliveries, &domain.Delivery{
+			ID:            uuid.New().String(),
+			DSP:           req.DSP,
+			TrackID:       track.ID,
+			ReleaseID:     track.ReleaseID,
+			PackageID:     req.PackageID,
+			PackageFormat: req.PackageFormat,
+			Kind:          domain.DeliveryKindRelease,
+			Status:        domain.DeliveryStatusSent,
+			DeliveredAt:   deliveredAt,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+
+	if err := uc.deliveries.Create(ctx, deliveries); err != nil {
+		return nil, err
+	}
+	metrics.Deliveries.WithLabelValues(req.DSP, string(domain.DeliveryStatusSent)).Add(float64(len(deliveries)))
+	return deliveries, nil
+}
+
+// List returns the deliveries matching filter, newest first
+func (uc *DeliveryUseCase) List(ctx context.Context, filter domain.DeliveryFilter) ([]*domain.Delivery, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, fmt.Errorf("%w: status must be sent, acknowledged or failed", domain.ErrInvalidInput)
+	}
+	if filter.Kind != "" && !filter.Kind.IsValid() {
+		return nil, fmt.Errorf("%w: kind must be release, takedown or territory_block", domain.ErrInvalidInput)
+	}
+	return uc.deliveries.List(ctx, filter)
+}
+
+// Acknowledge applies a DDEX acknowledgement to the deliveries of the
+// package it refers to. Refused packages fail their deliveries with the
+// DSP's error text; accepted releases move their tracks to delivered where
+// the workflow allows it.
+func (uc *DeliveryUseCase) Acknowledge(ctx context.Context, data []byte) (*domain.AcknowledgementResult, error) {
+	var ack domain.DeliveryAcknowledgement
+	if err := xml.Unmarshal(data, &ack); err != nil {
+		return nil, fmt.Errorf("%w: acknowledgement is not valid XML: %v", domain.ErrInvalidInput, err)
+	}
+	packageID := strings.TrimSpace(ack.AcknowledgedFile.MessageID)
+	if packageID == "" {
+		return nil, fmt.Errorf("%w: acknowledgement does not name the acknowledged message", domain.ErrInvalidInput)
+	}
+
+	deliveries, err := uc.deliveries.ListByPackage(ctx, packageID)
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, fmt.Errorf("%w: package %s", domain.ErrDeliveryNotFound, packageID)
+	}
+
+	result := &domain.AcknowledgementResult{PackageID: packageID, Status: domain.DeliveryStatusAcknowledged}
+	if status := strings.TrimSpace(ack.MessageStatus); status != domain.AcknowledgementFileOK {
+		result.Status = domain.DeliveryStatusFailed
+		result.Error = strings.Join(append([]string{status}, ack.ErrorText...), ": ")
+	}
+	result.Deliveries, err = uc.deliveries.UpdateStatus(ctx, packageID, result.Status, result.Error, uc.now())
+	if err != nil {
+		return nil, err
+	}
+
+	dsp := deliveries[0].DSP
+	metrics.Deliveries.WithLabelValues(dsp, string(result.Status)).Add(float64(result.Deliveries))
+	if result.Status == domain.DeliveryStatusFailed {
+		log.Printf("Delivery of package %s to %s failed: %s", packageID, dsp, result.Error)
+		return result, nil
+	}
+
+	if uc.workflow != nil && deliveries[0].Kind == domain.DeliveryKindRelease {
+		for _, delivery := range deliveries {
+			uc.markDelivered(ctx, delivery)
+		}
+	}
+	return result, nil
+}
+
+// markDelivered moves an acknowledged track to delivered if it may move
+// there; tracks in other states, such as ones still in review, are left alone
+func (uc *DeliveryUseCase) markDelivered(ctx context.Context, delivery *domain.Delivery) {
+	msg := fmt.Sprintf("delivered to %s in package %s", delivery.DSP, delivery.PackageID)
+	_, err := uc.workflow.Transition(ctx, delivery.TrackID, domain.TrackStatusDelivered, msg, "", 0)
+	if err != nil && !errors.Is(err, domain.ErrInvalidStatusTransition) {
+		log.Printf("Failed to mark track %s delivered: %v", delivery.TrackID, err)
+	}
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryDeliveryRepository keeps deliveries in memory
+type memoryDeliveryRepository struct {
+	deliveries []*pkgdomain.Delivery
+}
+
+func (r *memoryDeliveryRepository) Create(_ context.Context, deliveries []*pkgdomain.Delivery) error {
+	r.deliveries = append(r.deliveries, deliveries...)
+	return nil
+}
+
+func (r *memoryDeliveryRepository) ListByPackage(_ context.Context, packageID string) ([]*pkgdomain.Delivery, error) {
+	var out []*pkgdomain.Delivery
+	for _, d := range r.deliveries {
+		if d.PackageID == packageID {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryDeliveryRepository) List(_ context.Context, filter pkgdomain.DeliveryFilter) ([]*pkgdomain.Delivery, error) {
+	var out []*pkgdomain.Delivery
+	for _, d := range r.deliveries {
+		if (filter.DSP == "" || d.DSP == filter.DSP) &&
+			(filter.TrackID == "" || d.TrackID == filter.TrackID) &&
+			(filter.Kind == "" || d.Kind == filter.Kind) &&
+			(filter.Status == "" || d.Status == filter.Status) {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryDeliveryRepository) UpdateStatus(_ context.Context, packageID string, status pkgdomain.DeliveryStatus, errText string, at time.Time) (int64, error) {
+	var n int64
+	for _, d := range r.deliveries {
+		if d.PackageID == packageID {
+			d.Status, d.Error, d.AcknowledgedAt = status, errText, &at
+			n++
+		}
+	}
+	return n, nil
+}
+
+const testAcknowledgement = `<?xml version="1.0" encoding="UTF-8"?>
+<ftpAcknowledgementMessage>
+  <messageHeader><messageId>ack-1</messageId></messageHeader>
+  <acknowledgedFile><messageId>pkg-1</messageId><fileName>pkg-1.xml</fileName></acknowledgedFile>
+  <messageStatus>%s</messageStatus>
+  %s
+</ftpAcknowledgementMessage>`
+
+func newDeliveryUseCase(status pkgdomain.TrackStatus) (*DeliveryUseCase, *memoryDeliveryRepository, *MockTrackRepository) {
+	trackRepo := new(MockTrackRepository)
+	trackRepo.On("GetByID", mock.Anything, "t1").Return(&pkgdomain.Track{ID: "t1", ReleaseID: "r1", Version: 1, Status: status}, nil)
+	trackRepo.On("GetByID", mock.Anything, "missing").Return(nil, pkgdomain.ErrTrackNotFound)
+	trackRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	repo := &memoryDeliveryRepository{}
+	uc := NewDeliveryUseCase(repo, trackRepo)
+	uc.SetWorkflow(NewTrackWorkflowUseCase(trackRepo))
+	return uc, repo, trackRepo
+}
+
+func TestDeliveryUseCase_Record(t *testing.T) {
+	uc, repo, _ := newDeliveryUseCase(pkgdomain.TrackStatusApproved)
+
+	deliveries, err := uc.Record(context.Background(), &RecordDeliveryRequest{
+		DSP: "spotify", PackageID: "pkg-1", TrackIDs: []string{"t1", "t1"},
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, "r1", deliveries[0].ReleaseID)
+	assert.Equal(t, pkgdomain.DeliveryStatusSent, deliveries[0].Status)
+	assert.False(t, deliveries[0].DeliveredAt.IsZero())
+
+	_, err = uc.Record(context.Background(), &RecordDeliveryRequest{DSP: "spotify", PackageID: "pkg-1", TrackIDs: []string{"t1"}})
+	assert.True(t, errors.Is(err, pkgdomain.ErrInvalidInput), "a package is recorded once")
+
+	_, err = uc.Record(context.Background(), &RecordDeliveryRequest{DSP: "spotify", PackageID: "pkg-2", TrackIDs: []string{"missing"}})
+	assert.True(t, errors.Is(err, pkgdomain.ErrTrackNotFound))
+	assert.Len(t, repo.deliveries, 1)
+}
+
+func TestDeliveryUseCase_Acknowledge(t *testing.T) {
+	record := func(t *testing.T, uc *DeliveryUseCase) {
+		_, err := uc.Record(context.Background(), &RecordDeliveryRequest{DSP: "spotify", PackageID: "pkg-1", TrackIDs: []string{"t1"}})
+		require.NoError(t, err)
+	}
+
+	t.Run("accepted", func(t *testing.T) {
+		uc, repo, trackRepo := newDeliveryUseCase(pkgdomain.TrackStatusApproved)
+		record(t, uc)
+
+		result, err := uc.Acknowledge(context.Background(), []byte(fmt.Sprintf(testAcknowledgement, "FileOK", "")))
+		require.NoError(t, err)
+		assert.Equal(t, pkgdomain.DeliveryStatusAcknowledged, result.Status)
+		assert.EqualValues(t, 1, result.Deliveries)
+		assert.NotNil(t, repo.deliveries[0].AcknowledgedAt)
+		trackRepo.AssertCalled(t, "Update", mock.Anything, mock.MatchedBy(func(track *pkgdomain.Track) bool {
+			return track.Status == pkgdomain.TrackStatusDelivered
+		}))
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		uc, repo, trackRepo := newDeliveryUseCase(pkgdomain.TrackStatusApproved)
+		record(t, uc)
+
+		result, err := uc.Acknowledge(context.Background(), []byte(fmt.Sprintf(testAcknowledgement,
+			"ResourceCorrupt", "<errorText>checksum mismatch</errorText>")))
+		require.NoError(t, err)
+		assert.Equal(t, pkgdomain.DeliveryStatusFailed, result.Status)
+		assert.Equal(t, "ResourceCorrupt: checksum mismatch", repo.deliveries[0].Error)
+		trackRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+		failed, err := uc.List(context.Background(), pkgdomain.DeliveryFilter{Status: pkgdomain.DeliveryStatusFailed})
+		require.NoError(t, err)
+		assert.Len(t, failed, 1)
+	})
+
+	t.Run("track not ready", func(t *testing.T) {
+		uc, _, trackRepo := newDeliveryUseCase(pkgdomain.TrackStatusNeedsReview)
+		record(t, uc)
+
+		_, err := uc.Acknowledge(context.Background(), []byte(fmt.Sprintf(testAcknowledgement, "FileOK", "")))
+		require.NoError(t, err)
+		trackRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown package", func(t *testing.T) {
+		uc, _, _ := newDeliveryUseCase(pkgdomain.TrackStatusApproved)
+
+		_, err := uc.Acknowledge(context.Background(), []byte(fmt.Sprintf(testAcknowledgement, "FileOK", "")))
+		assert.True(t, errors.Is(err, pkgdomain.ErrDeliveryNotFound))
+	})
+
+	t.Run("invalid XML", func(t *testing.T) {
+		uc, _, _ := newDeliveryUseCase(pkgdomain.TrackStatusApproved)
+
+		_, err := uc.Acknowledge(context.Background(), []byte("<ftpAcknowledgementMessage>"))
+		assert.True(t, errors.Is(err, pkgdomain.ErrInvalidInput))
+	})
+}
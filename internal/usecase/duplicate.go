@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+const (
+	// MaxDuplicateScan caps the catalog tracks a new track is compared with.
+	// Tracks sharing its ISRC are found beyond it.
+	MaxDuplicateScan = 10000
+	// duplicateScanPage is the number of tracks read per query
+	duplicateScanPage = 500
+)
+
+// duplicateScanFields are the track fields duplicate detection reads
+var duplicateScanFields = []string{"title", "artist", "isrc"}
+
+// DuplicateUseCase finds probable duplicates by fuzzy matching of track
+// metadata and merges them
+type DuplicateUseCase struct {
+	tracks     domain.TrackRepository
+	redirects  domain.TrackRedirectRepository
+	unitOfWork domain.UnitOfWork
+	references []namedReferenceRewriter
+}
+
+// NewDuplicateUseCase creates a new duplicate use case
+func NewDuplicateUseCase(tracks domain.TrackRepository) *DuplicateUseCase {
+	return &DuplicateUseCase{tracks: tracks}
+}
+
+// Catalog returns the catalog tracks new tracks are compared with, holding
+// only the fields duplicate detection reads. Imports load it once and pass
+// it to domain.FindDuplicates for every row.
+func (uc *DuplicateUseCase) Catalog(ctx context.Context) ([]*domain.Track, error) {
+	ctx = domain.WithTrackFields(ctx, duplicateScanFields)
+	var catalog []*domain.Track
+	for offset := 0; offset < MaxDuplicateScan; offset += duplicateScanPage {
+		page, err := uc.tracks.List(ctx, map[string]interface{}{}, offset, duplicateScanPage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tracks: %w", err)
+		}
+		catalog = append(catalog, page...)
+		if len(page) < duplicateScanPage {
+			break
+		}
+	}
+	return catalog, nil
+}
+
+// Check returns the catalog tracks that track probably duplicates, most
+// probable first
+func (uc *DuplicateUseCase) Check(ctx context.Context, track *domain.Track) ([]domain.DuplicateCandidate, error) {
+	catalog, err := uc.Catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if track.ISRC() != "" {
+		existing, err := uc.tracks.GetByISRC(ctx, track.ISRC())
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil && !containsTrack(catalog, existing.ID) {
+			catalog = append(catalog, existing)
+		}
+	}
+	return domain.FindDuplicates(track, catalog), nil
+}
+
+// ForTrack returns the probable duplicates of a stored track
+func (uc *DuplicateUseCase) ForTrack(ctx context.Context, id string) ([]domain.DuplicateCandidate, error) {
+	track, err := uc.tracks.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if track == nil {
+		return nil, domain.ErrTrackNotFound
+	}
+	return uc.Check(ctx, track)
+}
+
+func containsTrack(tracks []*domain.Track, id string) bool {
+	for _, t := range tracks {
+		if t.ID == id {
+			return true
+		}
+	}
+	return false
+}
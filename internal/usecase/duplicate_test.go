@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newDuplicateTrack(id, title, artist, isrc string) *pkgdomain.Track {
+	track := &pkgdomain.Track{ID: id, Version: 1}
+	track.SetTitle(title)
+	track.SetArtist(artist)
+	track.SetISRC(isrc)
+	return track
+}
+
+func TestDuplicateUseCase_Check(t *testing.T) {
+	catalog := []*pkgdomain.Track{
+		newDuplicateTrack("remaster", "Café del Mar (Remastered 2011)", "The Energy 52", ""),
+		newDuplicateTrack("misspelled", "Sweet Child o' Mine", "Guns N' Roses", ""),
+		newDuplicateTrack("soundalike", "Summer Rain", "Jon Smyth", ""),
+		newDuplicateTrack("cover", "Hallelujah", "Jeff Buckley", ""),
+		newDuplicateTrack("other", "Something Else", "Someone Else", ""),
+	}
+	tracks := new(MockTrackRepository)
+	tracks.On("List", mock.Anything, mock.Anything, 0, duplicateScanPage).Return(catalog, nil)
+	uc := NewDuplicateUseCase(tracks)
+
+	tests := []struct {
+		name   string
+		track  *pkgdomain.Track
+		want   []string
+		reason string
+	}{
+		{"diacritics and remarks", newDuplicateTrack("", "Cafe Del Mar", "Energy 52 feat. Someone", ""), []string{"remaster"}, pkgdomain.DuplicateReasonTitle},
+		{"typo", newDuplicateTrack("", "Sweet Child of Mine", "Guns N Roses", ""), []string{"misspelled"}, pkgdomain.DuplicateReasonArtist},
+		{"artists that sound alike", newDuplicateTrack("", "Summer Rain", "John Smith", ""), []string{"soundalike"}, pkgdomain.DuplicateReasonArtistSoundex},
+		{"covers are not duplicates", newDuplicateTrack("", "Hallelujah", "Leonard Cohen", ""), nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			duplicates, err := uc.Check(context.Background(), tt.track)
+			require.NoError(t, err)
+
+			var ids []string
+			for _, d := range duplicates {
+				ids = append(ids, d.TrackID)
+				assert.GreaterOrEqual(t, d.Score, pkgdomain.DuplicateThreshold)
+				assert.Contains(t, d.Reasons, tt.reason)
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestDuplicateUseCase_CheckFindsISRCBeyondScan(t *testing.T) {
+	existing := newDuplicateTrack("existing", "Completely Different", "Nobody", "USABC2400001")
+	tracks := new(MockTrackRepository)
+	tracks.On("List", mock.Anything, mock.Anything, 0, duplicateScanPage).Return([]*pkgdomain.Track{}, nil)
+	tracks.On("GetByISRC", mock.Anything, "USABC2400001").Return(existing, nil)
+
+	duplicates, err := NewDuplicateUseCase(tracks).Check(context.Background(), newDuplicateTrack("", "Song", "Artist", "USABC2400001"))
+	require.NoError(t, err)
+	require.Len(t, duplicates, 1)
+	assert.Equal(t, "existing", duplicates[0].TrackID)
+	assert.Equal(t, 1.0, duplicates[0].Score)
+	assert.Equal(t, []string{pkgdomain.DuplicateReasonISRC}, duplicates[0].Reasons)
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+)
+
+// eventQueueSize is how many events an asynchronous subscriber may fall
+// behind before further events are dropped for it
+const eventQueueSize = 1000
+
+// subscription is a subscriber to one kind of event
+type subscription struct {
+	name   string
+	handle func(ctx context.Context, event domain.Event) error
+	// queue holds the events of an asynchronous subscriber; it is nil for
+	// synchronous ones
+	queue chan domain.Event
+}
+
+// EventBus delivers domain events to the subscribers in this process. A
+// synchronous subscriber handles an event before Publish returns; an
+// asynchronous one handles the events of each kind in order on a goroutine,
+// for slow reactions such as calling a webhook. Events are published as
+// values, such as domain.TrackCreated{...}.
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers map[string][]*subscription
+	closed      bool
+	wg          sync.WaitGroup
+}
+
+// NewEventBus creates an event bus without subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[string][]*subscription)}
+}
+
+// Subscribe makes handle, named name in logs and metrics, handle the events
+// of type E synchronously. Its error is logged; it does not fail the
+// publisher.
+func Subscribe[E domain.Event](bus *EventBus, name string, handle func(ctx context.Context, event E) error) {
+	eventName, handler := typedHandler(handle)
+	bus.subscribe(name, eventName, handler, false)
+}
+
+// SubscribeAsync makes handle, named name in logs and metrics, handle the
+// events of type E in the background. The publisher's context may be gone
+// by then, so handle is given a background context instead.
+func SubscribeAsync[E domain.Event](bus *EventBus, name string, handle func(ctx context.Context, event E) error) {
+	eventName, handler := typedHandler(handle)
+	bus.subscribe(name, eventName, handler, true)
+}
+
+// typedHandler adapts a handler of events of type E
+func typedHandler[E domain.Event](handle func(ctx context.Context, event E) error) (string, func(context.Context, domain.Event) error) {
+	var zero E
+	return zero.EventName(), func(ctx context.Context, event domain.Event) error {
+		typed, ok := event.(E)
+		if !ok {
+			return fmt.Errorf("unexpected event type %T", event)
+		}
+		return handle(ctx, typed)
+	}
+}
+
+func (b *EventBus) subscribe(name, eventName string, handle func(context.Context, domain.Event) error, async bool) {
+	sub := &subscription{name: name, handle: handle}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if async {
+		sub.queue = make(chan domain.Event, eventQueueSize)
+		if b.closed {
+			close(sub.queue)
+		} else {
+			b.wg.Add(1)
+			go b.drain(eventName, sub)
+		}
+	}
+	b.subscribers[eventName] = append(b.subscribers[eventName], sub)
+}
+
+// Publish implements domain.EventPublisher
+func (b *EventBus) Publish(ctx context.Context, event domain.Event) {
+	domain.AfterCommit(ctx, func() {
+		b.deliver(ctx, event)
+	})
+}
+
+func (b *EventBus) deliver(ctx context.Context, event domain.Event) {
+	name := event.EventName()
+	metrics.DomainEventsPublished.WithLabelValues(name).Inc()
+
+	b.mu.RLock()
+	subscribers := b.subscribers[name]
+	b.mu.RUnlock()
+	for _, sub := range subscribers {
+		if sub.queue == nil {
+			b.handle(ctx, name, sub, event)
+			continue
+		}
+		b.enqueue(name, sub, event)
+	}
+}
+
+// enqueue hands an event to an asynchronous subscriber, dropping it when the
+// subscriber is too far behind or the bus is closed
+func (b *EventBus) enqueue(name string, sub *subscription, event domain.Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if !b.closed {
+		select {
+		case sub.queue <- event:
+			return
+		default:
+		}
+	}
+	log.Printf("Dropping %s event for subscriber %s: it is not accepting events", name, sub.name)
+	metrics.DomainEventDeliveries.WithLabelValues(name, sub.name, "dropped").Inc()
+}
+
+// drain handles the events queued for an asynchronous subscriber until the
+// bus is closed
+func (b *EventBus) drain(name string, sub *subscription) {
+	defer b.wg.Done()
+	for event := range sub.queue {
+		b.handle(context.Background(), name, sub, event)
+	}
+}
+
+// handle calls a subscriber, turning a panic into an error
+func (b *EventBus) handle(ctx context.Context, name string, sub *subscription, event domain.Event) {
+	err := func() (err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				log.Printf("Panic in subscriber %s handling %s event: %v\n%s", sub.name, name, recovered, debug.Stack())
+				err = fmt.Errorf("panic: %v", recovered)
+			}
+		}()
+		return sub.handle(ctx, event)
+	}()
+
+	status := "delivered"
+	if err != nil {
+		status = "failed"
+		log.Printf("Subscriber %s failed to handle %s event: %v", sub.name, name, err)
+	}
+	metrics.DomainEventDeliveries.WithLabelValues(name, sub.name, status).Inc()
+}
+
+// Close stops accepting events for the asynchronous subscribers and waits
+// for them to handle the events already queued
+func (b *EventBus) Close() {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		for _, subscribers := range b.subscribers {
+			for _, sub := range subscribers {
+				if sub.queue != nil {
+					close(sub.queue)
+				}
+			}
+		}
+	}
+	b.mu.Unlock()
+	b.wg.Wait()
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryLabelStatsInvalidator struct {
+	invalidated []string
+}
+
+func (m *memoryLabelStatsInvalidator) InvalidateLabelStats(ctx context.Context, labelID string) error {
+	m.invalidated = append(m.invalidated, labelID)
+	return nil
+}
+
+type memoryEventNotifier struct {
+	mu      sync.Mutex
+	notices []*domain.EventNotice
+}
+
+func (m *memoryEventNotifier) SendEventNotice(ctx context.Context, notice *domain.EventNotice) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notices = append(m.notices, notice)
+	return nil
+}
+
+func TestEventBus_DeliversByType(t *testing.T) {
+	bus := NewEventBus()
+	defer bus.Close()
+
+	var created, enriched []string
+	Subscribe(bus, "created", func(ctx context.Context, event domain.TrackCreated) error {
+		created = append(created, event.Track.ID)
+		return nil
+	})
+	Subscribe(bus, "enriched", func(ctx context.Context, event domain.MetadataEnriched) error {
+		enriched = append(enriched, event.Track.ID)
+		return nil
+	})
+
+	bus.Publish(context.Background(), domain.TrackCreated{Track: &domain.Track{ID: "t1"}})
+	bus.Publish(context.Background(), domain.MetadataEnriched{Track: &domain.Track{ID: "t2"}})
+	bus.Publish(context.Background(), domain.ValidationFailed{Track: &domain.Track{ID: "t3"}})
+
+	assert.Equal(t, []string{"t1"}, created)
+	assert.Equal(t, []string{"t2"}, enriched)
+}
+
+func TestEventBus_FailingSubscriberDoesNotStopOthers(t *testing.T) {
+	bus := NewEventBus()
+	defer bus.Close()
+
+	var delivered int
+	Subscribe(bus, "failing", func(ctx context.Context, event domain.TrackCreated) error {
+		return errors.New("unavailable")
+	})
+	Subscribe(bus, "panicking", func(ctx context.Context, event domain.TrackCreated) error {
+		panic("boom")
+	})
+	Subscribe(bus, "counting", func(ctx context.Context, event domain.TrackCreated) error {
+		delivered++
+		return nil
+	})
+
+	bus.Publish(context.Background(), domain.TrackCreated{Track: &domain.Track{ID: "t1"}})
+	assert.Equal(t, 1, delivered)
+}
+
+func TestEventBus_DeliversAfterCommit(t *testing.T) {
+	bus := NewEventBus()
+	defer bus.Close()
+
+	var delivered []string
+	Subscribe(bus, "created", func(ctx context.Context, event domain.TrackCreated) error {
+		delivered = append(delivered, event.Track.ID)
+		return nil
+	})
+
+	// Committed: delivered once the hooks run
+	ctx, commit := domain.WithCommitHooks(context.Background())
+	bus.Publish(ctx, domain.TrackCreated{Track: &domain.Track{ID: "t1"}})
+	assert.Empty(t, delivered)
+	commit()
+	assert.Equal(t, []string{"t1"}, delivered)
+
+	// Rolled back: the hooks never run
+	ctx, _ = domain.WithCommitHooks(context.Background())
+	bus.Publish(ctx, domain.TrackCreated{Track: &domain.Track{ID: "t2"}})
+	assert.Equal(t, []string{"t1"}, delivered)
+}
+
+func TestEventBus_AsyncSubscriberDrainsOnClose(t *testing.T) {
+	bus := NewEventBus()
+	notifier := &memoryEventNotifier{}
+	SubscribeEventWebhook(bus, notifier)
+
+	bus.Publish(context.Background(), domain.TrackCreated{Track: &domain.Track{ID: "t1", LabelID: "l1"}, Actor: "u1"})
+	bus.Publish(context.Background(), domain.ValidationFailed{
+		Track:  &domain.Track{ID: "t2"},
+		Errors: []domain.ValidationError{{Field: "isrc", Message: "invalid ISRC"}},
+	})
+	bus.Close()
+
+	// Events of different kinds are queued apart, so they arrive in any order
+	require.Len(t, notifier.notices, 2)
+	assert.ElementsMatch(t, []*domain.EventNotice{
+		{Event: domain.EventTrackCreated, TrackID: "t1", LabelID: "l1", Actor: "u1"},
+		{Event: domain.EventValidationFailed, TrackID: "t2", Errors: []domain.ValidationError{{Field: "isrc", Message: "invalid ISRC"}}},
+	}, notifier.notices)
+
+	// Events published after Close are dropped
+	bus.Publish(context.Background(), domain.TrackCreated{Track: &domain.Track{ID: "t3"}})
+	assert.Len(t, notifier.notices, 2)
+}
+
+func TestSubscribeAuditLog(t *testing.T) {
+	bus := NewEventBus()
+	defer bus.Close()
+	var audit bytes.Buffer
+	subscribeAuditLog(bus, log.New(&audit, "", 0))
+
+	bus.Publish(context.Background(), domain.TrackCreated{Track: &domain.Track{ID: "t1"}, Actor: "u1"})
+	bus.Publish(context.Background(), domain.ValidationFailed{
+		Track:  &domain.Track{ID: "t1"},
+		Errors: []domain.ValidationError{{Field: "isrc"}, {Field: "title"}},
+	})
+	assert.Equal(t, "audit: user \"u1\" created track t1\naudit: track t1 failed validation of isrc, title\n", audit.String())
+}
+
+func TestSubscribeLabelStatsInvalidation(t *testing.T) {
+	bus := NewEventBus()
+	defer bus.Close()
+	stats := &memoryLabelStatsInvalidator{}
+	SubscribeLabelStatsInvalidation(bus, stats)
+
+	bus.Publish(context.Background(), domain.TrackCreated{Track: &domain.Track{ID: "t1", LabelID: "l1"}})
+	bus.Publish(context.Background(), domain.MetadataEnriched{Track: &domain.Track{ID: "t2", LabelID: "l2"}})
+	// Tracks without a label have no statistics to drop
+	bus.Publish(context.Background(), domain.TrackCreated{Track: &domain.Track{ID: "t3"}})
+	assert.Equal(t, []string{"l1", "l2"}, stats.invalidated)
+}
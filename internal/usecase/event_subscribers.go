@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"metadatatool/internal/pkg/analytics"
+	"metadatatool/internal/pkg/domain"
+)
+
+// SubscribeAuditLog writes the track events to the audit log
+func SubscribeAuditLog(bus *EventBus) {
+	subscribeAuditLog(bus, log.Default())
+}
+
+func subscribeAuditLog(bus *EventBus, audit *log.Logger) {
+	Subscribe(bus, "audit_log", func(_ context.Context, event domain.TrackCreated) error {
+		audit.Printf("audit: user %q created track %s", event.Actor, event.Track.ID)
+		return nil
+	})
+	Subscribe(bus, "audit_log", func(_ context.Context, event domain.MetadataEnriched) error {
+		audit.Printf("audit: AI enrichment changed the metadata of track %s", event.Track.ID)
+		return nil
+	})
+	Subscribe(bus, "audit_log", func(_ context.Context, event domain.ValidationFailed) error {
+		fields := make([]string, len(event.Errors))
+		for i, e := range event.Errors {
+			fields[i] = e.Field
+		}
+		audit.Printf("audit: track %s failed validation of %s", event.Track.ID, strings.Join(fields, ", "))
+		return nil
+	})
+}
+
+// SubscribeLabelStatsInvalidation drops the cached statistics of the label
+// of every track created or enriched
+func SubscribeLabelStatsInvalidation(bus *EventBus, stats domain.LabelStatsInvalidator) {
+	invalidate := func(ctx context.Context, track *domain.Track) error {
+		if track.LabelID == "" {
+			return nil
+		}
+		return stats.InvalidateLabelStats(ctx, track.LabelID)
+	}
+	Subscribe(bus, "label_stats_cache", func(ctx context.Context, event domain.TrackCreated) error {
+		return invalidate(ctx, event.Track)
+	})
+	Subscribe(bus, "label_stats_cache", func(ctx context.Context, event domain.MetadataEnriched) error {
+		return invalidate(ctx, event.Track)
+	})
+}
+
+// SubscribeAnalytics records the track events in the catalog_events
+// analytics table
+func SubscribeAnalytics(bus *EventBus, recorder analytics.EventRecorder) {
+	record := func(ctx context.Context, event domain.Event, at time.Time, track *domain.Track, errors int) {
+		recorder.Record(analytics.CatalogEvent{
+			Timestamp: at,
+			Tenant:    domain.TenantFromContext(ctx),
+			Event:     event.EventName(),
+			TrackID:   track.ID,
+			LabelID:   track.LabelID,
+			Errors:    errors,
+		})
+	}
+	Subscribe(bus, "analytics", func(ctx context.Context, event domain.TrackCreated) error {
+		record(ctx, event, event.At, event.Track, 0)
+		return nil
+	})
+	Subscribe(bus, "analytics", func(ctx context.Context, event domain.MetadataEnriched) error {
+		record(ctx, event, event.At, event.Track, 0)
+		return nil
+	})
+	Subscribe(bus, "analytics", func(ctx context.Context, event domain.ValidationFailed) error {
+		record(ctx, event, event.At, event.Track, len(event.Errors))
+		return nil
+	})
+}
+
+// SubscribeEventWebhook reports the track events to notifier in the
+// background
+func SubscribeEventWebhook(bus *EventBus, notifier domain.EventNotifier) {
+	SubscribeAsync(bus, "webhook", func(ctx context.Context, event domain.TrackCreated) error {
+		return notifier.SendEventNotice(ctx, &domain.EventNotice{
+			Event:   event.EventName(),
+			TrackID: event.Track.ID,
+			LabelID: event.Track.LabelID,
+			Actor:   event.Actor,
+			At:      event.At,
+		})
+	})
+	SubscribeAsync(bus, "webhook", func(ctx context.Context, event domain.MetadataEnriched) error {
+		return notifier.SendEventNotice(ctx, &domain.EventNotice{
+			Event:   event.EventName(),
+			TrackID: event.Track.ID,
+			LabelID: event.Track.LabelID,
+			At:      event.At,
+		})
+	})
+	SubscribeAsync(bus, "webhook", func(ctx context.Context, event domain.ValidationFailed) error {
+		return notifier.SendEventNotice(ctx, &domain.EventNotice{
+			Event:   event.EventName(),
+			TrackID: event.Track.ID,
+			LabelID: event.Track.LabelID,
+			Errors:  event.Errors,
+			At:      event.At,
+		})
+	})
+}
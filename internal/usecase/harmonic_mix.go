@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+const (
+	// DefaultHarmonicMatches is the number of mixable tracks returned when
+	// no limit is asked for
+	DefaultHarmonicMatches = 25
+	// MaxHarmonicMatches caps the mixable tracks returned at once
+	MaxHarmonicMatches = 200
+)
+
+// harmonicRank orders the relations of mixable keys, smoothest first
+var harmonicRank = map[domain.HarmonicRelation]int{
+	domain.HarmonicSame:     0,
+	domain.HarmonicAdjacent: 1,
+	domain.HarmonicRelative: 2,
+}
+
+// HarmonicMixUseCase finds the tracks a DJ can mix with a track: those
+// whose tempo is close and whose key is next to it on the Camelot wheel
+type HarmonicMixUseCase struct {
+	tracks domain.TrackRepository
+}
+
+// NewHarmonicMixUseCase creates a new harmonic mix use case
+func NewHarmonicMixUseCase(tracks domain.TrackRepository) *HarmonicMixUseCase {
+	return &HarmonicMixUseCase{tracks: tracks}
+}
+
+// Compatible returns the track and its key, and up to limit tracks that
+// mix with it: the same key first, then adjacent and relative keys, each
+// by closeness of tempo. A limit out of range is replaced by the default
+// or the maximum.
+func (uc *HarmonicMixUseCase) Compatible(ctx context.Context, trackID string, limit int) (*domain.Track, domain.CamelotKey, []domain.HarmonicMatch, error) {
+	if limit <= 0 {
+		limit = DefaultHarmonicMatches
+	}
+	if limit > MaxHarmonicMatches {
+		limit = MaxHarmonicMatches
+	}
+	track, err := uc.tracks.GetByID(ctx, trackID)
+	if err != nil {
+		return nil, domain.CamelotKey{}, nil, fmt.Errorf("failed to get track: %w", err)
+	}
+	if track == nil {
+		return nil, domain.CamelotKey{}, nil, domain.ErrTrackNotFound
+	}
+	bpm := track.BPM()
+	if bpm <= 0 {
+		return nil, domain.CamelotKey{}, nil, fmt.Errorf("%w: track has no BPM", domain.ErrInvalidInput)
+	}
+	key, ok := track.Metadata.Musical.CamelotKey()
+	if !ok {
+		return nil, domain.CamelotKey{}, nil, fmt.Errorf("%w: track key %q is not a known key", domain.ErrInvalidInput, track.Key())
+	}
+
+	// Keys are free text, so the query asks for the Camelot codes of the
+	// mixable keys, stored with tracks written since they were derived, or
+	// for any common spelling of them. The candidates are parsed again below.
+	var codes, spellings []string
+	for _, k := range key.Compatible() {
+		codes = append(codes, k.String())
+		spellings = append(spellings, k.Spellings()...)
+	}
+	tolerance := bpm * domain.HarmonicBPMTolerance
+	candidates, err := uc.tracks.SearchByMetadata(ctx, map[string]interface{}{
+		domain.SearchFilterKey: &domain.SearchFilter{And: []*domain.SearchFilter{
+			{Field: "bpm", Op: domain.SearchBetween, Value: []interface{}{bpm - tolerance, bpm + tolerance}},
+			{Or: []*domain.SearchFilter{
+				{Field: "camelot", Op: domain.SearchIn, Value: toInterfaces(codes)},
+				{Field: "key", Op: domain.SearchIn, Value: toInterfaces(spellings)},
+			}},
+		}},
+	})
+	if err != nil {
+		return nil, domain.CamelotKey{}, nil, fmt.Errorf("failed to search tracks: %w", err)
+	}
+
+	matches := make([]domain.HarmonicMatch, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.ID == track.ID {
+			continue
+		}
+		candidateKey, ok := candidate.Metadata.Musical.CamelotKey()
+		if !ok {
+			continue
+		}
+		relation, ok := key.RelationTo(candidateKey)
+		difference := (candidate.BPM() - bpm) / bpm
+		if !ok || math.Abs(difference) > domain.HarmonicBPMTolerance {
+			continue
+		}
+		matches = append(matches, domain.HarmonicMatch{
+			Track:         candidate,
+			Camelot:       candidateKey.String(),
+			Relation:      relation,
+			BPMDifference: difference,
+		})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if ri, rj := harmonicRank[matches[i].Relation], harmonicRank[matches[j].Relation]; ri != rj {
+			return ri < rj
+		}
+		if di, dj := math.Abs(matches[i].BPMDifference), math.Abs(matches[j].BPMDifference); di != dj {
+			return di < dj
+		}
+		return matches[i].Track.ID < matches[j].Track.ID
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return track, key, matches, nil
+}
+
+// toInterfaces returns the strings as a list of values
+func toInterfaces(values []string) []interface{} {
+	list := make([]interface{}, len(values))
+	for i, v := range values {
+		list[i] = v
+	}
+	return list
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newMixTrack(id string, bpm float64, key string) *pkgdomain.Track {
+	track := &pkgdomain.Track{ID: id}
+	track.SetBPM(bpm)
+	track.SetKey(key)
+	return track
+}
+
+// modeTrack sets the mode of a track apart from its key
+func modeTrack(track *pkgdomain.Track, mode string) *pkgdomain.Track {
+	track.SetMode(mode)
+	return track
+}
+
+func TestMusicalMetadata_KeyNotation(t *testing.T) {
+	tests := []struct {
+		key, mode, camelot, openKey string
+	}{
+		{"A", "minor", "8A", "1m"},
+		{"Am", "", "8A", "1m"},
+		{"Am", "minor", "8A", "1m"},
+		{"D", "", "10B", "3d"},
+		{"9b", "", "9B", "2d"},
+		{"unknown", "", "", ""},
+	}
+	for _, tt := range tests {
+		musical := pkgdomain.MusicalMetadata{Key: tt.key, Mode: tt.mode, Camelot: "stale", OpenKey: "stale"}
+		data, err := json.Marshal(musical)
+		require.NoError(t, err)
+
+		var written pkgdomain.MusicalMetadata
+		require.NoError(t, json.Unmarshal(data, &written))
+		assert.Equal(t, tt.camelot, written.Camelot, tt.key)
+		assert.Equal(t, tt.openKey, written.OpenKey, tt.key)
+		assert.Equal(t, tt.key, written.Key)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		camelot string
+		openKey string
+	}{
+		{"Am", "8A", "1m"},
+		{"A minor", "8A", "1m"},
+		{"C", "8B", "1d"},
+		{"c major", "8B", "1d"},
+		{"G", "9B", "2d"},
+		{"F", "7B", "12d"},
+		{"F#m", "11A", "4m"},
+		{"Gb minor", "11A", "4m"},
+		{"E♭", "5B", "10d"},
+		{"Bbm", "3A", "8m"},
+		{"8a", "8A", "1m"},
+		{"12B", "12B", "5d"},
+		{"1m", "8A", "1m"},
+		{"12d", "7B", "12d"},
+	}
+	for _, tt := range tests {
+		key, ok := pkgdomain.ParseKey(tt.key)
+		require.True(t, ok, tt.key)
+		assert.Equal(t, tt.camelot, key.String(), tt.key)
+		assert.Equal(t, tt.openKey, key.OpenKey(), tt.key)
+		// Every spelling of a key reads back as the key
+		for _, spelling := range key.Spellings() {
+			again, ok := pkgdomain.ParseKey(spelling)
+			assert.True(t, ok, spelling)
+			assert.Equal(t, key, again, spelling)
+		}
+	}
+
+	for _, key := range []string{"", "H", "13A", "Am dorian", "0B"} {
+		_, ok := pkgdomain.ParseKey(key)
+		assert.False(t, ok, key)
+	}
+}
+
+func TestHarmonicMixUseCase_Compatible(t *testing.T) {
+	seed := newMixTrack("seed", 124, "Am")
+	tracks := new(MockTrackRepository)
+	tracks.On("GetByID", mock.Anything, "seed").Return(seed, nil)
+	tracks.On("GetByID", mock.Anything, "nobpm").Return(newMixTrack("nobpm", 0, "Am"), nil)
+	tracks.On("GetByID", mock.Anything, "nokey").Return(newMixTrack("nokey", 124, "unknown"), nil)
+	tracks.On("GetByID", mock.Anything, "missing").Return(nil, nil)
+	tracks.On("SearchByMetadata", mock.Anything, mock.MatchedBy(func(query map[string]interface{}) bool {
+		filter, ok := query[pkgdomain.SearchFilterKey].(*pkgdomain.SearchFilter)
+		return ok && len(filter.Validate()) == 0
+	})).Return([]*pkgdomain.Track{
+		seed,
+		newMixTrack("relative", 124, "C major"),
+		newMixTrack("adjacent-far", 130, "Em"),
+		newMixTrack("adjacent-near", 125, "9A"),
+		modeTrack(newMixTrack("adjacent-mode", 122.5, "D"), "minor"),
+		newMixTrack("same", 127, "A minor"),
+		newMixTrack("too-fast", 140, "Am"),
+		newMixTrack("clash", 124, "Bb"),
+	}, nil)
+	uc := NewHarmonicMixUseCase(tracks)
+	ctx := context.Background()
+
+	track, key, matches, err := uc.Compatible(ctx, "seed", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "seed", track.ID)
+	assert.Equal(t, "8A", key.String())
+	var ids []string
+	for _, match := range matches {
+		ids = append(ids, match.Track.ID)
+	}
+	assert.Equal(t, []string{"same", "adjacent-near", "adjacent-mode", "adjacent-far", "relative"}, ids)
+	assert.Equal(t, pkgdomain.HarmonicAdjacent, matches[1].Relation)
+	assert.Equal(t, "9A", matches[1].Camelot)
+	assert.InDelta(t, 3.0/124, matches[0].BPMDifference, 1e-9)
+
+	_, _, matches, err = uc.Compatible(ctx, "seed", 2)
+	require.NoError(t, err)
+	assert.Len(t, matches, 2)
+
+	_, _, _, err = uc.Compatible(ctx, "nobpm", 0)
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput)
+	_, _, _, err = uc.Compatible(ctx, "nokey", 0)
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput)
+	_, _, _, err = uc.Compatible(ctx, "missing", 0)
+	assert.ErrorIs(t, err, pkgdomain.ErrTrackNotFound)
+}
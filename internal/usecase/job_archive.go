@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+const (
+	// jobArchiveBatchSize is how many finished jobs are archived at a time
+	jobArchiveBatchSize = 500
+	// maxJobReportDays bounds the range of a job history report
+	maxJobReportDays = 366
+	// defaultJobReportDays is the range of a job history report without from
+	defaultJobReportDays = 12 * 7
+	// slowestJobTypes is how many job types a report ranks by duration
+	slowestJobTypes = 10
+	// defaultJobHistoryPageSize and maxJobHistoryPageSize bound a page of
+	// archived jobs
+	defaultJobHistoryPageSize = 50
+	maxJobHistoryPageSize     = 500
+)
+
+// JobArchiveUseCase moves finished jobs out of the job queues into the job
+// history and reports on it
+type JobArchiveUseCase struct {
+	repo    domain.JobHistoryRepository
+	sources []domain.FinishedJobSource
+	// after is how long a finished job stays in its queue
+	after time.Duration
+	now   func() time.Time
+}
+
+// NewJobArchiveUseCase creates a job archive use case that archives the jobs
+// of sources that finished more than after ago
+func NewJobArchiveUseCase(repo domain.JobHistoryRepository, after time.Duration, sources ...domain.FinishedJobSource) *JobArchiveUseCase {
+	return &JobArchiveUseCase{repo: repo, sources: sources, after: after, now: time.Now}
+}
+
+// Archive moves the jobs that finished long enough ago to the job history
+// and returns how many it moved. Jobs are removed from their queue only once
+// they are stored, so a failed run archives them again the next time.
+func (uc *JobArchiveUseCase) Archive(ctx context.Context) (int, error) {
+	before := uc.now().Add(-uc.after)
+	archived := 0
+	for _, source := range uc.sources {
+		for {
+			jobs, err := source.FinishedJobs(ctx, before, jobArchiveBatchSize)
+			if err != nil {
+				return archived, err
+			}
+			if len(jobs) == 0 {
+				break
+			}
+			if err := uc.repo.Save(ctx, jobs); err != nil {
+				return archived, err
+			}
+			ids := make([]string, len(jobs))
+			for i, job := range jobs {
+				ids[i] = job.ID
+			}
+			if err := source.RemoveFinished(ctx, ids); err != nil {
+				return archived, err
+			}
+			archived += len(jobs)
+			if len(jobs) < jobArchiveBatchSize {
+				break
+			}
+		}
+	}
+	return archived, nil
+}
+
+// Run archives finished jobs now and every interval until ctx is done
+func (uc *JobArchiveUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := uc.Archive(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to archive finished jobs: %v", err)
+		} else if n > 0 {
+			log.Printf("Archived %d finished jobs", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// List returns a page of the archived jobs matching filter, newest first
+func (uc *JobArchiveUseCase) List(ctx context.Context, filter domain.JobHistoryFilter) (*domain.JobHistoryPage, error) {
+	if filter.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", domain.ErrInvalidInput)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultJobHistoryPageSize
+	}
+	if filter.Limit > maxJobHistoryPageSize {
+		return nil, fmt.Errorf("%w: limit must be at most %d", domain.ErrInvalidInput, maxJobHistoryPageSize)
+	}
+
+	jobs, total, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if jobs == nil {
+		jobs = []*domain.JobHistory{}
+	}
+	return &domain.JobHistoryPage{Total: total, Offset: filter.Offset, Limit: filter.Limit, Jobs: jobs}, nil
+}
+
+// Report returns the weekly success rates and the slowest job types between
+// from and to inclusive. from defaults to twelve weeks before to, and to
+// defaults to today.
+func (uc *JobArchiveUseCase) Report(ctx context.Context, from, to string) (*domain.JobHistoryReport, error) {
+	toDay := uc.now().UTC().Truncate(24 * time.Hour)
+	if to != "" {
+		var err error
+		if toDay, err = time.Parse(domain.UsageDayFormat, to); err != nil {
+			return nil, fmt.Errorf("%w: to must be a date like 2006-01-02", domain.ErrInvalidInput)
+		}
+	}
+	fromDay := toDay.AddDate(0, 0, -defaultJobReportDays+1)
+	if from != "" {
+		var err error
+		if fromDay, err = time.Parse(domain.UsageDayFormat, from); err != nil {
+			return nil, fmt.Errorf("%w: from must be a date like 2006-01-02", domain.ErrInvalidInput)
+		}
+	}
+	if toDay.Before(fromDay) {
+		return nil, fmt.Errorf("%w: to must not be before from", domain.ErrInvalidInput)
+	}
+	if toDay.Sub(fromDay) >= maxJobReportDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: reports cover at most %d days", domain.ErrInvalidInput, maxJobReportDays)
+	}
+
+	end := toDay.AddDate(0, 0, 1)
+	weeks, err := uc.repo.WeeklyStats(ctx, fromDay, end)
+	if err != nil {
+		return nil, err
+	}
+	slowest, err := uc.repo.TypeDurations(ctx, fromDay, end, slowestJobTypes)
+	if err != nil {
+		return nil, err
+	}
+	if weeks == nil {
+		weeks = []domain.JobWeekStats{}
+	}
+	if slowest == nil {
+		slowest = []domain.JobTypeDuration{}
+	}
+	return &domain.JobHistoryReport{
+		From:         fromDay.Format(domain.UsageDayFormat),
+		To:           toDay.Format(domain.UsageDayFormat),
+		Weeks:        weeks,
+		SlowestTypes: slowest,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryJobHistoryRepository struct {
+	jobs    map[string]*domain.JobHistory
+	saveErr error
+	from    time.Time
+	to      time.Time
+}
+
+func (r *memoryJobHistoryRepository) Save(ctx context.Context, jobs []*domain.JobHistory) error {
+	if r.saveErr != nil {
+		return r.saveErr
+	}
+	if r.jobs == nil {
+		r.jobs = make(map[string]*domain.JobHistory)
+	}
+	for _, job := range jobs {
+		if _, ok := r.jobs[job.ID]; !ok {
+			r.jobs[job.ID] = job
+		}
+	}
+	return nil
+}
+
+func (r *memoryJobHistoryRepository) List(ctx context.Context, filter domain.JobHistoryFilter) ([]*domain.JobHistory, int64, error) {
+	return nil, int64(len(r.jobs)), nil
+}
+
+func (r *memoryJobHistoryRepository) WeeklyStats(ctx context.Context, from, to time.Time) ([]domain.JobWeekStats, error) {
+	r.from, r.to = from, to
+	return []domain.JobWeekStats{{Week: "2024-02-26", Type: domain.JobTypeDDEXExport, Completed: 3, Failed: 1, SuccessRate: 0.75}}, nil
+}
+
+func (r *memoryJobHistoryRepository) TypeDurations(ctx context.Context, from, to time.Time, limit int) ([]domain.JobTypeDuration, error) {
+	return nil, nil
+}
+
+// memoryFinishedJobs is a job queue holding jobs that finished at the given
+// times
+type memoryFinishedJobs struct {
+	finished map[string]time.Time
+}
+
+func (s *memoryFinishedJobs) FinishedJobs(ctx context.Context, before time.Time, limit int) ([]*domain.JobHistory, error) {
+	var jobs []*domain.JobHistory
+	for id, at := range s.finished {
+		if at.Before(before) && len(jobs) < limit {
+			jobs = append(jobs, &domain.JobHistory{ID: id, FinishedAt: at})
+		}
+	}
+	return jobs, nil
+}
+
+func (s *memoryFinishedJobs) RemoveFinished(ctx context.Context, jobIDs []string) error {
+	for _, id := range jobIDs {
+		delete(s.finished, id)
+	}
+	return nil
+}
+
+func TestJobArchiveUseCase_ArchivesJobsFinishedLongEnoughAgo(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &memoryJobHistoryRepository{}
+	jobs := &memoryFinishedJobs{finished: map[string]time.Time{"old": now.Add(-2 * time.Hour), "recent": now.Add(-time.Minute)}}
+	replication := &memoryFinishedJobs{finished: map[string]time.Time{"copy": now.Add(-3 * time.Hour)}}
+	uc := NewJobArchiveUseCase(repo, time.Hour, jobs, replication)
+	uc.now = func() time.Time { return now }
+
+	n, err := uc.Archive(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Contains(t, repo.jobs, "old")
+	assert.Contains(t, repo.jobs, "copy")
+	assert.Equal(t, map[string]time.Time{"recent": now.Add(-time.Minute)}, jobs.finished)
+	assert.Empty(t, replication.finished)
+}
+
+func TestJobArchiveUseCase_KeepsJobsInTheQueueWhenTheyCannotBeStored(t *testing.T) {
+	now := time.Now()
+	repo := &memoryJobHistoryRepository{saveErr: errors.New("database is down")}
+	jobs := &memoryFinishedJobs{finished: map[string]time.Time{"old": now.Add(-2 * time.Hour)}}
+	uc := NewJobArchiveUseCase(repo, time.Hour, jobs)
+
+	_, err := uc.Archive(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, jobs.finished, "old")
+}
+
+func TestJobArchiveUseCase_ReportCoversWholeDays(t *testing.T) {
+	repo := &memoryJobHistoryRepository{}
+	uc := NewJobArchiveUseCase(repo, time.Hour)
+	uc.now = func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+
+	report, err := uc.Report(ctx, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "2023-12-09", report.From)
+	assert.Equal(t, "2024-03-01", report.To)
+	assert.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), repo.to)
+	assert.Len(t, report.Weeks, 1)
+	assert.NotNil(t, report.SlowestTypes)
+
+	for _, tc := range []struct{ from, to string }{
+		{"2024-03-01", "2024-02-01"},
+		{"2023-01-01", "2024-03-01"},
+		{"yesterday", ""},
+	} {
+		_, err := uc.Report(ctx, tc.from, tc.to)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput, tc)
+	}
+
+	_, err = uc.List(ctx, domain.JobHistoryFilter{Limit: maxJobHistoryPageSize + 1})
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
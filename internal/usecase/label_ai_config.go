@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// apiKeyHintLength is how much of the end of an API key is kept readable
+const apiKeyHintLength = 4
+
+// LabelAIConfigUseCase manages the AI configurations labels use instead of
+// the global one
+type LabelAIConfigUseCase struct {
+	configs    domain.LabelAIConfigRepository
+	labels     domain.LabelRepository
+	invalidate func(labelID string)
+	now        func() time.Time
+}
+
+// NewLabelAIConfigUseCase creates a new label AI config use case. invalidate
+// is called with the label whose configuration changed and may be nil.
+func NewLabelAIConfigUseCase(configs domain.LabelAIConfigRepository, labels domain.LabelRepository, invalidate func(labelID string)) *LabelAIConfigUseCase {
+	if invalidate == nil {
+		invalidate = func(string) {}
+	}
+	return &LabelAIConfigUseCase{configs: configs, labels: labels, invalidate: invalidate, now: time.Now}
+}
+
+// List returns the labels' configurations, without API keys
+func (uc *LabelAIConfigUseCase) List(ctx context.Context) ([]*domain.LabelAIConfig, error) {
+	return uc.configs.List(ctx)
+}
+
+// Get returns a label's configuration, without its API key
+func (uc *LabelAIConfigUseCase) Get(ctx context.Context, labelID string) (*domain.LabelAIConfig, error) {
+	config, err := uc.configs.Get(ctx, labelID)
+	if err != nil {
+		return nil, err
+	}
+	config.APIKey = ""
+	return config, nil
+}
+
+// Save creates or replaces a label's validated configuration. A config
+// without an API key keeps the stored one when the provider is unchanged.
+func (uc *LabelAIConfigUseCase) Save(ctx context.Context, labelID string, config *domain.LabelAIConfig, userID string) error {
+	if _, err := uc.labels.GetByID(ctx, labelID); err != nil {
+		return err
+	}
+
+	now := uc.now()
+	config.LabelID = labelID
+	config.CreatedAt = now
+	existing, err := uc.configs.Get(ctx, labelID)
+	switch {
+	case err == nil:
+		config.CreatedAt = existing.CreatedAt
+		if config.APIKey == "" && config.Provider == existing.Provider {
+			config.APIKey = existing.APIKey
+		}
+	case !errors.Is(err, domain.ErrLabelAIConfigNotFound):
+		return err
+	}
+	config.APIKeyHint = ""
+	if len(config.APIKey) > apiKeyHintLength {
+		config.APIKeyHint = "…" + config.APIKey[len(config.APIKey)-apiKeyHintLength:]
+	}
+	config.UpdatedBy = userID
+	config.UpdatedAt = now
+
+	if err := uc.configs.Save(ctx, config); err != nil {
+		return err
+	}
+	uc.invalidate(labelID)
+	config.APIKey = ""
+	return nil
+}
+
+// Delete removes a label's configuration, so its tracks use the global one
+// again
+func (uc *LabelAIConfigUseCase) Delete(ctx context.Context, labelID string) error {
+	if err := uc.configs.Delete(ctx, labelID); err != nil {
+		return err
+	}
+	uc.invalidate(labelID)
+	return nil
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryLabelAIConfigRepository keeps label AI configurations in memory
+type memoryLabelAIConfigRepository struct {
+	configs map[string]pkgdomain.LabelAIConfig
+}
+
+func (r *memoryLabelAIConfigRepository) Save(_ context.Context, config *pkgdomain.LabelAIConfig) error {
+	r.configs[config.LabelID] = *config
+	return nil
+}
+
+func (r *memoryLabelAIConfigRepository) Get(_ context.Context, labelID string) (*pkgdomain.LabelAIConfig, error) {
+	config, ok := r.configs[labelID]
+	if !ok {
+		return nil, pkgdomain.ErrLabelAIConfigNotFound
+	}
+	return &config, nil
+}
+
+func (r *memoryLabelAIConfigRepository) List(_ context.Context) ([]*pkgdomain.LabelAIConfig, error) {
+	var configs []*pkgdomain.LabelAIConfig
+	for _, config := range r.configs {
+		config.APIKey = ""
+		configs = append(configs, &config)
+	}
+	sort.Slice(configs, func(i, j int) bool { return configs[i].LabelID < configs[j].LabelID })
+	return configs, nil
+}
+
+func (r *memoryLabelAIConfigRepository) Delete(_ context.Context, labelID string) error {
+	if _, ok := r.configs[labelID]; !ok {
+		return pkgdomain.ErrLabelAIConfigNotFound
+	}
+	delete(r.configs, labelID)
+	return nil
+}
+
+func TestLabelAIConfigUseCase_Save(t *testing.T) {
+	configs := &memoryLabelAIConfigRepository{configs: map[string]pkgdomain.LabelAIConfig{}}
+	labels := &memoryLabelRepository{labels: map[string]*pkgdomain.Label{"acme": {ID: "acme", Name: "Acme"}}}
+	var invalidated []string
+	uc := NewLabelAIConfigUseCase(configs, labels, func(labelID string) { invalidated = append(invalidated, labelID) })
+	ctx := context.Background()
+
+	config := &pkgdomain.LabelAIConfig{Provider: pkgdomain.AIProviderOpenAI, APIKey: "sk-acme-1234", Model: "gpt-4o-2024-08-06"}
+	require.NoError(t, uc.Save(ctx, "acme", config, "admin-1"))
+	assert.Empty(t, config.APIKey, "the key is not returned")
+	assert.Equal(t, "…1234", config.APIKeyHint)
+	assert.Equal(t, "admin-1", config.UpdatedBy)
+	assert.Equal(t, []string{"acme"}, invalidated)
+
+	// Leaving out the key keeps the stored one
+	require.NoError(t, uc.Save(ctx, "acme", &pkgdomain.LabelAIConfig{Provider: pkgdomain.AIProviderOpenAI}, "admin-2"))
+	assert.Equal(t, "sk-acme-1234", configs.configs["acme"].APIKey)
+	assert.Equal(t, "…1234", configs.configs["acme"].APIKeyHint)
+
+	// but not when the provider changes
+	require.NoError(t, uc.Save(ctx, "acme", &pkgdomain.LabelAIConfig{Provider: pkgdomain.AIProviderQwen2}, "admin-2"))
+	assert.Empty(t, configs.configs["acme"].APIKey)
+
+	got, err := uc.Get(ctx, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, pkgdomain.AIProviderQwen2, got.Provider)
+
+	err = uc.Save(ctx, "unknown", &pkgdomain.LabelAIConfig{Provider: pkgdomain.AIProviderOpenAI}, "admin-1")
+	assert.ErrorIs(t, err, pkgdomain.ErrLabelNotFound)
+
+	require.NoError(t, uc.Delete(ctx, "acme"))
+	_, err = uc.Get(ctx, "acme")
+	assert.ErrorIs(t, err, pkgdomain.ErrLabelAIConfigNotFound)
+	assert.Len(t, invalidated, 4)
+}
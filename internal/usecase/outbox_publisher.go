@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+)
+
+// OutboxPublisherConfig holds settings for the change feed publisher
+type OutboxPublisherConfig struct {
+	Topic        string
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+// OutboxPublisher relays track change events from the outbox table to the
+// queue. Events are published strictly in sequence order: a failed publish
+// stops the batch so later events never overtake it.
+type OutboxPublisher struct {
+	outbox    domain.OutboxRepository
+	publisher domain.ChangeFeedPublisher
+	config    OutboxPublisherConfig
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+	once   sync.Once
+}
+
+// NewOutboxPublisher creates a new outbox publisher
+func NewOutboxPublisher(outbox domain.OutboxRepository, publisher domain.ChangeFeedPublisher, config OutboxPublisherConfig) *OutboxPublisher {
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	return &OutboxPublisher{
+		outbox:    outbox,
+		publisher: publisher,
+		config:    config,
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}
+}
+
+// Start begins polling the outbox in the background
+func (p *OutboxPublisher) Start(ctx context.Context) {
+	go func() {
+		defer close(p.doneCh)
+
+		ticker := time.NewTicker(p.config.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := p.PublishPending(ctx); err != nil {
+				log.Printf("Error publishing outbox events: %v", err)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-p.stopCh:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts polling and waits for the current batch to finish
+func (p *OutboxPublisher) Stop() {
+	p.once.Do(func() {
+		close(p.stopCh)
+	})
+	<-p.doneCh
+}
+
+// PublishPending publishes one batch of pending events and returns how many
+// were published
+func (p *OutboxPublisher) PublishPending(ctx context.Context) (int, error) {
+	events, err := p.outbox.FetchPending(ctx, p.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, event := range events {
+		if err := p.publish(ctx, event); err != nil {
+			metrics.OutboxPublishErrors.WithLabelValues(string(event.EventType)).Inc()
+			if markErr := p.outbox.MarkFailed(ctx, event.Sequence, err); markErr != nil {
+				log.Printf("Error recording outbox failure for event %d: %v", event.Sequence, markErr)
+			}
+			p.reportPending(ctx)
+			return published, fmt.Errorf("failed to publish outbox event %d: %w", event.Sequence, err)
+		}
+
+		if err := p.outbox.MarkPublished(ctx, event.Sequence); err != nil {
+			// The event will be redelivered; consumers deduplicate by sequence
+			p.reportPending(ctx)
+			return published, err
+		}
+
+		metrics.OutboxEventsPublished.WithLabelValues(string(event.EventType)).Inc()
+		metrics.OutboxPublishLag.Observe(time.Since(event.CreatedAt).Seconds())
+		published++
+	}
+
+	p.reportPending(ctx)
+	return published, nil
+}
+
+func (p *OutboxPublisher) publish(ctx context.Context, event *domain.OutboxEvent) error {
+	change, err := event.ChangeEvent()
+	if err != nil {
+		return err
+	}
+
+	msg := &domain.Message{
+		ID:   strconv.FormatInt(event.Sequence, 10),
+		Type: string(change.Type),
+		Data: map[string]interface{}{
+			"sequence":    change.Sequence,
+			"type":        change.Type,
+			"track_id":    change.TrackID,
+			"version":     change.Version,
+			"track":       change.Track,
+			"occurred_at": change.OccurredAt,
+		},
+		Status:    domain.MessageStatusPending,
+		CreatedAt: event.CreatedAt,
+		UpdatedAt: time.Now(),
+	}
+
+	return p.publisher.PublishOrdered(ctx, p.config.Topic, change.TrackID, msg)
+}
+
+func (p *OutboxPublisher) reportPending(ctx context.Context) {
+	count, err := p.outbox.PendingCount(ctx)
+	if err != nil {
+		return
+	}
+	metrics.OutboxPendingEvents.Set(float64(count))
+}
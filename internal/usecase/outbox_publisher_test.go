@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOutboxRepository is an in-memory pkg/domain.OutboxRepository
+type fakeOutboxRepository struct {
+	events    []*pkgdomain.OutboxEvent
+	published map[int64]bool
+	failures  map[int64]int
+}
+
+func newFakeOutboxRepository(events ...*pkgdomain.OutboxEvent) *fakeOutboxRepository {
+	return &fakeOutboxRepository{
+		events:    events,
+		published: make(map[int64]bool),
+		failures:  make(map[int64]int),
+	}
+}
+
+func (r *fakeOutboxRepository) FetchPending(ctx context.Context, limit int) ([]*pkgdomain.OutboxEvent, error) {
+	var pending []*pkgdomain.OutboxEvent
+	for _, e := range r.events {
+		if !r.published[e.Sequence] && len(pending) < limit {
+			pending = append(pending, e)
+		}
+	}
+	return pending, nil
+}
+
+func (r *fakeOutboxRepository) MarkPublished(ctx context.Context, sequence int64) error {
+	r.published[sequence] = true
+	return nil
+}
+
+func (r *fakeOutboxRepository) MarkFailed(ctx context.Context, sequence int64, err error) error {
+	r.failures[sequence]++
+	return nil
+}
+
+func (r *fakeOutboxRepository) PendingCount(ctx context.Context) (int64, error) {
+	return int64(len(r.events) - len(r.published)), nil
+}
+
+// MockChangeFeedPublisher is a mock implementation of pkg/domain.ChangeFeedPublisher
+type MockChangeFeedPublisher struct {
+	mock.Mock
+}
+
+func (m *MockChangeFeedPublisher) PublishOrdered(ctx context.Context, topic, orderingKey string, message *pkgdomain.Message) error {
+	args := m.Called(ctx, topic, orderingKey, message)
+	return args.Error(0)
+}
+
+func newOutboxEvent(t *testing.T, sequence int64, changeType pkgdomain.TrackChangeType, trackID string) *pkgdomain.OutboxEvent {
+	event, err := pkgdomain.NewTrackOutboxEvent(changeType, &pkgdomain.Track{ID: trackID, Version: 1})
+	require.NoError(t, err)
+	event.Sequence = sequence
+	return event
+}
+
+func messageID(id string) interface{} {
+	return mock.MatchedBy(func(msg *pkgdomain.Message) bool { return msg.ID == id })
+}
+
+func TestOutboxPublisher_PublishesInSequenceOrder(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeOutboxRepository(
+		newOutboxEvent(t, 1, pkgdomain.TrackChangeCreated, "track-1"),
+		newOutboxEvent(t, 2, pkgdomain.TrackChangeUpdated, "track-1"),
+		newOutboxEvent(t, 3, pkgdomain.TrackChangeDeleted, "track-2"),
+	)
+	publisher := new(MockChangeFeedPublisher)
+
+	var order []string
+	publisher.On("PublishOrdered", ctx, "track-changes", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			order = append(order, args.Get(3).(*pkgdomain.Message).ID)
+		}).
+		Return(nil)
+
+	p := NewOutboxPublisher(repo, publisher, OutboxPublisherConfig{Topic: "track-changes"})
+	published, err := p.PublishPending(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, published)
+	assert.Equal(t, []string{"1", "2", "3"}, order)
+	publisher.AssertCalled(t, "PublishOrdered", ctx, "track-changes", "track-2", messageID("3"))
+}
+
+func TestOutboxPublisher_StopsAtFirstFailure(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeOutboxRepository(
+		newOutboxEvent(t, 1, pkgdomain.TrackChangeCreated, "track-1"),
+		newOutboxEvent(t, 2, pkgdomain.TrackChangeUpdated, "track-1"),
+		newOutboxEvent(t, 3, pkgdomain.TrackChangeUpdated, "track-1"),
+	)
+	publisher := new(MockChangeFeedPublisher)
+	publisher.On("PublishOrdered", ctx, "track-changes", "track-1", messageID("1")).Return(nil)
+	publisher.On("PublishOrdered", ctx, "track-changes", "track-1", messageID("2")).Return(errors.New("unavailable")).Once()
+
+	p := NewOutboxPublisher(repo, publisher, OutboxPublisherConfig{Topic: "track-changes"})
+	published, err := p.PublishPending(ctx)
+
+	require.Error(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, 1, repo.failures[2])
+	assert.False(t, repo.published[3])
+	publisher.AssertNotCalled(t, "PublishOrdered", ctx, "track-changes", "track-1", messageID("3"))
+
+	// The next run resumes from the failed event
+	publisher.On("PublishOrdered", ctx, "track-changes", "track-1", mock.Anything).Return(nil)
+	published, err = p.PublishPending(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.True(t, repo.published[3])
+}
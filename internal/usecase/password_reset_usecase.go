@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// PasswordResetConfig holds settings for the password reset flow
+type PasswordResetConfig struct {
+	// Secret signs reset tokens
+	Secret string
+	// TokenTTL is how long a reset token stays valid
+	TokenTTL time.Duration
+	// MaxRequests is the number of reset requests allowed per email address
+	// and per client IP within Window
+	MaxRequests int
+	Window      time.Duration
+	// MinPasswordLength is the minimum length of the new password
+	MinPasswordLength int
+}
+
+// PasswordResetUseCase issues and redeems password reset tokens.
+//
+// A token has the form <id>.<expiry>.<signature>. The id is random and is the
+// only part stored in Redis; the signature binds it to the user and expiry so
+// a token cannot be altered or replayed against another account.
+type PasswordResetUseCase struct {
+	userRepo    domain.UserRepository
+	sessions    domain.SessionStore
+	authService domain.AuthService
+	store       domain.PasswordResetStore
+	notifier    domain.PasswordResetNotifier
+	config      PasswordResetConfig
+
+	refreshTokens domain.RefreshTokenStore
+	refreshTTL    time.Duration
+}
+
+// NewPasswordResetUseCase creates a new password reset use case
+func NewPasswordResetUseCase(
+	userRepo domain.UserRepository,
+	sessions domain.SessionStore,
+	authService domain.AuthService,
+	store domain.PasswordResetStore,
+	notifier domain.PasswordResetNotifier,
+	config PasswordResetConfig,
+) *PasswordResetUseCase {
+	if config.TokenTTL <= 0 {
+		config.TokenTTL = 30 * time.Minute
+	}
+	if config.Window <= 0 {
+		config.Window = time.Hour
+	}
+	return &PasswordResetUseCase{
+		userRepo:    userRepo,
+		sessions:    sessions,
+		authService: authService,
+		store:       store,
+		notifier:    notifier,
+		config:      config,
+	}
+}
+
+// SetRefreshTokenStore makes a reset revoke every token issued to the user,
+// refresh token families included. refreshTTL is the lifetime of refresh
+// tokens, which the revocation must outlive.
+func (uc *PasswordResetUseCase) SetRefreshTokenStore(store domain.RefreshTokenStore, refreshTTL time.Duration) {
+	uc.refreshTokens = store
+	uc.refreshTTL = refreshTTL
+}
+
+// ForgotPassword sends a reset token to the account registered with email.
+// Unknown addresses are not reported so the endpoint cannot be used to
+// discover accounts.
+func (uc *PasswordResetUseCase) ForgotPassword(ctx context.Context, email, clientIP string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if err := uc.checkRate(ctx, "forgot:email:"+email); err != nil {
+		return err
+	}
+	if err := uc.checkRate(ctx, "forgot:ip:"+clientIP); err != nil {
+		return err
+	}
+
+	user, err := uc.userRepo.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		return fmt.Errorf("error getting user: %w", err)
+	}
+	if user == nil {
+		return nil
+	}
+
+	tokenID, err := randomTokenID()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(uc.config.TokenTTL)
+	if err := uc.store.Save(ctx, tokenID, user.ID, uc.config.TokenTTL); err != nil {
+		return fmt.Errorf("error storing reset token: %w", err)
+	}
+
+	token := tokenID + "." + strconv.FormatInt(expiresAt.Unix(), 10) + "." + uc.sign(tokenID, user.ID, expiresAt.Unix())
+	if err := uc.notifier.SendPasswordReset(ctx, user, token, expiresAt); err != nil {
+		return fmt.Errorf("error sending reset token: %w", err)
+	}
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token and signs the user
+// out of every session. Tokens issued before the reset are revoked before
+// the password changes, so a failed revocation leaves the old password in
+// place rather than the old tokens working.
+func (uc *PasswordResetUseCase) ResetPassword(ctx context.Context, token, newPassword, clientIP string) error {
+	if err := uc.checkRate(ctx, "reset:ip:"+clientIP); err != nil {
+		return err
+	}
+	if len(newPassword) < uc.config.MinPasswordLength {
+		return domain.ErrWeakPassword
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return domain.ErrInvalidToken
+	}
+	expiry, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expiry {
+		return domain.ErrInvalidToken
+	}
+
+	userID, err := uc.store.Consume(ctx, parts[0])
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(uc.sign(parts[0], userID, expiry))) {
+		return domain.ErrInvalidToken
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("error getting user: %w", err)
+	}
+	if user == nil {
+		return domain.ErrInvalidToken
+	}
+
+	hashed, err := uc.authService.HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %w", err)
+	}
+	if uc.refreshTokens != nil {
+		if err := uc.refreshTokens.RevokeUser(ctx, user.ID, uc.refreshTTL); err != nil {
+			return fmt.Errorf("error revoking tokens: %w", err)
+		}
+	}
+	user.Password = hashed
+	user.UpdatedAt = time.Now()
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("error updating user: %w", err)
+	}
+
+	// The password is already changed, so a failure here must not be
+	// reported as a failed reset
+	if err := uc.sessions.DeleteUserSessions(ctx, user.ID); err != nil {
+		log.Printf("failed to revoke sessions for user %s after password reset: %v", user.ID, err)
+	}
+	return nil
+}
+
+func (uc *PasswordResetUseCase) checkRate(ctx context.Context, key string) error {
+	if uc.config.MaxRequests <= 0 {
+		return nil
+	}
+	count, err := uc.store.Hit(ctx, key, uc.config.Window)
+	if err != nil {
+		return fmt.Errorf("error checking reset rate limit: %w", err)
+	}
+	if count > int64(uc.config.MaxRequests) {
+		return domain.ErrResetRateLimited
+	}
+	return nil
+}
+
+func (uc *PasswordResetUseCase) sign(tokenID, userID string, expiry int64) string {
+	mac := hmac.New(sha256.New, []byte(uc.config.Secret))
+	fmt.Fprintf(mac, "%s.%s.%d", tokenID, userID, expiry)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func randomTokenID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate reset token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/config"
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePasswordResetStore struct {
+	tokens map[string]string
+	hits   map[string]int64
+}
+
+func newFakePasswordResetStore() *fakePasswordResetStore {
+	return &fakePasswordResetStore{tokens: map[string]string{}, hits: map[string]int64{}}
+}
+
+func (s *fakePasswordResetStore) Save(ctx context.Context, tokenID, userID string, ttl time.Duration) error {
+	s.tokens[tokenID] = userID
+	return nil
+}
+
+func (s *fakePasswordResetStore) Consume(ctx context.Context, tokenID string) (string, error) {
+	userID, ok := s.tokens[tokenID]
+	if !ok {
+		return "", domain.ErrInvalidToken
+	}
+	delete(s.tokens, tokenID)
+	return userID, nil
+}
+
+func (s *fakePasswordResetStore) Hit(ctx context.Context, key string, window time.Duration) (int64, error) {
+	s.hits[key]++
+	return s.hits[key], nil
+}
+
+type captureNotifier struct {
+	token string
+}
+
+func (n *captureNotifier) SendPasswordReset(ctx context.Context, user *domain.User, token string, expiresAt time.Time) error {
+	n.token = token
+	return nil
+}
+
+func setupPasswordReset() (*PasswordResetUseCase, *MockUserRepository, *MockSessionStore, *captureNotifier) {
+	userRepo := new(MockUserRepository)
+	sessions := new(MockSessionStore)
+	authService := new(MockAuthService)
+	authService.On("HashPassword", mock.Anything).Return("new-hash", nil)
+	notifier := &captureNotifier{}
+
+	uc := NewPasswordResetUseCase(userRepo, sessions, authService, newFakePasswordResetStore(), notifier, PasswordResetConfig{
+		Secret:            "secret",
+		TokenTTL:          time.Minute,
+		MaxRequests:       2,
+		Window:            time.Hour,
+		MinPasswordLength: 8,
+	})
+	return uc, userRepo, sessions, notifier
+}
+
+func TestPasswordResetUseCase_ResetInvalidatesSessions(t *testing.T) {
+	uc, userRepo, sessions, notifier := setupPasswordReset()
+	user := createTestUser()
+	userRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	userRepo.On("Update", mock.Anything, user).Return(nil)
+	sessions.On("DeleteUserSessions", mock.Anything, user.ID).Return(nil)
+
+	require.NoError(t, uc.ForgotPassword(context.Background(), user.Email, "10.0.0.1"))
+	require.NotEmpty(t, notifier.token)
+
+	require.NoError(t, uc.ResetPassword(context.Background(), notifier.token, "new-password", "10.0.0.1"))
+	assert.Equal(t, "new-hash", user.Password)
+	sessions.AssertCalled(t, "DeleteUserSessions", mock.Anything, user.ID)
+
+	// A token can only be used once
+	err := uc.ResetPassword(context.Background(), notifier.token, "new-password", "10.0.0.1")
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+}
+
+func TestPasswordResetUseCase_ResetRevokesRefreshTokens(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryRefreshTokenStore()
+	authService := NewRevocationAwareAuthService(NewAuthService(&config.AuthConfig{
+		JWTSecret:       "test-secret",
+		AccessTokenTTL:  time.Minute,
+		RefreshTokenTTL: time.Hour,
+	}), store)
+
+	user := createTestUser()
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+	userRepo.On("Update", mock.Anything, user).Return(nil)
+	sessions := new(MockSessionStore)
+	sessions.On("DeleteUserSessions", mock.Anything, user.ID).Return(nil)
+
+	auth := NewAuthUseCase(userRepo, sessions, authService)
+	auth.SetRefreshTokenStore(store, time.Hour)
+	tokens, err := authService.GenerateTokens(user)
+	require.NoError(t, err)
+	require.NoError(t, store.Issue(ctx, user.ID, tokens.FamilyID, tokens.RefreshTokenID, time.Hour))
+
+	notifier := &captureNotifier{}
+	uc := NewPasswordResetUseCase(userRepo, sessions, authService, newFakePasswordResetStore(), notifier, PasswordResetConfig{
+		Secret:            "secret",
+		MinPasswordLength: 8,
+	})
+	uc.SetRefreshTokenStore(store, time.Hour)
+
+	require.NoError(t, uc.ForgotPassword(ctx, user.Email, "10.0.0.1"))
+	require.NoError(t, uc.ResetPassword(ctx, notifier.token, "new-password", "10.0.0.1"))
+
+	// Refresh tokens from before the reset no longer work
+	_, _, _, err = auth.RefreshToken(ctx, tokens.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	_, err = auth.ValidateToken(ctx, tokens.AccessToken)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+}
+
+func TestPasswordResetUseCase_RejectsTamperedToken(t *testing.T) {
+	uc, userRepo, _, notifier := setupPasswordReset()
+	user := createTestUser()
+	userRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+
+	require.NoError(t, uc.ForgotPassword(context.Background(), user.Email, "10.0.0.1"))
+
+	parts := strings.Split(notifier.token, ".")
+	parts[1] = "9999999999"
+	err := uc.ResetPassword(context.Background(), strings.Join(parts, "."), "new-password", "10.0.0.1")
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestPasswordResetUseCase_UnknownEmailAndRateLimit(t *testing.T) {
+	uc, userRepo, _, notifier := setupPasswordReset()
+	userRepo.On("GetByEmail", mock.Anything, "nobody@example.com").Return(nil, domain.ErrUserNotFound)
+
+	require.NoError(t, uc.ForgotPassword(context.Background(), "nobody@example.com", "10.0.0.1"))
+	require.NoError(t, uc.ForgotPassword(context.Background(), "nobody@example.com", "10.0.0.1"))
+	assert.Empty(t, notifier.token)
+
+	err := uc.ForgotPassword(context.Background(), "nobody@example.com", "10.0.0.1")
+	assert.ErrorIs(t, err, domain.ErrResetRateLimited)
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"metadatatool/internal/pkg/ddex"
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+)
+
+// PlayCountUseCase ingests DSP usage reports and reports the play counts
+// royalties are calculated from
+type PlayCountUseCase struct {
+	counts domain.PlayCountRepository
+	tracks domain.TrackRepository
+	now    func() time.Time
+}
+
+// NewPlayCountUseCase creates a new play count use case
+func NewPlayCountUseCase(counts domain.PlayCountRepository, tracks domain.TrackRepository) *PlayCountUseCase {
+	return &PlayCountUseCase{counts: counts, tracks: tracks, now: time.Now}
+}
+
+// playCountKey identifies the play count a usage adds to
+type playCountKey struct {
+	trackID   string
+	territory string
+}
+
+// Ingest reads a DSR flat file and adds its plays to the track's counts for
+// the month the report's usage period starts in. dsp names the DSP that
+// sent the report and defaults to the report's sender. Usages of ISRCs
+// that match no track are counted in the result but not stored.
+func (uc *PlayCountUseCase) Ingest(ctx context.Context, dsp string, r io.Reader) (*domain.SalesReport, error) {
+	parsed, err := ddex.ParseDSR(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+	if dsp == "" {
+		dsp = parsed.SenderName
+	}
+	if dsp == "" {
+		return nil, fmt.Errorf("%w: name the DSP; the report has no SenderName", domain.ErrInvalidInput)
+	}
+
+	now := uc.now()
+	report := &domain.SalesReport{
+		DSP:        dsp,
+		MessageID:  parsed.MessageID,
+		Month:      parsed.UsageStart.Format(domain.PlayMonthFormat),
+		Records:    len(parsed.Usages),
+		IngestedAt: now,
+	}
+
+	// Look every ISRC up once; reports list a track once per territory
+	trackIDs := make(map[string]string)
+	totals := make(map[playCountKey]*domain.PlayCount)
+	for _, usage := range parsed.Usages {
+		trackID, ok := trackIDs[usage.ISRC]
+		if !ok {
+			track, err := uc.tracks.GetByISRC(ctx, usage.ISRC)
+			if err != nil {
+				return nil, err
+			}
+			if track != nil {
+				trackID = track.ID
+			}
+			trackIDs[usage.ISRC] = trackID
+		}
+		if trackID == "" {
+			report.Unmatched++
+			continue
+		}
+
+		report.Plays += usage.Plays
+		key := playCountKey{trackID: trackID, territory: usage.Territory}
+		if count, ok := totals[key]; ok {
+			count.Plays += usage.Plays
+			continue
+		}
+		totals[key] = &domain.PlayCount{
+			TrackID:   trackID,
+			DSP:       dsp,
+			Territory: usage.Territory,
+			Month:     report.Month,
+			ISRC:      usage.ISRC,
+			Plays:     usage.Plays,
+			UpdatedAt: now,
+		}
+	}
+
+	counts := make([]*domain.PlayCount, 0, len(totals))
+	for _, count := range totals {
+		counts = append(counts, count)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].TrackID != counts[j].TrackID {
+			return counts[i].TrackID < counts[j].TrackID
+		}
+		return counts[i].Territory < counts[j].Territory
+	})
+
+	if err := uc.counts.Ingest(ctx, report, counts); err != nil {
+		return nil, err
+	}
+	metrics.PlaysIngested.WithLabelValues(dsp).Add(float64(report.Plays))
+	return report, nil
+}
+
+// List returns the play counts matching filter
+func (uc *PlayCountUseCase) List(ctx context.Context, filter domain.PlayCountFilter) ([]*domain.PlayCount, error) {
+	for _, month := range []string{filter.From, filter.To} {
+		if month == "" {
+			continue
+		}
+		if _, err := time.Parse(domain.PlayMonthFormat, month); err != nil {
+			return nil, fmt.Errorf("%w: month %q is not YYYY-MM", domain.ErrInvalidInput, month)
+		}
+	}
+	if filter.From != "" && filter.To != "" && filter.From > filter.To {
+		return nil, fmt.Errorf("%w: from is after to", domain.ErrInvalidInput)
+	}
+	return uc.counts.List(ctx, filter)
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryPlayCountRepository keeps play counts in memory
+type memoryPlayCountRepository struct {
+	reports []*pkgdomain.SalesReport
+	counts  []*pkgdomain.PlayCount
+}
+
+func (r *memoryPlayCountRepository) Ingest(_ context.Context, report *pkgdomain.SalesReport, counts []*pkgdomain.PlayCount) error {
+	for _, existing := range r.reports {
+		if existing.DSP == report.DSP && existing.MessageID == report.MessageID {
+			return pkgdomain.ErrSalesReportIngested
+		}
+	}
+	r.reports = append(r.reports, report)
+	r.counts = append(r.counts, counts...)
+	return nil
+}
+
+func (r *memoryPlayCountRepository) List(_ context.Context, _ pkgdomain.PlayCountFilter) ([]*pkgdomain.PlayCount, error) {
+	return r.counts, nil
+}
+
+const testSalesReport = "#HEAD\tMessageId\tSenderName\tUsageStartDate\tUsageEndDate\n" +
+	"#SY02\tCommercialModel\tTerritoryOfUse\n" +
+	"#AS02\tISRC\tNumberOfStreams\n" +
+	"HEAD\tMSG-1\tSpotify\t2024-03-01\t2024-03-31\n" +
+	"SY02\tSubscriptionModel\tSE\n" +
+	"AS02\tSEABC2400001\t100\n" +
+	"AS02\tXXABC2400009\t7\n" +
+	"SY02\tAdSupportedModel\tSE\n" +
+	"AS02\tSEABC2400001\t20\n" +
+	"SY02\tAdSupportedModel\tNO\n" +
+	"AS02\tSEABC2400001\t5\n"
+
+func TestPlayCountUseCase_Ingest(t *testing.T) {
+	newUseCase := func() (*PlayCountUseCase, *memoryPlayCountRepository, *MockTrackRepository) {
+		trackRepo := new(MockTrackRepository)
+		trackRepo.On("GetByISRC", mock.Anything, "SEABC2400001").Return(&pkgdomain.Track{ID: "t1"}, nil)
+		trackRepo.On("GetByISRC", mock.Anything, "XXABC2400009").Return(nil, nil)
+		repo := &memoryPlayCountRepository{}
+		return NewPlayCountUseCase(repo, trackRepo), repo, trackRepo
+	}
+
+	t.Run("aggregates per track and territory", func(t *testing.T) {
+		uc, repo, trackRepo := newUseCase()
+
+		report, err := uc.Ingest(context.Background(), "", strings.NewReader(testSalesReport))
+		require.NoError(t, err)
+		assert.Equal(t, "Spotify", report.DSP)
+		assert.Equal(t, "2024-03", report.Month)
+		assert.Equal(t, 4, report.Records)
+		assert.Equal(t, 1, report.Unmatched)
+		assert.EqualValues(t, 125, report.Plays)
+
+		require.Len(t, repo.counts, 2)
+		assert.Equal(t, "NO", repo.counts[0].Territory)
+		assert.EqualValues(t, 5, repo.counts[0].Plays)
+		assert.Equal(t, "SE", repo.counts[1].Territory)
+		assert.EqualValues(t, 120, repo.counts[1].Plays)
+		assert.Equal(t, "2024-03", repo.counts[1].Month)
+		trackRepo.AssertNumberOfCalls(t, "GetByISRC", 2)
+	})
+
+	t.Run("report ingested once", func(t *testing.T) {
+		uc, _, _ := newUseCase()
+
+		_, err := uc.Ingest(context.Background(), "spotify", strings.NewReader(testSalesReport))
+		require.NoError(t, err)
+		_, err = uc.Ingest(context.Background(), "spotify", strings.NewReader(testSalesReport))
+		assert.True(t, errors.Is(err, pkgdomain.ErrSalesReportIngested))
+	})
+
+	t.Run("invalid report", func(t *testing.T) {
+		uc, _, _ := newUseCase()
+
+		_, err := uc.Ingest(context.Background(), "spotify", strings.NewReader("HEAD\tMSG-1\n"))
+		assert.True(t, errors.Is(err, pkgdomain.ErrInvalidInput))
+	})
+}
+
+func TestPlayCountUseCase_List(t *testing.T) {
+	uc := NewPlayCountUseCase(&memoryPlayCountRepository{}, new(MockTrackRepository))
+
+	_, err := uc.List(context.Background(), pkgdomain.PlayCountFilter{From: "2024-01", To: "2024-03"})
+	assert.NoError(t, err)
+	_, err = uc.List(context.Background(), pkgdomain.PlayCountFilter{From: "2024-1"})
+	assert.True(t, errors.Is(err, pkgdomain.ErrInvalidInput))
+	_, err = uc.List(context.Background(), pkgdomain.PlayCountFilter{From: "2024-04", To: "2024-03"})
+	assert.True(t, errors.Is(err, pkgdomain.ErrInvalidInput))
+}
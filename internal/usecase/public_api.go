@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// publicAPIKeyBytes is the entropy of a public API key
+	publicAPIKeyBytes = 24
+	// publicAPIKeyShown is how many characters after PublicAPIKeyPrefix are
+	// kept to tell keys apart
+	publicAPIKeyShown = 6
+)
+
+// PublicAPIKeyRequest creates a public API key
+type PublicAPIKeyRequest struct {
+	Name    string `json:"name" binding:"required"`
+	LabelID string `json:"label_id" binding:"required"`
+	// Tier defaults to the free tier
+	Tier string `json:"tier,omitempty"`
+}
+
+// CreatedPublicAPIKey is a new public API key with its secret, which is not
+// shown again
+type CreatedPublicAPIKey struct {
+	*domain.PublicAPIKey
+	Key string `json:"key"`
+}
+
+// PublicAPIUseCase serves a label's approved tracks to the partners holding
+// its public API keys and manages the keys
+type PublicAPIUseCase struct {
+	keys   domain.PublicAPIKeyRepository
+	tracks domain.TrackRepository
+	quota  domain.PublicAPIQuota
+	tiers  []domain.PublicAPITier
+	now    func() time.Time
+}
+
+// NewPublicAPIUseCase creates a new public API use case. Keys can only be
+// created on the given tiers.
+func NewPublicAPIUseCase(keys domain.PublicAPIKeyRepository, tracks domain.TrackRepository, quota domain.PublicAPIQuota, tiers []domain.PublicAPITier) *PublicAPIUseCase {
+	return &PublicAPIUseCase{keys: keys, tracks: tracks, quota: quota, tiers: tiers, now: time.Now}
+}
+
+// Tiers returns the tiers keys can be created on
+func (uc *PublicAPIUseCase) Tiers() []domain.PublicAPITier {
+	return uc.tiers
+}
+
+// CreateKey issues a key reading a label's tracks
+func (uc *PublicAPIUseCase) CreateKey(ctx context.Context, req PublicAPIKeyRequest) (*CreatedPublicAPIKey, error) {
+	if strings.TrimSpace(req.Name) == "" || strings.TrimSpace(req.LabelID) == "" {
+		return nil, fmt.Errorf("%w: name and label_id are required", domain.ErrInvalidInput)
+	}
+	if req.Tier == "" {
+		req.Tier = domain.PublicAPITierFree
+	}
+	if _, ok := uc.tier(req.Tier); !ok {
+		return nil, fmt.Errorf("%w: unknown tier %q", domain.ErrInvalidInput, req.Tier)
+	}
+
+	secret := make([]byte, publicAPIKeyBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate public API key: %w", err)
+	}
+	raw := domain.PublicAPIKeyPrefix + hex.EncodeToString(secret)
+	key := &domain.PublicAPIKey{
+		ID:        uuid.New().String(),
+		Name:      strings.TrimSpace(req.Name),
+		LabelID:   strings.TrimSpace(req.LabelID),
+		Tier:      req.Tier,
+		Prefix:    raw[:len(domain.PublicAPIKeyPrefix)+publicAPIKeyShown],
+		KeyHash:   domain.HashPublicAPIKey(raw),
+		CreatedAt: uc.now(),
+	}
+	if err := uc.keys.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	return &CreatedPublicAPIKey{PublicAPIKey: key, Key: raw}, nil
+}
+
+// ListKeys returns the keys of a label, or of every label when labelID is
+// empty
+func (uc *PublicAPIUseCase) ListKeys(ctx context.Context, labelID string) ([]*domain.PublicAPIKey, error) {
+	return uc.keys.List(ctx, labelID)
+}
+
+// RevokeKey stops a key from being accepted
+func (uc *PublicAPIUseCase) RevokeKey(ctx context.Context, id string) error {
+	return uc.keys.Revoke(ctx, id, uc.now())
+}
+
+// Authenticate returns the key a request presented, or
+// ErrPublicAPIKeyNotFound for unknown and revoked keys
+func (uc *PublicAPIUseCase) Authenticate(ctx context.Context, raw string) (*domain.PublicAPIKey, error) {
+	if !strings.HasPrefix(raw, domain.PublicAPIKeyPrefix) {
+		return nil, domain.ErrPublicAPIKeyNotFound
+	}
+	key, err := uc.keys.GetByHash(ctx, domain.HashPublicAPIKey(raw))
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, domain.ErrPublicAPIKeyNotFound
+	}
+	return key, nil
+}
+
+// Take counts a request by key against the quotas of its tier. Keys on a
+// tier that is no longer configured get the free tier's quotas.
+func (uc *PublicAPIUseCase) Take(ctx context.Context, key *domain.PublicAPIKey) (*domain.PublicAPIAllowance, error) {
+	tier, ok := uc.tier(key.Tier)
+	if !ok {
+		tier, _ = uc.tier(domain.PublicAPITierFree)
+	}
+	return uc.quota.Take(ctx, key.ID, tier, uc.now())
+}
+
+// ListTracks returns a page of the approved tracks of the key's label
+func (uc *PublicAPIUseCase) ListTracks(ctx context.Context, key *domain.PublicAPIKey, offset, limit int) ([]*domain.PublicTrack, error) {
+	filter := map[string]interface{}{
+		"label_id": key.LabelID,
+		"status":   domain.PublicTrackStatuses,
+	}
+	tracks, err := uc.tracks.List(ctx, filter, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	public := make([]*domain.PublicTrack, 0, len(tracks))
+	for _, track := range tracks {
+		if track.IsPublic() && track.LabelID == key.LabelID {
+			public = append(public, domain.NewPublicTrack(track))
+		}
+	}
+	return public, nil
+}
+
+// GetTrack returns an approved track of the key's label. Other tracks are
+// reported missing rather than forbidden so their existence is not leaked.
+func (uc *PublicAPIUseCase) GetTrack(ctx context.Context, key *domain.PublicAPIKey, id string) (*domain.PublicTrack, error) {
+	track, err := uc.tracks.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if track == nil || !track.IsPublic() || track.LabelID != key.LabelID {
+		return nil, domain.ErrTrackNotFound
+	}
+	return domain.NewPublicTrack(track), nil
+}
+
+func (uc *PublicAPIUseCase) tier(name string) (domain.PublicAPITier, bool) {
+	for _, tier := range uc.tiers {
+		if tier.Name == name {
+			return tier, true
+		}
+	}
+	return domain.PublicAPITier{}, false
+}
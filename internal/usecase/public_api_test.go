@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryPublicAPIKeyRepository struct {
+	keys []*pkgdomain.PublicAPIKey
+}
+
+func (r *memoryPublicAPIKeyRepository) Create(_ context.Context, key *pkgdomain.PublicAPIKey) error {
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *memoryPublicAPIKeyRepository) GetByHash(_ context.Context, hash string) (*pkgdomain.PublicAPIKey, error) {
+	for _, key := range r.keys {
+		if key.KeyHash == hash {
+			return key, nil
+		}
+	}
+	return nil, pkgdomain.ErrPublicAPIKeyNotFound
+}
+
+func (r *memoryPublicAPIKeyRepository) List(_ context.Context, labelID string) ([]*pkgdomain.PublicAPIKey, error) {
+	var out []*pkgdomain.PublicAPIKey
+	for _, key := range r.keys {
+		if labelID == "" || key.LabelID == labelID {
+			out = append(out, key)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryPublicAPIKeyRepository) Revoke(_ context.Context, id string, at time.Time) error {
+	for _, key := range r.keys {
+		if key.ID == id {
+			key.RevokedAt = &at
+			return nil
+		}
+	}
+	return pkgdomain.ErrPublicAPIKeyNotFound
+}
+
+type recordingPublicAPIQuota struct {
+	tiers []pkgdomain.PublicAPITier
+}
+
+func (q *recordingPublicAPIQuota) Take(_ context.Context, _ string, tier pkgdomain.PublicAPITier, _ time.Time) (*pkgdomain.PublicAPIAllowance, error) {
+	q.tiers = append(q.tiers, tier)
+	return &pkgdomain.PublicAPIAllowance{Tier: tier}, nil
+}
+
+var testPublicAPITiers = []pkgdomain.PublicAPITier{
+	{Name: pkgdomain.PublicAPITierFree, RequestsPerMinute: 1, RequestsPerDay: 10},
+	{Name: pkgdomain.PublicAPITierPartner, RequestsPerMinute: 100},
+}
+
+func TestPublicAPIUseCase_KeyLifecycle(t *testing.T) {
+	keys := &memoryPublicAPIKeyRepository{}
+	quota := &recordingPublicAPIQuota{}
+	uc := NewPublicAPIUseCase(keys, new(MockTrackRepository), quota, testPublicAPITiers)
+	ctx := context.Background()
+
+	_, err := uc.CreateKey(ctx, PublicAPIKeyRequest{Name: "dsp", LabelID: "label-a", Tier: "platinum"})
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput)
+	_, err = uc.CreateKey(ctx, PublicAPIKeyRequest{Name: "dsp"})
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput)
+
+	created, err := uc.CreateKey(ctx, PublicAPIKeyRequest{Name: "dsp", LabelID: "label-a"})
+	require.NoError(t, err)
+	assert.Equal(t, pkgdomain.PublicAPITierFree, created.Tier)
+	assert.True(t, strings.HasPrefix(created.Key, pkgdomain.PublicAPIKeyPrefix))
+	assert.True(t, strings.HasPrefix(created.Key, created.Prefix))
+	assert.NotContains(t, created.KeyHash, created.Key)
+
+	key, err := uc.Authenticate(ctx, created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, key.ID)
+	_, err = uc.Authenticate(ctx, created.Key+"x")
+	assert.ErrorIs(t, err, pkgdomain.ErrPublicAPIKeyNotFound)
+	_, err = uc.Authenticate(ctx, "not-a-key")
+	assert.ErrorIs(t, err, pkgdomain.ErrPublicAPIKeyNotFound)
+
+	_, err = uc.Take(ctx, key)
+	require.NoError(t, err)
+	// Keys on tiers that are no longer configured fall back to free
+	_, err = uc.Take(ctx, &pkgdomain.PublicAPIKey{ID: "old", Tier: "legacy"})
+	require.NoError(t, err)
+	assert.Equal(t, []pkgdomain.PublicAPITier{testPublicAPITiers[0], testPublicAPITiers[0]}, quota.tiers)
+
+	require.NoError(t, uc.RevokeKey(ctx, created.ID))
+	_, err = uc.Authenticate(ctx, created.Key)
+	assert.ErrorIs(t, err, pkgdomain.ErrPublicAPIKeyNotFound)
+	assert.ErrorIs(t, uc.RevokeKey(ctx, "missing"), pkgdomain.ErrPublicAPIKeyNotFound)
+}
+
+func TestPublicAPIUseCase_ServesOnlyApprovedTracksOfTheLabel(t *testing.T) {
+	tracks := new(MockTrackRepository)
+	uc := NewPublicAPIUseCase(&memoryPublicAPIKeyRepository{}, tracks, &recordingPublicAPIQuota{}, testPublicAPITiers)
+	ctx := context.Background()
+	key := &pkgdomain.PublicAPIKey{ID: "k1", LabelID: "label-a", Tier: pkgdomain.PublicAPITierFree}
+
+	approved := &pkgdomain.Track{ID: "t1", LabelID: "label-a", Status: pkgdomain.TrackStatusApproved, StoragePath: "audio/t1.wav"}
+	approved.SetTitle("Song")
+	draft := &pkgdomain.Track{ID: "t2", LabelID: "label-a", Status: pkgdomain.TrackStatusDraft}
+	other := &pkgdomain.Track{ID: "t3", LabelID: "label-b", Status: pkgdomain.TrackStatusDelivered}
+
+	filter := map[string]interface{}{"label_id": "label-a", "status": pkgdomain.PublicTrackStatuses}
+	tracks.On("List", ctx, filter, 50, 50).Return([]*pkgdomain.Track{approved, draft}, nil)
+	list, err := uc.ListTracks(ctx, key, 50, 50)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "Song", list[0].Title)
+
+	tracks.On("GetByID", ctx, "t1").Return(approved, nil)
+	tracks.On("GetByID", ctx, "t2").Return(draft, nil)
+	tracks.On("GetByID", ctx, "t3").Return(other, nil)
+	tracks.On("GetByID", ctx, "t4").Return(nil, nil)
+
+	track, err := uc.GetTrack(ctx, key, "t1")
+	require.NoError(t, err)
+	assert.Equal(t, "t1", track.ID)
+	for _, id := range []string{"t2", "t3", "t4"} {
+		_, err := uc.GetTrack(ctx, key, id)
+		assert.ErrorIs(t, err, pkgdomain.ErrTrackNotFound, id)
+	}
+	tracks.AssertExpectations(t)
+}
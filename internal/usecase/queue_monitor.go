@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+)
+
+// backpressureSeverity is the value of each level in the backpressure gauge
+var backpressureSeverity = map[domain.BackpressureLevel]float64{
+	domain.BackpressureNone:     0,
+	domain.BackpressureThrottle: 1,
+	domain.BackpressureReject:   2,
+}
+
+// QueueMonitorConfig holds the lag thresholds of the queue monitor
+type QueueMonitorConfig struct {
+	// LagThreshold is the lag at which new messages are downgraded to low
+	// priority; zero disables it
+	LagThreshold int64
+	// MaxLag is the lag at which new messages are refused; zero disables it
+	MaxLag int64
+}
+
+// QueueMonitor samples the lag and publish rate of every topic and applies
+// backpressure to topics that lag too far behind. Changes of a topic's
+// backpressure level are sent to the alerter.
+type QueueMonitor struct {
+	config    QueueMonitorConfig
+	sources   []domain.QueueStatsSource
+	alerter   domain.QueueAlerter
+	now       func() time.Time
+	mu        sync.RWMutex
+	topics    map[string]domain.TopicLag
+	published map[string]int64
+	sampledAt time.Time
+}
+
+// NewQueueMonitor creates a queue monitor. alerter may be nil.
+func NewQueueMonitor(config QueueMonitorConfig, alerter domain.QueueAlerter) *QueueMonitor {
+	return &QueueMonitor{
+		config:    config,
+		alerter:   alerter,
+		now:       time.Now,
+		topics:    make(map[string]domain.TopicLag),
+		published: make(map[string]int64),
+	}
+}
+
+// AddSource adds queues whose lag is monitored
+func (m *QueueMonitor) AddSource(source domain.QueueStatsSource) {
+	m.sources = append(m.sources, source)
+}
+
+// Admit returns the priority to publish a message to topic with. Messages
+// to a throttled topic are downgraded to low priority and a topic that
+// rejects messages returns ErrQueueBackpressure.
+func (m *QueueMonitor) Admit(topic string, priority domain.QueuePriority) (domain.QueuePriority, error) {
+	m.mu.RLock()
+	level := m.topics[topic].Level
+	m.mu.RUnlock()
+
+	switch {
+	case level == domain.BackpressureReject:
+		metrics.QueueBackpressureActions.WithLabelValues(topic, "rejected").Inc()
+		return priority, domain.ErrQueueBackpressure
+	case level == domain.BackpressureThrottle && priority > domain.PriorityLow:
+		metrics.QueueBackpressureActions.WithLabelValues(topic, "downgraded").Inc()
+		return domain.PriorityLow, nil
+	}
+	return priority, nil
+}
+
+// RecordPublish counts a message published to topic for its publish rate
+func (m *QueueMonitor) RecordPublish(topic string) {
+	m.mu.Lock()
+	m.published[topic]++
+	m.mu.Unlock()
+}
+
+// Lags returns the lag of every topic at the last sample
+func (m *QueueMonitor) Lags() []domain.TopicLag {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	lags := make([]domain.TopicLag, 0, len(m.topics))
+	for _, lag := range m.topics {
+		lags = append(lags, lag)
+	}
+	sort.Slice(lags, func(i, j int) bool { return lags[i].Topic < lags[j].Topic })
+	return lags
+}
+
+// Sample measures the lag of every topic and updates its backpressure
+// level. A source that fails keeps its topics at their previous level.
+func (m *QueueMonitor) Sample(ctx context.Context) error {
+	var errs []error
+	pending := make(map[string]int64)
+	for _, source := range m.sources {
+		stats, err := source.QueueStats(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, s := range stats {
+			pending[s.Name] = s.Pending
+		}
+	}
+
+	now := m.now()
+	var alerts []*domain.QueueLagAlert
+
+	m.mu.Lock()
+	// Topics without a lag source still report their publish rate
+	for topic := range m.published {
+		if _, ok := pending[topic]; !ok {
+			pending[topic] = m.topics[topic].Lag
+		}
+	}
+	elapsed := now.Sub(m.sampledAt).Seconds()
+	for topic, lag := range pending {
+		previous, ok := m.topics[topic]
+		if !ok {
+			previous.Level = domain.BackpressureNone
+		}
+		current := domain.TopicLag{Topic: topic, Lag: lag, Level: m.level(lag), SampledAt: now}
+		if !m.sampledAt.IsZero() && elapsed > 0 {
+			current.PublishRate = float64(m.published[topic]) / elapsed
+		}
+		m.topics[topic] = current
+
+		metrics.QueueLag.WithLabelValues(topic).Set(float64(current.Lag))
+		metrics.QueuePublishRate.WithLabelValues(topic).Set(current.PublishRate)
+		metrics.QueueBackpressureLevel.WithLabelValues(topic).Set(backpressureSeverity[current.Level])
+		if current.Level != previous.Level {
+			alerts = append(alerts, &domain.QueueLagAlert{
+				TopicLag:  current,
+				Previous:  previous.Level,
+				Threshold: m.threshold(current.Level, previous.Level),
+			})
+		}
+	}
+	m.published = make(map[string]int64)
+	m.sampledAt = now
+	m.mu.Unlock()
+
+	for _, alert := range alerts {
+		log.Printf("Queue %s backpressure changed from %s to %s at lag %d", alert.Topic, alert.Previous, alert.Level, alert.Lag)
+		if m.alerter == nil {
+			continue
+		}
+		if err := m.alerter.SendQueueLagAlert(ctx, alert); err != nil {
+			log.Printf("Failed to send queue lag alert for %s: %v", alert.Topic, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run samples now and every interval until ctx is done
+func (m *QueueMonitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Sample(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to sample queue lag: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// level returns the backpressure level for lag
+func (m *QueueMonitor) level(lag int64) domain.BackpressureLevel {
+	switch {
+	case m.config.MaxLag > 0 && lag >= m.config.MaxLag:
+		return domain.BackpressureReject
+	case m.config.LagThreshold > 0 && lag >= m.config.LagThreshold:
+		return domain.BackpressureThrottle
+	default:
+		return domain.BackpressureNone
+	}
+}
+
+// threshold returns the lag threshold crossed when moving between levels
+func (m *QueueMonitor) threshold(current, previous domain.BackpressureLevel) int64 {
+	if current == domain.BackpressureReject || previous == domain.BackpressureReject {
+		return m.config.MaxLag
+	}
+	return m.config.LagThreshold
+}
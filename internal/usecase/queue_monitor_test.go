@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingQueueAlerter struct {
+	alerts []*domain.QueueLagAlert
+}
+
+func (a *recordingQueueAlerter) SendQueueLagAlert(ctx context.Context, alert *domain.QueueLagAlert) error {
+	a.alerts = append(a.alerts, alert)
+	return nil
+}
+
+func TestQueueMonitor_Backpressure(t *testing.T) {
+	source := &staticQueueStats{stats: []domain.QueueStats{{Name: "jobs", Pending: 10}}}
+	alerter := &recordingQueueAlerter{}
+	monitor := NewQueueMonitor(QueueMonitorConfig{LagThreshold: 100, MaxLag: 1000}, alerter)
+	monitor.AddSource(source)
+	ctx := context.Background()
+
+	require.NoError(t, monitor.Sample(ctx))
+	priority, err := monitor.Admit("jobs", domain.PriorityHigh)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PriorityHigh, priority)
+	assert.Empty(t, alerter.alerts)
+
+	source.stats[0].Pending = 500
+	require.NoError(t, monitor.Sample(ctx))
+	priority, err = monitor.Admit("jobs", domain.PriorityHigh)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PriorityLow, priority)
+
+	source.stats[0].Pending = 1000
+	require.NoError(t, monitor.Sample(ctx))
+	_, err = monitor.Admit("jobs", domain.PriorityHigh)
+	assert.ErrorIs(t, err, domain.ErrQueueBackpressure)
+
+	require.Len(t, alerter.alerts, 2)
+	assert.Equal(t, domain.BackpressureNone, alerter.alerts[0].Previous)
+	assert.Equal(t, domain.BackpressureThrottle, alerter.alerts[0].Level)
+	assert.Equal(t, int64(100), alerter.alerts[0].Threshold)
+	assert.Equal(t, domain.BackpressureReject, alerter.alerts[1].Level)
+	assert.Equal(t, int64(1000), alerter.alerts[1].Threshold)
+
+	// Topics that were never sampled are admitted unchanged
+	priority, err = monitor.Admit("outbox", domain.PriorityHigh)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PriorityHigh, priority)
+}
+
+func TestQueueMonitor_PublishRate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	monitor := NewQueueMonitor(QueueMonitorConfig{}, nil)
+	monitor.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, monitor.Sample(ctx))
+	for i := 0; i < 30; i++ {
+		monitor.RecordPublish("outbox")
+	}
+	now = now.Add(10 * time.Second)
+	require.NoError(t, monitor.Sample(ctx))
+
+	lags := monitor.Lags()
+	require.Len(t, lags, 1)
+	assert.Equal(t, "outbox", lags[0].Topic)
+	assert.Equal(t, 3.0, lags[0].PublishRate)
+	assert.Equal(t, domain.BackpressureNone, lags[0].Level)
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// RuntimeConfigUseCase holds the current runtime settings and applies
+// changes made through any API instance to the registered watchers
+type RuntimeConfigUseCase struct {
+	store domain.RuntimeConfigStore
+
+	mu       sync.RWMutex
+	current  domain.RuntimeSettings
+	watchers []func(domain.RuntimeSettings)
+}
+
+// NewRuntimeConfigUseCase creates a use case starting from defaults, the
+// settings taken from the static configuration
+func NewRuntimeConfigUseCase(store domain.RuntimeConfigStore, defaults domain.RuntimeSettings) *RuntimeConfigUseCase {
+	return &RuntimeConfigUseCase{store: store, current: defaults}
+}
+
+// Current returns the settings in effect
+func (uc *RuntimeConfigUseCase) Current() domain.RuntimeSettings {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	return uc.current
+}
+
+// Watch registers fn to be called with the settings whenever they change.
+// fn is called once immediately with the current settings.
+func (uc *RuntimeConfigUseCase) Watch(fn func(domain.RuntimeSettings)) {
+	uc.mu.Lock()
+	uc.watchers = append(uc.watchers, fn)
+	current := uc.current
+	uc.mu.Unlock()
+
+	fn(current)
+}
+
+// Load replaces the defaults with the settings saved in the store, if any
+func (uc *RuntimeConfigUseCase) Load(ctx context.Context) error {
+	settings, err := uc.store.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if settings != nil {
+		uc.apply(*settings)
+	}
+	return nil
+}
+
+// Update applies patch to the current settings, saves them and makes them
+// effective on every instance
+func (uc *RuntimeConfigUseCase) Update(ctx context.Context, patch *domain.RuntimeSettingsPatch, actor string) (*domain.RuntimeSettings, error) {
+	settings := patch.Apply(uc.Current())
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	settings.UpdatedAt = time.Now()
+	settings.UpdatedBy = actor
+
+	if err := uc.store.Save(ctx, &settings); err != nil {
+		return nil, fmt.Errorf("failed to save runtime settings: %w", err)
+	}
+	// Applied here as well so the change is visible before the store's
+	// notification comes back
+	uc.apply(settings)
+	return &settings, nil
+}
+
+// Run applies settings saved by other instances until ctx is done
+func (uc *RuntimeConfigUseCase) Run(ctx context.Context) error {
+	updates, err := uc.store.Subscribe(ctx)
+	if err != nil {
+		return err
+	}
+	for settings := range updates {
+		if err := settings.Validate(); err != nil {
+			log.Printf("Ignoring invalid runtime settings: %v", err)
+			continue
+		}
+		uc.apply(*settings)
+	}
+	return ctx.Err()
+}
+
+func (uc *RuntimeConfigUseCase) apply(settings domain.RuntimeSettings) {
+	uc.mu.Lock()
+	if uc.current == settings {
+		uc.mu.Unlock()
+		return
+	}
+	uc.current = settings
+	watchers := append([]func(domain.RuntimeSettings){}, uc.watchers...)
+	uc.mu.Unlock()
+
+	for _, fn := range watchers {
+		fn(settings)
+	}
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryRuntimeConfigStore struct {
+	settings *domain.RuntimeSettings
+	updates  chan *domain.RuntimeSettings
+}
+
+func (s *memoryRuntimeConfigStore) Get(ctx context.Context) (*domain.RuntimeSettings, error) {
+	return s.settings, nil
+}
+
+func (s *memoryRuntimeConfigStore) Save(ctx context.Context, settings *domain.RuntimeSettings) error {
+	s.settings = settings
+	return nil
+}
+
+func (s *memoryRuntimeConfigStore) Subscribe(ctx context.Context) (<-chan *domain.RuntimeSettings, error) {
+	return s.updates, nil
+}
+
+func TestRuntimeConfigUseCase_UpdateNotifiesWatchers(t *testing.T) {
+	store := &memoryRuntimeConfigStore{}
+	uc := NewRuntimeConfigUseCase(store, domain.RuntimeSettings{AIMinConfidence: 0.85, ExperimentTrafficPercent: 0.1})
+
+	var seen []float64
+	uc.Watch(func(s domain.RuntimeSettings) { seen = append(seen, s.ExperimentTrafficPercent) })
+
+	traffic := 0.5
+	settings, err := uc.Update(context.Background(), &domain.RuntimeSettingsPatch{ExperimentTrafficPercent: &traffic}, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0.85, settings.AIMinConfidence)
+	assert.Equal(t, "admin-1", store.settings.UpdatedBy)
+	assert.Equal(t, []float64{0.1, 0.5}, seen)
+	assert.Equal(t, 0.5, uc.Current().ExperimentTrafficPercent)
+}
+
+func TestRuntimeConfigUseCase_RejectsInvalidSettings(t *testing.T) {
+	store := &memoryRuntimeConfigStore{}
+	uc := NewRuntimeConfigUseCase(store, domain.RuntimeSettings{AIMinConfidence: 0.85})
+
+	confidence := 1.5
+	_, err := uc.Update(context.Background(), &domain.RuntimeSettingsPatch{AIMinConfidence: &confidence}, "")
+	assert.True(t, errors.Is(err, domain.ErrInvalidInput))
+	assert.Nil(t, store.settings)
+	assert.Equal(t, 0.85, uc.Current().AIMinConfidence)
+}
+
+func TestRuntimeConfigUseCase_LoadAndRunApplyStoredSettings(t *testing.T) {
+	store := &memoryRuntimeConfigStore{
+		settings: &domain.RuntimeSettings{AIMinConfidence: 0.7},
+		updates:  make(chan *domain.RuntimeSettings, 2),
+	}
+	uc := NewRuntimeConfigUseCase(store, domain.RuntimeSettings{AIMinConfidence: 0.85})
+	require.NoError(t, uc.Load(context.Background()))
+	assert.Equal(t, 0.7, uc.Current().AIMinConfidence)
+
+	// Updates from other instances are applied; invalid ones are skipped
+	store.updates <- &domain.RuntimeSettings{AIMinConfidence: 0.6, RateLimitPerMinute: 100}
+	store.updates <- &domain.RuntimeSettings{RateLimitPerMinute: -1}
+	close(store.updates)
+	require.NoError(t, uc.Run(context.Background()))
+	assert.Equal(t, domain.RuntimeSettings{AIMinConfidence: 0.6, RateLimitPerMinute: 100}, uc.Current())
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// DeviceFingerprint derives a stable device identifier from request traits
+// that do not change between logins from the same browser. The IP address is
+// deliberately left out since it changes as devices move between networks.
+func DeviceFingerprint(userAgent, acceptLanguage string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(userAgent) + "\n" + strings.TrimSpace(acceptLanguage)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// DeviceName returns a readable description of a user agent such as
+// "Chrome on macOS"
+func DeviceName(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+
+	browser := "Unknown browser"
+	switch {
+	case strings.Contains(ua, "edg/"):
+		browser = "Edge"
+	case strings.Contains(ua, "opr/") || strings.Contains(ua, "opera"):
+		browser = "Opera"
+	case strings.Contains(ua, "firefox/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "chrome/") || strings.Contains(ua, "crios/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "safari/"):
+		browser = "Safari"
+	case strings.Contains(ua, "curl/"):
+		browser = "curl"
+	case strings.Contains(ua, "go-http-client"):
+		browser = "Go client"
+	}
+
+	platform := ""
+	switch {
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad"):
+		platform = "iOS"
+	case strings.Contains(ua, "android"):
+		platform = "Android"
+	case strings.Contains(ua, "windows"):
+		platform = "Windows"
+	case strings.Contains(ua, "mac os x") || strings.Contains(ua, "macintosh"):
+		platform = "macOS"
+	case strings.Contains(ua, "linux"):
+		platform = "Linux"
+	}
+
+	if platform == "" {
+		return browser
+	}
+	return fmt.Sprintf("%s on %s", browser, platform)
+}
+
+// DescribeSessionDevice fills in the device fields of a session from its user
+// agent and the request's language and country
+func DescribeSessionDevice(session *domain.Session, acceptLanguage, country string) {
+	session.DeviceID = DeviceFingerprint(session.UserAgent, acceptLanguage)
+	session.DeviceName = DeviceName(session.UserAgent)
+	session.Country = strings.ToUpper(country)
+}
+
+// SessionDeviceTracker remembers the devices a user signs in from and alerts
+// the user when a login comes from a device or country not seen before
+type SessionDeviceTracker struct {
+	registry domain.DeviceRegistry
+	notifier domain.LoginAlertNotifier
+}
+
+// NewSessionDeviceTracker creates a new session device tracker
+func NewSessionDeviceTracker(registry domain.DeviceRegistry, notifier domain.LoginAlertNotifier) *SessionDeviceTracker {
+	return &SessionDeviceTracker{
+		registry: registry,
+		notifier: notifier,
+	}
+}
+
+// Track records the session's device for the user and sends an alert when
+// the login is unfamiliar. Failures are logged rather than returned so that
+// they never block a login.
+func (t *SessionDeviceTracker) Track(ctx context.Context, user *domain.User, session *domain.Session) domain.LoginNovelty {
+	novelty, err := t.registry.Remember(ctx, user.ID, session.DeviceID, session.Country)
+	if err != nil {
+		log.Printf("failed to record device for user %s: %v", user.ID, err)
+		return novelty
+	}
+
+	if novelty.Suspicious() {
+		if err := t.notifier.SendNewLoginAlert(ctx, user, session, novelty); err != nil {
+			log.Printf("failed to send new login alert for user %s: %v", user.ID, err)
+		}
+	}
+	return novelty
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubDeviceRegistry struct {
+	novelty domain.LoginNovelty
+}
+
+func (r *stubDeviceRegistry) Remember(ctx context.Context, userID, deviceID, country string) (domain.LoginNovelty, error) {
+	return r.novelty, nil
+}
+
+type recordingLoginNotifier struct {
+	alerts []domain.LoginNovelty
+}
+
+func (n *recordingLoginNotifier) SendNewLoginAlert(ctx context.Context, user *domain.User, session *domain.Session, novelty domain.LoginNovelty) error {
+	n.alerts = append(n.alerts, novelty)
+	return nil
+}
+
+func TestDeviceName(t *testing.T) {
+	tests := map[string]string{
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_4) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36": "Chrome on macOS",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 Version/17.4 Mobile Safari/604.1":   "Safari on iOS",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:125.0) Gecko/20100101 Firefox/125.0":                               "Firefox on Windows",
+		"curl/8.5.0": "curl",
+		"":           "Unknown browser",
+	}
+	for ua, want := range tests {
+		assert.Equal(t, want, DeviceName(ua), ua)
+	}
+}
+
+func TestSessionDeviceTracker_AlertsOnlyForUnfamiliarLogins(t *testing.T) {
+	user := createTestUser()
+	session := &domain.Session{ID: "s1", UserID: user.ID, UserAgent: "curl/8.5.0"}
+	DescribeSessionDevice(session, "en", "se")
+	assert.Equal(t, "SE", session.Country)
+	assert.Equal(t, DeviceFingerprint("curl/8.5.0", "en"), session.DeviceID)
+
+	registry := &stubDeviceRegistry{}
+	notifier := &recordingLoginNotifier{}
+	tracker := NewSessionDeviceTracker(registry, notifier)
+
+	registry.novelty = domain.LoginNovelty{FirstLogin: true, NewDevice: true}
+	tracker.Track(context.Background(), user, session)
+	assert.Empty(t, notifier.alerts)
+
+	registry.novelty = domain.LoginNovelty{NewCountry: true}
+	tracker.Track(context.Background(), user, session)
+	assert.Len(t, notifier.alerts, 1)
+}
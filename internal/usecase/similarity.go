@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+const (
+	// DefaultSimilarTracks is the number of similar tracks returned when no
+	// limit is asked for
+	DefaultSimilarTracks = 10
+	// MaxSimilarTracks caps the similar tracks returned at once
+	MaxSimilarTracks = 100
+)
+
+// SimilarityUseCase stores the audio embeddings of tracks and finds the
+// tracks that sound alike, for A&R discovery and duplicate triage
+type SimilarityUseCase struct {
+	embeddings domain.EmbeddingRepository
+	tracks     domain.TrackRepository
+	embedder   domain.Embedder
+}
+
+// NewSimilarityUseCase creates a new similarity use case. Analyses are
+// embedded with the FeatureEmbedder until SetEmbedder picks another.
+func NewSimilarityUseCase(embeddings domain.EmbeddingRepository, tracks domain.TrackRepository) *SimilarityUseCase {
+	return &SimilarityUseCase{
+		embeddings: embeddings,
+		tracks:     tracks,
+		embedder:   FeatureEmbedder{},
+	}
+}
+
+// SetEmbedder computes the embeddings of analyses with embedder
+func (uc *SimilarityUseCase) SetEmbedder(embedder domain.Embedder) {
+	uc.embedder = embedder
+}
+
+// Index embeds the audio analysis of a track and stores the embedding
+func (uc *SimilarityUseCase) Index(ctx context.Context, trackID string, analysis *domain.AudioAnalysis) error {
+	vector, err := uc.embedder.Embed(ctx, analysis)
+	if err != nil {
+		return fmt.Errorf("failed to embed analysis: %w", err)
+	}
+	return uc.Save(ctx, &domain.TrackEmbedding{TrackID: trackID, Model: uc.embedder.Model(), Vector: vector})
+}
+
+// Save stores an embedding of a track, replacing the one it had. Embeddings
+// computed by external models are saved as they are.
+func (uc *SimilarityUseCase) Save(ctx context.Context, embedding *domain.TrackEmbedding) error {
+	if errs := embedding.Validate(); len(errs) > 0 {
+		return fmt.Errorf("%w: %s", domain.ErrInvalidInput, errs[0].Message)
+	}
+	track, err := uc.tracks.GetByID(ctx, embedding.TrackID)
+	if err != nil {
+		return fmt.Errorf("failed to get track: %w", err)
+	}
+	if track == nil {
+		return domain.ErrTrackNotFound
+	}
+	embedding.UpdatedAt = time.Now()
+	return uc.embeddings.Save(ctx, embedding)
+}
+
+// Similar returns up to limit tracks that sound like a track, most similar
+// first. A limit out of range is replaced by the default or the maximum.
+func (uc *SimilarityUseCase) Similar(ctx context.Context, trackID string, limit int) ([]domain.SimilarTrack, error) {
+	if limit <= 0 {
+		limit = DefaultSimilarTracks
+	}
+	if limit > MaxSimilarTracks {
+		limit = MaxSimilarTracks
+	}
+	track, err := uc.tracks.GetByID(ctx, trackID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get track: %w", err)
+	}
+	if track == nil {
+		return nil, domain.ErrTrackNotFound
+	}
+
+	neighbors, err := uc.embeddings.Nearest(ctx, trackID, limit)
+	if err != nil {
+		return nil, err
+	}
+	similar := make([]domain.SimilarTrack, 0, len(neighbors))
+	for _, neighbor := range neighbors {
+		// Neighbors are few, so they are read one by one through the
+		// track cache rather than in a batch
+		track, err := uc.tracks.GetByID(ctx, neighbor.TrackID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get track: %w", err)
+		}
+		if track == nil {
+			continue
+		}
+		similar = append(similar, domain.SimilarTrack{Track: track, Distance: neighbor.Distance})
+	}
+	return similar, nil
+}
+
+// FeatureEmbedderModel names the embeddings of the FeatureEmbedder
+const FeatureEmbedderModel = "audio-features-v1"
+
+// FeatureEmbedder embeds the features of the audio analysis, scaled to
+// comparable ranges: tempo, the key as a point on the circle of fifths with
+// relative minors at their major, the mode, loudness and the perceptual
+// features. It needs no model, so every analyzed track can be compared.
+type FeatureEmbedder struct{}
+
+// Model names the embeddings of the embedder
+func (FeatureEmbedder) Model() string {
+	return FeatureEmbedderModel
+}
+
+// Embed returns the scaled features of the analysis
+func (FeatureEmbedder) Embed(_ context.Context, analysis *domain.AudioAnalysis) ([]float32, error) {
+	if analysis == nil {
+		return nil, fmt.Errorf("%w: no audio analysis", domain.ErrInvalidInput)
+	}
+	var keyX, keyY, mode float64
+	fifths, minor, ok := circleOfFifths(analysis.Key, analysis.Mode)
+	if ok {
+		angle := 2 * math.Pi * float64(fifths) / 12
+		keyX, keyY = math.Cos(angle), math.Sin(angle)
+	}
+	switch {
+	case minor:
+		mode = -1
+	case ok || analysis.Mode != "":
+		mode = 1
+	}
+	vector := []float64{
+		unit(analysis.BPM / 200),
+		keyX,
+		keyY,
+		mode,
+		unit((analysis.Loudness + 60) / 60),
+		unit(analysis.Energy),
+		unit(analysis.Brightness),
+		unit(analysis.Danceability),
+		unit(analysis.Valence),
+		unit(analysis.Arousal),
+		unit(analysis.Complexity),
+		unit(analysis.Intensity),
+	}
+	embedding := make([]float32, len(vector))
+	for i, v := range vector {
+		embedding[i] = float32(v)
+	}
+	return embedding, nil
+}
+
+// circleOfFifths returns the position of a key on the circle of fifths, C
+// major and A minor at 0 and G major at 1, and whether it is minor. The
+// mode is read from mode or from the key, as in "Am" or "A minor".
+func circleOfFifths(key, mode string) (int, bool, bool) {
+	k, ok := domain.ParseKey(key + " " + mode)
+	if !ok {
+		k, ok = domain.ParseKey(key)
+	}
+	if !ok {
+		return 0, strings.HasPrefix(strings.ToLower(mode), "min"), false
+	}
+	return (k.Number + 4) % 12, k.Minor, true
+}
+
+// unit clamps v to the range 0 to 1
+func unit(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memEmbeddingRepository keeps embeddings in memory and returns fixed
+// neighbors
+type memEmbeddingRepository struct {
+	saved     map[string]*pkgdomain.TrackEmbedding
+	neighbors []pkgdomain.TrackNeighbor
+	limit     int
+}
+
+func (r *memEmbeddingRepository) Save(_ context.Context, embedding *pkgdomain.TrackEmbedding) error {
+	r.saved[embedding.TrackID] = embedding
+	return nil
+}
+
+func (r *memEmbeddingRepository) Get(_ context.Context, trackID string) (*pkgdomain.TrackEmbedding, error) {
+	if embedding, ok := r.saved[trackID]; ok {
+		return embedding, nil
+	}
+	return nil, pkgdomain.ErrEmbeddingNotFound
+}
+
+func (r *memEmbeddingRepository) Nearest(_ context.Context, trackID string, limit int) ([]pkgdomain.TrackNeighbor, error) {
+	r.limit = limit
+	if _, ok := r.saved[trackID]; !ok {
+		return nil, pkgdomain.ErrEmbeddingNotFound
+	}
+	return r.neighbors, nil
+}
+
+func TestSimilarityUseCase_Similar(t *testing.T) {
+	seed := &pkgdomain.Track{ID: "seed"}
+	near := &pkgdomain.Track{ID: "near"}
+	far := &pkgdomain.Track{ID: "far"}
+	tracks := new(MockTrackRepository)
+	tracks.On("GetByID", mock.Anything, "seed").Return(seed, nil)
+	tracks.On("GetByID", mock.Anything, "near").Return(near, nil)
+	tracks.On("GetByID", mock.Anything, "far").Return(far, nil)
+	tracks.On("GetByID", mock.Anything, "gone").Return(nil, nil)
+	tracks.On("GetByID", mock.Anything, "bare").Return(&pkgdomain.Track{ID: "bare"}, nil)
+	tracks.On("GetByID", mock.Anything, "missing").Return(nil, nil)
+
+	embeddings := &memEmbeddingRepository{
+		saved: map[string]*pkgdomain.TrackEmbedding{"seed": {TrackID: "seed"}},
+		neighbors: []pkgdomain.TrackNeighbor{
+			{TrackID: "near", Distance: 0.01},
+			{TrackID: "gone", Distance: 0.1},
+			{TrackID: "far", Distance: 0.4},
+		},
+	}
+	uc := NewSimilarityUseCase(embeddings, tracks)
+	ctx := context.Background()
+
+	similar, err := uc.Similar(ctx, "seed", 0)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSimilarTracks, embeddings.limit)
+	// Neighbors keep their order, and deleted tracks are skipped
+	require.Len(t, similar, 2)
+	assert.Equal(t, "near", similar[0].Track.ID)
+	assert.Equal(t, 0.01, similar[0].Distance)
+	assert.Equal(t, "far", similar[1].Track.ID)
+
+	_, err = uc.Similar(ctx, "seed", 1000)
+	require.NoError(t, err)
+	assert.Equal(t, MaxSimilarTracks, embeddings.limit)
+
+	_, err = uc.Similar(ctx, "bare", 5)
+	assert.ErrorIs(t, err, pkgdomain.ErrEmbeddingNotFound)
+
+	_, err = uc.Similar(ctx, "missing", 5)
+	assert.ErrorIs(t, err, pkgdomain.ErrTrackNotFound)
+}
+
+func TestSimilarityUseCase_Index(t *testing.T) {
+	tracks := new(MockTrackRepository)
+	tracks.On("GetByID", mock.Anything, "t1").Return(&pkgdomain.Track{ID: "t1"}, nil)
+	tracks.On("GetByID", mock.Anything, "missing").Return(nil, nil)
+	embeddings := &memEmbeddingRepository{saved: map[string]*pkgdomain.TrackEmbedding{}}
+	uc := NewSimilarityUseCase(embeddings, tracks)
+	ctx := context.Background()
+
+	analysis := &pkgdomain.AudioAnalysis{BPM: 124, Key: "A", Mode: "minor", Loudness: -8, Energy: 0.8, Danceability: 0.9}
+	require.NoError(t, uc.Index(ctx, "t1", analysis))
+	saved := embeddings.saved["t1"]
+	require.NotNil(t, saved)
+	assert.Equal(t, FeatureEmbedderModel, saved.Model)
+	assert.False(t, saved.UpdatedAt.IsZero())
+
+	assert.ErrorIs(t, uc.Index(ctx, "missing", analysis), pkgdomain.ErrTrackNotFound)
+
+	err := uc.Save(ctx, &pkgdomain.TrackEmbedding{TrackID: "t1", Model: "clap", Vector: []float32{0, 0}})
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput)
+}
+
+func TestFeatureEmbedder(t *testing.T) {
+	embed := func(analysis *pkgdomain.AudioAnalysis) []float32 {
+		vector, err := FeatureEmbedder{}.Embed(context.Background(), analysis)
+		require.NoError(t, err)
+		return vector
+	}
+	distance := func(a, b []float32) float64 {
+		var dot, na, nb float64
+		for i := range a {
+			dot += float64(a[i]) * float64(b[i])
+			na += float64(a[i]) * float64(a[i])
+			nb += float64(b[i]) * float64(b[i])
+		}
+		return 1 - dot/math.Sqrt(na*nb)
+	}
+
+	house := embed(&pkgdomain.AudioAnalysis{BPM: 124, Key: "A", Mode: "minor", Loudness: -7, Energy: 0.8, Danceability: 0.9})
+	// A minor and C major sit together on the circle of fifths
+	relative := embed(&pkgdomain.AudioAnalysis{BPM: 126, Key: "C", Mode: "major", Loudness: -8, Energy: 0.75, Danceability: 0.85})
+	ballad := embed(&pkgdomain.AudioAnalysis{BPM: 70, Key: "F#", Mode: "major", Loudness: -18, Energy: 0.2, Danceability: 0.3})
+	assert.Less(t, distance(house, relative), distance(house, ballad))
+
+	// The mode may be written in the key
+	assert.Equal(t, house, embed(&pkgdomain.AudioAnalysis{BPM: 124, Key: "Am", Loudness: -7, Energy: 0.8, Danceability: 0.9}))
+
+	// Features are clamped to their range
+	loud := embed(&pkgdomain.AudioAnalysis{BPM: 400, Loudness: 6, Energy: 3})
+	for _, v := range loud {
+		assert.LessOrEqual(t, v, float32(1))
+		assert.GreaterOrEqual(t, v, float32(-1))
+	}
+
+	_, err := FeatureEmbedder{}.Embed(context.Background(), nil)
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput)
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/errortracking"
+	"metadatatool/internal/pkg/metrics"
+)
+
+// integrityAuditPageSize is the number of tracks an audit reads at a time
+const integrityAuditPageSize = 100
+
+// IntegrityUseCase checks stored track files against the checksums
+// recorded at upload, so silent corruption is detected
+type IntegrityUseCase struct {
+	tracks       domain.TrackRepository
+	storage      domain.StorageService
+	errorTracker *errortracking.ErrorTracker
+}
+
+// NewIntegrityUseCase creates a new integrity use case. errorTracker may be
+// nil.
+func NewIntegrityUseCase(tracks domain.TrackRepository, storage domain.StorageService, errorTracker *errortracking.ErrorTracker) *IntegrityUseCase {
+	return &IntegrityUseCase{
+		tracks:       tracks,
+		storage:      storage,
+		errorTracker: errorTracker,
+	}
+}
+
+// Check reads a track's file and compares it with its recorded checksums
+func (uc *IntegrityUseCase) Check(ctx context.Context, trackID string) (*domain.IntegrityReport, error) {
+	track, err := uc.tracks.GetByID(ctx, trackID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get track: %w", err)
+	}
+	if track == nil {
+		return nil, domain.ErrTrackNotFound
+	}
+	if track.StoragePath == "" {
+		return nil, fmt.Errorf("%w: track has no file", domain.ErrInvalidInput)
+	}
+	return uc.verify(ctx, track), nil
+}
+
+// Audit checks the file of every track and returns the number of
+// mismatches found
+func (uc *IntegrityUseCase) Audit(ctx context.Context) (int, error) {
+	mismatches := 0
+	for offset := 0; ; offset += integrityAuditPageSize {
+		tracks, err := uc.tracks.List(ctx, nil, offset, integrityAuditPageSize)
+		if err != nil {
+			return mismatches, fmt.Errorf("failed to list tracks: %w", err)
+		}
+		for _, track := range tracks {
+			if ctx.Err() != nil {
+				return mismatches, ctx.Err()
+			}
+			// Quarantined files are checked when the scan releases them
+			if track.StoragePath == "" || domain.IsQuarantined(track.StoragePath) {
+				continue
+			}
+			if report := uc.verify(ctx, track); report.Status == domain.IntegrityMismatch {
+				mismatches++
+			}
+		}
+		if len(tracks) < integrityAuditPageSize {
+			return mismatches, nil
+		}
+	}
+}
+
+// Run audits every track file every interval until ctx is cancelled
+func (uc *IntegrityUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			mismatches, err := uc.Audit(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Error auditing storage integrity: %v", err)
+			}
+			if mismatches > 0 {
+				log.Printf("Storage integrity audit found %d corrupted files", mismatches)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// verify hashes a track's file and compares the checksums. Tracks stored
+// before checksums were recorded get the measured ones.
+func (uc *IntegrityUseCase) verify(ctx context.Context, track *domain.Track) *domain.IntegrityReport {
+	technical := track.Metadata.Technical
+	report := &domain.IntegrityReport{
+		TrackID:        track.ID,
+		Key:            track.StoragePath,
+		ExpectedSHA256: technical.ContentHash,
+		ExpectedMD5:    technical.ContentMD5,
+		CheckedAt:      time.Now(),
+	}
+	defer func() {
+		metrics.StorageIntegrityChecks.WithLabelValues(string(report.Status)).Inc()
+	}()
+
+	checksums, size, err := uc.hash(ctx, track.StoragePath)
+	if err != nil {
+		report.Status = domain.IntegrityUnavailable
+		report.Error = err.Error()
+		return report
+	}
+	report.ActualSHA256, report.ActualMD5, report.Size = checksums.SHA256, checksums.MD5, size
+
+	if err := checksums.Verify(technical.ContentHash, technical.ContentMD5); err != nil {
+		report.Status = domain.IntegrityMismatch
+		log.Printf("Integrity check of track %s failed: %v", track.ID, err)
+		if uc.errorTracker != nil {
+			uc.errorTracker.CaptureError(err, map[string]string{
+				"integrity_event": "checksum_mismatch",
+				"track_id":        track.ID,
+				"storage_path":    track.StoragePath,
+			})
+		}
+		return report
+	}
+
+	report.Status = domain.IntegrityOK
+	if technical.ContentHash != "" && technical.ContentMD5 != "" {
+		return report
+	}
+	_, err = domain.PatchTrack(ctx, uc.tracks, track.ID, func(t *domain.Track) error {
+		if t.StoragePath != track.StoragePath {
+			return nil
+		}
+		if t.Metadata.Technical.ContentHash == "" {
+			t.Metadata.Technical.ContentHash = checksums.SHA256
+		}
+		t.Metadata.Technical.ContentMD5 = checksums.MD5
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error recording checksums of track %s: %v", track.ID, err)
+		return report
+	}
+	if technical.ContentHash == "" {
+		report.Status = domain.IntegrityRecorded
+	}
+	return report
+}
+
+// hash reads a stored file and returns its checksums and size
+func (uc *IntegrityUseCase) hash(ctx context.Context, key string) (domain.Checksums, int64, error) {
+	file, err := uc.storage.Download(ctx, key)
+	if err != nil {
+		return domain.Checksums{}, 0, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer closeContent(file)
+
+	content := domain.NewChecksumReader(file.Content)
+	if _, err := io.Copy(io.Discard, content); err != nil {
+		return domain.Checksums{}, 0, fmt.Errorf("failed to read file: %w", err)
+	}
+	return content.Sum(), content.Size(), nil
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/repository/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrity_DetectsCorruptedFiles(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewLocalStorage(t.TempDir(), "/files", nil)
+	require.NoError(t, err)
+
+	content := pkgdomain.NewChecksumReader(strings.NewReader("audio"))
+	require.NoError(t, store.Upload(ctx, &pkgdomain.StorageFile{Key: "tracks/t1/audio.mp3", Content: content}))
+	checksums := content.Sum()
+
+	track := &pkgdomain.Track{ID: "t1", StoragePath: "tracks/t1/audio.mp3"}
+	track.Metadata.Technical.ContentHash = checksums.SHA256
+	track.Metadata.Technical.ContentMD5 = checksums.MD5
+	repo := new(MockTrackRepository)
+	repo.On("GetByID", mock.Anything, "t1").Return(track, nil)
+	repo.On("GetByID", mock.Anything, "missing").Return(nil, nil)
+	repo.On("List", mock.Anything, mock.Anything, 0, integrityAuditPageSize).Return([]*pkgdomain.Track{track}, nil)
+	uc := NewIntegrityUseCase(repo, store, nil)
+
+	report, err := uc.Check(ctx, "t1")
+	require.NoError(t, err)
+	assert.Equal(t, pkgdomain.IntegrityOK, report.Status)
+	assert.Equal(t, int64(5), report.Size)
+
+	// Corrupt the stored file
+	require.NoError(t, store.Upload(ctx, &pkgdomain.StorageFile{Key: "tracks/t1/audio.mp3", Content: strings.NewReader("audi0")}))
+	report, err = uc.Check(ctx, "t1")
+	require.NoError(t, err)
+	assert.Equal(t, pkgdomain.IntegrityMismatch, report.Status)
+	assert.Equal(t, checksums.SHA256, report.ExpectedSHA256)
+	assert.NotEqual(t, report.ExpectedSHA256, report.ActualSHA256)
+
+	mismatches, err := uc.Audit(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, mismatches)
+
+	_, err = uc.Check(ctx, "missing")
+	assert.ErrorIs(t, err, pkgdomain.ErrTrackNotFound)
+}
+
+func TestIntegrity_RecordsMissingChecksums(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewLocalStorage(t.TempDir(), "/files", nil)
+	require.NoError(t, err)
+	require.NoError(t, store.Upload(ctx, &pkgdomain.StorageFile{Key: "tracks/t1/audio.mp3", Content: strings.NewReader("audio")}))
+
+	track := &pkgdomain.Track{ID: "t1", StoragePath: "tracks/t1/audio.mp3", Version: 1}
+	repo := new(MockTrackRepository)
+	repo.On("GetByID", mock.Anything, "t1").Return(track, nil)
+	repo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Track")).Return(nil)
+	uc := NewIntegrityUseCase(repo, store, nil)
+
+	report, err := uc.Check(ctx, "t1")
+	require.NoError(t, err)
+	assert.Equal(t, pkgdomain.IntegrityRecorded, report.Status)
+	assert.Equal(t, report.ActualSHA256, track.Metadata.Technical.ContentHash)
+	assert.Equal(t, report.ActualMD5, track.Metadata.Technical.ContentMD5)
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+)
+
+// StorageQuotaUseCase enforces the per-user storage quota at upload time
+// and warns users whose usage crosses the warning threshold
+type StorageQuotaUseCase struct {
+	store      domain.QuotaStore
+	limit      int64
+	warningPct int
+	notifier   domain.QuotaNotifier
+}
+
+// NewStorageQuotaUseCase creates a quota use case allowing every user limit
+// bytes, zero being unlimited, and warning at warningPct percent of it.
+// notifier may be nil.
+func NewStorageQuotaUseCase(store domain.QuotaStore, limit int64, warningPct int, notifier domain.QuotaNotifier) *StorageQuotaUseCase {
+	return &StorageQuotaUseCase{
+		store:      store,
+		limit:      limit,
+		warningPct: warningPct,
+		notifier:   notifier,
+	}
+}
+
+// Reserve counts size bytes of trackID's file against userID's quota and
+// returns domain.ErrQuotaExceeded when they do not fit
+func (uc *StorageQuotaUseCase) Reserve(ctx context.Context, userID, trackID string, size int64) error {
+	used, err := uc.store.Reserve(ctx, userID, trackID, size, uc.limit)
+	if err != nil {
+		return err
+	}
+	metrics.StorageQuotaUsage.WithLabelValues(userID).Set(float64(used))
+
+	if threshold := uc.threshold(); threshold > 0 && used-size < threshold && used >= threshold && uc.notifier != nil {
+		warning := &domain.QuotaWarning{
+			UserID:  userID,
+			Used:    used,
+			Limit:   uc.limit,
+			Percent: int(used * 100 / uc.limit),
+		}
+		ctx := context.WithoutCancel(ctx)
+		go func() {
+			if err := uc.notifier.SendQuotaWarning(ctx, warning); err != nil {
+				log.Printf("Error sending quota warning for user %s: %v", warning.UserID, err)
+			}
+		}()
+	}
+	return nil
+}
+
+// Release stops counting trackID's file against its owner's quota. Errors
+// are only logged, as the file is gone either way.
+func (uc *StorageQuotaUseCase) Release(ctx context.Context, trackID string) {
+	userID, used, err := uc.store.Release(ctx, trackID)
+	if err != nil {
+		log.Printf("Error releasing storage quota of track %s: %v", trackID, err)
+		return
+	}
+	if userID != "" {
+		metrics.StorageQuotaUsage.WithLabelValues(userID).Set(float64(used))
+	}
+}
+
+// threshold returns the usage at which users are warned, or 0 when there
+// is no warning
+func (uc *StorageQuotaUseCase) threshold() int64 {
+	if uc.limit <= 0 || uc.warningPct <= 0 {
+		return 0
+	}
+	return uc.limit * int64(uc.warningPct) / 100
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryQuotaStore counts usage per user in memory
+type memoryQuotaStore struct {
+	usage map[string]int64
+	files map[string]string
+	sizes map[string]int64
+}
+
+func newMemoryQuotaStore() *memoryQuotaStore {
+	return &memoryQuotaStore{usage: map[string]int64{}, files: map[string]string{}, sizes: map[string]int64{}}
+}
+
+func (s *memoryQuotaStore) Reserve(_ context.Context, userID, fileID string, size, limit int64) (int64, error) {
+	if _, ok := s.files[fileID]; ok {
+		return s.usage[userID], nil
+	}
+	if limit > 0 && s.usage[userID]+size > limit {
+		return s.usage[userID], pkgdomain.ErrQuotaExceeded
+	}
+	s.files[fileID], s.sizes[fileID] = userID, size
+	s.usage[userID] += size
+	return s.usage[userID], nil
+}
+
+func (s *memoryQuotaStore) Release(_ context.Context, fileID string) (string, int64, error) {
+	userID, ok := s.files[fileID]
+	if !ok {
+		return "", 0, nil
+	}
+	s.usage[userID] -= s.sizes[fileID]
+	delete(s.files, fileID)
+	return userID, s.usage[userID], nil
+}
+
+func (s *memoryQuotaStore) Usage(_ context.Context, userID string) (int64, error) {
+	return s.usage[userID], nil
+}
+
+// channelQuotaNotifier passes the warnings it is sent to a channel
+type channelQuotaNotifier chan *pkgdomain.QuotaWarning
+
+func (n channelQuotaNotifier) SendQuotaWarning(_ context.Context, warning *pkgdomain.QuotaWarning) error {
+	n <- warning
+	return nil
+}
+
+func TestStorageQuota_EnforcesLimitAndWarnsOnce(t *testing.T) {
+	ctx := context.Background()
+	notifier := make(channelQuotaNotifier, 4)
+	uc := NewStorageQuotaUseCase(newMemoryQuotaStore(), 100, 80, notifier)
+
+	require.NoError(t, uc.Reserve(ctx, "u1", "t1", 50))
+	require.NoError(t, uc.Reserve(ctx, "u1", "t2", 35))
+
+	select {
+	case warning := <-notifier:
+		assert.Equal(t, "u1", warning.UserID)
+		assert.Equal(t, int64(85), warning.Used)
+		assert.Equal(t, 85, warning.Percent)
+	case <-time.After(time.Second):
+		t.Fatal("no quota warning sent")
+	}
+
+	assert.ErrorIs(t, uc.Reserve(ctx, "u1", "t3", 20), pkgdomain.ErrQuotaExceeded)
+
+	// Staying above the threshold does not warn again
+	require.NoError(t, uc.Reserve(ctx, "u1", "t4", 10))
+	uc.Release(ctx, "t1")
+	require.NoError(t, uc.Reserve(ctx, "u1", "t3", 20))
+	select {
+	case <-notifier:
+		t.Fatal("unexpected quota warning")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// StorageRestoreUseCase restores archived track files and notifies once
+// they can be read again. Restores in progress are tracked in memory; one
+// requested before a restart still completes in storage, but is only
+// reported if it is requested again.
+type StorageRestoreUseCase struct {
+	tracks   domain.TrackRepository
+	tierer   domain.StorageTierer
+	notifier domain.RestoreNotifier
+	days     int
+
+	mu      sync.Mutex
+	pending map[string]*domain.RestoreNotice
+}
+
+// NewStorageRestoreUseCase creates a restore use case keeping restored
+// copies for days. notifier may be nil.
+func NewStorageRestoreUseCase(tracks domain.TrackRepository, tierer domain.StorageTierer, notifier domain.RestoreNotifier, days int) *StorageRestoreUseCase {
+	if days <= 0 {
+		days = 7
+	}
+	return &StorageRestoreUseCase{
+		tracks:   tracks,
+		tierer:   tierer,
+		notifier: notifier,
+		days:     days,
+		pending:  make(map[string]*domain.RestoreNotice),
+	}
+}
+
+// Status returns the archive status of a track's file
+func (uc *StorageRestoreUseCase) Status(ctx context.Context, trackID string) (*domain.ArchiveStatus, error) {
+	track, err := uc.track(ctx, trackID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.tierer.ArchiveStatus(ctx, track.StoragePath)
+}
+
+// Restore starts retrieving a track's archived file on behalf of userID.
+// Files that can already be read are left alone.
+func (uc *StorageRestoreUseCase) Restore(ctx context.Context, trackID, userID string) (*domain.ArchiveStatus, error) {
+	track, err := uc.track(ctx, trackID)
+	if err != nil {
+		return nil, err
+	}
+	status, err := uc.tierer.ArchiveStatus(ctx, track.StoragePath)
+	if err != nil {
+		return nil, err
+	}
+	if status.State.Readable() {
+		return status, nil
+	}
+
+	if status.State == domain.ArchiveStateArchived {
+		if err := uc.tierer.Restore(ctx, track.StoragePath, uc.days); err != nil {
+			return nil, err
+		}
+		status.State = domain.ArchiveStateRestoring
+	}
+
+	uc.mu.Lock()
+	if _, ok := uc.pending[track.ID]; !ok {
+		uc.pending[track.ID] = &domain.RestoreNotice{
+			TrackID:     track.ID,
+			Key:         track.StoragePath,
+			RequestedBy: userID,
+			RequestedAt: time.Now(),
+		}
+	}
+	uc.mu.Unlock()
+	return status, nil
+}
+
+// Check looks at every restore in progress and notifies about those that
+// have completed
+func (uc *StorageRestoreUseCase) Check(ctx context.Context) {
+	uc.mu.Lock()
+	notices := make([]*domain.RestoreNotice, 0, len(uc.pending))
+	for _, notice := range uc.pending {
+		notices = append(notices, notice)
+	}
+	uc.mu.Unlock()
+
+	for _, notice := range notices {
+		status, err := uc.tierer.ArchiveStatus(ctx, notice.Key)
+		if err != nil {
+			log.Printf("Error checking restore of track %s: %v", notice.TrackID, err)
+			continue
+		}
+		if !status.State.Readable() {
+			continue
+		}
+
+		notice.RestoredUntil = status.RestoredUntil
+		if uc.notifier != nil {
+			if err := uc.notifier.SendRestoreCompleted(ctx, notice); err != nil {
+				log.Printf("Error sending restore notice for track %s: %v", notice.TrackID, err)
+				continue
+			}
+		}
+		uc.mu.Lock()
+		delete(uc.pending, notice.TrackID)
+		uc.mu.Unlock()
+	}
+}
+
+// Run checks restores in progress every interval until ctx is cancelled
+func (uc *StorageRestoreUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			uc.Check(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (uc *StorageRestoreUseCase) track(ctx context.Context, trackID string) (*domain.Track, error) {
+	track, err := uc.tracks.GetByID(ctx, trackID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get track: %w", err)
+	}
+	if track == nil {
+		return nil, domain.ErrTrackNotFound
+	}
+	if track.StoragePath == "" {
+		return nil, fmt.Errorf("%w: track has no file", domain.ErrInvalidInput)
+	}
+	return track, nil
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTierer keeps the archive state of files and counts restores
+type fakeTierer struct {
+	states   map[string]pkgdomain.ArchiveState
+	restores int
+}
+
+func (f *fakeTierer) ApplyLifecycle(context.Context, pkgdomain.LifecyclePolicy) error { return nil }
+
+func (f *fakeTierer) ArchiveStatus(_ context.Context, key string) (*pkgdomain.ArchiveStatus, error) {
+	return &pkgdomain.ArchiveStatus{Key: key, StorageClass: pkgdomain.StorageClassArchive, State: f.states[key]}, nil
+}
+
+func (f *fakeTierer) Restore(_ context.Context, key string, _ int) error {
+	f.restores++
+	f.states[key] = pkgdomain.ArchiveStateRestoring
+	return nil
+}
+
+// recordingRestoreNotifier records the notices it is sent
+type recordingRestoreNotifier struct {
+	notices []*pkgdomain.RestoreNotice
+}
+
+func (n *recordingRestoreNotifier) SendRestoreCompleted(_ context.Context, notice *pkgdomain.RestoreNotice) error {
+	n.notices = append(n.notices, notice)
+	return nil
+}
+
+func TestStorageRestore_NotifiesOnceRestored(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockTrackRepository)
+	repo.On("GetByID", mock.Anything, "t1").Return(&pkgdomain.Track{ID: "t1", StoragePath: "tracks/t1/audio.wav"}, nil)
+	repo.On("GetByID", mock.Anything, "missing").Return(nil, nil)
+	tierer := &fakeTierer{states: map[string]pkgdomain.ArchiveState{"tracks/t1/audio.wav": pkgdomain.ArchiveStateArchived}}
+	notifier := &recordingRestoreNotifier{}
+	uc := NewStorageRestoreUseCase(repo, tierer, notifier, 3)
+
+	status, err := uc.Restore(ctx, "t1", "u1")
+	require.NoError(t, err)
+	assert.Equal(t, pkgdomain.ArchiveStateRestoring, status.State)
+
+	// Asking again does not start a second restore
+	_, err = uc.Restore(ctx, "t1", "u2")
+	require.NoError(t, err)
+	assert.Equal(t, 1, tierer.restores)
+
+	uc.Check(ctx)
+	assert.Empty(t, notifier.notices)
+
+	tierer.states["tracks/t1/audio.wav"] = pkgdomain.ArchiveStateRestored
+	uc.Check(ctx)
+	uc.Check(ctx)
+	require.Len(t, notifier.notices, 1)
+	assert.Equal(t, "t1", notifier.notices[0].TrackID)
+	assert.Equal(t, "u1", notifier.notices[0].RequestedBy)
+
+	_, err = uc.Restore(ctx, "missing", "u1")
+	assert.ErrorIs(t, err, pkgdomain.ErrTrackNotFound)
+}
+
+func TestStorageRestore_SkipsReadableFiles(t *testing.T) {
+	repo := new(MockTrackRepository)
+	repo.On("GetByID", mock.Anything, "t1").Return(&pkgdomain.Track{ID: "t1", StoragePath: "tracks/t1/audio.wav"}, nil)
+	tierer := &fakeTierer{states: map[string]pkgdomain.ArchiveState{"tracks/t1/audio.wav": pkgdomain.ArchiveStateAvailable}}
+	uc := NewStorageRestoreUseCase(repo, tierer, nil, 0)
+
+	status, err := uc.Restore(context.Background(), "t1", "u1")
+	require.NoError(t, err)
+	assert.Equal(t, pkgdomain.ArchiveStateAvailable, status.State)
+	assert.Zero(t, tierer.restores)
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+const (
+	// systemStatsTimeout bounds each source of the system stats
+	systemStatsTimeout = 3 * time.Second
+	// jobStatsWindow is the window the job failure rate is computed over
+	jobStatsWindow = time.Hour
+)
+
+// QuotaReporter reports the storage in use
+type QuotaReporter interface {
+	GetQuotaUsage(ctx context.Context) (int64, error)
+}
+
+// SystemStatsUseCase collects the operational state of the API and the
+// services it depends on into one snapshot. Every source is optional.
+type SystemStatsUseCase struct {
+	queues       []domain.QueueStatsSource
+	queueLag     domain.QueueLagSource
+	jobs         domain.JobStatsSource
+	storage      QuotaReporter
+	storageQuota int64
+	ai           domain.AIProviderStatsSource
+	db           *sql.DB
+	now          func() time.Time
+}
+
+// NewSystemStatsUseCase creates a system stats use case without sources
+func NewSystemStatsUseCase() *SystemStatsUseCase {
+	return &SystemStatsUseCase{now: time.Now}
+}
+
+// AddQueue adds queues whose depth is reported
+func (uc *SystemStatsUseCase) AddQueue(source domain.QueueStatsSource) {
+	uc.queues = append(uc.queues, source)
+}
+
+// SetQueueLag sets the source of the topic lag and backpressure
+func (uc *SystemStatsUseCase) SetQueueLag(source domain.QueueLagSource) {
+	uc.queueLag = source
+}
+
+// SetJobs sets the source of the job failure rate
+func (uc *SystemStatsUseCase) SetJobs(source domain.JobStatsSource) {
+	uc.jobs = source
+}
+
+// SetStorage sets the storage whose usage is reported against quota
+func (uc *SystemStatsUseCase) SetStorage(storage QuotaReporter, quota int64) {
+	uc.storage = storage
+	uc.storageQuota = quota
+}
+
+// SetAIProviders sets the source of the AI provider health
+func (uc *SystemStatsUseCase) SetAIProviders(source domain.AIProviderStatsSource) {
+	uc.ai = source
+}
+
+// SetDatabase sets the database whose connection pool is reported
+func (uc *SystemStatsUseCase) SetDatabase(db *sql.DB) {
+	uc.db = db
+}
+
+// Stats queries every source concurrently. A failing source does not fail
+// the snapshot; its error is reported under the section's name.
+func (uc *SystemStatsUseCase) Stats(ctx context.Context) *domain.SystemStats {
+	stats := &domain.SystemStats{
+		GeneratedAt: uc.now().UTC(),
+		Queues:      []domain.QueueStats{},
+		QueueLag:    []domain.TopicLag{},
+		AIProviders: []domain.AIProviderStats{},
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	collect := func(section string, fn func(ctx context.Context) error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, systemStatsTimeout)
+			defer cancel()
+
+			err := fn(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if stats.Errors == nil {
+					stats.Errors = make(map[string]string)
+				}
+				stats.Errors[section] = err.Error()
+			}
+		}()
+	}
+
+	queues := make([][]domain.QueueStats, len(uc.queues))
+	for i, source := range uc.queues {
+		collect("queues", func(ctx context.Context) error {
+			var err error
+			queues[i], err = source.QueueStats(ctx)
+			return err
+		})
+	}
+
+	var jobs *domain.JobStats
+	if uc.jobs != nil {
+		collect("jobs", func(ctx context.Context) error {
+			var err error
+			jobs, err = uc.jobs.JobStats(ctx, jobStatsWindow)
+			return err
+		})
+	}
+
+	var storage *domain.StorageStats
+	if uc.storage != nil {
+		collect("storage", func(ctx context.Context) error {
+			used, err := uc.storage.GetQuotaUsage(ctx)
+			if err != nil {
+				return err
+			}
+			storage = &domain.StorageStats{UsedBytes: used, QuotaBytes: uc.storageQuota}
+			if uc.storageQuota > 0 {
+				storage.UsedPercent = float64(used) / float64(uc.storageQuota) * 100
+			}
+			return nil
+		})
+	}
+
+	wg.Wait()
+
+	for _, q := range queues {
+		stats.Queues = append(stats.Queues, q...)
+	}
+	stats.Jobs = jobs
+	if uc.queueLag != nil {
+		stats.QueueLag = uc.queueLag.Lags()
+	}
+	stats.Storage = storage
+	if uc.ai != nil {
+		stats.AIProviders = uc.ai.ProviderStats()
+	}
+	if uc.db != nil {
+		pool := uc.db.Stats()
+		stats.Database = &domain.DatabaseStats{
+			MaxOpenConnections: pool.MaxOpenConnections,
+			OpenConnections:    pool.OpenConnections,
+			InUse:              pool.InUse,
+			Idle:               pool.Idle,
+			WaitCount:          pool.WaitCount,
+			WaitDurationMs:     pool.WaitDuration.Milliseconds(),
+		}
+	}
+	return stats
+}
+
+// OutboxQueueStats reports the change feed events waiting in the outbox as
+// a queue
+func OutboxQueueStats(repo domain.OutboxRepository) domain.QueueStatsSource {
+	return outboxQueueStats{repo: repo}
+}
+
+type outboxQueueStats struct {
+	repo domain.OutboxRepository
+}
+
+func (s outboxQueueStats) QueueStats(ctx context.Context) ([]domain.QueueStats, error) {
+	pending, err := s.repo.PendingCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []domain.QueueStats{{Name: "outbox", Pending: pending}}, nil
+}
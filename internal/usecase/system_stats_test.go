@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticQueueStats struct {
+	stats []domain.QueueStats
+	err   error
+}
+
+func (s staticQueueStats) QueueStats(ctx context.Context) ([]domain.QueueStats, error) {
+	return s.stats, s.err
+}
+
+type staticJobStats struct{}
+
+func (staticJobStats) JobStats(ctx context.Context, window time.Duration) (*domain.JobStats, error) {
+	return &domain.JobStats{WindowSeconds: int64(window.Seconds()), Completed: 9, Failed: 1, FailureRate: 0.1}, nil
+}
+
+type staticQuota int64
+
+func (q staticQuota) GetQuotaUsage(ctx context.Context) (int64, error) {
+	return int64(q), nil
+}
+
+func TestSystemStatsUseCase_Stats(t *testing.T) {
+	uc := NewSystemStatsUseCase()
+	uc.AddQueue(staticQueueStats{stats: []domain.QueueStats{{Name: "jobs", Pending: 4, DeadLetters: 2}}})
+	uc.AddQueue(staticQueueStats{err: errors.New("redis is down")})
+	uc.SetJobs(staticJobStats{})
+	uc.SetStorage(staticQuota(250), 1000)
+
+	stats := uc.Stats(context.Background())
+	assert.Equal(t, []domain.QueueStats{{Name: "jobs", Pending: 4, DeadLetters: 2}}, stats.Queues)
+	require.NotNil(t, stats.Jobs)
+	assert.Equal(t, int64(3600), stats.Jobs.WindowSeconds)
+	assert.Equal(t, &domain.StorageStats{UsedBytes: 250, QuotaBytes: 1000, UsedPercent: 25}, stats.Storage)
+	assert.Equal(t, map[string]string{"queues": "redis is down"}, stats.Errors)
+
+	// Sources that are not configured are reported empty
+	assert.Empty(t, stats.AIProviders)
+	assert.Nil(t, stats.Database)
+}
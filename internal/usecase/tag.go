@@ -0,0 +1,293 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/google/uuid"
+)
+
+// TagChangeResult reports a tag rename, merge or delete
+type TagChangeResult struct {
+	// Tag is the tag the tracks now carry, empty for deletes
+	Tag string `json:"tag,omitempty"`
+	// Tracks counts the tracks changed
+	Tracks int `json:"tracks"`
+	// Rules counts the tagging rules changed or, for deletes, removed
+	Rules int `json:"rules"`
+}
+
+// TagUseCase manages the catalog's tags and the rules adding them to
+// tracks when they are saved
+type TagUseCase struct {
+	tags   domain.TagRepository
+	rules  domain.TagRuleRepository
+	tracks domain.TrackRepository
+	now    func() time.Time
+}
+
+// NewTagUseCase creates a new tag use case
+func NewTagUseCase(tags domain.TagRepository, rules domain.TagRuleRepository, tracks domain.TrackRepository) *TagUseCase {
+	return &TagUseCase{tags: tags, rules: rules, tracks: tracks, now: time.Now}
+}
+
+// ListTags returns the catalog's tags
+func (uc *TagUseCase) ListTags(ctx context.Context) ([]*domain.Tag, error) {
+	return uc.tags.List(ctx)
+}
+
+// GetTag returns a tag by name, in any form
+func (uc *TagUseCase) GetTag(ctx context.Context, name string) (*domain.Tag, error) {
+	return uc.tags.Get(ctx, domain.NormalizeTag(name))
+}
+
+// CreateTag adds a tag, named in canonical form
+func (uc *TagUseCase) CreateTag(ctx context.Context, tag *domain.Tag) error {
+	if tag.Name = domain.NormalizeTag(tag.Name); tag.Name == "" {
+		return fmt.Errorf("%w: name is required", domain.ErrInvalidInput)
+	}
+	_, err := uc.tags.Get(ctx, tag.Name)
+	switch {
+	case err == nil:
+		return fmt.Errorf("%w: tag %q already exists", domain.ErrInvalidInput, tag.Name)
+	case !errors.Is(err, domain.ErrTagNotFound):
+		return err
+	}
+	tag.CreatedAt = uc.now()
+	tag.UpdatedAt = tag.CreatedAt
+	return uc.tags.Save(ctx, tag)
+}
+
+// UpdateTag replaces the description of a tag
+func (uc *TagUseCase) UpdateTag(ctx context.Context, name, description string) (*domain.Tag, error) {
+	tag, err := uc.GetTag(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	tag.Description = description
+	tag.UpdatedAt = uc.now()
+	if err := uc.tags.Save(ctx, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// RenameTag renames a tag on the tracks carrying it and in the rules adding
+// it. The new name must not be taken; merge into existing tags instead.
+// A failed rename can be repeated.
+func (uc *TagUseCase) RenameTag(ctx context.Context, from, to, actor string) (*TagChangeResult, error) {
+	tag, err := uc.GetTag(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	if to = domain.NormalizeTag(to); to == "" {
+		return nil, fmt.Errorf("%w: name is required", domain.ErrInvalidInput)
+	}
+	if to == tag.Name {
+		return &TagChangeResult{Tag: to}, nil
+	}
+	_, err = uc.tags.Get(ctx, to)
+	switch {
+	case err == nil:
+		return nil, fmt.Errorf("%w: tag %q already exists; merge into it instead", domain.ErrInvalidInput, to)
+	case !errors.Is(err, domain.ErrTagNotFound):
+		return nil, err
+	}
+
+	result, err := uc.retag(ctx, tag.Name, to, actor)
+	if err != nil {
+		return nil, err
+	}
+	renamed := *tag
+	renamed.Name = to
+	renamed.UpdatedAt = uc.now()
+	if err := uc.tags.Save(ctx, &renamed); err != nil {
+		return nil, err
+	}
+	if err := uc.tags.Delete(ctx, tag.Name); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// MergeTag replaces a tag with another on the tracks carrying it and in
+// the rules adding it, then deletes it
+func (uc *TagUseCase) MergeTag(ctx context.Context, from, into, actor string) (*TagChangeResult, error) {
+	tag, err := uc.GetTag(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	target, err := uc.GetTag(ctx, into)
+	if err != nil {
+		return nil, err
+	}
+	if tag.Name == target.Name {
+		return nil, fmt.Errorf("%w: a tag cannot be merged into itself", domain.ErrInvalidInput)
+	}
+
+	result, err := uc.retag(ctx, tag.Name, target.Name, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.tags.Delete(ctx, tag.Name); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteTag removes a tag from the tracks carrying it, deletes the rules
+// adding it, then deletes it
+func (uc *TagUseCase) DeleteTag(ctx context.Context, name, actor string) (*TagChangeResult, error) {
+	tag, err := uc.GetTag(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	result, err := uc.retag(ctx, tag.Name, "", actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.tags.Delete(ctx, tag.Name); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// retag replaces the tag from with the tag to on tracks and rules, or
+// removes it and deletes its rules when to is empty
+func (uc *TagUseCase) retag(ctx context.Context, from, to, actor string) (*TagChangeResult, error) {
+	result := &TagChangeResult{Tag: to}
+	tracks, err := uc.tracks.SearchByMetadata(ctx, map[string]interface{}{"tag": from})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tagged tracks: %w", err)
+	}
+	for _, found := range tracks {
+		track, err := domain.PatchTrack(ctx, uc.tracks, found.ID, func(track *domain.Track) error {
+			if changes := track.ReplaceTag(from, to); changes != nil {
+				track.RecordProvenance(changes, domain.ProvenanceManual, actor)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to retag track %s: %w", found.ID, err)
+		}
+		if track != nil {
+			result.Tracks++
+		}
+	}
+
+	rules, err := uc.rules.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.Tag != from {
+			continue
+		}
+		if to == "" {
+			err = uc.rules.Delete(ctx, rule.ID)
+		} else {
+			rule.Tag = to
+			rule.UpdatedAt = uc.now()
+			err = uc.rules.Save(ctx, rule)
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Rules++
+	}
+	return result, nil
+}
+
+// ListRules returns the tagging rules
+func (uc *TagUseCase) ListRules(ctx context.Context) ([]*domain.TagRule, error) {
+	return uc.rules.List(ctx)
+}
+
+// GetRule returns a tagging rule
+func (uc *TagUseCase) GetRule(ctx context.Context, id string) (*domain.TagRule, error) {
+	return uc.rules.Get(ctx, id)
+}
+
+// SaveRule stores a validated tagging rule. Without an ID a new rule is
+// created; with one the rule is replaced. The rule's tag is added to the
+// catalog if needed. Rules apply to tracks saved afterwards.
+func (uc *TagUseCase) SaveRule(ctx context.Context, rule *domain.TagRule) error {
+	now := uc.now()
+	rule.UpdatedAt = now
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+		rule.CreatedAt = now
+	} else {
+		existing, err := uc.rules.Get(ctx, rule.ID)
+		if err != nil {
+			return err
+		}
+		rule.CreatedAt = existing.CreatedAt
+	}
+	if err := uc.Register(ctx, rule.Tag); err != nil {
+		return err
+	}
+	return uc.rules.Save(ctx, rule)
+}
+
+// DeleteRule removes a tagging rule. The tags it added stay on the tracks.
+func (uc *TagUseCase) DeleteRule(ctx context.Context, id string) error {
+	return uc.rules.Delete(ctx, id)
+}
+
+// Rules returns the enabled tagging rules
+func (uc *TagUseCase) Rules(ctx context.Context) ([]*domain.TagRule, error) {
+	rules, err := uc.rules.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var enabled []*domain.TagRule
+	for _, rule := range rules {
+		if !rule.Disabled {
+			enabled = append(enabled, rule)
+		}
+	}
+	return enabled, nil
+}
+
+// Tag prepares the tags of a track being saved: they are written in
+// canonical form, the tags of the rules the track matches are added, and
+// tags new to the catalog are registered. It returns the tags added by
+// rules.
+func (uc *TagUseCase) Tag(ctx context.Context, track *domain.Track) ([]string, error) {
+	rules, err := uc.Rules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	track.NormalizeTags()
+	added := domain.ApplyTagRules(track, rules)
+	if err := uc.Register(ctx, track.Tags()...); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// Register adds the named tags to the catalog if they are not in it yet
+func (uc *TagUseCase) Register(ctx context.Context, names ...string) error {
+	for _, name := range names {
+		if name = domain.NormalizeTag(name); name == "" {
+			continue
+		}
+		_, err := uc.tags.Get(ctx, name)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, domain.ErrTagNotFound) {
+			return err
+		}
+		now := uc.now()
+		if err := uc.tags.Save(ctx, &domain.Tag{Name: name, CreatedAt: now, UpdatedAt: now}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
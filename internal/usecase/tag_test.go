@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryTagRepository keeps tags in memory
+type memoryTagRepository struct {
+	tags map[string]*pkgdomain.Tag
+}
+
+func (r *memoryTagRepository) Save(_ context.Context, tag *pkgdomain.Tag) error {
+	r.tags[tag.Name] = tag
+	return nil
+}
+
+func (r *memoryTagRepository) Get(_ context.Context, name string) (*pkgdomain.Tag, error) {
+	if tag, ok := r.tags[name]; ok {
+		return tag, nil
+	}
+	return nil, pkgdomain.ErrTagNotFound
+}
+
+func (r *memoryTagRepository) List(_ context.Context) ([]*pkgdomain.Tag, error) {
+	var out []*pkgdomain.Tag
+	for _, tag := range r.tags {
+		out = append(out, tag)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (r *memoryTagRepository) Delete(_ context.Context, name string) error {
+	if _, ok := r.tags[name]; !ok {
+		return pkgdomain.ErrTagNotFound
+	}
+	delete(r.tags, name)
+	return nil
+}
+
+// memoryTagRuleRepository keeps tagging rules in memory
+type memoryTagRuleRepository struct {
+	rules map[string]*pkgdomain.TagRule
+}
+
+func (r *memoryTagRuleRepository) Save(_ context.Context, rule *pkgdomain.TagRule) error {
+	r.rules[rule.ID] = rule
+	return nil
+}
+
+func (r *memoryTagRuleRepository) Get(_ context.Context, id string) (*pkgdomain.TagRule, error) {
+	if rule, ok := r.rules[id]; ok {
+		return rule, nil
+	}
+	return nil, pkgdomain.ErrTagRuleNotFound
+}
+
+func (r *memoryTagRuleRepository) List(_ context.Context) ([]*pkgdomain.TagRule, error) {
+	var out []*pkgdomain.TagRule
+	for _, rule := range r.rules {
+		out = append(out, rule)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (r *memoryTagRuleRepository) Delete(_ context.Context, id string) error {
+	if _, ok := r.rules[id]; !ok {
+		return pkgdomain.ErrTagRuleNotFound
+	}
+	delete(r.rules, id)
+	return nil
+}
+
+func newTagUseCase(t *testing.T) (*TagUseCase, *MockTrackRepository, *memoryTagRepository, *memoryTagRuleRepository) {
+	tags := &memoryTagRepository{tags: map[string]*pkgdomain.Tag{}}
+	rules := &memoryTagRuleRepository{rules: map[string]*pkgdomain.TagRule{}}
+	tracks := new(MockTrackRepository)
+	return NewTagUseCase(tags, rules, tracks), tracks, tags, rules
+}
+
+// drumAndBassRule tags fast drum and bass tracks
+func drumAndBassRule() *pkgdomain.TagRule {
+	return &pkgdomain.TagRule{
+		Name: "Drum and bass",
+		Tag:  "Drum and Bass",
+		Conditions: []pkgdomain.TagCondition{
+			{Field: "bpm", Op: pkgdomain.TagRuleGt, Value: 170.0},
+			{Field: "genre", Op: pkgdomain.TagRuleIn, Value: []interface{}{"dnb", "drum & bass"}},
+		},
+	}
+}
+
+func taggedTrack(id, genre string, bpm float64, tags ...string) *pkgdomain.Track {
+	track := &pkgdomain.Track{ID: id, Version: 1}
+	track.SetGenre(genre)
+	track.SetBPM(bpm)
+	track.Metadata.Additional.Tags = tags
+	return track
+}
+
+func TestTagRules_Match(t *testing.T) {
+	rule := drumAndBassRule()
+	require.Empty(t, rule.Validate())
+	assert.Equal(t, "drum-and-bass", rule.Tag)
+
+	assert.True(t, rule.Matches(taggedTrack("t1", "DnB", 174)))
+	assert.False(t, rule.Matches(taggedTrack("t2", "dnb", 140)))
+	assert.False(t, rule.Matches(taggedTrack("t3", "house", 174)))
+	// An unknown tempo is not below or above anything
+	assert.False(t, rule.Matches(taggedTrack("t4", "dnb", 0)))
+
+	contains := &pkgdomain.TagRule{Tag: "remix", Conditions: []pkgdomain.TagCondition{{Field: "title", Op: pkgdomain.TagRuleContains, Value: "remix"}}}
+	remix := taggedTrack("t5", "house", 124)
+	remix.SetTitle("Night Drive (Club Remix)")
+	assert.True(t, contains.Matches(remix))
+
+	invalid := &pkgdomain.TagRule{Tag: "x", Conditions: []pkgdomain.TagCondition{
+		{Field: "nope", Op: pkgdomain.TagRuleEq, Value: "x"},
+		{Field: "bpm", Op: pkgdomain.TagRuleGt, Value: "fast"},
+		{Field: "genre", Op: "like", Value: "x"},
+	}}
+	assert.Len(t, invalid.Validate(), 3)
+}
+
+func TestTagUseCase_TagAppliesRulesAndRegistersTags(t *testing.T) {
+	uc, _, tags, _ := newTagUseCase(t)
+	ctx := context.Background()
+	rule := drumAndBassRule()
+	require.Empty(t, rule.Validate())
+	require.NoError(t, uc.SaveRule(ctx, rule))
+	disabled := &pkgdomain.TagRule{Tag: "never", Disabled: true, Conditions: []pkgdomain.TagCondition{{Field: "genre", Op: pkgdomain.TagRuleEq, Value: "dnb"}}}
+	require.NoError(t, uc.SaveRule(ctx, disabled))
+
+	track := taggedTrack("t1", "dnb", 174, "Late Night", "late_night", "")
+	added, err := uc.Tag(ctx, track)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"drum-and-bass"}, added)
+	assert.Equal(t, []string{"late-night", "drum-and-bass"}, track.Tags())
+	assert.Contains(t, tags.tags, "late-night")
+	assert.Contains(t, tags.tags, "drum-and-bass")
+}
+
+func TestTagUseCase_RenameRewritesTracksAndRules(t *testing.T) {
+	uc, tracks, tags, rules := newTagUseCase(t)
+	ctx := context.Background()
+	rule := drumAndBassRule()
+	require.Empty(t, rule.Validate())
+	require.NoError(t, uc.SaveRule(ctx, rule))
+
+	track := taggedTrack("t1", "dnb", 174, "drum-and-bass", "late-night")
+	tracks.On("SearchByMetadata", mock.Anything, map[string]interface{}{"tag": "drum-and-bass"}).Return([]*pkgdomain.Track{track}, nil)
+	tracks.On("GetByID", mock.Anything, "t1").Return(track, nil)
+	tracks.On("Update", mock.Anything, track).Return(nil)
+
+	result, err := uc.RenameTag(ctx, "Drum and Bass", "DnB", "user-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, &TagChangeResult{Tag: "dnb", Tracks: 1, Rules: 1}, result)
+	assert.Equal(t, []string{"late-night", "dnb"}, track.Tags())
+	assert.Equal(t, "dnb", rules.rules[rule.ID].Tag)
+	assert.Contains(t, tags.tags, "dnb")
+	assert.NotContains(t, tags.tags, "drum-and-bass")
+
+	require.NoError(t, uc.CreateTag(ctx, &pkgdomain.Tag{Name: "jungle"}))
+	_, err = uc.RenameTag(ctx, "dnb", "Jungle", "user-1")
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput)
+}
+
+func TestTagUseCase_MergeAndDeleteCascade(t *testing.T) {
+	uc, tracks, tags, rules := newTagUseCase(t)
+	ctx := context.Background()
+	rule := drumAndBassRule()
+	require.Empty(t, rule.Validate())
+	require.NoError(t, uc.SaveRule(ctx, rule))
+	require.NoError(t, uc.Register(ctx, "dnb", "chill"))
+
+	both := taggedTrack("t1", "dnb", 174, "dnb", "drum-and-bass")
+	tracks.On("SearchByMetadata", mock.Anything, map[string]interface{}{"tag": "drum-and-bass"}).Return([]*pkgdomain.Track{both}, nil)
+	tracks.On("GetByID", mock.Anything, "t1").Return(both, nil)
+	tracks.On("Update", mock.Anything, both).Return(nil)
+
+	result, err := uc.MergeTag(ctx, "drum-and-bass", "dnb", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Tracks)
+	assert.Equal(t, []string{"dnb"}, both.Tags())
+	assert.Equal(t, "dnb", rules.rules[rule.ID].Tag)
+	assert.NotContains(t, tags.tags, "drum-and-bass")
+
+	chill := taggedTrack("t2", "ambient", 80, "chill", "dnb")
+	tracks.On("SearchByMetadata", mock.Anything, map[string]interface{}{"tag": "dnb"}).Return([]*pkgdomain.Track{chill}, nil)
+	tracks.On("GetByID", mock.Anything, "t2").Return(chill, nil)
+	tracks.On("Update", mock.Anything, chill).Return(nil)
+
+	result, err = uc.DeleteTag(ctx, "dnb", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, &TagChangeResult{Tracks: 1, Rules: 1}, result)
+	assert.Equal(t, []string{"chill"}, chill.Tags())
+	assert.Empty(t, rules.rules)
+	assert.NotContains(t, tags.tags, "dnb")
+
+	_, err = uc.DeleteTag(ctx, "dnb", "user-1")
+	assert.ErrorIs(t, err, pkgdomain.ErrTagNotFound)
+}
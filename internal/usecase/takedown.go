@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+
+	"github.com/google/uuid"
+)
+
+// takedownMessageSender is the sender of generated takedown messages
+const takedownMessageSender = "MetadataTool"
+
+// territoryCodePattern matches ISO 3166-1 alpha-2 country codes
+var territoryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// TakedownRequest withdraws delivered tracks from a DSP. Without
+// territories the tracks are taken down everywhere; with them they are
+// blocked in those territories only.
+type TakedownRequest struct {
+	DSP         string   `json:"dsp" binding:"required"`
+	TrackIDs    []string `json:"track_ids" binding:"required,min=1"`
+	Territories []string `json:"territories,omitempty"`
+}
+
+// TakedownResult is a generated takedown or territory-block message and
+// the deliveries recorded for it
+type TakedownResult struct {
+	PackageID string              `json:"package_id"`
+	Kind      domain.DeliveryKind `json:"kind"`
+	// DryRun is set when nothing was recorded
+	DryRun     bool               `json:"dry_run,omitempty"`
+	Deliveries []*domain.Delivery `json:"deliveries"`
+	// Message is the DDEX update message to send to the DSP
+	Message string `json:"message"`
+}
+
+// RequestTakedown generates the DDEX update message that withdraws tracks
+// from a DSP and records a delivery per track, which the DSP's
+// acknowledgement updates like any other. Every track must have been
+// delivered to the DSP and acknowledged. Dry runs return the message and
+// deliveries without recording them.
+func (uc *DeliveryUseCase) RequestTakedown(ctx context.Context, req *TakedownRequest) (*TakedownResult, error) {
+	if len(req.TrackIDs) > MaxDeliveryTracks {
+		return nil, fmt.Errorf("%w: a takedown holds at most %d tracks", domain.ErrInvalidInput, MaxDeliveryTracks)
+	}
+	territories, err := normalizeTerritories(req.Territories)
+	if err != nil {
+		return nil, err
+	}
+	kind := domain.DeliveryKindTakedown
+	if len(territories) > 0 {
+		kind = domain.DeliveryKindTerritoryBlock
+	}
+
+	var tracks []*domain.Track
+	seen := make(map[string]bool, len(req.TrackIDs))
+	for _, id := range req.TrackIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		track, err := uc.tracks.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if track == nil {
+			return nil, fmt.Errorf("%w: track %s", domain.ErrTrackNotFound, id)
+		}
+		delivered, err := uc.deliveries.List(ctx, domain.DeliveryFilter{
+			DSP:     req.DSP,
+			TrackID: id,
+			Kind:    domain.DeliveryKindRelease,
+			Status:  domain.DeliveryStatusAcknowledged,
+			Limit:   1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(delivered) == 0 {
+			return nil, fmt.Errorf("%w: track %s was not delivered to %s", domain.ErrInvalidInput, id, req.DSP)
+		}
+		tracks = append(tracks, track)
+	}
+
+	now := uc.now()
+	message := newTakedownMessage(uuid.New().String(), req.DSP, tracks, territories, now.UTC().Format(time.RFC3339))
+	output, err := xml.MarshalIndent(message, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal takedown message: %w", err)
+	}
+
+	packageID := message.MessageHeader.MessageID
+	deliveries := make([]*domain.Delivery, len(tracks))
+	for i, track := range tracks {
+		deliveries[i] = &domain.Delivery{
+			ID:            uuid.New().String(),
+			DSP:           req.DSP,
+			TrackID:       track.ID,
+			ReleaseID:     track.ReleaseID,
+			PackageID:     packageID,
+			PackageFormat: "ERN " + domain.DDEXERN43,
+			Kind:          kind,
+			Territories:   territories,
+			Status:        domain.DeliveryStatusSent,
+			DeliveredAt:   now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+	}
+	result := &TakedownResult{
+		PackageID:  packageID,
+		Kind:       kind,
+		Deliveries: deliveries,
+		Message:    xml.Header + string(output),
+	}
+	if domain.DryRunFromContext(ctx) {
+		result.DryRun = true
+		return result, nil
+	}
+
+	if err := uc.deliveries.Create(ctx, deliveries); err != nil {
+		return nil, err
+	}
+	metrics.Deliveries.WithLabelValues(req.DSP, string(domain.DeliveryStatusSent)).Add(float64(len(deliveries)))
+	return result, nil
+}
+
+// normalizeTerritories upper-cases and deduplicates territory codes and
+// rejects those that are not ISO 3166-1 alpha-2 codes
+func normalizeTerritories(codes []string) ([]string, error) {
+	var territories []string
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !territoryCodePattern.MatchString(code) {
+			return nil, fmt.Errorf("%w: territory %q is not an ISO 3166-1 alpha-2 code", domain.ErrInvalidInput, code)
+		}
+		if !seen[code] {
+			seen[code] = true
+			territories = append(territories, code)
+		}
+	}
+	return territories, nil
+}
+
+// newTakedownMessage creates the ERN update message withdrawing tracks:
+// a worldwide takedown deal, or a deal excluding the blocked territories
+func newTakedownMessage(messageID, dsp string, tracks []*domain.Track, territories []string, createdAt string) *domain.ERNMessage {
+	message := &domain.ERNMessage{
+		MessageHeader: domain.MessageHeader{
+			MessageID:              messageID,
+			MessageSender:          takedownMessageSender,
+			MessageRecipient:       dsp,
+			MessageCreatedDateTime: createdAt,
+		},
+		UpdateIndicator: domain.DDEXUpdateMessage,
+	}
+
+	for _, track := range tracks {
+		message.ResourceList.SoundRecordings = append(message.ResourceList.SoundRecordings, domain.SoundRecording{
+			ISRC:               track.ISRC(),
+			Title:              domain.Title{TitleText: track.Title()},
+			SoundRecordingType: "MusicalWorkSoundRecording",
+			ResourceReference:  track.ID,
+		})
+		message.ReleaseList.Releases = append(message.ReleaseList.Releases, domain.Release{
+			ReleaseID:      domain.ReleaseID{ICPN: track.ID},
+			ReferenceTitle: domain.Title{TitleText: track.Title()},
+			ReleaseType:    "Single",
+		})
+
+		deal := domain.Deal{
+			Territory: domain.Territory{TerritoryCode: domain.DDEXWorldwide},
+			DealTerms: domain.DealTerms{
+				CommercialModelType: "PayAsYouGoModel",
+				Usage:               domain.Usage{UseType: "OnDemandStream"},
+			},
+		}
+		if len(territories) > 0 {
+			deal.Territory.ExcludedTerritoryCodes = territories
+		} else {
+			deal.DealTerms.TakeDown = true
+		}
+		message.DealList.ReleaseDeals = append(message.DealList.ReleaseDeals, domain.ReleaseDeal{
+			DealReleaseReference: track.ID,
+			Deal:                 deal,
+		})
+	}
+	return message
+}
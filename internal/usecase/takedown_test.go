@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveryUseCase_RequestTakedown(t *testing.T) {
+	// delivered returns a use case whose track t1 was delivered to spotify
+	delivered := func(t *testing.T) (*DeliveryUseCase, *memoryDeliveryRepository, *MockTrackRepository) {
+		uc, repo, trackRepo := newDeliveryUseCase(pkgdomain.TrackStatusApproved)
+		_, err := uc.Record(context.Background(), &RecordDeliveryRequest{DSP: "spotify", PackageID: "pkg-1", TrackIDs: []string{"t1"}})
+		require.NoError(t, err)
+		_, err = uc.Acknowledge(context.Background(), []byte(fmt.Sprintf(testAcknowledgement, "FileOK", "")))
+		require.NoError(t, err)
+		return uc, repo, trackRepo
+	}
+
+	t.Run("takedown", func(t *testing.T) {
+		uc, repo, _ := delivered(t)
+
+		result, err := uc.RequestTakedown(context.Background(), &TakedownRequest{DSP: "spotify", TrackIDs: []string{"t1"}})
+		require.NoError(t, err)
+		assert.Equal(t, pkgdomain.DeliveryKindTakedown, result.Kind)
+		require.Len(t, result.Deliveries, 1)
+		assert.Equal(t, pkgdomain.DeliveryStatusSent, result.Deliveries[0].Status)
+		assert.Contains(t, result.Message, "<updateIndicator>UpdateMessage</updateIndicator>")
+		assert.Contains(t, result.Message, "<takeDown>true</takeDown>")
+		assert.Contains(t, result.Message, "<messageRecipient>spotify</messageRecipient>")
+		assert.Len(t, repo.deliveries, 2)
+	})
+
+	t.Run("territory block acknowledged", func(t *testing.T) {
+		uc, repo, trackRepo := delivered(t)
+
+		result, err := uc.RequestTakedown(context.Background(), &TakedownRequest{
+			DSP: "spotify", TrackIDs: []string{"t1"}, Territories: []string{"de", "FR", "DE"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, pkgdomain.DeliveryKindTerritoryBlock, result.Kind)
+		assert.Equal(t, []string{"DE", "FR"}, result.Deliveries[0].Territories)
+		assert.Contains(t, result.Message, "<excludedTerritoryCode>DE</excludedTerritoryCode>")
+		assert.NotContains(t, result.Message, "<takeDown>")
+
+		ack := fmt.Sprintf(testAcknowledgement, "FileOK", "")
+		ack = strings.Replace(ack, "<messageId>pkg-1</messageId>", "<messageId>"+result.PackageID+"</messageId>", 1)
+		updates := len(trackRepo.Calls)
+		_, err = uc.Acknowledge(context.Background(), []byte(ack))
+		require.NoError(t, err)
+		assert.Equal(t, pkgdomain.DeliveryStatusAcknowledged, repo.deliveries[1].Status)
+		assert.Len(t, trackRepo.Calls, updates, "acknowledged blocks leave the track status alone")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		uc, repo, _ := delivered(t)
+
+		result, err := uc.RequestTakedown(pkgdomain.WithDryRun(context.Background()), &TakedownRequest{DSP: "spotify", TrackIDs: []string{"t1"}})
+		require.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Contains(t, result.Message, "<takeDown>true</takeDown>")
+		assert.Len(t, repo.deliveries, 1, "a dry run records nothing")
+	})
+
+	t.Run("not delivered", func(t *testing.T) {
+		uc, _, _ := delivered(t)
+
+		_, err := uc.RequestTakedown(context.Background(), &TakedownRequest{DSP: "apple", TrackIDs: []string{"t1"}})
+		assert.True(t, errors.Is(err, pkgdomain.ErrInvalidInput))
+	})
+
+	t.Run("invalid territory", func(t *testing.T) {
+		uc, repo, _ := delivered(t)
+
+		_, err := uc.RequestTakedown(context.Background(), &TakedownRequest{
+			DSP: "spotify", TrackIDs: []string{"t1"}, Territories: []string{"Germany"},
+		})
+		assert.True(t, errors.Is(err, pkgdomain.ErrInvalidInput))
+		assert.Len(t, repo.deliveries, 1)
+	})
+}
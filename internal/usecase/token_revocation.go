@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// RevocationAwareAuthService wraps an auth service so that tokens belonging
+// to a revoked refresh token family, or issued to a user before all of the
+// user's tokens were revoked, fail validation. Every middleware that
+// validates through the auth service therefore honours revocations.
+type RevocationAwareAuthService struct {
+	domain.AuthService
+	store domain.RefreshTokenStore
+}
+
+// NewRevocationAwareAuthService creates a new revocation aware auth service
+func NewRevocationAwareAuthService(authService domain.AuthService, store domain.RefreshTokenStore) *RevocationAwareAuthService {
+	return &RevocationAwareAuthService{
+		AuthService: authService,
+		store:       store,
+	}
+}
+
+// ValidateToken validates a token and rejects it if its family or its
+// user's tokens were revoked
+func (s *RevocationAwareAuthService) ValidateToken(ctx context.Context, token string) (*domain.Claims, error) {
+	claims, err := s.AuthService.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.FamilyID != "" {
+		revoked, err := s.store.IsRevoked(ctx, claims.FamilyID)
+		if err != nil {
+			return nil, fmt.Errorf("error checking token revocation: %w", err)
+		}
+		if revoked {
+			return nil, domain.ErrInvalidToken
+		}
+	}
+
+	revokedAt, err := s.store.RevokedBefore(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking token revocation: %w", err)
+	}
+	// Tokens carry their issue time in whole seconds, so one issued in the
+	// second of the revocation survives; its family is revoked all the same
+	if !revokedAt.IsZero() && (claims.IssuedAt == nil || claims.IssuedAt.Time.Before(revokedAt.Truncate(time.Second))) {
+		return nil, domain.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// GenerateTokensInFamily delegates to the wrapped service
+func (s *RevocationAwareAuthService) GenerateTokensInFamily(user *domain.User, familyID string) (*domain.TokenPair, error) {
+	issuer, ok := s.AuthService.(domain.TokenFamilyIssuer)
+	if !ok {
+		return nil, errors.New("auth service does not support token families")
+	}
+	return issuer.GenerateTokensInFamily(user, familyID)
+}
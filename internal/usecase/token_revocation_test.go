@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/config"
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryRefreshTokenStore struct {
+	mu          sync.Mutex
+	current     map[string]string
+	revoked     map[string]bool
+	families    map[string][]string
+	userRevoked map[string]time.Time
+}
+
+func newMemoryRefreshTokenStore() *memoryRefreshTokenStore {
+	return &memoryRefreshTokenStore{
+		current:     map[string]string{},
+		revoked:     map[string]bool{},
+		families:    map[string][]string{},
+		userRevoked: map[string]time.Time{},
+	}
+}
+
+func (s *memoryRefreshTokenStore) Issue(ctx context.Context, userID, familyID, tokenID string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current[familyID] = tokenID
+	s.families[userID] = append(s.families[userID], familyID)
+	return nil
+}
+
+func (s *memoryRefreshTokenStore) Rotate(ctx context.Context, familyID, presentedID, nextID string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.revoked[familyID] {
+		return domain.ErrInvalidToken
+	}
+	if s.current[familyID] != presentedID {
+		s.revoked[familyID] = true
+		delete(s.current, familyID)
+		return domain.ErrTokenReused
+	}
+	s.current[familyID] = nextID
+	return nil
+}
+
+func (s *memoryRefreshTokenStore) RevokeFamily(ctx context.Context, familyID string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revoked[familyID] = true
+	return nil
+}
+
+func (s *memoryRefreshTokenStore) IsRevoked(ctx context.Context, familyID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.revoked[familyID], nil
+}
+
+func (s *memoryRefreshTokenStore) RevokeUser(ctx context.Context, userID string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, familyID := range s.families[userID] {
+		s.revoked[familyID] = true
+		delete(s.current, familyID)
+	}
+	delete(s.families, userID)
+	s.userRevoked[userID] = time.Now()
+	return nil
+}
+
+func (s *memoryRefreshTokenStore) RevokedBefore(ctx context.Context, userID string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.userRevoked[userID], nil
+}
+
+func TestAuthUseCase_RefreshTokenReuseRevokesFamily(t *testing.T) {
+	store := newMemoryRefreshTokenStore()
+	authService := NewRevocationAwareAuthService(NewAuthService(&config.AuthConfig{
+		JWTSecret:       "test-secret",
+		AccessTokenTTL:  time.Minute,
+		RefreshTokenTTL: time.Hour,
+	}), store)
+
+	userRepo := new(MockUserRepository)
+	sessions := new(MockSessionStore)
+	user := createTestUser()
+	userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+
+	uc := NewAuthUseCase(userRepo, sessions, authService)
+	uc.SetRefreshTokenStore(store, time.Hour)
+
+	tokens, err := authService.GenerateTokens(user)
+	require.NoError(t, err)
+	require.NoError(t, store.Issue(context.Background(), user.ID, tokens.FamilyID, tokens.RefreshTokenID, time.Hour))
+
+	access, refresh, _, err := uc.RefreshToken(context.Background(), tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, tokens.RefreshToken, refresh)
+
+	// Refresh tokens cannot be used as access tokens
+	_, err = uc.ValidateToken(context.Background(), refresh)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+
+	_, err = uc.ValidateToken(context.Background(), access)
+	require.NoError(t, err)
+
+	// Replaying the first refresh token revokes the family
+	_, _, _, err = uc.RefreshToken(context.Background(), tokens.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrTokenReused)
+
+	_, _, _, err = uc.RefreshToken(context.Background(), refresh)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+
+	_, err = uc.ValidateToken(context.Background(), access)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+}
+
+func TestAuthUseCase_LogoutRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryRefreshTokenStore()
+	authService := NewRevocationAwareAuthService(NewAuthService(&config.AuthConfig{
+		JWTSecret:       "test-secret",
+		AccessTokenTTL:  time.Minute,
+		RefreshTokenTTL: time.Hour,
+	}), store)
+
+	user := createTestUser()
+	hashed, err := authService.HashPassword("password")
+	require.NoError(t, err)
+	user.Password = hashed
+
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByEmail", mock.Anything, user.Email).Return(user, nil)
+	userRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil)
+
+	sessions := new(MockSessionStore)
+	sessions.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	uc := NewAuthUseCase(userRepo, sessions, authService)
+	uc.SetRefreshTokenStore(store, time.Hour)
+
+	login, err := uc.Login(ctx, LoginInput{Email: user.Email, Password: "password"})
+	require.NoError(t, err)
+	other, err := uc.Login(ctx, LoginInput{Email: user.Email, Password: "password"})
+	require.NoError(t, err)
+
+	sessions.On("Get", mock.Anything, login.Session.ID).Return(login.Session, nil)
+	sessions.On("Delete", mock.Anything, login.Session.ID).Return(nil)
+	require.NoError(t, uc.Logout(ctx, login.Session.ID))
+
+	_, _, _, err = uc.RefreshToken(ctx, login.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	_, err = uc.ValidateToken(ctx, login.AccessToken)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+
+	// Sessions elsewhere stay signed in until all of them are revoked
+	_, err = uc.ValidateToken(ctx, other.AccessToken)
+	require.NoError(t, err)
+
+	sessions.On("DeleteUserSessions", mock.Anything, user.ID).Return(nil)
+	require.NoError(t, uc.RevokeAllSessions(ctx, user.ID))
+
+	_, _, _, err = uc.RefreshToken(ctx, other.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	_, err = uc.ValidateToken(ctx, other.AccessToken)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/google/uuid"
+)
+
+// reanalysisBatchSize is the number of outdated tracks read at a time when
+// queueing them for analysis
+const reanalysisBatchSize = 500
+
+// AnalysisUseCase stores the audio analyses of tracks and has them redone
+// when the analyzer changes
+type AnalysisUseCase struct {
+	analyses domain.AnalysisRepository
+	tracks   domain.TrackRepository
+	jobs     domain.JobQueue
+}
+
+// NewAnalysisUseCase creates a new analysis use case. Tracks are only
+// queued for analysis once SetJobQueue gives it a queue.
+func NewAnalysisUseCase(analyses domain.AnalysisRepository, tracks domain.TrackRepository) *AnalysisUseCase {
+	return &AnalysisUseCase{analyses: analyses, tracks: tracks}
+}
+
+// SetJobQueue queues reanalysis and audio processing jobs on jobs
+func (uc *AnalysisUseCase) SetJobQueue(jobs domain.JobQueue) {
+	uc.jobs = jobs
+}
+
+// Save stores the analysis of a track by an analyzer version, replacing
+// the analysis that version made before
+func (uc *AnalysisUseCase) Save(ctx context.Context, trackID, version string, analysis *domain.AudioAnalysis) error {
+	if version == "" {
+		return fmt.Errorf("%w: analyzer version is required", domain.ErrInvalidInput)
+	}
+	analyzedAt := analysis.AnalyzedAt
+	if analyzedAt.IsZero() {
+		analyzedAt = time.Now()
+	}
+	return uc.analyses.Save(ctx, &domain.TrackAnalysis{
+		TrackID:         trackID,
+		AnalyzerVersion: version,
+		Analysis:        analysis,
+		AnalyzedAt:      analyzedAt,
+	})
+}
+
+// Get returns the analysis of a track by an analyzer version, or its
+// latest analysis when version is empty
+func (uc *AnalysisUseCase) Get(ctx context.Context, trackID, version string) (*domain.TrackAnalysis, error) {
+	track, err := uc.tracks.GetByID(ctx, trackID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get track: %w", err)
+	}
+	if track == nil {
+		return nil, domain.ErrTrackNotFound
+	}
+	return uc.analyses.Get(ctx, trackID, version)
+}
+
+// ScheduleReanalysis queues a job to analyze the tracks again when the
+// analyzer has changed: tracks were analyzed by other versions and none
+// yet by version. It reports whether a job was queued.
+func (uc *AnalysisUseCase) ScheduleReanalysis(ctx context.Context, version string) (bool, error) {
+	if uc.jobs == nil {
+		return false, fmt.Errorf("no job queue to schedule reanalysis on")
+	}
+	versions, err := uc.analyses.Versions(ctx)
+	if err != nil {
+		return false, err
+	}
+	if versions[version] > 0 || len(versions) == 0 {
+		return false, nil
+	}
+
+	payload, err := json.Marshal(domain.ReanalyzePayload{AnalyzerVersion: version})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	err = uc.jobs.Enqueue(ctx, &domain.Job{
+		ID:         uuid.New().String(),
+		Type:       domain.JobTypeReanalyze,
+		Priority:   domain.JobPriorityLow,
+		Status:     domain.JobStatusPending,
+		Payload:    payload,
+		MaxRetries: 3,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to enqueue reanalysis: %w", err)
+	}
+	return true, nil
+}
+
+// Reanalyze queues audio processing of every track analyzed by other
+// versions but not by version, and returns how many it queued. Tracks
+// already analyzed by version are skipped, so running it again only
+// queues the tracks still outstanding.
+func (uc *AnalysisUseCase) Reanalyze(ctx context.Context, version string) (int, error) {
+	if uc.jobs == nil {
+		return 0, fmt.Errorf("no job queue to queue analyses on")
+	}
+	queued := 0
+	afterID := ""
+	for {
+		targets, err := uc.analyses.Outdated(ctx, version, afterID, reanalysisBatchSize)
+		if err != nil {
+			return queued, err
+		}
+		for _, target := range targets {
+			payload, err := json.Marshal(domain.AudioProcessPayload{TrackID: target.TrackID, StoragePath: target.StoragePath})
+			if err != nil {
+				return queued, fmt.Errorf("failed to marshal payload: %w", err)
+			}
+			err = uc.jobs.Enqueue(ctx, &domain.Job{
+				ID:         uuid.New().String(),
+				Type:       domain.JobTypeAudioProcess,
+				Priority:   domain.JobPriorityLow,
+				Status:     domain.JobStatusPending,
+				Payload:    payload,
+				MaxRetries: 3,
+				CreatedAt:  time.Now(),
+			})
+			if err != nil {
+				return queued, fmt.Errorf("failed to enqueue analysis of track %s: %w", target.TrackID, err)
+			}
+			queued++
+		}
+		if len(targets) < reanalysisBatchSize {
+			return queued, nil
+		}
+		afterID = targets[len(targets)-1].TrackID
+	}
+}
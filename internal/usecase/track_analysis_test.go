@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memAnalysisRepository keeps analyses in memory by track and version
+type memAnalysisRepository struct {
+	saved map[string]map[string]*pkgdomain.TrackAnalysis
+}
+
+func newMemAnalysisRepository() *memAnalysisRepository {
+	return &memAnalysisRepository{saved: make(map[string]map[string]*pkgdomain.TrackAnalysis)}
+}
+
+func (r *memAnalysisRepository) Save(_ context.Context, analysis *pkgdomain.TrackAnalysis) error {
+	if r.saved[analysis.TrackID] == nil {
+		r.saved[analysis.TrackID] = make(map[string]*pkgdomain.TrackAnalysis)
+	}
+	r.saved[analysis.TrackID][analysis.AnalyzerVersion] = analysis
+	return nil
+}
+
+func (r *memAnalysisRepository) Get(_ context.Context, trackID, version string) (*pkgdomain.TrackAnalysis, error) {
+	var latest *pkgdomain.TrackAnalysis
+	for v, analysis := range r.saved[trackID] {
+		if version == v || version == "" && (latest == nil || analysis.AnalyzedAt.After(latest.AnalyzedAt)) {
+			latest = analysis
+		}
+	}
+	if latest == nil {
+		return nil, pkgdomain.ErrAnalysisNotFound
+	}
+	return latest, nil
+}
+
+func (r *memAnalysisRepository) Versions(_ context.Context) (map[string]int64, error) {
+	versions := make(map[string]int64)
+	for _, analyses := range r.saved {
+		for version := range analyses {
+			versions[version]++
+		}
+	}
+	return versions, nil
+}
+
+func (r *memAnalysisRepository) Outdated(_ context.Context, version, afterID string, limit int) ([]pkgdomain.ReanalysisTarget, error) {
+	var ids []string
+	for id, analyses := range r.saved {
+		if _, ok := analyses[version]; !ok && id > afterID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	targets := make([]pkgdomain.ReanalysisTarget, len(ids))
+	for i, id := range ids {
+		targets[i] = pkgdomain.ReanalysisTarget{TrackID: id, StoragePath: "audio/" + id + ".wav"}
+	}
+	return targets, nil
+}
+
+// memJobQueue records the jobs enqueued
+type memJobQueue struct {
+	pkgdomain.JobQueue
+	jobs []*pkgdomain.Job
+}
+
+func (q *memJobQueue) Enqueue(_ context.Context, job *pkgdomain.Job) error {
+	q.jobs = append(q.jobs, job)
+	return nil
+}
+
+func TestAnalysisUseCase_Get(t *testing.T) {
+	tracks := new(MockTrackRepository)
+	tracks.On("GetByID", mock.Anything, "analyzed").Return(&pkgdomain.Track{ID: "analyzed"}, nil)
+	tracks.On("GetByID", mock.Anything, "new").Return(&pkgdomain.Track{ID: "new"}, nil)
+	tracks.On("GetByID", mock.Anything, "missing").Return(nil, nil)
+
+	uc := NewAnalysisUseCase(newMemAnalysisRepository(), tracks)
+	ctx := context.Background()
+	require.NoError(t, uc.Save(ctx, "analyzed", "v1", &pkgdomain.AudioAnalysis{BPM: 120, Beats: []float64{0.5, 1}}))
+	require.NoError(t, uc.Save(ctx, "analyzed", "v2", &pkgdomain.AudioAnalysis{BPM: 121, Beats: []float64{0.49, 0.99}}))
+	assert.ErrorIs(t, uc.Save(ctx, "analyzed", "", &pkgdomain.AudioAnalysis{}), pkgdomain.ErrInvalidInput)
+
+	analysis, err := uc.Get(ctx, "analyzed", "")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", analysis.AnalyzerVersion)
+	assert.Equal(t, []float64{0.49, 0.99}, analysis.Analysis.Beats)
+	assert.False(t, analysis.AnalyzedAt.IsZero())
+
+	analysis, err = uc.Get(ctx, "analyzed", "v1")
+	require.NoError(t, err)
+	assert.Equal(t, 120.0, analysis.Analysis.BPM)
+
+	_, err = uc.Get(ctx, "new", "")
+	assert.ErrorIs(t, err, pkgdomain.ErrAnalysisNotFound)
+	_, err = uc.Get(ctx, "missing", "")
+	assert.ErrorIs(t, err, pkgdomain.ErrTrackNotFound)
+}
+
+func TestAnalysisUseCase_ScheduleReanalysis(t *testing.T) {
+	ctx := context.Background()
+	analyses := newMemAnalysisRepository()
+	queue := &memJobQueue{}
+	uc := NewAnalysisUseCase(analyses, new(MockTrackRepository))
+
+	_, err := uc.ScheduleReanalysis(ctx, "v2")
+	assert.Error(t, err, "no job queue")
+	uc.SetJobQueue(queue)
+
+	// Nothing analyzed yet, so nothing to redo
+	scheduled, err := uc.ScheduleReanalysis(ctx, "v2")
+	require.NoError(t, err)
+	assert.False(t, scheduled)
+
+	require.NoError(t, uc.Save(ctx, "a", "v1", &pkgdomain.AudioAnalysis{}))
+	scheduled, err = uc.ScheduleReanalysis(ctx, "v2")
+	require.NoError(t, err)
+	assert.True(t, scheduled)
+	require.Len(t, queue.jobs, 1)
+	assert.Equal(t, pkgdomain.JobTypeReanalyze, queue.jobs[0].Type)
+	var payload pkgdomain.ReanalyzePayload
+	require.NoError(t, json.Unmarshal(queue.jobs[0].Payload, &payload))
+	assert.Equal(t, "v2", payload.AnalyzerVersion)
+
+	// Once the new version has analyzed a track the change is under way
+	require.NoError(t, uc.Save(ctx, "a", "v2", &pkgdomain.AudioAnalysis{}))
+	scheduled, err = uc.ScheduleReanalysis(ctx, "v2")
+	require.NoError(t, err)
+	assert.False(t, scheduled)
+	assert.Len(t, queue.jobs, 1)
+}
+
+func TestAnalysisUseCase_Reanalyze(t *testing.T) {
+	ctx := context.Background()
+	analyses := newMemAnalysisRepository()
+	queue := &memJobQueue{}
+	uc := NewAnalysisUseCase(analyses, new(MockTrackRepository))
+	uc.SetJobQueue(queue)
+
+	// More tracks than a batch, so they are read in pages
+	for i := 0; i < reanalysisBatchSize+20; i++ {
+		require.NoError(t, uc.Save(ctx, fmt.Sprintf("track-%04d", i), "v1", &pkgdomain.AudioAnalysis{}))
+	}
+	require.NoError(t, uc.Save(ctx, "track-0003", "v2", &pkgdomain.AudioAnalysis{}))
+
+	queued, err := uc.Reanalyze(ctx, "v2")
+	require.NoError(t, err)
+	assert.Equal(t, reanalysisBatchSize+19, queued)
+	require.Len(t, queue.jobs, queued)
+
+	seen := make(map[string]bool)
+	for _, job := range queue.jobs {
+		assert.Equal(t, pkgdomain.JobTypeAudioProcess, job.Type)
+		assert.Equal(t, pkgdomain.JobPriorityLow, job.Priority)
+		var payload pkgdomain.AudioProcessPayload
+		require.NoError(t, json.Unmarshal(job.Payload, &payload))
+		assert.Equal(t, "audio/"+payload.TrackID+".wav", payload.StoragePath)
+		assert.False(t, seen[payload.TrackID], "track %s queued twice", payload.TrackID)
+		seen[payload.TrackID] = true
+	}
+	assert.False(t, seen["track-0003"])
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// TrackLockUseCase keeps two workers from enriching the same track at the
+// same time. A worker locks the tracks for as long as it reads, enriches
+// and saves them.
+type TrackLockUseCase struct {
+	locker domain.TrackLocker
+	// ttl bounds how long a lock outlives a worker that stopped without
+	// releasing it
+	ttl time.Duration
+}
+
+// NewTrackLockUseCase creates a track lock use case whose locks expire
+// after ttl
+func NewTrackLockUseCase(locker domain.TrackLocker, ttl time.Duration) *TrackLockUseCase {
+	return &TrackLockUseCase{locker: locker, ttl: ttl}
+}
+
+// Do runs fn while owner holds the locks of the tracks. When another worker
+// holds one of them, fn is not run and the error wraps
+// domain.ErrTrackLocked. A nil use case runs fn without locking.
+func (uc *TrackLockUseCase) Do(ctx context.Context, owner string, trackIDs []string, fn func(ctx context.Context) error) error {
+	if uc == nil {
+		return fn(ctx)
+	}
+
+	lock, err := uc.locker.Lock(ctx, trackIDs, owner, uc.ttl)
+	if err != nil {
+		return fmt.Errorf("failed to lock tracks for %s: %w", owner, err)
+	}
+	defer func() {
+		if err := uc.locker.Unlock(context.WithoutCancel(ctx), trackIDs, lock); err != nil {
+			log.Printf("Failed to unlock tracks locked for %s: %v", owner, err)
+		}
+	}()
+	return fn(ctx)
+}
+
+// Annotate sets the lock of each track that a worker is processing. A nil
+// use case leaves the tracks as they are.
+func (uc *TrackLockUseCase) Annotate(ctx context.Context, tracks ...*domain.Track) error {
+	if uc == nil || len(tracks) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(tracks))
+	for i, track := range tracks {
+		ids[i] = track.ID
+	}
+	locks, err := uc.locker.Locks(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, track := range tracks {
+		track.Lock = locks[track.ID]
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memTrackLocker keeps track locks in memory
+type memTrackLocker map[string]*pkgdomain.TrackLock
+
+func (l memTrackLocker) Lock(_ context.Context, trackIDs []string, owner string, ttl time.Duration) (*pkgdomain.TrackLock, error) {
+	for _, id := range trackIDs {
+		if _, ok := l[id]; ok {
+			return nil, pkgdomain.ErrTrackLocked
+		}
+	}
+	lock := &pkgdomain.TrackLock{Owner: owner, AcquiredAt: time.Now(), ExpiresAt: time.Now().Add(ttl), Token: owner}
+	for _, id := range trackIDs {
+		l[id] = lock
+	}
+	return lock, nil
+}
+
+func (l memTrackLocker) Unlock(_ context.Context, trackIDs []string, lock *pkgdomain.TrackLock) error {
+	for _, id := range trackIDs {
+		if held, ok := l[id]; ok && held.Token == lock.Token {
+			delete(l, id)
+		}
+	}
+	return nil
+}
+
+func (l memTrackLocker) Locks(_ context.Context, trackIDs []string) (map[string]*pkgdomain.TrackLock, error) {
+	locks := make(map[string]*pkgdomain.TrackLock)
+	for _, id := range trackIDs {
+		if lock, ok := l[id]; ok {
+			locks[id] = lock
+		}
+	}
+	return locks, nil
+}
+
+func TestTrackLockUseCase_Do(t *testing.T) {
+	locker := memTrackLocker{}
+	uc := NewTrackLockUseCase(locker, time.Minute)
+	ctx := context.Background()
+
+	err := uc.Do(ctx, "batch-a", []string{"t1", "t2"}, func(ctx context.Context) error {
+		assert.Equal(t, "batch-a", locker["t1"].Owner, "the tracks are locked while fn runs")
+
+		ran := false
+		err := uc.Do(ctx, "batch-b", []string{"t2", "t3"}, func(context.Context) error {
+			ran = true
+			return nil
+		})
+		assert.ErrorIs(t, err, pkgdomain.ErrTrackLocked)
+		assert.False(t, ran)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Empty(t, locker, "the locks are released when fn returns")
+
+	var none *TrackLockUseCase
+	ran := false
+	require.NoError(t, none.Do(ctx, "batch-a", []string{"t1"}, func(context.Context) error {
+		ran = true
+		return nil
+	}))
+	assert.True(t, ran, "a nil use case runs fn unlocked")
+}
+
+func TestTrackLockUseCase_Annotate(t *testing.T) {
+	locker := memTrackLocker{}
+	uc := NewTrackLockUseCase(locker, time.Minute)
+	ctx := context.Background()
+	lock, err := locker.Lock(ctx, []string{"busy"}, "reenrichment", time.Minute)
+	require.NoError(t, err)
+
+	busy, idle := &pkgdomain.Track{ID: "busy"}, &pkgdomain.Track{ID: "idle"}
+	require.NoError(t, uc.Annotate(ctx, busy, idle))
+	assert.Same(t, lock, busy.Lock)
+	assert.Nil(t, idle.Lock)
+}
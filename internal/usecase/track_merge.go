@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// TrackMergeResult reports a merge of two tracks
+type TrackMergeResult struct {
+	// Track is the merged target
+	Track *domain.Track
+	// SourceID is the ID of the merged track, which now redirects to the
+	// target
+	SourceID string
+	// References counts the references moved to the target, by store
+	References map[string]int64
+	// MovedAudio is set when the source's audio file moved to the target
+	MovedAudio bool
+}
+
+// namedReferenceRewriter is a store of track references, named for merge
+// results
+type namedReferenceRewriter struct {
+	name     string
+	rewriter domain.TrackReferenceRewriter
+}
+
+// AddReferenceRewriter registers a store whose references to merged
+// tracks are moved to the track they are merged into, such as
+// "deliveries"
+func (uc *DuplicateUseCase) AddReferenceRewriter(name string, rewriter domain.TrackReferenceRewriter) {
+	uc.references = append(uc.references, namedReferenceRewriter{name: name, rewriter: rewriter})
+}
+
+// SetRedirects stores the tombstones of merged tracks in redirects. Without
+// it merged tracks are deleted without leaving a redirect.
+func (uc *DuplicateUseCase) SetRedirects(redirects domain.TrackRedirectRepository) {
+	uc.redirects = redirects
+	uc.AddReferenceRewriter("redirects", redirects)
+}
+
+// SetUnitOfWork makes each merge one transaction, so a merge that fails
+// leaves both tracks, their references and the redirects as they were.
+// Without it a failed merge can be left half done.
+func (uc *DuplicateUseCase) SetUnitOfWork(uow domain.UnitOfWork) {
+	uc.unitOfWork = uow
+}
+
+// Resolve returns the ID of the track a merged track was merged into, or ""
+// if id was not merged
+func (uc *DuplicateUseCase) Resolve(ctx context.Context, id string) (string, error) {
+	if uc.redirects == nil {
+		return "", nil
+	}
+	redirect, err := uc.redirects.Get(ctx, id)
+	if err != nil || redirect == nil {
+		return "", err
+	}
+	return redirect.TargetID, nil
+}
+
+// Merge merges the track sourceID into targetID on behalf of actor. The
+// fields of the target are picked following rules, the references to the
+// source move to the target, and the source is deleted, leaving a redirect
+// to the target.
+func (uc *DuplicateUseCase) Merge(ctx context.Context, sourceID, targetID string, rules domain.MergeRules, actor string) (*TrackMergeResult, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("%w: a track cannot be merged into itself", domain.ErrInvalidInput)
+	}
+	if errs := rules.Validate(); len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %s: %s", domain.ErrInvalidInput, errs[0].Field, errs[0].Message)
+	}
+	result := &TrackMergeResult{SourceID: sourceID, References: make(map[string]int64, len(uc.references))}
+	err := inUnitOfWork(ctx, uc.unitOfWork, func(ctx context.Context) error {
+		return uc.merge(ctx, sourceID, targetID, rules, actor, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// merge does the writes of Merge, filling in result
+func (uc *DuplicateUseCase) merge(ctx context.Context, sourceID, targetID string, rules domain.MergeRules, actor string, result *TrackMergeResult) error {
+	source, err := uc.tracks.GetByID(domain.WithPrimaryReads(ctx), sourceID)
+	if err != nil {
+		return err
+	}
+	if source == nil {
+		return fmt.Errorf("%w: track %s", domain.ErrTrackNotFound, sourceID)
+	}
+
+	target, err := domain.PatchTrack(ctx, uc.tracks, targetID, func(t *domain.Track) error {
+		changes, movedAudio := domain.MergeTracks(t, source, rules)
+		t.RecordProvenance(changes, domain.ProvenanceManual, actor)
+		result.MovedAudio = movedAudio
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return fmt.Errorf("%w: track %s", domain.ErrTrackNotFound, targetID)
+	}
+	result.Track = target
+
+	for _, ref := range uc.references {
+		moved, err := ref.rewriter.RewriteTrackReferences(ctx, sourceID, targetID)
+		if err != nil {
+			return fmt.Errorf("failed to move %s of track %s: %w", ref.name, sourceID, err)
+		}
+		result.References[ref.name] = moved
+	}
+	if uc.redirects != nil {
+		redirect := &domain.TrackRedirect{SourceID: sourceID, TargetID: targetID, MergedBy: actor, MergedAt: time.Now()}
+		if err := uc.redirects.Save(ctx, redirect); err != nil {
+			return err
+		}
+	}
+	return uc.tracks.Delete(ctx, sourceID)
+}
+
+// inUnitOfWork runs fn in uow, or straight away when uow is nil
+func inUnitOfWork(ctx context.Context, uow domain.UnitOfWork, fn func(ctx context.Context) error) error {
+	if uow == nil {
+		return fn(ctx)
+	}
+	return uow.Do(ctx, fn)
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryTrackRedirectRepository keeps track redirects in memory
+type memoryTrackRedirectRepository struct {
+	redirects map[string]*pkgdomain.TrackRedirect
+}
+
+func (r *memoryTrackRedirectRepository) Save(_ context.Context, redirect *pkgdomain.TrackRedirect) error {
+	r.redirects[redirect.SourceID] = redirect
+	return nil
+}
+
+func (r *memoryTrackRedirectRepository) Get(_ context.Context, sourceID string) (*pkgdomain.TrackRedirect, error) {
+	return r.redirects[sourceID], nil
+}
+
+func (r *memoryTrackRedirectRepository) RewriteTrackReferences(_ context.Context, fromID, toID string) (int64, error) {
+	var n int64
+	for _, redirect := range r.redirects {
+		if redirect.TargetID == fromID {
+			redirect.TargetID = toID
+			n++
+		}
+	}
+	return n, nil
+}
+
+// countingRewriter records the references it was asked to move
+type countingRewriter struct {
+	moved int64
+	calls [][2]string
+}
+
+func (r *countingRewriter) RewriteTrackReferences(_ context.Context, fromID, toID string) (int64, error) {
+	r.calls = append(r.calls, [2]string{fromID, toID})
+	return r.moved, nil
+}
+
+func newMergeTracks() (source, target *pkgdomain.Track) {
+	source = newDuplicateTrack("source", "Song (Live)", "Artist", "USABC2400001")
+	source.SetGenre("Rock")
+	source.SetMood("Energetic")
+	source.StoragePath = "audio/source.flac"
+	source.Metadata.Technical.SampleRate = 96000
+	source.Metadata.Additional.Tags = []string{"live", "guitar"}
+	source.Metadata.Additional.CustomFields = map[string]string{"mix": "live", "label": "Old Label"}
+	target = newDuplicateTrack("target", "Song", "Artist", "")
+	target.SetGenre("Pop")
+	target.Metadata.Additional.Tags = []string{"guitar"}
+	target.Metadata.Additional.CustomFields = map[string]string{"label": "New Label"}
+	return source, target
+}
+
+func newMergeUseCase(source, target *pkgdomain.Track) (*DuplicateUseCase, *MockTrackRepository, *memoryTrackRedirectRepository) {
+	tracks := new(MockTrackRepository)
+	tracks.On("GetByID", mock.Anything, "source").Return(source, nil)
+	tracks.On("GetByID", mock.Anything, "target").Return(target, nil)
+	tracks.On("GetByID", mock.Anything, mock.Anything).Return(nil, nil)
+	tracks.On("Update", mock.Anything, mock.Anything).Return(nil)
+	tracks.On("Delete", mock.Anything, "source").Return(nil)
+
+	redirects := &memoryTrackRedirectRepository{redirects: map[string]*pkgdomain.TrackRedirect{
+		// An earlier merge into the source
+		"older": {SourceID: "older", TargetID: "source"},
+	}}
+	uc := NewDuplicateUseCase(tracks)
+	uc.SetRedirects(redirects)
+	return uc, tracks, redirects
+}
+
+func TestDuplicateUseCase_MergeFillsBlanks(t *testing.T) {
+	source, target := newMergeTracks()
+	uc, tracks, redirects := newMergeUseCase(source, target)
+	deliveries := &countingRewriter{moved: 3}
+	uc.AddReferenceRewriter("deliveries", deliveries)
+
+	result, err := uc.Merge(context.Background(), "source", "target", nil, "user-1")
+	require.NoError(t, err)
+
+	// The target keeps its values and gains those it lacked
+	merged := result.Track
+	assert.Equal(t, "Song", merged.Title())
+	assert.Equal(t, "Pop", merged.Genre())
+	assert.Equal(t, "Energetic", merged.Mood())
+	assert.Equal(t, "USABC2400001", merged.ISRC())
+	assert.Equal(t, []string{"guitar", "live"}, merged.Tags())
+	assert.Equal(t, "New Label", merged.Label())
+	assert.Equal(t, "live", merged.Metadata.Additional.CustomFields["mix"])
+	provenance, ok := merged.FieldProvenance("isrc")
+	require.True(t, ok)
+	assert.Equal(t, "user-1", provenance.UpdatedBy)
+
+	// The audio file moves to the target, which had none
+	assert.True(t, result.MovedAudio)
+	assert.Equal(t, "audio/source.flac", merged.StoragePath)
+	assert.Equal(t, 96000, merged.SampleRate())
+
+	// References move, and the source and tracks merged into it redirect
+	// to the target
+	assert.Equal(t, [][2]string{{"source", "target"}}, deliveries.calls)
+	assert.Equal(t, map[string]int64{"redirects": 1, "deliveries": 3}, result.References)
+	for _, id := range []string{"source", "older"} {
+		targetID, err := uc.Resolve(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, "target", targetID, id)
+	}
+	assert.Equal(t, "user-1", redirects.redirects["source"].MergedBy)
+	tracks.AssertCalled(t, "Delete", mock.Anything, "source")
+}
+
+func TestDuplicateUseCase_MergeFollowsRules(t *testing.T) {
+	source, target := newMergeTracks()
+	target.StoragePath = "audio/target.mp3"
+	uc, _, _ := newMergeUseCase(source, target)
+
+	result, err := uc.Merge(context.Background(), "source", "target", pkgdomain.MergeRules{
+		"title": pkgdomain.MergePickSource,
+		"isrc":  pkgdomain.MergePickTarget,
+		"label": pkgdomain.MergePickSource,
+		"tags":  pkgdomain.MergePickTarget,
+	}, "user-1")
+	require.NoError(t, err)
+
+	merged := result.Track
+	assert.Equal(t, "Song (Live)", merged.Title())
+	assert.Equal(t, "", merged.ISRC())
+	assert.Equal(t, "Old Label", merged.Label())
+	assert.Equal(t, []string{"guitar"}, merged.Tags())
+	assert.Equal(t, "Pop", merged.Genre())
+	assert.False(t, result.MovedAudio)
+	assert.Equal(t, "audio/target.mp3", merged.StoragePath)
+}
+
+func TestDuplicateUseCase_MergeRejects(t *testing.T) {
+	source, target := newMergeTracks()
+	uc, tracks, _ := newMergeUseCase(source, target)
+
+	_, err := uc.Merge(context.Background(), "target", "target", nil, "user-1")
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput)
+
+	_, err = uc.Merge(context.Background(), "source", "target", pkgdomain.MergeRules{"genre": pkgdomain.MergePickCombine}, "user-1")
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput)
+
+	_, err = uc.Merge(context.Background(), "source", "target", pkgdomain.MergeRules{"status": pkgdomain.MergePickSource}, "user-1")
+	assert.ErrorIs(t, err, pkgdomain.ErrInvalidInput)
+
+	_, err = uc.Merge(context.Background(), "source", "missing", nil, "user-1")
+	assert.ErrorIs(t, err, pkgdomain.ErrTrackNotFound)
+	tracks.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+type unitOfWorkKey struct{}
+
+// snapshotUnitOfWork stands in for a database transaction, restoring the
+// redirects when the work fails
+type snapshotUnitOfWork struct {
+	redirects *memoryTrackRedirectRepository
+	units     int
+}
+
+func (u *snapshotUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	u.units++
+	saved := make(map[string]*pkgdomain.TrackRedirect, len(u.redirects.redirects))
+	for id, redirect := range u.redirects.redirects {
+		copied := *redirect
+		saved[id] = &copied
+	}
+	if err := fn(context.WithValue(ctx, unitOfWorkKey{}, true)); err != nil {
+		u.redirects.redirects = saved
+		return err
+	}
+	return nil
+}
+
+func TestDuplicateUseCase_MergeRollsBackInUnitOfWork(t *testing.T) {
+	source, target := newMergeTracks()
+	inUnit := mock.MatchedBy(func(ctx context.Context) bool { return ctx.Value(unitOfWorkKey{}) != nil })
+	tracks := new(MockTrackRepository)
+	tracks.On("GetByID", inUnit, "source").Return(source, nil)
+	tracks.On("GetByID", inUnit, "target").Return(target, nil)
+	tracks.On("Update", inUnit, mock.Anything).Return(nil)
+	tracks.On("Delete", inUnit, "source").Return(errors.New("connection reset"))
+
+	redirects := &memoryTrackRedirectRepository{redirects: map[string]*pkgdomain.TrackRedirect{
+		"older": {SourceID: "older", TargetID: "source"},
+	}}
+	uow := &snapshotUnitOfWork{redirects: redirects}
+	uc := NewDuplicateUseCase(tracks)
+	uc.SetRedirects(redirects)
+	uc.SetUnitOfWork(uow)
+
+	_, err := uc.Merge(context.Background(), "source", "target", nil, "user-1")
+	require.Error(t, err)
+
+	// Every write ran in the one unit of work, which undid them
+	assert.Equal(t, 1, uow.units)
+	assert.NotContains(t, redirects.redirects, "source")
+	assert.Equal(t, "source", redirects.redirects["older"].TargetID)
+	tracks.AssertExpectations(t)
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/metrics"
+)
+
+// TrackWorkflowUseCase moves tracks through the status workflow. Only the
+// transitions allowed by domain.CheckStatusTransition are made, and every
+// stored change is passed to the registered hooks.
+type TrackWorkflowUseCase struct {
+	tracks domain.TrackRepository
+
+	mu    sync.RWMutex
+	hooks []domain.TrackTransitionHook
+}
+
+// NewTrackWorkflowUseCase creates a workflow use case
+func NewTrackWorkflowUseCase(tracks domain.TrackRepository) *TrackWorkflowUseCase {
+	return &TrackWorkflowUseCase{tracks: tracks}
+}
+
+// OnTransition registers hook to be called after every status change
+func (uc *TrackWorkflowUseCase) OnTransition(hook domain.TrackTransitionHook) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.hooks = append(uc.hooks, hook)
+}
+
+// NextStatuses returns a track and the statuses it may move to
+func (uc *TrackWorkflowUseCase) NextStatuses(ctx context.Context, trackID string) (*domain.Track, []domain.TrackStatus, error) {
+	track, err := uc.tracks.GetByID(ctx, trackID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if track == nil {
+		return nil, nil, domain.ErrTrackNotFound
+	}
+	return track, track.Status.NextStatuses(), nil
+}
+
+// Transition moves a track to status to on behalf of userID, giving message
+// as the reason. With an expectedVersion other than zero, the track must
+// still be at that version.
+func (uc *TrackWorkflowUseCase) Transition(ctx context.Context, trackID string, to domain.TrackStatus, message, userID string, expectedVersion int) (*domain.Track, error) {
+	var from domain.TrackStatus
+	track, err := domain.PatchTrack(ctx, uc.tracks, trackID, func(t *domain.Track) error {
+		if expectedVersion > 0 && t.Version != expectedVersion {
+			submitted := t.Clone()
+			submitted.Version = expectedVersion
+			submitted.Status = to
+			return domain.NewVersionConflictError(t, submitted)
+		}
+		if t.Status == to {
+			return fmt.Errorf("%w: track is already %s", domain.ErrInvalidStatusTransition, to)
+		}
+		from = t.Status
+		return t.SetStatus(to, message)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if track == nil {
+		return nil, domain.ErrTrackNotFound
+	}
+
+	uc.Notify(ctx, domain.TrackStatusChange{
+		TrackID:    track.ID,
+		From:       from,
+		To:         to,
+		Message:    message,
+		UserID:     userID,
+		Version:    track.Version,
+		OccurredAt: track.UpdatedAt,
+	})
+	return track, nil
+}
+
+// Notify passes a stored status change to the hooks. Writers that change the
+// status outside Transition, such as full track updates, call it themselves.
+func (uc *TrackWorkflowUseCase) Notify(ctx context.Context, change domain.TrackStatusChange) {
+	if change.From == change.To {
+		return
+	}
+	if change.OccurredAt.IsZero() {
+		change.OccurredAt = time.Now()
+	}
+	metrics.TrackStatusTransitions.WithLabelValues(string(change.From), string(change.To)).Inc()
+
+	uc.mu.RLock()
+	hooks := uc.hooks
+	uc.mu.RUnlock()
+	for _, hook := range hooks {
+		hook(ctx, change)
+	}
+}
+
+// PublishStatusChanges returns a hook publishing status changes to topic as
+// track.status_changed events, ordered per track with the change feed
+func PublishStatusChanges(feed domain.ChangeFeedPublisher, topic string) domain.TrackTransitionHook {
+	return func(ctx context.Context, change domain.TrackStatusChange) {
+		msg := &domain.Message{
+			ID:   fmt.Sprintf("%s:%d:status", change.TrackID, change.Version),
+			Type: string(domain.TrackChangeStatusChanged),
+			Data: map[string]interface{}{
+				"type":        domain.TrackChangeStatusChanged,
+				"track_id":    change.TrackID,
+				"version":     change.Version,
+				"from":        change.From,
+				"to":          change.To,
+				"message":     change.Message,
+				"user_id":     change.UserID,
+				"occurred_at": change.OccurredAt,
+			},
+			Status:    domain.MessageStatusPending,
+			CreatedAt: change.OccurredAt,
+			UpdatedAt: time.Now(),
+		}
+		if err := feed.PublishOrdered(context.WithoutCancel(ctx), topic, change.TrackID, msg); err != nil {
+			log.Printf("failed to publish status change of track %s: %v", change.TrackID, err)
+		}
+	}
+}
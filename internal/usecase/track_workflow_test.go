@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackWorkflowUseCase_Transition(t *testing.T) {
+	newWorkflow := func(status pkgdomain.TrackStatus) (*TrackWorkflowUseCase, *MockTrackRepository, *[]pkgdomain.TrackStatusChange) {
+		trackRepo := new(MockTrackRepository)
+		trackRepo.On("GetByID", mock.Anything, "t1").Return(&pkgdomain.Track{ID: "t1", Version: 2, Status: status}, nil)
+		trackRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		uc := NewTrackWorkflowUseCase(trackRepo)
+		var changes []pkgdomain.TrackStatusChange
+		uc.OnTransition(func(_ context.Context, change pkgdomain.TrackStatusChange) {
+			changes = append(changes, change)
+		})
+		return uc, trackRepo, &changes
+	}
+
+	t.Run("allowed transition", func(t *testing.T) {
+		uc, trackRepo, changes := newWorkflow(pkgdomain.TrackStatusNeedsReview)
+
+		track, err := uc.Transition(context.Background(), "t1", pkgdomain.TrackStatusApproved, "looks good", "user-1", 2)
+		require.NoError(t, err)
+		assert.Equal(t, pkgdomain.TrackStatusApproved, track.Status)
+		assert.Equal(t, "looks good", track.StatusMsg)
+		trackRepo.AssertCalled(t, "Update", mock.Anything, mock.Anything)
+		require.Len(t, *changes, 1)
+		assert.Equal(t, pkgdomain.TrackStatusNeedsReview, (*changes)[0].From)
+		assert.Equal(t, pkgdomain.TrackStatusApproved, (*changes)[0].To)
+		assert.Equal(t, "user-1", (*changes)[0].UserID)
+	})
+
+	t.Run("refused transition", func(t *testing.T) {
+		uc, trackRepo, changes := newWorkflow(pkgdomain.TrackStatusPending)
+
+		_, err := uc.Transition(context.Background(), "t1", pkgdomain.TrackStatusDelivered, "", "user-1", 0)
+		assert.True(t, errors.Is(err, pkgdomain.ErrInvalidStatusTransition))
+		trackRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		assert.Empty(t, *changes)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		uc, _, changes := newWorkflow(pkgdomain.TrackStatusApproved)
+
+		_, err := uc.Transition(context.Background(), "t1", pkgdomain.TrackStatusDelivered, "", "user-1", 1)
+		assert.True(t, errors.Is(err, pkgdomain.ErrVersionConflict))
+		assert.Empty(t, *changes)
+	})
+}
+
+func TestTrackWorkflowUseCase_NextStatuses(t *testing.T) {
+	trackRepo := new(MockTrackRepository)
+	trackRepo.On("GetByID", mock.Anything, "t1").Return(&pkgdomain.Track{ID: "t1", Status: pkgdomain.TrackStatusApproved}, nil)
+	trackRepo.On("GetByID", mock.Anything, "missing").Return(nil, nil)
+	uc := NewTrackWorkflowUseCase(trackRepo)
+
+	_, next, err := uc.NextStatuses(context.Background(), "t1")
+	require.NoError(t, err)
+	assert.Equal(t, []pkgdomain.TrackStatus{pkgdomain.TrackStatusDelivered, pkgdomain.TrackStatusNeedsReview, pkgdomain.TrackStatusArchived}, next)
+
+	_, _, err = uc.NextStatuses(context.Background(), "missing")
+	assert.True(t, errors.Is(err, pkgdomain.ErrTrackNotFound))
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+	"metadatatool/internal/pkg/errortracking"
+	"metadatatool/internal/pkg/metrics"
+)
+
+// UploadScanner scans the files of uploaded tracks for malware. Uploads are
+// stored under domain.QuarantinePrefix; a clean file is moved to its final
+// key and the track is queued for enrichment, an infected one is deleted,
+// its track rejected and a security event raised.
+type UploadScanner struct {
+	tracks       domain.TrackRepository
+	storage      domain.StorageService
+	scanner      domain.FileScanner
+	ai           domain.AIService
+	errorTracker *errortracking.ErrorTracker
+}
+
+// NewUploadScanner creates a new upload scanner. ai and errorTracker may be
+// nil.
+func NewUploadScanner(tracks domain.TrackRepository, storage domain.StorageService, scanner domain.FileScanner,
+	ai domain.AIService, errorTracker *errortracking.ErrorTracker) *UploadScanner {
+	return &UploadScanner{
+		tracks:       tracks,
+		storage:      storage,
+		scanner:      scanner,
+		ai:           ai,
+		errorTracker: errorTracker,
+	}
+}
+
+// Submit scans the file of a track in the background
+func (s *UploadScanner) Submit(trackID string) {
+	metrics.JobsInQueue.WithLabelValues(string(domain.JobTypeFileScan), "normal").Inc()
+	go func() {
+		start := time.Now()
+		status := domain.JobStatusCompleted
+		if err := s.ScanTrack(context.Background(), trackID); err != nil {
+			status = domain.JobStatusFailed
+			log.Printf("Error scanning upload of track %s: %v", trackID, err)
+			s.capture(err, map[string]string{"operation": "file_scan", "track_id": trackID})
+		}
+		metrics.JobsInQueue.WithLabelValues(string(domain.JobTypeFileScan), "normal").Dec()
+		metrics.JobProcessingDuration.WithLabelValues(string(domain.JobTypeFileScan)).Observe(time.Since(start).Seconds())
+		metrics.JobsProcessed.WithLabelValues(string(domain.JobTypeFileScan), string(status)).Inc()
+	}()
+}
+
+// ScanTrack scans the quarantined file of a track and promotes or rejects
+// it. Tracks whose file is not in quarantine are left alone. An error means
+// the scan could not be completed and the file stays in quarantine.
+func (s *UploadScanner) ScanTrack(ctx context.Context, trackID string) error {
+	track, err := s.tracks.GetByID(ctx, trackID)
+	if err != nil {
+		return fmt.Errorf("failed to get track: %w", err)
+	}
+	if track == nil || !domain.IsQuarantined(track.StoragePath) {
+		return nil
+	}
+	quarantined := track.StoragePath
+
+	result, err := s.scan(ctx, quarantined)
+	if err != nil {
+		return err
+	}
+	if !result.Clean {
+		return s.reject(ctx, track, result.Threat)
+	}
+
+	released := domain.ReleasedKey(quarantined)
+	keyID, err := s.move(ctx, track, quarantined, released)
+	if err != nil {
+		return err
+	}
+	promoted, err := domain.PatchTrack(ctx, s.tracks, track.ID, func(t *domain.Track) error {
+		t.StoragePath = released
+		if keyID != "" {
+			t.Metadata.Technical.EncryptionKeyID = keyID
+		}
+		t.StatusMsg = ""
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release track: %w", err)
+	}
+
+	if s.ai != nil {
+		if err := s.ai.EnrichMetadata(ctx, promoted); err != nil {
+			s.capture(err, map[string]string{"operation": "ai_enrich", "track_id": track.ID})
+		}
+	}
+	return nil
+}
+
+func (s *UploadScanner) scan(ctx context.Context, key string) (*domain.ScanResult, error) {
+	file, err := s.storage.Download(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download quarantined file: %w", err)
+	}
+	defer closeContent(file)
+
+	result, err := s.scanner.Scan(ctx, file.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan file: %w", err)
+	}
+	return result, nil
+}
+
+// move copies a track's file to a new key and deletes the original. The
+// copy is verified against the checksums recorded at upload. It returns
+// the KMS key the copy is encrypted with, if any.
+func (s *UploadScanner) move(ctx context.Context, track *domain.Track, from, to string) (string, error) {
+	file, err := s.storage.Download(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("failed to download quarantined file: %w", err)
+	}
+	defer closeContent(file)
+
+	content := domain.NewChecksumReader(file.Content)
+	released := *file
+	released.Key, released.Content = to, content
+	if err := s.storage.Upload(ctx, &released); err != nil {
+		return "", fmt.Errorf("failed to release file: %w", err)
+	}
+	technical := track.Metadata.Technical
+	if err := content.Sum().Verify(technical.ContentHash, technical.ContentMD5); err != nil {
+		if delErr := s.storage.Delete(ctx, to); delErr != nil {
+			log.Printf("Error deleting corrupted copy %s: %v", to, delErr)
+		}
+		return "", fmt.Errorf("quarantined file of track %s is corrupted: %w", track.ID, err)
+	}
+	if err := s.storage.Delete(ctx, from); err != nil {
+		log.Printf("Error deleting released file %s: %v", from, err)
+	}
+	return released.EncryptionKeyID, nil
+}
+
+// reject deletes an infected file, rejects its track and raises a security
+// event
+func (s *UploadScanner) reject(ctx context.Context, track *domain.Track, threat string) error {
+	log.Printf("Malware %q detected in upload of track %s", threat, track.ID)
+	s.capture(fmt.Errorf("%w in upload of track %s: %s", domain.ErrMalwareDetected, track.ID, threat), map[string]string{
+		"security_event": "malware_detected",
+		"track_id":       track.ID,
+		"storage_path":   track.StoragePath,
+		"threat":         threat,
+	})
+
+	if err := s.storage.Delete(ctx, track.StoragePath); err != nil {
+		return fmt.Errorf("failed to delete infected file: %w", err)
+	}
+	_, err := domain.PatchTrack(ctx, s.tracks, track.ID, func(t *domain.Track) error {
+		t.StoragePath = ""
+		return t.SetStatus(domain.TrackStatusRejected, "malware detected: "+threat)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reject track: %w", err)
+	}
+	return nil
+}
+
+func (s *UploadScanner) capture(err error, tags map[string]string) {
+	if s.errorTracker != nil {
+		s.errorTracker.CaptureError(err, tags)
+	}
+}
+
+// closeContent closes the content of a downloaded file if it can be closed
+func closeContent(file *domain.StorageFile) {
+	if closer, ok := file.Content.(io.Closer); ok {
+		closer.Close()
+	}
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/repository/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// signatureScanner reports files containing signature as infected
+type signatureScanner struct {
+	signature string
+}
+
+func (s *signatureScanner) Scan(_ context.Context, content io.Reader) (*pkgdomain.ScanResult, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(string(data), s.signature) {
+		return &pkgdomain.ScanResult{Threat: "Test-Signature"}, nil
+	}
+	return &pkgdomain.ScanResult{Clean: true}, nil
+}
+
+func newScannedUpload(t *testing.T, content string) (*UploadScanner, *MockTrackRepository, pkgdomain.StorageService, *pkgdomain.Track) {
+	store, err := storage.NewLocalStorage(t.TempDir(), "/files", nil)
+	require.NoError(t, err)
+
+	track := &pkgdomain.Track{
+		ID:          "t1",
+		Version:     1,
+		Status:      pkgdomain.TrackStatusPending,
+		StatusMsg:   "awaiting malware scan",
+		StoragePath: pkgdomain.QuarantineKey("tracks/t1/audio.mp3"),
+	}
+	require.NoError(t, store.Upload(context.Background(), &pkgdomain.StorageFile{
+		Key:     track.StoragePath,
+		Content: strings.NewReader(content),
+	}))
+
+	repo := new(MockTrackRepository)
+	repo.On("GetByID", mock.Anything, "t1").Return(track, nil)
+	repo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Track")).Return(nil)
+	return NewUploadScanner(repo, store, &signatureScanner{signature: "EICAR"}, nil, nil), repo, store, track
+}
+
+func TestUploadScanner_ReleasesCleanFiles(t *testing.T) {
+	scanner, repo, store, track := newScannedUpload(t, "clean audio")
+
+	require.NoError(t, scanner.ScanTrack(context.Background(), "t1"))
+
+	assert.Equal(t, "tracks/t1/audio.mp3", track.StoragePath)
+	assert.Equal(t, pkgdomain.TrackStatusPending, track.Status)
+	assert.Empty(t, track.StatusMsg)
+	_, err := store.GetMetadata(context.Background(), "tracks/t1/audio.mp3")
+	assert.NoError(t, err)
+	_, err = store.GetMetadata(context.Background(), pkgdomain.QuarantineKey("tracks/t1/audio.mp3"))
+	assert.Error(t, err)
+	repo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestUploadScanner_RejectsInfectedFiles(t *testing.T) {
+	scanner, repo, store, track := newScannedUpload(t, "X5O!P%@AP EICAR")
+
+	require.NoError(t, scanner.ScanTrack(context.Background(), "t1"))
+
+	assert.Equal(t, pkgdomain.TrackStatusRejected, track.Status)
+	assert.Equal(t, "malware detected: Test-Signature", track.StatusMsg)
+	assert.Empty(t, track.StoragePath)
+	_, err := store.GetMetadata(context.Background(), pkgdomain.QuarantineKey("tracks/t1/audio.mp3"))
+	assert.Error(t, err)
+	_, err = store.GetMetadata(context.Background(), "tracks/t1/audio.mp3")
+	assert.Error(t, err)
+	repo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestUploadScanner_KeepsCorruptedFilesInQuarantine(t *testing.T) {
+	scanner, repo, store, track := newScannedUpload(t, "clean audio")
+	track.Metadata.Technical.ContentHash = "0000"
+
+	err := scanner.ScanTrack(context.Background(), "t1")
+	assert.ErrorIs(t, err, pkgdomain.ErrChecksumMismatch)
+
+	assert.Equal(t, pkgdomain.QuarantineKey("tracks/t1/audio.mp3"), track.StoragePath)
+	_, err = store.GetMetadata(context.Background(), track.StoragePath)
+	assert.NoError(t, err)
+	_, err = store.GetMetadata(context.Background(), "tracks/t1/audio.mp3")
+	assert.Error(t, err)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUploadScanner_IgnoresReleasedFiles(t *testing.T) {
+	repo := new(MockTrackRepository)
+	repo.On("GetByID", mock.Anything, "t1").Return(&pkgdomain.Track{ID: "t1", StoragePath: "tracks/t1/audio.mp3"}, nil)
+
+	scanner := NewUploadScanner(repo, nil, &signatureScanner{}, nil, nil)
+	require.NoError(t, scanner.ScanTrack(context.Background(), "t1"))
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+)
+
+// maxUsageReportDays bounds the range of a usage report
+const maxUsageReportDays = 366
+
+// UsageUseCase meters usage per label in daily totals and reports it
+type UsageUseCase struct {
+	repo domain.UsageRepository
+	now  func() time.Time
+}
+
+// NewUsageUseCase creates a new usage use case
+func NewUsageUseCase(repo domain.UsageRepository) *UsageUseCase {
+	return &UsageUseCase{repo: repo, now: time.Now}
+}
+
+// RecordUsage adds n to a label's metric for today. Metering never fails
+// the operation being metered, so errors are only logged.
+func (uc *UsageUseCase) RecordUsage(ctx context.Context, labelID string, metric domain.UsageMetric, n int64) {
+	if n == 0 {
+		return
+	}
+	if err := uc.repo.Increment(ctx, labelID, uc.today(), metric, n); err != nil {
+		log.Printf("Failed to record %s usage for label %q: %v", metric, labelID, err)
+	}
+}
+
+// Snapshot records the tracks and bytes every label has stored today
+func (uc *UsageUseCase) Snapshot(ctx context.Context) error {
+	return uc.repo.SnapshotStored(ctx, uc.today())
+}
+
+// Run takes a snapshot now and every interval until ctx is done. The last
+// snapshot of a day is the stored amount reported for it.
+func (uc *UsageUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := uc.Snapshot(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to snapshot stored usage: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Report returns the daily usage of one label, or of every label when
+// labelID is empty, between from and to inclusive. from defaults to the
+// first day of the current month and to defaults to today.
+func (uc *UsageUseCase) Report(ctx context.Context, labelID, from, to string) (*domain.UsageReport, error) {
+	now := uc.now().UTC()
+	if to == "" {
+		to = now.Format(domain.UsageDayFormat)
+	}
+	if from == "" {
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Format(domain.UsageDayFormat)
+	}
+
+	fromDay, err := time.Parse(domain.UsageDayFormat, from)
+	if err != nil {
+		return nil, fmt.Errorf("%w: from must be a date like 2006-01-02", domain.ErrInvalidInput)
+	}
+	toDay, err := time.Parse(domain.UsageDayFormat, to)
+	if err != nil {
+		return nil, fmt.Errorf("%w: to must be a date like 2006-01-02", domain.ErrInvalidInput)
+	}
+	if toDay.Before(fromDay) {
+		return nil, fmt.Errorf("%w: to must not be before from", domain.ErrInvalidInput)
+	}
+	if toDay.Sub(fromDay) >= maxUsageReportDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: reports cover at most %d days", domain.ErrInvalidInput, maxUsageReportDays)
+	}
+
+	records, err := uc.repo.List(ctx, domain.UsageFilter{LabelID: labelID, From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	// Records are ordered by label and day, so each day's metrics are adjacent
+	report := &domain.UsageReport{From: from, To: to, Days: []domain.UsageDay{}}
+	for _, record := range records {
+		n := len(report.Days)
+		if n == 0 || report.Days[n-1].LabelID != record.LabelID || report.Days[n-1].Day != record.Day {
+			report.Days = append(report.Days, domain.UsageDay{LabelID: record.LabelID, Day: record.Day})
+			n++
+		}
+		day := &report.Days[n-1]
+		switch record.Metric {
+		case domain.UsageTracksStored:
+			day.TracksStored = record.Value
+		case domain.UsageStorageBytes:
+			day.StorageBytes = record.Value
+		case domain.UsageAIEnrichments:
+			day.AIEnrichments = record.Value
+		case domain.UsageExports:
+			day.Exports = record.Value
+		case domain.UsagePublicAPIRequests:
+			day.PublicAPIRequests = record.Value
+		}
+	}
+	return report, nil
+}
+
+func (uc *UsageUseCase) today() string {
+	return uc.now().UTC().Format(domain.UsageDayFormat)
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryUsageRepository struct {
+	records map[[3]string]int64
+}
+
+func (r *memoryUsageRepository) Increment(ctx context.Context, labelID, day string, metric domain.UsageMetric, n int64) error {
+	if r.records == nil {
+		r.records = make(map[[3]string]int64)
+	}
+	r.records[[3]string{labelID, day, string(metric)}] += n
+	return nil
+}
+
+func (r *memoryUsageRepository) SnapshotStored(ctx context.Context, day string) error {
+	return nil
+}
+
+func (r *memoryUsageRepository) List(ctx context.Context, filter domain.UsageFilter) ([]*domain.UsageRecord, error) {
+	var records []*domain.UsageRecord
+	for key, value := range r.records {
+		if key[1] < filter.From || key[1] > filter.To || (filter.LabelID != "" && key[0] != filter.LabelID) {
+			continue
+		}
+		records = append(records, &domain.UsageRecord{LabelID: key[0], Day: key[1], Metric: domain.UsageMetric(key[2]), Value: value})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].LabelID != records[j].LabelID {
+			return records[i].LabelID < records[j].LabelID
+		}
+		return records[i].Day < records[j].Day
+	})
+	return records, nil
+}
+
+func TestUsageUseCase_ReportPivotsDailyMetrics(t *testing.T) {
+	repo := &memoryUsageRepository{}
+	uc := NewUsageUseCase(repo)
+	ctx := context.Background()
+
+	uc.now = func() time.Time { return time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC) }
+	uc.RecordUsage(ctx, "label-a", domain.UsageAIEnrichments, 2)
+	uc.RecordUsage(ctx, "label-a", domain.UsageAIEnrichments, 1)
+	uc.RecordUsage(ctx, "label-a", domain.UsageExports, 5)
+	uc.RecordUsage(ctx, "label-b", domain.UsageExports, 1)
+	uc.RecordUsage(ctx, "label-b", domain.UsagePublicAPIRequests, 7)
+	uc.now = func() time.Time { return time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC) }
+	uc.RecordUsage(ctx, "label-a", domain.UsageAIEnrichments, 4)
+	require.NoError(t, repo.Increment(ctx, "label-a", "2024-03-02", domain.UsageStorageBytes, 1024))
+
+	report, err := uc.Report(ctx, "", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-01", report.From)
+	assert.Equal(t, "2024-03-02", report.To)
+	assert.Equal(t, []domain.UsageDay{
+		{LabelID: "label-a", Day: "2024-03-01", AIEnrichments: 3, Exports: 5},
+		{LabelID: "label-a", Day: "2024-03-02", AIEnrichments: 4, StorageBytes: 1024},
+		{LabelID: "label-b", Day: "2024-03-01", Exports: 1, PublicAPIRequests: 7},
+	}, report.Days)
+
+	report, err = uc.Report(ctx, "label-b", "2024-03-01", "2024-03-01")
+	require.NoError(t, err)
+	assert.Len(t, report.Days, 1)
+}
+
+func TestUsageUseCase_ReportRejectsInvalidRanges(t *testing.T) {
+	uc := NewUsageUseCase(&memoryUsageRepository{})
+
+	for _, r := range [][2]string{
+		{"2024-13-01", "2024-12-31"},
+		{"2024-03-02", "2024-03-01"},
+		{"2022-01-01", "2024-01-01"},
+	} {
+		_, err := uc.Report(context.Background(), "", r[0], r[1])
+		assert.True(t, errors.Is(err, domain.ErrInvalidInput), "range %v", r)
+	}
+}
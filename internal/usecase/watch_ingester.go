@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"metadatatool/internal/pkg/domain"
+
+	"github.com/dhowden/tag"
+	"github.com/google/uuid"
+)
+
+const (
+	// watchProcessedDir and watchFailedDir receive files once they have been
+	// handled so a restart never ingests them twice
+	watchProcessedDir = ".processed"
+	watchFailedDir    = ".failed"
+)
+
+// WatchIngesterConfig holds settings for the watch-folder ingester
+type WatchIngesterConfig struct {
+	// Dir is the drop directory that is watched for new audio files
+	Dir string
+	// PollInterval is the time between directory scans
+	PollInterval time.Duration
+	// EnqueueEnrichment queues an AI enrichment job for every created track
+	EnqueueEnrichment bool
+}
+
+// fileState is the size and modification time of a file at the last scan
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// WatchIngester turns audio files dropped into a directory into tracks. Each
+// file is uploaded through the storage service, its embedded tags become the
+// track metadata and, optionally, an enrichment job is queued. Handled files
+// are moved to .processed, or to .failed when ingestion fails.
+type WatchIngester struct {
+	tracks  domain.TrackRepository
+	storage domain.StorageService
+	jobs    domain.JobQueue
+	config  WatchIngesterConfig
+
+	// pending holds files seen in the previous scan; a file is ingested once
+	// it is unchanged between two scans so partially copied files are skipped
+	pending map[string]fileState
+}
+
+// NewWatchIngester creates a new watch-folder ingester. jobs may be nil when
+// enrichment is not enqueued.
+func NewWatchIngester(tracks domain.TrackRepository, storage domain.StorageService, jobs domain.JobQueue, config WatchIngesterConfig) *WatchIngester {
+	if config.PollInterval <= 0 {
+		config.PollInterval = 2 * time.Second
+	}
+	return &WatchIngester{
+		tracks:  tracks,
+		storage: storage,
+		jobs:    jobs,
+		config:  config,
+		pending: make(map[string]fileState),
+	}
+}
+
+// Run scans the directory until ctx is cancelled
+func (w *WatchIngester) Run(ctx context.Context) error {
+	if _, err := os.Stat(w.config.Dir); err != nil {
+		return fmt.Errorf("failed to watch directory: %w", err)
+	}
+
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Scan(ctx); err != nil {
+			log.Printf("Error scanning %s: %v", w.config.Dir, err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Scan performs a single pass over the directory and returns the tracks
+// created from files that have settled since the previous pass
+func (w *WatchIngester) Scan(ctx context.Context) ([]*domain.Track, error) {
+	entries, err := os.ReadDir(w.config.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	var created []*domain.Track
+	seen := make(map[string]fileState)
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !isAudioFile(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		state := fileState{size: info.Size(), modTime: info.ModTime()}
+		if previous, ok := w.pending[entry.Name()]; !ok || previous != state {
+			seen[entry.Name()] = state
+			continue
+		}
+
+		track, err := w.ingest(ctx, entry.Name(), info.Size())
+		if err != nil {
+			log.Printf("Failed to ingest %s: %v", entry.Name(), err)
+			w.moveTo(watchFailedDir, entry.Name())
+			continue
+		}
+		w.moveTo(watchProcessedDir, entry.Name())
+		created = append(created, track)
+	}
+	w.pending = seen
+
+	return created, nil
+}
+
+// ingest uploads a single file and creates its track
+func (w *WatchIngester) ingest(ctx context.Context, name string, size int64) (*domain.Track, error) {
+	path := filepath.Join(w.config.Dir, name)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	ext := strings.ToLower(filepath.Ext(name))
+	track := &domain.Track{
+		ID:       uuid.New().String(),
+		FileSize: size,
+		Status:   domain.TrackStatusPending,
+	}
+	track.SetAudioFormat(strings.TrimPrefix(ext, "."))
+	applyTags(track, file, name)
+
+	if _, err := file.Seek(0, 0); err != nil {
+		return nil, fmt.Errorf("failed to rewind file: %w", err)
+	}
+	key := fmt.Sprintf("%s/%s%s", domain.StoragePathPerm, track.ID, ext)
+	content := domain.NewChecksumReader(file)
+	stored := &domain.StorageFile{
+		Key:         key,
+		Name:        name,
+		Size:        size,
+		ContentType: mime.TypeByExtension(ext),
+		Content:     content,
+	}
+	if err := w.storage.Upload(ctx, stored); err != nil {
+		return nil, fmt.Errorf("failed to upload file: %w", err)
+	}
+	track.StoragePath = key
+	track.FilePath = key
+	checksums := content.Sum()
+	track.Metadata.Technical.ContentHash = checksums.SHA256
+	track.Metadata.Technical.ContentMD5 = checksums.MD5
+	track.Metadata.Technical.EncryptionKeyID = stored.EncryptionKeyID
+
+	if err := w.tracks.Create(ctx, track); err != nil {
+		// Do not leave an orphaned upload behind
+		if delErr := w.storage.Delete(ctx, key); delErr != nil {
+			log.Printf("Failed to delete upload %s: %v", key, delErr)
+		}
+		return nil, fmt.Errorf("failed to create track: %w", err)
+	}
+
+	if w.config.EnqueueEnrichment && w.jobs != nil {
+		if err := w.enqueueEnrichment(ctx, track); err != nil {
+			// The track exists; enrichment can still be requested later
+			log.Printf("Failed to enqueue enrichment for track %s: %v", track.ID, err)
+		}
+	}
+
+	return track, nil
+}
+
+func (w *WatchIngester) enqueueEnrichment(ctx context.Context, track *domain.Track) error {
+	payload, err := json.Marshal(domain.AIEnrichPayload{TrackID: track.ID})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return w.jobs.Enqueue(ctx, &domain.Job{
+		ID:         uuid.New().String(),
+		Type:       domain.JobTypeAIEnrich,
+		Priority:   domain.JobPriorityNormal,
+		Status:     domain.JobStatusPending,
+		Payload:    payload,
+		MaxRetries: 3,
+		CreatedAt:  time.Now(),
+	})
+}
+
+// moveTo moves a handled file into one of the bookkeeping directories
+func (w *WatchIngester) moveTo(dir, name string) {
+	if err := os.MkdirAll(filepath.Join(w.config.Dir, dir), 0o755); err != nil {
+		log.Printf("Failed to create %s directory: %v", dir, err)
+		return
+	}
+	if err := os.Rename(filepath.Join(w.config.Dir, name), filepath.Join(w.config.Dir, dir, name)); err != nil {
+		log.Printf("Failed to move %s to %s: %v", name, dir, err)
+	}
+}
+
+// applyTags copies the tags embedded in the file into the track metadata.
+// Files without readable tags are titled after their file name.
+func applyTags(track *domain.Track, file *os.File, name string) {
+	title := strings.TrimSuffix(name, filepath.Ext(name))
+	metadata, err := tag.ReadFrom(file)
+	if err != nil {
+		track.SetTitle(title)
+		return
+	}
+
+	if metadata.Title() != "" {
+		title = metadata.Title()
+	}
+	track.SetTitle(title)
+	track.SetArtist(metadata.Artist())
+	track.SetAlbum(metadata.Album())
+	track.SetYear(metadata.Year())
+	track.SetGenre(metadata.Genre())
+	if lyrics := metadata.Lyrics(); lyrics != "" {
+		track.SetLyrics(lyrics)
+	}
+
+	var changes []domain.FieldChange
+	for _, field := range []string{"title", "artist", "album", "year", "genre", "lyrics"} {
+		if value, _ := track.FieldValue(field); value != "" && value != 0 {
+			changes = append(changes, domain.FieldChange{Field: field, NewValue: value})
+		}
+	}
+	track.RecordProvenance(changes, domain.ProvenanceImport, "watch")
+}
+
+// isAudioFile reports whether name has the extension of a supported format
+func isAudioFile(name string) bool {
+	return domain.AudioFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")).IsValid()
+}
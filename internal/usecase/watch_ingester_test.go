@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pkgdomain "metadatatool/internal/pkg/domain"
+	"metadatatool/internal/repository/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWatchIngester(t *testing.T, repo *MockTrackRepository) (*WatchIngester, string, pkgdomain.StorageService) {
+	dir := t.TempDir()
+	store, err := storage.NewLocalStorage(t.TempDir(), "/files", nil)
+	require.NoError(t, err)
+	return NewWatchIngester(repo, store, nil, WatchIngesterConfig{Dir: dir}), dir, store
+}
+
+func TestWatchIngester_IngestsSettledFiles(t *testing.T) {
+	repo := new(MockTrackRepository)
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Track")).Return(nil)
+	ingester, dir, store := newTestWatchIngester(t, repo)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Night Drive.mp3"), []byte("not really audio"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644))
+
+	// The first scan only records the file so partial copies are not ingested
+	created, err := ingester.Scan(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, created)
+
+	created, err = ingester.Scan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+
+	track := created[0]
+	assert.Equal(t, "Night Drive", track.Title())
+	assert.Equal(t, "mp3", track.AudioFormat())
+	assert.Equal(t, pkgdomain.TrackStatusPending, track.Status)
+	assert.EqualValues(t, 16, track.FileSize)
+
+	stored, err := store.GetMetadata(context.Background(), track.StoragePath)
+	require.NoError(t, err)
+	assert.EqualValues(t, 16, stored.Size)
+
+	assert.FileExists(t, filepath.Join(dir, watchProcessedDir, "Night Drive.mp3"))
+	assert.NoFileExists(t, filepath.Join(dir, "Night Drive.mp3"))
+	assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+	repo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestWatchIngester_MovesFailedFiles(t *testing.T) {
+	repo := new(MockTrackRepository)
+	repo.On("Create", mock.Anything, mock.Anything).Return(errors.New("database down"))
+	ingester, dir, store := newTestWatchIngester(t, repo)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "take.flac"), []byte("audio"), 0o644))
+
+	_, err := ingester.Scan(context.Background())
+	require.NoError(t, err)
+	created, err := ingester.Scan(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, created)
+
+	assert.FileExists(t, filepath.Join(dir, watchFailedDir, "take.flac"))
+
+	// The upload is removed again when the track cannot be created
+	files, err := store.ListFiles(context.Background(), "")
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
@@ -0,0 +1,214 @@
+// Package client is a typed Go client for the Metadata Tool HTTP API.
+//
+// The request and response types and the per-operation methods in
+// client_gen.go are generated from the OpenAPI document by cmd/openapigen;
+// this file holds the hand-written transport they share.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client calls the API over HTTP
+type Client struct {
+	// BaseURL is the API root, for example https://api.example.com/api/v1
+	BaseURL string
+	// HTTPClient performs requests; http.DefaultClient is used when nil
+	HTTPClient *http.Client
+	// Header is added to every request, for example Authorization
+	Header http.Header
+	// TokenSource, when set, returns the bearer token of every request, for
+	// example to refresh a JWT before it expires. It overrides WithToken.
+	TokenSource func(ctx context.Context) (string, error)
+}
+
+// NewClient creates a client for the API at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		Header:     make(http.Header),
+	}
+}
+
+// WithToken sets the bearer token sent with every request
+func (c *Client) WithToken(token string) *Client {
+	if c.Header == nil {
+		c.Header = make(http.Header)
+	}
+	c.Header.Set("Authorization", "Bearer "+token)
+	return c
+}
+
+// WithAPIKey sets the API key sent with every request. Keys issued to users
+// and public catalog keys are both sent in the X-API-Key header.
+func (c *Client) WithAPIKey(key string) *Client {
+	if c.Header == nil {
+		c.Header = make(http.Header)
+	}
+	c.Header.Set("X-API-Key", key)
+	return c
+}
+
+// APIError is returned for non-2xx responses
+type APIError struct {
+	StatusCode int
+	Code       string       `json:"code"`
+	Type       string       `json:"type"`
+	Message    string       `json:"message"`
+	Details    interface{}  `json:"details,omitempty"`
+	Fields     []FieldError `json:"fields,omitempty"`
+	RequestID  string       `json:"request_id,omitempty"`
+	TraceID    string       `json:"trace_id,omitempty"`
+	Body       []byte       `json:"-"`
+}
+
+// FieldError reports why one field of a rejected request was invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		if e.Code != "" {
+			return fmt.Sprintf("api error %d %s: %s", e.StatusCode, e.Code, e.Message)
+		}
+		return fmt.Sprintf("api error %d %s: %s", e.StatusCode, e.Type, e.Message)
+	}
+	return fmt.Sprintf("api error %d: %s", e.StatusCode, strings.TrimSpace(string(e.Body)))
+}
+
+// IsNotModified reports whether err is the answer to a conditional request
+// for a resource the client already has
+func IsNotModified(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotModified
+}
+
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	header      http.Header
+	body        interface{}
+	contentType string
+}
+
+// do sends the request and decodes a successful response into out.
+// out may be nil, or a *[]byte to receive the raw body.
+func (c *Client) do(ctx context.Context, r request, out interface{}) error {
+	u := c.BaseURL + r.path
+	if len(r.query) > 0 {
+		u += "?" + r.query.Encode()
+	}
+
+	var body io.Reader
+	switch b := r.body.(type) {
+	case nil:
+	case io.Reader:
+		body = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return fmt.Errorf("failed to encode request body: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, u, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range c.Header {
+		req.Header[k] = v
+	}
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+	if c.TokenSource != nil {
+		token, err := c.TokenSource(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Body: data}
+		var envelope struct {
+			Error json.RawMessage `json:"error"`
+		}
+		if json.Unmarshal(data, &envelope) == nil && len(envelope.Error) > 0 {
+			if json.Unmarshal(envelope.Error, apiErr) != nil {
+				_ = json.Unmarshal(envelope.Error, &apiErr.Message)
+			}
+		}
+		return apiErr
+	}
+
+	switch o := out.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		*o = data
+		return nil
+	default:
+		if len(data) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		return nil
+	}
+}
+
+func setParam[T any](q url.Values, name string, v *T) {
+	if v == nil {
+		return
+	}
+	// Lists are sent as the parameter repeated once per value
+	if values, ok := any(*v).([]string); ok {
+		for _, value := range values {
+			q.Add(name, value)
+		}
+		return
+	}
+	q.Set(name, fmt.Sprint(*v))
+}
+
+func setHeader[T any](h http.Header, name string, v *T) {
+	if v != nil {
+		h.Set(name, fmt.Sprint(*v))
+	}
+}
@@ -0,0 +1,3056 @@
+// Code generated by openapigen. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var (
+	_ = time.Time{}
+	_ io.Reader
+)
+
+// AIBatch is a schema from the API document
+type AIBatch struct {
+	CompletedAt     time.Time     `json:"completed_at,omitempty"`
+	CreatedAt       time.Time     `json:"created_at,omitempty"`
+	Enriched        int           `json:"enriched,omitempty"`
+	Error           string        `json:"error,omitempty"`
+	Failed          int           `json:"failed,omitempty"`
+	ID              string        `json:"id,omitempty"`
+	Provider        AIProvider    `json:"provider,omitempty"`
+	ProviderBatchID string        `json:"provider_batch_id,omitempty"`
+	RequestedBy     string        `json:"requested_by,omitempty"`
+	Status          AIBatchStatus `json:"status,omitempty"`
+	TrackIDs        []string      `json:"track_ids,omitempty"`
+	UpdatedAt       time.Time     `json:"updated_at,omitempty"`
+}
+
+// AIBatchStatus is a schema from the API document
+type AIBatchStatus string
+
+const (
+	AIBatchStatusPending   AIBatchStatus = "pending"
+	AIBatchStatusCompleted AIBatchStatus = "completed"
+	AIBatchStatusFailed    AIBatchStatus = "failed"
+	AIBatchStatusExpired   AIBatchStatus = "expired"
+	AIBatchStatusCancelled AIBatchStatus = "cancelled"
+)
+
+// AIModelCoverage is a schema from the API document
+type AIModelCoverage struct {
+	Current       bool       `json:"current,omitempty"`
+	Model         string     `json:"model,omitempty"`
+	PromptVersion string     `json:"prompt_version,omitempty"`
+	Provider      AIProvider `json:"provider,omitempty"`
+	Share         float64    `json:"share,omitempty"`
+	Tracks        int64      `json:"tracks,omitempty"`
+	Version       string     `json:"version,omitempty"`
+}
+
+// AIModelCoverageReport is a schema from the API document
+type AIModelCoverageReport struct {
+	Current    []*AIModelVersion  `json:"current,omitempty"`
+	Models     []*AIModelCoverage `json:"models,omitempty"`
+	Outdated   int64              `json:"outdated,omitempty"`
+	Tracks     int64              `json:"tracks,omitempty"`
+	Unenriched int64              `json:"unenriched,omitempty"`
+}
+
+// AIModelVersion is a schema from the API document
+type AIModelVersion struct {
+	Model         string     `json:"model,omitempty"`
+	PromptVersion string     `json:"prompt_version,omitempty"`
+	Provider      AIProvider `json:"provider,omitempty"`
+	Version       string     `json:"version,omitempty"`
+}
+
+// AIProvider is a schema from the API document
+type AIProvider string
+
+const (
+	AIProviderQwen2  AIProvider = "qwen2"
+	AIProviderOpenai AIProvider = "openai"
+)
+
+// AIProviderHealth is a schema from the API document
+type AIProviderHealth struct {
+	ConsecutiveFailures int        `json:"consecutive_failures,omitempty"`
+	Healthy             bool       `json:"healthy,omitempty"`
+	LastChecked         time.Time  `json:"last_checked,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	LatencyMs           int64      `json:"latency_ms,omitempty"`
+	Provider            AIProvider `json:"provider,omitempty"`
+}
+
+// AIProviderStats is a schema from the API document
+type AIProviderStats struct {
+	AverageLatencyMs int64      `json:"average_latency_ms,omitempty"`
+	FailureRate      float64    `json:"failure_rate,omitempty"`
+	Failures         int64      `json:"failures,omitempty"`
+	Healthy          bool       `json:"healthy,omitempty"`
+	LastError        string     `json:"last_error,omitempty"`
+	LastSuccess      time.Time  `json:"last_success,omitempty"`
+	Provider         AIProvider `json:"provider,omitempty"`
+	QueueDepth       int        `json:"queue_depth,omitempty"`
+	Requests         int64      `json:"requests,omitempty"`
+}
+
+// AIRouting is a schema from the API document
+type AIRouting struct {
+	Forced    AIProvider          `json:"forced,omitempty"`
+	Order     []AIProvider        `json:"order,omitempty"`
+	Policy    AIRoutingPolicy     `json:"policy,omitempty"`
+	Providers []*AIProviderHealth `json:"providers,omitempty"`
+}
+
+// AIRoutingPolicy is a schema from the API document
+type AIRoutingPolicy string
+
+const (
+	AIRoutingPolicyExperiment AIRoutingPolicy = "experiment"
+	AIRoutingPolicyLatency    AIRoutingPolicy = "latency"
+)
+
+// AcknowledgementResult is a schema from the API document
+type AcknowledgementResult struct {
+	Deliveries int64          `json:"deliveries,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	PackageID  string         `json:"package_id,omitempty"`
+	Status     DeliveryStatus `json:"status,omitempty"`
+}
+
+// AdditionalMetadata is a schema from the API document
+type AdditionalMetadata struct {
+	Copyright    string            `json:"copyright,omitempty"`
+	CustomFields map[string]string `json:"customFields,omitempty"`
+	CustomTags   map[string]string `json:"customTags,omitempty"`
+	Lyrics       string            `json:"lyrics,omitempty"`
+	Publisher    string            `json:"publisher,omitempty"`
+	Tags         []string          `json:"tags,omitempty"`
+}
+
+// ArchiveState is a schema from the API document
+type ArchiveState string
+
+const (
+	ArchiveStateAvailable ArchiveState = "available"
+	ArchiveStateArchived  ArchiveState = "archived"
+	ArchiveStateRestoring ArchiveState = "restoring"
+	ArchiveStateRestored  ArchiveState = "restored"
+)
+
+// ArchiveStatus is a schema from the API document
+type ArchiveStatus struct {
+	Key           string       `json:"key,omitempty"`
+	RestoredUntil time.Time    `json:"restored_until,omitempty"`
+	State         ArchiveState `json:"state,omitempty"`
+	StorageClass  StorageClass `json:"storage_class,omitempty"`
+}
+
+// AudioAnalysis is a schema from the API document
+type AudioAnalysis struct {
+	AnalyzedAt    time.Time       `json:"analyzed_at,omitempty"`
+	AnalyzerInfo  string          `json:"analyzer_info,omitempty"`
+	Arousal       float64         `json:"arousal,omitempty"`
+	Beats         []float64       `json:"beats,omitempty"`
+	BeatsPerBar   int             `json:"beats_per_bar,omitempty"`
+	BPM           float64         `json:"bpm,omitempty"`
+	Brightness    float64         `json:"brightness,omitempty"`
+	Complexity    float64         `json:"complexity,omitempty"`
+	Danceability  float64         `json:"danceability,omitempty"`
+	Duration      float64         `json:"duration,omitempty"`
+	Energy        float64         `json:"energy,omitempty"`
+	HopSize       int             `json:"hop_size,omitempty"`
+	Intensity     float64         `json:"intensity,omitempty"`
+	Key           string          `json:"key,omitempty"`
+	Loudness      float64         `json:"loudness,omitempty"`
+	Mode          string          `json:"mode,omitempty"`
+	Mood          string          `json:"mood,omitempty"`
+	SampleCount   int64           `json:"sample_count,omitempty"`
+	SampleRate    int             `json:"sample_rate,omitempty"`
+	SectionCount  int             `json:"section_count,omitempty"`
+	Sections      []*AudioSection `json:"sections,omitempty"`
+	Segments      []*AudioSegment `json:"segments,omitempty"`
+	SpectralFlux  float64         `json:"spectral_flux,omitempty"`
+	SpectralRoll  float64         `json:"spectral_roll,omitempty"`
+	SpectralSlope float64         `json:"spectral_slope,omitempty"`
+	Tempo         float64         `json:"tempo,omitempty"`
+	Timbre        float64         `json:"timbre,omitempty"`
+	TimeSignature string          `json:"time_signature,omitempty"`
+	Transitions   []float64       `json:"transitions,omitempty"`
+	Valence       float64         `json:"valence,omitempty"`
+	WindowSize    int             `json:"window_size,omitempty"`
+}
+
+// AudioFormat is a schema from the API document
+type AudioFormat string
+
+const (
+	AudioFormatMp3  AudioFormat = "mp3"
+	AudioFormatWav  AudioFormat = "wav"
+	AudioFormatFlac AudioFormat = "flac"
+	AudioFormatM4a  AudioFormat = "m4a"
+	AudioFormatAac  AudioFormat = "aac"
+	AudioFormatOgg  AudioFormat = "ogg"
+)
+
+// AudioSection is a schema from the API document
+type AudioSection struct {
+	BPM      float64 `json:"bpm,omitempty"`
+	Camelot  string  `json:"camelot,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+	Energy   float64 `json:"energy,omitempty"`
+	Key      string  `json:"key,omitempty"`
+	Mode     string  `json:"mode,omitempty"`
+	Start    float64 `json:"start,omitempty"`
+}
+
+// AudioSegment is a schema from the API document
+type AudioSegment struct {
+	Confidence float64   `json:"confidence,omitempty"`
+	Duration   float64   `json:"duration,omitempty"`
+	Loudness   float64   `json:"loudness,omitempty"`
+	Pitches    []float64 `json:"pitches,omitempty"`
+	Start      float64   `json:"start,omitempty"`
+	Timbre     []float64 `json:"timbre,omitempty"`
+}
+
+// AudioTechnicalMetadata is a schema from the API document
+type AudioTechnicalMetadata struct {
+	Bitrate         int         `json:"bitrate,omitempty"`
+	Channels        int         `json:"channels,omitempty"`
+	ContentHash     string      `json:"contentHash,omitempty"`
+	ContentMD5      string      `json:"contentMD5,omitempty"`
+	EncryptionKeyID string      `json:"encryptionKeyId,omitempty"`
+	FileSize        int64       `json:"fileSize,omitempty"`
+	Format          AudioFormat `json:"format,omitempty"`
+	SampleRate      int         `json:"sampleRate,omitempty"`
+}
+
+// BackpressureLevel is a schema from the API document
+type BackpressureLevel string
+
+const (
+	BackpressureLevelNone     BackpressureLevel = "none"
+	BackpressureLevelThrottle BackpressureLevel = "throttle"
+	BackpressureLevelReject   BackpressureLevel = "reject"
+)
+
+// BasicTrackMetadata is a schema from the API document
+type BasicTrackMetadata struct {
+	Album     string    `json:"album,omitempty"`
+	Artist    string    `json:"artist,omitempty"`
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	Duration  float64   `json:"duration,omitempty"`
+	ISRC      string    `json:"isrc,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+	Year      int       `json:"year,omitempty"`
+}
+
+// BootstrapResult is a schema from the API document
+type BootstrapResult struct {
+	Resources []*ProvisionedResource `json:"resources,omitempty"`
+}
+
+// BulkEditJob is a schema from the API document
+type BulkEditJob struct {
+	CompletedAt time.Time         `json:"completed_at,omitempty"`
+	CreatedAt   time.Time         `json:"created_at,omitempty"`
+	CreatedBy   string            `json:"created_by,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	ID          string            `json:"id,omitempty"`
+	Processed   int               `json:"processed,omitempty"`
+	Request     *BulkEditRequest  `json:"request,omitempty"`
+	Results     []*BulkEditResult `json:"results,omitempty"`
+	StartedAt   time.Time         `json:"started_at,omitempty"`
+	Status      JobStatus         `json:"status,omitempty"`
+	Total       int               `json:"total,omitempty"`
+}
+
+// BulkEditPatch is a schema from the API document
+type BulkEditPatch struct {
+	AppendTags []string `json:"append_tags,omitempty"`
+	SetGenre   string   `json:"set_genre,omitempty"`
+	SetLabel   string   `json:"set_label,omitempty"`
+}
+
+// BulkEditRequest is a schema from the API document
+type BulkEditRequest struct {
+	DryRun   bool                   `json:"dry_run,omitempty"`
+	Filter   map[string]interface{} `json:"filter,omitempty"`
+	Patch    *BulkEditPatch         `json:"patch,omitempty"`
+	TrackIDs []string               `json:"track_ids,omitempty"`
+}
+
+// BulkEditResult is a schema from the API document
+type BulkEditResult struct {
+	Changes []*FieldChange       `json:"changes,omitempty"`
+	Error   string               `json:"error,omitempty"`
+	Status  BulkEditResultStatus `json:"status,omitempty"`
+	TrackID string               `json:"track_id,omitempty"`
+}
+
+// BulkEditResultStatus is a schema from the API document
+type BulkEditResultStatus string
+
+const (
+	BulkEditResultStatusUpdated   BulkEditResultStatus = "updated"
+	BulkEditResultStatusUnchanged BulkEditResultStatus = "unchanged"
+	BulkEditResultStatusNotFound  BulkEditResultStatus = "not_found"
+	BulkEditResultStatusFailed    BulkEditResultStatus = "failed"
+)
+
+// CalibrationBin is a schema from the API document
+type CalibrationBin struct {
+	Accuracy       float64 `json:"accuracy,omitempty"`
+	Correct        int     `json:"correct,omitempty"`
+	MaxConfidence  float64 `json:"max_confidence,omitempty"`
+	MeanConfidence float64 `json:"mean_confidence,omitempty"`
+	MinConfidence  float64 `json:"min_confidence,omitempty"`
+	Samples        int     `json:"samples,omitempty"`
+}
+
+// CompleteTrackMetadata is a schema from the API document
+type CompleteTrackMetadata struct {
+	Additional *AdditionalMetadata         `json:"additional,omitempty"`
+	AI         *TrackAIMetadata            `json:"ai,omitempty"`
+	Basic      *BasicTrackMetadata         `json:"basic,omitempty"`
+	Musical    *MusicalMetadata            `json:"musical,omitempty"`
+	Provenance map[string]*FieldProvenance `json:"provenance,omitempty"`
+	Technical  *AudioTechnicalMetadata     `json:"technical,omitempty"`
+}
+
+// ConfigBundle is a schema from the API document
+type ConfigBundle struct {
+	Environment string           `json:"environment,omitempty"`
+	ExportedAt  time.Time        `json:"exported_at,omitempty"`
+	ExportedBy  string           `json:"exported_by,omitempty"`
+	Labels      []*LabelConfig   `json:"labels,omitempty"`
+	Settings    *RuntimeSettings `json:"settings,omitempty"`
+	Signature   string           `json:"signature,omitempty"`
+	TagRules    []*TagRule       `json:"tag_rules,omitempty"`
+	Tags        []*Tag           `json:"tags,omitempty"`
+	Version     int              `json:"version,omitempty"`
+}
+
+// ConfigChange is a schema from the API document
+type ConfigChange struct {
+	Action  string `json:"action,omitempty"`
+	Kind    string `json:"kind,omitempty"`
+	LabelID string `json:"label_id,omitempty"`
+	Name    string `json:"name,omitempty"`
+}
+
+// ConfigImportResult is a schema from the API document
+type ConfigImportResult struct {
+	Changes []*ConfigChange `json:"changes,omitempty"`
+	DryRun  bool            `json:"dry_run,omitempty"`
+	Source  string          `json:"source,omitempty"`
+}
+
+// CustomFieldDefinition is a schema from the API document
+type CustomFieldDefinition struct {
+	CreatedAt   time.Time       `json:"created_at,omitempty"`
+	Description string          `json:"description,omitempty"`
+	LabelID     string          `json:"label_id,omitempty"`
+	Max         float64         `json:"max,omitempty"`
+	Min         float64         `json:"min,omitempty"`
+	Name        string          `json:"name,omitempty"`
+	Pattern     string          `json:"pattern,omitempty"`
+	Required    bool            `json:"required,omitempty"`
+	Type        CustomFieldType `json:"type,omitempty"`
+	UpdatedAt   time.Time       `json:"updated_at,omitempty"`
+	Values      []string        `json:"values,omitempty"`
+}
+
+// CustomFieldType is a schema from the API document
+type CustomFieldType string
+
+const (
+	CustomFieldTypeString  CustomFieldType = "string"
+	CustomFieldTypeInteger CustomFieldType = "integer"
+	CustomFieldTypeNumber  CustomFieldType = "number"
+	CustomFieldTypeBoolean CustomFieldType = "boolean"
+	CustomFieldTypeDate    CustomFieldType = "date"
+	CustomFieldTypeURL     CustomFieldType = "url"
+	CustomFieldTypeEnum    CustomFieldType = "enum"
+)
+
+// DatabaseStats is a schema from the API document
+type DatabaseStats struct {
+	Idle               int   `json:"idle,omitempty"`
+	InUse              int   `json:"in_use,omitempty"`
+	MaxOpenConnections int   `json:"max_open_connections,omitempty"`
+	OpenConnections    int   `json:"open_connections,omitempty"`
+	WaitCount          int64 `json:"wait_count,omitempty"`
+	WaitDurationMs     int64 `json:"wait_duration_ms,omitempty"`
+}
+
+// DeadLetterPage is a schema from the API document
+type DeadLetterPage struct {
+	Limit    int        `json:"limit,omitempty"`
+	Messages []*Message `json:"messages,omitempty"`
+	Offset   int        `json:"offset,omitempty"`
+	Topic    string     `json:"topic,omitempty"`
+	Total    int64      `json:"total,omitempty"`
+}
+
+// DeadLetterReplayReport is a schema from the API document
+type DeadLetterReplayReport struct {
+	Failed   int                       `json:"failed,omitempty"`
+	Replayed int                       `json:"replayed,omitempty"`
+	Results  []*DeadLetterReplayResult `json:"results,omitempty"`
+	Topic    string                    `json:"topic,omitempty"`
+}
+
+// DeadLetterReplayRequest is a schema from the API document
+type DeadLetterReplayRequest struct {
+	IDs []string `json:"ids,omitempty"`
+}
+
+// DeadLetterReplayResult is a schema from the API document
+type DeadLetterReplayResult struct {
+	Error    string `json:"error,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Replayed bool   `json:"replayed,omitempty"`
+}
+
+// Delivery is a schema from the API document
+type Delivery struct {
+	AcknowledgedAt time.Time      `json:"acknowledged_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at,omitempty"`
+	DeliveredAt    time.Time      `json:"delivered_at,omitempty"`
+	Dsp            string         `json:"dsp,omitempty"`
+	Error          string         `json:"error,omitempty"`
+	ID             string         `json:"id,omitempty"`
+	Kind           DeliveryKind   `json:"kind,omitempty"`
+	PackageFormat  string         `json:"package_format,omitempty"`
+	PackageID      string         `json:"package_id,omitempty"`
+	ReleaseID      string         `json:"release_id,omitempty"`
+	Status         DeliveryStatus `json:"status,omitempty"`
+	Territories    []string       `json:"territories,omitempty"`
+	TrackID        string         `json:"track_id,omitempty"`
+	UpdatedAt      time.Time      `json:"updated_at,omitempty"`
+}
+
+// DeliveryKind is a schema from the API document
+type DeliveryKind string
+
+const (
+	DeliveryKindRelease        DeliveryKind = "release"
+	DeliveryKindTakedown       DeliveryKind = "takedown"
+	DeliveryKindTerritoryBlock DeliveryKind = "territory_block"
+)
+
+// DeliveryStatus is a schema from the API document
+type DeliveryStatus string
+
+const (
+	DeliveryStatusSent         DeliveryStatus = "sent"
+	DeliveryStatusAcknowledged DeliveryStatus = "acknowledged"
+	DeliveryStatusFailed       DeliveryStatus = "failed"
+)
+
+// DuplicateCandidate is a schema from the API document
+type DuplicateCandidate struct {
+	Artist  string   `json:"artist,omitempty"`
+	ISRC    string   `json:"isrc,omitempty"`
+	Reasons []string `json:"reasons,omitempty"`
+	Score   float64  `json:"score,omitempty"`
+	Title   string   `json:"title,omitempty"`
+	TrackID string   `json:"track_id,omitempty"`
+}
+
+// EvaluationReport is a schema from the API document
+type EvaluationReport struct {
+	EvaluatedAt    time.Time             `json:"evaluated_at,omitempty"`
+	GoldenTracks   int                   `json:"golden_tracks,omitempty"`
+	Providers      []*ProviderEvaluation `json:"providers,omitempty"`
+	TargetAccuracy float64               `json:"target_accuracy,omitempty"`
+}
+
+// FieldAccuracy is a schema from the API document
+type FieldAccuracy struct {
+	Accuracy float64 `json:"accuracy,omitempty"`
+	Correct  int     `json:"correct,omitempty"`
+	Field    string  `json:"field,omitempty"`
+	Samples  int     `json:"samples,omitempty"`
+}
+
+// FieldChange is a schema from the API document
+type FieldChange struct {
+	Field    string      `json:"field,omitempty"`
+	NewValue interface{} `json:"new_value,omitempty"`
+	OldValue interface{} `json:"old_value,omitempty"`
+}
+
+// FieldProvenance is a schema from the API document
+type FieldProvenance struct {
+	Source    ProvenanceSource `json:"source,omitempty"`
+	UpdatedAt time.Time        `json:"updatedAt,omitempty"`
+	UpdatedBy string           `json:"updatedBy,omitempty"`
+}
+
+// FieldProvenanceView is a schema from the API document
+type FieldProvenanceView struct {
+	Field     string           `json:"field,omitempty"`
+	Source    ProvenanceSource `json:"source,omitempty"`
+	UpdatedAt time.Time        `json:"updatedAt,omitempty"`
+	UpdatedBy string           `json:"updatedBy,omitempty"`
+	Value     interface{}      `json:"value,omitempty"`
+}
+
+// GoldenTrack is a schema from the API document
+type GoldenTrack struct {
+	BPM       float64   `json:"bpm,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	Genre     string    `json:"genre,omitempty"`
+	Key       string    `json:"key,omitempty"`
+	LabeledBy string    `json:"labeled_by,omitempty"`
+	Mood      string    `json:"mood,omitempty"`
+	TrackID   string    `json:"track_id,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// HarmonicMatch is a schema from the API document
+type HarmonicMatch struct {
+	BPMDifference float64          `json:"bpm_difference,omitempty"`
+	Camelot       string           `json:"camelot,omitempty"`
+	Relation      HarmonicRelation `json:"relation,omitempty"`
+	Track         *Track           `json:"track,omitempty"`
+}
+
+// HarmonicRelation is a schema from the API document
+type HarmonicRelation string
+
+const (
+	HarmonicRelationSame     HarmonicRelation = "same"
+	HarmonicRelationAdjacent HarmonicRelation = "adjacent"
+	HarmonicRelationRelative HarmonicRelation = "relative"
+)
+
+// ImportColumn is a schema from the API document
+type ImportColumn struct {
+	Column     string             `json:"column,omitempty"`
+	Field      string             `json:"field,omitempty"`
+	Required   bool               `json:"required,omitempty"`
+	Transforms []*ImportTransform `json:"transforms,omitempty"`
+}
+
+// ImportMapping is a schema from the API document
+type ImportMapping struct {
+	Columns   []*ImportColumn   `json:"columns,omitempty"`
+	CreatedAt time.Time         `json:"created_at,omitempty"`
+	Defaults  map[string]string `json:"defaults,omitempty"`
+	Delimiter string            `json:"delimiter,omitempty"`
+	ID        string            `json:"id,omitempty"`
+	LabelID   string            `json:"label_id,omitempty"`
+	Name      string            `json:"name,omitempty"`
+	UpdatedAt time.Time         `json:"updated_at,omitempty"`
+}
+
+// ImportTransform is a schema from the API document
+type ImportTransform struct {
+	Default string              `json:"default,omitempty"`
+	Format  string              `json:"format,omitempty"`
+	Type    ImportTransformType `json:"type,omitempty"`
+	Values  map[string]string   `json:"values,omitempty"`
+}
+
+// ImportTransformType is a schema from the API document
+type ImportTransformType string
+
+const (
+	ImportTransformTypeDate   ImportTransformType = "date"
+	ImportTransformTypeLookup ImportTransformType = "lookup"
+	ImportTransformTypeUpper  ImportTransformType = "upper"
+	ImportTransformTypeLower  ImportTransformType = "lower"
+)
+
+// IntegrityReport is a schema from the API document
+type IntegrityReport struct {
+	ActualMd5      string          `json:"actual_md5,omitempty"`
+	ActualSha256   string          `json:"actual_sha256,omitempty"`
+	CheckedAt      time.Time       `json:"checked_at,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	ExpectedMd5    string          `json:"expected_md5,omitempty"`
+	ExpectedSha256 string          `json:"expected_sha256,omitempty"`
+	Key            string          `json:"key,omitempty"`
+	Size           int64           `json:"size,omitempty"`
+	Status         IntegrityStatus `json:"status,omitempty"`
+	TrackID        string          `json:"track_id,omitempty"`
+}
+
+// IntegrityStatus is a schema from the API document
+type IntegrityStatus string
+
+const (
+	IntegrityStatusOk          IntegrityStatus = "ok"
+	IntegrityStatusMismatch    IntegrityStatus = "mismatch"
+	IntegrityStatusRecorded    IntegrityStatus = "recorded"
+	IntegrityStatusUnavailable IntegrityStatus = "unavailable"
+)
+
+// JobHistory is a schema from the API document
+type JobHistory struct {
+	ArchivedAt time.Time              `json:"archived_at,omitempty"`
+	CreatedAt  time.Time              `json:"created_at,omitempty"`
+	DurationMs int64                  `json:"duration_ms,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	FinishedAt time.Time              `json:"finished_at,omitempty"`
+	ID         string                 `json:"id,omitempty"`
+	Payload    map[string]interface{} `json:"payload,omitempty"`
+	Priority   JobPriority            `json:"priority,omitempty"`
+	Queue      string                 `json:"queue,omitempty"`
+	RetryCount int                    `json:"retry_count,omitempty"`
+	StartedAt  time.Time              `json:"started_at,omitempty"`
+	Status     JobStatus              `json:"status,omitempty"`
+	Type       JobType                `json:"type,omitempty"`
+}
+
+// JobHistoryPage is a schema from the API document
+type JobHistoryPage struct {
+	Jobs   []*JobHistory `json:"jobs,omitempty"`
+	Limit  int           `json:"limit,omitempty"`
+	Offset int           `json:"offset,omitempty"`
+	Total  int64         `json:"total,omitempty"`
+}
+
+// JobHistoryReport is a schema from the API document
+type JobHistoryReport struct {
+	From         string             `json:"from,omitempty"`
+	SlowestTypes []*JobTypeDuration `json:"slowest_types,omitempty"`
+	To           string             `json:"to,omitempty"`
+	Weeks        []*JobWeekStats    `json:"weeks,omitempty"`
+}
+
+// JobPriority is a schema from the API document
+type JobPriority int
+
+// JobStats is a schema from the API document
+type JobStats struct {
+	Completed     int64   `json:"completed,omitempty"`
+	Failed        int64   `json:"failed,omitempty"`
+	FailureRate   float64 `json:"failure_rate,omitempty"`
+	WindowSeconds int64   `json:"window_seconds,omitempty"`
+}
+
+// JobStatus is a schema from the API document
+type JobStatus string
+
+const (
+	JobStatusPending    JobStatus = "pending"
+	JobStatusProcessing JobStatus = "processing"
+	JobStatusCompleted  JobStatus = "completed"
+	JobStatusFailed     JobStatus = "failed"
+	JobStatusCanceled   JobStatus = "canceled"
+)
+
+// JobType is a schema from the API document
+type JobType string
+
+const (
+	JobTypeAudioProcess     JobType = "audio_process"
+	JobTypeAIEnrich         JobType = "ai_enrich"
+	JobTypeDDEXExport       JobType = "ddex_export"
+	JobTypeCleanup          JobType = "cleanup"
+	JobTypeBulkEdit         JobType = "bulk_edit"
+	JobTypeFileScan         JobType = "file_scan"
+	JobTypeStorageReplicate JobType = "storage_replicate"
+	JobTypeAudioReanalyze   JobType = "audio_reanalyze"
+)
+
+// JobTypeDuration is a schema from the API document
+type JobTypeDuration struct {
+	AvgMs float64 `json:"avg_ms,omitempty"`
+	Jobs  int64   `json:"jobs,omitempty"`
+	MaxMs int64   `json:"max_ms,omitempty"`
+	P95Ms float64 `json:"p95_ms,omitempty"`
+	Type  JobType `json:"type,omitempty"`
+}
+
+// JobWeekStats is a schema from the API document
+type JobWeekStats struct {
+	Completed   int64   `json:"completed,omitempty"`
+	Failed      int64   `json:"failed,omitempty"`
+	SuccessRate float64 `json:"success_rate,omitempty"`
+	Type        JobType `json:"type,omitempty"`
+	Week        string  `json:"week,omitempty"`
+}
+
+// LabelAIConfig is a schema from the API document
+type LabelAIConfig struct {
+	APIKeyHint    string     `json:"api_key_hint,omitempty"`
+	CreatedAt     time.Time  `json:"created_at,omitempty"`
+	LabelID       string     `json:"label_id,omitempty"`
+	MinConfidence float64    `json:"min_confidence,omitempty"`
+	Model         string     `json:"model,omitempty"`
+	Provider      AIProvider `json:"provider,omitempty"`
+	UpdatedAt     time.Time  `json:"updated_at,omitempty"`
+	UpdatedBy     string     `json:"updated_by,omitempty"`
+}
+
+// LabelConfig is a schema from the API document
+type LabelConfig struct {
+	CustomFields   []*CustomFieldDefinition `json:"custom_fields,omitempty"`
+	ImportMappings []*ImportMapping         `json:"import_mappings,omitempty"`
+	LabelID        string                   `json:"label_id,omitempty"`
+}
+
+// LabelStats is a schema from the API document
+type LabelStats struct {
+	AverageConfidence  float64          `json:"average_confidence,omitempty"`
+	ByGenre            map[string]int64 `json:"by_genre,omitempty"`
+	ByStatus           map[string]int64 `json:"by_status,omitempty"`
+	ByYear             map[string]int64 `json:"by_year,omitempty"`
+	ComputedAt         time.Time        `json:"computed_at,omitempty"`
+	DurationSeconds    float64          `json:"duration_seconds,omitempty"`
+	Enriched           int64            `json:"enriched,omitempty"`
+	EnrichmentCoverage float64          `json:"enrichment_coverage,omitempty"`
+	LabelID            string           `json:"label_id,omitempty"`
+	StorageBytes       int64            `json:"storage_bytes,omitempty"`
+	Tracks             int64            `json:"tracks,omitempty"`
+}
+
+// MaintenanceRun is a schema from the API document
+type MaintenanceRun struct {
+	DueAt      time.Time `json:"due_at,omitempty"`
+	DurationMs int64     `json:"duration_ms,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+	Instance   string    `json:"instance,omitempty"`
+	StartedAt  time.Time `json:"started_at,omitempty"`
+	Task       string    `json:"task,omitempty"`
+}
+
+// MaintenanceStatus is a schema from the API document
+type MaintenanceStatus struct {
+	Instance string             `json:"instance,omitempty"`
+	Leader   bool               `json:"leader,omitempty"`
+	Tasks    []*MaintenanceTask `json:"tasks,omitempty"`
+}
+
+// MaintenanceTask is a schema from the API document
+type MaintenanceTask struct {
+	Name     string            `json:"name,omitempty"`
+	NextRun  time.Time         `json:"next_run,omitempty"`
+	Running  bool              `json:"running,omitempty"`
+	Runs     []*MaintenanceRun `json:"runs,omitempty"`
+	Schedule string            `json:"schedule,omitempty"`
+}
+
+// MergePick is a schema from the API document
+type MergePick string
+
+const (
+	MergePickFill    MergePick = "fill"
+	MergePickTarget  MergePick = "target"
+	MergePickSource  MergePick = "source"
+	MergePickCombine MergePick = "combine"
+)
+
+// MergeRules is a schema from the API document
+type MergeRules map[string]MergePick
+
+// Message is a schema from the API document
+type Message struct {
+	CreatedAt    time.Time              `json:"created_at,omitempty"`
+	Data         map[string]interface{} `json:"data,omitempty"`
+	DeadLetterAt time.Time              `json:"dead_letter_at,omitempty"`
+	ErrorMessage string                 `json:"error_message,omitempty"`
+	ID           string                 `json:"id,omitempty"`
+	MaxRetries   int                    `json:"max_retries,omitempty"`
+	NextRetryAt  time.Time              `json:"next_retry_at,omitempty"`
+	Priority     QueuePriority          `json:"priority,omitempty"`
+	ProcessedAt  time.Time              `json:"processed_at,omitempty"`
+	RetryCount   int                    `json:"retry_count,omitempty"`
+	Status       MessageStatus          `json:"status,omitempty"`
+	Type         string                 `json:"type,omitempty"`
+	UpdatedAt    time.Time              `json:"updated_at,omitempty"`
+}
+
+// MessageStatus is a schema from the API document
+type MessageStatus string
+
+const (
+	MessageStatusPending    MessageStatus = "pending"
+	MessageStatusProcessing MessageStatus = "processing"
+	MessageStatusCompleted  MessageStatus = "completed"
+	MessageStatusFailed     MessageStatus = "failed"
+	MessageStatusRetrying   MessageStatus = "retrying"
+	MessageStatusDeadLetter MessageStatus = "dead_letter"
+)
+
+// MusicalMetadata is a schema from the API document
+type MusicalMetadata struct {
+	BPM     float64 `json:"bpm,omitempty"`
+	Camelot string  `json:"camelot,omitempty"`
+	Energy  float64 `json:"energy,omitempty"`
+	Genre   string  `json:"genre,omitempty"`
+	Key     string  `json:"key,omitempty"`
+	Mode    string  `json:"mode,omitempty"`
+	Mood    string  `json:"mood,omitempty"`
+	OpenKey string  `json:"openKey,omitempty"`
+	Tempo   float64 `json:"tempo,omitempty"`
+}
+
+// PlayCount is a schema from the API document
+type PlayCount struct {
+	Dsp       string    `json:"dsp,omitempty"`
+	ISRC      string    `json:"isrc,omitempty"`
+	Month     string    `json:"month,omitempty"`
+	Plays     int64     `json:"plays,omitempty"`
+	Territory string    `json:"territory,omitempty"`
+	TrackID   string    `json:"track_id,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// ProvenanceSource is a schema from the API document
+type ProvenanceSource string
+
+const (
+	ProvenanceSourceManual ProvenanceSource = "manual"
+	ProvenanceSourceAI     ProvenanceSource = "ai"
+	ProvenanceSourceImport ProvenanceSource = "import"
+)
+
+// ProviderEvaluation is a schema from the API document
+type ProviderEvaluation struct {
+	Calibration            []*CalibrationBin `json:"calibration,omitempty"`
+	Failures               int               `json:"failures,omitempty"`
+	Fields                 []*FieldAccuracy  `json:"fields,omitempty"`
+	Provider               AIProvider        `json:"provider,omitempty"`
+	SuggestedMinConfidence float64           `json:"suggested_min_confidence,omitempty"`
+	Tracks                 int               `json:"tracks,omitempty"`
+}
+
+// ProvisionedResource is a schema from the API document
+type ProvisionedResource struct {
+	Kind   string `json:"kind,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+// PublicAPIKey is a schema from the API document
+type PublicAPIKey struct {
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	ID        string    `json:"id,omitempty"`
+	LabelID   string    `json:"label_id,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Prefix    string    `json:"prefix,omitempty"`
+	RevokedAt time.Time `json:"revoked_at,omitempty"`
+	Tier      string    `json:"tier,omitempty"`
+}
+
+// PublicAPITier is a schema from the API document
+type PublicAPITier struct {
+	Name              string `json:"name,omitempty"`
+	RequestsPerDay    int    `json:"requests_per_day,omitempty"`
+	RequestsPerMinute int    `json:"requests_per_minute,omitempty"`
+}
+
+// QueuePriority is a schema from the API document
+type QueuePriority int
+
+// QueueStats is a schema from the API document
+type QueueStats struct {
+	DeadLetters int64  `json:"dead_letters,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Pending     int64  `json:"pending,omitempty"`
+	Processing  int64  `json:"processing,omitempty"`
+}
+
+// ReenrichmentRun is a schema from the API document
+type ReenrichmentRun struct {
+	CompletedAt time.Time          `json:"completed_at,omitempty"`
+	Current     []*AIModelVersion  `json:"current,omitempty"`
+	Enriched    int                `json:"enriched,omitempty"`
+	Error       string             `json:"error,omitempty"`
+	Failed      int                `json:"failed,omitempty"`
+	StartedAt   time.Time          `json:"started_at,omitempty"`
+	Status      ReenrichmentStatus `json:"status,omitempty"`
+}
+
+// ReenrichmentStatus is a schema from the API document
+type ReenrichmentStatus string
+
+const (
+	ReenrichmentStatusRunning   ReenrichmentStatus = "running"
+	ReenrichmentStatusCompleted ReenrichmentStatus = "completed"
+	ReenrichmentStatusCancelled ReenrichmentStatus = "cancelled"
+	ReenrichmentStatusFailed    ReenrichmentStatus = "failed"
+)
+
+// RuntimeSettings is a schema from the API document
+type RuntimeSettings struct {
+	AIForcedProvider         AIProvider `json:"ai_forced_provider,omitempty"`
+	AIMinConfidence          float64    `json:"ai_min_confidence,omitempty"`
+	ExperimentTrafficPercent float64    `json:"experiment_traffic_percent,omitempty"`
+	RateLimitPerMinute       int        `json:"rate_limit_per_minute,omitempty"`
+	UpdatedAt                time.Time  `json:"updated_at,omitempty"`
+	UpdatedBy                string     `json:"updated_by,omitempty"`
+}
+
+// RuntimeSettingsPatch is a schema from the API document
+type RuntimeSettingsPatch struct {
+	AIForcedProvider         AIProvider `json:"ai_forced_provider,omitempty"`
+	AIMinConfidence          float64    `json:"ai_min_confidence,omitempty"`
+	ExperimentTrafficPercent float64    `json:"experiment_traffic_percent,omitempty"`
+	RateLimitPerMinute       int        `json:"rate_limit_per_minute,omitempty"`
+}
+
+// SalesReport is a schema from the API document
+type SalesReport struct {
+	Dsp        string    `json:"dsp,omitempty"`
+	IngestedAt time.Time `json:"ingested_at,omitempty"`
+	MessageID  string    `json:"message_id,omitempty"`
+	Month      string    `json:"month,omitempty"`
+	Plays      int64     `json:"plays,omitempty"`
+	Records    int       `json:"records,omitempty"`
+	Unmatched  int       `json:"unmatched,omitempty"`
+}
+
+// SearchFilter is a schema from the API document
+type SearchFilter struct {
+	And   []*SearchFilter `json:"and,omitempty"`
+	Field string          `json:"field,omitempty"`
+	Not   *SearchFilter   `json:"not,omitempty"`
+	Op    SearchOp        `json:"op,omitempty"`
+	Or    []*SearchFilter `json:"or,omitempty"`
+	Value interface{}     `json:"value,omitempty"`
+}
+
+// SearchOp is a schema from the API document
+type SearchOp string
+
+const (
+	SearchOpEq       SearchOp = "eq"
+	SearchOpNe       SearchOp = "ne"
+	SearchOpGt       SearchOp = "gt"
+	SearchOpGte      SearchOp = "gte"
+	SearchOpLt       SearchOp = "lt"
+	SearchOpLte      SearchOp = "lte"
+	SearchOpBetween  SearchOp = "between"
+	SearchOpContains SearchOp = "contains"
+	SearchOpIn       SearchOp = "in"
+	SearchOpExists   SearchOp = "exists"
+)
+
+// SignedUpload is a schema from the API document
+type SignedUpload struct {
+	ExpiresAt time.Time         `json:"expires_at,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Method    string            `json:"method,omitempty"`
+	URL       string            `json:"url,omitempty"`
+}
+
+// SimilarTrack is a schema from the API document
+type SimilarTrack struct {
+	Distance float64 `json:"distance,omitempty"`
+	Track    *Track  `json:"track,omitempty"`
+}
+
+// StorageClass is a schema from the API document
+type StorageClass string
+
+const (
+	StorageClassSTANDARD   StorageClass = "STANDARD"
+	StorageClassSTANDARDIA StorageClass = "STANDARD_IA"
+	StorageClassGLACIER    StorageClass = "GLACIER"
+)
+
+// StorageStats is a schema from the API document
+type StorageStats struct {
+	QuotaBytes  int64   `json:"quota_bytes,omitempty"`
+	UsedBytes   int64   `json:"used_bytes,omitempty"`
+	UsedPercent float64 `json:"used_percent,omitempty"`
+}
+
+// SystemStats is a schema from the API document
+type SystemStats struct {
+	AIProviders []*AIProviderStats `json:"ai_providers,omitempty"`
+	Database    *DatabaseStats     `json:"database,omitempty"`
+	Errors      map[string]string  `json:"errors,omitempty"`
+	GeneratedAt time.Time          `json:"generated_at,omitempty"`
+	Jobs        *JobStats          `json:"jobs,omitempty"`
+	QueueLag    []*TopicLag        `json:"queue_lag,omitempty"`
+	Queues      []*QueueStats      `json:"queues,omitempty"`
+	Storage     *StorageStats      `json:"storage,omitempty"`
+}
+
+// Tag is a schema from the API document
+type Tag struct {
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+// TagCondition is a schema from the API document
+type TagCondition struct {
+	Field string      `json:"field,omitempty"`
+	Op    TagRuleOp   `json:"op,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// TagRule is a schema from the API document
+type TagRule struct {
+	Conditions []*TagCondition `json:"conditions,omitempty"`
+	CreatedAt  time.Time       `json:"created_at,omitempty"`
+	Disabled   bool            `json:"disabled,omitempty"`
+	ID         string          `json:"id,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	Tag        string          `json:"tag,omitempty"`
+	UpdatedAt  time.Time       `json:"updated_at,omitempty"`
+}
+
+// TagRuleOp is a schema from the API document
+type TagRuleOp string
+
+const (
+	TagRuleOpEq       TagRuleOp = "eq"
+	TagRuleOpNe       TagRuleOp = "ne"
+	TagRuleOpGt       TagRuleOp = "gt"
+	TagRuleOpGte      TagRuleOp = "gte"
+	TagRuleOpLt       TagRuleOp = "lt"
+	TagRuleOpLte      TagRuleOp = "lte"
+	TagRuleOpContains TagRuleOp = "contains"
+	TagRuleOpIn       TagRuleOp = "in"
+)
+
+// TopicLag is a schema from the API document
+type TopicLag struct {
+	Lag         int64             `json:"lag,omitempty"`
+	Level       BackpressureLevel `json:"level,omitempty"`
+	PublishRate float64           `json:"publish_rate,omitempty"`
+	SampledAt   time.Time         `json:"sampled_at,omitempty"`
+	Topic       string            `json:"topic,omitempty"`
+}
+
+// Track is a schema from the API document
+type Track struct {
+	ArtistIDs   []string               `json:"artistIds,omitempty"`
+	CreatedAt   time.Time              `json:"createdAt,omitempty"`
+	DeletedAt   time.Time              `json:"deletedAt,omitempty"`
+	FilePath    string                 `json:"filePath,omitempty"`
+	FileSize    int64                  `json:"fileSize,omitempty"`
+	ID          string                 `json:"id,omitempty"`
+	LabelID     string                 `json:"labelId,omitempty"`
+	Lock        *TrackLock             `json:"lock,omitempty"`
+	Metadata    *CompleteTrackMetadata `json:"metadata,omitempty"`
+	PreviousID  string                 `json:"previousId,omitempty"`
+	ReleaseID   string                 `json:"releaseId,omitempty"`
+	Status      TrackStatus            `json:"status,omitempty"`
+	StatusMsg   string                 `json:"statusMsg,omitempty"`
+	StoragePath string                 `json:"storagePath,omitempty"`
+	UpdatedAt   time.Time              `json:"updatedAt,omitempty"`
+	Version     int                    `json:"version,omitempty"`
+}
+
+// TrackAIMetadata is a schema from the API document
+type TrackAIMetadata struct {
+	Analysis              string                  `json:"analysis,omitempty"`
+	Confidence            float64                 `json:"confidence,omitempty"`
+	Model                 string                  `json:"model,omitempty"`
+	NeedsReview           bool                    `json:"needsReview,omitempty"`
+	ProcessedAt           time.Time               `json:"processedAt,omitempty"`
+	PromptVersion         string                  `json:"promptVersion,omitempty"`
+	ReviewReason          string                  `json:"reviewReason,omitempty"`
+	Tags                  []string                `json:"tags,omitempty"`
+	ValidationIssues      []*ValidationIssue      `json:"validationIssues,omitempty"`
+	ValidationSuggestions []*ValidationSuggestion `json:"validationSuggestions,omitempty"`
+	Version               string                  `json:"version,omitempty"`
+}
+
+// TrackAnalysis is a schema from the API document
+type TrackAnalysis struct {
+	Analysis        *AudioAnalysis `json:"analysis,omitempty"`
+	AnalyzedAt      time.Time      `json:"analyzed_at,omitempty"`
+	AnalyzerVersion string         `json:"analyzer_version,omitempty"`
+	TrackID         string         `json:"track_id,omitempty"`
+}
+
+// TrackEmbedding is a schema from the API document
+type TrackEmbedding struct {
+	Model     string    `json:"model,omitempty"`
+	TrackID   string    `json:"track_id,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	Vector    []float64 `json:"vector,omitempty"`
+}
+
+// TrackLock is a schema from the API document
+type TrackLock struct {
+	AcquiredAt time.Time `json:"acquiredAt,omitempty"`
+	ExpiresAt  time.Time `json:"expiresAt,omitempty"`
+	Owner      string    `json:"owner,omitempty"`
+}
+
+// TrackStatus is a schema from the API document
+type TrackStatus string
+
+const (
+	TrackStatusDraft       TrackStatus = "draft"
+	TrackStatusPending     TrackStatus = "pending"
+	TrackStatusProcessing  TrackStatus = "processing"
+	TrackStatusNeedsReview TrackStatus = "needs_review"
+	TrackStatusApproved    TrackStatus = "approved"
+	TrackStatusDelivered   TrackStatus = "delivered"
+	TrackStatusArchived    TrackStatus = "archived"
+	TrackStatusRejected    TrackStatus = "rejected"
+	TrackStatusActive      TrackStatus = "active"
+	TrackStatusInactive    TrackStatus = "inactive"
+	TrackStatusDeleted     TrackStatus = "deleted"
+)
+
+// UsageDay is a schema from the API document
+type UsageDay struct {
+	AIEnrichments     int64  `json:"ai_enrichments,omitempty"`
+	Day               string `json:"day,omitempty"`
+	Exports           int64  `json:"exports,omitempty"`
+	LabelID           string `json:"label_id,omitempty"`
+	PublicAPIRequests int64  `json:"public_api_requests,omitempty"`
+	StorageBytes      int64  `json:"storage_bytes,omitempty"`
+	TracksStored      int64  `json:"tracks_stored,omitempty"`
+}
+
+// UsageReport is a schema from the API document
+type UsageReport struct {
+	Days []*UsageDay `json:"days,omitempty"`
+	From string      `json:"from,omitempty"`
+	To   string      `json:"to,omitempty"`
+}
+
+// ValidationIssue is a schema from the API document
+type ValidationIssue struct {
+	Description string `json:"description,omitempty"`
+	Field       string `json:"field,omitempty"`
+	Severity    string `json:"severity,omitempty"`
+}
+
+// ValidationSuggestion is a schema from the API document
+type ValidationSuggestion struct {
+	CurrentValue   string `json:"current_value,omitempty"`
+	Field          string `json:"field,omitempty"`
+	Reason         string `json:"reason,omitempty"`
+	SuggestedValue string `json:"suggested_value,omitempty"`
+}
+
+// AIBatchesResponse is a schema from the API document
+type AIBatchesResponse struct {
+	Batches []*AIBatch `json:"batches,omitempty"`
+}
+
+// BatchDeleteRequest is a schema from the API document
+type BatchDeleteRequest struct {
+	TrackIDs []string `json:"track_ids"`
+}
+
+// BatchDeleteResponse is a schema from the API document
+type BatchDeleteResponse struct {
+	Deleted int                  `json:"deleted,omitempty"`
+	DryRun  bool                 `json:"dry_run,omitempty"`
+	Results []*BatchDeleteResult `json:"results,omitempty"`
+}
+
+// BatchDeleteResult is a schema from the API document
+type BatchDeleteResult struct {
+	Error   string            `json:"error,omitempty"`
+	Status  BatchDeleteStatus `json:"status,omitempty"`
+	TrackID string            `json:"track_id,omitempty"`
+}
+
+// BatchDeleteStatus is a schema from the API document
+type BatchDeleteStatus string
+
+const (
+	BatchDeleteStatusDeleted  BatchDeleteStatus = "deleted"
+	BatchDeleteStatusNotFound BatchDeleteStatus = "not_found"
+	BatchDeleteStatusFailed   BatchDeleteStatus = "failed"
+)
+
+// BootstrapRequest is a schema from the API document
+type BootstrapRequest struct {
+	AdminEmail    string `json:"admin_email"`
+	AdminName     string `json:"admin_name,omitempty"`
+	AdminPassword string `json:"admin_password"`
+	LabelID       string `json:"label_id,omitempty"`
+	LabelName     string `json:"label_name,omitempty"`
+}
+
+// ConflictResponse is a schema from the API document
+type ConflictResponse struct {
+	Conflicts      []*FieldChange `json:"conflicts,omitempty"`
+	CurrentVersion int            `json:"current_version,omitempty"`
+	Error          *ErrorBody     `json:"error,omitempty"`
+}
+
+// CustomFieldsResponse is a schema from the API document
+type CustomFieldsResponse struct {
+	Fields []*CustomFieldDefinition `json:"fields,omitempty"`
+}
+
+// DeliveriesResponse is a schema from the API document
+type DeliveriesResponse struct {
+	Deliveries []*Delivery `json:"deliveries,omitempty"`
+	Limit      int         `json:"limit,omitempty"`
+	Page       int         `json:"page,omitempty"`
+}
+
+// DuplicatesResponse is a schema from the API document
+type DuplicatesResponse struct {
+	Duplicates []*DuplicateCandidate `json:"duplicates,omitempty"`
+	TrackID    string                `json:"track_id,omitempty"`
+}
+
+// EmbeddingRequest is a schema from the API document
+type EmbeddingRequest struct {
+	Model  string    `json:"model,omitempty"`
+	Vector []float64 `json:"vector,omitempty"`
+}
+
+// ErrorBody is a schema from the API document
+type ErrorBody struct {
+	Code      string        `json:"code,omitempty"`
+	Details   string        `json:"details,omitempty"`
+	Fields    []*ErrorField `json:"fields,omitempty"`
+	Message   string        `json:"message,omitempty"`
+	RequestID string        `json:"request_id,omitempty"`
+	TraceID   string        `json:"trace_id,omitempty"`
+	Type      string        `json:"type,omitempty"`
+}
+
+// ErrorField is a schema from the API document
+type ErrorField struct {
+	Code    string `json:"code,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ErrorResponse is a schema from the API document
+type ErrorResponse struct {
+	Error *ErrorBody `json:"error,omitempty"`
+}
+
+// ExportRequest is a schema from the API document
+type ExportRequest struct {
+	Format   string   `json:"format,omitempty"`
+	TrackIDs []string `json:"track_ids"`
+}
+
+// ExportResponse is a schema from the API document
+type ExportResponse struct {
+	Data   interface{} `json:"data,omitempty"`
+	Format string      `json:"format,omitempty"`
+}
+
+// ForceAIProviderRequest is a schema from the API document
+type ForceAIProviderRequest struct {
+	Provider AIProvider `json:"provider"`
+}
+
+// GoldenTracksResponse is a schema from the API document
+type GoldenTracksResponse struct {
+	Tracks []*GoldenTrack `json:"tracks,omitempty"`
+}
+
+// HarmonicMixResponse is a schema from the API document
+type HarmonicMixResponse struct {
+	BPM     float64          `json:"bpm,omitempty"`
+	Camelot string           `json:"camelot,omitempty"`
+	Key     string           `json:"key,omitempty"`
+	OpenKey string           `json:"open_key,omitempty"`
+	TrackID string           `json:"track_id,omitempty"`
+	Tracks  []*HarmonicMatch `json:"tracks,omitempty"`
+}
+
+// ImportMappingsResponse is a schema from the API document
+type ImportMappingsResponse struct {
+	Mappings []*ImportMapping `json:"mappings,omitempty"`
+}
+
+// ImportPreview is a schema from the API document
+type ImportPreview struct {
+	DryRun     bool                  `json:"dry_run,omitempty"`
+	Duplicates []*DuplicatesResponse `json:"duplicates,omitempty"`
+	Errors     []string              `json:"errors,omitempty"`
+	Tracks     []*Track              `json:"tracks,omitempty"`
+}
+
+// LabelAIConfigRequest is a schema from the API document
+type LabelAIConfigRequest struct {
+	APIKey        string     `json:"api_key,omitempty"`
+	MinConfidence float64    `json:"min_confidence,omitempty"`
+	Model         string     `json:"model,omitempty"`
+	Provider      AIProvider `json:"provider,omitempty"`
+}
+
+// LabelAIConfigsResponse is a schema from the API document
+type LabelAIConfigsResponse struct {
+	Configs []*LabelAIConfig `json:"configs,omitempty"`
+}
+
+// ListResponse is a schema from the API document
+type ListResponse struct {
+	Limit  int      `json:"limit,omitempty"`
+	Page   int      `json:"page,omitempty"`
+	Tracks []*Track `json:"tracks,omitempty"`
+}
+
+// PlayCountsResponse is a schema from the API document
+type PlayCountsResponse struct {
+	Limit      int          `json:"limit,omitempty"`
+	Page       int          `json:"page,omitempty"`
+	PlayCounts []*PlayCount `json:"play_counts,omitempty"`
+}
+
+// ProvenanceResponse is a schema from the API document
+type ProvenanceResponse struct {
+	Fields  []*FieldProvenanceView `json:"fields,omitempty"`
+	TrackID string                 `json:"track_id,omitempty"`
+	Version int                    `json:"version,omitempty"`
+}
+
+// PublicAPIKeysResponse is a schema from the API document
+type PublicAPIKeysResponse struct {
+	Keys  []*PublicAPIKey  `json:"keys,omitempty"`
+	Tiers []*PublicAPITier `json:"tiers,omitempty"`
+}
+
+// SearchQuery is a schema from the API document
+type SearchQuery struct {
+	Album        string                 `json:"album,omitempty"`
+	Artist       string                 `json:"artist,omitempty"`
+	CreatedFrom  time.Time              `json:"created_from,omitempty"`
+	CreatedTo    time.Time              `json:"created_to,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+	Filter       *SearchFilter          `json:"filter,omitempty"`
+	Genre        string                 `json:"genre,omitempty"`
+	ISRC         string                 `json:"isrc,omitempty"`
+	ISWC         string                 `json:"iswc,omitempty"`
+	Label        string                 `json:"label,omitempty"`
+	LabelID      string                 `json:"label_id,omitempty"`
+	NeedsReview  bool                   `json:"needs_review,omitempty"`
+	Title        string                 `json:"title,omitempty"`
+}
+
+// SimilarTracksResponse is a schema from the API document
+type SimilarTracksResponse struct {
+	TrackID string          `json:"track_id,omitempty"`
+	Tracks  []*SimilarTrack `json:"tracks,omitempty"`
+}
+
+// SubmitAIBatchRequest is a schema from the API document
+type SubmitAIBatchRequest struct {
+	Provider AIProvider `json:"provider,omitempty"`
+	TrackIDs []string   `json:"track_ids"`
+}
+
+// TagRenameRequest is a schema from the API document
+type TagRenameRequest struct {
+	Name string `json:"name"`
+}
+
+// TagRulesResponse is a schema from the API document
+type TagRulesResponse struct {
+	Rules []*TagRule `json:"rules,omitempty"`
+}
+
+// TagUpdateRequest is a schema from the API document
+type TagUpdateRequest struct {
+	Description string `json:"description,omitempty"`
+}
+
+// TagsResponse is a schema from the API document
+type TagsResponse struct {
+	Tags []*Tag `json:"tags,omitempty"`
+}
+
+// TrackMergeRequest is a schema from the API document
+type TrackMergeRequest struct {
+	Fields *MergeRules `json:"fields,omitempty"`
+}
+
+// TrackMergeResponse is a schema from the API document
+type TrackMergeResponse struct {
+	MovedAudio   bool             `json:"moved_audio,omitempty"`
+	RedirectFrom string           `json:"redirect_from,omitempty"`
+	References   map[string]int64 `json:"references,omitempty"`
+	Track        interface{}      `json:"track,omitempty"`
+}
+
+// TransitionRequest is a schema from the API document
+type TransitionRequest struct {
+	Message string      `json:"message,omitempty"`
+	Status  TrackStatus `json:"status"`
+}
+
+// TransitionsResponse is a schema from the API document
+type TransitionsResponse struct {
+	Next    []TrackStatus `json:"next,omitempty"`
+	Status  TrackStatus   `json:"status,omitempty"`
+	TrackID string        `json:"track_id,omitempty"`
+	Version int           `json:"version,omitempty"`
+}
+
+// UploadURLRequest is a schema from the API document
+type UploadURLRequest struct {
+	Album    string `json:"album,omitempty"`
+	Artist   string `json:"artist"`
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+	Title    string `json:"title"`
+}
+
+// UploadURLResponse is a schema from the API document
+type UploadURLResponse struct {
+	Track  *Track        `json:"track,omitempty"`
+	Upload *SignedUpload `json:"upload,omitempty"`
+}
+
+// ValidationResponse is a schema from the API document
+type ValidationResponse struct {
+	Errors []string `json:"errors,omitempty"`
+	Valid  bool     `json:"valid,omitempty"`
+}
+
+// ListBatches calls GET /admin/ai/batches
+//
+// List AI batches
+func (c *Client) ListBatches(ctx context.Context) (*AIBatchesResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *AIBatchesResponse
+	if err := c.do(ctx, request{method: "GET", path: "/admin/ai/batches", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// SubmitBatch calls POST /admin/ai/batches
+//
+// Submit AI batch
+func (c *Client) SubmitBatch(ctx context.Context, body *SubmitAIBatchRequest) (*AIBatch, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *AIBatch
+	if err := c.do(ctx, request{method: "POST", path: "/admin/ai/batches", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// CancelBatch calls DELETE /admin/ai/batches/{id}
+//
+// Cancel AI batch
+func (c *Client) CancelBatch(ctx context.Context, id string) (*AIBatch, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *AIBatch
+	if err := c.do(ctx, request{method: "DELETE", path: "/admin/ai/batches/" + url.PathEscape(id), query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetBatch calls GET /admin/ai/batches/{id}
+//
+// Get AI batch
+func (c *Client) GetBatch(ctx context.Context, id string) (*AIBatch, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *AIBatch
+	if err := c.do(ctx, request{method: "GET", path: "/admin/ai/batches/" + url.PathEscape(id), query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// RunEvaluation calls POST /admin/ai/evaluations
+//
+// Run evaluation
+func (c *Client) RunEvaluation(ctx context.Context) (*EvaluationReport, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *EvaluationReport
+	if err := c.do(ctx, request{method: "POST", path: "/admin/ai/evaluations", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetLatestEvaluation calls GET /admin/ai/evaluations/latest
+//
+// Get latest evaluation
+func (c *Client) GetLatestEvaluation(ctx context.Context) (*EvaluationReport, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *EvaluationReport
+	if err := c.do(ctx, request{method: "GET", path: "/admin/ai/evaluations/latest", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListGoldenTracks calls GET /admin/ai/golden-tracks
+//
+// List golden tracks
+func (c *Client) ListGoldenTracks(ctx context.Context) (*GoldenTracksResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *GoldenTracksResponse
+	if err := c.do(ctx, request{method: "GET", path: "/admin/ai/golden-tracks", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// DeleteGoldenTrack calls DELETE /admin/ai/golden-tracks/{track_id}
+//
+// Delete golden track
+func (c *Client) DeleteGoldenTrack(ctx context.Context, trackID string) error {
+	q := url.Values{}
+	h := http.Header{}
+	return c.do(ctx, request{method: "DELETE", path: "/admin/ai/golden-tracks/" + url.PathEscape(trackID), query: q, header: h, body: nil, contentType: ""}, nil)
+}
+
+// SaveGoldenTrack calls PUT /admin/ai/golden-tracks/{track_id}
+//
+// Save golden track
+func (c *Client) SaveGoldenTrack(ctx context.Context, trackID string, body *GoldenTrack) (*GoldenTrack, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *GoldenTrack
+	if err := c.do(ctx, request{method: "PUT", path: "/admin/ai/golden-tracks/" + url.PathEscape(trackID), query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetModelCoverage calls GET /admin/ai/model-coverage
+//
+// Get AI model coverage
+func (c *Client) GetModelCoverage(ctx context.Context) (*AIModelCoverageReport, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *AIModelCoverageReport
+	if err := c.do(ctx, request{method: "GET", path: "/admin/ai/model-coverage", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// CancelReenrichment calls DELETE /admin/ai/reenrichment
+//
+// Cancel re-enrichment
+func (c *Client) CancelReenrichment(ctx context.Context) (*ReenrichmentRun, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *ReenrichmentRun
+	if err := c.do(ctx, request{method: "DELETE", path: "/admin/ai/reenrichment", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetReenrichment calls GET /admin/ai/reenrichment
+//
+// Get re-enrichment
+func (c *Client) GetReenrichment(ctx context.Context) (*ReenrichmentRun, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *ReenrichmentRun
+	if err := c.do(ctx, request{method: "GET", path: "/admin/ai/reenrichment", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// StartReenrichment calls POST /admin/ai/reenrichment
+//
+// Start re-enrichment
+func (c *Client) StartReenrichment(ctx context.Context) (*ReenrichmentRun, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *ReenrichmentRun
+	if err := c.do(ctx, request{method: "POST", path: "/admin/ai/reenrichment", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetRouting calls GET /admin/ai/routing
+//
+// Get AI routing
+func (c *Client) GetRouting(ctx context.Context) (*AIRouting, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *AIRouting
+	if err := c.do(ctx, request{method: "GET", path: "/admin/ai/routing", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ClearForcedProvider calls DELETE /admin/ai/routing/forced
+//
+// Clear forced AI provider
+func (c *Client) ClearForcedProvider(ctx context.Context) (*AIRouting, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *AIRouting
+	if err := c.do(ctx, request{method: "DELETE", path: "/admin/ai/routing/forced", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ForceProvider calls PUT /admin/ai/routing/forced
+//
+// Force AI provider
+func (c *Client) ForceProvider(ctx context.Context, body *ForceAIProviderRequest) (*AIRouting, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *AIRouting
+	if err := c.do(ctx, request{method: "PUT", path: "/admin/ai/routing/forced", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// CheckHealth calls POST /admin/ai/routing/health-check
+//
+// Check AI provider health
+func (c *Client) CheckHealth(ctx context.Context) (*AIRouting, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *AIRouting
+	if err := c.do(ctx, request{method: "POST", path: "/admin/ai/routing/health-check", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ExportConfigParams holds the optional parameters of ExportConfig
+type ExportConfigParams struct {
+	Label *[]string
+}
+
+// ExportConfig calls GET /admin/config/export
+//
+// Export configuration
+func (c *Client) ExportConfig(ctx context.Context, params *ExportConfigParams) (*ConfigBundle, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "label", params.Label)
+	}
+	var out *ConfigBundle
+	if err := c.do(ctx, request{method: "GET", path: "/admin/config/export", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ImportConfigParams holds the optional parameters of ImportConfig
+type ImportConfigParams struct {
+	DryRun *bool
+}
+
+// ImportConfig calls POST /admin/config/import
+//
+// Import configuration
+func (c *Client) ImportConfig(ctx context.Context, body *ConfigBundle, params *ImportConfigParams) (*ConfigImportResult, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "dry_run", params.DryRun)
+	}
+	var out *ConfigImportResult
+	if err := c.do(ctx, request{method: "POST", path: "/admin/config/import", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// PurgeDeadLetters calls DELETE /admin/dead-letters/{topic}
+//
+// Purge dead letters
+func (c *Client) PurgeDeadLetters(ctx context.Context, topic string) error {
+	q := url.Values{}
+	h := http.Header{}
+	return c.do(ctx, request{method: "DELETE", path: "/admin/dead-letters/" + url.PathEscape(topic), query: q, header: h, body: nil, contentType: ""}, nil)
+}
+
+// ListDeadLettersParams holds the optional parameters of ListDeadLetters
+type ListDeadLettersParams struct {
+	Offset *int
+	Limit  *int
+}
+
+// ListDeadLetters calls GET /admin/dead-letters/{topic}
+//
+// List dead letters
+func (c *Client) ListDeadLetters(ctx context.Context, topic string, params *ListDeadLettersParams) (*DeadLetterPage, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "offset", params.Offset)
+		setParam(q, "limit", params.Limit)
+	}
+	var out *DeadLetterPage
+	if err := c.do(ctx, request{method: "GET", path: "/admin/dead-letters/" + url.PathEscape(topic), query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ReplayDeadLetters calls POST /admin/dead-letters/{topic}/replay
+//
+// Replay dead letters
+func (c *Client) ReplayDeadLetters(ctx context.Context, topic string, body *DeadLetterReplayRequest) (*DeadLetterReplayReport, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *DeadLetterReplayReport
+	if err := c.do(ctx, request{method: "POST", path: "/admin/dead-letters/" + url.PathEscape(topic) + "/replay", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetDeadLetter calls GET /admin/dead-letters/{topic}/{id}
+//
+// Get dead letter
+func (c *Client) GetDeadLetter(ctx context.Context, topic string, id string) (*Message, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *Message
+	if err := c.do(ctx, request{method: "GET", path: "/admin/dead-letters/" + url.PathEscape(topic) + "/" + url.PathEscape(id), query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ReplayDeadLetter calls POST /admin/dead-letters/{topic}/{id}/replay
+//
+// Replay dead letter
+func (c *Client) ReplayDeadLetter(ctx context.Context, topic string, id string) error {
+	q := url.Values{}
+	h := http.Header{}
+	return c.do(ctx, request{method: "POST", path: "/admin/dead-letters/" + url.PathEscape(topic) + "/" + url.PathEscape(id) + "/replay", query: q, header: h, body: nil, contentType: ""}, nil)
+}
+
+// ListJobHistoryParams holds the optional parameters of ListJobHistory
+type ListJobHistoryParams struct {
+	Type   *string
+	Status *string
+	From   *string
+	To     *string
+	Offset *int
+	Limit  *int
+}
+
+// ListJobHistory calls GET /admin/jobs/history
+//
+// List job history
+func (c *Client) ListJobHistory(ctx context.Context, params *ListJobHistoryParams) (*JobHistoryPage, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "type", params.Type)
+		setParam(q, "status", params.Status)
+		setParam(q, "from", params.From)
+		setParam(q, "to", params.To)
+		setParam(q, "offset", params.Offset)
+		setParam(q, "limit", params.Limit)
+	}
+	var out *JobHistoryPage
+	if err := c.do(ctx, request{method: "GET", path: "/admin/jobs/history", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetJobHistoryReportParams holds the optional parameters of GetJobHistoryReport
+type GetJobHistoryReportParams struct {
+	From *string
+	To   *string
+}
+
+// GetJobHistoryReport calls GET /admin/jobs/history/report
+//
+// Get job history report
+func (c *Client) GetJobHistoryReport(ctx context.Context, params *GetJobHistoryReportParams) (*JobHistoryReport, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "from", params.From)
+		setParam(q, "to", params.To)
+	}
+	var out *JobHistoryReport
+	if err := c.do(ctx, request{method: "GET", path: "/admin/jobs/history/report", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListLabelAIConfigs calls GET /admin/labels/ai-configs
+//
+// List label AI configurations
+func (c *Client) ListLabelAIConfigs(ctx context.Context) (*LabelAIConfigsResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *LabelAIConfigsResponse
+	if err := c.do(ctx, request{method: "GET", path: "/admin/labels/ai-configs", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// DeleteLabelAIConfig calls DELETE /admin/labels/{label_id}/ai-config
+//
+// Delete label AI configuration
+func (c *Client) DeleteLabelAIConfig(ctx context.Context, labelID string) error {
+	q := url.Values{}
+	h := http.Header{}
+	return c.do(ctx, request{method: "DELETE", path: "/admin/labels/" + url.PathEscape(labelID) + "/ai-config", query: q, header: h, body: nil, contentType: ""}, nil)
+}
+
+// GetLabelAIConfig calls GET /admin/labels/{label_id}/ai-config
+//
+// Get label AI configuration
+func (c *Client) GetLabelAIConfig(ctx context.Context, labelID string) (*LabelAIConfig, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *LabelAIConfig
+	if err := c.do(ctx, request{method: "GET", path: "/admin/labels/" + url.PathEscape(labelID) + "/ai-config", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// SetLabelAIConfig calls PUT /admin/labels/{label_id}/ai-config
+//
+// Set label AI configuration
+func (c *Client) SetLabelAIConfig(ctx context.Context, labelID string, body *LabelAIConfigRequest) (*LabelAIConfig, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *LabelAIConfig
+	if err := c.do(ctx, request{method: "PUT", path: "/admin/labels/" + url.PathEscape(labelID) + "/ai-config", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetMaintenanceStatus calls GET /admin/maintenance
+//
+// Get maintenance status
+func (c *Client) GetMaintenanceStatus(ctx context.Context) (*MaintenanceStatus, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *MaintenanceStatus
+	if err := c.do(ctx, request{method: "GET", path: "/admin/maintenance", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetRuntimeConfig calls GET /admin/runtime-config
+//
+// Get runtime settings
+func (c *Client) GetRuntimeConfig(ctx context.Context) (*RuntimeSettings, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *RuntimeSettings
+	if err := c.do(ctx, request{method: "GET", path: "/admin/runtime-config", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// UpdateRuntimeConfig calls PATCH /admin/runtime-config
+//
+// Update runtime settings
+func (c *Client) UpdateRuntimeConfig(ctx context.Context, body *RuntimeSettingsPatch) (*RuntimeSettings, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *RuntimeSettings
+	if err := c.do(ctx, request{method: "PATCH", path: "/admin/runtime-config", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetSystemStats calls GET /admin/stats
+//
+// Get system stats
+func (c *Client) GetSystemStats(ctx context.Context) (*SystemStats, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *SystemStats
+	if err := c.do(ctx, request{method: "GET", path: "/admin/stats", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// UploadAudio calls POST /audio/upload
+//
+// Upload audio file
+func (c *Client) UploadAudio(ctx context.Context, body io.Reader, contentType string) (*Track, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *Track
+	if err := c.do(ctx, request{method: "POST", path: "/audio/upload", query: q, header: h, body: body, contentType: contentType}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetAudioURL calls GET /audio/{id}
+//
+// Get audio download URL
+func (c *Client) GetAudioURL(ctx context.Context, id string) (map[string]string, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out map[string]string
+	if err := c.do(ctx, request{method: "GET", path: "/audio/" + url.PathEscape(id), query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// BootstrapParams holds the optional parameters of Bootstrap
+type BootstrapParams struct {
+	XSetupToken *string
+}
+
+// Bootstrap calls POST /bootstrap
+//
+// Bootstrap environment
+func (c *Client) Bootstrap(ctx context.Context, body *BootstrapRequest, params *BootstrapParams) (*BootstrapResult, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setHeader(h, "X-Setup-Token", params.XSetupToken)
+	}
+	var out *BootstrapResult
+	if err := c.do(ctx, request{method: "POST", path: "/bootstrap", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ExportERN calls POST /ddex/export
+//
+// Export DDEX ERN
+func (c *Client) ExportERN(ctx context.Context) ([]byte, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out []byte
+	err := c.do(ctx, request{method: "POST", path: "/ddex/export", query: q, header: h, body: nil, contentType: ""}, &out)
+	return out, err
+}
+
+// ImportERNParams holds the optional parameters of ImportERN
+type ImportERNParams struct {
+	DryRun *bool
+}
+
+// ImportERN calls POST /ddex/import
+//
+// Import DDEX ERN
+func (c *Client) ImportERN(ctx context.Context, body io.Reader, contentType string, params *ImportERNParams) (*ImportPreview, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "dry_run", params.DryRun)
+	}
+	var out *ImportPreview
+	if err := c.do(ctx, request{method: "POST", path: "/ddex/import", query: q, header: h, body: body, contentType: contentType}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ValidateERN calls POST /ddex/validate
+//
+// Validate DDEX ERN
+func (c *Client) ValidateERN(ctx context.Context, body io.Reader, contentType string) (*ValidationResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *ValidationResponse
+	if err := c.do(ctx, request{method: "POST", path: "/ddex/validate", query: q, header: h, body: body, contentType: contentType}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListDeliveriesParams holds the optional parameters of ListDeliveries
+type ListDeliveriesParams struct {
+	Dsp       *string
+	TrackID   *string
+	ReleaseID *string
+	Kind      *string
+	Status    *string
+	Page      *int
+	Limit     *int
+}
+
+// ListDeliveries calls GET /deliveries
+//
+// List deliveries
+func (c *Client) ListDeliveries(ctx context.Context, params *ListDeliveriesParams) (*DeliveriesResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "dsp", params.Dsp)
+		setParam(q, "track_id", params.TrackID)
+		setParam(q, "release_id", params.ReleaseID)
+		setParam(q, "kind", params.Kind)
+		setParam(q, "status", params.Status)
+		setParam(q, "page", params.Page)
+		setParam(q, "limit", params.Limit)
+	}
+	var out *DeliveriesResponse
+	if err := c.do(ctx, request{method: "GET", path: "/deliveries", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListDeliveriesPager walks the pages of ListDeliveries from params.Page on
+func (c *Client) ListDeliveriesPager(params *ListDeliveriesParams) *Pager[*Delivery] {
+	var p ListDeliveriesParams
+	if params != nil {
+		p = *params
+	}
+	return newPager(p.Page, func(ctx context.Context, page int) ([]*Delivery, int, error) {
+		p.Page = &page
+		out, err := c.ListDeliveries(ctx, &p)
+		if err != nil || out == nil {
+			return nil, 0, err
+		}
+		return out.Deliveries, out.Limit, nil
+	})
+}
+
+// RecordDelivery calls POST /deliveries
+//
+// Record a delivery
+func (c *Client) RecordDelivery(ctx context.Context, body map[string]interface{}) ([]*Delivery, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out []*Delivery
+	if err := c.do(ctx, request{method: "POST", path: "/deliveries", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// AcknowledgeDelivery calls POST /deliveries/acknowledgements
+//
+// Ingest a delivery acknowledgement
+func (c *Client) AcknowledgeDelivery(ctx context.Context, body string) (*AcknowledgementResult, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *AcknowledgementResult
+	if err := c.do(ctx, request{method: "POST", path: "/deliveries/acknowledgements", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// RequestTakedownParams holds the optional parameters of RequestTakedown
+type RequestTakedownParams struct {
+	DryRun *bool
+}
+
+// RequestTakedown calls POST /deliveries/takedowns
+//
+// Request a takedown or territory block
+func (c *Client) RequestTakedown(ctx context.Context, body map[string]interface{}, params *RequestTakedownParams) (map[string]interface{}, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "dry_run", params.DryRun)
+	}
+	var out map[string]interface{}
+	if err := c.do(ctx, request{method: "POST", path: "/deliveries/takedowns", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListCustomFields calls GET /labels/{label_id}/custom-fields
+//
+// List custom fields
+func (c *Client) ListCustomFields(ctx context.Context, labelID string) (*CustomFieldsResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *CustomFieldsResponse
+	if err := c.do(ctx, request{method: "GET", path: "/labels/" + url.PathEscape(labelID) + "/custom-fields", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// CreateCustomField calls POST /labels/{label_id}/custom-fields
+//
+// Create custom field
+func (c *Client) CreateCustomField(ctx context.Context, labelID string, body *CustomFieldDefinition) (*CustomFieldDefinition, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *CustomFieldDefinition
+	if err := c.do(ctx, request{method: "POST", path: "/labels/" + url.PathEscape(labelID) + "/custom-fields", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// DeleteCustomField calls DELETE /labels/{label_id}/custom-fields/{name}
+//
+// Delete custom field
+func (c *Client) DeleteCustomField(ctx context.Context, labelID string, name string) error {
+	q := url.Values{}
+	h := http.Header{}
+	return c.do(ctx, request{method: "DELETE", path: "/labels/" + url.PathEscape(labelID) + "/custom-fields/" + url.PathEscape(name), query: q, header: h, body: nil, contentType: ""}, nil)
+}
+
+// GetCustomField calls GET /labels/{label_id}/custom-fields/{name}
+//
+// Get custom field
+func (c *Client) GetCustomField(ctx context.Context, labelID string, name string) (*CustomFieldDefinition, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *CustomFieldDefinition
+	if err := c.do(ctx, request{method: "GET", path: "/labels/" + url.PathEscape(labelID) + "/custom-fields/" + url.PathEscape(name), query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// UpdateCustomField calls PUT /labels/{label_id}/custom-fields/{name}
+//
+// Replace custom field
+func (c *Client) UpdateCustomField(ctx context.Context, labelID string, name string, body *CustomFieldDefinition) (*CustomFieldDefinition, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *CustomFieldDefinition
+	if err := c.do(ctx, request{method: "PUT", path: "/labels/" + url.PathEscape(labelID) + "/custom-fields/" + url.PathEscape(name), query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListImportMappings calls GET /labels/{label_id}/import-mappings
+//
+// List import mappings
+func (c *Client) ListImportMappings(ctx context.Context, labelID string) (*ImportMappingsResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *ImportMappingsResponse
+	if err := c.do(ctx, request{method: "GET", path: "/labels/" + url.PathEscape(labelID) + "/import-mappings", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// CreateImportMapping calls POST /labels/{label_id}/import-mappings
+//
+// Create import mapping
+func (c *Client) CreateImportMapping(ctx context.Context, labelID string, body *ImportMapping) (*ImportMapping, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *ImportMapping
+	if err := c.do(ctx, request{method: "POST", path: "/labels/" + url.PathEscape(labelID) + "/import-mappings", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// DeleteImportMapping calls DELETE /labels/{label_id}/import-mappings/{id}
+//
+// Delete import mapping
+func (c *Client) DeleteImportMapping(ctx context.Context, labelID string, id string) error {
+	q := url.Values{}
+	h := http.Header{}
+	return c.do(ctx, request{method: "DELETE", path: "/labels/" + url.PathEscape(labelID) + "/import-mappings/" + url.PathEscape(id), query: q, header: h, body: nil, contentType: ""}, nil)
+}
+
+// GetImportMapping calls GET /labels/{label_id}/import-mappings/{id}
+//
+// Get import mapping
+func (c *Client) GetImportMapping(ctx context.Context, labelID string, id string) (*ImportMapping, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *ImportMapping
+	if err := c.do(ctx, request{method: "GET", path: "/labels/" + url.PathEscape(labelID) + "/import-mappings/" + url.PathEscape(id), query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// UpdateImportMapping calls PUT /labels/{label_id}/import-mappings/{id}
+//
+// Replace import mapping
+func (c *Client) UpdateImportMapping(ctx context.Context, labelID string, id string, body *ImportMapping) (*ImportMapping, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *ImportMapping
+	if err := c.do(ctx, request{method: "PUT", path: "/labels/" + url.PathEscape(labelID) + "/import-mappings/" + url.PathEscape(id), query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ImportCSVParams holds the optional parameters of ImportCSV
+type ImportCSVParams struct {
+	Mapping *string
+	DryRun  *bool
+}
+
+// ImportCSV calls POST /labels/{label_id}/imports
+//
+// Import CSV
+func (c *Client) ImportCSV(ctx context.Context, labelID string, body string, params *ImportCSVParams) (map[string]interface{}, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "mapping", params.Mapping)
+		setParam(q, "dry_run", params.DryRun)
+	}
+	var out map[string]interface{}
+	if err := c.do(ctx, request{method: "POST", path: "/labels/" + url.PathEscape(labelID) + "/imports", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetLabelStats calls GET /labels/{label_id}/stats
+//
+// Get label statistics
+func (c *Client) GetLabelStats(ctx context.Context, labelID string) (*LabelStats, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *LabelStats
+	if err := c.do(ctx, request{method: "GET", path: "/labels/" + url.PathEscape(labelID) + "/stats", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListKeysParams holds the optional parameters of ListKeys
+type ListKeysParams struct {
+	LabelID *string
+}
+
+// ListKeys calls GET /public-api-keys
+//
+// List public API keys
+func (c *Client) ListKeys(ctx context.Context, params *ListKeysParams) (*PublicAPIKeysResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "label_id", params.LabelID)
+	}
+	var out *PublicAPIKeysResponse
+	if err := c.do(ctx, request{method: "GET", path: "/public-api-keys", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// CreateKey calls POST /public-api-keys
+//
+// Create public API key
+func (c *Client) CreateKey(ctx context.Context, body map[string]interface{}) (map[string]interface{}, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out map[string]interface{}
+	if err := c.do(ctx, request{method: "POST", path: "/public-api-keys", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// RevokeKey calls DELETE /public-api-keys/{id}
+//
+// Revoke public API key
+func (c *Client) RevokeKey(ctx context.Context, id string) error {
+	q := url.Values{}
+	h := http.Header{}
+	return c.do(ctx, request{method: "DELETE", path: "/public-api-keys/" + url.PathEscape(id), query: q, header: h, body: nil, contentType: ""}, nil)
+}
+
+// ListPlayCountsParams holds the optional parameters of ListPlayCounts
+type ListPlayCountsParams struct {
+	TrackID   *string
+	Dsp       *string
+	Territory *string
+	From      *string
+	To        *string
+	Page      *int
+	Limit     *int
+}
+
+// ListPlayCounts calls GET /royalties/plays
+//
+// List play counts
+func (c *Client) ListPlayCounts(ctx context.Context, params *ListPlayCountsParams) (*PlayCountsResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "track_id", params.TrackID)
+		setParam(q, "dsp", params.Dsp)
+		setParam(q, "territory", params.Territory)
+		setParam(q, "from", params.From)
+		setParam(q, "to", params.To)
+		setParam(q, "page", params.Page)
+		setParam(q, "limit", params.Limit)
+	}
+	var out *PlayCountsResponse
+	if err := c.do(ctx, request{method: "GET", path: "/royalties/plays", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListPlayCountsPager walks the pages of ListPlayCounts from params.Page on
+func (c *Client) ListPlayCountsPager(params *ListPlayCountsParams) *Pager[*PlayCount] {
+	var p ListPlayCountsParams
+	if params != nil {
+		p = *params
+	}
+	return newPager(p.Page, func(ctx context.Context, page int) ([]*PlayCount, int, error) {
+		p.Page = &page
+		out, err := c.ListPlayCounts(ctx, &p)
+		if err != nil || out == nil {
+			return nil, 0, err
+		}
+		return out.PlayCounts, out.Limit, nil
+	})
+}
+
+// IngestSalesReportParams holds the optional parameters of IngestSalesReport
+type IngestSalesReportParams struct {
+	Dsp *string
+}
+
+// IngestSalesReport calls POST /royalties/reports
+//
+// Ingest a usage report
+func (c *Client) IngestSalesReport(ctx context.Context, body string, params *IngestSalesReportParams) (*SalesReport, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "dsp", params.Dsp)
+	}
+	var out *SalesReport
+	if err := c.do(ctx, request{method: "POST", path: "/royalties/reports", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListTagRules calls GET /tag-rules
+//
+// List tagging rules
+func (c *Client) ListTagRules(ctx context.Context) (*TagRulesResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *TagRulesResponse
+	if err := c.do(ctx, request{method: "GET", path: "/tag-rules", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// CreateTagRule calls POST /tag-rules
+//
+// Create tagging rule
+func (c *Client) CreateTagRule(ctx context.Context, body *TagRule) (*TagRule, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *TagRule
+	if err := c.do(ctx, request{method: "POST", path: "/tag-rules", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// DeleteTagRule calls DELETE /tag-rules/{id}
+//
+// Delete tagging rule
+func (c *Client) DeleteTagRule(ctx context.Context, id string) error {
+	q := url.Values{}
+	h := http.Header{}
+	return c.do(ctx, request{method: "DELETE", path: "/tag-rules/" + url.PathEscape(id), query: q, header: h, body: nil, contentType: ""}, nil)
+}
+
+// GetTagRule calls GET /tag-rules/{id}
+//
+// Get tagging rule
+func (c *Client) GetTagRule(ctx context.Context, id string) (*TagRule, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *TagRule
+	if err := c.do(ctx, request{method: "GET", path: "/tag-rules/" + url.PathEscape(id), query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// UpdateTagRule calls PUT /tag-rules/{id}
+//
+// Replace tagging rule
+func (c *Client) UpdateTagRule(ctx context.Context, id string, body *TagRule) (*TagRule, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *TagRule
+	if err := c.do(ctx, request{method: "PUT", path: "/tag-rules/" + url.PathEscape(id), query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListTags calls GET /tags
+//
+// List tags
+func (c *Client) ListTags(ctx context.Context) (*TagsResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *TagsResponse
+	if err := c.do(ctx, request{method: "GET", path: "/tags", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// CreateTag calls POST /tags
+//
+// Create tag
+func (c *Client) CreateTag(ctx context.Context, body *Tag) (*Tag, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *Tag
+	if err := c.do(ctx, request{method: "POST", path: "/tags", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// DeleteTag calls DELETE /tags/{name}
+//
+// Delete tag
+func (c *Client) DeleteTag(ctx context.Context, name string) (map[string]interface{}, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out map[string]interface{}
+	if err := c.do(ctx, request{method: "DELETE", path: "/tags/" + url.PathEscape(name), query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetTag calls GET /tags/{name}
+//
+// Get tag
+func (c *Client) GetTag(ctx context.Context, name string) (*Tag, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *Tag
+	if err := c.do(ctx, request{method: "GET", path: "/tags/" + url.PathEscape(name), query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// UpdateTag calls PUT /tags/{name}
+//
+// Update tag
+func (c *Client) UpdateTag(ctx context.Context, name string, body *TagUpdateRequest) (*Tag, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *Tag
+	if err := c.do(ctx, request{method: "PUT", path: "/tags/" + url.PathEscape(name), query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// MergeTag calls POST /tags/{name}/merge-into/{target}
+//
+// Merge tags
+func (c *Client) MergeTag(ctx context.Context, name string, target string) (map[string]interface{}, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out map[string]interface{}
+	if err := c.do(ctx, request{method: "POST", path: "/tags/" + url.PathEscape(name) + "/merge-into/" + url.PathEscape(target), query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// RenameTag calls POST /tags/{name}/rename
+//
+// Rename tag
+func (c *Client) RenameTag(ctx context.Context, name string, body *TagRenameRequest) (map[string]interface{}, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out map[string]interface{}
+	if err := c.do(ctx, request{method: "POST", path: "/tags/" + url.PathEscape(name) + "/rename", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListTracksParams holds the optional parameters of ListTracks
+type ListTracksParams struct {
+	Page   *int
+	Limit  *int
+	Fields *string
+	Sort   *string
+}
+
+// ListTracks calls GET /tracks
+//
+// List tracks
+func (c *Client) ListTracks(ctx context.Context, params *ListTracksParams) (*ListResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "page", params.Page)
+		setParam(q, "limit", params.Limit)
+		setParam(q, "fields", params.Fields)
+		setParam(q, "sort", params.Sort)
+	}
+	var out *ListResponse
+	if err := c.do(ctx, request{method: "GET", path: "/tracks", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ListTracksPager walks the pages of ListTracks from params.Page on
+func (c *Client) ListTracksPager(params *ListTracksParams) *Pager[*Track] {
+	var p ListTracksParams
+	if params != nil {
+		p = *params
+	}
+	return newPager(p.Page, func(ctx context.Context, page int) ([]*Track, int, error) {
+		p.Page = &page
+		out, err := c.ListTracks(ctx, &p)
+		if err != nil || out == nil {
+			return nil, 0, err
+		}
+		return out.Tracks, out.Limit, nil
+	})
+}
+
+// CreateTrackParams holds the optional parameters of CreateTrack
+type CreateTrackParams struct {
+	IdempotencyKey *string
+}
+
+// CreateTrack calls POST /tracks
+//
+// Create track
+func (c *Client) CreateTrack(ctx context.Context, body *Track, params *CreateTrackParams) (*Track, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setHeader(h, "Idempotency-Key", params.IdempotencyKey)
+	}
+	var out *Track
+	if err := c.do(ctx, request{method: "POST", path: "/tracks", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// BatchDeleteTracksParams holds the optional parameters of BatchDeleteTracks
+type BatchDeleteTracksParams struct {
+	DryRun *bool
+}
+
+// BatchDeleteTracks calls POST /tracks/batch-delete
+//
+// Delete tracks
+func (c *Client) BatchDeleteTracks(ctx context.Context, body *BatchDeleteRequest, params *BatchDeleteTracksParams) (*BatchDeleteResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "dry_run", params.DryRun)
+	}
+	var out *BatchDeleteResponse
+	if err := c.do(ctx, request{method: "POST", path: "/tracks/batch-delete", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// BulkEditParams holds the optional parameters of BulkEdit
+type BulkEditParams struct {
+	DryRun *bool
+}
+
+// BulkEdit calls POST /tracks/bulk-edit
+//
+// Bulk edit tracks
+func (c *Client) BulkEdit(ctx context.Context, body *BulkEditRequest, params *BulkEditParams) (*BulkEditJob, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "dry_run", params.DryRun)
+	}
+	var out *BulkEditJob
+	if err := c.do(ctx, request{method: "POST", path: "/tracks/bulk-edit", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetBulkEditJob calls GET /tracks/bulk-edit/{id}
+//
+// Get bulk edit job
+func (c *Client) GetBulkEditJob(ctx context.Context, id string) (*BulkEditJob, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *BulkEditJob
+	if err := c.do(ctx, request{method: "GET", path: "/tracks/bulk-edit/" + url.PathEscape(id), query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ExportTracksParams holds the optional parameters of ExportTracks
+type ExportTracksParams struct {
+	IdempotencyKey *string
+}
+
+// ExportTracks calls POST /tracks/export
+//
+// Export tracks
+func (c *Client) ExportTracks(ctx context.Context, body *ExportRequest, params *ExportTracksParams) (*ExportResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setHeader(h, "Idempotency-Key", params.IdempotencyKey)
+	}
+	var out *ExportResponse
+	if err := c.do(ctx, request{method: "POST", path: "/tracks/export", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// SearchTracksParams holds the optional parameters of SearchTracks
+type SearchTracksParams struct {
+	Fields *string
+}
+
+// SearchTracks calls POST /tracks/search
+//
+// Search tracks
+func (c *Client) SearchTracks(ctx context.Context, body *SearchQuery, params *SearchTracksParams) ([]*Track, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "fields", params.Fields)
+	}
+	var out []*Track
+	if err := c.do(ctx, request{method: "POST", path: "/tracks/search", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// UploadTrackParams holds the optional parameters of UploadTrack
+type UploadTrackParams struct {
+	IdempotencyKey *string
+}
+
+// UploadTrack calls POST /tracks/upload
+//
+// Upload new track
+func (c *Client) UploadTrack(ctx context.Context, body io.Reader, contentType string, params *UploadTrackParams) (*Track, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setHeader(h, "Idempotency-Key", params.IdempotencyKey)
+	}
+	var out *Track
+	if err := c.do(ctx, request{method: "POST", path: "/tracks/upload", query: q, header: h, body: body, contentType: contentType}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// CreateUploadURLParams holds the optional parameters of CreateUploadURL
+type CreateUploadURLParams struct {
+	IdempotencyKey *string
+}
+
+// CreateUploadURL calls POST /tracks/upload-url
+//
+// Create direct upload URL
+func (c *Client) CreateUploadURL(ctx context.Context, body *UploadURLRequest, params *CreateUploadURLParams) (*UploadURLResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setHeader(h, "Idempotency-Key", params.IdempotencyKey)
+	}
+	var out *UploadURLResponse
+	if err := c.do(ctx, request{method: "POST", path: "/tracks/upload-url", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// DeleteTrack calls DELETE /tracks/{id}
+//
+// Delete track
+func (c *Client) DeleteTrack(ctx context.Context, id string) error {
+	q := url.Values{}
+	h := http.Header{}
+	return c.do(ctx, request{method: "DELETE", path: "/tracks/" + url.PathEscape(id), query: q, header: h, body: nil, contentType: ""}, nil)
+}
+
+// GetTrackParams holds the optional parameters of GetTrack
+type GetTrackParams struct {
+	IfNoneMatch     *string
+	IfModifiedSince *string
+}
+
+// GetTrack calls GET /tracks/{id}
+//
+// Get track
+func (c *Client) GetTrack(ctx context.Context, id string, params *GetTrackParams) (*Track, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setHeader(h, "If-None-Match", params.IfNoneMatch)
+		setHeader(h, "If-Modified-Since", params.IfModifiedSince)
+	}
+	var out *Track
+	if err := c.do(ctx, request{method: "GET", path: "/tracks/" + url.PathEscape(id), query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// PatchTrackParams holds the optional parameters of PatchTrack
+type PatchTrackParams struct {
+	IfMatch *string
+}
+
+// PatchTrack calls PATCH /tracks/{id}
+//
+// Patch track
+func (c *Client) PatchTrack(ctx context.Context, id string, body map[string]interface{}, params *PatchTrackParams) (*Track, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setHeader(h, "If-Match", params.IfMatch)
+	}
+	var out *Track
+	if err := c.do(ctx, request{method: "PATCH", path: "/tracks/" + url.PathEscape(id), query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// UpdateTrackParams holds the optional parameters of UpdateTrack
+type UpdateTrackParams struct {
+	IfMatch *string
+}
+
+// UpdateTrack calls PUT /tracks/{id}
+//
+// Update track
+func (c *Client) UpdateTrack(ctx context.Context, id string, body *Track, params *UpdateTrackParams) (*Track, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setHeader(h, "If-Match", params.IfMatch)
+	}
+	var out *Track
+	if err := c.do(ctx, request{method: "PUT", path: "/tracks/" + url.PathEscape(id), query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetTrackAnalysisParams holds the optional parameters of GetTrackAnalysis
+type GetTrackAnalysisParams struct {
+	Version *string
+}
+
+// GetTrackAnalysis calls GET /tracks/{id}/analysis
+//
+// Get track audio analysis
+func (c *Client) GetTrackAnalysis(ctx context.Context, id string, params *GetTrackAnalysisParams) (*TrackAnalysis, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "version", params.Version)
+	}
+	var out *TrackAnalysis
+	if err := c.do(ctx, request{method: "GET", path: "/tracks/" + url.PathEscape(id) + "/analysis", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// ConfirmUpload calls POST /tracks/{id}/confirm-upload
+//
+// Confirm direct upload
+func (c *Client) ConfirmUpload(ctx context.Context, id string) (*Track, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *Track
+	if err := c.do(ctx, request{method: "POST", path: "/tracks/" + url.PathEscape(id) + "/confirm-upload", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetTrackDuplicates calls GET /tracks/{id}/duplicates
+//
+// List probable duplicates
+func (c *Client) GetTrackDuplicates(ctx context.Context, id string) (*DuplicatesResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *DuplicatesResponse
+	if err := c.do(ctx, request{method: "GET", path: "/tracks/" + url.PathEscape(id) + "/duplicates", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// PutTrackEmbedding calls PUT /tracks/{id}/embedding
+//
+// Upload track embedding
+func (c *Client) PutTrackEmbedding(ctx context.Context, id string, body *EmbeddingRequest) (*TrackEmbedding, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *TrackEmbedding
+	if err := c.do(ctx, request{method: "PUT", path: "/tracks/" + url.PathEscape(id) + "/embedding", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetHarmonicMixesParams holds the optional parameters of GetHarmonicMixes
+type GetHarmonicMixesParams struct {
+	Limit *int
+}
+
+// GetHarmonicMixes calls GET /tracks/{id}/harmonic-mixes
+//
+// List harmonically compatible tracks
+func (c *Client) GetHarmonicMixes(ctx context.Context, id string, params *GetHarmonicMixesParams) (*HarmonicMixResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "limit", params.Limit)
+	}
+	var out *HarmonicMixResponse
+	if err := c.do(ctx, request{method: "GET", path: "/tracks/" + url.PathEscape(id) + "/harmonic-mixes", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetIntegrity calls GET /tracks/{id}/integrity
+//
+// Check track file integrity
+func (c *Client) GetIntegrity(ctx context.Context, id string) (*IntegrityReport, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *IntegrityReport
+	if err := c.do(ctx, request{method: "GET", path: "/tracks/" + url.PathEscape(id) + "/integrity", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// MergeTrack calls POST /tracks/{id}/merge-into/{target_id}
+//
+// Merge tracks
+func (c *Client) MergeTrack(ctx context.Context, id string, targetID string, body *TrackMergeRequest) (*TrackMergeResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *TrackMergeResponse
+	if err := c.do(ctx, request{method: "POST", path: "/tracks/" + url.PathEscape(id) + "/merge-into/" + url.PathEscape(targetID), query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetTrackProvenance calls GET /tracks/{id}/provenance
+//
+// Get track field provenance
+func (c *Client) GetTrackProvenance(ctx context.Context, id string) (*ProvenanceResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *ProvenanceResponse
+	if err := c.do(ctx, request{method: "GET", path: "/tracks/" + url.PathEscape(id) + "/provenance", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetRestore calls GET /tracks/{id}/restore
+//
+// Get track file archive status
+func (c *Client) GetRestore(ctx context.Context, id string) (*ArchiveStatus, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *ArchiveStatus
+	if err := c.do(ctx, request{method: "GET", path: "/tracks/" + url.PathEscape(id) + "/restore", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// Restore calls POST /tracks/{id}/restore
+//
+// Restore archived track file
+func (c *Client) Restore(ctx context.Context, id string) (*ArchiveStatus, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *ArchiveStatus
+	if err := c.do(ctx, request{method: "POST", path: "/tracks/" + url.PathEscape(id) + "/restore", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetSimilarTracksParams holds the optional parameters of GetSimilarTracks
+type GetSimilarTracksParams struct {
+	Limit *int
+}
+
+// GetSimilarTracks calls GET /tracks/{id}/similar
+//
+// List similar tracks
+func (c *Client) GetSimilarTracks(ctx context.Context, id string, params *GetSimilarTracksParams) (*SimilarTracksResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "limit", params.Limit)
+	}
+	var out *SimilarTracksResponse
+	if err := c.do(ctx, request{method: "GET", path: "/tracks/" + url.PathEscape(id) + "/similar", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// StreamTrackParams holds the optional parameters of StreamTrack
+type StreamTrackParams struct {
+	Range *string
+}
+
+// StreamTrack calls GET /tracks/{id}/stream
+//
+// Stream track audio
+func (c *Client) StreamTrack(ctx context.Context, id string, params *StreamTrackParams) ([]byte, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setHeader(h, "Range", params.Range)
+	}
+	var out []byte
+	err := c.do(ctx, request{method: "GET", path: "/tracks/" + url.PathEscape(id) + "/stream", query: q, header: h, body: nil, contentType: ""}, &out)
+	return out, err
+}
+
+// GetTrackTransitions calls GET /tracks/{id}/transitions
+//
+// List allowed status changes
+func (c *Client) GetTrackTransitions(ctx context.Context, id string) (*TransitionsResponse, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out *TransitionsResponse
+	if err := c.do(ctx, request{method: "GET", path: "/tracks/" + url.PathEscape(id) + "/transitions", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// TransitionTrackParams holds the optional parameters of TransitionTrack
+type TransitionTrackParams struct {
+	IfMatch *string
+}
+
+// TransitionTrack calls POST /tracks/{id}/transitions
+//
+// Change track status
+func (c *Client) TransitionTrack(ctx context.Context, id string, body *TransitionRequest, params *TransitionTrackParams) (*Track, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setHeader(h, "If-Match", params.IfMatch)
+	}
+	var out *Track
+	if err := c.do(ctx, request{method: "POST", path: "/tracks/" + url.PathEscape(id) + "/transitions", query: q, header: h, body: body, contentType: "application/json"}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// GetUsageParams holds the optional parameters of GetUsage
+type GetUsageParams struct {
+	LabelID *string
+	From    *string
+	To      *string
+	Format  *string
+}
+
+// GetUsage calls GET /usage
+//
+// Get usage
+func (c *Client) GetUsage(ctx context.Context, params *GetUsageParams) (*UsageReport, error) {
+	q := url.Values{}
+	h := http.Header{}
+	if params != nil {
+		setParam(q, "label_id", params.LabelID)
+		setParam(q, "from", params.From)
+		setParam(q, "to", params.To)
+		setParam(q, "format", params.Format)
+	}
+	var out *UsageReport
+	if err := c.do(ctx, request{method: "GET", path: "/usage", query: q, header: h, body: nil, contentType: ""}, &out); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// DeleteUser calls DELETE /users/{id}
+//
+// Delete user
+func (c *Client) DeleteUser(ctx context.Context, id string) error {
+	q := url.Values{}
+	h := http.Header{}
+	return c.do(ctx, request{method: "DELETE", path: "/users/" + url.PathEscape(id), query: q, header: h, body: nil, contentType: ""}, nil)
+}
+
+// ExportUserData calls POST /users/{id}/export
+//
+// Export user data
+func (c *Client) ExportUserData(ctx context.Context, id string) ([]byte, error) {
+	q := url.Values{}
+	h := http.Header{}
+	var out []byte
+	err := c.do(ctx, request{method: "POST", path: "/users/" + url.PathEscape(id) + "/export", query: q, header: h, body: nil, contentType: ""}, &out)
+	return out, err
+}
@@ -0,0 +1,24 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListParamsAreRepeated(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, []string{"acme", "indie"}, r.URL.Query()["label"])
+		_, _ = w.Write([]byte(`{"version":1}`))
+	}))
+	defer srv.Close()
+
+	labels := []string{"acme", "indie"}
+	bundle, err := NewClient(srv.URL).ExportConfig(context.Background(), &ExportConfigParams{Label: &labels})
+	require.NoError(t, err)
+	assert.Equal(t, 1, bundle.Version)
+}
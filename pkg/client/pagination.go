@@ -0,0 +1,72 @@
+package client
+
+import "context"
+
+// Pager walks the pages of a paginated list operation. The generated
+// <Operation>Pager methods create one:
+//
+//	pager := c.ListTracksPager(&client.ListTracksParams{Limit: &limit})
+//	for pager.Next(ctx) {
+//		for _, track := range pager.Items() {
+//			...
+//		}
+//	}
+//	if err := pager.Err(); err != nil {
+//		...
+//	}
+type Pager[T any] struct {
+	fetch func(ctx context.Context, page int) ([]T, int, error)
+	page  int
+	items []T
+	err   error
+	done  bool
+}
+
+// newPager creates a pager starting at page start, or the first page when
+// start is nil. fetch returns the items of a page and the page size the API
+// applied; a shorter page is the last one.
+func newPager[T any](start *int, fetch func(ctx context.Context, page int) ([]T, int, error)) *Pager[T] {
+	page := 1
+	if start != nil && *start > 0 {
+		page = *start
+	}
+	return &Pager[T]{fetch: fetch, page: page}
+}
+
+// Next fetches the next page and reports whether it holds any items
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	if p.done || p.err != nil {
+		return false
+	}
+	items, limit, err := p.fetch(ctx, p.page)
+	if err != nil {
+		p.err = err
+		p.items = nil
+		return false
+	}
+	p.page++
+	p.items = items
+	if limit <= 0 || len(items) < limit {
+		p.done = true
+	}
+	return len(items) > 0
+}
+
+// Items returns the items of the current page
+func (p *Pager[T]) Items() []T {
+	return p.items
+}
+
+// Err returns the error that stopped the pager, if any
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// All fetches the remaining pages and returns their items
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	for p.Next(ctx) {
+		all = append(all, p.Items()...)
+	}
+	return all, p.Err()
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTracksPager(t *testing.T) {
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer fresh", r.Header.Get("Authorization"))
+		assert.Equal(t, "mdt_pub_key", r.Header.Get("X-API-Key"))
+		pages = append(pages, r.URL.Query().Get("page"))
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		// The API caps the page size at 2, so the pager must not wait for
+		// a page of the requested size
+		count := map[int]int{1: 2, 2: 2, 3: 1}[page]
+		tracks := make([]*Track, count)
+		for i := range tracks {
+			tracks[i] = &Track{ID: fmt.Sprintf("t%d-%d", page, i)}
+		}
+		_ = json.NewEncoder(w).Encode(ListResponse{Tracks: tracks, Page: page, Limit: 2})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL).WithToken("stale").WithAPIKey("mdt_pub_key")
+	c.TokenSource = func(context.Context) (string, error) { return "fresh", nil }
+
+	limit := 10
+	tracks, err := c.ListTracksPager(&ListTracksParams{Limit: &limit}).All(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, tracks, 5)
+	assert.Equal(t, []string{"1", "2", "3"}, pages)
+
+	start := 3
+	pages = nil
+	pager := c.ListTracksPager(&ListTracksParams{Page: &start})
+	require.True(t, pager.Next(context.Background()))
+	assert.Equal(t, "t3-0", pager.Items()[0].ID)
+	assert.False(t, pager.Next(context.Background()))
+	assert.NoError(t, pager.Err())
+	assert.Equal(t, []string{"3"}, pages)
+}
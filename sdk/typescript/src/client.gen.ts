@@ -0,0 +1,3014 @@
+// Code generated by openapigen. DO NOT EDIT.
+
+import { BaseClient, paginate } from './runtime.js';
+
+/** AIBatch is a schema from the API document */
+export interface AIBatch {
+  completed_at?: string | null;
+  created_at?: string;
+  enriched?: number;
+  error?: string;
+  failed?: number;
+  id?: string;
+  provider?: AIProvider;
+  provider_batch_id?: string;
+  requested_by?: string;
+  status?: AIBatchStatus;
+  track_ids?: string[];
+  updated_at?: string;
+}
+
+/** AIBatchStatus is a schema from the API document */
+export type AIBatchStatus = 'pending' | 'completed' | 'failed' | 'expired' | 'cancelled';
+
+/** AIModelCoverage is a schema from the API document */
+export interface AIModelCoverage {
+  current?: boolean;
+  model?: string;
+  prompt_version?: string;
+  provider?: AIProvider;
+  share?: number;
+  tracks?: number;
+  version?: string;
+}
+
+/** AIModelCoverageReport is a schema from the API document */
+export interface AIModelCoverageReport {
+  current?: AIModelVersion[];
+  models?: AIModelCoverage[];
+  outdated?: number;
+  tracks?: number;
+  unenriched?: number;
+}
+
+/** AIModelVersion is a schema from the API document */
+export interface AIModelVersion {
+  model?: string;
+  prompt_version?: string;
+  provider?: AIProvider;
+  version?: string;
+}
+
+/** AIProvider is a schema from the API document */
+export type AIProvider = 'qwen2' | 'openai';
+
+/** AIProviderHealth is a schema from the API document */
+export interface AIProviderHealth {
+  consecutive_failures?: number;
+  healthy?: boolean;
+  last_checked?: string | null;
+  last_error?: string;
+  latency_ms?: number;
+  provider?: AIProvider;
+}
+
+/** AIProviderStats is a schema from the API document */
+export interface AIProviderStats {
+  average_latency_ms?: number;
+  failure_rate?: number;
+  failures?: number;
+  healthy?: boolean;
+  last_error?: string;
+  last_success?: string | null;
+  provider?: AIProvider;
+  queue_depth?: number;
+  requests?: number;
+}
+
+/** AIRouting is a schema from the API document */
+export interface AIRouting {
+  forced?: AIProvider;
+  order?: AIProvider[];
+  policy?: AIRoutingPolicy;
+  providers?: AIProviderHealth[];
+}
+
+/** AIRoutingPolicy is a schema from the API document */
+export type AIRoutingPolicy = 'experiment' | 'latency';
+
+/** AcknowledgementResult is a schema from the API document */
+export interface AcknowledgementResult {
+  deliveries?: number;
+  error?: string;
+  package_id?: string;
+  status?: DeliveryStatus;
+}
+
+/** AdditionalMetadata is a schema from the API document */
+export interface AdditionalMetadata {
+  copyright?: string;
+  customFields?: Record<string, string>;
+  customTags?: Record<string, string>;
+  lyrics?: string;
+  publisher?: string;
+  tags?: string[];
+}
+
+/** ArchiveState is a schema from the API document */
+export type ArchiveState = 'available' | 'archived' | 'restoring' | 'restored';
+
+/** ArchiveStatus is a schema from the API document */
+export interface ArchiveStatus {
+  key?: string;
+  restored_until?: string | null;
+  state?: ArchiveState;
+  storage_class?: StorageClass;
+}
+
+/** AudioAnalysis is a schema from the API document */
+export interface AudioAnalysis {
+  analyzed_at?: string;
+  analyzer_info?: string;
+  arousal?: number;
+  beats?: number[];
+  beats_per_bar?: number;
+  bpm?: number;
+  brightness?: number;
+  complexity?: number;
+  danceability?: number;
+  duration?: number;
+  energy?: number;
+  hop_size?: number;
+  intensity?: number;
+  key?: string;
+  loudness?: number;
+  mode?: string;
+  mood?: string;
+  sample_count?: number;
+  sample_rate?: number;
+  section_count?: number;
+  sections?: AudioSection[];
+  segments?: AudioSegment[];
+  spectral_flux?: number;
+  spectral_roll?: number;
+  spectral_slope?: number;
+  tempo?: number;
+  timbre?: number;
+  time_signature?: string;
+  transitions?: number[];
+  valence?: number;
+  window_size?: number;
+}
+
+/** AudioFormat is a schema from the API document */
+export type AudioFormat = 'mp3' | 'wav' | 'flac' | 'm4a' | 'aac' | 'ogg';
+
+/** AudioSection is a schema from the API document */
+export interface AudioSection {
+  bpm?: number;
+  camelot?: string;
+  duration?: number;
+  energy?: number;
+  key?: string;
+  mode?: string;
+  start?: number;
+}
+
+/** AudioSegment is a schema from the API document */
+export interface AudioSegment {
+  confidence?: number;
+  duration?: number;
+  loudness?: number;
+  pitches?: number[];
+  start?: number;
+  timbre?: number[];
+}
+
+/** AudioTechnicalMetadata is a schema from the API document */
+export interface AudioTechnicalMetadata {
+  bitrate?: number;
+  channels?: number;
+  contentHash?: string;
+  contentMD5?: string;
+  encryptionKeyId?: string;
+  fileSize?: number;
+  format?: AudioFormat;
+  sampleRate?: number;
+}
+
+/** BackpressureLevel is a schema from the API document */
+export type BackpressureLevel = 'none' | 'throttle' | 'reject';
+
+/** BasicTrackMetadata is a schema from the API document */
+export interface BasicTrackMetadata {
+  album?: string;
+  artist?: string;
+  createdAt?: string;
+  duration?: number;
+  isrc?: string;
+  title?: string;
+  updatedAt?: string;
+  year?: number;
+}
+
+/** BootstrapResult is a schema from the API document */
+export interface BootstrapResult {
+  resources?: ProvisionedResource[];
+}
+
+/** BulkEditJob is a schema from the API document */
+export interface BulkEditJob {
+  completed_at?: string | null;
+  created_at?: string;
+  created_by?: string;
+  error?: string;
+  id?: string;
+  processed?: number;
+  request?: BulkEditRequest;
+  results?: BulkEditResult[];
+  started_at?: string | null;
+  status?: JobStatus;
+  total?: number;
+}
+
+/** BulkEditPatch is a schema from the API document */
+export interface BulkEditPatch {
+  append_tags?: string[];
+  set_genre?: string | null;
+  set_label?: string | null;
+}
+
+/** BulkEditRequest is a schema from the API document */
+export interface BulkEditRequest {
+  dry_run?: boolean;
+  filter?: Record<string, unknown>;
+  patch?: BulkEditPatch;
+  track_ids?: string[];
+}
+
+/** BulkEditResult is a schema from the API document */
+export interface BulkEditResult {
+  changes?: FieldChange[];
+  error?: string;
+  status?: BulkEditResultStatus;
+  track_id?: string;
+}
+
+/** BulkEditResultStatus is a schema from the API document */
+export type BulkEditResultStatus = 'updated' | 'unchanged' | 'not_found' | 'failed';
+
+/** CalibrationBin is a schema from the API document */
+export interface CalibrationBin {
+  accuracy?: number;
+  correct?: number;
+  max_confidence?: number;
+  mean_confidence?: number;
+  min_confidence?: number;
+  samples?: number;
+}
+
+/** CompleteTrackMetadata is a schema from the API document */
+export interface CompleteTrackMetadata {
+  additional?: AdditionalMetadata;
+  ai?: TrackAIMetadata;
+  basic?: BasicTrackMetadata;
+  musical?: MusicalMetadata;
+  provenance?: Record<string, FieldProvenance>;
+  technical?: AudioTechnicalMetadata;
+}
+
+/** ConfigBundle is a schema from the API document */
+export interface ConfigBundle {
+  environment?: string;
+  exported_at?: string;
+  exported_by?: string;
+  labels?: LabelConfig[];
+  settings?: RuntimeSettings;
+  signature?: string;
+  tag_rules?: TagRule[];
+  tags?: Tag[];
+  version?: number;
+}
+
+/** ConfigChange is a schema from the API document */
+export interface ConfigChange {
+  action?: string;
+  kind?: string;
+  label_id?: string;
+  name?: string;
+}
+
+/** ConfigImportResult is a schema from the API document */
+export interface ConfigImportResult {
+  changes?: ConfigChange[];
+  dry_run?: boolean;
+  source?: string;
+}
+
+/** CustomFieldDefinition is a schema from the API document */
+export interface CustomFieldDefinition {
+  created_at?: string;
+  description?: string;
+  label_id?: string;
+  max?: number | null;
+  min?: number | null;
+  name?: string;
+  pattern?: string;
+  required?: boolean;
+  type?: CustomFieldType;
+  updated_at?: string;
+  values?: string[];
+}
+
+/** CustomFieldType is a schema from the API document */
+export type CustomFieldType = 'string' | 'integer' | 'number' | 'boolean' | 'date' | 'url' | 'enum';
+
+/** DatabaseStats is a schema from the API document */
+export interface DatabaseStats {
+  idle?: number;
+  in_use?: number;
+  max_open_connections?: number;
+  open_connections?: number;
+  wait_count?: number;
+  wait_duration_ms?: number;
+}
+
+/** DeadLetterPage is a schema from the API document */
+export interface DeadLetterPage {
+  limit?: number;
+  messages?: Message[];
+  offset?: number;
+  topic?: string;
+  total?: number;
+}
+
+/** DeadLetterReplayReport is a schema from the API document */
+export interface DeadLetterReplayReport {
+  failed?: number;
+  replayed?: number;
+  results?: DeadLetterReplayResult[];
+  topic?: string;
+}
+
+/** DeadLetterReplayRequest is a schema from the API document */
+export interface DeadLetterReplayRequest {
+  ids?: string[];
+}
+
+/** DeadLetterReplayResult is a schema from the API document */
+export interface DeadLetterReplayResult {
+  error?: string;
+  id?: string;
+  replayed?: boolean;
+}
+
+/** Delivery is a schema from the API document */
+export interface Delivery {
+  acknowledged_at?: string | null;
+  created_at?: string;
+  delivered_at?: string;
+  dsp?: string;
+  error?: string;
+  id?: string;
+  kind?: DeliveryKind;
+  package_format?: string;
+  package_id?: string;
+  release_id?: string;
+  status?: DeliveryStatus;
+  territories?: string[];
+  track_id?: string;
+  updated_at?: string;
+}
+
+/** DeliveryKind is a schema from the API document */
+export type DeliveryKind = 'release' | 'takedown' | 'territory_block';
+
+/** DeliveryStatus is a schema from the API document */
+export type DeliveryStatus = 'sent' | 'acknowledged' | 'failed';
+
+/** DuplicateCandidate is a schema from the API document */
+export interface DuplicateCandidate {
+  artist?: string;
+  isrc?: string;
+  reasons?: string[];
+  score?: number;
+  title?: string;
+  track_id?: string;
+}
+
+/** EvaluationReport is a schema from the API document */
+export interface EvaluationReport {
+  evaluated_at?: string;
+  golden_tracks?: number;
+  providers?: ProviderEvaluation[];
+  target_accuracy?: number;
+}
+
+/** FieldAccuracy is a schema from the API document */
+export interface FieldAccuracy {
+  accuracy?: number;
+  correct?: number;
+  field?: string;
+  samples?: number;
+}
+
+/** FieldChange is a schema from the API document */
+export interface FieldChange {
+  field?: string;
+  new_value?: unknown;
+  old_value?: unknown;
+}
+
+/** FieldProvenance is a schema from the API document */
+export interface FieldProvenance {
+  source?: ProvenanceSource;
+  updatedAt?: string;
+  updatedBy?: string;
+}
+
+/** FieldProvenanceView is a schema from the API document */
+export interface FieldProvenanceView {
+  field?: string;
+  source?: ProvenanceSource;
+  updatedAt?: string | null;
+  updatedBy?: string;
+  value?: unknown;
+}
+
+/** GoldenTrack is a schema from the API document */
+export interface GoldenTrack {
+  bpm?: number;
+  created_at?: string;
+  genre?: string;
+  key?: string;
+  labeled_by?: string;
+  mood?: string;
+  track_id?: string;
+  updated_at?: string;
+}
+
+/** HarmonicMatch is a schema from the API document */
+export interface HarmonicMatch {
+  bpm_difference?: number;
+  camelot?: string;
+  relation?: HarmonicRelation;
+  track?: Track;
+}
+
+/** HarmonicRelation is a schema from the API document */
+export type HarmonicRelation = 'same' | 'adjacent' | 'relative';
+
+/** ImportColumn is a schema from the API document */
+export interface ImportColumn {
+  column?: string;
+  field?: string;
+  required?: boolean;
+  transforms?: ImportTransform[];
+}
+
+/** ImportMapping is a schema from the API document */
+export interface ImportMapping {
+  columns?: ImportColumn[];
+  created_at?: string;
+  defaults?: Record<string, string>;
+  delimiter?: string;
+  id?: string;
+  label_id?: string;
+  name?: string;
+  updated_at?: string;
+}
+
+/** ImportTransform is a schema from the API document */
+export interface ImportTransform {
+  default?: string;
+  format?: string;
+  type?: ImportTransformType;
+  values?: Record<string, string>;
+}
+
+/** ImportTransformType is a schema from the API document */
+export type ImportTransformType = 'date' | 'lookup' | 'upper' | 'lower';
+
+/** IntegrityReport is a schema from the API document */
+export interface IntegrityReport {
+  actual_md5?: string;
+  actual_sha256?: string;
+  checked_at?: string;
+  error?: string;
+  expected_md5?: string;
+  expected_sha256?: string;
+  key?: string;
+  size?: number;
+  status?: IntegrityStatus;
+  track_id?: string;
+}
+
+/** IntegrityStatus is a schema from the API document */
+export type IntegrityStatus = 'ok' | 'mismatch' | 'recorded' | 'unavailable';
+
+/** JobHistory is a schema from the API document */
+export interface JobHistory {
+  archived_at?: string;
+  created_at?: string;
+  duration_ms?: number | null;
+  error?: string;
+  finished_at?: string;
+  id?: string;
+  payload?: Record<string, unknown>;
+  priority?: JobPriority;
+  queue?: string;
+  retry_count?: number;
+  started_at?: string | null;
+  status?: JobStatus;
+  type?: JobType;
+}
+
+/** JobHistoryPage is a schema from the API document */
+export interface JobHistoryPage {
+  jobs?: JobHistory[];
+  limit?: number;
+  offset?: number;
+  total?: number;
+}
+
+/** JobHistoryReport is a schema from the API document */
+export interface JobHistoryReport {
+  from?: string;
+  slowest_types?: JobTypeDuration[];
+  to?: string;
+  weeks?: JobWeekStats[];
+}
+
+/** JobPriority is a schema from the API document */
+export type JobPriority = number;
+
+/** JobStats is a schema from the API document */
+export interface JobStats {
+  completed?: number;
+  failed?: number;
+  failure_rate?: number;
+  window_seconds?: number;
+}
+
+/** JobStatus is a schema from the API document */
+export type JobStatus = 'pending' | 'processing' | 'completed' | 'failed' | 'canceled';
+
+/** JobType is a schema from the API document */
+export type JobType = 'audio_process' | 'ai_enrich' | 'ddex_export' | 'cleanup' | 'bulk_edit' | 'file_scan' | 'storage_replicate' | 'audio_reanalyze';
+
+/** JobTypeDuration is a schema from the API document */
+export interface JobTypeDuration {
+  avg_ms?: number;
+  jobs?: number;
+  max_ms?: number;
+  p95_ms?: number;
+  type?: JobType;
+}
+
+/** JobWeekStats is a schema from the API document */
+export interface JobWeekStats {
+  completed?: number;
+  failed?: number;
+  success_rate?: number;
+  type?: JobType;
+  week?: string;
+}
+
+/** LabelAIConfig is a schema from the API document */
+export interface LabelAIConfig {
+  api_key_hint?: string;
+  created_at?: string;
+  label_id?: string;
+  min_confidence?: number | null;
+  model?: string;
+  provider?: AIProvider;
+  updated_at?: string;
+  updated_by?: string;
+}
+
+/** LabelConfig is a schema from the API document */
+export interface LabelConfig {
+  custom_fields?: CustomFieldDefinition[];
+  import_mappings?: ImportMapping[];
+  label_id?: string;
+}
+
+/** LabelStats is a schema from the API document */
+export interface LabelStats {
+  average_confidence?: number;
+  by_genre?: Record<string, number>;
+  by_status?: Record<string, number>;
+  by_year?: Record<string, number>;
+  computed_at?: string;
+  duration_seconds?: number;
+  enriched?: number;
+  enrichment_coverage?: number;
+  label_id?: string;
+  storage_bytes?: number;
+  tracks?: number;
+}
+
+/** MaintenanceRun is a schema from the API document */
+export interface MaintenanceRun {
+  due_at?: string;
+  duration_ms?: number;
+  error?: string;
+  finished_at?: string;
+  instance?: string;
+  started_at?: string;
+  task?: string;
+}
+
+/** MaintenanceStatus is a schema from the API document */
+export interface MaintenanceStatus {
+  instance?: string;
+  leader?: boolean;
+  tasks?: MaintenanceTask[];
+}
+
+/** MaintenanceTask is a schema from the API document */
+export interface MaintenanceTask {
+  name?: string;
+  next_run?: string;
+  running?: boolean;
+  runs?: MaintenanceRun[];
+  schedule?: string;
+}
+
+/** MergePick is a schema from the API document */
+export type MergePick = 'fill' | 'target' | 'source' | 'combine';
+
+/** MergeRules is a schema from the API document */
+export type MergeRules = Record<string, MergePick>;
+
+/** Message is a schema from the API document */
+export interface Message {
+  created_at?: string;
+  data?: Record<string, unknown>;
+  dead_letter_at?: string | null;
+  error_message?: string;
+  id?: string;
+  max_retries?: number;
+  next_retry_at?: string | null;
+  priority?: QueuePriority;
+  processed_at?: string | null;
+  retry_count?: number;
+  status?: MessageStatus;
+  type?: string;
+  updated_at?: string;
+}
+
+/** MessageStatus is a schema from the API document */
+export type MessageStatus = 'pending' | 'processing' | 'completed' | 'failed' | 'retrying' | 'dead_letter';
+
+/** MusicalMetadata is a schema from the API document */
+export interface MusicalMetadata {
+  bpm?: number;
+  camelot?: string;
+  energy?: number;
+  genre?: string;
+  key?: string;
+  mode?: string;
+  mood?: string;
+  openKey?: string;
+  tempo?: number;
+}
+
+/** PlayCount is a schema from the API document */
+export interface PlayCount {
+  dsp?: string;
+  isrc?: string;
+  month?: string;
+  plays?: number;
+  territory?: string;
+  track_id?: string;
+  updated_at?: string;
+}
+
+/** ProvenanceSource is a schema from the API document */
+export type ProvenanceSource = 'manual' | 'ai' | 'import';
+
+/** ProviderEvaluation is a schema from the API document */
+export interface ProviderEvaluation {
+  calibration?: CalibrationBin[];
+  failures?: number;
+  fields?: FieldAccuracy[];
+  provider?: AIProvider;
+  suggested_min_confidence?: number | null;
+  tracks?: number;
+}
+
+/** ProvisionedResource is a schema from the API document */
+export interface ProvisionedResource {
+  kind?: string;
+  name?: string;
+  status?: string;
+}
+
+/** PublicAPIKey is a schema from the API document */
+export interface PublicAPIKey {
+  created_at?: string;
+  id?: string;
+  label_id?: string;
+  name?: string;
+  prefix?: string;
+  revoked_at?: string | null;
+  tier?: string;
+}
+
+/** PublicAPITier is a schema from the API document */
+export interface PublicAPITier {
+  name?: string;
+  requests_per_day?: number;
+  requests_per_minute?: number;
+}
+
+/** QueuePriority is a schema from the API document */
+export type QueuePriority = number;
+
+/** QueueStats is a schema from the API document */
+export interface QueueStats {
+  dead_letters?: number;
+  name?: string;
+  pending?: number;
+  processing?: number;
+}
+
+/** ReenrichmentRun is a schema from the API document */
+export interface ReenrichmentRun {
+  completed_at?: string | null;
+  current?: AIModelVersion[];
+  enriched?: number;
+  error?: string;
+  failed?: number;
+  started_at?: string;
+  status?: ReenrichmentStatus;
+}
+
+/** ReenrichmentStatus is a schema from the API document */
+export type ReenrichmentStatus = 'running' | 'completed' | 'cancelled' | 'failed';
+
+/** RuntimeSettings is a schema from the API document */
+export interface RuntimeSettings {
+  ai_forced_provider?: AIProvider;
+  ai_min_confidence?: number;
+  experiment_traffic_percent?: number;
+  rate_limit_per_minute?: number;
+  updated_at?: string;
+  updated_by?: string;
+}
+
+/** RuntimeSettingsPatch is a schema from the API document */
+export interface RuntimeSettingsPatch {
+  ai_forced_provider?: AIProvider;
+  ai_min_confidence?: number | null;
+  experiment_traffic_percent?: number | null;
+  rate_limit_per_minute?: number | null;
+}
+
+/** SalesReport is a schema from the API document */
+export interface SalesReport {
+  dsp?: string;
+  ingested_at?: string;
+  message_id?: string;
+  month?: string;
+  plays?: number;
+  records?: number;
+  unmatched?: number;
+}
+
+/** SearchFilter is a schema from the API document */
+export interface SearchFilter {
+  and?: SearchFilter[];
+  field?: string;
+  not?: SearchFilter;
+  op?: SearchOp;
+  or?: SearchFilter[];
+  value?: unknown;
+}
+
+/** SearchOp is a schema from the API document */
+export type SearchOp = 'eq' | 'ne' | 'gt' | 'gte' | 'lt' | 'lte' | 'between' | 'contains' | 'in' | 'exists';
+
+/** SignedUpload is a schema from the API document */
+export interface SignedUpload {
+  expires_at?: string;
+  headers?: Record<string, string>;
+  method?: string;
+  url?: string;
+}
+
+/** SimilarTrack is a schema from the API document */
+export interface SimilarTrack {
+  distance?: number;
+  track?: Track;
+}
+
+/** StorageClass is a schema from the API document */
+export type StorageClass = 'STANDARD' | 'STANDARD_IA' | 'GLACIER';
+
+/** StorageStats is a schema from the API document */
+export interface StorageStats {
+  quota_bytes?: number;
+  used_bytes?: number;
+  used_percent?: number;
+}
+
+/** SystemStats is a schema from the API document */
+export interface SystemStats {
+  ai_providers?: AIProviderStats[];
+  database?: DatabaseStats;
+  errors?: Record<string, string>;
+  generated_at?: string;
+  jobs?: JobStats;
+  queue_lag?: TopicLag[];
+  queues?: QueueStats[];
+  storage?: StorageStats;
+}
+
+/** Tag is a schema from the API document */
+export interface Tag {
+  created_at?: string;
+  description?: string;
+  name?: string;
+  updated_at?: string;
+}
+
+/** TagCondition is a schema from the API document */
+export interface TagCondition {
+  field?: string;
+  op?: TagRuleOp;
+  value?: unknown;
+}
+
+/** TagRule is a schema from the API document */
+export interface TagRule {
+  conditions?: TagCondition[];
+  created_at?: string;
+  disabled?: boolean;
+  id?: string;
+  name?: string;
+  tag?: string;
+  updated_at?: string;
+}
+
+/** TagRuleOp is a schema from the API document */
+export type TagRuleOp = 'eq' | 'ne' | 'gt' | 'gte' | 'lt' | 'lte' | 'contains' | 'in';
+
+/** TopicLag is a schema from the API document */
+export interface TopicLag {
+  lag?: number;
+  level?: BackpressureLevel;
+  publish_rate?: number;
+  sampled_at?: string;
+  topic?: string;
+}
+
+/** Track is a schema from the API document */
+export interface Track {
+  artistIds?: string[];
+  createdAt?: string;
+  deletedAt?: string | null;
+  filePath?: string;
+  fileSize?: number;
+  id?: string;
+  labelId?: string;
+  lock?: TrackLock;
+  metadata?: CompleteTrackMetadata;
+  previousId?: string;
+  releaseId?: string;
+  status?: TrackStatus;
+  statusMsg?: string;
+  storagePath?: string;
+  updatedAt?: string;
+  version?: number;
+}
+
+/** TrackAIMetadata is a schema from the API document */
+export interface TrackAIMetadata {
+  analysis?: string;
+  confidence?: number;
+  model?: string;
+  needsReview?: boolean;
+  processedAt?: string;
+  promptVersion?: string;
+  reviewReason?: string;
+  tags?: string[];
+  validationIssues?: ValidationIssue[];
+  validationSuggestions?: ValidationSuggestion[];
+  version?: string;
+}
+
+/** TrackAnalysis is a schema from the API document */
+export interface TrackAnalysis {
+  analysis?: AudioAnalysis;
+  analyzed_at?: string;
+  analyzer_version?: string;
+  track_id?: string;
+}
+
+/** TrackEmbedding is a schema from the API document */
+export interface TrackEmbedding {
+  model?: string;
+  track_id?: string;
+  updated_at?: string;
+  vector?: number[];
+}
+
+/** TrackLock is a schema from the API document */
+export interface TrackLock {
+  acquiredAt?: string;
+  expiresAt?: string;
+  owner?: string;
+}
+
+/** TrackStatus is a schema from the API document */
+export type TrackStatus = 'draft' | 'pending' | 'processing' | 'needs_review' | 'approved' | 'delivered' | 'archived' | 'rejected' | 'active' | 'inactive' | 'deleted';
+
+/** UsageDay is a schema from the API document */
+export interface UsageDay {
+  ai_enrichments?: number;
+  day?: string;
+  exports?: number;
+  label_id?: string;
+  public_api_requests?: number;
+  storage_bytes?: number;
+  tracks_stored?: number;
+}
+
+/** UsageReport is a schema from the API document */
+export interface UsageReport {
+  days?: UsageDay[];
+  from?: string;
+  to?: string;
+}
+
+/** ValidationIssue is a schema from the API document */
+export interface ValidationIssue {
+  description?: string;
+  field?: string;
+  severity?: string;
+}
+
+/** ValidationSuggestion is a schema from the API document */
+export interface ValidationSuggestion {
+  current_value?: string;
+  field?: string;
+  reason?: string;
+  suggested_value?: string;
+}
+
+/** AIBatchesResponse is a schema from the API document */
+export interface AIBatchesResponse {
+  batches?: AIBatch[];
+}
+
+/** BatchDeleteRequest is a schema from the API document */
+export interface BatchDeleteRequest {
+  track_ids: string[];
+}
+
+/** BatchDeleteResponse is a schema from the API document */
+export interface BatchDeleteResponse {
+  deleted?: number;
+  dry_run?: boolean;
+  results?: BatchDeleteResult[];
+}
+
+/** BatchDeleteResult is a schema from the API document */
+export interface BatchDeleteResult {
+  error?: string;
+  status?: BatchDeleteStatus;
+  track_id?: string;
+}
+
+/** BatchDeleteStatus is a schema from the API document */
+export type BatchDeleteStatus = 'deleted' | 'not_found' | 'failed';
+
+/** BootstrapRequest is a schema from the API document */
+export interface BootstrapRequest {
+  admin_email: string;
+  admin_name?: string;
+  admin_password: string;
+  label_id?: string;
+  label_name?: string;
+}
+
+/** ConflictResponse is a schema from the API document */
+export interface ConflictResponse {
+  conflicts?: FieldChange[];
+  current_version?: number;
+  error?: ErrorBody;
+}
+
+/** CustomFieldsResponse is a schema from the API document */
+export interface CustomFieldsResponse {
+  fields?: CustomFieldDefinition[];
+}
+
+/** DeliveriesResponse is a schema from the API document */
+export interface DeliveriesResponse {
+  deliveries?: Delivery[];
+  limit?: number;
+  page?: number;
+}
+
+/** DuplicatesResponse is a schema from the API document */
+export interface DuplicatesResponse {
+  duplicates?: DuplicateCandidate[];
+  track_id?: string;
+}
+
+/** EmbeddingRequest is a schema from the API document */
+export interface EmbeddingRequest {
+  model?: string;
+  vector?: number[];
+}
+
+/** ErrorBody is a schema from the API document */
+export interface ErrorBody {
+  code?: string;
+  details?: string;
+  fields?: ErrorField[];
+  message?: string;
+  request_id?: string;
+  trace_id?: string;
+  type?: string;
+}
+
+/** ErrorField is a schema from the API document */
+export interface ErrorField {
+  code?: string;
+  field?: string;
+  message?: string;
+}
+
+/** ErrorResponse is a schema from the API document */
+export interface ErrorResponse {
+  error?: ErrorBody;
+}
+
+/** ExportRequest is a schema from the API document */
+export interface ExportRequest {
+  format?: string;
+  track_ids: string[];
+}
+
+/** ExportResponse is a schema from the API document */
+export interface ExportResponse {
+  data?: unknown;
+  format?: string;
+}
+
+/** ForceAIProviderRequest is a schema from the API document */
+export interface ForceAIProviderRequest {
+  provider: AIProvider;
+}
+
+/** GoldenTracksResponse is a schema from the API document */
+export interface GoldenTracksResponse {
+  tracks?: GoldenTrack[];
+}
+
+/** HarmonicMixResponse is a schema from the API document */
+export interface HarmonicMixResponse {
+  bpm?: number;
+  camelot?: string;
+  key?: string;
+  open_key?: string;
+  track_id?: string;
+  tracks?: HarmonicMatch[];
+}
+
+/** ImportMappingsResponse is a schema from the API document */
+export interface ImportMappingsResponse {
+  mappings?: ImportMapping[];
+}
+
+/** ImportPreview is a schema from the API document */
+export interface ImportPreview {
+  dry_run?: boolean;
+  duplicates?: DuplicatesResponse[];
+  errors?: string[];
+  tracks?: Track[];
+}
+
+/** LabelAIConfigRequest is a schema from the API document */
+export interface LabelAIConfigRequest {
+  api_key?: string;
+  min_confidence?: number | null;
+  model?: string;
+  provider?: AIProvider;
+}
+
+/** LabelAIConfigsResponse is a schema from the API document */
+export interface LabelAIConfigsResponse {
+  configs?: LabelAIConfig[];
+}
+
+/** ListResponse is a schema from the API document */
+export interface ListResponse {
+  limit?: number;
+  page?: number;
+  tracks?: Track[];
+}
+
+/** PlayCountsResponse is a schema from the API document */
+export interface PlayCountsResponse {
+  limit?: number;
+  page?: number;
+  play_counts?: PlayCount[];
+}
+
+/** ProvenanceResponse is a schema from the API document */
+export interface ProvenanceResponse {
+  fields?: FieldProvenanceView[];
+  track_id?: string;
+  version?: number;
+}
+
+/** PublicAPIKeysResponse is a schema from the API document */
+export interface PublicAPIKeysResponse {
+  keys?: PublicAPIKey[];
+  tiers?: PublicAPITier[];
+}
+
+/** SearchQuery is a schema from the API document */
+export interface SearchQuery {
+  album?: string;
+  artist?: string;
+  created_from?: string;
+  created_to?: string;
+  custom_fields?: Record<string, unknown>;
+  filter?: SearchFilter;
+  genre?: string;
+  isrc?: string;
+  iswc?: string;
+  label?: string;
+  label_id?: string;
+  needs_review?: boolean | null;
+  title?: string;
+}
+
+/** SimilarTracksResponse is a schema from the API document */
+export interface SimilarTracksResponse {
+  track_id?: string;
+  tracks?: SimilarTrack[];
+}
+
+/** SubmitAIBatchRequest is a schema from the API document */
+export interface SubmitAIBatchRequest {
+  provider?: AIProvider;
+  track_ids: string[];
+}
+
+/** TagRenameRequest is a schema from the API document */
+export interface TagRenameRequest {
+  name: string;
+}
+
+/** TagRulesResponse is a schema from the API document */
+export interface TagRulesResponse {
+  rules?: TagRule[];
+}
+
+/** TagUpdateRequest is a schema from the API document */
+export interface TagUpdateRequest {
+  description?: string;
+}
+
+/** TagsResponse is a schema from the API document */
+export interface TagsResponse {
+  tags?: Tag[];
+}
+
+/** TrackMergeRequest is a schema from the API document */
+export interface TrackMergeRequest {
+  fields?: MergeRules;
+}
+
+/** TrackMergeResponse is a schema from the API document */
+export interface TrackMergeResponse {
+  moved_audio?: boolean;
+  redirect_from?: string;
+  references?: Record<string, number>;
+  track?: unknown;
+}
+
+/** TransitionRequest is a schema from the API document */
+export interface TransitionRequest {
+  message?: string;
+  status: TrackStatus;
+}
+
+/** TransitionsResponse is a schema from the API document */
+export interface TransitionsResponse {
+  next?: TrackStatus[];
+  status?: TrackStatus;
+  track_id?: string;
+  version?: number;
+}
+
+/** UploadURLRequest is a schema from the API document */
+export interface UploadURLRequest {
+  album?: string;
+  artist: string;
+  filename: string;
+  size: number;
+  title: string;
+}
+
+/** UploadURLResponse is a schema from the API document */
+export interface UploadURLResponse {
+  track?: Track;
+  upload?: SignedUpload;
+}
+
+/** ValidationResponse is a schema from the API document */
+export interface ValidationResponse {
+  errors?: string[];
+  valid?: boolean;
+}
+
+/** ExportConfigParams holds the optional parameters of exportConfig */
+export interface ExportConfigParams {
+  /** Labels whose custom fields and import mappings to export; all labels when omitted */
+  label?: string[];
+}
+
+/** ImportConfigParams holds the optional parameters of importConfig */
+export interface ImportConfigParams {
+  /** Report the changes without writing them */
+  dryRun?: boolean;
+}
+
+/** ListDeadLettersParams holds the optional parameters of listDeadLetters */
+export interface ListDeadLettersParams {
+  /** Messages to skip */
+  offset?: number;
+  /** Page size, at most 500 (default 50) */
+  limit?: number;
+}
+
+/** ListJobHistoryParams holds the optional parameters of listJobHistory */
+export interface ListJobHistoryParams {
+  /** Only jobs of this type */
+  type?: string;
+  /** Only completed or failed jobs */
+  status?: string;
+  /** First day the jobs finished, YYYY-MM-DD */
+  from?: string;
+  /** Last day the jobs finished, YYYY-MM-DD */
+  to?: string;
+  /** Jobs to skip */
+  offset?: number;
+  /** Page size, at most 500 (default 50) */
+  limit?: number;
+}
+
+/** GetJobHistoryReportParams holds the optional parameters of getJobHistoryReport */
+export interface GetJobHistoryReportParams {
+  /** First day, YYYY-MM-DD; defaults to twelve weeks before to */
+  from?: string;
+  /** Last day, YYYY-MM-DD; defaults to today */
+  to?: string;
+}
+
+/** BootstrapParams holds the optional parameters of bootstrap */
+export interface BootstrapParams {
+  /** Setup token */
+  xSetupToken?: string;
+}
+
+/** ImportERNParams holds the optional parameters of importERN */
+export interface ImportERNParams {
+  /** Preview the import without saving */
+  dryRun?: boolean;
+}
+
+/** ListDeliveriesParams holds the optional parameters of listDeliveries */
+export interface ListDeliveriesParams {
+  /** Only deliveries to this DSP */
+  dsp?: string;
+  /** Only deliveries of this track */
+  trackID?: string;
+  /** Only deliveries of this release */
+  releaseID?: string;
+  /** release, takedown or territory_block */
+  kind?: string;
+  /** sent, acknowledged or failed */
+  status?: string;
+  /** Page number */
+  page?: number;
+  /** Items per page */
+  limit?: number;
+}
+
+/** RequestTakedownParams holds the optional parameters of requestTakedown */
+export interface RequestTakedownParams {
+  /** Generate the message without recording the deliveries */
+  dryRun?: boolean;
+}
+
+/** ImportCSVParams holds the optional parameters of importCSV */
+export interface ImportCSVParams {
+  /** Mapping ID or name */
+  mapping?: string;
+  /** Map and validate the rows without creating tracks */
+  dryRun?: boolean;
+}
+
+/** ListKeysParams holds the optional parameters of listKeys */
+export interface ListKeysParams {
+  /** Label whose keys to list */
+  labelID?: string;
+}
+
+/** ListPlayCountsParams holds the optional parameters of listPlayCounts */
+export interface ListPlayCountsParams {
+  /** Only this track */
+  trackID?: string;
+  /** Only this DSP */
+  dsp?: string;
+  /** Only this territory, an ISO 3166-1 alpha-2 code */
+  territory?: string;
+  /** First month, YYYY-MM */
+  from?: string;
+  /** Last month, YYYY-MM */
+  to?: string;
+  /** Page number */
+  page?: number;
+  /** Items per page, at most 1000 */
+  limit?: number;
+}
+
+/** IngestSalesReportParams holds the optional parameters of ingestSalesReport */
+export interface IngestSalesReportParams {
+  /** DSP that sent the report; defaults to its SenderName */
+  dsp?: string;
+}
+
+/** ListTracksParams holds the optional parameters of listTracks */
+export interface ListTracksParams {
+  /** Page number */
+  page?: number;
+  /** Items per page */
+  limit?: number;
+  /** Comma-separated fields to return, such as id,title,artist,status */
+  fields?: string;
+  /** Comma-separated fields to order by, such as artist,-created_at */
+  sort?: string;
+}
+
+/** CreateTrackParams holds the optional parameters of createTrack */
+export interface CreateTrackParams {
+  /** Key under which repeats of this request return the original response */
+  idempotencyKey?: string;
+}
+
+/** BatchDeleteTracksParams holds the optional parameters of batchDeleteTracks */
+export interface BatchDeleteTracksParams {
+  /** Report what would be deleted without deleting */
+  dryRun?: boolean;
+}
+
+/** BulkEditParams holds the optional parameters of bulkEdit */
+export interface BulkEditParams {
+  /** Preview the changes without writing them */
+  dryRun?: boolean;
+}
+
+/** ExportTracksParams holds the optional parameters of exportTracks */
+export interface ExportTracksParams {
+  /** Key under which repeats of this request return the original response */
+  idempotencyKey?: string;
+}
+
+/** SearchTracksParams holds the optional parameters of searchTracks */
+export interface SearchTracksParams {
+  /** Comma-separated fields to return, such as id,title,artist,status */
+  fields?: string;
+}
+
+/** UploadTrackParams holds the optional parameters of uploadTrack */
+export interface UploadTrackParams {
+  /** Key under which repeats of this request return the original response */
+  idempotencyKey?: string;
+}
+
+/** CreateUploadURLParams holds the optional parameters of createUploadURL */
+export interface CreateUploadURLParams {
+  /** Key under which repeats of this request return the original response */
+  idempotencyKey?: string;
+}
+
+/** GetTrackParams holds the optional parameters of getTrack */
+export interface GetTrackParams {
+  /** ETag of the track version the client has */
+  ifNoneMatch?: string;
+  /** Time of the track version the client has */
+  ifModifiedSince?: string;
+}
+
+/** PatchTrackParams holds the optional parameters of patchTrack */
+export interface PatchTrackParams {
+  /** ETag of the track version being updated */
+  ifMatch?: string;
+}
+
+/** UpdateTrackParams holds the optional parameters of updateTrack */
+export interface UpdateTrackParams {
+  /** ETag of the track version being updated */
+  ifMatch?: string;
+}
+
+/** GetTrackAnalysisParams holds the optional parameters of getTrackAnalysis */
+export interface GetTrackAnalysisParams {
+  /** Analyzer version, such as basic_analyzer_v1 */
+  version?: string;
+}
+
+/** GetHarmonicMixesParams holds the optional parameters of getHarmonicMixes */
+export interface GetHarmonicMixesParams {
+  /** Maximum number of tracks (default 25, at most 200) */
+  limit?: number;
+}
+
+/** GetSimilarTracksParams holds the optional parameters of getSimilarTracks */
+export interface GetSimilarTracksParams {
+  /** Maximum number of tracks (default 10, at most 100) */
+  limit?: number;
+}
+
+/** StreamTrackParams holds the optional parameters of streamTrack */
+export interface StreamTrackParams {
+  /** Byte range of the audio to return, such as bytes=0-1023 */
+  range?: string;
+}
+
+/** TransitionTrackParams holds the optional parameters of transitionTrack */
+export interface TransitionTrackParams {
+  /** ETag of the track version being changed */
+  ifMatch?: string;
+}
+
+/** GetUsageParams holds the optional parameters of getUsage */
+export interface GetUsageParams {
+  /** Only this label */
+  labelID?: string;
+  /** First day, YYYY-MM-DD; defaults to the start of the month */
+  from?: string;
+  /** Last day, YYYY-MM-DD; defaults to today */
+  to?: string;
+  /** json (default) or csv */
+  format?: string;
+}
+
+/** Client calls the API operations over HTTP */
+export class Client extends BaseClient {
+  /**
+   * listBatches calls GET /admin/ai/batches
+   *
+   * List AI batches
+   */
+  listBatches(): Promise<AIBatchesResponse> {
+    return this.request<AIBatchesResponse>({
+      method: 'GET',
+      path: '/admin/ai/batches',
+      response: 'json',
+    });
+  }
+
+  /**
+   * submitBatch calls POST /admin/ai/batches
+   *
+   * Submit AI batch
+   */
+  submitBatch(body: SubmitAIBatchRequest): Promise<AIBatch> {
+    return this.request<AIBatch>({
+      method: 'POST',
+      path: '/admin/ai/batches',
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * cancelBatch calls DELETE /admin/ai/batches/{id}
+   *
+   * Cancel AI batch
+   */
+  cancelBatch(id: string): Promise<AIBatch> {
+    return this.request<AIBatch>({
+      method: 'DELETE',
+      path: `/admin/ai/batches/${encodeURIComponent(id)}`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * getBatch calls GET /admin/ai/batches/{id}
+   *
+   * Get AI batch
+   */
+  getBatch(id: string): Promise<AIBatch> {
+    return this.request<AIBatch>({
+      method: 'GET',
+      path: `/admin/ai/batches/${encodeURIComponent(id)}`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * runEvaluation calls POST /admin/ai/evaluations
+   *
+   * Run evaluation
+   */
+  runEvaluation(): Promise<EvaluationReport> {
+    return this.request<EvaluationReport>({
+      method: 'POST',
+      path: '/admin/ai/evaluations',
+      response: 'json',
+    });
+  }
+
+  /**
+   * getLatestEvaluation calls GET /admin/ai/evaluations/latest
+   *
+   * Get latest evaluation
+   */
+  getLatestEvaluation(): Promise<EvaluationReport> {
+    return this.request<EvaluationReport>({
+      method: 'GET',
+      path: '/admin/ai/evaluations/latest',
+      response: 'json',
+    });
+  }
+
+  /**
+   * listGoldenTracks calls GET /admin/ai/golden-tracks
+   *
+   * List golden tracks
+   */
+  listGoldenTracks(): Promise<GoldenTracksResponse> {
+    return this.request<GoldenTracksResponse>({
+      method: 'GET',
+      path: '/admin/ai/golden-tracks',
+      response: 'json',
+    });
+  }
+
+  /**
+   * deleteGoldenTrack calls DELETE /admin/ai/golden-tracks/{track_id}
+   *
+   * Delete golden track
+   */
+  deleteGoldenTrack(trackID: string): Promise<void> {
+    return this.request<void>({
+      method: 'DELETE',
+      path: `/admin/ai/golden-tracks/${encodeURIComponent(trackID)}`,
+      response: 'none',
+    });
+  }
+
+  /**
+   * saveGoldenTrack calls PUT /admin/ai/golden-tracks/{track_id}
+   *
+   * Save golden track
+   */
+  saveGoldenTrack(trackID: string, body: GoldenTrack): Promise<GoldenTrack> {
+    return this.request<GoldenTrack>({
+      method: 'PUT',
+      path: `/admin/ai/golden-tracks/${encodeURIComponent(trackID)}`,
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * getModelCoverage calls GET /admin/ai/model-coverage
+   *
+   * Get AI model coverage
+   */
+  getModelCoverage(): Promise<AIModelCoverageReport> {
+    return this.request<AIModelCoverageReport>({
+      method: 'GET',
+      path: '/admin/ai/model-coverage',
+      response: 'json',
+    });
+  }
+
+  /**
+   * cancelReenrichment calls DELETE /admin/ai/reenrichment
+   *
+   * Cancel re-enrichment
+   */
+  cancelReenrichment(): Promise<ReenrichmentRun> {
+    return this.request<ReenrichmentRun>({
+      method: 'DELETE',
+      path: '/admin/ai/reenrichment',
+      response: 'json',
+    });
+  }
+
+  /**
+   * getReenrichment calls GET /admin/ai/reenrichment
+   *
+   * Get re-enrichment
+   */
+  getReenrichment(): Promise<ReenrichmentRun> {
+    return this.request<ReenrichmentRun>({
+      method: 'GET',
+      path: '/admin/ai/reenrichment',
+      response: 'json',
+    });
+  }
+
+  /**
+   * startReenrichment calls POST /admin/ai/reenrichment
+   *
+   * Start re-enrichment
+   */
+  startReenrichment(): Promise<ReenrichmentRun> {
+    return this.request<ReenrichmentRun>({
+      method: 'POST',
+      path: '/admin/ai/reenrichment',
+      response: 'json',
+    });
+  }
+
+  /**
+   * getRouting calls GET /admin/ai/routing
+   *
+   * Get AI routing
+   */
+  getRouting(): Promise<AIRouting> {
+    return this.request<AIRouting>({
+      method: 'GET',
+      path: '/admin/ai/routing',
+      response: 'json',
+    });
+  }
+
+  /**
+   * clearForcedProvider calls DELETE /admin/ai/routing/forced
+   *
+   * Clear forced AI provider
+   */
+  clearForcedProvider(): Promise<AIRouting> {
+    return this.request<AIRouting>({
+      method: 'DELETE',
+      path: '/admin/ai/routing/forced',
+      response: 'json',
+    });
+  }
+
+  /**
+   * forceProvider calls PUT /admin/ai/routing/forced
+   *
+   * Force AI provider
+   */
+  forceProvider(body: ForceAIProviderRequest): Promise<AIRouting> {
+    return this.request<AIRouting>({
+      method: 'PUT',
+      path: '/admin/ai/routing/forced',
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * checkHealth calls POST /admin/ai/routing/health-check
+   *
+   * Check AI provider health
+   */
+  checkHealth(): Promise<AIRouting> {
+    return this.request<AIRouting>({
+      method: 'POST',
+      path: '/admin/ai/routing/health-check',
+      response: 'json',
+    });
+  }
+
+  /**
+   * exportConfig calls GET /admin/config/export
+   *
+   * Export configuration
+   */
+  exportConfig(params?: ExportConfigParams): Promise<ConfigBundle> {
+    return this.request<ConfigBundle>({
+      method: 'GET',
+      path: '/admin/config/export',
+      query: {
+        label: params?.label,
+      },
+      response: 'json',
+    });
+  }
+
+  /**
+   * importConfig calls POST /admin/config/import
+   *
+   * Import configuration
+   */
+  importConfig(body: ConfigBundle, params?: ImportConfigParams): Promise<ConfigImportResult> {
+    return this.request<ConfigImportResult>({
+      method: 'POST',
+      path: '/admin/config/import',
+      query: {
+        dry_run: params?.dryRun,
+      },
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * purgeDeadLetters calls DELETE /admin/dead-letters/{topic}
+   *
+   * Purge dead letters
+   */
+  purgeDeadLetters(topic: string): Promise<void> {
+    return this.request<void>({
+      method: 'DELETE',
+      path: `/admin/dead-letters/${encodeURIComponent(topic)}`,
+      response: 'none',
+    });
+  }
+
+  /**
+   * listDeadLetters calls GET /admin/dead-letters/{topic}
+   *
+   * List dead letters
+   */
+  listDeadLetters(topic: string, params?: ListDeadLettersParams): Promise<DeadLetterPage> {
+    return this.request<DeadLetterPage>({
+      method: 'GET',
+      path: `/admin/dead-letters/${encodeURIComponent(topic)}`,
+      query: {
+        offset: params?.offset,
+        limit: params?.limit,
+      },
+      response: 'json',
+    });
+  }
+
+  /**
+   * replayDeadLetters calls POST /admin/dead-letters/{topic}/replay
+   *
+   * Replay dead letters
+   */
+  replayDeadLetters(topic: string, body: DeadLetterReplayRequest): Promise<DeadLetterReplayReport> {
+    return this.request<DeadLetterReplayReport>({
+      method: 'POST',
+      path: `/admin/dead-letters/${encodeURIComponent(topic)}/replay`,
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * getDeadLetter calls GET /admin/dead-letters/{topic}/{id}
+   *
+   * Get dead letter
+   */
+  getDeadLetter(topic: string, id: string): Promise<Message> {
+    return this.request<Message>({
+      method: 'GET',
+      path: `/admin/dead-letters/${encodeURIComponent(topic)}/${encodeURIComponent(id)}`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * replayDeadLetter calls POST /admin/dead-letters/{topic}/{id}/replay
+   *
+   * Replay dead letter
+   */
+  replayDeadLetter(topic: string, id: string): Promise<void> {
+    return this.request<void>({
+      method: 'POST',
+      path: `/admin/dead-letters/${encodeURIComponent(topic)}/${encodeURIComponent(id)}/replay`,
+      response: 'none',
+    });
+  }
+
+  /**
+   * listJobHistory calls GET /admin/jobs/history
+   *
+   * List job history
+   */
+  listJobHistory(params?: ListJobHistoryParams): Promise<JobHistoryPage> {
+    return this.request<JobHistoryPage>({
+      method: 'GET',
+      path: '/admin/jobs/history',
+      query: {
+        type: params?.type,
+        status: params?.status,
+        from: params?.from,
+        to: params?.to,
+        offset: params?.offset,
+        limit: params?.limit,
+      },
+      response: 'json',
+    });
+  }
+
+  /**
+   * getJobHistoryReport calls GET /admin/jobs/history/report
+   *
+   * Get job history report
+   */
+  getJobHistoryReport(params?: GetJobHistoryReportParams): Promise<JobHistoryReport> {
+    return this.request<JobHistoryReport>({
+      method: 'GET',
+      path: '/admin/jobs/history/report',
+      query: {
+        from: params?.from,
+        to: params?.to,
+      },
+      response: 'json',
+    });
+  }
+
+  /**
+   * listLabelAIConfigs calls GET /admin/labels/ai-configs
+   *
+   * List label AI configurations
+   */
+  listLabelAIConfigs(): Promise<LabelAIConfigsResponse> {
+    return this.request<LabelAIConfigsResponse>({
+      method: 'GET',
+      path: '/admin/labels/ai-configs',
+      response: 'json',
+    });
+  }
+
+  /**
+   * deleteLabelAIConfig calls DELETE /admin/labels/{label_id}/ai-config
+   *
+   * Delete label AI configuration
+   */
+  deleteLabelAIConfig(labelID: string): Promise<void> {
+    return this.request<void>({
+      method: 'DELETE',
+      path: `/admin/labels/${encodeURIComponent(labelID)}/ai-config`,
+      response: 'none',
+    });
+  }
+
+  /**
+   * getLabelAIConfig calls GET /admin/labels/{label_id}/ai-config
+   *
+   * Get label AI configuration
+   */
+  getLabelAIConfig(labelID: string): Promise<LabelAIConfig> {
+    return this.request<LabelAIConfig>({
+      method: 'GET',
+      path: `/admin/labels/${encodeURIComponent(labelID)}/ai-config`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * setLabelAIConfig calls PUT /admin/labels/{label_id}/ai-config
+   *
+   * Set label AI configuration
+   */
+  setLabelAIConfig(labelID: string, body: LabelAIConfigRequest): Promise<LabelAIConfig> {
+    return this.request<LabelAIConfig>({
+      method: 'PUT',
+      path: `/admin/labels/${encodeURIComponent(labelID)}/ai-config`,
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * getMaintenanceStatus calls GET /admin/maintenance
+   *
+   * Get maintenance status
+   */
+  getMaintenanceStatus(): Promise<MaintenanceStatus> {
+    return this.request<MaintenanceStatus>({
+      method: 'GET',
+      path: '/admin/maintenance',
+      response: 'json',
+    });
+  }
+
+  /**
+   * getRuntimeConfig calls GET /admin/runtime-config
+   *
+   * Get runtime settings
+   */
+  getRuntimeConfig(): Promise<RuntimeSettings> {
+    return this.request<RuntimeSettings>({
+      method: 'GET',
+      path: '/admin/runtime-config',
+      response: 'json',
+    });
+  }
+
+  /**
+   * updateRuntimeConfig calls PATCH /admin/runtime-config
+   *
+   * Update runtime settings
+   */
+  updateRuntimeConfig(body: RuntimeSettingsPatch): Promise<RuntimeSettings> {
+    return this.request<RuntimeSettings>({
+      method: 'PATCH',
+      path: '/admin/runtime-config',
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * getSystemStats calls GET /admin/stats
+   *
+   * Get system stats
+   */
+  getSystemStats(): Promise<SystemStats> {
+    return this.request<SystemStats>({
+      method: 'GET',
+      path: '/admin/stats',
+      response: 'json',
+    });
+  }
+
+  /**
+   * uploadAudio calls POST /audio/upload
+   *
+   * Upload audio file
+   */
+  uploadAudio(body: BodyInit, contentType?: string): Promise<Track> {
+    return this.request<Track>({
+      method: 'POST',
+      path: '/audio/upload',
+      body: body,
+      contentType: contentType,
+      response: 'json',
+    });
+  }
+
+  /**
+   * getAudioURL calls GET /audio/{id}
+   *
+   * Get audio download URL
+   */
+  getAudioURL(id: string): Promise<Record<string, string>> {
+    return this.request<Record<string, string>>({
+      method: 'GET',
+      path: `/audio/${encodeURIComponent(id)}`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * bootstrap calls POST /bootstrap
+   *
+   * Bootstrap environment
+   */
+  bootstrap(body: BootstrapRequest, params?: BootstrapParams): Promise<BootstrapResult> {
+    return this.request<BootstrapResult>({
+      method: 'POST',
+      path: '/bootstrap',
+      headers: {
+        'X-Setup-Token': params?.xSetupToken,
+      },
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * exportERN calls POST /ddex/export
+   *
+   * Export DDEX ERN
+   */
+  exportERN(): Promise<Blob> {
+    return this.request<Blob>({
+      method: 'POST',
+      path: '/ddex/export',
+      response: 'blob',
+    });
+  }
+
+  /**
+   * importERN calls POST /ddex/import
+   *
+   * Import DDEX ERN
+   */
+  importERN(body: BodyInit, contentType?: string, params?: ImportERNParams): Promise<ImportPreview> {
+    return this.request<ImportPreview>({
+      method: 'POST',
+      path: '/ddex/import',
+      query: {
+        dry_run: params?.dryRun,
+      },
+      body: body,
+      contentType: contentType,
+      response: 'json',
+    });
+  }
+
+  /**
+   * validateERN calls POST /ddex/validate
+   *
+   * Validate DDEX ERN
+   */
+  validateERN(body: BodyInit, contentType?: string): Promise<ValidationResponse> {
+    return this.request<ValidationResponse>({
+      method: 'POST',
+      path: '/ddex/validate',
+      body: body,
+      contentType: contentType,
+      response: 'json',
+    });
+  }
+
+  /**
+   * listDeliveries calls GET /deliveries
+   *
+   * List deliveries
+   */
+  listDeliveries(params?: ListDeliveriesParams): Promise<DeliveriesResponse> {
+    return this.request<DeliveriesResponse>({
+      method: 'GET',
+      path: '/deliveries',
+      query: {
+        dsp: params?.dsp,
+        track_id: params?.trackID,
+        release_id: params?.releaseID,
+        kind: params?.kind,
+        status: params?.status,
+        page: params?.page,
+        limit: params?.limit,
+      },
+      response: 'json',
+    });
+  }
+
+  /** listDeliveriesPager iterates over the items of every page of listDeliveries from params.page on */
+  listDeliveriesPager(params?: ListDeliveriesParams): AsyncGenerator<Delivery> {
+    return paginate(params?.page, async (page) => {
+      const out = await this.listDeliveries({ ...params, page });
+      return [out.deliveries ?? [], out.limit ?? 0];
+    });
+  }
+
+  /**
+   * recordDelivery calls POST /deliveries
+   *
+   * Record a delivery
+   */
+  recordDelivery(body: Record<string, unknown>): Promise<Delivery[]> {
+    return this.request<Delivery[]>({
+      method: 'POST',
+      path: '/deliveries',
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * acknowledgeDelivery calls POST /deliveries/acknowledgements
+   *
+   * Ingest a delivery acknowledgement
+   */
+  acknowledgeDelivery(body: string): Promise<AcknowledgementResult> {
+    return this.request<AcknowledgementResult>({
+      method: 'POST',
+      path: '/deliveries/acknowledgements',
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * requestTakedown calls POST /deliveries/takedowns
+   *
+   * Request a takedown or territory block
+   */
+  requestTakedown(body: Record<string, unknown>, params?: RequestTakedownParams): Promise<Record<string, unknown>> {
+    return this.request<Record<string, unknown>>({
+      method: 'POST',
+      path: '/deliveries/takedowns',
+      query: {
+        dry_run: params?.dryRun,
+      },
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * listCustomFields calls GET /labels/{label_id}/custom-fields
+   *
+   * List custom fields
+   */
+  listCustomFields(labelID: string): Promise<CustomFieldsResponse> {
+    return this.request<CustomFieldsResponse>({
+      method: 'GET',
+      path: `/labels/${encodeURIComponent(labelID)}/custom-fields`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * createCustomField calls POST /labels/{label_id}/custom-fields
+   *
+   * Create custom field
+   */
+  createCustomField(labelID: string, body: CustomFieldDefinition): Promise<CustomFieldDefinition> {
+    return this.request<CustomFieldDefinition>({
+      method: 'POST',
+      path: `/labels/${encodeURIComponent(labelID)}/custom-fields`,
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * deleteCustomField calls DELETE /labels/{label_id}/custom-fields/{name}
+   *
+   * Delete custom field
+   */
+  deleteCustomField(labelID: string, name: string): Promise<void> {
+    return this.request<void>({
+      method: 'DELETE',
+      path: `/labels/${encodeURIComponent(labelID)}/custom-fields/${encodeURIComponent(name)}`,
+      response: 'none',
+    });
+  }
+
+  /**
+   * getCustomField calls GET /labels/{label_id}/custom-fields/{name}
+   *
+   * Get custom field
+   */
+  getCustomField(labelID: string, name: string): Promise<CustomFieldDefinition> {
+    return this.request<CustomFieldDefinition>({
+      method: 'GET',
+      path: `/labels/${encodeURIComponent(labelID)}/custom-fields/${encodeURIComponent(name)}`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * updateCustomField calls PUT /labels/{label_id}/custom-fields/{name}
+   *
+   * Replace custom field
+   */
+  updateCustomField(labelID: string, name: string, body: CustomFieldDefinition): Promise<CustomFieldDefinition> {
+    return this.request<CustomFieldDefinition>({
+      method: 'PUT',
+      path: `/labels/${encodeURIComponent(labelID)}/custom-fields/${encodeURIComponent(name)}`,
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * listImportMappings calls GET /labels/{label_id}/import-mappings
+   *
+   * List import mappings
+   */
+  listImportMappings(labelID: string): Promise<ImportMappingsResponse> {
+    return this.request<ImportMappingsResponse>({
+      method: 'GET',
+      path: `/labels/${encodeURIComponent(labelID)}/import-mappings`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * createImportMapping calls POST /labels/{label_id}/import-mappings
+   *
+   * Create import mapping
+   */
+  createImportMapping(labelID: string, body: ImportMapping): Promise<ImportMapping> {
+    return this.request<ImportMapping>({
+      method: 'POST',
+      path: `/labels/${encodeURIComponent(labelID)}/import-mappings`,
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * deleteImportMapping calls DELETE /labels/{label_id}/import-mappings/{id}
+   *
+   * Delete import mapping
+   */
+  deleteImportMapping(labelID: string, id: string): Promise<void> {
+    return this.request<void>({
+      method: 'DELETE',
+      path: `/labels/${encodeURIComponent(labelID)}/import-mappings/${encodeURIComponent(id)}`,
+      response: 'none',
+    });
+  }
+
+  /**
+   * getImportMapping calls GET /labels/{label_id}/import-mappings/{id}
+   *
+   * Get import mapping
+   */
+  getImportMapping(labelID: string, id: string): Promise<ImportMapping> {
+    return this.request<ImportMapping>({
+      method: 'GET',
+      path: `/labels/${encodeURIComponent(labelID)}/import-mappings/${encodeURIComponent(id)}`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * updateImportMapping calls PUT /labels/{label_id}/import-mappings/{id}
+   *
+   * Replace import mapping
+   */
+  updateImportMapping(labelID: string, id: string, body: ImportMapping): Promise<ImportMapping> {
+    return this.request<ImportMapping>({
+      method: 'PUT',
+      path: `/labels/${encodeURIComponent(labelID)}/import-mappings/${encodeURIComponent(id)}`,
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * importCSV calls POST /labels/{label_id}/imports
+   *
+   * Import CSV
+   */
+  importCSV(labelID: string, body: string, params?: ImportCSVParams): Promise<Record<string, unknown>> {
+    return this.request<Record<string, unknown>>({
+      method: 'POST',
+      path: `/labels/${encodeURIComponent(labelID)}/imports`,
+      query: {
+        mapping: params?.mapping,
+        dry_run: params?.dryRun,
+      },
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * getLabelStats calls GET /labels/{label_id}/stats
+   *
+   * Get label statistics
+   */
+  getLabelStats(labelID: string): Promise<LabelStats> {
+    return this.request<LabelStats>({
+      method: 'GET',
+      path: `/labels/${encodeURIComponent(labelID)}/stats`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * listKeys calls GET /public-api-keys
+   *
+   * List public API keys
+   */
+  listKeys(params?: ListKeysParams): Promise<PublicAPIKeysResponse> {
+    return this.request<PublicAPIKeysResponse>({
+      method: 'GET',
+      path: '/public-api-keys',
+      query: {
+        label_id: params?.labelID,
+      },
+      response: 'json',
+    });
+  }
+
+  /**
+   * createKey calls POST /public-api-keys
+   *
+   * Create public API key
+   */
+  createKey(body: Record<string, unknown>): Promise<Record<string, unknown>> {
+    return this.request<Record<string, unknown>>({
+      method: 'POST',
+      path: '/public-api-keys',
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * revokeKey calls DELETE /public-api-keys/{id}
+   *
+   * Revoke public API key
+   */
+  revokeKey(id: string): Promise<void> {
+    return this.request<void>({
+      method: 'DELETE',
+      path: `/public-api-keys/${encodeURIComponent(id)}`,
+      response: 'none',
+    });
+  }
+
+  /**
+   * listPlayCounts calls GET /royalties/plays
+   *
+   * List play counts
+   */
+  listPlayCounts(params?: ListPlayCountsParams): Promise<PlayCountsResponse> {
+    return this.request<PlayCountsResponse>({
+      method: 'GET',
+      path: '/royalties/plays',
+      query: {
+        track_id: params?.trackID,
+        dsp: params?.dsp,
+        territory: params?.territory,
+        from: params?.from,
+        to: params?.to,
+        page: params?.page,
+        limit: params?.limit,
+      },
+      response: 'json',
+    });
+  }
+
+  /** listPlayCountsPager iterates over the items of every page of listPlayCounts from params.page on */
+  listPlayCountsPager(params?: ListPlayCountsParams): AsyncGenerator<PlayCount> {
+    return paginate(params?.page, async (page) => {
+      const out = await this.listPlayCounts({ ...params, page });
+      return [out.play_counts ?? [], out.limit ?? 0];
+    });
+  }
+
+  /**
+   * ingestSalesReport calls POST /royalties/reports
+   *
+   * Ingest a usage report
+   */
+  ingestSalesReport(body: string, params?: IngestSalesReportParams): Promise<SalesReport> {
+    return this.request<SalesReport>({
+      method: 'POST',
+      path: '/royalties/reports',
+      query: {
+        dsp: params?.dsp,
+      },
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * listTagRules calls GET /tag-rules
+   *
+   * List tagging rules
+   */
+  listTagRules(): Promise<TagRulesResponse> {
+    return this.request<TagRulesResponse>({
+      method: 'GET',
+      path: '/tag-rules',
+      response: 'json',
+    });
+  }
+
+  /**
+   * createTagRule calls POST /tag-rules
+   *
+   * Create tagging rule
+   */
+  createTagRule(body: TagRule): Promise<TagRule> {
+    return this.request<TagRule>({
+      method: 'POST',
+      path: '/tag-rules',
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * deleteTagRule calls DELETE /tag-rules/{id}
+   *
+   * Delete tagging rule
+   */
+  deleteTagRule(id: string): Promise<void> {
+    return this.request<void>({
+      method: 'DELETE',
+      path: `/tag-rules/${encodeURIComponent(id)}`,
+      response: 'none',
+    });
+  }
+
+  /**
+   * getTagRule calls GET /tag-rules/{id}
+   *
+   * Get tagging rule
+   */
+  getTagRule(id: string): Promise<TagRule> {
+    return this.request<TagRule>({
+      method: 'GET',
+      path: `/tag-rules/${encodeURIComponent(id)}`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * updateTagRule calls PUT /tag-rules/{id}
+   *
+   * Replace tagging rule
+   */
+  updateTagRule(id: string, body: TagRule): Promise<TagRule> {
+    return this.request<TagRule>({
+      method: 'PUT',
+      path: `/tag-rules/${encodeURIComponent(id)}`,
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * listTags calls GET /tags
+   *
+   * List tags
+   */
+  listTags(): Promise<TagsResponse> {
+    return this.request<TagsResponse>({
+      method: 'GET',
+      path: '/tags',
+      response: 'json',
+    });
+  }
+
+  /**
+   * createTag calls POST /tags
+   *
+   * Create tag
+   */
+  createTag(body: Tag): Promise<Tag> {
+    return this.request<Tag>({
+      method: 'POST',
+      path: '/tags',
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * deleteTag calls DELETE /tags/{name}
+   *
+   * Delete tag
+   */
+  deleteTag(name: string): Promise<Record<string, unknown>> {
+    return this.request<Record<string, unknown>>({
+      method: 'DELETE',
+      path: `/tags/${encodeURIComponent(name)}`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * getTag calls GET /tags/{name}
+   *
+   * Get tag
+   */
+  getTag(name: string): Promise<Tag> {
+    return this.request<Tag>({
+      method: 'GET',
+      path: `/tags/${encodeURIComponent(name)}`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * updateTag calls PUT /tags/{name}
+   *
+   * Update tag
+   */
+  updateTag(name: string, body: TagUpdateRequest): Promise<Tag> {
+    return this.request<Tag>({
+      method: 'PUT',
+      path: `/tags/${encodeURIComponent(name)}`,
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * mergeTag calls POST /tags/{name}/merge-into/{target}
+   *
+   * Merge tags
+   */
+  mergeTag(name: string, target: string): Promise<Record<string, unknown>> {
+    return this.request<Record<string, unknown>>({
+      method: 'POST',
+      path: `/tags/${encodeURIComponent(name)}/merge-into/${encodeURIComponent(target)}`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * renameTag calls POST /tags/{name}/rename
+   *
+   * Rename tag
+   */
+  renameTag(name: string, body: TagRenameRequest): Promise<Record<string, unknown>> {
+    return this.request<Record<string, unknown>>({
+      method: 'POST',
+      path: `/tags/${encodeURIComponent(name)}/rename`,
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * listTracks calls GET /tracks
+   *
+   * List tracks
+   */
+  listTracks(params?: ListTracksParams): Promise<ListResponse> {
+    return this.request<ListResponse>({
+      method: 'GET',
+      path: '/tracks',
+      query: {
+        page: params?.page,
+        limit: params?.limit,
+        fields: params?.fields,
+        sort: params?.sort,
+      },
+      response: 'json',
+    });
+  }
+
+  /** listTracksPager iterates over the items of every page of listTracks from params.page on */
+  listTracksPager(params?: ListTracksParams): AsyncGenerator<Track> {
+    return paginate(params?.page, async (page) => {
+      const out = await this.listTracks({ ...params, page });
+      return [out.tracks ?? [], out.limit ?? 0];
+    });
+  }
+
+  /**
+   * createTrack calls POST /tracks
+   *
+   * Create track
+   */
+  createTrack(body: Track, params?: CreateTrackParams): Promise<Track> {
+    return this.request<Track>({
+      method: 'POST',
+      path: '/tracks',
+      headers: {
+        'Idempotency-Key': params?.idempotencyKey,
+      },
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * batchDeleteTracks calls POST /tracks/batch-delete
+   *
+   * Delete tracks
+   */
+  batchDeleteTracks(body: BatchDeleteRequest, params?: BatchDeleteTracksParams): Promise<BatchDeleteResponse> {
+    return this.request<BatchDeleteResponse>({
+      method: 'POST',
+      path: '/tracks/batch-delete',
+      query: {
+        dry_run: params?.dryRun,
+      },
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * bulkEdit calls POST /tracks/bulk-edit
+   *
+   * Bulk edit tracks
+   */
+  bulkEdit(body: BulkEditRequest, params?: BulkEditParams): Promise<BulkEditJob> {
+    return this.request<BulkEditJob>({
+      method: 'POST',
+      path: '/tracks/bulk-edit',
+      query: {
+        dry_run: params?.dryRun,
+      },
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * getBulkEditJob calls GET /tracks/bulk-edit/{id}
+   *
+   * Get bulk edit job
+   */
+  getBulkEditJob(id: string): Promise<BulkEditJob> {
+    return this.request<BulkEditJob>({
+      method: 'GET',
+      path: `/tracks/bulk-edit/${encodeURIComponent(id)}`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * exportTracks calls POST /tracks/export
+   *
+   * Export tracks
+   */
+  exportTracks(body: ExportRequest, params?: ExportTracksParams): Promise<ExportResponse> {
+    return this.request<ExportResponse>({
+      method: 'POST',
+      path: '/tracks/export',
+      headers: {
+        'Idempotency-Key': params?.idempotencyKey,
+      },
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * searchTracks calls POST /tracks/search
+   *
+   * Search tracks
+   */
+  searchTracks(body: SearchQuery, params?: SearchTracksParams): Promise<Track[]> {
+    return this.request<Track[]>({
+      method: 'POST',
+      path: '/tracks/search',
+      query: {
+        fields: params?.fields,
+      },
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * uploadTrack calls POST /tracks/upload
+   *
+   * Upload new track
+   */
+  uploadTrack(body: BodyInit, contentType?: string, params?: UploadTrackParams): Promise<Track> {
+    return this.request<Track>({
+      method: 'POST',
+      path: '/tracks/upload',
+      headers: {
+        'Idempotency-Key': params?.idempotencyKey,
+      },
+      body: body,
+      contentType: contentType,
+      response: 'json',
+    });
+  }
+
+  /**
+   * createUploadURL calls POST /tracks/upload-url
+   *
+   * Create direct upload URL
+   */
+  createUploadURL(body: UploadURLRequest, params?: CreateUploadURLParams): Promise<UploadURLResponse> {
+    return this.request<UploadURLResponse>({
+      method: 'POST',
+      path: '/tracks/upload-url',
+      headers: {
+        'Idempotency-Key': params?.idempotencyKey,
+      },
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * deleteTrack calls DELETE /tracks/{id}
+   *
+   * Delete track
+   */
+  deleteTrack(id: string): Promise<void> {
+    return this.request<void>({
+      method: 'DELETE',
+      path: `/tracks/${encodeURIComponent(id)}`,
+      response: 'none',
+    });
+  }
+
+  /**
+   * getTrack calls GET /tracks/{id}
+   *
+   * Get track
+   */
+  getTrack(id: string, params?: GetTrackParams): Promise<Track> {
+    return this.request<Track>({
+      method: 'GET',
+      path: `/tracks/${encodeURIComponent(id)}`,
+      headers: {
+        'If-None-Match': params?.ifNoneMatch,
+        'If-Modified-Since': params?.ifModifiedSince,
+      },
+      response: 'json',
+    });
+  }
+
+  /**
+   * patchTrack calls PATCH /tracks/{id}
+   *
+   * Patch track
+   */
+  patchTrack(id: string, body: Record<string, unknown>, params?: PatchTrackParams): Promise<Track> {
+    return this.request<Track>({
+      method: 'PATCH',
+      path: `/tracks/${encodeURIComponent(id)}`,
+      headers: {
+        'If-Match': params?.ifMatch,
+      },
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * updateTrack calls PUT /tracks/{id}
+   *
+   * Update track
+   */
+  updateTrack(id: string, body: Track, params?: UpdateTrackParams): Promise<Track> {
+    return this.request<Track>({
+      method: 'PUT',
+      path: `/tracks/${encodeURIComponent(id)}`,
+      headers: {
+        'If-Match': params?.ifMatch,
+      },
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * getTrackAnalysis calls GET /tracks/{id}/analysis
+   *
+   * Get track audio analysis
+   */
+  getTrackAnalysis(id: string, params?: GetTrackAnalysisParams): Promise<TrackAnalysis> {
+    return this.request<TrackAnalysis>({
+      method: 'GET',
+      path: `/tracks/${encodeURIComponent(id)}/analysis`,
+      query: {
+        version: params?.version,
+      },
+      response: 'json',
+    });
+  }
+
+  /**
+   * confirmUpload calls POST /tracks/{id}/confirm-upload
+   *
+   * Confirm direct upload
+   */
+  confirmUpload(id: string): Promise<Track> {
+    return this.request<Track>({
+      method: 'POST',
+      path: `/tracks/${encodeURIComponent(id)}/confirm-upload`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * getTrackDuplicates calls GET /tracks/{id}/duplicates
+   *
+   * List probable duplicates
+   */
+  getTrackDuplicates(id: string): Promise<DuplicatesResponse> {
+    return this.request<DuplicatesResponse>({
+      method: 'GET',
+      path: `/tracks/${encodeURIComponent(id)}/duplicates`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * putTrackEmbedding calls PUT /tracks/{id}/embedding
+   *
+   * Upload track embedding
+   */
+  putTrackEmbedding(id: string, body: EmbeddingRequest): Promise<TrackEmbedding> {
+    return this.request<TrackEmbedding>({
+      method: 'PUT',
+      path: `/tracks/${encodeURIComponent(id)}/embedding`,
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * getHarmonicMixes calls GET /tracks/{id}/harmonic-mixes
+   *
+   * List harmonically compatible tracks
+   */
+  getHarmonicMixes(id: string, params?: GetHarmonicMixesParams): Promise<HarmonicMixResponse> {
+    return this.request<HarmonicMixResponse>({
+      method: 'GET',
+      path: `/tracks/${encodeURIComponent(id)}/harmonic-mixes`,
+      query: {
+        limit: params?.limit,
+      },
+      response: 'json',
+    });
+  }
+
+  /**
+   * getIntegrity calls GET /tracks/{id}/integrity
+   *
+   * Check track file integrity
+   */
+  getIntegrity(id: string): Promise<IntegrityReport> {
+    return this.request<IntegrityReport>({
+      method: 'GET',
+      path: `/tracks/${encodeURIComponent(id)}/integrity`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * mergeTrack calls POST /tracks/{id}/merge-into/{target_id}
+   *
+   * Merge tracks
+   */
+  mergeTrack(id: string, targetID: string, body: TrackMergeRequest): Promise<TrackMergeResponse> {
+    return this.request<TrackMergeResponse>({
+      method: 'POST',
+      path: `/tracks/${encodeURIComponent(id)}/merge-into/${encodeURIComponent(targetID)}`,
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * getTrackProvenance calls GET /tracks/{id}/provenance
+   *
+   * Get track field provenance
+   */
+  getTrackProvenance(id: string): Promise<ProvenanceResponse> {
+    return this.request<ProvenanceResponse>({
+      method: 'GET',
+      path: `/tracks/${encodeURIComponent(id)}/provenance`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * getRestore calls GET /tracks/{id}/restore
+   *
+   * Get track file archive status
+   */
+  getRestore(id: string): Promise<ArchiveStatus> {
+    return this.request<ArchiveStatus>({
+      method: 'GET',
+      path: `/tracks/${encodeURIComponent(id)}/restore`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * restore calls POST /tracks/{id}/restore
+   *
+   * Restore archived track file
+   */
+  restore(id: string): Promise<ArchiveStatus> {
+    return this.request<ArchiveStatus>({
+      method: 'POST',
+      path: `/tracks/${encodeURIComponent(id)}/restore`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * getSimilarTracks calls GET /tracks/{id}/similar
+   *
+   * List similar tracks
+   */
+  getSimilarTracks(id: string, params?: GetSimilarTracksParams): Promise<SimilarTracksResponse> {
+    return this.request<SimilarTracksResponse>({
+      method: 'GET',
+      path: `/tracks/${encodeURIComponent(id)}/similar`,
+      query: {
+        limit: params?.limit,
+      },
+      response: 'json',
+    });
+  }
+
+  /**
+   * streamTrack calls GET /tracks/{id}/stream
+   *
+   * Stream track audio
+   */
+  streamTrack(id: string, params?: StreamTrackParams): Promise<Blob> {
+    return this.request<Blob>({
+      method: 'GET',
+      path: `/tracks/${encodeURIComponent(id)}/stream`,
+      headers: {
+        Range: params?.range,
+      },
+      response: 'blob',
+    });
+  }
+
+  /**
+   * getTrackTransitions calls GET /tracks/{id}/transitions
+   *
+   * List allowed status changes
+   */
+  getTrackTransitions(id: string): Promise<TransitionsResponse> {
+    return this.request<TransitionsResponse>({
+      method: 'GET',
+      path: `/tracks/${encodeURIComponent(id)}/transitions`,
+      response: 'json',
+    });
+  }
+
+  /**
+   * transitionTrack calls POST /tracks/{id}/transitions
+   *
+   * Change track status
+   */
+  transitionTrack(id: string, body: TransitionRequest, params?: TransitionTrackParams): Promise<Track> {
+    return this.request<Track>({
+      method: 'POST',
+      path: `/tracks/${encodeURIComponent(id)}/transitions`,
+      headers: {
+        'If-Match': params?.ifMatch,
+      },
+      body: body,
+      contentType: 'application/json',
+      response: 'json',
+    });
+  }
+
+  /**
+   * getUsage calls GET /usage
+   *
+   * Get usage
+   */
+  getUsage(params?: GetUsageParams): Promise<UsageReport> {
+    return this.request<UsageReport>({
+      method: 'GET',
+      path: '/usage',
+      query: {
+        label_id: params?.labelID,
+        from: params?.from,
+        to: params?.to,
+        format: params?.format,
+      },
+      response: 'json',
+    });
+  }
+
+  /**
+   * deleteUser calls DELETE /users/{id}
+   *
+   * Delete user
+   */
+  deleteUser(id: string): Promise<void> {
+    return this.request<void>({
+      method: 'DELETE',
+      path: `/users/${encodeURIComponent(id)}`,
+      response: 'none',
+    });
+  }
+
+  /**
+   * exportUserData calls POST /users/{id}/export
+   *
+   * Export user data
+   */
+  exportUserData(id: string): Promise<Blob> {
+    return this.request<Blob>({
+      method: 'POST',
+      path: `/users/${encodeURIComponent(id)}/export`,
+      response: 'blob',
+    });
+  }
+}
@@ -0,0 +1,180 @@
+// The request and response types and the per-operation methods in
+// client.gen.ts are generated from the OpenAPI document by cmd/openapigen;
+// this file holds the hand-written transport they share.
+
+/** TokenSource returns the bearer token of a request, for example refreshing a JWT before it expires */
+export type TokenSource = () => string | Promise<string>;
+
+/** ClientOptions configures a Client */
+export interface ClientOptions {
+  /** baseUrl is the API root, for example https://api.example.com/api/v1 */
+  baseUrl: string;
+  /** token is the bearer token sent with every request */
+  token?: string | TokenSource;
+  /** apiKey is sent in the X-API-Key header of every request */
+  apiKey?: string;
+  /** headers are added to every request */
+  headers?: Record<string, string>;
+  /** fetch performs requests; the global fetch is used when unset */
+  fetch?: typeof fetch;
+}
+
+/** FieldError reports why one field of a rejected request was invalid */
+export interface FieldError {
+  field: string;
+  code: string;
+  message: string;
+}
+
+/** ApiError is thrown for non-2xx responses */
+export class ApiError extends Error {
+  readonly status: number;
+  readonly code?: string;
+  readonly type?: string;
+  readonly details?: unknown;
+  readonly fields?: FieldError[];
+  readonly requestId?: string;
+  readonly traceId?: string;
+  readonly body: string;
+
+  constructor(status: number, body: string) {
+    let error: Record<string, unknown> = {};
+    try {
+      const envelope = JSON.parse(body);
+      if (typeof envelope?.error === 'string') {
+        error = { message: envelope.error };
+      } else if (envelope?.error && typeof envelope.error === 'object') {
+        error = envelope.error;
+      }
+    } catch {
+      // Not JSON, so only the body is kept
+    }
+    const message = typeof error.message === 'string' ? error.message : body.trim();
+    super(`api error ${status}${error.code ? ` ${error.code}` : ''}: ${message}`);
+    this.name = 'ApiError';
+    this.status = status;
+    this.code = error.code as string | undefined;
+    this.type = error.type as string | undefined;
+    this.details = error.details;
+    this.fields = error.fields as FieldError[] | undefined;
+    this.requestId = error.request_id as string | undefined;
+    this.traceId = error.trace_id as string | undefined;
+    this.body = body;
+  }
+}
+
+/** isNotModified reports whether err answers a conditional request for a resource the client already has */
+export function isNotModified(err: unknown): boolean {
+  return err instanceof ApiError && err.status === 304;
+}
+
+type Scalar = string | number | boolean | undefined | null;
+
+/** ApiRequest is one call of an operation, built by the generated methods */
+export interface ApiRequest {
+  method: string;
+  path: string;
+  query?: Record<string, Scalar | Scalar[]>;
+  headers?: Record<string, Scalar>;
+  body?: unknown;
+  contentType?: string;
+  response: 'json' | 'blob' | 'none';
+}
+
+/** BaseClient sends requests to the API; the generated Client adds a method per operation */
+export class BaseClient {
+  protected readonly baseUrl: string;
+  private token?: string | TokenSource;
+  private readonly headers: Record<string, string>;
+  private readonly fetcher?: typeof fetch;
+
+  constructor(options: ClientOptions) {
+    this.baseUrl = options.baseUrl.replace(/\/+$/, '');
+    this.token = options.token;
+    this.headers = { ...options.headers };
+    if (options.apiKey) {
+      this.headers['X-API-Key'] = options.apiKey;
+    }
+    this.fetcher = options.fetch;
+  }
+
+  /** withToken sets the bearer token sent with every request */
+  withToken(token: string | TokenSource): this {
+    this.token = token;
+    return this;
+  }
+
+  /** withApiKey sets the API key sent with every request. Keys issued to users and public catalog keys are both sent in the X-API-Key header. */
+  withApiKey(key: string): this {
+    this.headers['X-API-Key'] = key;
+    return this;
+  }
+
+  protected async request<T>(req: ApiRequest): Promise<T> {
+    const query = new URLSearchParams();
+    for (const [name, value] of Object.entries(req.query ?? {})) {
+      // Lists are sent as the parameter repeated once per value
+      for (const item of Array.isArray(value) ? value : [value]) {
+        if (item !== undefined && item !== null) {
+          query.append(name, String(item));
+        }
+      }
+    }
+    const search = query.toString();
+    const url = this.baseUrl + req.path + (search ? `?${search}` : '');
+
+    const headers: Record<string, string> = { ...this.headers, Accept: 'application/json' };
+    for (const [name, value] of Object.entries(req.headers ?? {})) {
+      if (value !== undefined && value !== null) {
+        headers[name] = String(value);
+      }
+    }
+    const token = typeof this.token === 'function' ? await this.token() : this.token;
+    if (token) {
+      headers.Authorization = `Bearer ${token}`;
+    }
+
+    let body: BodyInit | undefined;
+    if (req.body !== undefined) {
+      body = req.contentType === 'application/json' ? JSON.stringify(req.body) : (req.body as BodyInit);
+      if (req.contentType) {
+        headers['Content-Type'] = req.contentType;
+      }
+    }
+
+    const fetcher = this.fetcher ?? fetch;
+    const resp = await fetcher(url, { method: req.method, headers, body });
+    if (!resp.ok) {
+      throw new ApiError(resp.status, await resp.text());
+    }
+
+    switch (req.response) {
+      case 'none':
+        return undefined as T;
+      case 'blob':
+        return (await resp.blob()) as T;
+      default: {
+        const text = await resp.text();
+        return (text ? JSON.parse(text) : undefined) as T;
+      }
+    }
+  }
+}
+
+/**
+ * paginate iterates over the items of every page from start, or the first
+ * page when start is unset. fetchPage returns the items of a page and the
+ * page size the API applied; a shorter page is the last one.
+ */
+export async function* paginate<T>(
+  start: number | undefined,
+  fetchPage: (page: number) => Promise<[T[], number]>,
+): AsyncGenerator<T> {
+  for (let page = start && start > 0 ? start : 1; ; page++) {
+    const [items, limit] = await fetchPage(page);
+    yield* items;
+    if (items.length === 0 || limit <= 0 || items.length < limit) {
+      return;
+    }
+  }
+}